@@ -102,6 +102,21 @@ output "kubeone_workers" {
           diskIops           = 500
           isSpotInstance     = var.initial_machinedeployment_spotinstances
           ebsVolumeEncrypted = false
+          ## KMS key used to encrypt the EBS volume, requires ebsVolumeEncrypted = true
+          # ebsVolumeKmsKeyId = ""
+          ## Instance types ordered by priority, the first one is used if instanceType is not set
+          # instanceTypes = ["m5.large", "m5a.large"]
+          ## Spot market options, requires isSpotInstance = true
+          # spotInstanceConfig = {
+          #   maxPrice             = "0.05"
+          #   persistentRequest    = false
+          #   interruptionBehavior = "terminate"
+          # }
+          ## Enforce IMDSv2 on the worker instances
+          # instanceMetadataOptions = {
+          #   httpTokens              = "required"
+          #   httpPutResponseHopLimit = 2
+          # }
           tags = {
             "${var.cluster_name}-workers" = ""
           }
@@ -140,6 +155,21 @@ output "kubeone_workers" {
           diskIops           = 500
           isSpotInstance     = var.initial_machinedeployment_spotinstances
           ebsVolumeEncrypted = false
+          ## KMS key used to encrypt the EBS volume, requires ebsVolumeEncrypted = true
+          # ebsVolumeKmsKeyId = ""
+          ## Instance types ordered by priority, the first one is used if instanceType is not set
+          # instanceTypes = ["m5.large", "m5a.large"]
+          ## Spot market options, requires isSpotInstance = true
+          # spotInstanceConfig = {
+          #   maxPrice             = "0.05"
+          #   persistentRequest    = false
+          #   interruptionBehavior = "terminate"
+          # }
+          ## Enforce IMDSv2 on the worker instances
+          # instanceMetadataOptions = {
+          #   httpTokens              = "required"
+          #   httpPutResponseHopLimit = 2
+          # }
           tags = {
             "${var.cluster_name}-workers" = ""
           }
@@ -178,6 +208,21 @@ output "kubeone_workers" {
           diskIops           = 500
           isSpotInstance     = var.initial_machinedeployment_spotinstances
           ebsVolumeEncrypted = false
+          ## KMS key used to encrypt the EBS volume, requires ebsVolumeEncrypted = true
+          # ebsVolumeKmsKeyId = ""
+          ## Instance types ordered by priority, the first one is used if instanceType is not set
+          # instanceTypes = ["m5.large", "m5a.large"]
+          ## Spot market options, requires isSpotInstance = true
+          # spotInstanceConfig = {
+          #   maxPrice             = "0.05"
+          #   persistentRequest    = false
+          #   interruptionBehavior = "terminate"
+          # }
+          ## Enforce IMDSv2 on the worker instances
+          # instanceMetadataOptions = {
+          #   httpTokens              = "required"
+          #   httpPutResponseHopLimit = 2
+          # }
           tags = {
             "${var.cluster_name}-workers" = ""
           }
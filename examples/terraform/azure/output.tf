@@ -77,9 +77,22 @@ output "kubeone_workers" {
           # Zones (optional)
           # Represents Availability Zones is a high-availability offering
           # that protects your applications and data from datacenter failures.
-          # zones = {
-          #   "1"
-          # }
+          # Zones can't be used together with availabilitySet. If multiple
+          # zones are set, KubeOne creates a MachineDeployment per zone named
+          # <workerset>-<zone> and splits the replicas between them.
+          # zones = [
+          #   "1",
+          #   "2",
+          #   "3",
+          # ]
+          # Enable accelerated networking, if supported by the VM size (optional)
+          # enableAcceleratedNetworking = true
+          # Use Spot VMs (optional). Spot VMs can't be used with availabilitySet.
+          # priority = "Spot"
+          # What happens to the evicted Spot VMs: Deallocate or Delete (optional)
+          # evictionPolicy = "Delete"
+          # Maximum price per hour in USD, -1 caps it at the on-demand price (optional)
+          # maxPrice = -1
           tags = {
             "${var.cluster_name}-workers" = "pool1"
           }
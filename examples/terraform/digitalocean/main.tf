@@ -25,6 +25,11 @@ resource "digitalocean_tag" "kube_cluster_tag" {
   name = local.kube_cluster_tag
 }
 
+resource "digitalocean_vpc" "vpc" {
+  name   = "${var.cluster_name}-vpc"
+  region = var.region
+}
+
 resource "digitalocean_ssh_key" "deployer" {
   name       = "${var.cluster_name}-deployer-key"
   public_key = file(var.ssh_public_key_file)
@@ -43,6 +48,7 @@ resource "digitalocean_droplet" "control_plane" {
   region             = var.region
   size               = var.control_plane_size
   private_networking = true
+  vpc_uuid           = digitalocean_vpc.vpc.id
   monitoring         = false
   ipv6               = false
 
@@ -52,8 +58,9 @@ resource "digitalocean_droplet" "control_plane" {
 }
 
 resource "digitalocean_loadbalancer" "control_plane" {
-  name   = "${var.cluster_name}-lb"
-  region = var.region
+  name     = "${var.cluster_name}-lb"
+  region   = var.region
+  vpc_uuid = digitalocean_vpc.vpc.id
 
   forwarding_rule {
     entry_port     = 6443
@@ -30,6 +30,7 @@ output "kubeone_hosts" {
     control_plane = {
       cluster_name         = var.cluster_name
       cloud_provider       = "digitalocean"
+      vpc_uuid             = digitalocean_vpc.vpc.id
       private_address      = digitalocean_droplet.control_plane.*.ipv4_address_private
       public_address       = digitalocean_droplet.control_plane.*.ipv4_address
       ssh_agent_socket     = var.ssh_agent_socket
@@ -64,6 +65,8 @@ output "kubeone_workers" {
           backups            = false
           ipv6               = false
           monitoring         = false
+          # KubeOne sets the VPC of the cluster and the
+          # "kubernetes-cluster:<cluster name>" tag by default
           tags = [
             local.kube_cluster_tag,
             "${var.cluster_name}-workers-pool1"
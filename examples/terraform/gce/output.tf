@@ -72,10 +72,20 @@ output "kubeone_workers" {
           labels = {
             "${var.cluster_name}-workers" = "pool1"
           }
+          # KubeOne additionally sets the "kubeone-<cluster name>" network tag
           tags     = ["firewall", "targets", "${var.cluster_name}-pool1"]
           regional = false
           # Use custom image (optional)
           # customImage = ""
+          # Shielded VM options (optional)
+          # shieldedVM = {
+          #   enableSecureBoot          = true
+          #   enableVtpm                = true
+          #   enableIntegrityMonitoring = true
+          # }
+          # Custom service account and its scopes (optional)
+          # serviceAccount = "workers@${var.project}.iam.gserviceaccount.com"
+          # scopes         = ["https://www.googleapis.com/auth/cloud-platform"]
         }
       }
     }
@@ -72,6 +72,10 @@ output "kubeone_workers" {
           ]
           # Datacenter (optional)
           # datacenter = ""
+          # Spread placement group (optional), created if it doesn't exist.
+          # Defaults to "<cluster name>-<workerset name>" for workersets
+          # with up to 10 replicas.
+          # placementGroup = ""
           labels = {
             "kubeone_cluster_name"        = var.cluster_name
             "${var.cluster_name}-workers" = "pool1"
@@ -73,6 +73,14 @@ output "kubeone_workers" {
           # rootDiskSizeGB = 50
           # Optional: limit how many volumes can be attached to a node
           # nodeVolumeAttachLimit = 25
+          # Optional: attach the workers to multiple networks instead of `network`.
+          # The first network is the primary one. Requires internal-network-name
+          # in the [Networking] section of the cloud-config.
+          # networks = [openstack_networking_network_v2.network.name, "storage"]
+          # Optional: ID of the server group, e.g. with the anti-affinity policy
+          # serverGroup = openstack_compute_servergroup_v2.workers.id
+          # Optional: use config drive instead of the metadata service
+          # configDrive = true
           tags = {
             "${var.cluster_name}-workers" = "pool1"
           }
@@ -74,6 +74,16 @@ output "kubeone_workers" {
           vmNetName      = var.network_name
           resourcePool   = var.resource_pool_name
           folder         = var.folder_name
+          # Optional: keep the machines of the same MachineSet on different
+          # ESXi hosts using the DRS anti-affinity rule. Requires cluster.
+          # vmAntiAffinity = true
+          # Optional: tags attached to the machines
+          # tags = [
+          #   {
+          #     name       = "k8s-worker"
+          #     categoryID = "urn:vmomi:InventoryServiceCategory:..."
+          #   }
+          # ]
         }
       }
     }
@@ -29,3 +29,8 @@ bash vendor/k8s.io/code-generator/generate-internal-groups.sh \
   "deepcopy,conversion,defaulter" "" ./pkg/apis ./pkg/apis \
   "kubeone:v1alpha1,v1beta1" \
   --go-header-file hack/boilerplate/boilerplate.generatego.txt
+
+bash vendor/k8s.io/code-generator/generate-groups.sh \
+  "deepcopy" "" ./pkg/apis ./pkg/apis \
+  "operator:v1alpha1" \
+  --go-header-file hack/boilerplate/boilerplate.generatego.txt
@@ -27,6 +27,7 @@ import (
 	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
 	"k8c.io/kubeone/pkg/certificate"
 	"k8c.io/kubeone/pkg/credentials"
+	"k8c.io/kubeone/pkg/secretstore"
 	"k8c.io/kubeone/pkg/state"
 	"k8c.io/kubeone/pkg/templates/images"
 	"k8c.io/kubeone/pkg/templates/resources"
@@ -64,6 +65,7 @@ type templateData struct {
 	InternalImages                      *internalImages
 	Resources                           map[string]string
 	Params                              map[string]string
+	ServingCerts                        *servingCerts
 }
 
 func newAddonsApplier(s *state.State) (*applier, error) {
@@ -78,7 +80,7 @@ func newAddonsApplier(s *state.State) (*applier, error) {
 		localFS = os.DirFS(addonsPath)
 	}
 
-	creds, err := credentials.Any(s.CredentialsFilePath)
+	creds, err := credentials.Any(s.Cluster.CloudProvider, s.CredentialsFilePath)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to fetch credentials")
 	}
@@ -93,7 +95,7 @@ func newAddonsApplier(s *state.State) (*applier, error) {
 		return nil, errors.Wrap(err, "unable to convert env var bindings for credentials to yaml")
 	}
 
-	kubeCAPrivateKey, kubeCACert, err := certificate.CAKeyPair(s.Configuration)
+	kubeCAIssuer, err := certificate.KubernetesIssuer(s.Context, s.Cluster, s.Configuration, s.Cluster.CertificateKeyAlgorithm())
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to load CA keypair")
 	}
@@ -119,8 +121,8 @@ func newAddonsApplier(s *state.State) (*applier, error) {
 		resources.MachineControllerWebhookName,
 		resources.MachineControllerNameSpace,
 		s.Cluster.ClusterNetwork.ServiceDomainName,
-		kubeCAPrivateKey,
-		kubeCACert,
+		s.Cluster.CertificateValidity(),
+		kubeCAIssuer,
 	)
 	if err != nil {
 		return nil, err
@@ -131,8 +133,8 @@ func newAddonsApplier(s *state.State) (*applier, error) {
 		resources.MetricsServerName,
 		resources.MetricsServerNamespace,
 		s.Cluster.ClusterNetwork.ServiceDomainName,
-		kubeCAPrivateKey,
-		kubeCACert,
+		s.Cluster.CertificateValidity(),
+		kubeCAIssuer,
 	)
 	if err != nil {
 		return nil, err
@@ -140,7 +142,11 @@ func newAddonsApplier(s *state.State) (*applier, error) {
 
 	params := map[string]string{}
 	if s.Cluster.Addons.Enabled() {
-		for k, v := range s.Cluster.Addons.GlobalParams {
+		globalParams, err := secretstore.ResolveMap(s.Context, s.Cluster.Addons.GlobalParams)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to resolve addons global params")
+		}
+		for k, v := range globalParams {
 			params[k] = v
 		}
 	}
@@ -164,6 +170,11 @@ func newAddonsApplier(s *state.State) (*applier, error) {
 		},
 		Resources: resources.All(),
 		Params:    params,
+		ServingCerts: newServingCerts(
+			s.Cluster.ClusterNetwork.ServiceDomainName,
+			s.Cluster.CertificateValidity(),
+			kubeCAIssuer,
+		),
 	}
 
 	// Certs for vsphere-csi-webhook (deployed only if CSIMigration is enabled)
@@ -172,8 +183,8 @@ func newAddonsApplier(s *state.State) (*applier, error) {
 			resources.VsphereCSIWebhookName,
 			resources.VsphereCSIWebhookNamespace,
 			s.Cluster.ClusterNetwork.ServiceDomainName,
-			kubeCAPrivateKey,
-			kubeCACert,
+			s.Cluster.CertificateValidity(),
+			kubeCAIssuer,
 		)
 		if err != nil {
 			return nil, err
@@ -182,6 +193,28 @@ func newAddonsApplier(s *state.State) (*applier, error) {
 		data.Certificates["vSphereCSIWebhookKey"] = vsphereCSICertsMap[resources.TLSKeyName]
 	}
 
+	// Cluster CA keypair for the cert-manager ClusterIssuer (deployed only if
+	// the ClusterIssuer is enabled and no CA Secret is provided)
+	if s.Cluster.Features.CertManager.ClusterCAIssuerEnabled() {
+		caKey, found := s.Configuration.KubernetesPKI[certificate.KubernetesCAKeyPath]
+		if !found {
+			return nil, errors.Errorf("%q not found, the cluster CA can't be used by the cert-manager ClusterIssuer", certificate.KubernetesCAKeyPath)
+		}
+		data.Certificates["KubernetesCAKey"] = string(caKey)
+	}
+
+	// Certs for Hubble server and Hubble Relay client (deployed only if Hubble is enabled)
+	if cilium := s.Cluster.ClusterNetwork.CNI.Cilium; cilium != nil && cilium.EnableHubble {
+		hubbleCertsMap, err := hubbleCertificates(s.Cluster.CertificateValidity(), s.Cluster.CertificateKeyAlgorithm())
+		if err != nil {
+			return nil, err
+		}
+
+		for k, v := range hubbleCertsMap {
+			data.Certificates[k] = v
+		}
+	}
+
 	return &applier{
 		TemplateData: data,
 		LocalFS:      localFS,
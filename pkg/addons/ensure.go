@@ -45,17 +45,28 @@ var (
 		resources.AddonCCMOpenStack:       "",
 		resources.AddonCCMPacket:          "",
 		resources.AddonCCMVsphere:         "",
+		resources.AddonCertManager:        "",
+		resources.AddonCertManagerIssuer:  "",
 		resources.AddonCNICanal:           "",
 		resources.AddonCNICilium:          "",
 		resources.AddonCNIWeavenet:        "",
+		resources.AddonCSIAwsEBS:          "",
 		resources.AddonCSIAzureDisk:       "",
 		resources.AddonCSIAzureFile:       "",
+		resources.AddonCSIDigitalOcean:    "",
 		resources.AddonCSIHetnzer:         "",
 		resources.AddonCSIOpenStackCinder: "",
 		resources.AddonCSIVsphere:         "",
+		resources.AddonFlux:               "",
+		resources.AddonFluxSync:           "",
+		resources.AddonIngressNginx:       "",
 		resources.AddonMachineController:  "",
 		resources.AddonMetricsServer:      "",
+		resources.AddonMonitoring:         "",
 		resources.AddonNodeLocalDNS:       "",
+		resources.AddonNvidiaDevicePlugin: "",
+		resources.AddonVelero:             "",
+		resources.AddonVeleroBackups:      "",
 	}
 )
 
@@ -74,9 +85,80 @@ func EnsureUserAddons(s *state.State) error {
 
 	s.Logger.Infof("Applying user provided addons...")
 
-	customAddons, err := fs.ReadDir(applier.LocalFS, ".")
+	combinedAddons, deletedAddons, err := applier.userAddons(s)
 	if err != nil {
-		return errors.Wrap(err, "failed to read addons directory")
+		return err
+	}
+
+	for _, addonName := range deletedAddons {
+		s.Logger.Infof("Deleting addon %q...", addonName)
+		if err := applier.loadAndDeleteAddon(s, applier.EmbededFS, addonName); err != nil {
+			return errors.Wrapf(err, "failed to load and delete the addon %q", addonName)
+		}
+	}
+
+	for addonName := range combinedAddons {
+		s.Logger.Infof("Applying addon %q...", addonName)
+
+		if err := EnsureAddonByName(s, addonName); err != nil {
+			return errors.Wrapf(err, "failed to load and apply the addon %q", addonName)
+		}
+	}
+
+	s.Logger.Info("Applying addons from the root directory...")
+	if err := applier.loadAndApplyAddon(s, applier.LocalFS, ""); err != nil {
+		return errors.Wrap(err, "failed to load and apply addons from the root directory")
+	}
+
+	return nil
+}
+
+// DeleteUserAddons deletes addons applied by EnsureUserAddons, including
+// addons in the root of the addons directory. Embedded addons deployed by
+// KubeOne itself (e.g. CNI and machine-controller) are not deleted.
+func DeleteUserAddons(s *state.State) error {
+	applier, err := newAddonsApplier(s)
+	if err != nil {
+		return err
+	}
+
+	if applier.LocalFS == nil {
+		s.Logger.Infoln("Skipping deleting addons because addons are not enabled...")
+		return nil
+	}
+
+	combinedAddons, _, err := applier.userAddons(s)
+	if err != nil {
+		return err
+	}
+
+	for addonName := range combinedAddons {
+		// Addons in the addons directory take precedence over the embedded ones
+		fsys := applier.EmbededFS
+		if info, statErr := fs.Stat(applier.LocalFS, addonName); statErr == nil && info.IsDir() {
+			fsys = applier.LocalFS
+		}
+
+		s.Logger.Infof("Deleting addon %q...", addonName)
+		if err := applier.loadAndDeleteAddon(s, fsys, addonName); err != nil {
+			return errors.Wrapf(err, "failed to load and delete the addon %q", addonName)
+		}
+	}
+
+	s.Logger.Info("Deleting addons from the root directory...")
+	if err := applier.loadAndDeleteAddon(s, applier.LocalFS, ""); err != nil {
+		return errors.Wrap(err, "failed to load and delete addons from the root directory")
+	}
+
+	return nil
+}
+
+// userAddons returns names of addons provided by the user and that are not
+// embedded, along with names of addons to be deleted
+func (a *applier) userAddons(s *state.State) (map[string]string, []string, error) {
+	customAddons, err := fs.ReadDir(a.LocalFS, ".")
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to read addons directory")
 	}
 
 	combinedAddons := map[string]string{}
@@ -94,16 +176,14 @@ func EnsureUserAddons(s *state.State) error {
 		}
 	}
 
+	deletedAddons := []string{}
 	for _, embeddedAddon := range s.Cluster.Addons.Addons {
 		if _, ok := embeddedAddons[embeddedAddon.Name]; ok {
 			continue
 		}
 
 		if embeddedAddon.Delete {
-			s.Logger.Infof("Deleting addon %q...", embeddedAddon.Name)
-			if err := applier.loadAndDeleteAddon(s, applier.EmbededFS, embeddedAddon.Name); err != nil {
-				return errors.Wrapf(err, "failed to load and delete the addon %q", embeddedAddon.Name)
-			}
+			deletedAddons = append(deletedAddons, embeddedAddon.Name)
 			continue
 		}
 
@@ -112,20 +192,7 @@ func EnsureUserAddons(s *state.State) error {
 		}
 	}
 
-	for addonName := range combinedAddons {
-		s.Logger.Infof("Applying addon %q...", addonName)
-
-		if err := EnsureAddonByName(s, addonName); err != nil {
-			return errors.Wrapf(err, "failed to load and apply the addon %q", addonName)
-		}
-	}
-
-	s.Logger.Info("Applying addons from the root directory...")
-	if err := applier.loadAndApplyAddon(s, applier.LocalFS, ""); err != nil {
-		return errors.Wrap(err, "failed to load and apply addons from the root directory")
-	}
-
-	return nil
+	return combinedAddons, deletedAddons, nil
 }
 
 // EnsureAddonByName deploys an addon by its name. If the addon is not found
@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"crypto/x509"
+	"time"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/certificate"
+	"k8c.io/kubeone/pkg/templates/resources"
+)
+
+const hubbleCACommonName = "hubble-ca.cilium.io"
+
+// hubbleCertificates generates the Hubble CA, and the Hubble server and the
+// Hubble Relay client certificates signed by it. Hubble uses the dedicated CA,
+// because the certificates signed by the Kubernetes CA could be used to
+// authenticate to the kube-apiserver.
+func hubbleCertificates(validity time.Duration, algorithm kubeoneapi.KeyAlgorithm) (map[string]string, error) {
+	caCertPEM, caKeyPEM, err := certificate.NewCA(hubbleCACommonName, algorithm)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate Hubble CA")
+	}
+
+	caKey, caCert, err := certificate.ParseCAKeyPair(caCertPEM, caKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	issuer := certificate.NewKeyIssuer(caKey, caCert, algorithm)
+
+	serverCertsMap, err := certificate.NewSignedCert(
+		resources.HubbleServerCommonName,
+		[]string{resources.HubbleServerCommonName},
+		[]x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		validity,
+		issuer,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	relayClientCertsMap, err := certificate.NewSignedCert(
+		resources.HubbleRelayClientCommonName,
+		[]string{resources.HubbleRelayClientCommonName},
+		[]x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		validity,
+		issuer,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		"HubbleCA":              string(caCertPEM),
+		"HubbleServerCert":      serverCertsMap[resources.TLSCertName],
+		"HubbleServerKey":       serverCertsMap[resources.TLSKeyName],
+		"HubbleRelayClientCert": relayClientCertsMap[resources.TLSCertName],
+		"HubbleRelayClientKey":  relayClientCertsMap[resources.TLSKeyName],
+	}, nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+)
+
+func TestHubbleCertificates(t *testing.T) {
+	t.Parallel()
+
+	certs, err := hubbleCertificates(time.Hour, kubeoneapi.KeyAlgorithmECDSA)
+	if err != nil {
+		t.Fatalf("hubbleCertificates() error = %v", err)
+	}
+
+	parse := func(name string) *x509.Certificate {
+		block, _ := pem.Decode([]byte(certs[name]))
+		if block == nil {
+			t.Fatalf("%s is not PEM encoded", name)
+		}
+		cert, parseErr := x509.ParseCertificate(block.Bytes)
+		if parseErr != nil {
+			t.Fatalf("unable to parse %s: %v", name, parseErr)
+		}
+
+		return cert
+	}
+
+	ca := parse("HubbleCA")
+	if !ca.IsCA || ca.Subject.CommonName != hubbleCACommonName {
+		t.Errorf("HubbleCA is not the dedicated Hubble CA: %s", ca.Subject.CommonName)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+
+	tests := []struct {
+		name  string
+		usage x509.ExtKeyUsage
+	}{
+		{name: "HubbleServerCert", usage: x509.ExtKeyUsageServerAuth},
+		{name: "HubbleRelayClientCert", usage: x509.ExtKeyUsageClientAuth},
+	}
+
+	for _, tt := range tests {
+		cert := parse(tt.name)
+
+		if len(cert.ExtKeyUsage) != 1 || cert.ExtKeyUsage[0] != tt.usage {
+			t.Errorf("%s ExtKeyUsage = %v, expected only %v", tt.name, cert.ExtKeyUsage, tt.usage)
+		}
+
+		if _, err := cert.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{tt.usage}}); err != nil {
+			t.Errorf("%s is not signed by the Hubble CA: %v", tt.name, err)
+		}
+	}
+}
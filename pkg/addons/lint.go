@@ -0,0 +1,425 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/certificate"
+	"k8c.io/kubeone/pkg/manifestpatch"
+	"k8c.io/kubeone/pkg/state"
+
+	metav1unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// LintSeverityError is the severity of problems that prevent the addon
+	// from being applied, or that are against the configured rules
+	LintSeverityError = "error"
+	// LintSeverityWarning is the severity of problems that don't prevent the
+	// addon from being applied
+	LintSeverityWarning = "warning"
+
+	// LintRuleTemplate reports templates that can't be parsed or executed
+	LintRuleTemplate = "template"
+	// LintRuleMissingParameter reports params used by the template, but not
+	// provided in the manifest
+	LintRuleMissingParameter = "missing-parameter"
+	// LintRuleInvalidManifest reports rendered manifests that aren't valid
+	// Kubernetes objects
+	LintRuleInvalidManifest = "invalid-manifest"
+	// LintRuleDeprecatedAPI reports objects using deprecated or removed API
+	// versions
+	LintRuleDeprecatedAPI = "deprecated-api"
+	// LintRuleMissingLabel reports objects without the required labels
+	LintRuleMissingLabel = "missing-label"
+)
+
+// LintResult is a problem found by Lint
+type LintResult struct {
+	Addon    string `json:"addon"`
+	File     string `json:"file,omitempty"`
+	Object   string `json:"object,omitempty"`
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`
+	Message  string `json:"message"`
+}
+
+// LintOptions configures Lint
+type LintOptions struct {
+	// RequiredLabels are labels that all objects must have
+	RequiredLabels []string
+}
+
+// deprecatedAPI is an API version deprecated or removed in the given
+// Kubernetes version
+type deprecatedAPI struct {
+	groupVersion string
+	// kinds affected by the deprecation, all kinds if empty
+	kinds        []string
+	deprecatedIn string
+	removedIn    string
+	replacement  string
+}
+
+var deprecatedAPIs = []deprecatedAPI{
+	{groupVersion: "extensions/v1beta1", kinds: []string{"Deployment", "DaemonSet", "ReplicaSet"}, deprecatedIn: "1.9", removedIn: "1.16", replacement: "apps/v1"},
+	{groupVersion: "extensions/v1beta1", kinds: []string{"NetworkPolicy"}, deprecatedIn: "1.9", removedIn: "1.16", replacement: "networking.k8s.io/v1"},
+	{groupVersion: "extensions/v1beta1", kinds: []string{"PodSecurityPolicy"}, deprecatedIn: "1.10", removedIn: "1.16", replacement: "policy/v1beta1"},
+	{groupVersion: "extensions/v1beta1", kinds: []string{"Ingress"}, deprecatedIn: "1.14", removedIn: "1.22", replacement: "networking.k8s.io/v1"},
+	{groupVersion: "apps/v1beta1", deprecatedIn: "1.9", removedIn: "1.16", replacement: "apps/v1"},
+	{groupVersion: "apps/v1beta2", deprecatedIn: "1.9", removedIn: "1.16", replacement: "apps/v1"},
+	{groupVersion: "admissionregistration.k8s.io/v1beta1", deprecatedIn: "1.16", removedIn: "1.22", replacement: "admissionregistration.k8s.io/v1"},
+	{groupVersion: "apiextensions.k8s.io/v1beta1", deprecatedIn: "1.16", removedIn: "1.22", replacement: "apiextensions.k8s.io/v1"},
+	{groupVersion: "apiregistration.k8s.io/v1beta1", deprecatedIn: "1.19", removedIn: "1.22", replacement: "apiregistration.k8s.io/v1"},
+	{groupVersion: "certificates.k8s.io/v1beta1", deprecatedIn: "1.19", removedIn: "1.22", replacement: "certificates.k8s.io/v1"},
+	{groupVersion: "coordination.k8s.io/v1beta1", deprecatedIn: "1.19", removedIn: "1.22", replacement: "coordination.k8s.io/v1"},
+	{groupVersion: "networking.k8s.io/v1beta1", kinds: []string{"Ingress", "IngressClass"}, deprecatedIn: "1.19", removedIn: "1.22", replacement: "networking.k8s.io/v1"},
+	{groupVersion: "rbac.authorization.k8s.io/v1beta1", deprecatedIn: "1.17", removedIn: "1.22", replacement: "rbac.authorization.k8s.io/v1"},
+	{groupVersion: "scheduling.k8s.io/v1beta1", deprecatedIn: "1.14", removedIn: "1.22", replacement: "scheduling.k8s.io/v1"},
+	{groupVersion: "storage.k8s.io/v1beta1", kinds: []string{"CSIDriver", "CSINode", "StorageClass", "VolumeAttachment"}, deprecatedIn: "1.19", removedIn: "1.22", replacement: "storage.k8s.io/v1"},
+	{groupVersion: "batch/v1beta1", kinds: []string{"CronJob"}, deprecatedIn: "1.21", removedIn: "1.25", replacement: "batch/v1"},
+	{groupVersion: "discovery.k8s.io/v1beta1", kinds: []string{"EndpointSlice"}, deprecatedIn: "1.21", removedIn: "1.25", replacement: "discovery.k8s.io/v1"},
+	{groupVersion: "events.k8s.io/v1beta1", kinds: []string{"Event"}, deprecatedIn: "1.21", removedIn: "1.25", replacement: "events.k8s.io/v1"},
+	{groupVersion: "policy/v1beta1", kinds: []string{"PodDisruptionBudget"}, deprecatedIn: "1.21", removedIn: "1.25", replacement: "policy/v1"},
+	{groupVersion: "policy/v1beta1", kinds: []string{"PodSecurityPolicy"}, deprecatedIn: "1.21", removedIn: "1.25"},
+	{groupVersion: "autoscaling/v2beta1", kinds: []string{"HorizontalPodAutoscaler"}, deprecatedIn: "1.22", removedIn: "1.25", replacement: "autoscaling/v2"},
+	{groupVersion: "node.k8s.io/v1beta1", kinds: []string{"RuntimeClass"}, deprecatedIn: "1.22", removedIn: "1.25", replacement: "node.k8s.io/v1"},
+	{groupVersion: "autoscaling/v2beta2", kinds: []string{"HorizontalPodAutoscaler"}, deprecatedIn: "1.23", removedIn: "1.26", replacement: "autoscaling/v2"},
+}
+
+// Lint renders the addons the same way as EnsureUserAddons, and reports
+// template errors, params missing in the manifest, invalid manifests,
+// deprecated API versions and objects without the required labels. The
+// cluster is not accessed, so the Kubernetes CA is generated if it's not
+// downloaded.
+func Lint(s *state.State, opts LintOptions) ([]LintResult, error) {
+	if !s.Cluster.Addons.Enabled() {
+		return nil, errors.New("addons are not enabled in the manifest")
+	}
+
+	kubernetesVersion, err := semver.NewVersion(s.Cluster.Versions.Kubernetes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse Kubernetes version")
+	}
+
+	if _, ok := s.Configuration.KubernetesPKI[certificate.KubernetesCACertPath]; !ok {
+		if err = certificate.GenerateKubeCA(s); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.LiveCluster == nil {
+		// The cluster is not probed, so the addons are rendered as for a new cluster
+		s.LiveCluster = &state.Cluster{}
+	}
+
+	applier, err := newAddonsApplier(s)
+	if err != nil {
+		return nil, err
+	}
+
+	addonNames, _, err := applier.userAddons(s)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for name := range addonNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	// Addons in the root of the addons directory
+	names = append(names, "")
+
+	l := &linter{
+		state:             s,
+		applier:           applier,
+		opts:              opts,
+		kubernetesVersion: kubernetesVersion,
+	}
+
+	for _, name := range names {
+		fsys := applier.LocalFS
+		if name != "" {
+			if info, statErr := fs.Stat(applier.LocalFS, name); statErr != nil || !info.IsDir() {
+				fsys = applier.EmbededFS
+			}
+		}
+
+		if err := l.lintAddon(fsys, name); err != nil {
+			return nil, err
+		}
+	}
+
+	return l.results, nil
+}
+
+type linter struct {
+	state             *state.State
+	applier           *applier
+	opts              LintOptions
+	kubernetesVersion *semver.Version
+	results           []LintResult
+}
+
+func (l *linter) report(result LintResult) {
+	l.results = append(l.results, result)
+}
+
+func (l *linter) lintAddon(fsys fs.FS, addonName string) error {
+	files, err := fs.ReadDir(fsys, filepath.Join(".", addonName))
+	if err != nil {
+		return errors.Wrapf(err, "failed to read the addons directory %s", addonName)
+	}
+
+	// Params are not resolved from the secret stores, as only the names
+	// are needed to detect missing params
+	addonParams := map[string]string{}
+	for _, addon := range l.state.Cluster.Addons.Addons {
+		if addon.Name == addonName {
+			addonParams = addon.Params
+			break
+		}
+	}
+	tplData := l.applier.addonTemplateData(addonParams)
+
+	overwriteRegistry := ""
+	if l.state.Cluster.RegistryConfiguration != nil {
+		overwriteRegistry = l.state.Cluster.RegistryConfiguration.OverwriteRegistry
+	}
+
+	for _, file := range files {
+		switch strings.ToLower(filepath.Ext(file.Name())) {
+		case ".yaml", ".yml", ".json":
+		default:
+			continue
+		}
+		if file.IsDir() {
+			continue
+		}
+
+		filePath := filepath.Join(addonName, file.Name())
+		result := LintResult{Addon: addonName, File: filePath}
+
+		manifestBytes, err := fs.ReadFile(fsys, filePath)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load addon %s", file.Name())
+		}
+
+		tpl, err := template.New("addons-base").Funcs(txtFuncMap(overwriteRegistry)).Parse(string(manifestBytes))
+		if err != nil {
+			l.report(withProblem(result, LintSeverityError, LintRuleTemplate, err.Error()))
+			continue
+		}
+
+		missingParams := []string{}
+		for _, param := range requiredParams(tpl.Tree) {
+			if _, ok := tplData.Params[param]; !ok {
+				missingParams = append(missingParams, param)
+			}
+		}
+		for _, param := range missingParams {
+			l.report(withProblem(result, LintSeverityError, LintRuleMissingParameter, fmt.Sprintf("param %q is used, but not provided", param)))
+		}
+
+		buf := bytes.NewBuffer([]byte{})
+		if err = tpl.Execute(buf, tplData); err != nil {
+			l.report(withProblem(result, LintSeverityError, LintRuleTemplate, err.Error()))
+			continue
+		}
+
+		manifests, err := decodeManifests(buf, file.Name())
+		if err != nil {
+			l.report(withProblem(result, LintSeverityError, LintRuleInvalidManifest, err.Error()))
+			continue
+		}
+
+		manifests, err = manifestpatch.Apply(manifests, l.state.Cluster.ManifestPatches)
+		if err != nil {
+			l.report(withProblem(result, LintSeverityError, LintRuleInvalidManifest, err.Error()))
+			continue
+		}
+
+		for _, m := range manifests {
+			obj := &metav1unstructured.Unstructured{}
+			if _, _, err := metav1unstructured.UnstructuredJSONScheme.Decode(m.Raw, nil, obj); err != nil {
+				l.report(withProblem(result, LintSeverityError, LintRuleInvalidManifest, err.Error()))
+				continue
+			}
+
+			l.lintObject(result, obj)
+		}
+	}
+
+	return nil
+}
+
+func (l *linter) lintObject(result LintResult, obj *metav1unstructured.Unstructured) {
+	result.Object = fmt.Sprintf("%s %s", obj.GetKind(), obj.GetName())
+	if obj.GetNamespace() != "" {
+		result.Object = fmt.Sprintf("%s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+	}
+
+	if obj.GetName() == "" && obj.GetGenerateName() == "" {
+		l.report(withProblem(result, LintSeverityError, LintRuleInvalidManifest, "object has no name"))
+	}
+
+	for _, api := range deprecatedAPIs {
+		if !api.matches(obj.GetAPIVersion(), obj.GetKind()) {
+			continue
+		}
+
+		replacement := ""
+		if api.replacement != "" {
+			replacement = fmt.Sprintf(", use %s instead", api.replacement)
+		}
+
+		switch {
+		case !l.kubernetesVersion.LessThan(semver.MustParse(api.removedIn)):
+			l.report(withProblem(result, LintSeverityError, LintRuleDeprecatedAPI,
+				fmt.Sprintf("%s %s is removed in Kubernetes %s%s", obj.GetAPIVersion(), obj.GetKind(), api.removedIn, replacement)))
+		case !l.kubernetesVersion.LessThan(semver.MustParse(api.deprecatedIn)):
+			l.report(withProblem(result, LintSeverityWarning, LintRuleDeprecatedAPI,
+				fmt.Sprintf("%s %s is deprecated since Kubernetes %s and removed in %s%s", obj.GetAPIVersion(), obj.GetKind(), api.deprecatedIn, api.removedIn, replacement)))
+		}
+	}
+
+	labels := obj.GetLabels()
+	for _, label := range l.opts.RequiredLabels {
+		if _, ok := labels[label]; !ok {
+			l.report(withProblem(result, LintSeverityError, LintRuleMissingLabel, fmt.Sprintf("object doesn't have the required label %q", label)))
+		}
+	}
+}
+
+func (api deprecatedAPI) matches(apiVersion, kind string) bool {
+	if api.groupVersion != apiVersion {
+		return false
+	}
+	if len(api.kinds) == 0 {
+		return true
+	}
+	for _, k := range api.kinds {
+		if k == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+func withProblem(result LintResult, severity, rule, message string) LintResult {
+	result.Severity = severity
+	result.Rule = rule
+	result.Message = message
+
+	return result
+}
+
+// requiredParams returns names of the params used by the template. Params
+// used only as arguments of the default function, or in if and with
+// conditions, are optional and not returned.
+func requiredParams(tree *parse.Tree) []string {
+	found := map[string]bool{}
+	if tree != nil && tree.Root != nil {
+		walkParamsNode(tree.Root, found)
+	}
+
+	params := []string{}
+	for param := range found {
+		params = append(params, param)
+	}
+	sort.Strings(params)
+
+	return params
+}
+
+func walkParamsNode(node parse.Node, found map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			walkParamsNode(child, found)
+		}
+	case *parse.ActionNode:
+		walkParamsPipe(n.Pipe, false, found)
+	case *parse.IfNode:
+		walkParamsPipe(n.Pipe, true, found)
+		walkParamsNode(n.List, found)
+		walkParamsNode(n.ElseList, found)
+	case *parse.WithNode:
+		walkParamsPipe(n.Pipe, true, found)
+		walkParamsNode(n.List, found)
+		walkParamsNode(n.ElseList, found)
+	case *parse.RangeNode:
+		walkParamsPipe(n.Pipe, false, found)
+		walkParamsNode(n.List, found)
+		walkParamsNode(n.ElseList, found)
+	case *parse.TemplateNode:
+		walkParamsPipe(n.Pipe, false, found)
+	}
+}
+
+func walkParamsPipe(pipe *parse.PipeNode, optional bool, found map[string]bool) {
+	if pipe == nil {
+		return
+	}
+
+	for i, cmd := range pipe.Cmds {
+		// {{ .Params.foo | default "bar" }}
+		cmdOptional := optional || (i+1 < len(pipe.Cmds) && isDefaultCommand(pipe.Cmds[i+1]))
+		// {{ default "bar" .Params.foo }}
+		cmdOptional = cmdOptional || isDefaultCommand(cmd)
+
+		for _, arg := range cmd.Args {
+			switch a := arg.(type) {
+			case *parse.FieldNode:
+				if !cmdOptional && len(a.Ident) > 1 && a.Ident[0] == "Params" {
+					found[a.Ident[1]] = true
+				}
+			case *parse.VariableNode:
+				if !cmdOptional && len(a.Ident) > 2 && a.Ident[0] == "$" && a.Ident[1] == "Params" {
+					found[a.Ident[2]] = true
+				}
+			case *parse.PipeNode:
+				walkParamsPipe(a, cmdOptional, found)
+			}
+		}
+	}
+}
+
+func isDefaultCommand(cmd *parse.CommandNode) bool {
+	if len(cmd.Args) == 0 {
+		return false
+	}
+	ident, ok := cmd.Args[0].(*parse.IdentifierNode)
+
+	return ok && ident.Ident == "default"
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"reflect"
+	"testing"
+	"text/template"
+
+	"github.com/Masterminds/semver/v3"
+
+	metav1unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestRequiredParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     []string
+	}{
+		{
+			name:     "no params",
+			template: `value: {{ .Config.Name }}`,
+			want:     []string{},
+		},
+		{
+			name:     "required params",
+			template: `password: {{ .Params.password | b64enc }}\nbucket: "{{ .Params.bucket }}"\nregion: {{ $.Params.region }}`,
+			want:     []string{"bucket", "password", "region"},
+		},
+		{
+			name:     "optional params",
+			template: `mtu: {{ default 0 .Params.mtu }}\nimage: {{ .Params.image | default "test" }}\n{{ if .Params.debug }}debug: true{{ end }}\n{{ with .Params.level }}level: {{ . }}{{ end }}`,
+			want:     []string{},
+		},
+		{
+			name:     "params inside conditional blocks",
+			template: `{{ if .Config.Name }}name: {{ .Params.name }}{{ else }}name: {{ .Params.fallback }}{{ end }}`,
+			want:     []string{"fallback", "name"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tpl, err := template.New("addons-base").Funcs(txtFuncMap("")).Parse(tt.template)
+			if err != nil {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+
+			got := requiredParams(tpl.Tree)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("requiredParams() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintObject(t *testing.T) {
+	tests := []struct {
+		name              string
+		kubernetesVersion string
+		object            map[string]interface{}
+		requiredLabels    []string
+		want              []string
+	}{
+		{
+			name:              "valid object",
+			kubernetesVersion: "1.22.2",
+			object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata": map[string]interface{}{
+					"name":   "test",
+					"labels": map[string]interface{}{"owner": "platform"},
+				},
+			},
+			requiredLabels: []string{"owner"},
+			want:           nil,
+		},
+		{
+			name:              "removed API",
+			kubernetesVersion: "1.22.2",
+			object: map[string]interface{}{
+				"apiVersion": "rbac.authorization.k8s.io/v1beta1",
+				"kind":       "ClusterRole",
+				"metadata":   map[string]interface{}{"name": "test"},
+			},
+			want: []string{LintSeverityError + "/" + LintRuleDeprecatedAPI},
+		},
+		{
+			name:              "deprecated API",
+			kubernetesVersion: "1.21.5",
+			object: map[string]interface{}{
+				"apiVersion": "policy/v1beta1",
+				"kind":       "PodDisruptionBudget",
+				"metadata":   map[string]interface{}{"name": "test"},
+			},
+			want: []string{LintSeverityWarning + "/" + LintRuleDeprecatedAPI},
+		},
+		{
+			name:              "API deprecated in a newer version",
+			kubernetesVersion: "1.20.11",
+			object: map[string]interface{}{
+				"apiVersion": "batch/v1beta1",
+				"kind":       "CronJob",
+				"metadata":   map[string]interface{}{"name": "test"},
+			},
+			want: nil,
+		},
+		{
+			name:              "missing name and labels",
+			kubernetesVersion: "1.22.2",
+			object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]interface{}{"labels": map[string]interface{}{"owner": "platform"}},
+			},
+			requiredLabels: []string{"owner", "team"},
+			want: []string{
+				LintSeverityError + "/" + LintRuleInvalidManifest,
+				LintSeverityError + "/" + LintRuleMissingLabel,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			l := &linter{
+				opts:              LintOptions{RequiredLabels: tt.requiredLabels},
+				kubernetesVersion: semver.MustParse(tt.kubernetesVersion),
+			}
+
+			l.lintObject(LintResult{Addon: "test"}, &metav1unstructured.Unstructured{Object: tt.object})
+
+			var got []string
+			for _, result := range l.results {
+				got = append(got, result.Severity+"/"+result.Rule)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lintObject() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
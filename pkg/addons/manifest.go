@@ -32,6 +32,9 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"k8c.io/kubeone/pkg/certificate/cabundle"
+	"k8c.io/kubeone/pkg/credentials"
+	"k8c.io/kubeone/pkg/manifestpatch"
+	"k8c.io/kubeone/pkg/secretstore"
 	"k8c.io/kubeone/pkg/state"
 
 	corev1 "k8s.io/api/core/v1"
@@ -51,7 +54,11 @@ func (a *applier) getManifestsFromDirectory(s *state.State, fsys fs.FS, addonNam
 	if s.Cluster.Addons.Enabled() {
 		for _, addon := range s.Cluster.Addons.Addons {
 			if addon.Name == addonName {
-				addonParams = addon.Params
+				var err error
+				addonParams, err = secretstore.ResolveMap(s.Context, addon.Params)
+				if err != nil {
+					return "", errors.Wrapf(err, "failed to resolve params of addon %q", addonName)
+				}
 				break
 			}
 		}
@@ -62,6 +69,11 @@ func (a *applier) getManifestsFromDirectory(s *state.State, fsys fs.FS, addonNam
 		return "", err
 	}
 
+	manifests, err = manifestpatch.Apply(manifests, s.Cluster.ManifestPatches)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to patch manifests of addon %q", addonName)
+	}
+
 	rawManifests, err := ensureAddonsLabelsOnResources(manifests, addonName)
 	if err != nil {
 		return "", err
@@ -119,20 +131,8 @@ func (a *applier) loadAddonsManifests(
 			return nil, errors.Wrapf(err, "failed to template addons manifest %s", file.Name())
 		}
 
-		// Make a copy and merge Params
-		tplDataParams := map[string]string{}
-		for k, v := range a.TemplateData.Params {
-			tplDataParams[k] = v
-		}
-		for k, v := range addonParams {
-			tplDataParams[k] = v
-		}
-
-		tplData := a.TemplateData
-		tplData.Params = tplDataParams
-
 		buf := bytes.NewBuffer([]byte{})
-		if err := tpl.Execute(buf, tplData); err != nil {
+		if err := tpl.Execute(buf, a.addonTemplateData(addonParams)); err != nil {
 			return nil, errors.Wrapf(err, "failed to template addons manifest %s", file.Name())
 		}
 
@@ -141,34 +141,65 @@ func (a *applier) loadAddonsManifests(
 			logger.Infof("Addons manifest '%s' is empty after parsing. Skipping.\n", file.Name())
 		}
 
-		reader := kyaml.NewYAMLReader(bufio.NewReader(buf))
-		for {
-			b, err := reader.Read()
-			if err != nil {
-				if err == io.EOF {
-					break
-				}
-				return nil, errors.Wrapf(err, "failed reading from YAML reader for manifest %s", file.Name())
-			}
+		fileManifests, err := decodeManifests(buf, file.Name())
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, fileManifests...)
+	}
 
-			b = bytes.TrimSpace(b)
-			if len(b) == 0 {
-				continue
-			}
+	return manifests, nil
+}
 
-			decoder := kyaml.NewYAMLToJSONDecoder(bytes.NewBuffer(b))
-			raw := runtime.RawExtension{}
-			if err := decoder.Decode(&raw); err != nil {
-				return nil, errors.Wrapf(err, "failed to decode manifest %s", file.Name())
-			}
+// addonTemplateData returns a copy of the template data with addon params
+// merged into the global params
+func (a *applier) addonTemplateData(addonParams map[string]string) templateData {
+	tplDataParams := map[string]string{}
+	for k, v := range a.TemplateData.Params {
+		tplDataParams[k] = v
+	}
+	for k, v := range addonParams {
+		tplDataParams[k] = v
+	}
+
+	tplData := a.TemplateData
+	tplData.Params = tplDataParams
+
+	return tplData
+}
+
+// decodeManifests splits the multi-document YAML manifest and decodes each
+// document to JSON
+func decodeManifests(r io.Reader, fileName string) ([]runtime.RawExtension, error) {
+	var manifests []runtime.RawExtension
 
-			if len(raw.Raw) == 0 {
-				// This can happen if the manifest contains only comments
-				continue
+	reader := kyaml.NewYAMLReader(bufio.NewReader(r))
+	for {
+		b, err := reader.Read()
+		if err != nil {
+			if err == io.EOF {
+				break
 			}
+			return nil, errors.Wrapf(err, "failed reading from YAML reader for manifest %s", fileName)
+		}
 
-			manifests = append(manifests, raw)
+		b = bytes.TrimSpace(b)
+		if len(b) == 0 {
+			continue
 		}
+
+		decoder := kyaml.NewYAMLToJSONDecoder(bytes.NewBuffer(b))
+		raw := runtime.RawExtension{}
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode manifest %s", fileName)
+		}
+
+		if len(raw.Raw) == 0 {
+			// This can happen if the manifest contains only comments
+			continue
+		}
+
+		manifests = append(manifests, raw)
 	}
 
 	return manifests, nil
@@ -243,6 +274,11 @@ func txtFuncMap(overwriteRegistry string) template.FuncMap {
 		return registry
 	}
 
+	funcs["toYaml"] = func(v interface{}) (string, error) {
+		buf, err := yaml.Marshal(v)
+		return strings.TrimSuffix(string(buf), "\n"), err
+	}
+
 	funcs["caBundleEnvVar"] = func() (string, error) {
 		buf, err := yaml.Marshal([]corev1.EnvVar{cabundle.EnvVar()})
 		return string(buf), err
@@ -271,6 +307,8 @@ func txtFuncMap(overwriteRegistry string) template.FuncMap {
 		return string(buf), err
 	}
 
+	funcs["openstackCloudConfig"] = credentials.OpenstackCloudConfig
+
 	funcs["vSphereCSIWebhookConfig"] = func() (string, error) {
 		cfg := vsphereCSIWebhookConfigWrapper{
 			WebHookConfig: vsphereCSIWebhookConfig{
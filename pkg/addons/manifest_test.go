@@ -18,6 +18,7 @@ package addons
 
 import (
 	"bytes"
+	"encoding/base64"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -25,8 +26,16 @@ import (
 	"strings"
 	"testing"
 	"text/template"
+	"time"
 
+	"github.com/sirupsen/logrus"
+
+	embeddedaddons "k8c.io/kubeone/addons"
 	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/templates/images"
+	"k8c.io/kubeone/pkg/templates/resources"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var testManifests = []string{
@@ -306,6 +315,768 @@ func TestImageRegistryParsing(t *testing.T) {
 	}
 }
 
+func TestClusterAutoscalerArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		autoscaler *kubeoneapi.ClusterAutoscaler
+		expected   []string
+		unexpected []string
+	}{
+		{
+			name:       "addon without the feature",
+			autoscaler: nil,
+			expected:   []string{"--cloud-provider=clusterapi", "--node-group-auto-discovery=clusterapi:namespace=kube-system"},
+			unexpected: []string{"--expander", "--scale-down-unneeded-time", "--balance-similar-node-groups"},
+		},
+		{
+			name:       "feature defaults",
+			autoscaler: &kubeoneapi.ClusterAutoscaler{Enable: true},
+			expected:   []string{"--node-group-auto-discovery=clusterapi:namespace=kube-system", "--expander=random"},
+			unexpected: []string{"--scale-down-unneeded-time", "--scale-down-delay-after-add", "--balance-similar-node-groups"},
+		},
+		{
+			name: "feature configured",
+			autoscaler: &kubeoneapi.ClusterAutoscaler{
+				Enable:                   true,
+				Expander:                 "least-waste",
+				ScaleDownUnneededTime:    &metav1.Duration{Duration: 5 * time.Minute},
+				ScaleDownDelayAfterAdd:   &metav1.Duration{Duration: 15 * time.Minute},
+				BalanceSimilarNodeGroups: true,
+			},
+			expected: []string{
+				"--expander=least-waste",
+				"--scale-down-unneeded-time=5m0s",
+				"--scale-down-delay-after-add=15m0s",
+				"--balance-similar-node-groups=true",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			applier := &applier{
+				TemplateData: templateData{
+					Config: &kubeoneapi.KubeOneCluster{
+						Name:     "kubeone-test",
+						Features: kubeoneapi.Features{ClusterAutoscaler: tc.autoscaler},
+					},
+					InternalImages: &internalImages{
+						resolver: images.NewResolver(images.WithKubernetesVersionGetter(func() string { return "1.22.3" })).Get,
+					},
+				},
+				EmbededFS: embeddedaddons.F,
+			}
+
+			manifests, err := applier.loadAddonsManifests(applier.EmbededFS, resources.AddonClusterAutoscaler, nil, nil, false, "")
+			if err != nil {
+				t.Fatalf("unable to load manifests: %v", err)
+			}
+
+			var buf bytes.Buffer
+			for _, m := range manifests {
+				buf.Write(m.Raw)
+			}
+
+			for _, arg := range tc.expected {
+				if !strings.Contains(buf.String(), `"`+arg+`"`) {
+					t.Errorf("expected the argument %q in the manifest", arg)
+				}
+			}
+			for _, arg := range tc.unexpected {
+				if strings.Contains(buf.String(), arg) {
+					t.Errorf("unexpected argument %q in the manifest", arg)
+				}
+			}
+		})
+	}
+}
+
+func TestVsphereCSIAddon(t *testing.T) {
+	tests := []struct {
+		name       string
+		csi        *kubeoneapi.VsphereCSIConfig
+		expected   []string
+		unexpected []string
+	}{
+		{
+			name:       "csi config provided by the user",
+			csi:        nil,
+			expected:   []string{`"block-volume-snapshot":"false"`},
+			unexpected: []string{`"csi-snapshotter"`, `"VolumeSnapshotClass"`, `"csi-snapshot-controller"`, `"StorageClass"`},
+		},
+		{
+			name: "storage class and snapshots",
+			csi: &kubeoneapi.VsphereCSIConfig{
+				Datacenters: []string{"dc-1"},
+				StorageClass: &kubeoneapi.VsphereCSIStorageClass{
+					DatastoreURL:      "ds:///vmfs/volumes/vsan:52a8/",
+					StoragePolicyName: "gold",
+				},
+				Snapshots: true,
+			},
+			expected: []string{
+				`"block-volume-snapshot":"true"`,
+				`"name":"csi-snapshotter"`,
+				`"name":"csi-snapshot-controller"`,
+				`"kind":"VolumeSnapshotClass"`,
+				`"datastoreurl":"ds:///vmfs/volumes/vsan:52a8/"`,
+				`"storagepolicyname":"gold"`,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			applier := &applier{
+				TemplateData: templateData{
+					Config: &kubeoneapi.KubeOneCluster{
+						Name: "kubeone-test",
+						CloudProvider: kubeoneapi.CloudProviderSpec{
+							External: true,
+							Vsphere:  &kubeoneapi.VsphereSpec{CSI: tc.csi},
+						},
+					},
+					InternalImages: &internalImages{
+						resolver: images.NewResolver(images.WithKubernetesVersionGetter(func() string { return "1.22.3" })).Get,
+					},
+				},
+				EmbededFS: embeddedaddons.F,
+			}
+
+			logger := logrus.New()
+			logger.SetOutput(ioutil.Discard)
+
+			manifests, err := applier.loadAddonsManifests(applier.EmbededFS, resources.AddonCSIVsphere, nil, logger, false, "")
+			if err != nil {
+				t.Fatalf("unable to load manifests: %v", err)
+			}
+
+			var buf bytes.Buffer
+			for _, m := range manifests {
+				buf.Write(m.Raw)
+			}
+
+			for _, s := range tc.expected {
+				if !strings.Contains(buf.String(), s) {
+					t.Errorf("expected %s in the manifest", s)
+				}
+			}
+			for _, s := range tc.unexpected {
+				if strings.Contains(buf.String(), s) {
+					t.Errorf("unexpected %s in the manifest", s)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenstackAddonsCloudConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		addon      string
+		openstack  *kubeoneapi.OpenstackSpec
+		expected   []string
+		unexpected []string
+	}{
+		{
+			name:       "ccm with the cloud-config",
+			addon:      resources.AddonCCMOpenStack,
+			openstack:  &kubeoneapi.OpenstackSpec{},
+			expected:   []string{`"secretName":"cloud-config"`},
+			unexpected: []string{`"name":"openstack-ccm-cloud-config"`},
+		},
+		{
+			name:  "ccm with cloud-config sections",
+			addon: resources.AddonCCMOpenStack,
+			openstack: &kubeoneapi.OpenstackSpec{
+				CCM: &kubeoneapi.OpenstackComponent{
+					CloudConfigSections: map[string]map[string]string{"LoadBalancer": {"use-octavia": "true"}},
+				},
+			},
+			expected: []string{`"name":"openstack-ccm-cloud-config"`, `"secretName":"openstack-ccm-cloud-config"`},
+		},
+		{
+			name:       "cinder csi with the cloud-config",
+			addon:      resources.AddonCSIOpenStackCinder,
+			openstack:  &kubeoneapi.OpenstackSpec{},
+			expected:   []string{`"secretName":"cloud-config"`},
+			unexpected: []string{`"name":"cinder-csi-cloud-config"`},
+		},
+		{
+			name:  "cinder csi with cloud-config sections",
+			addon: resources.AddonCSIOpenStackCinder,
+			openstack: &kubeoneapi.OpenstackSpec{
+				CinderCSI: &kubeoneapi.OpenstackComponent{
+					CloudConfigSections: map[string]map[string]string{"BlockStorage": {"ignore-volume-az": "true"}},
+				},
+			},
+			expected:   []string{`"name":"cinder-csi-cloud-config"`, `"secretName":"cinder-csi-cloud-config"`},
+			unexpected: []string{`"secretName":"cloud-config"`},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			applier := &applier{
+				TemplateData: templateData{
+					Config: &kubeoneapi.KubeOneCluster{
+						Name:     "kubeone-test",
+						Versions: kubeoneapi.VersionConfig{Kubernetes: "1.22.3"},
+						CloudProvider: kubeoneapi.CloudProviderSpec{
+							External:    true,
+							Openstack:   tc.openstack,
+							CloudConfig: "[Global]\nauth-url = \"https://keystone:5000/v3\"\n",
+						},
+					},
+					InternalImages: &internalImages{
+						resolver: images.NewResolver(images.WithKubernetesVersionGetter(func() string { return "1.22.3" })).Get,
+					},
+				},
+				EmbededFS: embeddedaddons.F,
+			}
+
+			logger := logrus.New()
+			logger.SetOutput(ioutil.Discard)
+
+			manifests, err := applier.loadAddonsManifests(applier.EmbededFS, tc.addon, nil, logger, false, "")
+			if err != nil {
+				t.Fatalf("unable to load manifests: %v", err)
+			}
+
+			var buf bytes.Buffer
+			for _, m := range manifests {
+				buf.Write(m.Raw)
+			}
+
+			for _, s := range tc.expected {
+				if !strings.Contains(buf.String(), s) {
+					t.Errorf("expected %s in the manifest", s)
+				}
+			}
+			for _, s := range tc.unexpected {
+				if strings.Contains(buf.String(), s) {
+					t.Errorf("unexpected %s in the manifest", s)
+				}
+			}
+		})
+	}
+}
+
+func TestAWSEBSCSICredentials(t *testing.T) {
+	creds := map[string]string{
+		"AWS_ACCESS_KEY_ID":     "key-id",
+		"AWS_SECRET_ACCESS_KEY": "secret-key",
+	}
+
+	tests := []struct {
+		name        string
+		ebsCSI      *kubeoneapi.AWSEBSCSIConfig
+		credentials map[string]string
+		wantSecret  bool
+	}{
+		{
+			name:        "credentials provided",
+			ebsCSI:      &kubeoneapi.AWSEBSCSIConfig{Enable: true},
+			credentials: creds,
+			wantSecret:  true,
+		},
+		{
+			name:        "credentials not provided",
+			ebsCSI:      &kubeoneapi.AWSEBSCSIConfig{Enable: true},
+			credentials: map[string]string{},
+			wantSecret:  false,
+		},
+		{
+			name:        "instance profile",
+			ebsCSI:      &kubeoneapi.AWSEBSCSIConfig{Enable: true, UseInstanceProfile: true},
+			credentials: creds,
+			wantSecret:  false,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			applier := &applier{
+				TemplateData: templateData{
+					Config: &kubeoneapi.KubeOneCluster{
+						Name: "kubeone-test",
+						CloudProvider: kubeoneapi.CloudProviderSpec{
+							External: true,
+							AWS:      &kubeoneapi.AWSSpec{EBSCSI: tc.ebsCSI},
+						},
+					},
+					Credentials: tc.credentials,
+					InternalImages: &internalImages{
+						resolver: images.NewResolver(images.WithKubernetesVersionGetter(func() string { return "1.22.3" })).Get,
+					},
+				},
+				EmbededFS: embeddedaddons.F,
+			}
+
+			manifests, err := applier.loadAddonsManifests(applier.EmbededFS, resources.AddonCSIAwsEBS, nil, nil, false, "")
+			if err != nil {
+				t.Fatalf("unable to load manifests: %v", err)
+			}
+
+			var buf bytes.Buffer
+			for _, m := range manifests {
+				buf.Write(m.Raw)
+			}
+
+			gotSecret := strings.Contains(buf.String(), `"name":"aws-ebs-csi-credentials","namespace":"kube-system"`)
+			if gotSecret != tc.wantSecret {
+				t.Errorf("credentials secret deployed = %t, want %t", gotSecret, tc.wantSecret)
+			}
+			if !strings.Contains(buf.String(), `"type":"gp3"`) {
+				t.Errorf("expected the gp3 StorageClass in the manifest")
+			}
+		})
+	}
+}
+
+func TestCertManagerAddons(t *testing.T) {
+	tests := []struct {
+		name          string
+		clusterIssuer *kubeoneapi.CertManagerClusterIssuer
+		want          []string
+		wantNo        []string
+	}{
+		{
+			name:          "cluster CA ClusterIssuer",
+			clusterIssuer: &kubeoneapi.CertManagerClusterIssuer{},
+			want: []string{
+				`"kind":"ClusterIssuer","metadata":{"name":"kubeone-ca"`,
+				`"ca":{"secretName":"kubeone-cluster-ca"}`,
+				`"name":"kubeone-cluster-ca","namespace":"cert-manager"`,
+				`"tls.key":"` + base64.StdEncoding.EncodeToString([]byte("ca-key")) + `"`,
+			},
+		},
+		{
+			name:          "provided CA ClusterIssuer",
+			clusterIssuer: &kubeoneapi.CertManagerClusterIssuer{Name: "my-issuer", CASecretName: "my-ca"},
+			want: []string{
+				`"kind":"ClusterIssuer","metadata":{"name":"my-issuer"`,
+				`"ca":{"secretName":"my-ca"}`,
+			},
+			wantNo: []string{
+				`"kind":"Secret"`,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			applier := &applier{
+				TemplateData: templateData{
+					Config: &kubeoneapi.KubeOneCluster{
+						Name: "kubeone-test",
+						Features: kubeoneapi.Features{
+							CertManager: &kubeoneapi.CertManager{Enable: true, ClusterIssuer: tc.clusterIssuer},
+						},
+					},
+					Certificates: map[string]string{
+						"KubernetesCA":    "ca-cert",
+						"KubernetesCAKey": "ca-key",
+					},
+					InternalImages: &internalImages{
+						resolver: images.NewResolver(images.WithKubernetesVersionGetter(func() string { return "1.22.3" })).Get,
+					},
+				},
+				EmbededFS: embeddedaddons.F,
+			}
+
+			manifests, err := applier.loadAddonsManifests(applier.EmbededFS, resources.AddonCertManager, nil, nil, false, "")
+			if err != nil {
+				t.Fatalf("unable to load cert-manager manifests: %v", err)
+			}
+
+			var buf bytes.Buffer
+			for _, m := range manifests {
+				buf.Write(m.Raw)
+			}
+			for _, image := range []string{"cert-manager-controller", "cert-manager-cainjector", "cert-manager-webhook"} {
+				if !strings.Contains(buf.String(), "quay.io/jetstack/"+image+":") {
+					t.Errorf("expected the %s image in the cert-manager manifest", image)
+				}
+			}
+
+			manifests, err = applier.loadAddonsManifests(applier.EmbededFS, resources.AddonCertManagerIssuer, nil, nil, false, "")
+			if err != nil {
+				t.Fatalf("unable to load ClusterIssuer manifests: %v", err)
+			}
+
+			buf.Reset()
+			for _, m := range manifests {
+				buf.Write(m.Raw)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("expected %s in the ClusterIssuer manifest, got %s", want, buf.String())
+				}
+			}
+			for _, wantNo := range tc.wantNo {
+				if strings.Contains(buf.String(), wantNo) {
+					t.Errorf("expected no %s in the ClusterIssuer manifest", wantNo)
+				}
+			}
+		})
+	}
+}
+
+func TestIngressNginxAddon(t *testing.T) {
+	tests := []struct {
+		name              string
+		cloudProvider     kubeoneapi.CloudProviderSpec
+		ingressController *kubeoneapi.IngressController
+		want              []string
+		wantNo            []string
+	}{
+		{
+			name:              "aws",
+			cloudProvider:     kubeoneapi.CloudProviderSpec{AWS: &kubeoneapi.AWSSpec{}},
+			ingressController: &kubeoneapi.IngressController{Enable: true},
+			want: []string{
+				`"type":"LoadBalancer"`,
+				`"kind":"Deployment"`,
+				`"replicas":2`,
+				`"--publish-service=$(POD_NAMESPACE)/ingress-nginx-controller"`,
+				`"kind":"IngressClass","metadata":{"labels":{"app.kubernetes.io/component":"controller","app.kubernetes.io/instance":"ingress-nginx","app.kubernetes.io/name":"ingress-nginx"},"name":"nginx"}`,
+			},
+			wantNo: []string{
+				`"hostNetwork":true`,
+				`ingressclass.kubernetes.io/is-default-class`,
+			},
+		},
+		{
+			name:              "vsphere with host network",
+			cloudProvider:     kubeoneapi.CloudProviderSpec{Vsphere: &kubeoneapi.VsphereSpec{}},
+			ingressController: &kubeoneapi.IngressController{Enable: true, HostNetwork: true},
+			want: []string{
+				`"type":"ClusterIP"`,
+				`"kind":"DaemonSet"`,
+				`"hostNetwork":true`,
+			},
+			wantNo: []string{
+				`"type":"NodePort"`,
+				`"--publish-service`,
+			},
+		},
+		{
+			name:          "none with ingress class",
+			cloudProvider: kubeoneapi.CloudProviderSpec{None: &kubeoneapi.NoneSpec{}},
+			ingressController: &kubeoneapi.IngressController{
+				Enable:              true,
+				Replicas:            3,
+				IngressClass:        "public",
+				DefaultIngressClass: true,
+			},
+			want: []string{
+				`"type":"NodePort"`,
+				`"replicas":3`,
+				`"--ingress-class=public"`,
+				`"annotations":{"ingressclass.kubernetes.io/is-default-class":"true"}`,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			applier := &applier{
+				TemplateData: templateData{
+					Config: &kubeoneapi.KubeOneCluster{
+						Name:          "kubeone-test",
+						CloudProvider: tc.cloudProvider,
+						Features: kubeoneapi.Features{
+							IngressController: tc.ingressController,
+						},
+					},
+					InternalImages: &internalImages{
+						resolver: images.NewResolver(images.WithKubernetesVersionGetter(func() string { return "1.22.3" })).Get,
+					},
+					ServingCerts: testServingCerts(t),
+				},
+				EmbededFS: embeddedaddons.F,
+			}
+
+			manifests, err := applier.loadAddonsManifests(applier.EmbededFS, resources.AddonIngressNginx, nil, nil, false, "")
+			if err != nil {
+				t.Fatalf("unable to load manifests: %v", err)
+			}
+
+			var buf bytes.Buffer
+			for _, m := range manifests {
+				buf.Write(m.Raw)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("expected %s in the manifest", want)
+				}
+			}
+			for _, wantNo := range tc.wantNo {
+				if strings.Contains(buf.String(), wantNo) {
+					t.Errorf("expected no %s in the manifest", wantNo)
+				}
+			}
+			if !strings.Contains(buf.String(), `"name":"ingress-nginx-admission","namespace":"ingress-nginx"`) {
+				t.Errorf("expected the admission webhook certificate secret in the manifest")
+			}
+		})
+	}
+}
+
+func TestFluxAddons(t *testing.T) {
+	tests := []struct {
+		name       string
+		repository kubeoneapi.GitOpsRepository
+		want       []string
+		wantNo     []string
+	}{
+		{
+			name:       "defaults",
+			repository: kubeoneapi.GitOpsRepository{URL: "https://github.com/example/fleet"},
+			want: []string{
+				`"url":"https://github.com/example/fleet"`,
+				`"ref":{"branch":"main"}`,
+				`"interval":"1m0s"`,
+				`"path":"./"`,
+			},
+			wantNo: []string{
+				`"secretRef"`,
+			},
+		},
+		{
+			name: "ssh repository",
+			repository: kubeoneapi.GitOpsRepository{
+				URL:        "ssh://git@github.com/example/fleet",
+				Branch:     "production",
+				Path:       "./clusters/production",
+				Interval:   &metav1.Duration{Duration: 5 * time.Minute},
+				SecretName: "flux-git-auth",
+			},
+			want: []string{
+				`"url":"ssh://git@github.com/example/fleet"`,
+				`"ref":{"branch":"production"}`,
+				`"secretRef":{"name":"flux-git-auth"}`,
+				`"interval":"5m0s"`,
+				`"path":"./clusters/production"`,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			applier := &applier{
+				TemplateData: templateData{
+					Config: &kubeoneapi.KubeOneCluster{
+						Name: "kubeone-test",
+						ClusterNetwork: kubeoneapi.ClusterNetworkConfig{
+							ServiceDomainName: "cluster.local",
+						},
+						Features: kubeoneapi.Features{
+							GitOps: &kubeoneapi.GitOps{Enable: true, Repository: tc.repository},
+						},
+					},
+					InternalImages: &internalImages{
+						resolver: images.NewResolver(images.WithKubernetesVersionGetter(func() string { return "1.22.3" })).Get,
+					},
+				},
+				EmbededFS: embeddedaddons.F,
+			}
+
+			manifests, err := applier.loadAddonsManifests(applier.EmbededFS, resources.AddonFlux, nil, nil, false, "")
+			if err != nil {
+				t.Fatalf("unable to load Flux manifests: %v", err)
+			}
+
+			var buf bytes.Buffer
+			for _, m := range manifests {
+				buf.Write(m.Raw)
+			}
+			if !strings.Contains(buf.String(), "--storage-adv-addr=source-controller.$(RUNTIME_NAMESPACE).svc.cluster.local.") {
+				t.Errorf("expected the source-controller storage address in the Flux manifest")
+			}
+
+			manifests, err = applier.loadAddonsManifests(applier.EmbededFS, resources.AddonFluxSync, nil, nil, false, "")
+			if err != nil {
+				t.Fatalf("unable to load Flux sync manifests: %v", err)
+			}
+			if len(manifests) != 2 {
+				t.Fatalf("expected GitRepository and Kustomization, got %d manifests", len(manifests))
+			}
+
+			buf.Reset()
+			for _, m := range manifests {
+				buf.Write(m.Raw)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("expected %s in the Flux sync manifest, got %s", want, buf.String())
+				}
+			}
+			for _, wantNo := range tc.wantNo {
+				if strings.Contains(buf.String(), wantNo) {
+					t.Errorf("expected no %s in the Flux sync manifest", wantNo)
+				}
+			}
+		})
+	}
+}
+
+func TestVeleroAddons(t *testing.T) {
+	tests := []struct {
+		name          string
+		cloudProvider kubeoneapi.CloudProviderSpec
+		velero        *kubeoneapi.Velero
+		credentials   map[string]string
+		wantManifests int
+		want          []string
+		wantNo        []string
+	}{
+		{
+			name:          "aws defaults",
+			cloudProvider: kubeoneapi.CloudProviderSpec{AWS: &kubeoneapi.AWSSpec{}},
+			velero: &kubeoneapi.Velero{
+				Enable: true,
+				Bucket: "backups",
+				Config: map[string]string{"region": "eu-west-1"},
+			},
+			credentials: map[string]string{
+				"AWS_ACCESS_KEY_ID":     "AKIAEXAMPLE",
+				"AWS_SECRET_ACCESS_KEY": "secret",
+			},
+			wantManifests: 2,
+			want: []string{
+				`velero-plugin-for-aws:v1.3.0`,
+				`aws_access_key_id=AKIAEXAMPLE\naws_secret_access_key=secret`,
+				`"provider":"aws"`,
+				`"objectStorage":{"bucket":"backups"}`,
+				`"config":{"region":"eu-west-1"}`,
+				`"schedule":"0 3 * * *"`,
+				`"ttl":"720h0m0s"`,
+				`"defaultVolumesToRestic":false`,
+			},
+			wantNo: []string{
+				`"name":"restic"`,
+			},
+		},
+		{
+			name:          "gcp with restic and without schedule",
+			cloudProvider: kubeoneapi.CloudProviderSpec{GCE: &kubeoneapi.GCESpec{}},
+			velero: &kubeoneapi.Velero{
+				Enable:   true,
+				Bucket:   "backups",
+				Prefix:   "production",
+				Restic:   true,
+				Schedule: kubeoneapi.VeleroSchedule{Disable: true},
+			},
+			credentials: map[string]string{
+				"GOOGLE_CREDENTIALS": `{"type": "service_account"}`,
+			},
+			wantManifests: 1,
+			want: []string{
+				`velero-plugin-for-gcp:v1.3.0`,
+				`"cloud":"{\"type\": \"service_account\"}`,
+				`"name":"restic"`,
+				`"provider":"gcp"`,
+				`"objectStorage":{"bucket":"backups","prefix":"production"}`,
+			},
+			wantNo: []string{
+				`"name":"kubeone-default"`,
+			},
+		},
+		{
+			name:          "azure with custom schedule",
+			cloudProvider: kubeoneapi.CloudProviderSpec{Azure: &kubeoneapi.AzureSpec{}},
+			velero: &kubeoneapi.Velero{
+				Enable: true,
+				Bucket: "backups",
+				Config: map[string]string{"resourceGroup": "backups", "storageAccount": "kubeonebackups"},
+				Restic: true,
+				Schedule: kubeoneapi.VeleroSchedule{
+					Cron: "@every 6h",
+					TTL:  &metav1.Duration{Duration: 168 * time.Hour},
+				},
+			},
+			credentials: map[string]string{
+				"ARM_CLIENT_ID":       "client",
+				"ARM_CLIENT_SECRET":   "secret",
+				"ARM_TENANT_ID":       "tenant",
+				"ARM_SUBSCRIPTION_ID": "subscription",
+			},
+			wantManifests: 2,
+			want: []string{
+				`velero-plugin-for-microsoft-azure:v1.3.1`,
+				`AZURE_CLIENT_ID=client`,
+				`"config":{"resourceGroup":"backups","storageAccount":"kubeonebackups"}`,
+				`"schedule":"@every 6h"`,
+				`"ttl":"168h0m0s"`,
+				`"defaultVolumesToRestic":true`,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			applier := &applier{
+				TemplateData: templateData{
+					Config: &kubeoneapi.KubeOneCluster{
+						Name:          "kubeone-test",
+						CloudProvider: tc.cloudProvider,
+						Features: kubeoneapi.Features{
+							Velero: tc.velero,
+						},
+					},
+					Credentials: tc.credentials,
+					InternalImages: &internalImages{
+						resolver: images.NewResolver(images.WithKubernetesVersionGetter(func() string { return "1.22.3" })).Get,
+					},
+				},
+				EmbededFS: embeddedaddons.F,
+			}
+
+			manifests, err := applier.loadAddonsManifests(applier.EmbededFS, resources.AddonVelero, nil, nil, false, "")
+			if err != nil {
+				t.Fatalf("unable to load Velero manifests: %v", err)
+			}
+
+			var buf bytes.Buffer
+			for _, m := range manifests {
+				buf.Write(m.Raw)
+			}
+
+			backups, err := applier.loadAddonsManifests(applier.EmbededFS, resources.AddonVeleroBackups, nil, nil, false, "")
+			if err != nil {
+				t.Fatalf("unable to load Velero backups manifests: %v", err)
+			}
+			if len(backups) != tc.wantManifests {
+				t.Fatalf("expected %d Velero backups manifests, got %d", tc.wantManifests, len(backups))
+			}
+			for _, m := range backups {
+				buf.Write(m.Raw)
+			}
+
+			for _, want := range tc.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("expected %s in the Velero manifests", want)
+				}
+			}
+			for _, wantNo := range tc.wantNo {
+				if strings.Contains(buf.String(), wantNo) {
+					t.Errorf("expected no %s in the Velero manifests", wantNo)
+				}
+			}
+		})
+	}
+}
+
 func TestCABundleFuncs(t *testing.T) {
 	tests := []string{
 		"caBundleEnvVar",
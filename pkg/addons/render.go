@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"io/fs"
+
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/state"
+)
+
+// RenderUserAddons returns the manifests of the addons applied by
+// EnsureUserAddons, keyed by the addon name. Manifests of the addons in the
+// root of the addons directory are keyed by an empty string. Nothing is
+// applied to the cluster.
+func RenderUserAddons(s *state.State) (map[string]string, error) {
+	applier, err := newAddonsApplier(s)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered := map[string]string{}
+	if applier.LocalFS == nil {
+		return rendered, nil
+	}
+
+	addonNames, _, err := applier.userAddons(s)
+	if err != nil {
+		return nil, err
+	}
+
+	for addonName := range addonNames {
+		// Addons in the addons directory take precedence over the embedded ones
+		fsys := applier.EmbededFS
+		if info, statErr := fs.Stat(applier.LocalFS, addonName); statErr == nil && info.IsDir() {
+			fsys = applier.LocalFS
+		}
+
+		manifest, err := applier.getManifestsFromDirectory(s, fsys, addonName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to render the addon %q", addonName)
+		}
+		rendered[addonName] = manifest
+	}
+
+	manifest, err := applier.getManifestsFromDirectory(s, applier.LocalFS, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to render addons from the root directory")
+	}
+	rendered[""] = manifest
+
+	return rendered, nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/certificate"
+	"k8c.io/kubeone/pkg/templates/resources"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// servingCerts issues serving certificates signed by the Kubernetes CA to the
+// addons templates, so the custom addons can bootstrap their admission
+// webhooks without shipping their own PKI
+type servingCerts struct {
+	domain   string
+	validity time.Duration
+	issuer   certificate.Issuer
+
+	lock  sync.Mutex
+	certs map[string]*servingCert
+}
+
+// servingCert is a serving certificate issued for a Service
+type servingCert struct {
+	name      string
+	namespace string
+
+	// Cert is the PEM encoded serving certificate
+	Cert string
+	// Key is the PEM encoded private key of the serving certificate
+	Key string
+	// CABundle is the PEM encoded CA certificate to be used as the webhook
+	// caBundle
+	CABundle string
+}
+
+func newServingCerts(domain string, validity time.Duration, issuer certificate.Issuer) *servingCerts {
+	return &servingCerts{
+		domain:   domain,
+		validity: validity,
+		issuer:   issuer,
+		certs:    map[string]*servingCert{},
+	}
+}
+
+// Get returns the serving certificate for the Service with the given name
+// and namespace. The certificate is issued once and reused for all addons
+// referencing the same Service.
+func (sc *servingCerts) Get(name, namespace string) (*servingCert, error) {
+	if sc == nil {
+		return nil, errors.New("serving certificates are not available")
+	}
+	if name == "" || namespace == "" {
+		return nil, errors.New("service name and namespace are required to issue a serving certificate")
+	}
+
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+
+	key := strings.Join([]string{namespace, name}, "/")
+	if cert, ok := sc.certs[key]; ok {
+		return cert, nil
+	}
+
+	certsMap, err := certificate.NewSignedTLSCert(name, namespace, sc.domain, sc.validity, sc.issuer)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to issue serving certificate for service %s", key)
+	}
+
+	cert := &servingCert{
+		name:      name,
+		namespace: namespace,
+		Cert:      certsMap[resources.TLSCertName],
+		Key:       certsMap[resources.TLSKeyName],
+		CABundle:  certsMap[resources.KubernetesCACertName],
+	}
+	sc.certs[key] = cert
+
+	return cert, nil
+}
+
+// TLSSecret renders the kubernetes.io/tls Secret with the given name, holding
+// the serving certificate, in the namespace of the Service
+func (c *servingCert) TLSSecret(secretName string) (string, error) {
+	secret := corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: c.namespace,
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       []byte(c.Cert),
+			corev1.TLSPrivateKeyKey: []byte(c.Key),
+			"ca.crt":                []byte(c.CABundle),
+		},
+	}
+
+	buf, err := yaml.Marshal(secret)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal serving certificate secret")
+	}
+
+	return strings.TrimSuffix(string(buf), "\n"), nil
+}
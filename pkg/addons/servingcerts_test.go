@@ -0,0 +1,161 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/certificate"
+)
+
+const webhookAddonManifest = `{{ $cert := .ServingCerts.Get "my-webhook" "my-namespace" }}
+{{ $cert.TLSSecret "my-webhook-tls" }}
+---
+apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: my-webhook
+webhooks:
+  - name: my-webhook.example.com
+    admissionReviewVersions: ["v1"]
+    sideEffects: None
+    clientConfig:
+      caBundle: {{ (.ServingCerts.Get "my-webhook" "my-namespace").CABundle | b64enc }}
+      service:
+        name: my-webhook
+        namespace: my-namespace
+`
+
+func testServingCerts(t *testing.T) *servingCerts {
+	t.Helper()
+
+	caCertPEM, caKeyPEM, err := certificate.NewCA("kubernetes", kubeoneapi.KeyAlgorithmRSA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caKey, caCert, err := certificate.ParseCAKeyPair(caCertPEM, caKeyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return newServingCerts("cluster.local", time.Hour, certificate.NewKeyIssuer(caKey, caCert, kubeoneapi.KeyAlgorithmRSA))
+}
+
+func TestServingCertsGet(t *testing.T) {
+	t.Parallel()
+
+	sc := testServingCerts(t)
+
+	cert, err := sc.Get("my-webhook", "my-namespace")
+	if err != nil {
+		t.Fatalf("unable to issue serving certificate: %v", err)
+	}
+
+	block, _ := pem.Decode([]byte(cert.Cert))
+	if block == nil {
+		t.Fatal("serving certificate is not PEM encoded")
+	}
+	parsed, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("unable to parse serving certificate: %v", err)
+	}
+
+	expectedDNSNames := []string{"my-webhook.my-namespace.svc.cluster.local.", "my-webhook.my-namespace.svc"}
+	if strings.Join(parsed.DNSNames, ",") != strings.Join(expectedDNSNames, ",") {
+		t.Errorf("expected DNS names %v, got %v", expectedDNSNames, parsed.DNSNames)
+	}
+
+	again, err := sc.Get("my-webhook", "my-namespace")
+	if err != nil {
+		t.Fatalf("unable to get serving certificate: %v", err)
+	}
+	if again != cert {
+		t.Error("expected the serving certificate to be issued once per service")
+	}
+
+	other, err := sc.Get("my-webhook", "other-namespace")
+	if err != nil {
+		t.Fatalf("unable to issue serving certificate: %v", err)
+	}
+	if other.Cert == cert.Cert {
+		t.Error("expected a different serving certificate for a different service")
+	}
+
+	if _, err := sc.Get("", "my-namespace"); err == nil {
+		t.Error("expected an error for an empty service name")
+	}
+
+	var nilCerts *servingCerts
+	if _, err := nilCerts.Get("my-webhook", "my-namespace"); err == nil {
+		t.Error("expected an error when serving certificates are not available")
+	}
+}
+
+func TestServingCertsTemplate(t *testing.T) {
+	t.Parallel()
+
+	sc := testServingCerts(t)
+	applier := &applier{
+		TemplateData: templateData{
+			Config: &kubeoneapi.KubeOneCluster{
+				Name: "kubeone-test",
+			},
+			ServingCerts: sc,
+		},
+		LocalFS: fstest.MapFS{
+			"webhook.yaml": &fstest.MapFile{Data: []byte(webhookAddonManifest)},
+		},
+	}
+
+	manifests, err := applier.loadAddonsManifests(applier.LocalFS, ".", nil, nil, false, "")
+	if err != nil {
+		t.Fatalf("unable to load manifests: %v", err)
+	}
+	if len(manifests) != 2 {
+		t.Fatalf("expected to load 2 manifests, got %d", len(manifests))
+	}
+
+	cert, err := sc.Get("my-webhook", "my-namespace")
+	if err != nil {
+		t.Fatalf("unable to get serving certificate: %v", err)
+	}
+
+	secret := string(manifests[0].Raw)
+	for _, expected := range []string{
+		`"name":"my-webhook-tls"`,
+		`"namespace":"my-namespace"`,
+		`"type":"kubernetes.io/tls"`,
+		`"tls.crt":"` + base64.StdEncoding.EncodeToString([]byte(cert.Cert)) + `"`,
+		`"tls.key":"` + base64.StdEncoding.EncodeToString([]byte(cert.Key)) + `"`,
+	} {
+		if !strings.Contains(secret, expected) {
+			t.Errorf("expected secret to contain %s", expected)
+		}
+	}
+
+	webhook := string(manifests[1].Raw)
+	if !strings.Contains(webhook, `"caBundle":"`+base64.StdEncoding.EncodeToString([]byte(cert.CABundle))+`"`) {
+		t.Error("expected caBundle to be injected into the webhook configuration")
+	}
+}
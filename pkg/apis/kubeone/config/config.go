@@ -17,6 +17,10 @@ limitations under the License.
 package config
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
 	"io/ioutil"
 	"os"
 	"os/exec"
@@ -30,10 +34,14 @@ import (
 	kubeonev1alpha1 "k8c.io/kubeone/pkg/apis/kubeone/v1alpha1"
 	kubeonev1beta1 "k8c.io/kubeone/pkg/apis/kubeone/v1beta1"
 	kubeonevalidation "k8c.io/kubeone/pkg/apis/kubeone/validation"
+	"k8c.io/kubeone/pkg/credentials"
+	"k8c.io/kubeone/pkg/hostdiscovery"
+	"k8c.io/kubeone/pkg/secretstore"
 	terraformv1alpha1 "k8c.io/kubeone/pkg/terraform/v1alpha1"
 	terraformv1beta1 "k8c.io/kubeone/pkg/terraform/v1beta1"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
 )
 
 const (
@@ -55,18 +63,14 @@ var (
 )
 
 // LoadKubeOneCluster returns the internal representation of the KubeOneCluster object
-// parsed from the versioned KubeOneCluster manifest, Terraform output and credentials file
+// parsed from the versioned KubeOneCluster manifest, Terraform output and credentials file.
+// The manifest and the credentials file can be encrypted using SOPS. Environment
+// variable and file references in the manifest are resolved, see SubstituteReferences.
 func LoadKubeOneCluster(clusterCfgPath, tfOutputPath, credentialsFilePath string, logger logrus.FieldLogger) (*kubeoneapi.KubeOneCluster, error) {
-	if len(clusterCfgPath) == 0 {
-		return nil, errors.New("cluster configuration path not provided")
-	}
-
-	cluster, err := ioutil.ReadFile(clusterCfgPath)
-	if err != nil {
-		return nil, errors.Wrap(err, "unable to read the given cluster configuration file")
-	}
-
-	var tfOutput []byte
+	var (
+		tfOutput []byte
+		err      error
+	)
 
 	switch {
 	case tfOutputPath == "-":
@@ -85,9 +89,59 @@ func LoadKubeOneCluster(clusterCfgPath, tfOutputPath, credentialsFilePath string
 		}
 	}
 
+	return LoadKubeOneClusterWithTerraformOutput(clusterCfgPath, tfOutput, credentialsFilePath, logger)
+}
+
+// LoadKubeOneClusterWithTerraformOutput is the same as LoadKubeOneCluster, but uses
+// the already read Terraform output (e.g. read from the remote state backend).
+func LoadKubeOneClusterWithTerraformOutput(clusterCfgPath string, tfOutput []byte, credentialsFilePath string, logger logrus.FieldLogger) (*kubeoneapi.KubeOneCluster, error) {
+	return loadKubeOneClusterDocument(clusterCfgPath, -1, tfOutput, credentialsFilePath, logger)
+}
+
+// LoadKubeOneClusterDocument is the same as LoadKubeOneClusterWithTerraformOutput, but
+// loads the document with the given index from the multi-document manifest.
+func LoadKubeOneClusterDocument(clusterCfgPath string, document int, tfOutput []byte, credentialsFilePath string, logger logrus.FieldLogger) (*kubeoneapi.KubeOneCluster, error) {
+	if document < 0 {
+		return nil, errors.Errorf("invalid document index %d", document)
+	}
+
+	return loadKubeOneClusterDocument(clusterCfgPath, document, tfOutput, credentialsFilePath, logger)
+}
+
+// loadKubeOneClusterDocument loads the given document from the manifest. If
+// the document index is negative, the manifest must be a single document.
+func loadKubeOneClusterDocument(clusterCfgPath string, document int, tfOutput []byte, credentialsFilePath string, logger logrus.FieldLogger) (*kubeoneapi.KubeOneCluster, error) {
+	if len(clusterCfgPath) == 0 {
+		return nil, errors.New("cluster configuration path not provided")
+	}
+
+	cluster, err := secretstore.ReadFile(clusterCfgPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read the given cluster configuration file")
+	}
+
+	documents, err := SplitManifest(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case document < 0 && len(documents) > 1:
+		return nil, errors.Errorf("the given cluster configuration file contains %d documents, multi-cluster manifests are supported only by 'kubeone fleet apply'", len(documents))
+	case document >= len(documents):
+		return nil, errors.Errorf("the given cluster configuration file contains %d documents, document %d not found", len(documents), document)
+	case document >= 0:
+		cluster = documents[document]
+	}
+
+	cluster, err = SubstituteReferences(cluster, clusterCfgPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to resolve the references in the given cluster configuration file")
+	}
+
 	var credentialsFile []byte
 	if len(credentialsFilePath) != 0 {
-		credentialsFile, err = ioutil.ReadFile(credentialsFilePath)
+		credentialsFile, err = secretstore.ReadFile(credentialsFilePath)
 		if err != nil {
 			return nil, errors.Wrap(err, "unable to read the given credentials file")
 		}
@@ -96,6 +150,35 @@ func LoadKubeOneCluster(clusterCfgPath, tfOutputPath, credentialsFilePath string
 	return BytesToKubeOneCluster(cluster, tfOutput, credentialsFile, logger)
 }
 
+// SplitManifest splits the multi-document manifest into documents. Empty
+// documents (e.g. containing only comments) are skipped.
+func SplitManifest(manifest []byte) ([][]byte, error) {
+	documents := [][]byte{}
+
+	reader := k8syaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(manifest)))
+	for {
+		document, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to split the manifest into documents")
+		}
+
+		var obj interface{}
+		if err = yaml.Unmarshal(document, &obj); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal the manifest document")
+		}
+		if obj == nil {
+			continue
+		}
+
+		documents = append(documents, document)
+	}
+
+	return documents, nil
+}
+
 // BytesToKubeOneCluster parses the bytes of the versioned KubeOneCluster manifests
 func BytesToKubeOneCluster(cluster, tfOutput, credentialsFile []byte, logger logrus.FieldLogger) (*kubeoneapi.KubeOneCluster, error) {
 	// Get the GVK from the given KubeOneCluster manifest
@@ -156,6 +239,10 @@ func DefaultedV1Alpha1KubeOneCluster(versionedCluster *kubeonev1alpha1.KubeOneCl
 		return nil, errors.Wrap(err, "failed to convert versioned cluster object to internal object")
 	}
 
+	if err := resolveKubernetesVersion(internalCluster, logger); err != nil {
+		return nil, err
+	}
+
 	// Apply the dynamic defaults
 	err := SetKubeOneClusterDynamicDefaults(internalCluster, credentialsFile)
 	if err != nil {
@@ -187,6 +274,16 @@ func DefaultedV1Beta1KubeOneCluster(versionedCluster *kubeonev1beta1.KubeOneClus
 		}
 	}
 
+	// Hosts are discovered before defaulting, so the discovered hosts are
+	// defaulted the same way as the hosts from the manifest
+	if err := discoverHosts(versionedCluster, credentialsFile); err != nil {
+		return nil, err
+	}
+
+	// The same applies to the hosts of the static worker groups, which are
+	// defaulted from the group first
+	expandStaticWorkerGroups(versionedCluster)
+
 	internalCluster := &kubeoneapi.KubeOneCluster{}
 
 	kubeonescheme.Scheme.Default(versionedCluster)
@@ -194,6 +291,10 @@ func DefaultedV1Beta1KubeOneCluster(versionedCluster *kubeonev1beta1.KubeOneClus
 		return nil, errors.Wrap(err, "failed to convert versioned cluster object to internal object")
 	}
 
+	if err := resolveKubernetesVersion(internalCluster, logger); err != nil {
+		return nil, err
+	}
+
 	// Apply the dynamic defaults
 	err := SetKubeOneClusterDynamicDefaults(internalCluster, credentialsFile)
 	if err != nil {
@@ -211,6 +312,31 @@ func DefaultedV1Beta1KubeOneCluster(versionedCluster *kubeonev1beta1.KubeOneClus
 	return internalCluster, nil
 }
 
+// discoverHosts adds the hosts discovered using the cloud provider API, if
+// the host discovery is configured
+func discoverHosts(cluster *kubeonev1beta1.KubeOneCluster, credentialsFile []byte) error {
+	if cluster.ControlPlane.HostDiscovery == nil && cluster.StaticWorkers.HostDiscovery == nil {
+		return nil
+	}
+
+	cloudProvider := kubeoneapi.CloudProviderSpec{}
+	if err := kubeonescheme.Scheme.Convert(&cluster.CloudProvider, &cloudProvider, nil); err != nil {
+		return errors.Wrap(err, "failed to convert cloud provider spec")
+	}
+
+	creds, err := credentials.ProviderCredentialsFromBytes(cloudProvider, credentialsFile)
+	if err != nil {
+		return errors.Wrap(err, "failed to get provider credentials for host discovery")
+	}
+
+	discoverer, err := hostdiscovery.New(cluster, creds)
+	if err != nil {
+		return err
+	}
+
+	return errors.Wrap(hostdiscovery.Apply(context.Background(), cluster, discoverer), "host discovery failed")
+}
+
 // SetKubeOneClusterDynamicDefaults sets the dynamic defaults for a given KubeOneCluster object
 func SetKubeOneClusterDynamicDefaults(cfg *kubeoneapi.KubeOneCluster, credentialsFile []byte) error {
 	// Parse the credentials file
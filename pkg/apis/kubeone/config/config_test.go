@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestSplitManifest(t *testing.T) {
+	testcases := []struct {
+		name              string
+		manifest          string
+		expectedDocuments []string
+	}{
+		{
+			name:              "single document",
+			manifest:          "name: a\n",
+			expectedDocuments: []string{"name: a"},
+		},
+		{
+			name:              "multiple documents",
+			manifest:          "---\nname: a\n---\nname: b\n",
+			expectedDocuments: []string{"name: a", "name: b"},
+		},
+		{
+			name:              "empty documents are skipped",
+			manifest:          "name: a\n---\n# comment\n---\n\n---\nname: b\n",
+			expectedDocuments: []string{"name: a", "name: b"},
+		},
+		{
+			name:              "empty manifest",
+			manifest:          "",
+			expectedDocuments: []string{},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			documents, err := SplitManifest([]byte(tc.manifest))
+			if err != nil {
+				t.Fatalf("SplitManifest() error = %v", err)
+			}
+
+			got := []string{}
+			for _, document := range documents {
+				got = append(got, strings.TrimSpace(strings.TrimPrefix(string(document), "---")))
+			}
+			if strings.Join(got, "|") != strings.Join(tc.expectedDocuments, "|") {
+				t.Errorf("SplitManifest() = %q, expected %q", got, tc.expectedDocuments)
+			}
+		})
+	}
+}
+
+func TestLoadKubeOneClusterMultipleDocuments(t *testing.T) {
+	manifestFilePath := filepath.Join(t.TempDir(), "kubeone.yaml")
+	manifest := "apiVersion: kubeone.io/v1beta1\nkind: KubeOneCluster\nname: a\n---\napiVersion: kubeone.io/v1beta1\nkind: KubeOneCluster\nname: b\n"
+	if err := ioutil.WriteFile(manifestFilePath, []byte(manifest), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadKubeOneClusterWithTerraformOutput(manifestFilePath, nil, "", logrus.New())
+	if err == nil || !strings.Contains(err.Error(), "contains 2 documents") {
+		t.Errorf("LoadKubeOneClusterWithTerraformOutput() error = %v, expected multiple documents error", err)
+	}
+
+	_, err = LoadKubeOneClusterDocument(manifestFilePath, 2, nil, "", logrus.New())
+	if err == nil || !strings.Contains(err.Error(), "document 2 not found") {
+		t.Errorf("LoadKubeOneClusterDocument() error = %v, expected document not found error", err)
+	}
+}
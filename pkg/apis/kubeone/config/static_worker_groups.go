@@ -0,0 +1,97 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	kubeonev1beta1 "k8c.io/kubeone/pkg/apis/kubeone/v1beta1"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// StaticWorkerGroupLabel is set on the Nodes of the static worker groups, with
+// the name of the group as the value
+const StaticWorkerGroupLabel = "kubeone.io/static-worker-group"
+
+// expandStaticWorkerGroups adds the hosts of the static worker groups to the
+// static worker hosts, defaulting the fields which are not set from the
+// group template
+func expandStaticWorkerGroups(cluster *kubeonev1beta1.KubeOneCluster) {
+	for _, group := range cluster.StaticWorkers.Groups {
+		for i := range group.Hosts {
+			host := mergeHostConfig(group.Template, *group.Hosts[i].DeepCopy())
+			host.Labels = labels.Merge(host.Labels, map[string]string{StaticWorkerGroupLabel: group.Name})
+			cluster.StaticWorkers.Hosts = append(cluster.StaticWorkers.Hosts, host)
+		}
+	}
+}
+
+// mergeHostConfig returns the host with the fields which are not set taken
+// from the template. The labels are merged, with the host labels taking
+// precedence.
+func mergeHostConfig(template, host kubeonev1beta1.HostConfig) kubeonev1beta1.HostConfig {
+	template = *template.DeepCopy()
+
+	if host.SSHPort == 0 {
+		host.SSHPort = template.SSHPort
+	}
+	if host.SSHUsername == "" {
+		host.SSHUsername = template.SSHUsername
+	}
+	if host.SSHPrivateKeyFile == "" {
+		host.SSHPrivateKeyFile = template.SSHPrivateKeyFile
+	}
+	if host.SSHPrivateKeyPassphrase == "" {
+		host.SSHPrivateKeyPassphrase = template.SSHPrivateKeyPassphrase
+	}
+	if host.SSHCertFile == "" {
+		host.SSHCertFile = template.SSHCertFile
+	}
+	if host.SSHAgentSocket == "" {
+		host.SSHAgentSocket = template.SSHAgentSocket
+	}
+	if host.Bastion == "" {
+		host.Bastion = template.Bastion
+	}
+	if host.BastionPort == 0 {
+		host.BastionPort = template.BastionPort
+	}
+	if host.BastionUser == "" {
+		host.BastionUser = template.BastionUser
+	}
+	if len(host.BastionHosts) == 0 {
+		host.BastionHosts = template.BastionHosts
+	}
+	if host.Taints == nil {
+		host.Taints = template.Taints
+	}
+	if host.NodeDrain == nil {
+		host.NodeDrain = template.NodeDrain
+	}
+	if host.IgnorePreflightErrors == nil {
+		host.IgnorePreflightErrors = template.IgnorePreflightErrors
+	}
+	if host.Kubelet == nil {
+		host.Kubelet = template.Kubelet
+	}
+	host.NvidiaGPU = host.NvidiaGPU || template.NvidiaGPU
+
+	if len(template.Labels) > 0 {
+		host.Labels = labels.Merge(template.Labels, host.Labels)
+	}
+
+	return host
+}
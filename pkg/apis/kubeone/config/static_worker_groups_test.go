@@ -0,0 +1,95 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	kubeonev1beta1 "k8c.io/kubeone/pkg/apis/kubeone/v1beta1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestExpandStaticWorkerGroups(t *testing.T) {
+	maxPods := int32(50)
+	taint := corev1.Taint{Key: "storage", Effect: corev1.TaintEffectNoSchedule}
+
+	cluster := &kubeonev1beta1.KubeOneCluster{
+		StaticWorkers: kubeonev1beta1.StaticWorkersConfig{
+			Hosts: []kubeonev1beta1.HostConfig{
+				{PublicAddress: "10.0.0.1"},
+			},
+			Groups: []kubeonev1beta1.StaticWorkerGroup{
+				{
+					Name: "storage",
+					Template: kubeonev1beta1.HostConfig{
+						SSHUsername:       "ubuntu",
+						SSHPort:           2222,
+						SSHPrivateKeyFile: "/home/me/.ssh/storage",
+						Taints:            []corev1.Taint{taint},
+						Labels:            map[string]string{"tier": "storage", "disk": "hdd"},
+						Kubelet:           &kubeonev1beta1.KubeletConfig{MaxPods: &maxPods},
+					},
+					Hosts: []kubeonev1beta1.HostConfig{
+						{PublicAddress: "10.0.1.1"},
+						{
+							PublicAddress: "10.0.1.2",
+							SSHUsername:   "admin",
+							Taints:        []corev1.Taint{},
+							Labels:        map[string]string{"disk": "ssd"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	expandStaticWorkerGroups(cluster)
+
+	expected := []kubeonev1beta1.HostConfig{
+		{PublicAddress: "10.0.0.1"},
+		{
+			PublicAddress:     "10.0.1.1",
+			SSHUsername:       "ubuntu",
+			SSHPort:           2222,
+			SSHPrivateKeyFile: "/home/me/.ssh/storage",
+			Taints:            []corev1.Taint{taint},
+			Labels:            map[string]string{"tier": "storage", "disk": "hdd", StaticWorkerGroupLabel: "storage"},
+			Kubelet:           &kubeonev1beta1.KubeletConfig{MaxPods: &maxPods},
+		},
+		{
+			PublicAddress:     "10.0.1.2",
+			SSHUsername:       "admin",
+			SSHPort:           2222,
+			SSHPrivateKeyFile: "/home/me/.ssh/storage",
+			Taints:            []corev1.Taint{},
+			Labels:            map[string]string{"tier": "storage", "disk": "ssd", StaticWorkerGroupLabel: "storage"},
+			Kubelet:           &kubeonev1beta1.KubeletConfig{MaxPods: &maxPods},
+		},
+	}
+
+	if !reflect.DeepEqual(cluster.StaticWorkers.Hosts, expected) {
+		t.Errorf("expandStaticWorkerGroups() hosts = %+v, expected %+v", cluster.StaticWorkers.Hosts, expected)
+	}
+
+	// the template must not be modified through the expanded hosts
+	cluster.StaticWorkers.Hosts[1].Labels["disk"] = "nvme"
+	if got := cluster.StaticWorkers.Groups[0].Template.Labels["disk"]; got != "hdd" {
+		t.Errorf("template label modified through the expanded host, got %q", got)
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	kyaml "sigs.k8s.io/yaml"
+)
+
+const (
+	// fileReferencePrefix is the prefix of the string values replaced with
+	// the content of the referenced file
+	fileReferencePrefix = "file:"
+	// escapedFileReferencePrefix is used for the string values starting with
+	// the literal fileReferencePrefix
+	escapedFileReferencePrefix = `\` + fileReferencePrefix
+)
+
+// envReferenceRe matches ${NAME} and ${NAME:-default} references, as well as
+// the escaped $${...} references, which are replaced with the literal ${...}
+var envReferenceRe = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// SubstituteReferences resolves the references in the string values of the
+// KubeOneCluster manifest:
+//   - ${NAME} is replaced with the value of the NAME environment variable,
+//     which must be set, and ${NAME:-default} is replaced with the value of
+//     the NAME environment variable, or with default if it's not set or empty.
+//     $${NAME} is replaced with the literal ${NAME}.
+//   - a value in form of file:<path> is replaced with the content of the file.
+//     Relative paths are relative to the manifest file. Values starting with
+//     \file: are replaced with the literal file:, and file:// URLs are not
+//     references.
+//
+// Environment variables are resolved before files, so paths can contain
+// environment variables. Keys are never substituted. The manifest is returned
+// as JSON, which is accepted by the KubeOneCluster decoder.
+func SubstituteReferences(manifest []byte, manifestFilePath string) ([]byte, error) {
+	jsonManifest, err := kyaml.YAMLToJSON(manifest)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert the manifest to JSON")
+	}
+
+	var obj interface{}
+	decoder := json.NewDecoder(bytes.NewReader(jsonManifest))
+	decoder.UseNumber()
+	if err = decoder.Decode(&obj); err != nil {
+		return nil, errors.Wrap(err, "failed to decode the manifest")
+	}
+
+	baseDir := ""
+	if manifestFilePath != "" {
+		baseDir = filepath.Dir(manifestFilePath)
+	}
+
+	obj, err = substituteValue(obj, baseDir, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(obj)
+}
+
+func substituteValue(value interface{}, baseDir, path string) (interface{}, error) {
+	var err error
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if v[key], err = substituteValue(item, baseDir, path+"."+key); err != nil {
+				return nil, err
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			if v[i], err = substituteValue(item, baseDir, path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return nil, err
+			}
+		}
+	case string:
+		substituted, substituteErr := substituteString(v, baseDir)
+		return substituted, errors.Wrapf(substituteErr, "failed to substitute %s", path)
+	}
+
+	return value, nil
+}
+
+func substituteString(value, baseDir string) (string, error) {
+	var missing []string
+	value = envReferenceRe.ReplaceAllStringFunc(value, func(ref string) string {
+		if strings.HasPrefix(ref, "$$") {
+			return ref[1:]
+		}
+
+		match := envReferenceRe.FindStringSubmatch(ref)
+		name, hasDefault, defaultValue := match[1], match[2] != "", match[3]
+
+		envValue, ok := os.LookupEnv(name)
+		switch {
+		case hasDefault && envValue == "":
+			return defaultValue
+		case !ok:
+			missing = append(missing, name)
+		}
+
+		return envValue
+	})
+	if len(missing) > 0 {
+		return "", errors.Errorf("environment variable(s) %s not set", strings.Join(missing, ", "))
+	}
+
+	switch {
+	case strings.HasPrefix(value, escapedFileReferencePrefix):
+		return value[1:], nil
+	case strings.HasPrefix(value, fileReferencePrefix) && !strings.HasPrefix(value, fileReferencePrefix+"//"):
+		filePath := strings.TrimPrefix(value, fileReferencePrefix)
+		if !filepath.IsAbs(filePath) && baseDir != "" {
+			filePath = filepath.Join(baseDir, filePath)
+		}
+
+		content, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return "", errors.Wrapf(err, "unable to read the referenced file %q", filePath)
+		}
+
+		return string(content), nil
+	}
+
+	return value, nil
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestSubstituteReferences(t *testing.T) {
+	dir := t.TempDir()
+	manifestFilePath := filepath.Join(dir, "kubeone.yaml")
+	if err := ioutil.WriteFile(filepath.Join(dir, "cloud.conf"), []byte("[Global]\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("KUBEONE_TEST_VERSION", "1.22.3")
+	t.Setenv("KUBEONE_TEST_DIR", dir)
+	t.Setenv("KUBEONE_TEST_EMPTY", "")
+
+	testcases := []struct {
+		name          string
+		manifest      string
+		expected      string
+		expectedError bool
+	}{
+		{
+			name:     "no references",
+			manifest: "name: test\napiEndpoint:\n  port: 6443\n",
+			expected: `{"apiEndpoint":{"port":6443},"name":"test"}`,
+		},
+		{
+			name:     "environment variable",
+			manifest: "versions:\n  kubernetes: ${KUBEONE_TEST_VERSION}\nname: test-${KUBEONE_TEST_VERSION}\n",
+			expected: `{"name":"test-1.22.3","versions":{"kubernetes":"1.22.3"}}`,
+		},
+		{
+			name:     "environment variable with default",
+			manifest: "name: ${KUBEONE_TEST_UNSET:-default}-${KUBEONE_TEST_EMPTY:-empty}-${KUBEONE_TEST_VERSION:-default}\n",
+			expected: `{"name":"default-empty-1.22.3"}`,
+		},
+		{
+			name:     "set empty environment variable",
+			manifest: "name: test${KUBEONE_TEST_EMPTY}\n",
+			expected: `{"name":"test"}`,
+		},
+		{
+			name:          "unset environment variable",
+			manifest:      "name: ${KUBEONE_TEST_UNSET}\n",
+			expectedError: true,
+		},
+		{
+			name:     "escaped environment variable",
+			manifest: "name: $${KUBEONE_TEST_UNSET}\n",
+			expected: `{"name":"${KUBEONE_TEST_UNSET}"}`,
+		},
+		{
+			name:     "keys are not substituted",
+			manifest: "addons:\n  globalParams:\n    ${KUBEONE_TEST_VERSION}: value\n",
+			expected: `{"addons":{"globalParams":{"${KUBEONE_TEST_VERSION}":"value"}}}`,
+		},
+		{
+			name:     "relative file reference",
+			manifest: "cloudProvider:\n  cloudConfig: file:cloud.conf\n",
+			expected: `{"cloudProvider":{"cloudConfig":"[Global]\n"}}`,
+		},
+		{
+			name:     "file reference with environment variable",
+			manifest: "hosts:\n- cloudConfig: file:${KUBEONE_TEST_DIR}/cloud.conf\n",
+			expected: `{"hosts":[{"cloudConfig":"[Global]\n"}]}`,
+		},
+		{
+			name:          "missing file",
+			manifest:      "cloudProvider:\n  cloudConfig: file:missing.conf\n",
+			expectedError: true,
+		},
+		{
+			name:     "escaped file reference",
+			manifest: "name: \\file:cloud.conf\n",
+			expected: `{"name":"file:cloud.conf"}`,
+		},
+		{
+			name:     "file URL",
+			manifest: "name: file:///cloud.conf\n",
+			expected: `{"name":"file:///cloud.conf"}`,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := SubstituteReferences([]byte(tc.manifest), manifestFilePath)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("SubstituteReferences() error = %v, expectedError %v", err, tc.expectedError)
+			}
+			if tc.expectedError {
+				return
+			}
+			if string(got) != tc.expected {
+				t.Errorf("SubstituteReferences() = %s, expected %s", got, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/upgradeplan"
+)
+
+const (
+	// KubernetesReleaseURLEnv overrides the location of the Kubernetes
+	// release markers used to resolve the pinned .versions.kubernetes. It
+	// can be the URL, or the local directory mirroring the release markers.
+	KubernetesReleaseURLEnv = "KUBEONE_KUBERNETES_RELEASE_URL"
+
+	resolveKubernetesVersionTimeout = 30 * time.Second
+)
+
+// kubernetesVersionPin matches .versions.kubernetes pinned to the latest
+// patch release of the minor version, e.g. 1.21.x
+var kubernetesVersionPin = regexp.MustCompile(`^(\d+)\.(\d+)\.x$`)
+
+// resolveKubernetesVersion replaces .versions.kubernetes pinned to the latest
+// patch release of the minor version with the latest patch release
+func resolveKubernetesVersion(cluster *kubeoneapi.KubeOneCluster, logger logrus.FieldLogger) error {
+	pin := cluster.Versions.Kubernetes
+	match := kubernetesVersionPin.FindStringSubmatch(pin)
+	if match == nil {
+		return nil
+	}
+
+	minor, err := semver.NewVersion(fmt.Sprintf("%s.%s.0", match[1], match[2]))
+	if err != nil {
+		return errors.Wrapf(err, "invalid .versions.kubernetes %q", pin)
+	}
+
+	releaseURL := os.Getenv(KubernetesReleaseURLEnv)
+	if releaseURL == "" {
+		releaseURL = upgradeplan.DefaultReleaseURL
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), resolveKubernetesVersionTimeout)
+	defer cancel()
+
+	latest, err := upgradeplan.LatestPatch(ctx, releaseURL, minor)
+	if err != nil {
+		return errors.Wrapf(err, "failed to resolve .versions.kubernetes %q", pin)
+	}
+
+	cluster.Versions.Kubernetes = latest.String()
+	logger.Infof("Resolved .versions.kubernetes %q to %q", pin, cluster.Versions.Kubernetes)
+
+	return nil
+}
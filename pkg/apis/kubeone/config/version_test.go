@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+)
+
+func TestResolveKubernetesVersion(t *testing.T) {
+	mirror := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(mirror, "stable-1.21.txt"), []byte("v1.21.14\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(KubernetesReleaseURLEnv, mirror)
+
+	tests := []struct {
+		name    string
+		version string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "exact version",
+			version: "1.22.4",
+			want:    "1.22.4",
+		},
+		{
+			name:    "latest patch",
+			version: "1.21.x",
+			want:    "1.21.14",
+		},
+		{
+			name:    "missing release marker",
+			version: "1.22.x",
+			wantErr: true,
+		},
+		{
+			name:    "pinned major version is not resolved",
+			version: "1.x",
+			want:    "1.x",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := &kubeoneapi.KubeOneCluster{
+				Versions: kubeoneapi.VersionConfig{Kubernetes: tt.version},
+			}
+
+			err := resolveKubernetesVersion(cluster, logrus.New())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveKubernetesVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && cluster.Versions.Kubernetes != tt.want {
+				t.Errorf("resolveKubernetesVersion() = %q, want %q", cluster.Versions.Kubernetes, tt.want)
+			}
+		})
+	}
+}
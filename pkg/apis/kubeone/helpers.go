@@ -23,9 +23,13 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Leader returns the first configured host. Only call this after
@@ -39,6 +43,27 @@ func (c KubeOneCluster) Leader() (HostConfig, error) {
 	return HostConfig{}, errors.New("leader not found")
 }
 
+// SetLeader makes the control plane host with the given hostname, public or
+// private address the leader
+func (c *KubeOneCluster) SetLeader(name string) error {
+	idx := -1
+	for i, host := range c.ControlPlane.Hosts {
+		if name != "" && (host.Hostname == name || host.PublicAddress == name || host.PrivateAddress == name) {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return errors.Errorf("control plane host %q not found", name)
+	}
+
+	for i := range c.ControlPlane.Hosts {
+		c.ControlPlane.Hosts[i].SetLeader(i == idx)
+	}
+
+	return nil
+}
+
 func (c KubeOneCluster) RandomHost() HostConfig {
 	//nolint:gosec
 	// G404: Use of weak random number generator (math/rand instead of crypto/rand) (gosec)
@@ -69,6 +94,279 @@ func (c *KubeOneCluster) IsManagedNode(nodename string) bool {
 	return false
 }
 
+// NodeDrainConfig returns the drain configuration for the given host, with
+// host overrides applied on top of the cluster-wide configuration, and
+// defaults applied to all unset fields
+func (c KubeOneCluster) NodeDrainConfig(host HostConfig) NodeDrainConfig {
+	var (
+		gracePeriodSeconds       = -1
+		deleteEmptyDirData       = true
+		skipWaitForDeleteTimeout = 0
+		ignorePDBs               = false
+	)
+
+	cfg := NodeDrainConfig{
+		GracePeriodSeconds:              &gracePeriodSeconds,
+		Timeout:                         &metav1.Duration{},
+		DeleteEmptyDirData:              &deleteEmptyDirData,
+		SkipWaitForDeleteTimeoutSeconds: &skipWaitForDeleteTimeout,
+		IgnorePodDisruptionBudgets:      &ignorePDBs,
+	}
+
+	for _, override := range []*NodeDrainConfig{c.NodeDrain, host.NodeDrain} {
+		if override == nil {
+			continue
+		}
+		if override.GracePeriodSeconds != nil {
+			cfg.GracePeriodSeconds = override.GracePeriodSeconds
+		}
+		if override.Timeout != nil {
+			cfg.Timeout = override.Timeout
+		}
+		if override.DeleteEmptyDirData != nil {
+			cfg.DeleteEmptyDirData = override.DeleteEmptyDirData
+		}
+		if override.SkipWaitForDeleteTimeoutSeconds != nil {
+			cfg.SkipWaitForDeleteTimeoutSeconds = override.SkipWaitForDeleteTimeoutSeconds
+		}
+		if override.IgnorePodDisruptionBudgets != nil {
+			cfg.IgnorePodDisruptionBudgets = override.IgnorePodDisruptionBudgets
+		}
+	}
+
+	return cfg
+}
+
+// HostOSTuning returns the kernel modules and sysctl settings for the control
+// plane or the static worker hosts, merged with the settings for all hosts.
+// Kernel modules are deduplicated and sorted.
+func (c KubeOneCluster) HostOSTuning(controlPlane bool) OSTuning {
+	tuning := OSTuning{}
+	if c.OSTuning == nil {
+		return tuning
+	}
+
+	group := c.OSTuning.StaticWorkers
+	if controlPlane {
+		group = c.OSTuning.ControlPlane
+	}
+
+	modules := map[string]struct{}{}
+	for _, cfg := range []OSTuning{c.OSTuning.All, group} {
+		for _, module := range cfg.KernelModules {
+			if _, ok := modules[module]; !ok {
+				modules[module] = struct{}{}
+				tuning.KernelModules = append(tuning.KernelModules, module)
+			}
+		}
+		for k, v := range cfg.Sysctls {
+			if tuning.Sysctls == nil {
+				tuning.Sysctls = map[string]string{}
+			}
+			tuning.Sysctls[k] = v
+		}
+	}
+	sort.Strings(tuning.KernelModules)
+
+	return tuning
+}
+
+// CISHardeningEnabled returns true if the CIS hardening profile is applied
+func (c KubeOneCluster) CISHardeningEnabled() bool {
+	return c.Hardening != nil && c.Hardening.Profile == HardeningProfileCIS
+}
+
+// FirewallEnabled returns true if KubeOne creates the firewall rules on the hosts
+func (c KubeOneCluster) FirewallEnabled() bool {
+	return c.Firewall != nil
+}
+
+// SELinuxMode returns how SELinux is handled on the hosts, defaulting to
+// permissive
+func (c KubeOneCluster) SELinuxMode() SELinuxMode {
+	if c.SecurityModules != nil && c.SecurityModules.SELinux != "" {
+		return c.SecurityModules.SELinux
+	}
+
+	return SELinuxModePermissive
+}
+
+// AppArmorMode returns how AppArmor is handled on the hosts, defaulting to
+// configure
+func (c KubeOneCluster) AppArmorMode() AppArmorMode {
+	if c.SecurityModules != nil && c.SecurityModules.AppArmor != "" {
+		return c.SecurityModules.AppArmor
+	}
+
+	return AppArmorModeConfigure
+}
+
+// ChronyEnabled returns true if chrony should be installed on the hosts
+func (c KubeOneCluster) ChronyEnabled() bool {
+	return c.TimeSync != nil && c.TimeSync.Chrony
+}
+
+// MaxClockSkew returns the maximum clock skew allowed by the time-sync
+// preflight check, defaulting to 1s
+func (c KubeOneCluster) MaxClockSkew() time.Duration {
+	if c.TimeSync != nil && c.TimeSync.MaxClockSkew != nil {
+		return c.TimeSync.MaxClockSkew.Duration
+	}
+
+	return time.Second
+}
+
+// NodeNamingStrategy returns the strategy used to name the Nodes of the
+// control plane and static worker hosts, defaulting to the short hostname on
+// Azure, where the Node name must match the VM name, and to the FQDN on other
+// providers
+func (c KubeOneCluster) NodeNamingStrategy() NodeNamingStrategy {
+	if c.NodeNaming != nil && c.NodeNaming.Strategy != "" {
+		return c.NodeNaming.Strategy
+	}
+
+	if c.CloudProvider.Azure != nil {
+		return NodeNamingHostname
+	}
+
+	return NodeNamingFQDN
+}
+
+// MachineControllerNodeNaming returns the strategy machine-controller names
+// the Nodes with. The Nodes are named after the Machines, which are also set
+// as the hostnames, except on AWS where the private DNS name is used.
+func (c KubeOneCluster) MachineControllerNodeNaming() NodeNamingStrategy {
+	if c.CloudProvider.AWS != nil {
+		return NodeNamingFQDN
+	}
+
+	return NodeNamingHostname
+}
+
+// IgnorePreflightErrorsConfig returns kubeadm preflight errors ignored on the
+// given host, merging the cluster-wide and the host configuration, and the
+// kubeadm checks configured in the PreflightConfig
+func (c KubeOneCluster) IgnorePreflightErrorsConfig(host HostConfig) IgnorePreflightErrorsConfig {
+	cfg := IgnorePreflightErrorsConfig{}
+
+	preflight := &IgnorePreflightErrorsConfig{
+		Install: c.kubeadmPreflightErrors(),
+		Upgrade: c.kubeadmPreflightErrors(),
+	}
+
+	for _, ignored := range []*IgnorePreflightErrorsConfig{c.IgnorePreflightErrors, host.IgnorePreflightErrors, preflight} {
+		if ignored == nil {
+			continue
+		}
+		cfg.Install = MergePreflightErrors(cfg.Install, ignored.Install)
+		cfg.Upgrade = MergePreflightErrors(cfg.Upgrade, ignored.Upgrade)
+	}
+
+	return cfg
+}
+
+// KubeOne preflight checks which can be configured in the PreflightConfig
+const (
+	PreflightCheckSudo              = "sudo"
+	PreflightCheckResources         = "resources"
+	PreflightCheckSwap              = "swap"
+	PreflightCheckCgroup            = "cgroup"
+	PreflightCheckTimeSync          = "time-sync"
+	PreflightCheckSELinux           = "selinux"
+	PreflightCheckAppArmor          = "apparmor"
+	PreflightCheckKernelModules     = "kernel-modules"
+	PreflightCheckLocalPorts        = "local-ports"
+	PreflightCheckPeerPorts         = "peer-ports"
+	PreflightCheckNodesMatch        = "nodes-match"
+	PreflightCheckNodesReady        = "nodes-ready"
+	PreflightCheckUpgradeInProgress = "upgrade-in-progress"
+	PreflightCheckVersion           = "version"
+	PreflightCheckVersionSkew       = "version-skew"
+
+	// KubeadmPreflightCheckPrefix prefixes the names of the kubeadm preflight checks
+	KubeadmPreflightCheckPrefix = "kubeadm:"
+)
+
+// PreflightChecks are all KubeOne preflight checks which can be configured
+var PreflightChecks = []string{
+	PreflightCheckSudo,
+	PreflightCheckResources,
+	PreflightCheckSwap,
+	PreflightCheckCgroup,
+	PreflightCheckTimeSync,
+	PreflightCheckSELinux,
+	PreflightCheckAppArmor,
+	PreflightCheckKernelModules,
+	PreflightCheckLocalPorts,
+	PreflightCheckPeerPorts,
+	PreflightCheckNodesMatch,
+	PreflightCheckNodesReady,
+	PreflightCheckUpgradeInProgress,
+	PreflightCheckVersion,
+	PreflightCheckVersionSkew,
+}
+
+// PreflightSkipped returns true if the KubeOne preflight check is skipped
+func (c KubeOneCluster) PreflightSkipped(check string) bool {
+	return c.Preflight != nil && containsFold(c.Preflight.Skip, check)
+}
+
+// PreflightWarnOnly returns true if failures of the KubeOne preflight check
+// are reported only as warnings
+func (c KubeOneCluster) PreflightWarnOnly(check string) bool {
+	return c.Preflight != nil && containsFold(c.Preflight.WarnOnly, check)
+}
+
+// kubeadmPreflightErrors returns the kubeadm preflight checks which are
+// skipped or warning-only, without the prefix
+func (c KubeOneCluster) kubeadmPreflightErrors() []string {
+	if c.Preflight == nil {
+		return nil
+	}
+
+	var checks []string
+	for _, check := range append(append([]string{}, c.Preflight.Skip...), c.Preflight.WarnOnly...) {
+		if strings.HasPrefix(check, KubeadmPreflightCheckPrefix) {
+			checks = append(checks, strings.TrimPrefix(check, KubeadmPreflightCheckPrefix))
+		}
+	}
+
+	return checks
+}
+
+func containsFold(list []string, s string) bool {
+	for _, l := range list {
+		if strings.EqualFold(l, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MergePreflightErrors merges the given lists of kubeadm preflight errors,
+// dropping duplicates. kubeadm doesn't allow listing other preflight errors
+// along with "all", so only "all" is returned if it's found in any list.
+func MergePreflightErrors(lists ...[]string) []string {
+	var merged []string
+	seen := map[string]bool{}
+
+	for _, list := range lists {
+		for _, preflightError := range list {
+			key := strings.ToLower(preflightError)
+			if key == "all" {
+				return []string{"all"}
+			}
+			if !seen[key] {
+				seen[key] = true
+				merged = append(merged, preflightError)
+			}
+		}
+	}
+
+	return merged
+}
+
 // SetHostname sets the hostname for the given host
 func (h *HostConfig) SetHostname(hostname string) {
 	h.Hostname = hostname
@@ -119,6 +417,122 @@ func (crc ContainerRuntimeConfig) CRISocket() string {
 	return ""
 }
 
+// CgroupDriverName returns the cgroup driver used by containerd and kubelet
+func (c *ContainerRuntimeContainerd) CgroupDriverName() string {
+	if c == nil || c.CgroupDriver == "" {
+		return "systemd"
+	}
+
+	return c.CgroupDriver
+}
+
+// RuncSystemdCgroup returns true if runc should use the systemd cgroup driver
+func (c *ContainerRuntimeContainerd) RuncSystemdCgroup() bool {
+	if c != nil && c.SystemdCgroup != nil {
+		return *c.SystemdCgroup
+	}
+
+	return c.CgroupDriverName() == "systemd"
+}
+
+// NvidiaRuntimeEnabled returns true if the nvidia runtime handler should be
+// configured
+func (c *ContainerRuntimeContainerd) NvidiaRuntimeEnabled() bool {
+	return c != nil && c.NvidiaRuntime != nil && c.NvidiaRuntime.Enable
+}
+
+// KubeletInsecureTLSEnabled returns true if metrics-server should skip
+// verifying the kubelet serving certificates
+func (m *MetricsServer) KubeletInsecureTLSEnabled() bool {
+	return m == nil || m.KubeletInsecureTLS == nil || *m.KubeletInsecureTLS
+}
+
+// Enabled returns true if cert-manager should be deployed by KubeOne
+func (cm *CertManager) Enabled() bool {
+	return cm != nil && cm.Enable
+}
+
+// ClusterIssuerEnabled returns true if the cert-manager ClusterIssuer should
+// be bootstrapped by KubeOne
+func (cm *CertManager) ClusterIssuerEnabled() bool {
+	return cm.Enabled() && cm.ClusterIssuer != nil
+}
+
+// ClusterCAIssuerEnabled returns true if the cert-manager ClusterIssuer
+// should issue the certificates signed by the cluster CA
+func (cm *CertManager) ClusterCAIssuerEnabled() bool {
+	return cm.ClusterIssuerEnabled() && cm.ClusterIssuer.CASecretName == ""
+}
+
+// Enabled returns true if Flux should be deployed by KubeOne
+func (g *GitOps) Enabled() bool {
+	return g != nil && g.Enable
+}
+
+// Enabled returns true if Velero should be deployed by KubeOne
+func (v *Velero) Enabled() bool {
+	return v != nil && v.Enable
+}
+
+// IngressControllerServiceType returns the type of the ingress-nginx
+// controller Service, defaulted based on the cloud provider
+func (c KubeOneCluster) IngressControllerServiceType() corev1.ServiceType {
+	ic := c.Features.IngressController
+	switch {
+	case ic != nil && ic.HostNetwork:
+		return corev1.ServiceTypeClusterIP
+	case ic != nil && ic.ServiceType != "":
+		return ic.ServiceType
+	case c.CloudProvider.LoadBalancerSupported():
+		return corev1.ServiceTypeLoadBalancer
+	}
+
+	return corev1.ServiceTypeNodePort
+}
+
+// VeleroProvider returns the provider of the Velero object storage,
+// defaulted based on the cloud provider
+func (c KubeOneCluster) VeleroProvider() VeleroProvider {
+	v := c.Features.Velero
+	switch {
+	case v != nil && v.Provider != "":
+		return v.Provider
+	case c.CloudProvider.AWS != nil:
+		return VeleroProviderAWS
+	case c.CloudProvider.GCE != nil:
+		return VeleroProviderGCP
+	case c.CloudProvider.Azure != nil:
+		return VeleroProviderAzure
+	}
+
+	return ""
+}
+
+// Enabled returns true if the Azure component should be deployed by KubeOne
+func (c *AzureComponent) Enabled() bool {
+	return c == nil || c.Enable == nil || *c.Enable
+}
+
+// Enabled returns true if the OpenStack component should be deployed by
+// KubeOne
+func (c *OpenstackComponent) Enabled() bool {
+	return c == nil || c.Enable == nil || *c.Enable
+}
+
+// ContainerdSandboxImage returns the pod sandbox image configured for
+// containerd, falling back to the pause image from the AssetConfiguration
+func (c KubeOneCluster) ContainerdSandboxImage() string {
+	if c.ContainerRuntime.Containerd != nil && c.ContainerRuntime.Containerd.SandboxImage != "" {
+		return c.ContainerRuntime.Containerd.SandboxImage
+	}
+
+	if c.AssetConfiguration.Pause.ImageRepository != "" {
+		return c.AssetConfiguration.Pause.ImageRepository + "/pause:" + c.AssetConfiguration.Pause.ImageTag
+	}
+
+	return ""
+}
+
 // CloudProviderName returns name of the cloud provider
 func (p CloudProviderSpec) CloudProviderName() string {
 	switch {
@@ -145,6 +559,12 @@ func (p CloudProviderSpec) CloudProviderName() string {
 	return ""
 }
 
+// LoadBalancerSupported returns true if the cloud provider provisions load
+// balancers for the LoadBalancer Services
+func (p CloudProviderSpec) LoadBalancerSupported() bool {
+	return p.AWS != nil || p.Azure != nil || p.DigitalOcean != nil || p.GCE != nil || p.Hetzner != nil || p.Openstack != nil
+}
+
 // CloudProviderInTree detects is there in-tree cloud provider implementation for specified provider.
 // List of in-tree provider can be found here: https://github.com/kubernetes/kubernetes/tree/master/pkg/cloudprovider
 func (p CloudProviderSpec) CloudProviderInTree() bool {
@@ -161,7 +581,12 @@ func (p CloudProviderSpec) CloudProviderInTree() bool {
 // NB: The CSI migration can be supported only if KubeOne supports CSI plugin and driver
 // for the provider
 func (p CloudProviderSpec) CSIMigrationSupported() bool {
-	return p.External && (p.Openstack != nil || p.Vsphere != nil)
+	return p.External && (p.Openstack != nil || p.Vsphere != nil || p.AWS.EBSCSIEnabled())
+}
+
+// EBSCSIEnabled returns true if the Amazon EBS CSI driver should be deployed
+func (a *AWSSpec) EBSCSIEnabled() bool {
+	return a != nil && a.EBSCSI != nil && a.EBSCSI.Enable
 }
 
 // CSIMigrationFeatureGates returns CSI migration feature gates in form of a map
@@ -173,6 +598,17 @@ func (p CloudProviderSpec) CSIMigrationSupported() bool {
 // This is a KubeOneCluster function because feature gates are Kubernetes-version dependent.
 func (c KubeOneCluster) CSIMigrationFeatureGates(complete bool) (map[string]bool, string, error) {
 	switch {
+	case c.CloudProvider.AWS.EBSCSIEnabled():
+		featureGates := map[string]bool{
+			"CSIMigrationAWS": true,
+		}
+
+		unregister := c.InTreePluginUnregisterFeatureGate()
+		if complete && unregister != "" {
+			featureGates[unregister] = true
+		}
+
+		return featureGates, marshalFeatureGates(featureGates), nil
 	case c.CloudProvider.Openstack != nil:
 		featureGates := map[string]bool{
 			"CSIMigrationOpenStack": true,
@@ -209,6 +645,11 @@ func (c KubeOneCluster) InTreePluginUnregisterFeatureGate() string {
 	ver, _ := semver.NewVersion(c.Versions.Kubernetes)
 
 	switch {
+	case c.CloudProvider.AWS.EBSCSIEnabled():
+		if lessThan21.Check(ver) {
+			return "CSIMigrationAWSComplete"
+		}
+		return "InTreePluginAWSUnregister"
 	case c.CloudProvider.Openstack != nil:
 		if lessThan21.Check(ver) {
 			return "CSIMigrationOpenStackComplete"
@@ -253,6 +694,12 @@ func (r *RegistryConfiguration) InsecureRegistryAddress() string {
 	return insecureRegistry
 }
 
+// BinariesFromAssets returns true if kubeadm, kubelet, kubectl and CNI plugins
+// should be downloaded from the binary assets instead of installing packages
+func (a AssetConfiguration) BinariesFromAssets() bool {
+	return a.BinariesMirror != "" || a.Kubeadm.URL != "" || a.Kubelet.URL != ""
+}
+
 func (ads *Addons) Enabled() bool {
 	return ads != nil && ads.Enable
 }
@@ -271,3 +718,71 @@ func (ads *Addons) RelativePath(manifestFilePath string) (string, error) {
 
 	return addonsPath, nil
 }
+
+// ExternalCA returns the configuration of the CA provided by the user, or nil
+// if the CA is generated by kubeadm
+func (c KubeOneCluster) ExternalCA() *ExternalCAConfig {
+	if c.CertificateAuthority == nil {
+		return nil
+	}
+
+	return c.CertificateAuthority.External
+}
+
+// CertificateKeyAlgorithm returns the algorithm of the keys of the certificates
+// generated by KubeOne
+func (c KubeOneCluster) CertificateKeyAlgorithm() KeyAlgorithm {
+	if c.CertificateAuthority == nil || c.CertificateAuthority.KeyAlgorithm == "" {
+		return KeyAlgorithmRSA
+	}
+
+	return c.CertificateAuthority.KeyAlgorithm
+}
+
+const (
+	// DefaultCertificateValidity is the default validity period of the
+	// certificates generated by KubeOne, the same as of the kubeadm certificates
+	DefaultCertificateValidity = 365 * 24 * time.Hour
+	// DefaultCertificateRenewBefore is the default renewal threshold of the
+	// control plane certificates
+	DefaultCertificateRenewBefore = 90 * 24 * time.Hour
+)
+
+// CertificateValidity returns the validity period of the certificates
+// generated by KubeOne, defaulting to 1 year
+func (c KubeOneCluster) CertificateValidity() time.Duration {
+	if c.CertificateAuthority != nil && c.CertificateAuthority.CertificateValidity != nil {
+		return c.CertificateAuthority.CertificateValidity.Duration
+	}
+
+	return DefaultCertificateValidity
+}
+
+// CertificateRenewBefore returns the renewal threshold of the control plane
+// certificates, defaulting to 90 days
+func (c KubeOneCluster) CertificateRenewBefore() time.Duration {
+	if c.CertificateAuthority != nil && c.CertificateAuthority.RenewBefore != nil {
+		return c.CertificateAuthority.RenewBefore.Duration
+	}
+
+	return DefaultCertificateRenewBefore
+}
+
+// KubeadmKeyAlgorithm returns the algorithm of the keys generated by kubeadm,
+// which supports only RSA and ECDSA keys
+func (c KubeOneCluster) KubeadmKeyAlgorithm() KeyAlgorithm {
+	if c.CertificateKeyAlgorithm() == KeyAlgorithmECDSA {
+		return KeyAlgorithmECDSA
+	}
+
+	return KeyAlgorithmRSA
+}
+
+// ExternalCASignerEnabled returns true if the Kubernetes CA key is not available
+// and the certificates are signed by the external CA signer plugin or the key
+// provider
+func (c KubeOneCluster) ExternalCASignerEnabled() bool {
+	ca := c.ExternalCA()
+
+	return ca != nil && ca.IntermediateKey == ""
+}
@@ -16,7 +16,14 @@ limitations under the License.
 
 package kubeone
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 func TestFeatureGatesString(t *testing.T) {
 	t.Parallel()
@@ -70,3 +77,492 @@ func TestFeatureGatesString(t *testing.T) {
 		})
 	}
 }
+
+func TestCSIMigrationFeatureGates(t *testing.T) {
+	t.Parallel()
+
+	ebsCSI := &AWSSpec{EBSCSI: &AWSEBSCSIConfig{Enable: true}}
+
+	testCases := []struct {
+		name          string
+		cloudProvider CloudProviderSpec
+		version       string
+		complete      bool
+		expected      string
+		expectedErr   bool
+	}{
+		{
+			name:          "aws without ebs csi",
+			cloudProvider: CloudProviderSpec{AWS: &AWSSpec{}, External: true},
+			version:       "1.22.3",
+			expectedErr:   true,
+		},
+		{
+			name:          "aws with ebs csi",
+			cloudProvider: CloudProviderSpec{AWS: ebsCSI, External: true},
+			version:       "1.22.3",
+			expected:      "CSIMigrationAWS=true",
+		},
+		{
+			name:          "aws with ebs csi complete",
+			cloudProvider: CloudProviderSpec{AWS: ebsCSI, External: true},
+			version:       "1.22.3",
+			complete:      true,
+			expected:      "CSIMigrationAWS=true,InTreePluginAWSUnregister=true",
+		},
+		{
+			name:          "aws 1.20 with ebs csi complete",
+			cloudProvider: CloudProviderSpec{AWS: ebsCSI, External: true},
+			version:       "1.20.11",
+			complete:      true,
+			expected:      "CSIMigrationAWS=true,CSIMigrationAWSComplete=true",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			c := KubeOneCluster{
+				CloudProvider: tc.cloudProvider,
+				Versions:      VersionConfig{Kubernetes: tc.version},
+			}
+
+			if c.CloudProvider.CSIMigrationSupported() == tc.expectedErr {
+				t.Errorf("CSIMigrationSupported() = %t, expected %t", !tc.expectedErr, tc.expectedErr)
+			}
+
+			_, got, err := c.CSIMigrationFeatureGates(tc.complete)
+			if (err != nil) != tc.expectedErr {
+				t.Fatalf("CSIMigrationFeatureGates() error = %v, expected error %t", err, tc.expectedErr)
+			}
+			if got != tc.expected {
+				t.Errorf("CSIMigrationFeatureGates() got = %v, expected %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestIngressControllerServiceType(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name              string
+		cloudProvider     CloudProviderSpec
+		ingressController *IngressController
+		expected          corev1.ServiceType
+	}{
+		{
+			name:          "aws",
+			cloudProvider: CloudProviderSpec{AWS: &AWSSpec{}},
+			expected:      corev1.ServiceTypeLoadBalancer,
+		},
+		{
+			name:          "vsphere",
+			cloudProvider: CloudProviderSpec{Vsphere: &VsphereSpec{}},
+			expected:      corev1.ServiceTypeNodePort,
+		},
+		{
+			name:              "none with LoadBalancer",
+			cloudProvider:     CloudProviderSpec{None: &NoneSpec{}},
+			ingressController: &IngressController{Enable: true, ServiceType: corev1.ServiceTypeLoadBalancer},
+			expected:          corev1.ServiceTypeLoadBalancer,
+		},
+		{
+			name:              "hetzner with host network",
+			cloudProvider:     CloudProviderSpec{Hetzner: &HetznerSpec{}},
+			ingressController: &IngressController{Enable: true, HostNetwork: true},
+			expected:          corev1.ServiceTypeClusterIP,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			c := KubeOneCluster{
+				CloudProvider: tc.cloudProvider,
+				Features:      Features{IngressController: tc.ingressController},
+			}
+
+			if got := c.IngressControllerServiceType(); got != tc.expected {
+				t.Errorf("IngressControllerServiceType() = %v, expected %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestVeleroProvider(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		cloudProvider CloudProviderSpec
+		velero        *Velero
+		expected      VeleroProvider
+	}{
+		{
+			name:          "aws",
+			cloudProvider: CloudProviderSpec{AWS: &AWSSpec{}},
+			velero:        &Velero{Enable: true},
+			expected:      VeleroProviderAWS,
+		},
+		{
+			name:          "gce",
+			cloudProvider: CloudProviderSpec{GCE: &GCESpec{}},
+			velero:        &Velero{Enable: true},
+			expected:      VeleroProviderGCP,
+		},
+		{
+			name:          "azure",
+			cloudProvider: CloudProviderSpec{Azure: &AzureSpec{}},
+			expected:      VeleroProviderAzure,
+		},
+		{
+			name:          "vsphere with aws",
+			cloudProvider: CloudProviderSpec{Vsphere: &VsphereSpec{}},
+			velero:        &Velero{Enable: true, Provider: VeleroProviderAWS},
+			expected:      VeleroProviderAWS,
+		},
+		{
+			name:          "hetzner",
+			cloudProvider: CloudProviderSpec{Hetzner: &HetznerSpec{}},
+			velero:        &Velero{Enable: true},
+			expected:      "",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			c := KubeOneCluster{
+				CloudProvider: tc.cloudProvider,
+				Features:      Features{Velero: tc.velero},
+			}
+
+			if got := c.VeleroProvider(); got != tc.expected {
+				t.Errorf("VeleroProvider() = %v, expected %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestNodeNamingStrategy(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		cloudProvider CloudProviderSpec
+		nodeNaming    *NodeNamingConfig
+		expected      NodeNamingStrategy
+	}{
+		{
+			name:          "aws default",
+			cloudProvider: CloudProviderSpec{AWS: &AWSSpec{}},
+			expected:      NodeNamingFQDN,
+		},
+		{
+			name:          "azure default",
+			cloudProvider: CloudProviderSpec{Azure: &AzureSpec{}},
+			nodeNaming:    &NodeNamingConfig{},
+			expected:      NodeNamingHostname,
+		},
+		{
+			name:          "explicit strategy",
+			cloudProvider: CloudProviderSpec{Azure: &AzureSpec{}},
+			nodeNaming:    &NodeNamingConfig{Strategy: NodeNamingPrivateIP},
+			expected:      NodeNamingPrivateIP,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			c := KubeOneCluster{
+				CloudProvider: tc.cloudProvider,
+				NodeNaming:    tc.nodeNaming,
+			}
+
+			if got := c.NodeNamingStrategy(); got != tc.expected {
+				t.Errorf("NodeNamingStrategy() = %v, expected %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestNodeDrainConfig(t *testing.T) {
+	t.Parallel()
+
+	gracePeriod := 30
+	ignorePDBs := true
+	keepEmptyDirData := false
+
+	cluster := KubeOneCluster{
+		NodeDrain: &NodeDrainConfig{
+			GracePeriodSeconds: &gracePeriod,
+			Timeout:            &metav1.Duration{Duration: 10 * time.Minute},
+		},
+	}
+
+	testCases := []struct {
+		name                       string
+		host                       HostConfig
+		expectedGracePeriodSeconds int
+		expectedTimeout            time.Duration
+		expectedDeleteEmptyDirData bool
+		expectedIgnorePDBs         bool
+	}{
+		{
+			name:                       "cluster-wide configuration",
+			host:                       HostConfig{},
+			expectedGracePeriodSeconds: 30,
+			expectedTimeout:            10 * time.Minute,
+			expectedDeleteEmptyDirData: true,
+			expectedIgnorePDBs:         false,
+		},
+		{
+			name: "host overrides",
+			host: HostConfig{
+				NodeDrain: &NodeDrainConfig{
+					DeleteEmptyDirData:         &keepEmptyDirData,
+					IgnorePodDisruptionBudgets: &ignorePDBs,
+				},
+			},
+			expectedGracePeriodSeconds: 30,
+			expectedTimeout:            10 * time.Minute,
+			expectedDeleteEmptyDirData: false,
+			expectedIgnorePDBs:         true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := cluster.NodeDrainConfig(tc.host)
+			if *got.GracePeriodSeconds != tc.expectedGracePeriodSeconds {
+				t.Errorf("NodeDrainConfig() gracePeriodSeconds = %v, expected %v", *got.GracePeriodSeconds, tc.expectedGracePeriodSeconds)
+			}
+			if got.Timeout.Duration != tc.expectedTimeout {
+				t.Errorf("NodeDrainConfig() timeout = %v, expected %v", got.Timeout.Duration, tc.expectedTimeout)
+			}
+			if *got.DeleteEmptyDirData != tc.expectedDeleteEmptyDirData {
+				t.Errorf("NodeDrainConfig() deleteEmptyDirData = %v, expected %v", *got.DeleteEmptyDirData, tc.expectedDeleteEmptyDirData)
+			}
+			if *got.IgnorePodDisruptionBudgets != tc.expectedIgnorePDBs {
+				t.Errorf("NodeDrainConfig() ignorePodDisruptionBudgets = %v, expected %v", *got.IgnorePodDisruptionBudgets, tc.expectedIgnorePDBs)
+			}
+		})
+	}
+}
+
+func TestSetLeader(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		leader         string
+		expectedLeader string
+		expectedError  bool
+	}{
+		{
+			name:           "by hostname",
+			leader:         "cp-2",
+			expectedLeader: "cp-2",
+		},
+		{
+			name:           "by public address",
+			leader:         "192.168.1.3",
+			expectedLeader: "cp-3",
+		},
+		{
+			name:           "by private address",
+			leader:         "10.0.0.2",
+			expectedLeader: "cp-2",
+		},
+		{
+			name:          "unknown host",
+			leader:        "cp-4",
+			expectedError: true,
+		},
+		{
+			name:          "empty name",
+			leader:        "",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cluster := KubeOneCluster{
+				ControlPlane: ControlPlaneConfig{
+					Hosts: []HostConfig{
+						{Hostname: "cp-1", PublicAddress: "192.168.1.1", PrivateAddress: "10.0.0.1", IsLeader: true},
+						{Hostname: "cp-2", PublicAddress: "192.168.1.2", PrivateAddress: "10.0.0.2"},
+						{Hostname: "cp-3", PublicAddress: "192.168.1.3", PrivateAddress: "10.0.0.3"},
+					},
+				},
+			}
+
+			err := cluster.SetLeader(tc.leader)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("SetLeader() error = %v, expectedError %v", err, tc.expectedError)
+			}
+			if tc.expectedError {
+				return
+			}
+
+			leader, err := cluster.Leader()
+			if err != nil {
+				t.Fatalf("Leader() error = %v", err)
+			}
+			if leader.Hostname != tc.expectedLeader {
+				t.Errorf("SetLeader() leader = %q, expected %q", leader.Hostname, tc.expectedLeader)
+			}
+
+			leaders := 0
+			for _, host := range cluster.ControlPlane.Hosts {
+				if host.IsLeader {
+					leaders++
+				}
+			}
+			if leaders != 1 {
+				t.Errorf("SetLeader() resulted in %d leaders, expected 1", leaders)
+			}
+		})
+	}
+}
+
+func TestMergePreflightErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		lists    [][]string
+		expected []string
+	}{
+		{
+			name:     "no errors",
+			lists:    [][]string{nil, nil},
+			expected: nil,
+		},
+		{
+			name:     "duplicates dropped",
+			lists:    [][]string{{"ImagePull", "NumCPU"}, {"numcpu", "SystemVerification"}},
+			expected: []string{"ImagePull", "NumCPU", "SystemVerification"},
+		},
+		{
+			name:     "all",
+			lists:    [][]string{{"ImagePull"}, {"All"}},
+			expected: []string{"all"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := MergePreflightErrors(tc.lists...)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("MergePreflightErrors() = %v, expected %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestIgnorePreflightErrorsConfigWithPreflight(t *testing.T) {
+	t.Parallel()
+
+	cluster := KubeOneCluster{
+		IgnorePreflightErrors: &IgnorePreflightErrorsConfig{
+			Install: []string{"NumCPU"},
+		},
+		Preflight: &PreflightConfig{
+			Skip:     []string{"kubeadm:Swap", "time-sync"},
+			WarnOnly: []string{"kubeadm:numcpu", "version-skew"},
+		},
+	}
+
+	got := cluster.IgnorePreflightErrorsConfig(HostConfig{})
+	expected := IgnorePreflightErrorsConfig{
+		Install: []string{"NumCPU", "Swap"},
+		Upgrade: []string{"Swap", "numcpu"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("IgnorePreflightErrorsConfig() = %v, expected %v", got, expected)
+	}
+
+	if !cluster.PreflightSkipped(PreflightCheckTimeSync) || cluster.PreflightSkipped(PreflightCheckVersionSkew) {
+		t.Errorf("PreflightSkipped() should be true only for %q", PreflightCheckTimeSync)
+	}
+	if !cluster.PreflightWarnOnly(PreflightCheckVersionSkew) || cluster.PreflightWarnOnly(PreflightCheckTimeSync) {
+		t.Errorf("PreflightWarnOnly() should be true only for %q", PreflightCheckVersionSkew)
+	}
+}
+
+func TestHostOSTuning(t *testing.T) {
+	t.Parallel()
+
+	cluster := KubeOneCluster{
+		OSTuning: &OSTuningConfig{
+			All: OSTuning{
+				KernelModules: []string{"nf_conntrack", "br_netfilter"},
+				Sysctls: map[string]string{
+					"net.ipv4.conf.all.rp_filter":    "1",
+					"net.netfilter.nf_conntrack_max": "1000000",
+				},
+			},
+			ControlPlane: OSTuning{
+				KernelModules: []string{"nf_conntrack", "ip_vs"},
+				Sysctls: map[string]string{
+					"net.ipv4.conf.all.rp_filter": "2",
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name         string
+		cluster      KubeOneCluster
+		controlPlane bool
+		expected     OSTuning
+	}{
+		{
+			name:     "not configured",
+			cluster:  KubeOneCluster{},
+			expected: OSTuning{},
+		},
+		{
+			name:         "control plane overrides",
+			cluster:      cluster,
+			controlPlane: true,
+			expected: OSTuning{
+				KernelModules: []string{"br_netfilter", "ip_vs", "nf_conntrack"},
+				Sysctls: map[string]string{
+					"net.ipv4.conf.all.rp_filter":    "2",
+					"net.netfilter.nf_conntrack_max": "1000000",
+				},
+			},
+		},
+		{
+			name:    "static workers",
+			cluster: cluster,
+			expected: OSTuning{
+				KernelModules: []string{"br_netfilter", "nf_conntrack"},
+				Sysctls: map[string]string{
+					"net.ipv4.conf.all.rp_filter":    "1",
+					"net.netfilter.nf_conntrack_max": "1000000",
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.cluster.HostOSTuning(tc.controlPlane)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("HostOSTuning() = %v, expected %v", got, tc.expected)
+			}
+		})
+	}
+}
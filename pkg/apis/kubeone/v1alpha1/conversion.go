@@ -298,6 +298,17 @@ func Convert_v1alpha1_MachineControllerConfig_To_kubeone_MachineControllerConfig
 	return nil
 }
 
+func Convert_kubeone_MachineControllerConfig_To_v1alpha1_MachineControllerConfig(in *kubeoneapi.MachineControllerConfig, out *MachineControllerConfig, s conversion.Scope) error {
+	// The image overrides and the deployment settings don't exist in the
+	// v1alpha1 API.
+	return autoConvert_kubeone_MachineControllerConfig_To_v1alpha1_MachineControllerConfig(in, out, s)
+}
+
+func Convert_kubeone_MetricsServer_To_v1alpha1_MetricsServer(in *kubeoneapi.MetricsServer, out *MetricsServer, s conversion.Scope) error {
+	// The metrics-server deployment settings don't exist in the v1alpha1 API.
+	return autoConvert_kubeone_MetricsServer_To_v1alpha1_MetricsServer(in, out, s)
+}
+
 func Convert_kubeone_Features_To_v1alpha1_Features(in *kubeoneapi.Features, out *Features, s conversion.Scope) error {
 	return autoConvert_kubeone_Features_To_v1alpha1_Features(in, out, s)
 }
@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"hash/fnv"
+	"sort"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -58,14 +60,12 @@ func SetDefaults_Hosts(obj *KubeOneCluster) {
 
 	setDefaultLeader := true
 
-	// Define a unique ID for each host
 	for idx := range obj.Hosts {
 		if setDefaultLeader && obj.Hosts[idx].IsLeader {
 			// override setting default leader, as explicit leader already
 			// defined
 			setDefaultLeader = false
 		}
-		obj.Hosts[idx].ID = idx
 		defaultHostConfig(&obj.Hosts[idx])
 	}
 	if setDefaultLeader {
@@ -75,10 +75,10 @@ func SetDefaults_Hosts(obj *KubeOneCluster) {
 	}
 
 	for idx := range obj.StaticWorkers {
-		// continue assinging IDs after control plane hosts. This way every node gets a unique ID regardless of the different host slices
-		obj.StaticWorkers[idx].ID = idx + len(obj.Hosts)
 		defaultHostConfig(&obj.StaticWorkers[idx])
 	}
+
+	setDefaultHostIDs(obj.Hosts, obj.StaticWorkers)
 }
 
 func SetDefaults_APIEndpoints(obj *KubeOneCluster) {
@@ -182,6 +182,50 @@ func defaultStaticAuditLogConfig(obj *StaticAuditLogConfig) {
 	obj.LogMaxSize = defaulti(obj.LogMaxSize, 100)
 }
 
+// maxHostID is the largest host ID, so the IDs fit int32 on all platforms
+const maxHostID = 1<<31 - 1
+
+// setDefaultHostIDs assigns a unique ID to each host, derived from the hash of
+// the host addresses and hostname. IDs depend neither on the order of the hosts
+// in the manifest nor on the other hosts, so adding, removing or reordering
+// hosts doesn't change the IDs of the remaining hosts. In the unlikely case of
+// a hash collision, the next free ID is used, in the order of the host
+// addresses. IDs are assigned at runtime and aren't persisted, so changing how
+// they're assigned doesn't require any migration.
+func setDefaultHostIDs(hostLists ...[]HostConfig) {
+	hosts := []*HostConfig{}
+	for i := range hostLists {
+		for j := range hostLists[i] {
+			hosts = append(hosts, &hostLists[i][j])
+		}
+	}
+
+	sort.SliceStable(hosts, func(i, j int) bool {
+		return hostIDKey(*hosts[i]) < hostIDKey(*hosts[j])
+	})
+
+	used := map[int]bool{}
+	for _, host := range hosts {
+		id := hostIDHash(*host)
+		for used[id] {
+			id = (id + 1) & maxHostID
+		}
+		used[id] = true
+		host.ID = id
+	}
+}
+
+func hostIDHash(host HostConfig) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(hostIDKey(host)))
+
+	return int(h.Sum32() & maxHostID)
+}
+
+func hostIDKey(host HostConfig) string {
+	return strings.Join([]string{host.PrivateAddress, host.PublicAddress, host.Hostname}, "/")
+}
+
 func defaultHostConfig(obj *HostConfig) {
 	if len(obj.PublicAddress) == 0 && len(obj.PrivateAddress) > 0 {
 		obj.PublicAddress = obj.PrivateAddress
@@ -73,21 +73,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*kubeone.MachineControllerConfig)(nil), (*MachineControllerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_kubeone_MachineControllerConfig_To_v1alpha1_MachineControllerConfig(a.(*kubeone.MachineControllerConfig), b.(*MachineControllerConfig), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*MetricsServer)(nil), (*kubeone.MetricsServer)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_MetricsServer_To_kubeone_MetricsServer(a.(*MetricsServer), b.(*kubeone.MetricsServer), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*kubeone.MetricsServer)(nil), (*MetricsServer)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_kubeone_MetricsServer_To_v1alpha1_MetricsServer(a.(*kubeone.MetricsServer), b.(*MetricsServer), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*OpenIDConnect)(nil), (*kubeone.OpenIDConnect)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_OpenIDConnect_To_kubeone_OpenIDConnect(a.(*OpenIDConnect), b.(*kubeone.OpenIDConnect), scope)
 	}); err != nil {
@@ -243,6 +233,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*kubeone.MachineControllerConfig)(nil), (*MachineControllerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_MachineControllerConfig_To_v1alpha1_MachineControllerConfig(a.(*kubeone.MachineControllerConfig), b.(*MachineControllerConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*kubeone.MetricsServer)(nil), (*MetricsServer)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_MetricsServer_To_v1alpha1_MetricsServer(a.(*kubeone.MetricsServer), b.(*MetricsServer), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*kubeone.ProviderSpec)(nil), (*ProviderSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_kubeone_ProviderSpec_To_v1alpha1_ProviderSpec(a.(*kubeone.ProviderSpec), b.(*ProviderSpec), scope)
 	}); err != nil {
@@ -352,6 +352,7 @@ func autoConvert_kubeone_CloudProviderSpec_To_v1alpha1_CloudProviderSpec(in *kub
 	// WARNING: in.Packet requires manual conversion: does not exist in peer-type
 	// WARNING: in.Vsphere requires manual conversion: does not exist in peer-type
 	// WARNING: in.None requires manual conversion: does not exist in peer-type
+	// WARNING: in.CredentialsVault requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -437,7 +438,15 @@ func autoConvert_v1alpha1_Features_To_kubeone_Features(in *Features, out *kubeon
 	out.PodSecurityPolicy = (*kubeone.PodSecurityPolicy)(unsafe.Pointer(in.PodSecurityPolicy))
 	out.StaticAuditLog = (*kubeone.StaticAuditLog)(unsafe.Pointer(in.StaticAuditLog))
 	out.DynamicAuditLog = (*kubeone.DynamicAuditLog)(unsafe.Pointer(in.DynamicAuditLog))
-	out.MetricsServer = (*kubeone.MetricsServer)(unsafe.Pointer(in.MetricsServer))
+	if in.MetricsServer != nil {
+		in, out := &in.MetricsServer, &out.MetricsServer
+		*out = new(kubeone.MetricsServer)
+		if err := Convert_v1alpha1_MetricsServer_To_kubeone_MetricsServer(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.MetricsServer = nil
+	}
 	out.OpenIDConnect = (*kubeone.OpenIDConnect)(unsafe.Pointer(in.OpenIDConnect))
 	return nil
 }
@@ -453,9 +462,24 @@ func autoConvert_kubeone_Features_To_v1alpha1_Features(in *kubeone.Features, out
 	out.PodSecurityPolicy = (*PodSecurityPolicy)(unsafe.Pointer(in.PodSecurityPolicy))
 	out.StaticAuditLog = (*StaticAuditLog)(unsafe.Pointer(in.StaticAuditLog))
 	out.DynamicAuditLog = (*DynamicAuditLog)(unsafe.Pointer(in.DynamicAuditLog))
-	out.MetricsServer = (*MetricsServer)(unsafe.Pointer(in.MetricsServer))
+	if in.MetricsServer != nil {
+		in, out := &in.MetricsServer, &out.MetricsServer
+		*out = new(MetricsServer)
+		if err := Convert_kubeone_MetricsServer_To_v1alpha1_MetricsServer(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.MetricsServer = nil
+	}
 	out.OpenIDConnect = (*OpenIDConnect)(unsafe.Pointer(in.OpenIDConnect))
 	// WARNING: in.EncryptionProviders requires manual conversion: does not exist in peer-type
+	// WARNING: in.NvidiaGPU requires manual conversion: does not exist in peer-type
+	// WARNING: in.Monitoring requires manual conversion: does not exist in peer-type
+	// WARNING: in.ClusterAutoscaler requires manual conversion: does not exist in peer-type
+	// WARNING: in.CertManager requires manual conversion: does not exist in peer-type
+	// WARNING: in.IngressController requires manual conversion: does not exist in peer-type
+	// WARNING: in.GitOps requires manual conversion: does not exist in peer-type
+	// WARNING: in.Velero requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -484,13 +508,21 @@ func autoConvert_kubeone_HostConfig_To_v1alpha1_HostConfig(in *kubeone.HostConfi
 	out.SSHPort = in.SSHPort
 	out.SSHUsername = in.SSHUsername
 	out.SSHPrivateKeyFile = in.SSHPrivateKeyFile
+	// WARNING: in.SSHPrivateKeyPassphrase requires manual conversion: does not exist in peer-type
+	// WARNING: in.SSHCertFile requires manual conversion: does not exist in peer-type
 	out.SSHAgentSocket = in.SSHAgentSocket
 	out.Bastion = in.Bastion
 	out.BastionPort = in.BastionPort
 	out.BastionUser = in.BastionUser
+	// WARNING: in.BastionHosts requires manual conversion: does not exist in peer-type
 	out.Hostname = in.Hostname
 	out.IsLeader = in.IsLeader
 	// WARNING: in.Taints requires manual conversion: does not exist in peer-type
+	// WARNING: in.Labels requires manual conversion: does not exist in peer-type
+	// WARNING: in.NodeDrain requires manual conversion: does not exist in peer-type
+	// WARNING: in.IgnorePreflightErrors requires manual conversion: does not exist in peer-type
+	// WARNING: in.NvidiaGPU requires manual conversion: does not exist in peer-type
+	// WARNING: in.Kubelet requires manual conversion: does not exist in peer-type
 	out.OperatingSystem = string(in.OperatingSystem)
 	return nil
 }
@@ -587,6 +619,20 @@ func autoConvert_kubeone_KubeOneCluster_To_v1alpha1_KubeOneCluster(in *kubeone.K
 	out.SystemPackages = (*SystemPackages)(unsafe.Pointer(in.SystemPackages))
 	// WARNING: in.AssetConfiguration requires manual conversion: does not exist in peer-type
 	// WARNING: in.RegistryConfiguration requires manual conversion: does not exist in peer-type
+	// WARNING: in.RBAC requires manual conversion: does not exist in peer-type
+	// WARNING: in.NodeDrain requires manual conversion: does not exist in peer-type
+	// WARNING: in.IgnorePreflightErrors requires manual conversion: does not exist in peer-type
+	// WARNING: in.ManifestPatches requires manual conversion: does not exist in peer-type
+	// WARNING: in.ClusterSize requires manual conversion: does not exist in peer-type
+	// WARNING: in.Preflight requires manual conversion: does not exist in peer-type
+	// WARNING: in.HostReboot requires manual conversion: does not exist in peer-type
+	// WARNING: in.OSTuning requires manual conversion: does not exist in peer-type
+	// WARNING: in.TimeSync requires manual conversion: does not exist in peer-type
+	// WARNING: in.Hardening requires manual conversion: does not exist in peer-type
+	// WARNING: in.SecurityModules requires manual conversion: does not exist in peer-type
+	// WARNING: in.Firewall requires manual conversion: does not exist in peer-type
+	// WARNING: in.CertificateAuthority requires manual conversion: does not exist in peer-type
+	// WARNING: in.NodeNaming requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -598,14 +644,15 @@ func autoConvert_v1alpha1_MachineControllerConfig_To_kubeone_MachineControllerCo
 
 func autoConvert_kubeone_MachineControllerConfig_To_v1alpha1_MachineControllerConfig(in *kubeone.MachineControllerConfig, out *MachineControllerConfig, s conversion.Scope) error {
 	out.Deploy = in.Deploy
+	// WARNING: in.ImageRepository requires manual conversion: does not exist in peer-type
+	// WARNING: in.ImageTag requires manual conversion: does not exist in peer-type
+	// WARNING: in.Resources requires manual conversion: does not exist in peer-type
+	// WARNING: in.NodeSelector requires manual conversion: does not exist in peer-type
+	// WARNING: in.Tolerations requires manual conversion: does not exist in peer-type
+	// WARNING: in.ExtraFlags requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_kubeone_MachineControllerConfig_To_v1alpha1_MachineControllerConfig is an autogenerated conversion function.
-func Convert_kubeone_MachineControllerConfig_To_v1alpha1_MachineControllerConfig(in *kubeone.MachineControllerConfig, out *MachineControllerConfig, s conversion.Scope) error {
-	return autoConvert_kubeone_MachineControllerConfig_To_v1alpha1_MachineControllerConfig(in, out, s)
-}
-
 func autoConvert_v1alpha1_MetricsServer_To_kubeone_MetricsServer(in *MetricsServer, out *kubeone.MetricsServer, s conversion.Scope) error {
 	out.Enable = in.Enable
 	return nil
@@ -618,14 +665,13 @@ func Convert_v1alpha1_MetricsServer_To_kubeone_MetricsServer(in *MetricsServer,
 
 func autoConvert_kubeone_MetricsServer_To_v1alpha1_MetricsServer(in *kubeone.MetricsServer, out *MetricsServer, s conversion.Scope) error {
 	out.Enable = in.Enable
+	// WARNING: in.Replicas requires manual conversion: does not exist in peer-type
+	// WARNING: in.Resources requires manual conversion: does not exist in peer-type
+	// WARNING: in.KubeletInsecureTLS requires manual conversion: does not exist in peer-type
+	// WARNING: in.HostNetwork requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_kubeone_MetricsServer_To_v1alpha1_MetricsServer is an autogenerated conversion function.
-func Convert_kubeone_MetricsServer_To_v1alpha1_MetricsServer(in *kubeone.MetricsServer, out *MetricsServer, s conversion.Scope) error {
-	return autoConvert_kubeone_MetricsServer_To_v1alpha1_MetricsServer(in, out, s)
-}
-
 func autoConvert_v1alpha1_OpenIDConnect_To_kubeone_OpenIDConnect(in *OpenIDConnect, out *kubeone.OpenIDConnect, s conversion.Scope) error {
 	out.Enable = in.Enable
 	if err := Convert_v1alpha1_OpenIDConnectConfig_To_kubeone_OpenIDConnectConfig(&in.Config, &out.Config, s); err != nil {
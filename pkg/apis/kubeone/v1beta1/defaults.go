@@ -17,11 +17,14 @@ limitations under the License.
 package v1beta1
 
 import (
+	"hash/fnv"
+	"sort"
 	"strings"
 
 	"github.com/Masterminds/semver/v3"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -38,6 +41,10 @@ const (
 	DefaultStaticNoProxy = "127.0.0.1/8,localhost"
 	// DefaultCanalMTU defines default VXLAN MTU for Canal CNI
 	DefaultCanalMTU = 1450
+	// DefaultCosignCertificateIdentity is the identity used to sign the Kubernetes release binaries
+	DefaultCosignCertificateIdentity = "krel-staging@k8s-releng-prod.iam.gserviceaccount.com"
+	// DefaultCosignCertificateOIDCIssuer is the OIDC issuer of the Kubernetes release binaries signing identity
+	DefaultCosignCertificateOIDCIssuer = "https://accounts.google.com"
 )
 
 func addDefaultingFuncs(scheme *runtime.Scheme) error {
@@ -66,14 +73,12 @@ func SetDefaults_Hosts(obj *KubeOneCluster) {
 
 	setDefaultLeader := true
 
-	// Define a unique ID for each host
 	for idx := range obj.ControlPlane.Hosts {
 		if setDefaultLeader && obj.ControlPlane.Hosts[idx].IsLeader {
 			// override setting default leader, as explicit leader already
 			// defined
 			setDefaultLeader = false
 		}
-		obj.ControlPlane.Hosts[idx].ID = idx
 		defaultHostConfig(&obj.ControlPlane.Hosts[idx])
 		if obj.ControlPlane.Hosts[idx].Taints == nil {
 			obj.ControlPlane.Hosts[idx].Taints = []corev1.Taint{
@@ -91,13 +96,13 @@ func SetDefaults_Hosts(obj *KubeOneCluster) {
 	}
 
 	for idx := range obj.StaticWorkers.Hosts {
-		// continue assinging IDs after control plane hosts. This way every node gets a unique ID regardless of the different host slices
-		obj.StaticWorkers.Hosts[idx].ID = idx + len(obj.ControlPlane.Hosts)
 		defaultHostConfig(&obj.StaticWorkers.Hosts[idx])
 		if obj.StaticWorkers.Hosts[idx].Taints == nil {
 			obj.StaticWorkers.Hosts[idx].Taints = []corev1.Taint{}
 		}
 	}
+
+	setDefaultHostIDs(obj.ControlPlane.Hosts, obj.StaticWorkers.Hosts)
 }
 
 func SetDefaults_APIEndpoints(obj *KubeOneCluster) {
@@ -166,6 +171,10 @@ func SetDefaults_ClusterNetwork(obj *KubeOneCluster) {
 	if obj.ClusterNetwork.CNI.Cilium != nil && obj.ClusterNetwork.CNI.Cilium.KubeProxyReplacement == "" {
 		obj.ClusterNetwork.CNI.Cilium.KubeProxyReplacement = "disabled"
 	}
+
+	if obj.ClusterNetwork.CNI.External != nil && obj.ClusterNetwork.CNI.External.HelmChart != nil {
+		obj.ClusterNetwork.CNI.External.HelmChart.Namespace = defaults(obj.ClusterNetwork.CNI.External.HelmChart.Namespace, metav1.NamespaceSystem)
+	}
 }
 
 func SetDefaults_Proxy(obj *KubeOneCluster) {
@@ -201,6 +210,11 @@ func SetDefaults_SystemPackages(obj *KubeOneCluster) {
 }
 
 func SetDefaults_AssetConfiguration(obj *KubeOneCluster) {
+	if sv := obj.AssetConfiguration.SignatureVerification; sv != nil && sv.PublicKey == "" {
+		sv.CertificateIdentity = defaults(sv.CertificateIdentity, DefaultCosignCertificateIdentity)
+		sv.CertificateOIDCIssuer = defaults(sv.CertificateOIDCIssuer, DefaultCosignCertificateOIDCIssuer)
+	}
+
 	if obj.RegistryConfiguration == nil || obj.RegistryConfiguration.OverwriteRegistry == "" {
 		// We default AssetConfiguration only if RegistryConfiguration.OverwriteRegistry
 		// is used
@@ -261,6 +275,50 @@ func defaultStaticAuditLogConfig(obj *StaticAuditLogConfig) {
 	obj.LogMaxSize = defaulti(obj.LogMaxSize, 100)
 }
 
+// maxHostID is the largest host ID, so the IDs fit int32 on all platforms
+const maxHostID = 1<<31 - 1
+
+// setDefaultHostIDs assigns a unique ID to each host, derived from the hash of
+// the host addresses and hostname. IDs depend neither on the order of the hosts
+// in the manifest nor on the other hosts, so adding, removing or reordering
+// hosts doesn't change the IDs of the remaining hosts. In the unlikely case of
+// a hash collision, the next free ID is used, in the order of the host
+// addresses. IDs are assigned at runtime and aren't persisted, so changing how
+// they're assigned doesn't require any migration.
+func setDefaultHostIDs(hostLists ...[]HostConfig) {
+	hosts := []*HostConfig{}
+	for i := range hostLists {
+		for j := range hostLists[i] {
+			hosts = append(hosts, &hostLists[i][j])
+		}
+	}
+
+	sort.SliceStable(hosts, func(i, j int) bool {
+		return hostIDKey(*hosts[i]) < hostIDKey(*hosts[j])
+	})
+
+	used := map[int]bool{}
+	for _, host := range hosts {
+		id := hostIDHash(*host)
+		for used[id] {
+			id = (id + 1) & maxHostID
+		}
+		used[id] = true
+		host.ID = id
+	}
+}
+
+func hostIDHash(host HostConfig) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(hostIDKey(host)))
+
+	return int(h.Sum32() & maxHostID)
+}
+
+func hostIDKey(host HostConfig) string {
+	return strings.Join([]string{host.PrivateAddress, host.PublicAddress, host.Hostname}, "/")
+}
+
 func defaultHostConfig(obj *HostConfig) {
 	if len(obj.PublicAddress) == 0 && len(obj.PrivateAddress) > 0 {
 		obj.PublicAddress = obj.PrivateAddress
@@ -275,6 +333,10 @@ func defaultHostConfig(obj *HostConfig) {
 	obj.SSHPort = defaulti(obj.SSHPort, 22)
 	obj.BastionPort = defaulti(obj.BastionPort, 22)
 	obj.BastionUser = defaults(obj.BastionUser, obj.SSHUsername)
+	for i := range obj.BastionHosts {
+		obj.BastionHosts[i].Port = defaulti(obj.BastionHosts[i].Port, 22)
+		obj.BastionHosts[i].User = defaults(obj.BastionHosts[i].User, obj.SSHUsername)
+	}
 }
 
 func defaults(input, defaultValue string) string {
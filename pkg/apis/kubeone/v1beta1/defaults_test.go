@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+)
+
+func TestSetDefaults_HostsIDs(t *testing.T) {
+	hostIDs := func(controlPlane, staticWorkers []HostConfig) map[string]int {
+		obj := &KubeOneCluster{
+			ControlPlane:  ControlPlaneConfig{Hosts: controlPlane},
+			StaticWorkers: StaticWorkersConfig{Hosts: staticWorkers},
+		}
+		SetDefaults_Hosts(obj)
+
+		ids := map[string]int{}
+		for _, host := range append(obj.ControlPlane.Hosts, obj.StaticWorkers.Hosts...) {
+			ids[host.PrivateAddress] = host.ID
+		}
+
+		return ids
+	}
+
+	original := hostIDs(
+		[]HostConfig{{PrivateAddress: "10.0.0.1"}, {PrivateAddress: "10.0.0.2"}},
+		[]HostConfig{{PrivateAddress: "10.0.0.3"}},
+	)
+
+	unique := map[int]bool{}
+	for _, id := range original {
+		unique[id] = true
+	}
+	if len(unique) != len(original) {
+		t.Fatalf("SetDefaults_Hosts() IDs = %v, expected unique IDs", original)
+	}
+
+	tests := []struct {
+		name          string
+		controlPlane  []HostConfig
+		staticWorkers []HostConfig
+	}{
+		{
+			name: "reordered hosts",
+			controlPlane: []HostConfig{
+				{PrivateAddress: "10.0.0.2"},
+				{PrivateAddress: "10.0.0.1"},
+			},
+			staticWorkers: []HostConfig{
+				{PrivateAddress: "10.0.0.3"},
+			},
+		},
+		{
+			name: "first host removed",
+			controlPlane: []HostConfig{
+				{PrivateAddress: "10.0.0.2"},
+			},
+			staticWorkers: []HostConfig{
+				{PrivateAddress: "10.0.0.3"},
+			},
+		},
+		{
+			name: "host added",
+			controlPlane: []HostConfig{
+				{PrivateAddress: "10.0.0.0"},
+				{PrivateAddress: "10.0.0.1"},
+				{PrivateAddress: "10.0.0.2"},
+			},
+			staticWorkers: []HostConfig{
+				{PrivateAddress: "10.0.0.3"},
+			},
+		},
+		{
+			name: "public address only",
+			controlPlane: []HostConfig{
+				{PublicAddress: "10.0.0.2"},
+				{PublicAddress: "10.0.0.1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got := hostIDs(tt.controlPlane, tt.staticWorkers)
+
+			for address, id := range got {
+				want, ok := original[address]
+				if ok && id != want {
+					t.Errorf("SetDefaults_Hosts() ID of %s = %d, want %d", address, id, want)
+				}
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -63,6 +64,44 @@ type KubeOneCluster struct {
 	AssetConfiguration AssetConfiguration `json:"assetConfiguration,omitempty"`
 	// RegistryConfiguration configures how Docker images are pulled from an image registry
 	RegistryConfiguration *RegistryConfiguration `json:"registryConfiguration,omitempty"`
+	// RBAC defines the RBAC resources created and reconciled by KubeOne
+	RBAC *RBACConfig `json:"rbac,omitempty"`
+	// NodeDrain configures how nodes are drained before they're upgraded or reset
+	NodeDrain *NodeDrainConfig `json:"nodeDrain,omitempty"`
+	// IgnorePreflightErrors are kubeadm preflight errors ignored on all hosts
+	IgnorePreflightErrors *IgnorePreflightErrorsConfig `json:"ignorePreflightErrors,omitempty"`
+	// ManifestPatches are patches applied to the manifests before they're applied to the cluster,
+	// including the addons (embedded ones such as CNI and machine-controller as well),
+	// MachineDeployments and the other objects created by KubeOne.
+	ManifestPatches []ManifestPatch `json:"manifestPatches,omitempty"`
+	// ClusterSize is the size the cluster is expected to grow to, used to verify
+	// the control plane hosts have enough resources
+	ClusterSize *ClusterSizeConfig `json:"clusterSize,omitempty"`
+	// Preflight configures which kubeadm and KubeOne preflight checks are skipped or
+	// reported only as warnings
+	Preflight *PreflightConfig `json:"preflight,omitempty"`
+	// HostReboot configures rebooting hosts when it's required by the provisioning,
+	// e.g. after the kernel is upgraded
+	HostReboot *HostRebootConfig `json:"hostReboot,omitempty"`
+	// OSTuning configures the kernel modules loaded and the sysctl settings applied on hosts
+	OSTuning *OSTuningConfig `json:"osTuning,omitempty"`
+	// TimeSync configures the time synchronization on hosts and the maximum clock skew
+	// allowed by the time-sync preflight check
+	TimeSync *TimeSyncConfig `json:"timeSync,omitempty"`
+	// Hardening configures the hardening profile applied to the hosts and
+	// the Kubernetes components
+	Hardening *HardeningConfig `json:"hardening,omitempty"`
+	// SecurityModules configures how SELinux and AppArmor are handled on the hosts
+	SecurityModules *SecurityModulesConfig `json:"securityModules,omitempty"`
+	// Firewall configures the firewall rules created on the hosts for the ports used
+	// by the Kubernetes components and the CNI plugin
+	Firewall *FirewallConfig `json:"firewall,omitempty"`
+	// CertificateAuthority configures the Kubernetes cluster CA. By default, the CA is
+	// generated by kubeadm on the first control plane host
+	CertificateAuthority *CertificateAuthorityConfig `json:"certificateAuthority,omitempty"`
+	// NodeNaming configures how the names of the Node objects are derived, for the
+	// control plane and static worker hosts
+	NodeNaming *NodeNamingConfig `json:"nodeNaming,omitempty"`
 }
 
 // ContainerRuntimeConfig
@@ -75,7 +114,41 @@ type ContainerRuntimeConfig struct {
 type ContainerRuntimeDocker struct{}
 
 // ContainerRuntimeContainerd defines docker container runtime
-type ContainerRuntimeContainerd struct{}
+type ContainerRuntimeContainerd struct {
+	// SandboxImage is the image used for the pod sandbox (pause) containers.
+	// Default: AssetConfiguration.Pause if specified, otherwise defaulted
+	// dynamically by containerd
+	SandboxImage string `json:"sandboxImage,omitempty"`
+	// CgroupDriver is the cgroup driver used by containerd and kubelet.
+	// Possible values: systemd, cgroupfs
+	// Default: systemd
+	CgroupDriver string `json:"cgroupDriver,omitempty"`
+	// SystemdCgroup configures runc to use the systemd cgroup driver.
+	// It must match CgroupDriver.
+	// Default: true if CgroupDriver is systemd
+	SystemdCgroup *bool `json:"systemdCgroup,omitempty"`
+	// NvidiaRuntime configures the NVIDIA container runtime
+	NvidiaRuntime *ContainerdNvidiaRuntime `json:"nvidiaRuntime,omitempty"`
+	// ConfigPatch is a TOML snippet merged into the config.toml generated
+	// by KubeOne. Values from ConfigPatch take precedence over the generated
+	// values.
+	ConfigPatch string `json:"configPatch,omitempty"`
+}
+
+// ContainerdNvidiaRuntime configures the nvidia runtime handler in containerd
+// and the nvidia RuntimeClass used to run GPU workloads. The NVIDIA driver and
+// the nvidia-container-runtime must be installed on the nodes.
+type ContainerdNvidiaRuntime struct {
+	// Enable the nvidia runtime handler and the nvidia RuntimeClass
+	Enable bool `json:"enable,omitempty"`
+	// BinaryName is the path to the nvidia-container-runtime binary
+	// Default: /usr/bin/nvidia-container-runtime
+	BinaryName string `json:"binaryName,omitempty"`
+	// DefaultRuntime makes nvidia the default runtime handler, so all pods
+	// are run using the nvidia runtime, not only pods using the nvidia
+	// RuntimeClass
+	DefaultRuntime bool `json:"defaultRuntime,omitempty"`
+}
 
 // OperatingSystemName defines the operating system used on instances
 type OperatingSystemName string
@@ -90,9 +163,32 @@ const (
 	OperatingSystemNameUnknown OperatingSystemName = ""
 )
 
+// BastionHost describes a single bastion (or jump) host in the chain of hosts
+// traversed to reach the host
+type BastionHost struct {
+	// Host is an IP or hostname of the bastion host.
+	Host string `json:"host"`
+	// Port is SSH port to use when connecting to the bastion host.
+	// Default value is 22.
+	Port int `json:"port,omitempty"`
+	// User is system login name to use when connecting to the bastion host.
+	// Default value is the SSHUsername of the host.
+	User string `json:"user,omitempty"`
+	// SSHPrivateKeyFile is path to the file with the private key used to
+	// authenticate to the bastion host, or reference to the secret with the
+	// key in the external secret store.
+	// Default value is "", the authentication configured for the host is used
+	// if neither SSHPrivateKeyFile nor SSHAgentSocket is set.
+	SSHPrivateKeyFile string `json:"sshPrivateKeyFile,omitempty"`
+	// SSHAgentSocket path (or reference to the environment) to the SSH agent
+	// unix domain socket used to authenticate to the bastion host.
+	// Default value is "".
+	SSHAgentSocket string `json:"sshAgentSocket,omitempty"`
+}
+
 // HostConfig describes a single control plane node.
 type HostConfig struct {
-	// ID automatically assigned at runtime.
+	// ID automatically assigned at runtime, based on the host addresses and hostname.
 	ID int `json:"-"`
 	// PublicAddress is externally accessible IP address from public internet.
 	PublicAddress string `json:"publicAddress"`
@@ -104,9 +200,22 @@ type HostConfig struct {
 	// SSHUsername is system login name.
 	// Default value is "root".
 	SSHUsername string `json:"sshUsername,omitempty"`
-	// SSHPrivateKeyFile is path to the file with PRIVATE AND CLEANTEXT ssh key.
+	// SSHPrivateKeyFile is path to the file with PRIVATE ssh key,
+	// or reference to the secret with the key in the external secret store,
+	// e.g. vault:secret/data/kubeone/ssh#privateKey.
 	// Default value is "".
 	SSHPrivateKeyFile string `json:"sshPrivateKeyFile,omitempty"`
+	// SSHPrivateKeyPassphrase is reference to the passphrase of the encrypted
+	// private key given in SSHPrivateKeyFile, either to the environment
+	// variable (e.g. env:SSH_KEY_PASSPHRASE) or to the secret in the external
+	// secret store. If the private key is encrypted and the passphrase is not
+	// given, the passphrase is prompted in the terminal.
+	// Default value is "".
+	SSHPrivateKeyPassphrase string `json:"sshPrivateKeyPassphrase,omitempty"`
+	// SSHCertFile is path to the OpenSSH certificate signed by the CA for the
+	// private key given in SSHPrivateKeyFile.
+	// Default value is "<SSHPrivateKeyFile>-cert.pub" if such file exists.
+	SSHCertFile string `json:"sshCertFile,omitempty"`
 	// SSHAgentSocket path (or reference to the environment) to the SSH agent unix domain socket.
 	// Default value is "env:SSH_AUTH_SOCK".
 	SSHAgentSocket string `json:"sshAgentSocket,omitempty"`
@@ -119,6 +228,11 @@ type HostConfig struct {
 	// BastionUser is system login name to use when connecting to bastion host.
 	// Default value is "root".
 	BastionUser string `json:"bastionUser,omitempty"`
+	// BastionHosts is the chain of bastion (or jump) hosts traversed in order
+	// to reach the host, e.g. bastion → jump host → host. Can't be used along
+	// with .Bastion.
+	// Default value is [].
+	BastionHosts []BastionHost `json:"bastionHosts,omitempty"`
 	// Hostname is the hostname(1) of the host.
 	// Default value is populated at the runtime via running `hostname -f` command over ssh.
 	Hostname string `json:"hostname,omitempty"`
@@ -129,20 +243,464 @@ type HostConfig struct {
 	// control plane nodes.
 	// Explicitly empty (i.e. []corev1.Taint{}) means no taints will be applied (this is default for worker nodes).
 	Taints []corev1.Taint `json:"taints,omitempty"`
+	// Labels are applied to the Node object of the host when it joins the cluster.
+	// Labels in the kubernetes.io and k8s.io namespaces can be set only if they're
+	// allowed to be set by the kubelet, e.g. node.kubernetes.io/instance-type.
+	Labels map[string]string `json:"labels,omitempty"`
+	// NodeDrain overrides the cluster-wide .nodeDrain configuration for this host.
+	NodeDrain *NodeDrainConfig `json:"nodeDrain,omitempty"`
+	// IgnorePreflightErrors are kubeadm preflight errors ignored on this host, in addition to the
+	// cluster-wide .ignorePreflightErrors.
+	IgnorePreflightErrors *IgnorePreflightErrorsConfig `json:"ignorePreflightErrors,omitempty"`
+	// NvidiaGPU installs the NVIDIA driver and the NVIDIA container toolkit on the host
+	// and labels the node, so the NVIDIA device plugin is scheduled on it.
+	// Requires the NvidiaGPU feature to be enabled.
+	NvidiaGPU bool `json:"nvidiaGPU,omitempty"`
+	// Kubelet configures the kubelet of the host. The configuration is applied when
+	// the host joins the cluster, and kubelet of the joined host is restarted when
+	// the configuration is changed.
+	Kubelet *KubeletConfig `json:"kubelet,omitempty"`
 	// OperatingSystem information populated at the runtime.
 	OperatingSystem OperatingSystemName `json:"-"`
 }
 
+// KubeletConfig configures the kubelet of the host, in addition to the kubelet
+// configuration shared by all hosts
+type KubeletConfig struct {
+	// MaxPods is the maximum number of pods running on the host
+	MaxPods *int32 `json:"maxPods,omitempty"`
+	// SystemReserved reserves the resources for the system daemons,
+	// e.g. cpu: 200m, memory: 500Mi
+	SystemReserved map[string]string `json:"systemReserved,omitempty"`
+	// KubeReserved reserves the resources for the Kubernetes components,
+	// e.g. cpu: 200m, memory: 500Mi
+	KubeReserved map[string]string `json:"kubeReserved,omitempty"`
+	// EvictionHard are the hard eviction thresholds, e.g. memory.available: 100Mi
+	EvictionHard map[string]string `json:"evictionHard,omitempty"`
+}
+
+// NodeDrainConfig configures how nodes are drained
+type NodeDrainConfig struct {
+	// GracePeriodSeconds is the period of time in seconds given to each pod to terminate gracefully.
+	// If negative, the grace period defined in the pod is used.
+	// Default value is -1.
+	GracePeriodSeconds *int `json:"gracePeriodSeconds,omitempty"`
+	// Timeout is the time to wait for the node to be drained before giving up, e.g. 10m.
+	// Zero means waiting infinitely.
+	// Default value is 0.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// DeleteEmptyDirData allows deleting pods using emptyDir volumes. Data stored in emptyDir volumes is lost.
+	// Default value is true.
+	DeleteEmptyDirData *bool `json:"deleteEmptyDirData,omitempty"`
+	// SkipWaitForDeleteTimeoutSeconds skips waiting for pods which have been deleted for longer than
+	// the given number of seconds. Zero means waiting for all deleted pods.
+	// Default value is 0.
+	SkipWaitForDeleteTimeoutSeconds *int `json:"skipWaitForDeleteTimeoutSeconds,omitempty"`
+	// IgnorePodDisruptionBudgets deletes pods instead of evicting them, bypassing PodDisruptionBudgets.
+	// It should be used only if strict PodDisruptionBudgets prevent the node from being drained.
+	// Default value is false.
+	IgnorePodDisruptionBudgets *bool `json:"ignorePodDisruptionBudgets,omitempty"`
+}
+
+// IgnorePreflightErrorsConfig defines kubeadm preflight errors to be ignored, e.g. NumCPU or
+// SystemVerification. The value "all" ignores all preflight errors.
+type IgnorePreflightErrorsConfig struct {
+	// Install are preflight errors ignored when running 'kubeadm init' and 'kubeadm join'.
+	Install []string `json:"install,omitempty"`
+	// Upgrade are preflight errors ignored when running 'kubeadm upgrade'.
+	Upgrade []string `json:"upgrade,omitempty"`
+}
+
+// ManifestPatch is a patch applied to all manifests matching the target
+type ManifestPatch struct {
+	// Target selects the manifests the patch is applied to. Empty target matches all manifests.
+	Target ManifestPatchTarget `json:"target,omitempty"`
+	// MergePatch is a JSON merge patch (RFC 7386), written in YAML or JSON.
+	// Lists in the merge patch replace the existing lists.
+	MergePatch string `json:"mergePatch,omitempty"`
+	// JSONPatch is a JSON patch (RFC 6902), written in YAML or JSON.
+	JSONPatch string `json:"jsonPatch,omitempty"`
+}
+
+// ManifestPatchTarget selects manifests by their API group, kind, name and namespace.
+// Empty fields match any value.
+type ManifestPatchTarget struct {
+	// Group is the API group of the manifest, e.g. apps. Use "core" to match the core API group.
+	Group string `json:"group,omitempty"`
+	// Kind is the kind of the manifest, e.g. Deployment
+	Kind string `json:"kind,omitempty"`
+	// Name is the name of the manifest
+	Name string `json:"name,omitempty"`
+	// Namespace is the namespace of the manifest
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ClusterSizeConfig declares the number of nodes and pods the cluster is expected to
+// grow to. The preflight checks verify that the CPU, memory and etcd disk of the
+// control plane hosts are sufficient for the cluster of that size.
+type ClusterSizeConfig struct {
+	// Nodes is the expected number of nodes in the cluster
+	Nodes int `json:"nodes,omitempty"`
+	// Pods is the expected number of pods in the cluster
+	Pods int `json:"pods,omitempty"`
+	// Enforce fails the preflight checks if the control plane hosts don't meet the
+	// requirements. By default, only warnings are shown.
+	Enforce bool `json:"enforce,omitempty"`
+}
+
+// PreflightConfig configures the preflight checks. KubeOne checks are referred to by
+// their names, e.g. time-sync or version-skew, and kubeadm checks by their names
+// prefixed with "kubeadm:", e.g. kubeadm:NumCPU. kubeadm checks can't be skipped,
+// so both Skip and WarnOnly make kubeadm report their failures as warnings.
+type PreflightConfig struct {
+	// Skip are the preflight checks which are not run.
+	Skip []string `json:"skip,omitempty"`
+	// WarnOnly are the preflight checks whose failures are reported as warnings
+	// instead of failing the command.
+	WarnOnly []string `json:"warnOnly,omitempty"`
+}
+
+// HostRebootConfig configures rebooting hosts which require reboot after the prerequisites
+// are installed, e.g. because the kernel or the core system packages were upgraded. Hosts are
+// rebooted one at a time. Hosts which are already part of the cluster are drained before the
+// reboot, and uncordoned once they're ready again.
+type HostRebootConfig struct {
+	// Enable enables rebooting hosts. If disabled, hosts which require reboot are only reported.
+	// Default value is false.
+	Enable bool `json:"enable,omitempty"`
+	// Timeout is the time to wait for the host to come back after the reboot, e.g. 15m.
+	// Default value is 10m.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// SELinuxMode defines how SELinux is handled on the hosts
+type SELinuxMode string
+
+const (
+	// SELinuxModePermissive switches SELinux to the permissive mode when the hosts are provisioned
+	SELinuxModePermissive SELinuxMode = "permissive"
+	// SELinuxModeEnforcing keeps SELinux enforcing, installs container-selinux, labels the
+	// Kubernetes and CNI directories and enables SELinux support in the container runtime
+	SELinuxModeEnforcing SELinuxMode = "enforcing"
+	// SELinuxModeUnmanaged doesn't change SELinux on the hosts, the selinux preflight check
+	// fails if SELinux is enforcing
+	SELinuxModeUnmanaged SELinuxMode = "unmanaged"
+)
+
+// AppArmorMode defines how AppArmor is handled on the hosts
+type AppArmorMode string
+
+const (
+	// AppArmorModeConfigure installs the apparmor package on Debian and Ubuntu hosts with
+	// AppArmor enabled, which is required by the container runtime to load the AppArmor profiles
+	AppArmorModeConfigure AppArmorMode = "configure"
+	// AppArmorModeUnmanaged doesn't change AppArmor on the hosts, the apparmor preflight check
+	// fails if AppArmor is enabled, but apparmor_parser is not installed
+	AppArmorModeUnmanaged AppArmorMode = "unmanaged"
+)
+
+// FirewallBackend is the firewall used to create the rules on the hosts
+type FirewallBackend string
+
+const (
+	// FirewallBackendFirewalld adds rich rules to the default zone of firewalld
+	FirewallBackendFirewalld FirewallBackend = "firewalld"
+	// FirewallBackendUFW adds ufw rules
+	FirewallBackendUFW FirewallBackend = "ufw"
+	// FirewallBackendNFTables adds the kubeone nftables table, which drops the traffic
+	// to the Kubernetes ports unless it comes from the node CIDRs or the pod subnet
+	FirewallBackendNFTables FirewallBackend = "nftables"
+)
+
+// FirewallConfig configures the firewall rules created on the hosts. The ports of
+// etcd, kubelet, the NodePort range and the CNI overlay are allowed from the
+// node CIDRs and the pod subnet, while the API server port is allowed from
+// everywhere, as it's used by the clients and the load balancers.
+type FirewallConfig struct {
+	// Backend is one of: firewalld, ufw, nftables.
+	// Default: firewalld if it's running on the host, ufw if it's installed on the host,
+	// otherwise no rules are created
+	Backend FirewallBackend `json:"backend,omitempty"`
+	// NodeCIDRs are the CIDRs of the private addresses of all nodes, including the
+	// nodes managed by machine-controller
+	NodeCIDRs []string `json:"nodeCIDRs"`
+}
+
+// NodeNamingStrategy is the source of the names of the Node objects
+type NodeNamingStrategy string
+
+const (
+	// NodeNamingHostname names the Node after the short hostname of the host
+	NodeNamingHostname NodeNamingStrategy = "hostname"
+	// NodeNamingFQDN names the Node after the fully qualified domain name of the host
+	NodeNamingFQDN NodeNamingStrategy = "fqdn"
+	// NodeNamingPrivateIP names the Node after the private address of the host
+	NodeNamingPrivateIP NodeNamingStrategy = "privateIP"
+	// NodeNamingInstanceID names the Node after the ID of the cloud instance, read
+	// from the instance metadata service. Supported only on AWS.
+	NodeNamingInstanceID NodeNamingStrategy = "instanceID"
+)
+
+// NodeNamingConfig configures how the names of the Node objects are derived. The
+// cloud controller manager matches the Nodes to the cloud instances by name on
+// some providers, so all nodes of the cluster must be named in the same way.
+type NodeNamingConfig struct {
+	// Strategy is the source of the Node names, one of: hostname, fqdn, privateIP,
+	// instanceID. The hostname set explicitly for a host always takes precedence.
+	// instanceID is supported only on AWS, because the cloud controller managers
+	// of the other providers match the Nodes to the instances by the instance name.
+	// The strategy isn't applied to the MachineDeployments: machine-controller
+	// names the nodes after the Machines, except on AWS where the private DNS name
+	// is used, so the strategy is only validated to match that when the dynamic
+	// workers are used.
+	// Changing the strategy of a provisioned cluster is not supported, because
+	// the existing Nodes are not renamed.
+	// Default value is hostname on Azure and fqdn on other providers.
+	Strategy NodeNamingStrategy `json:"strategy,omitempty"`
+}
+
+// KeyAlgorithm is the algorithm of the private keys of the certificates
+type KeyAlgorithm string
+
+const (
+	// KeyAlgorithmRSA generates 2048-bit RSA keys
+	KeyAlgorithmRSA KeyAlgorithm = "RSA"
+	// KeyAlgorithmECDSA generates ECDSA P-256 keys
+	KeyAlgorithmECDSA KeyAlgorithm = "ECDSA"
+	// KeyAlgorithmEd25519 generates Ed25519 keys
+	KeyAlgorithmEd25519 KeyAlgorithm = "Ed25519"
+)
+
+// CertificateAuthorityConfig configures the Kubernetes cluster CA
+type CertificateAuthorityConfig struct {
+	// KeyAlgorithm is the algorithm of the keys of the certificates generated by
+	// kubeadm and KubeOne, one of: RSA, ECDSA, Ed25519. With ECDSA, kubeadm
+	// generates ECDSA keys for the CA and the control plane certificates. kubeadm
+	// and the service account tokens don't support Ed25519, so with Ed25519 only
+	// the certificates generated by KubeOne (e.g. addons webhooks and client
+	// certificates) use Ed25519 keys, while kubeadm keeps using RSA.
+	// Default value is RSA. Changing it affects only the newly generated
+	// certificates.
+	KeyAlgorithm KeyAlgorithm `json:"keyAlgorithm,omitempty"`
+	// CertificateValidity is the validity period of the certificates generated by
+	// KubeOne, e.g. the TLS certificates of the addons webhooks, the kubelet client
+	// certificates and, with the external CA signer, the control plane certificates.
+	// The certificates never outlive the CA.
+	// Default value is 8760h (1 year).
+	CertificateValidity *metav1.Duration `json:"certificateValidity,omitempty"`
+	// RenewBefore is the renewal threshold of the control plane certificates:
+	// apply renews them if any of them expires in less than RenewBefore.
+	// It must be shorter than the certificateValidity and than 1 year, the
+	// validity of the certificates renewed by kubeadm.
+	// Default value is 2160h (90 days).
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+	// External makes the cluster use the CA provided by the user instead of the CA
+	// generated by kubeadm
+	External *ExternalCAConfig `json:"external,omitempty"`
+}
+
+// ExternalCAConfig configures the CA provided by the user. The private key of the
+// root CA is never required: the Kubernetes certificates are signed either by the
+// intermediate CA, if its key is provided, by the signer plugin, or by the key
+// provider.
+type ExternalCAConfig struct {
+	// CACertificate is the PEM encoded root CA certificate
+	CACertificate string `json:"caCertificate"`
+	// IntermediateCertificate is the PEM encoded intermediate CA certificate, signed
+	// by the root CA. If set, the intermediate CA is used as the Kubernetes cluster CA.
+	IntermediateCertificate string `json:"intermediateCertificate,omitempty"`
+	// IntermediateKey is the PEM encoded private key of the intermediate CA. If set,
+	// the key is installed on the control plane hosts and the certificates are signed
+	// by kubeadm and kube-controller-manager, in the same way as with the generated CA.
+	IntermediateKey string `json:"intermediateKey,omitempty"`
+	// Signer signs the certificates when the CA key is not provided. In that case,
+	// kubeadm runs in the external CA mode, and KubeOne issues the control plane
+	// certificates and the kubelet client certificates of the joining hosts.
+	Signer *ExternalCASigner `json:"signer,omitempty"`
+	// KeyProvider signs the certificates with the CA key stored in a hardware
+	// security module or a cloud KMS, in the same way as the signer. The CA key
+	// never leaves the key provider. Only one of signer and keyProvider can be set.
+	KeyProvider *ExternalCAKeyProvider `json:"keyProvider,omitempty"`
+}
+
+// ExternalCAKeyProvider is the key provider holding the private key of the CA
+// used as the Kubernetes cluster CA. Only one of the providers can be set.
+type ExternalCAKeyProvider struct {
+	// PKCS11 signs with the key stored in a PKCS#11 token, e.g. a hardware
+	// security module
+	PKCS11 *PKCS11KeyProvider `json:"pkcs11,omitempty"`
+	// AWSKMS signs with the asymmetric AWS KMS key
+	AWSKMS *AWSKMSKeyProvider `json:"awsKMS,omitempty"`
+}
+
+// PKCS11KeyProvider signs with the key stored in a PKCS#11 token using the
+// pkcs11-tool utility (OpenSC), which must be installed on the machine running
+// KubeOne
+type PKCS11KeyProvider struct {
+	// Module is the path to the PKCS#11 module library, e.g. /usr/lib/softhsm/libsofthsm2.so
+	Module string `json:"module"`
+	// TokenLabel is the label of the token holding the key. The first token is
+	// used if not set.
+	TokenLabel string `json:"tokenLabel,omitempty"`
+	// KeyID is the hex encoded ID of the private key object
+	KeyID string `json:"keyID"`
+	// PIN is the user PIN of the token. It can be a reference to the external
+	// secret store, e.g. vault:secret/data/hsm#pin
+	PIN string `json:"pin,omitempty"`
+}
+
+// AWSKMSKeyProvider signs with the asymmetric AWS KMS key. The AWS credentials
+// are taken from the environment, in the same way as by the AWS CLI.
+type AWSKMSKeyProvider struct {
+	// KeyID is the ID, ARN, alias name or alias ARN of the key
+	KeyID string `json:"keyID"`
+	// Region is the AWS region of the key.
+	// Default value is taken from the AWS configuration.
+	Region string `json:"region,omitempty"`
+}
+
+// ExternalCASigner is the command run on the machine running KubeOne to sign the
+// certificates. The PEM encoded certificate signing request is written to the
+// standard input of the command, and the command writes the PEM encoded signed
+// certificate, optionally followed by the intermediate certificates, to the
+// standard output. The requested usages (e.g. "client auth", "server auth") and
+// validity (e.g. "8760h0m0s") are passed in the KUBEONE_CERTIFICATE_USAGES and
+// KUBEONE_CERTIFICATE_VALIDITY environment variables.
+type ExternalCASigner struct {
+	// Command is the signer plugin executable
+	Command string `json:"command"`
+	// Args are the arguments passed to the command
+	Args []string `json:"args,omitempty"`
+}
+
+// SecurityModulesConfig configures how the Linux security modules are handled on the hosts
+type SecurityModulesConfig struct {
+	// SELinux is one of: permissive, enforcing, unmanaged.
+	// Default value is permissive.
+	SELinux SELinuxMode `json:"selinux,omitempty"`
+	// AppArmor is one of: configure, unmanaged.
+	// Default value is configure.
+	AppArmor AppArmorMode `json:"appArmor,omitempty"`
+}
+
+// HardeningProfile is the name of the hardening profile
+type HardeningProfile string
+
+const (
+	// HardeningProfileCIS applies the settings aligned with the CIS Kubernetes Benchmark
+	HardeningProfileCIS HardeningProfile = "cis"
+)
+
+// HardeningConfig configures the hardening profile applied to the cluster
+type HardeningConfig struct {
+	// Profile is the hardening profile. Only "cis" is supported.
+	// The CIS profile restricts the permissions of the Kubernetes configuration files,
+	// disables profiling of the control plane components, restricts the TLS cipher suites,
+	// enables protectKernelDefaults in kubelet and installs auditd rules watching the
+	// Kubernetes and container runtime files. Use `kubeone verify cis` to report the
+	// compliance of the hosts.
+	Profile HardeningProfile `json:"profile"`
+}
+
+// TimeSyncConfig configures the time synchronization on hosts. etcd and TLS
+// certificates require the clocks of all hosts to be synchronized.
+type TimeSyncConfig struct {
+	// Chrony installs, configures and enables chrony on all hosts when they're provisioned.
+	// On Flatcar Linux, systemd-timesyncd is configured instead.
+	Chrony bool `json:"chrony,omitempty"`
+	// NTPServers are the NTP servers used to synchronize time. Requires Chrony.
+	// Default: the NTP servers configured by the operating system
+	NTPServers []string `json:"ntpServers,omitempty"`
+	// MaxClockSkew is the maximum difference between the clock of the host and the clock
+	// of the machine running KubeOne, verified by the time-sync preflight check.
+	// Default value is 1s.
+	MaxClockSkew *metav1.Duration `json:"maxClockSkew,omitempty"`
+}
+
+// OSTuningConfig configures the kernel modules and sysctl settings for all hosts and
+// per host group. The settings are saved to /etc/modules-load.d/kubeone.conf and
+// /etc/sysctl.d/99-kubeone.conf on every apply, so they're persisted across reboots.
+// Sysctl settings removed from the configuration are reverted only after the reboot.
+type OSTuningConfig struct {
+	// All configures the kernel modules and sysctl settings for all hosts
+	All OSTuning `json:"all,omitempty"`
+	// ControlPlane configures the kernel modules and sysctl settings for the control
+	// plane hosts. Sysctl settings take precedence over the settings in All.
+	ControlPlane OSTuning `json:"controlPlane,omitempty"`
+	// StaticWorkers configures the kernel modules and sysctl settings for the static
+	// worker hosts. Sysctl settings take precedence over the settings in All.
+	StaticWorkers OSTuning `json:"staticWorkers,omitempty"`
+}
+
+// OSTuning defines the kernel modules and sysctl settings
+type OSTuning struct {
+	// KernelModules are the kernel modules loaded on hosts, e.g. nf_conntrack
+	KernelModules []string `json:"kernelModules,omitempty"`
+	// Sysctls are the sysctl settings applied on hosts, e.g.
+	// net.ipv4.conf.all.rp_filter: "1"
+	Sysctls map[string]string `json:"sysctls,omitempty"`
+}
+
 // ControlPlaneConfig defines control plane nodes
 type ControlPlaneConfig struct {
 	// Hosts array of all control plane hosts.
 	Hosts []HostConfig `json:"hosts"`
+	// StaticPods are static pods run by kubelet on all control plane hosts.
+	StaticPods []StaticPod `json:"staticPods,omitempty"`
+	// HostDiscovery discovers the control plane hosts using the cloud provider API.
+	// The discovered hosts are added to Hosts each time the manifest is loaded.
+	HostDiscovery *HostDiscoveryConfig `json:"hostDiscovery,omitempty"`
 }
 
 // StaticWorkersConfig defines static worker nodes provisioned by KubeOne and kubeadm
 type StaticWorkersConfig struct {
 	// Hosts
 	Hosts []HostConfig `json:"hosts,omitempty"`
+	// Groups are the named groups of static worker hosts sharing the configuration.
+	// The hosts of the groups are added to Hosts each time the manifest is loaded.
+	Groups []StaticWorkerGroup `json:"groups,omitempty"`
+	// StaticPods are static pods run by kubelet on all static worker hosts.
+	StaticPods []StaticPod `json:"staticPods,omitempty"`
+	// HostDiscovery discovers the static worker hosts using the cloud provider API.
+	// The discovered hosts are added to Hosts each time the manifest is loaded.
+	HostDiscovery *HostDiscoveryConfig `json:"hostDiscovery,omitempty"`
+}
+
+// StaticWorkerGroup is a named group of static worker hosts. The host fields which
+// are not set are defaulted from the group template, before the defaults shared by
+// all hosts are applied.
+type StaticWorkerGroup struct {
+	// Name of the group, set as the kubeone.io/static-worker-group label on the Nodes
+	// of the group
+	Name string `json:"name"`
+	// Template is the configuration shared by the hosts of the group, e.g. the SSH
+	// settings, taints, labels and the kubelet configuration. The labels of the
+	// template are merged with the labels of the host. The addresses, the hostname
+	// and isLeader of the template are ignored.
+	Template HostConfig `json:"template,omitempty"`
+	// Hosts of the group
+	Hosts []HostConfig `json:"hosts"`
+}
+
+// HostDiscoveryConfig discovers the running instances with the given tags using the
+// cloud provider API. Supported on AWS, Hetzner and OpenStack.
+type HostDiscoveryConfig struct {
+	// Tags the instances must have: the instance tags on AWS, the server labels on
+	// Hetzner and the server metadata on OpenStack.
+	Tags map[string]string `json:"tags"`
+	// Template is the configuration of the discovered hosts. The public and the
+	// private address are taken from the instance.
+	Template HostConfig `json:"template,omitempty"`
+}
+
+// StaticPod is a pod managed directly by kubelet, from the manifest placed
+// in the kubelet static pod path by KubeOne
+type StaticPod struct {
+	// Name is a unique name of the static pod manifest. The manifest is saved
+	// as /etc/kubernetes/manifests/kubeone-<name>.yaml.
+	Name string `json:"name"`
+	// Manifest is the Pod manifest in the YAML format.
+	Manifest string `json:"manifest"`
 }
 
 // APIEndpoint is the endpoint used to communicate with the Kubernetes API
@@ -161,7 +719,10 @@ type APIEndpoint struct {
 type CloudProviderSpec struct {
 	// External
 	External bool `json:"external,omitempty"`
-	// CloudConfig
+	// CloudConfig is the cloud-config file used by the cloud provider
+	// integrations. It's rendered as a Go template, with the cloud provider
+	// credentials available as .Credentials (using the same keys as the
+	// cloud-provider-credentials Secret), e.g. {{ .Credentials.OS_PASSWORD }}.
 	CloudConfig string `json:"cloudConfig,omitempty"`
 	// CSIConfig
 	CSIConfig string `json:"csiConfig,omitempty"`
@@ -183,16 +744,109 @@ type CloudProviderSpec struct {
 	Vsphere *VsphereSpec `json:"vsphere,omitempty"`
 	// None
 	None *NoneSpec `json:"none,omitempty"`
+	// CredentialsVault configures reading the cloud provider credentials
+	// from HashiCorp Vault, instead of the environment variables and the
+	// credentials file
+	CredentialsVault *VaultCredentials `json:"credentialsVault,omitempty"`
+}
+
+// VaultCredentials references the HashiCorp Vault secret with the cloud
+// provider credentials. Vault is configured using the VAULT_ADDR, VAULT_TOKEN
+// and VAULT_NAMESPACE environment variables.
+type VaultCredentials struct {
+	// Path of the secret, e.g. secret/data/kubeone/aws for the KV version 2
+	// secrets engine, or aws/creds/kubeone for the AWS secrets engine.
+	// The secret is read once, so the credentials generated by the dynamic
+	// secrets engines are the same for the whole run.
+	Path string `json:"path"`
+	// Keys maps the names of the credentials (e.g. AWS_ACCESS_KEY_ID) to the
+	// keys of the secret (e.g. access_key). Credentials that are not listed are
+	// read from the keys with the same name.
+	Keys map[string]string `json:"keys,omitempty"`
 }
 
 // AWSSpec defines the AWS cloud provider
-type AWSSpec struct{}
+type AWSSpec struct {
+	// EBSCSI configures the Amazon EBS CSI driver deployed on clusters using
+	// the external cloud provider
+	EBSCSI *AWSEBSCSIConfig `json:"ebsCSI,omitempty"`
+}
+
+// AWSEBSCSIConfig configures the Amazon EBS CSI driver
+type AWSEBSCSIConfig struct {
+	// Enable deploys the EBS CSI driver along with the default gp3
+	// StorageClass ebs-csi, and enables the CSI migration of the in-tree EBS
+	// volumes for the new clusters
+	Enable bool `json:"enable,omitempty"`
+	// UseInstanceProfile makes the driver use the IAM instance profile of the
+	// nodes, instead of the credentials KubeOne is using. The instance profile
+	// is also used if no AWS access keys are provided to KubeOne.
+	UseInstanceProfile bool `json:"useInstanceProfile,omitempty"`
+}
 
 // AzureSpec defines the Azure cloud provider
-type AzureSpec struct{}
+type AzureSpec struct {
+	// CloudConfig generates the azure.json cloud-config from the Azure
+	// credentials, unless .cloudProvider.cloudConfig is provided. The
+	// cloud-config is deployed on all nodes and used by the CCM and the CSI
+	// drivers.
+	CloudConfig *AzureCloudConfig `json:"cloudConfig,omitempty"`
+	// CCM configures the cloud-provider-azure cloud-controller-manager and
+	// cloud-node-manager deployed on clusters using the external cloud
+	// provider
+	CCM *AzureComponent `json:"ccm,omitempty"`
+	// DiskCSI configures the AzureDisk CSI driver deployed on clusters using
+	// the external cloud provider
+	DiskCSI *AzureComponent `json:"diskCSI,omitempty"`
+	// FileCSI configures the AzureFile CSI driver deployed on clusters using
+	// the external cloud provider
+	FileCSI *AzureComponent `json:"fileCSI,omitempty"`
+}
+
+// AzureCloudConfig is used to generate the azure.json cloud-config
+type AzureCloudConfig struct {
+	// Cloud is the name of the Azure cloud.
+	// Default value is "AzurePublicCloud".
+	Cloud string `json:"cloud,omitempty"`
+	// ResourceGroup is the resource group of the cluster
+	ResourceGroup string `json:"resourceGroup"`
+	// Location is the Azure region of the cluster, e.g. westeurope
+	Location string `json:"location"`
+	// VNetName is the name of the virtual network of the cluster
+	VNetName string `json:"vnetName"`
+	// VNetResourceGroup is the resource group of the virtual network.
+	// Default value is the ResourceGroup.
+	VNetResourceGroup string `json:"vnetResourceGroup,omitempty"`
+	// SubnetName is the name of the subnet of the nodes
+	SubnetName string `json:"subnetName"`
+	// SecurityGroupName is the name of the network security group of the
+	// nodes
+	SecurityGroupName string `json:"securityGroupName"`
+	// RouteTableName is the name of the route table of the nodes
+	RouteTableName string `json:"routeTableName,omitempty"`
+	// PrimaryAvailabilitySetName is the name of the availability set of the
+	// nodes
+	PrimaryAvailabilitySetName string `json:"primaryAvailabilitySetName,omitempty"`
+	// LoadBalancerSku is the SKU of the load balancers, basic or standard.
+	// Default value is "standard".
+	LoadBalancerSku string `json:"loadBalancerSku,omitempty"`
+}
+
+// AzureComponent configures an Azure component managed by KubeOne
+type AzureComponent struct {
+	// Enable deploys the component and keeps it up to date. Disable it only
+	// if the component is managed outside of KubeOne.
+	// Default value is true.
+	Enable *bool `json:"enable,omitempty"`
+}
 
 // DigitalOceanSpec defines the DigitalOcean cloud provider
-type DigitalOceanSpec struct{}
+type DigitalOceanSpec struct {
+	// VPCUUID is the UUID of the VPC the cluster is running in. It's used by
+	// the DigitalOcean CCM for load balancers and as the default VPC of
+	// the worker droplets.
+	VPCUUID string `json:"vpcUUID,omitempty"`
+}
 
 // GCESpec defines the GCE cloud provider
 type GCESpec struct{}
@@ -204,19 +858,78 @@ type HetznerSpec struct {
 }
 
 // OpenstackSpec defines the Openstack provider
-type OpenstackSpec struct{}
+type OpenstackSpec struct {
+	// CCM configures the OpenStack cloud-controller-manager deployed on
+	// clusters using the external cloud provider
+	CCM *OpenstackComponent `json:"ccm,omitempty"`
+	// CinderCSI configures the Cinder CSI driver deployed on clusters using
+	// the external cloud provider
+	CinderCSI *OpenstackComponent `json:"cinderCSI,omitempty"`
+}
+
+// OpenstackComponent configures an OpenStack component managed by KubeOne
+type OpenstackComponent struct {
+	// Enable deploys the component and keeps it up to date. Disable it only
+	// if the component is managed outside of KubeOne.
+	// Default value is true.
+	Enable *bool `json:"enable,omitempty"`
+	// CloudConfigSections are appended to the .cloudProvider.cloudConfig
+	// used by this component only, e.g. LoadBalancer for the CCM or
+	// BlockStorage for the Cinder CSI. Keys are the section names, values
+	// are the options of the section.
+	CloudConfigSections map[string]map[string]string `json:"cloudConfigSections,omitempty"`
+}
 
 // PacketSpec defines the Packet cloud provider
 type PacketSpec struct{}
 
 // VsphereSpec defines the vSphere provider
-type VsphereSpec struct{}
+type VsphereSpec struct {
+	// CSI configures the vSphere CSI driver deployed on clusters using the
+	// external cloud provider. Unless .cloudProvider.csiConfig is provided,
+	// the CSI config is generated from the vSphere credentials.
+	CSI *VsphereCSIConfig `json:"csi,omitempty"`
+}
+
+// VsphereCSIConfig configures the vSphere CSI driver
+type VsphereCSIConfig struct {
+	// Datacenters is the list of the vCenter datacenters the volumes are
+	// provisioned in
+	Datacenters []string `json:"datacenters"`
+	// InsecureFlag disables the verification of the vCenter certificate
+	InsecureFlag bool `json:"insecureFlag,omitempty"`
+	// StorageClass creates the default StorageClass vsphere-csi. The volumes
+	// are provisioned in any of the datastores shared by the nodes, unless
+	// the datastore URL or the storage policy is set.
+	StorageClass *VsphereCSIStorageClass `json:"storageClass,omitempty"`
+	// Snapshots deploys the CSI snapshot controller, the snapshot CRDs and
+	// the VolumeSnapshotClass vsphere-csi.
+	// Requires Kubernetes 1.21 or newer and vSphere 7.0 Update 3 or newer.
+	Snapshots bool `json:"snapshots,omitempty"`
+}
+
+// VsphereCSIStorageClass configures the StorageClass of the vSphere CSI driver
+type VsphereCSIStorageClass struct {
+	// DatastoreURL is the URL of the datastore the volumes are provisioned
+	// in, e.g. ds:///vmfs/volumes/<datastore UUID>/
+	DatastoreURL string `json:"datastoreURL,omitempty"`
+	// StoragePolicyName is the name of the vSphere storage policy the
+	// volumes are provisioned with
+	StoragePolicyName string `json:"storagePolicyName,omitempty"`
+}
 
 // NoneSpec defines a none provider
 type NoneSpec struct{}
 
 // VersionConfig describes the versions of components that are installed on the machines
 type VersionConfig struct {
+	// Kubernetes is the Kubernetes version, e.g. 1.21.5. The version can be
+	// pinned to the latest patch release of the minor version, e.g. 1.21.x,
+	// which is resolved using the Kubernetes release markers each time the
+	// manifest is loaded. The release markers are fetched from
+	// https://dl.k8s.io/release, unless overridden by the
+	// KUBEONE_KUBERNETES_RELEASE_URL environment variable, which can point
+	// to the URL or the local directory mirroring the release markers.
 	Kubernetes string `json:"kubernetes"`
 }
 
@@ -336,8 +1049,72 @@ type WeaveNetSpec struct {
 }
 
 // ExternalCNISpec defines the external CNI plugin.
-// It's up to the user's responsibility to deploy the external CNI plugin manually or as an addon
-type ExternalCNISpec struct{}
+// It's up to the user's responsibility to deploy the external CNI plugin manually or as an addon,
+// unless the HelmChart is specified.
+type ExternalCNISpec struct {
+	// HelmChart is a reference to the Helm chart used to deploy the CNI plugin.
+	// If specified, KubeOne installs and upgrades the Helm release along with the cluster.
+	HelmChart *HelmChart `json:"helmChart,omitempty"`
+}
+
+// HelmChart is a reference to the Helm chart
+type HelmChart struct {
+	// ReleaseName is the name of the Helm release
+	ReleaseName string `json:"releaseName"`
+	// Chart is the name of the chart in the repository, or a path to the local chart,
+	// starting with "/", "./" or "../"
+	Chart string `json:"chart"`
+	// RepoURL is the URL of the chart repository
+	RepoURL string `json:"repoURL,omitempty"`
+	// Version is the version of the chart
+	// required for the charts from the repositories, ignored for the local charts
+	Version string `json:"version,omitempty"`
+	// Namespace to deploy the release to
+	// default value is "kube-system"
+	Namespace string `json:"namespace,omitempty"`
+	// ValuesFiles is a list of paths to the files with values used to render the chart
+	ValuesFiles []string `json:"valuesFiles,omitempty"`
+	// Values to set on the command line, in the same way as using the `helm --set` flag.
+	// Values can reference the secrets in the external secret stores,
+	// e.g. "vault:secret/data/cni#apiKey" or "aws-secretsmanager:<secret-id>#apiKey".
+	// Values set here override values from the ValuesFiles.
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// RBACConfig defines the RBAC resources created and reconciled by KubeOne.
+// Resources removed from the configuration are removed from the cluster as well,
+// set it to an empty object to remove all RBAC resources created by KubeOne.
+type RBACConfig struct {
+	// ClusterRoles is a list of ClusterRoles to create
+	ClusterRoles []RBACClusterRole `json:"clusterRoles,omitempty"`
+	// ClusterRoleBindings is a list of ClusterRoleBindings to create
+	ClusterRoleBindings []RBACRoleBinding `json:"clusterRoleBindings,omitempty"`
+	// RoleBindings is a list of RoleBindings to create
+	RoleBindings []RBACRoleBinding `json:"roleBindings,omitempty"`
+}
+
+// RBACClusterRole defines the ClusterRole
+type RBACClusterRole struct {
+	// Name of the ClusterRole
+	Name string `json:"name"`
+	// Rules of the ClusterRole
+	Rules []rbacv1.PolicyRule `json:"rules"`
+}
+
+// RBACRoleBinding binds the ClusterRole to the groups and users
+type RBACRoleBinding struct {
+	// Name of the binding
+	Name string `json:"name"`
+	// Namespace of the binding, required for the RoleBindings
+	Namespace string `json:"namespace,omitempty"`
+	// ClusterRole is the name of the ClusterRole to bind
+	ClusterRole string `json:"clusterRole"`
+	// Groups to bind the ClusterRole to. Groups coming from the OIDC provider must
+	// include the prefix configured in .features.openidConnect.config.groupsPrefix
+	Groups []string `json:"groups,omitempty"`
+	// Users to bind the ClusterRole to
+	Users []string `json:"users,omitempty"`
+}
 
 // ProxyConfig configures proxy for the Docker daemon and is used by KubeOne scripts
 type ProxyConfig struct {
@@ -357,6 +1134,24 @@ type DynamicWorkerConfig struct {
 	Replicas *int `json:"replicas"`
 	// Config
 	Config ProviderSpec `json:"providerSpec"`
+	// NvidiaGPU labels the workerset nodes, so the NVIDIA device plugin is scheduled on them.
+	// The machine image must have the NVIDIA driver and the NVIDIA container toolkit preinstalled.
+	// Requires the NvidiaGPU feature to be enabled.
+	NvidiaGPU bool `json:"nvidiaGPU,omitempty"`
+	// Autoscaling annotates the MachineDeployment, so it's scaled by the
+	// cluster-autoscaler between the minimum and the maximum replicas.
+	// Requires the ClusterAutoscaler feature to be enabled.
+	Autoscaling *WorkersetAutoscaling `json:"autoscaling,omitempty"`
+}
+
+// WorkersetAutoscaling is the size range of the workerset scaled by the
+// cluster-autoscaler. Workersets spread across the Azure availability zones
+// have the range applied to each of the zones.
+type WorkersetAutoscaling struct {
+	// MinReplicas is the minimum number of replicas, greater than 0
+	MinReplicas int `json:"minReplicas"`
+	// MaxReplicas is the maximum number of replicas
+	MaxReplicas int `json:"maxReplicas"`
 }
 
 // ProviderSpec describes a worker node
@@ -404,6 +1199,26 @@ type ProviderStaticNetworkConfig struct {
 type MachineControllerConfig struct {
 	// Deploy
 	Deploy bool `json:"deploy,omitempty"`
+	// ImageRepository overrides the repository of the machine-controller
+	// image, e.g. "registry.example.com/kubermatic/machine-controller".
+	// The registry set in .registryConfiguration.overwriteRegistry is not
+	// applied to the overridden repository.
+	ImageRepository string `json:"imageRepository,omitempty"`
+	// ImageTag pins the machine-controller build, e.g. "v1.36.0". Semantic
+	// version tags are validated against the Kubernetes version.
+	ImageTag string `json:"imageTag,omitempty"`
+	// Resources are the compute resources of the machine-controller and the
+	// machine-controller-webhook containers
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// NodeSelector replaces the default node selector, which schedules
+	// machine-controller on the control plane nodes
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations are added to the default tolerations
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// ExtraFlags are passed to machine-controller after the default flags,
+	// so they override them, e.g. {"node-csr-approver": "false"}. Flags with
+	// an empty value are passed without a value.
+	ExtraFlags map[string]string `json:"extraFlags,omitempty"`
 }
 
 // Features controls what features will be enabled on the cluster
@@ -426,6 +1241,21 @@ type Features struct {
 	OpenIDConnect *OpenIDConnect `json:"openidConnect,omitempty"`
 	// Encryption Providers
 	EncryptionProviders *EncryptionProviders `json:"encryptionProviders,omitempty"`
+	// NvidiaGPU
+	NvidiaGPU *NvidiaGPU `json:"nvidiaGPU,omitempty"`
+	// Monitoring
+	Monitoring *Monitoring `json:"monitoring,omitempty"`
+	// ClusterAutoscaler
+	ClusterAutoscaler *ClusterAutoscaler `json:"clusterAutoscaler,omitempty"`
+	// CertManager
+	CertManager *CertManager `json:"certManager,omitempty"`
+	// IngressController
+	IngressController *IngressController `json:"ingressController,omitempty"`
+	// GitOps
+	GitOps *GitOps `json:"gitOps,omitempty"`
+
+	// Velero
+	Velero *Velero `json:"velero,omitempty"`
 }
 
 // PodPresets feature flag
@@ -444,7 +1274,9 @@ type SystemPackages struct {
 
 // AssetConfiguration controls how assets (e.g. CNI, Kubelet, kube-apiserver, and more)
 // are pulled.
-// The AssetConfiguration API is an alpha API currently working only on Amazon Linux 2.
+// The AssetConfiguration API is an alpha API. Image assets and the Kubeadm,
+// Kubelet, Kubectl, CNI and BinariesMirror binary assets work on all supported
+// operating systems, while NodeBinaries works only on Amazon Linux 2.
 type AssetConfiguration struct {
 	// Kubernetes configures the image registry and repository for the core Kubernetes
 	// images (kube-apiserver, kube-controller-manager, kube-scheduler, and kube-proxy).
@@ -493,6 +1325,37 @@ type AssetConfiguration struct {
 	// If not specified, kubelet package will be installed.
 	// Default: none
 	Kubectl BinaryAsset `json:"kubectl,omitempty"`
+	// Kubeadm configures the source for downloading the Kubeadm binary.
+	// If specified, Kubelet, Kubectl and CNI must be specified as well,
+	// unless BinariesMirror is used.
+	// Default: none
+	Kubeadm BinaryAsset `json:"kubeadm,omitempty"`
+	// Kubelet configures the source for downloading the Kubelet binary.
+	// If specified, Kubeadm, Kubectl and CNI must be specified as well,
+	// unless BinariesMirror is used.
+	// Default: none
+	Kubelet BinaryAsset `json:"kubelet,omitempty"`
+	// BinariesMirror is the URL of the HTTP mirror used to download the
+	// Kubeadm, Kubelet, Kubectl and CNI binaries not specified explicitly.
+	// Kubernetes binaries are downloaded using the same layout as dl.k8s.io:
+	// - <mirror>/v<version>/bin/linux/<arch>/{kubeadm,kubelet,kubectl}
+	// CNI plugins and crictl are downloaded from:
+	// - <mirror>/cni-plugins/v<version>/cni-plugins-linux-<arch>-v<version>.tgz
+	// - <mirror>/cri-tools/v<version>/crictl-v<version>-linux-<arch>.tar.gz
+	// Every binary is verified against the SHA256 checksum published next to
+	// it with the .sha256 extension, unless BinaryAsset.SHA256 is specified.
+	// If specified, the binaries are installed to /opt/bin instead of installing
+	// packages, which allows installing clusters without access to dl.k8s.io
+	// and the package repositories.
+	// Default: none
+	BinariesMirror string `json:"binariesMirror,omitempty"`
+	// SignatureVerification configures verification of cosign signatures of
+	// the Kubeadm, Kubelet and Kubectl binaries, in addition to the SHA256
+	// checksum verification. cosign must be installed on the nodes.
+	// Used only when the binaries are installed from the binary assets or
+	// on Flatcar Linux.
+	// Default: none
+	SignatureVerification *SignatureVerification `json:"signatureVerification,omitempty"`
 }
 
 // ImageAsset is used to customize the image repository and the image tag
@@ -507,6 +1370,30 @@ type ImageAsset struct {
 type BinaryAsset struct {
 	// URL from where to download the binary
 	URL string `json:"url,omitempty"`
+	// SHA256 is the expected SHA256 checksum of the downloaded binary.
+	// Every binary downloaded by KubeOne is verified against its checksum.
+	// If empty, the binary is verified against the checksum published next
+	// to it with the .sha256 extension, and the installation fails if the
+	// checksum can't be downloaded.
+	// Default: none
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// SignatureVerification configures verification of binaries signatures
+// using cosign. Signatures are downloaded from <url>.sig, while certificates
+// used for the keyless verification are downloaded from <url>.cert.
+type SignatureVerification struct {
+	// PublicKey is the PEM-encoded cosign public key used to verify the
+	// signatures. If empty, the keyless verification is used.
+	PublicKey string `json:"publicKey,omitempty"`
+	// CertificateIdentity is the identity expected in the keyless signing
+	// certificate.
+	// Default: krel-staging@k8s-releng-prod.iam.gserviceaccount.com
+	CertificateIdentity string `json:"certificateIdentity,omitempty"`
+	// CertificateOIDCIssuer is the OIDC issuer expected in the keyless
+	// signing certificate.
+	// Default: https://accounts.google.com
+	CertificateOIDCIssuer string `json:"certificateOIDCIssuer,omitempty"`
 }
 
 // RegistryConfiguration controls how images used for components deployed by
@@ -592,6 +1479,217 @@ type MetricsServer struct {
 	// Enable deployment of metrics-server.
 	// Default value is true.
 	Enable bool `json:"enable,omitempty"`
+	// Replicas is the number of metrics-server replicas.
+	// Default value is 1.
+	Replicas int32 `json:"replicas,omitempty"`
+	// Resources replace the default compute resources of metrics-server
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// KubeletInsecureTLS disables verifying the kubelet serving certificates.
+	// Disable it only if the kubelet serving certificates are signed by the
+	// cluster CA.
+	// Default value is true.
+	KubeletInsecureTLS *bool `json:"kubeletInsecureTLS,omitempty"`
+	// HostNetwork runs metrics-server in the host network namespace, for
+	// clusters where the API server can't reach the pods over the CNI.
+	// metrics-server listens on the port 4443 of the host.
+	HostNetwork bool `json:"hostNetwork,omitempty"`
+}
+
+// Monitoring feature flag
+type Monitoring struct {
+	// Enable deployment of kube-state-metrics and node-exporter.
+	// node-exporter listens on the port 9100 of every node.
+	// Default value is false.
+	Enable bool `json:"enable,omitempty"`
+	// ServiceMonitors enables deployment of the Prometheus Operator
+	// ServiceMonitors for kube-state-metrics and node-exporter.
+	// Requires the monitoring.coreos.com/v1 CRDs to be installed in the cluster.
+	ServiceMonitors bool `json:"serviceMonitors,omitempty"`
+}
+
+// ClusterAutoscaler feature flag
+type ClusterAutoscaler struct {
+	// Enable deployment of the cluster-autoscaler, configured to discover and
+	// scale the MachineDeployments managed by machine-controller. Only the
+	// workersets with autoscaling configured are scaled.
+	// Default value is false.
+	Enable bool `json:"enable,omitempty"`
+	// Expander is the strategy used to choose the MachineDeployment to be
+	// scaled up, one of: random, most-pods, least-waste, priority.
+	// Default value is random.
+	Expander string `json:"expander,omitempty"`
+	// ScaleDownUnneededTime is how long a node has to be unneeded before
+	// it's removed.
+	// Default value is 10m.
+	ScaleDownUnneededTime *metav1.Duration `json:"scaleDownUnneededTime,omitempty"`
+	// ScaleDownDelayAfterAdd is how long after a scale up the scale down
+	// evaluation resumes.
+	// Default value is 10m.
+	ScaleDownDelayAfterAdd *metav1.Duration `json:"scaleDownDelayAfterAdd,omitempty"`
+	// BalanceSimilarNodeGroups balances the number of nodes between the
+	// MachineDeployments with the same instance type and labels, e.g. the
+	// workersets spread across the availability zones.
+	BalanceSimilarNodeGroups bool `json:"balanceSimilarNodeGroups,omitempty"`
+}
+
+// CertManager feature flag
+type CertManager struct {
+	// Enable deployment of cert-manager, including the cainjector injecting
+	// the CA bundles into the webhooks, APIServices and CRDs annotated for
+	// the CA injection. cert-manager is deployed and ready before the user
+	// addons are applied, so the addons can use the cert-manager resources.
+	// Default value is false.
+	Enable bool `json:"enable,omitempty"`
+	// ClusterIssuer bootstraps a CA ClusterIssuer issuing the certificates
+	// signed by the cluster CA or by the provided CA.
+	ClusterIssuer *CertManagerClusterIssuer `json:"clusterIssuer,omitempty"`
+}
+
+// CertManagerClusterIssuer describes the CA ClusterIssuer bootstrapped by KubeOne
+type CertManagerClusterIssuer struct {
+	// Name of the ClusterIssuer.
+	// Default value is kubeone-ca.
+	Name string `json:"name,omitempty"`
+	// CASecretName is the name of the existing kubernetes.io/tls Secret in the
+	// cert-manager namespace holding the CA certificate and key used by the
+	// ClusterIssuer. If empty, the cluster CA is used, which requires the
+	// cluster CA key to be available to KubeOne, i.e. the external CA signer
+	// can't be used.
+	CASecretName string `json:"caSecretName,omitempty"`
+}
+
+// IngressController feature flag
+type IngressController struct {
+	// Enable deployment of the ingress-nginx controller.
+	// Default value is false.
+	Enable bool `json:"enable,omitempty"`
+	// Replicas is the number of ingress-nginx controller replicas. Ignored if
+	// HostNetwork is enabled.
+	// Default value is 2.
+	Replicas int32 `json:"replicas,omitempty"`
+	// ServiceType is the type of the ingress-nginx controller Service, one of:
+	// LoadBalancer, NodePort.
+	// Default value is LoadBalancer if the cloud provider provisions load
+	// balancers (AWS, Azure, DigitalOcean, GCE, Hetzner, OpenStack),
+	// otherwise NodePort.
+	ServiceType corev1.ServiceType `json:"serviceType,omitempty"`
+	// HostNetwork runs the ingress-nginx controller as a DaemonSet in the
+	// host network namespace of the worker nodes, listening on the ports 80
+	// and 443 of the nodes, instead of exposing it with a Service.
+	HostNetwork bool `json:"hostNetwork,omitempty"`
+	// IngressClass is the name of the IngressClass handled by the
+	// ingress-nginx controller.
+	// Default value is nginx.
+	IngressClass string `json:"ingressClass,omitempty"`
+	// DefaultIngressClass marks the IngressClass as the default IngressClass
+	// of the cluster, used by the Ingresses without the ingressClassName.
+	DefaultIngressClass bool `json:"defaultIngressClass,omitempty"`
+}
+
+// GitOps feature flag
+type GitOps struct {
+	// Enable bootstrapping of GitOps with Flux. Flux (source-controller and
+	// kustomize-controller) is deployed as the last step of the apply and
+	// install commands, and it's configured to reconcile the manifests from
+	// the git repository.
+	// Default value is false.
+	Enable bool `json:"enable,omitempty"`
+	// Repository is the git repository reconciled by Flux
+	Repository GitOpsRepository `json:"repository"`
+}
+
+// GitOpsRepository describes the git repository reconciled by Flux
+type GitOpsRepository struct {
+	// URL of the git repository, using the http, https or ssh scheme,
+	// e.g. https://github.com/example/fleet or ssh://git@github.com/example/fleet
+	URL string `json:"url"`
+	// Branch of the git repository.
+	// Default value is main.
+	Branch string `json:"branch,omitempty"`
+	// Path to the directory in the git repository with the manifests to be
+	// reconciled.
+	// Default value is the root of the repository.
+	Path string `json:"path,omitempty"`
+	// Interval at which the git repository is fetched and reconciled.
+	// Default value is 1m.
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// SecretName is the name of the Secret in the flux-system namespace with
+	// the git credentials, the username and password keys for https, or the
+	// identity and known_hosts keys for ssh. The Secret can be deployed using
+	// the addons, which are applied before Flux is deployed.
+	// Required for the ssh repositories.
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// Velero feature flag
+type Velero struct {
+	// Enable deploying Velero for backing up the cluster resources and
+	// volumes to the object storage. Velero is deployed after the worker
+	// nodes are provisioned.
+	// Default value is false.
+	Enable bool `json:"enable,omitempty"`
+	// Provider of the object storage, one of: aws, gcp, azure. The object
+	// storage credentials are taken from the credentials file or the
+	// environment variables (AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
+	// for aws, GOOGLE_CREDENTIALS for gcp, and ARM_CLIENT_ID,
+	// ARM_CLIENT_SECRET, ARM_TENANT_ID and ARM_SUBSCRIPTION_ID for azure).
+	// Default value is based on the cloud provider (aws for AWS, gcp for
+	// GCE, and azure for Azure), and it's required for other providers.
+	Provider VeleroProvider `json:"provider,omitempty"`
+	// Bucket is the name of the bucket (or the Azure blob container) where
+	// the backups are stored
+	Bucket string `json:"bucket"`
+	// Prefix is the directory in the bucket where the backups are stored.
+	// Default value is the root of the bucket.
+	Prefix string `json:"prefix,omitempty"`
+	// Config is the provider-specific configuration of the backup storage
+	// location, e.g. region and s3Url for aws, or resourceGroup and
+	// storageAccount for azure.
+	Config map[string]string `json:"config,omitempty"`
+	// Restic deploys the restic DaemonSet used to back up the contents of
+	// the pod volumes (file-system backups). If enabled, the volumes are
+	// backed up with restic in the default scheduled backups.
+	// Default value is false.
+	Restic bool `json:"restic,omitempty"`
+	// Schedule configures the default scheduled backup of the whole cluster
+	Schedule VeleroSchedule `json:"schedule,omitempty"`
+}
+
+// VeleroProvider is the provider of the object storage used by Velero
+type VeleroProvider string
+
+const (
+	// VeleroProviderAWS stores the backups in AWS S3 or a S3-compatible storage
+	VeleroProviderAWS VeleroProvider = "aws"
+	// VeleroProviderGCP stores the backups in Google Cloud Storage
+	VeleroProviderGCP VeleroProvider = "gcp"
+	// VeleroProviderAzure stores the backups in Azure Blob Storage
+	VeleroProviderAzure VeleroProvider = "azure"
+)
+
+// VeleroSchedule configures the default scheduled backup
+type VeleroSchedule struct {
+	// Disable the default scheduled backup.
+	// Default value is false.
+	Disable bool `json:"disable,omitempty"`
+	// Cron expression for the scheduled backup.
+	// Default value is "0 3 * * *" (every day at 03:00).
+	Cron string `json:"cron,omitempty"`
+	// TTL is how long the scheduled backups are kept.
+	// Default value is 720h (30 days).
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
+
+// NvidiaGPU feature flag
+type NvidiaGPU struct {
+	// Enable deployment of the NVIDIA device plugin and installation of the NVIDIA
+	// driver and container toolkit on hosts with NvidiaGPU enabled.
+	// Requires containerd with the NVIDIA runtime enabled
+	// (.containerRuntime.containerd.nvidiaRuntime.enable).
+	Enable bool `json:"enable,omitempty"`
+	// DriverVersion is the NVIDIA driver branch installed on hosts, e.g. 470.
+	// Default: the latest driver available in the operating system repositories
+	DriverVersion string `json:"driverVersion,omitempty"`
 }
 
 // OpenIDConnect feature flag
@@ -26,7 +26,9 @@ import (
 	unsafe "unsafe"
 
 	kubeone "k8c.io/kubeone/pkg/apis/kubeone"
-	v1 "k8s.io/api/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -48,6 +50,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*AWSEBSCSIConfig)(nil), (*kubeone.AWSEBSCSIConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_AWSEBSCSIConfig_To_kubeone_AWSEBSCSIConfig(a.(*AWSEBSCSIConfig), b.(*kubeone.AWSEBSCSIConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.AWSEBSCSIConfig)(nil), (*AWSEBSCSIConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_AWSEBSCSIConfig_To_v1beta1_AWSEBSCSIConfig(a.(*kubeone.AWSEBSCSIConfig), b.(*AWSEBSCSIConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*AWSKMSKeyProvider)(nil), (*kubeone.AWSKMSKeyProvider)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_AWSKMSKeyProvider_To_kubeone_AWSKMSKeyProvider(a.(*AWSKMSKeyProvider), b.(*kubeone.AWSKMSKeyProvider), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.AWSKMSKeyProvider)(nil), (*AWSKMSKeyProvider)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_AWSKMSKeyProvider_To_v1beta1_AWSKMSKeyProvider(a.(*kubeone.AWSKMSKeyProvider), b.(*AWSKMSKeyProvider), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*AWSSpec)(nil), (*kubeone.AWSSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_AWSSpec_To_kubeone_AWSSpec(a.(*AWSSpec), b.(*kubeone.AWSSpec), scope)
 	}); err != nil {
@@ -88,6 +110,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*AzureCloudConfig)(nil), (*kubeone.AzureCloudConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_AzureCloudConfig_To_kubeone_AzureCloudConfig(a.(*AzureCloudConfig), b.(*kubeone.AzureCloudConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.AzureCloudConfig)(nil), (*AzureCloudConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_AzureCloudConfig_To_v1beta1_AzureCloudConfig(a.(*kubeone.AzureCloudConfig), b.(*AzureCloudConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*AzureComponent)(nil), (*kubeone.AzureComponent)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_AzureComponent_To_kubeone_AzureComponent(a.(*AzureComponent), b.(*kubeone.AzureComponent), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.AzureComponent)(nil), (*AzureComponent)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_AzureComponent_To_v1beta1_AzureComponent(a.(*kubeone.AzureComponent), b.(*AzureComponent), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*AzureSpec)(nil), (*kubeone.AzureSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_AzureSpec_To_kubeone_AzureSpec(a.(*AzureSpec), b.(*kubeone.AzureSpec), scope)
 	}); err != nil {
@@ -98,6 +140,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*BastionHost)(nil), (*kubeone.BastionHost)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_BastionHost_To_kubeone_BastionHost(a.(*BastionHost), b.(*kubeone.BastionHost), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.BastionHost)(nil), (*BastionHost)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_BastionHost_To_v1beta1_BastionHost(a.(*kubeone.BastionHost), b.(*BastionHost), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*BinaryAsset)(nil), (*kubeone.BinaryAsset)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_BinaryAsset_To_kubeone_BinaryAsset(a.(*BinaryAsset), b.(*kubeone.BinaryAsset), scope)
 	}); err != nil {
@@ -128,6 +180,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*CertManager)(nil), (*kubeone.CertManager)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_CertManager_To_kubeone_CertManager(a.(*CertManager), b.(*kubeone.CertManager), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.CertManager)(nil), (*CertManager)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_CertManager_To_v1beta1_CertManager(a.(*kubeone.CertManager), b.(*CertManager), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*CertManagerClusterIssuer)(nil), (*kubeone.CertManagerClusterIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_CertManagerClusterIssuer_To_kubeone_CertManagerClusterIssuer(a.(*CertManagerClusterIssuer), b.(*kubeone.CertManagerClusterIssuer), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.CertManagerClusterIssuer)(nil), (*CertManagerClusterIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_CertManagerClusterIssuer_To_v1beta1_CertManagerClusterIssuer(a.(*kubeone.CertManagerClusterIssuer), b.(*CertManagerClusterIssuer), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*CertificateAuthorityConfig)(nil), (*kubeone.CertificateAuthorityConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_CertificateAuthorityConfig_To_kubeone_CertificateAuthorityConfig(a.(*CertificateAuthorityConfig), b.(*kubeone.CertificateAuthorityConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.CertificateAuthorityConfig)(nil), (*CertificateAuthorityConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_CertificateAuthorityConfig_To_v1beta1_CertificateAuthorityConfig(a.(*kubeone.CertificateAuthorityConfig), b.(*CertificateAuthorityConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*CiliumSpec)(nil), (*kubeone.CiliumSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_CiliumSpec_To_kubeone_CiliumSpec(a.(*CiliumSpec), b.(*kubeone.CiliumSpec), scope)
 	}); err != nil {
@@ -148,6 +230,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ClusterAutoscaler)(nil), (*kubeone.ClusterAutoscaler)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ClusterAutoscaler_To_kubeone_ClusterAutoscaler(a.(*ClusterAutoscaler), b.(*kubeone.ClusterAutoscaler), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.ClusterAutoscaler)(nil), (*ClusterAutoscaler)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_ClusterAutoscaler_To_v1beta1_ClusterAutoscaler(a.(*kubeone.ClusterAutoscaler), b.(*ClusterAutoscaler), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ClusterNetworkConfig)(nil), (*kubeone.ClusterNetworkConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_ClusterNetworkConfig_To_kubeone_ClusterNetworkConfig(a.(*ClusterNetworkConfig), b.(*kubeone.ClusterNetworkConfig), scope)
 	}); err != nil {
@@ -158,6 +250,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ClusterSizeConfig)(nil), (*kubeone.ClusterSizeConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ClusterSizeConfig_To_kubeone_ClusterSizeConfig(a.(*ClusterSizeConfig), b.(*kubeone.ClusterSizeConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.ClusterSizeConfig)(nil), (*ClusterSizeConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_ClusterSizeConfig_To_v1beta1_ClusterSizeConfig(a.(*kubeone.ClusterSizeConfig), b.(*ClusterSizeConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ContainerRuntimeConfig)(nil), (*kubeone.ContainerRuntimeConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_ContainerRuntimeConfig_To_kubeone_ContainerRuntimeConfig(a.(*ContainerRuntimeConfig), b.(*kubeone.ContainerRuntimeConfig), scope)
 	}); err != nil {
@@ -188,6 +290,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ContainerdNvidiaRuntime)(nil), (*kubeone.ContainerdNvidiaRuntime)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ContainerdNvidiaRuntime_To_kubeone_ContainerdNvidiaRuntime(a.(*ContainerdNvidiaRuntime), b.(*kubeone.ContainerdNvidiaRuntime), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.ContainerdNvidiaRuntime)(nil), (*ContainerdNvidiaRuntime)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_ContainerdNvidiaRuntime_To_v1beta1_ContainerdNvidiaRuntime(a.(*kubeone.ContainerdNvidiaRuntime), b.(*ContainerdNvidiaRuntime), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ControlPlaneConfig)(nil), (*kubeone.ControlPlaneConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_ControlPlaneConfig_To_kubeone_ControlPlaneConfig(a.(*ControlPlaneConfig), b.(*kubeone.ControlPlaneConfig), scope)
 	}); err != nil {
@@ -248,6 +360,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ExternalCAConfig)(nil), (*kubeone.ExternalCAConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ExternalCAConfig_To_kubeone_ExternalCAConfig(a.(*ExternalCAConfig), b.(*kubeone.ExternalCAConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.ExternalCAConfig)(nil), (*ExternalCAConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_ExternalCAConfig_To_v1beta1_ExternalCAConfig(a.(*kubeone.ExternalCAConfig), b.(*ExternalCAConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ExternalCAKeyProvider)(nil), (*kubeone.ExternalCAKeyProvider)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ExternalCAKeyProvider_To_kubeone_ExternalCAKeyProvider(a.(*ExternalCAKeyProvider), b.(*kubeone.ExternalCAKeyProvider), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.ExternalCAKeyProvider)(nil), (*ExternalCAKeyProvider)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_ExternalCAKeyProvider_To_v1beta1_ExternalCAKeyProvider(a.(*kubeone.ExternalCAKeyProvider), b.(*ExternalCAKeyProvider), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ExternalCASigner)(nil), (*kubeone.ExternalCASigner)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ExternalCASigner_To_kubeone_ExternalCASigner(a.(*ExternalCASigner), b.(*kubeone.ExternalCASigner), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.ExternalCASigner)(nil), (*ExternalCASigner)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_ExternalCASigner_To_v1beta1_ExternalCASigner(a.(*kubeone.ExternalCASigner), b.(*ExternalCASigner), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ExternalCNISpec)(nil), (*kubeone.ExternalCNISpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_ExternalCNISpec_To_kubeone_ExternalCNISpec(a.(*ExternalCNISpec), b.(*kubeone.ExternalCNISpec), scope)
 	}); err != nil {
@@ -268,6 +410,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*FirewallConfig)(nil), (*kubeone.FirewallConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_FirewallConfig_To_kubeone_FirewallConfig(a.(*FirewallConfig), b.(*kubeone.FirewallConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.FirewallConfig)(nil), (*FirewallConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_FirewallConfig_To_v1beta1_FirewallConfig(a.(*kubeone.FirewallConfig), b.(*FirewallConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*GCESpec)(nil), (*kubeone.GCESpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_GCESpec_To_kubeone_GCESpec(a.(*GCESpec), b.(*kubeone.GCESpec), scope)
 	}); err != nil {
@@ -278,6 +430,46 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*GitOps)(nil), (*kubeone.GitOps)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_GitOps_To_kubeone_GitOps(a.(*GitOps), b.(*kubeone.GitOps), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.GitOps)(nil), (*GitOps)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_GitOps_To_v1beta1_GitOps(a.(*kubeone.GitOps), b.(*GitOps), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*GitOpsRepository)(nil), (*kubeone.GitOpsRepository)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_GitOpsRepository_To_kubeone_GitOpsRepository(a.(*GitOpsRepository), b.(*kubeone.GitOpsRepository), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.GitOpsRepository)(nil), (*GitOpsRepository)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_GitOpsRepository_To_v1beta1_GitOpsRepository(a.(*kubeone.GitOpsRepository), b.(*GitOpsRepository), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*HardeningConfig)(nil), (*kubeone.HardeningConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_HardeningConfig_To_kubeone_HardeningConfig(a.(*HardeningConfig), b.(*kubeone.HardeningConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.HardeningConfig)(nil), (*HardeningConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_HardeningConfig_To_v1beta1_HardeningConfig(a.(*kubeone.HardeningConfig), b.(*HardeningConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*HelmChart)(nil), (*kubeone.HelmChart)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_HelmChart_To_kubeone_HelmChart(a.(*HelmChart), b.(*kubeone.HelmChart), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.HelmChart)(nil), (*HelmChart)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_HelmChart_To_v1beta1_HelmChart(a.(*kubeone.HelmChart), b.(*HelmChart), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*HetznerSpec)(nil), (*kubeone.HetznerSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_HetznerSpec_To_kubeone_HetznerSpec(a.(*HetznerSpec), b.(*kubeone.HetznerSpec), scope)
 	}); err != nil {
@@ -298,6 +490,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*HostDiscoveryConfig)(nil), (*kubeone.HostDiscoveryConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_HostDiscoveryConfig_To_kubeone_HostDiscoveryConfig(a.(*HostDiscoveryConfig), b.(*kubeone.HostDiscoveryConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.HostDiscoveryConfig)(nil), (*HostDiscoveryConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_HostDiscoveryConfig_To_v1beta1_HostDiscoveryConfig(a.(*kubeone.HostDiscoveryConfig), b.(*HostDiscoveryConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*HostRebootConfig)(nil), (*kubeone.HostRebootConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_HostRebootConfig_To_kubeone_HostRebootConfig(a.(*HostRebootConfig), b.(*kubeone.HostRebootConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.HostRebootConfig)(nil), (*HostRebootConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_HostRebootConfig_To_v1beta1_HostRebootConfig(a.(*kubeone.HostRebootConfig), b.(*HostRebootConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*IPTables)(nil), (*kubeone.IPTables)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_IPTables_To_kubeone_IPTables(a.(*IPTables), b.(*kubeone.IPTables), scope)
 	}); err != nil {
@@ -318,6 +530,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*IgnorePreflightErrorsConfig)(nil), (*kubeone.IgnorePreflightErrorsConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_IgnorePreflightErrorsConfig_To_kubeone_IgnorePreflightErrorsConfig(a.(*IgnorePreflightErrorsConfig), b.(*kubeone.IgnorePreflightErrorsConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.IgnorePreflightErrorsConfig)(nil), (*IgnorePreflightErrorsConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_IgnorePreflightErrorsConfig_To_v1beta1_IgnorePreflightErrorsConfig(a.(*kubeone.IgnorePreflightErrorsConfig), b.(*IgnorePreflightErrorsConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ImageAsset)(nil), (*kubeone.ImageAsset)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_ImageAsset_To_kubeone_ImageAsset(a.(*ImageAsset), b.(*kubeone.ImageAsset), scope)
 	}); err != nil {
@@ -328,6 +550,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*IngressController)(nil), (*kubeone.IngressController)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_IngressController_To_kubeone_IngressController(a.(*IngressController), b.(*kubeone.IngressController), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.IngressController)(nil), (*IngressController)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_IngressController_To_v1beta1_IngressController(a.(*kubeone.IngressController), b.(*IngressController), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*KubeOneCluster)(nil), (*kubeone.KubeOneCluster)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_KubeOneCluster_To_kubeone_KubeOneCluster(a.(*KubeOneCluster), b.(*kubeone.KubeOneCluster), scope)
 	}); err != nil {
@@ -348,6 +580,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*KubeletConfig)(nil), (*kubeone.KubeletConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_KubeletConfig_To_kubeone_KubeletConfig(a.(*KubeletConfig), b.(*kubeone.KubeletConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.KubeletConfig)(nil), (*KubeletConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_KubeletConfig_To_v1beta1_KubeletConfig(a.(*kubeone.KubeletConfig), b.(*KubeletConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*MachineControllerConfig)(nil), (*kubeone.MachineControllerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_MachineControllerConfig_To_kubeone_MachineControllerConfig(a.(*MachineControllerConfig), b.(*kubeone.MachineControllerConfig), scope)
 	}); err != nil {
@@ -358,6 +600,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ManifestPatch)(nil), (*kubeone.ManifestPatch)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ManifestPatch_To_kubeone_ManifestPatch(a.(*ManifestPatch), b.(*kubeone.ManifestPatch), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.ManifestPatch)(nil), (*ManifestPatch)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_ManifestPatch_To_v1beta1_ManifestPatch(a.(*kubeone.ManifestPatch), b.(*ManifestPatch), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ManifestPatchTarget)(nil), (*kubeone.ManifestPatchTarget)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ManifestPatchTarget_To_kubeone_ManifestPatchTarget(a.(*ManifestPatchTarget), b.(*kubeone.ManifestPatchTarget), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.ManifestPatchTarget)(nil), (*ManifestPatchTarget)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_ManifestPatchTarget_To_v1beta1_ManifestPatchTarget(a.(*kubeone.ManifestPatchTarget), b.(*ManifestPatchTarget), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*MetricsServer)(nil), (*kubeone.MetricsServer)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_MetricsServer_To_kubeone_MetricsServer(a.(*MetricsServer), b.(*kubeone.MetricsServer), scope)
 	}); err != nil {
@@ -368,6 +630,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*Monitoring)(nil), (*kubeone.Monitoring)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_Monitoring_To_kubeone_Monitoring(a.(*Monitoring), b.(*kubeone.Monitoring), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.Monitoring)(nil), (*Monitoring)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_Monitoring_To_v1beta1_Monitoring(a.(*kubeone.Monitoring), b.(*Monitoring), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NodeDrainConfig)(nil), (*kubeone.NodeDrainConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NodeDrainConfig_To_kubeone_NodeDrainConfig(a.(*NodeDrainConfig), b.(*kubeone.NodeDrainConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.NodeDrainConfig)(nil), (*NodeDrainConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_NodeDrainConfig_To_v1beta1_NodeDrainConfig(a.(*kubeone.NodeDrainConfig), b.(*NodeDrainConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NodeNamingConfig)(nil), (*kubeone.NodeNamingConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NodeNamingConfig_To_kubeone_NodeNamingConfig(a.(*NodeNamingConfig), b.(*kubeone.NodeNamingConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.NodeNamingConfig)(nil), (*NodeNamingConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_NodeNamingConfig_To_v1beta1_NodeNamingConfig(a.(*kubeone.NodeNamingConfig), b.(*NodeNamingConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NoneSpec)(nil), (*kubeone.NoneSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_NoneSpec_To_kubeone_NoneSpec(a.(*NoneSpec), b.(*kubeone.NoneSpec), scope)
 	}); err != nil {
@@ -378,6 +670,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NvidiaGPU)(nil), (*kubeone.NvidiaGPU)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NvidiaGPU_To_kubeone_NvidiaGPU(a.(*NvidiaGPU), b.(*kubeone.NvidiaGPU), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.NvidiaGPU)(nil), (*NvidiaGPU)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_NvidiaGPU_To_v1beta1_NvidiaGPU(a.(*kubeone.NvidiaGPU), b.(*NvidiaGPU), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*OSTuning)(nil), (*kubeone.OSTuning)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_OSTuning_To_kubeone_OSTuning(a.(*OSTuning), b.(*kubeone.OSTuning), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.OSTuning)(nil), (*OSTuning)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_OSTuning_To_v1beta1_OSTuning(a.(*kubeone.OSTuning), b.(*OSTuning), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*OSTuningConfig)(nil), (*kubeone.OSTuningConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_OSTuningConfig_To_kubeone_OSTuningConfig(a.(*OSTuningConfig), b.(*kubeone.OSTuningConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.OSTuningConfig)(nil), (*OSTuningConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_OSTuningConfig_To_v1beta1_OSTuningConfig(a.(*kubeone.OSTuningConfig), b.(*OSTuningConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*OpenIDConnect)(nil), (*kubeone.OpenIDConnect)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_OpenIDConnect_To_kubeone_OpenIDConnect(a.(*OpenIDConnect), b.(*kubeone.OpenIDConnect), scope)
 	}); err != nil {
@@ -398,6 +720,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*OpenstackComponent)(nil), (*kubeone.OpenstackComponent)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_OpenstackComponent_To_kubeone_OpenstackComponent(a.(*OpenstackComponent), b.(*kubeone.OpenstackComponent), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.OpenstackComponent)(nil), (*OpenstackComponent)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_OpenstackComponent_To_v1beta1_OpenstackComponent(a.(*kubeone.OpenstackComponent), b.(*OpenstackComponent), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*OpenstackSpec)(nil), (*kubeone.OpenstackSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_OpenstackSpec_To_kubeone_OpenstackSpec(a.(*OpenstackSpec), b.(*kubeone.OpenstackSpec), scope)
 	}); err != nil {
@@ -408,6 +740,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*PKCS11KeyProvider)(nil), (*kubeone.PKCS11KeyProvider)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_PKCS11KeyProvider_To_kubeone_PKCS11KeyProvider(a.(*PKCS11KeyProvider), b.(*kubeone.PKCS11KeyProvider), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.PKCS11KeyProvider)(nil), (*PKCS11KeyProvider)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_PKCS11KeyProvider_To_v1beta1_PKCS11KeyProvider(a.(*kubeone.PKCS11KeyProvider), b.(*PKCS11KeyProvider), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*PacketSpec)(nil), (*kubeone.PacketSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_PacketSpec_To_kubeone_PacketSpec(a.(*PacketSpec), b.(*kubeone.PacketSpec), scope)
 	}); err != nil {
@@ -458,6 +800,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*PreflightConfig)(nil), (*kubeone.PreflightConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_PreflightConfig_To_kubeone_PreflightConfig(a.(*PreflightConfig), b.(*kubeone.PreflightConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.PreflightConfig)(nil), (*PreflightConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_PreflightConfig_To_v1beta1_PreflightConfig(a.(*kubeone.PreflightConfig), b.(*PreflightConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ProviderSpec)(nil), (*kubeone.ProviderSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_ProviderSpec_To_kubeone_ProviderSpec(a.(*ProviderSpec), b.(*kubeone.ProviderSpec), scope)
 	}); err != nil {
@@ -488,6 +840,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*RBACClusterRole)(nil), (*kubeone.RBACClusterRole)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_RBACClusterRole_To_kubeone_RBACClusterRole(a.(*RBACClusterRole), b.(*kubeone.RBACClusterRole), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.RBACClusterRole)(nil), (*RBACClusterRole)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_RBACClusterRole_To_v1beta1_RBACClusterRole(a.(*kubeone.RBACClusterRole), b.(*RBACClusterRole), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RBACConfig)(nil), (*kubeone.RBACConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_RBACConfig_To_kubeone_RBACConfig(a.(*RBACConfig), b.(*kubeone.RBACConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.RBACConfig)(nil), (*RBACConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_RBACConfig_To_v1beta1_RBACConfig(a.(*kubeone.RBACConfig), b.(*RBACConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RBACRoleBinding)(nil), (*kubeone.RBACRoleBinding)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_RBACRoleBinding_To_kubeone_RBACRoleBinding(a.(*RBACRoleBinding), b.(*kubeone.RBACRoleBinding), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.RBACRoleBinding)(nil), (*RBACRoleBinding)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_RBACRoleBinding_To_v1beta1_RBACRoleBinding(a.(*kubeone.RBACRoleBinding), b.(*RBACRoleBinding), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*RegistryConfiguration)(nil), (*kubeone.RegistryConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_RegistryConfiguration_To_kubeone_RegistryConfiguration(a.(*RegistryConfiguration), b.(*kubeone.RegistryConfiguration), scope)
 	}); err != nil {
@@ -498,6 +880,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*SecurityModulesConfig)(nil), (*kubeone.SecurityModulesConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_SecurityModulesConfig_To_kubeone_SecurityModulesConfig(a.(*SecurityModulesConfig), b.(*kubeone.SecurityModulesConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.SecurityModulesConfig)(nil), (*SecurityModulesConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_SecurityModulesConfig_To_v1beta1_SecurityModulesConfig(a.(*kubeone.SecurityModulesConfig), b.(*SecurityModulesConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SignatureVerification)(nil), (*kubeone.SignatureVerification)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_SignatureVerification_To_kubeone_SignatureVerification(a.(*SignatureVerification), b.(*kubeone.SignatureVerification), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.SignatureVerification)(nil), (*SignatureVerification)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_SignatureVerification_To_v1beta1_SignatureVerification(a.(*kubeone.SignatureVerification), b.(*SignatureVerification), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*StaticAuditLog)(nil), (*kubeone.StaticAuditLog)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_StaticAuditLog_To_kubeone_StaticAuditLog(a.(*StaticAuditLog), b.(*kubeone.StaticAuditLog), scope)
 	}); err != nil {
@@ -518,6 +920,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*StaticPod)(nil), (*kubeone.StaticPod)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_StaticPod_To_kubeone_StaticPod(a.(*StaticPod), b.(*kubeone.StaticPod), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.StaticPod)(nil), (*StaticPod)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_StaticPod_To_v1beta1_StaticPod(a.(*kubeone.StaticPod), b.(*StaticPod), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*StaticWorkerGroup)(nil), (*kubeone.StaticWorkerGroup)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_StaticWorkerGroup_To_kubeone_StaticWorkerGroup(a.(*StaticWorkerGroup), b.(*kubeone.StaticWorkerGroup), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.StaticWorkerGroup)(nil), (*StaticWorkerGroup)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_StaticWorkerGroup_To_v1beta1_StaticWorkerGroup(a.(*kubeone.StaticWorkerGroup), b.(*StaticWorkerGroup), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*StaticWorkersConfig)(nil), (*kubeone.StaticWorkersConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_StaticWorkersConfig_To_kubeone_StaticWorkersConfig(a.(*StaticWorkersConfig), b.(*kubeone.StaticWorkersConfig), scope)
 	}); err != nil {
@@ -538,13 +960,73 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*VersionConfig)(nil), (*kubeone.VersionConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_VersionConfig_To_kubeone_VersionConfig(a.(*VersionConfig), b.(*kubeone.VersionConfig), scope)
+	if err := s.AddGeneratedConversionFunc((*TimeSyncConfig)(nil), (*kubeone.TimeSyncConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_TimeSyncConfig_To_kubeone_TimeSyncConfig(a.(*TimeSyncConfig), b.(*kubeone.TimeSyncConfig), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*kubeone.VersionConfig)(nil), (*VersionConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_kubeone_VersionConfig_To_v1beta1_VersionConfig(a.(*kubeone.VersionConfig), b.(*VersionConfig), scope)
+	if err := s.AddGeneratedConversionFunc((*kubeone.TimeSyncConfig)(nil), (*TimeSyncConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_TimeSyncConfig_To_v1beta1_TimeSyncConfig(a.(*kubeone.TimeSyncConfig), b.(*TimeSyncConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*VaultCredentials)(nil), (*kubeone.VaultCredentials)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_VaultCredentials_To_kubeone_VaultCredentials(a.(*VaultCredentials), b.(*kubeone.VaultCredentials), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.VaultCredentials)(nil), (*VaultCredentials)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_VaultCredentials_To_v1beta1_VaultCredentials(a.(*kubeone.VaultCredentials), b.(*VaultCredentials), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*Velero)(nil), (*kubeone.Velero)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_Velero_To_kubeone_Velero(a.(*Velero), b.(*kubeone.Velero), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.Velero)(nil), (*Velero)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_Velero_To_v1beta1_Velero(a.(*kubeone.Velero), b.(*Velero), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*VeleroSchedule)(nil), (*kubeone.VeleroSchedule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_VeleroSchedule_To_kubeone_VeleroSchedule(a.(*VeleroSchedule), b.(*kubeone.VeleroSchedule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.VeleroSchedule)(nil), (*VeleroSchedule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_VeleroSchedule_To_v1beta1_VeleroSchedule(a.(*kubeone.VeleroSchedule), b.(*VeleroSchedule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*VersionConfig)(nil), (*kubeone.VersionConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_VersionConfig_To_kubeone_VersionConfig(a.(*VersionConfig), b.(*kubeone.VersionConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.VersionConfig)(nil), (*VersionConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_VersionConfig_To_v1beta1_VersionConfig(a.(*kubeone.VersionConfig), b.(*VersionConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*VsphereCSIConfig)(nil), (*kubeone.VsphereCSIConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_VsphereCSIConfig_To_kubeone_VsphereCSIConfig(a.(*VsphereCSIConfig), b.(*kubeone.VsphereCSIConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.VsphereCSIConfig)(nil), (*VsphereCSIConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_VsphereCSIConfig_To_v1beta1_VsphereCSIConfig(a.(*kubeone.VsphereCSIConfig), b.(*VsphereCSIConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*VsphereCSIStorageClass)(nil), (*kubeone.VsphereCSIStorageClass)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_VsphereCSIStorageClass_To_kubeone_VsphereCSIStorageClass(a.(*VsphereCSIStorageClass), b.(*kubeone.VsphereCSIStorageClass), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.VsphereCSIStorageClass)(nil), (*VsphereCSIStorageClass)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_VsphereCSIStorageClass_To_v1beta1_VsphereCSIStorageClass(a.(*kubeone.VsphereCSIStorageClass), b.(*VsphereCSIStorageClass), scope)
 	}); err != nil {
 		return err
 	}
@@ -568,6 +1050,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*WorkersetAutoscaling)(nil), (*kubeone.WorkersetAutoscaling)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_WorkersetAutoscaling_To_kubeone_WorkersetAutoscaling(a.(*WorkersetAutoscaling), b.(*kubeone.WorkersetAutoscaling), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.WorkersetAutoscaling)(nil), (*WorkersetAutoscaling)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_WorkersetAutoscaling_To_v1beta1_WorkersetAutoscaling(a.(*kubeone.WorkersetAutoscaling), b.(*WorkersetAutoscaling), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -595,7 +1087,52 @@ func Convert_kubeone_APIEndpoint_To_v1beta1_APIEndpoint(in *kubeone.APIEndpoint,
 	return autoConvert_kubeone_APIEndpoint_To_v1beta1_APIEndpoint(in, out, s)
 }
 
+func autoConvert_v1beta1_AWSEBSCSIConfig_To_kubeone_AWSEBSCSIConfig(in *AWSEBSCSIConfig, out *kubeone.AWSEBSCSIConfig, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.UseInstanceProfile = in.UseInstanceProfile
+	return nil
+}
+
+// Convert_v1beta1_AWSEBSCSIConfig_To_kubeone_AWSEBSCSIConfig is an autogenerated conversion function.
+func Convert_v1beta1_AWSEBSCSIConfig_To_kubeone_AWSEBSCSIConfig(in *AWSEBSCSIConfig, out *kubeone.AWSEBSCSIConfig, s conversion.Scope) error {
+	return autoConvert_v1beta1_AWSEBSCSIConfig_To_kubeone_AWSEBSCSIConfig(in, out, s)
+}
+
+func autoConvert_kubeone_AWSEBSCSIConfig_To_v1beta1_AWSEBSCSIConfig(in *kubeone.AWSEBSCSIConfig, out *AWSEBSCSIConfig, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.UseInstanceProfile = in.UseInstanceProfile
+	return nil
+}
+
+// Convert_kubeone_AWSEBSCSIConfig_To_v1beta1_AWSEBSCSIConfig is an autogenerated conversion function.
+func Convert_kubeone_AWSEBSCSIConfig_To_v1beta1_AWSEBSCSIConfig(in *kubeone.AWSEBSCSIConfig, out *AWSEBSCSIConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_AWSEBSCSIConfig_To_v1beta1_AWSEBSCSIConfig(in, out, s)
+}
+
+func autoConvert_v1beta1_AWSKMSKeyProvider_To_kubeone_AWSKMSKeyProvider(in *AWSKMSKeyProvider, out *kubeone.AWSKMSKeyProvider, s conversion.Scope) error {
+	out.KeyID = in.KeyID
+	out.Region = in.Region
+	return nil
+}
+
+// Convert_v1beta1_AWSKMSKeyProvider_To_kubeone_AWSKMSKeyProvider is an autogenerated conversion function.
+func Convert_v1beta1_AWSKMSKeyProvider_To_kubeone_AWSKMSKeyProvider(in *AWSKMSKeyProvider, out *kubeone.AWSKMSKeyProvider, s conversion.Scope) error {
+	return autoConvert_v1beta1_AWSKMSKeyProvider_To_kubeone_AWSKMSKeyProvider(in, out, s)
+}
+
+func autoConvert_kubeone_AWSKMSKeyProvider_To_v1beta1_AWSKMSKeyProvider(in *kubeone.AWSKMSKeyProvider, out *AWSKMSKeyProvider, s conversion.Scope) error {
+	out.KeyID = in.KeyID
+	out.Region = in.Region
+	return nil
+}
+
+// Convert_kubeone_AWSKMSKeyProvider_To_v1beta1_AWSKMSKeyProvider is an autogenerated conversion function.
+func Convert_kubeone_AWSKMSKeyProvider_To_v1beta1_AWSKMSKeyProvider(in *kubeone.AWSKMSKeyProvider, out *AWSKMSKeyProvider, s conversion.Scope) error {
+	return autoConvert_kubeone_AWSKMSKeyProvider_To_v1beta1_AWSKMSKeyProvider(in, out, s)
+}
+
 func autoConvert_v1beta1_AWSSpec_To_kubeone_AWSSpec(in *AWSSpec, out *kubeone.AWSSpec, s conversion.Scope) error {
+	out.EBSCSI = (*kubeone.AWSEBSCSIConfig)(unsafe.Pointer(in.EBSCSI))
 	return nil
 }
 
@@ -605,6 +1142,7 @@ func Convert_v1beta1_AWSSpec_To_kubeone_AWSSpec(in *AWSSpec, out *kubeone.AWSSpe
 }
 
 func autoConvert_kubeone_AWSSpec_To_v1beta1_AWSSpec(in *kubeone.AWSSpec, out *AWSSpec, s conversion.Scope) error {
+	out.EBSCSI = (*AWSEBSCSIConfig)(unsafe.Pointer(in.EBSCSI))
 	return nil
 }
 
@@ -688,6 +1226,14 @@ func autoConvert_v1beta1_AssetConfiguration_To_kubeone_AssetConfiguration(in *As
 	if err := Convert_v1beta1_BinaryAsset_To_kubeone_BinaryAsset(&in.Kubectl, &out.Kubectl, s); err != nil {
 		return err
 	}
+	if err := Convert_v1beta1_BinaryAsset_To_kubeone_BinaryAsset(&in.Kubeadm, &out.Kubeadm, s); err != nil {
+		return err
+	}
+	if err := Convert_v1beta1_BinaryAsset_To_kubeone_BinaryAsset(&in.Kubelet, &out.Kubelet, s); err != nil {
+		return err
+	}
+	out.BinariesMirror = in.BinariesMirror
+	out.SignatureVerification = (*kubeone.SignatureVerification)(unsafe.Pointer(in.SignatureVerification))
 	return nil
 }
 
@@ -721,6 +1267,14 @@ func autoConvert_kubeone_AssetConfiguration_To_v1beta1_AssetConfiguration(in *ku
 	if err := Convert_kubeone_BinaryAsset_To_v1beta1_BinaryAsset(&in.Kubectl, &out.Kubectl, s); err != nil {
 		return err
 	}
+	if err := Convert_kubeone_BinaryAsset_To_v1beta1_BinaryAsset(&in.Kubeadm, &out.Kubeadm, s); err != nil {
+		return err
+	}
+	if err := Convert_kubeone_BinaryAsset_To_v1beta1_BinaryAsset(&in.Kubelet, &out.Kubelet, s); err != nil {
+		return err
+	}
+	out.BinariesMirror = in.BinariesMirror
+	out.SignatureVerification = (*SignatureVerification)(unsafe.Pointer(in.SignatureVerification))
 	return nil
 }
 
@@ -729,7 +1283,69 @@ func Convert_kubeone_AssetConfiguration_To_v1beta1_AssetConfiguration(in *kubeon
 	return autoConvert_kubeone_AssetConfiguration_To_v1beta1_AssetConfiguration(in, out, s)
 }
 
+func autoConvert_v1beta1_AzureCloudConfig_To_kubeone_AzureCloudConfig(in *AzureCloudConfig, out *kubeone.AzureCloudConfig, s conversion.Scope) error {
+	out.Cloud = in.Cloud
+	out.ResourceGroup = in.ResourceGroup
+	out.Location = in.Location
+	out.VNetName = in.VNetName
+	out.VNetResourceGroup = in.VNetResourceGroup
+	out.SubnetName = in.SubnetName
+	out.SecurityGroupName = in.SecurityGroupName
+	out.RouteTableName = in.RouteTableName
+	out.PrimaryAvailabilitySetName = in.PrimaryAvailabilitySetName
+	out.LoadBalancerSku = in.LoadBalancerSku
+	return nil
+}
+
+// Convert_v1beta1_AzureCloudConfig_To_kubeone_AzureCloudConfig is an autogenerated conversion function.
+func Convert_v1beta1_AzureCloudConfig_To_kubeone_AzureCloudConfig(in *AzureCloudConfig, out *kubeone.AzureCloudConfig, s conversion.Scope) error {
+	return autoConvert_v1beta1_AzureCloudConfig_To_kubeone_AzureCloudConfig(in, out, s)
+}
+
+func autoConvert_kubeone_AzureCloudConfig_To_v1beta1_AzureCloudConfig(in *kubeone.AzureCloudConfig, out *AzureCloudConfig, s conversion.Scope) error {
+	out.Cloud = in.Cloud
+	out.ResourceGroup = in.ResourceGroup
+	out.Location = in.Location
+	out.VNetName = in.VNetName
+	out.VNetResourceGroup = in.VNetResourceGroup
+	out.SubnetName = in.SubnetName
+	out.SecurityGroupName = in.SecurityGroupName
+	out.RouteTableName = in.RouteTableName
+	out.PrimaryAvailabilitySetName = in.PrimaryAvailabilitySetName
+	out.LoadBalancerSku = in.LoadBalancerSku
+	return nil
+}
+
+// Convert_kubeone_AzureCloudConfig_To_v1beta1_AzureCloudConfig is an autogenerated conversion function.
+func Convert_kubeone_AzureCloudConfig_To_v1beta1_AzureCloudConfig(in *kubeone.AzureCloudConfig, out *AzureCloudConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_AzureCloudConfig_To_v1beta1_AzureCloudConfig(in, out, s)
+}
+
+func autoConvert_v1beta1_AzureComponent_To_kubeone_AzureComponent(in *AzureComponent, out *kubeone.AzureComponent, s conversion.Scope) error {
+	out.Enable = (*bool)(unsafe.Pointer(in.Enable))
+	return nil
+}
+
+// Convert_v1beta1_AzureComponent_To_kubeone_AzureComponent is an autogenerated conversion function.
+func Convert_v1beta1_AzureComponent_To_kubeone_AzureComponent(in *AzureComponent, out *kubeone.AzureComponent, s conversion.Scope) error {
+	return autoConvert_v1beta1_AzureComponent_To_kubeone_AzureComponent(in, out, s)
+}
+
+func autoConvert_kubeone_AzureComponent_To_v1beta1_AzureComponent(in *kubeone.AzureComponent, out *AzureComponent, s conversion.Scope) error {
+	out.Enable = (*bool)(unsafe.Pointer(in.Enable))
+	return nil
+}
+
+// Convert_kubeone_AzureComponent_To_v1beta1_AzureComponent is an autogenerated conversion function.
+func Convert_kubeone_AzureComponent_To_v1beta1_AzureComponent(in *kubeone.AzureComponent, out *AzureComponent, s conversion.Scope) error {
+	return autoConvert_kubeone_AzureComponent_To_v1beta1_AzureComponent(in, out, s)
+}
+
 func autoConvert_v1beta1_AzureSpec_To_kubeone_AzureSpec(in *AzureSpec, out *kubeone.AzureSpec, s conversion.Scope) error {
+	out.CloudConfig = (*kubeone.AzureCloudConfig)(unsafe.Pointer(in.CloudConfig))
+	out.CCM = (*kubeone.AzureComponent)(unsafe.Pointer(in.CCM))
+	out.DiskCSI = (*kubeone.AzureComponent)(unsafe.Pointer(in.DiskCSI))
+	out.FileCSI = (*kubeone.AzureComponent)(unsafe.Pointer(in.FileCSI))
 	return nil
 }
 
@@ -739,6 +1355,10 @@ func Convert_v1beta1_AzureSpec_To_kubeone_AzureSpec(in *AzureSpec, out *kubeone.
 }
 
 func autoConvert_kubeone_AzureSpec_To_v1beta1_AzureSpec(in *kubeone.AzureSpec, out *AzureSpec, s conversion.Scope) error {
+	out.CloudConfig = (*AzureCloudConfig)(unsafe.Pointer(in.CloudConfig))
+	out.CCM = (*AzureComponent)(unsafe.Pointer(in.CCM))
+	out.DiskCSI = (*AzureComponent)(unsafe.Pointer(in.DiskCSI))
+	out.FileCSI = (*AzureComponent)(unsafe.Pointer(in.FileCSI))
 	return nil
 }
 
@@ -747,8 +1367,37 @@ func Convert_kubeone_AzureSpec_To_v1beta1_AzureSpec(in *kubeone.AzureSpec, out *
 	return autoConvert_kubeone_AzureSpec_To_v1beta1_AzureSpec(in, out, s)
 }
 
+func autoConvert_v1beta1_BastionHost_To_kubeone_BastionHost(in *BastionHost, out *kubeone.BastionHost, s conversion.Scope) error {
+	out.Host = in.Host
+	out.Port = in.Port
+	out.User = in.User
+	out.SSHPrivateKeyFile = in.SSHPrivateKeyFile
+	out.SSHAgentSocket = in.SSHAgentSocket
+	return nil
+}
+
+// Convert_v1beta1_BastionHost_To_kubeone_BastionHost is an autogenerated conversion function.
+func Convert_v1beta1_BastionHost_To_kubeone_BastionHost(in *BastionHost, out *kubeone.BastionHost, s conversion.Scope) error {
+	return autoConvert_v1beta1_BastionHost_To_kubeone_BastionHost(in, out, s)
+}
+
+func autoConvert_kubeone_BastionHost_To_v1beta1_BastionHost(in *kubeone.BastionHost, out *BastionHost, s conversion.Scope) error {
+	out.Host = in.Host
+	out.Port = in.Port
+	out.User = in.User
+	out.SSHPrivateKeyFile = in.SSHPrivateKeyFile
+	out.SSHAgentSocket = in.SSHAgentSocket
+	return nil
+}
+
+// Convert_kubeone_BastionHost_To_v1beta1_BastionHost is an autogenerated conversion function.
+func Convert_kubeone_BastionHost_To_v1beta1_BastionHost(in *kubeone.BastionHost, out *BastionHost, s conversion.Scope) error {
+	return autoConvert_kubeone_BastionHost_To_v1beta1_BastionHost(in, out, s)
+}
+
 func autoConvert_v1beta1_BinaryAsset_To_kubeone_BinaryAsset(in *BinaryAsset, out *kubeone.BinaryAsset, s conversion.Scope) error {
 	out.URL = in.URL
+	out.SHA256 = in.SHA256
 	return nil
 }
 
@@ -759,6 +1408,7 @@ func Convert_v1beta1_BinaryAsset_To_kubeone_BinaryAsset(in *BinaryAsset, out *ku
 
 func autoConvert_kubeone_BinaryAsset_To_v1beta1_BinaryAsset(in *kubeone.BinaryAsset, out *BinaryAsset, s conversion.Scope) error {
 	out.URL = in.URL
+	out.SHA256 = in.SHA256
 	return nil
 }
 
@@ -813,6 +1463,76 @@ func Convert_kubeone_CanalSpec_To_v1beta1_CanalSpec(in *kubeone.CanalSpec, out *
 	return autoConvert_kubeone_CanalSpec_To_v1beta1_CanalSpec(in, out, s)
 }
 
+func autoConvert_v1beta1_CertManager_To_kubeone_CertManager(in *CertManager, out *kubeone.CertManager, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.ClusterIssuer = (*kubeone.CertManagerClusterIssuer)(unsafe.Pointer(in.ClusterIssuer))
+	return nil
+}
+
+// Convert_v1beta1_CertManager_To_kubeone_CertManager is an autogenerated conversion function.
+func Convert_v1beta1_CertManager_To_kubeone_CertManager(in *CertManager, out *kubeone.CertManager, s conversion.Scope) error {
+	return autoConvert_v1beta1_CertManager_To_kubeone_CertManager(in, out, s)
+}
+
+func autoConvert_kubeone_CertManager_To_v1beta1_CertManager(in *kubeone.CertManager, out *CertManager, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.ClusterIssuer = (*CertManagerClusterIssuer)(unsafe.Pointer(in.ClusterIssuer))
+	return nil
+}
+
+// Convert_kubeone_CertManager_To_v1beta1_CertManager is an autogenerated conversion function.
+func Convert_kubeone_CertManager_To_v1beta1_CertManager(in *kubeone.CertManager, out *CertManager, s conversion.Scope) error {
+	return autoConvert_kubeone_CertManager_To_v1beta1_CertManager(in, out, s)
+}
+
+func autoConvert_v1beta1_CertManagerClusterIssuer_To_kubeone_CertManagerClusterIssuer(in *CertManagerClusterIssuer, out *kubeone.CertManagerClusterIssuer, s conversion.Scope) error {
+	out.Name = in.Name
+	out.CASecretName = in.CASecretName
+	return nil
+}
+
+// Convert_v1beta1_CertManagerClusterIssuer_To_kubeone_CertManagerClusterIssuer is an autogenerated conversion function.
+func Convert_v1beta1_CertManagerClusterIssuer_To_kubeone_CertManagerClusterIssuer(in *CertManagerClusterIssuer, out *kubeone.CertManagerClusterIssuer, s conversion.Scope) error {
+	return autoConvert_v1beta1_CertManagerClusterIssuer_To_kubeone_CertManagerClusterIssuer(in, out, s)
+}
+
+func autoConvert_kubeone_CertManagerClusterIssuer_To_v1beta1_CertManagerClusterIssuer(in *kubeone.CertManagerClusterIssuer, out *CertManagerClusterIssuer, s conversion.Scope) error {
+	out.Name = in.Name
+	out.CASecretName = in.CASecretName
+	return nil
+}
+
+// Convert_kubeone_CertManagerClusterIssuer_To_v1beta1_CertManagerClusterIssuer is an autogenerated conversion function.
+func Convert_kubeone_CertManagerClusterIssuer_To_v1beta1_CertManagerClusterIssuer(in *kubeone.CertManagerClusterIssuer, out *CertManagerClusterIssuer, s conversion.Scope) error {
+	return autoConvert_kubeone_CertManagerClusterIssuer_To_v1beta1_CertManagerClusterIssuer(in, out, s)
+}
+
+func autoConvert_v1beta1_CertificateAuthorityConfig_To_kubeone_CertificateAuthorityConfig(in *CertificateAuthorityConfig, out *kubeone.CertificateAuthorityConfig, s conversion.Scope) error {
+	out.KeyAlgorithm = kubeone.KeyAlgorithm(in.KeyAlgorithm)
+	out.CertificateValidity = (*v1.Duration)(unsafe.Pointer(in.CertificateValidity))
+	out.RenewBefore = (*v1.Duration)(unsafe.Pointer(in.RenewBefore))
+	out.External = (*kubeone.ExternalCAConfig)(unsafe.Pointer(in.External))
+	return nil
+}
+
+// Convert_v1beta1_CertificateAuthorityConfig_To_kubeone_CertificateAuthorityConfig is an autogenerated conversion function.
+func Convert_v1beta1_CertificateAuthorityConfig_To_kubeone_CertificateAuthorityConfig(in *CertificateAuthorityConfig, out *kubeone.CertificateAuthorityConfig, s conversion.Scope) error {
+	return autoConvert_v1beta1_CertificateAuthorityConfig_To_kubeone_CertificateAuthorityConfig(in, out, s)
+}
+
+func autoConvert_kubeone_CertificateAuthorityConfig_To_v1beta1_CertificateAuthorityConfig(in *kubeone.CertificateAuthorityConfig, out *CertificateAuthorityConfig, s conversion.Scope) error {
+	out.KeyAlgorithm = KeyAlgorithm(in.KeyAlgorithm)
+	out.CertificateValidity = (*v1.Duration)(unsafe.Pointer(in.CertificateValidity))
+	out.RenewBefore = (*v1.Duration)(unsafe.Pointer(in.RenewBefore))
+	out.External = (*ExternalCAConfig)(unsafe.Pointer(in.External))
+	return nil
+}
+
+// Convert_kubeone_CertificateAuthorityConfig_To_v1beta1_CertificateAuthorityConfig is an autogenerated conversion function.
+func Convert_kubeone_CertificateAuthorityConfig_To_v1beta1_CertificateAuthorityConfig(in *kubeone.CertificateAuthorityConfig, out *CertificateAuthorityConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_CertificateAuthorityConfig_To_v1beta1_CertificateAuthorityConfig(in, out, s)
+}
+
 func autoConvert_v1beta1_CiliumSpec_To_kubeone_CiliumSpec(in *CiliumSpec, out *kubeone.CiliumSpec, s conversion.Scope) error {
 	out.KubeProxyReplacement = kubeone.KubeProxyReplacementType(in.KubeProxyReplacement)
 	out.EnableHubble = in.EnableHubble
@@ -848,6 +1568,7 @@ func autoConvert_v1beta1_CloudProviderSpec_To_kubeone_CloudProviderSpec(in *Clou
 	out.Packet = (*kubeone.PacketSpec)(unsafe.Pointer(in.Packet))
 	out.Vsphere = (*kubeone.VsphereSpec)(unsafe.Pointer(in.Vsphere))
 	out.None = (*kubeone.NoneSpec)(unsafe.Pointer(in.None))
+	out.CredentialsVault = (*kubeone.VaultCredentials)(unsafe.Pointer(in.CredentialsVault))
 	return nil
 }
 
@@ -869,6 +1590,7 @@ func autoConvert_kubeone_CloudProviderSpec_To_v1beta1_CloudProviderSpec(in *kube
 	out.Packet = (*PacketSpec)(unsafe.Pointer(in.Packet))
 	out.Vsphere = (*VsphereSpec)(unsafe.Pointer(in.Vsphere))
 	out.None = (*NoneSpec)(unsafe.Pointer(in.None))
+	out.CredentialsVault = (*VaultCredentials)(unsafe.Pointer(in.CredentialsVault))
 	return nil
 }
 
@@ -877,6 +1599,34 @@ func Convert_kubeone_CloudProviderSpec_To_v1beta1_CloudProviderSpec(in *kubeone.
 	return autoConvert_kubeone_CloudProviderSpec_To_v1beta1_CloudProviderSpec(in, out, s)
 }
 
+func autoConvert_v1beta1_ClusterAutoscaler_To_kubeone_ClusterAutoscaler(in *ClusterAutoscaler, out *kubeone.ClusterAutoscaler, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.Expander = in.Expander
+	out.ScaleDownUnneededTime = (*v1.Duration)(unsafe.Pointer(in.ScaleDownUnneededTime))
+	out.ScaleDownDelayAfterAdd = (*v1.Duration)(unsafe.Pointer(in.ScaleDownDelayAfterAdd))
+	out.BalanceSimilarNodeGroups = in.BalanceSimilarNodeGroups
+	return nil
+}
+
+// Convert_v1beta1_ClusterAutoscaler_To_kubeone_ClusterAutoscaler is an autogenerated conversion function.
+func Convert_v1beta1_ClusterAutoscaler_To_kubeone_ClusterAutoscaler(in *ClusterAutoscaler, out *kubeone.ClusterAutoscaler, s conversion.Scope) error {
+	return autoConvert_v1beta1_ClusterAutoscaler_To_kubeone_ClusterAutoscaler(in, out, s)
+}
+
+func autoConvert_kubeone_ClusterAutoscaler_To_v1beta1_ClusterAutoscaler(in *kubeone.ClusterAutoscaler, out *ClusterAutoscaler, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.Expander = in.Expander
+	out.ScaleDownUnneededTime = (*v1.Duration)(unsafe.Pointer(in.ScaleDownUnneededTime))
+	out.ScaleDownDelayAfterAdd = (*v1.Duration)(unsafe.Pointer(in.ScaleDownDelayAfterAdd))
+	out.BalanceSimilarNodeGroups = in.BalanceSimilarNodeGroups
+	return nil
+}
+
+// Convert_kubeone_ClusterAutoscaler_To_v1beta1_ClusterAutoscaler is an autogenerated conversion function.
+func Convert_kubeone_ClusterAutoscaler_To_v1beta1_ClusterAutoscaler(in *kubeone.ClusterAutoscaler, out *ClusterAutoscaler, s conversion.Scope) error {
+	return autoConvert_kubeone_ClusterAutoscaler_To_v1beta1_ClusterAutoscaler(in, out, s)
+}
+
 func autoConvert_v1beta1_ClusterNetworkConfig_To_kubeone_ClusterNetworkConfig(in *ClusterNetworkConfig, out *kubeone.ClusterNetworkConfig, s conversion.Scope) error {
 	out.PodSubnet = in.PodSubnet
 	out.ServiceSubnet = in.ServiceSubnet
@@ -907,6 +1657,30 @@ func Convert_kubeone_ClusterNetworkConfig_To_v1beta1_ClusterNetworkConfig(in *ku
 	return autoConvert_kubeone_ClusterNetworkConfig_To_v1beta1_ClusterNetworkConfig(in, out, s)
 }
 
+func autoConvert_v1beta1_ClusterSizeConfig_To_kubeone_ClusterSizeConfig(in *ClusterSizeConfig, out *kubeone.ClusterSizeConfig, s conversion.Scope) error {
+	out.Nodes = in.Nodes
+	out.Pods = in.Pods
+	out.Enforce = in.Enforce
+	return nil
+}
+
+// Convert_v1beta1_ClusterSizeConfig_To_kubeone_ClusterSizeConfig is an autogenerated conversion function.
+func Convert_v1beta1_ClusterSizeConfig_To_kubeone_ClusterSizeConfig(in *ClusterSizeConfig, out *kubeone.ClusterSizeConfig, s conversion.Scope) error {
+	return autoConvert_v1beta1_ClusterSizeConfig_To_kubeone_ClusterSizeConfig(in, out, s)
+}
+
+func autoConvert_kubeone_ClusterSizeConfig_To_v1beta1_ClusterSizeConfig(in *kubeone.ClusterSizeConfig, out *ClusterSizeConfig, s conversion.Scope) error {
+	out.Nodes = in.Nodes
+	out.Pods = in.Pods
+	out.Enforce = in.Enforce
+	return nil
+}
+
+// Convert_kubeone_ClusterSizeConfig_To_v1beta1_ClusterSizeConfig is an autogenerated conversion function.
+func Convert_kubeone_ClusterSizeConfig_To_v1beta1_ClusterSizeConfig(in *kubeone.ClusterSizeConfig, out *ClusterSizeConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_ClusterSizeConfig_To_v1beta1_ClusterSizeConfig(in, out, s)
+}
+
 func autoConvert_v1beta1_ContainerRuntimeConfig_To_kubeone_ContainerRuntimeConfig(in *ContainerRuntimeConfig, out *kubeone.ContainerRuntimeConfig, s conversion.Scope) error {
 	out.Docker = (*kubeone.ContainerRuntimeDocker)(unsafe.Pointer(in.Docker))
 	out.Containerd = (*kubeone.ContainerRuntimeContainerd)(unsafe.Pointer(in.Containerd))
@@ -930,6 +1704,11 @@ func Convert_kubeone_ContainerRuntimeConfig_To_v1beta1_ContainerRuntimeConfig(in
 }
 
 func autoConvert_v1beta1_ContainerRuntimeContainerd_To_kubeone_ContainerRuntimeContainerd(in *ContainerRuntimeContainerd, out *kubeone.ContainerRuntimeContainerd, s conversion.Scope) error {
+	out.SandboxImage = in.SandboxImage
+	out.CgroupDriver = in.CgroupDriver
+	out.SystemdCgroup = (*bool)(unsafe.Pointer(in.SystemdCgroup))
+	out.NvidiaRuntime = (*kubeone.ContainerdNvidiaRuntime)(unsafe.Pointer(in.NvidiaRuntime))
+	out.ConfigPatch = in.ConfigPatch
 	return nil
 }
 
@@ -939,6 +1718,11 @@ func Convert_v1beta1_ContainerRuntimeContainerd_To_kubeone_ContainerRuntimeConta
 }
 
 func autoConvert_kubeone_ContainerRuntimeContainerd_To_v1beta1_ContainerRuntimeContainerd(in *kubeone.ContainerRuntimeContainerd, out *ContainerRuntimeContainerd, s conversion.Scope) error {
+	out.SandboxImage = in.SandboxImage
+	out.CgroupDriver = in.CgroupDriver
+	out.SystemdCgroup = (*bool)(unsafe.Pointer(in.SystemdCgroup))
+	out.NvidiaRuntime = (*ContainerdNvidiaRuntime)(unsafe.Pointer(in.NvidiaRuntime))
+	out.ConfigPatch = in.ConfigPatch
 	return nil
 }
 
@@ -965,8 +1749,34 @@ func Convert_kubeone_ContainerRuntimeDocker_To_v1beta1_ContainerRuntimeDocker(in
 	return autoConvert_kubeone_ContainerRuntimeDocker_To_v1beta1_ContainerRuntimeDocker(in, out, s)
 }
 
+func autoConvert_v1beta1_ContainerdNvidiaRuntime_To_kubeone_ContainerdNvidiaRuntime(in *ContainerdNvidiaRuntime, out *kubeone.ContainerdNvidiaRuntime, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.BinaryName = in.BinaryName
+	out.DefaultRuntime = in.DefaultRuntime
+	return nil
+}
+
+// Convert_v1beta1_ContainerdNvidiaRuntime_To_kubeone_ContainerdNvidiaRuntime is an autogenerated conversion function.
+func Convert_v1beta1_ContainerdNvidiaRuntime_To_kubeone_ContainerdNvidiaRuntime(in *ContainerdNvidiaRuntime, out *kubeone.ContainerdNvidiaRuntime, s conversion.Scope) error {
+	return autoConvert_v1beta1_ContainerdNvidiaRuntime_To_kubeone_ContainerdNvidiaRuntime(in, out, s)
+}
+
+func autoConvert_kubeone_ContainerdNvidiaRuntime_To_v1beta1_ContainerdNvidiaRuntime(in *kubeone.ContainerdNvidiaRuntime, out *ContainerdNvidiaRuntime, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.BinaryName = in.BinaryName
+	out.DefaultRuntime = in.DefaultRuntime
+	return nil
+}
+
+// Convert_kubeone_ContainerdNvidiaRuntime_To_v1beta1_ContainerdNvidiaRuntime is an autogenerated conversion function.
+func Convert_kubeone_ContainerdNvidiaRuntime_To_v1beta1_ContainerdNvidiaRuntime(in *kubeone.ContainerdNvidiaRuntime, out *ContainerdNvidiaRuntime, s conversion.Scope) error {
+	return autoConvert_kubeone_ContainerdNvidiaRuntime_To_v1beta1_ContainerdNvidiaRuntime(in, out, s)
+}
+
 func autoConvert_v1beta1_ControlPlaneConfig_To_kubeone_ControlPlaneConfig(in *ControlPlaneConfig, out *kubeone.ControlPlaneConfig, s conversion.Scope) error {
 	out.Hosts = *(*[]kubeone.HostConfig)(unsafe.Pointer(&in.Hosts))
+	out.StaticPods = *(*[]kubeone.StaticPod)(unsafe.Pointer(&in.StaticPods))
+	out.HostDiscovery = (*kubeone.HostDiscoveryConfig)(unsafe.Pointer(in.HostDiscovery))
 	return nil
 }
 
@@ -977,6 +1787,8 @@ func Convert_v1beta1_ControlPlaneConfig_To_kubeone_ControlPlaneConfig(in *Contro
 
 func autoConvert_kubeone_ControlPlaneConfig_To_v1beta1_ControlPlaneConfig(in *kubeone.ControlPlaneConfig, out *ControlPlaneConfig, s conversion.Scope) error {
 	out.Hosts = *(*[]HostConfig)(unsafe.Pointer(&in.Hosts))
+	out.StaticPods = *(*[]StaticPod)(unsafe.Pointer(&in.StaticPods))
+	out.HostDiscovery = (*HostDiscoveryConfig)(unsafe.Pointer(in.HostDiscovery))
 	return nil
 }
 
@@ -1006,6 +1818,7 @@ func Convert_kubeone_DNSConfig_To_v1beta1_DNSConfig(in *kubeone.DNSConfig, out *
 }
 
 func autoConvert_v1beta1_DigitalOceanSpec_To_kubeone_DigitalOceanSpec(in *DigitalOceanSpec, out *kubeone.DigitalOceanSpec, s conversion.Scope) error {
+	out.VPCUUID = in.VPCUUID
 	return nil
 }
 
@@ -1015,6 +1828,7 @@ func Convert_v1beta1_DigitalOceanSpec_To_kubeone_DigitalOceanSpec(in *DigitalOce
 }
 
 func autoConvert_kubeone_DigitalOceanSpec_To_v1beta1_DigitalOceanSpec(in *kubeone.DigitalOceanSpec, out *DigitalOceanSpec, s conversion.Scope) error {
+	out.VPCUUID = in.VPCUUID
 	return nil
 }
 
@@ -1049,6 +1863,8 @@ func autoConvert_v1beta1_DynamicWorkerConfig_To_kubeone_DynamicWorkerConfig(in *
 	if err := Convert_v1beta1_ProviderSpec_To_kubeone_ProviderSpec(&in.Config, &out.Config, s); err != nil {
 		return err
 	}
+	out.NvidiaGPU = in.NvidiaGPU
+	out.Autoscaling = (*kubeone.WorkersetAutoscaling)(unsafe.Pointer(in.Autoscaling))
 	return nil
 }
 
@@ -1063,6 +1879,8 @@ func autoConvert_kubeone_DynamicWorkerConfig_To_v1beta1_DynamicWorkerConfig(in *
 	if err := Convert_kubeone_ProviderSpec_To_v1beta1_ProviderSpec(&in.Config, &out.Config, s); err != nil {
 		return err
 	}
+	out.NvidiaGPU = in.NvidiaGPU
+	out.Autoscaling = (*WorkersetAutoscaling)(unsafe.Pointer(in.Autoscaling))
 	return nil
 }
 
@@ -1093,7 +1911,80 @@ func Convert_kubeone_EncryptionProviders_To_v1beta1_EncryptionProviders(in *kube
 	return autoConvert_kubeone_EncryptionProviders_To_v1beta1_EncryptionProviders(in, out, s)
 }
 
+func autoConvert_v1beta1_ExternalCAConfig_To_kubeone_ExternalCAConfig(in *ExternalCAConfig, out *kubeone.ExternalCAConfig, s conversion.Scope) error {
+	out.CACertificate = in.CACertificate
+	out.IntermediateCertificate = in.IntermediateCertificate
+	out.IntermediateKey = in.IntermediateKey
+	out.Signer = (*kubeone.ExternalCASigner)(unsafe.Pointer(in.Signer))
+	out.KeyProvider = (*kubeone.ExternalCAKeyProvider)(unsafe.Pointer(in.KeyProvider))
+	return nil
+}
+
+// Convert_v1beta1_ExternalCAConfig_To_kubeone_ExternalCAConfig is an autogenerated conversion function.
+func Convert_v1beta1_ExternalCAConfig_To_kubeone_ExternalCAConfig(in *ExternalCAConfig, out *kubeone.ExternalCAConfig, s conversion.Scope) error {
+	return autoConvert_v1beta1_ExternalCAConfig_To_kubeone_ExternalCAConfig(in, out, s)
+}
+
+func autoConvert_kubeone_ExternalCAConfig_To_v1beta1_ExternalCAConfig(in *kubeone.ExternalCAConfig, out *ExternalCAConfig, s conversion.Scope) error {
+	out.CACertificate = in.CACertificate
+	out.IntermediateCertificate = in.IntermediateCertificate
+	out.IntermediateKey = in.IntermediateKey
+	out.Signer = (*ExternalCASigner)(unsafe.Pointer(in.Signer))
+	out.KeyProvider = (*ExternalCAKeyProvider)(unsafe.Pointer(in.KeyProvider))
+	return nil
+}
+
+// Convert_kubeone_ExternalCAConfig_To_v1beta1_ExternalCAConfig is an autogenerated conversion function.
+func Convert_kubeone_ExternalCAConfig_To_v1beta1_ExternalCAConfig(in *kubeone.ExternalCAConfig, out *ExternalCAConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_ExternalCAConfig_To_v1beta1_ExternalCAConfig(in, out, s)
+}
+
+func autoConvert_v1beta1_ExternalCAKeyProvider_To_kubeone_ExternalCAKeyProvider(in *ExternalCAKeyProvider, out *kubeone.ExternalCAKeyProvider, s conversion.Scope) error {
+	out.PKCS11 = (*kubeone.PKCS11KeyProvider)(unsafe.Pointer(in.PKCS11))
+	out.AWSKMS = (*kubeone.AWSKMSKeyProvider)(unsafe.Pointer(in.AWSKMS))
+	return nil
+}
+
+// Convert_v1beta1_ExternalCAKeyProvider_To_kubeone_ExternalCAKeyProvider is an autogenerated conversion function.
+func Convert_v1beta1_ExternalCAKeyProvider_To_kubeone_ExternalCAKeyProvider(in *ExternalCAKeyProvider, out *kubeone.ExternalCAKeyProvider, s conversion.Scope) error {
+	return autoConvert_v1beta1_ExternalCAKeyProvider_To_kubeone_ExternalCAKeyProvider(in, out, s)
+}
+
+func autoConvert_kubeone_ExternalCAKeyProvider_To_v1beta1_ExternalCAKeyProvider(in *kubeone.ExternalCAKeyProvider, out *ExternalCAKeyProvider, s conversion.Scope) error {
+	out.PKCS11 = (*PKCS11KeyProvider)(unsafe.Pointer(in.PKCS11))
+	out.AWSKMS = (*AWSKMSKeyProvider)(unsafe.Pointer(in.AWSKMS))
+	return nil
+}
+
+// Convert_kubeone_ExternalCAKeyProvider_To_v1beta1_ExternalCAKeyProvider is an autogenerated conversion function.
+func Convert_kubeone_ExternalCAKeyProvider_To_v1beta1_ExternalCAKeyProvider(in *kubeone.ExternalCAKeyProvider, out *ExternalCAKeyProvider, s conversion.Scope) error {
+	return autoConvert_kubeone_ExternalCAKeyProvider_To_v1beta1_ExternalCAKeyProvider(in, out, s)
+}
+
+func autoConvert_v1beta1_ExternalCASigner_To_kubeone_ExternalCASigner(in *ExternalCASigner, out *kubeone.ExternalCASigner, s conversion.Scope) error {
+	out.Command = in.Command
+	out.Args = *(*[]string)(unsafe.Pointer(&in.Args))
+	return nil
+}
+
+// Convert_v1beta1_ExternalCASigner_To_kubeone_ExternalCASigner is an autogenerated conversion function.
+func Convert_v1beta1_ExternalCASigner_To_kubeone_ExternalCASigner(in *ExternalCASigner, out *kubeone.ExternalCASigner, s conversion.Scope) error {
+	return autoConvert_v1beta1_ExternalCASigner_To_kubeone_ExternalCASigner(in, out, s)
+}
+
+func autoConvert_kubeone_ExternalCASigner_To_v1beta1_ExternalCASigner(in *kubeone.ExternalCASigner, out *ExternalCASigner, s conversion.Scope) error {
+	out.Command = in.Command
+	out.Args = *(*[]string)(unsafe.Pointer(&in.Args))
+	return nil
+}
+
+// Convert_kubeone_ExternalCASigner_To_v1beta1_ExternalCASigner is an autogenerated conversion function.
+func Convert_kubeone_ExternalCASigner_To_v1beta1_ExternalCASigner(in *kubeone.ExternalCASigner, out *ExternalCASigner, s conversion.Scope) error {
+	return autoConvert_kubeone_ExternalCASigner_To_v1beta1_ExternalCASigner(in, out, s)
+}
+
 func autoConvert_v1beta1_ExternalCNISpec_To_kubeone_ExternalCNISpec(in *ExternalCNISpec, out *kubeone.ExternalCNISpec, s conversion.Scope) error {
+	out.HelmChart = (*kubeone.HelmChart)(unsafe.Pointer(in.HelmChart))
 	return nil
 }
 
@@ -1103,6 +1994,7 @@ func Convert_v1beta1_ExternalCNISpec_To_kubeone_ExternalCNISpec(in *ExternalCNIS
 }
 
 func autoConvert_kubeone_ExternalCNISpec_To_v1beta1_ExternalCNISpec(in *kubeone.ExternalCNISpec, out *ExternalCNISpec, s conversion.Scope) error {
+	out.HelmChart = (*HelmChart)(unsafe.Pointer(in.HelmChart))
 	return nil
 }
 
@@ -1120,6 +2012,13 @@ func autoConvert_v1beta1_Features_To_kubeone_Features(in *Features, out *kubeone
 	out.MetricsServer = (*kubeone.MetricsServer)(unsafe.Pointer(in.MetricsServer))
 	out.OpenIDConnect = (*kubeone.OpenIDConnect)(unsafe.Pointer(in.OpenIDConnect))
 	out.EncryptionProviders = (*kubeone.EncryptionProviders)(unsafe.Pointer(in.EncryptionProviders))
+	out.NvidiaGPU = (*kubeone.NvidiaGPU)(unsafe.Pointer(in.NvidiaGPU))
+	out.Monitoring = (*kubeone.Monitoring)(unsafe.Pointer(in.Monitoring))
+	out.ClusterAutoscaler = (*kubeone.ClusterAutoscaler)(unsafe.Pointer(in.ClusterAutoscaler))
+	out.CertManager = (*kubeone.CertManager)(unsafe.Pointer(in.CertManager))
+	out.IngressController = (*kubeone.IngressController)(unsafe.Pointer(in.IngressController))
+	out.GitOps = (*kubeone.GitOps)(unsafe.Pointer(in.GitOps))
+	out.Velero = (*kubeone.Velero)(unsafe.Pointer(in.Velero))
 	return nil
 }
 
@@ -1137,6 +2036,13 @@ func autoConvert_kubeone_Features_To_v1beta1_Features(in *kubeone.Features, out
 	out.MetricsServer = (*MetricsServer)(unsafe.Pointer(in.MetricsServer))
 	out.OpenIDConnect = (*OpenIDConnect)(unsafe.Pointer(in.OpenIDConnect))
 	out.EncryptionProviders = (*EncryptionProviders)(unsafe.Pointer(in.EncryptionProviders))
+	out.NvidiaGPU = (*NvidiaGPU)(unsafe.Pointer(in.NvidiaGPU))
+	out.Monitoring = (*Monitoring)(unsafe.Pointer(in.Monitoring))
+	out.ClusterAutoscaler = (*ClusterAutoscaler)(unsafe.Pointer(in.ClusterAutoscaler))
+	out.CertManager = (*CertManager)(unsafe.Pointer(in.CertManager))
+	out.IngressController = (*IngressController)(unsafe.Pointer(in.IngressController))
+	out.GitOps = (*GitOps)(unsafe.Pointer(in.GitOps))
+	out.Velero = (*Velero)(unsafe.Pointer(in.Velero))
 	return nil
 }
 
@@ -1145,6 +2051,28 @@ func Convert_kubeone_Features_To_v1beta1_Features(in *kubeone.Features, out *Fea
 	return autoConvert_kubeone_Features_To_v1beta1_Features(in, out, s)
 }
 
+func autoConvert_v1beta1_FirewallConfig_To_kubeone_FirewallConfig(in *FirewallConfig, out *kubeone.FirewallConfig, s conversion.Scope) error {
+	out.Backend = kubeone.FirewallBackend(in.Backend)
+	out.NodeCIDRs = *(*[]string)(unsafe.Pointer(&in.NodeCIDRs))
+	return nil
+}
+
+// Convert_v1beta1_FirewallConfig_To_kubeone_FirewallConfig is an autogenerated conversion function.
+func Convert_v1beta1_FirewallConfig_To_kubeone_FirewallConfig(in *FirewallConfig, out *kubeone.FirewallConfig, s conversion.Scope) error {
+	return autoConvert_v1beta1_FirewallConfig_To_kubeone_FirewallConfig(in, out, s)
+}
+
+func autoConvert_kubeone_FirewallConfig_To_v1beta1_FirewallConfig(in *kubeone.FirewallConfig, out *FirewallConfig, s conversion.Scope) error {
+	out.Backend = FirewallBackend(in.Backend)
+	out.NodeCIDRs = *(*[]string)(unsafe.Pointer(&in.NodeCIDRs))
+	return nil
+}
+
+// Convert_kubeone_FirewallConfig_To_v1beta1_FirewallConfig is an autogenerated conversion function.
+func Convert_kubeone_FirewallConfig_To_v1beta1_FirewallConfig(in *kubeone.FirewallConfig, out *FirewallConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_FirewallConfig_To_v1beta1_FirewallConfig(in, out, s)
+}
+
 func autoConvert_v1beta1_GCESpec_To_kubeone_GCESpec(in *GCESpec, out *kubeone.GCESpec, s conversion.Scope) error {
 	return nil
 }
@@ -1163,46 +2091,160 @@ func Convert_kubeone_GCESpec_To_v1beta1_GCESpec(in *kubeone.GCESpec, out *GCESpe
 	return autoConvert_kubeone_GCESpec_To_v1beta1_GCESpec(in, out, s)
 }
 
-func autoConvert_v1beta1_HetznerSpec_To_kubeone_HetznerSpec(in *HetznerSpec, out *kubeone.HetznerSpec, s conversion.Scope) error {
-	out.NetworkID = in.NetworkID
+func autoConvert_v1beta1_GitOps_To_kubeone_GitOps(in *GitOps, out *kubeone.GitOps, s conversion.Scope) error {
+	out.Enable = in.Enable
+	if err := Convert_v1beta1_GitOpsRepository_To_kubeone_GitOpsRepository(&in.Repository, &out.Repository, s); err != nil {
+		return err
+	}
 	return nil
 }
 
-// Convert_v1beta1_HetznerSpec_To_kubeone_HetznerSpec is an autogenerated conversion function.
-func Convert_v1beta1_HetznerSpec_To_kubeone_HetznerSpec(in *HetznerSpec, out *kubeone.HetznerSpec, s conversion.Scope) error {
-	return autoConvert_v1beta1_HetznerSpec_To_kubeone_HetznerSpec(in, out, s)
+// Convert_v1beta1_GitOps_To_kubeone_GitOps is an autogenerated conversion function.
+func Convert_v1beta1_GitOps_To_kubeone_GitOps(in *GitOps, out *kubeone.GitOps, s conversion.Scope) error {
+	return autoConvert_v1beta1_GitOps_To_kubeone_GitOps(in, out, s)
 }
 
-func autoConvert_kubeone_HetznerSpec_To_v1beta1_HetznerSpec(in *kubeone.HetznerSpec, out *HetznerSpec, s conversion.Scope) error {
-	out.NetworkID = in.NetworkID
+func autoConvert_kubeone_GitOps_To_v1beta1_GitOps(in *kubeone.GitOps, out *GitOps, s conversion.Scope) error {
+	out.Enable = in.Enable
+	if err := Convert_kubeone_GitOpsRepository_To_v1beta1_GitOpsRepository(&in.Repository, &out.Repository, s); err != nil {
+		return err
+	}
 	return nil
 }
 
-// Convert_kubeone_HetznerSpec_To_v1beta1_HetznerSpec is an autogenerated conversion function.
-func Convert_kubeone_HetznerSpec_To_v1beta1_HetznerSpec(in *kubeone.HetznerSpec, out *HetznerSpec, s conversion.Scope) error {
-	return autoConvert_kubeone_HetznerSpec_To_v1beta1_HetznerSpec(in, out, s)
+// Convert_kubeone_GitOps_To_v1beta1_GitOps is an autogenerated conversion function.
+func Convert_kubeone_GitOps_To_v1beta1_GitOps(in *kubeone.GitOps, out *GitOps, s conversion.Scope) error {
+	return autoConvert_kubeone_GitOps_To_v1beta1_GitOps(in, out, s)
 }
 
-func autoConvert_v1beta1_HostConfig_To_kubeone_HostConfig(in *HostConfig, out *kubeone.HostConfig, s conversion.Scope) error {
-	out.ID = in.ID
-	out.PublicAddress = in.PublicAddress
-	out.PrivateAddress = in.PrivateAddress
-	out.SSHPort = in.SSHPort
-	out.SSHUsername = in.SSHUsername
-	out.SSHPrivateKeyFile = in.SSHPrivateKeyFile
-	out.SSHAgentSocket = in.SSHAgentSocket
-	out.Bastion = in.Bastion
-	out.BastionPort = in.BastionPort
-	out.BastionUser = in.BastionUser
-	out.Hostname = in.Hostname
-	out.IsLeader = in.IsLeader
-	out.Taints = *(*[]v1.Taint)(unsafe.Pointer(&in.Taints))
-	out.OperatingSystem = kubeone.OperatingSystemName(in.OperatingSystem)
+func autoConvert_v1beta1_GitOpsRepository_To_kubeone_GitOpsRepository(in *GitOpsRepository, out *kubeone.GitOpsRepository, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Branch = in.Branch
+	out.Path = in.Path
+	out.Interval = (*v1.Duration)(unsafe.Pointer(in.Interval))
+	out.SecretName = in.SecretName
 	return nil
 }
 
-// Convert_v1beta1_HostConfig_To_kubeone_HostConfig is an autogenerated conversion function.
-func Convert_v1beta1_HostConfig_To_kubeone_HostConfig(in *HostConfig, out *kubeone.HostConfig, s conversion.Scope) error {
+// Convert_v1beta1_GitOpsRepository_To_kubeone_GitOpsRepository is an autogenerated conversion function.
+func Convert_v1beta1_GitOpsRepository_To_kubeone_GitOpsRepository(in *GitOpsRepository, out *kubeone.GitOpsRepository, s conversion.Scope) error {
+	return autoConvert_v1beta1_GitOpsRepository_To_kubeone_GitOpsRepository(in, out, s)
+}
+
+func autoConvert_kubeone_GitOpsRepository_To_v1beta1_GitOpsRepository(in *kubeone.GitOpsRepository, out *GitOpsRepository, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Branch = in.Branch
+	out.Path = in.Path
+	out.Interval = (*v1.Duration)(unsafe.Pointer(in.Interval))
+	out.SecretName = in.SecretName
+	return nil
+}
+
+// Convert_kubeone_GitOpsRepository_To_v1beta1_GitOpsRepository is an autogenerated conversion function.
+func Convert_kubeone_GitOpsRepository_To_v1beta1_GitOpsRepository(in *kubeone.GitOpsRepository, out *GitOpsRepository, s conversion.Scope) error {
+	return autoConvert_kubeone_GitOpsRepository_To_v1beta1_GitOpsRepository(in, out, s)
+}
+
+func autoConvert_v1beta1_HardeningConfig_To_kubeone_HardeningConfig(in *HardeningConfig, out *kubeone.HardeningConfig, s conversion.Scope) error {
+	out.Profile = kubeone.HardeningProfile(in.Profile)
+	return nil
+}
+
+// Convert_v1beta1_HardeningConfig_To_kubeone_HardeningConfig is an autogenerated conversion function.
+func Convert_v1beta1_HardeningConfig_To_kubeone_HardeningConfig(in *HardeningConfig, out *kubeone.HardeningConfig, s conversion.Scope) error {
+	return autoConvert_v1beta1_HardeningConfig_To_kubeone_HardeningConfig(in, out, s)
+}
+
+func autoConvert_kubeone_HardeningConfig_To_v1beta1_HardeningConfig(in *kubeone.HardeningConfig, out *HardeningConfig, s conversion.Scope) error {
+	out.Profile = HardeningProfile(in.Profile)
+	return nil
+}
+
+// Convert_kubeone_HardeningConfig_To_v1beta1_HardeningConfig is an autogenerated conversion function.
+func Convert_kubeone_HardeningConfig_To_v1beta1_HardeningConfig(in *kubeone.HardeningConfig, out *HardeningConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_HardeningConfig_To_v1beta1_HardeningConfig(in, out, s)
+}
+
+func autoConvert_v1beta1_HelmChart_To_kubeone_HelmChart(in *HelmChart, out *kubeone.HelmChart, s conversion.Scope) error {
+	out.ReleaseName = in.ReleaseName
+	out.Chart = in.Chart
+	out.RepoURL = in.RepoURL
+	out.Version = in.Version
+	out.Namespace = in.Namespace
+	out.ValuesFiles = *(*[]string)(unsafe.Pointer(&in.ValuesFiles))
+	out.Values = *(*map[string]string)(unsafe.Pointer(&in.Values))
+	return nil
+}
+
+// Convert_v1beta1_HelmChart_To_kubeone_HelmChart is an autogenerated conversion function.
+func Convert_v1beta1_HelmChart_To_kubeone_HelmChart(in *HelmChart, out *kubeone.HelmChart, s conversion.Scope) error {
+	return autoConvert_v1beta1_HelmChart_To_kubeone_HelmChart(in, out, s)
+}
+
+func autoConvert_kubeone_HelmChart_To_v1beta1_HelmChart(in *kubeone.HelmChart, out *HelmChart, s conversion.Scope) error {
+	out.ReleaseName = in.ReleaseName
+	out.Chart = in.Chart
+	out.RepoURL = in.RepoURL
+	out.Version = in.Version
+	out.Namespace = in.Namespace
+	out.ValuesFiles = *(*[]string)(unsafe.Pointer(&in.ValuesFiles))
+	out.Values = *(*map[string]string)(unsafe.Pointer(&in.Values))
+	return nil
+}
+
+// Convert_kubeone_HelmChart_To_v1beta1_HelmChart is an autogenerated conversion function.
+func Convert_kubeone_HelmChart_To_v1beta1_HelmChart(in *kubeone.HelmChart, out *HelmChart, s conversion.Scope) error {
+	return autoConvert_kubeone_HelmChart_To_v1beta1_HelmChart(in, out, s)
+}
+
+func autoConvert_v1beta1_HetznerSpec_To_kubeone_HetznerSpec(in *HetznerSpec, out *kubeone.HetznerSpec, s conversion.Scope) error {
+	out.NetworkID = in.NetworkID
+	return nil
+}
+
+// Convert_v1beta1_HetznerSpec_To_kubeone_HetznerSpec is an autogenerated conversion function.
+func Convert_v1beta1_HetznerSpec_To_kubeone_HetznerSpec(in *HetznerSpec, out *kubeone.HetznerSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_HetznerSpec_To_kubeone_HetznerSpec(in, out, s)
+}
+
+func autoConvert_kubeone_HetznerSpec_To_v1beta1_HetznerSpec(in *kubeone.HetznerSpec, out *HetznerSpec, s conversion.Scope) error {
+	out.NetworkID = in.NetworkID
+	return nil
+}
+
+// Convert_kubeone_HetznerSpec_To_v1beta1_HetznerSpec is an autogenerated conversion function.
+func Convert_kubeone_HetznerSpec_To_v1beta1_HetznerSpec(in *kubeone.HetznerSpec, out *HetznerSpec, s conversion.Scope) error {
+	return autoConvert_kubeone_HetznerSpec_To_v1beta1_HetznerSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_HostConfig_To_kubeone_HostConfig(in *HostConfig, out *kubeone.HostConfig, s conversion.Scope) error {
+	out.ID = in.ID
+	out.PublicAddress = in.PublicAddress
+	out.PrivateAddress = in.PrivateAddress
+	out.SSHPort = in.SSHPort
+	out.SSHUsername = in.SSHUsername
+	out.SSHPrivateKeyFile = in.SSHPrivateKeyFile
+	out.SSHPrivateKeyPassphrase = in.SSHPrivateKeyPassphrase
+	out.SSHCertFile = in.SSHCertFile
+	out.SSHAgentSocket = in.SSHAgentSocket
+	out.Bastion = in.Bastion
+	out.BastionPort = in.BastionPort
+	out.BastionUser = in.BastionUser
+	out.BastionHosts = *(*[]kubeone.BastionHost)(unsafe.Pointer(&in.BastionHosts))
+	out.Hostname = in.Hostname
+	out.IsLeader = in.IsLeader
+	out.Taints = *(*[]corev1.Taint)(unsafe.Pointer(&in.Taints))
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.NodeDrain = (*kubeone.NodeDrainConfig)(unsafe.Pointer(in.NodeDrain))
+	out.IgnorePreflightErrors = (*kubeone.IgnorePreflightErrorsConfig)(unsafe.Pointer(in.IgnorePreflightErrors))
+	out.NvidiaGPU = in.NvidiaGPU
+	out.Kubelet = (*kubeone.KubeletConfig)(unsafe.Pointer(in.Kubelet))
+	out.OperatingSystem = kubeone.OperatingSystemName(in.OperatingSystem)
+	return nil
+}
+
+// Convert_v1beta1_HostConfig_To_kubeone_HostConfig is an autogenerated conversion function.
+func Convert_v1beta1_HostConfig_To_kubeone_HostConfig(in *HostConfig, out *kubeone.HostConfig, s conversion.Scope) error {
 	return autoConvert_v1beta1_HostConfig_To_kubeone_HostConfig(in, out, s)
 }
 
@@ -1213,13 +2255,21 @@ func autoConvert_kubeone_HostConfig_To_v1beta1_HostConfig(in *kubeone.HostConfig
 	out.SSHPort = in.SSHPort
 	out.SSHUsername = in.SSHUsername
 	out.SSHPrivateKeyFile = in.SSHPrivateKeyFile
+	out.SSHPrivateKeyPassphrase = in.SSHPrivateKeyPassphrase
+	out.SSHCertFile = in.SSHCertFile
 	out.SSHAgentSocket = in.SSHAgentSocket
 	out.Bastion = in.Bastion
 	out.BastionPort = in.BastionPort
 	out.BastionUser = in.BastionUser
+	out.BastionHosts = *(*[]BastionHost)(unsafe.Pointer(&in.BastionHosts))
 	out.Hostname = in.Hostname
 	out.IsLeader = in.IsLeader
-	out.Taints = *(*[]v1.Taint)(unsafe.Pointer(&in.Taints))
+	out.Taints = *(*[]corev1.Taint)(unsafe.Pointer(&in.Taints))
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.NodeDrain = (*NodeDrainConfig)(unsafe.Pointer(in.NodeDrain))
+	out.IgnorePreflightErrors = (*IgnorePreflightErrorsConfig)(unsafe.Pointer(in.IgnorePreflightErrors))
+	out.NvidiaGPU = in.NvidiaGPU
+	out.Kubelet = (*KubeletConfig)(unsafe.Pointer(in.Kubelet))
 	out.OperatingSystem = OperatingSystemName(in.OperatingSystem)
 	return nil
 }
@@ -1229,6 +2279,54 @@ func Convert_kubeone_HostConfig_To_v1beta1_HostConfig(in *kubeone.HostConfig, ou
 	return autoConvert_kubeone_HostConfig_To_v1beta1_HostConfig(in, out, s)
 }
 
+func autoConvert_v1beta1_HostDiscoveryConfig_To_kubeone_HostDiscoveryConfig(in *HostDiscoveryConfig, out *kubeone.HostDiscoveryConfig, s conversion.Scope) error {
+	out.Tags = *(*map[string]string)(unsafe.Pointer(&in.Tags))
+	if err := Convert_v1beta1_HostConfig_To_kubeone_HostConfig(&in.Template, &out.Template, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_HostDiscoveryConfig_To_kubeone_HostDiscoveryConfig is an autogenerated conversion function.
+func Convert_v1beta1_HostDiscoveryConfig_To_kubeone_HostDiscoveryConfig(in *HostDiscoveryConfig, out *kubeone.HostDiscoveryConfig, s conversion.Scope) error {
+	return autoConvert_v1beta1_HostDiscoveryConfig_To_kubeone_HostDiscoveryConfig(in, out, s)
+}
+
+func autoConvert_kubeone_HostDiscoveryConfig_To_v1beta1_HostDiscoveryConfig(in *kubeone.HostDiscoveryConfig, out *HostDiscoveryConfig, s conversion.Scope) error {
+	out.Tags = *(*map[string]string)(unsafe.Pointer(&in.Tags))
+	if err := Convert_kubeone_HostConfig_To_v1beta1_HostConfig(&in.Template, &out.Template, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_kubeone_HostDiscoveryConfig_To_v1beta1_HostDiscoveryConfig is an autogenerated conversion function.
+func Convert_kubeone_HostDiscoveryConfig_To_v1beta1_HostDiscoveryConfig(in *kubeone.HostDiscoveryConfig, out *HostDiscoveryConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_HostDiscoveryConfig_To_v1beta1_HostDiscoveryConfig(in, out, s)
+}
+
+func autoConvert_v1beta1_HostRebootConfig_To_kubeone_HostRebootConfig(in *HostRebootConfig, out *kubeone.HostRebootConfig, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.Timeout = (*v1.Duration)(unsafe.Pointer(in.Timeout))
+	return nil
+}
+
+// Convert_v1beta1_HostRebootConfig_To_kubeone_HostRebootConfig is an autogenerated conversion function.
+func Convert_v1beta1_HostRebootConfig_To_kubeone_HostRebootConfig(in *HostRebootConfig, out *kubeone.HostRebootConfig, s conversion.Scope) error {
+	return autoConvert_v1beta1_HostRebootConfig_To_kubeone_HostRebootConfig(in, out, s)
+}
+
+func autoConvert_kubeone_HostRebootConfig_To_v1beta1_HostRebootConfig(in *kubeone.HostRebootConfig, out *HostRebootConfig, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.Timeout = (*v1.Duration)(unsafe.Pointer(in.Timeout))
+	return nil
+}
+
+// Convert_kubeone_HostRebootConfig_To_v1beta1_HostRebootConfig is an autogenerated conversion function.
+func Convert_kubeone_HostRebootConfig_To_v1beta1_HostRebootConfig(in *kubeone.HostRebootConfig, out *HostRebootConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_HostRebootConfig_To_v1beta1_HostRebootConfig(in, out, s)
+}
+
 func autoConvert_v1beta1_IPTables_To_kubeone_IPTables(in *IPTables, out *kubeone.IPTables, s conversion.Scope) error {
 	return nil
 }
@@ -1277,6 +2375,28 @@ func Convert_kubeone_IPVSConfig_To_v1beta1_IPVSConfig(in *kubeone.IPVSConfig, ou
 	return autoConvert_kubeone_IPVSConfig_To_v1beta1_IPVSConfig(in, out, s)
 }
 
+func autoConvert_v1beta1_IgnorePreflightErrorsConfig_To_kubeone_IgnorePreflightErrorsConfig(in *IgnorePreflightErrorsConfig, out *kubeone.IgnorePreflightErrorsConfig, s conversion.Scope) error {
+	out.Install = *(*[]string)(unsafe.Pointer(&in.Install))
+	out.Upgrade = *(*[]string)(unsafe.Pointer(&in.Upgrade))
+	return nil
+}
+
+// Convert_v1beta1_IgnorePreflightErrorsConfig_To_kubeone_IgnorePreflightErrorsConfig is an autogenerated conversion function.
+func Convert_v1beta1_IgnorePreflightErrorsConfig_To_kubeone_IgnorePreflightErrorsConfig(in *IgnorePreflightErrorsConfig, out *kubeone.IgnorePreflightErrorsConfig, s conversion.Scope) error {
+	return autoConvert_v1beta1_IgnorePreflightErrorsConfig_To_kubeone_IgnorePreflightErrorsConfig(in, out, s)
+}
+
+func autoConvert_kubeone_IgnorePreflightErrorsConfig_To_v1beta1_IgnorePreflightErrorsConfig(in *kubeone.IgnorePreflightErrorsConfig, out *IgnorePreflightErrorsConfig, s conversion.Scope) error {
+	out.Install = *(*[]string)(unsafe.Pointer(&in.Install))
+	out.Upgrade = *(*[]string)(unsafe.Pointer(&in.Upgrade))
+	return nil
+}
+
+// Convert_kubeone_IgnorePreflightErrorsConfig_To_v1beta1_IgnorePreflightErrorsConfig is an autogenerated conversion function.
+func Convert_kubeone_IgnorePreflightErrorsConfig_To_v1beta1_IgnorePreflightErrorsConfig(in *kubeone.IgnorePreflightErrorsConfig, out *IgnorePreflightErrorsConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_IgnorePreflightErrorsConfig_To_v1beta1_IgnorePreflightErrorsConfig(in, out, s)
+}
+
 func autoConvert_v1beta1_ImageAsset_To_kubeone_ImageAsset(in *ImageAsset, out *kubeone.ImageAsset, s conversion.Scope) error {
 	out.ImageRepository = in.ImageRepository
 	out.ImageTag = in.ImageTag
@@ -1299,6 +2419,36 @@ func Convert_kubeone_ImageAsset_To_v1beta1_ImageAsset(in *kubeone.ImageAsset, ou
 	return autoConvert_kubeone_ImageAsset_To_v1beta1_ImageAsset(in, out, s)
 }
 
+func autoConvert_v1beta1_IngressController_To_kubeone_IngressController(in *IngressController, out *kubeone.IngressController, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.Replicas = in.Replicas
+	out.ServiceType = corev1.ServiceType(in.ServiceType)
+	out.HostNetwork = in.HostNetwork
+	out.IngressClass = in.IngressClass
+	out.DefaultIngressClass = in.DefaultIngressClass
+	return nil
+}
+
+// Convert_v1beta1_IngressController_To_kubeone_IngressController is an autogenerated conversion function.
+func Convert_v1beta1_IngressController_To_kubeone_IngressController(in *IngressController, out *kubeone.IngressController, s conversion.Scope) error {
+	return autoConvert_v1beta1_IngressController_To_kubeone_IngressController(in, out, s)
+}
+
+func autoConvert_kubeone_IngressController_To_v1beta1_IngressController(in *kubeone.IngressController, out *IngressController, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.Replicas = in.Replicas
+	out.ServiceType = corev1.ServiceType(in.ServiceType)
+	out.HostNetwork = in.HostNetwork
+	out.IngressClass = in.IngressClass
+	out.DefaultIngressClass = in.DefaultIngressClass
+	return nil
+}
+
+// Convert_kubeone_IngressController_To_v1beta1_IngressController is an autogenerated conversion function.
+func Convert_kubeone_IngressController_To_v1beta1_IngressController(in *kubeone.IngressController, out *IngressController, s conversion.Scope) error {
+	return autoConvert_kubeone_IngressController_To_v1beta1_IngressController(in, out, s)
+}
+
 func autoConvert_v1beta1_KubeOneCluster_To_kubeone_KubeOneCluster(in *KubeOneCluster, out *kubeone.KubeOneCluster, s conversion.Scope) error {
 	out.Name = in.Name
 	if err := Convert_v1beta1_ControlPlaneConfig_To_kubeone_ControlPlaneConfig(&in.ControlPlane, &out.ControlPlane, s); err != nil {
@@ -1337,6 +2487,20 @@ func autoConvert_v1beta1_KubeOneCluster_To_kubeone_KubeOneCluster(in *KubeOneClu
 		return err
 	}
 	out.RegistryConfiguration = (*kubeone.RegistryConfiguration)(unsafe.Pointer(in.RegistryConfiguration))
+	out.RBAC = (*kubeone.RBACConfig)(unsafe.Pointer(in.RBAC))
+	out.NodeDrain = (*kubeone.NodeDrainConfig)(unsafe.Pointer(in.NodeDrain))
+	out.IgnorePreflightErrors = (*kubeone.IgnorePreflightErrorsConfig)(unsafe.Pointer(in.IgnorePreflightErrors))
+	out.ManifestPatches = *(*[]kubeone.ManifestPatch)(unsafe.Pointer(&in.ManifestPatches))
+	out.ClusterSize = (*kubeone.ClusterSizeConfig)(unsafe.Pointer(in.ClusterSize))
+	out.Preflight = (*kubeone.PreflightConfig)(unsafe.Pointer(in.Preflight))
+	out.HostReboot = (*kubeone.HostRebootConfig)(unsafe.Pointer(in.HostReboot))
+	out.OSTuning = (*kubeone.OSTuningConfig)(unsafe.Pointer(in.OSTuning))
+	out.TimeSync = (*kubeone.TimeSyncConfig)(unsafe.Pointer(in.TimeSync))
+	out.Hardening = (*kubeone.HardeningConfig)(unsafe.Pointer(in.Hardening))
+	out.SecurityModules = (*kubeone.SecurityModulesConfig)(unsafe.Pointer(in.SecurityModules))
+	out.Firewall = (*kubeone.FirewallConfig)(unsafe.Pointer(in.Firewall))
+	out.CertificateAuthority = (*kubeone.CertificateAuthorityConfig)(unsafe.Pointer(in.CertificateAuthority))
+	out.NodeNaming = (*kubeone.NodeNamingConfig)(unsafe.Pointer(in.NodeNaming))
 	return nil
 }
 
@@ -1383,6 +2547,20 @@ func autoConvert_kubeone_KubeOneCluster_To_v1beta1_KubeOneCluster(in *kubeone.Ku
 		return err
 	}
 	out.RegistryConfiguration = (*RegistryConfiguration)(unsafe.Pointer(in.RegistryConfiguration))
+	out.RBAC = (*RBACConfig)(unsafe.Pointer(in.RBAC))
+	out.NodeDrain = (*NodeDrainConfig)(unsafe.Pointer(in.NodeDrain))
+	out.IgnorePreflightErrors = (*IgnorePreflightErrorsConfig)(unsafe.Pointer(in.IgnorePreflightErrors))
+	out.ManifestPatches = *(*[]ManifestPatch)(unsafe.Pointer(&in.ManifestPatches))
+	out.ClusterSize = (*ClusterSizeConfig)(unsafe.Pointer(in.ClusterSize))
+	out.Preflight = (*PreflightConfig)(unsafe.Pointer(in.Preflight))
+	out.HostReboot = (*HostRebootConfig)(unsafe.Pointer(in.HostReboot))
+	out.OSTuning = (*OSTuningConfig)(unsafe.Pointer(in.OSTuning))
+	out.TimeSync = (*TimeSyncConfig)(unsafe.Pointer(in.TimeSync))
+	out.Hardening = (*HardeningConfig)(unsafe.Pointer(in.Hardening))
+	out.SecurityModules = (*SecurityModulesConfig)(unsafe.Pointer(in.SecurityModules))
+	out.Firewall = (*FirewallConfig)(unsafe.Pointer(in.Firewall))
+	out.CertificateAuthority = (*CertificateAuthorityConfig)(unsafe.Pointer(in.CertificateAuthority))
+	out.NodeNaming = (*NodeNamingConfig)(unsafe.Pointer(in.NodeNaming))
 	return nil
 }
 
@@ -1415,8 +2593,40 @@ func Convert_kubeone_KubeProxyConfig_To_v1beta1_KubeProxyConfig(in *kubeone.Kube
 	return autoConvert_kubeone_KubeProxyConfig_To_v1beta1_KubeProxyConfig(in, out, s)
 }
 
+func autoConvert_v1beta1_KubeletConfig_To_kubeone_KubeletConfig(in *KubeletConfig, out *kubeone.KubeletConfig, s conversion.Scope) error {
+	out.MaxPods = (*int32)(unsafe.Pointer(in.MaxPods))
+	out.SystemReserved = *(*map[string]string)(unsafe.Pointer(&in.SystemReserved))
+	out.KubeReserved = *(*map[string]string)(unsafe.Pointer(&in.KubeReserved))
+	out.EvictionHard = *(*map[string]string)(unsafe.Pointer(&in.EvictionHard))
+	return nil
+}
+
+// Convert_v1beta1_KubeletConfig_To_kubeone_KubeletConfig is an autogenerated conversion function.
+func Convert_v1beta1_KubeletConfig_To_kubeone_KubeletConfig(in *KubeletConfig, out *kubeone.KubeletConfig, s conversion.Scope) error {
+	return autoConvert_v1beta1_KubeletConfig_To_kubeone_KubeletConfig(in, out, s)
+}
+
+func autoConvert_kubeone_KubeletConfig_To_v1beta1_KubeletConfig(in *kubeone.KubeletConfig, out *KubeletConfig, s conversion.Scope) error {
+	out.MaxPods = (*int32)(unsafe.Pointer(in.MaxPods))
+	out.SystemReserved = *(*map[string]string)(unsafe.Pointer(&in.SystemReserved))
+	out.KubeReserved = *(*map[string]string)(unsafe.Pointer(&in.KubeReserved))
+	out.EvictionHard = *(*map[string]string)(unsafe.Pointer(&in.EvictionHard))
+	return nil
+}
+
+// Convert_kubeone_KubeletConfig_To_v1beta1_KubeletConfig is an autogenerated conversion function.
+func Convert_kubeone_KubeletConfig_To_v1beta1_KubeletConfig(in *kubeone.KubeletConfig, out *KubeletConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_KubeletConfig_To_v1beta1_KubeletConfig(in, out, s)
+}
+
 func autoConvert_v1beta1_MachineControllerConfig_To_kubeone_MachineControllerConfig(in *MachineControllerConfig, out *kubeone.MachineControllerConfig, s conversion.Scope) error {
 	out.Deploy = in.Deploy
+	out.ImageRepository = in.ImageRepository
+	out.ImageTag = in.ImageTag
+	out.Resources = (*corev1.ResourceRequirements)(unsafe.Pointer(in.Resources))
+	out.NodeSelector = *(*map[string]string)(unsafe.Pointer(&in.NodeSelector))
+	out.Tolerations = *(*[]corev1.Toleration)(unsafe.Pointer(&in.Tolerations))
+	out.ExtraFlags = *(*map[string]string)(unsafe.Pointer(&in.ExtraFlags))
 	return nil
 }
 
@@ -1427,6 +2637,12 @@ func Convert_v1beta1_MachineControllerConfig_To_kubeone_MachineControllerConfig(
 
 func autoConvert_kubeone_MachineControllerConfig_To_v1beta1_MachineControllerConfig(in *kubeone.MachineControllerConfig, out *MachineControllerConfig, s conversion.Scope) error {
 	out.Deploy = in.Deploy
+	out.ImageRepository = in.ImageRepository
+	out.ImageTag = in.ImageTag
+	out.Resources = (*corev1.ResourceRequirements)(unsafe.Pointer(in.Resources))
+	out.NodeSelector = *(*map[string]string)(unsafe.Pointer(&in.NodeSelector))
+	out.Tolerations = *(*[]corev1.Toleration)(unsafe.Pointer(&in.Tolerations))
+	out.ExtraFlags = *(*map[string]string)(unsafe.Pointer(&in.ExtraFlags))
 	return nil
 }
 
@@ -1435,8 +2651,66 @@ func Convert_kubeone_MachineControllerConfig_To_v1beta1_MachineControllerConfig(
 	return autoConvert_kubeone_MachineControllerConfig_To_v1beta1_MachineControllerConfig(in, out, s)
 }
 
+func autoConvert_v1beta1_ManifestPatch_To_kubeone_ManifestPatch(in *ManifestPatch, out *kubeone.ManifestPatch, s conversion.Scope) error {
+	if err := Convert_v1beta1_ManifestPatchTarget_To_kubeone_ManifestPatchTarget(&in.Target, &out.Target, s); err != nil {
+		return err
+	}
+	out.MergePatch = in.MergePatch
+	out.JSONPatch = in.JSONPatch
+	return nil
+}
+
+// Convert_v1beta1_ManifestPatch_To_kubeone_ManifestPatch is an autogenerated conversion function.
+func Convert_v1beta1_ManifestPatch_To_kubeone_ManifestPatch(in *ManifestPatch, out *kubeone.ManifestPatch, s conversion.Scope) error {
+	return autoConvert_v1beta1_ManifestPatch_To_kubeone_ManifestPatch(in, out, s)
+}
+
+func autoConvert_kubeone_ManifestPatch_To_v1beta1_ManifestPatch(in *kubeone.ManifestPatch, out *ManifestPatch, s conversion.Scope) error {
+	if err := Convert_kubeone_ManifestPatchTarget_To_v1beta1_ManifestPatchTarget(&in.Target, &out.Target, s); err != nil {
+		return err
+	}
+	out.MergePatch = in.MergePatch
+	out.JSONPatch = in.JSONPatch
+	return nil
+}
+
+// Convert_kubeone_ManifestPatch_To_v1beta1_ManifestPatch is an autogenerated conversion function.
+func Convert_kubeone_ManifestPatch_To_v1beta1_ManifestPatch(in *kubeone.ManifestPatch, out *ManifestPatch, s conversion.Scope) error {
+	return autoConvert_kubeone_ManifestPatch_To_v1beta1_ManifestPatch(in, out, s)
+}
+
+func autoConvert_v1beta1_ManifestPatchTarget_To_kubeone_ManifestPatchTarget(in *ManifestPatchTarget, out *kubeone.ManifestPatchTarget, s conversion.Scope) error {
+	out.Group = in.Group
+	out.Kind = in.Kind
+	out.Name = in.Name
+	out.Namespace = in.Namespace
+	return nil
+}
+
+// Convert_v1beta1_ManifestPatchTarget_To_kubeone_ManifestPatchTarget is an autogenerated conversion function.
+func Convert_v1beta1_ManifestPatchTarget_To_kubeone_ManifestPatchTarget(in *ManifestPatchTarget, out *kubeone.ManifestPatchTarget, s conversion.Scope) error {
+	return autoConvert_v1beta1_ManifestPatchTarget_To_kubeone_ManifestPatchTarget(in, out, s)
+}
+
+func autoConvert_kubeone_ManifestPatchTarget_To_v1beta1_ManifestPatchTarget(in *kubeone.ManifestPatchTarget, out *ManifestPatchTarget, s conversion.Scope) error {
+	out.Group = in.Group
+	out.Kind = in.Kind
+	out.Name = in.Name
+	out.Namespace = in.Namespace
+	return nil
+}
+
+// Convert_kubeone_ManifestPatchTarget_To_v1beta1_ManifestPatchTarget is an autogenerated conversion function.
+func Convert_kubeone_ManifestPatchTarget_To_v1beta1_ManifestPatchTarget(in *kubeone.ManifestPatchTarget, out *ManifestPatchTarget, s conversion.Scope) error {
+	return autoConvert_kubeone_ManifestPatchTarget_To_v1beta1_ManifestPatchTarget(in, out, s)
+}
+
 func autoConvert_v1beta1_MetricsServer_To_kubeone_MetricsServer(in *MetricsServer, out *kubeone.MetricsServer, s conversion.Scope) error {
 	out.Enable = in.Enable
+	out.Replicas = in.Replicas
+	out.Resources = (*corev1.ResourceRequirements)(unsafe.Pointer(in.Resources))
+	out.KubeletInsecureTLS = (*bool)(unsafe.Pointer(in.KubeletInsecureTLS))
+	out.HostNetwork = in.HostNetwork
 	return nil
 }
 
@@ -1447,6 +2721,10 @@ func Convert_v1beta1_MetricsServer_To_kubeone_MetricsServer(in *MetricsServer, o
 
 func autoConvert_kubeone_MetricsServer_To_v1beta1_MetricsServer(in *kubeone.MetricsServer, out *MetricsServer, s conversion.Scope) error {
 	out.Enable = in.Enable
+	out.Replicas = in.Replicas
+	out.Resources = (*corev1.ResourceRequirements)(unsafe.Pointer(in.Resources))
+	out.KubeletInsecureTLS = (*bool)(unsafe.Pointer(in.KubeletInsecureTLS))
+	out.HostNetwork = in.HostNetwork
 	return nil
 }
 
@@ -1455,6 +2733,76 @@ func Convert_kubeone_MetricsServer_To_v1beta1_MetricsServer(in *kubeone.MetricsS
 	return autoConvert_kubeone_MetricsServer_To_v1beta1_MetricsServer(in, out, s)
 }
 
+func autoConvert_v1beta1_Monitoring_To_kubeone_Monitoring(in *Monitoring, out *kubeone.Monitoring, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.ServiceMonitors = in.ServiceMonitors
+	return nil
+}
+
+// Convert_v1beta1_Monitoring_To_kubeone_Monitoring is an autogenerated conversion function.
+func Convert_v1beta1_Monitoring_To_kubeone_Monitoring(in *Monitoring, out *kubeone.Monitoring, s conversion.Scope) error {
+	return autoConvert_v1beta1_Monitoring_To_kubeone_Monitoring(in, out, s)
+}
+
+func autoConvert_kubeone_Monitoring_To_v1beta1_Monitoring(in *kubeone.Monitoring, out *Monitoring, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.ServiceMonitors = in.ServiceMonitors
+	return nil
+}
+
+// Convert_kubeone_Monitoring_To_v1beta1_Monitoring is an autogenerated conversion function.
+func Convert_kubeone_Monitoring_To_v1beta1_Monitoring(in *kubeone.Monitoring, out *Monitoring, s conversion.Scope) error {
+	return autoConvert_kubeone_Monitoring_To_v1beta1_Monitoring(in, out, s)
+}
+
+func autoConvert_v1beta1_NodeDrainConfig_To_kubeone_NodeDrainConfig(in *NodeDrainConfig, out *kubeone.NodeDrainConfig, s conversion.Scope) error {
+	out.GracePeriodSeconds = (*int)(unsafe.Pointer(in.GracePeriodSeconds))
+	out.Timeout = (*v1.Duration)(unsafe.Pointer(in.Timeout))
+	out.DeleteEmptyDirData = (*bool)(unsafe.Pointer(in.DeleteEmptyDirData))
+	out.SkipWaitForDeleteTimeoutSeconds = (*int)(unsafe.Pointer(in.SkipWaitForDeleteTimeoutSeconds))
+	out.IgnorePodDisruptionBudgets = (*bool)(unsafe.Pointer(in.IgnorePodDisruptionBudgets))
+	return nil
+}
+
+// Convert_v1beta1_NodeDrainConfig_To_kubeone_NodeDrainConfig is an autogenerated conversion function.
+func Convert_v1beta1_NodeDrainConfig_To_kubeone_NodeDrainConfig(in *NodeDrainConfig, out *kubeone.NodeDrainConfig, s conversion.Scope) error {
+	return autoConvert_v1beta1_NodeDrainConfig_To_kubeone_NodeDrainConfig(in, out, s)
+}
+
+func autoConvert_kubeone_NodeDrainConfig_To_v1beta1_NodeDrainConfig(in *kubeone.NodeDrainConfig, out *NodeDrainConfig, s conversion.Scope) error {
+	out.GracePeriodSeconds = (*int)(unsafe.Pointer(in.GracePeriodSeconds))
+	out.Timeout = (*v1.Duration)(unsafe.Pointer(in.Timeout))
+	out.DeleteEmptyDirData = (*bool)(unsafe.Pointer(in.DeleteEmptyDirData))
+	out.SkipWaitForDeleteTimeoutSeconds = (*int)(unsafe.Pointer(in.SkipWaitForDeleteTimeoutSeconds))
+	out.IgnorePodDisruptionBudgets = (*bool)(unsafe.Pointer(in.IgnorePodDisruptionBudgets))
+	return nil
+}
+
+// Convert_kubeone_NodeDrainConfig_To_v1beta1_NodeDrainConfig is an autogenerated conversion function.
+func Convert_kubeone_NodeDrainConfig_To_v1beta1_NodeDrainConfig(in *kubeone.NodeDrainConfig, out *NodeDrainConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_NodeDrainConfig_To_v1beta1_NodeDrainConfig(in, out, s)
+}
+
+func autoConvert_v1beta1_NodeNamingConfig_To_kubeone_NodeNamingConfig(in *NodeNamingConfig, out *kubeone.NodeNamingConfig, s conversion.Scope) error {
+	out.Strategy = kubeone.NodeNamingStrategy(in.Strategy)
+	return nil
+}
+
+// Convert_v1beta1_NodeNamingConfig_To_kubeone_NodeNamingConfig is an autogenerated conversion function.
+func Convert_v1beta1_NodeNamingConfig_To_kubeone_NodeNamingConfig(in *NodeNamingConfig, out *kubeone.NodeNamingConfig, s conversion.Scope) error {
+	return autoConvert_v1beta1_NodeNamingConfig_To_kubeone_NodeNamingConfig(in, out, s)
+}
+
+func autoConvert_kubeone_NodeNamingConfig_To_v1beta1_NodeNamingConfig(in *kubeone.NodeNamingConfig, out *NodeNamingConfig, s conversion.Scope) error {
+	out.Strategy = NodeNamingStrategy(in.Strategy)
+	return nil
+}
+
+// Convert_kubeone_NodeNamingConfig_To_v1beta1_NodeNamingConfig is an autogenerated conversion function.
+func Convert_kubeone_NodeNamingConfig_To_v1beta1_NodeNamingConfig(in *kubeone.NodeNamingConfig, out *NodeNamingConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_NodeNamingConfig_To_v1beta1_NodeNamingConfig(in, out, s)
+}
+
 func autoConvert_v1beta1_NoneSpec_To_kubeone_NoneSpec(in *NoneSpec, out *kubeone.NoneSpec, s conversion.Scope) error {
 	return nil
 }
@@ -1473,6 +2821,86 @@ func Convert_kubeone_NoneSpec_To_v1beta1_NoneSpec(in *kubeone.NoneSpec, out *Non
 	return autoConvert_kubeone_NoneSpec_To_v1beta1_NoneSpec(in, out, s)
 }
 
+func autoConvert_v1beta1_NvidiaGPU_To_kubeone_NvidiaGPU(in *NvidiaGPU, out *kubeone.NvidiaGPU, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.DriverVersion = in.DriverVersion
+	return nil
+}
+
+// Convert_v1beta1_NvidiaGPU_To_kubeone_NvidiaGPU is an autogenerated conversion function.
+func Convert_v1beta1_NvidiaGPU_To_kubeone_NvidiaGPU(in *NvidiaGPU, out *kubeone.NvidiaGPU, s conversion.Scope) error {
+	return autoConvert_v1beta1_NvidiaGPU_To_kubeone_NvidiaGPU(in, out, s)
+}
+
+func autoConvert_kubeone_NvidiaGPU_To_v1beta1_NvidiaGPU(in *kubeone.NvidiaGPU, out *NvidiaGPU, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.DriverVersion = in.DriverVersion
+	return nil
+}
+
+// Convert_kubeone_NvidiaGPU_To_v1beta1_NvidiaGPU is an autogenerated conversion function.
+func Convert_kubeone_NvidiaGPU_To_v1beta1_NvidiaGPU(in *kubeone.NvidiaGPU, out *NvidiaGPU, s conversion.Scope) error {
+	return autoConvert_kubeone_NvidiaGPU_To_v1beta1_NvidiaGPU(in, out, s)
+}
+
+func autoConvert_v1beta1_OSTuning_To_kubeone_OSTuning(in *OSTuning, out *kubeone.OSTuning, s conversion.Scope) error {
+	out.KernelModules = *(*[]string)(unsafe.Pointer(&in.KernelModules))
+	out.Sysctls = *(*map[string]string)(unsafe.Pointer(&in.Sysctls))
+	return nil
+}
+
+// Convert_v1beta1_OSTuning_To_kubeone_OSTuning is an autogenerated conversion function.
+func Convert_v1beta1_OSTuning_To_kubeone_OSTuning(in *OSTuning, out *kubeone.OSTuning, s conversion.Scope) error {
+	return autoConvert_v1beta1_OSTuning_To_kubeone_OSTuning(in, out, s)
+}
+
+func autoConvert_kubeone_OSTuning_To_v1beta1_OSTuning(in *kubeone.OSTuning, out *OSTuning, s conversion.Scope) error {
+	out.KernelModules = *(*[]string)(unsafe.Pointer(&in.KernelModules))
+	out.Sysctls = *(*map[string]string)(unsafe.Pointer(&in.Sysctls))
+	return nil
+}
+
+// Convert_kubeone_OSTuning_To_v1beta1_OSTuning is an autogenerated conversion function.
+func Convert_kubeone_OSTuning_To_v1beta1_OSTuning(in *kubeone.OSTuning, out *OSTuning, s conversion.Scope) error {
+	return autoConvert_kubeone_OSTuning_To_v1beta1_OSTuning(in, out, s)
+}
+
+func autoConvert_v1beta1_OSTuningConfig_To_kubeone_OSTuningConfig(in *OSTuningConfig, out *kubeone.OSTuningConfig, s conversion.Scope) error {
+	if err := Convert_v1beta1_OSTuning_To_kubeone_OSTuning(&in.All, &out.All, s); err != nil {
+		return err
+	}
+	if err := Convert_v1beta1_OSTuning_To_kubeone_OSTuning(&in.ControlPlane, &out.ControlPlane, s); err != nil {
+		return err
+	}
+	if err := Convert_v1beta1_OSTuning_To_kubeone_OSTuning(&in.StaticWorkers, &out.StaticWorkers, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_OSTuningConfig_To_kubeone_OSTuningConfig is an autogenerated conversion function.
+func Convert_v1beta1_OSTuningConfig_To_kubeone_OSTuningConfig(in *OSTuningConfig, out *kubeone.OSTuningConfig, s conversion.Scope) error {
+	return autoConvert_v1beta1_OSTuningConfig_To_kubeone_OSTuningConfig(in, out, s)
+}
+
+func autoConvert_kubeone_OSTuningConfig_To_v1beta1_OSTuningConfig(in *kubeone.OSTuningConfig, out *OSTuningConfig, s conversion.Scope) error {
+	if err := Convert_kubeone_OSTuning_To_v1beta1_OSTuning(&in.All, &out.All, s); err != nil {
+		return err
+	}
+	if err := Convert_kubeone_OSTuning_To_v1beta1_OSTuning(&in.ControlPlane, &out.ControlPlane, s); err != nil {
+		return err
+	}
+	if err := Convert_kubeone_OSTuning_To_v1beta1_OSTuning(&in.StaticWorkers, &out.StaticWorkers, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_kubeone_OSTuningConfig_To_v1beta1_OSTuningConfig is an autogenerated conversion function.
+func Convert_kubeone_OSTuningConfig_To_v1beta1_OSTuningConfig(in *kubeone.OSTuningConfig, out *OSTuningConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_OSTuningConfig_To_v1beta1_OSTuningConfig(in, out, s)
+}
+
 func autoConvert_v1beta1_OpenIDConnect_To_kubeone_OpenIDConnect(in *OpenIDConnect, out *kubeone.OpenIDConnect, s conversion.Scope) error {
 	out.Enable = in.Enable
 	if err := Convert_v1beta1_OpenIDConnectConfig_To_kubeone_OpenIDConnectConfig(&in.Config, &out.Config, s); err != nil {
@@ -1535,7 +2963,31 @@ func Convert_kubeone_OpenIDConnectConfig_To_v1beta1_OpenIDConnectConfig(in *kube
 	return autoConvert_kubeone_OpenIDConnectConfig_To_v1beta1_OpenIDConnectConfig(in, out, s)
 }
 
+func autoConvert_v1beta1_OpenstackComponent_To_kubeone_OpenstackComponent(in *OpenstackComponent, out *kubeone.OpenstackComponent, s conversion.Scope) error {
+	out.Enable = (*bool)(unsafe.Pointer(in.Enable))
+	out.CloudConfigSections = *(*map[string]map[string]string)(unsafe.Pointer(&in.CloudConfigSections))
+	return nil
+}
+
+// Convert_v1beta1_OpenstackComponent_To_kubeone_OpenstackComponent is an autogenerated conversion function.
+func Convert_v1beta1_OpenstackComponent_To_kubeone_OpenstackComponent(in *OpenstackComponent, out *kubeone.OpenstackComponent, s conversion.Scope) error {
+	return autoConvert_v1beta1_OpenstackComponent_To_kubeone_OpenstackComponent(in, out, s)
+}
+
+func autoConvert_kubeone_OpenstackComponent_To_v1beta1_OpenstackComponent(in *kubeone.OpenstackComponent, out *OpenstackComponent, s conversion.Scope) error {
+	out.Enable = (*bool)(unsafe.Pointer(in.Enable))
+	out.CloudConfigSections = *(*map[string]map[string]string)(unsafe.Pointer(&in.CloudConfigSections))
+	return nil
+}
+
+// Convert_kubeone_OpenstackComponent_To_v1beta1_OpenstackComponent is an autogenerated conversion function.
+func Convert_kubeone_OpenstackComponent_To_v1beta1_OpenstackComponent(in *kubeone.OpenstackComponent, out *OpenstackComponent, s conversion.Scope) error {
+	return autoConvert_kubeone_OpenstackComponent_To_v1beta1_OpenstackComponent(in, out, s)
+}
+
 func autoConvert_v1beta1_OpenstackSpec_To_kubeone_OpenstackSpec(in *OpenstackSpec, out *kubeone.OpenstackSpec, s conversion.Scope) error {
+	out.CCM = (*kubeone.OpenstackComponent)(unsafe.Pointer(in.CCM))
+	out.CinderCSI = (*kubeone.OpenstackComponent)(unsafe.Pointer(in.CinderCSI))
 	return nil
 }
 
@@ -1545,6 +2997,8 @@ func Convert_v1beta1_OpenstackSpec_To_kubeone_OpenstackSpec(in *OpenstackSpec, o
 }
 
 func autoConvert_kubeone_OpenstackSpec_To_v1beta1_OpenstackSpec(in *kubeone.OpenstackSpec, out *OpenstackSpec, s conversion.Scope) error {
+	out.CCM = (*OpenstackComponent)(unsafe.Pointer(in.CCM))
+	out.CinderCSI = (*OpenstackComponent)(unsafe.Pointer(in.CinderCSI))
 	return nil
 }
 
@@ -1553,6 +3007,32 @@ func Convert_kubeone_OpenstackSpec_To_v1beta1_OpenstackSpec(in *kubeone.Openstac
 	return autoConvert_kubeone_OpenstackSpec_To_v1beta1_OpenstackSpec(in, out, s)
 }
 
+func autoConvert_v1beta1_PKCS11KeyProvider_To_kubeone_PKCS11KeyProvider(in *PKCS11KeyProvider, out *kubeone.PKCS11KeyProvider, s conversion.Scope) error {
+	out.Module = in.Module
+	out.TokenLabel = in.TokenLabel
+	out.KeyID = in.KeyID
+	out.PIN = in.PIN
+	return nil
+}
+
+// Convert_v1beta1_PKCS11KeyProvider_To_kubeone_PKCS11KeyProvider is an autogenerated conversion function.
+func Convert_v1beta1_PKCS11KeyProvider_To_kubeone_PKCS11KeyProvider(in *PKCS11KeyProvider, out *kubeone.PKCS11KeyProvider, s conversion.Scope) error {
+	return autoConvert_v1beta1_PKCS11KeyProvider_To_kubeone_PKCS11KeyProvider(in, out, s)
+}
+
+func autoConvert_kubeone_PKCS11KeyProvider_To_v1beta1_PKCS11KeyProvider(in *kubeone.PKCS11KeyProvider, out *PKCS11KeyProvider, s conversion.Scope) error {
+	out.Module = in.Module
+	out.TokenLabel = in.TokenLabel
+	out.KeyID = in.KeyID
+	out.PIN = in.PIN
+	return nil
+}
+
+// Convert_kubeone_PKCS11KeyProvider_To_v1beta1_PKCS11KeyProvider is an autogenerated conversion function.
+func Convert_kubeone_PKCS11KeyProvider_To_v1beta1_PKCS11KeyProvider(in *kubeone.PKCS11KeyProvider, out *PKCS11KeyProvider, s conversion.Scope) error {
+	return autoConvert_kubeone_PKCS11KeyProvider_To_v1beta1_PKCS11KeyProvider(in, out, s)
+}
+
 func autoConvert_v1beta1_PacketSpec_To_kubeone_PacketSpec(in *PacketSpec, out *kubeone.PacketSpec, s conversion.Scope) error {
 	return nil
 }
@@ -1657,12 +3137,34 @@ func Convert_kubeone_PodSecurityPolicy_To_v1beta1_PodSecurityPolicy(in *kubeone.
 	return autoConvert_kubeone_PodSecurityPolicy_To_v1beta1_PodSecurityPolicy(in, out, s)
 }
 
+func autoConvert_v1beta1_PreflightConfig_To_kubeone_PreflightConfig(in *PreflightConfig, out *kubeone.PreflightConfig, s conversion.Scope) error {
+	out.Skip = *(*[]string)(unsafe.Pointer(&in.Skip))
+	out.WarnOnly = *(*[]string)(unsafe.Pointer(&in.WarnOnly))
+	return nil
+}
+
+// Convert_v1beta1_PreflightConfig_To_kubeone_PreflightConfig is an autogenerated conversion function.
+func Convert_v1beta1_PreflightConfig_To_kubeone_PreflightConfig(in *PreflightConfig, out *kubeone.PreflightConfig, s conversion.Scope) error {
+	return autoConvert_v1beta1_PreflightConfig_To_kubeone_PreflightConfig(in, out, s)
+}
+
+func autoConvert_kubeone_PreflightConfig_To_v1beta1_PreflightConfig(in *kubeone.PreflightConfig, out *PreflightConfig, s conversion.Scope) error {
+	out.Skip = *(*[]string)(unsafe.Pointer(&in.Skip))
+	out.WarnOnly = *(*[]string)(unsafe.Pointer(&in.WarnOnly))
+	return nil
+}
+
+// Convert_kubeone_PreflightConfig_To_v1beta1_PreflightConfig is an autogenerated conversion function.
+func Convert_kubeone_PreflightConfig_To_v1beta1_PreflightConfig(in *kubeone.PreflightConfig, out *PreflightConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_PreflightConfig_To_v1beta1_PreflightConfig(in, out, s)
+}
+
 func autoConvert_v1beta1_ProviderSpec_To_kubeone_ProviderSpec(in *ProviderSpec, out *kubeone.ProviderSpec, s conversion.Scope) error {
 	out.CloudProviderSpec = *(*json.RawMessage)(unsafe.Pointer(&in.CloudProviderSpec))
 	out.Annotations = *(*map[string]string)(unsafe.Pointer(&in.Annotations))
 	out.MachineAnnotations = *(*map[string]string)(unsafe.Pointer(&in.MachineAnnotations))
 	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
-	out.Taints = *(*[]v1.Taint)(unsafe.Pointer(&in.Taints))
+	out.Taints = *(*[]corev1.Taint)(unsafe.Pointer(&in.Taints))
 	out.SSHPublicKeys = *(*[]string)(unsafe.Pointer(&in.SSHPublicKeys))
 	out.OperatingSystem = in.OperatingSystem
 	out.OperatingSystemSpec = *(*json.RawMessage)(unsafe.Pointer(&in.OperatingSystemSpec))
@@ -1681,7 +3183,7 @@ func autoConvert_kubeone_ProviderSpec_To_v1beta1_ProviderSpec(in *kubeone.Provid
 	out.Annotations = *(*map[string]string)(unsafe.Pointer(&in.Annotations))
 	out.MachineAnnotations = *(*map[string]string)(unsafe.Pointer(&in.MachineAnnotations))
 	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
-	out.Taints = *(*[]v1.Taint)(unsafe.Pointer(&in.Taints))
+	out.Taints = *(*[]corev1.Taint)(unsafe.Pointer(&in.Taints))
 	out.SSHPublicKeys = *(*[]string)(unsafe.Pointer(&in.SSHPublicKeys))
 	out.OperatingSystem = in.OperatingSystem
 	out.OperatingSystemSpec = *(*json.RawMessage)(unsafe.Pointer(&in.OperatingSystemSpec))
@@ -1747,6 +3249,80 @@ func Convert_kubeone_ProxyConfig_To_v1beta1_ProxyConfig(in *kubeone.ProxyConfig,
 	return autoConvert_kubeone_ProxyConfig_To_v1beta1_ProxyConfig(in, out, s)
 }
 
+func autoConvert_v1beta1_RBACClusterRole_To_kubeone_RBACClusterRole(in *RBACClusterRole, out *kubeone.RBACClusterRole, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Rules = *(*[]rbacv1.PolicyRule)(unsafe.Pointer(&in.Rules))
+	return nil
+}
+
+// Convert_v1beta1_RBACClusterRole_To_kubeone_RBACClusterRole is an autogenerated conversion function.
+func Convert_v1beta1_RBACClusterRole_To_kubeone_RBACClusterRole(in *RBACClusterRole, out *kubeone.RBACClusterRole, s conversion.Scope) error {
+	return autoConvert_v1beta1_RBACClusterRole_To_kubeone_RBACClusterRole(in, out, s)
+}
+
+func autoConvert_kubeone_RBACClusterRole_To_v1beta1_RBACClusterRole(in *kubeone.RBACClusterRole, out *RBACClusterRole, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Rules = *(*[]rbacv1.PolicyRule)(unsafe.Pointer(&in.Rules))
+	return nil
+}
+
+// Convert_kubeone_RBACClusterRole_To_v1beta1_RBACClusterRole is an autogenerated conversion function.
+func Convert_kubeone_RBACClusterRole_To_v1beta1_RBACClusterRole(in *kubeone.RBACClusterRole, out *RBACClusterRole, s conversion.Scope) error {
+	return autoConvert_kubeone_RBACClusterRole_To_v1beta1_RBACClusterRole(in, out, s)
+}
+
+func autoConvert_v1beta1_RBACConfig_To_kubeone_RBACConfig(in *RBACConfig, out *kubeone.RBACConfig, s conversion.Scope) error {
+	out.ClusterRoles = *(*[]kubeone.RBACClusterRole)(unsafe.Pointer(&in.ClusterRoles))
+	out.ClusterRoleBindings = *(*[]kubeone.RBACRoleBinding)(unsafe.Pointer(&in.ClusterRoleBindings))
+	out.RoleBindings = *(*[]kubeone.RBACRoleBinding)(unsafe.Pointer(&in.RoleBindings))
+	return nil
+}
+
+// Convert_v1beta1_RBACConfig_To_kubeone_RBACConfig is an autogenerated conversion function.
+func Convert_v1beta1_RBACConfig_To_kubeone_RBACConfig(in *RBACConfig, out *kubeone.RBACConfig, s conversion.Scope) error {
+	return autoConvert_v1beta1_RBACConfig_To_kubeone_RBACConfig(in, out, s)
+}
+
+func autoConvert_kubeone_RBACConfig_To_v1beta1_RBACConfig(in *kubeone.RBACConfig, out *RBACConfig, s conversion.Scope) error {
+	out.ClusterRoles = *(*[]RBACClusterRole)(unsafe.Pointer(&in.ClusterRoles))
+	out.ClusterRoleBindings = *(*[]RBACRoleBinding)(unsafe.Pointer(&in.ClusterRoleBindings))
+	out.RoleBindings = *(*[]RBACRoleBinding)(unsafe.Pointer(&in.RoleBindings))
+	return nil
+}
+
+// Convert_kubeone_RBACConfig_To_v1beta1_RBACConfig is an autogenerated conversion function.
+func Convert_kubeone_RBACConfig_To_v1beta1_RBACConfig(in *kubeone.RBACConfig, out *RBACConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_RBACConfig_To_v1beta1_RBACConfig(in, out, s)
+}
+
+func autoConvert_v1beta1_RBACRoleBinding_To_kubeone_RBACRoleBinding(in *RBACRoleBinding, out *kubeone.RBACRoleBinding, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Namespace = in.Namespace
+	out.ClusterRole = in.ClusterRole
+	out.Groups = *(*[]string)(unsafe.Pointer(&in.Groups))
+	out.Users = *(*[]string)(unsafe.Pointer(&in.Users))
+	return nil
+}
+
+// Convert_v1beta1_RBACRoleBinding_To_kubeone_RBACRoleBinding is an autogenerated conversion function.
+func Convert_v1beta1_RBACRoleBinding_To_kubeone_RBACRoleBinding(in *RBACRoleBinding, out *kubeone.RBACRoleBinding, s conversion.Scope) error {
+	return autoConvert_v1beta1_RBACRoleBinding_To_kubeone_RBACRoleBinding(in, out, s)
+}
+
+func autoConvert_kubeone_RBACRoleBinding_To_v1beta1_RBACRoleBinding(in *kubeone.RBACRoleBinding, out *RBACRoleBinding, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Namespace = in.Namespace
+	out.ClusterRole = in.ClusterRole
+	out.Groups = *(*[]string)(unsafe.Pointer(&in.Groups))
+	out.Users = *(*[]string)(unsafe.Pointer(&in.Users))
+	return nil
+}
+
+// Convert_kubeone_RBACRoleBinding_To_v1beta1_RBACRoleBinding is an autogenerated conversion function.
+func Convert_kubeone_RBACRoleBinding_To_v1beta1_RBACRoleBinding(in *kubeone.RBACRoleBinding, out *RBACRoleBinding, s conversion.Scope) error {
+	return autoConvert_kubeone_RBACRoleBinding_To_v1beta1_RBACRoleBinding(in, out, s)
+}
+
 func autoConvert_v1beta1_RegistryConfiguration_To_kubeone_RegistryConfiguration(in *RegistryConfiguration, out *kubeone.RegistryConfiguration, s conversion.Scope) error {
 	out.OverwriteRegistry = in.OverwriteRegistry
 	out.InsecureRegistry = in.InsecureRegistry
@@ -1769,6 +3345,52 @@ func Convert_kubeone_RegistryConfiguration_To_v1beta1_RegistryConfiguration(in *
 	return autoConvert_kubeone_RegistryConfiguration_To_v1beta1_RegistryConfiguration(in, out, s)
 }
 
+func autoConvert_v1beta1_SecurityModulesConfig_To_kubeone_SecurityModulesConfig(in *SecurityModulesConfig, out *kubeone.SecurityModulesConfig, s conversion.Scope) error {
+	out.SELinux = kubeone.SELinuxMode(in.SELinux)
+	out.AppArmor = kubeone.AppArmorMode(in.AppArmor)
+	return nil
+}
+
+// Convert_v1beta1_SecurityModulesConfig_To_kubeone_SecurityModulesConfig is an autogenerated conversion function.
+func Convert_v1beta1_SecurityModulesConfig_To_kubeone_SecurityModulesConfig(in *SecurityModulesConfig, out *kubeone.SecurityModulesConfig, s conversion.Scope) error {
+	return autoConvert_v1beta1_SecurityModulesConfig_To_kubeone_SecurityModulesConfig(in, out, s)
+}
+
+func autoConvert_kubeone_SecurityModulesConfig_To_v1beta1_SecurityModulesConfig(in *kubeone.SecurityModulesConfig, out *SecurityModulesConfig, s conversion.Scope) error {
+	out.SELinux = SELinuxMode(in.SELinux)
+	out.AppArmor = AppArmorMode(in.AppArmor)
+	return nil
+}
+
+// Convert_kubeone_SecurityModulesConfig_To_v1beta1_SecurityModulesConfig is an autogenerated conversion function.
+func Convert_kubeone_SecurityModulesConfig_To_v1beta1_SecurityModulesConfig(in *kubeone.SecurityModulesConfig, out *SecurityModulesConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_SecurityModulesConfig_To_v1beta1_SecurityModulesConfig(in, out, s)
+}
+
+func autoConvert_v1beta1_SignatureVerification_To_kubeone_SignatureVerification(in *SignatureVerification, out *kubeone.SignatureVerification, s conversion.Scope) error {
+	out.PublicKey = in.PublicKey
+	out.CertificateIdentity = in.CertificateIdentity
+	out.CertificateOIDCIssuer = in.CertificateOIDCIssuer
+	return nil
+}
+
+// Convert_v1beta1_SignatureVerification_To_kubeone_SignatureVerification is an autogenerated conversion function.
+func Convert_v1beta1_SignatureVerification_To_kubeone_SignatureVerification(in *SignatureVerification, out *kubeone.SignatureVerification, s conversion.Scope) error {
+	return autoConvert_v1beta1_SignatureVerification_To_kubeone_SignatureVerification(in, out, s)
+}
+
+func autoConvert_kubeone_SignatureVerification_To_v1beta1_SignatureVerification(in *kubeone.SignatureVerification, out *SignatureVerification, s conversion.Scope) error {
+	out.PublicKey = in.PublicKey
+	out.CertificateIdentity = in.CertificateIdentity
+	out.CertificateOIDCIssuer = in.CertificateOIDCIssuer
+	return nil
+}
+
+// Convert_kubeone_SignatureVerification_To_v1beta1_SignatureVerification is an autogenerated conversion function.
+func Convert_kubeone_SignatureVerification_To_v1beta1_SignatureVerification(in *kubeone.SignatureVerification, out *SignatureVerification, s conversion.Scope) error {
+	return autoConvert_kubeone_SignatureVerification_To_v1beta1_SignatureVerification(in, out, s)
+}
+
 func autoConvert_v1beta1_StaticAuditLog_To_kubeone_StaticAuditLog(in *StaticAuditLog, out *kubeone.StaticAuditLog, s conversion.Scope) error {
 	out.Enable = in.Enable
 	if err := Convert_v1beta1_StaticAuditLogConfig_To_kubeone_StaticAuditLogConfig(&in.Config, &out.Config, s); err != nil {
@@ -1823,8 +3445,61 @@ func Convert_kubeone_StaticAuditLogConfig_To_v1beta1_StaticAuditLogConfig(in *ku
 	return autoConvert_kubeone_StaticAuditLogConfig_To_v1beta1_StaticAuditLogConfig(in, out, s)
 }
 
+func autoConvert_v1beta1_StaticPod_To_kubeone_StaticPod(in *StaticPod, out *kubeone.StaticPod, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Manifest = in.Manifest
+	return nil
+}
+
+// Convert_v1beta1_StaticPod_To_kubeone_StaticPod is an autogenerated conversion function.
+func Convert_v1beta1_StaticPod_To_kubeone_StaticPod(in *StaticPod, out *kubeone.StaticPod, s conversion.Scope) error {
+	return autoConvert_v1beta1_StaticPod_To_kubeone_StaticPod(in, out, s)
+}
+
+func autoConvert_kubeone_StaticPod_To_v1beta1_StaticPod(in *kubeone.StaticPod, out *StaticPod, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Manifest = in.Manifest
+	return nil
+}
+
+// Convert_kubeone_StaticPod_To_v1beta1_StaticPod is an autogenerated conversion function.
+func Convert_kubeone_StaticPod_To_v1beta1_StaticPod(in *kubeone.StaticPod, out *StaticPod, s conversion.Scope) error {
+	return autoConvert_kubeone_StaticPod_To_v1beta1_StaticPod(in, out, s)
+}
+
+func autoConvert_v1beta1_StaticWorkerGroup_To_kubeone_StaticWorkerGroup(in *StaticWorkerGroup, out *kubeone.StaticWorkerGroup, s conversion.Scope) error {
+	out.Name = in.Name
+	if err := Convert_v1beta1_HostConfig_To_kubeone_HostConfig(&in.Template, &out.Template, s); err != nil {
+		return err
+	}
+	out.Hosts = *(*[]kubeone.HostConfig)(unsafe.Pointer(&in.Hosts))
+	return nil
+}
+
+// Convert_v1beta1_StaticWorkerGroup_To_kubeone_StaticWorkerGroup is an autogenerated conversion function.
+func Convert_v1beta1_StaticWorkerGroup_To_kubeone_StaticWorkerGroup(in *StaticWorkerGroup, out *kubeone.StaticWorkerGroup, s conversion.Scope) error {
+	return autoConvert_v1beta1_StaticWorkerGroup_To_kubeone_StaticWorkerGroup(in, out, s)
+}
+
+func autoConvert_kubeone_StaticWorkerGroup_To_v1beta1_StaticWorkerGroup(in *kubeone.StaticWorkerGroup, out *StaticWorkerGroup, s conversion.Scope) error {
+	out.Name = in.Name
+	if err := Convert_kubeone_HostConfig_To_v1beta1_HostConfig(&in.Template, &out.Template, s); err != nil {
+		return err
+	}
+	out.Hosts = *(*[]HostConfig)(unsafe.Pointer(&in.Hosts))
+	return nil
+}
+
+// Convert_kubeone_StaticWorkerGroup_To_v1beta1_StaticWorkerGroup is an autogenerated conversion function.
+func Convert_kubeone_StaticWorkerGroup_To_v1beta1_StaticWorkerGroup(in *kubeone.StaticWorkerGroup, out *StaticWorkerGroup, s conversion.Scope) error {
+	return autoConvert_kubeone_StaticWorkerGroup_To_v1beta1_StaticWorkerGroup(in, out, s)
+}
+
 func autoConvert_v1beta1_StaticWorkersConfig_To_kubeone_StaticWorkersConfig(in *StaticWorkersConfig, out *kubeone.StaticWorkersConfig, s conversion.Scope) error {
 	out.Hosts = *(*[]kubeone.HostConfig)(unsafe.Pointer(&in.Hosts))
+	out.Groups = *(*[]kubeone.StaticWorkerGroup)(unsafe.Pointer(&in.Groups))
+	out.StaticPods = *(*[]kubeone.StaticPod)(unsafe.Pointer(&in.StaticPods))
+	out.HostDiscovery = (*kubeone.HostDiscoveryConfig)(unsafe.Pointer(in.HostDiscovery))
 	return nil
 }
 
@@ -1835,6 +3510,9 @@ func Convert_v1beta1_StaticWorkersConfig_To_kubeone_StaticWorkersConfig(in *Stat
 
 func autoConvert_kubeone_StaticWorkersConfig_To_v1beta1_StaticWorkersConfig(in *kubeone.StaticWorkersConfig, out *StaticWorkersConfig, s conversion.Scope) error {
 	out.Hosts = *(*[]HostConfig)(unsafe.Pointer(&in.Hosts))
+	out.Groups = *(*[]StaticWorkerGroup)(unsafe.Pointer(&in.Groups))
+	out.StaticPods = *(*[]StaticPod)(unsafe.Pointer(&in.StaticPods))
+	out.HostDiscovery = (*HostDiscoveryConfig)(unsafe.Pointer(in.HostDiscovery))
 	return nil
 }
 
@@ -1863,6 +3541,112 @@ func Convert_kubeone_SystemPackages_To_v1beta1_SystemPackages(in *kubeone.System
 	return autoConvert_kubeone_SystemPackages_To_v1beta1_SystemPackages(in, out, s)
 }
 
+func autoConvert_v1beta1_TimeSyncConfig_To_kubeone_TimeSyncConfig(in *TimeSyncConfig, out *kubeone.TimeSyncConfig, s conversion.Scope) error {
+	out.Chrony = in.Chrony
+	out.NTPServers = *(*[]string)(unsafe.Pointer(&in.NTPServers))
+	out.MaxClockSkew = (*v1.Duration)(unsafe.Pointer(in.MaxClockSkew))
+	return nil
+}
+
+// Convert_v1beta1_TimeSyncConfig_To_kubeone_TimeSyncConfig is an autogenerated conversion function.
+func Convert_v1beta1_TimeSyncConfig_To_kubeone_TimeSyncConfig(in *TimeSyncConfig, out *kubeone.TimeSyncConfig, s conversion.Scope) error {
+	return autoConvert_v1beta1_TimeSyncConfig_To_kubeone_TimeSyncConfig(in, out, s)
+}
+
+func autoConvert_kubeone_TimeSyncConfig_To_v1beta1_TimeSyncConfig(in *kubeone.TimeSyncConfig, out *TimeSyncConfig, s conversion.Scope) error {
+	out.Chrony = in.Chrony
+	out.NTPServers = *(*[]string)(unsafe.Pointer(&in.NTPServers))
+	out.MaxClockSkew = (*v1.Duration)(unsafe.Pointer(in.MaxClockSkew))
+	return nil
+}
+
+// Convert_kubeone_TimeSyncConfig_To_v1beta1_TimeSyncConfig is an autogenerated conversion function.
+func Convert_kubeone_TimeSyncConfig_To_v1beta1_TimeSyncConfig(in *kubeone.TimeSyncConfig, out *TimeSyncConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_TimeSyncConfig_To_v1beta1_TimeSyncConfig(in, out, s)
+}
+
+func autoConvert_v1beta1_VaultCredentials_To_kubeone_VaultCredentials(in *VaultCredentials, out *kubeone.VaultCredentials, s conversion.Scope) error {
+	out.Path = in.Path
+	out.Keys = *(*map[string]string)(unsafe.Pointer(&in.Keys))
+	return nil
+}
+
+// Convert_v1beta1_VaultCredentials_To_kubeone_VaultCredentials is an autogenerated conversion function.
+func Convert_v1beta1_VaultCredentials_To_kubeone_VaultCredentials(in *VaultCredentials, out *kubeone.VaultCredentials, s conversion.Scope) error {
+	return autoConvert_v1beta1_VaultCredentials_To_kubeone_VaultCredentials(in, out, s)
+}
+
+func autoConvert_kubeone_VaultCredentials_To_v1beta1_VaultCredentials(in *kubeone.VaultCredentials, out *VaultCredentials, s conversion.Scope) error {
+	out.Path = in.Path
+	out.Keys = *(*map[string]string)(unsafe.Pointer(&in.Keys))
+	return nil
+}
+
+// Convert_kubeone_VaultCredentials_To_v1beta1_VaultCredentials is an autogenerated conversion function.
+func Convert_kubeone_VaultCredentials_To_v1beta1_VaultCredentials(in *kubeone.VaultCredentials, out *VaultCredentials, s conversion.Scope) error {
+	return autoConvert_kubeone_VaultCredentials_To_v1beta1_VaultCredentials(in, out, s)
+}
+
+func autoConvert_v1beta1_Velero_To_kubeone_Velero(in *Velero, out *kubeone.Velero, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.Provider = kubeone.VeleroProvider(in.Provider)
+	out.Bucket = in.Bucket
+	out.Prefix = in.Prefix
+	out.Config = *(*map[string]string)(unsafe.Pointer(&in.Config))
+	out.Restic = in.Restic
+	if err := Convert_v1beta1_VeleroSchedule_To_kubeone_VeleroSchedule(&in.Schedule, &out.Schedule, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_Velero_To_kubeone_Velero is an autogenerated conversion function.
+func Convert_v1beta1_Velero_To_kubeone_Velero(in *Velero, out *kubeone.Velero, s conversion.Scope) error {
+	return autoConvert_v1beta1_Velero_To_kubeone_Velero(in, out, s)
+}
+
+func autoConvert_kubeone_Velero_To_v1beta1_Velero(in *kubeone.Velero, out *Velero, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.Provider = VeleroProvider(in.Provider)
+	out.Bucket = in.Bucket
+	out.Prefix = in.Prefix
+	out.Config = *(*map[string]string)(unsafe.Pointer(&in.Config))
+	out.Restic = in.Restic
+	if err := Convert_kubeone_VeleroSchedule_To_v1beta1_VeleroSchedule(&in.Schedule, &out.Schedule, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_kubeone_Velero_To_v1beta1_Velero is an autogenerated conversion function.
+func Convert_kubeone_Velero_To_v1beta1_Velero(in *kubeone.Velero, out *Velero, s conversion.Scope) error {
+	return autoConvert_kubeone_Velero_To_v1beta1_Velero(in, out, s)
+}
+
+func autoConvert_v1beta1_VeleroSchedule_To_kubeone_VeleroSchedule(in *VeleroSchedule, out *kubeone.VeleroSchedule, s conversion.Scope) error {
+	out.Disable = in.Disable
+	out.Cron = in.Cron
+	out.TTL = (*v1.Duration)(unsafe.Pointer(in.TTL))
+	return nil
+}
+
+// Convert_v1beta1_VeleroSchedule_To_kubeone_VeleroSchedule is an autogenerated conversion function.
+func Convert_v1beta1_VeleroSchedule_To_kubeone_VeleroSchedule(in *VeleroSchedule, out *kubeone.VeleroSchedule, s conversion.Scope) error {
+	return autoConvert_v1beta1_VeleroSchedule_To_kubeone_VeleroSchedule(in, out, s)
+}
+
+func autoConvert_kubeone_VeleroSchedule_To_v1beta1_VeleroSchedule(in *kubeone.VeleroSchedule, out *VeleroSchedule, s conversion.Scope) error {
+	out.Disable = in.Disable
+	out.Cron = in.Cron
+	out.TTL = (*v1.Duration)(unsafe.Pointer(in.TTL))
+	return nil
+}
+
+// Convert_kubeone_VeleroSchedule_To_v1beta1_VeleroSchedule is an autogenerated conversion function.
+func Convert_kubeone_VeleroSchedule_To_v1beta1_VeleroSchedule(in *kubeone.VeleroSchedule, out *VeleroSchedule, s conversion.Scope) error {
+	return autoConvert_kubeone_VeleroSchedule_To_v1beta1_VeleroSchedule(in, out, s)
+}
+
 func autoConvert_v1beta1_VersionConfig_To_kubeone_VersionConfig(in *VersionConfig, out *kubeone.VersionConfig, s conversion.Scope) error {
 	out.Kubernetes = in.Kubernetes
 	return nil
@@ -1883,7 +3667,56 @@ func Convert_kubeone_VersionConfig_To_v1beta1_VersionConfig(in *kubeone.VersionC
 	return autoConvert_kubeone_VersionConfig_To_v1beta1_VersionConfig(in, out, s)
 }
 
+func autoConvert_v1beta1_VsphereCSIConfig_To_kubeone_VsphereCSIConfig(in *VsphereCSIConfig, out *kubeone.VsphereCSIConfig, s conversion.Scope) error {
+	out.Datacenters = *(*[]string)(unsafe.Pointer(&in.Datacenters))
+	out.InsecureFlag = in.InsecureFlag
+	out.StorageClass = (*kubeone.VsphereCSIStorageClass)(unsafe.Pointer(in.StorageClass))
+	out.Snapshots = in.Snapshots
+	return nil
+}
+
+// Convert_v1beta1_VsphereCSIConfig_To_kubeone_VsphereCSIConfig is an autogenerated conversion function.
+func Convert_v1beta1_VsphereCSIConfig_To_kubeone_VsphereCSIConfig(in *VsphereCSIConfig, out *kubeone.VsphereCSIConfig, s conversion.Scope) error {
+	return autoConvert_v1beta1_VsphereCSIConfig_To_kubeone_VsphereCSIConfig(in, out, s)
+}
+
+func autoConvert_kubeone_VsphereCSIConfig_To_v1beta1_VsphereCSIConfig(in *kubeone.VsphereCSIConfig, out *VsphereCSIConfig, s conversion.Scope) error {
+	out.Datacenters = *(*[]string)(unsafe.Pointer(&in.Datacenters))
+	out.InsecureFlag = in.InsecureFlag
+	out.StorageClass = (*VsphereCSIStorageClass)(unsafe.Pointer(in.StorageClass))
+	out.Snapshots = in.Snapshots
+	return nil
+}
+
+// Convert_kubeone_VsphereCSIConfig_To_v1beta1_VsphereCSIConfig is an autogenerated conversion function.
+func Convert_kubeone_VsphereCSIConfig_To_v1beta1_VsphereCSIConfig(in *kubeone.VsphereCSIConfig, out *VsphereCSIConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_VsphereCSIConfig_To_v1beta1_VsphereCSIConfig(in, out, s)
+}
+
+func autoConvert_v1beta1_VsphereCSIStorageClass_To_kubeone_VsphereCSIStorageClass(in *VsphereCSIStorageClass, out *kubeone.VsphereCSIStorageClass, s conversion.Scope) error {
+	out.DatastoreURL = in.DatastoreURL
+	out.StoragePolicyName = in.StoragePolicyName
+	return nil
+}
+
+// Convert_v1beta1_VsphereCSIStorageClass_To_kubeone_VsphereCSIStorageClass is an autogenerated conversion function.
+func Convert_v1beta1_VsphereCSIStorageClass_To_kubeone_VsphereCSIStorageClass(in *VsphereCSIStorageClass, out *kubeone.VsphereCSIStorageClass, s conversion.Scope) error {
+	return autoConvert_v1beta1_VsphereCSIStorageClass_To_kubeone_VsphereCSIStorageClass(in, out, s)
+}
+
+func autoConvert_kubeone_VsphereCSIStorageClass_To_v1beta1_VsphereCSIStorageClass(in *kubeone.VsphereCSIStorageClass, out *VsphereCSIStorageClass, s conversion.Scope) error {
+	out.DatastoreURL = in.DatastoreURL
+	out.StoragePolicyName = in.StoragePolicyName
+	return nil
+}
+
+// Convert_kubeone_VsphereCSIStorageClass_To_v1beta1_VsphereCSIStorageClass is an autogenerated conversion function.
+func Convert_kubeone_VsphereCSIStorageClass_To_v1beta1_VsphereCSIStorageClass(in *kubeone.VsphereCSIStorageClass, out *VsphereCSIStorageClass, s conversion.Scope) error {
+	return autoConvert_kubeone_VsphereCSIStorageClass_To_v1beta1_VsphereCSIStorageClass(in, out, s)
+}
+
 func autoConvert_v1beta1_VsphereSpec_To_kubeone_VsphereSpec(in *VsphereSpec, out *kubeone.VsphereSpec, s conversion.Scope) error {
+	out.CSI = (*kubeone.VsphereCSIConfig)(unsafe.Pointer(in.CSI))
 	return nil
 }
 
@@ -1893,6 +3726,7 @@ func Convert_v1beta1_VsphereSpec_To_kubeone_VsphereSpec(in *VsphereSpec, out *ku
 }
 
 func autoConvert_kubeone_VsphereSpec_To_v1beta1_VsphereSpec(in *kubeone.VsphereSpec, out *VsphereSpec, s conversion.Scope) error {
+	out.CSI = (*VsphereCSIConfig)(unsafe.Pointer(in.CSI))
 	return nil
 }
 
@@ -1920,3 +3754,25 @@ func autoConvert_kubeone_WeaveNetSpec_To_v1beta1_WeaveNetSpec(in *kubeone.WeaveN
 func Convert_kubeone_WeaveNetSpec_To_v1beta1_WeaveNetSpec(in *kubeone.WeaveNetSpec, out *WeaveNetSpec, s conversion.Scope) error {
 	return autoConvert_kubeone_WeaveNetSpec_To_v1beta1_WeaveNetSpec(in, out, s)
 }
+
+func autoConvert_v1beta1_WorkersetAutoscaling_To_kubeone_WorkersetAutoscaling(in *WorkersetAutoscaling, out *kubeone.WorkersetAutoscaling, s conversion.Scope) error {
+	out.MinReplicas = in.MinReplicas
+	out.MaxReplicas = in.MaxReplicas
+	return nil
+}
+
+// Convert_v1beta1_WorkersetAutoscaling_To_kubeone_WorkersetAutoscaling is an autogenerated conversion function.
+func Convert_v1beta1_WorkersetAutoscaling_To_kubeone_WorkersetAutoscaling(in *WorkersetAutoscaling, out *kubeone.WorkersetAutoscaling, s conversion.Scope) error {
+	return autoConvert_v1beta1_WorkersetAutoscaling_To_kubeone_WorkersetAutoscaling(in, out, s)
+}
+
+func autoConvert_kubeone_WorkersetAutoscaling_To_v1beta1_WorkersetAutoscaling(in *kubeone.WorkersetAutoscaling, out *WorkersetAutoscaling, s conversion.Scope) error {
+	out.MinReplicas = in.MinReplicas
+	out.MaxReplicas = in.MaxReplicas
+	return nil
+}
+
+// Convert_kubeone_WorkersetAutoscaling_To_v1beta1_WorkersetAutoscaling is an autogenerated conversion function.
+func Convert_kubeone_WorkersetAutoscaling_To_v1beta1_WorkersetAutoscaling(in *kubeone.WorkersetAutoscaling, out *WorkersetAutoscaling, s conversion.Scope) error {
+	return autoConvert_kubeone_WorkersetAutoscaling_To_v1beta1_WorkersetAutoscaling(in, out, s)
+}
@@ -24,7 +24,9 @@ package v1beta1
 import (
 	json "encoding/json"
 
-	v1 "k8s.io/api/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -49,9 +51,46 @@ func (in *APIEndpoint) DeepCopy() *APIEndpoint {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSEBSCSIConfig) DeepCopyInto(out *AWSEBSCSIConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSEBSCSIConfig.
+func (in *AWSEBSCSIConfig) DeepCopy() *AWSEBSCSIConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSEBSCSIConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSKMSKeyProvider) DeepCopyInto(out *AWSKMSKeyProvider) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSKMSKeyProvider.
+func (in *AWSKMSKeyProvider) DeepCopy() *AWSKMSKeyProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSKMSKeyProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSSpec) DeepCopyInto(out *AWSSpec) {
 	*out = *in
+	if in.EBSCSI != nil {
+		in, out := &in.EBSCSI, &out.EBSCSI
+		*out = new(AWSEBSCSIConfig)
+		**out = **in
+	}
 	return
 }
 
@@ -129,6 +168,13 @@ func (in *AssetConfiguration) DeepCopyInto(out *AssetConfiguration) {
 	out.CNI = in.CNI
 	out.NodeBinaries = in.NodeBinaries
 	out.Kubectl = in.Kubectl
+	out.Kubeadm = in.Kubeadm
+	out.Kubelet = in.Kubelet
+	if in.SignatureVerification != nil {
+		in, out := &in.SignatureVerification, &out.SignatureVerification
+		*out = new(SignatureVerification)
+		**out = **in
+	}
 	return
 }
 
@@ -142,9 +188,66 @@ func (in *AssetConfiguration) DeepCopy() *AssetConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureCloudConfig) DeepCopyInto(out *AzureCloudConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureCloudConfig.
+func (in *AzureCloudConfig) DeepCopy() *AzureCloudConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureCloudConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureComponent) DeepCopyInto(out *AzureComponent) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureComponent.
+func (in *AzureComponent) DeepCopy() *AzureComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureSpec) DeepCopyInto(out *AzureSpec) {
 	*out = *in
+	if in.CloudConfig != nil {
+		in, out := &in.CloudConfig, &out.CloudConfig
+		*out = new(AzureCloudConfig)
+		**out = **in
+	}
+	if in.CCM != nil {
+		in, out := &in.CCM, &out.CCM
+		*out = new(AzureComponent)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DiskCSI != nil {
+		in, out := &in.DiskCSI, &out.DiskCSI
+		*out = new(AzureComponent)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FileCSI != nil {
+		in, out := &in.FileCSI, &out.FileCSI
+		*out = new(AzureComponent)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -158,6 +261,22 @@ func (in *AzureSpec) DeepCopy() *AzureSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BastionHost) DeepCopyInto(out *BastionHost) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BastionHost.
+func (in *BastionHost) DeepCopy() *BastionHost {
+	if in == nil {
+		return nil
+	}
+	out := new(BastionHost)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BinaryAsset) DeepCopyInto(out *BinaryAsset) {
 	*out = *in
@@ -195,7 +314,7 @@ func (in *CNI) DeepCopyInto(out *CNI) {
 	if in.External != nil {
 		in, out := &in.External, &out.External
 		*out = new(ExternalCNISpec)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
@@ -226,6 +345,74 @@ func (in *CanalSpec) DeepCopy() *CanalSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManager) DeepCopyInto(out *CertManager) {
+	*out = *in
+	if in.ClusterIssuer != nil {
+		in, out := &in.ClusterIssuer, &out.ClusterIssuer
+		*out = new(CertManagerClusterIssuer)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManager.
+func (in *CertManager) DeepCopy() *CertManager {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManager)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerClusterIssuer) DeepCopyInto(out *CertManagerClusterIssuer) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerClusterIssuer.
+func (in *CertManagerClusterIssuer) DeepCopy() *CertManagerClusterIssuer {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerClusterIssuer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateAuthorityConfig) DeepCopyInto(out *CertificateAuthorityConfig) {
+	*out = *in
+	if in.CertificateValidity != nil {
+		in, out := &in.CertificateValidity, &out.CertificateValidity
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(ExternalCAConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateAuthorityConfig.
+func (in *CertificateAuthorityConfig) DeepCopy() *CertificateAuthorityConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateAuthorityConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CiliumSpec) DeepCopyInto(out *CiliumSpec) {
 	*out = *in
@@ -248,12 +435,12 @@ func (in *CloudProviderSpec) DeepCopyInto(out *CloudProviderSpec) {
 	if in.AWS != nil {
 		in, out := &in.AWS, &out.AWS
 		*out = new(AWSSpec)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Azure != nil {
 		in, out := &in.Azure, &out.Azure
 		*out = new(AzureSpec)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.DigitalOcean != nil {
 		in, out := &in.DigitalOcean, &out.DigitalOcean
@@ -273,7 +460,7 @@ func (in *CloudProviderSpec) DeepCopyInto(out *CloudProviderSpec) {
 	if in.Openstack != nil {
 		in, out := &in.Openstack, &out.Openstack
 		*out = new(OpenstackSpec)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Packet != nil {
 		in, out := &in.Packet, &out.Packet
@@ -283,13 +470,18 @@ func (in *CloudProviderSpec) DeepCopyInto(out *CloudProviderSpec) {
 	if in.Vsphere != nil {
 		in, out := &in.Vsphere, &out.Vsphere
 		*out = new(VsphereSpec)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.None != nil {
 		in, out := &in.None, &out.None
 		*out = new(NoneSpec)
 		**out = **in
 	}
+	if in.CredentialsVault != nil {
+		in, out := &in.CredentialsVault, &out.CredentialsVault
+		*out = new(VaultCredentials)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -303,6 +495,32 @@ func (in *CloudProviderSpec) DeepCopy() *CloudProviderSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterAutoscaler) DeepCopyInto(out *ClusterAutoscaler) {
+	*out = *in
+	if in.ScaleDownUnneededTime != nil {
+		in, out := &in.ScaleDownUnneededTime, &out.ScaleDownUnneededTime
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ScaleDownDelayAfterAdd != nil {
+		in, out := &in.ScaleDownDelayAfterAdd, &out.ScaleDownDelayAfterAdd
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAutoscaler.
+func (in *ClusterAutoscaler) DeepCopy() *ClusterAutoscaler {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterAutoscaler)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterNetworkConfig) DeepCopyInto(out *ClusterNetworkConfig) {
 	*out = *in
@@ -329,6 +547,22 @@ func (in *ClusterNetworkConfig) DeepCopy() *ClusterNetworkConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSizeConfig) DeepCopyInto(out *ClusterSizeConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSizeConfig.
+func (in *ClusterSizeConfig) DeepCopy() *ClusterSizeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSizeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContainerRuntimeConfig) DeepCopyInto(out *ContainerRuntimeConfig) {
 	*out = *in
@@ -340,7 +574,7 @@ func (in *ContainerRuntimeConfig) DeepCopyInto(out *ContainerRuntimeConfig) {
 	if in.Containerd != nil {
 		in, out := &in.Containerd, &out.Containerd
 		*out = new(ContainerRuntimeContainerd)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
@@ -358,6 +592,16 @@ func (in *ContainerRuntimeConfig) DeepCopy() *ContainerRuntimeConfig {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContainerRuntimeContainerd) DeepCopyInto(out *ContainerRuntimeContainerd) {
 	*out = *in
+	if in.SystemdCgroup != nil {
+		in, out := &in.SystemdCgroup, &out.SystemdCgroup
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NvidiaRuntime != nil {
+		in, out := &in.NvidiaRuntime, &out.NvidiaRuntime
+		*out = new(ContainerdNvidiaRuntime)
+		**out = **in
+	}
 	return
 }
 
@@ -387,6 +631,22 @@ func (in *ContainerRuntimeDocker) DeepCopy() *ContainerRuntimeDocker {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerdNvidiaRuntime) DeepCopyInto(out *ContainerdNvidiaRuntime) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerdNvidiaRuntime.
+func (in *ContainerdNvidiaRuntime) DeepCopy() *ContainerdNvidiaRuntime {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerdNvidiaRuntime)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControlPlaneConfig) DeepCopyInto(out *ControlPlaneConfig) {
 	*out = *in
@@ -397,6 +657,16 @@ func (in *ControlPlaneConfig) DeepCopyInto(out *ControlPlaneConfig) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.StaticPods != nil {
+		in, out := &in.StaticPods, &out.StaticPods
+		*out = make([]StaticPod, len(*in))
+		copy(*out, *in)
+	}
+	if in.HostDiscovery != nil {
+		in, out := &in.HostDiscovery, &out.HostDiscovery
+		*out = new(HostDiscoveryConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -472,6 +742,11 @@ func (in *DynamicWorkerConfig) DeepCopyInto(out *DynamicWorkerConfig) {
 		**out = **in
 	}
 	in.Config.DeepCopyInto(&out.Config)
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(WorkersetAutoscaling)
+		**out = **in
+	}
 	return
 }
 
@@ -501,9 +776,87 @@ func (in *EncryptionProviders) DeepCopy() *EncryptionProviders {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalCAConfig) DeepCopyInto(out *ExternalCAConfig) {
+	*out = *in
+	if in.Signer != nil {
+		in, out := &in.Signer, &out.Signer
+		*out = new(ExternalCASigner)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KeyProvider != nil {
+		in, out := &in.KeyProvider, &out.KeyProvider
+		*out = new(ExternalCAKeyProvider)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalCAConfig.
+func (in *ExternalCAConfig) DeepCopy() *ExternalCAConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalCAConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalCAKeyProvider) DeepCopyInto(out *ExternalCAKeyProvider) {
+	*out = *in
+	if in.PKCS11 != nil {
+		in, out := &in.PKCS11, &out.PKCS11
+		*out = new(PKCS11KeyProvider)
+		**out = **in
+	}
+	if in.AWSKMS != nil {
+		in, out := &in.AWSKMS, &out.AWSKMS
+		*out = new(AWSKMSKeyProvider)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalCAKeyProvider.
+func (in *ExternalCAKeyProvider) DeepCopy() *ExternalCAKeyProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalCAKeyProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalCASigner) DeepCopyInto(out *ExternalCASigner) {
+	*out = *in
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalCASigner.
+func (in *ExternalCASigner) DeepCopy() *ExternalCASigner {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalCASigner)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalCNISpec) DeepCopyInto(out *ExternalCNISpec) {
 	*out = *in
+	if in.HelmChart != nil {
+		in, out := &in.HelmChart, &out.HelmChart
+		*out = new(HelmChart)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -548,7 +901,7 @@ func (in *Features) DeepCopyInto(out *Features) {
 	if in.MetricsServer != nil {
 		in, out := &in.MetricsServer, &out.MetricsServer
 		*out = new(MetricsServer)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.OpenIDConnect != nil {
 		in, out := &in.OpenIDConnect, &out.OpenIDConnect
@@ -560,6 +913,41 @@ func (in *Features) DeepCopyInto(out *Features) {
 		*out = new(EncryptionProviders)
 		**out = **in
 	}
+	if in.NvidiaGPU != nil {
+		in, out := &in.NvidiaGPU, &out.NvidiaGPU
+		*out = new(NvidiaGPU)
+		**out = **in
+	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(Monitoring)
+		**out = **in
+	}
+	if in.ClusterAutoscaler != nil {
+		in, out := &in.ClusterAutoscaler, &out.ClusterAutoscaler
+		*out = new(ClusterAutoscaler)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(CertManager)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IngressController != nil {
+		in, out := &in.IngressController, &out.IngressController
+		*out = new(IngressController)
+		**out = **in
+	}
+	if in.GitOps != nil {
+		in, out := &in.GitOps, &out.GitOps
+		*out = new(GitOps)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Velero != nil {
+		in, out := &in.Velero, &out.Velero
+		*out = new(Velero)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -574,17 +962,120 @@ func (in *Features) DeepCopy() *Features {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GCESpec) DeepCopyInto(out *GCESpec) {
+func (in *FirewallConfig) DeepCopyInto(out *FirewallConfig) {
 	*out = *in
+	if in.NodeCIDRs != nil {
+		in, out := &in.NodeCIDRs, &out.NodeCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCESpec.
-func (in *GCESpec) DeepCopy() *GCESpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirewallConfig.
+func (in *FirewallConfig) DeepCopy() *FirewallConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(GCESpec)
+	out := new(FirewallConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GCESpec) DeepCopyInto(out *GCESpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCESpec.
+func (in *GCESpec) DeepCopy() *GCESpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GCESpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitOps) DeepCopyInto(out *GitOps) {
+	*out = *in
+	in.Repository.DeepCopyInto(&out.Repository)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOps.
+func (in *GitOps) DeepCopy() *GitOps {
+	if in == nil {
+		return nil
+	}
+	out := new(GitOps)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitOpsRepository) DeepCopyInto(out *GitOpsRepository) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsRepository.
+func (in *GitOpsRepository) DeepCopy() *GitOpsRepository {
+	if in == nil {
+		return nil
+	}
+	out := new(GitOpsRepository)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardeningConfig) DeepCopyInto(out *HardeningConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardeningConfig.
+func (in *HardeningConfig) DeepCopy() *HardeningConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HardeningConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelmChart) DeepCopyInto(out *HelmChart) {
+	*out = *in
+	if in.ValuesFiles != nil {
+		in, out := &in.ValuesFiles, &out.ValuesFiles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmChart.
+func (in *HelmChart) DeepCopy() *HelmChart {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmChart)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -608,13 +1099,40 @@ func (in *HetznerSpec) DeepCopy() *HetznerSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostConfig) DeepCopyInto(out *HostConfig) {
 	*out = *in
+	if in.BastionHosts != nil {
+		in, out := &in.BastionHosts, &out.BastionHosts
+		*out = make([]BastionHost, len(*in))
+		copy(*out, *in)
+	}
 	if in.Taints != nil {
 		in, out := &in.Taints, &out.Taints
-		*out = make([]v1.Taint, len(*in))
+		*out = make([]corev1.Taint, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodeDrain != nil {
+		in, out := &in.NodeDrain, &out.NodeDrain
+		*out = new(NodeDrainConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IgnorePreflightErrors != nil {
+		in, out := &in.IgnorePreflightErrors, &out.IgnorePreflightErrors
+		*out = new(IgnorePreflightErrorsConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Kubelet != nil {
+		in, out := &in.Kubelet, &out.Kubelet
+		*out = new(KubeletConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -628,6 +1146,51 @@ func (in *HostConfig) DeepCopy() *HostConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostDiscoveryConfig) DeepCopyInto(out *HostDiscoveryConfig) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Template.DeepCopyInto(&out.Template)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostDiscoveryConfig.
+func (in *HostDiscoveryConfig) DeepCopy() *HostDiscoveryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HostDiscoveryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostRebootConfig) DeepCopyInto(out *HostRebootConfig) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRebootConfig.
+func (in *HostRebootConfig) DeepCopy() *HostRebootConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HostRebootConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IPTables) DeepCopyInto(out *IPTables) {
 	*out = *in
@@ -668,6 +1231,32 @@ func (in *IPVSConfig) DeepCopy() *IPVSConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IgnorePreflightErrorsConfig) DeepCopyInto(out *IgnorePreflightErrorsConfig) {
+	*out = *in
+	if in.Install != nil {
+		in, out := &in.Install, &out.Install
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IgnorePreflightErrorsConfig.
+func (in *IgnorePreflightErrorsConfig) DeepCopy() *IgnorePreflightErrorsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(IgnorePreflightErrorsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImageAsset) DeepCopyInto(out *ImageAsset) {
 	*out = *in
@@ -684,6 +1273,22 @@ func (in *ImageAsset) DeepCopy() *ImageAsset {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressController) DeepCopyInto(out *IngressController) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressController.
+func (in *IngressController) DeepCopy() *IngressController {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressController)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubeOneCluster) DeepCopyInto(out *KubeOneCluster) {
 	*out = *in
@@ -706,7 +1311,7 @@ func (in *KubeOneCluster) DeepCopyInto(out *KubeOneCluster) {
 	if in.MachineController != nil {
 		in, out := &in.MachineController, &out.MachineController
 		*out = new(MachineControllerConfig)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	in.Features.DeepCopyInto(&out.Features)
 	if in.Addons != nil {
@@ -719,12 +1324,82 @@ func (in *KubeOneCluster) DeepCopyInto(out *KubeOneCluster) {
 		*out = new(SystemPackages)
 		**out = **in
 	}
-	out.AssetConfiguration = in.AssetConfiguration
+	in.AssetConfiguration.DeepCopyInto(&out.AssetConfiguration)
 	if in.RegistryConfiguration != nil {
 		in, out := &in.RegistryConfiguration, &out.RegistryConfiguration
 		*out = new(RegistryConfiguration)
 		**out = **in
 	}
+	if in.RBAC != nil {
+		in, out := &in.RBAC, &out.RBAC
+		*out = new(RBACConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeDrain != nil {
+		in, out := &in.NodeDrain, &out.NodeDrain
+		*out = new(NodeDrainConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IgnorePreflightErrors != nil {
+		in, out := &in.IgnorePreflightErrors, &out.IgnorePreflightErrors
+		*out = new(IgnorePreflightErrorsConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ManifestPatches != nil {
+		in, out := &in.ManifestPatches, &out.ManifestPatches
+		*out = make([]ManifestPatch, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterSize != nil {
+		in, out := &in.ClusterSize, &out.ClusterSize
+		*out = new(ClusterSizeConfig)
+		**out = **in
+	}
+	if in.Preflight != nil {
+		in, out := &in.Preflight, &out.Preflight
+		*out = new(PreflightConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostReboot != nil {
+		in, out := &in.HostReboot, &out.HostReboot
+		*out = new(HostRebootConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OSTuning != nil {
+		in, out := &in.OSTuning, &out.OSTuning
+		*out = new(OSTuningConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TimeSync != nil {
+		in, out := &in.TimeSync, &out.TimeSync
+		*out = new(TimeSyncConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hardening != nil {
+		in, out := &in.Hardening, &out.Hardening
+		*out = new(HardeningConfig)
+		**out = **in
+	}
+	if in.SecurityModules != nil {
+		in, out := &in.SecurityModules, &out.SecurityModules
+		*out = new(SecurityModulesConfig)
+		**out = **in
+	}
+	if in.Firewall != nil {
+		in, out := &in.Firewall, &out.Firewall
+		*out = new(FirewallConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CertificateAuthority != nil {
+		in, out := &in.CertificateAuthority, &out.CertificateAuthority
+		*out = new(CertificateAuthorityConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeNaming != nil {
+		in, out := &in.NodeNaming, &out.NodeNaming
+		*out = new(NodeNamingConfig)
+		**out = **in
+	}
 	return
 }
 
@@ -772,9 +1447,77 @@ func (in *KubeProxyConfig) DeepCopy() *KubeProxyConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeletConfig) DeepCopyInto(out *KubeletConfig) {
+	*out = *in
+	if in.MaxPods != nil {
+		in, out := &in.MaxPods, &out.MaxPods
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SystemReserved != nil {
+		in, out := &in.SystemReserved, &out.SystemReserved
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.KubeReserved != nil {
+		in, out := &in.KubeReserved, &out.KubeReserved
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.EvictionHard != nil {
+		in, out := &in.EvictionHard, &out.EvictionHard
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeletConfig.
+func (in *KubeletConfig) DeepCopy() *KubeletConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeletConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachineControllerConfig) DeepCopyInto(out *MachineControllerConfig) {
 	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraFlags != nil {
+		in, out := &in.ExtraFlags, &out.ExtraFlags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -788,9 +1531,52 @@ func (in *MachineControllerConfig) DeepCopy() *MachineControllerConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManifestPatch) DeepCopyInto(out *ManifestPatch) {
+	*out = *in
+	out.Target = in.Target
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestPatch.
+func (in *ManifestPatch) DeepCopy() *ManifestPatch {
+	if in == nil {
+		return nil
+	}
+	out := new(ManifestPatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManifestPatchTarget) DeepCopyInto(out *ManifestPatchTarget) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestPatchTarget.
+func (in *ManifestPatchTarget) DeepCopy() *ManifestPatchTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ManifestPatchTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetricsServer) DeepCopyInto(out *MetricsServer) {
 	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KubeletInsecureTLS != nil {
+		in, out := &in.KubeletInsecureTLS, &out.KubeletInsecureTLS
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -804,6 +1590,79 @@ func (in *MetricsServer) DeepCopy() *MetricsServer {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Monitoring) DeepCopyInto(out *Monitoring) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Monitoring.
+func (in *Monitoring) DeepCopy() *Monitoring {
+	if in == nil {
+		return nil
+	}
+	out := new(Monitoring)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeDrainConfig) DeepCopyInto(out *NodeDrainConfig) {
+	*out = *in
+	if in.GracePeriodSeconds != nil {
+		in, out := &in.GracePeriodSeconds, &out.GracePeriodSeconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.DeleteEmptyDirData != nil {
+		in, out := &in.DeleteEmptyDirData, &out.DeleteEmptyDirData
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SkipWaitForDeleteTimeoutSeconds != nil {
+		in, out := &in.SkipWaitForDeleteTimeoutSeconds, &out.SkipWaitForDeleteTimeoutSeconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.IgnorePodDisruptionBudgets != nil {
+		in, out := &in.IgnorePodDisruptionBudgets, &out.IgnorePodDisruptionBudgets
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeDrainConfig.
+func (in *NodeDrainConfig) DeepCopy() *NodeDrainConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeDrainConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeNamingConfig) DeepCopyInto(out *NodeNamingConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeNamingConfig.
+func (in *NodeNamingConfig) DeepCopy() *NodeNamingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeNamingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NoneSpec) DeepCopyInto(out *NoneSpec) {
 	*out = *in
@@ -820,6 +1679,69 @@ func (in *NoneSpec) DeepCopy() *NoneSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NvidiaGPU) DeepCopyInto(out *NvidiaGPU) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NvidiaGPU.
+func (in *NvidiaGPU) DeepCopy() *NvidiaGPU {
+	if in == nil {
+		return nil
+	}
+	out := new(NvidiaGPU)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSTuning) DeepCopyInto(out *OSTuning) {
+	*out = *in
+	if in.KernelModules != nil {
+		in, out := &in.KernelModules, &out.KernelModules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Sysctls != nil {
+		in, out := &in.Sysctls, &out.Sysctls
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSTuning.
+func (in *OSTuning) DeepCopy() *OSTuning {
+	if in == nil {
+		return nil
+	}
+	out := new(OSTuning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSTuningConfig) DeepCopyInto(out *OSTuningConfig) {
+	*out = *in
+	in.All.DeepCopyInto(&out.All)
+	in.ControlPlane.DeepCopyInto(&out.ControlPlane)
+	in.StaticWorkers.DeepCopyInto(&out.StaticWorkers)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSTuningConfig.
+func (in *OSTuningConfig) DeepCopy() *OSTuningConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OSTuningConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenIDConnect) DeepCopyInto(out *OpenIDConnect) {
 	*out = *in
@@ -848,23 +1770,87 @@ func (in *OpenIDConnectConfig) DeepCopy() *OpenIDConnectConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenIDConnectConfig)
+	out := new(OpenIDConnectConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenstackComponent) DeepCopyInto(out *OpenstackComponent) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CloudConfigSections != nil {
+		in, out := &in.CloudConfigSections, &out.CloudConfigSections
+		*out = make(map[string]map[string]string, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenstackComponent.
+func (in *OpenstackComponent) DeepCopy() *OpenstackComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenstackComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenstackSpec) DeepCopyInto(out *OpenstackSpec) {
+	*out = *in
+	if in.CCM != nil {
+		in, out := &in.CCM, &out.CCM
+		*out = new(OpenstackComponent)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CinderCSI != nil {
+		in, out := &in.CinderCSI, &out.CinderCSI
+		*out = new(OpenstackComponent)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenstackSpec.
+func (in *OpenstackSpec) DeepCopy() *OpenstackSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenstackSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenstackSpec) DeepCopyInto(out *OpenstackSpec) {
+func (in *PKCS11KeyProvider) DeepCopyInto(out *PKCS11KeyProvider) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenstackSpec.
-func (in *OpenstackSpec) DeepCopy() *OpenstackSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PKCS11KeyProvider.
+func (in *PKCS11KeyProvider) DeepCopy() *PKCS11KeyProvider {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenstackSpec)
+	out := new(PKCS11KeyProvider)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -950,6 +1936,32 @@ func (in *PodSecurityPolicy) DeepCopy() *PodSecurityPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreflightConfig) DeepCopyInto(out *PreflightConfig) {
+	*out = *in
+	if in.Skip != nil {
+		in, out := &in.Skip, &out.Skip
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.WarnOnly != nil {
+		in, out := &in.WarnOnly, &out.WarnOnly
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreflightConfig.
+func (in *PreflightConfig) DeepCopy() *PreflightConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PreflightConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProviderSpec) DeepCopyInto(out *ProviderSpec) {
 	*out = *in
@@ -981,7 +1993,7 @@ func (in *ProviderSpec) DeepCopyInto(out *ProviderSpec) {
 	}
 	if in.Taints != nil {
 		in, out := &in.Taints, &out.Taints
-		*out = make([]v1.Taint, len(*in))
+		*out = make([]corev1.Taint, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -1052,6 +2064,92 @@ func (in *ProxyConfig) DeepCopy() *ProxyConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBACClusterRole) DeepCopyInto(out *RBACClusterRole) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBACClusterRole.
+func (in *RBACClusterRole) DeepCopy() *RBACClusterRole {
+	if in == nil {
+		return nil
+	}
+	out := new(RBACClusterRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBACConfig) DeepCopyInto(out *RBACConfig) {
+	*out = *in
+	if in.ClusterRoles != nil {
+		in, out := &in.ClusterRoles, &out.ClusterRoles
+		*out = make([]RBACClusterRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ClusterRoleBindings != nil {
+		in, out := &in.ClusterRoleBindings, &out.ClusterRoleBindings
+		*out = make([]RBACRoleBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RoleBindings != nil {
+		in, out := &in.RoleBindings, &out.RoleBindings
+		*out = make([]RBACRoleBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBACConfig.
+func (in *RBACConfig) DeepCopy() *RBACConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RBACConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBACRoleBinding) DeepCopyInto(out *RBACRoleBinding) {
+	*out = *in
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBACRoleBinding.
+func (in *RBACRoleBinding) DeepCopy() *RBACRoleBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(RBACRoleBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RegistryConfiguration) DeepCopyInto(out *RegistryConfiguration) {
 	*out = *in
@@ -1068,6 +2166,38 @@ func (in *RegistryConfiguration) DeepCopy() *RegistryConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityModulesConfig) DeepCopyInto(out *SecurityModulesConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityModulesConfig.
+func (in *SecurityModulesConfig) DeepCopy() *SecurityModulesConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityModulesConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SignatureVerification) DeepCopyInto(out *SignatureVerification) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SignatureVerification.
+func (in *SignatureVerification) DeepCopy() *SignatureVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(SignatureVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StaticAuditLog) DeepCopyInto(out *StaticAuditLog) {
 	*out = *in
@@ -1101,6 +2231,46 @@ func (in *StaticAuditLogConfig) DeepCopy() *StaticAuditLogConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StaticPod) DeepCopyInto(out *StaticPod) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StaticPod.
+func (in *StaticPod) DeepCopy() *StaticPod {
+	if in == nil {
+		return nil
+	}
+	out := new(StaticPod)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StaticWorkerGroup) DeepCopyInto(out *StaticWorkerGroup) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]HostConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StaticWorkerGroup.
+func (in *StaticWorkerGroup) DeepCopy() *StaticWorkerGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(StaticWorkerGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StaticWorkersConfig) DeepCopyInto(out *StaticWorkersConfig) {
 	*out = *in
@@ -1111,6 +2281,23 @@ func (in *StaticWorkersConfig) DeepCopyInto(out *StaticWorkersConfig) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]StaticWorkerGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StaticPods != nil {
+		in, out := &in.StaticPods, &out.StaticPods
+		*out = make([]StaticPod, len(*in))
+		copy(*out, *in)
+	}
+	if in.HostDiscovery != nil {
+		in, out := &in.HostDiscovery, &out.HostDiscovery
+		*out = new(HostDiscoveryConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1140,6 +2327,100 @@ func (in *SystemPackages) DeepCopy() *SystemPackages {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeSyncConfig) DeepCopyInto(out *TimeSyncConfig) {
+	*out = *in
+	if in.NTPServers != nil {
+		in, out := &in.NTPServers, &out.NTPServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxClockSkew != nil {
+		in, out := &in.MaxClockSkew, &out.MaxClockSkew
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeSyncConfig.
+func (in *TimeSyncConfig) DeepCopy() *TimeSyncConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeSyncConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultCredentials) DeepCopyInto(out *VaultCredentials) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultCredentials.
+func (in *VaultCredentials) DeepCopy() *VaultCredentials {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultCredentials)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Velero) DeepCopyInto(out *Velero) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Schedule.DeepCopyInto(&out.Schedule)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Velero.
+func (in *Velero) DeepCopy() *Velero {
+	if in == nil {
+		return nil
+	}
+	out := new(Velero)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VeleroSchedule) DeepCopyInto(out *VeleroSchedule) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VeleroSchedule.
+func (in *VeleroSchedule) DeepCopy() *VeleroSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(VeleroSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VersionConfig) DeepCopyInto(out *VersionConfig) {
 	*out = *in
@@ -1156,9 +2437,56 @@ func (in *VersionConfig) DeepCopy() *VersionConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VsphereCSIConfig) DeepCopyInto(out *VsphereCSIConfig) {
+	*out = *in
+	if in.Datacenters != nil {
+		in, out := &in.Datacenters, &out.Datacenters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StorageClass != nil {
+		in, out := &in.StorageClass, &out.StorageClass
+		*out = new(VsphereCSIStorageClass)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VsphereCSIConfig.
+func (in *VsphereCSIConfig) DeepCopy() *VsphereCSIConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(VsphereCSIConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VsphereCSIStorageClass) DeepCopyInto(out *VsphereCSIStorageClass) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VsphereCSIStorageClass.
+func (in *VsphereCSIStorageClass) DeepCopy() *VsphereCSIStorageClass {
+	if in == nil {
+		return nil
+	}
+	out := new(VsphereCSIStorageClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VsphereSpec) DeepCopyInto(out *VsphereSpec) {
 	*out = *in
+	if in.CSI != nil {
+		in, out := &in.CSI, &out.CSI
+		*out = new(VsphereCSIConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1187,3 +2515,19 @@ func (in *WeaveNetSpec) DeepCopy() *WeaveNetSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkersetAutoscaling) DeepCopyInto(out *WorkersetAutoscaling) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkersetAutoscaling.
+func (in *WorkersetAutoscaling) DeepCopy() *WorkersetAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkersetAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
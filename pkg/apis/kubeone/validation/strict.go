@@ -0,0 +1,168 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+
+	"k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/secretstore"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidateKubeOneClusterStrict runs the checks used by the
+// `kubeone config validate --strict` command on top of ValidateKubeOneCluster.
+// Those checks catch problems that otherwise show up only in the middle of
+// the apply, such as overlapping networks and missing local files. Relative
+// paths of the files referenced in the manifest are resolved against the
+// manifest file path.
+func ValidateKubeOneClusterStrict(c kubeone.KubeOneCluster, manifestFilePath string) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	allErrs = append(allErrs, ValidateNetworkOverlaps(c, field.NewPath("clusterNetwork"))...)
+	allErrs = append(allErrs, ValidateCloudProviderRequirements(c.CloudProvider, field.NewPath("cloudProvider"))...)
+	allErrs = append(allErrs, ValidateReferencedFiles(c, manifestFilePath)...)
+
+	return allErrs
+}
+
+// ValidateNetworkOverlaps validates that the pod and the service subnets
+// don't overlap with each other, nor with the addresses of the nodes
+func ValidateNetworkOverlaps(c kubeone.KubeOneCluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	// Invalid CIDRs are already reported by ValidateClusterNetworkConfig
+	_, podNet, podErr := net.ParseCIDR(c.ClusterNetwork.PodSubnet)
+	_, serviceNet, serviceErr := net.ParseCIDR(c.ClusterNetwork.ServiceSubnet)
+
+	if podErr == nil && serviceErr == nil && cidrsOverlap(podNet, serviceNet) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("serviceSubnet"), c.ClusterNetwork.ServiceSubnet,
+			fmt.Sprintf(".clusterNetwork.serviceSubnet overlaps with .clusterNetwork.podSubnet (%s)", c.ClusterNetwork.PodSubnet)))
+	}
+
+	subnets := map[string]*net.IPNet{}
+	if podErr == nil {
+		subnets["podSubnet"] = podNet
+	}
+	if serviceErr == nil {
+		subnets["serviceSubnet"] = serviceNet
+	}
+
+	checkHosts := func(hosts []kubeone.HostConfig, hostsPath *field.Path) {
+		for i, host := range hosts {
+			for _, addr := range []string{host.PrivateAddress, host.PublicAddress} {
+				ip := net.ParseIP(addr)
+				if ip == nil {
+					continue
+				}
+				for _, name := range []string{"podSubnet", "serviceSubnet"} {
+					subnet, ok := subnets[name]
+					if ok && subnet.Contains(ip) {
+						allErrs = append(allErrs, field.Invalid(hostsPath.Index(i), addr,
+							fmt.Sprintf("node address is in the .clusterNetwork.%s (%s)", name, subnet)))
+					}
+				}
+			}
+		}
+	}
+
+	checkHosts(c.ControlPlane.Hosts, field.NewPath("controlPlane", "hosts"))
+	checkHosts(c.StaticWorkers.Hosts, field.NewPath("staticWorkers", "hosts"))
+
+	return allErrs
+}
+
+// ValidateCloudProviderRequirements validates the provider-specific
+// requirements not covered by ValidateCloudProviderSpec
+func ValidateCloudProviderRequirements(p kubeone.CloudProviderSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if p.External && p.None != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("external"), p.External, ".cloudProvider.external can't be used with the none provider"))
+	}
+	if p.Vsphere != nil && p.External && len(p.CSIConfig) == 0 && p.Vsphere.CSI == nil {
+		allErrs = append(allErrs, field.Required(fldPath.Child("csiConfig"), ".cloudProvider.csiConfig or .cloudProvider.vsphere.csi is required for vSphere clusters using external cloud provider"))
+	}
+
+	return allErrs
+}
+
+// ValidateReferencedFiles validates that the local files referenced in the
+// manifest exist
+func ValidateReferencedFiles(c kubeone.KubeOneCluster, manifestFilePath string) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	checkHosts := func(hosts []kubeone.HostConfig, hostsPath *field.Path) {
+		for i, host := range hosts {
+			hostPath := hostsPath.Index(i)
+			allErrs = append(allErrs, validateFileExists(host.SSHPrivateKeyFile, "", hostPath.Child("sshPrivateKeyFile"))...)
+			allErrs = append(allErrs, validateFileExists(host.SSHCertFile, "", hostPath.Child("sshCertFile"))...)
+			for j, bastion := range host.BastionHosts {
+				allErrs = append(allErrs, validateFileExists(bastion.SSHPrivateKeyFile, "", hostPath.Child("bastionHosts").Index(j).Child("sshPrivateKeyFile"))...)
+			}
+		}
+	}
+
+	checkHosts(c.ControlPlane.Hosts, field.NewPath("controlPlane", "hosts"))
+	checkHosts(c.StaticWorkers.Hosts, field.NewPath("staticWorkers", "hosts"))
+
+	featuresPath := field.NewPath("features")
+	if c.Features.StaticAuditLog != nil && c.Features.StaticAuditLog.Enable {
+		allErrs = append(allErrs, validateFileExists(c.Features.StaticAuditLog.Config.PolicyFilePath, manifestFilePath,
+			featuresPath.Child("staticAuditLog", "config", "policyFilePath"))...)
+	}
+	if c.Features.PodNodeSelector != nil && c.Features.PodNodeSelector.Enable {
+		allErrs = append(allErrs, validateFileExists(c.Features.PodNodeSelector.Config.ConfigFilePath, manifestFilePath,
+			featuresPath.Child("podNodeSelector", "config", "configFilePath"))...)
+	}
+	if c.Addons.Enabled() && c.Addons.Path != "" {
+		allErrs = append(allErrs, validateFileExists(c.Addons.Path, manifestFilePath, field.NewPath("addons", "path"))...)
+	}
+
+	return allErrs
+}
+
+// validateFileExists validates that the file exists. Empty paths and
+// references to the external secret store are skipped. Relative paths are
+// resolved against the directory of the manifest file, if it's given, or
+// against the working directory otherwise.
+func validateFileExists(path, manifestFilePath string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if path == "" || secretstore.IsReference(path) {
+		return allErrs
+	}
+
+	resolved := path
+	if !filepath.IsAbs(path) && manifestFilePath != "" {
+		resolved = filepath.Join(filepath.Dir(manifestFilePath), path)
+	}
+
+	if _, err := os.Stat(resolved); err != nil {
+		allErrs = append(allErrs, field.NotFound(fldPath, path))
+	}
+
+	return allErrs
+}
+
+func cidrsOverlap(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
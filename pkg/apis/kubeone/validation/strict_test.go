@@ -0,0 +1,282 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"k8c.io/kubeone/pkg/apis/kubeone"
+)
+
+func TestValidateNetworkOverlaps(t *testing.T) {
+	tests := []struct {
+		name          string
+		cluster       kubeone.KubeOneCluster
+		expectedError bool
+	}{
+		{
+			name: "no overlaps",
+			cluster: kubeone.KubeOneCluster{
+				ClusterNetwork: kubeone.ClusterNetworkConfig{
+					PodSubnet:     "10.244.0.0/16",
+					ServiceSubnet: "10.96.0.0/12",
+				},
+				ControlPlane: kubeone.ControlPlaneConfig{
+					Hosts: []kubeone.HostConfig{
+						{PublicAddress: "192.168.1.1", PrivateAddress: "172.16.0.1"},
+					},
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "pod subnet overlaps with service subnet",
+			cluster: kubeone.KubeOneCluster{
+				ClusterNetwork: kubeone.ClusterNetworkConfig{
+					PodSubnet:     "10.0.0.0/8",
+					ServiceSubnet: "10.96.0.0/12",
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "control plane address in pod subnet",
+			cluster: kubeone.KubeOneCluster{
+				ClusterNetwork: kubeone.ClusterNetworkConfig{
+					PodSubnet:     "10.244.0.0/16",
+					ServiceSubnet: "10.96.0.0/12",
+				},
+				ControlPlane: kubeone.ControlPlaneConfig{
+					Hosts: []kubeone.HostConfig{
+						{PublicAddress: "192.168.1.1", PrivateAddress: "10.244.1.10"},
+					},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "static worker address in service subnet",
+			cluster: kubeone.KubeOneCluster{
+				ClusterNetwork: kubeone.ClusterNetworkConfig{
+					PodSubnet:     "10.244.0.0/16",
+					ServiceSubnet: "10.96.0.0/12",
+				},
+				StaticWorkers: kubeone.StaticWorkersConfig{
+					Hosts: []kubeone.HostConfig{
+						{PublicAddress: "10.100.0.5", PrivateAddress: "172.16.0.5"},
+					},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid subnets are skipped",
+			cluster: kubeone.KubeOneCluster{
+				ClusterNetwork: kubeone.ClusterNetworkConfig{
+					PodSubnet:     "10.244.0.0",
+					ServiceSubnet: "10.96.0.0",
+				},
+			},
+			expectedError: false,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateNetworkOverlaps(tc.cluster, nil)
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateCloudProviderRequirements(t *testing.T) {
+	tests := []struct {
+		name          string
+		providerSpec  kubeone.CloudProviderSpec
+		expectedError bool
+	}{
+		{
+			name: "vSphere external with csiConfig",
+			providerSpec: kubeone.CloudProviderSpec{
+				Vsphere:     &kubeone.VsphereSpec{},
+				External:    true,
+				CloudConfig: "cloud-config",
+				CSIConfig:   "csi-config",
+			},
+			expectedError: false,
+		},
+		{
+			name: "vSphere external without csiConfig",
+			providerSpec: kubeone.CloudProviderSpec{
+				Vsphere:     &kubeone.VsphereSpec{},
+				External:    true,
+				CloudConfig: "cloud-config",
+			},
+			expectedError: true,
+		},
+		{
+			name: "vSphere external with generated csiConfig",
+			providerSpec: kubeone.CloudProviderSpec{
+				Vsphere: &kubeone.VsphereSpec{
+					CSI: &kubeone.VsphereCSIConfig{Datacenters: []string{"dc-1"}},
+				},
+				External:    true,
+				CloudConfig: "cloud-config",
+			},
+			expectedError: false,
+		},
+		{
+			name: "vSphere in-tree without csiConfig",
+			providerSpec: kubeone.CloudProviderSpec{
+				Vsphere:     &kubeone.VsphereSpec{},
+				CloudConfig: "cloud-config",
+			},
+			expectedError: false,
+		},
+		{
+			name: "none provider with external",
+			providerSpec: kubeone.CloudProviderSpec{
+				None:     &kubeone.NoneSpec{},
+				External: true,
+			},
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateCloudProviderRequirements(tc.providerSpec, nil)
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateReferencedFiles(t *testing.T) {
+	dir := t.TempDir()
+	manifestFilePath := filepath.Join(dir, "kubeone.yaml")
+	keyFilePath := filepath.Join(dir, "id_rsa")
+	for _, file := range []string{manifestFilePath, keyFilePath, filepath.Join(dir, "audit-policy.yaml")} {
+		if err := ioutil.WriteFile(file, []byte{}, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name          string
+		cluster       kubeone.KubeOneCluster
+		expectedError bool
+	}{
+		{
+			name: "all files exist",
+			cluster: kubeone.KubeOneCluster{
+				ControlPlane: kubeone.ControlPlaneConfig{
+					Hosts: []kubeone.HostConfig{
+						{SSHPrivateKeyFile: keyFilePath},
+					},
+				},
+				Features: kubeone.Features{
+					StaticAuditLog: &kubeone.StaticAuditLog{
+						Enable: true,
+						Config: kubeone.StaticAuditLogConfig{
+							PolicyFilePath: "audit-policy.yaml",
+						},
+					},
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "secret store reference",
+			cluster: kubeone.KubeOneCluster{
+				ControlPlane: kubeone.ControlPlaneConfig{
+					Hosts: []kubeone.HostConfig{
+						{SSHPrivateKeyFile: "vault:secret/data/kubeone#key"},
+					},
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "missing SSH private key",
+			cluster: kubeone.KubeOneCluster{
+				ControlPlane: kubeone.ControlPlaneConfig{
+					Hosts: []kubeone.HostConfig{
+						{SSHPrivateKeyFile: filepath.Join(dir, "missing")},
+					},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "missing bastion SSH private key",
+			cluster: kubeone.KubeOneCluster{
+				StaticWorkers: kubeone.StaticWorkersConfig{
+					Hosts: []kubeone.HostConfig{
+						{
+							BastionHosts: []kubeone.BastionHost{
+								{SSHPrivateKeyFile: filepath.Join(dir, "missing")},
+							},
+						},
+					},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "missing audit policy",
+			cluster: kubeone.KubeOneCluster{
+				Features: kubeone.Features{
+					StaticAuditLog: &kubeone.StaticAuditLog{
+						Enable: true,
+						Config: kubeone.StaticAuditLogConfig{
+							PolicyFilePath: "missing-policy.yaml",
+						},
+					},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "audit policy of the disabled feature is not checked",
+			cluster: kubeone.KubeOneCluster{
+				Features: kubeone.Features{
+					StaticAuditLog: &kubeone.StaticAuditLog{
+						Config: kubeone.StaticAuditLogConfig{
+							PolicyFilePath: "missing-policy.yaml",
+						},
+					},
+				},
+			},
+			expectedError: false,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateReferencedFiles(tc.cluster, manifestFilePath)
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
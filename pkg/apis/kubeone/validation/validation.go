@@ -18,16 +18,63 @@ package validation
 
 import (
 	"bytes"
+	"crypto/ed25519"
+	"crypto/tls"
 	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
 	"net"
+	"net/url"
+	"path/filepath"
 	"reflect"
+	"regexp"
 	"strings"
 
+	"github.com/BurntSushi/toml"
 	"github.com/Masterminds/semver/v3"
+	"github.com/docker/distribution/reference"
+	jsonpatch "github.com/evanphx/json-patch"
 
 	"k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/secretstore"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	preflightErrorRegexp = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+	sha256Regexp         = regexp.MustCompile(`^[a-fA-F0-9]{64}$`)
+	nvidiaDriverRegexp   = regexp.MustCompile(`^[0-9]+$`)
+	kernelModuleRegexp   = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	sysctlKeyRegexp      = regexp.MustCompile(`^[A-Za-z0-9_-]+([./][A-Za-z0-9_-]+)+$`)
+	commandFlagRegexp    = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+	imageTagRegexp       = regexp.MustCompile(`^` + reference.TagRegexp.String() + `$`)
+
+	// evictionSignals are the eviction signals supported by the kubelet
+	evictionSignals = sets.NewString(
+		"memory.available",
+		"nodefs.available",
+		"nodefs.inodesFree",
+		"imagefs.available",
+		"imagefs.inodesFree",
+		"pid.available",
+	)
+
+	// machineControllerMinVersions are the oldest machine-controller releases
+	// supporting the Kubernetes minor versions
+	machineControllerMinVersions = map[uint64]string{
+		19: "1.19.0",
+		20: "1.24.0",
+		21: "1.30.0",
+		22: "1.35.0",
+	}
 )
 
 // ValidateKubeOneCluster validates the KubeOneCluster object
@@ -47,6 +94,7 @@ func ValidateKubeOneCluster(c kubeone.KubeOneCluster) field.ErrorList {
 	allErrs = append(allErrs, ValidateStaticWorkersConfig(c.StaticWorkers, field.NewPath("staticWorkers"))...)
 
 	if c.MachineController != nil && c.MachineController.Deploy {
+		allErrs = append(allErrs, ValidateMachineControllerConfig(c.MachineController, c.Versions, field.NewPath("machineController"))...)
 		allErrs = append(allErrs, ValidateDynamicWorkerConfig(c.DynamicWorkers, field.NewPath("dynamicWorkers"))...)
 	} else if len(c.DynamicWorkers) > 0 {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("dynamicWorkers"),
@@ -57,6 +105,24 @@ func ValidateKubeOneCluster(c kubeone.KubeOneCluster) field.ErrorList {
 	allErrs = append(allErrs, ValidateFeatures(c.Features, c.Versions, field.NewPath("features"))...)
 	allErrs = append(allErrs, ValidateAddons(c.Addons, field.NewPath("addons"))...)
 	allErrs = append(allErrs, ValidateRegistryConfiguration(c.RegistryConfiguration, field.NewPath("registryConfiguration"))...)
+	allErrs = append(allErrs, ValidateRBACConfig(c.RBAC, field.NewPath("rbac"))...)
+	allErrs = append(allErrs, ValidateNodeDrainConfig(c.NodeDrain, field.NewPath("nodeDrain"))...)
+	allErrs = append(allErrs, ValidateIgnorePreflightErrorsConfig(c.IgnorePreflightErrors, field.NewPath("ignorePreflightErrors"))...)
+	allErrs = append(allErrs, ValidateManifestPatches(c.ManifestPatches, field.NewPath("manifestPatches"))...)
+	allErrs = append(allErrs, ValidateClusterSizeConfig(c.ClusterSize, field.NewPath("clusterSize"))...)
+	allErrs = append(allErrs, ValidatePreflightConfig(c.Preflight, field.NewPath("preflight"))...)
+	allErrs = append(allErrs, ValidateHostRebootConfig(c.HostReboot, field.NewPath("hostReboot"))...)
+	allErrs = append(allErrs, ValidateOSTuningConfig(c.OSTuning, field.NewPath("osTuning"))...)
+	allErrs = append(allErrs, ValidateTimeSyncConfig(c.TimeSync, field.NewPath("timeSync"))...)
+	allErrs = append(allErrs, ValidateHardeningConfig(c.Hardening, field.NewPath("hardening"))...)
+	allErrs = append(allErrs, ValidateSecurityModulesConfig(c.SecurityModules, field.NewPath("securityModules"))...)
+	allErrs = append(allErrs, ValidateFirewallConfig(c, field.NewPath("firewall"))...)
+	allErrs = append(allErrs, ValidateCertificateAuthorityConfig(c, field.NewPath("certificateAuthority"))...)
+	allErrs = append(allErrs, ValidateNodeNamingConfig(c, field.NewPath("nodeNaming"))...)
+	allErrs = append(allErrs, ValidateNvidiaGPU(c, field.NewPath(""))...)
+	allErrs = append(allErrs, ValidateClusterAutoscaler(c, field.NewPath(""))...)
+	allErrs = append(allErrs, ValidateCertManager(c, field.NewPath("features", "certManager"))...)
+	allErrs = append(allErrs, ValidateVelero(c, field.NewPath("features", "velero"))...)
 
 	return allErrs
 }
@@ -71,6 +137,8 @@ func ValidateControlPlaneConfig(c kubeone.ControlPlaneConfig, fldPath *field.Pat
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("hosts"), "",
 			".controlPlane.Hosts is a required field. There must be at least one control plane instance in the cluster."))
 	}
+	allErrs = append(allErrs, ValidateStaticPods(c.StaticPods, fldPath.Child("staticPods"))...)
+	allErrs = append(allErrs, ValidateHostDiscoveryConfig(c.HostDiscovery, fldPath.Child("hostDiscovery"))...)
 
 	return allErrs
 }
@@ -108,14 +176,35 @@ func ValidateCloudProviderSpec(p kubeone.CloudProviderSpec, fldPath *field.Path)
 
 	providerFound := false
 	if p.AWS != nil {
+		if p.AWS.EBSCSIEnabled() && !p.External {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("aws", "ebsCSI", "enable"), true, "EBS CSI driver is supported only for clusters using external cloud provider (.cloudProvider.external)"))
+		}
 		providerFound = true
 	}
 	if p.Azure != nil {
 		if providerFound {
 			allErrs = append(allErrs, field.Forbidden(fldPath.Child("azure"), "only one provider can be used at the same time"))
 		}
-		if len(p.CloudConfig) == 0 {
-			allErrs = append(allErrs, field.Required(fldPath.Child("cloudConfig"), ".cloudProvider.cloudConfig is required for azure provider"))
+		if len(p.CloudConfig) == 0 && p.Azure.CloudConfig == nil {
+			allErrs = append(allErrs, field.Required(fldPath.Child("cloudConfig"), ".cloudProvider.cloudConfig or .cloudProvider.azure.cloudConfig is required for azure provider"))
+		}
+		if p.Azure.CloudConfig != nil {
+			allErrs = append(allErrs, ValidateAzureCloudConfig(p.Azure.CloudConfig, fldPath.Child("azure", "cloudConfig"))...)
+		}
+		if !p.External {
+			components := []struct {
+				name      string
+				component *kubeone.AzureComponent
+			}{
+				{"ccm", p.Azure.CCM},
+				{"diskCSI", p.Azure.DiskCSI},
+				{"fileCSI", p.Azure.FileCSI},
+			}
+			for _, c := range components {
+				if c.component != nil {
+					allErrs = append(allErrs, field.Invalid(fldPath.Child("azure", c.name), "", "Azure components are managed only for clusters using external cloud provider (.cloudProvider.external)"))
+				}
+			}
 		}
 		providerFound = true
 	}
@@ -144,6 +233,12 @@ func ValidateCloudProviderSpec(p kubeone.CloudProviderSpec, fldPath *field.Path)
 		if len(p.CloudConfig) == 0 {
 			allErrs = append(allErrs, field.Required(fldPath.Child("cloudConfig"), ".cloudProvider.cloudConfig is required for openstack provider"))
 		}
+		if p.Openstack.CCM != nil {
+			allErrs = append(allErrs, ValidateOpenstackComponent(p, p.Openstack.CCM, fldPath.Child("openstack", "ccm"))...)
+		}
+		if p.Openstack.CinderCSI != nil {
+			allErrs = append(allErrs, ValidateOpenstackComponent(p, p.Openstack.CinderCSI, fldPath.Child("openstack", "cinderCSI"))...)
+		}
 		providerFound = true
 	}
 	if p.Packet != nil {
@@ -159,6 +254,9 @@ func ValidateCloudProviderSpec(p kubeone.CloudProviderSpec, fldPath *field.Path)
 		if len(p.CloudConfig) == 0 {
 			allErrs = append(allErrs, field.Required(fldPath.Child("cloudConfig"), ".cloudProvider.cloudConfig is required for vSphere provider"))
 		}
+		if p.Vsphere.CSI != nil {
+			allErrs = append(allErrs, ValidateVsphereCSIConfig(p, fldPath.Child("vsphere", "csi"))...)
+		}
 		providerFound = true
 	}
 	if p.None != nil {
@@ -181,6 +279,18 @@ func ValidateCloudProviderSpec(p kubeone.CloudProviderSpec, fldPath *field.Path)
 		}
 	}
 
+	if p.CredentialsVault != nil {
+		vaultPath := fldPath.Child("credentialsVault")
+		if p.CredentialsVault.Path == "" {
+			allErrs = append(allErrs, field.Required(vaultPath.Child("path"), "path of the vault secret is required"))
+		}
+		for name, key := range p.CredentialsVault.Keys {
+			if key == "" {
+				allErrs = append(allErrs, field.Invalid(vaultPath.Child("keys").Key(name), key, "key of the vault secret must not be empty"))
+			}
+		}
+	}
+
 	return allErrs
 }
 
@@ -203,6 +313,85 @@ func ValidateVersionConfig(version kubeone.VersionConfig, fldPath *field.Path) f
 	return allErrs
 }
 
+// ValidateAzureCloudConfig validates the AzureCloudConfig structure
+func ValidateAzureCloudConfig(c *kubeone.AzureCloudConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	required := []struct {
+		name  string
+		value string
+	}{
+		{"resourceGroup", c.ResourceGroup},
+		{"location", c.Location},
+		{"vnetName", c.VNetName},
+		{"subnetName", c.SubnetName},
+		{"securityGroupName", c.SecurityGroupName},
+	}
+	for _, r := range required {
+		if r.value == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child(r.name), "required to generate the azure.json cloud-config"))
+		}
+	}
+
+	switch strings.ToLower(c.LoadBalancerSku) {
+	case "", "basic", "standard":
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("loadBalancerSku"), c.LoadBalancerSku, []string{"basic", "standard"}))
+	}
+
+	return allErrs
+}
+
+// ValidateOpenstackComponent validates the OpenstackComponent structure
+func ValidateOpenstackComponent(p kubeone.CloudProviderSpec, c *kubeone.OpenstackComponent, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if !p.External {
+		allErrs = append(allErrs, field.Invalid(fldPath, "", "OpenStack components are managed only for clusters using external cloud provider (.cloudProvider.external)"))
+	}
+
+	for name, options := range c.CloudConfigSections {
+		sectionPath := fldPath.Child("cloudConfigSections").Key(name)
+		if strings.TrimSpace(name) == "" || strings.ContainsAny(name, "[]\n") {
+			allErrs = append(allErrs, field.Invalid(sectionPath, name, "section name can't be empty or contain brackets and newlines"))
+		}
+		for key, value := range options {
+			if key == "" || strings.ContainsAny(key, "=[] \t\n") {
+				allErrs = append(allErrs, field.Invalid(sectionPath.Key(key), key, "option name can't be empty or contain whitespace, brackets and equal signs"))
+			}
+			if strings.Contains(value, "\n") {
+				allErrs = append(allErrs, field.Invalid(sectionPath.Key(key), value, "option value can't contain newlines"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateVsphereCSIConfig validates the VsphereCSIConfig structure
+func ValidateVsphereCSIConfig(p kubeone.CloudProviderSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if !p.External {
+		allErrs = append(allErrs, field.Invalid(fldPath, "", ".cloudProvider.vsphere.csi is supported only for clusters using external cloud provider (.cloudProvider.external)"))
+	}
+
+	csi := p.Vsphere.CSI
+	if len(csi.Datacenters) == 0 && p.CSIConfig == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("datacenters"), "datacenters are required to generate the CSI config"))
+	}
+	for i, dc := range csi.Datacenters {
+		if strings.TrimSpace(dc) == "" || strings.Contains(dc, ",") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("datacenters").Index(i), dc, "datacenter can't be empty or contain commas"))
+		}
+	}
+	if sc := csi.StorageClass; sc != nil && sc.DatastoreURL != "" && !strings.HasPrefix(sc.DatastoreURL, "ds:///") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("storageClass", "datastoreURL"), sc.DatastoreURL, "datastoreURL must start with ds:///"))
+	}
+
+	return allErrs
+}
+
 func ValidateCloudProviderSupportsKubernetes(c kubeone.KubeOneCluster, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -215,6 +404,9 @@ func ValidateCloudProviderSupportsKubernetes(c kubeone.KubeOneCluster, fldPath *
 	if c.CloudProvider.Vsphere != nil && v.Minor() >= 22 {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("versions").Child("kubernetes"), c.Versions.Kubernetes, "kubernetes versions 1.22.0 and newer are currently not supported for vsphere clusters"))
 	}
+	if vs := c.CloudProvider.Vsphere; vs != nil && vs.CSI != nil && vs.CSI.Snapshots && v.Minor() < 21 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("cloudProvider", "vsphere", "csi", "snapshots"), true, "vSphere CSI snapshots require kubernetes 1.21.0 or newer"))
+	}
 
 	return allErrs
 }
@@ -245,6 +437,38 @@ func ValidateContainerRuntimeConfig(cr kubeone.ContainerRuntimeConfig, versions
 		}
 	}
 
+	if cr.Containerd != nil {
+		allErrs = append(allErrs, ValidateContainerdConfig(cr.Containerd, fldPath.Child("containerd"))...)
+	}
+
+	return allErrs
+}
+
+// ValidateContainerdConfig validates the ContainerRuntimeContainerd structure
+func ValidateContainerdConfig(c *kubeone.ContainerRuntimeContainerd, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	switch c.CgroupDriver {
+	case "", "systemd", "cgroupfs":
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("cgroupDriver"), c.CgroupDriver, []string{"systemd", "cgroupfs"}))
+	}
+
+	if c.SystemdCgroup != nil && *c.SystemdCgroup != (c.CgroupDriverName() == "systemd") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("systemdCgroup"), *c.SystemdCgroup, "systemdCgroup must be enabled only if cgroupDriver is systemd"))
+	}
+
+	if c.NvidiaRuntime != nil && c.NvidiaRuntime.BinaryName != "" && !strings.HasPrefix(c.NvidiaRuntime.BinaryName, "/") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("nvidiaRuntime", "binaryName"), c.NvidiaRuntime.BinaryName, "binaryName must be an absolute path"))
+	}
+
+	if c.ConfigPatch != "" {
+		patch := map[string]interface{}{}
+		if _, err := toml.Decode(c.ConfigPatch, &patch); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("configPatch"), c.ConfigPatch, fmt.Sprintf("configPatch must be a valid TOML document: %v", err)))
+		}
+	}
+
 	return allErrs
 }
 
@@ -326,6 +550,9 @@ func ValidateCNI(c *kubeone.CNI, fldPath *field.Path) field.ErrorList {
 			allErrs = append(allErrs, field.Forbidden(fldPath.Child("external"), "only one cni plugin can be used at the same time"))
 		}
 		cniFound = true
+		if c.External.HelmChart != nil {
+			allErrs = append(allErrs, ValidateHelmChart(c.External.HelmChart, fldPath.Child("external").Child("helmChart"))...)
+		}
 	}
 
 	if !cniFound {
@@ -335,6 +562,30 @@ func ValidateCNI(c *kubeone.CNI, fldPath *field.Path) field.ErrorList {
 	return allErrs
 }
 
+// ValidateHelmChart validates the HelmChart structure
+func ValidateHelmChart(h *kubeone.HelmChart, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if h.ReleaseName == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("releaseName"), ".helmChart.releaseName is a required field"))
+	}
+	if h.Chart == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("chart"), ".helmChart.chart is a required field"))
+	} else if h.Version == "" && !isLocalChart(h.Chart) {
+		// Otherwise the latest version of the chart would be installed, and
+		// upgraded to on every apply
+		allErrs = append(allErrs, field.Required(fldPath.Child("version"), ".helmChart.version is a required field for charts from the repositories"))
+	}
+
+	return allErrs
+}
+
+// isLocalChart returns true if the chart is referenced by the path to the
+// local chart
+func isLocalChart(chart string) bool {
+	return filepath.IsAbs(chart) || strings.HasPrefix(chart, "./") || strings.HasPrefix(chart, "../")
+}
+
 // ValidateStaticWorkersConfig validates the StaticWorkersConfig structure
 func ValidateStaticWorkersConfig(staticWorkers kubeone.StaticWorkersConfig, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -342,6 +593,211 @@ func ValidateStaticWorkersConfig(staticWorkers kubeone.StaticWorkersConfig, fldP
 	if len(staticWorkers.Hosts) > 0 {
 		allErrs = append(allErrs, ValidateHostConfig(staticWorkers.Hosts, fldPath.Child("hosts"))...)
 	}
+	allErrs = append(allErrs, ValidateStaticWorkerGroups(staticWorkers.Groups, fldPath.Child("groups"))...)
+	allErrs = append(allErrs, ValidateStaticPods(staticWorkers.StaticPods, fldPath.Child("staticPods"))...)
+	allErrs = append(allErrs, ValidateHostDiscoveryConfig(staticWorkers.HostDiscovery, fldPath.Child("hostDiscovery"))...)
+
+	return allErrs
+}
+
+// ValidateStaticWorkerGroups validates the StaticWorkerGroup structures. The
+// hosts of the groups are validated as a part of the static worker hosts.
+func ValidateStaticWorkerGroups(groups []kubeone.StaticWorkerGroup, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := sets.NewString()
+	for i, group := range groups {
+		groupPath := fldPath.Index(i)
+
+		if group.Name == "" {
+			allErrs = append(allErrs, field.Required(groupPath.Child("name"), "group name is required"))
+		} else {
+			for _, msg := range validation.IsDNS1123Label(group.Name) {
+				allErrs = append(allErrs, field.Invalid(groupPath.Child("name"), group.Name, msg))
+			}
+		}
+		if names.Has(group.Name) {
+			allErrs = append(allErrs, field.Duplicate(groupPath.Child("name"), group.Name))
+		}
+		names.Insert(group.Name)
+
+		if len(group.Hosts) == 0 {
+			allErrs = append(allErrs, field.Required(groupPath.Child("hosts"), "at least one host is required"))
+		}
+		for j, host := range group.Hosts {
+			if host.IsLeader {
+				allErrs = append(allErrs, field.Invalid(groupPath.Child("hosts").Index(j).Child("isLeader"), host.IsLeader, "static worker hosts can't be the leader"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateHostDiscoveryConfig validates the HostDiscoveryConfig structure
+func ValidateHostDiscoveryConfig(c *kubeone.HostDiscoveryConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if c == nil {
+		return allErrs
+	}
+
+	if len(c.Tags) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("tags"), "at least one tag is required"))
+	}
+	if c.Template.PublicAddress != "" || c.Template.PrivateAddress != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("template"), "addresses are taken from the discovered instances"))
+	}
+	if c.Template.IsLeader {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("template", "isLeader"), "the leader can't be set for the discovered hosts"))
+	}
+
+	return allErrs
+}
+
+// ValidateStaticPods validates the StaticPod structures
+func ValidateStaticPods(pods []kubeone.StaticPod, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := map[string]bool{}
+	for i, pod := range pods {
+		podPath := fldPath.Index(i)
+
+		if pod.Name == "" {
+			allErrs = append(allErrs, field.Required(podPath.Child("name"), "name is a required field"))
+		} else {
+			for _, msg := range validation.IsDNS1123Label(pod.Name) {
+				allErrs = append(allErrs, field.Invalid(podPath.Child("name"), pod.Name, msg))
+			}
+		}
+		if names[pod.Name] {
+			allErrs = append(allErrs, field.Duplicate(podPath.Child("name"), pod.Name))
+		}
+		names[pod.Name] = true
+
+		if pod.Manifest == "" {
+			allErrs = append(allErrs, field.Required(podPath.Child("manifest"), "manifest is a required field"))
+			continue
+		}
+
+		manifest := corev1.Pod{}
+		if err := yaml.UnmarshalStrict([]byte(pod.Manifest), &manifest); err != nil {
+			allErrs = append(allErrs, field.Invalid(podPath.Child("manifest"), pod.Name, fmt.Sprintf("failed to parse the manifest: %v", err)))
+			continue
+		}
+		if manifest.Kind != "Pod" || manifest.APIVersion != "v1" {
+			allErrs = append(allErrs, field.Invalid(podPath.Child("manifest"), pod.Name, "manifest must be a v1 Pod"))
+		}
+		if manifest.Name == "" {
+			allErrs = append(allErrs, field.Required(podPath.Child("manifest"), "metadata.name is required in the manifest"))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateMachineControllerConfig validates the MachineControllerConfig
+// structure, including that the pinned machine-controller release supports
+// the Kubernetes version
+func ValidateMachineControllerConfig(mc *kubeone.MachineControllerConfig, versions kubeone.VersionConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if mc.ImageRepository != "" {
+		named, err := reference.ParseNormalizedNamed(mc.ImageRepository)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("imageRepository"), mc.ImageRepository, err.Error()))
+		} else if !reference.IsNameOnly(named) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("imageRepository"), mc.ImageRepository, "repository can't contain the tag or the digest, use .machineController.imageTag to pin the release"))
+		}
+	}
+
+	if mc.ImageTag != "" {
+		allErrs = append(allErrs, validateMachineControllerImageTag(mc.ImageTag, versions, fldPath.Child("imageTag"))...)
+	}
+
+	allErrs = append(allErrs, validateResourceRequirements(mc.Resources, fldPath.Child("resources"))...)
+
+	for k, v := range mc.NodeSelector {
+		for _, msg := range validation.IsQualifiedName(k) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("nodeSelector").Key(k), k, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(v) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("nodeSelector").Key(k), v, msg))
+		}
+	}
+
+	for i, toleration := range mc.Tolerations {
+		if toleration.Operator == corev1.TolerationOpExists && toleration.Value != "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("tolerations").Index(i).Child("value"), toleration.Value, "value must be empty when operator is Exists"))
+		}
+		switch toleration.Effect {
+		case "", corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+		default:
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("tolerations").Index(i).Child("effect"), toleration.Effect,
+				[]string{string(corev1.TaintEffectNoSchedule), string(corev1.TaintEffectPreferNoSchedule), string(corev1.TaintEffectNoExecute)}))
+		}
+	}
+
+	for flag := range mc.ExtraFlags {
+		if !commandFlagRegexp.MatchString(flag) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("extraFlags").Key(flag), flag, "flag must be given without the leading dash and contain only lowercase alphanumeric characters and dashes"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateResourceRequirements validates that the requests are not greater
+// than the limits
+func validateResourceRequirements(res *corev1.ResourceRequirements, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if res == nil {
+		return allErrs
+	}
+
+	for name, request := range res.Requests {
+		if limit, ok := res.Limits[name]; ok && request.Cmp(limit) > 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("requests").Key(string(name)), request.String(), "request can't be greater than the limit"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateMachineControllerImageTag validates the tag and, if the tag is
+// a semantic version, checks that the release supports the Kubernetes version.
+// Tags of the custom builds can't be checked.
+func validateMachineControllerImageTag(tag string, versions kubeone.VersionConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if !imageTagRegexp.MatchString(tag) {
+		return append(allErrs, field.Invalid(fldPath, tag, "invalid image tag"))
+	}
+
+	mcVersion, err := semver.NewVersion(tag)
+	if err != nil {
+		return allErrs
+	}
+
+	kubeVersion, err := semver.NewVersion(versions.Kubernetes)
+	if err != nil {
+		return allErrs
+	}
+
+	// Kubernetes versions newer than the listed ones require the release
+	// supporting the newest listed version
+	var minMinor uint64
+	var minVersion string
+	for minor, version := range machineControllerMinVersions {
+		if minor <= kubeVersion.Minor() && minor >= minMinor {
+			minMinor, minVersion = minor, version
+		}
+	}
+
+	if minVersion != "" && mcVersion.LessThan(semver.MustParse(minVersion)) {
+		allErrs = append(allErrs, field.Invalid(fldPath, tag, fmt.Sprintf("machine-controller %s doesn't support Kubernetes %s, v%s or newer is required", tag, versions.Kubernetes, minVersion)))
+	}
 
 	return allErrs
 }
@@ -391,6 +847,17 @@ func ValidateFeatures(f kubeone.Features, versions kubeone.VersionConfig, fldPat
 	if f.OpenIDConnect != nil && f.OpenIDConnect.Enable {
 		allErrs = append(allErrs, ValidateOIDCConfig(f.OpenIDConnect.Config, fldPath.Child("openidConnect"))...)
 	}
+	if f.MetricsServer != nil && f.MetricsServer.Enable {
+		allErrs = append(allErrs, ValidateMetricsServerConfig(f.MetricsServer, fldPath.Child("metricsServer"))...)
+	}
+
+	if f.IngressController != nil && f.IngressController.Enable {
+		allErrs = append(allErrs, ValidateIngressController(f.IngressController, fldPath.Child("ingressController"))...)
+	}
+
+	if f.GitOps.Enabled() {
+		allErrs = append(allErrs, ValidateGitOps(f.GitOps, fldPath.Child("gitOps"))...)
+	}
 
 	if f.PodPresets != nil && f.PodPresets.Enable {
 		allErrs = append(allErrs, field.Forbidden(fldPath.Child("podPresets"), "podPresets feature is removed in kubernetes 1.20+ and must be disabled"))
@@ -399,76 +866,494 @@ func ValidateFeatures(f kubeone.Features, versions kubeone.VersionConfig, fldPat
 	return allErrs
 }
 
-// ValidatePodNodeSelectorConfig validates the PodNodeSelectorConfig structure
-func ValidatePodNodeSelectorConfig(n kubeone.PodNodeSelectorConfig, fldPath *field.Path) field.ErrorList {
+// ValidateMetricsServerConfig validates the MetricsServer structure
+func ValidateMetricsServerConfig(m *kubeone.MetricsServer, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
-	if len(n.ConfigFilePath) == 0 {
-		allErrs = append(allErrs, field.Required(fldPath.Child("configFilePath"), ".podNodeSelector.config.configFilePath is a required field"))
+	if m.Replicas < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("replicas"), m.Replicas, "replicas can't be negative"))
 	}
+	allErrs = append(allErrs, validateResourceRequirements(m.Resources, fldPath.Child("resources"))...)
 
 	return allErrs
 }
 
-// ValidateStaticAuditLogConfig validates the StaticAuditLogConfig structure
-func ValidateStaticAuditLogConfig(s kubeone.StaticAuditLogConfig, fldPath *field.Path) field.ErrorList {
+// ValidateIngressController validates the IngressController structure
+func ValidateIngressController(ic *kubeone.IngressController, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
-	if len(s.PolicyFilePath) == 0 {
-		allErrs = append(allErrs, field.Required(fldPath.Child("policyFilePath"), ".staticAuditLog.config.policyFilePath is a required field"))
-	}
-	if len(s.LogPath) == 0 {
-		allErrs = append(allErrs, field.Required(fldPath.Child("logPath"), ".staticAuditLog.config.logPath is a required field"))
-	}
-	if s.LogMaxAge <= 0 {
-		allErrs = append(allErrs, field.Invalid(fldPath.Child("logMaxAge"), s.LogMaxAge, ".staticAuditLog.config.logMaxAge must be greater than 0"))
+	if ic.Replicas < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("replicas"), ic.Replicas, "replicas can't be negative"))
 	}
-	if s.LogMaxBackup <= 0 {
-		allErrs = append(allErrs, field.Invalid(fldPath.Child("logMaxBackup"), s.LogMaxBackup, ".staticAuditLog.config.logMaxBackup must be greater than 0"))
+
+	switch ic.ServiceType {
+	case "", corev1.ServiceTypeLoadBalancer, corev1.ServiceTypeNodePort:
+		if ic.HostNetwork && ic.ServiceType != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("serviceType"), "serviceType can't be set if hostNetwork is enabled"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("serviceType"), ic.ServiceType,
+			[]string{string(corev1.ServiceTypeLoadBalancer), string(corev1.ServiceTypeNodePort)}))
 	}
-	if s.LogMaxSize <= 0 {
-		allErrs = append(allErrs, field.Invalid(fldPath.Child("logMaxSize"), s.LogMaxSize, ".staticAuditLog.config.logMaxSize must be greater than 0"))
+
+	if ic.IngressClass != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(ic.IngressClass) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ingressClass"), ic.IngressClass, msg))
+		}
 	}
 
 	return allErrs
 }
 
-// ValidateOIDCConfig validates the OpenIDConnectConfig structure
-func ValidateOIDCConfig(o kubeone.OpenIDConnectConfig, fldPath *field.Path) field.ErrorList {
+// ValidateGitOps validates the GitOps structure
+func ValidateGitOps(g *kubeone.GitOps, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
-	if len(o.IssuerURL) == 0 {
-		allErrs = append(allErrs, field.Required(fldPath.Child("issuerURL"), ".openidConnect.config.issuerURL is a required field"))
+	repo := g.Repository
+	repoPath := fldPath.Child("repository")
+
+	if repo.URL == "" {
+		allErrs = append(allErrs, field.Required(repoPath.Child("url"), "url of the git repository is required"))
+	} else {
+		u, err := url.Parse(repo.URL)
+		switch {
+		case err != nil:
+			allErrs = append(allErrs, field.Invalid(repoPath.Child("url"), repo.URL, err.Error()))
+		case u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "ssh":
+			allErrs = append(allErrs, field.Invalid(repoPath.Child("url"), repo.URL, "url must use the http, https or ssh scheme"))
+		case u.Host == "":
+			allErrs = append(allErrs, field.Invalid(repoPath.Child("url"), repo.URL, "url must contain the host"))
+		case u.Scheme == "ssh" && repo.SecretName == "":
+			allErrs = append(allErrs, field.Required(repoPath.Child("secretName"), "secretName with the ssh identity is required for the ssh repositories"))
+		}
 	}
-	if len(o.ClientID) == 0 {
-		allErrs = append(allErrs, field.Required(fldPath.Child("clientID"), ".openidConnect.config.clientID is a required field"))
+
+	if strings.HasPrefix(repo.Path, "/") || strings.Contains(repo.Path, "..") {
+		allErrs = append(allErrs, field.Invalid(repoPath.Child("path"), repo.Path, "path must be relative to the root of the repository"))
+	}
+	if repo.Interval != nil && repo.Interval.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(repoPath.Child("interval"), repo.Interval.Duration.String(), "interval must be positive"))
+	}
+	if repo.SecretName != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(repo.SecretName) {
+			allErrs = append(allErrs, field.Invalid(repoPath.Child("secretName"), repo.SecretName, msg))
+		}
 	}
 
 	return allErrs
 }
 
-// ValidateAddons validates the Addons configuration
-func ValidateAddons(o *kubeone.Addons, fldPath *field.Path) field.ErrorList {
+// ValidateCertificateAuthorityConfig validates the CertificateAuthorityConfig structure
+func ValidateCertificateAuthorityConfig(c kubeone.KubeOneCluster, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
-	if o == nil || !o.Enable {
+	if c.CertificateAuthority != nil {
+		switch c.CertificateAuthority.KeyAlgorithm {
+		case "", kubeone.KeyAlgorithmRSA, kubeone.KeyAlgorithmECDSA, kubeone.KeyAlgorithmEd25519:
+		default:
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("keyAlgorithm"), c.CertificateAuthority.KeyAlgorithm,
+				[]string{string(kubeone.KeyAlgorithmRSA), string(kubeone.KeyAlgorithmECDSA), string(kubeone.KeyAlgorithmEd25519)}))
+		}
+
+		validity, renewBefore := c.CertificateValidity(), c.CertificateRenewBefore()
+		if validity <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("certificateValidity"), validity.String(), "certificateValidity must be positive"))
+		}
+		switch {
+		case renewBefore <= 0:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("renewBefore"), renewBefore.String(), "renewBefore must be positive"))
+		case renewBefore >= validity || renewBefore >= kubeone.DefaultCertificateValidity:
+			// Otherwise the certificates would be renewed on every apply
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("renewBefore"), renewBefore.String(), "renewBefore must be shorter than certificateValidity and 1 year"))
+		}
+	}
+
+	ca := c.ExternalCA()
+	if ca == nil {
 		return allErrs
 	}
-	if o.Enable && len(o.Path) == 0 {
-		allErrs = append(allErrs, field.Invalid(fldPath.Child("path"), "", ".addons.path must be specified"))
+	fldPath = fldPath.Child("external")
+
+	rootCA, err := parseCACertificate(ca.CACertificate)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("caCertificate"), "", err.Error()))
+	}
+
+	if ca.IntermediateCertificate != "" {
+		intermediateCA, err := parseCACertificate(ca.IntermediateCertificate)
+		switch {
+		case err != nil:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("intermediateCertificate"), "", err.Error()))
+		case rootCA != nil && intermediateCA.CheckSignatureFrom(rootCA) != nil:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("intermediateCertificate"), "", "intermediate CA certificate is not signed by the root CA"))
+		}
+	}
+
+	if ca.IntermediateKey != "" {
+		if ca.IntermediateCertificate == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("intermediateCertificate"), "intermediateCertificate is required if intermediateKey is set"))
+		} else if keyPair, err := tls.X509KeyPair([]byte(ca.IntermediateCertificate), []byte(ca.IntermediateKey)); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("intermediateKey"), "", "intermediateKey doesn't match intermediateCertificate"))
+		} else if _, ok := keyPair.PrivateKey.(ed25519.PrivateKey); ok {
+			// kubeadm loads only the RSA and ECDSA CA keys
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("intermediateKey"), "", "Ed25519 CA keys are not supported by kubeadm"))
+		}
+		if ca.Signer != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("signer"), "signer can't be used together with intermediateKey"))
+		}
+		if ca.KeyProvider != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("keyProvider"), "keyProvider can't be used together with intermediateKey"))
+		}
+
+		return allErrs
+	}
+
+	switch {
+	case ca.Signer != nil && ca.KeyProvider != nil:
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("keyProvider"), "only one of signer and keyProvider can be set"))
+	case ca.Signer != nil:
+		if ca.Signer.Command == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("signer", "command"), "signer command is required"))
+		}
+	case ca.KeyProvider != nil:
+		allErrs = append(allErrs, ValidateExternalCAKeyProvider(*ca.KeyProvider, fldPath.Child("keyProvider"))...)
+	default:
+		allErrs = append(allErrs, field.Required(fldPath.Child("signer"), "signer or keyProvider is required if intermediateKey is not set"))
+	}
+
+	// Without the CA key, kube-controller-manager can't sign the kubelet client
+	// certificates of the machines created by machine-controller
+	if len(c.DynamicWorkers) > 0 {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("dynamicWorkers"), "dynamic workers are not supported if the certificates are signed by the external CA signer"))
 	}
 
 	return allErrs
 }
 
-// ValidateHostConfig validates the HostConfig structure
-func ValidateHostConfig(hosts []kubeone.HostConfig, fldPath *field.Path) field.ErrorList {
+// ValidateNodeNamingConfig validates the NodeNamingConfig structure
+func ValidateNodeNamingConfig(c kubeone.KubeOneCluster, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
-	leaderFound := false
-	for _, h := range hosts {
-		if leaderFound && h.IsLeader {
-			allErrs = append(allErrs, field.Invalid(fldPath, h.IsLeader, "only one leader is allowed"))
+	if c.NodeNaming == nil || c.NodeNaming.Strategy == "" {
+		return allErrs
+	}
+
+	strategy := c.NodeNaming.Strategy
+	switch strategy {
+	case kubeone.NodeNamingHostname, kubeone.NodeNamingFQDN, kubeone.NodeNamingPrivateIP:
+	case kubeone.NodeNamingInstanceID:
+		// Other cloud controller managers match the Nodes to the instances
+		// by the instance name, so they wouldn't find the instance ID named Nodes
+		if c.CloudProvider.AWS == nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("strategy"), strategy,
+				"instanceID is supported only on aws"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("strategy"), strategy, []string{
+			string(kubeone.NodeNamingHostname),
+			string(kubeone.NodeNamingFQDN),
+			string(kubeone.NodeNamingPrivateIP),
+			string(kubeone.NodeNamingInstanceID),
+		}))
+
+		return allErrs
+	}
+
+	if c.MachineController != nil && c.MachineController.Deploy && len(c.DynamicWorkers) > 0 {
+		if mcStrategy := c.MachineControllerNodeNaming(); strategy != mcStrategy {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("strategy"), strategy,
+				fmt.Sprintf("machine-controller names the dynamic workers using the %s strategy on this provider, which would mix the node naming strategies", mcStrategy)))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateExternalCAKeyProvider validates the ExternalCAKeyProvider structure
+func ValidateExternalCAKeyProvider(p kubeone.ExternalCAKeyProvider, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	switch {
+	case p.PKCS11 != nil && p.AWSKMS != nil:
+		allErrs = append(allErrs, field.Forbidden(fldPath, "only one key provider can be set"))
+	case p.PKCS11 != nil:
+		if p.PKCS11.Module == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("pkcs11", "module"), "PKCS#11 module is required"))
+		}
+		if p.PKCS11.KeyID == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("pkcs11", "keyID"), "key ID is required"))
+		} else if _, err := hex.DecodeString(p.PKCS11.KeyID); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("pkcs11", "keyID"), p.PKCS11.KeyID, "key ID must be hex encoded"))
+		}
+	case p.AWSKMS != nil:
+		if p.AWSKMS.KeyID == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("awsKMS", "keyID"), "key ID is required"))
+		}
+	default:
+		allErrs = append(allErrs, field.Required(fldPath, "one of pkcs11 and awsKMS is required"))
+	}
+
+	return allErrs
+}
+
+// parseCACertificate parses the first PEM encoded certificate and ensures it's a CA
+func parseCACertificate(caPEM string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(caPEM))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("can't find a PEM encoded certificate")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse the certificate: %v", err)
+	}
+
+	if !cert.IsCA {
+		return nil, errors.New("certificate is not a CA certificate")
+	}
+
+	return cert, nil
+}
+
+// ValidateNvidiaGPU validates the NvidiaGPU feature and that it's enabled
+// if any of the hosts or workersets has NvidiaGPU enabled
+func ValidateNvidiaGPU(c kubeone.KubeOneCluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	enabled := c.Features.NvidiaGPU != nil && c.Features.NvidiaGPU.Enable
+	if enabled {
+		featurePath := fldPath.Child("features", "nvidiaGPU")
+		if !c.ContainerRuntime.Containerd.NvidiaRuntimeEnabled() {
+			allErrs = append(allErrs, field.Invalid(featurePath.Child("enable"), true, "nvidiaGPU feature requires containerd with the NVIDIA runtime enabled (.containerRuntime.containerd.nvidiaRuntime.enable)"))
+		}
+		if v := c.Features.NvidiaGPU.DriverVersion; v != "" && !nvidiaDriverRegexp.MatchString(v) {
+			allErrs = append(allErrs, field.Invalid(featurePath.Child("driverVersion"), v, "driverVersion must be a driver branch, e.g. 470"))
+		}
+
+		return allErrs
+	}
+
+	for i, host := range c.ControlPlane.Hosts {
+		if host.NvidiaGPU {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("controlPlane", "hosts").Index(i).Child("nvidiaGPU"), true, "nvidiaGPU requires the nvidiaGPU feature to be enabled"))
+		}
+	}
+	for i, host := range c.StaticWorkers.Hosts {
+		if host.NvidiaGPU {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("staticWorkers", "hosts").Index(i).Child("nvidiaGPU"), true, "nvidiaGPU requires the nvidiaGPU feature to be enabled"))
+		}
+	}
+	for i, workerset := range c.DynamicWorkers {
+		if workerset.NvidiaGPU {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("dynamicWorkers").Index(i).Child("nvidiaGPU"), true, "nvidiaGPU requires the nvidiaGPU feature to be enabled"))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateClusterAutoscaler validates the ClusterAutoscaler feature and the
+// autoscaling of the workersets, which requires the feature to be enabled
+func ValidateClusterAutoscaler(c kubeone.KubeOneCluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	enabled := c.Features.ClusterAutoscaler != nil && c.Features.ClusterAutoscaler.Enable
+	if enabled {
+		featurePath := fldPath.Child("features", "clusterAutoscaler")
+		ca := c.Features.ClusterAutoscaler
+
+		switch ca.Expander {
+		case "", "random", "most-pods", "least-waste", "priority":
+		default:
+			allErrs = append(allErrs, field.NotSupported(featurePath.Child("expander"), ca.Expander, []string{"random", "most-pods", "least-waste", "priority"}))
+		}
+		if d := ca.ScaleDownUnneededTime; d != nil && d.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(featurePath.Child("scaleDownUnneededTime"), d.Duration.String(), "scaleDownUnneededTime must be positive"))
+		}
+		if d := ca.ScaleDownDelayAfterAdd; d != nil && d.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(featurePath.Child("scaleDownDelayAfterAdd"), d.Duration.String(), "scaleDownDelayAfterAdd must be positive"))
+		}
+	}
+
+	for i, workerset := range c.DynamicWorkers {
+		as := workerset.Autoscaling
+		if as == nil {
+			continue
+		}
+
+		asPath := fldPath.Child("dynamicWorkers").Index(i).Child("autoscaling")
+		if !enabled {
+			allErrs = append(allErrs, field.Invalid(asPath, workerset.Name, "autoscaling requires the clusterAutoscaler feature to be enabled"))
+			continue
+		}
+		if as.MinReplicas < 1 {
+			allErrs = append(allErrs, field.Invalid(asPath.Child("minReplicas"), as.MinReplicas, "minReplicas must be greater than 0"))
+		}
+		if as.MaxReplicas < as.MinReplicas {
+			allErrs = append(allErrs, field.Invalid(asPath.Child("maxReplicas"), as.MaxReplicas, "maxReplicas can't be less than minReplicas"))
+		}
+		if r := workerset.Replicas; r != nil && (*r < as.MinReplicas || *r > as.MaxReplicas) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("dynamicWorkers").Index(i).Child("replicas"), *r, "replicas must be between autoscaling.minReplicas and autoscaling.maxReplicas"))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateCertManager validates the CertManager feature and the ClusterIssuer
+// bootstrapped by KubeOne
+func ValidateCertManager(c kubeone.KubeOneCluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	cm := c.Features.CertManager
+	if cm == nil || cm.ClusterIssuer == nil {
+		return allErrs
+	}
+
+	issuerPath := fldPath.Child("clusterIssuer")
+	if !cm.Enable {
+		allErrs = append(allErrs, field.Forbidden(issuerPath, "clusterIssuer requires the certManager feature to be enabled"))
+		return allErrs
+	}
+
+	if cm.ClusterIssuer.Name != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(cm.ClusterIssuer.Name) {
+			allErrs = append(allErrs, field.Invalid(issuerPath.Child("name"), cm.ClusterIssuer.Name, msg))
+		}
+	}
+	if cm.ClusterIssuer.CASecretName != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(cm.ClusterIssuer.CASecretName) {
+			allErrs = append(allErrs, field.Invalid(issuerPath.Child("caSecretName"), cm.ClusterIssuer.CASecretName, msg))
+		}
+	} else if c.ExternalCASignerEnabled() {
+		allErrs = append(allErrs, field.Required(issuerPath.Child("caSecretName"), "caSecretName is required when the cluster CA key is not available to KubeOne (external CA signer)"))
+	}
+
+	return allErrs
+}
+
+// ValidateVelero validates the Velero feature
+func ValidateVelero(c kubeone.KubeOneCluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	v := c.Features.Velero
+	if !v.Enabled() {
+		return allErrs
+	}
+
+	provider := c.VeleroProvider()
+	switch provider {
+	case kubeone.VeleroProviderAWS:
+		if v.Config["region"] == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("config", "region"), "region is required for the aws provider"))
+		}
+	case kubeone.VeleroProviderGCP:
+	case kubeone.VeleroProviderAzure:
+		for _, key := range []string{"resourceGroup", "storageAccount"} {
+			if v.Config[key] == "" {
+				allErrs = append(allErrs, field.Required(fldPath.Child("config", key), key+" is required for the azure provider"))
+			}
+		}
+	case "":
+		allErrs = append(allErrs, field.Required(fldPath.Child("provider"), "provider is required for the cloud providers without the object storage supported by Velero"))
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("provider"), provider, []string{
+			string(kubeone.VeleroProviderAWS),
+			string(kubeone.VeleroProviderGCP),
+			string(kubeone.VeleroProviderAzure),
+		}))
+	}
+
+	if v.Bucket == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("bucket"), "bucket is required"))
+	} else if strings.Contains(v.Bucket, "/") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("bucket"), v.Bucket, "bucket must not contain slashes, use prefix for storing the backups in a directory"))
+	}
+	if strings.HasPrefix(v.Prefix, "/") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("prefix"), v.Prefix, "prefix must be relative to the root of the bucket"))
+	}
+
+	schedulePath := fldPath.Child("schedule")
+	if v.Schedule.Cron != "" && !strings.HasPrefix(v.Schedule.Cron, "@") && len(strings.Fields(v.Schedule.Cron)) != 5 {
+		allErrs = append(allErrs, field.Invalid(schedulePath.Child("cron"), v.Schedule.Cron, "cron must be a cron expression with five fields or a descriptor such as @daily"))
+	}
+	if v.Schedule.TTL != nil && v.Schedule.TTL.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(schedulePath.Child("ttl"), v.Schedule.TTL.Duration.String(), "ttl must be positive"))
+	}
+
+	return allErrs
+}
+
+// ValidatePodNodeSelectorConfig validates the PodNodeSelectorConfig structure
+func ValidatePodNodeSelectorConfig(n kubeone.PodNodeSelectorConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(n.ConfigFilePath) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("configFilePath"), ".podNodeSelector.config.configFilePath is a required field"))
+	}
+
+	return allErrs
+}
+
+// ValidateStaticAuditLogConfig validates the StaticAuditLogConfig structure
+func ValidateStaticAuditLogConfig(s kubeone.StaticAuditLogConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(s.PolicyFilePath) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("policyFilePath"), ".staticAuditLog.config.policyFilePath is a required field"))
+	}
+	if len(s.LogPath) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("logPath"), ".staticAuditLog.config.logPath is a required field"))
+	}
+	if s.LogMaxAge <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("logMaxAge"), s.LogMaxAge, ".staticAuditLog.config.logMaxAge must be greater than 0"))
+	}
+	if s.LogMaxBackup <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("logMaxBackup"), s.LogMaxBackup, ".staticAuditLog.config.logMaxBackup must be greater than 0"))
+	}
+	if s.LogMaxSize <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("logMaxSize"), s.LogMaxSize, ".staticAuditLog.config.logMaxSize must be greater than 0"))
+	}
+
+	return allErrs
+}
+
+// ValidateOIDCConfig validates the OpenIDConnectConfig structure
+func ValidateOIDCConfig(o kubeone.OpenIDConnectConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(o.IssuerURL) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("issuerURL"), ".openidConnect.config.issuerURL is a required field"))
+	}
+	if len(o.ClientID) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("clientID"), ".openidConnect.config.clientID is a required field"))
+	}
+
+	return allErrs
+}
+
+// ValidateAddons validates the Addons configuration
+func ValidateAddons(o *kubeone.Addons, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if o == nil || !o.Enable {
+		return allErrs
+	}
+	if o.Enable && len(o.Path) == 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("path"), "", ".addons.path must be specified"))
+	}
+
+	return allErrs
+}
+
+// ValidateHostConfig validates the HostConfig structure
+func ValidateHostConfig(hosts []kubeone.HostConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	leaderFound := false
+	for _, h := range hosts {
+		if leaderFound && h.IsLeader {
+			allErrs = append(allErrs, field.Invalid(fldPath, h.IsLeader, "only one leader is allowed"))
 		}
 		if h.IsLeader {
 			leaderFound = true
@@ -486,6 +1371,70 @@ func ValidateHostConfig(hosts []kubeone.HostConfig, fldPath *field.Path) field.E
 		if len(h.SSHUsername) == 0 {
 			allErrs = append(allErrs, field.Required(fldPath, "no SSH username given"))
 		}
+		if p := h.SSHPrivateKeyPassphrase; p != "" && !strings.HasPrefix(p, "env:") && !secretstore.IsReference(p) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("sshPrivateKeyPassphrase"), "<redacted>", "passphrase must be given as reference to the environment variable (env:<name>) or to the external secret store"))
+		}
+		for k, v := range h.Labels {
+			for _, msg := range validation.IsQualifiedName(k) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("labels").Key(k), k, msg))
+			}
+			for _, msg := range validation.IsValidLabelValue(v) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("labels").Key(k), v, msg))
+			}
+		}
+		if len(h.BastionHosts) > 0 && h.Bastion != "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("bastionHosts"), h.BastionHosts, "bastionHosts and bastion are mutually exclusive"))
+		}
+		for i, bh := range h.BastionHosts {
+			if bh.Host == "" {
+				allErrs = append(allErrs, field.Required(fldPath.Child("bastionHosts").Index(i).Child("host"), "no bastion host address given"))
+			}
+		}
+		if h.SSHCertFile != "" && h.SSHPrivateKeyFile == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("sshCertFile"), h.SSHCertFile, "sshCertFile requires sshPrivateKeyFile to be configured"))
+		}
+		allErrs = append(allErrs, ValidateNodeDrainConfig(h.NodeDrain, fldPath.Child("nodeDrain"))...)
+		allErrs = append(allErrs, ValidateIgnorePreflightErrorsConfig(h.IgnorePreflightErrors, fldPath.Child("ignorePreflightErrors"))...)
+		allErrs = append(allErrs, ValidateKubeletConfig(h.Kubelet, fldPath.Child("kubelet"))...)
+	}
+
+	return allErrs
+}
+
+// ValidateKubeletConfig validates the KubeletConfig structure
+func ValidateKubeletConfig(c *kubeone.KubeletConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if c == nil {
+		return allErrs
+	}
+
+	if c.MaxPods != nil && *c.MaxPods <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxPods"), *c.MaxPods, "maxPods must be positive"))
+	}
+
+	for name, reserved := range map[string]map[string]string{
+		"systemReserved": c.SystemReserved,
+		"kubeReserved":   c.KubeReserved,
+	} {
+		for k, v := range reserved {
+			if k != string(corev1.ResourceCPU) && k != string(corev1.ResourceMemory) &&
+				k != string(corev1.ResourceEphemeralStorage) && k != "pid" {
+				allErrs = append(allErrs, field.NotSupported(fldPath.Child(name).Key(k), k, []string{"cpu", "memory", "ephemeral-storage", "pid"}))
+			}
+			if _, err := resource.ParseQuantity(v); err != nil {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child(name).Key(k), v, err.Error()))
+			}
+		}
+	}
+
+	for k, v := range c.EvictionHard {
+		if !evictionSignals.Has(k) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("evictionHard").Key(k), k, evictionSignals.List()))
+		}
+		if _, err := resource.ParseQuantity(strings.TrimSuffix(v, "%")); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("evictionHard").Key(k), v, "threshold must be a quantity or a percentage"))
+		}
 	}
 
 	return allErrs
@@ -505,6 +1454,402 @@ func ValidateRegistryConfiguration(r *kubeone.RegistryConfiguration, fldPath *fi
 	return allErrs
 }
 
+// ValidateRBACConfig validates the RBACConfig structure
+func ValidateRBACConfig(r *kubeone.RBACConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if r == nil {
+		return allErrs
+	}
+
+	clusterRoleNames := map[string]bool{}
+	for i, cr := range r.ClusterRoles {
+		crPath := fldPath.Child("clusterRoles").Index(i)
+		if cr.Name == "" {
+			allErrs = append(allErrs, field.Required(crPath.Child("name"), "name is a required field"))
+		}
+		if clusterRoleNames[cr.Name] {
+			allErrs = append(allErrs, field.Duplicate(crPath.Child("name"), cr.Name))
+		}
+		clusterRoleNames[cr.Name] = true
+		if len(cr.Rules) == 0 {
+			allErrs = append(allErrs, field.Required(crPath.Child("rules"), "at least one rule is required"))
+		}
+	}
+
+	clusterRoleBindingNames := map[string]bool{}
+	for i, crb := range r.ClusterRoleBindings {
+		crbPath := fldPath.Child("clusterRoleBindings").Index(i)
+		allErrs = append(allErrs, validateRBACRoleBinding(crb, crbPath)...)
+		if crb.Namespace != "" {
+			allErrs = append(allErrs, field.Forbidden(crbPath.Child("namespace"), "namespace can't be set for ClusterRoleBindings"))
+		}
+		if clusterRoleBindingNames[crb.Name] {
+			allErrs = append(allErrs, field.Duplicate(crbPath.Child("name"), crb.Name))
+		}
+		clusterRoleBindingNames[crb.Name] = true
+	}
+
+	roleBindingNames := map[string]bool{}
+	for i, rb := range r.RoleBindings {
+		rbPath := fldPath.Child("roleBindings").Index(i)
+		allErrs = append(allErrs, validateRBACRoleBinding(rb, rbPath)...)
+		if rb.Namespace == "" {
+			allErrs = append(allErrs, field.Required(rbPath.Child("namespace"), "namespace is a required field for RoleBindings"))
+		}
+		key := rb.Namespace + "/" + rb.Name
+		if roleBindingNames[key] {
+			allErrs = append(allErrs, field.Duplicate(rbPath.Child("name"), rb.Name))
+		}
+		roleBindingNames[key] = true
+	}
+
+	return allErrs
+}
+
+func validateRBACRoleBinding(rb kubeone.RBACRoleBinding, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if rb.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("name"), "name is a required field"))
+	}
+	if rb.ClusterRole == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("clusterRole"), "clusterRole is a required field"))
+	}
+	if len(rb.Groups) == 0 && len(rb.Users) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath, "at least one group or user is required"))
+	}
+
+	return allErrs
+}
+
+// ValidateNodeDrainConfig validates the NodeDrainConfig structure
+func ValidateNodeDrainConfig(d *kubeone.NodeDrainConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if d == nil {
+		return allErrs
+	}
+
+	if d.Timeout != nil && d.Timeout.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeout"), d.Timeout.Duration.String(), "timeout can't be negative"))
+	}
+	if d.SkipWaitForDeleteTimeoutSeconds != nil && *d.SkipWaitForDeleteTimeoutSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("skipWaitForDeleteTimeoutSeconds"), *d.SkipWaitForDeleteTimeoutSeconds, "skipWaitForDeleteTimeoutSeconds can't be negative"))
+	}
+
+	return allErrs
+}
+
+// ValidateIgnorePreflightErrorsConfig validates the IgnorePreflightErrorsConfig structure
+func ValidateIgnorePreflightErrorsConfig(c *kubeone.IgnorePreflightErrorsConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if c == nil {
+		return allErrs
+	}
+
+	allErrs = append(allErrs, validatePreflightErrors(c.Install, fldPath.Child("install"))...)
+	allErrs = append(allErrs, validatePreflightErrors(c.Upgrade, fldPath.Child("upgrade"))...)
+
+	return allErrs
+}
+
+func validatePreflightErrors(preflightErrors []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, preflightError := range preflightErrors {
+		if !preflightErrorRegexp.MatchString(preflightError) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), preflightError, "preflight error must consist of alphanumeric characters, '.', '_' or '-'"))
+		}
+		if strings.EqualFold(preflightError, "all") && len(preflightErrors) > 1 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), preflightError, "other preflight errors can't be specified along with \"all\""))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateManifestPatches validates the ManifestPatch structures
+func ValidateManifestPatches(patches []kubeone.ManifestPatch, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, patch := range patches {
+		patchPath := fldPath.Index(i)
+
+		switch {
+		case patch.MergePatch == "" && patch.JSONPatch == "":
+			allErrs = append(allErrs, field.Required(patchPath, "either mergePatch or jsonPatch must be specified"))
+		case patch.MergePatch != "" && patch.JSONPatch != "":
+			allErrs = append(allErrs, field.Forbidden(patchPath, "only one of mergePatch and jsonPatch can be specified"))
+		case patch.MergePatch != "":
+			mergePatch := map[string]interface{}{}
+			if err := yaml.Unmarshal([]byte(patch.MergePatch), &mergePatch); err != nil {
+				allErrs = append(allErrs, field.Invalid(patchPath.Child("mergePatch"), patch.MergePatch, fmt.Sprintf("merge patch must be an object: %v", err)))
+			}
+		case patch.JSONPatch != "":
+			patchJSON, err := yaml.YAMLToJSON([]byte(patch.JSONPatch))
+			if err == nil {
+				_, err = jsonpatch.DecodePatch(patchJSON)
+			}
+			if err != nil {
+				allErrs = append(allErrs, field.Invalid(patchPath.Child("jsonPatch"), patch.JSONPatch, fmt.Sprintf("invalid JSON patch: %v", err)))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// ValidatePreflightConfig validates the PreflightConfig structure
+func ValidatePreflightConfig(c *kubeone.PreflightConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if c == nil {
+		return allErrs
+	}
+
+	allErrs = append(allErrs, validatePreflightChecks(c.Skip, fldPath.Child("skip"))...)
+	allErrs = append(allErrs, validatePreflightChecks(c.WarnOnly, fldPath.Child("warnOnly"))...)
+
+	for i, check := range c.WarnOnly {
+		for _, skipped := range c.Skip {
+			if strings.EqualFold(check, skipped) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("warnOnly").Index(i), check, "check can't be both skipped and warning-only"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validatePreflightChecks(checks []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, check := range checks {
+		if strings.HasPrefix(check, kubeone.KubeadmPreflightCheckPrefix) {
+			allErrs = append(allErrs, validatePreflightErrors([]string{strings.TrimPrefix(check, kubeone.KubeadmPreflightCheckPrefix)}, fldPath.Index(i))...)
+			continue
+		}
+
+		known := false
+		for _, c := range kubeone.PreflightChecks {
+			if strings.EqualFold(check, c) {
+				known = true
+			}
+		}
+		if !known {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Index(i), check, kubeone.PreflightChecks))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateClusterSizeConfig validates the ClusterSizeConfig structure
+func ValidateClusterSizeConfig(c *kubeone.ClusterSizeConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if c == nil {
+		return allErrs
+	}
+
+	if c.Nodes < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("nodes"), c.Nodes, "nodes must not be negative"))
+	}
+	if c.Pods < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("pods"), c.Pods, "pods must not be negative"))
+	}
+	if c.Nodes == 0 && c.Pods == 0 {
+		allErrs = append(allErrs, field.Required(fldPath, "at least one of nodes and pods must be specified"))
+	}
+
+	return allErrs
+}
+
+// ValidateHostRebootConfig validates the HostRebootConfig structure
+func ValidateHostRebootConfig(c *kubeone.HostRebootConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if c == nil {
+		return allErrs
+	}
+
+	if c.Timeout != nil && c.Timeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeout"), c.Timeout.Duration.String(), "timeout must be positive"))
+	}
+
+	return allErrs
+}
+
+// ValidateOSTuningConfig validates the OSTuningConfig structure
+func ValidateOSTuningConfig(c *kubeone.OSTuningConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if c == nil {
+		return allErrs
+	}
+
+	allErrs = append(allErrs, validateOSTuning(c.All, fldPath.Child("all"))...)
+	allErrs = append(allErrs, validateOSTuning(c.ControlPlane, fldPath.Child("controlPlane"))...)
+	allErrs = append(allErrs, validateOSTuning(c.StaticWorkers, fldPath.Child("staticWorkers"))...)
+
+	return allErrs
+}
+
+func validateOSTuning(t kubeone.OSTuning, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, module := range t.KernelModules {
+		if !kernelModuleRegexp.MatchString(module) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("kernelModules").Index(i), module, "kernel module name must consist of alphanumeric characters, '-' or '_'"))
+		}
+	}
+
+	for key, value := range t.Sysctls {
+		if !sysctlKeyRegexp.MatchString(key) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("sysctls").Key(key), key, "sysctl name must consist of alphanumeric characters, '-' or '_', separated by '.' or '/'"))
+		}
+		if strings.TrimSpace(value) == "" || strings.ContainsAny(value, "\n\r") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("sysctls").Key(key), value, "sysctl value must be a non-empty single line"))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateTimeSyncConfig validates the TimeSyncConfig structure
+func ValidateTimeSyncConfig(c *kubeone.TimeSyncConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if c == nil {
+		return allErrs
+	}
+
+	if len(c.NTPServers) > 0 && !c.Chrony {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("ntpServers"), "ntpServers requires chrony to be enabled"))
+	}
+	for i, server := range c.NTPServers {
+		if net.ParseIP(server) != nil {
+			continue
+		}
+		for _, msg := range validation.IsDNS1123Subdomain(server) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ntpServers").Index(i), server, msg))
+		}
+	}
+
+	if c.MaxClockSkew != nil && c.MaxClockSkew.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxClockSkew"), c.MaxClockSkew.Duration.String(), "maxClockSkew must be positive"))
+	}
+
+	return allErrs
+}
+
+// ValidateHardeningConfig validates the HardeningConfig structure
+func ValidateHardeningConfig(c *kubeone.HardeningConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if c == nil {
+		return allErrs
+	}
+
+	if c.Profile != kubeone.HardeningProfileCIS {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("profile"), c.Profile, []string{string(kubeone.HardeningProfileCIS)}))
+	}
+
+	return allErrs
+}
+
+// ValidateSecurityModulesConfig validates the SecurityModulesConfig structure
+func ValidateSecurityModulesConfig(c *kubeone.SecurityModulesConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if c == nil {
+		return allErrs
+	}
+
+	switch c.SELinux {
+	case "", kubeone.SELinuxModePermissive, kubeone.SELinuxModeEnforcing, kubeone.SELinuxModeUnmanaged:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("selinux"), c.SELinux, []string{
+			string(kubeone.SELinuxModePermissive),
+			string(kubeone.SELinuxModeEnforcing),
+			string(kubeone.SELinuxModeUnmanaged),
+		}))
+	}
+
+	switch c.AppArmor {
+	case "", kubeone.AppArmorModeConfigure, kubeone.AppArmorModeUnmanaged:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("appArmor"), c.AppArmor, []string{
+			string(kubeone.AppArmorModeConfigure),
+			string(kubeone.AppArmorModeUnmanaged),
+		}))
+	}
+
+	return allErrs
+}
+
+// ValidateFirewallConfig validates the FirewallConfig structure. The private
+// addresses of all hosts must be in the node CIDRs, otherwise the hosts can't
+// reach each other.
+func ValidateFirewallConfig(c kubeone.KubeOneCluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if c.Firewall == nil {
+		return allErrs
+	}
+
+	switch c.Firewall.Backend {
+	case "", kubeone.FirewallBackendFirewalld, kubeone.FirewallBackendUFW, kubeone.FirewallBackendNFTables:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("backend"), c.Firewall.Backend, []string{
+			string(kubeone.FirewallBackendFirewalld),
+			string(kubeone.FirewallBackendUFW),
+			string(kubeone.FirewallBackendNFTables),
+		}))
+	}
+
+	if len(c.Firewall.NodeCIDRs) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("nodeCIDRs"), "at least one node CIDR is required"))
+	}
+
+	nodeNets := []*net.IPNet{}
+	for i, cidr := range c.Firewall.NodeCIDRs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("nodeCIDRs").Index(i), cidr, "must be a valid CIDR string"))
+			continue
+		}
+		nodeNets = append(nodeNets, ipNet)
+	}
+	if len(nodeNets) != len(c.Firewall.NodeCIDRs) {
+		return allErrs
+	}
+
+	hosts := append([]kubeone.HostConfig{}, c.ControlPlane.Hosts...)
+	hosts = append(hosts, c.StaticWorkers.Hosts...)
+	for _, host := range hosts {
+		ip := net.ParseIP(host.PrivateAddress)
+		if ip == nil {
+			continue
+		}
+
+		found := false
+		for _, ipNet := range nodeNets {
+			if ipNet.Contains(ip) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("nodeCIDRs"), c.Firewall.NodeCIDRs, fmt.Sprintf("private address %s of host %s is not in the node CIDRs", host.PrivateAddress, host.PublicAddress)))
+		}
+	}
+
+	return allErrs
+}
+
 func ValidateAssetConfiguration(a *kubeone.AssetConfiguration, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -519,18 +1864,90 @@ func ValidateAssetConfiguration(a *kubeone.AssetConfiguration, fldPath *field.Pa
 		allErrs = append(allErrs, field.Required(fldPath.Child("imageRepository"), "imageRepository for sandbox (pause) image is required"))
 	}
 
-	found := 0
-	if a.CNI.URL != "" {
-		found++
+	if a.BinariesFromAssets() {
+		allErrs = append(allErrs, validateBinariesFromAssets(a, fldPath)...)
+	} else {
+		found := 0
+		if a.CNI.URL != "" {
+			found++
+		}
+		if a.NodeBinaries.URL != "" {
+			found++
+		}
+		if a.Kubectl.URL != "" {
+			found++
+		}
+		if found != 0 && found != 3 {
+			allErrs = append(allErrs, field.Invalid(fldPath, "", "all binary assets must be specified (cni, nodeBinaries, kubectl)"))
+		}
+	}
+
+	binaryAssets := []struct {
+		name  string
+		asset kubeone.BinaryAsset
+	}{
+		{"cni", a.CNI},
+		{"nodeBinaries", a.NodeBinaries},
+		{"kubectl", a.Kubectl},
+		{"kubeadm", a.Kubeadm},
+		{"kubelet", a.Kubelet},
+	}
+	for _, ba := range binaryAssets {
+		if ba.asset.SHA256 != "" && !sha256Regexp.MatchString(ba.asset.SHA256) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child(ba.name).Child("sha256"), ba.asset.SHA256, "sha256 must be a hex-encoded SHA256 checksum"))
+		}
+	}
+
+	if sv := a.SignatureVerification; sv != nil {
+		svPath := fldPath.Child("signatureVerification")
+		if sv.PublicKey != "" {
+			if block, _ := pem.Decode([]byte(sv.PublicKey)); block == nil {
+				allErrs = append(allErrs, field.Invalid(svPath.Child("publicKey"), "", "publicKey must be a PEM-encoded public key"))
+			}
+		} else {
+			if sv.CertificateIdentity == "" {
+				allErrs = append(allErrs, field.Required(svPath.Child("certificateIdentity"), "certificateIdentity is required for the keyless verification"))
+			}
+			if sv.CertificateOIDCIssuer == "" {
+				allErrs = append(allErrs, field.Required(svPath.Child("certificateOIDCIssuer"), "certificateOIDCIssuer is required for the keyless verification"))
+			}
+		}
 	}
+
+	return allErrs
+}
+
+// validateBinariesFromAssets validates that kubeadm, kubelet, kubectl and CNI
+// plugins can be downloaded from the binary assets
+func validateBinariesFromAssets(a *kubeone.AssetConfiguration, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
 	if a.NodeBinaries.URL != "" {
-		found++
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("nodeBinaries"), "nodeBinaries can't be used together with kubeadm, kubelet and binariesMirror"))
 	}
-	if a.Kubectl.URL != "" {
-		found++
+
+	if a.BinariesMirror != "" {
+		u, err := url.Parse(a.BinariesMirror)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("binariesMirror"), a.BinariesMirror, "binariesMirror must be a valid http or https URL"))
+		}
+
+		return allErrs
 	}
-	if found != 0 && found != 3 {
-		allErrs = append(allErrs, field.Invalid(fldPath, "", "all binary assets must be specified (cni, nodeBinaries, kubectl)"))
+
+	binaryAssets := []struct {
+		name  string
+		asset kubeone.BinaryAsset
+	}{
+		{"kubeadm", a.Kubeadm},
+		{"kubelet", a.Kubelet},
+		{"kubectl", a.Kubectl},
+		{"cni", a.CNI},
+	}
+	for _, ba := range binaryAssets {
+		if ba.asset.URL == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child(ba.name).Child("url"), "kubeadm, kubelet, kubectl and cni must be specified if binariesMirror is not used"))
+		}
 	}
 
 	return allErrs
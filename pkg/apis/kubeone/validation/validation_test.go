@@ -17,12 +17,23 @@ limitations under the License.
 package validation
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
 	"testing"
+	"time"
 
 	"github.com/MakeNowJust/heredoc/v2"
 
 	"k8c.io/kubeone/pkg/apis/kubeone"
 
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -392,6 +403,21 @@ func TestValidateCloudProviderSpec(t *testing.T) {
 			},
 			expectedError: false,
 		},
+		{
+			name: "AWS provider config with EBS CSI",
+			providerConfig: kubeone.CloudProviderSpec{
+				AWS:      &kubeone.AWSSpec{EBSCSI: &kubeone.AWSEBSCSIConfig{Enable: true}},
+				External: true,
+			},
+			expectedError: false,
+		},
+		{
+			name: "AWS provider config with EBS CSI (external disabled)",
+			providerConfig: kubeone.CloudProviderSpec{
+				AWS: &kubeone.AWSSpec{EBSCSI: &kubeone.AWSEBSCSIConfig{Enable: true}},
+			},
+			expectedError: true,
+		},
 		{
 			name: "valid Azure provider config",
 			providerConfig: kubeone.CloudProviderSpec{
@@ -400,6 +426,56 @@ func TestValidateCloudProviderSpec(t *testing.T) {
 			},
 			expectedError: false,
 		},
+		{
+			name: "Azure provider config with generated cloud-config",
+			providerConfig: kubeone.CloudProviderSpec{
+				Azure: &kubeone.AzureSpec{
+					CloudConfig: &kubeone.AzureCloudConfig{
+						ResourceGroup:     "rg",
+						Location:          "westeurope",
+						VNetName:          "vnet",
+						SubnetName:        "subnet",
+						SecurityGroupName: "nsg",
+					},
+					FileCSI: &kubeone.AzureComponent{Enable: boolPtr(false)},
+				},
+				External: true,
+			},
+			expectedError: false,
+		},
+		{
+			name: "Azure provider config with incomplete generated cloud-config",
+			providerConfig: kubeone.CloudProviderSpec{
+				Azure: &kubeone.AzureSpec{
+					CloudConfig: &kubeone.AzureCloudConfig{ResourceGroup: "rg", Location: "westeurope"},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "Azure provider config with invalid load balancer SKU",
+			providerConfig: kubeone.CloudProviderSpec{
+				Azure: &kubeone.AzureSpec{
+					CloudConfig: &kubeone.AzureCloudConfig{
+						ResourceGroup:     "rg",
+						Location:          "westeurope",
+						VNetName:          "vnet",
+						SubnetName:        "subnet",
+						SecurityGroupName: "nsg",
+						LoadBalancerSku:   "premium",
+					},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "Azure provider config with components (external disabled)",
+			providerConfig: kubeone.CloudProviderSpec{
+				Azure:       &kubeone.AzureSpec{DiskCSI: &kubeone.AzureComponent{Enable: boolPtr(false)}},
+				CloudConfig: "cloud-config",
+			},
+			expectedError: true,
+		},
 		{
 			name: "valid DigitalOcean provider config",
 			providerConfig: kubeone.CloudProviderSpec{
@@ -590,6 +666,127 @@ func TestValidateCloudProviderSpec(t *testing.T) {
 			},
 			expectedError: true,
 		},
+		{
+			name: "vSphere provider config with csi",
+			providerConfig: kubeone.CloudProviderSpec{
+				Vsphere: &kubeone.VsphereSpec{
+					CSI: &kubeone.VsphereCSIConfig{
+						Datacenters: []string{"dc-1"},
+						StorageClass: &kubeone.VsphereCSIStorageClass{
+							DatastoreURL:      "ds:///vmfs/volumes/vsan:52cdfa80721ff516-ea1e993113acfc77/",
+							StoragePolicyName: "gold",
+						},
+						Snapshots: true,
+					},
+				},
+				External:    true,
+				CloudConfig: "test",
+			},
+			expectedError: false,
+		},
+		{
+			name: "vSphere provider config with csi and csiConfig",
+			providerConfig: kubeone.CloudProviderSpec{
+				Vsphere: &kubeone.VsphereSpec{
+					CSI: &kubeone.VsphereCSIConfig{Snapshots: true},
+				},
+				External:    true,
+				CloudConfig: "test",
+				CSIConfig:   "test",
+			},
+			expectedError: false,
+		},
+		{
+			name: "vSphere provider config with csi without datacenters",
+			providerConfig: kubeone.CloudProviderSpec{
+				Vsphere: &kubeone.VsphereSpec{
+					CSI: &kubeone.VsphereCSIConfig{},
+				},
+				External:    true,
+				CloudConfig: "test",
+			},
+			expectedError: true,
+		},
+		{
+			name: "vSphere provider config with csi (external disabled)",
+			providerConfig: kubeone.CloudProviderSpec{
+				Vsphere: &kubeone.VsphereSpec{
+					CSI: &kubeone.VsphereCSIConfig{Datacenters: []string{"dc-1"}},
+				},
+				CloudConfig: "test",
+			},
+			expectedError: true,
+		},
+		{
+			name: "vSphere provider config with csi (invalid datastore URL)",
+			providerConfig: kubeone.CloudProviderSpec{
+				Vsphere: &kubeone.VsphereSpec{
+					CSI: &kubeone.VsphereCSIConfig{
+						Datacenters:  []string{"dc-1"},
+						StorageClass: &kubeone.VsphereCSIStorageClass{DatastoreURL: "datastore1"},
+					},
+				},
+				External:    true,
+				CloudConfig: "test",
+			},
+			expectedError: true,
+		},
+		{
+			name: "OpenStack provider config with managed components",
+			providerConfig: kubeone.CloudProviderSpec{
+				Openstack: &kubeone.OpenstackSpec{
+					CCM: &kubeone.OpenstackComponent{
+						CloudConfigSections: map[string]map[string]string{
+							"LoadBalancer": {"use-octavia": "true"},
+						},
+					},
+					CinderCSI: &kubeone.OpenstackComponent{Enable: boolPtr(false)},
+				},
+				External:    true,
+				CloudConfig: "test",
+			},
+			expectedError: false,
+		},
+		{
+			name: "OpenStack provider config with managed components (external disabled)",
+			providerConfig: kubeone.CloudProviderSpec{
+				Openstack: &kubeone.OpenstackSpec{
+					CCM: &kubeone.OpenstackComponent{Enable: boolPtr(false)},
+				},
+				CloudConfig: "test",
+			},
+			expectedError: true,
+		},
+		{
+			name: "OpenStack provider config with invalid cloud-config section",
+			providerConfig: kubeone.CloudProviderSpec{
+				Openstack: &kubeone.OpenstackSpec{
+					CinderCSI: &kubeone.OpenstackComponent{
+						CloudConfigSections: map[string]map[string]string{
+							"BlockStorage]": {"ignore-volume-az": "true"},
+						},
+					},
+				},
+				External:    true,
+				CloudConfig: "test",
+			},
+			expectedError: true,
+		},
+		{
+			name: "OpenStack provider config with invalid cloud-config option",
+			providerConfig: kubeone.CloudProviderSpec{
+				Openstack: &kubeone.OpenstackSpec{
+					CCM: &kubeone.OpenstackComponent{
+						CloudConfigSections: map[string]map[string]string{
+							"LoadBalancer": {"use-octavia = false\nlb-method": "ROUND_ROBIN"},
+						},
+					},
+				},
+				External:    true,
+				CloudConfig: "test",
+			},
+			expectedError: true,
+		},
 		{
 			name: "OpenStack provider config without csiConfig",
 			providerConfig: kubeone.CloudProviderSpec{
@@ -612,6 +809,41 @@ func TestValidateCloudProviderSpec(t *testing.T) {
 			providerConfig: kubeone.CloudProviderSpec{},
 			expectedError:  true,
 		},
+		{
+			name: "AWS provider config with credentials from vault",
+			providerConfig: kubeone.CloudProviderSpec{
+				AWS: &kubeone.AWSSpec{},
+				CredentialsVault: &kubeone.VaultCredentials{
+					Path: "aws/creds/kubeone",
+					Keys: map[string]string{
+						"AWS_ACCESS_KEY_ID":     "access_key",
+						"AWS_SECRET_ACCESS_KEY": "secret_key",
+					},
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "credentials from vault without path",
+			providerConfig: kubeone.CloudProviderSpec{
+				AWS:              &kubeone.AWSSpec{},
+				CredentialsVault: &kubeone.VaultCredentials{},
+			},
+			expectedError: true,
+		},
+		{
+			name: "credentials from vault with empty key",
+			providerConfig: kubeone.CloudProviderSpec{
+				AWS: &kubeone.AWSSpec{},
+				CredentialsVault: &kubeone.VaultCredentials{
+					Path: "secret/data/kubeone/aws",
+					Keys: map[string]string{
+						"AWS_ACCESS_KEY_ID": "",
+					},
+				},
+			},
+			expectedError: true,
+		},
 	}
 	for _, tc := range tests {
 		tc := tc
@@ -770,6 +1002,26 @@ func TestValidateCloudProviderSupportsKubernetes(t *testing.T) {
 			},
 			expectedError: false,
 		},
+		{
+			name: "vSphere 1.21.4 cluster with CSI snapshots",
+			providerConfig: kubeone.CloudProviderSpec{
+				Vsphere: &kubeone.VsphereSpec{CSI: &kubeone.VsphereCSIConfig{Snapshots: true}},
+			},
+			versionConfig: kubeone.VersionConfig{
+				Kubernetes: "1.21.4",
+			},
+			expectedError: false,
+		},
+		{
+			name: "vSphere 1.20.11 cluster with CSI snapshots",
+			providerConfig: kubeone.CloudProviderSpec{
+				Vsphere: &kubeone.VsphereSpec{CSI: &kubeone.VsphereCSIConfig{Snapshots: true}},
+			},
+			versionConfig: kubeone.VersionConfig{
+				Kubernetes: "1.20.11",
+			},
+			expectedError: true,
+		},
 		{
 			name: "vSphere 1.22.1 cluster",
 			providerConfig: kubeone.CloudProviderSpec{
@@ -843,6 +1095,70 @@ func TestValidateContainerRuntimeConfig(t *testing.T) {
 			versions:         kubeone.VersionConfig{Kubernetes: "1.21"},
 			expectedError:    false,
 		},
+		{
+			name: "containerd with cgroupfs driver",
+			containerRuntime: kubeone.ContainerRuntimeConfig{Containerd: &kubeone.ContainerRuntimeContainerd{
+				CgroupDriver:  "cgroupfs",
+				SystemdCgroup: boolPtr(false),
+			}},
+			versions:      kubeone.VersionConfig{Kubernetes: "1.22"},
+			expectedError: false,
+		},
+		{
+			name: "containerd with invalid cgroup driver",
+			containerRuntime: kubeone.ContainerRuntimeConfig{Containerd: &kubeone.ContainerRuntimeContainerd{
+				CgroupDriver: "foo",
+			}},
+			versions:      kubeone.VersionConfig{Kubernetes: "1.22"},
+			expectedError: true,
+		},
+		{
+			name: "containerd with systemdCgroup not matching cgroup driver",
+			containerRuntime: kubeone.ContainerRuntimeConfig{Containerd: &kubeone.ContainerRuntimeContainerd{
+				CgroupDriver:  "cgroupfs",
+				SystemdCgroup: boolPtr(true),
+			}},
+			versions:      kubeone.VersionConfig{Kubernetes: "1.22"},
+			expectedError: true,
+		},
+		{
+			name: "containerd with nvidia runtime",
+			containerRuntime: kubeone.ContainerRuntimeConfig{Containerd: &kubeone.ContainerRuntimeContainerd{
+				NvidiaRuntime: &kubeone.ContainerdNvidiaRuntime{
+					Enable:     true,
+					BinaryName: "/usr/local/bin/nvidia-container-runtime",
+				},
+			}},
+			versions:      kubeone.VersionConfig{Kubernetes: "1.22"},
+			expectedError: false,
+		},
+		{
+			name: "containerd with relative nvidia runtime binary",
+			containerRuntime: kubeone.ContainerRuntimeConfig{Containerd: &kubeone.ContainerRuntimeContainerd{
+				NvidiaRuntime: &kubeone.ContainerdNvidiaRuntime{
+					Enable:     true,
+					BinaryName: "nvidia-container-runtime",
+				},
+			}},
+			versions:      kubeone.VersionConfig{Kubernetes: "1.22"},
+			expectedError: true,
+		},
+		{
+			name: "containerd with valid config patch",
+			containerRuntime: kubeone.ContainerRuntimeConfig{Containerd: &kubeone.ContainerRuntimeContainerd{
+				ConfigPatch: "[plugins.\"io.containerd.grpc.v1.cri\"]\nmax_concurrent_downloads = 5\n",
+			}},
+			versions:      kubeone.VersionConfig{Kubernetes: "1.22"},
+			expectedError: false,
+		},
+		{
+			name: "containerd with invalid config patch",
+			containerRuntime: kubeone.ContainerRuntimeConfig{Containerd: &kubeone.ContainerRuntimeContainerd{
+				ConfigPatch: "[plugins\nfoo",
+			}},
+			versions:      kubeone.VersionConfig{Kubernetes: "1.22"},
+			expectedError: true,
+		},
 	}
 
 	for _, tc := range tests {
@@ -960,6 +1276,67 @@ func TestValidateCNIConfig(t *testing.T) {
 			},
 			expectedError: false,
 		},
+		{
+			name: "valid External CNI config with Helm chart",
+			cniConfig: &kubeone.CNI{
+				External: &kubeone.ExternalCNISpec{
+					HelmChart: &kubeone.HelmChart{
+						ReleaseName: "cilium",
+						Chart:       "cilium",
+						RepoURL:     "https://helm.cilium.io/",
+						Version:     "1.10.5",
+					},
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "External CNI config with Helm chart without release name",
+			cniConfig: &kubeone.CNI{
+				External: &kubeone.ExternalCNISpec{
+					HelmChart: &kubeone.HelmChart{
+						Chart: "cilium",
+					},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "External CNI config with Helm chart without version",
+			cniConfig: &kubeone.CNI{
+				External: &kubeone.ExternalCNISpec{
+					HelmChart: &kubeone.HelmChart{
+						ReleaseName: "cilium",
+						Chart:       "cilium",
+						RepoURL:     "https://helm.cilium.io/",
+					},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "External CNI config with local Helm chart without version",
+			cniConfig: &kubeone.CNI{
+				External: &kubeone.ExternalCNISpec{
+					HelmChart: &kubeone.HelmChart{
+						ReleaseName: "cilium",
+						Chart:       "./charts/cilium",
+					},
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "External CNI config with Helm chart without chart",
+			cniConfig: &kubeone.CNI{
+				External: &kubeone.ExternalCNISpec{
+					HelmChart: &kubeone.HelmChart{
+						ReleaseName: "cilium",
+					},
+				},
+			},
+			expectedError: true,
+		},
 		{
 			name: "Canal and WeaveNet specified at the same time",
 			cniConfig: &kubeone.CNI{
@@ -1135,60 +1512,181 @@ func TestValidateDynamicWorkerConfig(t *testing.T) {
 	}
 }
 
-func TestValidateCABundle(t *testing.T) {
+func TestValidateMachineControllerConfig(t *testing.T) {
 	tests := []struct {
-		name          string
-		caBundle      string
-		expectedError bool
+		name              string
+		machineController kubeone.MachineControllerConfig
+		versions          kubeone.VersionConfig
+		expectedError     bool
 	}{
 		{
-			name:          "empty",
-			caBundle:      "",
-			expectedError: false,
+			name:              "defaults",
+			machineController: kubeone.MachineControllerConfig{Deploy: true},
+			versions:          kubeone.VersionConfig{Kubernetes: "1.22.2"},
+			expectedError:     false,
 		},
 		{
-			name: "correct",
-			caBundle: heredoc.Doc(`
-				## some comments
-
-				GlobalSign Root CA
-				==================
-				-----BEGIN CERTIFICATE-----
-				MIIDdTCCAl2gAwIBAgILBAAAAAABFUtaw5QwDQYJKoZIhvcNAQEFBQAwVzELMAkGA1UEBhMCQkUx
-				GTAXBgNVBAoTEEdsb2JhbFNpZ24gbnYtc2ExEDAOBgNVBAsTB1Jvb3QgQ0ExGzAZBgNVBAMTEkds
-				b2JhbFNpZ24gUm9vdCBDQTAeFw05ODA5MDExMjAwMDBaFw0yODAxMjgxMjAwMDBaMFcxCzAJBgNV
-				BAYTAkJFMRkwFwYDVQQKExBHbG9iYWxTaWduIG52LXNhMRAwDgYDVQQLEwdSb290IENBMRswGQYD
-				VQQDExJHbG9iYWxTaWduIFJvb3QgQ0EwggEiMA0GCSqGSIb3DQEBAQUAA4IBDwAwggEKAoIBAQDa
-				DuaZjc6j40+Kfvvxi4Mla+pIH/EqsLmVEQS98GPR4mdmzxzdzxtIK+6NiY6arymAZavpxy0Sy6sc
-				THAHoT0KMM0VjU/43dSMUBUc71DuxC73/OlS8pF94G3VNTCOXkNz8kHp1Wrjsok6Vjk4bwY8iGlb
-				Kk3Fp1S4bInMm/k8yuX9ifUSPJJ4ltbcdG6TRGHRjcdGsnUOhugZitVtbNV4FpWi6cgKOOvyJBNP
-				c1STE4U6G7weNLWLBYy5d4ux2x8gkasJU26Qzns3dLlwR5EiUWMWea6xrkEmCMgZK9FGqkjWZCrX
-				gzT/LCrBbBlDSgeF59N89iFo7+ryUp9/k5DPAgMBAAGjQjBAMA4GA1UdDwEB/wQEAwIBBjAPBgNV
-				HRMBAf8EBTADAQH/MB0GA1UdDgQWBBRge2YaRQ2XyolQL30EzTSo//z9SzANBgkqhkiG9w0BAQUF
-				AAOCAQEA1nPnfE920I2/7LqivjTFKDK1fPxsnCwrvQmeU79rXqoRSLblCKOzyj1hTdNGCbM+w6Dj
-				Y1Ub8rrvrTnhQ7k4o+YviiY776BQVvnGCv04zcQLcFGUl5gE38NflNUVyRRBnMRddWQVDf9VMOyG
-				j/8N7yy5Y0b2qvzfvGn9LhJIZJrglfCm7ymPAbEVtQwdpf5pLGkkeB6zpxxxYu7KyJesF12KwvhH
-				hm4qxFYxldBniYUr+WymXUadDKqC5JlR3XC321Y9YeRq4VzW9v493kHMB65jUr9TU/Qr6cf9tveC
-				X4XSQRjbgbMEHMUfpIBvFSDJ3gyICh3WZlXi/EjJKSZp4A==
-				-----END CERTIFICATE-----
-			`),
+			name: "pinned release supporting kubernetes version",
+			machineController: kubeone.MachineControllerConfig{
+				ImageRepository: "registry.example.com/kubermatic/machine-controller",
+				ImageTag:        "v1.35.2",
+			},
+			versions:      kubeone.VersionConfig{Kubernetes: "1.22.2"},
 			expectedError: false,
 		},
 		{
-			name: "no certs but with comments",
-			caBundle: heredoc.Doc(`
-				# leading comment
-				## additional comment
-			`),
-			expectedError: true,
+			name:              "pinned release not supporting kubernetes version",
+			machineController: kubeone.MachineControllerConfig{ImageTag: "v1.30.0"},
+			versions:          kubeone.VersionConfig{Kubernetes: "1.22.2"},
+			expectedError:     true,
 		},
 		{
-			name:          "incorrect",
-			caBundle:      "garbadge",
-			expectedError: true,
+			name:              "pinned release not supporting newer kubernetes version",
+			machineController: kubeone.MachineControllerConfig{ImageTag: "v1.30.0"},
+			versions:          kubeone.VersionConfig{Kubernetes: "1.23.0"},
+			expectedError:     true,
 		},
-	}
-
+		{
+			name:              "custom build",
+			machineController: kubeone.MachineControllerConfig{ImageTag: "a1b2c3d"},
+			versions:          kubeone.VersionConfig{Kubernetes: "1.22.2"},
+			expectedError:     false,
+		},
+		{
+			name:              "invalid tag",
+			machineController: kubeone.MachineControllerConfig{ImageTag: "v1.36.0:latest"},
+			versions:          kubeone.VersionConfig{Kubernetes: "1.22.2"},
+			expectedError:     true,
+		},
+		{
+			name:              "repository with tag",
+			machineController: kubeone.MachineControllerConfig{ImageRepository: "docker.io/kubermatic/machine-controller:v1.36.0"},
+			versions:          kubeone.VersionConfig{Kubernetes: "1.22.2"},
+			expectedError:     true,
+		},
+		{
+			name: "requests greater than limits",
+			machineController: kubeone.MachineControllerConfig{
+				Resources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+					Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+				},
+			},
+			versions:      kubeone.VersionConfig{Kubernetes: "1.22.2"},
+			expectedError: true,
+		},
+		{
+			name: "node selector and tolerations",
+			machineController: kubeone.MachineControllerConfig{
+				NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+				Tolerations: []corev1.Toleration{
+					{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "infra", Effect: corev1.TaintEffectNoSchedule},
+				},
+			},
+			versions:      kubeone.VersionConfig{Kubernetes: "1.22.2"},
+			expectedError: false,
+		},
+		{
+			name: "invalid node selector",
+			machineController: kubeone.MachineControllerConfig{
+				NodeSelector: map[string]string{"node role": "infra"},
+			},
+			versions:      kubeone.VersionConfig{Kubernetes: "1.22.2"},
+			expectedError: true,
+		},
+		{
+			name: "toleration with value and exists operator",
+			machineController: kubeone.MachineControllerConfig{
+				Tolerations: []corev1.Toleration{
+					{Key: "dedicated", Operator: corev1.TolerationOpExists, Value: "infra"},
+				},
+			},
+			versions:      kubeone.VersionConfig{Kubernetes: "1.22.2"},
+			expectedError: true,
+		},
+		{
+			name: "extra flags",
+			machineController: kubeone.MachineControllerConfig{
+				ExtraFlags: map[string]string{"node-csr-approver": "false", "worker-count": "10"},
+			},
+			versions:      kubeone.VersionConfig{Kubernetes: "1.22.2"},
+			expectedError: false,
+		},
+		{
+			name: "extra flag with leading dash",
+			machineController: kubeone.MachineControllerConfig{
+				ExtraFlags: map[string]string{"-node-csr-approver": "false"},
+			},
+			versions:      kubeone.VersionConfig{Kubernetes: "1.22.2"},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateMachineControllerConfig(&tc.machineController, tc.versions, &field.Path{})
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateCABundle(t *testing.T) {
+	tests := []struct {
+		name          string
+		caBundle      string
+		expectedError bool
+	}{
+		{
+			name:          "empty",
+			caBundle:      "",
+			expectedError: false,
+		},
+		{
+			name: "correct",
+			caBundle: heredoc.Doc(`
+				## some comments
+
+				GlobalSign Root CA
+				==================
+				-----BEGIN CERTIFICATE-----
+				MIIDdTCCAl2gAwIBAgILBAAAAAABFUtaw5QwDQYJKoZIhvcNAQEFBQAwVzELMAkGA1UEBhMCQkUx
+				GTAXBgNVBAoTEEdsb2JhbFNpZ24gbnYtc2ExEDAOBgNVBAsTB1Jvb3QgQ0ExGzAZBgNVBAMTEkds
+				b2JhbFNpZ24gUm9vdCBDQTAeFw05ODA5MDExMjAwMDBaFw0yODAxMjgxMjAwMDBaMFcxCzAJBgNV
+				BAYTAkJFMRkwFwYDVQQKExBHbG9iYWxTaWduIG52LXNhMRAwDgYDVQQLEwdSb290IENBMRswGQYD
+				VQQDExJHbG9iYWxTaWduIFJvb3QgQ0EwggEiMA0GCSqGSIb3DQEBAQUAA4IBDwAwggEKAoIBAQDa
+				DuaZjc6j40+Kfvvxi4Mla+pIH/EqsLmVEQS98GPR4mdmzxzdzxtIK+6NiY6arymAZavpxy0Sy6sc
+				THAHoT0KMM0VjU/43dSMUBUc71DuxC73/OlS8pF94G3VNTCOXkNz8kHp1Wrjsok6Vjk4bwY8iGlb
+				Kk3Fp1S4bInMm/k8yuX9ifUSPJJ4ltbcdG6TRGHRjcdGsnUOhugZitVtbNV4FpWi6cgKOOvyJBNP
+				c1STE4U6G7weNLWLBYy5d4ux2x8gkasJU26Qzns3dLlwR5EiUWMWea6xrkEmCMgZK9FGqkjWZCrX
+				gzT/LCrBbBlDSgeF59N89iFo7+ryUp9/k5DPAgMBAAGjQjBAMA4GA1UdDwEB/wQEAwIBBjAPBgNV
+				HRMBAf8EBTADAQH/MB0GA1UdDgQWBBRge2YaRQ2XyolQL30EzTSo//z9SzANBgkqhkiG9w0BAQUF
+				AAOCAQEA1nPnfE920I2/7LqivjTFKDK1fPxsnCwrvQmeU79rXqoRSLblCKOzyj1hTdNGCbM+w6Dj
+				Y1Ub8rrvrTnhQ7k4o+YviiY776BQVvnGCv04zcQLcFGUl5gE38NflNUVyRRBnMRddWQVDf9VMOyG
+				j/8N7yy5Y0b2qvzfvGn9LhJIZJrglfCm7ymPAbEVtQwdpf5pLGkkeB6zpxxxYu7KyJesF12KwvhH
+				hm4qxFYxldBniYUr+WymXUadDKqC5JlR3XC321Y9YeRq4VzW9v493kHMB65jUr9TU/Qr6cf9tveC
+				X4XSQRjbgbMEHMUfpIBvFSDJ3gyICh3WZlXi/EjJKSZp4A==
+				-----END CERTIFICATE-----
+			`),
+			expectedError: false,
+		},
+		{
+			name: "no certs but with comments",
+			caBundle: heredoc.Doc(`
+				# leading comment
+				## additional comment
+			`),
+			expectedError: true,
+		},
+		{
+			name:          "incorrect",
+			caBundle:      "garbadge",
+			expectedError: true,
+		},
+	}
+
 	for _, tc := range tests {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
@@ -1324,29 +1822,52 @@ func TestValidateFeatures(t *testing.T) {
 	}
 }
 
-func TestValidatePodNodeSelectorConfig(t *testing.T) {
+func TestValidateMetricsServerConfig(t *testing.T) {
 	tests := []struct {
-		name                  string
-		podNodeSelectorConfig kubeone.PodNodeSelectorConfig
-		expectedError         bool
+		name          string
+		metricsServer kubeone.MetricsServer
+		expectedError bool
 	}{
 		{
-			name: "valid podNodeSelector config",
-			podNodeSelectorConfig: kubeone.PodNodeSelectorConfig{
-				ConfigFilePath: "./podnodeselector.yaml",
+			name:          "defaults",
+			metricsServer: kubeone.MetricsServer{Enable: true},
+			expectedError: false,
+		},
+		{
+			name: "replicas, resources and host network",
+			metricsServer: kubeone.MetricsServer{
+				Enable:   true,
+				Replicas: 2,
+				Resources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50m")},
+					Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+				},
+				HostNetwork: true,
 			},
 			expectedError: false,
 		},
 		{
-			name:                  "invalid podNodeSelector config",
-			podNodeSelectorConfig: kubeone.PodNodeSelectorConfig{},
-			expectedError:         true,
+			name:          "negative replicas",
+			metricsServer: kubeone.MetricsServer{Enable: true, Replicas: -1},
+			expectedError: true,
+		},
+		{
+			name: "requests greater than limits",
+			metricsServer: kubeone.MetricsServer{
+				Enable: true,
+				Resources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+					Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+				},
+			},
+			expectedError: true,
 		},
 	}
+
 	for _, tc := range tests {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
-			errs := ValidatePodNodeSelectorConfig(tc.podNodeSelectorConfig, nil)
+			errs := ValidateMetricsServerConfig(&tc.metricsServer, &field.Path{})
 			if (len(errs) == 0) == tc.expectedError {
 				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
 			}
@@ -1354,81 +1875,59 @@ func TestValidatePodNodeSelectorConfig(t *testing.T) {
 	}
 }
 
-func TestValidateStaticAuditLogConfig(t *testing.T) {
+func TestValidateIngressController(t *testing.T) {
 	tests := []struct {
-		name                 string
-		staticAuditLogConfig kubeone.StaticAuditLogConfig
-		expectedError        bool
+		name              string
+		ingressController kubeone.IngressController
+		expectedError     bool
 	}{
 		{
-			name: "valid staticAuditLog config",
-			staticAuditLogConfig: kubeone.StaticAuditLogConfig{
-				PolicyFilePath: "/etc/kubernetes/policy.yaml",
-				LogPath:        "/var/log/kubernetes",
-				LogMaxAge:      10,
-				LogMaxBackup:   10,
-				LogMaxSize:     100,
+			name:              "defaults",
+			ingressController: kubeone.IngressController{Enable: true},
+			expectedError:     false,
+		},
+		{
+			name: "replicas, NodePort and ingress class",
+			ingressController: kubeone.IngressController{
+				Enable:              true,
+				Replicas:            3,
+				ServiceType:         corev1.ServiceTypeNodePort,
+				IngressClass:        "public",
+				DefaultIngressClass: true,
 			},
 			expectedError: false,
 		},
 		{
-			name: "policy file path missing",
-			staticAuditLogConfig: kubeone.StaticAuditLogConfig{
-				LogPath:      "/var/log/kubernetes",
-				LogMaxAge:    10,
-				LogMaxBackup: 10,
-				LogMaxSize:   100,
-			},
-			expectedError: true,
+			name:              "host network",
+			ingressController: kubeone.IngressController{Enable: true, HostNetwork: true},
+			expectedError:     false,
 		},
 		{
-			name: "log file path missing",
-			staticAuditLogConfig: kubeone.StaticAuditLogConfig{
-				PolicyFilePath: "/etc/kubernetes/policy.yaml",
-				LogMaxAge:      10,
-				LogMaxBackup:   10,
-				LogMaxSize:     100,
-			},
-			expectedError: true,
+			name:              "negative replicas",
+			ingressController: kubeone.IngressController{Enable: true, Replicas: -1},
+			expectedError:     true,
 		},
 		{
-			name: "log max age set to 0",
-			staticAuditLogConfig: kubeone.StaticAuditLogConfig{
-				PolicyFilePath: "/etc/kubernetes/policy.yaml",
-				LogPath:        "/var/log/kubernetes",
-				LogMaxAge:      0,
-				LogMaxBackup:   10,
-				LogMaxSize:     100,
-			},
-			expectedError: true,
+			name:              "unsupported service type",
+			ingressController: kubeone.IngressController{Enable: true, ServiceType: corev1.ServiceTypeExternalName},
+			expectedError:     true,
 		},
 		{
-			name: "log max backup set to 0",
-			staticAuditLogConfig: kubeone.StaticAuditLogConfig{
-				PolicyFilePath: "/etc/kubernetes/policy.yaml",
-				LogPath:        "/var/log/kubernetes",
-				LogMaxAge:      10,
-				LogMaxBackup:   0,
-				LogMaxSize:     100,
-			},
-			expectedError: true,
+			name:              "service type with host network",
+			ingressController: kubeone.IngressController{Enable: true, ServiceType: corev1.ServiceTypeLoadBalancer, HostNetwork: true},
+			expectedError:     true,
 		},
 		{
-			name: "log max size set to 0",
-			staticAuditLogConfig: kubeone.StaticAuditLogConfig{
-				PolicyFilePath: "/etc/kubernetes/policy.yaml",
-				LogPath:        "/var/log/kubernetes",
-				LogMaxAge:      10,
-				LogMaxBackup:   10,
-				LogMaxSize:     0,
-			},
-			expectedError: true,
+			name:              "invalid ingress class",
+			ingressController: kubeone.IngressController{Enable: true, IngressClass: "Public_Nginx"},
+			expectedError:     true,
 		},
 	}
+
 	for _, tc := range tests {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
-			errs := ValidateStaticAuditLogConfig(tc.staticAuditLogConfig, nil)
+			errs := ValidateIngressController(&tc.ingressController, &field.Path{})
 			if (len(errs) == 0) == tc.expectedError {
 				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
 			}
@@ -1436,39 +1935,64 @@ func TestValidateStaticAuditLogConfig(t *testing.T) {
 	}
 }
 
-func TestValidateOIDCConfig(t *testing.T) {
+func TestValidateGitOps(t *testing.T) {
 	tests := []struct {
 		name          string
-		oidcConfig    kubeone.OpenIDConnectConfig
+		repository    kubeone.GitOpsRepository
 		expectedError bool
 	}{
 		{
-			name: "valid oidc config",
-			oidcConfig: kubeone.OpenIDConnectConfig{
-				IssuerURL: "test.cluster.local",
-				ClientID:  "test",
-			},
+			name:          "https repository",
+			repository:    kubeone.GitOpsRepository{URL: "https://github.com/example/fleet"},
 			expectedError: false,
 		},
 		{
-			name: "no issuer url",
-			oidcConfig: kubeone.OpenIDConnectConfig{
-				ClientID: "test",
+			name: "ssh repository with secret, branch, path and interval",
+			repository: kubeone.GitOpsRepository{
+				URL:        "ssh://git@github.com/example/fleet",
+				Branch:     "production",
+				Path:       "./clusters/production",
+				Interval:   &metav1.Duration{Duration: 5 * time.Minute},
+				SecretName: "flux-system",
 			},
+			expectedError: false,
+		},
+		{
+			name:          "missing url",
+			repository:    kubeone.GitOpsRepository{},
 			expectedError: true,
 		},
 		{
-			name: "no client id",
-			oidcConfig: kubeone.OpenIDConnectConfig{
-				IssuerURL: "test.cluster.local",
-			},
+			name:          "scp-like url",
+			repository:    kubeone.GitOpsRepository{URL: "git@github.com:example/fleet.git"},
+			expectedError: true,
+		},
+		{
+			name:          "ssh repository without secret",
+			repository:    kubeone.GitOpsRepository{URL: "ssh://git@github.com/example/fleet"},
+			expectedError: true,
+		},
+		{
+			name:          "absolute path",
+			repository:    kubeone.GitOpsRepository{URL: "https://github.com/example/fleet", Path: "/clusters"},
+			expectedError: true,
+		},
+		{
+			name:          "path outside of the repository",
+			repository:    kubeone.GitOpsRepository{URL: "https://github.com/example/fleet", Path: "../clusters"},
+			expectedError: true,
+		},
+		{
+			name:          "negative interval",
+			repository:    kubeone.GitOpsRepository{URL: "https://github.com/example/fleet", Interval: &metav1.Duration{Duration: -time.Minute}},
 			expectedError: true,
 		},
 	}
+
 	for _, tc := range tests {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
-			errs := ValidateOIDCConfig(tc.oidcConfig, nil)
+			errs := ValidateGitOps(&kubeone.GitOps{Enable: true, Repository: tc.repository}, &field.Path{})
 			if (len(errs) == 0) == tc.expectedError {
 				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
 			}
@@ -1476,197 +2000,2166 @@ func TestValidateOIDCConfig(t *testing.T) {
 	}
 }
 
-func TestValidateAddons(t *testing.T) {
+func TestValidateVelero(t *testing.T) {
 	tests := []struct {
 		name          string
-		addons        *kubeone.Addons
+		cloudProvider kubeone.CloudProviderSpec
+		velero        *kubeone.Velero
 		expectedError bool
 	}{
 		{
-			name: "valid addons config (enabled)",
-			addons: &kubeone.Addons{
-				Enable: true,
-				Path:   "./addons",
-			},
+			name:          "disabled",
+			cloudProvider: kubeone.CloudProviderSpec{Hetzner: &kubeone.HetznerSpec{}},
+			velero:        &kubeone.Velero{},
 			expectedError: false,
 		},
 		{
-			name: "addons enabled, no path set",
-			addons: &kubeone.Addons{
+			name:          "aws with region",
+			cloudProvider: kubeone.CloudProviderSpec{AWS: &kubeone.AWSSpec{}},
+			velero:        &kubeone.Velero{Enable: true, Bucket: "backups", Config: map[string]string{"region": "eu-west-1"}},
+			expectedError: false,
+		},
+		{
+			name:          "aws without region",
+			cloudProvider: kubeone.CloudProviderSpec{AWS: &kubeone.AWSSpec{}},
+			velero:        &kubeone.Velero{Enable: true, Bucket: "backups"},
+			expectedError: true,
+		},
+		{
+			name:          "gcp with prefix, restic and schedule",
+			cloudProvider: kubeone.CloudProviderSpec{GCE: &kubeone.GCESpec{}},
+			velero: &kubeone.Velero{
 				Enable: true,
-				Path:   "",
+				Bucket: "backups",
+				Prefix: "clusters/production",
+				Restic: true,
+				Schedule: kubeone.VeleroSchedule{
+					Cron: "0 */6 * * *",
+					TTL:  &metav1.Duration{Duration: 168 * time.Hour},
+				},
 			},
+			expectedError: false,
+		},
+		{
+			name:          "azure without storage account",
+			cloudProvider: kubeone.CloudProviderSpec{Azure: &kubeone.AzureSpec{}},
+			velero:        &kubeone.Velero{Enable: true, Bucket: "backups", Config: map[string]string{"resourceGroup": "backups"}},
 			expectedError: true,
 		},
 		{
-			name: "valid addons config (disabled)",
-			addons: &kubeone.Addons{
-				Enable: false,
+			name:          "vsphere with aws provider",
+			cloudProvider: kubeone.CloudProviderSpec{Vsphere: &kubeone.VsphereSpec{}},
+			velero: &kubeone.Velero{
+				Enable:   true,
+				Provider: kubeone.VeleroProviderAWS,
+				Bucket:   "backups",
+				Config:   map[string]string{"region": "minio", "s3Url": "https://minio.example.com"},
 			},
 			expectedError: false,
 		},
 		{
-			name:          "valid addons config (empty)",
-			addons:        &kubeone.Addons{},
-			expectedError: false,
+			name:          "vsphere without provider",
+			cloudProvider: kubeone.CloudProviderSpec{Vsphere: &kubeone.VsphereSpec{}},
+			velero:        &kubeone.Velero{Enable: true, Bucket: "backups"},
+			expectedError: true,
 		},
 		{
-			name:          "valid addons config (nil)",
-			addons:        nil,
-			expectedError: false,
+			name:          "unsupported provider",
+			cloudProvider: kubeone.CloudProviderSpec{AWS: &kubeone.AWSSpec{}},
+			velero:        &kubeone.Velero{Enable: true, Provider: "minio", Bucket: "backups"},
+			expectedError: true,
 		},
 		{
-			name: "invalid addons config (enabled without path)",
-			addons: &kubeone.Addons{
-				Enable: true,
-			},
+			name:          "missing bucket",
+			cloudProvider: kubeone.CloudProviderSpec{GCE: &kubeone.GCESpec{}},
+			velero:        &kubeone.Velero{Enable: true},
 			expectedError: true,
 		},
-	}
-	for _, tc := range tests {
-		tc := tc
-		t.Run(tc.name, func(t *testing.T) {
-			errs := ValidateAddons(tc.addons, nil)
-			if (len(errs) == 0) == tc.expectedError {
-				t.Log(errs[0])
-				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+		{
+			name:          "bucket with path",
+			cloudProvider: kubeone.CloudProviderSpec{GCE: &kubeone.GCESpec{}},
+			velero:        &kubeone.Velero{Enable: true, Bucket: "backups/production"},
+			expectedError: true,
+		},
+		{
+			name:          "invalid cron",
+			cloudProvider: kubeone.CloudProviderSpec{GCE: &kubeone.GCESpec{}},
+			velero:        &kubeone.Velero{Enable: true, Bucket: "backups", Schedule: kubeone.VeleroSchedule{Cron: "0 3 * *"}},
+			expectedError: true,
+		},
+		{
+			name:          "negative ttl",
+			cloudProvider: kubeone.CloudProviderSpec{GCE: &kubeone.GCESpec{}},
+			velero:        &kubeone.Velero{Enable: true, Bucket: "backups", Schedule: kubeone.VeleroSchedule{TTL: &metav1.Duration{Duration: -time.Hour}}},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			c := kubeone.KubeOneCluster{
+				CloudProvider: tc.cloudProvider,
+				Features:      kubeone.Features{Velero: tc.velero},
+			}
+			errs := ValidateVelero(c, &field.Path{})
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateNodeNamingConfig(t *testing.T) {
+	mc := &kubeone.MachineControllerConfig{Deploy: true}
+	workers := []kubeone.DynamicWorkerConfig{{Name: "pool1"}}
+
+	tests := []struct {
+		name              string
+		cloudProvider     kubeone.CloudProviderSpec
+		nodeNaming        *kubeone.NodeNamingConfig
+		machineController *kubeone.MachineControllerConfig
+		dynamicWorkers    []kubeone.DynamicWorkerConfig
+		expectedError     bool
+	}{
+		{
+			name:          "not set",
+			cloudProvider: kubeone.CloudProviderSpec{Vsphere: &kubeone.VsphereSpec{}},
+			expectedError: false,
+		},
+		{
+			name:          "private IP",
+			cloudProvider: kubeone.CloudProviderSpec{Vsphere: &kubeone.VsphereSpec{}},
+			nodeNaming:    &kubeone.NodeNamingConfig{Strategy: kubeone.NodeNamingPrivateIP},
+			expectedError: false,
+		},
+		{
+			name:          "instance ID on aws",
+			cloudProvider: kubeone.CloudProviderSpec{AWS: &kubeone.AWSSpec{}},
+			nodeNaming:    &kubeone.NodeNamingConfig{Strategy: kubeone.NodeNamingInstanceID},
+			expectedError: false,
+		},
+		{
+			name:          "instance ID on azure",
+			cloudProvider: kubeone.CloudProviderSpec{Azure: &kubeone.AzureSpec{}},
+			nodeNaming:    &kubeone.NodeNamingConfig{Strategy: kubeone.NodeNamingInstanceID},
+			expectedError: true,
+		},
+		{
+			name:          "instance ID on gce",
+			cloudProvider: kubeone.CloudProviderSpec{GCE: &kubeone.GCESpec{}},
+			nodeNaming:    &kubeone.NodeNamingConfig{Strategy: kubeone.NodeNamingInstanceID},
+			expectedError: true,
+		},
+		{
+			name:          "instance ID on vsphere",
+			cloudProvider: kubeone.CloudProviderSpec{Vsphere: &kubeone.VsphereSpec{}},
+			nodeNaming:    &kubeone.NodeNamingConfig{Strategy: kubeone.NodeNamingInstanceID},
+			expectedError: true,
+		},
+		{
+			name:          "unsupported strategy",
+			cloudProvider: kubeone.CloudProviderSpec{AWS: &kubeone.AWSSpec{}},
+			nodeNaming:    &kubeone.NodeNamingConfig{Strategy: "machineName"},
+			expectedError: true,
+		},
+		{
+			name:              "fqdn with dynamic workers on aws",
+			cloudProvider:     kubeone.CloudProviderSpec{AWS: &kubeone.AWSSpec{}},
+			nodeNaming:        &kubeone.NodeNamingConfig{Strategy: kubeone.NodeNamingFQDN},
+			machineController: mc,
+			dynamicWorkers:    workers,
+			expectedError:     false,
+		},
+		{
+			name:              "hostname with dynamic workers on hetzner",
+			cloudProvider:     kubeone.CloudProviderSpec{Hetzner: &kubeone.HetznerSpec{}},
+			nodeNaming:        &kubeone.NodeNamingConfig{Strategy: kubeone.NodeNamingHostname},
+			machineController: mc,
+			dynamicWorkers:    workers,
+			expectedError:     false,
+		},
+		{
+			name:              "instance ID with dynamic workers",
+			cloudProvider:     kubeone.CloudProviderSpec{AWS: &kubeone.AWSSpec{}},
+			nodeNaming:        &kubeone.NodeNamingConfig{Strategy: kubeone.NodeNamingInstanceID},
+			machineController: mc,
+			dynamicWorkers:    workers,
+			expectedError:     true,
+		},
+		{
+			name:              "instance ID without dynamic workers",
+			cloudProvider:     kubeone.CloudProviderSpec{AWS: &kubeone.AWSSpec{}},
+			nodeNaming:        &kubeone.NodeNamingConfig{Strategy: kubeone.NodeNamingInstanceID},
+			machineController: mc,
+			expectedError:     false,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			c := kubeone.KubeOneCluster{
+				CloudProvider:     tc.cloudProvider,
+				NodeNaming:        tc.nodeNaming,
+				MachineController: tc.machineController,
+				DynamicWorkers:    tc.dynamicWorkers,
+			}
+			errs := ValidateNodeNamingConfig(c, &field.Path{})
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateStaticWorkerGroups(t *testing.T) {
+	host := kubeone.HostConfig{PublicAddress: "10.0.0.1"}
+
+	tests := []struct {
+		name          string
+		groups        []kubeone.StaticWorkerGroup
+		expectedError bool
+	}{
+		{
+			name: "valid groups",
+			groups: []kubeone.StaticWorkerGroup{
+				{Name: "storage", Hosts: []kubeone.HostConfig{host}},
+				{Name: "gpu", Hosts: []kubeone.HostConfig{host}},
+			},
+			expectedError: false,
+		},
+		{
+			name:          "no name",
+			groups:        []kubeone.StaticWorkerGroup{{Hosts: []kubeone.HostConfig{host}}},
+			expectedError: true,
+		},
+		{
+			name:          "invalid name",
+			groups:        []kubeone.StaticWorkerGroup{{Name: "Storage_Pool", Hosts: []kubeone.HostConfig{host}}},
+			expectedError: true,
+		},
+		{
+			name: "duplicate name",
+			groups: []kubeone.StaticWorkerGroup{
+				{Name: "storage", Hosts: []kubeone.HostConfig{host}},
+				{Name: "storage", Hosts: []kubeone.HostConfig{host}},
+			},
+			expectedError: true,
+		},
+		{
+			name:          "no hosts",
+			groups:        []kubeone.StaticWorkerGroup{{Name: "storage"}},
+			expectedError: true,
+		},
+		{
+			name:          "leader host",
+			groups:        []kubeone.StaticWorkerGroup{{Name: "storage", Hosts: []kubeone.HostConfig{{PublicAddress: "10.0.0.1", IsLeader: true}}}},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateStaticWorkerGroups(tc.groups, &field.Path{})
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateKubeletConfig(t *testing.T) {
+	maxPods := int32(110)
+	zeroPods := int32(0)
+
+	tests := []struct {
+		name          string
+		kubelet       *kubeone.KubeletConfig
+		expectedError bool
+	}{
+		{
+			name:          "not set",
+			expectedError: false,
+		},
+		{
+			name: "valid",
+			kubelet: &kubeone.KubeletConfig{
+				MaxPods:        &maxPods,
+				SystemReserved: map[string]string{"cpu": "200m", "memory": "500Mi"},
+				KubeReserved:   map[string]string{"ephemeral-storage": "1Gi"},
+				EvictionHard:   map[string]string{"memory.available": "100Mi", "nodefs.available": "10%"},
+			},
+			expectedError: false,
+		},
+		{
+			name:          "zero max pods",
+			kubelet:       &kubeone.KubeletConfig{MaxPods: &zeroPods},
+			expectedError: true,
+		},
+		{
+			name:          "unknown reserved resource",
+			kubelet:       &kubeone.KubeletConfig{SystemReserved: map[string]string{"gpu": "1"}},
+			expectedError: true,
+		},
+		{
+			name:          "invalid reserved quantity",
+			kubelet:       &kubeone.KubeletConfig{KubeReserved: map[string]string{"memory": "500Mi,cpu=1"}},
+			expectedError: true,
+		},
+		{
+			name:          "unknown eviction signal",
+			kubelet:       &kubeone.KubeletConfig{EvictionHard: map[string]string{"memory.free": "100Mi"}},
+			expectedError: true,
+		},
+		{
+			name:          "invalid eviction threshold",
+			kubelet:       &kubeone.KubeletConfig{EvictionHard: map[string]string{"memory.available": "lots"}},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateKubeletConfig(tc.kubelet, &field.Path{})
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidatePodNodeSelectorConfig(t *testing.T) {
+	tests := []struct {
+		name                  string
+		podNodeSelectorConfig kubeone.PodNodeSelectorConfig
+		expectedError         bool
+	}{
+		{
+			name: "valid podNodeSelector config",
+			podNodeSelectorConfig: kubeone.PodNodeSelectorConfig{
+				ConfigFilePath: "./podnodeselector.yaml",
+			},
+			expectedError: false,
+		},
+		{
+			name:                  "invalid podNodeSelector config",
+			podNodeSelectorConfig: kubeone.PodNodeSelectorConfig{},
+			expectedError:         true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidatePodNodeSelectorConfig(tc.podNodeSelectorConfig, nil)
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateStaticAuditLogConfig(t *testing.T) {
+	tests := []struct {
+		name                 string
+		staticAuditLogConfig kubeone.StaticAuditLogConfig
+		expectedError        bool
+	}{
+		{
+			name: "valid staticAuditLog config",
+			staticAuditLogConfig: kubeone.StaticAuditLogConfig{
+				PolicyFilePath: "/etc/kubernetes/policy.yaml",
+				LogPath:        "/var/log/kubernetes",
+				LogMaxAge:      10,
+				LogMaxBackup:   10,
+				LogMaxSize:     100,
+			},
+			expectedError: false,
+		},
+		{
+			name: "policy file path missing",
+			staticAuditLogConfig: kubeone.StaticAuditLogConfig{
+				LogPath:      "/var/log/kubernetes",
+				LogMaxAge:    10,
+				LogMaxBackup: 10,
+				LogMaxSize:   100,
+			},
+			expectedError: true,
+		},
+		{
+			name: "log file path missing",
+			staticAuditLogConfig: kubeone.StaticAuditLogConfig{
+				PolicyFilePath: "/etc/kubernetes/policy.yaml",
+				LogMaxAge:      10,
+				LogMaxBackup:   10,
+				LogMaxSize:     100,
+			},
+			expectedError: true,
+		},
+		{
+			name: "log max age set to 0",
+			staticAuditLogConfig: kubeone.StaticAuditLogConfig{
+				PolicyFilePath: "/etc/kubernetes/policy.yaml",
+				LogPath:        "/var/log/kubernetes",
+				LogMaxAge:      0,
+				LogMaxBackup:   10,
+				LogMaxSize:     100,
+			},
+			expectedError: true,
+		},
+		{
+			name: "log max backup set to 0",
+			staticAuditLogConfig: kubeone.StaticAuditLogConfig{
+				PolicyFilePath: "/etc/kubernetes/policy.yaml",
+				LogPath:        "/var/log/kubernetes",
+				LogMaxAge:      10,
+				LogMaxBackup:   0,
+				LogMaxSize:     100,
+			},
+			expectedError: true,
+		},
+		{
+			name: "log max size set to 0",
+			staticAuditLogConfig: kubeone.StaticAuditLogConfig{
+				PolicyFilePath: "/etc/kubernetes/policy.yaml",
+				LogPath:        "/var/log/kubernetes",
+				LogMaxAge:      10,
+				LogMaxBackup:   10,
+				LogMaxSize:     0,
+			},
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateStaticAuditLogConfig(tc.staticAuditLogConfig, nil)
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateOIDCConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		oidcConfig    kubeone.OpenIDConnectConfig
+		expectedError bool
+	}{
+		{
+			name: "valid oidc config",
+			oidcConfig: kubeone.OpenIDConnectConfig{
+				IssuerURL: "test.cluster.local",
+				ClientID:  "test",
+			},
+			expectedError: false,
+		},
+		{
+			name: "no issuer url",
+			oidcConfig: kubeone.OpenIDConnectConfig{
+				ClientID: "test",
+			},
+			expectedError: true,
+		},
+		{
+			name: "no client id",
+			oidcConfig: kubeone.OpenIDConnectConfig{
+				IssuerURL: "test.cluster.local",
+			},
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateOIDCConfig(tc.oidcConfig, nil)
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateAddons(t *testing.T) {
+	tests := []struct {
+		name          string
+		addons        *kubeone.Addons
+		expectedError bool
+	}{
+		{
+			name: "valid addons config (enabled)",
+			addons: &kubeone.Addons{
+				Enable: true,
+				Path:   "./addons",
+			},
+			expectedError: false,
+		},
+		{
+			name: "addons enabled, no path set",
+			addons: &kubeone.Addons{
+				Enable: true,
+				Path:   "",
+			},
+			expectedError: true,
+		},
+		{
+			name: "valid addons config (disabled)",
+			addons: &kubeone.Addons{
+				Enable: false,
+			},
+			expectedError: false,
+		},
+		{
+			name:          "valid addons config (empty)",
+			addons:        &kubeone.Addons{},
+			expectedError: false,
+		},
+		{
+			name:          "valid addons config (nil)",
+			addons:        nil,
+			expectedError: false,
+		},
+		{
+			name: "invalid addons config (enabled without path)",
+			addons: &kubeone.Addons{
+				Enable: true,
+			},
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateAddons(tc.addons, nil)
+			if (len(errs) == 0) == tc.expectedError {
+				t.Log(errs[0])
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateHostConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		hostConfig    []kubeone.HostConfig
+		expectedError bool
+	}{
+		{
+			name: "host config with ip addresses",
+			hostConfig: []kubeone.HostConfig{
+				{
+					PublicAddress:     "192.168.1.1",
+					PrivateAddress:    "192.168.0.1",
+					SSHPrivateKeyFile: "test",
+					SSHAgentSocket:    "test",
+					SSHUsername:       "root",
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "host config with dns domain",
+			hostConfig: []kubeone.HostConfig{
+				{
+					PublicAddress:     "cluster-test.public.local",
+					PrivateAddress:    "cluster-test.private.local",
+					SSHPrivateKeyFile: "test",
+					SSHAgentSocket:    "test",
+					SSHUsername:       "root",
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "no public address provided",
+			hostConfig: []kubeone.HostConfig{
+				{
+					PublicAddress:     "",
+					PrivateAddress:    "cluster-test.private.local",
+					SSHPrivateKeyFile: "test",
+					SSHAgentSocket:    "test",
+					SSHUsername:       "root",
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "no private address provided",
+			hostConfig: []kubeone.HostConfig{
+				{
+					PublicAddress:     "cluster-test.public.local",
+					PrivateAddress:    "",
+					SSHPrivateKeyFile: "test",
+					SSHAgentSocket:    "test",
+					SSHUsername:       "root",
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "private key passphrase from environment",
+			hostConfig: []kubeone.HostConfig{
+				{
+					PublicAddress:           "192.168.1.1",
+					PrivateAddress:          "192.168.0.1",
+					SSHPrivateKeyFile:       "test",
+					SSHPrivateKeyPassphrase: "env:SSH_KEY_PASSPHRASE",
+					SSHCertFile:             "test-cert.pub",
+					SSHUsername:             "root",
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "plaintext private key passphrase",
+			hostConfig: []kubeone.HostConfig{
+				{
+					PublicAddress:           "192.168.1.1",
+					PrivateAddress:          "192.168.0.1",
+					SSHPrivateKeyFile:       "test",
+					SSHPrivateKeyPassphrase: "secret",
+					SSHUsername:             "root",
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "certificate without private key file",
+			hostConfig: []kubeone.HostConfig{
+				{
+					PublicAddress:  "192.168.1.1",
+					PrivateAddress: "192.168.0.1",
+					SSHAgentSocket: "test",
+					SSHCertFile:    "test-cert.pub",
+					SSHUsername:    "root",
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "bastion hosts chain",
+			hostConfig: []kubeone.HostConfig{
+				{
+					PublicAddress:     "192.168.1.1",
+					PrivateAddress:    "192.168.0.1",
+					SSHPrivateKeyFile: "test",
+					SSHUsername:       "root",
+					BastionHosts: []kubeone.BastionHost{
+						{Host: "1.2.3.4", User: "jump"},
+						{Host: "10.0.0.1", SSHPrivateKeyFile: "jump-key"},
+					},
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "bastion hosts along with bastion",
+			hostConfig: []kubeone.HostConfig{
+				{
+					PublicAddress:     "192.168.1.1",
+					PrivateAddress:    "192.168.0.1",
+					SSHPrivateKeyFile: "test",
+					SSHUsername:       "root",
+					Bastion:           "1.2.3.4",
+					BastionHosts:      []kubeone.BastionHost{{Host: "10.0.0.1"}},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "bastion host without address",
+			hostConfig: []kubeone.HostConfig{
+				{
+					PublicAddress:     "192.168.1.1",
+					PrivateAddress:    "192.168.0.1",
+					SSHPrivateKeyFile: "test",
+					SSHUsername:       "root",
+					BastionHosts:      []kubeone.BastionHost{{User: "jump"}},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "host labels",
+			hostConfig: []kubeone.HostConfig{
+				{
+					PublicAddress:     "192.168.1.1",
+					PrivateAddress:    "192.168.0.1",
+					SSHPrivateKeyFile: "test",
+					SSHUsername:       "root",
+					Labels:            map[string]string{"example.com/zone": "a", "tier": "db"},
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "invalid host label",
+			hostConfig: []kubeone.HostConfig{
+				{
+					PublicAddress:     "192.168.1.1",
+					PrivateAddress:    "192.168.0.1",
+					SSHPrivateKeyFile: "test",
+					SSHUsername:       "root",
+					Labels:            map[string]string{"tier": "not valid"},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "no private key file and agent provided",
+			hostConfig: []kubeone.HostConfig{
+				{
+					PublicAddress:     "cluster-test.public.local",
+					PrivateAddress:    "cluster-test.private.local",
+					SSHPrivateKeyFile: "",
+					SSHAgentSocket:    "",
+					SSHUsername:       "root",
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "no username provided",
+			hostConfig: []kubeone.HostConfig{
+				{
+					PublicAddress:     "cluster-test.public.local",
+					PrivateAddress:    "cluster-test.private.local",
+					SSHPrivateKeyFile: "test",
+					SSHAgentSocket:    "test",
+					SSHUsername:       "",
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "one valid host config and one invalid host config (no username)",
+			hostConfig: []kubeone.HostConfig{
+				{
+					PublicAddress:     "192.168.1.1",
+					PrivateAddress:    "192.168.0.1",
+					SSHPrivateKeyFile: "test",
+					SSHAgentSocket:    "test",
+					SSHUsername:       "root",
+				},
+				{
+					PublicAddress:     "cluster-test.public.local",
+					PrivateAddress:    "cluster-test.private.local",
+					SSHPrivateKeyFile: "test",
+					SSHAgentSocket:    "test",
+					SSHUsername:       "",
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "two leaders at the same time",
+			hostConfig: []kubeone.HostConfig{
+				{
+					PublicAddress:     "192.168.1.1",
+					PrivateAddress:    "192.168.0.1",
+					SSHPrivateKeyFile: "test",
+					SSHAgentSocket:    "test",
+					SSHUsername:       "root",
+					IsLeader:          true,
+				},
+				{
+					PublicAddress:     "cluster-test.public.local",
+					PrivateAddress:    "cluster-test.private.local",
+					SSHPrivateKeyFile: "test",
+					SSHAgentSocket:    "test",
+					SSHUsername:       "root",
+					IsLeader:          true,
+				},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateHostConfig(tc.hostConfig, nil)
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateRegistryConfiguration(t *testing.T) {
+	tests := []struct {
+		name                  string
+		registryConfiguration *kubeone.RegistryConfiguration
+		expectedError         bool
+	}{
+		{
+			name: "valid registry config (overwrite registry)",
+			registryConfiguration: &kubeone.RegistryConfiguration{
+				OverwriteRegistry: "127.0.0.1:5000",
+			},
+			expectedError: false,
+		},
+		{
+			name: "valid registry config (overwrite registry and insecure)",
+			registryConfiguration: &kubeone.RegistryConfiguration{
+				OverwriteRegistry: "127.0.0.1:5000",
+				InsecureRegistry:  true,
+			},
+			expectedError: false,
+		},
+		{
+			name:                  "valid registry config (empty)",
+			registryConfiguration: &kubeone.RegistryConfiguration{},
+			expectedError:         false,
+		},
+		{
+			name:                  "valid registry config (nil)",
+			registryConfiguration: nil,
+			expectedError:         false,
+		},
+		{
+			name: "invalid registry config (insecure registry without overwrite registry)",
+			registryConfiguration: &kubeone.RegistryConfiguration{
+				InsecureRegistry: true,
+			},
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateRegistryConfiguration(tc.registryConfiguration, nil)
+			if (len(errs) == 0) == tc.expectedError {
+				t.Log(errs[0])
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateAssetConfiguration(t *testing.T) {
+	tests := []struct {
+		name               string
+		assetConfiguration *kubeone.AssetConfiguration
+		expectedError      bool
+	}{
+		{
+			name:               "empty asset configuration",
+			assetConfiguration: &kubeone.AssetConfiguration{},
+			expectedError:      false,
+		},
+		{
+			name: "kubernetes image configured",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				Kubernetes: kubeone.ImageAsset{
+					ImageRepository: "127.0.0.1:5000",
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "kubernetes image and tag configured",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				Kubernetes: kubeone.ImageAsset{
+					ImageRepository: "127.0.0.1:5000",
+					ImageTag:        "test",
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "kubernetes tag configured",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				Kubernetes: kubeone.ImageAsset{
+					ImageTag: "test",
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "pause image configured",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				Pause: kubeone.ImageAsset{
+					ImageRepository: "127.0.0.1:5000",
+					ImageTag:        "3.2",
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "pause image configured (repository missing)",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				Pause: kubeone.ImageAsset{
+					ImageTag: "3.2",
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "pause image configured (tag missing)",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				Pause: kubeone.ImageAsset{
+					ImageRepository: "127.0.0.1:5000",
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "coredns image and tag configured",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				CoreDNS: kubeone.ImageAsset{
+					ImageRepository: "127.0.0.1:5000",
+					ImageTag:        "test",
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "coredns image configured",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				CoreDNS: kubeone.ImageAsset{
+					ImageRepository: "127.0.0.1:5000",
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "coredns tag configured",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				CoreDNS: kubeone.ImageAsset{
+					ImageTag: "test",
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "etcd image and tag configured",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				Etcd: kubeone.ImageAsset{
+					ImageRepository: "127.0.0.1:5000",
+					ImageTag:        "test",
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "etcd image configured",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				Etcd: kubeone.ImageAsset{
+					ImageRepository: "127.0.0.1:5000",
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "etcd tag configured",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				Etcd: kubeone.ImageAsset{
+					ImageTag: "test",
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "metrics-server image and tag configured",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				MetricsServer: kubeone.ImageAsset{
+					ImageRepository: "127.0.0.1:5000",
+					ImageTag:        "test",
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "metrics-server image configured",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				MetricsServer: kubeone.ImageAsset{
+					ImageRepository: "127.0.0.1:5000",
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "metrics-server tag configured",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				MetricsServer: kubeone.ImageAsset{
+					ImageTag: "test",
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "cni, node binaries, and kubectl configured",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				CNI: kubeone.BinaryAsset{
+					URL: "https://127.0.0.1/cni",
+				},
+				NodeBinaries: kubeone.BinaryAsset{
+					URL: "https://127.0.0.1/kubernetes-node-linux-amd64.tar.gz",
+				},
+				Kubectl: kubeone.BinaryAsset{
+					URL: "https://127.0.0.1/kubectl",
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "binary assets configured (node binaries missing)",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				CNI: kubeone.BinaryAsset{
+					URL: "https://127.0.0.1/cni",
+				},
+				Kubectl: kubeone.BinaryAsset{
+					URL: "https://127.0.0.1/kubectl",
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "binaries mirror configured",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				BinariesMirror: "http://127.0.0.1:8080/kubernetes",
+			},
+			expectedError: false,
+		},
+		{
+			name: "binaries mirror configured with kubeadm checksum",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				BinariesMirror: "http://127.0.0.1:8080/kubernetes",
+				Kubeadm: kubeone.BinaryAsset{
+					SHA256: "ee2e5d4d9b6dab9e3a1c9b0f0c4e8d2e6b3e6a4c3f2a1b0c9d8e7f6a5b4c3d2e",
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "binaries mirror configured (invalid url)",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				BinariesMirror: "127.0.0.1:8080/kubernetes",
+			},
+			expectedError: true,
+		},
+		{
+			name: "binaries mirror and node binaries configured",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				BinariesMirror: "http://127.0.0.1:8080/kubernetes",
+				NodeBinaries: kubeone.BinaryAsset{
+					URL: "https://127.0.0.1/kubernetes-node-linux-amd64.tar.gz",
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "kubeadm, kubelet, kubectl and cni configured",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				Kubeadm: kubeone.BinaryAsset{
+					URL:    "https://127.0.0.1/kubeadm",
+					SHA256: "ee2e5d4d9b6dab9e3a1c9b0f0c4e8d2e6b3e6a4c3f2a1b0c9d8e7f6a5b4c3d2e",
+				},
+				Kubelet: kubeone.BinaryAsset{
+					URL: "https://127.0.0.1/kubelet",
+				},
+				Kubectl: kubeone.BinaryAsset{
+					URL: "https://127.0.0.1/kubectl",
+				},
+				CNI: kubeone.BinaryAsset{
+					URL: "https://127.0.0.1/cni.tgz",
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "kubeadm and kubelet configured (kubectl and cni missing)",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				Kubeadm: kubeone.BinaryAsset{
+					URL: "https://127.0.0.1/kubeadm",
+				},
+				Kubelet: kubeone.BinaryAsset{
+					URL: "https://127.0.0.1/kubelet",
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "signature verification configured (keyless)",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				BinariesMirror: "http://127.0.0.1:8080/kubernetes",
+				SignatureVerification: &kubeone.SignatureVerification{
+					CertificateIdentity:   "krel-staging@k8s-releng-prod.iam.gserviceaccount.com",
+					CertificateOIDCIssuer: "https://accounts.google.com",
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "signature verification configured (public key)",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				BinariesMirror: "http://127.0.0.1:8080/kubernetes",
+				SignatureVerification: &kubeone.SignatureVerification{
+					PublicKey: "-----BEGIN PUBLIC KEY-----\nMFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAE\n-----END PUBLIC KEY-----\n",
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "signature verification configured (invalid public key)",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				BinariesMirror: "http://127.0.0.1:8080/kubernetes",
+				SignatureVerification: &kubeone.SignatureVerification{
+					PublicKey: "not-a-key",
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "signature verification configured (identity missing)",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				BinariesMirror: "http://127.0.0.1:8080/kubernetes",
+				SignatureVerification: &kubeone.SignatureVerification{
+					CertificateOIDCIssuer: "https://accounts.google.com",
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "binaries mirror configured (invalid checksum)",
+			assetConfiguration: &kubeone.AssetConfiguration{
+				BinariesMirror: "http://127.0.0.1:8080/kubernetes",
+				Kubelet: kubeone.BinaryAsset{
+					SHA256: "not-a-checksum",
+				},
+			},
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateAssetConfiguration(tc.assetConfiguration, nil)
+			if (len(errs) == 0) == tc.expectedError {
+				t.Log(errs[0])
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestValidateRBACConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		rbacConfig    *kubeone.RBACConfig
+		expectedError bool
+	}{
+		{
+			name:          "valid rbac config (nil)",
+			rbacConfig:    nil,
+			expectedError: false,
+		},
+		{
+			name: "valid rbac config",
+			rbacConfig: &kubeone.RBACConfig{
+				ClusterRoles: []kubeone.RBACClusterRole{
+					{
+						Name: "pod-reader",
+						Rules: []rbacv1.PolicyRule{
+							{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "list", "watch"}},
+						},
+					},
+				},
+				ClusterRoleBindings: []kubeone.RBACRoleBinding{
+					{Name: "oidc-admins", ClusterRole: "cluster-admin", Groups: []string{"oidc:admins"}},
+				},
+				RoleBindings: []kubeone.RBACRoleBinding{
+					{Name: "oidc-developers", Namespace: "dev", ClusterRole: "edit", Groups: []string{"oidc:developers"}},
+					{Name: "pod-readers", Namespace: "dev", ClusterRole: "pod-reader", Users: []string{"jane"}},
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "invalid rbac config (cluster role without rules)",
+			rbacConfig: &kubeone.RBACConfig{
+				ClusterRoles: []kubeone.RBACClusterRole{
+					{Name: "pod-reader"},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid rbac config (binding without subjects)",
+			rbacConfig: &kubeone.RBACConfig{
+				ClusterRoleBindings: []kubeone.RBACRoleBinding{
+					{Name: "oidc-admins", ClusterRole: "cluster-admin"},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid rbac config (binding without cluster role)",
+			rbacConfig: &kubeone.RBACConfig{
+				ClusterRoleBindings: []kubeone.RBACRoleBinding{
+					{Name: "oidc-admins", Groups: []string{"oidc:admins"}},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid rbac config (role binding without namespace)",
+			rbacConfig: &kubeone.RBACConfig{
+				RoleBindings: []kubeone.RBACRoleBinding{
+					{Name: "oidc-developers", ClusterRole: "edit", Groups: []string{"oidc:developers"}},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid rbac config (duplicated cluster role bindings)",
+			rbacConfig: &kubeone.RBACConfig{
+				ClusterRoleBindings: []kubeone.RBACRoleBinding{
+					{Name: "oidc-admins", ClusterRole: "cluster-admin", Groups: []string{"oidc:admins"}},
+					{Name: "oidc-admins", ClusterRole: "cluster-admin", Groups: []string{"oidc:ops"}},
+				},
+			},
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateRBACConfig(tc.rbacConfig, nil)
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateNodeDrainConfig(t *testing.T) {
+	gracePeriod := 30
+	negative := -1
+
+	tests := []struct {
+		name          string
+		drainConfig   *kubeone.NodeDrainConfig
+		expectedError bool
+	}{
+		{
+			name:          "valid node drain config (nil)",
+			drainConfig:   nil,
+			expectedError: false,
+		},
+		{
+			name: "valid node drain config",
+			drainConfig: &kubeone.NodeDrainConfig{
+				GracePeriodSeconds: &gracePeriod,
+				Timeout:            &metav1.Duration{Duration: 10 * time.Minute},
+			},
+			expectedError: false,
+		},
+		{
+			name: "valid node drain config (pod grace period)",
+			drainConfig: &kubeone.NodeDrainConfig{
+				GracePeriodSeconds: &negative,
+			},
+			expectedError: false,
+		},
+		{
+			name: "invalid node drain config (negative timeout)",
+			drainConfig: &kubeone.NodeDrainConfig{
+				Timeout: &metav1.Duration{Duration: -time.Minute},
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid node drain config (negative skipWaitForDeleteTimeoutSeconds)",
+			drainConfig: &kubeone.NodeDrainConfig{
+				SkipWaitForDeleteTimeoutSeconds: &negative,
+			},
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateNodeDrainConfig(tc.drainConfig, nil)
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateIgnorePreflightErrorsConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        *kubeone.IgnorePreflightErrorsConfig
+		expectedError bool
+	}{
+		{
+			name:          "valid config (nil)",
+			config:        nil,
+			expectedError: false,
+		},
+		{
+			name: "valid config",
+			config: &kubeone.IgnorePreflightErrorsConfig{
+				Install: []string{"NumCPU", "SystemVerification"},
+				Upgrade: []string{"CoreDNSUnsupportedPlugins"},
+			},
+			expectedError: false,
+		},
+		{
+			name: "valid config (all)",
+			config: &kubeone.IgnorePreflightErrorsConfig{
+				Install: []string{"all"},
+			},
+			expectedError: false,
+		},
+		{
+			name: "invalid config (all with other errors)",
+			config: &kubeone.IgnorePreflightErrorsConfig{
+				Install: []string{"all", "NumCPU"},
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid config (invalid characters)",
+			config: &kubeone.IgnorePreflightErrorsConfig{
+				Upgrade: []string{"NumCPU; reboot"},
+			},
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateIgnorePreflightErrorsConfig(tc.config, nil)
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateManifestPatches(t *testing.T) {
+	tests := []struct {
+		name          string
+		patches       []kubeone.ManifestPatch
+		expectedError bool
+	}{
+		{
+			name:          "valid config (no patches)",
+			patches:       nil,
+			expectedError: false,
+		},
+		{
+			name: "valid merge patch",
+			patches: []kubeone.ManifestPatch{
+				{
+					Target:     kubeone.ManifestPatchTarget{Group: "apps", Kind: "Deployment"},
+					MergePatch: "metadata:\n  labels:\n    company.com/owner: platform\n",
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "valid JSON patch",
+			patches: []kubeone.ManifestPatch{
+				{
+					JSONPatch: `[{"op": "add", "path": "/metadata/labels/owner", "value": "platform"}]`,
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "invalid config (no patch)",
+			patches: []kubeone.ManifestPatch{
+				{
+					Target: kubeone.ManifestPatchTarget{Kind: "Deployment"},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid config (both patches)",
+			patches: []kubeone.ManifestPatch{
+				{
+					MergePatch: "metadata: {}",
+					JSONPatch:  `[{"op": "remove", "path": "/metadata/labels"}]`,
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid config (merge patch is not an object)",
+			patches: []kubeone.ManifestPatch{
+				{
+					MergePatch: "- a\n- b\n",
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid config (JSON patch is not a list)",
+			patches: []kubeone.ManifestPatch{
+				{
+					JSONPatch: `{"op": "add"}`,
+				},
+			},
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateManifestPatches(tc.patches, field.NewPath("manifestPatches"))
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidatePreflightConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		preflight     *kubeone.PreflightConfig
+		expectedError bool
+	}{
+		{
+			name:          "valid config (not set)",
+			preflight:     nil,
+			expectedError: false,
+		},
+		{
+			name: "valid config (kubeone and kubeadm checks)",
+			preflight: &kubeone.PreflightConfig{
+				Skip:     []string{"time-sync", "kubeadm:NumCPU"},
+				WarnOnly: []string{"version-skew", "kubeadm:Swap"},
+			},
+			expectedError: false,
+		},
+		{
+			name:          "invalid config (unknown kubeone check)",
+			preflight:     &kubeone.PreflightConfig{Skip: []string{"ssh"}},
+			expectedError: true,
+		},
+		{
+			name:          "invalid config (invalid kubeadm check)",
+			preflight:     &kubeone.PreflightConfig{WarnOnly: []string{"kubeadm:Num CPU"}},
+			expectedError: true,
+		},
+		{
+			name: "invalid config (skipped and warning-only)",
+			preflight: &kubeone.PreflightConfig{
+				Skip:     []string{"swap"},
+				WarnOnly: []string{"swap"},
+			},
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidatePreflightConfig(tc.preflight, field.NewPath("preflight"))
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateClusterSizeConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		clusterSize   *kubeone.ClusterSizeConfig
+		expectedError bool
+	}{
+		{
+			name:          "valid config (not set)",
+			clusterSize:   nil,
+			expectedError: false,
+		},
+		{
+			name:          "valid config (nodes and pods)",
+			clusterSize:   &kubeone.ClusterSizeConfig{Nodes: 300, Pods: 9000, Enforce: true},
+			expectedError: false,
+		},
+		{
+			name:          "valid config (only pods)",
+			clusterSize:   &kubeone.ClusterSizeConfig{Pods: 3000},
+			expectedError: false,
+		},
+		{
+			name:          "invalid config (empty)",
+			clusterSize:   &kubeone.ClusterSizeConfig{Enforce: true},
+			expectedError: true,
+		},
+		{
+			name:          "invalid config (negative nodes)",
+			clusterSize:   &kubeone.ClusterSizeConfig{Nodes: -1, Pods: 100},
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateClusterSizeConfig(tc.clusterSize, field.NewPath("clusterSize"))
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateHostRebootConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		rebootConfig  *kubeone.HostRebootConfig
+		expectedError bool
+	}{
+		{
+			name:          "valid config (nil)",
+			rebootConfig:  nil,
+			expectedError: false,
+		},
+		{
+			name: "valid config",
+			rebootConfig: &kubeone.HostRebootConfig{
+				Enable:  true,
+				Timeout: &metav1.Duration{Duration: 15 * time.Minute},
+			},
+			expectedError: false,
+		},
+		{
+			name: "invalid config (zero timeout)",
+			rebootConfig: &kubeone.HostRebootConfig{
+				Enable:  true,
+				Timeout: &metav1.Duration{},
+			},
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateHostRebootConfig(tc.rebootConfig, field.NewPath("hostReboot"))
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateOSTuningConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		osTuning      *kubeone.OSTuningConfig
+		expectedError bool
+	}{
+		{
+			name:          "valid config (nil)",
+			osTuning:      nil,
+			expectedError: false,
+		},
+		{
+			name: "valid config",
+			osTuning: &kubeone.OSTuningConfig{
+				All: kubeone.OSTuning{
+					KernelModules: []string{"nf_conntrack", "ip_vs"},
+					Sysctls: map[string]string{
+						"net.ipv4.conf.all.rp_filter":    "1",
+						"net/netfilter/nf_conntrack_max": "1000000",
+					},
+				},
+				ControlPlane: kubeone.OSTuning{
+					Sysctls: map[string]string{"vm.swappiness": "0"},
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "invalid config (kernel module)",
+			osTuning: &kubeone.OSTuningConfig{
+				StaticWorkers: kubeone.OSTuning{
+					KernelModules: []string{"nf_conntrack; reboot"},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid config (sysctl key)",
+			osTuning: &kubeone.OSTuningConfig{
+				All: kubeone.OSTuning{
+					Sysctls: map[string]string{"swappiness": "0"},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid config (multiline sysctl value)",
+			osTuning: &kubeone.OSTuningConfig{
+				All: kubeone.OSTuning{
+					Sysctls: map[string]string{"vm.swappiness": "0\nkernel.panic = 0"},
+				},
+			},
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateOSTuningConfig(tc.osTuning, field.NewPath("osTuning"))
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateTimeSyncConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		timeSync      *kubeone.TimeSyncConfig
+		expectedError bool
+	}{
+		{
+			name:          "valid config (nil)",
+			timeSync:      nil,
+			expectedError: false,
+		},
+		{
+			name: "valid config",
+			timeSync: &kubeone.TimeSyncConfig{
+				Chrony:       true,
+				NTPServers:   []string{"0.pool.ntp.org", "10.0.0.1"},
+				MaxClockSkew: &metav1.Duration{Duration: 500 * time.Millisecond},
+			},
+			expectedError: false,
+		},
+		{
+			name: "invalid config (ntpServers without chrony)",
+			timeSync: &kubeone.TimeSyncConfig{
+				NTPServers: []string{"0.pool.ntp.org"},
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid config (ntp server)",
+			timeSync: &kubeone.TimeSyncConfig{
+				Chrony:     true,
+				NTPServers: []string{"ntp.example.com iburst"},
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid config (maxClockSkew)",
+			timeSync: &kubeone.TimeSyncConfig{
+				MaxClockSkew: &metav1.Duration{},
+			},
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateTimeSyncConfig(tc.timeSync, field.NewPath("timeSync"))
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateHardeningConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		hardening     *kubeone.HardeningConfig
+		expectedError bool
+	}{
+		{
+			name:          "valid config (nil)",
+			hardening:     nil,
+			expectedError: false,
+		},
+		{
+			name:          "valid config (cis)",
+			hardening:     &kubeone.HardeningConfig{Profile: kubeone.HardeningProfileCIS},
+			expectedError: false,
+		},
+		{
+			name:          "invalid config (unknown profile)",
+			hardening:     &kubeone.HardeningConfig{Profile: "stig"},
+			expectedError: true,
+		},
+		{
+			name:          "invalid config (empty profile)",
+			hardening:     &kubeone.HardeningConfig{},
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateHardeningConfig(tc.hardening, field.NewPath("hardening"))
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateSecurityModulesConfig(t *testing.T) {
+	tests := []struct {
+		name            string
+		securityModules *kubeone.SecurityModulesConfig
+		expectedError   bool
+	}{
+		{
+			name:            "valid config (nil)",
+			securityModules: nil,
+			expectedError:   false,
+		},
+		{
+			name:            "valid config (empty)",
+			securityModules: &kubeone.SecurityModulesConfig{},
+			expectedError:   false,
+		},
+		{
+			name: "valid config (enforcing, unmanaged apparmor)",
+			securityModules: &kubeone.SecurityModulesConfig{
+				SELinux:  kubeone.SELinuxModeEnforcing,
+				AppArmor: kubeone.AppArmorModeUnmanaged,
+			},
+			expectedError: false,
+		},
+		{
+			name:            "invalid config (unknown selinux mode)",
+			securityModules: &kubeone.SecurityModulesConfig{SELinux: "disabled"},
+			expectedError:   true,
+		},
+		{
+			name:            "invalid config (unknown apparmor mode)",
+			securityModules: &kubeone.SecurityModulesConfig{AppArmor: "enforcing"},
+			expectedError:   true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateSecurityModulesConfig(tc.securityModules, field.NewPath("securityModules"))
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateHostDiscoveryConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		hostDiscovery *kubeone.HostDiscoveryConfig
+		expectedError bool
+	}{
+		{
+			name:          "valid config (nil)",
+			hostDiscovery: nil,
+			expectedError: false,
+		},
+		{
+			name: "valid config",
+			hostDiscovery: &kubeone.HostDiscoveryConfig{
+				Tags:     map[string]string{"kubeone-cluster": "test", "role": "control-plane"},
+				Template: kubeone.HostConfig{SSHUsername: "ubuntu"},
+			},
+			expectedError: false,
+		},
+		{
+			name:          "invalid config (no tags)",
+			hostDiscovery: &kubeone.HostDiscoveryConfig{},
+			expectedError: true,
+		},
+		{
+			name: "invalid config (address in template)",
+			hostDiscovery: &kubeone.HostDiscoveryConfig{
+				Tags:     map[string]string{"role": "control-plane"},
+				Template: kubeone.HostConfig{PrivateAddress: "10.0.0.1"},
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid config (leader in template)",
+			hostDiscovery: &kubeone.HostDiscoveryConfig{
+				Tags:     map[string]string{"role": "control-plane"},
+				Template: kubeone.HostConfig{IsLeader: true},
+			},
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateHostDiscoveryConfig(tc.hostDiscovery, field.NewPath("hostDiscovery"))
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
 			}
 		})
 	}
 }
 
-func TestValidateHostConfig(t *testing.T) {
+func TestValidateFirewallConfig(t *testing.T) {
+	controlPlane := kubeone.ControlPlaneConfig{
+		Hosts: []kubeone.HostConfig{{PublicAddress: "192.0.2.10", PrivateAddress: "10.0.0.10"}},
+	}
+
 	tests := []struct {
 		name          string
-		hostConfig    []kubeone.HostConfig
+		firewall      *kubeone.FirewallConfig
 		expectedError bool
 	}{
 		{
-			name: "host config with ip addresses",
-			hostConfig: []kubeone.HostConfig{
-				{
-					PublicAddress:     "192.168.1.1",
-					PrivateAddress:    "192.168.0.1",
-					SSHPrivateKeyFile: "test",
-					SSHAgentSocket:    "test",
-					SSHUsername:       "root",
+			name:          "valid config (nil)",
+			firewall:      nil,
+			expectedError: false,
+		},
+		{
+			name:          "valid config (detected backend)",
+			firewall:      &kubeone.FirewallConfig{NodeCIDRs: []string{"10.0.0.0/24"}},
+			expectedError: false,
+		},
+		{
+			name: "valid config (nftables)",
+			firewall: &kubeone.FirewallConfig{
+				Backend:   kubeone.FirewallBackendNFTables,
+				NodeCIDRs: []string{"10.0.1.0/24", "10.0.0.0/24"},
+			},
+			expectedError: false,
+		},
+		{
+			name:          "invalid config (unknown backend)",
+			firewall:      &kubeone.FirewallConfig{Backend: "iptables", NodeCIDRs: []string{"10.0.0.0/24"}},
+			expectedError: true,
+		},
+		{
+			name:          "invalid config (no node CIDRs)",
+			firewall:      &kubeone.FirewallConfig{},
+			expectedError: true,
+		},
+		{
+			name:          "invalid config (invalid node CIDR)",
+			firewall:      &kubeone.FirewallConfig{NodeCIDRs: []string{"10.0.0.0"}},
+			expectedError: true,
+		},
+		{
+			name:          "invalid config (host not in node CIDRs)",
+			firewall:      &kubeone.FirewallConfig{NodeCIDRs: []string{"10.0.1.0/24"}},
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			c := kubeone.KubeOneCluster{
+				ControlPlane: controlPlane,
+				Firewall:     tc.firewall,
+			}
+			errs := ValidateFirewallConfig(c, field.NewPath("firewall"))
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateNvidiaGPU(t *testing.T) {
+	nvidiaContainerd := kubeone.ContainerRuntimeConfig{
+		Containerd: &kubeone.ContainerRuntimeContainerd{
+			NvidiaRuntime: &kubeone.ContainerdNvidiaRuntime{Enable: true},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		cluster       kubeone.KubeOneCluster
+		expectedError bool
+	}{
+		{
+			name:          "valid config (disabled)",
+			cluster:       kubeone.KubeOneCluster{},
+			expectedError: false,
+		},
+		{
+			name: "valid config",
+			cluster: kubeone.KubeOneCluster{
+				ContainerRuntime: nvidiaContainerd,
+				Features: kubeone.Features{
+					NvidiaGPU: &kubeone.NvidiaGPU{Enable: true, DriverVersion: "470"},
 				},
+				StaticWorkers: kubeone.StaticWorkersConfig{
+					Hosts: []kubeone.HostConfig{{NvidiaGPU: true}},
+				},
+				DynamicWorkers: []kubeone.DynamicWorkerConfig{{NvidiaGPU: true}},
 			},
 			expectedError: false,
 		},
 		{
-			name: "host config with dns domain",
-			hostConfig: []kubeone.HostConfig{
-				{
-					PublicAddress:     "cluster-test.public.local",
-					PrivateAddress:    "cluster-test.private.local",
-					SSHPrivateKeyFile: "test",
-					SSHAgentSocket:    "test",
-					SSHUsername:       "root",
+			name: "invalid config (nvidia runtime disabled)",
+			cluster: kubeone.KubeOneCluster{
+				ContainerRuntime: kubeone.ContainerRuntimeConfig{Containerd: &kubeone.ContainerRuntimeContainerd{}},
+				Features: kubeone.Features{
+					NvidiaGPU: &kubeone.NvidiaGPU{Enable: true},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid config (driver version)",
+			cluster: kubeone.KubeOneCluster{
+				ContainerRuntime: nvidiaContainerd,
+				Features: kubeone.Features{
+					NvidiaGPU: &kubeone.NvidiaGPU{Enable: true, DriverVersion: "470.82.01"},
 				},
 			},
+			expectedError: true,
+		},
+		{
+			name: "invalid config (static host without feature)",
+			cluster: kubeone.KubeOneCluster{
+				StaticWorkers: kubeone.StaticWorkersConfig{
+					Hosts: []kubeone.HostConfig{{NvidiaGPU: true}},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid config (workerset without feature)",
+			cluster: kubeone.KubeOneCluster{
+				DynamicWorkers: []kubeone.DynamicWorkerConfig{{NvidiaGPU: true}},
+			},
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateNvidiaGPU(tc.cluster, field.NewPath(""))
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateClusterAutoscaler(t *testing.T) {
+	replicas := func(r int) *int { return &r }
+
+	tests := []struct {
+		name          string
+		cluster       kubeone.KubeOneCluster
+		expectedError bool
+	}{
+		{
+			name:          "valid config (disabled)",
+			cluster:       kubeone.KubeOneCluster{},
 			expectedError: false,
 		},
 		{
-			name: "no public address provided",
-			hostConfig: []kubeone.HostConfig{
-				{
-					PublicAddress:     "",
-					PrivateAddress:    "cluster-test.private.local",
-					SSHPrivateKeyFile: "test",
-					SSHAgentSocket:    "test",
-					SSHUsername:       "root",
+			name: "valid config",
+			cluster: kubeone.KubeOneCluster{
+				Features: kubeone.Features{
+					ClusterAutoscaler: &kubeone.ClusterAutoscaler{
+						Enable:                true,
+						Expander:              "least-waste",
+						ScaleDownUnneededTime: &metav1.Duration{Duration: 5 * time.Minute},
+					},
+				},
+				DynamicWorkers: []kubeone.DynamicWorkerConfig{
+					{Replicas: replicas(2), Autoscaling: &kubeone.WorkersetAutoscaling{MinReplicas: 1, MaxReplicas: 5}},
+					{Replicas: replicas(3)},
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "invalid config (expander)",
+			cluster: kubeone.KubeOneCluster{
+				Features: kubeone.Features{
+					ClusterAutoscaler: &kubeone.ClusterAutoscaler{Enable: true, Expander: "cheapest"},
 				},
 			},
 			expectedError: true,
 		},
 		{
-			name: "no private address provided",
-			hostConfig: []kubeone.HostConfig{
-				{
-					PublicAddress:     "cluster-test.public.local",
-					PrivateAddress:    "",
-					SSHPrivateKeyFile: "test",
-					SSHAgentSocket:    "test",
-					SSHUsername:       "root",
+			name: "invalid config (negative scaleDownDelayAfterAdd)",
+			cluster: kubeone.KubeOneCluster{
+				Features: kubeone.Features{
+					ClusterAutoscaler: &kubeone.ClusterAutoscaler{
+						Enable:                 true,
+						ScaleDownDelayAfterAdd: &metav1.Duration{Duration: -time.Minute},
+					},
 				},
 			},
 			expectedError: true,
 		},
 		{
-			name: "no private key file and agent provided",
-			hostConfig: []kubeone.HostConfig{
-				{
-					PublicAddress:     "cluster-test.public.local",
-					PrivateAddress:    "cluster-test.private.local",
-					SSHPrivateKeyFile: "",
-					SSHAgentSocket:    "",
-					SSHUsername:       "root",
+			name: "invalid config (workerset without feature)",
+			cluster: kubeone.KubeOneCluster{
+				DynamicWorkers: []kubeone.DynamicWorkerConfig{
+					{Replicas: replicas(1), Autoscaling: &kubeone.WorkersetAutoscaling{MinReplicas: 1, MaxReplicas: 3}},
 				},
 			},
 			expectedError: true,
 		},
 		{
-			name: "no username provided",
-			hostConfig: []kubeone.HostConfig{
-				{
-					PublicAddress:     "cluster-test.public.local",
-					PrivateAddress:    "cluster-test.private.local",
-					SSHPrivateKeyFile: "test",
-					SSHAgentSocket:    "test",
-					SSHUsername:       "",
+			name: "invalid config (zero minReplicas)",
+			cluster: kubeone.KubeOneCluster{
+				Features: kubeone.Features{
+					ClusterAutoscaler: &kubeone.ClusterAutoscaler{Enable: true},
+				},
+				DynamicWorkers: []kubeone.DynamicWorkerConfig{
+					{Replicas: replicas(1), Autoscaling: &kubeone.WorkersetAutoscaling{MinReplicas: 0, MaxReplicas: 3}},
 				},
 			},
 			expectedError: true,
 		},
 		{
-			name: "one valid host config and one invalid host config (no username)",
-			hostConfig: []kubeone.HostConfig{
-				{
-					PublicAddress:     "192.168.1.1",
-					PrivateAddress:    "192.168.0.1",
-					SSHPrivateKeyFile: "test",
-					SSHAgentSocket:    "test",
-					SSHUsername:       "root",
+			name: "invalid config (maxReplicas less than minReplicas)",
+			cluster: kubeone.KubeOneCluster{
+				Features: kubeone.Features{
+					ClusterAutoscaler: &kubeone.ClusterAutoscaler{Enable: true},
 				},
-				{
-					PublicAddress:     "cluster-test.public.local",
-					PrivateAddress:    "cluster-test.private.local",
-					SSHPrivateKeyFile: "test",
-					SSHAgentSocket:    "test",
-					SSHUsername:       "",
+				DynamicWorkers: []kubeone.DynamicWorkerConfig{
+					{Replicas: replicas(2), Autoscaling: &kubeone.WorkersetAutoscaling{MinReplicas: 2, MaxReplicas: 1}},
 				},
 			},
 			expectedError: true,
 		},
 		{
-			name: "two leaders at the same time",
-			hostConfig: []kubeone.HostConfig{
-				{
-					PublicAddress:     "192.168.1.1",
-					PrivateAddress:    "192.168.0.1",
-					SSHPrivateKeyFile: "test",
-					SSHAgentSocket:    "test",
-					SSHUsername:       "root",
-					IsLeader:          true,
+			name: "invalid config (replicas out of range)",
+			cluster: kubeone.KubeOneCluster{
+				Features: kubeone.Features{
+					ClusterAutoscaler: &kubeone.ClusterAutoscaler{Enable: true},
+				},
+				DynamicWorkers: []kubeone.DynamicWorkerConfig{
+					{Replicas: replicas(5), Autoscaling: &kubeone.WorkersetAutoscaling{MinReplicas: 1, MaxReplicas: 3}},
+				},
+			},
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateClusterAutoscaler(tc.cluster, field.NewPath(""))
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateCertManager(t *testing.T) {
+	tests := []struct {
+		name          string
+		cluster       kubeone.KubeOneCluster
+		expectedError bool
+	}{
+		{
+			name:          "valid config (disabled)",
+			cluster:       kubeone.KubeOneCluster{},
+			expectedError: false,
+		},
+		{
+			name: "valid config (without ClusterIssuer)",
+			cluster: kubeone.KubeOneCluster{
+				Features: kubeone.Features{
+					CertManager: &kubeone.CertManager{Enable: true},
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "valid config (cluster CA ClusterIssuer)",
+			cluster: kubeone.KubeOneCluster{
+				Features: kubeone.Features{
+					CertManager: &kubeone.CertManager{
+						Enable:        true,
+						ClusterIssuer: &kubeone.CertManagerClusterIssuer{Name: "cluster-ca"},
+					},
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "valid config (provided CA ClusterIssuer with external CA signer)",
+			cluster: kubeone.KubeOneCluster{
+				CertificateAuthority: &kubeone.CertificateAuthorityConfig{
+					External: &kubeone.ExternalCAConfig{Signer: &kubeone.ExternalCASigner{}},
+				},
+				Features: kubeone.Features{
+					CertManager: &kubeone.CertManager{
+						Enable:        true,
+						ClusterIssuer: &kubeone.CertManagerClusterIssuer{CASecretName: "my-ca"},
+					},
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "invalid config (ClusterIssuer without cert-manager)",
+			cluster: kubeone.KubeOneCluster{
+				Features: kubeone.Features{
+					CertManager: &kubeone.CertManager{
+						ClusterIssuer: &kubeone.CertManagerClusterIssuer{},
+					},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid config (ClusterIssuer name)",
+			cluster: kubeone.KubeOneCluster{
+				Features: kubeone.Features{
+					CertManager: &kubeone.CertManager{
+						Enable:        true,
+						ClusterIssuer: &kubeone.CertManagerClusterIssuer{Name: "Cluster_CA"},
+					},
 				},
-				{
-					PublicAddress:     "cluster-test.public.local",
-					PrivateAddress:    "cluster-test.private.local",
-					SSHPrivateKeyFile: "test",
-					SSHAgentSocket:    "test",
-					SSHUsername:       "root",
-					IsLeader:          true,
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid config (cluster CA ClusterIssuer with external CA signer)",
+			cluster: kubeone.KubeOneCluster{
+				CertificateAuthority: &kubeone.CertificateAuthorityConfig{
+					External: &kubeone.ExternalCAConfig{Signer: &kubeone.ExternalCASigner{}},
+				},
+				Features: kubeone.Features{
+					CertManager: &kubeone.CertManager{
+						Enable:        true,
+						ClusterIssuer: &kubeone.CertManagerClusterIssuer{},
+					},
 				},
 			},
 			expectedError: true,
 		},
 	}
-
 	for _, tc := range tests {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
-			errs := ValidateHostConfig(tc.hostConfig, nil)
+			errs := ValidateCertManager(tc.cluster, field.NewPath("features", "certManager"))
 			if (len(errs) == 0) == tc.expectedError {
 				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
 			}
@@ -1674,41 +4167,70 @@ func TestValidateHostConfig(t *testing.T) {
 	}
 }
 
-func TestValidateRegistryConfiguration(t *testing.T) {
+func TestValidateStaticPods(t *testing.T) {
+	validManifest := heredoc.Doc(`
+		apiVersion: v1
+		kind: Pod
+		metadata:
+		  name: node-cache
+		  namespace: kube-system
+		spec:
+		  containers:
+		  - name: cache
+		    image: registry.example.com/node-cache:v1.0.0
+	`)
+
 	tests := []struct {
-		name                  string
-		registryConfiguration *kubeone.RegistryConfiguration
-		expectedError         bool
+		name          string
+		staticPods    []kubeone.StaticPod
+		expectedError bool
 	}{
 		{
-			name: "valid registry config (overwrite registry)",
-			registryConfiguration: &kubeone.RegistryConfiguration{
-				OverwriteRegistry: "127.0.0.1:5000",
-			},
+			name:          "valid static pods (nil)",
+			staticPods:    nil,
 			expectedError: false,
 		},
 		{
-			name: "valid registry config (overwrite registry and insecure)",
-			registryConfiguration: &kubeone.RegistryConfiguration{
-				OverwriteRegistry: "127.0.0.1:5000",
-				InsecureRegistry:  true,
+			name: "valid static pods",
+			staticPods: []kubeone.StaticPod{
+				{Name: "node-cache", Manifest: validManifest},
 			},
 			expectedError: false,
 		},
 		{
-			name:                  "valid registry config (empty)",
-			registryConfiguration: &kubeone.RegistryConfiguration{},
-			expectedError:         false,
+			name: "invalid static pods (invalid name)",
+			staticPods: []kubeone.StaticPod{
+				{Name: "Node_Cache", Manifest: validManifest},
+			},
+			expectedError: true,
 		},
 		{
-			name:                  "valid registry config (nil)",
-			registryConfiguration: nil,
-			expectedError:         false,
+			name: "invalid static pods (duplicated name)",
+			staticPods: []kubeone.StaticPod{
+				{Name: "node-cache", Manifest: validManifest},
+				{Name: "node-cache", Manifest: validManifest},
+			},
+			expectedError: true,
 		},
 		{
-			name: "invalid registry config (insecure registry without overwrite registry)",
-			registryConfiguration: &kubeone.RegistryConfiguration{
-				InsecureRegistry: true,
+			name: "invalid static pods (no manifest)",
+			staticPods: []kubeone.StaticPod{
+				{Name: "node-cache"},
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid static pods (not a pod)",
+			staticPods: []kubeone.StaticPod{
+				{
+					Name: "node-cache",
+					Manifest: heredoc.Doc(`
+						apiVersion: apps/v1
+						kind: Deployment
+						metadata:
+						  name: node-cache
+					`),
+				},
 			},
 			expectedError: true,
 		},
@@ -1716,190 +4238,296 @@ func TestValidateRegistryConfiguration(t *testing.T) {
 	for _, tc := range tests {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
-			errs := ValidateRegistryConfiguration(tc.registryConfiguration, nil)
+			errs := ValidateStaticPods(tc.staticPods, nil)
 			if (len(errs) == 0) == tc.expectedError {
-				t.Log(errs[0])
 				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
 			}
 		})
 	}
 }
 
-func TestValidateAssetConfiguration(t *testing.T) {
+// newTestCA generates a CA certificate signed by the parent CA, or a
+// self-signed CA if parent is nil, and returns the PEM encoded certificate
+// and key
+func newTestCA(t *testing.T, commonName string, parent *x509.Certificate, parentKey *rsa.PrivateKey) (*x509.Certificate, *rsa.PrivateKey, string, string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	return cert, key, string(certPEM), string(keyPEM)
+}
+
+func TestValidateCertificateAuthorityConfig(t *testing.T) {
+	rootCA, rootKey, rootPEM, _ := newTestCA(t, "root", nil, nil)
+	_, _, intermediatePEM, intermediateKeyPEM := newTestCA(t, "intermediate", rootCA, rootKey)
+	_, _, otherPEM, otherKeyPEM := newTestCA(t, "other", nil, nil)
+	signer := &kubeone.ExternalCASigner{Command: "/usr/local/bin/sign-csr"}
+	keyProvider := &kubeone.ExternalCAKeyProvider{
+		AWSKMS: &kubeone.AWSKMSKeyProvider{KeyID: "alias/kubernetes-ca"},
+	}
+
 	tests := []struct {
-		name               string
-		assetConfiguration *kubeone.AssetConfiguration
-		expectedError      bool
+		name           string
+		keyAlgorithm   kubeone.KeyAlgorithm
+		validity       *metav1.Duration
+		renewBefore    *metav1.Duration
+		ca             *kubeone.ExternalCAConfig
+		dynamicWorkers []kubeone.DynamicWorkerConfig
+		expectedError  bool
 	}{
 		{
-			name:               "empty asset configuration",
-			assetConfiguration: &kubeone.AssetConfiguration{},
-			expectedError:      false,
+			name:          "valid config (nil)",
+			ca:            nil,
+			expectedError: false,
 		},
 		{
-			name: "kubernetes image configured",
-			assetConfiguration: &kubeone.AssetConfiguration{
-				Kubernetes: kubeone.ImageAsset{
-					ImageRepository: "127.0.0.1:5000",
-				},
-			},
+			name:          "valid config (ECDSA keys)",
+			keyAlgorithm:  kubeone.KeyAlgorithmECDSA,
 			expectedError: false,
 		},
 		{
-			name: "kubernetes image and tag configured",
-			assetConfiguration: &kubeone.AssetConfiguration{
-				Kubernetes: kubeone.ImageAsset{
-					ImageRepository: "127.0.0.1:5000",
-					ImageTag:        "test",
-				},
-			},
+			name:          "valid config (Ed25519 keys with signer)",
+			keyAlgorithm:  kubeone.KeyAlgorithmEd25519,
+			ca:            &kubeone.ExternalCAConfig{CACertificate: rootPEM, Signer: signer},
+			expectedError: false,
+		},
+		{
+			name:          "invalid config (unsupported key algorithm)",
+			keyAlgorithm:  "DSA",
 			expectedError: true,
 		},
 		{
-			name: "kubernetes tag configured",
-			assetConfiguration: &kubeone.AssetConfiguration{
-				Kubernetes: kubeone.ImageAsset{
-					ImageTag: "test",
-				},
-			},
+			name:          "valid config (certificate validity and renewal threshold)",
+			validity:      &metav1.Duration{Duration: 30 * 24 * time.Hour},
+			renewBefore:   &metav1.Duration{Duration: 7 * 24 * time.Hour},
+			expectedError: false,
+		},
+		{
+			name:          "invalid config (non-positive certificate validity)",
+			validity:      &metav1.Duration{},
 			expectedError: true,
 		},
 		{
-			name: "pause image configured",
-			assetConfiguration: &kubeone.AssetConfiguration{
-				Pause: kubeone.ImageAsset{
-					ImageRepository: "127.0.0.1:5000",
-					ImageTag:        "3.2",
-				},
+			name:          "invalid config (non-positive renewal threshold)",
+			renewBefore:   &metav1.Duration{Duration: -time.Hour},
+			expectedError: true,
+		},
+		{
+			name:          "invalid config (renewal threshold longer than certificate validity)",
+			validity:      &metav1.Duration{Duration: 30 * 24 * time.Hour},
+			renewBefore:   &metav1.Duration{Duration: 60 * 24 * time.Hour},
+			expectedError: true,
+		},
+		{
+			name:          "invalid config (renewal threshold longer than kubeadm certificate validity)",
+			validity:      &metav1.Duration{Duration: 3 * 365 * 24 * time.Hour},
+			renewBefore:   &metav1.Duration{Duration: 400 * 24 * time.Hour},
+			expectedError: true,
+		},
+		{
+			name:          "valid config (root CA with signer)",
+			ca:            &kubeone.ExternalCAConfig{CACertificate: rootPEM, Signer: signer},
+			expectedError: false,
+		},
+		{
+			name: "valid config (intermediate CA with signer)",
+			ca: &kubeone.ExternalCAConfig{
+				CACertificate:           rootPEM,
+				IntermediateCertificate: intermediatePEM,
+				Signer:                  signer,
 			},
 			expectedError: false,
 		},
 		{
-			name: "pause image configured (repository missing)",
-			assetConfiguration: &kubeone.AssetConfiguration{
-				Pause: kubeone.ImageAsset{
-					ImageTag: "3.2",
-				},
+			name: "valid config (intermediate CA with key)",
+			ca: &kubeone.ExternalCAConfig{
+				CACertificate:           rootPEM,
+				IntermediateCertificate: intermediatePEM,
+				IntermediateKey:         intermediateKeyPEM,
 			},
+			dynamicWorkers: []kubeone.DynamicWorkerConfig{{Name: "pool1"}},
+			expectedError:  false,
+		},
+		{
+			name:          "invalid config (no CA certificate)",
+			ca:            &kubeone.ExternalCAConfig{Signer: signer},
 			expectedError: true,
 		},
 		{
-			name: "pause image configured (tag missing)",
-			assetConfiguration: &kubeone.AssetConfiguration{
-				Pause: kubeone.ImageAsset{
-					ImageRepository: "127.0.0.1:5000",
-				},
-			},
+			name:          "invalid config (no signer and no key)",
+			ca:            &kubeone.ExternalCAConfig{CACertificate: rootPEM},
 			expectedError: true,
 		},
 		{
-			name: "coredns image and tag configured",
-			assetConfiguration: &kubeone.AssetConfiguration{
-				CoreDNS: kubeone.ImageAsset{
-					ImageRepository: "127.0.0.1:5000",
-					ImageTag:        "test",
-				},
-			},
-			expectedError: false,
+			name:          "invalid config (signer without command)",
+			ca:            &kubeone.ExternalCAConfig{CACertificate: rootPEM, Signer: &kubeone.ExternalCASigner{}},
+			expectedError: true,
 		},
 		{
-			name: "coredns image configured",
-			assetConfiguration: &kubeone.AssetConfiguration{
-				CoreDNS: kubeone.ImageAsset{
-					ImageRepository: "127.0.0.1:5000",
-				},
+			name: "invalid config (intermediate CA not signed by the root CA)",
+			ca: &kubeone.ExternalCAConfig{
+				CACertificate:           rootPEM,
+				IntermediateCertificate: otherPEM,
+				Signer:                  signer,
 			},
-			expectedError: false,
+			expectedError: true,
 		},
 		{
-			name: "coredns tag configured",
-			assetConfiguration: &kubeone.AssetConfiguration{
-				CoreDNS: kubeone.ImageAsset{
-					ImageTag: "test",
-				},
+			name:          "invalid config (key without intermediate CA)",
+			ca:            &kubeone.ExternalCAConfig{CACertificate: rootPEM, IntermediateKey: intermediateKeyPEM},
+			expectedError: true,
+		},
+		{
+			name: "invalid config (key not matching the intermediate CA)",
+			ca: &kubeone.ExternalCAConfig{
+				CACertificate:           rootPEM,
+				IntermediateCertificate: intermediatePEM,
+				IntermediateKey:         otherKeyPEM,
 			},
-			expectedError: false,
+			expectedError: true,
 		},
 		{
-			name: "etcd image and tag configured",
-			assetConfiguration: &kubeone.AssetConfiguration{
-				Etcd: kubeone.ImageAsset{
-					ImageRepository: "127.0.0.1:5000",
-					ImageTag:        "test",
-				},
+			name: "invalid config (key and signer)",
+			ca: &kubeone.ExternalCAConfig{
+				CACertificate:           rootPEM,
+				IntermediateCertificate: intermediatePEM,
+				IntermediateKey:         intermediateKeyPEM,
+				Signer:                  signer,
 			},
+			expectedError: true,
+		},
+		{
+			name:          "valid config (root CA with key provider)",
+			ca:            &kubeone.ExternalCAConfig{CACertificate: rootPEM, KeyProvider: keyProvider},
 			expectedError: false,
 		},
 		{
-			name: "etcd image configured",
-			assetConfiguration: &kubeone.AssetConfiguration{
-				Etcd: kubeone.ImageAsset{
-					ImageRepository: "127.0.0.1:5000",
-				},
+			name:          "invalid config (signer and key provider)",
+			ca:            &kubeone.ExternalCAConfig{CACertificate: rootPEM, Signer: signer, KeyProvider: keyProvider},
+			expectedError: true,
+		},
+		{
+			name: "invalid config (key and key provider)",
+			ca: &kubeone.ExternalCAConfig{
+				CACertificate:           rootPEM,
+				IntermediateCertificate: intermediatePEM,
+				IntermediateKey:         intermediateKeyPEM,
+				KeyProvider:             keyProvider,
 			},
-			expectedError: false,
+			expectedError: true,
 		},
 		{
-			name: "etcd tag configured",
-			assetConfiguration: &kubeone.AssetConfiguration{
-				Etcd: kubeone.ImageAsset{
-					ImageTag: "test",
+			name:           "invalid config (key provider with dynamic workers)",
+			ca:             &kubeone.ExternalCAConfig{CACertificate: rootPEM, KeyProvider: keyProvider},
+			dynamicWorkers: []kubeone.DynamicWorkerConfig{{Name: "pool1"}},
+			expectedError:  true,
+		},
+		{
+			name:           "invalid config (signer with dynamic workers)",
+			ca:             &kubeone.ExternalCAConfig{CACertificate: rootPEM, Signer: signer},
+			dynamicWorkers: []kubeone.DynamicWorkerConfig{{Name: "pool1"}},
+			expectedError:  true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			c := kubeone.KubeOneCluster{
+				CertificateAuthority: &kubeone.CertificateAuthorityConfig{
+					KeyAlgorithm:        tc.keyAlgorithm,
+					CertificateValidity: tc.validity,
+					RenewBefore:         tc.renewBefore,
+					External:            tc.ca,
 				},
+				DynamicWorkers: tc.dynamicWorkers,
+			}
+			errs := ValidateCertificateAuthorityConfig(c, field.NewPath("certificateAuthority"))
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, errs)
+			}
+		})
+	}
+}
+
+func TestValidateExternalCAKeyProvider(t *testing.T) {
+	tests := []struct {
+		name          string
+		provider      kubeone.ExternalCAKeyProvider
+		expectedError bool
+	}{
+		{
+			name: "valid PKCS#11 key provider",
+			provider: kubeone.ExternalCAKeyProvider{
+				PKCS11: &kubeone.PKCS11KeyProvider{Module: "/usr/lib/softhsm/libsofthsm2.so", KeyID: "01a2"},
 			},
 			expectedError: false,
 		},
 		{
-			name: "metrics-server image and tag configured",
-			assetConfiguration: &kubeone.AssetConfiguration{
-				MetricsServer: kubeone.ImageAsset{
-					ImageRepository: "127.0.0.1:5000",
-					ImageTag:        "test",
-				},
+			name: "valid AWS KMS key provider",
+			provider: kubeone.ExternalCAKeyProvider{
+				AWSKMS: &kubeone.AWSKMSKeyProvider{KeyID: "alias/kubernetes-ca", Region: "eu-west-1"},
 			},
 			expectedError: false,
 		},
 		{
-			name: "metrics-server image configured",
-			assetConfiguration: &kubeone.AssetConfiguration{
-				MetricsServer: kubeone.ImageAsset{
-					ImageRepository: "127.0.0.1:5000",
-				},
+			name:          "no key provider",
+			provider:      kubeone.ExternalCAKeyProvider{},
+			expectedError: true,
+		},
+		{
+			name: "multiple key providers",
+			provider: kubeone.ExternalCAKeyProvider{
+				PKCS11: &kubeone.PKCS11KeyProvider{Module: "/usr/lib/softhsm/libsofthsm2.so", KeyID: "01"},
+				AWSKMS: &kubeone.AWSKMSKeyProvider{KeyID: "alias/kubernetes-ca"},
 			},
-			expectedError: false,
+			expectedError: true,
 		},
 		{
-			name: "metrics-server tag configured",
-			assetConfiguration: &kubeone.AssetConfiguration{
-				MetricsServer: kubeone.ImageAsset{
-					ImageTag: "test",
-				},
+			name: "PKCS#11 key provider without module",
+			provider: kubeone.ExternalCAKeyProvider{
+				PKCS11: &kubeone.PKCS11KeyProvider{KeyID: "01"},
 			},
-			expectedError: false,
+			expectedError: true,
 		},
 		{
-			name: "cni, node binaries, and kubectl configured",
-			assetConfiguration: &kubeone.AssetConfiguration{
-				CNI: kubeone.BinaryAsset{
-					URL: "https://127.0.0.1/cni",
-				},
-				NodeBinaries: kubeone.BinaryAsset{
-					URL: "https://127.0.0.1/kubernetes-node-linux-amd64.tar.gz",
-				},
-				Kubectl: kubeone.BinaryAsset{
-					URL: "https://127.0.0.1/kubectl",
-				},
+			name: "PKCS#11 key provider with invalid key ID",
+			provider: kubeone.ExternalCAKeyProvider{
+				PKCS11: &kubeone.PKCS11KeyProvider{Module: "/usr/lib/softhsm/libsofthsm2.so", KeyID: "ca-key"},
 			},
-			expectedError: false,
+			expectedError: true,
 		},
 		{
-			name: "binary assets configured (node binaries missing)",
-			assetConfiguration: &kubeone.AssetConfiguration{
-				CNI: kubeone.BinaryAsset{
-					URL: "https://127.0.0.1/cni",
-				},
-				Kubectl: kubeone.BinaryAsset{
-					URL: "https://127.0.0.1/kubectl",
-				},
+			name: "AWS KMS key provider without key ID",
+			provider: kubeone.ExternalCAKeyProvider{
+				AWSKMS: &kubeone.AWSKMSKeyProvider{Region: "eu-west-1"},
 			},
 			expectedError: true,
 		},
@@ -1907,15 +4535,10 @@ func TestValidateAssetConfiguration(t *testing.T) {
 	for _, tc := range tests {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
-			errs := ValidateAssetConfiguration(tc.assetConfiguration, nil)
+			errs := ValidateExternalCAKeyProvider(tc.provider, field.NewPath("keyProvider"))
 			if (len(errs) == 0) == tc.expectedError {
-				t.Log(errs[0])
-				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, errs)
 			}
 		})
 	}
 }
-
-func intPtr(i int) *int {
-	return &i
-}
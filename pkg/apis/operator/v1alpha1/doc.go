@@ -0,0 +1,22 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// +groupName=operator.kubeone.io
+// +k8s:deepcopy-gen=package
+
+// Package v1alpha1 defines the v1alpha1 version of the KubeOne operator API,
+// the KubeOneCluster custom resource reconciled by 'kubeone operator'
+package v1alpha1
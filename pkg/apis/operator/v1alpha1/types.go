@@ -0,0 +1,106 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// KubeOneClusterPhase is the phase of the reconciliation of the cluster
+type KubeOneClusterPhase string
+
+const (
+	// PhasePending means the cluster is not reconciled yet
+	PhasePending KubeOneClusterPhase = "Pending"
+	// PhaseReconciling means the cluster is being reconciled
+	PhaseReconciling KubeOneClusterPhase = "Reconciling"
+	// PhaseReady means the last reconciliation succeeded
+	PhaseReady KubeOneClusterPhase = "Ready"
+	// PhaseFailed means the last reconciliation failed
+	PhaseFailed KubeOneClusterPhase = "Failed"
+	// PhasePaused means the reconciliation is paused
+	PhasePaused KubeOneClusterPhase = "Paused"
+)
+
+const (
+	// ConditionReady is the condition reporting whether the last
+	// reconciliation succeeded
+	ConditionReady = "Ready"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// KubeOneCluster is the cluster managed by the KubeOne operator
+type KubeOneCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KubeOneClusterSpec   `json:"spec"`
+	Status KubeOneClusterStatus `json:"status,omitempty"`
+}
+
+// KubeOneClusterSpec is the desired state of the cluster
+type KubeOneClusterSpec struct {
+	// Manifest is the KubeOneCluster manifest (kubeone.io/v1beta1 or
+	// kubeone.io/v1alpha1), the same as given to 'kubeone apply'.
+	Manifest runtime.RawExtension `json:"manifest"`
+	// SSHPrivateKeySecretRef references the key of the Secret, in the
+	// namespace of the KubeOneCluster, holding the SSH private key used to
+	// access the hosts without their own private key file.
+	SSHPrivateKeySecretRef *corev1.SecretKeySelector `json:"sshPrivateKeySecretRef,omitempty"`
+	// CredentialsSecretRef references the Secret, in the namespace of the
+	// KubeOneCluster, holding the cloud provider credentials, the same keys
+	// as in the credentials file given to 'kubeone apply'.
+	CredentialsSecretRef *corev1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
+	// UpgradeMachineDeployments upgrades the MachineDeployments objects, the
+	// same as the --upgrade-machine-deployments flag.
+	UpgradeMachineDeployments bool `json:"upgradeMachineDeployments,omitempty"`
+	// Paused stops reconciling the cluster.
+	Paused bool `json:"paused,omitempty"`
+}
+
+// KubeOneClusterStatus is the observed state of the cluster
+type KubeOneClusterStatus struct {
+	// ObservedGeneration is the generation of the spec last reconciled
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Phase of the reconciliation
+	Phase KubeOneClusterPhase `json:"phase,omitempty"`
+	// LastAction is the action taken by the last reconciliation, such as
+	// install, repair, upgrade or reconcile
+	LastAction string `json:"lastAction,omitempty"`
+	// LastReconcileTime is the time the last reconciliation finished
+	LastReconcileTime *metav1.Time `json:"lastReconcileTime,omitempty"`
+	// KubernetesVersion is the Kubernetes version of the last successful
+	// reconciliation
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+	// Message is the error of the last failed reconciliation
+	Message string `json:"message,omitempty"`
+	// Conditions of the cluster
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// KubeOneClusterList is the list of KubeOneClusters
+type KubeOneClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []KubeOneCluster `json:"items"`
+}
@@ -19,12 +19,14 @@ package archive
 import (
 	"archive/tar"
 	"compress/gzip"
+	"io"
 	"os"
 
 	"github.com/pkg/errors"
 )
 
 type tarGzip struct {
+	// file is nil if the archive is written to the writer
 	file *os.File
 	gz   *gzip.Writer
 	arch *tar.Writer
@@ -37,14 +39,26 @@ func NewTarGzip(filename string) (Archive, error) {
 		return nil, err
 	}
 
-	gz := gzip.NewWriter(f)
+	tgz := newTarGzip(f)
+	tgz.file = f
+
+	return tgz, nil
+}
+
+// NewTarGzipWriter returns a new tar.gz archive written to the writer. The
+// archive is complete once it's closed.
+func NewTarGzipWriter(w io.Writer) Archive {
+	return newTarGzip(w)
+}
+
+func newTarGzip(w io.Writer) *tarGzip {
+	gz := gzip.NewWriter(w)
 	arch := tar.NewWriter(gz)
 
 	return &tarGzip{
-		file: f,
 		gz:   gz,
 		arch: arch,
-	}, nil
+	}
 }
 
 func (tgz tarGzip) Add(file string, content string) error {
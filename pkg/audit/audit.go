@@ -0,0 +1,224 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit records all changes KubeOne makes to the cluster hosts and
+// to the Kubernetes API into an append-only local file, one JSON object per
+// line.
+package audit
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"k8c.io/kubeone/pkg/secretstore"
+)
+
+const (
+	// TypeRun is recorded once for each KubeOne run writing to the audit log
+	TypeRun = "run"
+	// TypeSSHCommand is a command run on the host over SSH
+	TypeSSHCommand = "ssh-command"
+	// TypeFileUpload is a command run on the host over SSH with the uploaded
+	// data on its stdin, e.g. writing a file or applying manifests
+	TypeFileUpload = "file-upload"
+	// TypeAPIMutation is a change of an object made using the Kubernetes API
+	TypeAPIMutation = "api-mutation"
+
+	// OutcomeSuccess is the outcome of the succeeded operation
+	OutcomeSuccess = "success"
+	// OutcomeFailure is the outcome of the failed operation
+	OutcomeFailure = "failure"
+
+	// redacted replaces the secrets in the recorded commands and errors
+	redacted = "[REDACTED]"
+)
+
+var (
+	// bootstrapTokenRegexp matches the kubeadm bootstrap tokens
+	// (<token-id>.<token-secret>). Only the token secret is redacted.
+	bootstrapTokenRegexp = regexp.MustCompile(`\b([a-z0-9]{6})\.[a-z0-9]{16}\b`)
+	// certificateKeyRegexp matches the key encrypting the control plane
+	// certificates uploaded by kubeadm
+	certificateKeyRegexp = regexp.MustCompile(`(--certificate-key[= ]+)[a-fA-F0-9]+`)
+)
+
+// Resource identifies the Kubernetes object changed by the API request
+type Resource struct {
+	Group       string `json:"group,omitempty"`
+	Version     string `json:"version,omitempty"`
+	Resource    string `json:"resource"`
+	Subresource string `json:"subresource,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+	Name        string `json:"name,omitempty"`
+}
+
+// Entry is a single record in the audit log. Contents of the uploaded
+// files and Kubernetes objects are not recorded, as they can include
+// secrets. The bootstrap tokens, certificate keys and the secrets resolved
+// from the external secret stores are redacted from the commands and errors.
+type Entry struct {
+	Time  time.Time `json:"time"`
+	RunID string    `json:"runID"`
+	Type  string    `json:"type"`
+
+	// Host is the public address of the host, for the SSH operations
+	Host string `json:"host,omitempty"`
+	// Command is the command run over SSH, or the KubeOne command line for
+	// the run entries
+	Command string `json:"command,omitempty"`
+	// Bytes is the size of the data uploaded to the host
+	Bytes int64 `json:"bytes,omitempty"`
+	// ExitCode is the exit code of the command run over SSH
+	ExitCode *int `json:"exitCode,omitempty"`
+
+	// Verb is the Kubernetes API operation, e.g. create or patch
+	Verb     string    `json:"verb,omitempty"`
+	Path     string    `json:"path,omitempty"`
+	Resource *Resource `json:"resource,omitempty"`
+	DryRun   bool      `json:"dryRun,omitempty"`
+	// StatusCode is the HTTP status code of the Kubernetes API response
+	StatusCode int `json:"statusCode,omitempty"`
+
+	Outcome  string `json:"outcome,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration,omitempty"`
+}
+
+// Log is the append-only audit log file. The nil *Log doesn't record
+// anything, so callers don't have to check if auditing is enabled.
+type Log struct {
+	lock  sync.Mutex
+	file  *os.File
+	runID string
+	now   func() time.Time
+	// secrets returns the secrets to redact from the entries
+	secrets func() []string
+	// logger reports the operations which failed to be recorded
+	logger logrus.FieldLogger
+}
+
+// Open opens the audit log file for appending, creating it if needed, and
+// records the start of the run with the given command line
+func Open(path string, command string, logger logrus.FieldLogger) (*Log, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open audit log")
+	}
+
+	runID := make([]byte, 8)
+	if _, err = rand.Read(runID); err != nil {
+		f.Close()
+		return nil, errors.Wrap(err, "failed to generate run ID")
+	}
+
+	l := &Log{
+		file:    f,
+		runID:   hex.EncodeToString(runID),
+		now:     time.Now,
+		secrets: secretstore.Resolved,
+		logger:  logger,
+	}
+
+	if err = l.Record(Entry{Type: TypeRun, Command: command}); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Record appends the entry to the audit log, setting its time and run ID
+func (l *Log) Record(e Entry) error {
+	if l == nil {
+		return nil
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	e.Time = l.now().UTC()
+	e.RunID = l.runID
+	e.Command = l.redact(e.Command)
+	e.Error = l.redact(e.Error)
+
+	buf, err := json.Marshal(e)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode audit log entry")
+	}
+
+	// Each entry is written at once, so entries of the parallel tasks are
+	// not interleaved
+	_, err = l.file.Write(append(buf, '\n'))
+
+	return errors.Wrap(err, "failed to write audit log entry")
+}
+
+// redact returns the text with the known secrets replaced
+func (l *Log) redact(text string) string {
+	if text == "" {
+		return text
+	}
+
+	text = bootstrapTokenRegexp.ReplaceAllString(text, "${1}."+redacted)
+	text = certificateKeyRegexp.ReplaceAllString(text, "${1}"+redacted)
+
+	if l.secrets != nil {
+		for _, secret := range l.secrets() {
+			if secret != "" {
+				text = strings.ReplaceAll(text, secret, redacted)
+			}
+		}
+	}
+
+	return text
+}
+
+// Close closes the audit log file
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return errors.Wrap(l.file.Close(), "failed to close audit log")
+}
+
+// record records the outcome of the operation which started at start. The
+// operation can't be undone, so failure to record it is not returned, but
+// it's logged instead.
+func (l *Log) record(e Entry, start time.Time, err error) {
+	e.Duration = l.now().Sub(start).Round(time.Millisecond).String()
+	e.Outcome = OutcomeSuccess
+	if err != nil {
+		e.Outcome = OutcomeFailure
+		e.Error = err.Error()
+	}
+
+	if rerr := l.Record(e); rerr != nil {
+		l.logger.Warnf("Failed to record %s in the audit log: %v", e.Type, rerr)
+	}
+}
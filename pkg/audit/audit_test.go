@@ -0,0 +1,320 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+)
+
+type fakeConnection struct {
+	exitCode int
+	err      error
+}
+
+func (c *fakeConnection) Exec(cmd string) (string, string, int, error) {
+	return "", "", c.exitCode, c.err
+}
+
+func (c *fakeConnection) POpen(cmd string, stdin io.Reader, stdout io.Writer, stderr io.Writer) (int, error) {
+	if stdin != nil {
+		if _, err := io.Copy(ioutil.Discard, stdin); err != nil {
+			return -1, err
+		}
+	}
+
+	return c.exitCode, c.err
+}
+
+func (c *fakeConnection) Close() error {
+	return nil
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func readEntries(t *testing.T, path string) []Entry {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+	defer f.Close()
+
+	entries := []Entry{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		e := Entry{}
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("failed to decode audit log line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, e)
+	}
+
+	return entries
+}
+
+func openTestLog(t *testing.T) (*Log, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	l, err := Open(path, "kubeone apply", logrus.New())
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+
+	return l, path
+}
+
+func TestConnection(t *testing.T) {
+	l, path := openTestLog(t)
+	host := kubeoneapi.HostConfig{PublicAddress: "192.0.2.1"}
+
+	conn := l.WrapConnection(host, &fakeConnection{})
+	if _, _, _, err := conn.Exec("sudo systemctl restart kubelet"); err != nil {
+		t.Fatalf("exec failed: %v", err)
+	}
+	if _, err := conn.POpen("sudo dd of=/etc/kubeone/config", strings.NewReader("secret"), nil, nil); err != nil {
+		t.Fatalf("popen failed: %v", err)
+	}
+
+	failing := l.WrapConnection(host, &fakeConnection{exitCode: 1, err: errors.New("exit status 1")})
+	_, _, _, _ = failing.Exec("false")
+
+	if err := l.Close(); err != nil {
+		t.Fatalf("failed to close audit log: %v", err)
+	}
+
+	entries := readEntries(t, path)
+	if len(entries) != 4 {
+		t.Fatalf("expected 4 entries, got %d", len(entries))
+	}
+
+	tests := []struct {
+		name     string
+		entry    Entry
+		typ      string
+		command  string
+		bytes    int64
+		exitCode int
+		outcome  string
+	}{
+		{
+			name:    "run",
+			entry:   entries[0],
+			typ:     TypeRun,
+			command: "kubeone apply",
+		},
+		{
+			name:    "command",
+			entry:   entries[1],
+			typ:     TypeSSHCommand,
+			command: "sudo systemctl restart kubelet",
+			outcome: OutcomeSuccess,
+		},
+		{
+			name:    "upload",
+			entry:   entries[2],
+			typ:     TypeFileUpload,
+			command: "sudo dd of=/etc/kubeone/config",
+			bytes:   6,
+			outcome: OutcomeSuccess,
+		},
+		{
+			name:     "failed command",
+			entry:    entries[3],
+			typ:      TypeSSHCommand,
+			command:  "false",
+			exitCode: 1,
+			outcome:  OutcomeFailure,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.entry.RunID != entries[0].RunID || tt.entry.RunID == "" {
+				t.Errorf("unexpected run ID %q", tt.entry.RunID)
+			}
+			if tt.entry.Type != tt.typ {
+				t.Errorf("Type = %q, want %q", tt.entry.Type, tt.typ)
+			}
+			if tt.entry.Command != tt.command {
+				t.Errorf("Command = %q, want %q", tt.entry.Command, tt.command)
+			}
+			if tt.entry.Bytes != tt.bytes {
+				t.Errorf("Bytes = %d, want %d", tt.entry.Bytes, tt.bytes)
+			}
+			if tt.entry.Outcome != tt.outcome {
+				t.Errorf("Outcome = %q, want %q", tt.entry.Outcome, tt.outcome)
+			}
+			if tt.typ != TypeRun {
+				if tt.entry.Host != host.PublicAddress {
+					t.Errorf("Host = %q, want %q", tt.entry.Host, host.PublicAddress)
+				}
+				if tt.entry.ExitCode == nil || *tt.entry.ExitCode != tt.exitCode {
+					t.Errorf("ExitCode = %v, want %d", tt.entry.ExitCode, tt.exitCode)
+				}
+			}
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	l, path := openTestLog(t)
+	l.secrets = func() []string { return []string{"", "s3cr3t-value"} }
+	host := kubeoneapi.HostConfig{PublicAddress: "192.0.2.1"}
+
+	conn := l.WrapConnection(host, &fakeConnection{})
+	commands := []string{
+		"sudo kubeadm token create abcdef.0123456789abcdef --ttl 1h0m0s",
+		"sudo kubeadm init phase upload-certs --upload-certs --certificate-key=0a1b2c3d4e5f",
+		"echo s3cr3t-value | sudo tee /etc/kubeone/password",
+	}
+	for _, cmd := range commands {
+		if _, _, _, err := conn.Exec(cmd); err != nil {
+			t.Fatalf("exec failed: %v", err)
+		}
+	}
+
+	failing := l.WrapConnection(host, &fakeConnection{exitCode: 1, err: errors.New("invalid token abcdef.0123456789abcdef")})
+	_, _, _, _ = failing.Exec("true")
+
+	if err := l.Close(); err != nil {
+		t.Fatalf("failed to close audit log: %v", err)
+	}
+
+	entries := readEntries(t, path)
+	if len(entries) != 5 {
+		t.Fatalf("expected 5 entries, got %d", len(entries))
+	}
+
+	expected := []string{
+		"sudo kubeadm token create abcdef.[REDACTED] --ttl 1h0m0s",
+		"sudo kubeadm init phase upload-certs --upload-certs --certificate-key=[REDACTED]",
+		"echo [REDACTED] | sudo tee /etc/kubeone/password",
+	}
+	for i, cmd := range expected {
+		if entries[i+1].Command != cmd {
+			t.Errorf("Command = %q, want %q", entries[i+1].Command, cmd)
+		}
+	}
+	if entries[4].Error != "invalid token abcdef.[REDACTED]" {
+		t.Errorf("Error = %q, want the token redacted", entries[4].Error)
+	}
+}
+
+func TestTransport(t *testing.T) {
+	l, path := openTestLog(t)
+
+	rt := l.WrapTransport(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		status := http.StatusOK
+		if req.Method == http.MethodDelete {
+			status = http.StatusNotFound
+		}
+
+		return &http.Response{StatusCode: status, Body: ioutil.NopCloser(&bytes.Buffer{})}, nil
+	}))
+
+	requests := []struct {
+		method string
+		url    string
+		body   string
+	}{
+		{method: http.MethodGet, url: "https://cluster/api/v1/namespaces/kube-system/configmaps/kubeadm-config"},
+		{method: http.MethodPost, url: "https://cluster/api/v1/namespaces/kube-system/secrets", body: `{"metadata":{"name":"token"},"data":{"token":"c2VjcmV0"}}`},
+		{method: http.MethodPatch, url: "https://cluster/apis/apps/v1/namespaces/kube-system/deployments/coredns?dryRun=All"},
+		{method: http.MethodDelete, url: "https://cluster/apis/cluster.k8s.io/v1alpha1/namespaces/kube-system/machinedeployments/workers"},
+	}
+	for _, r := range requests {
+		req, err := http.NewRequest(r.method, r.url, strings.NewReader(r.body))
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("round trip failed: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	entries := readEntries(t, path)
+	if len(entries) != 4 {
+		t.Fatalf("expected 4 entries (run and 3 mutations), got %d", len(entries))
+	}
+
+	want := []Entry{
+		{
+			Verb:       "create",
+			Resource:   &Resource{Version: "v1", Resource: "secrets", Namespace: "kube-system", Name: "token"},
+			StatusCode: http.StatusOK,
+			Outcome:    OutcomeSuccess,
+		},
+		{
+			Verb:       "patch",
+			Resource:   &Resource{Group: "apps", Version: "v1", Resource: "deployments", Namespace: "kube-system", Name: "coredns"},
+			DryRun:     true,
+			StatusCode: http.StatusOK,
+			Outcome:    OutcomeSuccess,
+		},
+		{
+			Verb:       "delete",
+			Resource:   &Resource{Group: "cluster.k8s.io", Version: "v1alpha1", Resource: "machinedeployments", Namespace: "kube-system", Name: "workers"},
+			StatusCode: http.StatusNotFound,
+			Outcome:    OutcomeFailure,
+		},
+	}
+
+	for i, w := range want {
+		got := entries[i+1]
+		if got.Type != TypeAPIMutation || got.Verb != w.Verb || got.DryRun != w.DryRun || got.StatusCode != w.StatusCode || got.Outcome != w.Outcome {
+			t.Errorf("entry %d: got %+v, want %+v", i, got, w)
+		}
+		if got.Resource == nil || *got.Resource != *w.Resource {
+			t.Errorf("entry %d: Resource = %+v, want %+v", i, got.Resource, w.Resource)
+		}
+	}
+}
+
+func TestNilLog(t *testing.T) {
+	var l *Log
+
+	conn := &fakeConnection{}
+	if l.WrapConnection(kubeoneapi.HostConfig{}, conn) != conn {
+		t.Error("nil log wrapped the connection")
+	}
+	if err := l.Record(Entry{}); err != nil {
+		t.Errorf("nil log failed to record: %v", err)
+	}
+}
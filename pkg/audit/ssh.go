@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"io"
+	"net"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/ssh"
+)
+
+var (
+	_ ssh.Tunneler = &connection{}
+)
+
+// connection records all commands run over the SSH connection
+type connection struct {
+	ssh.Connection
+	log  *Log
+	host string
+}
+
+// WrapConnection returns the connection recording all commands run on the
+// host into the audit log. It's used with ssh.Connector.WrapConnections.
+func (l *Log) WrapConnection(host kubeoneapi.HostConfig, conn ssh.Connection) ssh.Connection {
+	if l == nil {
+		return conn
+	}
+
+	return &connection{
+		Connection: conn,
+		log:        l,
+		host:       host.PublicAddress,
+	}
+}
+
+func (c *connection) POpen(cmd string, stdin io.Reader, stdout io.Writer, stderr io.Writer) (int, error) {
+	entry := Entry{
+		Type:    TypeSSHCommand,
+		Host:    c.host,
+		Command: cmd,
+	}
+
+	var counter *countingReader
+	if stdin != nil {
+		entry.Type = TypeFileUpload
+		counter = &countingReader{Reader: stdin}
+		stdin = counter
+	}
+
+	start := c.log.now()
+	exitCode, err := c.Connection.POpen(cmd, stdin, stdout, stderr)
+
+	entry.ExitCode = &exitCode
+	if counter != nil {
+		entry.Bytes = counter.n
+	}
+	c.log.record(entry, start, err)
+
+	return exitCode, err
+}
+
+func (c *connection) Exec(cmd string) (string, string, int, error) {
+	start := c.log.now()
+	stdout, stderr, exitCode, err := c.Connection.Exec(cmd)
+
+	c.log.record(Entry{
+		Type:     TypeSSHCommand,
+		Host:     c.host,
+		Command:  cmd,
+		ExitCode: &exitCode,
+	}, start, err)
+
+	return stdout, stderr, exitCode, err
+}
+
+// TunnelTo is not recorded, the Kubernetes API requests are recorded by the
+// client instead
+func (c *connection) TunnelTo(ctx context.Context, network, addr string) (net.Conn, error) {
+	tunn, ok := c.Connection.(ssh.Tunneler)
+	if !ok {
+		return nil, errors.New("unable to assert Tunneler")
+	}
+
+	return tunn.TunnelTo(ctx, network, addr)
+}
+
+type countingReader struct {
+	io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.n += int64(n)
+
+	return n, err
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+var requestInfoFactory = &request.RequestInfoFactory{
+	APIPrefixes:          sets.NewString("api", "apis"),
+	GrouplessAPIPrefixes: sets.NewString("api"),
+}
+
+// transport records all Kubernetes API requests changing objects
+type transport struct {
+	rt  http.RoundTripper
+	log *Log
+}
+
+// WrapTransport returns the round tripper recording all Kubernetes API
+// requests changing objects into the audit log. It's used as
+// rest.Config.WrapTransport, so the requests made by all clients, including
+// Helm, are recorded.
+func (l *Log) WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	if l == nil {
+		return rt
+	}
+
+	return &transport{rt: rt, log: l}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return t.rt.RoundTrip(req)
+	}
+
+	entry := requestEntry(req)
+
+	start := t.log.now()
+	resp, err := t.rt.RoundTrip(req)
+
+	// Failed responses are returned to the client as they are
+	recordErr := err
+	if err == nil {
+		entry.StatusCode = resp.StatusCode
+		if resp.StatusCode >= http.StatusBadRequest {
+			recordErr = errors.New(http.StatusText(resp.StatusCode))
+		}
+	}
+	t.log.record(entry, start, recordErr)
+
+	return resp, err
+}
+
+// requestEntry returns the audit log entry for the request
+func requestEntry(req *http.Request) Entry {
+	entry := Entry{
+		Type:   TypeAPIMutation,
+		Verb:   req.Method,
+		Path:   req.URL.Path,
+		DryRun: req.URL.Query().Get("dryRun") != "",
+	}
+
+	info, err := requestInfoFactory.NewRequestInfo(req)
+	if err != nil || !info.IsResourceRequest {
+		return entry
+	}
+
+	entry.Verb = info.Verb
+	entry.Resource = &Resource{
+		Group:       info.APIGroup,
+		Version:     info.APIVersion,
+		Resource:    info.Resource,
+		Subresource: info.Subresource,
+		Namespace:   info.Namespace,
+		Name:        info.Name,
+	}
+
+	// Name of the created object is in the request body
+	if entry.Resource.Name == "" && info.Verb == "create" {
+		entry.Resource.Name = requestObjectName(req)
+	}
+
+	return entry
+}
+
+// requestObjectName returns the name of the object sent in the JSON
+// request body, or an empty string if it can't be read
+func requestObjectName(req *http.Request) string {
+	if req.GetBody == nil {
+		return ""
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+
+	buf, err := ioutil.ReadAll(body)
+	if err != nil {
+		return ""
+	}
+
+	obj := struct {
+		Metadata struct {
+			Name         string `json:"name"`
+			GenerateName string `json:"generateName"`
+		} `json:"metadata"`
+	}{}
+	if err := json.Unmarshal(buf, &obj); err != nil {
+		return ""
+	}
+
+	if obj.Metadata.Name == "" {
+		return obj.Metadata.GenerateName
+	}
+
+	return obj.Metadata.Name
+}
@@ -0,0 +1,409 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package capabilities computes the capability set of the cluster from its
+// KubeOneCluster manifest: effective versions, enabled features, images and
+// API endpoints. It's meant to be used by the tooling built on top of KubeOne,
+// so the tooling doesn't need to re-implement KubeOne's defaulting logic.
+//
+// Fields of the Capabilities structure are only added within the same
+// SchemaVersion, they are never renamed or removed.
+package capabilities
+
+import (
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	embeddedaddons "k8c.io/kubeone/addons"
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/apis/kubeone/config"
+	"k8c.io/kubeone/pkg/templates/images"
+	"k8c.io/kubeone/pkg/templates/kubeadm"
+	"k8c.io/kubeone/pkg/templates/resources"
+)
+
+const (
+	// SchemaVersion is the version of the Capabilities structure
+	SchemaVersion = "v1"
+
+	// defaultKubernetesImageRepository is used by kubeadm if the image
+	// repository is not configured
+	defaultKubernetesImageRepository = "k8s.gcr.io"
+
+	// apiServerBindPort is the port kube-apiserver listens on on each control
+	// plane host
+	apiServerBindPort = 6443
+)
+
+// internalImageRe matches the images referenced by the embedded addons
+var internalImageRe = regexp.MustCompile(`\.InternalImages\.Get "(\w+)"`)
+
+// Capabilities is the capability set of the cluster
+type Capabilities struct {
+	SchemaVersion string        `json:"schemaVersion"`
+	Name          string        `json:"name"`
+	Versions      Versions      `json:"versions"`
+	CloudProvider CloudProvider `json:"cloudProvider"`
+	// CNI is the name of the CNI plugin: canal, cilium, weavenet or external
+	CNI string `json:"cni"`
+	// Features are the names of the enabled features, as in .features
+	Features []string `json:"features"`
+	// Addons are the names of the embedded addons deployed to the cluster
+	Addons []string `json:"addons"`
+	// Images are the images of the Kubernetes components and the embedded
+	// addons. The etcd, CoreDNS and pause images are chosen by kubeadm.
+	Images       []string     `json:"images"`
+	APIEndpoints APIEndpoints `json:"apiEndpoints"`
+}
+
+// Versions are the effective versions of the cluster components
+type Versions struct {
+	Kubernetes       string `json:"kubernetes"`
+	KubeadmAPI       string `json:"kubeadmAPI"`
+	ContainerRuntime string `json:"containerRuntime"`
+}
+
+// CloudProvider is the cloud provider integration of the cluster
+type CloudProvider struct {
+	Name         string `json:"name"`
+	External     bool   `json:"external"`
+	CSIMigration bool   `json:"csiMigration"`
+}
+
+// APIEndpoints are the endpoints of the Kubernetes API
+type APIEndpoints struct {
+	// Kubernetes is the load balanced endpoint of the Kubernetes API
+	Kubernetes       string   `json:"kubernetes"`
+	AlternativeNames []string `json:"alternativeNames,omitempty"`
+	// ControlPlane are the endpoints of kube-apiserver on each control plane host
+	ControlPlane []string `json:"controlPlane"`
+}
+
+// FromManifest loads, defaults and validates the KubeOneCluster manifest the
+// same way as other KubeOne commands, and returns its capability set.
+// Terraform output and credentials file are optional.
+func FromManifest(manifest, tfOutput, credentialsFile []byte, logger logrus.FieldLogger) (*Capabilities, error) {
+	cluster, err := config.BytesToKubeOneCluster(manifest, tfOutput, credentialsFile, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to load the KubeOneCluster manifest")
+	}
+
+	return Compute(cluster)
+}
+
+// Compute returns the capability set of the defaulted KubeOneCluster
+func Compute(cluster *kubeoneapi.KubeOneCluster) (*Capabilities, error) {
+	kubeadmAPI, err := kubeadm.APIVersion(cluster.Versions.Kubernetes)
+	if err != nil {
+		return nil, err
+	}
+
+	addons := clusterAddons(cluster)
+
+	imgs, err := clusterImages(cluster, addons)
+	if err != nil {
+		return nil, err
+	}
+
+	caps := &Capabilities{
+		SchemaVersion: SchemaVersion,
+		Name:          cluster.Name,
+		Versions: Versions{
+			Kubernetes:       cluster.Versions.Kubernetes,
+			KubeadmAPI:       kubeadmAPI,
+			ContainerRuntime: cluster.ContainerRuntime.String(),
+		},
+		CloudProvider: CloudProvider{
+			Name:         cluster.CloudProvider.CloudProviderName(),
+			External:     cluster.CloudProvider.External,
+			CSIMigration: cluster.CloudProvider.CSIMigrationSupported(),
+		},
+		CNI:      cniName(cluster.ClusterNetwork.CNI),
+		Features: enabledFeatures(cluster.Features),
+		Addons:   addons,
+		Images:   imgs,
+		APIEndpoints: APIEndpoints{
+			Kubernetes:       fmt.Sprintf("https://%s:%d", cluster.APIEndpoint.Host, cluster.APIEndpoint.Port),
+			AlternativeNames: cluster.APIEndpoint.AlternativeNames,
+			ControlPlane:     []string{},
+		},
+	}
+
+	for _, host := range cluster.ControlPlane.Hosts {
+		addr := host.PrivateAddress
+		if addr == "" {
+			addr = host.PublicAddress
+		}
+		caps.APIEndpoints.ControlPlane = append(caps.APIEndpoints.ControlPlane, fmt.Sprintf("https://%s:%d", addr, apiServerBindPort))
+	}
+
+	return caps, nil
+}
+
+func cniName(cni *kubeoneapi.CNI) string {
+	switch {
+	case cni == nil:
+		return ""
+	case cni.Canal != nil:
+		return "canal"
+	case cni.Cilium != nil:
+		return "cilium"
+	case cni.WeaveNet != nil:
+		return "weavenet"
+	case cni.External != nil:
+		return "external"
+	}
+
+	return ""
+}
+
+func enabledFeatures(f kubeoneapi.Features) []string {
+	enabled := []string{}
+
+	add := func(name string, enable bool) {
+		if enable {
+			enabled = append(enabled, name)
+		}
+	}
+
+	add("podNodeSelector", f.PodNodeSelector != nil && f.PodNodeSelector.Enable)
+	add("podPresets", f.PodPresets != nil && f.PodPresets.Enable)
+	add("podSecurityPolicy", f.PodSecurityPolicy != nil && f.PodSecurityPolicy.Enable)
+	add("staticAuditLog", f.StaticAuditLog != nil && f.StaticAuditLog.Enable)
+	add("dynamicAuditLog", f.DynamicAuditLog != nil && f.DynamicAuditLog.Enable)
+	add("metricsServer", f.MetricsServer != nil && f.MetricsServer.Enable)
+	add("openidConnect", f.OpenIDConnect != nil && f.OpenIDConnect.Enable)
+	add("encryptionProviders", f.EncryptionProviders != nil && f.EncryptionProviders.Enable)
+	add("monitoring", f.Monitoring != nil && f.Monitoring.Enable)
+	add("clusterAutoscaler", f.ClusterAutoscaler != nil && f.ClusterAutoscaler.Enable)
+
+	return enabled
+}
+
+// clusterAddons returns the embedded addons deployed to the cluster, the
+// same way as they are deployed by the apply and install commands
+func clusterAddons(cluster *kubeoneapi.KubeOneCluster) []string {
+	addons := []string{resources.AddonNodeLocalDNS}
+
+	switch cniName(cluster.ClusterNetwork.CNI) {
+	case "canal":
+		addons = append(addons, resources.AddonCNICanal)
+	case "cilium":
+		addons = append(addons, resources.AddonCNICilium)
+	case "weavenet":
+		addons = append(addons, resources.AddonCNIWeavenet)
+	}
+
+	if cluster.MachineController != nil && cluster.MachineController.Deploy {
+		addons = append(addons, resources.AddonMachineController)
+	}
+
+	if cluster.Features.MetricsServer != nil && cluster.Features.MetricsServer.Enable {
+		addons = append(addons, resources.AddonMetricsServer)
+	}
+
+	if cluster.Features.Monitoring != nil && cluster.Features.Monitoring.Enable {
+		addons = append(addons, resources.AddonMonitoring)
+	}
+
+	if cluster.Features.ClusterAutoscaler != nil && cluster.Features.ClusterAutoscaler.Enable {
+		addons = append(addons, resources.AddonClusterAutoscaler)
+	}
+
+	if cluster.Features.IngressController != nil && cluster.Features.IngressController.Enable {
+		addons = append(addons, resources.AddonIngressNginx)
+	}
+
+	if cluster.Features.GitOps.Enabled() {
+		addons = append(addons, resources.AddonFlux, resources.AddonFluxSync)
+	}
+
+	if cluster.Features.Velero.Enabled() {
+		addons = append(addons, resources.AddonVelero, resources.AddonVeleroBackups)
+	}
+
+	if cluster.Features.CertManager.Enabled() {
+		addons = append(addons, resources.AddonCertManager)
+		if cluster.Features.CertManager.ClusterIssuerEnabled() {
+			addons = append(addons, resources.AddonCertManagerIssuer)
+		}
+	}
+
+	if cp := cluster.CloudProvider; cp.External {
+		switch {
+		case cp.AWS != nil:
+			addons = append(addons, resources.AddonCCMAws)
+			if cp.AWS.EBSCSIEnabled() {
+				addons = append(addons, resources.AddonCSIAwsEBS)
+			}
+		case cp.Azure != nil:
+			if cp.Azure.CCM.Enabled() {
+				addons = append(addons, resources.AddonCCMAzure)
+			}
+			if cp.Azure.DiskCSI.Enabled() {
+				addons = append(addons, resources.AddonCSIAzureDisk)
+			}
+			if cp.Azure.FileCSI.Enabled() {
+				addons = append(addons, resources.AddonCSIAzureFile)
+			}
+		case cp.DigitalOcean != nil:
+			addons = append(addons, resources.AddonCCMDigitalOcean, resources.AddonCSIDigitalOcean)
+		case cp.Hetzner != nil:
+			addons = append(addons, resources.AddonCCMHetzner, resources.AddonCSIHetnzer)
+		case cp.Openstack != nil:
+			if cp.Openstack.CCM.Enabled() {
+				addons = append(addons, resources.AddonCCMOpenStack)
+			}
+			if cp.Openstack.CinderCSI.Enabled() {
+				addons = append(addons, resources.AddonCSIOpenStackCinder)
+			}
+		case cp.Packet != nil:
+			addons = append(addons, resources.AddonCCMPacket)
+		case cp.Vsphere != nil:
+			addons = append(addons, resources.AddonCCMVsphere)
+			if cp.CSIConfig != "" || cp.Vsphere.CSI != nil {
+				addons = append(addons, resources.AddonCSIVsphere)
+			}
+		}
+	}
+
+	// Embedded addons enabled by the user
+	if cluster.Addons != nil && cluster.Addons.Enable {
+		for _, addon := range cluster.Addons.Addons {
+			if addon.Delete {
+				continue
+			}
+			if _, err := fs.Stat(embeddedaddons.F, addon.Name); err == nil {
+				addons = append(addons, addon.Name)
+			}
+		}
+	}
+
+	sort.Strings(addons)
+
+	return addons
+}
+
+// clusterImages returns the images of the Kubernetes components and the
+// images referenced by the addons
+func clusterImages(cluster *kubeoneapi.KubeOneCluster, addons []string) ([]string, error) {
+	resolver := images.NewResolver(
+		images.WithOverwriteRegistryGetter(func() string {
+			if cluster.RegistryConfiguration == nil {
+				return ""
+			}
+
+			return cluster.RegistryConfiguration.OverwriteRegistry
+		}),
+		images.WithOverwriteImageGetter(func(res images.Resource) (string, string) {
+			if cluster.MachineController == nil || res != images.MachineController {
+				return "", ""
+			}
+
+			return cluster.MachineController.ImageRepository, cluster.MachineController.ImageTag
+		}),
+		images.WithKubernetesVersionGetter(func() string {
+			return cluster.Versions.Kubernetes
+		}),
+	)
+
+	repo := cluster.AssetConfiguration.Kubernetes.ImageRepository
+	if repo == "" {
+		repo = defaultKubernetesImageRepository
+	}
+
+	found := map[string]struct{}{}
+	for _, component := range []string{"kube-apiserver", "kube-controller-manager", "kube-scheduler", "kube-proxy"} {
+		found[fmt.Sprintf("%s/%s:v%s", repo, component, cluster.Versions.Kubernetes)] = struct{}{}
+	}
+
+	hubble := cluster.ClusterNetwork.CNI != nil && cluster.ClusterNetwork.CNI.Cilium != nil && cluster.ClusterNetwork.CNI.Cilium.EnableHubble
+
+	for _, addon := range addons {
+		names, err := addonImageNames(addon)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range names {
+			// The pause image is chosen by kubeadm, same as for the static pods
+			if name == "PauseImage" {
+				continue
+			}
+
+			res, err := images.FindResource(name)
+			if err != nil {
+				return nil, errors.Wrapf(err, "addon %q", addon)
+			}
+
+			// Hubble is deployed along with Cilium only if enabled
+			if !hubble && isHubbleResource(res) {
+				continue
+			}
+
+			if img := resolver.Get(res); img != "" {
+				found[img] = struct{}{}
+			}
+		}
+	}
+
+	imgs := []string{}
+	for img := range found {
+		imgs = append(imgs, img)
+	}
+	sort.Strings(imgs)
+
+	return imgs, nil
+}
+
+// addonImageNames returns the names of the image resources referenced by the
+// embedded addon
+func addonImageNames(addon string) ([]string, error) {
+	names := []string{}
+
+	err := fs.WalkDir(embeddedaddons.F, addon, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		buf, err := fs.ReadFile(embeddedaddons.F, path)
+		if err != nil {
+			return err
+		}
+
+		for _, match := range internalImageRe.FindAllStringSubmatch(string(buf), -1) {
+			names = append(names, match[1])
+		}
+
+		return nil
+	})
+
+	return names, errors.Wrapf(err, "failed to read embedded addon %q", addon)
+}
+
+func isHubbleResource(res images.Resource) bool {
+	switch res {
+	case images.HubbleRelay, images.HubbleUI, images.HubbleUIBackend, images.HubbleProxy:
+		return true
+	}
+
+	return false
+}
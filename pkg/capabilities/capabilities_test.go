@@ -0,0 +1,142 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capabilities
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+const testManifest = `
+apiVersion: kubeone.io/v1beta1
+kind: KubeOneCluster
+name: test
+versions:
+  kubernetes: %s
+cloudProvider:
+  %s
+controlPlane:
+  hosts:
+  - publicAddress: 192.168.1.1
+    privateAddress: 10.0.0.1
+apiEndpoint:
+  host: lb.example.com
+  port: 6443
+`
+
+func TestFromManifest(t *testing.T) {
+	tests := []struct {
+		name            string
+		version         string
+		cloudProvider   string
+		wantKubeadmAPI  string
+		wantCNI         string
+		wantAddons      []string
+		wantImage       string
+		wantNoImage     string
+		wantAPIEndpoint string
+	}{
+		{
+			name:            "aws 1.21",
+			version:         "1.21.9",
+			cloudProvider:   "aws: {}",
+			wantKubeadmAPI:  "kubeadm.k8s.io/v1beta2",
+			wantCNI:         "canal",
+			wantAddons:      []string{"cni-canal", "machinecontroller"},
+			wantImage:       "k8s.gcr.io/kube-apiserver:v1.21.9",
+			wantNoImage:     "hubble",
+			wantAPIEndpoint: "https://lb.example.com:6443",
+		},
+		{
+			name:            "none 1.22",
+			version:         "1.22.6",
+			cloudProvider:   "none: {}",
+			wantKubeadmAPI:  "kubeadm.k8s.io/v1beta3",
+			wantCNI:         "canal",
+			wantAddons:      []string{"cni-canal"},
+			wantImage:       "k8s.gcr.io/kube-proxy:v1.22.6",
+			wantNoImage:     "cloud-controller-manager",
+			wantAPIEndpoint: "https://lb.example.com:6443",
+		},
+		{
+			name:    "openstack 1.21 without cinder csi",
+			version: "1.21.9",
+			cloudProvider: `openstack:
+    cinderCSI:
+      enable: false
+  external: true
+  cloudConfig: "[Global]"`,
+			wantKubeadmAPI:  "kubeadm.k8s.io/v1beta2",
+			wantCNI:         "canal",
+			wantAddons:      []string{"ccm-openstack", "cni-canal"},
+			wantImage:       "docker.io/k8scloudprovider/openstack-cloud-controller-manager:v1.21.0",
+			wantNoImage:     "cinder-csi-plugin",
+			wantAPIEndpoint: "https://lb.example.com:6443",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			manifest := []byte(fmt.Sprintf(testManifest, tt.version, tt.cloudProvider))
+
+			caps, err := FromManifest(manifest, nil, nil, logrus.New())
+			if err != nil {
+				t.Fatalf("FromManifest() error = %v", err)
+			}
+
+			if caps.SchemaVersion != SchemaVersion {
+				t.Errorf("SchemaVersion = %q, want %q", caps.SchemaVersion, SchemaVersion)
+			}
+			if caps.Versions.KubeadmAPI != tt.wantKubeadmAPI {
+				t.Errorf("Versions.KubeadmAPI = %q, want %q", caps.Versions.KubeadmAPI, tt.wantKubeadmAPI)
+			}
+			if caps.CNI != tt.wantCNI {
+				t.Errorf("CNI = %q, want %q", caps.CNI, tt.wantCNI)
+			}
+			for _, addon := range tt.wantAddons {
+				if !contains(caps.Addons, addon) {
+					t.Errorf("Addons = %v, want %q included", caps.Addons, addon)
+				}
+			}
+			if !contains(caps.Images, tt.wantImage) {
+				t.Errorf("Images = %v, want %q included", caps.Images, tt.wantImage)
+			}
+			for _, img := range caps.Images {
+				if strings.Contains(img, tt.wantNoImage) {
+					t.Errorf("Images contain %q, want no %q images", img, tt.wantNoImage)
+				}
+			}
+			if caps.APIEndpoints.Kubernetes != tt.wantAPIEndpoint {
+				t.Errorf("APIEndpoints.Kubernetes = %q, want %q", caps.APIEndpoints.Kubernetes, tt.wantAPIEndpoint)
+			}
+		})
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+
+	return false
+}
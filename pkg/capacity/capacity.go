@@ -0,0 +1,150 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacity
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// PodsPerNode is the average number of pods per node assumed when
+	// sizing the control plane for the expected number of pods. It follows
+	// the Kubernetes large cluster limits (150000 pods in 5000 nodes).
+	PodsPerNode = 30
+)
+
+// Requirements are the minimum resources of each control plane host
+type Requirements struct {
+	// Nodes is the cluster size the requirements are computed for
+	Nodes     int
+	CPUs      int
+	MemoryMiB int
+	// EtcdDiskGiB is the size of the filesystem storing the etcd data
+	EtcdDiskGiB int
+	// EtcdDiskSyncIOPS is the number of 4KiB synchronous writes per
+	// second to the etcd data filesystem. It's much lower than the random
+	// write IOPS advertised for the disks, because every write waits for
+	// the data to be flushed, the same as the etcd write-ahead log.
+	EtcdDiskSyncIOPS int
+}
+
+// tiers are the requirements for clusters up to the given number of nodes,
+// based on the Kubernetes control plane sizing and the etcd hardware
+// recommendations
+var tiers = []Requirements{
+	{Nodes: 10, CPUs: 2, MemoryMiB: 4 * 1024, EtcdDiskGiB: 20, EtcdDiskSyncIOPS: 50},
+	{Nodes: 100, CPUs: 4, MemoryMiB: 16 * 1024, EtcdDiskGiB: 50, EtcdDiskSyncIOPS: 100},
+	{Nodes: 250, CPUs: 8, MemoryMiB: 32 * 1024, EtcdDiskGiB: 80, EtcdDiskSyncIOPS: 200},
+	{Nodes: 500, CPUs: 16, MemoryMiB: 64 * 1024, EtcdDiskGiB: 100, EtcdDiskSyncIOPS: 300},
+	{Nodes: 1000, CPUs: 32, MemoryMiB: 128 * 1024, EtcdDiskGiB: 150, EtcdDiskSyncIOPS: 500},
+	{Nodes: 5000, CPUs: 64, MemoryMiB: 256 * 1024, EtcdDiskGiB: 200, EtcdDiskSyncIOPS: 800},
+}
+
+// ForClusterSize returns the requirements of the control plane hosts of the
+// cluster with the given number of nodes and pods. Clusters larger than the
+// largest known size get the largest requirements.
+func ForClusterSize(nodes, pods int) Requirements {
+	size := nodes
+	if podNodes := (pods + PodsPerNode - 1) / PodsPerNode; podNodes > size {
+		size = podNodes
+	}
+
+	for _, tier := range tiers {
+		if size <= tier.Nodes {
+			return tier
+		}
+	}
+
+	return tiers[len(tiers)-1]
+}
+
+// Resources are the resources of the control plane host
+type Resources struct {
+	CPUs             int
+	MemoryMiB        int
+	EtcdDiskGiB      int
+	EtcdDiskSyncIOPS int
+}
+
+// ParseResources parses the output of scripts.HostResources
+func ParseResources(output string) (Resources, error) {
+	values := map[string]int64{}
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			return Resources{}, errors.Errorf("invalid line %q", line)
+		}
+
+		value, err := strconv.ParseInt(kv[1], 10, 64)
+		if err != nil {
+			return Resources{}, errors.Wrapf(err, "invalid value of %s", kv[0])
+		}
+		values[kv[0]] = value
+	}
+
+	for _, key := range []string{"cpus", "memory_kib", "disk_kib", "sync_writes", "sync_writes_ns"} {
+		if _, ok := values[key]; !ok {
+			return Resources{}, errors.Errorf("%s not found", key)
+		}
+	}
+
+	res := Resources{
+		CPUs:        int(values["cpus"]),
+		MemoryMiB:   int(values["memory_kib"] / 1024),
+		EtcdDiskGiB: int(values["disk_kib"] / 1024 / 1024),
+	}
+	if elapsed := time.Duration(values["sync_writes_ns"]); elapsed > 0 {
+		res.EtcdDiskSyncIOPS = int(float64(values["sync_writes"]) / elapsed.Seconds())
+	}
+
+	return res, nil
+}
+
+// Check returns the descriptions of all requirements the host doesn't meet
+func Check(req Requirements, res Resources) []string {
+	unmet := []string{}
+
+	if res.CPUs < req.CPUs {
+		unmet = append(unmet, fmt.Sprintf("%d CPUs, at least %d required", res.CPUs, req.CPUs))
+	}
+	// The kernel reserves part of the memory, so MemTotal is always a bit
+	// lower than the memory of the host
+	if float64(res.MemoryMiB) < 0.9*float64(req.MemoryMiB) {
+		unmet = append(unmet, fmt.Sprintf("%d MiB memory, at least %d MiB required", res.MemoryMiB, req.MemoryMiB))
+	}
+	if res.EtcdDiskGiB < req.EtcdDiskGiB {
+		unmet = append(unmet, fmt.Sprintf("%d GiB etcd disk, at least %d GiB required", res.EtcdDiskGiB, req.EtcdDiskGiB))
+	}
+	if res.EtcdDiskSyncIOPS < req.EtcdDiskSyncIOPS {
+		unmet = append(unmet, fmt.Sprintf("%d etcd disk synchronous writes per second, at least %d required", res.EtcdDiskSyncIOPS, req.EtcdDiskSyncIOPS))
+	}
+
+	return unmet
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacity
+
+import (
+	"testing"
+)
+
+func TestForClusterSize(t *testing.T) {
+	tests := []struct {
+		name          string
+		nodes         int
+		pods          int
+		expectedNodes int
+	}{
+		{
+			name:          "small cluster",
+			nodes:         3,
+			expectedNodes: 10,
+		},
+		{
+			name:          "tier boundary",
+			nodes:         100,
+			expectedNodes: 100,
+		},
+		{
+			name:          "pods scale the cluster size",
+			nodes:         50,
+			pods:          6000,
+			expectedNodes: 250,
+		},
+		{
+			name:          "only pods",
+			pods:          301,
+			expectedNodes: 100,
+		},
+		{
+			name:          "larger than the largest tier",
+			nodes:         10000,
+			expectedNodes: 5000,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req := ForClusterSize(tt.nodes, tt.pods)
+			if req.Nodes != tt.expectedNodes {
+				t.Errorf("expected requirements for %d nodes, got %d", tt.expectedNodes, req.Nodes)
+			}
+		})
+	}
+}
+
+func TestParseResources(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected Resources
+		err      bool
+	}{
+		{
+			name: "valid output",
+			output: "cpus=4\nmemory_kib=16303104\ndisk_kib=104857600\n" +
+				"sync_writes=200\nsync_writes_ns=500000000\n",
+			expected: Resources{CPUs: 4, MemoryMiB: 15921, EtcdDiskGiB: 100, EtcdDiskSyncIOPS: 400},
+		},
+		{
+			name:   "missing value",
+			output: "cpus=4\nmemory_kib=16303104\n",
+			err:    true,
+		},
+		{
+			name:   "invalid value",
+			output: "cpus=four\nmemory_kib=16303104\ndisk_kib=1\nsync_writes=200\nsync_writes_ns=1\n",
+			err:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := ParseResources(tt.output)
+			if (err != nil) != tt.err {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if res != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, res)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	req := ForClusterSize(100, 0)
+
+	tests := []struct {
+		name          string
+		resources     Resources
+		expectedUnmet int
+	}{
+		{
+			name:      "sufficient host",
+			resources: Resources{CPUs: 4, MemoryMiB: 15921, EtcdDiskGiB: 50, EtcdDiskSyncIOPS: 400},
+		},
+		{
+			name:          "undersized host",
+			resources:     Resources{CPUs: 2, MemoryMiB: 7800, EtcdDiskGiB: 20, EtcdDiskSyncIOPS: 400},
+			expectedUnmet: 3,
+		},
+		{
+			name:          "slow disk",
+			resources:     Resources{CPUs: 8, MemoryMiB: 32000, EtcdDiskGiB: 100, EtcdDiskSyncIOPS: 40},
+			expectedUnmet: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			unmet := Check(req, tt.resources)
+			if len(unmet) != tt.expectedUnmet {
+				t.Errorf("expected %d unmet requirements, got %v", tt.expectedUnmet, unmet)
+			}
+		})
+	}
+}
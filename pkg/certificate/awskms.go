@@ -0,0 +1,147 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+)
+
+type awsKMSSigner struct {
+	ctx    context.Context
+	client kmsiface.KMSAPI
+	keyID  string
+	pub    crypto.PublicKey
+}
+
+func newAWSKMSSigner(ctx context.Context, config kubeoneapi.AWSKMSKeyProvider, caPub crypto.PublicKey) (*awsKMSSigner, error) {
+	sess, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create aws session")
+	}
+
+	awsConfig := aws.NewConfig()
+	if config.Region != "" {
+		awsConfig = awsConfig.WithRegion(config.Region)
+	}
+
+	return newAWSKMSSignerWithClient(ctx, kms.New(sess, awsConfig), config.KeyID, caPub)
+}
+
+// newAWSKMSSignerWithClient returns the signer using the given KMS key, after
+// ensuring the key matches the public key of the CA
+func newAWSKMSSignerWithClient(ctx context.Context, client kmsiface.KMSAPI, keyID string, caPub crypto.PublicKey) (*awsKMSSigner, error) {
+	out, err := client.GetPublicKeyWithContext(ctx, &kms.GetPublicKeyInput{
+		KeyId: aws.String(keyID),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the public key of the AWS KMS key %s", keyID)
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(out.PublicKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the public key of the AWS KMS key %s", keyID)
+	}
+
+	caKey, ok := caPub.(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !caKey.Equal(pub) {
+		return nil, errors.Errorf("AWS KMS key %s doesn't match the CA certificate", keyID)
+	}
+
+	return &awsKMSSigner{
+		ctx:    ctx,
+		client: client,
+		keyID:  keyID,
+		pub:    pub,
+	}, nil
+}
+
+func (ks *awsKMSSigner) Public() crypto.PublicKey {
+	return ks.pub
+}
+
+func (ks *awsKMSSigner) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	algorithm, err := awsKMSSigningAlgorithm(ks.pub, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := ks.client.SignWithContext(ks.ctx, &kms.SignInput{
+		KeyId:            aws.String(ks.keyID),
+		Message:          digest,
+		MessageType:      aws.String(kms.MessageTypeDigest),
+		SigningAlgorithm: aws.String(algorithm),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to sign with the AWS KMS key %s", ks.keyID)
+	}
+
+	// AWS KMS returns the ECDSA signatures ASN.1 encoded, as required by crypto.Signer
+	return out.Signature, nil
+}
+
+// awsKMSSigningAlgorithm returns the AWS KMS signing algorithm for the key
+// type and the signer options
+func awsKMSSigningAlgorithm(pub crypto.PublicKey, opts crypto.SignerOpts) (string, error) {
+	var algorithms map[crypto.Hash]string
+
+	switch pub.(type) {
+	case *rsa.PublicKey:
+		if _, ok := opts.(*rsa.PSSOptions); ok {
+			algorithms = map[crypto.Hash]string{
+				crypto.SHA256: kms.SigningAlgorithmSpecRsassaPssSha256,
+				crypto.SHA384: kms.SigningAlgorithmSpecRsassaPssSha384,
+				crypto.SHA512: kms.SigningAlgorithmSpecRsassaPssSha512,
+			}
+		} else {
+			algorithms = map[crypto.Hash]string{
+				crypto.SHA256: kms.SigningAlgorithmSpecRsassaPkcs1V15Sha256,
+				crypto.SHA384: kms.SigningAlgorithmSpecRsassaPkcs1V15Sha384,
+				crypto.SHA512: kms.SigningAlgorithmSpecRsassaPkcs1V15Sha512,
+			}
+		}
+	case *ecdsa.PublicKey:
+		algorithms = map[crypto.Hash]string{
+			crypto.SHA256: kms.SigningAlgorithmSpecEcdsaSha256,
+			crypto.SHA384: kms.SigningAlgorithmSpecEcdsaSha384,
+			crypto.SHA512: kms.SigningAlgorithmSpecEcdsaSha512,
+		}
+	default:
+		return "", errors.Errorf("unsupported AWS KMS key type %T", pub)
+	}
+
+	algorithm, ok := algorithms[opts.HashFunc()]
+	if !ok {
+		return "", errors.Errorf("unsupported hash function %v", opts.HashFunc())
+	}
+
+	return algorithm, nil
+}
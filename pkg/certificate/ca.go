@@ -21,12 +21,18 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"io/ioutil"
 	"path"
+	"path/filepath"
+
+	"github.com/pkg/errors"
 
 	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
 	"k8c.io/kubeone/pkg/ssh"
 	"k8c.io/kubeone/pkg/ssh/sshiofs"
 	"k8c.io/kubeone/pkg/state"
+
+	certutil "k8s.io/client-go/util/cert"
 )
 
 const (
@@ -34,23 +40,28 @@ const (
 	KubernetesCAKeyPath  = "/etc/kubernetes/pki/ca.key"
 )
 
-func kubernetesPKIFiles() []string {
-	return []string{
-		KubernetesCACertPath,
-		KubernetesCAKeyPath,
+func kubernetesPKIFiles(cluster *kubeoneapi.KubeOneCluster) []string {
+	files := []string{KubernetesCACertPath}
+
+	// The CA key is not available if the certificates are signed by the external CA signer
+	if !cluster.ExternalCASignerEnabled() {
+		files = append(files, KubernetesCAKeyPath)
+	}
+
+	return append(files,
 		"/etc/kubernetes/pki/sa.key",
 		"/etc/kubernetes/pki/sa.pub",
 		"/etc/kubernetes/pki/front-proxy-ca.crt",
 		"/etc/kubernetes/pki/front-proxy-ca.key",
 		"/etc/kubernetes/pki/etcd/ca.crt",
 		"/etc/kubernetes/pki/etcd/ca.key",
-	}
+	)
 }
 
 func DownloadKubePKI(s *state.State, _ *kubeoneapi.HostConfig, conn ssh.Connection) error {
 	sshfs := s.Runner.NewFS()
 
-	for _, fname := range kubernetesPKIFiles() {
+	for _, fname := range kubernetesPKIFiles(s.Cluster) {
 		buf, err := fs.ReadFile(sshfs, fname)
 		if err != nil {
 			return err
@@ -61,21 +72,73 @@ func DownloadKubePKI(s *state.State, _ *kubeoneapi.HostConfig, conn ssh.Connecti
 	if s.BackupFile != "" {
 		s.Logger.Infoln("Creating local backup...")
 
-		err := s.Configuration.Backup(s.BackupFile)
+		err := s.Configuration.Backup(s.BackupFile, s.EncryptArtifact)
 		if err != nil {
 			// do not stop in case of failed backups, the user can
 			// always create the backup themselves if needed
 			s.Logger.Warnf("Failed to create backup: %v", err)
+		} else if err = persistBackup(s); err != nil {
+			s.Logger.Warnf("Failed to save backup: %v", err)
 		}
 	}
 
 	return nil
 }
 
+// persistBackup saves the local backup to the state store, if it's
+// configured
+func persistBackup(s *state.State) error {
+	if s.StateStore == nil {
+		return nil
+	}
+
+	buf, err := ioutil.ReadFile(s.BackupFile)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the backup")
+	}
+
+	return s.PersistArtifact(filepath.Base(s.BackupFile), buf)
+}
+
+// GenerateKubeCA generates a self-signed Kubernetes CA and stores it in the
+// configuration. It's used to render manifests without access to the cluster.
+func GenerateKubeCA(s *state.State) error {
+	cert, key, err := NewCA("kubernetes", s.Cluster.KubeadmKeyAlgorithm())
+	if err != nil {
+		return err
+	}
+
+	s.Configuration.KubernetesPKI[KubernetesCACertPath] = cert
+	s.Configuration.KubernetesPKI[KubernetesCAKeyPath] = key
+
+	return nil
+}
+
+// NewCA generates a self-signed CA with the given CommonName and a key of the
+// given algorithm, and returns the PEM-encoded certificate and key
+func NewCA(commonName string, algorithm kubeoneapi.KeyAlgorithm) (caCert, caKey []byte, err error) {
+	key, err := newPrivateKey(algorithm)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to generate CA private key")
+	}
+
+	cert, err := certutil.NewSelfSignedCACert(certutil.Config{CommonName: commonName}, key)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to generate CA certificate")
+	}
+
+	keyPEM, err := encodePrivateKeyPEM(key)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to encode CA private key")
+	}
+
+	return encodeCertPEM(cert), keyPEM, nil
+}
+
 func UploadKubePKI(s *state.State, _ *kubeoneapi.HostConfig, conn ssh.Connection) error {
 	sshfs := s.Runner.NewFS().(sshiofs.MkdirFS)
 
-	for _, fname := range kubernetesPKIFiles() {
+	for _, fname := range kubernetesPKIFiles(s.Cluster) {
 		buf, found := s.Configuration.KubernetesPKI[fname]
 		if !found {
 			return fmt.Errorf("file %q found found in PKI", fname)
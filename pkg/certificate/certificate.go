@@ -18,22 +18,22 @@ package certificate
 
 import (
 	"crypto"
-	"crypto/rsa"
 	"crypto/x509"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
 	"k8c.io/kubeone/pkg/configupload"
 	"k8c.io/kubeone/pkg/templates/resources"
 
 	certutil "k8s.io/client-go/util/cert"
-	"k8s.io/client-go/util/keyutil"
 )
 
 // CAKeyPair parses generated PKI CA certificate and key
-func CAKeyPair(config *configupload.Configuration) (*rsa.PrivateKey, *x509.Certificate, error) {
+func CAKeyPair(config *configupload.Configuration) (crypto.Signer, *x509.Certificate, error) {
 	caCert, found := config.KubernetesPKI[KubernetesCACertPath]
 	if !found {
 		return nil, nil, fmt.Errorf("%q not found", KubernetesCACertPath)
@@ -44,6 +44,12 @@ func CAKeyPair(config *configupload.Configuration) (*rsa.PrivateKey, *x509.Certi
 		return nil, nil, fmt.Errorf("%q not found", KubernetesCAKeyPath)
 	}
 
+	return ParseCAKeyPair(caCert, caKey)
+}
+
+// ParseCAKeyPair parses the PEM-encoded CA certificate and key. The key can be
+// an RSA, ECDSA or Ed25519 key.
+func ParseCAKeyPair(caCert, caKey []byte) (crypto.Signer, *x509.Certificate, error) {
 	certs, err := certutil.ParseCertsPEM(caCert)
 	if err != nil {
 		return nil, nil, err
@@ -53,20 +59,22 @@ func CAKeyPair(config *configupload.Configuration) (*rsa.PrivateKey, *x509.Certi
 		return nil, nil, errors.New("ca.crt does not contain at least one valid certificate")
 	}
 
-	possibleKey, err := keyutil.ParsePrivateKeyPEM(caKey)
+	key, err := parsePrivateKeyPEM(caKey)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	rsaKey, ok := possibleKey.(*rsa.PrivateKey)
-	if !ok {
-		return nil, nil, errors.New("private key is not a RSA private key")
+	pub, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !pub.Equal(certs[0].PublicKey) {
+		return nil, nil, errors.New("private key doesn't match the CA certificate")
 	}
 
-	return rsaKey, certs[0], nil
+	return key, certs[0], nil
 }
 
-func NewSignedTLSCert(name, namespace, domain string, caKey crypto.Signer, caCert *x509.Certificate) (map[string]string, error) {
+// NewSignedTLSCert generates a new private key and a serving certificate for
+// the given service, issued by the given CA and valid for the given duration
+func NewSignedTLSCert(name, namespace, domain string, validity time.Duration, issuer Issuer) (map[string]string, error) {
 	serviceCommonName := strings.Join([]string{name, namespace, "svc"}, ".")
 	serviceFQDNCommonName := strings.Join([]string{serviceCommonName, domain, ""}, ".")
 
@@ -75,31 +83,117 @@ func NewSignedTLSCert(name, namespace, domain string, caKey crypto.Signer, caCer
 		serviceCommonName,
 	}
 
-	newKPKey, err := newPrivateKey()
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to generate private key")
-	}
+	return NewSignedCert(serviceCommonName, altdnsNames, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}, validity, issuer)
+}
 
+// NewSignedCert generates a new private key and a certificate with the given
+// CommonName, DNS names and usages, issued by the given CA and valid for the
+// given duration, but not longer than the CA
+func NewSignedCert(commonName string, dnsNames []string, usages []x509.ExtKeyUsage, validity time.Duration, issuer Issuer) (map[string]string, error) {
 	certCfg := certutil.Config{
 		AltNames: certutil.AltNames{
-			DNSNames: altdnsNames,
+			DNSNames: dnsNames,
 		},
-		CommonName: serviceCommonName,
-		Usages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		CommonName: commonName,
+		Usages:     usages,
+	}
+
+	return NewCert(certCfg, capValidity(validity, issuer.CACert()), issuer)
+}
+
+// NewCert generates a new private key and a certificate described by the
+// config, issued by the given CA and valid for the given duration
+func NewCert(certCfg certutil.Config, validity time.Duration, issuer Issuer) (map[string]string, error) {
+	newKPKey, err := issuer.NewKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate private key")
 	}
 
-	newKPCert, err := newSignedCert(&certCfg, newKPKey, caCert, caKey)
+	newKPCert, err := issuer.Issue(&certCfg, newKPKey, validity)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to generate certificate")
 	}
 
+	newKPKeyPEM, err := encodePrivateKeyPEM(newKPKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode private key")
+	}
+
 	return map[string]string{
 		resources.TLSCertName:          string(encodeCertPEM(newKPCert)),
-		resources.TLSKeyName:           string(encodePrivateKeyPEM(newKPKey)),
-		resources.KubernetesCACertName: string(encodeCertPEM(caCert)),
+		resources.TLSKeyName:           string(newKPKeyPEM),
+		resources.KubernetesCACertName: string(encodeCertPEM(issuer.CACert())),
 	}, nil
 }
 
+// NewClientCert generates a new private key and a client certificate for the
+// given user and groups, issued by the given CA and valid for the given
+// duration. The certificate can't outlive the CA.
+func NewClientCert(user string, groups []string, validity time.Duration, issuer Issuer) (map[string]string, error) {
+	if validity <= 0 {
+		return nil, errors.New("certificate validity must be positive")
+	}
+	if caCert := issuer.CACert(); time.Now().Add(validity).After(caCert.NotAfter) {
+		return nil, errors.Errorf("certificate would outlive the CA, which expires at %s", caCert.NotAfter.UTC().Format(time.RFC3339))
+	}
+
+	certCfg := certutil.Config{
+		CommonName:   user,
+		Organization: groups,
+		Usages:       []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	return NewCert(certCfg, validity, issuer)
+}
+
+// NewKubeletClientCert generates the client certificate of the kubelet running
+// on the given node, issued by the given CA and valid for the given duration,
+// but not longer than the CA. The certificate is followed by the key, in the
+// same way as in the kubelet-client-current.pem file.
+func NewKubeletClientCert(nodeName string, validity time.Duration, issuer Issuer) ([]byte, error) {
+	cert, err := NewClientCert("system:node:"+nodeName, []string{"system:nodes"}, capValidity(validity, issuer.CACert()), issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(cert[resources.TLSCertName] + cert[resources.TLSKeyName]), nil
+}
+
+// NewServiceAccountKeyPair generates the PEM-encoded key pair used to sign the
+// service account tokens. Only RSA and ECDSA keys are supported by Kubernetes.
+func NewServiceAccountKeyPair(algorithm kubeoneapi.KeyAlgorithm) (key, pub []byte, err error) {
+	if algorithm == kubeoneapi.KeyAlgorithmEd25519 {
+		return nil, nil, errors.New("service account keys can't be Ed25519 keys")
+	}
+
+	saKey, err := newPrivateKey(algorithm)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to generate service account key")
+	}
+
+	pub, err = EncodePublicKeyPEM(saKey.Public())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to encode service account public key")
+	}
+
+	key, err = encodePrivateKeyPEM(saKey)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to encode service account key")
+	}
+
+	return key, pub, nil
+}
+
+// capValidity shortens the validity period, so the certificate doesn't outlive
+// the CA
+func capValidity(validity time.Duration, caCert *x509.Certificate) time.Duration {
+	if remaining := time.Until(caCert.NotAfter) - time.Minute; remaining < validity {
+		return remaining
+	}
+
+	return validity
+}
+
 // GetCertificateSANs combines host name and subject alternative names into a list of SANs after transformation
 func GetCertificateSANs(host string, alternativeNames []string) []string {
 	certSANS := []string{strings.ToLower(host)}
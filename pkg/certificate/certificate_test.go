@@ -0,0 +1,200 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"testing"
+	"time"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/templates/resources"
+
+	certutil "k8s.io/client-go/util/cert"
+)
+
+func TestKeyAlgorithms(t *testing.T) {
+	algorithms := []kubeoneapi.KeyAlgorithm{
+		kubeoneapi.KeyAlgorithmRSA,
+		kubeoneapi.KeyAlgorithmECDSA,
+		kubeoneapi.KeyAlgorithmEd25519,
+	}
+
+	for _, caAlgorithm := range algorithms {
+		for _, keyAlgorithm := range algorithms {
+			caAlgorithm := caAlgorithm
+			keyAlgorithm := keyAlgorithm
+			t.Run(fmt.Sprintf("%s-CA-%s-key", caAlgorithm, keyAlgorithm), func(t *testing.T) {
+				caCertPEM, caKeyPEM, err := NewCA("kubernetes", caAlgorithm)
+				if err != nil {
+					t.Fatal(err)
+				}
+				caKey, caCert, err := ParseCAKeyPair(caCertPEM, caKeyPEM)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				issued, err := NewSignedCert("kubernetes-admin", nil, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, time.Hour, NewKeyIssuer(caKey, caCert, keyAlgorithm))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				keyPair, err := tls.X509KeyPair([]byte(issued[resources.TLSCertName]), []byte(issued[resources.TLSKeyName]))
+				if err != nil {
+					t.Fatalf("failed to load the key pair: %v", err)
+				}
+				leaf, err := x509.ParseCertificate(keyPair.Certificate[0])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err = leaf.CheckSignatureFrom(caCert); err != nil {
+					t.Fatalf("certificate is not signed by the CA: %v", err)
+				}
+
+				var expected bool
+				switch keyPair.PrivateKey.(type) {
+				case *rsa.PrivateKey:
+					expected = keyAlgorithm == kubeoneapi.KeyAlgorithmRSA
+				case *ecdsa.PrivateKey:
+					expected = keyAlgorithm == kubeoneapi.KeyAlgorithmECDSA
+				case ed25519.PrivateKey:
+					expected = keyAlgorithm == kubeoneapi.KeyAlgorithmEd25519
+				}
+				if !expected {
+					t.Errorf("expected %s key, but got %T", keyAlgorithm, keyPair.PrivateKey)
+				}
+
+				keyEncipherment := leaf.KeyUsage&x509.KeyUsageKeyEncipherment != 0
+				if keyEncipherment != (keyAlgorithm == kubeoneapi.KeyAlgorithmRSA) {
+					t.Errorf("unexpected key encipherment usage %v for %s key", keyEncipherment, keyAlgorithm)
+				}
+			})
+		}
+	}
+}
+
+func TestParseCAKeyPairMismatch(t *testing.T) {
+	caCertPEM, _, err := NewCA("kubernetes", kubeoneapi.KeyAlgorithmECDSA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherKeyPEM, err := NewCA("other", kubeoneapi.KeyAlgorithmECDSA)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err = ParseCAKeyPair(caCertPEM, otherKeyPEM); err == nil {
+		t.Error("expected error for the key not matching the CA certificate")
+	}
+}
+
+func TestNewServiceAccountKeyPair(t *testing.T) {
+	tests := []struct {
+		name          string
+		algorithm     kubeoneapi.KeyAlgorithm
+		expectedError bool
+	}{
+		{
+			name:      "RSA",
+			algorithm: kubeoneapi.KeyAlgorithmRSA,
+		},
+		{
+			name:      "ECDSA",
+			algorithm: kubeoneapi.KeyAlgorithmECDSA,
+		},
+		{
+			name:          "Ed25519",
+			algorithm:     kubeoneapi.KeyAlgorithmEd25519,
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			key, pub, err := NewServiceAccountKeyPair(tc.algorithm)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("expected error %v, but got %v", tc.expectedError, err)
+			}
+			if tc.expectedError {
+				return
+			}
+
+			saKey, err := parsePrivateKeyPEM(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expectedPub, err := EncodePublicKeyPEM(saKey.Public())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(pub) != string(expectedPub) {
+				t.Error("public key doesn't match the private key")
+			}
+		})
+	}
+}
+
+func TestNewSignedCertValidity(t *testing.T) {
+	caCertPEM, caKeyPEM, err := NewCA("kubernetes", kubeoneapi.KeyAlgorithmRSA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caKey, caCert, err := ParseCAKeyPair(caCertPEM, caKeyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuer := NewKeyIssuer(caKey, caCert, kubeoneapi.KeyAlgorithmRSA)
+
+	tests := []struct {
+		name     string
+		validity time.Duration
+		expected time.Time
+	}{
+		{
+			name:     "shorter than the CA",
+			validity: 30 * 24 * time.Hour,
+			expected: time.Now().Add(30 * 24 * time.Hour),
+		},
+		{
+			name:     "longer than the CA",
+			validity: 20 * 365 * 24 * time.Hour,
+			expected: caCert.NotAfter.Add(-time.Minute),
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			issued, err := NewSignedCert("hubble-server", nil, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}, tc.validity, issuer)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			certs, err := certutil.ParseCertsPEM([]byte(issued[resources.TLSCertName]))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := certs[0].NotAfter.Sub(tc.expected); diff < -time.Minute || diff > time.Minute {
+				t.Errorf("expected the certificate to expire at %s, but got %s", tc.expected, certs[0].NotAfter)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/configupload"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+const (
+	// SignerUsagesEnv is the environment variable with the comma-separated
+	// usages of the certificate requested from the external CA signer
+	SignerUsagesEnv = "KUBEONE_CERTIFICATE_USAGES"
+	// SignerValidityEnv is the environment variable with the validity of the
+	// certificate requested from the external CA signer
+	SignerValidityEnv = "KUBEONE_CERTIFICATE_VALIDITY"
+)
+
+// Issuer issues certificates signed by the CA
+type Issuer interface {
+	// CACert returns the certificate of the CA signing the issued certificates
+	CACert() *x509.Certificate
+	// NewKey generates the private key of a certificate to be issued
+	NewKey() (crypto.Signer, error)
+	// Issue issues a certificate for the public key of the given key, as
+	// described by the config, valid for the given duration
+	Issue(cfg *certutil.Config, key crypto.Signer, validity time.Duration) (*x509.Certificate, error)
+	// IssueCSR issues a certificate with the given usages, valid for the given
+	// duration, for the PEM encoded certificate signing request
+	IssueCSR(csrPEM []byte, usages []x509.ExtKeyUsage, validity time.Duration) (*x509.Certificate, error)
+}
+
+// KubernetesIssuer returns the Issuer of the Kubernetes CA, generating the keys
+// of the given algorithm. If the certificates are signed by the external CA
+// signer or the key provider, the CA key is not required.
+func KubernetesIssuer(ctx context.Context, cluster *kubeoneapi.KubeOneCluster, config *configupload.Configuration, keyAlgorithm kubeoneapi.KeyAlgorithm) (Issuer, error) {
+	if !cluster.ExternalCASignerEnabled() {
+		caKey, caCert, err := CAKeyPair(config)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewKeyIssuer(caKey, caCert, keyAlgorithm), nil
+	}
+
+	caCert, err := ExternalCACert(cluster.ExternalCA())
+	if err != nil {
+		return nil, err
+	}
+
+	if provider := cluster.ExternalCA().KeyProvider; provider != nil {
+		caKey, err := NewKeyProviderSigner(ctx, *provider, caCert)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewKeyIssuer(caKey, caCert, keyAlgorithm), nil
+	}
+
+	return NewSignerIssuer(*cluster.ExternalCA().Signer, caCert, keyAlgorithm), nil
+}
+
+// ExternalCACert returns the certificate of the external CA used as the
+// Kubernetes CA, i.e. the intermediate CA if it's provided, otherwise the root CA
+func ExternalCACert(ca *kubeoneapi.ExternalCAConfig) (*x509.Certificate, error) {
+	caPEM := ca.CACertificate
+	if ca.IntermediateCertificate != "" {
+		caPEM = ca.IntermediateCertificate
+	}
+
+	certs, err := certutil.ParseCertsPEM([]byte(caPEM))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse the external CA certificate")
+	}
+
+	return certs[0], nil
+}
+
+// keyGenerator generates the private keys of the issued certificates
+type keyGenerator struct {
+	keyAlgorithm kubeoneapi.KeyAlgorithm
+}
+
+func (kg keyGenerator) NewKey() (crypto.Signer, error) {
+	return newPrivateKey(kg.keyAlgorithm)
+}
+
+// NewKeyIssuer returns the Issuer signing the certificates with the given CA
+// key, generating the keys of the given algorithm
+func NewKeyIssuer(caKey crypto.Signer, caCert *x509.Certificate, keyAlgorithm kubeoneapi.KeyAlgorithm) Issuer {
+	return &keyIssuer{
+		keyGenerator: keyGenerator{keyAlgorithm: keyAlgorithm},
+		caKey:        caKey,
+		caCert:       caCert,
+	}
+}
+
+type keyIssuer struct {
+	keyGenerator
+	caKey  crypto.Signer
+	caCert *x509.Certificate
+}
+
+func (ki *keyIssuer) CACert() *x509.Certificate {
+	return ki.caCert
+}
+
+func (ki *keyIssuer) Issue(cfg *certutil.Config, key crypto.Signer, validity time.Duration) (*x509.Certificate, error) {
+	return newSignedCert(cfg, key.Public(), ki.caCert, ki.caKey, validity)
+}
+
+func (ki *keyIssuer) IssueCSR(csrPEM []byte, usages []x509.ExtKeyUsage, validity time.Duration) (*x509.Certificate, error) {
+	csr, err := parseCSR(csrPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := certutil.Config{
+		CommonName:   csr.Subject.CommonName,
+		Organization: csr.Subject.Organization,
+		AltNames: certutil.AltNames{
+			DNSNames: csr.DNSNames,
+			IPs:      csr.IPAddresses,
+		},
+		Usages: usages,
+	}
+
+	return newSignedCert(&cfg, csr.PublicKey, ki.caCert, ki.caKey, validity)
+}
+
+// NewSignerIssuer returns the Issuer sending certificate signing requests to
+// the external CA signer, generating the keys of the given algorithm. The
+// signed certificates must be signed by the given CA certificate.
+func NewSignerIssuer(signer kubeoneapi.ExternalCASigner, caCert *x509.Certificate, keyAlgorithm kubeoneapi.KeyAlgorithm) Issuer {
+	return &signerIssuer{
+		keyGenerator: keyGenerator{keyAlgorithm: keyAlgorithm},
+		signer:       signer,
+		caCert:       caCert,
+	}
+}
+
+type signerIssuer struct {
+	keyGenerator
+	signer kubeoneapi.ExternalCASigner
+	caCert *x509.Certificate
+}
+
+func (si *signerIssuer) CACert() *x509.Certificate {
+	return si.caCert
+}
+
+func (si *signerIssuer) Issue(cfg *certutil.Config, key crypto.Signer, validity time.Duration) (*x509.Certificate, error) {
+	if len(cfg.CommonName) == 0 {
+		return nil, errors.New("must specify a CommonName")
+	}
+
+	csrTmpl := x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName:   cfg.CommonName,
+			Organization: cfg.Organization,
+		},
+		DNSNames:    cfg.AltNames.DNSNames,
+		IPAddresses: cfg.AltNames.IPs,
+	}
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &csrTmpl, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create certificate signing request")
+	}
+
+	csrPEM := pem.EncodeToMemory(&pem.Block{Type: certutil.CertificateRequestBlockType, Bytes: csrDER})
+
+	return si.IssueCSR(csrPEM, cfg.Usages, validity)
+}
+
+func (si *signerIssuer) IssueCSR(csrPEM []byte, usages []x509.ExtKeyUsage, validity time.Duration) (*x509.Certificate, error) {
+	csr, err := parseCSR(csrPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	usageNames, err := signerUsages(usages)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(si.signer.Command, si.signer.Args...)
+	cmd.Env = append(os.Environ(),
+		fmt.Sprintf("%s=%s", SignerUsagesEnv, strings.Join(usageNames, ",")),
+		fmt.Sprintf("%s=%s", SignerValidityEnv, validity),
+	)
+	cmd.Stdin = bytes.NewReader(csrPEM)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	cn := csr.Subject.CommonName
+	if err = cmd.Run(); err != nil {
+		return nil, errors.Wrapf(err, "external CA signer failed to sign %q: %s", cn, strings.TrimSpace(stderr.String()))
+	}
+
+	certs, err := certutil.ParseCertsPEM(stdout.Bytes())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the certificate of %q returned by the external CA signer", cn)
+	}
+
+	// kubeadm requires the certificates to be signed directly by the CA in ca.crt
+	cert := certs[0]
+	if err = cert.CheckSignatureFrom(si.caCert); err != nil {
+		return nil, errors.Wrapf(err, "certificate of %q returned by the external CA signer is not signed by the CA", cn)
+	}
+
+	pub, ok := csr.PublicKey.(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !pub.Equal(cert.PublicKey) {
+		return nil, errors.Errorf("certificate of %q returned by the external CA signer doesn't match the requested key", cn)
+	}
+
+	return cert, nil
+}
+
+// parseCSR parses the PEM encoded certificate signing request and verifies
+// its signature
+func parseCSR(csrPEM []byte) (*x509.CertificateRequest, error) {
+	block, _ := pem.Decode(csrPEM)
+	if block == nil || block.Type != certutil.CertificateRequestBlockType {
+		return nil, errors.New("failed to find a PEM encoded certificate signing request")
+	}
+
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse the certificate signing request")
+	}
+
+	if err = csr.CheckSignature(); err != nil {
+		return nil, errors.Wrap(err, "invalid certificate signing request signature")
+	}
+
+	return csr, nil
+}
+
+// signerUsages returns the names of the extended key usages, in the same format
+// as in the Kubernetes CertificateSigningRequests
+func signerUsages(extUsages []x509.ExtKeyUsage) ([]string, error) {
+	if len(extUsages) == 0 {
+		return nil, errors.New("must specify at least one ExtKeyUsage")
+	}
+
+	usages := []string{}
+
+	for _, u := range extUsages {
+		switch u {
+		case x509.ExtKeyUsageServerAuth:
+			usages = append(usages, string(certificatesv1.UsageServerAuth))
+		case x509.ExtKeyUsageClientAuth:
+			usages = append(usages, string(certificatesv1.UsageClientAuth))
+		default:
+			return nil, errors.Errorf("unsupported extended key usage %v", u)
+		}
+	}
+
+	return usages, nil
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/templates/resources"
+
+	certutil "k8s.io/client-go/util/cert"
+)
+
+const (
+	helperSignerEnv     = "KUBEONE_TEST_HELPER_SIGNER"
+	helperSignerCACert  = "KUBEONE_TEST_HELPER_SIGNER_CA_CERT"
+	helperSignerCAKey   = "KUBEONE_TEST_HELPER_SIGNER_CA_KEY"
+	helperSignerModeEnv = "KUBEONE_TEST_HELPER_SIGNER_MODE"
+)
+
+// TestHelperSigner is not a real test, it's the external CA signer executed by
+// the signer issuer tests
+func TestHelperSigner(t *testing.T) {
+	if os.Getenv(helperSignerEnv) != "1" {
+		return
+	}
+
+	if os.Getenv(helperSignerModeEnv) == "fail" {
+		fmt.Fprint(os.Stderr, "request denied")
+		os.Exit(1)
+	}
+
+	caKey, caCert, err := ParseCAKeyPair([]byte(os.Getenv(helperSignerCACert)), []byte(os.Getenv(helperSignerCAKey)))
+	if err != nil {
+		fmt.Fprint(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	csrPEM, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		fmt.Fprint(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	usages := []x509.ExtKeyUsage{}
+	for _, u := range strings.Split(os.Getenv(SignerUsagesEnv), ",") {
+		switch u {
+		case "client auth":
+			usages = append(usages, x509.ExtKeyUsageClientAuth)
+		case "server auth":
+			usages = append(usages, x509.ExtKeyUsageServerAuth)
+		}
+	}
+	validity, err := time.ParseDuration(os.Getenv(SignerValidityEnv))
+	if err != nil {
+		fmt.Fprint(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	cert, err := NewKeyIssuer(caKey, caCert, kubeoneapi.KeyAlgorithmRSA).IssueCSR(csrPEM, usages, validity)
+	if err != nil {
+		fmt.Fprint(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	fmt.Print(string(encodeCertPEM(cert)))
+	os.Exit(0)
+}
+
+func TestIssuers(t *testing.T) {
+	caCertPEM, caKeyPEM, err := NewCA("kubernetes", kubeoneapi.KeyAlgorithmRSA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caKey, caCert, err := ParseCAKeyPair(caCertPEM, caKeyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCAPEM, _, err := NewCA("other", kubeoneapi.KeyAlgorithmRSA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCA, err := certutil.ParseCertsPEM(otherCAPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer := func(mode string) kubeoneapi.ExternalCASigner {
+		return kubeoneapi.ExternalCASigner{
+			Command: "env",
+			Args: []string{
+				helperSignerEnv + "=1",
+				helperSignerModeEnv + "=" + mode,
+				helperSignerCACert + "=" + string(caCertPEM),
+				helperSignerCAKey + "=" + string(caKeyPEM),
+				os.Args[0], "-test.run=TestHelperSigner",
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		issuer        Issuer
+		expectedError bool
+	}{
+		{
+			name:   "key issuer",
+			issuer: NewKeyIssuer(caKey, caCert, kubeoneapi.KeyAlgorithmRSA),
+		},
+		{
+			name:   "signer issuer",
+			issuer: NewSignerIssuer(signer("sign"), caCert, kubeoneapi.KeyAlgorithmRSA),
+		},
+		{
+			name:          "signer issuer failure",
+			issuer:        NewSignerIssuer(signer("fail"), caCert, kubeoneapi.KeyAlgorithmRSA),
+			expectedError: true,
+		},
+		{
+			name:          "signer issuer with another CA",
+			issuer:        NewSignerIssuer(signer("sign"), otherCA[0], kubeoneapi.KeyAlgorithmRSA),
+			expectedError: true,
+		},
+	}
+
+	cfg := certutil.Config{
+		CommonName:   "kube-apiserver",
+		Organization: []string{"kubeone"},
+		AltNames: certutil.AltNames{
+			DNSNames: []string{"kubernetes.default"},
+			IPs:      []net.IP{net.ParseIP("10.96.0.1")},
+		},
+		Usages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			issued, err := NewCert(cfg, time.Hour, tc.issuer)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("expected error = %v, got %v", tc.expectedError, err)
+			}
+			if tc.expectedError {
+				return
+			}
+
+			certs, err := certutil.ParseCertsPEM([]byte(issued[resources.TLSCertName]))
+			if err != nil {
+				t.Fatal(err)
+			}
+			cert := certs[0]
+
+			if cert.Subject.CommonName != cfg.CommonName || !reflect.DeepEqual(cert.Subject.Organization, cfg.Organization) {
+				t.Errorf("unexpected subject %v", cert.Subject)
+			}
+			if !reflect.DeepEqual(cert.DNSNames, cfg.AltNames.DNSNames) || len(cert.IPAddresses) != 1 || !cert.IPAddresses[0].Equal(cfg.AltNames.IPs[0]) {
+				t.Errorf("unexpected SANs %v %v", cert.DNSNames, cert.IPAddresses)
+			}
+			if !reflect.DeepEqual(cert.ExtKeyUsage, cfg.Usages) {
+				t.Errorf("unexpected usages %v", cert.ExtKeyUsage)
+			}
+			if err = cert.CheckSignatureFrom(caCert); err != nil {
+				t.Errorf("certificate is not signed by the CA: %v", err)
+			}
+		})
+	}
+}
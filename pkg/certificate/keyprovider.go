@@ -0,0 +1,48 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/secretstore"
+)
+
+// NewKeyProviderSigner returns the crypto.Signer signing with the CA key held
+// by the key provider. The private key never leaves the key provider, only the
+// digests to sign are sent to it. The public key of the returned signer is the
+// public key of the given CA certificate.
+func NewKeyProviderSigner(ctx context.Context, provider kubeoneapi.ExternalCAKeyProvider, caCert *x509.Certificate) (crypto.Signer, error) {
+	switch {
+	case provider.PKCS11 != nil:
+		pin, err := secretstore.Resolve(ctx, provider.PKCS11.PIN)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to resolve the PKCS#11 PIN")
+		}
+
+		return newPKCS11Signer(*provider.PKCS11, pin, caCert.PublicKey)
+	case provider.AWSKMS != nil:
+		return newAWSKMSSigner(ctx, *provider.AWSKMS, caCert.PublicKey)
+	}
+
+	return nil, errors.New("no key provider configured")
+}
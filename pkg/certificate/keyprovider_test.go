@@ -0,0 +1,297 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+)
+
+const (
+	helperPKCS11ToolEnv = "KUBEONE_TEST_HELPER_PKCS11_TOOL"
+	helperPKCS11KeyEnv  = "KUBEONE_TEST_HELPER_PKCS11_KEY"
+)
+
+// TestHelperPKCS11Tool is not a real test, it's the pkcs11-tool executed by
+// the PKCS#11 signer tests. It signs the input as is, like the token does.
+func TestHelperPKCS11Tool(t *testing.T) {
+	if os.Getenv(helperPKCS11ToolEnv) != "1" {
+		return
+	}
+
+	args := strings.Join(os.Args, " ")
+	if !strings.Contains(args, "--pin env:"+pkcs11PINEnv) || os.Getenv(pkcs11PINEnv) != "1234" {
+		fmt.Fprint(os.Stderr, "invalid PIN")
+		os.Exit(1)
+	}
+
+	key, err := keyutil.ParsePrivateKeyPEM([]byte(os.Getenv(helperPKCS11KeyEnv)))
+	if err != nil {
+		fmt.Fprint(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	input, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		fmt.Fprint(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	var sig []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if !strings.Contains(args, "--mechanism RSA-PKCS") {
+			fmt.Fprint(os.Stderr, "invalid mechanism")
+			os.Exit(1)
+		}
+		sig, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.Hash(0), input)
+	case *ecdsa.PrivateKey:
+		if !strings.Contains(args, "--mechanism ECDSA --signature-format openssl") {
+			fmt.Fprint(os.Stderr, "invalid mechanism")
+			os.Exit(1)
+		}
+		sig, err = ecdsa.SignASN1(rand.Reader, k, input)
+	}
+	if err != nil {
+		fmt.Fprint(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	_, _ = os.Stdout.Write(sig)
+	os.Exit(0)
+}
+
+// fakeKMS is the AWS KMS client signing with the local key
+type fakeKMS struct {
+	kmsiface.KMSAPI
+	key crypto.Signer
+}
+
+func (f *fakeKMS) GetPublicKeyWithContext(_ aws.Context, _ *kms.GetPublicKeyInput, _ ...request.Option) (*kms.GetPublicKeyOutput, error) {
+	pub, err := x509.MarshalPKIXPublicKey(f.key.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	return &kms.GetPublicKeyOutput{PublicKey: pub}, nil
+}
+
+func (f *fakeKMS) SignWithContext(_ aws.Context, in *kms.SignInput, _ ...request.Option) (*kms.SignOutput, error) {
+	opts := map[string]crypto.SignerOpts{
+		kms.SigningAlgorithmSpecRsassaPkcs1V15Sha256: crypto.SHA256,
+		kms.SigningAlgorithmSpecRsassaPssSha256:      &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: crypto.SHA256},
+		kms.SigningAlgorithmSpecEcdsaSha256:          crypto.SHA256,
+	}[aws.StringValue(in.SigningAlgorithm)]
+	if opts == nil || aws.StringValue(in.MessageType) != kms.MessageTypeDigest {
+		return nil, fmt.Errorf("unexpected signing algorithm %s", aws.StringValue(in.SigningAlgorithm))
+	}
+
+	sig, err := f.key.Sign(rand.Reader, in.Message, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kms.SignOutput{Signature: sig}, nil
+}
+
+func newTestKeyProviderCA(t *testing.T, key crypto.Signer) *x509.Certificate {
+	caCert, err := certutil.NewSelfSignedCACert(certutil.Config{CommonName: "kubernetes"}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return caCert
+}
+
+func TestKeyProviderSigners(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkcs11Signer := func(key crypto.Signer, pin string) func(*x509.Certificate) (crypto.Signer, error) {
+		return func(caCert *x509.Certificate) (crypto.Signer, error) {
+			keyPEM, err := keyutil.MarshalPrivateKeyToPEM(key)
+			if err != nil {
+				return nil, err
+			}
+
+			signer, err := newPKCS11Signer(kubeoneapi.PKCS11KeyProvider{Module: "softhsm2.so", KeyID: "01"}, pin, caCert.PublicKey)
+			if err != nil {
+				return nil, err
+			}
+			signer.command = []string{
+				"env",
+				helperPKCS11ToolEnv + "=1",
+				helperPKCS11KeyEnv + "=" + string(keyPEM),
+				os.Args[0], "-test.run=TestHelperPKCS11Tool", "--",
+			}
+
+			return signer, nil
+		}
+	}
+	awsKMSSigner := func(key crypto.Signer) func(*x509.Certificate) (crypto.Signer, error) {
+		return func(caCert *x509.Certificate) (crypto.Signer, error) {
+			return newAWSKMSSignerWithClient(context.Background(), &fakeKMS{key: key}, "alias/kubernetes-ca", caCert.PublicKey)
+		}
+	}
+
+	tests := []struct {
+		name          string
+		caKey         crypto.Signer
+		signer        func(*x509.Certificate) (crypto.Signer, error)
+		expectedError bool
+	}{
+		{
+			name:   "PKCS#11 RSA key",
+			caKey:  rsaKey,
+			signer: pkcs11Signer(rsaKey, "1234"),
+		},
+		{
+			name:   "PKCS#11 ECDSA key",
+			caKey:  ecdsaKey,
+			signer: pkcs11Signer(ecdsaKey, "1234"),
+		},
+		{
+			name:          "PKCS#11 invalid PIN",
+			caKey:         rsaKey,
+			signer:        pkcs11Signer(rsaKey, "0000"),
+			expectedError: true,
+		},
+		{
+			name:   "AWS KMS RSA key",
+			caKey:  rsaKey,
+			signer: awsKMSSigner(rsaKey),
+		},
+		{
+			name:   "AWS KMS ECDSA key",
+			caKey:  ecdsaKey,
+			signer: awsKMSSigner(ecdsaKey),
+		},
+		{
+			name:          "AWS KMS key not matching the CA",
+			caKey:         ecdsaKey,
+			signer:        awsKMSSigner(otherKey),
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			caCert := newTestKeyProviderCA(t, tc.caKey)
+
+			err := func() error {
+				signer, err := tc.signer(caCert)
+				if err != nil {
+					return err
+				}
+
+				_, err = NewSignedCert("kubernetes-admin", nil, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, time.Hour, NewKeyIssuer(signer, caCert, kubeoneapi.KeyAlgorithmRSA))
+
+				return err
+			}()
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("expected error %v, but got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestAWSKMSSigningAlgorithm(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		pub           crypto.PublicKey
+		opts          crypto.SignerOpts
+		expected      string
+		expectedError bool
+	}{
+		{
+			name:     "RSA PKCS#1 v1.5 SHA-256",
+			pub:      rsaKey.Public(),
+			opts:     crypto.SHA256,
+			expected: kms.SigningAlgorithmSpecRsassaPkcs1V15Sha256,
+		},
+		{
+			name:     "RSA PSS SHA-512",
+			pub:      rsaKey.Public(),
+			opts:     &rsa.PSSOptions{Hash: crypto.SHA512},
+			expected: kms.SigningAlgorithmSpecRsassaPssSha512,
+		},
+		{
+			name:     "ECDSA SHA-384",
+			pub:      ecdsaKey.Public(),
+			opts:     crypto.SHA384,
+			expected: kms.SigningAlgorithmSpecEcdsaSha384,
+		},
+		{
+			name:          "unsupported hash function",
+			pub:           rsaKey.Public(),
+			opts:          crypto.SHA1,
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := awsKMSSigningAlgorithm(tc.pub, tc.opts)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("expected error %v, but got %v", tc.expectedError, err)
+			}
+			if got != tc.expected {
+				t.Errorf("expected %q, but got %q", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+)
+
+const (
+	// pkcs11Tool is the OpenSC utility used to sign with the PKCS#11 token.
+	// It's used instead of linking the PKCS#11 module, which requires cgo.
+	pkcs11Tool = "pkcs11-tool"
+	// pkcs11PINEnv is the environment variable passing the PIN to pkcs11-tool,
+	// so the PIN is not visible in the process list
+	pkcs11PINEnv = "KUBEONE_PKCS11_PIN"
+)
+
+// rsaDigestInfoPrefixes are the DER encoded DigestInfo prefixes of the
+// RSASSA-PKCS1-v1_5 signatures. The RSA-PKCS mechanism signs the DigestInfo
+// as is, so it has to be built by the caller.
+var rsaDigestInfoPrefixes = map[crypto.Hash][]byte{
+	crypto.SHA256: {0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20},
+	crypto.SHA384: {0x30, 0x41, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x02, 0x05, 0x00, 0x04, 0x30},
+	crypto.SHA512: {0x30, 0x51, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x03, 0x05, 0x00, 0x04, 0x40},
+}
+
+type pkcs11Signer struct {
+	// command is the pkcs11-tool command, including the leading arguments
+	command []string
+	config  kubeoneapi.PKCS11KeyProvider
+	pin     string
+	pub     crypto.PublicKey
+}
+
+func newPKCS11Signer(config kubeoneapi.PKCS11KeyProvider, pin string, pub crypto.PublicKey) (*pkcs11Signer, error) {
+	switch pub.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+	default:
+		return nil, errors.Errorf("unsupported PKCS#11 key type %T", pub)
+	}
+
+	return &pkcs11Signer{
+		command: []string{pkcs11Tool},
+		config:  config,
+		pin:     pin,
+		pub:     pub,
+	}, nil
+}
+
+func (ps *pkcs11Signer) Public() crypto.PublicKey {
+	return ps.pub
+}
+
+func (ps *pkcs11Signer) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	input := digest
+	args := []string{
+		"--module", ps.config.Module,
+		"--id", ps.config.KeyID,
+		"--sign",
+	}
+
+	switch ps.pub.(type) {
+	case *rsa.PublicKey:
+		if _, ok := opts.(*rsa.PSSOptions); ok {
+			return nil, errors.New("RSA-PSS signatures are not supported by the PKCS#11 key provider")
+		}
+		prefix, ok := rsaDigestInfoPrefixes[opts.HashFunc()]
+		if !ok {
+			return nil, errors.Errorf("unsupported hash function %v", opts.HashFunc())
+		}
+		input = append(append([]byte{}, prefix...), digest...)
+		args = append(args, "--mechanism", "RSA-PKCS")
+	case *ecdsa.PublicKey:
+		// crypto.Signer must return ASN.1 encoded ECDSA signatures
+		args = append(args, "--mechanism", "ECDSA", "--signature-format", "openssl")
+	}
+
+	if ps.config.TokenLabel != "" {
+		args = append(args, "--token-label", ps.config.TokenLabel)
+	}
+	if ps.pin != "" {
+		args = append(args, "--login", "--pin", "env:"+pkcs11PINEnv)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(ps.command[0], append(ps.command[1:], args...)...)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", pkcs11PINEnv, ps.pin))
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrapf(err, "failed to sign with the PKCS#11 key %s: %s", ps.config.KeyID, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
@@ -24,6 +24,9 @@ package certificate
 
 import (
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -35,7 +38,10 @@ import (
 
 	"github.com/pkg/errors"
 
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+
 	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
 )
 
 const (
@@ -61,7 +67,7 @@ func encodeCertPEM(cert *x509.Certificate) []byte {
 }
 
 // EncodePublicKeyPEM returns PEM-encoded public data
-func EncodePublicKeyPEM(key *rsa.PublicKey) ([]byte, error) {
+func EncodePublicKeyPEM(key crypto.PublicKey) ([]byte, error) {
 	der, err := x509.MarshalPKIXPublicKey(key)
 	if err != nil {
 		return []byte{}, err
@@ -73,22 +79,65 @@ func EncodePublicKeyPEM(key *rsa.PublicKey) ([]byte, error) {
 	return pem.EncodeToMemory(&block), nil
 }
 
-// encodePrivateKeyPEM returns PEM-encoded private key data
-func encodePrivateKeyPEM(key *rsa.PrivateKey) []byte {
-	block := pem.Block{
-		Type:  RSAPrivateKeyBlockType,
-		Bytes: x509.MarshalPKCS1PrivateKey(key),
+// encodePrivateKeyPEM returns PEM-encoded private key data. RSA and ECDSA keys
+// are encoded in the PKCS#1 and SEC 1 formats, as by kubeadm, while Ed25519
+// keys are encoded in the PKCS#8 format.
+func encodePrivateKeyPEM(key crypto.Signer) ([]byte, error) {
+	if edKey, ok := key.(ed25519.PrivateKey); ok {
+		der, err := x509.MarshalPKCS8PrivateKey(edKey)
+		if err != nil {
+			return nil, err
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: PrivateKeyBlockType, Bytes: der}), nil
 	}
-	return pem.EncodeToMemory(&block)
+
+	return keyutil.MarshalPrivateKeyToPEM(key)
+}
+
+// parsePrivateKeyPEM parses the PEM-encoded RSA, ECDSA or Ed25519 private key
+func parsePrivateKeyPEM(keyPEM []byte) (crypto.Signer, error) {
+	key, err := keyutil.ParsePrivateKeyPEM(keyPEM)
+	if err != nil {
+		// keyutil doesn't support Ed25519 keys
+		block, _ := pem.Decode(keyPEM)
+		if block == nil || block.Type != PrivateKeyBlockType {
+			return nil, err
+		}
+		if key, err = x509.ParsePKCS8PrivateKey(block.Bytes); err != nil {
+			return nil, err
+		}
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k, nil
+	case *ecdsa.PrivateKey:
+		return k, nil
+	case ed25519.PrivateKey:
+		return k, nil
+	}
+
+	return nil, errors.Errorf("unsupported private key type %T", key)
 }
 
-// newPrivateKey creates an RSA private key
-func newPrivateKey() (*rsa.PrivateKey, error) {
-	return rsa.GenerateKey(rand.Reader, rsaKeySize)
+// newPrivateKey creates a private key of the given algorithm
+func newPrivateKey(algorithm kubeoneapi.KeyAlgorithm) (crypto.Signer, error) {
+	switch algorithm {
+	case kubeoneapi.KeyAlgorithmRSA, "":
+		return rsa.GenerateKey(rand.Reader, rsaKeySize)
+	case kubeoneapi.KeyAlgorithmECDSA:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case kubeoneapi.KeyAlgorithmEd25519:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	}
+
+	return nil, errors.Errorf("unsupported key algorithm %q", algorithm)
 }
 
-// newSignedCert creates a signed certificate using the given CA certificate and key
-func newSignedCert(cfg *certutil.Config, key crypto.Signer, caCert *x509.Certificate, caKey crypto.Signer) (*x509.Certificate, error) {
+// newSignedCert creates a signed certificate for the given public key using the
+// given CA certificate and key, valid for the given duration
+func newSignedCert(cfg *certutil.Config, pub crypto.PublicKey, caCert *x509.Certificate, caKey crypto.Signer, validity time.Duration) (*x509.Certificate, error) {
 	serial, err := rand.Int(rand.Reader, new(big.Int).SetInt64(math.MaxInt64))
 	if err != nil {
 		return nil, err
@@ -109,11 +158,15 @@ func newSignedCert(cfg *certutil.Config, key crypto.Signer, caCert *x509.Certifi
 		IPAddresses:  cfg.AltNames.IPs,
 		SerialNumber: serial,
 		NotBefore:    caCert.NotBefore,
-		NotAfter:     time.Now().Add(duration365d).UTC(),
-		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		NotAfter:     time.Now().Add(validity).UTC(),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
 		ExtKeyUsage:  cfg.Usages,
 	}
-	certDERBytes, err := x509.CreateCertificate(rand.Reader, &certTmpl, caCert, key.Public(), caKey)
+	// Key encipherment is used only by the RSA key exchange
+	if _, ok := pub.(*rsa.PublicKey); ok {
+		certTmpl.KeyUsage |= x509.KeyUsageKeyEncipherment
+	}
+	certDERBytes, err := x509.CreateCertificate(rand.Reader, &certTmpl, caCert, pub, caKey)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,227 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/state"
+	"k8c.io/kubeone/pkg/tasks"
+)
+
+// Action is what Apply did to reconcile the cluster
+type Action string
+
+const (
+	// ActionNone means nothing was done, because the cluster can't be
+	// repaired without removing the broken hosts manually
+	ActionNone Action = "none"
+	// ActionInstall means the cluster was provisioned
+	ActionInstall Action = "install"
+	// ActionRepair means the hosts missing from the cluster were joined
+	ActionRepair Action = "repair"
+	// ActionUpgrade means the cluster was upgraded
+	ActionUpgrade Action = "upgrade"
+	// ActionReconcile means the addons, MachineDeployments and other
+	// resources were reconciled
+	ActionReconcile Action = "reconcile"
+)
+
+// ApplyOptions are the options of the Apply operation, the same as the flags
+// of the 'kubeone apply' command
+type ApplyOptions struct {
+	// BackupFile is the path to where the PKI backup .tar.gz file is placed.
+	// Defaults to <cluster-name>.tar.gz next to the manifest.
+	BackupFile string
+	// NoInit installs only the binaries, without initializing the cluster
+	NoInit bool
+	// ForceInstall installs new binary versions (dangerous)
+	ForceInstall bool
+	// ForceUpgrade runs the upgrade even if the cluster is already running
+	// the requested version
+	ForceUpgrade bool
+	// UpgradeMachineDeployments upgrades the MachineDeployments objects
+	UpgradeMachineDeployments bool
+	// UpgradeVerifyTimeout is how long each upgraded node is allowed to
+	// become healthy, before the upgrade is stopped
+	UpgradeVerifyTimeout time.Duration
+}
+
+// ApplyResult describes what Apply did
+type ApplyResult struct {
+	// Action taken to reconcile the cluster
+	Action Action `json:"action"`
+	// Operations are the descriptions of the actions taken
+	Operations []string `json:"operations,omitempty"`
+	// BrokenHosts are the hosts which need to be removed manually, in the
+	// recommended removal order
+	BrokenHosts []string `json:"brokenHosts,omitempty"`
+}
+
+// Apply reconciles the cluster: it's installed if it's not provisioned yet,
+// repaired if some hosts are not in the cluster, upgraded if the requested
+// Kubernetes version differs from the running one, and otherwise the
+// resources are reconciled.
+func (c *Client) Apply(ctx context.Context, opts ApplyOptions) (*ApplyResult, error) {
+	s, err := c.newState(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.BackupFile = opts.BackupFile
+	s.ForceInstall = opts.ForceInstall
+	s.ForceUpgrade = opts.ForceUpgrade
+	s.UpgradeMachineDeployments = opts.UpgradeMachineDeployments
+	s.UpgradeVerifyTimeout = opts.UpgradeVerifyTimeout
+
+	if err = c.prepareBackupFile(s); err != nil {
+		return nil, err
+	}
+
+	if err = c.validateCredentials(); err != nil {
+		return nil, err
+	}
+
+	if err = probe(s, true); err != nil {
+		return nil, err
+	}
+
+	lock, err := acquireLock(s, "apply")
+	if err != nil {
+		return nil, err
+	}
+	defer releaseLock(s, lock)
+
+	if !s.LiveCluster.IsProvisioned() {
+		return applyInstall(s, opts, ActionInstall)
+	}
+
+	if !s.LiveCluster.Healthy() {
+		return applyRepair(s, opts)
+	}
+
+	return applyUpgradeIfNeeded(s, opts)
+}
+
+// prepareBackupFile defaults the backup file and checks it can be written
+// before anything else is done
+func (c *Client) prepareBackupFile(s *state.State) error {
+	if s.BackupFile == "" {
+		fullPath, _ := filepath.Abs(c.opts.ManifestFilePath)
+		s.BackupFile = filepath.Join(filepath.Dir(fullPath), fmt.Sprintf("%s.tar.gz", s.Cluster.Name))
+	}
+
+	f, err := os.OpenFile(s.BackupFile, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "cannot open %q for writing", s.BackupFile)
+	}
+
+	return f.Close()
+}
+
+func applyInstall(s *state.State, opts ApplyOptions, action Action) (*ApplyResult, error) {
+	result := &ApplyResult{Action: action}
+
+	for _, node := range s.LiveCluster.ControlPlane {
+		if !node.IsInCluster {
+			result.Operations = append(result.Operations, fmt.Sprintf("join control plane node %q (%s) using %s", node.Config.Hostname, node.Config.PrivateAddress, s.Cluster.Versions.Kubernetes))
+		}
+	}
+
+	for _, node := range s.LiveCluster.StaticWorkers {
+		if !node.IsInCluster {
+			result.Operations = append(result.Operations, fmt.Sprintf("join worker node %q (%s)", node.Config.Hostname, node.Config.PrivateAddress))
+		}
+	}
+
+	if opts.NoInit {
+		return result, errors.Wrap(tasks.WithBinariesOnly(nil).Run(s), "failed to install kubernetes binaries")
+	}
+
+	return result, errors.Wrap(tasks.WithFullInstall(nil).Run(s), "failed to install the cluster")
+}
+
+func applyRepair(s *state.State, opts ApplyOptions) (*ApplyResult, error) {
+	result := &ApplyResult{Action: ActionNone}
+
+	brokenHosts := s.LiveCluster.BrokenHosts()
+	if len(brokenHosts) > 0 {
+		result.BrokenHosts = s.LiveCluster.SafeToDeleteHosts()
+		for _, node := range brokenHosts {
+			s.Logger.Errorf("Host %q is broken and needs to be manually removed", node)
+		}
+	}
+
+	if safeRepair, higherVer := s.LiveCluster.SafeToRepair(s.Cluster.Versions.Kubernetes); !safeRepair {
+		return result, errors.Errorf("repair and upgrade are not supported at the same time, use version %s to repair the cluster", higherVer)
+	}
+
+	if hostsNotInCluster(s) {
+		return applyInstall(s, opts, ActionRepair)
+	}
+
+	if len(brokenHosts) > 0 {
+		return result, errors.New("broken host(s) found, remove it manually")
+	}
+
+	return result, nil
+}
+
+func applyUpgradeIfNeeded(s *state.State, opts ApplyOptions) (*ApplyResult, error) {
+	upgradeNeeded, err := s.LiveCluster.UpgradeNeeded()
+	if err != nil {
+		return nil, errors.Wrap(err, "upgrade not allowed")
+	}
+
+	result := &ApplyResult{Action: ActionReconcile}
+	tasksToRun := tasks.WithResources(nil)
+
+	if upgradeNeeded || opts.ForceUpgrade {
+		result.Action = ActionUpgrade
+		tasksToRun, result.Operations, err = tasks.WithUpgradeAndEncryption(nil, s)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result.Operations = append(result.Operations, tasksToRun.Descriptions(s)...)
+
+	return result, errors.Wrap(tasksToRun.Run(s), "failed to reconcile the cluster")
+}
+
+// hostsNotInCluster returns whether any of the hosts needs to be joined
+func hostsNotInCluster(s *state.State) bool {
+	for _, node := range s.LiveCluster.ControlPlane {
+		if !node.IsInCluster {
+			return true
+		}
+	}
+
+	for _, node := range s.LiveCluster.StaticWorkers {
+		if !node.IsInCluster {
+			return true
+		}
+	}
+
+	return false
+}
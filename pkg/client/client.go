@@ -0,0 +1,187 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package client is the Go API for running KubeOne operations, such as
+// apply, upgrade and reset, without shelling out to the kubeone binary.
+//
+// The Client is created for a single cluster. Each operation takes a context,
+// which cancels the SSH connections and the Kubernetes API requests done by
+// the operation. Operations don't ask for confirmation and don't print
+// anything, everything is logged using the given logger and the progress of
+// the tasks is reported using the Progress callback.
+//
+//	cluster, err := client.LoadCluster("kubeone.yaml", "tf.json", "", logger)
+//	if err != nil {
+//		return err
+//	}
+//
+//	c, err := client.New(cluster, client.Options{
+//		Logger:           logger,
+//		ManifestFilePath: "kubeone.yaml",
+//	})
+//	if err != nil {
+//		return err
+//	}
+//
+//	result, err := c.Apply(ctx, client.ApplyOptions{})
+package client
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/apis/kubeone/config"
+	"k8c.io/kubeone/pkg/clusterlock"
+	"k8c.io/kubeone/pkg/credentials"
+	"k8c.io/kubeone/pkg/progress"
+	"k8c.io/kubeone/pkg/state"
+	"k8c.io/kubeone/pkg/tasks"
+)
+
+// Options configures the Client
+type Options struct {
+	// Logger is used for logging the progress of the operations. Defaults to
+	// the logrus standard logger.
+	Logger logrus.FieldLogger
+	// Progress is called each time a task, or a step of the task running on
+	// a single node, is started, retried or finished. The function must not
+	// block, because the tasks wait for it.
+	Progress func(progress.Span)
+	// ManifestFilePath is the path to the KubeOneCluster manifest. It's used
+	// for resolving the paths in the manifest, such as the addons path,
+	// relative to the manifest. Defaults to the current directory.
+	ManifestFilePath string
+	// CredentialsFilePath is the path to the credentials file, the same as
+	// the --credentials flag. Defaults to the environment variables.
+	CredentialsFilePath string
+	// Verbose enables logging of the commands run on the hosts
+	Verbose bool
+}
+
+// Client runs KubeOne operations on a single cluster. It's safe to run
+// operations one after another, but not concurrently.
+type Client struct {
+	cluster *kubeoneapi.KubeOneCluster
+	opts    Options
+}
+
+// LoadCluster loads the KubeOneCluster manifest, merging the Terraform output
+// and the credentials into it, the same way as kubeone does. tfOutputPath and
+// credentialsFilePath are optional.
+func LoadCluster(manifestFilePath, tfOutputPath, credentialsFilePath string, logger logrus.FieldLogger) (*kubeoneapi.KubeOneCluster, error) {
+	if logger == nil {
+		logger = logrus.StandardLogger()
+	}
+
+	return config.LoadKubeOneCluster(manifestFilePath, tfOutputPath, credentialsFilePath, logger)
+}
+
+// New returns the Client running operations on the given cluster. The
+// cluster is copied, so it's not changed by the operations.
+func New(cluster *kubeoneapi.KubeOneCluster, opts Options) (*Client, error) {
+	if cluster == nil {
+		return nil, errors.New("cluster is required")
+	}
+	if opts.Logger == nil {
+		opts.Logger = logrus.StandardLogger()
+	}
+
+	return &Client{
+		cluster: cluster.DeepCopy(),
+		opts:    opts,
+	}, nil
+}
+
+// Cluster returns the copy of the cluster the operations are run on
+func (c *Client) Cluster() *kubeoneapi.KubeOneCluster {
+	return c.cluster.DeepCopy()
+}
+
+// newState returns the State for a single operation, bound to the context
+func (c *Client) newState(ctx context.Context) (*state.State, error) {
+	if ctx == nil {
+		return nil, errors.New("context is required")
+	}
+
+	s, err := state.New(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to initialize State")
+	}
+
+	s.Logger = c.opts.Logger
+	s.Cluster = c.cluster.DeepCopy()
+	s.ManifestFilePath = c.opts.ManifestFilePath
+	s.CredentialsFilePath = c.opts.CredentialsFilePath
+
+	s.Cluster.CloudProvider.CloudConfig, err = credentials.RenderCloudConfig(s.Cluster.CloudProvider, s.CredentialsFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Cluster.CloudProvider.CSIConfig, err = credentials.RenderCSIConfig(s.Cluster.Name, s.Cluster.CloudProvider, s.CredentialsFilePath)
+	if err != nil {
+		return nil, err
+	}
+	s.Verbose = c.opts.Verbose
+
+	if c.opts.Progress != nil {
+		s.Progress = progress.NewRecorder()
+		s.Progress.Notify(c.opts.Progress)
+	}
+
+	return s, nil
+}
+
+// validateCredentials checks that the credentials for the cloud provider
+// are available
+func (c *Client) validateCredentials() error {
+	_, err := credentials.ProviderCredentials(c.cluster.CloudProvider, c.opts.CredentialsFilePath)
+
+	return errors.Wrap(err, "failed to validate credentials")
+}
+
+// acquireLock acquires the cluster lock for the operation, in the target
+// cluster if the Kubernetes client is initialized
+func acquireLock(s *state.State, operation string) (*clusterlock.Lock, error) {
+	lock := clusterlock.New(clusterlock.Options{
+		Cluster:   s.Cluster.Name,
+		Operation: operation,
+		Logger:    s.Logger,
+	})
+
+	return lock, errors.Wrap(lock.AcquireState(s), "failed to lock the cluster")
+}
+
+func releaseLock(s *state.State, lock *clusterlock.Lock) {
+	if err := lock.Release(context.Background()); err != nil {
+		s.Logger.Warnf("Failed to release the cluster lock: %v", err)
+	}
+}
+
+// probe collects the actual state of the cluster
+func probe(s *state.State, safeguard bool) error {
+	probes := tasks.WithHostnameOS(nil)
+	if safeguard {
+		probes = tasks.WithProbesAndSafeguard(probes)
+	} else {
+		probes = tasks.WithProbes(probes)
+	}
+
+	return errors.Wrap(probes.Run(s), "failed to probe the cluster")
+}
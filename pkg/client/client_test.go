@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/progress"
+)
+
+func TestNew(t *testing.T) {
+	if _, err := New(nil, Options{}); err == nil {
+		t.Fatal("expected error for nil cluster")
+	}
+
+	cluster := &kubeoneapi.KubeOneCluster{}
+	cluster.Name = "test"
+
+	c, err := New(cluster, Options{})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if c.opts.Logger == nil {
+		t.Error("expected logger to be defaulted")
+	}
+
+	cluster.Name = "changed"
+	if got := c.Cluster().Name; got != "test" {
+		t.Errorf("expected the cluster to be copied, got name %q", got)
+	}
+}
+
+func TestNewState(t *testing.T) {
+	cluster := &kubeoneapi.KubeOneCluster{}
+	cluster.Name = "test"
+
+	spans := []progress.Span{}
+	c, err := New(cluster, Options{
+		ManifestFilePath: "kubeone.yaml",
+		Progress: func(sp progress.Span) {
+			spans = append(spans, sp)
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	//nolint:staticcheck
+	if _, err = c.newState(nil); err == nil {
+		t.Error("expected error for nil context")
+	}
+
+	s, err := c.newState(context.Background())
+	if err != nil {
+		t.Fatalf("failed to create state: %v", err)
+	}
+	if s.ManifestFilePath != "kubeone.yaml" {
+		t.Errorf("expected manifest file path to be set, got %q", s.ManifestFilePath)
+	}
+
+	s.Cluster.Name = "changed"
+	if got := c.Cluster().Name; got != "test" {
+		t.Errorf("expected the operation not to change the cluster, got name %q", got)
+	}
+
+	s.Progress.StartTask("install", "tasks").Finish(nil)
+	if len(spans) != 2 {
+		t.Errorf("expected progress to be reported twice, got %d", len(spans))
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/kubeconfig"
+	"k8c.io/kubeone/pkg/tasks"
+)
+
+// ResetOptions are the options of the Reset operation
+type ResetOptions struct {
+	// DestroyWorkers destroys the machine-controller managed worker nodes
+	DestroyWorkers bool
+	// RemoveBinaries removes the Kubernetes binaries from the hosts
+	RemoveBinaries bool
+	// DrainNodes drains the nodes before resetting them
+	DrainNodes bool
+}
+
+// Reset undoes all changes done by KubeOne to the hosts. There's no way to
+// recover the cluster or its data afterwards.
+func (c *Client) Reset(ctx context.Context, opts ResetOptions) error {
+	s, err := c.newState(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.DestroyWorkers = opts.DestroyWorkers
+	s.RemoveBinaries = opts.RemoveBinaries
+	s.DrainNodes = opts.DrainNodes
+
+	// The error is intentionally ignored because the cluster might be not
+	// provisioned yet, or broken
+	_ = kubeconfig.BuildKubernetesClientset(s)
+
+	lock, err := acquireLock(s, "reset")
+	if err != nil {
+		return err
+	}
+	// The Lease is gone together with the cluster, so the failure to release
+	// it after the successful reset is expected
+	defer func() { _ = lock.Release(context.Background()) }()
+
+	return errors.Wrap(tasks.WithReset(nil).Run(s), "failed to reset the cluster")
+}
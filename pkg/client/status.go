@@ -0,0 +1,55 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/clusterstatus"
+	"k8c.io/kubeone/pkg/kubeconfig"
+)
+
+// Status returns the report of the versions and health of the cluster
+// components. Commands changing the hosts are refused while getting the
+// status.
+func (c *Client) Status(ctx context.Context) (*clusterstatus.Report, error) {
+	s, err := c.newState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.Connector.SetReadOnly(true)
+
+	if err = probe(s, false); err != nil {
+		return nil, err
+	}
+
+	if !s.LiveCluster.IsProvisioned() {
+		return nil, errors.New("the target cluster is not provisioned")
+	}
+
+	if s.DynamicClient == nil {
+		if err = kubeconfig.BuildKubernetesClientset(s); err != nil {
+			return nil, errors.Wrap(err, "failed to build kubernetes clientset")
+		}
+	}
+
+	report, err := clusterstatus.GetReport(s)
+
+	return report, errors.Wrap(err, "failed to get cluster status")
+}
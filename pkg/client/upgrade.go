@@ -0,0 +1,61 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/tasks"
+)
+
+// UpgradeOptions are the options of the Upgrade operation
+type UpgradeOptions struct {
+	// Force runs the upgrade even if the cluster is already running the
+	// requested version
+	Force bool
+	// UpgradeMachineDeployments upgrades the MachineDeployments objects
+	UpgradeMachineDeployments bool
+}
+
+// Upgrade upgrades the cluster to the Kubernetes version from the manifest
+func (c *Client) Upgrade(ctx context.Context, opts UpgradeOptions) error {
+	s, err := c.newState(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.ForceUpgrade = opts.Force
+	s.UpgradeMachineDeployments = opts.UpgradeMachineDeployments
+
+	if err = c.validateCredentials(); err != nil {
+		return err
+	}
+
+	if err = probe(s, false); err != nil {
+		return err
+	}
+
+	lock, err := acquireLock(s, "upgrade")
+	if err != nil {
+		return err
+	}
+	defer releaseLock(s, lock)
+
+	return errors.Wrap(tasks.WithUpgrade(nil).Run(s), "failed to upgrade cluster")
+}
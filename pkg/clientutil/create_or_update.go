@@ -22,22 +22,35 @@ import (
 	"github.com/imdario/mergo"
 	"github.com/pkg/errors"
 
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/manifestpatch"
+
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-type Updater func(client.Client, client.Object)
+type Updater func(client.Client, client.Object) error
 
 func WithComponentLabel(componentname string) Updater {
-	return func(c client.Client, obj client.Object) {
+	return func(c client.Client, obj client.Object) error {
 		LabelComponent(componentname, obj)
+		return nil
+	}
+}
+
+// WithManifestPatches applies the manifest patches matching the object
+func WithManifestPatches(patches []kubeoneapi.ManifestPatch) Updater {
+	return func(c client.Client, obj client.Object) error {
+		return manifestpatch.ApplyToObject(obj, c.Scheme(), patches)
 	}
 }
 
 // CreateOrUpdate makes it easy to "apply" objects to kubernetes API server
 func CreateOrUpdate(ctx context.Context, c client.Client, obj client.Object, updaters ...Updater) error {
 	for _, update := range updaters {
-		update(c, obj)
+		if err := update(c, obj); err != nil {
+			return err
+		}
 	}
 
 	existing := obj.DeepCopyObject().(client.Object)
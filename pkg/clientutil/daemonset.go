@@ -0,0 +1,72 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientutil
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/client-go/util/retry"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// restartedAtAnnotation is the same annotation used by `kubectl rollout restart`
+	restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+)
+
+// RestartDaemonSet triggers a rolling restart of the DaemonSet pods by
+// bumping the restartedAt annotation in the pod template, the same way as
+// `kubectl rollout restart` does.
+func RestartDaemonSet(ctx context.Context, c dynclient.Client, key dynclient.ObjectKey) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ds := appsv1.DaemonSet{}
+		if err := c.Get(ctx, key, &ds); err != nil {
+			return errors.Wrapf(err, "failed to get DaemonSet %s", key)
+		}
+
+		if ds.Spec.Template.Annotations == nil {
+			ds.Spec.Template.Annotations = map[string]string{}
+		}
+		ds.Spec.Template.Annotations[restartedAtAnnotation] = time.Now().Format(time.RFC3339)
+
+		return c.Update(ctx, &ds)
+	})
+}
+
+// DaemonSetRolledOutCondition generate a k8s.io/apimachinery/pkg/util/wait.ConditionFunc function to be used in
+// k8s.io/apimachinery/pkg/util/wait.Poll* family of functions. It will check that the latest generation of the
+// DaemonSet is observed and that all scheduled pods are updated and available.
+func DaemonSetRolledOutCondition(ctx context.Context, c dynclient.Client, key dynclient.ObjectKey) func() (bool, error) {
+	return func() (bool, error) {
+		ds := appsv1.DaemonSet{}
+		if err := c.Get(ctx, key, &ds); err != nil {
+			return false, errors.Wrapf(err, "failed to get DaemonSet %s", key)
+		}
+
+		if ds.Status.ObservedGeneration < ds.Generation {
+			return false, nil
+		}
+
+		desired := ds.Status.DesiredNumberScheduled
+
+		return ds.Status.UpdatedNumberScheduled == desired && ds.Status.NumberAvailable == desired, nil
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientutil
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/client-go/util/retry"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RestartDeployment triggers a rolling restart of the Deployment pods by
+// bumping the restartedAt annotation in the pod template, the same way as
+// `kubectl rollout restart` does.
+func RestartDeployment(ctx context.Context, c dynclient.Client, key dynclient.ObjectKey) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		deploy := appsv1.Deployment{}
+		if err := c.Get(ctx, key, &deploy); err != nil {
+			return errors.Wrapf(err, "failed to get Deployment %s", key)
+		}
+
+		if deploy.Spec.Template.Annotations == nil {
+			deploy.Spec.Template.Annotations = map[string]string{}
+		}
+		deploy.Spec.Template.Annotations[restartedAtAnnotation] = time.Now().Format(time.RFC3339)
+
+		return c.Update(ctx, &deploy)
+	})
+}
+
+// DeploymentRolledOutCondition generate a k8s.io/apimachinery/pkg/util/wait.ConditionFunc function to be used in
+// k8s.io/apimachinery/pkg/util/wait.Poll* family of functions. It will check that the latest generation of the
+// Deployment is observed and that all replicas are updated and available.
+func DeploymentRolledOutCondition(ctx context.Context, c dynclient.Client, key dynclient.ObjectKey) func() (bool, error) {
+	return func() (bool, error) {
+		deploy := appsv1.Deployment{}
+		if err := c.Get(ctx, key, &deploy); err != nil {
+			return false, errors.Wrapf(err, "failed to get Deployment %s", key)
+		}
+
+		if deploy.Status.ObservedGeneration < deploy.Generation {
+			return false, nil
+		}
+
+		desired := int32(1)
+		if deploy.Spec.Replicas != nil {
+			desired = *deploy.Spec.Replicas
+		}
+
+		return deploy.Status.UpdatedReplicas == desired &&
+			deploy.Status.AvailableReplicas == desired &&
+			deploy.Status.Replicas == desired, nil
+	}
+}
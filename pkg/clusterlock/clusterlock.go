@@ -0,0 +1,308 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clusterlock prevents running multiple KubeOne operations, such as
+// apply, upgrade and reset, against the same cluster at the same time.
+//
+// The lock is held in the state store, if configured, and in the Lease
+// object in the target cluster, once the cluster is provisioned. The holder
+// renews the lock while the operation is running, so the lock left behind by
+// the crashed or killed process becomes stale after the TTL and is taken over
+// by the next operation.
+package clusterlock
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/user"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"k8c.io/kubeone/pkg/state"
+)
+
+const (
+	// DefaultTTL is how long the lock is valid after it was last renewed
+	DefaultTTL = 2 * time.Minute
+)
+
+// errConflict is returned by the backend if the lock was changed since it
+// was read
+var errConflict = errors.New("lock was changed concurrently")
+
+// Record describes the holder of the lock
+type Record struct {
+	// Holder identifies the user, the machine and the process holding the lock
+	Holder string `json:"holder"`
+	// Operation is the KubeOne operation running, e.g. apply
+	Operation string `json:"operation"`
+	// AcquiredAt is when the lock was acquired
+	AcquiredAt time.Time `json:"acquiredAt"`
+	// RenewedAt is when the lock was renewed the last time
+	RenewedAt time.Time `json:"renewedAt"`
+	// TTL is how long the lock is valid after it was renewed
+	TTL time.Duration `json:"ttl"`
+}
+
+// Stale returns true if the lock wasn't renewed within its TTL
+func (r *Record) Stale(now time.Time) bool {
+	return now.After(r.RenewedAt.Add(r.TTL))
+}
+
+// LockedError is returned if the lock is held by another operation
+type LockedError struct {
+	Location string
+	Record   Record
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("cluster is locked in %s by %s running %s since %s (last renewed at %s)",
+		e.Location,
+		e.Record.Holder,
+		e.Record.Operation,
+		e.Record.AcquiredAt.Format(time.RFC3339),
+		e.Record.RenewedAt.Format(time.RFC3339))
+}
+
+// backend stores the lock record
+type backend interface {
+	// read returns the current record, nil if the lock isn't held, and the
+	// revision to be passed to write and remove
+	read(ctx context.Context) (*Record, string, error)
+	// write stores the record, or returns errConflict if the lock was
+	// changed since the revision was read
+	write(ctx context.Context, rec *Record, revision string) error
+	// remove removes the lock, if it wasn't changed since the revision was
+	// read
+	remove(ctx context.Context, revision string) error
+	// String returns the location of the lock
+	String() string
+}
+
+// Options are the options of the Lock
+type Options struct {
+	// Cluster is the name of the cluster
+	Cluster string
+	// Operation is the name of the KubeOne operation, e.g. apply
+	Operation string
+	// Force takes over the lock held by another operation
+	Force bool
+	// TTL is how long the lock is valid after it was renewed, DefaultTTL
+	// if not set
+	TTL time.Duration
+	// Logger is used to log taking over and renewing the lock
+	Logger logrus.FieldLogger
+}
+
+// Lock is the cluster lock held by the single KubeOne operation, in one or
+// more backends
+type Lock struct {
+	opts   Options
+	holder string
+
+	lock     sync.Mutex
+	backends map[string]backend
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// New returns the lock for the operation, which isn't acquired yet
+func New(opts Options) *Lock {
+	if opts.TTL == 0 {
+		opts.TTL = DefaultTTL
+	}
+	if opts.Logger == nil {
+		opts.Logger = logrus.StandardLogger()
+	}
+
+	return &Lock{
+		opts:     opts,
+		holder:   holderIdentity(),
+		backends: map[string]backend{},
+	}
+}
+
+// AcquireState acquires the lock in the state store, if it's configured,
+// and in the cluster, if the Kubernetes client is initialized. It's safe to
+// call it again after the Kubernetes client is initialized, the lock held
+// already is kept as it is.
+func (l *Lock) AcquireState(s *state.State) error {
+	if s.StateStore != nil {
+		if err := l.acquire(s.Context, newStoreBackend(s.StateStore, l.opts.Cluster)); err != nil {
+			return err
+		}
+	}
+
+	if s.DynamicClient != nil {
+		if err := l.acquire(s.Context, newLeaseBackend(s.DynamicClient)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *Lock) acquire(ctx context.Context, b backend) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if _, ok := l.backends[b.String()]; ok {
+		return nil
+	}
+
+	for {
+		rec, revision, err := b.read(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read the lock in %s", b)
+		}
+
+		now := time.Now()
+		if rec != nil && rec.Holder != l.holder {
+			switch {
+			case l.opts.Force:
+				l.opts.Logger.Warnf("Forcefully taking over the lock in %s held by %s running %s", b, rec.Holder, rec.Operation)
+			case rec.Stale(now):
+				l.opts.Logger.Warnf("Taking over the stale lock in %s held by %s running %s, last renewed at %s", b, rec.Holder, rec.Operation, rec.RenewedAt.Format(time.RFC3339))
+			default:
+				return &LockedError{Location: b.String(), Record: *rec}
+			}
+		}
+
+		err = b.write(ctx, &Record{
+			Holder:     l.holder,
+			Operation:  l.opts.Operation,
+			AcquiredAt: now,
+			RenewedAt:  now,
+			TTL:        l.opts.TTL,
+		}, revision)
+		if errors.Is(err, errConflict) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to acquire the lock in %s", b)
+		}
+
+		break
+	}
+
+	l.opts.Logger.Debugf("Acquired the lock in %s", b)
+	l.backends[b.String()] = b
+	if l.cancel == nil {
+		l.startRenewing()
+	}
+
+	return nil
+}
+
+// startRenewing renews the held locks until the Lock is released
+func (l *Lock) startRenewing() {
+	ctx, cancel := context.WithCancel(context.Background())
+	l.cancel = cancel
+
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+
+		ticker := time.NewTicker(l.opts.TTL / 4)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				l.renew(ctx)
+			}
+		}
+	}()
+}
+
+func (l *Lock) renew(ctx context.Context) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for _, b := range l.backends {
+		rec, revision, err := b.read(ctx)
+		if err != nil {
+			l.opts.Logger.Warnf("Failed to renew the lock in %s: %v", b, err)
+			continue
+		}
+		if rec == nil || rec.Holder != l.holder {
+			l.opts.Logger.Errorf("The lock in %s was taken over by another operation", b)
+			continue
+		}
+
+		rec.RenewedAt = time.Now()
+		if err = b.write(ctx, rec, revision); err != nil {
+			l.opts.Logger.Warnf("Failed to renew the lock in %s: %v", b, err)
+		}
+	}
+}
+
+// Release stops renewing and removes all held locks, unless they were taken
+// over by another operation
+func (l *Lock) Release(ctx context.Context) error {
+	l.lock.Lock()
+	if l.cancel != nil {
+		l.cancel()
+	}
+	l.lock.Unlock()
+	l.wg.Wait()
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	var lastErr error
+	for key, b := range l.backends {
+		delete(l.backends, key)
+
+		rec, revision, err := b.read(ctx)
+		if err != nil {
+			lastErr = errors.Wrapf(err, "failed to release the lock in %s", b)
+			continue
+		}
+		if rec == nil || rec.Holder != l.holder {
+			continue
+		}
+
+		if err = b.remove(ctx, revision); err != nil {
+			lastErr = errors.Wrapf(err, "failed to release the lock in %s", b)
+		}
+	}
+	l.cancel = nil
+
+	return lastErr
+}
+
+// holderIdentity returns the identity of this process, unique across the
+// machines and the processes
+func holderIdentity() string {
+	username := "unknown"
+	if u, err := user.Current(); err == nil {
+		username = u.Username
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return fmt.Sprintf("%s@%s (pid %d)", username, hostname, os.Getpid())
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterlock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"k8c.io/kubeone/pkg/state"
+	"k8c.io/kubeone/pkg/statestore"
+
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestLock(holder string, force bool) *Lock {
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+
+	l := New(Options{
+		Cluster:   "test",
+		Operation: "apply",
+		Force:     force,
+		Logger:    logger,
+	})
+	l.holder = holder
+
+	return l
+}
+
+func newTestState(t *testing.T) *state.State {
+	store, err := statestore.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &state.State{
+		Context:       context.Background(),
+		StateStore:    store,
+		DynamicClient: fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
+	}
+}
+
+func TestLock(t *testing.T) {
+	s := newTestState(t)
+	ctx := context.Background()
+
+	first := newTestLock("first", false)
+	if err := first.AcquireState(s); err != nil {
+		t.Fatalf("AcquireState() failed: %v", err)
+	}
+	// Acquiring the held lock again is no-op
+	if err := first.AcquireState(s); err != nil {
+		t.Fatalf("AcquireState() of the held lock failed: %v", err)
+	}
+
+	second := newTestLock("second", false)
+	err := second.AcquireState(s)
+	var locked *LockedError
+	if !errors.As(err, &locked) {
+		t.Fatalf("AcquireState() of the lock held by another operation error = %v, want LockedError", err)
+	}
+	if locked.Record.Holder != "first" || locked.Record.Operation != "apply" {
+		t.Errorf("LockedError record = %+v, want the first holder", locked.Record)
+	}
+
+	if err = first.Release(ctx); err != nil {
+		t.Fatalf("Release() failed: %v", err)
+	}
+
+	if err = second.AcquireState(s); err != nil {
+		t.Fatalf("AcquireState() of the released lock failed: %v", err)
+	}
+	if err = second.Release(ctx); err != nil {
+		t.Fatalf("Release() failed: %v", err)
+	}
+
+	for _, b := range []backend{newStoreBackend(s.StateStore, "test"), newLeaseBackend(s.DynamicClient)} {
+		rec, _, err := b.read(ctx)
+		if err != nil {
+			t.Fatalf("reading the lock in %s failed: %v", b, err)
+		}
+		if rec != nil {
+			t.Errorf("lock in %s is held by %q after it was released", b, rec.Holder)
+		}
+	}
+}
+
+func TestLockTakeOver(t *testing.T) {
+	tests := []struct {
+		name      string
+		renewedAt time.Time
+		force     bool
+		wantErr   bool
+	}{
+		{
+			name:      "held lock",
+			renewedAt: time.Now(),
+			wantErr:   true,
+		},
+		{
+			name:      "stale lock",
+			renewedAt: time.Now().Add(-2 * DefaultTTL),
+		},
+		{
+			name:      "forced take over",
+			renewedAt: time.Now(),
+			force:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestState(t)
+			ctx := context.Background()
+
+			backends := []backend{newStoreBackend(s.StateStore, "test"), newLeaseBackend(s.DynamicClient)}
+			for _, b := range backends {
+				err := b.write(ctx, &Record{
+					Holder:     "crashed",
+					Operation:  "upgrade",
+					AcquiredAt: tt.renewedAt,
+					RenewedAt:  tt.renewedAt,
+					TTL:        DefaultTTL,
+				}, "")
+				if err != nil {
+					t.Fatalf("writing the lock in %s failed: %v", b, err)
+				}
+			}
+
+			l := newTestLock("new", tt.force)
+			defer func() { _ = l.Release(ctx) }()
+
+			err := l.AcquireState(s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AcquireState() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			for _, b := range backends {
+				rec, _, err := b.read(ctx)
+				if err != nil {
+					t.Fatalf("reading the lock in %s failed: %v", b, err)
+				}
+				if rec == nil || rec.Holder != "new" {
+					t.Errorf("lock in %s = %+v, want held by %q", b, rec, "new")
+				}
+			}
+		})
+	}
+}
+
+func TestStoreBackendConflict(t *testing.T) {
+	store, err := statestore.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	b := newStoreBackend(store, "test")
+
+	_, revision, err := b.read(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = b.write(ctx, &Record{Holder: "first"}, revision); err != nil {
+		t.Fatalf("write() failed: %v", err)
+	}
+
+	// The lock was changed since the revision was read
+	if err = b.write(ctx, &Record{Holder: "second"}, revision); !errors.Is(err, errConflict) {
+		t.Errorf("write() with outdated revision error = %v, want errConflict", err)
+	}
+}
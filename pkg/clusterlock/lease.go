@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterlock
+
+import (
+	"context"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	leaseNamespace = metav1.NamespaceSystem
+	leaseName      = "kubeone-lock"
+
+	operationAnnotation = "kubeone.io/operation"
+)
+
+// leaseBackend keeps the lock in the Lease object in the target cluster,
+// using its resourceVersion for the atomic updates
+type leaseBackend struct {
+	client dynclient.Client
+}
+
+func newLeaseBackend(c dynclient.Client) *leaseBackend {
+	return &leaseBackend{client: c}
+}
+
+func (b *leaseBackend) read(ctx context.Context) (*Record, string, error) {
+	lease := coordinationv1.Lease{}
+	err := b.client.Get(ctx, dynclient.ObjectKey{Namespace: leaseNamespace, Name: leaseName}, &lease)
+	if k8serrors.IsNotFound(err) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity == "" {
+		return nil, lease.ResourceVersion, nil
+	}
+
+	rec := &Record{
+		Holder:    *lease.Spec.HolderIdentity,
+		Operation: lease.Annotations[operationAnnotation],
+	}
+	if lease.Spec.AcquireTime != nil {
+		rec.AcquiredAt = lease.Spec.AcquireTime.Time
+	}
+	if lease.Spec.RenewTime != nil {
+		rec.RenewedAt = lease.Spec.RenewTime.Time
+	}
+	if lease.Spec.LeaseDurationSeconds != nil {
+		rec.TTL = time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+	}
+
+	return rec, lease.ResourceVersion, nil
+}
+
+func (b *leaseBackend) write(ctx context.Context, rec *Record, revision string) error {
+	ttl := int32(rec.TTL.Seconds())
+	lease := coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       leaseNamespace,
+			Name:            leaseName,
+			ResourceVersion: revision,
+			Annotations: map[string]string{
+				operationAnnotation: rec.Operation,
+			},
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &rec.Holder,
+			LeaseDurationSeconds: &ttl,
+			AcquireTime:          &metav1.MicroTime{Time: rec.AcquiredAt},
+			RenewTime:            &metav1.MicroTime{Time: rec.RenewedAt},
+		},
+	}
+
+	var err error
+	if revision == "" {
+		err = b.client.Create(ctx, &lease)
+	} else {
+		err = b.client.Update(ctx, &lease)
+	}
+	if k8serrors.IsConflict(err) || k8serrors.IsAlreadyExists(err) {
+		return errConflict
+	}
+
+	return err
+}
+
+func (b *leaseBackend) remove(ctx context.Context, revision string) error {
+	lease := coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: leaseNamespace,
+			Name:      leaseName,
+		},
+	}
+
+	err := b.client.Delete(ctx, &lease, dynclient.Preconditions{ResourceVersion: &revision})
+	if k8serrors.IsConflict(err) {
+		return errConflict
+	}
+
+	return dynclient.IgnoreNotFound(err)
+}
+
+func (b *leaseBackend) String() string {
+	return "Lease " + leaseNamespace + "/" + leaseName
+}
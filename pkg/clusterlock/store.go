@@ -0,0 +1,100 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterlock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/statestore"
+)
+
+// storeBackend keeps the lock as the artifact in the state store. The state
+// stores don't support atomic updates, so the lock is checked right before
+// it's written, which narrows, but doesn't close, the race window.
+type storeBackend struct {
+	store statestore.Store
+	name  string
+}
+
+func newStoreBackend(store statestore.Store, cluster string) *storeBackend {
+	return &storeBackend{
+		store: store,
+		name:  cluster + "-lock",
+	}
+}
+
+func (b *storeBackend) read(ctx context.Context) (*Record, string, error) {
+	data, err := b.store.Get(ctx, b.name)
+	if errors.Is(err, statestore.ErrNotFound) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	rec := &Record{}
+	if err = json.Unmarshal(data, rec); err != nil {
+		return nil, "", errors.Wrap(err, "failed to decode the lock")
+	}
+
+	return rec, string(data), nil
+}
+
+func (b *storeBackend) write(ctx context.Context, rec *Record, revision string) error {
+	if err := b.checkRevision(ctx, revision); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return b.store.Put(ctx, b.name, data)
+}
+
+func (b *storeBackend) remove(ctx context.Context, revision string) error {
+	if err := b.checkRevision(ctx, revision); err != nil {
+		return err
+	}
+
+	return b.store.Delete(ctx, b.name)
+}
+
+func (b *storeBackend) checkRevision(ctx context.Context, revision string) error {
+	data, err := b.store.Get(ctx, b.name)
+	if errors.Is(err, statestore.ErrNotFound) {
+		data, err = nil, nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(data, []byte(revision)) {
+		return errConflict
+	}
+
+	return nil
+}
+
+func (b *storeBackend) String() string {
+	return b.store.String() + "/" + b.name
+}
@@ -32,30 +32,62 @@ const (
 	LabelUpgradeLock      = "kubeone.io/upgrade-in-progress"
 )
 
-// Run ensures that all prerequisites are satisfied
-// TODO(xmudrii): Implement mechanism for skipping checks.
+// Run ensures that all prerequisites are satisfied. Checks can be skipped or
+// made warning-only in the manifest.
 func Run(s *state.State, nodes corev1.NodeList) error {
 	var errs []error
 
-	// Verify that list of nodes match with the provided manifest
-	s.Logger.Infoln("Verifying that nodes in the cluster match nodes defined in the manifest...")
-	if err := verifyMatchNodes(s.Cluster.ControlPlane.Hosts, nodes, s.Logger, s.Verbose); err != nil {
-		s.Logger.Errorln("Unable to match all control plane nodes in the cluster and all nodes defined in the manifest.")
-		errs = append(errs, err...)
+	checks := []struct {
+		name        string
+		description string
+		verify      func() []error
+		errMsg      string
+	}{
+		{
+			name:        kubeoneapi.PreflightCheckNodesMatch,
+			description: "Verifying that nodes in the cluster match nodes defined in the manifest...",
+			verify: func() []error {
+				return verifyMatchNodes(s.Cluster.ControlPlane.Hosts, nodes, s.Logger, s.Verbose)
+			},
+			errMsg: "Unable to match all control plane nodes in the cluster and all nodes defined in the manifest.",
+		},
+		{
+			name:        kubeoneapi.PreflightCheckNodesReady,
+			description: "Verifying that all nodes in the cluster are ready...",
+			verify: func() []error {
+				return verifyNodesReady(nodes, s.Logger, s.Verbose)
+			},
+			errMsg: "Unable to verify that all nodes in the cluster are ready.",
+		},
+		{
+			name:        kubeoneapi.PreflightCheckUpgradeInProgress,
+			description: "Verifying that there is no upgrade in progress...",
+			verify: func() []error {
+				return verifyNoUpgradeLabels(nodes, s.Logger, s.Verbose)
+			},
+			errMsg: "Unable to verify that there is no upgrade in progress.",
+		},
 	}
 
-	// Verify that all nodes in the cluster are ready
-	s.Logger.Infoln("Verifying that all nodes in the cluster are ready...")
-	if err := verifyNodesReady(nodes, s.Logger, s.Verbose); err != nil {
-		s.Logger.Errorln("Unable to match all control plane nodes in the cluster and all nodes defined in the manifest.")
-		errs = append(errs, err...)
-	}
+	for _, check := range checks {
+		if s.Cluster.PreflightSkipped(check.name) {
+			s.Logger.Warnf("Skipping the %s preflight check.", check.name)
+			continue
+		}
+
+		s.Logger.Infoln(check.description)
+		checkErrs := check.verify()
+		if len(checkErrs) == 0 {
+			continue
+		}
+
+		if s.Cluster.PreflightWarnOnly(check.name) {
+			s.Logger.Warnf("The %s preflight check failed: %v", check.name, utilerrors.NewAggregate(checkErrs))
+			continue
+		}
 
-	// Verify that upgrade is not in progress
-	s.Logger.Infoln("Verifying that there is no upgrade in progress...")
-	if err := verifyNoUpgradeLabels(nodes, s.Logger, s.Verbose); err != nil {
-		s.Logger.Errorf("Unable to verify that there is no upgrade in progress.")
-		errs = append(errs, err...)
+		s.Logger.Errorln(check.errMsg)
+		errs = append(errs, checkErrs...)
 	}
 
 	return utilerrors.NewAggregate(errs)
@@ -0,0 +1,276 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterstatus
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/clusterstatus/apiserverstatus"
+	"k8c.io/kubeone/pkg/clusterstatus/etcdstatus"
+	"k8c.io/kubeone/pkg/state"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	RoleControlPlane = "control-plane"
+	RoleStaticWorker = "static-worker"
+)
+
+// Report is the machine-readable status of the cluster
+type Report struct {
+	Name              string           `json:"name"`
+	KubernetesVersion string           `json:"kubernetesVersion"`
+	Hosts             []HostReport     `json:"hosts"`
+	CNI               *WorkloadReport  `json:"cni,omitempty"`
+	MachineController *WorkloadReport  `json:"machineController,omitempty"`
+	Workloads         []WorkloadReport `json:"workloads"`
+}
+
+// HostReport describes status of a single host
+type HostReport struct {
+	Name           string           `json:"name"`
+	PublicAddress  string           `json:"publicAddress"`
+	PrivateAddress string           `json:"privateAddress"`
+	Role           string           `json:"role"`
+	KubeletVersion string           `json:"kubeletVersion,omitempty"`
+	APIServer      *ComponentReport `json:"apiServer,omitempty"`
+	Etcd           *ComponentReport `json:"etcd,omitempty"`
+	// CertificatesExpiry is when the first of the control plane certificates expires
+	CertificatesExpiry *metav1.Time `json:"certificatesExpiry,omitempty"`
+}
+
+// ComponentReport describes status of a control plane component on a host
+type ComponentReport struct {
+	Version string `json:"version,omitempty"`
+	Healthy bool   `json:"healthy"`
+}
+
+// WorkloadReport describes status of a Deployment or a DaemonSet in the
+// kube-system namespace, such as addons, CNI and machine-controller
+type WorkloadReport struct {
+	Kind     string   `json:"kind"`
+	Name     string   `json:"name"`
+	Images   []string `json:"images"`
+	Versions []string `json:"versions"`
+	Desired  int32    `json:"desired"`
+	Ready    int32    `json:"ready"`
+	Healthy  bool     `json:"healthy"`
+}
+
+// GetReport returns the status of the cluster. Probes must be run and the
+// Kubernetes client must be initialized before calling it.
+func GetReport(s *state.State) (*Report, error) {
+	if s.DynamicClient == nil {
+		return nil, errors.New("kubernetes client not initialized")
+	}
+
+	report := &Report{
+		Name:              s.Cluster.Name,
+		KubernetesVersion: s.Cluster.Versions.Kubernetes,
+	}
+
+	componentVersions, err := controlPlaneVersions(s)
+	if err != nil {
+		return nil, err
+	}
+
+	etcdRing, err := etcdstatus.MemberList(s)
+	if err != nil {
+		s.Logger.Warnf("Failed to get etcd ring: %v", err)
+	}
+
+	for _, host := range s.LiveCluster.ControlPlane {
+		hostReport := newHostReport(host, RoleControlPlane)
+
+		apiserverStatus, err := apiserverstatus.Get(s, *host.Config)
+		if err != nil {
+			s.Logger.Warnf("Failed to get API server status on %q: %v", host.Config.Hostname, err)
+		}
+		hostReport.APIServer = &ComponentReport{
+			Version: componentVersions[host.Config.Hostname]["kube-apiserver"],
+			Healthy: apiserverStatus != nil && apiserverStatus.Health,
+		}
+
+		etcdHealthy := false
+		if etcdRing != nil {
+			etcdStatus, err := etcdstatus.Get(s, *host.Config, etcdRing)
+			if err != nil {
+				s.Logger.Warnf("Failed to get etcd status on %q: %v", host.Config.Hostname, err)
+			}
+			etcdHealthy = etcdStatus != nil && etcdStatus.Health && etcdStatus.Member
+		}
+		hostReport.Etcd = &ComponentReport{
+			Version: componentVersions[host.Config.Hostname]["etcd"],
+			Healthy: etcdHealthy,
+		}
+
+		if !host.EarliestCertExpiry.IsZero() {
+			expiry := metav1.NewTime(host.EarliestCertExpiry)
+			hostReport.CertificatesExpiry = &expiry
+		}
+
+		report.Hosts = append(report.Hosts, hostReport)
+	}
+
+	for _, host := range s.LiveCluster.StaticWorkers {
+		report.Hosts = append(report.Hosts, newHostReport(host, RoleStaticWorker))
+	}
+
+	report.Workloads, err = workloadReports(s)
+	if err != nil {
+		return nil, err
+	}
+
+	cniName := cniDaemonSetName(s.Cluster.ClusterNetwork.CNI)
+	for i := range report.Workloads {
+		workload := report.Workloads[i]
+		switch {
+		case workload.Kind == "DaemonSet" && workload.Name == cniName:
+			report.CNI = &workload
+		case workload.Kind == "Deployment" && workload.Name == "machine-controller":
+			report.MachineController = &workload
+		}
+	}
+
+	return report, nil
+}
+
+func newHostReport(host state.Host, role string) HostReport {
+	hostReport := HostReport{
+		Name:           host.Config.Hostname,
+		PublicAddress:  host.Config.PublicAddress,
+		PrivateAddress: host.Config.PrivateAddress,
+		Role:           role,
+	}
+
+	if host.Kubelet.Version != nil {
+		hostReport.KubeletVersion = host.Kubelet.Version.String()
+	}
+
+	return hostReport
+}
+
+// controlPlaneVersions returns versions of the control plane components on
+// each node, based on images used by the static pods
+func controlPlaneVersions(s *state.State) (map[string]map[string]string, error) {
+	pods := corev1.PodList{}
+	listOpts := []dynclient.ListOption{
+		dynclient.InNamespace(metav1.NamespaceSystem),
+		dynclient.MatchingLabels{"tier": "control-plane"},
+	}
+	if err := s.DynamicClient.List(s.Context, &pods, listOpts...); err != nil {
+		return nil, errors.Wrap(err, "failed to list control plane pods")
+	}
+
+	versions := map[string]map[string]string{}
+	for _, pod := range pods.Items {
+		if len(pod.Spec.Containers) == 0 {
+			continue
+		}
+		if versions[pod.Spec.NodeName] == nil {
+			versions[pod.Spec.NodeName] = map[string]string{}
+		}
+		versions[pod.Spec.NodeName][pod.Labels["component"]] = imageTag(pod.Spec.Containers[0].Image)
+	}
+
+	return versions, nil
+}
+
+// workloadReports returns status of all Deployments and DaemonSets in the
+// kube-system namespace
+func workloadReports(s *state.State) ([]WorkloadReport, error) {
+	reports := []WorkloadReport{}
+
+	deployments := appsv1.DeploymentList{}
+	if err := s.DynamicClient.List(s.Context, &deployments, dynclient.InNamespace(metav1.NamespaceSystem)); err != nil {
+		return nil, errors.Wrap(err, "failed to list deployments")
+	}
+
+	for _, deploy := range deployments.Items {
+		desired := int32(1)
+		if deploy.Spec.Replicas != nil {
+			desired = *deploy.Spec.Replicas
+		}
+
+		reports = append(reports, newWorkloadReport("Deployment", deploy.Name, deploy.Spec.Template.Spec, desired, deploy.Status.ReadyReplicas))
+	}
+
+	daemonSets := appsv1.DaemonSetList{}
+	if err := s.DynamicClient.List(s.Context, &daemonSets, dynclient.InNamespace(metav1.NamespaceSystem)); err != nil {
+		return nil, errors.Wrap(err, "failed to list daemonsets")
+	}
+
+	for _, ds := range daemonSets.Items {
+		reports = append(reports, newWorkloadReport("DaemonSet", ds.Name, ds.Spec.Template.Spec, ds.Status.DesiredNumberScheduled, ds.Status.NumberReady))
+	}
+
+	return reports, nil
+}
+
+func newWorkloadReport(kind, name string, podSpec corev1.PodSpec, desired, ready int32) WorkloadReport {
+	report := WorkloadReport{
+		Kind:     kind,
+		Name:     name,
+		Images:   []string{},
+		Versions: []string{},
+		Desired:  desired,
+		Ready:    ready,
+		Healthy:  ready >= desired,
+	}
+
+	for _, container := range podSpec.Containers {
+		report.Images = append(report.Images, container.Image)
+		report.Versions = append(report.Versions, imageTag(container.Image))
+	}
+
+	return report
+}
+
+func cniDaemonSetName(cni *kubeoneapi.CNI) string {
+	switch {
+	case cni == nil:
+		return ""
+	case cni.Canal != nil:
+		return "canal"
+	case cni.Cilium != nil:
+		return "cilium"
+	case cni.WeaveNet != nil:
+		return "weave-net"
+	}
+
+	return ""
+}
+
+// imageTag returns the tag of the given image, or an empty string if the
+// image has no tag
+func imageTag(image string) string {
+	image = strings.SplitN(image, "@", 2)[0]
+
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") {
+		return ""
+	}
+
+	return image[i+1:]
+}
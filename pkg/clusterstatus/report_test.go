@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterstatus
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"k8c.io/kubeone/pkg/state"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestImageTag(t *testing.T) {
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{image: "k8s.gcr.io/kube-apiserver:v1.22.2", want: "v1.22.2"},
+		{image: "registry.example.com:5000/etcd:3.5.0-0", want: "3.5.0-0"},
+		{image: "registry.example.com:5000/etcd", want: ""},
+		{image: "quay.io/cilium/cilium:v1.10.4@sha256:abcd", want: "v1.10.4"},
+		{image: "busybox", want: ""},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.image, func(t *testing.T) {
+			if got := imageTag(tt.image); got != tt.want {
+				t.Errorf("imageTag() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkloadReports(t *testing.T) {
+	replicas := int32(2)
+	podSpec := func(image string) corev1.PodTemplateSpec {
+		return corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "main", Image: image}}},
+		}
+	}
+
+	s := &state.State{
+		Context: context.Background(),
+		DynamicClient: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "coredns", Namespace: metav1.NamespaceSystem},
+				Spec:       appsv1.DeploymentSpec{Replicas: &replicas, Template: podSpec("k8s.gcr.io/coredns/coredns:v1.8.4")},
+				Status:     appsv1.DeploymentStatus{ReadyReplicas: 1},
+			},
+			&appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "canal", Namespace: metav1.NamespaceSystem},
+				Spec:       appsv1.DaemonSetSpec{Template: podSpec("quay.io/calico/node:v3.19.1")},
+				Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 3},
+			},
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: metav1.NamespaceDefault},
+				Spec:       appsv1.DeploymentSpec{Template: podSpec("app:v1")},
+			},
+		).Build(),
+	}
+
+	got, err := workloadReports(s)
+	if err != nil {
+		t.Fatalf("workloadReports() error = %v", err)
+	}
+
+	want := []WorkloadReport{
+		{
+			Kind:     "Deployment",
+			Name:     "coredns",
+			Images:   []string{"k8s.gcr.io/coredns/coredns:v1.8.4"},
+			Versions: []string{"v1.8.4"},
+			Desired:  2,
+			Ready:    1,
+			Healthy:  false,
+		},
+		{
+			Kind:     "DaemonSet",
+			Name:     "canal",
+			Images:   []string{"quay.io/calico/node:v3.19.1"},
+			Versions: []string{"v3.19.1"},
+			Desired:  3,
+			Ready:    3,
+			Healthy:  true,
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("workloadReports() = %+v, want %+v", got, want)
+	}
+}
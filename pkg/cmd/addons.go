@@ -0,0 +1,155 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8c.io/kubeone/pkg/addons"
+	"k8c.io/kubeone/pkg/tabwriter"
+
+	kyaml "sigs.k8s.io/yaml"
+)
+
+type addonsLintOpts struct {
+	globalOptions
+	RequiredLabels []string `longflag:"required-label"`
+	Output         string   `longflag:"output" shortflag:"o"`
+}
+
+func addonsCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "addons",
+		Short: "Commands for working with addons",
+	}
+
+	cmd.AddCommand(addonsLintCmd(rootFlags))
+	return cmd
+}
+
+func addonsLintCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	opts := &addonsLintOpts{}
+
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Render the addons and report problems",
+		Long: heredoc.Doc(`
+			Render all addons configured in the KubeOneCluster manifest, the same way as 'kubeone apply'
+			does, and report the following problems:
+			  * templates that can't be parsed or rendered
+			  * params used by the templates, but not provided in the manifest (params used only
+			    with the 'default' function, or in 'if' and 'with' conditions, are optional)
+			  * rendered manifests that aren't valid Kubernetes objects
+			  * objects using API versions deprecated or removed in the configured Kubernetes version
+			  * objects without the labels required by '--required-label'
+
+			The cluster is not accessed, so this command can be used in CI pipelines. With '--output json'
+			or '--output yaml', the results are printed in a machine-readable format. The command exits
+			with a non-zero exit code if any error is found.
+		`),
+		Example: `kubeone addons lint -m mycluster.yaml --required-label app.kubernetes.io/part-of -o json`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			gopts, err := persistentGlobalOptions(rootFlags)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			opts.globalOptions = *gopts
+
+			return runAddonsLint(opts)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(
+		&opts.RequiredLabels,
+		longFlagName(opts, "RequiredLabels"),
+		[]string{},
+		"label that all objects deployed by the addons must have (can be repeated)")
+
+	cmd.Flags().StringVarP(
+		&opts.Output,
+		longFlagName(opts, "Output"),
+		shortFlagName(opts, "Output"),
+		statusOutputTable,
+		fmt.Sprintf("output format, one of: %s, %s, %s", statusOutputTable, statusOutputJSON, statusOutputYAML))
+
+	return cmd
+}
+
+func runAddonsLint(opts *addonsLintOpts) error {
+	switch opts.Output {
+	case statusOutputTable, statusOutputJSON, statusOutputYAML:
+	default:
+		return errors.Errorf("unknown output format %q", opts.Output)
+	}
+
+	s, err := opts.BuildState()
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize State")
+	}
+
+	results, err := addons.Lint(s, addons.LintOptions{RequiredLabels: opts.RequiredLabels})
+	if err != nil {
+		return errors.Wrap(err, "failed to lint addons")
+	}
+	if results == nil {
+		results = []addons.LintResult{}
+	}
+
+	switch opts.Output {
+	case statusOutputJSON:
+		out, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal lint results")
+		}
+		fmt.Println(string(out))
+	case statusOutputYAML:
+		out, err := kyaml.Marshal(results)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal lint results")
+		}
+		fmt.Print(string(out))
+	default:
+		printer := tabwriter.GetNewTabWriter(os.Stdout)
+		fmt.Fprintln(printer, "SEVERITY\tRULE\tADDON\tFILE\tOBJECT\tMESSAGE")
+		for _, result := range results {
+			fmt.Fprintf(printer, "%s\t%s\t%s\t%s\t%s\t%s\n", result.Severity, result.Rule, result.Addon, result.File, result.Object, result.Message)
+		}
+		if err = printer.Flush(); err != nil {
+			return err
+		}
+	}
+
+	errorsFound := 0
+	for _, result := range results {
+		if result.Severity == addons.LintSeverityError {
+			errorsFound++
+		}
+	}
+	if errorsFound > 0 {
+		return errors.Errorf("found %d error(s) in addons", errorsFound)
+	}
+
+	return nil
+}
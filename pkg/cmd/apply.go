@@ -18,9 +18,13 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
-	"reflect"
+	"strings"
+	"time"
 
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/Masterminds/semver/v3"
@@ -28,11 +32,15 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
 	"k8c.io/kubeone/pkg/credentials"
+	"k8c.io/kubeone/pkg/nodehealth"
 	"k8c.io/kubeone/pkg/state"
 	"k8c.io/kubeone/pkg/tasks"
+	"k8c.io/kubeone/pkg/templates/machinecontroller"
+	"k8c.io/kubeone/pkg/upgradeplan"
 
-	apiserverconfigv1 "k8s.io/apiserver/pkg/apis/config/v1"
+	corev1 "k8s.io/api/core/v1"
 	kyaml "sigs.k8s.io/yaml"
 )
 
@@ -44,12 +52,45 @@ type applyOpts struct {
 	NoInit       bool   `longflag:"no-init"`
 	ForceInstall bool   `longflag:"force-install"`
 	// Upgrade flags
-	ForceUpgrade              bool `longflag:"force-upgrade"`
-	UpgradeMachineDeployments bool `longflag:"upgrade-machine-deployments"`
-	RotateEncryptionKey       bool `longflag:"rotate-encryption-key"`
+	ForceUpgrade              bool          `longflag:"force-upgrade"`
+	UpgradeMachineDeployments bool          `longflag:"upgrade-machine-deployments"`
+	UpgradeSequentially       bool          `longflag:"upgrade-sequentially"`
+	RotateEncryptionKey       bool          `longflag:"rotate-encryption-key"`
+	UpgradeVerifyTimeout      time.Duration `longflag:"upgrade-verify-timeout"`
+	UpgradeProbes             []string      `longflag:"upgrade-probe"`
+	// Canary upgrade flags
+	Canary             bool   `longflag:"canary"`
+	CanaryCheckCommand string `longflag:"canary-check-command"`
+	// Scale-out flags
+	JoinOnly []string `longflag:"join-only"`
+	// Locking flags
+	ForceUnlock bool `longflag:"force-unlock"`
+	// Credentials flags
+	SkipCredentialsCheck bool `longflag:"skip-credentials-check"`
+
+	// output is where the planned actions are printed, os.Stdout if not set
+	output io.Writer
+}
+
+func (opts *applyOpts) out() io.Writer {
+	if opts.output == nil {
+		return os.Stdout
+	}
+
+	return opts.output
 }
 
 func (opts *applyOpts) BuildState() (*state.State, error) {
+	if opts.CanaryCheckCommand != "" && !opts.Canary {
+		return nil, errors.New("--canary-check-command can be used only with --canary")
+	}
+	if opts.Canary && opts.AutoApprove && opts.CanaryCheckCommand == "" {
+		return nil, errors.New("--canary with --auto-approve requires --canary-check-command")
+	}
+	if len(opts.JoinOnly) > 0 && (opts.NoInit || opts.ForceInstall || opts.ForceUpgrade || opts.RotateEncryptionKey || opts.UpgradeSequentially || opts.Canary) {
+		return nil, errors.New("--join-only can't be used along with --no-init, --force-install, --force-upgrade, --rotate-encryption-key, --upgrade-sequentially and --canary")
+	}
+
 	s, err := opts.globalOptions.BuildState()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to build state")
@@ -59,6 +100,17 @@ func (opts *applyOpts) BuildState() (*state.State, error) {
 	s.ForceInstall = opts.ForceInstall
 	s.ForceUpgrade = opts.ForceUpgrade
 	s.UpgradeMachineDeployments = opts.UpgradeMachineDeployments
+	s.UpgradeVerifyTimeout = opts.UpgradeVerifyTimeout
+	s.CanaryUpgrade = opts.Canary
+	s.JoinOnlyHosts = opts.JoinOnly
+	if opts.Canary {
+		s.CanaryCheck = applyCanaryCheck(opts.CanaryCheckCommand)
+	}
+
+	s.UpgradeProbes, err = loadUpgradeProbes(opts.UpgradeProbes)
+	if err != nil {
+		return nil, err
+	}
 
 	if s.BackupFile == "" {
 		fullPath, _ := filepath.Abs(opts.ManifestFile)
@@ -95,6 +147,28 @@ func applyCmd(rootFlags *pflag.FlagSet) *cobra.Command {
 			Reconcile (Install/Upgrade/Repair/Restore) Kubernetes cluster on pre-existing machines. MachineDeployments get
 			initialized but won't get modified by default, see '--upgrade-machine-deployments'.
 
+			kubeadm supports upgrading only one minor version at the time. With the '--upgrade-sequentially' flag, the cluster
+			is upgraded to the latest patch release of each intermediate minor version, one after another, before upgrading it
+			to the requested version. Addons are reconciled after each upgrade.
+
+			After each node is upgraded, KubeOne verifies that the node is ready and, on control plane nodes, that
+			control plane static pods and the etcd member are healthy. Pods given with the '--upgrade-probe' flag are run
+			on the node and must succeed. The upgrade is stopped if the node isn't healthy within '--upgrade-verify-timeout',
+			leaving the node cordoned.
+
+			With the '--canary' flag, the leader control plane node and the first static worker node are upgraded first.
+			Once they're ready, the upgrade continues only after it's confirmed, or after the command given with the
+			'--canary-check-command' flag succeeds. The command is run locally, with the KUBEONE_CANARY_NODES environment
+			variable set to the comma-separated names of the canary nodes.
+
+			Hosts whose machines were recreated with the same address (the Node object exists, but the host is not
+			initialized) are detected during the repair. Their stale Node objects and etcd members are removed and
+			they're joined to the cluster again.
+
+			With the '--join-only' flag, only the given new control plane and static worker nodes are provisioned and
+			joined to the already provisioned cluster. Nodes already in the cluster are not touched, except for creating
+			the bootstrap token on the leader. Nodes are selected by their hostname, public or private address.
+
 			This command takes KubeOne manifest which contains information about hosts and how the cluster should be provisioned.
 			It's possible to source information about hosts from Terraform output, using the '--tfjson' flag.
 		`),
@@ -149,12 +223,60 @@ func applyCmd(rootFlags *pflag.FlagSet) *cobra.Command {
 		false,
 		"upgrade MachineDeployments objects")
 
+	cmd.Flags().BoolVar(
+		&opts.UpgradeSequentially,
+		longFlagName(opts, "UpgradeSequentially"),
+		false,
+		"upgrade through the latest patch release of each intermediate minor version, if the requested version is more than one minor version ahead. MachineDeployments are upgraded on each intermediate version")
+
 	cmd.Flags().BoolVar(
 		&opts.RotateEncryptionKey,
 		longFlagName(opts, "RotateEncryptionKey"),
 		false,
 		"rotate Encryption Provider encryption key")
 
+	cmd.Flags().DurationVar(
+		&opts.UpgradeVerifyTimeout,
+		longFlagName(opts, "UpgradeVerifyTimeout"),
+		nodehealth.DefaultTimeout,
+		"how long each upgraded node is allowed to become healthy, before the upgrade is stopped")
+
+	cmd.Flags().StringSliceVar(
+		&opts.UpgradeProbes,
+		longFlagName(opts, "UpgradeProbes"),
+		nil,
+		"path to a Pod manifest to run on each upgraded node. The upgrade continues only if the Pod succeeds. Can be given multiple times")
+
+	cmd.Flags().BoolVar(
+		&opts.Canary,
+		longFlagName(opts, "Canary"),
+		false,
+		"upgrade the leader control plane node and a single static worker node first, and pause before upgrading the rest of the cluster")
+
+	cmd.Flags().StringVar(
+		&opts.CanaryCheckCommand,
+		longFlagName(opts, "CanaryCheckCommand"),
+		"",
+		"command to run after the canary nodes are upgraded, instead of asking for confirmation. The upgrade continues only if the command succeeds")
+
+	cmd.Flags().StringSliceVar(
+		&opts.JoinOnly,
+		longFlagName(opts, "JoinOnly"),
+		nil,
+		"provision and join only the given new nodes (hostname, public or private address), without reconciling the rest of the cluster. Can be given multiple times")
+
+	cmd.Flags().BoolVar(
+		&opts.ForceUnlock,
+		longFlagName(opts, "ForceUnlock"),
+		false,
+		"take over the cluster lock held by another operation (!dangerous!, use only if the lock is stale)")
+
+	cmd.Flags().BoolVar(
+		&opts.SkipCredentialsCheck,
+		longFlagName(opts, "SkipCredentialsCheck"),
+		false,
+		"don't validate the credentials and their permissions using the cloud provider API before making any changes")
+
 	return cmd
 }
 
@@ -163,13 +285,26 @@ func runApply(opts *applyOpts) error {
 	if err != nil {
 		return errors.Wrap(err, "failed to initialize State")
 	}
+	defer opts.reportProgress(s, "apply")
+
+	lock := newClusterLock(s, "apply", opts.ForceUnlock)
+	defer releaseClusterLock(s, lock)
+	if err = acquireClusterLock(s, lock); err != nil {
+		return err
+	}
 
 	// Validate credentials
-	_, err = credentials.ProviderCredentials(s.Cluster.CloudProvider, opts.CredentialsFile)
+	creds, err := credentials.ProviderCredentials(s.Cluster.CloudProvider, opts.CredentialsFile)
 	if err != nil {
 		return errors.Wrap(err, "failed to validate credentials")
 	}
 
+	if !opts.SkipCredentialsCheck {
+		if err = checkCredentials(s, creds); err != nil {
+			return err
+		}
+	}
+
 	// Probe the cluster for the actual state and the needed tasks.
 	probbing := tasks.WithHostnameOS(nil)
 	probbing = tasks.WithProbesAndSafeguard(probbing)
@@ -178,6 +313,12 @@ func runApply(opts *applyOpts) error {
 		return err
 	}
 
+	// The Kubernetes client is initialized by the probes if the cluster is
+	// provisioned, so the lock is acquired in the cluster as well
+	if err = acquireClusterLock(s, lock); err != nil {
+		return err
+	}
+
 	if s.Verbose {
 		// Print information about hosts collected by probes
 		for _, host := range s.LiveCluster.ControlPlane {
@@ -189,6 +330,10 @@ func runApply(opts *applyOpts) error {
 		}
 	}
 
+	if len(opts.JoinOnly) > 0 {
+		return runApplyJoinOnly(s, opts)
+	}
+
 	// Reconcile the cluster based on the probe status
 	if !s.LiveCluster.IsProvisioned() {
 		return runApplyInstall(s, opts)
@@ -273,42 +418,51 @@ func runApply(opts *applyOpts) error {
 }
 
 func runApplyInstall(s *state.State, opts *applyOpts) error { // Print the expected changes
-	fmt.Println("The following actions will be taken: ")
-	fmt.Println("Run with --verbose flag for more information.")
+	fmt.Fprintln(opts.out(), "The following actions will be taken: ")
+	fmt.Fprintln(opts.out(), "Run with --verbose flag for more information.")
+
+	for _, node := range s.LiveCluster.ReplacedHosts() {
+		fmt.Fprintf(opts.out(), "\t! host %q (%s) was recreated, its Node object and etcd member will be replaced\n", node.Config.Hostname, node.Config.PrivateAddress)
+	}
 
 	for _, node := range s.LiveCluster.ControlPlane {
 		if !node.IsInCluster {
 			if node.Config.IsLeader {
-				fmt.Printf("\t+ initialize control plane node %q (%s) using %s\n", node.Config.Hostname, node.Config.PrivateAddress, s.Cluster.Versions.Kubernetes)
+				fmt.Fprintf(opts.out(), "\t+ initialize control plane node %q (%s) using %s\n", node.Config.Hostname, node.Config.PrivateAddress, s.Cluster.Versions.Kubernetes)
 			} else {
-				fmt.Printf("\t+ join control plane node %q (%s) using %s\n", node.Config.Hostname, node.Config.PrivateAddress, s.Cluster.Versions.Kubernetes)
+				fmt.Fprintf(opts.out(), "\t+ join control plane node %q (%s) using %s\n", node.Config.Hostname, node.Config.PrivateAddress, s.Cluster.Versions.Kubernetes)
 			}
 		}
 	}
 
 	for _, node := range s.LiveCluster.StaticWorkers {
 		if !node.IsInCluster {
-			fmt.Printf("\t+ join worker node %q (%s)\n", node.Config.Hostname, node.Config.PrivateAddress)
+			fmt.Fprintf(opts.out(), "\t+ join worker node %q (%s)\n", node.Config.Hostname, node.Config.PrivateAddress)
 		}
 	}
 
 	if opts.NoInit {
-		fmt.Println("\t! NoInit option provided: only binaries will be installed")
+		fmt.Fprintln(opts.out(), "\t! NoInit option provided: only binaries will be installed")
 	}
 
 	if opts.ForceInstall {
-		fmt.Println("\t! force-install option provided: force install new binary versions (!dangerous!)")
+		fmt.Fprintln(opts.out(), "\t! force-install option provided: force install new binary versions (!dangerous!)")
 	}
 
-	for _, node := range s.Cluster.DynamicWorkers {
-		fmt.Printf("\t+ ensure machinedeployment %q with %d replica(s) exists\n", node.Name, resolveInt(node.Replicas))
+	workersets, err := machinecontroller.Workersets(s.Cluster)
+	if err != nil {
+		return err
+	}
+
+	for _, node := range workersets {
+		fmt.Fprintf(opts.out(), "\t+ ensure machinedeployment %q with %d replica(s) exists\n", node.Name, resolveInt(node.Replicas))
 	}
 
 	if s.Cluster.Addons != nil && s.Cluster.Addons.Enable {
-		fmt.Printf("\t+ apply addons defined in %q\n", s.Cluster.Addons.Path)
+		fmt.Fprintf(opts.out(), "\t+ apply addons defined in %q\n", s.Cluster.Addons.Path)
 	}
 
-	fmt.Println()
+	fmt.Fprintln(opts.out())
 	confirm, err := confirmCommand(opts.AutoApprove)
 	if err != nil {
 		return err
@@ -326,10 +480,49 @@ func runApplyInstall(s *state.State, opts *applyOpts) error { // Print the expec
 	return errors.Wrap(tasks.WithFullInstall(nil).Run(s), "failed to install the cluster")
 }
 
+func runApplyJoinOnly(s *state.State, opts *applyOpts) error {
+	if !s.LiveCluster.IsProvisioned() {
+		return errors.New("the target cluster is not provisioned, --join-only requires a provisioned cluster")
+	}
+
+	if safeRepair, higherVer := s.LiveCluster.SafeToRepair(s.Cluster.Versions.Kubernetes); !safeRepair {
+		return errors.Errorf("joining nodes and upgrade are not supported at the same time, use version %s to join the nodes", higherVer)
+	}
+
+	controlPlane, workers, err := tasks.JoinOnlyHosts(s)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(opts.out(), "The following actions will be taken: ")
+	fmt.Fprintln(opts.out(), "Run with --verbose flag for more information.")
+
+	for _, node := range controlPlane {
+		fmt.Fprintf(opts.out(), "\t+ join control plane node %q (%s) using %s\n", node.Hostname, node.PrivateAddress, s.Cluster.Versions.Kubernetes)
+	}
+
+	for _, node := range workers {
+		fmt.Fprintf(opts.out(), "\t+ join worker node %q (%s)\n", node.Hostname, node.PrivateAddress)
+	}
+
+	fmt.Fprintln(opts.out())
+	confirm, err := confirmCommand(opts.AutoApprove)
+	if err != nil {
+		return err
+	}
+
+	if !confirm {
+		s.Logger.Println("Operation canceled.")
+		return nil
+	}
+
+	return errors.Wrap(tasks.WithJoinOnly(nil).Run(s), "failed to join the nodes")
+}
+
 func runApplyUpgradeIfNeeded(s *state.State, opts *applyOpts) error {
-	fmt.Println("The following actions will be taken: ")
+	fmt.Fprintln(opts.out(), "The following actions will be taken: ")
 	if !opts.Verbose {
-		fmt.Println("Run with --verbose flag for more information.")
+		fmt.Fprintln(opts.out(), "Run with --verbose flag for more information.")
 	}
 
 	upgradeNeeded, err := s.LiveCluster.UpgradeNeeded()
@@ -338,38 +531,34 @@ func runApplyUpgradeIfNeeded(s *state.State, opts *applyOpts) error {
 		return err
 	}
 
+	var intermediateHops []upgradeplan.Hop
+	if upgradeNeeded && opts.UpgradeSequentially {
+		intermediateHops, err = applyIntermediateHops(s)
+		if err != nil {
+			return err
+		}
+	}
+
 	operations := []string{}
 
 	var tasksToRun tasks.Tasks
 
 	if upgradeNeeded || opts.ForceUpgrade {
-		// disable case, we do this as early as possible.
-		if s.ShouldDisableEncryption() {
-			tasksToRun = tasks.WithDisableEncryptionProviders(tasksToRun, s.LiveCluster.EncryptionConfiguration.Custom)
-		}
-
-		tasksToRun = tasks.WithUpgrade(tasksToRun)
-
-		if s.ShouldEnableEncryption() {
-			operations = append(operations, "enable Encryption Provider support")
-			tasksToRun = tasks.WithRewriteSecrets(tasksToRun)
-		}
-
-		// custom encryption configuration was modified
-		if s.LiveCluster.CustomEncryptionEnabled() &&
-			s.Cluster.Features.EncryptionProviders != nil &&
-			s.Cluster.Features.EncryptionProviders.CustomEncryptionConfiguration != "" {
-			config := &apiserverconfigv1.EncryptionConfiguration{}
-			err = kyaml.UnmarshalStrict([]byte(s.Cluster.Features.EncryptionProviders.CustomEncryptionConfiguration), config)
-			if err != nil {
-				return err
+		for _, hop := range intermediateHops {
+			what := "all nodes"
+			if s.Cluster.MachineController.Deploy {
+				what = "all nodes and MachineDeployments"
 			}
+			operations = append(operations,
+				fmt.Sprintf("upgrade %s to the intermediate version %s and reconcile addons", what, hop.ToString()))
+		}
 
-			if !reflect.DeepEqual(config, s.LiveCluster.EncryptionConfiguration.Config) {
-				operations = append(operations, []string{"update Encryption Provider configuration", "restart KubeAPI"}...)
-				tasksToRun = tasks.WithCustomEncryptionConfigUpdated(tasksToRun)
-			}
+		var encryptionOperations []string
+		tasksToRun, encryptionOperations, err = tasks.WithUpgradeAndEncryption(tasksToRun, s)
+		if err != nil {
+			return err
 		}
+		operations = append(operations, encryptionOperations...)
 
 		for _, node := range s.LiveCluster.ControlPlane {
 			forceFlag := ""
@@ -403,16 +592,16 @@ func runApplyUpgradeIfNeeded(s *state.State, opts *applyOpts) error {
 		tasksToRun = tasks.WithResources(nil)
 	}
 
-	fmt.Println()
+	fmt.Fprintln(opts.out())
 	for _, op := range operations {
-		fmt.Printf("\t~ %s\n", op)
+		fmt.Fprintf(opts.out(), "\t~ %s\n", op)
 	}
 
 	for _, op := range tasksToRun.Descriptions(s) {
-		fmt.Printf("\t~ %s\n", op)
+		fmt.Fprintf(opts.out(), "\t~ %s\n", op)
 	}
 
-	fmt.Println()
+	fmt.Fprintln(opts.out())
 	confirm, err := confirmCommand(opts.AutoApprove)
 	if err != nil {
 		return err
@@ -423,9 +612,136 @@ func runApplyUpgradeIfNeeded(s *state.State, opts *applyOpts) error {
 		return nil
 	}
 
+	if err = runApplyIntermediateUpgrades(s, intermediateHops); err != nil {
+		return err
+	}
+
 	return errors.Wrap(tasksToRun.Run(s), "failed to reconcile the cluster")
 }
 
+// applyIntermediateHops returns upgrades to the intermediate minor versions,
+// needed before the cluster can be upgraded to the requested version
+func applyIntermediateHops(s *state.State) ([]upgradeplan.Hop, error) {
+	currentVersion, err := upgradeplan.ValidateSkew(planNodes(s), s.LiveCluster.ExpectedVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "version skew check failed")
+	}
+
+	hops, err := upgradeplan.Path(currentVersion, s.LiveCluster.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(hops) < 2 {
+		return nil, nil
+	}
+
+	// The last hop is to the requested version, which is done as usual
+	hops = hops[:len(hops)-1]
+	if err = upgradeplan.ResolveIntermediate(s.Context, upgradeplan.DefaultReleaseURL, hops); err != nil {
+		return nil, errors.Wrap(err, "failed to find intermediate versions")
+	}
+
+	return hops, nil
+}
+
+// runApplyIntermediateUpgrades upgrades the cluster to each of the
+// intermediate versions, one after another. The requested version is
+// restored once done, so the cluster can be upgraded to it.
+//
+// MachineDeployments are upgraded on every intermediate hop regardless of
+// the --upgrade-machine-deployments flag, and their rollout is awaited before
+// the next hop, otherwise the dynamic workers would fall behind the control
+// plane by more than the kubelet version skew allows.
+func runApplyIntermediateUpgrades(s *state.State, hops []upgradeplan.Hop) error {
+	targetVersion := s.Cluster.Versions.Kubernetes
+	upgradeMachineDeployments := s.UpgradeMachineDeployments
+	defer func() {
+		s.Cluster.Versions.Kubernetes = targetVersion
+		s.UpgradeMachineDeployments = upgradeMachineDeployments
+	}()
+
+	s.UpgradeMachineDeployments = s.Cluster.MachineController.Deploy || upgradeMachineDeployments
+
+	for i, hop := range hops {
+		s.Logger.Infof("Upgrading the cluster to the intermediate version %s (%d/%d)...", hop.ToString(), i+1, len(hops))
+
+		// Probes run as part of the upgrade tasks take the expected version
+		// from the manifest, so the whole upgrade targets the intermediate version
+		s.Cluster.Versions.Kubernetes = hop.To.String()
+		if err := tasks.WithUpgrade(nil).Run(s); err != nil {
+			return errors.Wrapf(err, "failed to upgrade the cluster to the intermediate version %s", hop.ToString())
+		}
+
+		if s.UpgradeMachineDeployments {
+			if err := tasks.WaitMachineDeploymentsUpgraded(s); err != nil {
+				return errors.Wrapf(err, "failed to upgrade MachineDeployments to the intermediate version %s", hop.ToString())
+			}
+		}
+	}
+
+	return nil
+}
+
+// loadUpgradeProbes reads the probe Pod manifests from the given files
+func loadUpgradeProbes(files []string) ([]corev1.Pod, error) {
+	probes := []corev1.Pod{}
+
+	for _, file := range files {
+		buf, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read the upgrade probe")
+		}
+
+		probe := corev1.Pod{}
+		if err = kyaml.UnmarshalStrict(buf, &probe); err != nil {
+			return nil, errors.Wrapf(err, "unable to parse the upgrade probe %q", file)
+		}
+
+		if probe.Kind != "Pod" || probe.Name == "" {
+			return nil, errors.Errorf("upgrade probe %q must be a Pod with a name", file)
+		}
+
+		probes = append(probes, probe)
+	}
+
+	return probes, nil
+}
+
+// applyCanaryCheck returns the check run once the canary nodes are upgraded.
+// The given command is run if set, otherwise the user is asked to confirm.
+func applyCanaryCheck(command string) func(*state.State, []kubeoneapi.HostConfig) error {
+	return func(s *state.State, nodes []kubeoneapi.HostConfig) error {
+		names := []string{}
+		for _, node := range nodes {
+			names = append(names, node.Hostname)
+		}
+
+		if command != "" {
+			cmd := exec.CommandContext(s.Context, "sh", "-c", command)
+			cmd.Env = append(os.Environ(), fmt.Sprintf("KUBEONE_CANARY_NODES=%s", strings.Join(names, ",")))
+			cmd.Stdout = os.Stdout
+			cmd.Stderr = os.Stderr
+
+			return errors.Wrapf(cmd.Run(), "canary check command %q failed", command)
+		}
+
+		fmt.Println()
+		fmt.Printf("Canary nodes %s are upgraded and ready.\n", strings.Join(names, ", "))
+		fmt.Println("Continue upgrading the rest of the cluster?")
+		confirm, err := confirmCommand(false)
+		if err != nil {
+			return err
+		}
+
+		if !confirm {
+			return errors.New("upgrade canceled after upgrading the canary nodes")
+		}
+
+		return nil
+	}
+}
+
 func runApplyRotateKey(s *state.State, opts *applyOpts) error {
 	if !opts.ForceUpgrade {
 		s.Logger.Error("rotating encryption keys requires the --force-upgrade flag")
@@ -436,15 +752,15 @@ func runApplyRotateKey(s *state.State, opts *applyOpts) error {
 		return errors.New("rotating encryption keys failed: Encryption Providers support is not enabled")
 	}
 
-	fmt.Println("The following actions will be taken: ")
-	fmt.Println("Run with --verbose flag for more information.")
+	fmt.Fprintln(opts.out(), "The following actions will be taken: ")
+	fmt.Fprintln(opts.out(), "Run with --verbose flag for more information.")
 	tasksToRun := tasks.WithRotateKey(nil)
 
 	for _, op := range tasksToRun.Descriptions(s) {
-		fmt.Printf("\t~ %s\n", op)
+		fmt.Fprintf(opts.out(), "\t~ %s\n", op)
 	}
 
-	fmt.Println()
+	fmt.Fprintln(opts.out())
 	confirm, err := confirmCommand(opts.AutoApprove)
 	if err != nil {
 		return err
@@ -0,0 +1,155 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8c.io/kubeone/pkg/tasks"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+type certificatesRotateOpts struct {
+	globalOptions
+	AutoApprove     bool   `longflag:"auto-approve" shortflag:"y"`
+	CARotationPhase string `longflag:"ca-rotation-phase"`
+}
+
+func certificatesCmd(fs *pflag.FlagSet) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "certificates",
+		Short: "Commands for managing the cluster certificates",
+	}
+
+	cmd.AddCommand(certificatesRotateCmd(fs))
+	return cmd
+}
+
+func certificatesRotateCmd(fs *pflag.FlagSet) *cobra.Command {
+	opts := &certificatesRotateOpts{}
+
+	cmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "Renew the control plane certificates and optionally rotate the Kubernetes CA",
+		Long: heredoc.Doc(`
+			Renew all certificates managed by kubeadm on the control plane nodes, one node at a time.
+			After the certificates are renewed on a node, the control plane static pods on the node are
+			restarted, and the next node is rotated only after the API server on the node is healthy.
+
+			The Kubernetes CA is rotated with the '--ca-rotation-phase' flag, using the dual-CA trust
+			bundle procedure. The phases must be run in order, each by a separate 'kubeone certificates rotate' run:
+
+			  trust:    generate the new CA and add it to the CAs trusted by all nodes and components,
+			            while the certificates are still signed by the old CA
+			  sign:     sign all control plane certificates and kubelet client certificates of the control
+			            plane nodes and static workers by the new CA, while the old CA is still trusted
+			  finalize: remove the old CA from the trusted CAs
+
+			The kubeconfig files signed by the old CA, including the previously downloaded admin kubeconfig,
+			stop working after the finalize phase. Dynamic workers (MachineDeployments) must be recreated after
+			the trust phase, so they trust the new CA before the sign phase, and after the sign phase, so
+			their kubelet client certificates are signed by the new CA before the finalize phase.
+			The etcd and front-proxy CAs are not rotated. The CA provided by the user (.certificateAuthority.external)
+			can't be rotated, but the certificates signed by the external CA signer are renewed.
+		`),
+		Example: heredoc.Doc(`
+			kubeone certificates rotate -m mycluster.yaml
+			kubeone certificates rotate -m mycluster.yaml --ca-rotation-phase trust
+		`),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			gopts, err := persistentGlobalOptions(fs)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			opts.globalOptions = *gopts
+
+			return runCertificatesRotate(opts)
+		},
+	}
+
+	cmd.Flags().BoolVarP(
+		&opts.AutoApprove,
+		longFlagName(opts, "AutoApprove"),
+		shortFlagName(opts, "AutoApprove"),
+		false,
+		"auto approve plan")
+
+	cmd.Flags().StringVar(
+		&opts.CARotationPhase,
+		longFlagName(opts, "CARotationPhase"),
+		"",
+		fmt.Sprintf("run the phase of the Kubernetes CA rotation, one of: %s", strings.Join(tasks.CARotationPhases, ", ")))
+
+	return cmd
+}
+
+func runCertificatesRotate(opts *certificatesRotateOpts) error {
+	if opts.CARotationPhase != "" && !sets.NewString(tasks.CARotationPhases...).Has(opts.CARotationPhase) {
+		return errors.Errorf("unknown CA rotation phase %q, must be one of: %s", opts.CARotationPhase, strings.Join(tasks.CARotationPhases, ", "))
+	}
+
+	s, err := opts.BuildState()
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize State")
+	}
+
+	// Probe the cluster for the actual state and the needed tasks.
+	probbing := tasks.WithHostnameOS(nil)
+	probbing = tasks.WithProbes(probbing)
+
+	if err = probbing.Run(s); err != nil {
+		return err
+	}
+
+	if !s.LiveCluster.IsProvisioned() {
+		return errors.New("the target cluster is not provisioned")
+	}
+	if !s.LiveCluster.Healthy() {
+		return errors.New("the target cluster is not healthy, please run 'kubeone apply' first")
+	}
+
+	fmt.Println("The following actions will be taken: ")
+	fmt.Println("Run with --verbose flag for more information.")
+	tasksToRun := tasks.WithCertificatesRotation(nil, opts.CARotationPhase)
+
+	for _, op := range tasksToRun.Descriptions(s) {
+		fmt.Printf("\t~ %s\n", op)
+	}
+
+	fmt.Println()
+	confirm, err := confirmCommand(opts.AutoApprove)
+	if err != nil {
+		return err
+	}
+
+	if !confirm {
+		s.Logger.Println("Operation canceled.")
+		return nil
+	}
+
+	defer opts.reportProgress(s, "certificates-rotate")
+
+	return errors.Wrap(tasksToRun.Run(s), "failed to rotate certificates")
+}
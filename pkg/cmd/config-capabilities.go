@@ -0,0 +1,105 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8c.io/kubeone/pkg/capabilities"
+
+	kyaml "sigs.k8s.io/yaml"
+)
+
+type capabilitiesOpts struct {
+	globalOptions
+	Output string `longflag:"output" shortflag:"o"`
+}
+
+func configCapabilitiesCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	opts := &capabilitiesOpts{}
+
+	cmd := &cobra.Command{
+		Use:   "capabilities",
+		Short: "Print the capability set computed from the manifest",
+		Long: heredoc.Doc(`
+			Print the capability set of the cluster computed from the KubeOneCluster manifest,
+			after applying the same defaulting and validation as other commands.
+
+			The capability set contains the effective versions, the cloud provider integration,
+			the enabled features and addons, the images used by the cluster and the Kubernetes
+			API endpoints. The output is stable within its schemaVersion and meant to be consumed
+			by other tooling. The cluster is not contacted.
+		`),
+		Args:    cobra.ExactArgs(0),
+		Example: `kubeone config capabilities -m mycluster.yaml -t tf.json -o json`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			gopts, err := persistentGlobalOptions(rootFlags)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			opts.globalOptions = *gopts
+
+			return runCapabilities(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(
+		&opts.Output,
+		longFlagName(opts, "Output"),
+		shortFlagName(opts, "Output"),
+		statusOutputJSON,
+		fmt.Sprintf("output format, one of: %s, %s", statusOutputJSON, statusOutputYAML))
+
+	return cmd
+}
+
+func runCapabilities(opts *capabilitiesOpts) error {
+	if opts.Output != statusOutputJSON && opts.Output != statusOutputYAML {
+		return errors.Errorf("unknown output format %q", opts.Output)
+	}
+
+	s, err := opts.BuildState()
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize State")
+	}
+
+	caps, err := capabilities.Compute(s.Cluster)
+	if err != nil {
+		return errors.Wrap(err, "failed to compute capabilities")
+	}
+
+	var out []byte
+	if opts.Output == statusOutputJSON {
+		out, err = json.MarshalIndent(caps, "", "  ")
+	} else {
+		out, err = kyaml.Marshal(caps)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal capabilities")
+	}
+
+	fmt.Println(string(out))
+
+	return nil
+}
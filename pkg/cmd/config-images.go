@@ -25,6 +25,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
+	"k8c.io/kubeone/pkg/apis/kubeone/config"
 	kubeonev1beta1 "k8c.io/kubeone/pkg/apis/kubeone/v1beta1"
 	"k8c.io/kubeone/pkg/templates/images"
 
@@ -103,8 +104,13 @@ func listImages(opts *listImagesOpts) error {
 	// FOR FUTURE READER: we only attempt to read the ManifestFile, but if it's not there, we don't care.
 	configBuf, err := os.ReadFile(opts.ManifestFile)
 	if err == nil {
+		if configBuf, err = config.SubstituteReferences(configBuf, opts.ManifestFile); err != nil {
+			return err
+		}
+
 		// Custom loading of the config is needed to avoid "normal" validation process, but we here don't care about
-		// validity of the config, the only part that's needed is `.RegistryConfiguration`
+		// validity of the config, the only parts that are needed are `.RegistryConfiguration`
+		// and the machine-controller image overrides
 		var conf kubeonev1beta1.KubeOneCluster
 		if err = yaml.Unmarshal(configBuf, &conf); err != nil {
 			return err
@@ -116,10 +122,16 @@ func listImages(opts *listImagesOpts) error {
 			}
 			return ""
 		})
+		overImgGetter := images.WithOverwriteImageGetter(func(res images.Resource) (string, string) {
+			if mc := conf.MachineController; mc != nil && res == images.MachineController {
+				return mc.ImageRepository, mc.ImageTag
+			}
+			return "", ""
+		})
 		kubeVerGetter := images.WithKubernetesVersionGetter(func() string {
 			return conf.Versions.Kubernetes
 		})
-		resolveropts = append(resolveropts, overRegGetter, kubeVerGetter)
+		resolveropts = append(resolveropts, overRegGetter, overImgGetter, kubeVerGetter)
 	}
 
 	imgResolver := images.NewResolver(resolveropts...)
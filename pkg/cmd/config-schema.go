@@ -0,0 +1,109 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"k8c.io/kubeone/pkg/apis/kubeone/config"
+	kubeonev1alpha1 "k8c.io/kubeone/pkg/apis/kubeone/v1alpha1"
+	kubeonev1beta1 "k8c.io/kubeone/pkg/apis/kubeone/v1beta1"
+	"k8c.io/kubeone/pkg/jsonschema"
+)
+
+type schemaOpts struct {
+	APIVersion string `longflag:"api-version"`
+}
+
+// schemaObjects are the versioned KubeOneCluster objects the schema can be
+// generated for
+var schemaObjects = map[string]interface{}{
+	kubeonev1alpha1.SchemeGroupVersion.String(): &kubeonev1alpha1.KubeOneCluster{},
+	kubeonev1beta1.SchemeGroupVersion.String():  &kubeonev1beta1.KubeOneCluster{},
+}
+
+func configSchemaCmd() *cobra.Command {
+	opts := &schemaOpts{}
+
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema of the KubeOneCluster manifest",
+		Long: heredoc.Doc(`
+			Print the JSON Schema of the KubeOneCluster manifest, generated from the API types
+			of the given apiVersion.
+
+			The schema can be used by editors (e.g. using the yaml-language-server) and CI
+			pipelines to validate and autocomplete the KubeOneCluster manifests. Same as
+			KubeOne, the schema doesn't allow unknown fields.
+		`),
+		Args:    cobra.ExactArgs(0),
+		Example: `kubeone config schema --api-version kubeone.io/v1beta1 > kubeone.schema.json`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runSchema(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&opts.APIVersion,
+		longFlagName(opts, "APIVersion"),
+		kubeonev1beta1.SchemeGroupVersion.String(),
+		fmt.Sprintf("apiVersion of the KubeOneCluster manifest, one of: %s", strings.Join(schemaAPIVersions(), ", ")))
+
+	return cmd
+}
+
+func runSchema(opts *schemaOpts) error {
+	obj, ok := schemaObjects[opts.APIVersion]
+	if !ok {
+		return errors.Errorf("unsupported apiVersion %q, supported apiVersions are: %s", opts.APIVersion, strings.Join(schemaAPIVersions(), ", "))
+	}
+
+	schema, err := jsonschema.Generate(obj, jsonschema.Options{
+		Title:      fmt.Sprintf("%s %s", config.KubeOneClusterKind, opts.APIVersion),
+		APIVersion: opts.APIVersion,
+		Kind:       config.KubeOneClusterKind,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to generate the schema")
+	}
+
+	out, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the schema")
+	}
+
+	fmt.Println(string(out))
+
+	return nil
+}
+
+func schemaAPIVersions() []string {
+	versions := []string{}
+	for version := range schemaObjects {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+
+	return versions
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	kubeonevalidation "k8c.io/kubeone/pkg/apis/kubeone/validation"
+)
+
+type validateOpts struct {
+	globalOptions
+	Strict bool `longflag:"strict"`
+}
+
+func configValidateCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	opts := &validateOpts{}
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the KubeOneCluster manifest",
+		Long: heredoc.Doc(`
+			Validate the KubeOneCluster manifest, using the same defaulting and validation
+			as other commands. Manifests with unknown fields (e.g. because of a typo) are
+			rejected. The cluster is not contacted.
+
+			With --strict, the following checks are done as well:
+			  * the pod subnet, the service subnet and the node addresses don't overlap
+			  * the cloud provider specific requirements are met
+			  * the files referenced in the manifest (SSH keys, audit policy,
+			    PodNodeSelector configuration, addons) exist
+		`),
+		Args:    cobra.ExactArgs(0),
+		Example: `kubeone config validate -m mycluster.yaml -t tf.json --strict`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			gopts, err := persistentGlobalOptions(rootFlags)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			opts.globalOptions = *gopts
+
+			return runValidate(opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(
+		&opts.Strict,
+		longFlagName(opts, "Strict"),
+		false,
+		"reject overlapping networks, unmet cloud provider requirements and missing files referenced in the manifest")
+
+	return cmd
+}
+
+func runValidate(opts *validateOpts) error {
+	s, err := opts.BuildState()
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize State")
+	}
+
+	if opts.Strict {
+		if err = kubeonevalidation.ValidateKubeOneClusterStrict(*s.Cluster, opts.ManifestFile).ToAggregate(); err != nil {
+			return errors.Wrap(err, "unable to validate the given KubeOneCluster object")
+		}
+	}
+
+	fmt.Println("The KubeOneCluster manifest is valid.")
+
+	return nil
+}
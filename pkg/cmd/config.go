@@ -91,6 +91,9 @@ func configCmd(rootFlags *pflag.FlagSet) *cobra.Command {
 	cmd.AddCommand(configMigrateCmd(rootFlags))
 	cmd.AddCommand(configMachinedeploymentsCmd(rootFlags))
 	cmd.AddCommand(configImagesCmd(rootFlags))
+	cmd.AddCommand(configCapabilitiesCmd(rootFlags))
+	cmd.AddCommand(configValidateCmd(rootFlags))
+	cmd.AddCommand(configSchemaCmd())
 
 	return cmd
 }
@@ -201,8 +204,21 @@ The new manifest is printed on the standard output.
 	return cmd
 }
 
+const (
+	machineDeploymentsFormatMachineController = "machine-controller"
+	machineDeploymentsFormatCAPI              = "capi"
+)
+
+type machineDeploymentsOpts struct {
+	globalOptions
+	Format    string `longflag:"format"`
+	Namespace string `longflag:"namespace"`
+}
+
 // configMachinedeploymentsCmd setups the machinedeployments command
 func configMachinedeploymentsCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	opts := &machineDeploymentsOpts{}
+
 	cmd := &cobra.Command{
 		Use:   "machinedeployments",
 		Short: "Print the manifest for creating MachineDeployments",
@@ -212,6 +228,13 @@ Print the manifest for creating MachineDeployment objects.
 The manifest contains all MachineDeployments defined in the API/config.
 Note that manifest may include already created MachineDeplyoments.
 The manifest is printed on the standard output.
+
+With --format capi, the Cluster API (cluster.x-k8s.io) MachineDeployment,
+KubeadmConfigTemplate and infrastructure machine template equivalents are
+printed instead, to help migrating from machine-controller to Cluster API.
+The cloudProviderSpec fields without a Cluster API equivalent are listed in
+the kubeone.io/unmapped-cloud-provider-spec-fields annotation of the machine
+template.
 `,
 		Args:    cobra.ExactArgs(0),
 		Example: `kubeone config machinedeplyoments --manifest mycluster.yaml`,
@@ -221,10 +244,24 @@ The manifest is printed on the standard output.
 				return errors.Wrap(err, "unable to get global flags")
 			}
 
-			return runGenerateMachineDeployments(gopts)
+			opts.globalOptions = *gopts
+
+			return runGenerateMachineDeployments(opts)
 		},
 	}
 
+	cmd.Flags().StringVar(
+		&opts.Format,
+		longFlagName(opts, "Format"),
+		machineDeploymentsFormatMachineController,
+		fmt.Sprintf("format of the manifest, one of: %s|%s", machineDeploymentsFormatMachineController, machineDeploymentsFormatCAPI))
+
+	cmd.Flags().StringVar(
+		&opts.Namespace,
+		longFlagName(opts, "Namespace"),
+		"default",
+		"namespace of the Cluster API objects, used only with --format capi")
+
 	return cmd
 }
 
@@ -239,7 +276,7 @@ func runPrint(printOptions *printOpts) error {
 		case "vsphere":
 			printOptions.CloudProviderCloudCfg = "<< cloudConfig is required for vSphere >>"
 		case "azure":
-			printOptions.CloudProviderCloudCfg = "<< cloudConfig is required for Azure >>"
+			printOptions.CloudProviderCloudCfg = "<< cloudConfig or .cloudProvider.azure.cloudConfig is required for Azure >>"
 		}
 
 		tmpl, err := template.New("example-manifest").Parse(exampleManifest)
@@ -450,13 +487,22 @@ func runMigrate(opts *globalOptions) error {
 }
 
 // runGenerateMachineDeployments generates the MachineDeployments manifest
-func runGenerateMachineDeployments(opts *globalOptions) error {
+func runGenerateMachineDeployments(opts *machineDeploymentsOpts) error {
+	if opts.Format != machineDeploymentsFormatMachineController && opts.Format != machineDeploymentsFormatCAPI {
+		return errors.Errorf("unsupported format %q, supported formats are %q and %q", opts.Format, machineDeploymentsFormatMachineController, machineDeploymentsFormatCAPI)
+	}
+
 	s, err := opts.BuildState()
 	if err != nil {
 		return errors.Wrap(err, "failed to initialize State")
 	}
 
-	manifest, err := machinecontroller.GenerateMachineDeploymentsManifest(s)
+	var manifest string
+	if opts.Format == machineDeploymentsFormatCAPI {
+		manifest, err = machinecontroller.GenerateCAPIManifest(s, opts.Namespace)
+	} else {
+		manifest, err = machinecontroller.GenerateMachineDeploymentsManifest(s)
+	}
 	if err != nil {
 		return errors.Wrap(err, "failed to generate machinedeployments manifest")
 	}
@@ -495,6 +541,7 @@ kind: KubeOneCluster
 name: {{ .ClusterName }}
 
 versions:
+  # exact version, or the minor version, e.g. 1.21.x, to use the latest patch release
   kubernetes: "{{ .KubernetesVersion }}"
 
 clusterNetwork:
@@ -520,7 +567,7 @@ clusterNetwork:
       # * sed: shortest expected delay
       # * nq: never queue
       scheduler: rr
-      strictArp: false
+      strictARP: false
       tcpTimeout: "0"
       tcpFinTimeout: "0"
       udpTimeout: "0"
@@ -534,7 +581,7 @@ clusterNetwork:
     # * canal
     # * weave-net
     # * cilium
-    # * external - The CNI plugin can be installed as an addon or manually
+    # * external - The CNI plugin can be installed as an addon, manually or as a Helm chart
     canal:
       # MTU represents the maximum transmission unit.
       # Default MTU value depends on the specified provider:
@@ -556,36 +603,112 @@ clusterNetwork:
     #   # supports encryption.
     #   encrypted: true
     # external: {}
+    # # Optionally, KubeOne can install and upgrade the external CNI plugin as a Helm chart
+    # external:
+    #   helmChart:
+    #     releaseName: cilium
+    #     chart: cilium
+    #     repoURL: https://helm.cilium.io/
+    #     # required, unless chart is a path to the local chart
+    #     version: 1.10.5
+    #     namespace: kube-system
+    #     # paths to the values files, relative to the KubeOneCluster manifest
+    #     valuesFiles:
+    #       - ./cilium-values.yaml
+    #     # values in the same format as "helm --set", can reference external secrets
+    #     # in the same way as addons params
+    #     values:
+    #       hubble.relay.enabled: "true"
 
 cloudProvider:
   # Only one cloud provider can be defined at the same time.
   # Possible values:
-  # aws: {}
-  # azure: {}
-  # digitalocean: {}
+  # aws:
+  #   # Deploy the Amazon EBS CSI driver and the default gp3 StorageClass
+  #   # ebs-csi (requires external: true). The driver uses the AWS credentials
+  #   # provided to KubeOne, or the instance profile of the nodes.
+  #   ebsCSI:
+  #     enable: false
+  #     useInstanceProfile: false
+  # azure:
+  #   # Generate the azure.json cloudConfig from the credentials.
+  #   cloudConfig:
+  #     resourceGroup: ""
+  #     location: ""
+  #     vnetName: ""
+  #     subnetName: ""
+  #     securityGroupName: ""
+  #     routeTableName: ""
+  #     loadBalancerSku: "standard"
+  #   # The cloud-provider-azure CCM and the AzureDisk and AzureFile CSI
+  #   # drivers are deployed on clusters using the external cloud provider.
+  #   ccm:
+  #     enable: true
+  #   diskCSI:
+  #     enable: true
+  #   fileCSI:
+  #     enable: true
+  # digitalocean:
+  #   vpcUUID: ""
   # gce: {}
   # hetzner:
   #   networkID: ""
-  # openstack: {}
+  # openstack:
+  #   # The OpenStack CCM and the Cinder CSI driver are deployed on clusters
+  #   # using the external cloud provider. The cloud-config sections are
+  #   # appended to the cloudConfig used only by the component.
+  #   ccm:
+  #     enable: true
+  #     cloudConfigSections:
+  #       LoadBalancer:
+  #         use-octavia: "true"
+  #   cinderCSI:
+  #     enable: true
+  #     cloudConfigSections:
+  #       BlockStorage:
+  #         ignore-volume-az: "true"
   # packet: {}
-  # vsphere: {}
+  # vsphere:
+  #   # Generate the CSI driver configuration (csiConfig) from the credentials
+  #   # and deploy the vSphere CSI driver with the optional StorageClass and
+  #   # the CSI snapshots support (Kubernetes 1.21+).
+  #   csi:
+  #     datacenters: ["dc-1"]
+  #     insecureFlag: false
+  #     storageClass:
+  #       datastoreURL: "ds:///vmfs/volumes/<datastore-id>/"
+  #       storagePolicyName: ""
+  #     snapshots: false
   # none: {}
   {{ .CloudProviderName }}: {}
   # Set the kubelet flag '--cloud-provider=external' and deploy the external CCM for supported providers
   external: {{ .CloudProviderExternal }}
   # Path to file that will be uploaded and used as custom '--cloud-config' file.
+  # The cloud-config is rendered as a template with the cloud provider credentials
+  # available as .Credentials, so the credentials don't have to be in the manifest, e.g.
+  #   password = {{"{{"}} .Credentials.OS_PASSWORD | quote {{"}}"}}
   cloudConfig: "{{ .CloudProviderCloudCfg }}"
   # CSIConfig is configuration passed to the CSI driver.
-  # This is currently used only for vSphere clusters.
+  # This is currently used only for vSphere clusters, and it can be omitted
+  # if .cloudProvider.vsphere.csi is set.
   csiConfig: ""
+  # Read the cloud provider credentials from the HashiCorp Vault secret,
+  # instead of the environment variables and the credentials file.
+  # Vault is configured using the VAULT_ADDR, VAULT_TOKEN and VAULT_NAMESPACE
+  # environment variables. Keys map the credentials to the keys of the secret,
+  # e.g. for the AWS secrets engine:
+  # credentialsVault:
+  #   path: aws/creds/kubeone
+  #   keys:
+  #     AWS_ACCESS_KEY_ID: access_key
+  #     AWS_SECRET_ACCESS_KEY: secret_key
 
 # Controls which container runtime will be installed on instances.
 # By default:
 # * Docker will be installed for Kubernetes clusters up to 1.20
 # * containerd will be installed for Kubernetes clusters 1.21+
-# Currently, it's not possible to migrate existing clusters from one to another
-# container runtime, however, migration from Docker to containerd is planned
-# for one of the upcoming KubeOne releases.
+# Existing clusters can be migrated from Docker to containerd by enabling
+# containerd below and running 'kubeone migrate to-containerd'.
 # Only one container runtime can be present at the time.
 #
 # Note: Kubernetes has announced deprecation of Docker (dockershim) support.
@@ -595,6 +718,22 @@ containerRuntime:
   # Installs containerd container runtime.
   # Default for 1.21+ Kubernetes clusters.
   # containerd: {}
+  # containerd:
+  #   # Image used for the pod sandbox (pause) containers.
+  #   # Defaults to the pause image from assetConfiguration, if set.
+  #   sandboxImage: ""
+  #   # Cgroup driver used by containerd and kubelet (systemd or cgroupfs).
+  #   cgroupDriver: systemd
+  #   # Configure the nvidia runtime handler and create the nvidia RuntimeClass.
+  #   # The NVIDIA driver and nvidia-container-runtime must be installed on nodes.
+  #   nvidiaRuntime:
+  #     enable: false
+  #     binaryName: /usr/bin/nvidia-container-runtime
+  #     defaultRuntime: false
+  #   # TOML snippet merged into the generated /etc/containerd/config.toml.
+  #   configPatch: |
+  #     [plugins."io.containerd.grpc.v1.cri"]
+  #     max_concurrent_downloads = 5
   # Installs Docker container runtime.
   # Default for Kubernetes clusters up to 1.20.
   # This option will be removed once Kubernetes 1.21 reaches EOL.
@@ -645,6 +784,17 @@ features:
   metricsServer:
     # enabled by default
     enable: {{ .EnableMetricsServer }}
+    # replicas: 1
+    # resources:
+    #   requests:
+    #     cpu: 100m
+    #     memory: 200Mi
+    # Verifying the kubelet serving certificates requires them to be signed
+    # by the cluster CA.
+    # kubeletInsecureTLS: true
+    # Run metrics-server in the host network if the API server can't reach
+    # the pods. metrics-server listens on the port 4443 of the host.
+    # hostNetwork: false
   # Enable OpenID-Connect support in API server
   # More info: https://kubernetes.io/docs/reference/access-authn-authz/authentication/#openid-connect-tokens
   openidConnect:
@@ -693,6 +843,86 @@ features:
     enable: {{ .EnableEncryptionProviders }}
     # inline string
     customEncryptionConfiguration: ""
+  # Deploy the NVIDIA device plugin and install the NVIDIA driver and container
+  # toolkit on hosts with .nvidiaGPU enabled. Dynamic workers with .nvidiaGPU
+  # enabled must use images with the NVIDIA driver and container toolkit preinstalled.
+  # Requires .containerRuntime.containerd.nvidiaRuntime.enable.
+  # nvidiaGPU:
+  #   enable: false
+  #   # NVIDIA driver branch, defaults to the latest driver available
+  #   driverVersion: ""
+
+  # Deploy kube-state-metrics and node-exporter to the kube-system namespace.
+  # node-exporter listens on the port 9100 of every node.
+  monitoring:
+    enable: false
+    # Deploy the Prometheus Operator ServiceMonitors for kube-state-metrics and
+    # node-exporter. Requires the monitoring.coreos.com CRDs.
+    serviceMonitors: false
+
+  # Deploy the cluster-autoscaler scaling the MachineDeployments of the
+  # workersets with autoscaling configured (.dynamicWorkers.autoscaling).
+  clusterAutoscaler:
+    enable: false
+    # one of: random, most-pods, least-waste, priority
+    expander: random
+    # scaleDownUnneededTime: 10m
+    # scaleDownDelayAfterAdd: 10m
+    # balanceSimilarNodeGroups: false
+
+  # Deploy the ingress-nginx controller
+  ingressController:
+    enable: false
+    # replicas: 2
+    # one of: LoadBalancer, NodePort; LoadBalancer by default on providers
+    # provisioning load balancers, otherwise NodePort
+    # serviceType: LoadBalancer
+    # run as a DaemonSet listening on the ports 80 and 443 of the nodes
+    # hostNetwork: false
+    # ingressClass: nginx
+    # defaultIngressClass: false
+
+  # Deploy Flux reconciling the manifests from the git repository
+  gitOps:
+    enable: false
+    # repository:
+    #   url: "https://github.com/example/fleet"
+    #   # default: main
+    #   branch: main
+    #   # default: the root of the repository
+    #   path: "./clusters/production"
+    #   interval: 1m
+    #   # Secret in the flux-system namespace with the git credentials,
+    #   # required for the ssh repositories
+    #   secretName: ""
+
+  # Deploy Velero backing up the cluster to the object storage
+  velero:
+    enable: false
+    # default: based on the cloud provider (aws, gcp or azure)
+    # provider: aws
+    # bucket: "kubeone-backups"
+    # prefix: ""
+    # config:
+    #   region: "eu-west-3"
+    # Deploy restic for backing up the contents of the pod volumes
+    # restic: false
+    # schedule:
+    #   disable: false
+    #   # default: "0 3 * * *"
+    #   cron: "0 3 * * *"
+    #   # default: 720h
+    #   ttl: 720h
+
+  # Deploy cert-manager before the user addons are applied
+  certManager:
+    enable: false
+    # clusterIssuer:
+    #   # default: kubeone-ca
+    #   name: kubeone-ca
+    #   # kubernetes.io/tls Secret in the cert-manager namespace with the CA
+    #   # certificate and key; the cluster CA is used if empty
+    #   caSecretName: ""
 
 ## Bundle of Root CA Certificates extracted from Mozilla
 ## can be found here: https://curl.se/ca/cacert.pem
@@ -704,7 +934,9 @@ systemPackages:
   configureRepositories: true # it's true by default
 
 # assetConfiguration controls how assets (e.g. CNI, Kubelet, kube-apiserver, and more) are pulled.
-# The AssetConfiguration API is an alpha API currently working only on Amazon Linux 2.
+# The AssetConfiguration API is an alpha API. Image assets and the kubeadm,
+# kubelet, kubectl, cni and binariesMirror binary assets work on all supported
+# operating systems, while nodeBinaries works only on Amazon Linux 2.
 assetConfiguration:
   # kubernetes configures the image registry and repository for the core Kubernetes
   # images (kube-apiserver, kube-controller-manager, kube-scheduler, and kube-proxy).
@@ -774,6 +1006,33 @@ assetConfiguration:
   # Default: none
   kubectl:
     url: ""
+  # kubeadm and kubelet configure the sources for downloading the Kubeadm
+  # and Kubelet binaries. If specified, kubectl and cni must be specified
+  # as well, unless binariesMirror is used.
+  # Default: none
+  kubeadm:
+    url: ""
+    # sha256 is the expected SHA256 checksum of the binary. Every binary is
+    # verified, using the checksum published at <url>.sha256 if sha256 is empty.
+    sha256: ""
+  kubelet:
+    url: ""
+    sha256: ""
+  # binariesMirror is the URL of the HTTP mirror used to download kubeadm,
+  # kubelet, kubectl, cni and crictl binaries not specified explicitly,
+  # instead of installing packages. The mirror uses the dl.k8s.io layout
+  # (<mirror>/v<version>/bin/linux/<arch>/kubeadm) and the binaries are
+  # verified against the published .sha256 checksums.
+  # Default: none
+  binariesMirror: ""
+  # signatureVerification configures verification of cosign signatures of
+  # kubeadm, kubelet and kubectl binaries, in addition to the SHA256 checksum
+  # verification. cosign must be installed on the nodes.
+  # If publicKey is empty, the keyless verification is used.
+  # signatureVerification:
+  #   publicKey: ""
+  #   certificateIdentity: "krel-staging@k8s-releng-prod.iam.gserviceaccount.com"
+  #   certificateOIDCIssuer: "https://accounts.google.com"
 
 # registryConfiguration controls how images used for components deployed by
 # KubeOne and kubeadm are pulled from an image registry
@@ -790,6 +1049,221 @@ registryConfiguration:
   # to the worker nodes managed by machine-controller and/or KubeOne.
   insecureRegistry: false
 
+# rbac defines the RBAC resources created and reconciled by KubeOne, e.g. to
+# bind groups coming from the OIDC provider to the roles. Resources removed from
+# this section are removed from the cluster as well.
+# rbac:
+#   clusterRoles:
+#     - name: pod-reader
+#       rules:
+#         - apiGroups: [""]
+#           resources: ["pods"]
+#           verbs: ["get", "list", "watch"]
+#   clusterRoleBindings:
+#     - name: oidc-admins
+#       clusterRole: cluster-admin
+#       # groups coming from the OIDC provider must include the groupsPrefix
+#       groups:
+#         - "oidc:admins"
+#   roleBindings:
+#     - name: oidc-developers
+#       namespace: dev
+#       clusterRole: edit
+#       groups:
+#         - "oidc:developers"
+#       users:
+#         - "jane@example.com"
+
+# nodeDrain configures how nodes are drained before they're upgraded, or reset
+# with the 'kubeone reset --drain-nodes' command. It can be overridden per host
+# using the .nodeDrain field of the host.
+# nodeDrain:
+#   # the period of time in seconds given to each pod to terminate gracefully,
+#   # -1 means using the grace period defined in the pod
+#   gracePeriodSeconds: -1
+#   # the time to wait for the node to be drained before giving up, 0 means
+#   # waiting infinitely
+#   timeout: 10m
+#   # allow deleting pods using emptyDir volumes
+#   deleteEmptyDirData: true
+#   # skip waiting for pods deleted for longer than the given number of seconds
+#   skipWaitForDeleteTimeoutSeconds: 0
+#   # delete pods instead of evicting them, bypassing PodDisruptionBudgets.
+#   # Use it only if strict PodDisruptionBudgets prevent nodes from being drained.
+#   ignorePodDisruptionBudgets: false
+
+# ignorePreflightErrors are kubeadm preflight errors ignored on all hosts, e.g.
+# NumCPU on small edge machines. Use "all" to ignore all preflight errors.
+# Additional preflight errors can be ignored per host using the
+# .ignorePreflightErrors field of the host.
+# ignorePreflightErrors:
+#   # ignored when running 'kubeadm init' and 'kubeadm join'
+#   install:
+#   - NumCPU
+#   # ignored when running 'kubeadm upgrade'
+#   upgrade:
+#   - SystemVerification
+
+# manifestPatches are applied to the manifests before they're applied to the
+# cluster, including the addons (the embedded addons such as CNI and
+# machine-controller as well), MachineDeployments and the other objects
+# created by KubeOne. Patches are applied in the order they're defined, to all manifests
+# matching the target. Empty target fields match any value, and group "core"
+# matches the core API group. Each patch is either a JSON merge patch
+# (mergePatch) or a JSON patch (jsonPatch), written in YAML or JSON.
+# manifestPatches:
+# - target:
+#     group: apps
+#     kind: Deployment
+#   mergePatch: |
+#     spec:
+#       template:
+#         spec:
+#           imagePullSecrets:
+#           - name: corporate-registry
+# - jsonPatch: |
+#     - op: add
+#       path: /metadata/labels/company.com~1owner
+#       value: platform
+
+# clusterSize is the number of nodes and pods the cluster is expected to grow
+# to. If set, the CPUs, memory and the size and synchronous write speed of the
+# etcd disk of the control plane hosts are checked before installing and
+# upgrading the cluster. Undersized hosts are reported as warnings, unless
+# enforce is true, in which case the check fails.
+# clusterSize:
+#   nodes: 250
+#   pods: 7500
+#   enforce: false
+
+# preflight configures the kubeadm and KubeOne preflight checks. Skipped checks
+# are not run, and failures of warning-only checks are reported as warnings.
+# kubeadm checks are prefixed with 'kubeadm:' and can't be skipped, so both
+# lists make kubeadm ignore their failures.
+# preflight:
+#   skip:
+#   - time-sync
+#   warnOnly:
+#   - version-skew
+#   - kubeadm:NumCPU
+
+# hostReboot configures rebooting hosts which require reboot after installing
+# the prerequisites, e.g. because the kernel was upgraded. Hosts are rebooted
+# one at a time, and hosts already joined to the cluster are drained before the
+# reboot. If not enabled, hosts requiring reboot are only reported.
+# hostReboot:
+#   enable: true
+#   timeout: 10m
+
+# osTuning configures kernel modules loaded and sysctl settings applied on
+# all hosts, and per host group. Settings for control plane and static worker
+# hosts take precedence over the settings for all hosts. Sysctl settings
+# removed from the configuration are reverted only after the reboot.
+# osTuning:
+#   all:
+#     kernelModules:
+#     - nf_conntrack
+#     sysctls:
+#       net.ipv4.conf.all.rp_filter: "1"
+#       net.netfilter.nf_conntrack_max: "1000000"
+#   controlPlane:
+#     sysctls: {}
+#   staticWorkers:
+#     kernelModules: []
+
+# timeSync configures the time synchronization on hosts. If chrony is enabled,
+# chrony is installed and enabled on all hosts when they're provisioned
+# (systemd-timesyncd is configured on Flatcar Linux instead). maxClockSkew is
+# the maximum difference between the clock of hosts and the clock of the
+# machine running KubeOne, verified by 'kubeone preflight'.
+# timeSync:
+#   chrony: false
+#   ntpServers:
+#   - 0.pool.ntp.org
+#   maxClockSkew: 1s
+
+# hardening applies the hardening profile to the hosts and the Kubernetes
+# components. The "cis" profile follows the CIS Kubernetes Benchmark: it
+# restricts permissions of the Kubernetes files, disables profiling, restricts
+# TLS cipher suites, enables kubelet protectKernelDefaults and installs auditd
+# rules. Run 'kubeone verify cis' to report the compliance of the hosts.
+# hardening:
+#   profile: cis
+
+# securityModules controls how SELinux and AppArmor are handled on the hosts.
+# selinux is one of: permissive (default, SELinux is switched to permissive),
+# enforcing (container-selinux is installed and the Kubernetes directories are
+# labeled) or unmanaged (preflight fails if SELinux is enforcing). appArmor is
+# one of: configure (default, apparmor_parser is installed on Debian and
+# Ubuntu if AppArmor is enabled) or unmanaged.
+# securityModules:
+#   selinux: permissive
+#   appArmor: configure
+
+# firewall creates the firewall rules for the ports of etcd, kubelet, the
+# NodePort range and the CNI overlay, allowed from nodeCIDRs and the pod
+# subnet. The API server port is allowed from everywhere. backend is one of:
+# firewalld, ufw or nftables; if not set, firewalld is used if it's running,
+# and ufw if it's installed. The nftables backend drops the traffic to these
+# ports from other sources, without changing other traffic.
+# firewall:
+#   backend: firewalld
+#   nodeCIDRs:
+#   - 10.0.0.0/24
+
+# Use the CA provided by the user instead of the CA generated by kubeadm. The
+# root CA key is never required: the certificates are signed either by the
+# intermediate CA, if its key is provided, or by the signer command, which reads
+# a PEM encoded CSR on stdin and writes the signed certificate to stdout. With
+# the signer, kubeadm runs in the external CA mode, and dynamic workers are not
+# supported.
+# certificateAuthority:
+#   # Algorithm of the keys generated by kubeadm and KubeOne: RSA (default),
+#   # ECDSA or Ed25519. kubeadm doesn't support Ed25519 keys and keeps using RSA.
+#   keyAlgorithm: ECDSA
+#   # Validity of the certificates generated by KubeOne, e.g. the addons webhooks
+#   # certificates (default: 8760h)
+#   certificateValidity: 4380h
+#   # apply renews the control plane certificates expiring in less than
+#   # renewBefore (default: 2160h)
+#   renewBefore: 720h
+#   external:
+#     caCertificate: |
+#       -----BEGIN CERTIFICATE-----
+#       ...
+#       -----END CERTIFICATE-----
+#     intermediateCertificate: |
+#       -----BEGIN CERTIFICATE-----
+#       ...
+#       -----END CERTIFICATE-----
+#     signer:
+#       command: /usr/local/bin/sign-csr
+#       args: ["--profile", "kubernetes"]
+#     # Instead of the signer, the certificates can be signed with the CA key
+#     # stored in a PKCS#11 token (using pkcs11-tool) or in AWS KMS. The key
+#     # never leaves the key provider.
+#     # keyProvider:
+#     #   pkcs11:
+#     #     module: /usr/lib/softhsm/libsofthsm2.so
+#     #     tokenLabel: kubernetes
+#     #     keyID: "01"
+#     #     pin: "vault:secret/data/hsm#pin"
+#     #   awsKMS:
+#     #     keyID: alias/kubernetes-ca
+#     #     region: eu-west-1
+
+# nodeNaming configures how the Node names of the hosts without the explicit
+# hostname are derived. All nodes must be named in the same way, because the
+# cloud controller manager matches the Nodes to the instances by name on some
+# providers. The strategy isn't applied to the MachineDeployments, so with the
+# dynamic workers it must match the one used by machine-controller: fqdn on AWS
+# and hostname on other providers.
+# Changing the strategy of a provisioned cluster is not supported.
+# nodeNaming:
+#   # hostname, fqdn, privateIP or instanceID (AWS only) (default: hostname on
+#   # Azure, fqdn on other providers)
+#   strategy: hostname
+
 # Addons are Kubernetes manifests to be deployed after provisioning the cluster
 addons:
   enable: false
@@ -801,8 +1275,13 @@ addons:
   # globalParams is a key-value map of values passed to the addons templating engine,
   # to be used in the addons' manifests. The values defined here are passed to all
   # addons.
+  # Values can reference secrets in the external secret stores, which are resolved
+  # on the machine running KubeOne and never written to the disk:
+  # * "vault:<path>#<key>" - HashiCorp Vault (configured using VAULT_ADDR and VAULT_TOKEN)
+  # * "aws-secretsmanager:<secret-id>#<key>" - AWS Secrets Manager
   globalParams:
     key: value
+    # apiKey: "vault:secret/data/addons#apiKey"
   # addons is used to enable addons embedded in the KubeOne binary.
   # Currently backups-restic, default-storage-class, and unattended-upgrades are
   # available addons.
@@ -831,12 +1310,30 @@ addons:
 #     bastion: '4.3.2.1'
 #     bastionPort: 22  # can be left out if using the default (22)
 #     bastionUser: 'root'  # can be left out if using the default ('root')
+#     # Chain of bastion hosts traversed in order to reach the host, can't be
+#     # used along with 'bastion'. Authentication configured for the host
+#     # is used if neither sshPrivateKeyFile nor sshAgentSocket is set.
+#     # bastionHosts:
+#     # - host: '4.3.2.1'
+#     #   user: 'jump'
+#     # - host: '10.0.0.1'
+#     #   port: 2222
+#     #   sshPrivateKeyFile: '/home/me/.ssh/id_jump'
 #     sshPort: 22 # can be left out if using the default (22)
 #     sshUsername: root
 #     # You usually want to configure either a private key OR an
 #     # agent socket, but never both. The socket value can be
 #     # prefixed with "env:" to refer to an environment variable.
+#     # The private key can be read from Vault using the reference
+#     # like 'vault:secret/data/kubeone/ssh#privateKey'.
 #     sshPrivateKeyFile: '/home/me/.ssh/id_rsa'
+#     # Passphrase of the encrypted private key, given as reference to the
+#     # environment variable or to the external secret store. If left out,
+#     # the passphrase is prompted when needed.
+#     # sshPrivateKeyPassphrase: 'env:SSH_KEY_PASSPHRASE'
+#     # OpenSSH certificate signed by the CA for the private key, defaults
+#     # to the '<sshPrivateKeyFile>-cert.pub' file if it exists.
+#     # sshCertFile: '/home/me/.ssh/id_rsa-cert.pub'
 #     sshAgentSocket: 'env:SSH_AUTH_SOCK'
 #     # Taints is used to apply taints to the node.
 #     # If not provided defaults to TaintEffectNoSchedule, with key
@@ -867,6 +1364,79 @@ addons:
 #     # taints:
 #     # - key: ""
 #     #   effect: ""
+#     # nodeDrain overrides the cluster-wide nodeDrain configuration.
+#     # nodeDrain:
+#     #   ignorePodDisruptionBudgets: true
+#     # ignorePreflightErrors are ignored in addition to the cluster-wide ones.
+#     # ignorePreflightErrors:
+#     #   install:
+#     #   - Mem
+#     # nvidiaGPU installs the NVIDIA driver and container toolkit on the host.
+#     # Requires the nvidiaGPU feature to be enabled.
+#     # nvidiaGPU: true
+#     # kubelet configures the kubelet of the host. Kubelet of the joined host is
+#     # restarted when the configuration is changed.
+#     # kubelet:
+#     #   maxPods: 110
+#     #   systemReserved:
+#     #     cpu: 200m
+#     #     memory: 500Mi
+#     #   kubeReserved:
+#     #     memory: 500Mi
+#     #   evictionHard:
+#     #     memory.available: 100Mi
+#   # groups are named groups of hosts sharing the configuration. The host fields
+#   # which are not set are taken from the group template, and then defaulted in
+#   # the same way as the other hosts. The labels are merged, and the Nodes are
+#   # labeled with kubeone.io/static-worker-group=<name>.
+#   groups:
+#   - name: storage
+#     template:
+#       sshUsername: ubuntu
+#       sshPrivateKeyFile: '/home/me/.ssh/id_rsa'
+#       bastion: '4.3.2.1'
+#       labels:
+#         tier: storage
+#       taints:
+#       - key: storage
+#         effect: NoSchedule
+#       kubelet:
+#         maxPods: 50
+#     hosts:
+#     - publicAddress: '1.2.3.6'
+#       privateAddress: '172.18.0.3'
+#     - publicAddress: '1.2.3.7'
+#       privateAddress: '172.18.0.4'
+#   # staticPods are run by kubelet on all static workers. Manifests are saved
+#   # to /etc/kubernetes/manifests/kubeone-<name>.yaml. Manifests of static pods
+#   # removed from this list are removed from the hosts as well.
+#   # The same can be configured for control plane hosts in .controlPlane.staticPods.
+#   staticPods:
+#   - name: node-cache
+#     manifest: |
+#       apiVersion: v1
+#       kind: Pod
+#       metadata:
+#         name: node-cache
+#         namespace: kube-system
+#       spec:
+#         hostNetwork: true
+#         containers:
+#         - name: node-cache
+#           image: registry.example.com/node-cache:v1.0.0
+#   # hostDiscovery adds the running instances with all the given tags to the
+#   # hosts each time the manifest is loaded, using the cloud provider API.
+#   # Supported on AWS (instance tags), Hetzner (server labels) and OpenStack
+#   # (server metadata). template is used as the configuration of the
+#   # discovered hosts, the addresses are taken from the instances.
+#   # The same can be configured for control plane hosts in .controlPlane.hostDiscovery.
+#   hostDiscovery:
+#     tags:
+#       kubeone-cluster: '{{ .ClusterName }}'
+#       kubeone-role: static-worker
+#     template:
+#       sshUsername: ubuntu
+#       sshAgentSocket: 'env:SSH_AUTH_SOCK'
 
 # The API server can also be overwritten by Terraform. Provide the
 # external address of your load balancer or the public addresses of
@@ -880,6 +1450,28 @@ addons:
 # case, anything you configure in your "workers" sections is ignored.
 machineController:
   deploy: {{ .DeployMachineController }}
+  # Pin the machine-controller build by overriding the image repository
+  # and tag. Semantic version tags are validated against the Kubernetes
+  # version.
+  # imageRepository: "docker.io/kubermatic/machine-controller"
+  # imageTag: "v1.36.0"
+  # resources:
+  #   requests:
+  #     cpu: 100m
+  #     memory: 128Mi
+  #   limits:
+  #     memory: 256Mi
+  # nodeSelector replaces the default node selector, while tolerations are
+  # added to the default ones.
+  # nodeSelector:
+  #   node-role.kubernetes.io/master: ""
+  # tolerations:
+  # - key: "dedicated"
+  #   operator: Exists
+  #   effect: NoSchedule
+  # Extra flags are passed to machine-controller after the default flags.
+  # extraFlags:
+  #   node-csr-approver: "false"
 
 # Proxy is used to configure HTTP_PROXY, HTTPS_PROXY and NO_PROXY
 # for Docker daemon and kubelet, and to be used when provisioning cluster
@@ -898,6 +1490,11 @@ machineController:
 # dynamicWorkers:
 # - name: fra1-a
 #   replicas: 1
+#   # scaled by the cluster-autoscaler between minReplicas and maxReplicas,
+#   # requires the clusterAutoscaler feature
+#   # autoscaling:
+#   #   minReplicas: 1
+#   #   maxReplicas: 5
 #   providerSpec:
 #     labels:
 #       mylabel: 'fra1-a'
@@ -0,0 +1,226 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/koron-go/prefixw"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8c.io/kubeone/pkg/fleet"
+	"k8c.io/kubeone/pkg/tabwriter"
+)
+
+type fleetApplyOpts struct {
+	globalOptions
+	FleetFile                 string `longflag:"fleet"`
+	Parallelism               int    `longflag:"parallelism"`
+	AutoApprove               bool   `longflag:"auto-approve" shortflag:"y"`
+	UpgradeMachineDeployments bool   `longflag:"upgrade-machine-deployments"`
+}
+
+func fleetCmd(fs *pflag.FlagSet) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fleet",
+		Short: "Commands for managing multiple clusters at the same time",
+	}
+
+	cmd.AddCommand(fleetApplyCmd(fs))
+	return cmd
+}
+
+func fleetApplyCmd(fs *pflag.FlagSet) *cobra.Command {
+	opts := &fleetApplyOpts{}
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Reconcile multiple clusters at the same time",
+		Long: heredoc.Doc(`
+			Reconcile multiple clusters at the same time, the same way as 'kubeone apply' does for a
+			single cluster, and print the report of the results once all clusters are reconciled.
+
+			The clusters are defined either by the manifest (--manifest) containing multiple
+			KubeOneCluster documents, or by the fleet file (--fleet) referencing the manifest,
+			the Terraform output and the credentials file of each cluster:
+
+			  clusters:
+			  - name: edge-1
+			    manifest: edge-1/kubeone.yaml
+			    tfjson: edge-1/tf.json
+			  - name: edge-2
+			    manifest: edge-2/kubeone.yaml
+			    tfstateBackend: edge-2/backend.yaml
+			    credentials: edge-2/credentials.yaml
+
+			Relative paths in the fleet file are relative to the fleet file. The credentials file given
+			to the command is used for the clusters without their own credentials file.
+
+			The actions for all clusters are confirmed only once, before reconciling the clusters.
+			A failure of a cluster doesn't stop reconciling the other clusters. The output and the logs
+			of each cluster are prefixed with the cluster name.
+		`),
+		Args:    cobra.ExactArgs(0),
+		Example: `kubeone fleet apply --fleet fleet.yaml --parallelism 10`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			gopts, err := persistentGlobalOptions(fs)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			opts.globalOptions = *gopts
+
+			return runFleetApply(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&opts.FleetFile,
+		longFlagName(opts, "FleetFile"),
+		"",
+		"path to the fleet file referencing the clusters, instead of the multi-cluster manifest")
+
+	cmd.Flags().IntVar(
+		&opts.Parallelism,
+		longFlagName(opts, "Parallelism"),
+		4,
+		"maximum number of clusters reconciled at the same time")
+
+	cmd.Flags().BoolVarP(
+		&opts.AutoApprove,
+		longFlagName(opts, "AutoApprove"),
+		shortFlagName(opts, "AutoApprove"),
+		false,
+		"auto approve reconciling the clusters")
+
+	cmd.Flags().BoolVar(
+		&opts.UpgradeMachineDeployments,
+		longFlagName(opts, "UpgradeMachineDeployments"),
+		false,
+		"upgrade MachineDeployments objects")
+
+	return cmd
+}
+
+func runFleetApply(opts *fleetApplyOpts) error {
+	if opts.Parallelism < 1 {
+		return errors.New("--parallelism must be greater than 0")
+	}
+	if opts.Progress || opts.TimingsFile != "" || opts.MetricsFile != "" || opts.Pushgateway != "" {
+		return errors.New("--progress, --timings-file, --metrics-file and --metrics-pushgateway are not supported when managing multiple clusters")
+	}
+
+	members, err := fleetMembers(&opts.globalOptions, opts.FleetFile)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("The following clusters will be reconciled:")
+	for _, member := range members {
+		fmt.Printf("\t~ %s (%s)\n", member.Name, member.ManifestFile)
+	}
+	fmt.Println()
+
+	confirm, err := confirmCommand(opts.AutoApprove)
+	if err != nil {
+		return err
+	}
+
+	if !confirm {
+		fmt.Println("Operation canceled.")
+		return nil
+	}
+
+	results := fleet.Run(members, opts.Parallelism, func(member fleet.Member) error {
+		return runApply(fleetMemberApplyOpts(opts, member))
+	})
+
+	fmt.Println()
+	printFleetReport(results)
+
+	if failed := fleet.Failed(results); failed > 0 {
+		return errors.Errorf("failed to reconcile %d of %d clusters", failed, len(results))
+	}
+
+	return nil
+}
+
+// fleetMembers returns the clusters defined by the fleet file, if given, or
+// by the manifest otherwise
+func fleetMembers(opts *globalOptions, fleetFile string) ([]fleet.Member, error) {
+	if fleetFile != "" {
+		if opts.TerraformState != "" || opts.TerraformBackend != "" {
+			return nil, errors.New("--tfjson and --tfstate-backend can't be used with --fleet, set them in the fleet file instead")
+		}
+
+		return fleet.Load(fleetFile, opts.CredentialsFile)
+	}
+
+	members, err := fleet.FromManifest(opts.ManifestFile, opts.CredentialsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(members) > 1 && (opts.TerraformState != "" || opts.TerraformBackend != "") {
+		return nil, errors.New("--tfjson and --tfstate-backend can't be used with multi-cluster manifests, use the fleet file instead")
+	}
+	for i := range members {
+		members[i].TerraformState = opts.TerraformState
+		members[i].TerraformBackend = opts.TerraformBackend
+	}
+
+	return members, nil
+}
+
+// fleetMemberApplyOpts returns the apply options for reconciling the fleet
+// member. The planned actions are already confirmed for the whole fleet.
+func fleetMemberApplyOpts(opts *fleetApplyOpts, member fleet.Member) *applyOpts {
+	gopts := opts.globalOptions
+	gopts.ManifestFile = member.ManifestFile
+	gopts.TerraformState = member.TerraformState
+	gopts.TerraformBackend = member.TerraformBackend
+	gopts.CredentialsFile = member.CredentialsFile
+	gopts.manifestDocument = member.Document
+	gopts.fleetMember = member.Name
+
+	return &applyOpts{
+		globalOptions:             gopts,
+		AutoApprove:               true,
+		UpgradeMachineDeployments: opts.UpgradeMachineDeployments,
+		output:                    prefixw.New(os.Stdout, fmt.Sprintf("[%s] ", member.Name)),
+	}
+}
+
+func printFleetReport(results []fleet.Result) {
+	printer := tabwriter.GetNewTabWriter(os.Stdout)
+
+	fmt.Fprintln(printer, "CLUSTER\tRESULT\tDURATION\tERROR")
+	for _, result := range results {
+		status, message := "succeeded", "-"
+		if result.Err != nil {
+			status, message = "failed", result.Err.Error()
+		}
+
+		fmt.Fprintf(printer, "%s\t%s\t%s\t%s\n", result.Member.Name, status, result.Duration.Round(time.Second), message)
+	}
+	printer.Flush()
+}
@@ -124,6 +124,7 @@ func runInstall(opts *installOpts) error {
 	if err != nil {
 		return errors.Wrap(err, "failed to initialize State")
 	}
+	defer opts.reportProgress(s, "install")
 
 	// Validate credentials
 	_, err = credentials.ProviderCredentials(s.Cluster.CloudProvider, opts.CredentialsFile)
@@ -18,17 +18,41 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
 	"k8c.io/kubeone/pkg/kubeconfig"
+	"k8c.io/kubeone/pkg/state"
+)
+
+type kubeconfigOpts struct {
+	globalOptions
+	OIDC             bool          `longflag:"oidc"`
+	OIDCClientSecret string        `longflag:"oidc-client-secret"`
+	OIDCExtraScopes  []string      `longflag:"oidc-extra-scope"`
+	TTL              time.Duration `longflag:"ttl"`
+	User             string        `longflag:"user"`
+	Groups           []string      `longflag:"groups"`
+}
+
+const (
+	// defaultClientCertUser and defaultClientCertGroup are the identity of
+	// the client certificate minted by the --ttl flag, matching the identity
+	// of the kubeadm admin credentials
+	defaultClientCertUser  = "kubernetes-admin"
+	defaultClientCertGroup = "system:masters"
+	defaultClientCertTTL   = 24 * time.Hour
 )
 
 // KubeconfigCommand returns the structure for declaring the "install" subcommand.
 func kubeconfigCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	opts := &kubeconfigOpts{}
+
 	cmd := &cobra.Command{
 		Use:   "kubeconfig",
 		Short: "Download the kubeconfig file from master",
@@ -37,34 +61,141 @@ func kubeconfigCmd(rootFlags *pflag.FlagSet) *cobra.Command {
 
 			This command takes KubeOne manifest which contains information about hosts. It's possible to source information about
 			hosts from Terraform output, using the '--tfjson' flag.
+
+			With the '--oidc' flag, the kubeconfig authenticates the user against the OpenID Connect provider configured
+			in the manifest (.features.openidConnect), instead of using the admin certificate. Such kubeconfig can be
+			safely handed to the cluster users. It requires the kubelogin kubectl plugin (https://github.com/int128/kubelogin).
+
+			With the '--ttl', '--user' or '--groups' flags, a new client certificate is signed by the cluster CA with the
+			requested validity and identity, instead of exporting the long-lived admin certificate. Without '--user' and
+			'--groups', the certificate has the same identity as the admin certificate. Such certificates can't be revoked,
+			so keep their validity short.
+		`),
+		Example: heredoc.Doc(`
+			kubeone kubeconfig -m mycluster.yaml -t terraformoutput.json
+			kubeone kubeconfig -m mycluster.yaml --ttl 8h --user alice --groups admins
 		`),
-		Example: `kubeone kubeconfig -m mycluster.yaml -t terraformoutput.json`,
 		RunE: func(_ *cobra.Command, args []string) error {
 			gopts, err := persistentGlobalOptions(rootFlags)
 			if err != nil {
 				return errors.Wrap(err, "unable to get global flags")
 			}
 
-			return runKubeconfig(gopts)
+			opts.globalOptions = *gopts
+
+			return runKubeconfig(opts)
 		},
 	}
 
+	cmd.Flags().BoolVar(
+		&opts.OIDC,
+		longFlagName(opts, "OIDC"),
+		false,
+		"generate the kubeconfig authenticating users with the OpenID Connect provider of the cluster, instead of the admin certificate")
+
+	cmd.Flags().StringVar(
+		&opts.OIDCClientSecret,
+		longFlagName(opts, "OIDCClientSecret"),
+		"",
+		"secret of the OpenID Connect client, required only by the confidential clients")
+
+	cmd.Flags().StringSliceVar(
+		&opts.OIDCExtraScopes,
+		longFlagName(opts, "OIDCExtraScopes"),
+		nil,
+		"scopes requested in addition to openid (default: email and the groups claim, if used by the cluster)")
+
+	cmd.Flags().DurationVar(
+		&opts.TTL,
+		longFlagName(opts, "TTL"),
+		0,
+		fmt.Sprintf("validity of the client certificate signed by the cluster CA (default %s if --user or --groups is set)", defaultClientCertTTL))
+
+	cmd.Flags().StringVar(
+		&opts.User,
+		longFlagName(opts, "User"),
+		"",
+		fmt.Sprintf("user name (CommonName) of the client certificate signed by the cluster CA (default %q)", defaultClientCertUser))
+
+	cmd.Flags().StringSliceVar(
+		&opts.Groups,
+		longFlagName(opts, "Groups"),
+		nil,
+		fmt.Sprintf("groups (Organizations) of the client certificate signed by the cluster CA (default %q if --user is not set)", defaultClientCertGroup))
+
 	return cmd
 }
 
 // runKubeconfig downloads kubeconfig file
-func runKubeconfig(opts *globalOptions) error {
+func runKubeconfig(opts *kubeconfigOpts) error {
+	opts.ReadOnly = true
 	s, err := opts.BuildState()
 	if err != nil {
 		return errors.Wrap(err, "failed to initialize State")
 	}
 
+	clientCert := opts.TTL != 0 || opts.User != "" || len(opts.Groups) != 0
+	if clientCert && opts.OIDC {
+		return errors.New("--oidc can't be used together with --ttl, --user and --groups")
+	}
+
+	var oidc *kubeoneapi.OpenIDConnect
+	if opts.OIDC {
+		oidc = s.Cluster.Features.OpenIDConnect
+		if oidc == nil || !oidc.Enable {
+			return errors.New("--oidc requires the openidConnect feature to be enabled in the manifest")
+		}
+	}
+
 	konfig, err := kubeconfig.Download(s)
 	if err != nil {
 		return err
 	}
 
+	if oidc != nil {
+		konfig, err = kubeconfig.OIDC(konfig, s.Cluster.Name, oidc.Config, kubeconfig.OIDCOptions{
+			ClientSecret: opts.OIDCClientSecret,
+			ExtraScopes:  opts.OIDCExtraScopes,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to generate the OpenID Connect kubeconfig")
+		}
+	}
+
+	if clientCert {
+		konfig, err = clientCertKubeconfig(s, konfig, opts)
+		if err != nil {
+			return errors.Wrap(err, "failed to generate the client certificate kubeconfig")
+		}
+	}
+
 	fmt.Println(string(konfig))
 
 	return nil
 }
+
+// clientCertKubeconfig converts the admin kubeconfig to the kubeconfig using
+// a new client certificate signed by the cluster CA
+func clientCertKubeconfig(s *state.State, adminKubeconfig []byte, opts *kubeconfigOpts) ([]byte, error) {
+	certOpts := kubeconfig.ClientCertificateOptions{
+		User:   opts.User,
+		Groups: opts.Groups,
+		TTL:    opts.TTL,
+	}
+	if certOpts.User == "" {
+		certOpts.User = defaultClientCertUser
+		if len(certOpts.Groups) == 0 {
+			certOpts.Groups = []string{defaultClientCertGroup}
+		}
+	}
+	if certOpts.TTL == 0 {
+		certOpts.TTL = defaultClientCertTTL
+	}
+
+	issuer, err := kubeconfig.CAIssuer(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return kubeconfig.ClientCertificate(adminKubeconfig, issuer, s.Cluster.Name, certOpts)
+}
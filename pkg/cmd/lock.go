@@ -0,0 +1,56 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/clusterlock"
+	"k8c.io/kubeone/pkg/state"
+)
+
+// newClusterLock returns the lock preventing other operations from running
+// against the cluster at the same time
+func newClusterLock(s *state.State, operation string, force bool) *clusterlock.Lock {
+	return clusterlock.New(clusterlock.Options{
+		Cluster:   s.Cluster.Name,
+		Operation: operation,
+		Force:     force,
+		Logger:    s.Logger,
+	})
+}
+
+// acquireClusterLock acquires the lock in the state store and, once the
+// Kubernetes client is initialized, in the cluster
+func acquireClusterLock(s *state.State, lock *clusterlock.Lock) error {
+	err := lock.AcquireState(s)
+
+	var locked *clusterlock.LockedError
+	if errors.As(err, &locked) {
+		return errors.Wrap(err, "another operation is running against the cluster, wait until it's finished, or use --force-unlock if the lock is stale")
+	}
+
+	return err
+}
+
+func releaseClusterLock(s *state.State, lock *clusterlock.Lock) {
+	if err := lock.Release(context.Background()); err != nil {
+		s.Logger.Warnf("Failed to release the cluster lock: %v", err)
+	}
+}
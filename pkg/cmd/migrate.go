@@ -17,6 +17,8 @@ limitations under the License.
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -38,27 +40,53 @@ func migrateCmd(fs *pflag.FlagSet) *cobra.Command {
 	return cmd
 }
 
+type migrateContainerdOptions struct {
+	globalOptions
+	AutoApprove bool `longflag:"auto-approve" shortflag:"y"`
+}
+
 func migrateToContainerdCmd(fs *pflag.FlagSet) *cobra.Command {
-	return &cobra.Command{
+	opts := &migrateContainerdOptions{}
+
+	cmd := &cobra.Command{
 		Use:   "to-containerd",
 		Short: "Migrate live cluster from docker to containerd",
 		Long: heredoc.Doc(`
-
 			Following the dockershim deprecation https://kubernetes.io/blog/2020/12/02/dockershim-faq/
 			this command helps to migrate Container Runtime to ContainerD.
+
+			Nodes are migrated one by one. Each node is drained, docker containers
+			are stopped, containerd is configured, kubelet is switched to containerd
+			and the node is uncordoned once it's ready again. Nodes that are already
+			using containerd are skipped, so the command can be safely rerun if it fails.
+
+			The KubeOneCluster manifest must have containerd enabled
+			(.containerRuntime.containerd).
 		`),
+		Example: `kubeone migrate to-containerd --manifest mycluster.yaml -t .`,
 		RunE: func(_ *cobra.Command, _ []string) error {
 			gopts, err := persistentGlobalOptions(fs)
 			if err != nil {
 				return errors.Wrap(err, "unable to get global flags")
 			}
 
-			return runMigrateToContainerd(gopts)
+			opts.globalOptions = *gopts
+
+			return runMigrateToContainerd(opts)
 		},
 	}
+
+	cmd.Flags().BoolVarP(
+		&opts.AutoApprove,
+		longFlagName(opts, "AutoApprove"),
+		shortFlagName(opts, "AutoApprove"),
+		false,
+		"auto approve migration")
+
+	return cmd
 }
 
-func runMigrateToContainerd(opts *globalOptions) error {
+func runMigrateToContainerd(opts *migrateContainerdOptions) error {
 	s, err := opts.BuildState()
 	if err != nil {
 		return errors.Wrap(err, "failed to initialize State")
@@ -79,7 +107,25 @@ func runMigrateToContainerd(opts *globalOptions) error {
 		return errors.New("the target cluster is not healthy, please run 'kubeone apply' first")
 	}
 
-	return errors.Wrap(tasks.WithContainerDMigration(nil).Run(s), "failed to get cluster status")
+	fmt.Println("The following nodes will be migrated to containerd, one by one:")
+	for _, hosts := range [][]state.Host{s.LiveCluster.ControlPlane, s.LiveCluster.StaticWorkers} {
+		for _, host := range hosts {
+			fmt.Printf("\t~ %s (%s)\n", host.Config.Hostname, host.Config.PublicAddress)
+		}
+	}
+	fmt.Println()
+
+	confirm, err := confirmCommand(opts.AutoApprove)
+	if err != nil {
+		return err
+	}
+
+	if !confirm {
+		s.Logger.Println("Operation canceled.")
+		return nil
+	}
+
+	return errors.Wrap(tasks.WithContainerDMigration(nil).Run(s), "failed to migrate to containerd")
 }
 
 type migrateCCMOptions struct {
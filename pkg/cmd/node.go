@@ -0,0 +1,153 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8c.io/kubeone/pkg/tasks"
+)
+
+type nodeRemoveOpts struct {
+	globalOptions
+	AutoApprove bool `longflag:"auto-approve" shortflag:"y"`
+}
+
+func nodeCmd(fs *pflag.FlagSet) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "node",
+		Short: "Commands for managing individual cluster nodes",
+	}
+
+	cmd.AddCommand(nodeRemoveCmd(fs))
+	return cmd
+}
+
+func nodeRemoveCmd(fs *pflag.FlagSet) *cobra.Command {
+	opts := &nodeRemoveOpts{}
+
+	cmd := &cobra.Command{
+		Use:   "remove <node>",
+		Short: "Remove the control plane or static worker node from the cluster",
+		Long: heredoc.Doc(`
+			Remove the control plane or static worker node from the cluster. The node is given by its hostname,
+			public or private address, and must be defined in the KubeOneCluster manifest.
+
+			The node is cordoned and drained, its etcd member is removed if it's a control plane node, the node
+			is reset using 'kubeadm reset' and its Node object is deleted. The leader control plane node and the
+			last control plane node can't be removed.
+
+			After the node is removed, remove it from the KubeOneCluster manifest (or the Terraform output) as well.
+			Nodes which are not reachable over SSH anymore should be removed from the manifest instead, after which
+			'kubeone apply' repairs the cluster.
+		`),
+		Args:    cobra.ExactArgs(1),
+		Example: `kubeone node remove -m mycluster.yaml worker-2`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			gopts, err := persistentGlobalOptions(fs)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			opts.globalOptions = *gopts
+
+			return runNodeRemove(opts, args[0])
+		},
+	}
+
+	cmd.Flags().BoolVarP(
+		&opts.AutoApprove,
+		longFlagName(opts, "AutoApprove"),
+		shortFlagName(opts, "AutoApprove"),
+		false,
+		"auto approve plan")
+
+	return cmd
+}
+
+func runNodeRemove(opts *nodeRemoveOpts, name string) error {
+	s, err := opts.BuildState()
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize State")
+	}
+
+	// Probe the cluster for the actual state and the needed tasks.
+	probbing := tasks.WithHostnameOS(nil)
+	probbing = tasks.WithProbes(probbing)
+
+	if err = probbing.Run(s); err != nil {
+		return err
+	}
+
+	if !s.LiveCluster.IsProvisioned() {
+		return errors.New("the target cluster is not provisioned")
+	}
+
+	host, controlPlane, found := tasks.FindHost(s.Cluster, name)
+	if !found {
+		return errors.Errorf("host %q is not defined in the manifest", name)
+	}
+	if host.IsLeader {
+		return errors.Errorf("host %q is the leader, make another control plane node the leader first", name)
+	}
+	if controlPlane && len(s.Cluster.ControlPlane.Hosts) < 2 {
+		return errors.Errorf("host %q is the last control plane node", name)
+	}
+
+	role := "static worker"
+	if controlPlane {
+		role = "control plane"
+	}
+
+	fmt.Println("The following actions will be taken: ")
+	fmt.Println("Run with --verbose flag for more information.")
+	fmt.Printf("\t- remove %s node %q (%s)\n", role, host.Hostname, host.PrivateAddress)
+
+	tasksToRun := tasks.WithRemoveNode(nil, host, controlPlane)
+	for _, op := range tasksToRun.Descriptions(s) {
+		fmt.Printf("\t\t~ %s\n", op)
+	}
+
+	if controlPlane && (len(s.Cluster.ControlPlane.Hosts)-1)%2 == 0 {
+		fmt.Println()
+		fmt.Printf("\t! %d control plane nodes will remain, an odd number of etcd members is recommended\n", len(s.Cluster.ControlPlane.Hosts)-1)
+	}
+
+	fmt.Println()
+	confirm, err := confirmCommand(opts.AutoApprove)
+	if err != nil {
+		return err
+	}
+
+	if !confirm {
+		s.Logger.Println("Operation canceled.")
+		return nil
+	}
+
+	if err = tasksToRun.Run(s); err != nil {
+		return errors.Wrapf(err, "failed to remove node %q", name)
+	}
+
+	s.Logger.Infof("Node %q is removed, remove it from the KubeOneCluster manifest as well.", host.Hostname)
+
+	return nil
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	operatorv1alpha1 "k8c.io/kubeone/pkg/apis/operator/v1alpha1"
+	"k8c.io/kubeone/pkg/encryption"
+	"k8c.io/kubeone/pkg/logging"
+	"k8c.io/kubeone/pkg/operator"
+
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrl "sigs.k8s.io/controller-runtime"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type operatorOpts struct {
+	globalOptions
+	Kubeconfig              string        `longflag:"kubeconfig"`
+	Namespace               string        `longflag:"namespace"`
+	WorkDir                 string        `longflag:"work-dir"`
+	ResyncPeriod            time.Duration `longflag:"resync-period"`
+	Timeout                 time.Duration `longflag:"timeout"`
+	MaxConcurrentReconciles int           `longflag:"max-concurrent-reconciles"`
+	InstallCRD              bool          `longflag:"install-crd"`
+	LeaderElect             bool          `longflag:"leader-elect"`
+	MetricsBindAddress      string        `longflag:"metrics-bind-address"`
+}
+
+func operatorCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	opts := &operatorOpts{}
+
+	cmd := &cobra.Command{
+		Use:   "operator",
+		Short: "Run the operator reconciling KubeOneCluster custom resources",
+		Long: heredoc.Doc(`
+			Run the operator watching the KubeOneCluster custom resources (operator.kubeone.io/v1alpha1)
+			in the management cluster, and reconciling the target clusters the same way as 'kubeone apply'.
+			The result of the last reconciliation is stored in the status of the custom resource.
+
+			  apiVersion: operator.kubeone.io/v1alpha1
+			  kind: KubeOneCluster
+			  metadata:
+			    name: edge-1
+			  spec:
+			    manifest:
+			      apiVersion: kubeone.io/v1beta1
+			      kind: KubeOneCluster
+			      versions:
+			        kubernetes: 1.22.2
+			      ...
+			    sshPrivateKeySecretRef:
+			      name: edge-1-ssh
+			      key: id_rsa
+			    credentialsSecretRef:
+			      name: edge-1-credentials
+
+			The SSH private key is used for the hosts without their own private key file. The credentials
+			Secret holds the same keys as the credentials file given with the '--credentials' flag.
+
+			Clusters are reconciled when their spec changes, and every '--resync-period' afterwards,
+			correcting the drift. Failed reconciliations are retried with the backoff. Set '.spec.paused'
+			to stop reconciling the cluster. Deleting the custom resource doesn't reset the cluster.
+
+			The PKI backups are kept in the '--work-dir' directory, in the <namespace>/<name> subdirectory.
+		`),
+		Args:    cobra.ExactArgs(0),
+		Example: `kubeone operator --namespace kubeone --work-dir /var/lib/kubeone`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			gopts, err := persistentGlobalOptions(rootFlags)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			opts.globalOptions = *gopts
+
+			return runOperator(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&opts.Kubeconfig,
+		longFlagName(opts, "Kubeconfig"),
+		"",
+		"path to the kubeconfig of the management cluster (default: in-cluster config or KUBECONFIG environment variable)")
+
+	cmd.Flags().StringVar(
+		&opts.Namespace,
+		longFlagName(opts, "Namespace"),
+		"",
+		"namespace to watch for KubeOneCluster custom resources (default: all namespaces)")
+
+	cmd.Flags().StringVar(
+		&opts.WorkDir,
+		longFlagName(opts, "WorkDir"),
+		"./kubeone-operator",
+		"directory keeping the files of the clusters, such as the PKI backups")
+
+	cmd.Flags().DurationVar(
+		&opts.ResyncPeriod,
+		longFlagName(opts, "ResyncPeriod"),
+		time.Hour,
+		"how often the clusters are reconciled again, correcting the drift. Zero disables resyncing")
+
+	cmd.Flags().DurationVar(
+		&opts.Timeout,
+		longFlagName(opts, "Timeout"),
+		time.Hour,
+		"maximum duration of a single reconciliation")
+
+	cmd.Flags().IntVar(
+		&opts.MaxConcurrentReconciles,
+		longFlagName(opts, "MaxConcurrentReconciles"),
+		4,
+		"maximum number of clusters reconciled at the same time")
+
+	cmd.Flags().BoolVar(
+		&opts.InstallCRD,
+		longFlagName(opts, "InstallCRD"),
+		true,
+		"create or update the KubeOneCluster CRD on start")
+
+	cmd.Flags().BoolVar(
+		&opts.LeaderElect,
+		longFlagName(opts, "LeaderElect"),
+		false,
+		"use the leader election, so only one of the operator replicas reconciles the clusters")
+
+	cmd.Flags().StringVar(
+		&opts.MetricsBindAddress,
+		longFlagName(opts, "MetricsBindAddress"),
+		"0",
+		"address the metrics are served on, 0 disables serving the metrics")
+
+	return cmd
+}
+
+func runOperator(opts *operatorOpts) error {
+	if opts.MaxConcurrentReconciles < 1 {
+		return errors.New("--max-concurrent-reconciles must be greater than 0")
+	}
+
+	logger, err := logging.New(os.Stderr, logging.Options{
+		Format:  opts.LogFormat,
+		Levels:  opts.LogLevel,
+		Verbose: opts.Verbose,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize logger")
+	}
+
+	cfg, err := ctrl.GetConfig()
+	if opts.Kubeconfig != "" {
+		cfg, err = loadOperatorKubeconfig(opts)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to load the kubeconfig of the management cluster")
+	}
+
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{
+		clientgoscheme.AddToScheme,
+		apiextensions.AddToScheme,
+		operatorv1alpha1.AddToScheme,
+	} {
+		if err = add(scheme); err != nil {
+			return errors.Wrap(err, "failed to build the scheme")
+		}
+	}
+
+	ctx := ctrl.SetupSignalHandler()
+
+	if opts.InstallCRD {
+		// The manager's client can't be used before the manager is started
+		c, cerr := dynclient.New(cfg, dynclient.Options{Scheme: scheme})
+		if cerr != nil {
+			return errors.Wrap(cerr, "failed to create the management cluster client")
+		}
+
+		if err = operator.InstallCRD(ctx, c); err != nil {
+			return err
+		}
+	}
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:             scheme,
+		Namespace:          opts.Namespace,
+		LeaderElection:     opts.LeaderElect,
+		LeaderElectionID:   "kubeone-operator",
+		MetricsBindAddress: opts.MetricsBindAddress,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create the manager")
+	}
+
+	r := &operator.Reconciler{
+		Client:       mgr.GetClient(),
+		Logger:       logger,
+		WorkDir:      opts.WorkDir,
+		ResyncPeriod: opts.ResyncPeriod,
+		Timeout:      opts.Timeout,
+	}
+	if err = r.SetupWithManager(mgr, opts.MaxConcurrentReconciles); err != nil {
+		return errors.Wrap(err, "failed to set up the controller")
+	}
+
+	logger.Infoln("Starting the operator...")
+
+	return errors.Wrap(mgr.Start(ctx), "failed to run the operator")
+}
+
+// loadOperatorKubeconfig loads the kubeconfig given by the --kubeconfig flag,
+// decrypting it if it's been written by KubeOne with the --encryption-key flag
+func loadOperatorKubeconfig(opts *operatorOpts) (*rest.Config, error) {
+	buf, err := ioutil.ReadFile(opts.Kubeconfig)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var key encryption.Key
+	if opts.EncryptionKey != "" {
+		if key, err = encryption.New(opts.EncryptionKey); err != nil {
+			return nil, errors.Wrap(err, "failed to initialize encryption key")
+		}
+	}
+
+	if buf, err = encryption.Decrypt(context.Background(), key, buf); err != nil {
+		return nil, errors.Wrapf(err, "failed to decrypt %s", opts.Kubeconfig)
+	}
+
+	return clientcmd.RESTConfigFromKubeConfig(buf)
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8c.io/kubeone/pkg/state"
+	"k8c.io/kubeone/pkg/tasks"
+	"k8c.io/kubeone/pkg/upgradeplan"
+)
+
+func planCmd(fs *pflag.FlagSet) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Commands for showing what KubeOne would do, without changing the cluster",
+	}
+
+	cmd.AddCommand(planUpgradeCmd(fs))
+	return cmd
+}
+
+func planUpgradeCmd(fs *pflag.FlagSet) *cobra.Command {
+	return &cobra.Command{
+		Use:   "upgrade",
+		Short: "Show the upgrade plan for the cluster",
+		Long: heredoc.Doc(`
+			Show the sequence of upgrades needed to reach the Kubernetes version
+			defined in the KubeOneCluster manifest, along with the actions taken on each node.
+
+			Versions of the nodes are validated against the kubeadm and Kubernetes
+			version skew policies. kubeadm supports upgrading only one minor version
+			at the time, so upgrading across multiple minor versions requires running
+			'kubeone apply' for each intermediate minor version, or running
+			'kubeone apply --upgrade-sequentially'.
+
+			This command doesn't change the cluster.
+		`),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			gopts, err := persistentGlobalOptions(fs)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			return runPlanUpgrade(gopts)
+		},
+	}
+}
+
+func runPlanUpgrade(opts *globalOptions) error {
+	opts.ReadOnly = true
+	s, err := opts.BuildState()
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize State")
+	}
+
+	// Probe the cluster for the actual state and the needed tasks.
+	probbing := tasks.WithHostnameOS(nil)
+	probbing = tasks.WithProbes(probbing)
+
+	if err = probbing.Run(s); err != nil {
+		return err
+	}
+
+	if !s.LiveCluster.IsProvisioned() {
+		return errors.New("the target cluster is not provisioned")
+	}
+
+	target := s.LiveCluster.ExpectedVersion
+	nodes := planNodes(s)
+
+	currentVersion, err := upgradeplan.ValidateSkew(nodes, target)
+	if err != nil {
+		return errors.Wrap(err, "version skew check failed")
+	}
+
+	hops, err := upgradeplan.Path(currentVersion, target)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Control plane version: v%s\n", currentVersion)
+	fmt.Printf("Requested version: v%s\n", target)
+	fmt.Println()
+
+	if len(hops) == 0 {
+		fmt.Println("The control plane is already running the requested version.")
+		printPlanOutdatedWorkers(nodes, target)
+		return nil
+	}
+
+	fmt.Println("The following upgrades are needed:")
+	for i, hop := range hops {
+		fmt.Printf("\t%d. %s\n", i+1, hop)
+	}
+	if len(hops) > 1 {
+		fmt.Println()
+		fmt.Println("Upgrading across multiple minor versions requires running 'kubeone apply' for each upgrade,")
+		fmt.Println("with .versions.kubernetes set to the latest patch release of the intermediate minor version.")
+		fmt.Println("Alternatively, run 'kubeone apply --upgrade-sequentially' to do all upgrades at once.")
+	}
+
+	fmt.Println()
+	fmt.Println("The following actions will be taken on each node:")
+	for i, node := range nodes {
+		role := "static worker"
+		if node.ControlPlane {
+			role = "control plane follower"
+			if i == 0 {
+				role = "control plane leader"
+			}
+		}
+
+		fmt.Printf("Host %q (%s, v%s):\n", node.Name, role, node.Version)
+		for _, hop := range hops {
+			if !planNodeNeedsHop(node.Version, hop) {
+				fmt.Printf("\t= %s: already up to date\n", hop.ToString())
+				continue
+			}
+
+			kubeadmCmd := "kubeadm upgrade node"
+			if node.ControlPlane && i == 0 {
+				kubeadmCmd = "kubeadm upgrade apply"
+			}
+
+			fmt.Printf("\t~ %s: drain, upgrade kubeadm and CNI binaries, %s, upgrade kubelet and kubectl, uncordon\n", hop.ToString(), kubeadmCmd)
+		}
+	}
+
+	if s.Cluster.MachineController.Deploy {
+		fmt.Println()
+		fmt.Println("MachineDeployments are upgraded only if 'kubeone apply' is run with the --upgrade-machine-deployments flag.")
+	}
+
+	return nil
+}
+
+// planNodes returns the control plane nodes (leader first) and the static
+// worker nodes with their current kubelet versions
+func planNodes(s *state.State) []upgradeplan.Node {
+	nodes := []upgradeplan.Node{}
+
+	for _, host := range s.LiveCluster.ControlPlane {
+		nodes = append(nodes, upgradeplan.Node{
+			Name:         host.Config.Hostname,
+			ControlPlane: true,
+			Version:      host.Kubelet.Version,
+		})
+	}
+
+	// Leader is upgraded first
+	for i := range nodes {
+		if s.LiveCluster.ControlPlane[i].Config.IsLeader {
+			nodes[0], nodes[i] = nodes[i], nodes[0]
+			break
+		}
+	}
+
+	for _, host := range s.LiveCluster.StaticWorkers {
+		nodes = append(nodes, upgradeplan.Node{
+			Name:    host.Config.Hostname,
+			Version: host.Kubelet.Version,
+		})
+	}
+
+	return nodes
+}
+
+func planNodeNeedsHop(version *semver.Version, hop upgradeplan.Hop) bool {
+	if hop.Intermediate {
+		return version.Minor() < hop.To.Minor()
+	}
+
+	return version.LessThan(hop.To)
+}
+
+func printPlanOutdatedWorkers(nodes []upgradeplan.Node, target *semver.Version) {
+	for _, node := range nodes {
+		if !node.ControlPlane && node.Version.LessThan(target) {
+			fmt.Printf("Host %q (static worker, v%s) will be upgraded to v%s by running 'kubeone apply'\n", node.Name, node.Version, target)
+		}
+	}
+}
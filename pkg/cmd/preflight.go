@@ -0,0 +1,178 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8c.io/kubeone/pkg/credentials"
+	"k8c.io/kubeone/pkg/hostpreflight"
+	"k8c.io/kubeone/pkg/tabwriter"
+
+	kyaml "sigs.k8s.io/yaml"
+)
+
+type preflightOpts struct {
+	globalOptions
+	Output string `longflag:"output" shortflag:"o"`
+}
+
+func preflightCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	opts := &preflightOpts{}
+
+	cmd := &cobra.Command{
+		Use:   "preflight",
+		Short: "Check the hosts meet the prerequisites of the cluster",
+		Long: heredoc.Doc(`
+			Check the hosts meet the prerequisites of the cluster, without changing them.
+
+			The following is checked on each host: SSH connectivity, passwordless sudo, CPUs and memory
+			(following .clusterSize for control plane hosts, if declared), swap, cgroup version, time
+			synchronization, SELinux and AppArmor state, kernel modules, ports used by the cluster
+			components, and whether the ports of the other hosts are reachable.
+
+			The credentials are validated using the cloud provider API as well, and the permissions
+			required by KubeOne and machine-controller which are missing are reported.
+
+			The command fails if any check fails on any host. Checks can be skipped or made warning-only
+			in the .preflight section of the manifest.
+		`),
+		Args:    cobra.ExactArgs(0),
+		Example: `kubeone preflight -m mycluster.yaml -t tf.json`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			gopts, err := persistentGlobalOptions(rootFlags)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			opts.globalOptions = *gopts
+
+			return runPreflight(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(
+		&opts.Output,
+		longFlagName(opts, "Output"),
+		shortFlagName(opts, "Output"),
+		statusOutputTable,
+		fmt.Sprintf("output format, one of: %s, %s, %s", statusOutputTable, statusOutputJSON, statusOutputYAML))
+
+	return cmd
+}
+
+func runPreflight(opts *preflightOpts) error {
+	if opts.Output != statusOutputTable && opts.Output != statusOutputJSON && opts.Output != statusOutputYAML {
+		return errors.Errorf("unknown output format %q", opts.Output)
+	}
+
+	s, err := opts.BuildState()
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize State")
+	}
+
+	creds, err := credentials.ProviderCredentials(s.Cluster.CloudProvider, opts.CredentialsFile)
+	if err != nil {
+		return errors.Wrap(err, "failed to validate credentials")
+	}
+
+	if err = checkCredentials(s, creds); err != nil {
+		return err
+	}
+
+	report, err := hostpreflight.Run(s)
+	if err != nil {
+		return errors.Wrap(err, "failed to run preflight checks")
+	}
+
+	switch opts.Output {
+	case statusOutputJSON:
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal preflight report")
+		}
+		fmt.Println(string(out))
+	case statusOutputYAML:
+		out, err := kyaml.Marshal(report)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal preflight report")
+		}
+		fmt.Print(string(out))
+	default:
+		printPreflightReport(report)
+	}
+
+	if !report.Passed {
+		return errors.New("preflight checks failed")
+	}
+
+	return nil
+}
+
+// printPreflightReport prints the pass/fail matrix of the hosts and the
+// checks, followed by the details of the checks which didn't pass
+func printPreflightReport(report *hostpreflight.Report) {
+	printer := tabwriter.GetNewTabWriter(os.Stdout)
+
+	fmt.Fprintf(printer, "HOST\tROLE\t")
+	for _, check := range hostpreflight.Checks {
+		fmt.Fprintf(printer, "%s\t", strings.ToUpper(check))
+	}
+	fmt.Fprintln(printer, "")
+
+	for _, host := range report.Hosts {
+		statuses := map[string]string{}
+		for _, res := range host.Results {
+			statuses[res.Check] = res.Status
+		}
+
+		fmt.Fprintf(printer, "%s\t%s\t", host.PublicAddress, host.Role)
+		for _, check := range hostpreflight.Checks {
+			status, ok := statuses[check]
+			if !ok {
+				status = "-"
+			}
+			fmt.Fprintf(printer, "%s\t", status)
+		}
+		fmt.Fprintln(printer, "")
+	}
+	printer.Flush()
+
+	details := []string{}
+	for _, host := range report.Hosts {
+		for _, res := range host.Results {
+			if res.Status != hostpreflight.StatusPass {
+				details = append(details, fmt.Sprintf("%s %s [%s]: %s", host.PublicAddress, res.Check, res.Status, res.Message))
+			}
+		}
+	}
+
+	if len(details) > 0 {
+		fmt.Println()
+		for _, d := range details {
+			fmt.Println(d)
+		}
+	}
+}
@@ -17,7 +17,9 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"path/filepath"
 
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/pkg/errors"
@@ -29,13 +31,28 @@ import (
 	"k8c.io/kubeone/pkg/tasks"
 
 	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	resetScopeCluster      = "cluster"
+	resetScopeWorkers      = "workers"
+	resetScopeControlPlane = "control-plane"
+	resetScopeNode         = "node"
+	resetScopeAddons       = "addons"
 )
 
 type resetOpts struct {
 	globalOptions
-	AutoApprove    bool `longflag:"auto-approve" shortflag:"y"`
-	DestroyWorkers bool `longflag:"destroy-workers"`
-	RemoveBinaries bool `longflag:"remove-binaries"`
+	AutoApprove            bool   `longflag:"auto-approve" shortflag:"y"`
+	Scope                  string `longflag:"scope"`
+	DestroyWorkers         bool   `longflag:"destroy-workers"`
+	KeepMachineDeployments bool   `longflag:"keep-machine-deployments"`
+	DrainNodes             bool   `longflag:"drain-nodes"`
+	RemoveBinaries         bool   `longflag:"remove-binaries"`
+	PreservePVCVolumes     bool   `longflag:"preserve-pvc-volumes"`
+	ForceUnlock            bool   `longflag:"force-unlock"`
 }
 
 func (opts *resetOpts) BuildState() (*state.State, error) {
@@ -44,8 +61,25 @@ func (opts *resetOpts) BuildState() (*state.State, error) {
 		return nil, errors.Wrap(err, "failed to build State")
 	}
 
-	s.DestroyWorkers = opts.DestroyWorkers
+	s.DestroyWorkers = opts.DestroyWorkers || opts.Scope == resetScopeWorkers
 	s.RemoveBinaries = opts.RemoveBinaries
+	s.PreservePVCVolumes = opts.PreservePVCVolumes
+	s.DrainNodes = opts.DrainNodes
+
+	if opts.KeepMachineDeployments {
+		if !s.DestroyWorkers {
+			return nil, errors.New("--keep-machine-deployments can't be used together with --destroy-workers=false")
+		}
+
+		fullPath, err := filepath.Abs(opts.ManifestFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to get absolute path to the cluster manifest")
+		}
+
+		s.KeepMachineDeployments = true
+		s.MachineDeploymentsFile = filepath.Join(filepath.Dir(fullPath), fmt.Sprintf("%s-machinedeployments.yaml", s.Cluster.Name))
+	}
+
 	return s, nil
 }
 
@@ -54,23 +88,50 @@ func resetCmd(rootFlags *pflag.FlagSet) *cobra.Command {
 	opts := &resetOpts{}
 
 	cmd := &cobra.Command{
-		Use:   "reset",
+		Use:   "reset [node]",
 		Short: "Revert changes",
 		Long: heredoc.Doc(`
 			Undo all changes done by KubeOne to the configured machines.
 
+			By default, the whole cluster is reset. The '--scope' flag limits the reset to a part of the cluster:
+			  * workers: destroy the machine-controller managed worker nodes (MachineDeployments) only
+			  * control-plane: reset the control plane nodes only
+			  * node: wipe a single control plane or static worker node, given by its hostname, public or private
+			    address, so it's provisioned again by 'kubeone apply'
+			  * addons: delete the addons from the addons directory and the addons listed in the manifest
+
+			By default, all MachineDeployments are deleted together with their machines. With the
+			'--keep-machine-deployments' flag, MachineDeployments are scaled down to zero replicas instead
+			and saved to the <cluster-name>-machinedeployments.yaml file next to the KubeOne manifest, so
+			worker pools can be restored with 'kubectl apply' after the cluster is provisioned again.
+
+			Before the cluster is reset, the claims of the dynamically provisioned volumes are deleted, together
+			with the pods using them, so the cloud volumes are deleted by the provisioners. Use the
+			'--preserve-pvc-volumes' flag to keep the cloud volumes.
+
+			The hosts, MachineDeployments and volumes to be destroyed are printed, and the cluster name must be
+			typed to confirm the reset, unless the '--auto-approve' flag is used.
+
 			This command takes KubeOne manifest which contains information about hosts. It's possible to source information about
 			hosts from Terraform output, using the '--tfjson' flag.
 		`),
-		Example: `kubeone reset -m mycluster.yaml -t terraformoutput.json`,
+		Example: heredoc.Doc(`
+			kubeone reset -m mycluster.yaml -t terraformoutput.json
+			kubeone reset -m mycluster.yaml -t terraformoutput.json --scope workers
+			kubeone reset -m mycluster.yaml -t terraformoutput.json --scope node worker-2
+		`),
 		RunE: func(_ *cobra.Command, args []string) error {
 			gopts, err := persistentGlobalOptions(rootFlags)
 			if err != nil {
 				return errors.Wrap(err, "unable to get global flags")
 			}
 
+			if err = validateResetScope(opts.Scope, args); err != nil {
+				return err
+			}
+
 			opts.globalOptions = *gopts
-			return runReset(opts)
+			return runReset(opts, args)
 		},
 	}
 
@@ -81,65 +142,141 @@ func resetCmd(rootFlags *pflag.FlagSet) *cobra.Command {
 		false,
 		"auto approve reset")
 
+	cmd.Flags().StringVar(
+		&opts.Scope,
+		longFlagName(opts, "Scope"),
+		resetScopeCluster,
+		"part of the cluster to reset, one of: cluster, workers, control-plane, node, addons")
+
 	cmd.Flags().BoolVar(
 		&opts.DestroyWorkers,
 		longFlagName(opts, "DestroyWorkers"),
 		true,
 		"destroy all worker machines before resetting the cluster")
 
+	cmd.Flags().BoolVar(
+		&opts.KeepMachineDeployments,
+		longFlagName(opts, "KeepMachineDeployments"),
+		false,
+		"scale down MachineDeployments instead of deleting them, and save them to a file before resetting the cluster")
+
+	cmd.Flags().BoolVar(
+		&opts.DrainNodes,
+		longFlagName(opts, "DrainNodes"),
+		false,
+		"drain nodes before resetting them, according to the .nodeDrain configuration")
+
 	cmd.Flags().BoolVar(
 		&opts.RemoveBinaries,
 		longFlagName(opts, "RemoveBinaries"),
 		false,
 		"remove kubernetes binaries after resetting the cluster")
 
+	cmd.Flags().BoolVar(
+		&opts.PreservePVCVolumes,
+		longFlagName(opts, "PreservePVCVolumes"),
+		false,
+		"don't delete the dynamically provisioned cloud volumes before resetting the cluster")
+
+	cmd.Flags().BoolVar(
+		&opts.ForceUnlock,
+		longFlagName(opts, "ForceUnlock"),
+		false,
+		"take over the cluster lock held by another operation (!dangerous!, use only if the lock is stale)")
+
 	return cmd
 }
 
-// runReset resets all machines provisioned by KubeOne
-func runReset(opts *resetOpts) error {
+// validateResetScope validates the reset scope and the node argument, which
+// is required only for the node scope
+func validateResetScope(scope string, args []string) error {
+	switch scope {
+	case resetScopeNode:
+		if len(args) != 1 {
+			return errors.New("--scope node requires exactly one node argument")
+		}
+
+		return nil
+	case resetScopeCluster, resetScopeWorkers, resetScopeControlPlane, resetScopeAddons:
+	default:
+		return errors.Errorf("unknown reset scope %q, must be one of: cluster, workers, control-plane, node, addons", scope)
+	}
+
+	if len(args) != 0 {
+		return errors.New("the node argument can be used only with --scope node")
+	}
+
+	return nil
+}
+
+// runReset resets the machines provisioned by KubeOne, limited to the
+// configured scope
+func runReset(opts *resetOpts, args []string) error {
 	s, err := opts.BuildState()
 	if err != nil {
 		return errors.Wrap(err, "failed to initialize State")
 	}
+	defer opts.reportProgress(s, "reset")
 
 	// We intentionally ignore error because "kubeone reset" might also be used
 	// on clusters that are not yet provisioned or broken
 	_ = kubeconfig.BuildKubernetesClientset(s)
 
-	s.Logger.Warnln("This command will PERMANENTLY destroy the Kubernetes cluster running on the following nodes:")
-
-	for _, node := range s.Cluster.ControlPlane.Hosts {
-		fmt.Printf("\t- reset control plane node %q (%s)\n", node.Hostname, node.PrivateAddress)
-	}
-	for _, node := range s.Cluster.StaticWorkers.Hosts {
-		fmt.Printf("\t- reset static worker nodes %q (%s)\n", node.Hostname, node.PrivateAddress)
+	lock := newClusterLock(s, "reset", opts.ForceUnlock)
+	if err = acquireClusterLock(s, lock); err != nil {
+		return err
 	}
+	// The Lease is gone together with the cluster, so the failure to release
+	// it after the successful reset of the whole cluster is expected
+	defer func() { _ = lock.Release(context.Background()) }()
+
+	var tasksToRun tasks.Tasks
 
-	if s.DynamicClient != nil {
-		// Gather information about machine-controller managed nodes
-		machines := clusterv1alpha1.MachineList{}
-		if err = s.DynamicClient.List(s.Context, &machines); err != nil {
-			s.Logger.Errorln("Failed to list machine-controller managed Machines.")
-			s.Logger.Warnln("Worker nodes might not be deleted. If there are worker nodes in the cluster, you might have to delete them manually.")
+	switch opts.Scope {
+	case resetScopeWorkers:
+		s.Logger.Warnln("This command will PERMANENTLY destroy the machine-controller managed worker nodes.")
+		printResetMachines(s)
+		tasksToRun = tasks.WithResetWorkers(nil)
+	case resetScopeControlPlane:
+		s.Logger.Warnln("This command will PERMANENTLY destroy the Kubernetes control plane running on the following nodes:")
+		for _, node := range s.Cluster.ControlPlane.Hosts {
+			fmt.Printf("\t- reset control plane node %q (%s)\n", node.Hostname, node.PrivateAddress)
+		}
+		tasksToRun = tasks.WithResetControlPlane(nil)
+	case resetScopeNode:
+		if tasksToRun, err = resetNodeTasks(s, args[0]); err != nil {
+			return err
 		}
+	case resetScopeAddons:
+		s.Logger.Warnln("This command will delete the addons configured in the KubeOneCluster manifest.")
+		tasksToRun = tasks.WithResetAddons(nil)
+	default:
+		s.Logger.Warnln("This command will PERMANENTLY destroy the Kubernetes cluster running on the following nodes:")
+		for _, node := range s.Cluster.ControlPlane.Hosts {
+			fmt.Printf("\t- reset control plane node %q (%s)\n", node.Hostname, node.PrivateAddress)
+		}
+		for _, node := range s.Cluster.StaticWorkers.Hosts {
+			fmt.Printf("\t- reset static worker nodes %q (%s)\n", node.Hostname, node.PrivateAddress)
+		}
+		printResetMachines(s)
+		printResetVolumes(s)
+		tasksToRun = tasks.WithReset(nil)
+	}
 
-		if len(machines.Items) > 0 {
-			fmt.Printf("\nThe following machine-controller managed worker nodes will be destroyed:\n")
-			for _, machine := range machines.Items {
-				fmt.Printf("\t- %s/%s\n", machine.Namespace, machine.Name)
-			}
+	if opts.Scope != resetScopeCluster {
+		fmt.Println("\nThe following actions will be taken:")
+		for _, op := range tasksToRun.Descriptions(s) {
+			fmt.Printf("\t~ %s\n", op)
 		}
-	} else {
-		s.Logger.Warnln("Failed to list machine-controller managed Machines.")
-		s.Logger.Warnln("Worker nodes might not be deleted.")
-		s.Logger.Warnln("If there are worker nodes in the cluster, you might have to delete them manually.")
-		s.Logger.Warnln("You can ignore this warning if the cluster isn't provisioned.")
 	}
 
-	fmt.Printf("\nAfter the command is complete, there's NO way to recover the cluster or its data!\n")
+	if opts.Scope == resetScopeCluster {
+		fmt.Printf("\nAfter the command is complete, there's NO way to recover the cluster or its data!\n")
+	} else {
+		fmt.Printf("\nAfter the command is complete, there's NO way to recover the reset resources or their data!\n")
+	}
 
-	confirm, err := confirmCommand(opts.AutoApprove)
+	confirm, err := confirmCommandWithName(opts.AutoApprove, s.Cluster.Name)
 	if err != nil {
 		return err
 	}
@@ -149,5 +286,114 @@ func runReset(opts *resetOpts) error {
 		return nil
 	}
 
-	return errors.Wrap(tasks.WithReset(nil).Run(s), "failed to reset the cluster")
+	return errors.Wrapf(tasksToRun.Run(s), "failed to reset the %s", opts.Scope)
+}
+
+// resetNodeTasks returns the tasks wiping the given control plane or static
+// worker node
+func resetNodeTasks(s *state.State, name string) (tasks.Tasks, error) {
+	// Hostnames are needed to find and drain the node
+	if err := tasks.WithHostnameOS(nil).Run(s); err != nil {
+		return nil, err
+	}
+
+	host, controlPlane, found := tasks.FindHost(s.Cluster, name)
+	if !found {
+		return nil, errors.Errorf("host %q is not defined in the manifest", name)
+	}
+	if host.IsLeader {
+		return nil, errors.Errorf("host %q is the leader, make another control plane node the leader first", name)
+	}
+	if controlPlane && len(s.Cluster.ControlPlane.Hosts) < 2 {
+		return nil, errors.Errorf("host %q is the last control plane node, use --scope control-plane instead", name)
+	}
+
+	role := "static worker"
+	if controlPlane {
+		role = "control plane"
+	}
+
+	s.Logger.Warnln("This command will PERMANENTLY wipe the following node:")
+	fmt.Printf("\t- reset %s node %q (%s)\n", role, host.Hostname, host.PrivateAddress)
+
+	return tasks.WithResetNode(nil, host, controlPlane), nil
+}
+
+// printResetMachines prints the machine-controller managed worker nodes that
+// are going to be destroyed
+func printResetMachines(s *state.State) {
+	if s.DynamicClient == nil {
+		s.Logger.Warnln("Failed to list machine-controller managed Machines.")
+		s.Logger.Warnln("Worker nodes might not be deleted.")
+		s.Logger.Warnln("If there are worker nodes in the cluster, you might have to delete them manually.")
+		s.Logger.Warnln("You can ignore this warning if the cluster isn't provisioned.")
+
+		return
+	}
+
+	machineDeployments := clusterv1alpha1.MachineDeploymentList{}
+	if err := s.DynamicClient.List(s.Context, &machineDeployments); err != nil {
+		s.Logger.Errorln("Failed to list MachineDeployments.")
+	}
+
+	if len(machineDeployments.Items) > 0 {
+		action := "deleted"
+		if s.KeepMachineDeployments {
+			action = "scaled down to zero replicas"
+		}
+		fmt.Printf("\nThe following MachineDeployments will be %s:\n", action)
+		for _, md := range machineDeployments.Items {
+			replicas := int32(0)
+			if md.Spec.Replicas != nil {
+				replicas = *md.Spec.Replicas
+			}
+			fmt.Printf("\t- %s/%s (%d replicas)\n", md.Namespace, md.Name, replicas)
+		}
+	}
+
+	// Gather information about machine-controller managed nodes
+	machines := clusterv1alpha1.MachineList{}
+	if err := s.DynamicClient.List(s.Context, &machines); err != nil {
+		s.Logger.Errorln("Failed to list machine-controller managed Machines.")
+		s.Logger.Warnln("Worker nodes might not be deleted. If there are worker nodes in the cluster, you might have to delete them manually.")
+	}
+
+	if len(machines.Items) > 0 {
+		fmt.Printf("\nThe following machine-controller managed worker nodes will be destroyed:\n")
+		for _, machine := range machines.Items {
+			fmt.Printf("\t- %s/%s\n", machine.Namespace, machine.Name)
+		}
+	}
+
+	if s.KeepMachineDeployments {
+		fmt.Printf("\nMachineDeployments will be scaled down to zero replicas and saved to %q\n", s.MachineDeploymentsFile)
+	}
+}
+
+// printResetVolumes prints the dynamically provisioned volumes that are going
+// to be deleted
+func printResetVolumes(s *state.State) {
+	if s.PreservePVCVolumes {
+		fmt.Printf("\nDynamically provisioned volumes will be preserved\n")
+		return
+	}
+	if s.DynamicClient == nil {
+		return
+	}
+
+	pvs, err := tasks.DeletableVolumes(s.Context, s.DynamicClient)
+	if err != nil {
+		s.Logger.Errorln("Failed to list PersistentVolumes.")
+		s.Logger.Warnln("Volumes might not be deleted. If there are dynamically provisioned volumes, you might have to delete them manually.")
+
+		return
+	}
+
+	if len(pvs) > 0 {
+		fmt.Printf("\nThe following dynamically provisioned volumes will be deleted:\n")
+		for _, pv := range pvs {
+			size := pv.Spec.Capacity[corev1.ResourceStorage]
+			fmt.Printf("\t- %s (claim %s/%s, %s)\n", pv.Name, pv.Spec.ClaimRef.Namespace, pv.Spec.ClaimRef.Name, size.String())
+		}
+	}
 }
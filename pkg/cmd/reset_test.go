@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateResetScope(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		scope   string
+		args    []string
+		wantErr bool
+	}{
+		{
+			name:  "cluster",
+			scope: resetScopeCluster,
+		},
+		{
+			name:  "workers",
+			scope: resetScopeWorkers,
+		},
+		{
+			name:  "node",
+			scope: resetScopeNode,
+			args:  []string{"worker-2"},
+		},
+		{
+			name:    "node without argument",
+			scope:   resetScopeNode,
+			wantErr: true,
+		},
+		{
+			name:    "node with multiple arguments",
+			scope:   resetScopeNode,
+			args:    []string{"worker-1", "worker-2"},
+			wantErr: true,
+		},
+		{
+			name:    "control-plane with argument",
+			scope:   resetScopeControlPlane,
+			args:    []string{"cp-1"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown scope",
+			scope:   "machines",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateResetScope(tt.scope, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateResetScope() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadConfirmationName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		want    bool
+		wantErr bool
+	}{
+		{
+			name:  "cluster name",
+			input: "prod-cluster\n",
+			want:  true,
+		},
+		{
+			name:  "cluster name with whitespace",
+			input: "  prod-cluster \r\n",
+			want:  true,
+		},
+		{
+			name:  "yes",
+			input: "yes\n",
+			want:  false,
+		},
+		{
+			name:  "another cluster name",
+			input: "prod\n",
+			want:  false,
+		},
+		{
+			name:    "no input",
+			input:   "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := readConfirmationName(strings.NewReader(tt.input), "prod-cluster")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readConfirmationName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("readConfirmationName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
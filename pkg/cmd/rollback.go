@@ -0,0 +1,163 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/kubeconfig"
+	"k8c.io/kubeone/pkg/scripts"
+	"k8c.io/kubeone/pkg/tasks"
+)
+
+type rollbackOpts struct {
+	globalOptions
+	AutoApprove bool     `longflag:"auto-approve" shortflag:"y"`
+	Hosts       []string `longflag:"host"`
+	ForceUnlock bool     `longflag:"force-unlock"`
+}
+
+func rollbackCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	opts := &rollbackOpts{}
+
+	cmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Restore the previous Kubernetes version on the control plane nodes where the upgrade failed",
+		Long: heredoc.Doc(`
+			Restore the previous Kubernetes version on the control plane nodes where the upgrade failed.
+
+			Before upgrading each control plane node, KubeOne saves the etcd snapshot, the /etc/kubernetes
+			directory, the kubelet configuration and the kubeadm, kubelet and kubectl binaries to
+			` + scripts.RollbackSnapshotDir + ` on the node. This command restores the configuration and the
+			binaries on the nodes whose upgrade wasn't completed, or on the nodes given by the --host flag,
+			one by one, and uncordons them once they're healthy.
+
+			The etcd data isn't restored, because restoring a single etcd member would make it diverge
+			from the rest of the etcd cluster. The etcd snapshot is kept on the node for the disaster recovery.
+			The cluster-wide changes done by 'kubeadm upgrade apply' on the leader, such as the kubeadm-config
+			ConfigMap, kube-proxy and CoreDNS, are not rolled back either.
+
+			Once rolled back, set .versions.kubernetes in the manifest to the restored version before running
+			'kubeone apply' again.
+		`),
+		Example: `kubeone rollback -m mycluster.yaml -t terraformoutput.json`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			gopts, err := persistentGlobalOptions(rootFlags)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			opts.globalOptions = *gopts
+
+			return runRollback(opts)
+		},
+	}
+
+	cmd.Flags().BoolVarP(
+		&opts.AutoApprove,
+		longFlagName(opts, "AutoApprove"),
+		shortFlagName(opts, "AutoApprove"),
+		false,
+		"auto approve plan")
+
+	cmd.Flags().StringSliceVar(
+		&opts.Hosts,
+		longFlagName(opts, "Hosts"),
+		nil,
+		"roll back the given control plane host (hostname, public or private address), even if its upgrade was completed. Can be given multiple times")
+
+	cmd.Flags().BoolVar(
+		&opts.ForceUnlock,
+		longFlagName(opts, "ForceUnlock"),
+		false,
+		"take over the cluster lock held by another operation (!dangerous!, use only if the lock is stale)")
+
+	return cmd
+}
+
+func runRollback(opts *rollbackOpts) error {
+	s, err := opts.BuildState()
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize State")
+	}
+	defer opts.reportProgress(s, "rollback")
+
+	if err = tasks.WithHostnameOS(nil).Run(s); err != nil {
+		return err
+	}
+
+	// The error is intentionally ignored, because the API server might be
+	// broken by the failed upgrade
+	_ = kubeconfig.BuildKubernetesClientset(s)
+
+	lock := newClusterLock(s, "rollback", opts.ForceUnlock)
+	defer releaseClusterLock(s, lock)
+	if err = acquireClusterLock(s, lock); err != nil {
+		return err
+	}
+
+	snapshots, err := tasks.RollbackNodes(s)
+	if err != nil {
+		return err
+	}
+
+	nodes, err := tasks.SelectRollbackNodes(s.Cluster, snapshots, opts.Hosts)
+	if err != nil {
+		return err
+	}
+
+	if len(nodes) == 0 {
+		fmt.Println("No control plane nodes to roll back, there are no interrupted upgrades.")
+		return nil
+	}
+
+	fmt.Println("The following actions will be taken: ")
+	hosts := []kubeoneapi.HostConfig{}
+	versions := map[string]bool{}
+	for _, node := range nodes {
+		fmt.Printf("\t~ roll back control plane node %q (%s) to Kubernetes %s\n", node.Host.Hostname, node.Host.PublicAddress, node.Version)
+		hosts = append(hosts, node.Host)
+		versions[node.Version] = true
+	}
+
+	fmt.Println()
+	confirm, err := confirmCommand(opts.AutoApprove)
+	if err != nil {
+		return err
+	}
+
+	if !confirm {
+		s.Logger.Println("Operation canceled.")
+		return nil
+	}
+
+	if err = tasks.WithRollback(nil, hosts).Run(s); err != nil {
+		return errors.Wrap(err, "failed to roll back the cluster")
+	}
+
+	for version := range versions {
+		s.Logger.Infof("Set .versions.kubernetes to %s in the manifest before running 'kubeone apply' again.", version)
+	}
+
+	return nil
+}
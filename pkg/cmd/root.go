@@ -22,6 +22,9 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"k8c.io/kubeone/pkg/logging"
+	"k8c.io/kubeone/pkg/progress"
+
 	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
 
 	apiextensionsscheme "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/scheme"
@@ -81,7 +84,7 @@ func newRoot() *cobra.Command {
 		longFlagName(opts, "ManifestFile"),
 		shortFlagName(opts, "ManifestFile"),
 		"./kubeone.yaml",
-		"Path to the KubeOne config")
+		"Path to the KubeOne config, optionally encrypted using SOPS")
 
 	fs.StringVarP(&opts.TerraformState,
 		longFlagName(opts, "TerraformState"),
@@ -89,11 +92,16 @@ func newRoot() *cobra.Command {
 		"",
 		"Source for terraform output in JSON - to read from stdin. If path is a file, contents will be used. If path is a dictionary, `terraform output -json` is executed in this path")
 
+	fs.StringVar(&opts.TerraformBackend,
+		longFlagName(opts, "TerraformBackend"),
+		"",
+		"Path to the terraform backend config (s3, gcs or remote), used to read terraform output directly from the remote state instead of --tfjson")
+
 	fs.StringVarP(&opts.CredentialsFile,
 		longFlagName(opts, "CredentialsFile"),
 		shortFlagName(opts, "CredentialsFile"),
 		"",
-		"File to source credentials and secrets from")
+		"File to source credentials and secrets from, optionally encrypted using SOPS")
 
 	fs.BoolVarP(&opts.Verbose,
 		longFlagName(opts, "Verbose"),
@@ -107,17 +115,96 @@ func newRoot() *cobra.Command {
 		false,
 		"debug output with stacktrace")
 
+	fs.BoolVar(&opts.DiagnoseHosts,
+		longFlagName(opts, "DiagnoseHosts"),
+		false,
+		"inspect hosts which can't be connected to over SSH using the cloud provider API (only AWS is supported)")
+
+	fs.StringVar(&opts.LogFormat,
+		longFlagName(opts, "LogFormat"),
+		logging.FormatText,
+		fmt.Sprintf("log format, one of: %s, %s", logging.FormatText, logging.FormatJSON))
+
+	fs.StringVar(&opts.LogLevel,
+		longFlagName(opts, "LogLevel"),
+		"",
+		"comma-separated log level and log levels per subsystem, e.g. 'info,addons=debug,ssh=warn' (defaults to info, or debug with --verbose)")
+
+	fs.StringVar(&opts.AuditLog,
+		longFlagName(opts, "AuditLog"),
+		"",
+		"append all commands run on the hosts over SSH and all changes made using the Kubernetes API to the file, as JSON lines")
+
+	fs.BoolVar(&opts.ReadOnly,
+		longFlagName(opts, "ReadOnly"),
+		false,
+		"refuse to run commands on the hosts over SSH, other than the probes and reading files (always enabled for status, plan, watch and kubeconfig)")
+
+	fs.StringVar(&opts.Leader,
+		longFlagName(opts, "Leader"),
+		"",
+		"hostname or address of the control plane host to be used as the leader, instead of the leader defined in the manifest")
+
+	fs.StringVar(&opts.StateStore,
+		longFlagName(opts, "StateStore"),
+		"",
+		"persist the kubeconfig and backups to a directory, an S3 bucket (s3://bucket/prefix) or a Kubernetes Secret (secret://namespace/name)")
+
+	fs.StringVar(&opts.EncryptionKey,
+		longFlagName(opts, "EncryptionKey"),
+		"",
+		"encrypt the kubeconfig and the backups written to the disk and the state store, using an age recipient (age:age1...), an age identity file (age:<path>) or an AWS KMS key (awskms:<key id or ARN>)")
+
+	fs.BoolVar(&opts.Progress,
+		longFlagName(opts, "Progress"),
+		false,
+		"show the running task and its steps on each host in the terminal, and print the durations of all tasks at the end")
+
+	fs.StringVar(&opts.TimingsFile,
+		longFlagName(opts, "TimingsFile"),
+		"",
+		"write durations and retries of all tasks and their steps on each host to the file")
+
+	fs.StringVar(&opts.TimingsFormat,
+		longFlagName(opts, "TimingsFormat"),
+		progress.FormatJSON,
+		fmt.Sprintf("format of the timings file, one of: %s, %s (OpenTelemetry spans in the OTLP JSON encoding)", progress.FormatJSON, progress.FormatOTLP))
+
+	fs.StringVar(&opts.MetricsFile,
+		longFlagName(opts, "MetricsFile"),
+		"",
+		"write Prometheus metrics of the run (task durations, retries and failures on each host) to the file, e.g. for the node-exporter textfile collector")
+
+	fs.StringVar(&opts.Pushgateway,
+		longFlagName(opts, "Pushgateway"),
+		"",
+		"push Prometheus metrics of the run (task durations, retries and failures on each host) to the Prometheus Pushgateway at the given URL")
+
 	rootCmd.AddCommand(
 		installCmd(fs),
 		applyCmd(fs),
 		upgradeCmd(fs),
+		rollbackCmd(fs),
 		resetCmd(fs),
 		kubeconfigCmd(fs),
 		configCmd(fs),
 		versionCmd(),
 		statusCmd(fs),
+		preflightCmd(fs),
 		proxyCmd(fs),
 		migrateCmd(fs),
+		planCmd(fs),
+		rotateCmd(fs),
+		certificatesCmd(fs),
+		verifyCmd(fs),
+		nodeCmd(fs),
+		fleetCmd(fs),
+		serveCmd(fs),
+		operatorCmd(fs),
+		stateCmd(fs),
+		workersCmd(fs),
+		watchCmd(fs),
+		addonsCmd(fs),
 		completionCmd(rootCmd),
 		documentCmd(rootCmd),
 	)
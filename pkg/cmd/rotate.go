@@ -0,0 +1,229 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8c.io/kubeone/pkg/credentials"
+	"k8c.io/kubeone/pkg/tasks"
+)
+
+type rotateOpts struct {
+	globalOptions
+	AutoApprove bool `longflag:"auto-approve" shortflag:"y"`
+}
+
+func rotateCmd(fs *pflag.FlagSet) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "Commands for rotating cluster secrets",
+	}
+
+	cmd.AddCommand(rotateWeavePasswordCmd(fs))
+	cmd.AddCommand(rotateCredentialsCmd(fs))
+	return cmd
+}
+
+func rotateWeavePasswordCmd(fs *pflag.FlagSet) *cobra.Command {
+	opts := &rotateOpts{}
+
+	cmd := &cobra.Command{
+		Use:   "weave-password",
+		Short: "Rotate the WeaveNet encryption password",
+		Long: heredoc.Doc(`
+			Generate a new WeaveNet encryption password and roll out the weave-net DaemonSet,
+			so all weave-net pods pick up the new password.
+
+			This command is available only if WeaveNet is used as CNI and encryption is enabled
+			(.clusterNetwork.cni.weaveNet.encrypted). While the DaemonSet is being rolled out,
+			pods on nodes using different passwords can't communicate with each other.
+		`),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			gopts, err := persistentGlobalOptions(fs)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			opts.globalOptions = *gopts
+
+			return runRotateWeavePassword(opts)
+		},
+	}
+
+	cmd.Flags().BoolVarP(
+		&opts.AutoApprove,
+		longFlagName(opts, "AutoApprove"),
+		shortFlagName(opts, "AutoApprove"),
+		false,
+		"auto approve plan")
+
+	return cmd
+}
+
+func runRotateWeavePassword(opts *rotateOpts) error {
+	s, err := opts.BuildState()
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize State")
+	}
+
+	weaveNet := s.Cluster.ClusterNetwork.CNI.WeaveNet
+	if weaveNet == nil || !weaveNet.Encrypted {
+		return errors.New("rotating weave-net password requires WeaveNet CNI with encryption enabled")
+	}
+
+	// Probe the cluster for the actual state and the needed tasks.
+	probbing := tasks.WithHostnameOS(nil)
+	probbing = tasks.WithProbes(probbing)
+
+	if err = probbing.Run(s); err != nil {
+		return err
+	}
+
+	if !s.LiveCluster.IsProvisioned() {
+		return errors.New("the target cluster is not provisioned")
+	}
+	if !s.LiveCluster.Healthy() {
+		return errors.New("the target cluster is not healthy, please run 'kubeone apply' first")
+	}
+
+	fmt.Println("The following actions will be taken: ")
+	fmt.Println("Run with --verbose flag for more information.")
+	tasksToRun := tasks.WithRotateWeavePassword(nil)
+
+	for _, op := range tasksToRun.Descriptions(s) {
+		fmt.Printf("\t~ %s\n", op)
+	}
+
+	fmt.Println()
+	confirm, err := confirmCommand(opts.AutoApprove)
+	if err != nil {
+		return err
+	}
+
+	if !confirm {
+		s.Logger.Println("Operation canceled.")
+		return nil
+	}
+
+	return errors.Wrap(tasksToRun.Run(s), "failed to rotate weave-net password")
+}
+
+func rotateCredentialsCmd(fs *pflag.FlagSet) *cobra.Command {
+	opts := &rotateOpts{}
+
+	cmd := &cobra.Command{
+		Use:   "credentials",
+		Short: "Rotate the cloud provider credentials used in the cluster",
+		Long: heredoc.Doc(`
+			Update the cloud provider credentials used by machine-controller, the external CCM,
+			CSI drivers and Velero with the credentials from the environment or the credentials
+			file (--credentials).
+
+			The new credentials are validated against the cloud provider API first. Then the
+			credentials secrets are updated, the addons using them are re-applied, and all
+			Deployments and DaemonSets using the credentials secrets are restarted in order.
+			Finally, the MachineDeployments are validated by the machine-controller webhook
+			to verify machines can still be reconciled with the new credentials.
+
+			The old credentials should be revoked only after this command succeeds. The
+			cloud-config file on the control plane nodes, used by the in-tree cloud provider,
+			is updated only by 'kubeone apply'.
+		`),
+		Example: `kubeone rotate credentials -m mycluster.yaml -c credentials.yaml`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			gopts, err := persistentGlobalOptions(fs)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			opts.globalOptions = *gopts
+
+			return runRotateCredentials(opts)
+		},
+	}
+
+	cmd.Flags().BoolVarP(
+		&opts.AutoApprove,
+		longFlagName(opts, "AutoApprove"),
+		shortFlagName(opts, "AutoApprove"),
+		false,
+		"auto approve plan")
+
+	return cmd
+}
+
+func runRotateCredentials(opts *rotateOpts) error {
+	s, err := opts.BuildState()
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize State")
+	}
+
+	if s.Cluster.CloudProvider.None != nil {
+		return errors.New("rotating credentials is not supported for the none cloud provider")
+	}
+
+	creds, err := credentials.ProviderCredentials(s.Cluster.CloudProvider, opts.CredentialsFile)
+	if err != nil {
+		return errors.Wrap(err, "failed to validate credentials")
+	}
+
+	if err = checkCredentials(s, creds); err != nil {
+		return err
+	}
+
+	// Probe the cluster for the actual state and the needed tasks.
+	probbing := tasks.WithHostnameOS(nil)
+	probbing = tasks.WithProbes(probbing)
+
+	if err = probbing.Run(s); err != nil {
+		return err
+	}
+
+	if !s.LiveCluster.IsProvisioned() {
+		return errors.New("the target cluster is not provisioned")
+	}
+	if !s.LiveCluster.Healthy() {
+		return errors.New("the target cluster is not healthy, please run 'kubeone apply' first")
+	}
+
+	fmt.Println("The following actions will be taken: ")
+	fmt.Println("Run with --verbose flag for more information.")
+	tasksToRun := tasks.WithRotateCredentials(nil)
+
+	for _, op := range tasksToRun.Descriptions(s) {
+		fmt.Printf("\t~ %s\n", op)
+	}
+
+	fmt.Println()
+	confirm, err := confirmCommand(opts.AutoApprove)
+	if err != nil {
+		return err
+	}
+
+	if !confirm {
+		s.Logger.Println("Operation canceled.")
+		return nil
+	}
+
+	return errors.Wrap(tasksToRun.Run(s), "failed to rotate credentials")
+}
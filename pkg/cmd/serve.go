@@ -0,0 +1,280 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/apis/kubeone/config"
+	"k8c.io/kubeone/pkg/client"
+	"k8c.io/kubeone/pkg/fleet"
+	"k8c.io/kubeone/pkg/logging"
+	"k8c.io/kubeone/pkg/server"
+)
+
+const (
+	// serveTokenEnv is the environment variable holding the API token, if
+	// the token file is not given
+	serveTokenEnv = "KUBEONE_SERVE_TOKEN"
+)
+
+type serveOpts struct {
+	globalOptions
+	ListenAddr  string `longflag:"listen"`
+	FleetFile   string `longflag:"fleet"`
+	TokenFile   string `longflag:"token-file"`
+	TLSCertFile string `longflag:"tls-cert-file"`
+	TLSKeyFile  string `longflag:"tls-key-file"`
+}
+
+func serveCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	opts := &serveOpts{}
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the API for running apply, upgrade and status remotely",
+		Long: heredoc.Doc(`
+			Serve the HTTP API for running 'kubeone apply', 'kubeone upgrade' and 'kubeone status' on the
+			clusters remotely. The server holds the manifests and the SSH keys of the clusters, so the
+			clients, such as CI runners, need only the API token.
+
+			The clusters are defined by the manifest (--manifest), which can contain multiple
+			KubeOneCluster documents, or by the fleet file (--fleet), the same way as for
+			'kubeone fleet apply'. The manifests are loaded again for each operation, so changes to
+			them are picked up without restarting the server.
+
+			Requests are authenticated with the bearer token, read from the file given with the
+			'--token-file' flag, or from the KUBEONE_SERVE_TOKEN environment variable:
+
+			  GET    /v1/clusters                   names of the served clusters
+			  GET    /v1/clusters/<name>/status     status report of the cluster
+			  POST   /v1/clusters/<name>/apply      start the apply, returns the operation
+			  POST   /v1/clusters/<name>/upgrade    start the upgrade, returns the operation
+			  GET    /v1/operations                 all operations
+			  GET    /v1/operations/<id>            the operation and its result
+			  GET    /v1/operations/<id>/logs       logs of the operation, streamed until it finishes
+			  DELETE /v1/operations/<id>            cancel the operation
+
+			The apply request accepts the optional JSON body with the noInit, forceInstall, forceUpgrade
+			and upgradeMachineDeployments fields, the upgrade request the force and
+			upgradeMachineDeployments fields. Only a single operation can run on a cluster at the time.
+
+			Use the '--tls-cert-file' and '--tls-key-file' flags to serve the API over HTTPS, otherwise the
+			token is sent in plain text.
+		`),
+		Args:    cobra.ExactArgs(0),
+		Example: `KUBEONE_SERVE_TOKEN=... kubeone serve --fleet fleet.yaml --listen :8443 --tls-cert-file tls.crt --tls-key-file tls.key`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			gopts, err := persistentGlobalOptions(rootFlags)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			opts.globalOptions = *gopts
+
+			return runServe(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&opts.ListenAddr,
+		longFlagName(opts, "ListenAddr"),
+		"127.0.0.1:8080",
+		"address the API is served on")
+
+	cmd.Flags().StringVar(
+		&opts.FleetFile,
+		longFlagName(opts, "FleetFile"),
+		"",
+		"path to the fleet file referencing the clusters, instead of the manifest")
+
+	cmd.Flags().StringVar(
+		&opts.TokenFile,
+		longFlagName(opts, "TokenFile"),
+		"",
+		"path to the file containing the API token (default: "+serveTokenEnv+" environment variable)")
+
+	cmd.Flags().StringVar(
+		&opts.TLSCertFile,
+		longFlagName(opts, "TLSCertFile"),
+		"",
+		"path to the TLS certificate for serving the API over HTTPS")
+
+	cmd.Flags().StringVar(
+		&opts.TLSKeyFile,
+		longFlagName(opts, "TLSKeyFile"),
+		"",
+		"path to the TLS private key for serving the API over HTTPS")
+
+	return cmd
+}
+
+func runServe(opts *serveOpts) error {
+	if (opts.TLSCertFile == "") != (opts.TLSKeyFile == "") {
+		return errors.New("--tls-cert-file and --tls-key-file must be given together")
+	}
+	if opts.Progress || opts.TimingsFile != "" || opts.AuditLog != "" {
+		return errors.New("--progress, --timings-file and --audit-log are not supported by the server")
+	}
+
+	token, err := serveToken(opts.TokenFile)
+	if err != nil {
+		return err
+	}
+
+	loggingOpts := logging.Options{
+		Format:  opts.LogFormat,
+		Levels:  opts.LogLevel,
+		Verbose: opts.Verbose,
+	}
+	logger, err := logging.New(os.Stderr, loggingOpts)
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize logger")
+	}
+
+	members, err := fleetMembers(&opts.globalOptions, opts.FleetFile)
+	if err != nil {
+		return err
+	}
+
+	names := []string{}
+	byName := map[string]fleet.Member{}
+	for _, member := range members {
+		names = append(names, member.Name)
+		byName[member.Name] = member
+	}
+
+	srv, err := server.New(server.Options{
+		Clusters: names,
+		Token:    token,
+		Logger:   logger,
+		Logging:  loggingOpts,
+		Load: func(ctx context.Context, name string, logger logrus.FieldLogger) (server.Cluster, error) {
+			c, lerr := serveLoadCluster(ctx, opts, byName[name], logger)
+			if lerr != nil {
+				return nil, lerr
+			}
+
+			return c, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	httpServer := &http.Server{
+		Addr:              opts.ListenAddr,
+		Handler:           srv,
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		if opts.TLSCertFile != "" {
+			errCh <- httpServer.ListenAndServeTLS(opts.TLSCertFile, opts.TLSKeyFile)
+			return
+		}
+
+		logger.Warnln("TLS is not configured, the API token is sent in plain text")
+		errCh <- httpServer.ListenAndServe()
+	}()
+
+	logger.Infof("Serving %d cluster(s) on %s", len(names), opts.ListenAddr)
+
+	select {
+	case err = <-errCh:
+		srv.Close()
+		return errors.Wrap(err, "failed to serve the API")
+	case <-ctx.Done():
+	}
+
+	logger.Infoln("Shutting down, canceling the running operations...")
+	srv.Close()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	return errors.Wrap(httpServer.Shutdown(shutdownCtx), "failed to shut down the server")
+}
+
+// serveToken reads the API token from the file, or from the environment
+// variable if the file is not given
+func serveToken(tokenFile string) (string, error) {
+	token := os.Getenv(serveTokenEnv)
+	if tokenFile != "" {
+		buf, err := ioutil.ReadFile(tokenFile)
+		if err != nil {
+			return "", errors.Wrap(err, "unable to read the token file")
+		}
+		token = string(buf)
+	}
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", errors.Errorf("API token is required, use --token-file or %s environment variable", serveTokenEnv)
+	}
+
+	return token, nil
+}
+
+// serveLoadCluster loads the cluster of the fleet member and returns the
+// client for running the operations on it
+func serveLoadCluster(ctx context.Context, opts *serveOpts, member fleet.Member, logger logrus.FieldLogger) (*client.Client, error) {
+	var (
+		cluster *kubeoneapi.KubeOneCluster
+		err     error
+	)
+
+	if member.Document != nil {
+		cluster, err = config.LoadKubeOneClusterDocument(member.ManifestFile, *member.Document, nil, member.CredentialsFile, logger)
+	} else {
+		cluster, err = loadClusterConfig(ctx, member.ManifestFile, member.TerraformState, member.TerraformBackend, member.CredentialsFile, logger)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Leader != "" {
+		if err = cluster.SetLeader(opts.Leader); err != nil {
+			return nil, errors.Wrap(err, "failed to set the leader")
+		}
+	}
+
+	return client.New(cluster, client.Options{
+		Logger:              logger,
+		ManifestFilePath:    member.ManifestFile,
+		CredentialsFilePath: member.CredentialsFile,
+		Verbose:             opts.Verbose,
+	})
+}
@@ -18,11 +18,14 @@ package cmd
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -31,17 +34,57 @@ import (
 
 	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
 	"k8c.io/kubeone/pkg/apis/kubeone/config"
+	"k8c.io/kubeone/pkg/audit"
+	"k8c.io/kubeone/pkg/credentialcheck"
+	"k8c.io/kubeone/pkg/credentials"
+	"k8c.io/kubeone/pkg/encryption"
+	"k8c.io/kubeone/pkg/filelock"
+	"k8c.io/kubeone/pkg/hostdiagnostics"
+	"k8c.io/kubeone/pkg/logging"
+	"k8c.io/kubeone/pkg/progress"
 	"k8c.io/kubeone/pkg/state"
+	"k8c.io/kubeone/pkg/statestore"
+	tfbackend "k8c.io/kubeone/pkg/terraform/backend"
 )
 
-const yes = "yes"
+const (
+	yes = "yes"
+
+	// credentialsCheckTimeout is how long the cloud provider API calls made
+	// by the credentials check can take
+	credentialsCheckTimeout = 30 * time.Second
+)
 
 type globalOptions struct {
-	ManifestFile    string `longflag:"manifest" shortflag:"m"`
-	TerraformState  string `longflag:"tfjson" shortflag:"t"`
-	CredentialsFile string `longflag:"credentials" shortflag:"c"`
-	Verbose         bool   `longflag:"verbose" shortflag:"v"`
-	Debug           bool   `longflag:"debug" shortflag:"d"`
+	ManifestFile     string `longflag:"manifest" shortflag:"m"`
+	TerraformState   string `longflag:"tfjson" shortflag:"t"`
+	TerraformBackend string `longflag:"tfstate-backend"`
+	CredentialsFile  string `longflag:"credentials" shortflag:"c"`
+	Verbose          bool   `longflag:"verbose" shortflag:"v"`
+	Debug            bool   `longflag:"debug" shortflag:"d"`
+	DiagnoseHosts    bool   `longflag:"diagnose-unreachable-hosts"`
+	LogFormat        string `longflag:"log-format"`
+	LogLevel         string `longflag:"log-level"`
+	Progress         bool   `longflag:"progress"`
+	TimingsFile      string `longflag:"timings-file"`
+	TimingsFormat    string `longflag:"timings-format"`
+	MetricsFile      string `longflag:"metrics-file"`
+	Pushgateway      string `longflag:"metrics-pushgateway"`
+	AuditLog         string `longflag:"audit-log"`
+	ReadOnly         bool   `longflag:"read-only"`
+	Leader           string `longflag:"leader"`
+	StateStore       string `longflag:"state-store"`
+	EncryptionKey    string `longflag:"encryption-key"`
+
+	// terminal shows the progress status line, if enabled
+	terminal *progress.Terminal
+
+	// manifestDocument is the index of the document loaded from the
+	// multi-document manifest, used by the fleet commands
+	manifestDocument *int
+	// fleetMember is added to the log entries, if set, so the entries of the
+	// clusters managed at the same time can be told apart
+	fleetMember string
 }
 
 func (opts *globalOptions) BuildState() (*state.State, error) {
@@ -50,18 +93,104 @@ func (opts *globalOptions) BuildState() (*state.State, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to initialize State")
 	}
-	s.Logger = newLogger(opts.Verbose)
+	logger, err := logging.New(os.Stderr, logging.Options{
+		Format:  opts.LogFormat,
+		Levels:  opts.LogLevel,
+		Verbose: opts.Verbose,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to initialize logger")
+	}
+	s.Logger = logger
+	if opts.fleetMember != "" {
+		s.Logger = logger.WithField(logging.FieldCluster, opts.fleetMember)
+	}
+
+	if opts.Progress || opts.TimingsFile != "" || opts.MetricsFile != "" || opts.Pushgateway != "" {
+		switch opts.TimingsFormat {
+		case progress.FormatJSON, progress.FormatOTLP:
+		default:
+			return nil, errors.Errorf("unknown timings format %q, must be one of: %s, %s", opts.TimingsFormat, progress.FormatJSON, progress.FormatOTLP)
+		}
+
+		s.Progress = progress.NewRecorder()
+	}
+
+	// The status line is shown only in the terminal, and it would break
+	// the JSON logs
+	if opts.Progress && opts.LogFormat != logging.FormatJSON && progress.IsTerminal(os.Stderr) {
+		opts.terminal = progress.NewTerminal(os.Stderr, s.Progress)
+		logger.Out = opts.terminal
+		opts.terminal.Start(time.Second)
+	}
 
-	cluster, err := loadClusterConfig(opts.ManifestFile, opts.TerraformState, opts.CredentialsFile, s.Logger)
+	var cluster *kubeoneapi.KubeOneCluster
+	if opts.manifestDocument != nil {
+		cluster, err = config.LoadKubeOneClusterDocument(opts.ManifestFile, *opts.manifestDocument, nil, opts.CredentialsFile, s.Logger)
+	} else {
+		cluster, err = loadClusterConfig(rootContext, opts.ManifestFile, opts.TerraformState, opts.TerraformBackend, opts.CredentialsFile, s.Logger)
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to load cluster")
 	}
 
+	if opts.Leader != "" {
+		if err = cluster.SetLeader(opts.Leader); err != nil {
+			return nil, errors.Wrap(err, "failed to set the leader")
+		}
+	}
+
+	// The cloud-config is rendered on every run, so the credentials are
+	// never stored in the manifest
+	cluster.CloudProvider.CloudConfig, err = credentials.RenderCloudConfig(cluster.CloudProvider, opts.CredentialsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster.CloudProvider.CSIConfig, err = credentials.RenderCSIConfig(cluster.Name, cluster.CloudProvider, opts.CredentialsFile)
+	if err != nil {
+		return nil, err
+	}
+
 	s.Cluster = cluster
 	s.ManifestFilePath = opts.ManifestFile
 	s.CredentialsFilePath = opts.CredentialsFile
 	s.Verbose = opts.Verbose
 
+	if opts.AuditLog != "" {
+		s.Audit, err = audit.Open(opts.AuditLog, strings.Join(os.Args, " "), s.Logger)
+		if err != nil {
+			return nil, err
+		}
+		s.Connector.WrapConnections(s.Audit.WrapConnection)
+	}
+
+	// Commands which could change the hosts are refused by the SSH
+	// connections themselves, regardless of the tasks being run
+	s.Connector.SetReadOnly(opts.ReadOnly)
+	s.Connector.SetPassphrasePrompt(promptPassphrase)
+
+	if opts.StateStore != "" {
+		s.StateStore, err = statestore.New(opts.StateStore)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to initialize state store")
+		}
+	}
+
+	if opts.EncryptionKey != "" {
+		s.EncryptionKey, err = encryption.New(opts.EncryptionKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to initialize encryption key")
+		}
+	}
+
+	if opts.DiagnoseHosts {
+		s.DiagnoseHost, err = hostDiagnoseFunc(s.Cluster, opts.CredentialsFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to initialize host diagnostics")
+		}
+	}
+
 	// Validate Addons path if provided
 	if s.Cluster.Addons.Enabled() {
 		addonsPath, err := s.Cluster.Addons.RelativePath(s.ManifestFilePath)
@@ -76,6 +205,53 @@ func (opts *globalOptions) BuildState() (*state.State, error) {
 	return s, nil
 }
 
+// reportProgress stops showing the progress status line, prints the summary
+// of the task durations, writes the timings file and exports the metrics of
+// the command run, if enabled
+func (opts *globalOptions) reportProgress(s *state.State, command string) {
+	if opts.terminal != nil {
+		opts.terminal.Stop()
+	}
+
+	if s.Progress == nil {
+		return
+	}
+
+	if opts.Progress {
+		fmt.Fprintln(os.Stderr)
+		if err := s.Progress.WriteSummary(os.Stderr); err != nil {
+			s.Logger.Warnf("Failed to print the timings summary: %v", err)
+		}
+	}
+
+	if opts.TimingsFile != "" {
+		buf := bytes.Buffer{}
+		if err := s.Progress.Export(&buf, opts.TimingsFormat, s.Cluster.Name); err != nil {
+			s.Logger.Warnf("Failed to export timings: %v", err)
+			return
+		}
+
+		if err := filelock.WriteFile(opts.TimingsFile, buf.Bytes(), 0600); err != nil {
+			s.Logger.Warnf("Failed to write timings: %v", err)
+		}
+	}
+
+	if opts.MetricsFile != "" {
+		buf := bytes.Buffer{}
+		if err := s.Progress.ExportMetrics(&buf, s.Cluster.Name, command); err != nil {
+			s.Logger.Warnf("Failed to export metrics: %v", err)
+		} else if err := filelock.WriteFile(opts.MetricsFile, buf.Bytes(), 0644); err != nil {
+			s.Logger.Warnf("Failed to write metrics: %v", err)
+		}
+	}
+
+	if opts.Pushgateway != "" {
+		if err := s.Progress.PushMetrics(opts.Pushgateway, s.Cluster.Name, command); err != nil {
+			s.Logger.Warnf("Failed to push metrics: %v", err)
+		}
+	}
+}
+
 func longFlagName(obj interface{}, fieldName string) string {
 	elem := reflect.TypeOf(obj).Elem()
 	field, ok := elem.FieldByName(fieldName)
@@ -113,31 +289,179 @@ func persistentGlobalOptions(fs *pflag.FlagSet) (*globalOptions, error) {
 	}
 	gf.TerraformState = tfjson
 
+	tfBackend, err := fs.GetString(longFlagName(gf, "TerraformBackend"))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	gf.TerraformBackend = tfBackend
+
 	creds, err := fs.GetString(longFlagName(gf, "CredentialsFile"))
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 	gf.CredentialsFile = creds
 
+	diagnoseHosts, err := fs.GetBool(longFlagName(gf, "DiagnoseHosts"))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	gf.DiagnoseHosts = diagnoseHosts
+
+	logFormat, err := fs.GetString(longFlagName(gf, "LogFormat"))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	gf.LogFormat = logFormat
+
+	logLevel, err := fs.GetString(longFlagName(gf, "LogLevel"))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	gf.LogLevel = logLevel
+
+	showProgress, err := fs.GetBool(longFlagName(gf, "Progress"))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	gf.Progress = showProgress
+
+	timingsFile, err := fs.GetString(longFlagName(gf, "TimingsFile"))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	gf.TimingsFile = timingsFile
+
+	timingsFormat, err := fs.GetString(longFlagName(gf, "TimingsFormat"))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	gf.TimingsFormat = timingsFormat
+
+	metricsFile, err := fs.GetString(longFlagName(gf, "MetricsFile"))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	gf.MetricsFile = metricsFile
+
+	pushgateway, err := fs.GetString(longFlagName(gf, "Pushgateway"))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	gf.Pushgateway = pushgateway
+
+	auditLog, err := fs.GetString(longFlagName(gf, "AuditLog"))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	gf.AuditLog = auditLog
+
+	readOnly, err := fs.GetBool(longFlagName(gf, "ReadOnly"))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	gf.ReadOnly = readOnly
+
+	leader, err := fs.GetString(longFlagName(gf, "Leader"))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	gf.Leader = leader
+
+	stateStore, err := fs.GetString(longFlagName(gf, "StateStore"))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	gf.StateStore = stateStore
+
+	encryptionKey, err := fs.GetString(longFlagName(gf, "EncryptionKey"))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	gf.EncryptionKey = encryptionKey
+
 	return gf, nil
 }
 
-func newLogger(verbose bool) *logrus.Logger {
-	logger := logrus.New()
-	logger.Formatter = &logrus.TextFormatter{
-		FullTimestamp:   true,
-		TimestampFormat: "15:04:05 MST",
+// hostDiagnoseFunc returns the function diagnosing hosts which can't be
+// connected to, using the cloud provider API
+func hostDiagnoseFunc(cluster *kubeoneapi.KubeOneCluster, credentialsFile string) (func(context.Context, kubeoneapi.HostConfig) string, error) {
+	creds, err := credentials.ProviderCredentials(cluster.CloudProvider, credentialsFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get provider credentials")
+	}
+
+	diagnoser, err := hostdiagnostics.New(cluster, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, host kubeoneapi.HostConfig) string {
+		report, err := diagnoser.Diagnose(ctx, host)
+		if err != nil {
+			return fmt.Sprintf("Unable to diagnose the host: %v", err)
+		}
+
+		return report.String()
+	}, nil
+}
+
+// checkCredentials validates the credentials using the cloud provider API
+// and fails if they are invalid or any of the required permissions is missing
+func checkCredentials(s *state.State, creds map[string]string) error {
+	checker, err := credentialcheck.New(s.Cluster, creds)
+	if errors.Is(err, credentialcheck.ErrUnsupported) {
+		s.Logger.Debugln("Skipping credentials check:", err)
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize credentials check")
+	}
+
+	ctx, cancel := context.WithTimeout(s.Context, credentialsCheckTimeout)
+	defer cancel()
+
+	report, err := checker.Check(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to check credentials")
+	}
+
+	for _, warning := range report.Warnings {
+		s.Logger.Warnln(warning)
 	}
 
-	if verbose {
-		logger.SetLevel(logrus.DebugLevel)
+	if !report.Passed() {
+		return errors.New(report.String())
 	}
+	s.Logger.Infoln(report.String())
 
-	return logger
+	return nil
 }
 
-func loadClusterConfig(filename, terraformOutputPath, credentialsFilePath string, logger logrus.FieldLogger) (*kubeoneapi.KubeOneCluster, error) {
-	a, err := config.LoadKubeOneCluster(filename, terraformOutputPath, credentialsFilePath, logger)
+func loadClusterConfig(ctx context.Context, filename, terraformOutputPath, terraformBackendPath, credentialsFilePath string, logger logrus.FieldLogger) (*kubeoneapi.KubeOneCluster, error) {
+	if terraformBackendPath == "" {
+		a, err := config.LoadKubeOneCluster(filename, terraformOutputPath, credentialsFilePath, logger)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to load a given KubeOneCluster object")
+		}
+
+		return a, nil
+	}
+
+	if terraformOutputPath != "" {
+		return nil, errors.New("--tfjson and --tfstate-backend can't be used at the same time")
+	}
+
+	backendConfig, err := tfbackend.Load(terraformBackendPath)
+	if err != nil {
+		return nil, err
+	}
+
+	tfOutput, err := backendConfig.ReadOutputs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := config.LoadKubeOneClusterWithTerraformOutput(filename, tfOutput, credentialsFilePath, logger)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to load a given KubeOneCluster object")
 	}
@@ -166,3 +490,44 @@ func confirmCommand(autoApprove bool) (bool, error) {
 
 	return strings.Trim(confirmation, "\n") == yes, nil
 }
+
+// confirmCommandWithName asks the user to type the cluster name to confirm
+// the destructive command
+func confirmCommandWithName(autoApprove bool, name string) (bool, error) {
+	if autoApprove {
+		return true, nil
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return false, errors.New("not running in the terminal")
+	}
+
+	fmt.Printf("Type the cluster name (%s) to proceed: ", name)
+
+	return readConfirmationName(os.Stdin, name)
+}
+
+func readConfirmationName(r io.Reader, name string) (bool, error) {
+	confirmation, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil {
+		return false, err
+	}
+
+	fmt.Println()
+
+	return strings.TrimSpace(confirmation) == name, nil
+}
+
+// promptPassphrase asks for the passphrase of the encrypted SSH private key
+// without echoing it
+func promptPassphrase(keySource string) (string, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", errors.New("not running in the terminal")
+	}
+
+	fmt.Fprintf(os.Stderr, "Enter passphrase for the SSH key %q: ", keySource)
+	passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+
+	return string(passphrase), err
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8c.io/kubeone/pkg/encryption"
+	"k8c.io/kubeone/pkg/statestore"
+)
+
+type stateGetOpts struct {
+	globalOptions
+	OutputFile string `longflag:"output-file"`
+}
+
+func stateCmd(fs *pflag.FlagSet) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state",
+		Short: "Commands for reading the artifacts persisted in the state store",
+		Long: heredoc.Doc(`
+			Read the artifacts, such as the kubeconfig, the PKI backups and the
+			saved MachineDeployments, persisted in the state store given by the
+			--state-store flag.
+		`),
+	}
+
+	cmd.AddCommand(stateListCmd(fs))
+	cmd.AddCommand(stateGetCmd(fs))
+	return cmd
+}
+
+func stateListCmd(fs *pflag.FlagSet) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the artifacts persisted in the state store",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			gopts, err := persistentGlobalOptions(fs)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			store, err := openStateStore(gopts)
+			if err != nil {
+				return err
+			}
+
+			names, err := store.List(context.Background())
+			if err != nil {
+				return err
+			}
+
+			for _, name := range names {
+				fmt.Println(name)
+			}
+
+			return nil
+		},
+	}
+}
+
+func stateGetCmd(fs *pflag.FlagSet) *cobra.Command {
+	opts := &stateGetOpts{}
+
+	cmd := &cobra.Command{
+		Use:   "get <name>",
+		Short: "Print the artifact persisted in the state store",
+		Example: heredoc.Doc(`
+			kubeone state get example-kubeconfig --state-store s3://kubeone/example --output-file example-kubeconfig
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			gopts, err := persistentGlobalOptions(fs)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			opts.globalOptions = *gopts
+
+			return runStateGet(opts, args[0])
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&opts.OutputFile,
+		longFlagName(opts, "OutputFile"),
+		"",
+		"write the artifact to the file instead of stdout")
+
+	return cmd
+}
+
+func runStateGet(opts *stateGetOpts, name string) error {
+	store, err := openStateStore(&opts.globalOptions)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	data, err := store.Get(ctx, name)
+	if errors.Is(err, statestore.ErrNotFound) {
+		return errors.Errorf("artifact %q not found in %s", name, store)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Without the --encryption-key flag, the encrypted artifacts are written
+	// as they are
+	if opts.EncryptionKey != "" {
+		key, kerr := encryption.New(opts.EncryptionKey)
+		if kerr != nil {
+			return errors.Wrap(kerr, "failed to initialize encryption key")
+		}
+
+		if data, err = encryption.Decrypt(ctx, key, data); err != nil {
+			return errors.Wrapf(err, "failed to decrypt artifact %q", name)
+		}
+	}
+
+	if opts.OutputFile != "" {
+		return errors.Wrap(ioutil.WriteFile(opts.OutputFile, data, 0600), "failed to write the artifact")
+	}
+
+	_, err = os.Stdout.Write(data)
+	return errors.WithStack(err)
+}
+
+func openStateStore(opts *globalOptions) (statestore.Store, error) {
+	if opts.StateStore == "" {
+		return nil, errors.New("the --state-store flag is required")
+	}
+
+	store, err := statestore.New(opts.StateStore)
+	return store, errors.Wrap(err, "failed to initialize state store")
+}
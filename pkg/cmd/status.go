@@ -17,16 +17,36 @@ limitations under the License.
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
+
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
+	"k8c.io/kubeone/pkg/clusterstatus"
+	"k8c.io/kubeone/pkg/kubeconfig"
 	"k8c.io/kubeone/pkg/tasks"
+
+	kyaml "sigs.k8s.io/yaml"
 )
 
+const (
+	statusOutputTable = "table"
+	statusOutputJSON  = "json"
+	statusOutputYAML  = "yaml"
+)
+
+type statusOpts struct {
+	globalOptions
+	Output string `longflag:"output" shortflag:"o"`
+}
+
 // statusCmd returns the structure for declaring the "status" subcommand.
 func statusCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	opts := &statusOpts{}
+
 	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Status of the cluster",
@@ -35,6 +55,11 @@ func statusCmd(rootFlags *pflag.FlagSet) *cobra.Command {
 
 			This command takes KubeOne manifest which contains information about hosts. It's possible to source information about
 			hosts from Terraform output, using the '--tfjson' flag.
+
+			With '--output json' or '--output yaml', a machine-readable report is printed instead. The report contains
+			versions and health of kubelet, API server and etcd on each host, expiration of the control plane
+			certificates, and versions and health of the CNI, machine-controller and other workloads in the kube-system
+			namespace.
 		`),
 		Example: `kubeone status -m mycluster.yaml -t terraformoutput.json`,
 		RunE: func(_ *cobra.Command, _ []string) error {
@@ -43,15 +68,32 @@ func statusCmd(rootFlags *pflag.FlagSet) *cobra.Command {
 				return errors.Wrap(err, "unable to get global flags")
 			}
 
-			return runStatus(gopts)
+			opts.globalOptions = *gopts
+
+			return runStatus(opts)
 		},
 	}
 
+	cmd.Flags().StringVarP(
+		&opts.Output,
+		longFlagName(opts, "Output"),
+		shortFlagName(opts, "Output"),
+		statusOutputTable,
+		fmt.Sprintf("output format, one of: %s, %s, %s", statusOutputTable, statusOutputJSON, statusOutputYAML))
+
 	return cmd
 }
 
 // runStatus gets cluster status
-func runStatus(opts *globalOptions) error {
+func runStatus(opts *statusOpts) error {
+	if opts.Output == statusOutputJSON || opts.Output == statusOutputYAML {
+		return runStatusReport(opts)
+	}
+	if opts.Output != statusOutputTable {
+		return errors.Errorf("unknown output format %q", opts.Output)
+	}
+
+	opts.ReadOnly = true
 	s, err := opts.BuildState()
 	if err != nil {
 		return errors.Wrap(err, "failed to initialize State")
@@ -59,3 +101,48 @@ func runStatus(opts *globalOptions) error {
 
 	return errors.Wrap(tasks.WithClusterStatus(nil).Run(s), "failed to get cluster status")
 }
+
+// runStatusReport prints the machine-readable cluster status report
+func runStatusReport(opts *statusOpts) error {
+	opts.ReadOnly = true
+	s, err := opts.BuildState()
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize State")
+	}
+
+	probbing := tasks.WithHostnameOS(nil)
+	probbing = tasks.WithProbes(probbing)
+
+	if err = probbing.Run(s); err != nil {
+		return err
+	}
+
+	if !s.LiveCluster.IsProvisioned() {
+		return errors.New("the target cluster is not provisioned")
+	}
+
+	if s.DynamicClient == nil {
+		if err = kubeconfig.BuildKubernetesClientset(s); err != nil {
+			return errors.Wrap(err, "failed to build kubernetes clientset")
+		}
+	}
+
+	report, err := clusterstatus.GetReport(s)
+	if err != nil {
+		return errors.Wrap(err, "failed to get cluster status")
+	}
+
+	var out []byte
+	if opts.Output == statusOutputJSON {
+		out, err = json.MarshalIndent(report, "", "  ")
+	} else {
+		out, err = kyaml.Marshal(report)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal cluster status")
+	}
+
+	fmt.Println(string(out))
+
+	return nil
+}
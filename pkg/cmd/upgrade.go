@@ -31,6 +31,7 @@ type upgradeOpts struct {
 	globalOptions
 	ForceUpgrade              bool `longflag:"force" shortflag:"f"`
 	UpgradeMachineDeployments bool `longflag:"upgrade-machine-deployments"`
+	ForceUnlock               bool `longflag:"force-unlock"`
 }
 
 func (opts *upgradeOpts) BuildState() (*state.State, error) {
@@ -80,6 +81,12 @@ func upgradeCmd(rootFlags *pflag.FlagSet) *cobra.Command {
 		false,
 		"upgrade MachineDeployments objects")
 
+	cmd.Flags().BoolVar(
+		&opts.ForceUnlock,
+		longFlagName(opts, "ForceUnlock"),
+		false,
+		"take over the cluster lock held by another operation (!dangerous!, use only if the lock is stale)")
+
 	return cmd
 }
 
@@ -89,6 +96,13 @@ func runUpgrade(opts *upgradeOpts) error {
 	if err != nil {
 		return errors.Wrap(err, "failed to initialize State")
 	}
+	defer opts.reportProgress(s, "upgrade")
+
+	lock := newClusterLock(s, "upgrade", opts.ForceUnlock)
+	defer releaseClusterLock(s, lock)
+	if err = acquireClusterLock(s, lock); err != nil {
+		return err
+	}
 
 	// Validate credentials
 	_, err = credentials.ProviderCredentials(s.Cluster.CloudProvider, opts.CredentialsFile)
@@ -104,5 +118,9 @@ func runUpgrade(opts *upgradeOpts) error {
 		return err
 	}
 
+	if err = acquireClusterLock(s, lock); err != nil {
+		return err
+	}
+
 	return errors.Wrap(tasks.WithUpgrade(nil).Run(s), "failed to upgrade cluster")
 }
@@ -0,0 +1,155 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8c.io/kubeone/pkg/hardening"
+	"k8c.io/kubeone/pkg/tabwriter"
+
+	kyaml "sigs.k8s.io/yaml"
+)
+
+type verifyCISOpts struct {
+	globalOptions
+	Output string `longflag:"output" shortflag:"o"`
+}
+
+func verifyCmd(fs *pflag.FlagSet) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Commands for verifying the compliance of the cluster",
+	}
+
+	cmd.AddCommand(verifyCISCmd(fs))
+	return cmd
+}
+
+func verifyCISCmd(fs *pflag.FlagSet) *cobra.Command {
+	opts := &verifyCISOpts{}
+
+	cmd := &cobra.Command{
+		Use:   "cis",
+		Short: "Report the compliance of the hosts with the CIS hardening profile",
+		Long: heredoc.Doc(`
+			Report the compliance of the hosts with the CIS hardening profile (.hardening.profile: cis),
+			without changing them.
+
+			The permissions and the ownership of the Kubernetes configuration files, the flags of the
+			control plane components, the kubelet settings and the auditd rules are checked on each host.
+			Controls are identified by the CIS Kubernetes Benchmark v1.6.0 recommendation numbers.
+			Audit logging of the API server (1.2.22) is configured by .features.staticAuditLog.
+
+			The command fails if any control fails on any host.
+		`),
+		Args:    cobra.ExactArgs(0),
+		Example: `kubeone verify cis -m mycluster.yaml -t tf.json`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			gopts, err := persistentGlobalOptions(fs)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			opts.globalOptions = *gopts
+
+			return runVerifyCIS(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(
+		&opts.Output,
+		longFlagName(opts, "Output"),
+		shortFlagName(opts, "Output"),
+		statusOutputTable,
+		fmt.Sprintf("output format, one of: %s, %s, %s", statusOutputTable, statusOutputJSON, statusOutputYAML))
+
+	return cmd
+}
+
+func runVerifyCIS(opts *verifyCISOpts) error {
+	if opts.Output != statusOutputTable && opts.Output != statusOutputJSON && opts.Output != statusOutputYAML {
+		return errors.Errorf("unknown output format %q", opts.Output)
+	}
+
+	s, err := opts.BuildState()
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize State")
+	}
+
+	report := hardening.Verify(s)
+
+	switch opts.Output {
+	case statusOutputJSON:
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal CIS report")
+		}
+		fmt.Println(string(out))
+	case statusOutputYAML:
+		out, err := kyaml.Marshal(report)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal CIS report")
+		}
+		fmt.Print(string(out))
+	default:
+		printCISReport(report)
+	}
+
+	if !report.Passed {
+		return errors.New("CIS controls failed")
+	}
+
+	return nil
+}
+
+// printCISReport prints the status of the controls of each host, followed by
+// the details of the failed controls
+func printCISReport(report *hardening.Report) {
+	printer := tabwriter.GetNewTabWriter(os.Stdout)
+
+	fmt.Fprintln(printer, "HOST\tROLE\tID\tSTATUS\tDESCRIPTION")
+	for _, host := range report.Hosts {
+		for _, res := range host.Results {
+			fmt.Fprintf(printer, "%s\t%s\t%s\t%s\t%s\n", host.PublicAddress, host.Role, res.ID, res.Status, res.Description)
+		}
+	}
+	printer.Flush()
+
+	details := []string{}
+	for _, host := range report.Hosts {
+		for _, res := range host.Results {
+			if res.Status != hardening.StatusPass {
+				details = append(details, fmt.Sprintf("%s %s [%s]: %s", host.PublicAddress, res.ID, res.Status, res.Message))
+			}
+		}
+	}
+
+	if len(details) > 0 {
+		fmt.Println()
+		for _, d := range details {
+			fmt.Println(d)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"time"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8c.io/kubeone/pkg/certificate"
+	"k8c.io/kubeone/pkg/drift"
+	"k8c.io/kubeone/pkg/kubeconfig"
+	"k8c.io/kubeone/pkg/state"
+	"k8c.io/kubeone/pkg/tasks"
+)
+
+type watchOpts struct {
+	globalOptions
+	Interval   time.Duration `longflag:"interval"`
+	Once       bool          `longflag:"once"`
+	WebhookURL string        `longflag:"webhook-url"`
+}
+
+func watchCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	opts := &watchOpts{}
+
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Periodically detect drift between the manifest and the cluster",
+		Long: heredoc.Doc(`
+			Periodically compare the KubeOneCluster manifest against the cluster and report drift.
+
+			The following drift is detected:
+			  * objects deployed by the addons that are missing or modified in the cluster
+			  * MachineDeployments defined in the manifest that are missing in the cluster
+			  * kubeadm ClusterConfiguration (Kubernetes version, control plane endpoint and
+			    networking) that doesn't match the manifest
+
+			Detected drift is logged and, if '--webhook-url' is provided, sent to the webhook as JSON.
+			With '--once', the check is run only once and the command exits with a non-zero exit code
+			if drift is detected.
+
+			This command doesn't change the cluster.
+		`),
+		Example: `kubeone watch -m mycluster.yaml -t terraformoutput.json --interval 5m`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			gopts, err := persistentGlobalOptions(rootFlags)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			opts.globalOptions = *gopts
+
+			return runWatch(opts)
+		},
+	}
+
+	cmd.Flags().DurationVar(
+		&opts.Interval,
+		longFlagName(opts, "Interval"),
+		10*time.Minute,
+		"interval between drift checks")
+
+	cmd.Flags().BoolVar(
+		&opts.Once,
+		longFlagName(opts, "Once"),
+		false,
+		"check for drift only once and exit with a non-zero exit code if drift is detected")
+
+	cmd.Flags().StringVar(
+		&opts.WebhookURL,
+		longFlagName(opts, "WebhookURL"),
+		"",
+		"URL to send the detected drift to as a JSON POST request")
+
+	return cmd
+}
+
+func runWatch(opts *watchOpts) error {
+	if opts.Interval <= 0 {
+		return errors.New("--interval must be greater than zero")
+	}
+
+	opts.ReadOnly = true
+	s, err := opts.BuildState()
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize State")
+	}
+
+	if opts.Once {
+		drifts, err := detectDrift(s, opts.WebhookURL)
+		if err != nil {
+			return err
+		}
+		if len(drifts) > 0 {
+			return errors.Errorf("detected %d drift(s) between the manifest and the cluster", len(drifts))
+		}
+
+		return nil
+	}
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := detectDrift(s, opts.WebhookURL); err != nil {
+			s.Logger.Errorf("Failed to detect drift: %v", err)
+		}
+
+		select {
+		case <-s.Context.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// detectDrift probes the cluster, detects drift, and reports it in the log
+// and to the webhook, if provided
+func detectDrift(s *state.State, webhookURL string) ([]drift.Drift, error) {
+	probbing := tasks.WithHostnameOS(nil)
+	probbing = tasks.WithProbes(probbing)
+
+	if err := probbing.Run(s); err != nil {
+		return nil, err
+	}
+
+	if !s.LiveCluster.IsProvisioned() {
+		return nil, errors.New("the target cluster is not provisioned")
+	}
+
+	if s.DynamicClient == nil {
+		if err := kubeconfig.BuildKubernetesClientset(s); err != nil {
+			return nil, errors.Wrap(err, "failed to build kubernetes clientset")
+		}
+	}
+
+	if err := s.RunTaskOnLeader(certificate.DownloadKubePKI); err != nil {
+		return nil, errors.Wrap(err, "failed to download Kubernetes PKI")
+	}
+
+	drifts, err := drift.Detect(s)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to detect drift")
+	}
+
+	if len(drifts) == 0 {
+		s.Logger.Info("No drift detected.")
+		return nil, nil
+	}
+
+	for _, d := range drifts {
+		s.Logger.Warnf("Drift detected: %s", d)
+	}
+
+	if webhookURL != "" {
+		if err := drift.Notify(s.Context, webhookURL, s.Cluster.Name, drifts); err != nil {
+			return drifts, err
+		}
+	}
+
+	return drifts, nil
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8c.io/kubeone/pkg/kubeconfig"
+	"k8c.io/kubeone/pkg/templates/machinecontroller"
+)
+
+type workersScaleOpts struct {
+	globalOptions
+	AutoApprove bool  `longflag:"auto-approve" shortflag:"y"`
+	Replicas    int32 `longflag:"replicas"`
+}
+
+func workersCmd(fs *pflag.FlagSet) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "workers",
+		Short: "Commands for managing machine-controller managed worker nodes",
+	}
+
+	cmd.AddCommand(workersScaleCmd(fs))
+	return cmd
+}
+
+func workersScaleCmd(fs *pflag.FlagSet) *cobra.Command {
+	opts := &workersScaleOpts{}
+
+	cmd := &cobra.Command{
+		Use:   "scale <machinedeployment-name>",
+		Short: "Scale the MachineDeployment",
+		Long: heredoc.Doc(`
+			Set the number of replicas of the MachineDeployment. Scaling to zero replicas deletes all
+			machines, while the MachineDeployment is kept, so the worker pool can be scaled up again later on.
+
+			MachineDeployments created by KubeOne are named after the .dynamicWorkers[].name field.
+		`),
+		Example: `kubeone workers scale -m mycluster.yaml mycluster-pool1 --replicas 0`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			gopts, err := persistentGlobalOptions(fs)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			opts.globalOptions = *gopts
+
+			return runWorkersScale(opts, args[0])
+		},
+	}
+
+	cmd.Flags().BoolVarP(
+		&opts.AutoApprove,
+		longFlagName(opts, "AutoApprove"),
+		shortFlagName(opts, "AutoApprove"),
+		false,
+		"auto approve plan")
+
+	cmd.Flags().Int32Var(
+		&opts.Replicas,
+		longFlagName(opts, "Replicas"),
+		0,
+		"desired number of replicas")
+
+	_ = cmd.MarkFlagRequired(longFlagName(opts, "Replicas"))
+
+	return cmd
+}
+
+func runWorkersScale(opts *workersScaleOpts, name string) error {
+	if opts.Replicas < 0 {
+		return errors.New("--replicas must not be negative")
+	}
+
+	s, err := opts.BuildState()
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize State")
+	}
+
+	if !s.Cluster.MachineController.Deploy {
+		return errors.New("scaling workers requires machine-controller to be deployed")
+	}
+
+	if err = kubeconfig.BuildKubernetesClientset(s); err != nil {
+		return errors.Wrap(err, "failed to build kubernetes clientset")
+	}
+
+	md, err := machinecontroller.GetMachineDeployment(s, name)
+	if err != nil {
+		return err
+	}
+
+	var currentReplicas int32
+	if md.Spec.Replicas != nil {
+		currentReplicas = *md.Spec.Replicas
+	}
+
+	if currentReplicas == opts.Replicas {
+		s.Logger.Infof("MachineDeployment %q already has %d replica(s).", name, opts.Replicas)
+		return nil
+	}
+
+	fmt.Println("The following actions will be taken: ")
+	fmt.Printf("\t~ scale MachineDeployment %q: %d -> %d replica(s)\n", name, currentReplicas, opts.Replicas)
+
+	fmt.Println()
+	confirm, err := confirmCommand(opts.AutoApprove)
+	if err != nil {
+		return err
+	}
+
+	if !confirm {
+		s.Logger.Println("Operation canceled.")
+		return nil
+	}
+
+	return machinecontroller.ScaleMachineDeployment(s, name, opts.Replicas)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package configupload
 
 import (
+	"bytes"
 	"io"
 	"io/ioutil"
 	"path/filepath"
@@ -25,6 +26,7 @@ import (
 	"github.com/pkg/errors"
 
 	"k8c.io/kubeone/pkg/archive"
+	"k8c.io/kubeone/pkg/filelock"
 	"k8c.io/kubeone/pkg/ssh"
 	"k8c.io/kubeone/pkg/ssh/sshiofs"
 )
@@ -111,24 +113,38 @@ func (c *Configuration) UploadTo(conn ssh.Connection, directory string) error {
 	return nil
 }
 
-// Backup dumps the files into a .tar.gz archive.
-func (c *Configuration) Backup(target string) error {
-	archive, err := archive.NewTarGzip(target)
-	if err != nil {
-		return errors.Wrap(err, "failed to open archive")
+// Backup dumps the files into a .tar.gz archive, encrypted by the encrypt
+// function, if it's not nil. The archive is written atomically, so an
+// interrupted or a parallel backup doesn't leave a corrupted archive behind.
+func (c *Configuration) Backup(target string, encrypt func([]byte) ([]byte, error)) error {
+	var buf bytes.Buffer
+	if err := c.writeBackup(&buf); err != nil {
+		return err
+	}
+
+	data := buf.Bytes()
+	if encrypt != nil {
+		var err error
+		if data, err = encrypt(data); err != nil {
+			return err
+		}
 	}
+
+	return filelock.WriteFile(target, data, 0600)
+}
+
+func (c *Configuration) writeBackup(w io.Writer) error {
+	archive := archive.NewTarGzipWriter(w)
 	defer archive.Close()
 
 	for filename, content := range c.files {
-		err = archive.Add(filename, content)
-		if err != nil {
+		if err := archive.Add(filename, content); err != nil {
 			return errors.Wrapf(err, "failed to add %s to archive", filename)
 		}
 	}
 
 	for filename, content := range c.KubernetesPKI {
-		err = archive.Add(strings.TrimPrefix(filename, "/"), string(content))
-		if err != nil {
+		if err := archive.Add(strings.TrimPrefix(filename, "/"), string(content)); err != nil {
 			return errors.Wrapf(err, "failed to add %s to archive", filename)
 		}
 	}
@@ -0,0 +1,148 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentialcheck
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awscredentials "github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/credentials"
+)
+
+// awsDefaultRegion is used to describe the regions if the region is set
+// neither in the environment nor in the dynamic workers
+const awsDefaultRegion = "us-east-1"
+
+type awsChecker struct {
+	sess *session.Session
+}
+
+func newAWSChecker(cluster *kubeoneapi.KubeOneCluster, creds map[string]string) (*awsChecker, error) {
+	config := aws.NewConfig()
+
+	if creds[credentials.AWSAccessKeyID] != "" {
+		config = config.WithCredentials(awscredentials.NewStaticCredentials(creds[credentials.AWSAccessKeyID], creds[credentials.AWSSecretAccessKey], ""))
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *config,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create aws session")
+	}
+
+	if aws.StringValue(sess.Config.Region) == "" {
+		region := workersSpecField(cluster, "region")
+		if region == "" {
+			region = awsDefaultRegion
+		}
+		sess = sess.Copy(aws.NewConfig().WithRegion(region))
+	}
+
+	return &awsChecker{sess: sess}, nil
+}
+
+func (c *awsChecker) Check(ctx context.Context) (*Report, error) {
+	identity, err := sts.New(c.sess).GetCallerIdentityWithContext(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get caller identity")
+	}
+
+	report := &Report{
+		Provider: "AWS",
+		Identity: aws.StringValue(identity.Arn),
+	}
+
+	_, err = ec2.New(c.sess).DescribeRegionsWithContext(ctx, &ec2.DescribeRegionsInput{})
+	switch {
+	case isAWSErrorCode(err, "UnauthorizedOperation"):
+		report.Missing = append(report.Missing, "ec2:DescribeRegions")
+	case err != nil:
+		return nil, errors.Wrap(err, "failed to describe regions")
+	}
+
+	principal := awsPrincipalARN(report.Identity)
+	if principal == "" {
+		// The root user is granted all permissions
+		return report, nil
+	}
+
+	granted := map[string]bool{}
+	input := &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String(principal),
+		ActionNames:     aws.StringSlice(RequiredPermissions["aws"]),
+	}
+	err = iam.New(c.sess).SimulatePrincipalPolicyPagesWithContext(ctx, input, func(out *iam.SimulatePolicyResponse, _ bool) bool {
+		for _, result := range out.EvaluationResults {
+			granted[aws.StringValue(result.EvalActionName)] = aws.StringValue(result.EvalDecision) == iam.PolicyEvaluationDecisionTypeAllowed
+		}
+		return true
+	})
+	if err != nil {
+		if !isAWSErrorCode(err, "AccessDenied") {
+			return nil, errors.Wrap(err, "failed to simulate principal policy")
+		}
+
+		// Without the simulation only the DescribeRegions call tells about
+		// the permissions
+		report.Warnings = append(report.Warnings, "permissions can't be verified without the iam:SimulatePrincipalPolicy permission")
+		return report, nil
+	}
+
+	report.Missing = missing(RequiredPermissions["aws"], func(permission string) bool {
+		return granted[permission]
+	})
+
+	return report, nil
+}
+
+// awsPrincipalARN returns the ARN of the IAM user or role the caller identity
+// belongs to, or an empty string for the root user. The path of the assumed
+// role is not part of the caller identity, so roles with path are not found.
+func awsPrincipalARN(identity string) string {
+	parts := strings.SplitN(identity, ":", 6)
+	if len(parts) != 6 {
+		return identity
+	}
+
+	resource := parts[5]
+	switch {
+	case resource == "root":
+		return ""
+	case parts[2] == "sts" && strings.HasPrefix(resource, "assumed-role/"):
+		role := strings.Split(strings.TrimPrefix(resource, "assumed-role/"), "/")[0]
+		return strings.Join([]string{parts[0], parts[1], "iam", "", parts[4], "role/" + role}, ":")
+	}
+
+	return identity
+}
+
+func isAWSErrorCode(err error, code string) bool {
+	var awsErr awserr.Error
+	return errors.As(err, &awsErr) && awsErr.Code() == code
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentialcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2/clientcredentials"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/credentials"
+)
+
+const (
+	azureLoginEndpoint      = "https://login.microsoftonline.com"
+	azureManagementEndpoint = "https://management.azure.com"
+)
+
+type azureChecker struct {
+	loginEndpoint      string
+	managementEndpoint string
+	creds              map[string]string
+	resourceGroup      string
+}
+
+type azurePermissionsResponse struct {
+	Value []struct {
+		Actions    []string `json:"actions"`
+		NotActions []string `json:"notActions"`
+	} `json:"value"`
+}
+
+func newAzureChecker(cluster *kubeoneapi.KubeOneCluster, creds map[string]string) *azureChecker {
+	return &azureChecker{
+		loginEndpoint:      azureLoginEndpoint,
+		managementEndpoint: azureManagementEndpoint,
+		creds:              creds,
+		resourceGroup:      workersSpecField(cluster, "resourceGroup"),
+	}
+}
+
+func (c *azureChecker) Check(ctx context.Context) (*Report, error) {
+	config := clientcredentials.Config{
+		ClientID:     c.creds[credentials.AzureClientIDMC],
+		ClientSecret: c.creds[credentials.AzureClientSecretMC],
+		TokenURL:     fmt.Sprintf("%s/%s/oauth2/v2.0/token", c.loginEndpoint, c.creds[credentials.AzureTenantIDMC]),
+		Scopes:       []string{c.managementEndpoint + "/.default"},
+	}
+
+	// The permissions are looked up in the resource group of the workers, as
+	// the service principal is often granted access only to it
+	scope := "/subscriptions/" + c.creds[credentials.AzureSubscribtionIDMC]
+	if c.resourceGroup != "" {
+		scope += "/resourceGroups/" + c.resourceGroup
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.managementEndpoint+scope+"/providers/Microsoft.Authorization/permissions?api-version=2015-07-01", nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	permissions := azurePermissionsResponse{}
+	if err = doJSON(config.Client(ctx), req, &permissions); err != nil {
+		return nil, errors.Wrap(err, "failed to list permissions")
+	}
+
+	report := &Report{
+		Provider: "Azure",
+		Identity: config.ClientID,
+	}
+	report.Missing = missing(RequiredPermissions["azure"], func(permission string) bool {
+		for _, p := range permissions.Value {
+			if azureMatchesAny(p.Actions, permission) && !azureMatchesAny(p.NotActions, permission) {
+				return true
+			}
+		}
+		return false
+	})
+
+	return report, nil
+}
+
+// azureMatchesAny returns true if the action matches any of the patterns. The
+// patterns are case-insensitive and the wildcard matches any characters.
+func azureMatchesAny(patterns []string, action string) bool {
+	for _, pattern := range patterns {
+		expr := "(?i)^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+		if matched, _ := regexp.MatchString(expr, action); matched {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package credentialcheck validates the cloud provider credentials before any
+// changes are made, by issuing harmless read-only API calls, and reports the
+// permissions required by KubeOne and machine-controller which are not
+// granted. Without the check, bad credentials surface only when
+// machine-controller fails to create the machines.
+package credentialcheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+)
+
+// ErrUnsupported is returned by New if the credentials of the cloud provider
+// can't be checked
+var ErrUnsupported = errors.New("credentials check is supported only on AWS, Azure, DigitalOcean, GCE, Hetzner and OpenStack")
+
+// RequiredPermissions are the permissions required on each cloud provider, as
+// documented in docs/required_permissions.md. The list is checked only on the
+// providers which allow the granted permissions to be looked up.
+var RequiredPermissions = map[string][]string{
+	"aws": {
+		"ec2:AttachVolume",
+		"ec2:AuthorizeSecurityGroupIngress",
+		"ec2:CreateSecurityGroup",
+		"ec2:CreateTags",
+		"ec2:CreateVolume",
+		"ec2:DeleteVolume",
+		"ec2:DescribeAvailabilityZones",
+		"ec2:DescribeImages",
+		"ec2:DescribeInstances",
+		"ec2:DescribeRegions",
+		"ec2:DescribeSecurityGroups",
+		"ec2:DescribeSubnets",
+		"ec2:DescribeVolumes",
+		"ec2:DescribeVpcs",
+		"ec2:DetachVolume",
+		"ec2:RunInstances",
+		"ec2:TerminateInstances",
+		"elasticloadbalancing:CreateLoadBalancer",
+		"elasticloadbalancing:DeleteLoadBalancer",
+		"elasticloadbalancing:DescribeLoadBalancers",
+		"iam:PassRole",
+	},
+	"azure": {
+		"Microsoft.Compute/availabilitySets/read",
+		"Microsoft.Compute/disks/delete",
+		"Microsoft.Compute/disks/read",
+		"Microsoft.Compute/virtualMachines/delete",
+		"Microsoft.Compute/virtualMachines/read",
+		"Microsoft.Compute/virtualMachines/write",
+		"Microsoft.Network/networkInterfaces/delete",
+		"Microsoft.Network/networkInterfaces/read",
+		"Microsoft.Network/networkInterfaces/write",
+		"Microsoft.Network/networkSecurityGroups/join/action",
+		"Microsoft.Network/publicIPAddresses/delete",
+		"Microsoft.Network/publicIPAddresses/read",
+		"Microsoft.Network/publicIPAddresses/write",
+		"Microsoft.Network/virtualNetworks/read",
+		"Microsoft.Network/virtualNetworks/subnets/join/action",
+	},
+	"gce": {
+		"compute.disks.create",
+		"compute.instances.create",
+		"compute.instances.delete",
+		"compute.instances.get",
+		"compute.instances.list",
+		"compute.instances.setLabels",
+		"compute.instances.setMetadata",
+		"compute.instances.setServiceAccount",
+		"compute.instances.setTags",
+		"compute.subnetworks.use",
+		"compute.subnetworks.useExternalIp",
+		"iam.serviceAccounts.actAs",
+	},
+	"openstack": {
+		"compute",
+		"image",
+		"network",
+	},
+}
+
+// Checker validates the credentials of the cloud provider
+type Checker interface {
+	Check(ctx context.Context) (*Report, error)
+}
+
+// Report is the result of the credentials check
+type Report struct {
+	// Provider is the name of the cloud provider
+	Provider string
+	// Identity is the user or the account the credentials belong to, if known
+	Identity string
+	// Missing are the required permissions which are not granted
+	Missing []string
+	// Warnings explain why the permissions couldn't be fully verified
+	Warnings []string
+}
+
+// Passed returns true if no required permissions are missing
+func (r *Report) Passed() bool {
+	return len(r.Missing) == 0
+}
+
+func (r *Report) String() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "%s credentials are valid", r.Provider)
+	if r.Identity != "" {
+		fmt.Fprintf(&sb, " (%s)", r.Identity)
+	}
+
+	if len(r.Missing) > 0 {
+		fmt.Fprintf(&sb, ", but the following required permissions are missing: %s", strings.Join(r.Missing, ", "))
+	}
+
+	return sb.String()
+}
+
+// New returns the Checker for the cloud provider used by the cluster
+func New(cluster *kubeoneapi.KubeOneCluster, credentials map[string]string) (Checker, error) {
+	switch {
+	case cluster.CloudProvider.AWS != nil:
+		return newAWSChecker(cluster, credentials)
+	case cluster.CloudProvider.Azure != nil:
+		return newAzureChecker(cluster, credentials), nil
+	case cluster.CloudProvider.DigitalOcean != nil:
+		return newDigitalOceanChecker(credentials), nil
+	case cluster.CloudProvider.GCE != nil:
+		return newGCEChecker(credentials)
+	case cluster.CloudProvider.Hetzner != nil:
+		return newHetznerChecker(credentials), nil
+	case cluster.CloudProvider.Openstack != nil:
+		return newOpenStackChecker(credentials), nil
+	}
+
+	return nil, ErrUnsupported
+}
+
+// missing returns the required permissions not found in the granted ones
+func missing(required []string, granted func(string) bool) []string {
+	result := []string{}
+	for _, permission := range required {
+		if !granted(permission) {
+			result = append(result, permission)
+		}
+	}
+
+	return result
+}
+
+// workersSpecField returns the value of the field of the cloud provider spec
+// of the first dynamic worker which sets it
+func workersSpecField(cluster *kubeoneapi.KubeOneCluster, field string) string {
+	for _, worker := range cluster.DynamicWorkers {
+		spec := map[string]interface{}{}
+		if err := json.Unmarshal(worker.Config.CloudProviderSpec, &spec); err != nil {
+			continue
+		}
+
+		if value, ok := spec[field].(string); ok && value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// doJSON sends the request and decodes the JSON response. The invalid
+// credentials are reported separately from the other failures.
+func doJSON(client *http.Client, req *http.Request, out interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return errors.New("credentials are invalid or expired")
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return errors.Errorf("unexpected status %s", resp.Status)
+	case out == nil:
+		return nil
+	}
+
+	return errors.WithStack(json.NewDecoder(resp.Body).Decode(out))
+}
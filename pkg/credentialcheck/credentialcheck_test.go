@@ -0,0 +1,305 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentialcheck
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHetznerCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{
+			name:  "valid token",
+			token: "valid",
+		},
+		{
+			name:    "invalid token",
+			token:   "invalid",
+			wantErr: true,
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer valid" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"servers": []}`)
+	}))
+	defer server.Close()
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			checker := &hetznerChecker{endpoint: server.URL, token: tt.token, client: server.Client()}
+
+			report, err := checker.Check(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !report.Passed() {
+				t.Errorf("Check() missing = %v, want none", report.Missing)
+			}
+		})
+	}
+}
+
+func TestDigitalOceanCheck(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       string
+		wantIdentity string
+		wantErr      bool
+	}{
+		{
+			name:         "active account",
+			status:       "active",
+			wantIdentity: "user@example.com",
+		},
+		{
+			name:    "locked account",
+			status:  "locked",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/account" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				fmt.Fprintf(w, `{"account": {"email": "user@example.com", "status": %q}}`, tt.status)
+			}))
+			defer server.Close()
+
+			checker := &digitalOceanChecker{endpoint: server.URL, token: "token", client: server.Client()}
+
+			report, err := checker.Check(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && report.Identity != tt.wantIdentity {
+				t.Errorf("Check() identity = %q, want %q", report.Identity, tt.wantIdentity)
+			}
+		})
+	}
+}
+
+func TestAzureCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions string
+		wantMissing []string
+	}{
+		{
+			name:        "all permissions granted by the wildcard",
+			permissions: `{"value": [{"actions": ["*"], "notActions": []}]}`,
+			wantMissing: []string{},
+		},
+		{
+			name:        "permissions denied by not actions",
+			permissions: `{"value": [{"actions": ["Microsoft.Compute/*", "Microsoft.Network/*"], "notActions": ["microsoft.compute/virtualMachines/delete"]}]}`,
+			wantMissing: []string{"Microsoft.Compute/virtualMachines/delete"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/tenant/oauth2/v2.0/token":
+					w.Header().Set("Content-Type", "application/json")
+					fmt.Fprint(w, `{"access_token": "token", "token_type": "Bearer", "expires_in": 3600}`)
+				case r.URL.Path == "/subscriptions/subscription/resourceGroups/rg/providers/Microsoft.Authorization/permissions" && r.Header.Get("Authorization") == "Bearer token":
+					fmt.Fprint(w, tt.permissions)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			checker := &azureChecker{
+				loginEndpoint:      server.URL,
+				managementEndpoint: server.URL,
+				resourceGroup:      "rg",
+				creds: map[string]string{
+					"AZURE_CLIENT_ID":       "client",
+					"AZURE_CLIENT_SECRET":   "secret",
+					"AZURE_TENANT_ID":       "tenant",
+					"AZURE_SUBSCRIPTION_ID": "subscription",
+				},
+			}
+
+			report, err := checker.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if !reflect.DeepEqual(report.Missing, tt.wantMissing) {
+				t.Errorf("Check() missing = %v, want %v", report.Missing, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestGCECheck(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"access_token": "token", "token_type": "Bearer", "expires_in": 3600}`)
+		case r.URL.Path == "/v1/projects/project:testIamPermissions" && r.Header.Get("Authorization") == "Bearer token":
+			req := gcePermissions{}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			granted := gcePermissions{}
+			for _, p := range req.Permissions {
+				if strings.HasPrefix(p, "compute.") {
+					granted.Permissions = append(granted.Permissions, p)
+				}
+			}
+			_ = json.NewEncoder(w).Encode(granted)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	checker := &gceChecker{
+		endpoint: server.URL,
+		serviceAccount: gceServiceAccount{
+			ProjectID:   "project",
+			PrivateKey:  string(privateKey),
+			ClientEmail: "kubeone@project.iam.gserviceaccount.com",
+			TokenURI:    server.URL + "/token",
+		},
+	}
+
+	report, err := checker.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	want := []string{"iam.serviceAccounts.actAs"}
+	if !reflect.DeepEqual(report.Missing, want) {
+		t.Errorf("Check() missing = %v, want %v", report.Missing, want)
+	}
+}
+
+func TestOpenStackCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v3/auth/tokens" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("X-Subject-Token", "token")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"token": {
+			"user": {"name": "user"},
+			"project": {"name": "project"},
+			"catalog": [
+				{"type": "compute", "endpoints": [{"interface": "public", "region": "region1"}]},
+				{"type": "image", "endpoints": [{"interface": "public", "region": "region1"}]},
+				{"type": "network", "endpoints": [{"interface": "public", "region": "region2"}]}
+			]
+		}}`)
+	}))
+	defer server.Close()
+
+	checker := &openstackChecker{
+		client: server.Client(),
+		creds: map[string]string{
+			"OS_AUTH_URL":    server.URL,
+			"OS_REGION_NAME": "region1",
+		},
+	}
+
+	report, err := checker.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	if report.Identity != "user@project" {
+		t.Errorf("Check() identity = %q, want %q", report.Identity, "user@project")
+	}
+
+	want := []string{"network service in region region1"}
+	if !reflect.DeepEqual(report.Missing, want) {
+		t.Errorf("Check() missing = %v, want %v", report.Missing, want)
+	}
+}
+
+func TestAWSPrincipalARN(t *testing.T) {
+	tests := []struct {
+		name     string
+		identity string
+		want     string
+	}{
+		{
+			name:     "user",
+			identity: "arn:aws:iam::123456789012:user/kubeone",
+			want:     "arn:aws:iam::123456789012:user/kubeone",
+		},
+		{
+			name:     "assumed role",
+			identity: "arn:aws:sts::123456789012:assumed-role/kubeone/session",
+			want:     "arn:aws:iam::123456789012:role/kubeone",
+		},
+		{
+			name:     "root",
+			identity: "arn:aws:iam::123456789012:root",
+			want:     "",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := awsPrincipalARN(tt.identity); got != tt.want {
+				t.Errorf("awsPrincipalARN() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
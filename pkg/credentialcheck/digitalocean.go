@@ -0,0 +1,73 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentialcheck
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/credentials"
+)
+
+const digitalOceanEndpoint = "https://api.digitalocean.com/v2"
+
+type digitalOceanChecker struct {
+	endpoint string
+	token    string
+	client   *http.Client
+}
+
+type digitalOceanAccountResponse struct {
+	Account struct {
+		Email         string `json:"email"`
+		Status        string `json:"status"`
+		StatusMessage string `json:"status_message"`
+	} `json:"account"`
+}
+
+func newDigitalOceanChecker(creds map[string]string) *digitalOceanChecker {
+	return &digitalOceanChecker{
+		endpoint: digitalOceanEndpoint,
+		token:    creds[credentials.DigitalOceanTokenKeyMC],
+		client:   http.DefaultClient,
+	}
+}
+
+func (c *digitalOceanChecker) Check(ctx context.Context) (*Report, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+"/account", nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	account := digitalOceanAccountResponse{}
+	if err = doJSON(c.client, req, &account); err != nil {
+		return nil, errors.Wrap(err, "failed to get account")
+	}
+
+	// Locked and unverified accounts can't create droplets
+	if account.Account.Status != "active" {
+		return nil, errors.Errorf("account %s is %s: %s", account.Account.Email, account.Account.Status, account.Account.StatusMessage)
+	}
+
+	return &Report{
+		Provider: "DigitalOcean",
+		Identity: account.Account.Email,
+	}, nil
+}
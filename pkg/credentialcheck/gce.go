@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentialcheck
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2/jwt"
+
+	"k8c.io/kubeone/pkg/credentials"
+)
+
+const (
+	gceResourceManagerEndpoint = "https://cloudresourcemanager.googleapis.com"
+	gceDefaultTokenURL         = "https://oauth2.googleapis.com/token"
+	gceCloudPlatformScope      = "https://www.googleapis.com/auth/cloud-platform"
+)
+
+type gceChecker struct {
+	endpoint       string
+	serviceAccount gceServiceAccount
+}
+
+type gceServiceAccount struct {
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+type gcePermissions struct {
+	Permissions []string `json:"permissions"`
+}
+
+func newGCEChecker(creds map[string]string) (*gceChecker, error) {
+	// The service account is encoded once more for machine-controller
+	buf, err := base64.StdEncoding.DecodeString(creds[credentials.GoogleServiceAccountKeyMC])
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode the service account")
+	}
+
+	sa := gceServiceAccount{}
+	if err = json.Unmarshal(buf, &sa); err != nil {
+		return nil, errors.Wrap(err, "failed to parse the service account")
+	}
+
+	if sa.TokenURI == "" {
+		sa.TokenURI = gceDefaultTokenURL
+	}
+
+	return &gceChecker{
+		endpoint:       gceResourceManagerEndpoint,
+		serviceAccount: sa,
+	}, nil
+}
+
+func (c *gceChecker) Check(ctx context.Context) (*Report, error) {
+	config := jwt.Config{
+		Email:        c.serviceAccount.ClientEmail,
+		PrivateKey:   []byte(c.serviceAccount.PrivateKey),
+		PrivateKeyID: c.serviceAccount.PrivateKeyID,
+		TokenURL:     c.serviceAccount.TokenURI,
+		Scopes:       []string{gceCloudPlatformScope},
+	}
+
+	body, err := json.Marshal(gcePermissions{Permissions: RequiredPermissions["gce"]})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+"/v1/projects/"+c.serviceAccount.ProjectID+":testIamPermissions", bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// Only the granted permissions are returned
+	granted := gcePermissions{}
+	if err = doJSON(config.Client(ctx), req, &granted); err != nil {
+		return nil, errors.Wrap(err, "failed to test IAM permissions")
+	}
+
+	report := &Report{
+		Provider: "GCE",
+		Identity: c.serviceAccount.ClientEmail,
+	}
+	report.Missing = missing(RequiredPermissions["gce"], func(permission string) bool {
+		for _, p := range granted.Permissions {
+			if p == permission {
+				return true
+			}
+		}
+		return false
+	})
+
+	return report, nil
+}
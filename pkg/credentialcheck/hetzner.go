@@ -0,0 +1,62 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentialcheck
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/credentials"
+)
+
+const hetznerEndpoint = "https://api.hetzner.cloud/v1"
+
+// hetznerChecker checks if the token is valid. Hetzner doesn't allow the
+// permissions of the token to be looked up, so read-only tokens are not
+// reported.
+type hetznerChecker struct {
+	endpoint string
+	token    string
+	client   *http.Client
+}
+
+func newHetznerChecker(creds map[string]string) *hetznerChecker {
+	return &hetznerChecker{
+		endpoint: hetznerEndpoint,
+		token:    creds[credentials.HetznerTokenKeyMC],
+		client:   http.DefaultClient,
+	}
+}
+
+func (c *hetznerChecker) Check(ctx context.Context) (*Report, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+"/servers?per_page=1", nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	if err = doJSON(c.client, req, nil); err != nil {
+		return nil, errors.Wrap(err, "failed to list servers")
+	}
+
+	return &Report{
+		Provider: "Hetzner",
+		Warnings: []string{"Hetzner doesn't allow checking if the token is read-only"},
+	}, nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentialcheck
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/credentials"
+)
+
+// openstackChecker requests the token from Keystone and checks if the
+// required services are available in the region. The permissions are
+// enforced by the policies of each service, so they can't be looked up.
+type openstackChecker struct {
+	creds  map[string]string
+	client *http.Client
+}
+
+type openstackTokenResponse struct {
+	Token struct {
+		User struct {
+			Name string `json:"name"`
+		} `json:"user"`
+		Project struct {
+			Name string `json:"name"`
+		} `json:"project"`
+		Catalog []struct {
+			Type      string `json:"type"`
+			Endpoints []struct {
+				Interface string `json:"interface"`
+				Region    string `json:"region"`
+			} `json:"endpoints"`
+		} `json:"catalog"`
+	} `json:"token"`
+}
+
+func newOpenStackChecker(creds map[string]string) *openstackChecker {
+	return &openstackChecker{
+		creds:  creds,
+		client: http.DefaultClient,
+	}
+}
+
+func (c *openstackChecker) Check(ctx context.Context) (*Report, error) {
+	domain := map[string]string{"name": c.creds[credentials.OpenStackDomainName]}
+
+	project := map[string]interface{}{"domain": domain}
+	if id := c.creds[credentials.OpenStackTenantID]; id != "" {
+		project = map[string]interface{}{"id": id}
+	} else {
+		project["name"] = c.creds[credentials.OpenStackTenantName]
+	}
+
+	body := map[string]interface{}{
+		"auth": map[string]interface{}{
+			"identity": map[string]interface{}{
+				"methods": []string{"password"},
+				"password": map[string]interface{}{
+					"user": map[string]interface{}{
+						"name":     c.creds[credentials.OpenStackUserNameMC],
+						"domain":   domain,
+						"password": c.creds[credentials.OpenStackPassword],
+					},
+				},
+			},
+			"scope": map[string]interface{}{"project": project},
+		},
+	}
+
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	authURL := strings.TrimSuffix(c.creds[credentials.OpenStackAuthURL], "/")
+	if !strings.HasSuffix(authURL, "/v3") {
+		authURL += "/v3"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, authURL+"/auth/tokens", bytes.NewReader(buf))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	token := openstackTokenResponse{}
+	if err = doJSON(c.client, req, &token); err != nil {
+		return nil, errors.Wrap(err, "failed to authenticate")
+	}
+
+	region := c.creds[credentials.OpenStackRegionName]
+	report := &Report{
+		Provider: "OpenStack",
+		Identity: token.Token.User.Name + "@" + token.Token.Project.Name,
+	}
+	report.Missing = missing(RequiredPermissions["openstack"], func(service string) bool {
+		for _, s := range token.Token.Catalog {
+			if s.Type != service {
+				continue
+			}
+			for _, endpoint := range s.Endpoints {
+				if endpoint.Interface == "public" && endpoint.Region == region {
+					return true
+				}
+			}
+		}
+		return false
+	})
+	for i := range report.Missing {
+		report.Missing[i] += " service in region " + region
+	}
+
+	return report, nil
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/apis/kubeone"
+)
+
+// cloudConfigTemplateData is passed to the cloud-config template
+type cloudConfigTemplateData struct {
+	// Credentials are the provider credentials, using the same keys as the
+	// cloud-provider-credentials Secret, e.g. OS_USER_NAME and OS_PASSWORD
+	Credentials map[string]string
+}
+
+// IsCloudConfigTemplate returns true if the cloud-config contains template
+// actions, i.e. it has to be rendered before it's used
+func IsCloudConfigTemplate(cloudConfig string) bool {
+	return strings.Contains(cloudConfig, "{{")
+}
+
+// RenderCloudConfig renders the cloud-config as a Go template with the
+// provider credentials available as .Credentials, so credentials don't
+// have to be embedded in the manifest. Referencing a credential which is
+// not set is an error.
+// The azure.json cloud-config is generated from the credentials, if it's
+// configured (.cloudProvider.azure.cloudConfig) and not provided.
+func RenderCloudConfig(cloudProvider kubeone.CloudProviderSpec, credentialsFilePath string) (string, error) {
+	if cloudProvider.CloudConfig == "" && cloudProvider.Azure != nil && cloudProvider.Azure.CloudConfig != nil {
+		creds, err := ProviderCredentials(cloudProvider, credentialsFilePath)
+		if err != nil {
+			return "", errors.Wrap(err, "unable to fetch cloud provider credentials")
+		}
+
+		return azureCloudConfig(cloudProvider.Azure.CloudConfig, creds)
+	}
+
+	if !IsCloudConfigTemplate(cloudProvider.CloudConfig) {
+		return cloudProvider.CloudConfig, nil
+	}
+
+	creds, err := ProviderCredentials(cloudProvider, credentialsFilePath)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to fetch cloud provider credentials")
+	}
+
+	return renderCloudConfig(cloudProvider.CloudConfig, creds)
+}
+
+func azureCloudConfig(cfg *kubeone.AzureCloudConfig, creds map[string]string) (string, error) {
+	azureJSON := struct {
+		Cloud                      string `json:"cloud"`
+		TenantID                   string `json:"tenantId"`
+		SubscriptionID             string `json:"subscriptionId"`
+		AADClientID                string `json:"aadClientId"`
+		AADClientSecret            string `json:"aadClientSecret"`
+		ResourceGroup              string `json:"resourceGroup"`
+		Location                   string `json:"location"`
+		VMType                     string `json:"vmType"`
+		VNetName                   string `json:"vnetName"`
+		VNetResourceGroup          string `json:"vnetResourceGroup"`
+		SubnetName                 string `json:"subnetName"`
+		SecurityGroupName          string `json:"securityGroupName"`
+		RouteTableName             string `json:"routeTableName,omitempty"`
+		PrimaryAvailabilitySetName string `json:"primaryAvailabilitySetName,omitempty"`
+		LoadBalancerSku            string `json:"loadBalancerSku"`
+		UseInstanceMetadata        bool   `json:"useInstanceMetadata"`
+	}{
+		Cloud:                      cfg.Cloud,
+		TenantID:                   creds[AzureTenantIDMC],
+		SubscriptionID:             creds[AzureSubscribtionIDMC],
+		AADClientID:                creds[AzureClientIDMC],
+		AADClientSecret:            creds[AzureClientSecretMC],
+		ResourceGroup:              cfg.ResourceGroup,
+		Location:                   cfg.Location,
+		VMType:                     "standard",
+		VNetName:                   cfg.VNetName,
+		VNetResourceGroup:          cfg.VNetResourceGroup,
+		SubnetName:                 cfg.SubnetName,
+		SecurityGroupName:          cfg.SecurityGroupName,
+		RouteTableName:             cfg.RouteTableName,
+		PrimaryAvailabilitySetName: cfg.PrimaryAvailabilitySetName,
+		LoadBalancerSku:            cfg.LoadBalancerSku,
+		UseInstanceMetadata:        true,
+	}
+
+	if azureJSON.Cloud == "" {
+		azureJSON.Cloud = "AzurePublicCloud"
+	}
+	if azureJSON.VNetResourceGroup == "" {
+		azureJSON.VNetResourceGroup = cfg.ResourceGroup
+	}
+	if azureJSON.LoadBalancerSku == "" {
+		azureJSON.LoadBalancerSku = "standard"
+	}
+
+	buf, err := json.MarshalIndent(azureJSON, "", "  ")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal the azure.json cloud-config")
+	}
+
+	return string(buf) + "\n", nil
+}
+
+func renderCloudConfig(cloudConfig string, creds map[string]string) (string, error) {
+	tpl, err := template.New("cloud-config").
+		Funcs(sprig.TxtFuncMap()).
+		Option("missingkey=error").
+		Parse(cloudConfig)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse the cloud-config template")
+	}
+
+	var buf bytes.Buffer
+	if err = tpl.Execute(&buf, cloudConfigTemplateData{Credentials: creds}); err != nil {
+		return "", errors.Wrap(err, "failed to render the cloud-config template")
+	}
+
+	return buf.String(), nil
+}
+
+// OpenstackCloudConfig returns the cloud-config used by the OpenStack
+// component, i.e. the cloud-config with the cloud-config sections of the
+// component appended. Sections and options are sorted by name.
+func OpenstackCloudConfig(cloudConfig string, component *kubeone.OpenstackComponent) string {
+	if component == nil || len(component.CloudConfigSections) == 0 {
+		return cloudConfig
+	}
+
+	sections := make([]string, 0, len(component.CloudConfigSections))
+	for name := range component.CloudConfigSections {
+		sections = append(sections, name)
+	}
+	sort.Strings(sections)
+
+	var buf strings.Builder
+	if cloudConfig = strings.TrimRight(cloudConfig, "\n"); cloudConfig != "" {
+		fmt.Fprintf(&buf, "%s\n\n", cloudConfig)
+	}
+
+	for i, name := range sections {
+		if i > 0 {
+			fmt.Fprintf(&buf, "\n")
+		}
+		fmt.Fprintf(&buf, "[%s]\n", name)
+
+		options := component.CloudConfigSections[name]
+		keys := make([]string, 0, len(options))
+		for key := range options {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			fmt.Fprintf(&buf, "%s = \"%s\"\n", key, configValueEscaper.Replace(options[key]))
+		}
+	}
+
+	return buf.String()
+}
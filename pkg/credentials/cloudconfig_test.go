@@ -0,0 +1,169 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"encoding/json"
+	"testing"
+
+	"k8c.io/kubeone/pkg/apis/kubeone"
+)
+
+func TestRenderCloudConfig(t *testing.T) {
+	creds := map[string]string{
+		OpenStackUserNameMC: "admin",
+		OpenStackPassword:   `pa"ss`,
+	}
+
+	tests := []struct {
+		name        string
+		cloudConfig string
+		want        string
+		wantErr     bool
+	}{
+		{
+			name:        "no template",
+			cloudConfig: "[Global]\nusername = \"admin\"\n",
+			want:        "[Global]\nusername = \"admin\"\n",
+		},
+		{
+			name:        "credentials",
+			cloudConfig: "[Global]\nusername = {{ .Credentials.OS_USER_NAME | quote }}\npassword = {{ .Credentials.OS_PASSWORD | quote }}\n",
+			want:        "[Global]\nusername = \"admin\"\npassword = \"pa\\\"ss\"\n",
+		},
+		{
+			name:        "missing credential",
+			cloudConfig: "[Global]\ntenant-id = {{ .Credentials.OS_TENANT_ID }}\n",
+			wantErr:     true,
+		},
+		{
+			name:        "invalid template",
+			cloudConfig: "[Global]\nusername = {{ .Credentials.OS_USER_NAME\n",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderCloudConfig(tt.cloudConfig, creds)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("renderCloudConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("renderCloudConfig() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenstackCloudConfig(t *testing.T) {
+	cloudConfig := "[Global]\nauth-url = \"https://keystone:5000/v3\"\n"
+
+	tests := []struct {
+		name      string
+		component *kubeone.OpenstackComponent
+		want      string
+	}{
+		{
+			name:      "component not configured",
+			component: nil,
+			want:      cloudConfig,
+		},
+		{
+			name:      "no sections",
+			component: &kubeone.OpenstackComponent{},
+			want:      cloudConfig,
+		},
+		{
+			name: "sections",
+			component: &kubeone.OpenstackComponent{
+				CloudConfigSections: map[string]map[string]string{
+					"LoadBalancer": {
+						"use-octavia":         "true",
+						"floating-network-id": `a"b`,
+					},
+					"BlockStorage": {
+						"ignore-volume-az": "true",
+					},
+				},
+			},
+			want: cloudConfig + "\n" +
+				"[BlockStorage]\nignore-volume-az = \"true\"\n\n" +
+				"[LoadBalancer]\nfloating-network-id = \"a\\\"b\"\nuse-octavia = \"true\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := OpenstackCloudConfig(cloudConfig, tt.component); got != tt.want {
+				t.Errorf("OpenstackCloudConfig() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAzureCloudConfig(t *testing.T) {
+	creds := map[string]string{
+		AzureClientIDMC:       "client-id",
+		AzureClientSecretMC:   "client-secret",
+		AzureTenantIDMC:       "tenant-id",
+		AzureSubscribtionIDMC: "subscription-id",
+	}
+
+	got, err := azureCloudConfig(&kubeone.AzureCloudConfig{
+		ResourceGroup:     "rg",
+		Location:          "westeurope",
+		VNetName:          "vnet",
+		SubnetName:        "subnet",
+		SecurityGroupName: "nsg",
+	}, creds)
+	if err != nil {
+		t.Fatalf("azureCloudConfig() error = %v", err)
+	}
+
+	azureJSON := map[string]interface{}{}
+	if err = json.Unmarshal([]byte(got), &azureJSON); err != nil {
+		t.Fatalf("azureCloudConfig() returned invalid JSON: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"cloud":               "AzurePublicCloud",
+		"tenantId":            "tenant-id",
+		"subscriptionId":      "subscription-id",
+		"aadClientId":         "client-id",
+		"aadClientSecret":     "client-secret",
+		"resourceGroup":       "rg",
+		"location":            "westeurope",
+		"vmType":              "standard",
+		"vnetName":            "vnet",
+		"vnetResourceGroup":   "rg",
+		"subnetName":          "subnet",
+		"securityGroupName":   "nsg",
+		"loadBalancerSku":     "standard",
+		"useInstanceMetadata": true,
+	}
+	for key, value := range want {
+		if azureJSON[key] != value {
+			t.Errorf("azure.json %s = %v, want %v", key, azureJSON[key], value)
+		}
+	}
+	if _, ok := azureJSON["routeTableName"]; ok {
+		t.Errorf("azure.json routeTableName is set, but it's not configured")
+	}
+}
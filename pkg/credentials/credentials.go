@@ -17,8 +17,8 @@ limitations under the License.
 package credentials
 
 import (
+	"context"
 	"encoding/base64"
-	"io/ioutil"
 	"os"
 	"strings"
 
@@ -27,6 +27,7 @@ import (
 	"gopkg.in/yaml.v2"
 
 	"k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/secretstore"
 )
 
 // The environment variable names with credential in them
@@ -101,8 +102,8 @@ type ProviderEnvironmentVariable struct {
 	MachineControllerName string
 }
 
-func Any(credentialsFilePath string) (map[string]string, error) {
-	credentialsFinder, err := newCredsFinder(credentialsFilePath)
+func Any(cloudProvider kubeone.CloudProviderSpec, credentialsFilePath string) (map[string]string, error) {
+	credentialsFinder, err := newCredsFinder(cloudProvider, credentialsFilePath)
 	if err != nil {
 		return nil, err
 	}
@@ -120,11 +121,26 @@ func Any(credentialsFilePath string) (map[string]string, error) {
 
 // ProviderCredentials implements fetching credentials for each supported provider
 func ProviderCredentials(cloudProvider kubeone.CloudProviderSpec, credentialsFilePath string) (map[string]string, error) {
-	credentialsFinder, err := newCredsFinder(credentialsFilePath)
+	credentialsFinder, err := newCredsFinder(cloudProvider, credentialsFilePath)
 	if err != nil {
 		return nil, err
 	}
 
+	return credentialsFinder.providerCredentials(cloudProvider)
+}
+
+// ProviderCredentialsFromBytes is the same as ProviderCredentials, but uses
+// the already read (and decrypted) credentials file
+func ProviderCredentialsFromBytes(cloudProvider kubeone.CloudProviderSpec, credentialsFile []byte) (map[string]string, error) {
+	credentialsFinder, err := newCredsFinderFromBytes(cloudProvider, credentialsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return credentialsFinder.providerCredentials(cloudProvider)
+}
+
+func (credentialsFinder lookupFunc) providerCredentials(cloudProvider kubeone.CloudProviderSpec) (map[string]string, error) {
 	switch {
 	case cloudProvider.AWS != nil:
 		return credentialsFinder.aws()
@@ -188,7 +204,24 @@ func ProviderCredentials(cloudProvider kubeone.CloudProviderSpec, credentialsFil
 	return nil, errors.New("no provider matched")
 }
 
-func newCredsFinder(credentialsFilePath string) (lookupFunc, error) {
+func newCredsFinder(cloudProvider kubeone.CloudProviderSpec, credentialsFilePath string) (lookupFunc, error) {
+	if cloudProvider.CredentialsVault != nil || credentialsFilePath == "" {
+		return newCredsFinderFromBytes(cloudProvider, nil)
+	}
+
+	buf, err := secretstore.ReadFile(credentialsFilePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to load credentials file")
+	}
+
+	return newCredsFinderFromBytes(cloudProvider, buf)
+}
+
+func newCredsFinderFromBytes(cloudProvider kubeone.CloudProviderSpec, credentialsFile []byte) (lookupFunc, error) {
+	if cloudProvider.CredentialsVault != nil {
+		return newVaultCredsFinder(cloudProvider.CredentialsVault)
+	}
+
 	staticMap := map[string]string{}
 	finder := func(name string) string {
 		if val := os.Getenv(name); val != "" {
@@ -197,17 +230,29 @@ func newCredsFinder(credentialsFilePath string) (lookupFunc, error) {
 		return staticMap[name]
 	}
 
-	if credentialsFilePath == "" {
-		return finder, nil
+	if err := yaml.Unmarshal(credentialsFile, &staticMap); err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal credentials file")
 	}
 
-	buf, err := ioutil.ReadFile(credentialsFilePath)
+	return finder, nil
+}
+
+// newVaultCredsFinder returns the function looking up credentials in the
+// Vault secret. Environment variables and the credentials file are not used.
+func newVaultCredsFinder(vault *kubeone.VaultCredentials) (lookupFunc, error) {
+	data, err := secretstore.ReadVault(context.Background(), vault.Path)
 	if err != nil {
-		return nil, errors.Wrap(err, "unable to load credentials file")
+		return nil, errors.Wrapf(err, "unable to read credentials from vault secret %q", vault.Path)
 	}
 
-	if err = yaml.Unmarshal(buf, &staticMap); err != nil {
-		return nil, errors.Wrap(err, "unable to unmarshal credentials file")
+	finder := func(name string) string {
+		key := name
+		if k, ok := vault.Keys[name]; ok {
+			key = k
+		}
+
+		val, _ := data[key].(string)
+		return val
 	}
 
 	return finder, nil
@@ -0,0 +1,73 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/apis/kubeone"
+)
+
+// configValueEscaper escapes the values of the generated cloud and CSI configs,
+// which are quoted strings
+var configValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// RenderCSIConfig returns the CSI config, generated from the provider
+// credentials for the vSphere clusters with the CSI driver configured
+// (.cloudProvider.vsphere.csi), unless it's provided in the manifest
+func RenderCSIConfig(clusterName string, cloudProvider kubeone.CloudProviderSpec, credentialsFilePath string) (string, error) {
+	if cloudProvider.CSIConfig != "" || cloudProvider.Vsphere == nil || cloudProvider.Vsphere.CSI == nil {
+		return cloudProvider.CSIConfig, nil
+	}
+
+	creds, err := ProviderCredentials(cloudProvider, credentialsFilePath)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to fetch cloud provider credentials")
+	}
+
+	return vsphereCSIConfig(clusterName, cloudProvider.Vsphere.CSI, creds)
+}
+
+func vsphereCSIConfig(clusterName string, csi *kubeone.VsphereCSIConfig, creds map[string]string) (string, error) {
+	// The address is always prefixed with the scheme
+	server, err := url.Parse(creds[VSphereAddressMC])
+	if err != nil || server.Hostname() == "" {
+		return "", errors.Errorf("invalid %s %q", VSphereAddress, strings.TrimPrefix(creds[VSphereAddressMC], "https://"))
+	}
+
+	port := server.Port()
+	if port == "" {
+		port = "443"
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "[Global]\n")
+	fmt.Fprintf(&buf, "cluster-id = \"%s\"\n", configValueEscaper.Replace(clusterName))
+	fmt.Fprintf(&buf, "\n")
+	fmt.Fprintf(&buf, "[VirtualCenter \"%s\"]\n", server.Hostname())
+	fmt.Fprintf(&buf, "insecure-flag = \"%t\"\n", csi.InsecureFlag)
+	fmt.Fprintf(&buf, "user = \"%s\"\n", configValueEscaper.Replace(creds[VSphereUsernameMC]))
+	fmt.Fprintf(&buf, "password = \"%s\"\n", configValueEscaper.Replace(creds[VSpherePassword]))
+	fmt.Fprintf(&buf, "port = %q\n", port)
+	fmt.Fprintf(&buf, "datacenters = \"%s\"\n", configValueEscaper.Replace(strings.Join(csi.Datacenters, ", ")))
+
+	return buf.String(), nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"testing"
+
+	"k8c.io/kubeone/pkg/apis/kubeone"
+)
+
+func TestVsphereCSIConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		csi     kubeone.VsphereCSIConfig
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "default port",
+			address: "https://vcenter.example.com",
+			csi:     kubeone.VsphereCSIConfig{Datacenters: []string{"dc-1"}},
+			want: "[Global]\ncluster-id = \"test\"\n\n" +
+				"[VirtualCenter \"vcenter.example.com\"]\ninsecure-flag = \"false\"\nuser = \"admin@vsphere.local\"\n" +
+				"password = \"pa\\\"ss\"\nport = \"443\"\ndatacenters = \"dc-1\"\n",
+		},
+		{
+			name:    "custom port and multiple datacenters",
+			address: "https://10.0.0.1:8443",
+			csi:     kubeone.VsphereCSIConfig{Datacenters: []string{"dc-1", "dc-2"}, InsecureFlag: true},
+			want: "[Global]\ncluster-id = \"test\"\n\n" +
+				"[VirtualCenter \"10.0.0.1\"]\ninsecure-flag = \"true\"\nuser = \"admin@vsphere.local\"\n" +
+				"password = \"pa\\\"ss\"\nport = \"8443\"\ndatacenters = \"dc-1, dc-2\"\n",
+		},
+		{
+			name:    "invalid address",
+			address: "https://",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			creds := map[string]string{
+				VSphereAddressMC:  tt.address,
+				VSphereUsernameMC: "admin@vsphere.local",
+				VSpherePassword:   `pa"ss`,
+			}
+
+			got, err := vsphereCSIConfig("test", &tt.csi, creds)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("vsphereCSIConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("vsphereCSIConfig() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
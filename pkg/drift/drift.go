@@ -0,0 +1,373 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drift
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/addons"
+	"k8c.io/kubeone/pkg/state"
+	"k8c.io/kubeone/pkg/templates/machinecontroller"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	kyaml "k8s.io/apimachinery/pkg/util/yaml"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// TypeAddonObject is a drift of an object deployed by an addon
+	TypeAddonObject = "AddonObject"
+	// TypeMachineDeployment is a drift of a MachineDeployment defined in the
+	// manifest
+	TypeMachineDeployment = "MachineDeployment"
+	// TypeKubeadmConfig is a drift of the ClusterConfiguration stored in the
+	// kubeadm-config ConfigMap
+	TypeKubeadmConfig = "KubeadmConfig"
+)
+
+// Drift describes a difference between the KubeOneCluster manifest and the
+// cluster
+type Drift struct {
+	Type   string `json:"type"`
+	Object string `json:"object"`
+	Reason string `json:"reason"`
+}
+
+func (d Drift) String() string {
+	return fmt.Sprintf("%s %s: %s", d.Type, d.Object, d.Reason)
+}
+
+// Detect compares the manifest against the cluster and returns all found
+// drifts. The Kubernetes clients must be initialized and the Kubernetes PKI
+// downloaded, as addons are rendered the same way as when they are applied.
+func Detect(s *state.State) ([]Drift, error) {
+	if s.DynamicClient == nil {
+		return nil, errors.New("kubernetes dynamic client is not initialized")
+	}
+
+	var drifts []Drift
+
+	addonDrifts, err := detectAddons(s)
+	if err != nil {
+		return nil, err
+	}
+	drifts = append(drifts, addonDrifts...)
+
+	mdDrifts, err := detectMachineDeployments(s)
+	if err != nil {
+		return nil, err
+	}
+	drifts = append(drifts, mdDrifts...)
+
+	kubeadmDrifts, err := detectKubeadmConfig(s)
+	if err != nil {
+		return nil, err
+	}
+	drifts = append(drifts, kubeadmDrifts...)
+
+	return drifts, nil
+}
+
+func detectAddons(s *state.State) ([]Drift, error) {
+	rendered, err := addons.RenderUserAddons(s)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to render addons")
+	}
+
+	// Iterate in a stable order, so the reports are comparable between runs
+	addonNames := []string{}
+	for name := range rendered {
+		addonNames = append(addonNames, name)
+	}
+	sort.Strings(addonNames)
+
+	var drifts []Drift
+	for _, name := range addonNames {
+		objs, err := decodeObjects(rendered[name])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode manifests of the addon %q", name)
+		}
+
+		for _, obj := range objs {
+			d, err := detectObject(s, obj)
+			if err != nil {
+				return nil, err
+			}
+			if d != nil {
+				drifts = append(drifts, *d)
+			}
+		}
+	}
+
+	return drifts, nil
+}
+
+func detectObject(s *state.State, desired *metav1unstructured.Unstructured) (*Drift, error) {
+	gvk := desired.GroupVersionKind()
+	mapping, err := s.DynamicClient.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return &Drift{Type: TypeAddonObject, Object: objectName(desired), Reason: "kind is not served by the cluster"}, nil
+		}
+
+		return nil, errors.Wrapf(err, "failed to get REST mapping for %s", gvk)
+	}
+
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace && desired.GetNamespace() == "" {
+		desired.SetNamespace(metav1.NamespaceDefault)
+	}
+
+	live := &metav1unstructured.Unstructured{}
+	live.SetGroupVersionKind(gvk)
+	key := dynclient.ObjectKey{Name: desired.GetName(), Namespace: desired.GetNamespace()}
+	if err := s.DynamicClient.Get(s.Context, key, live); err != nil {
+		if dynclient.IgnoreNotFound(err) == nil {
+			return &Drift{Type: TypeAddonObject, Object: objectName(desired), Reason: "object is missing"}, nil
+		}
+
+		return nil, errors.Wrapf(err, "failed to get %s", objectName(desired))
+	}
+
+	if path := firstDifference(desired.Object, live.Object); path != "" {
+		return &Drift{Type: TypeAddonObject, Object: objectName(desired), Reason: fmt.Sprintf("field %s is modified", path)}, nil
+	}
+
+	return nil, nil
+}
+
+func detectMachineDeployments(s *state.State) ([]Drift, error) {
+	if !s.Cluster.MachineController.Deploy {
+		return nil, nil
+	}
+
+	workersets, err := machinecontroller.Workersets(s.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	var drifts []Drift
+	for _, worker := range workersets {
+		if _, err := machinecontroller.GetMachineDeployment(s, worker.Name); err != nil {
+			if dynclient.IgnoreNotFound(errors.Cause(err)) == nil {
+				drifts = append(drifts, Drift{Type: TypeMachineDeployment, Object: worker.Name, Reason: "MachineDeployment is missing"})
+				continue
+			}
+
+			return nil, err
+		}
+	}
+
+	return drifts, nil
+}
+
+// kubeadmClusterConfiguration holds the ClusterConfiguration fields
+// KubeOne sets based on the manifest. Those fields are the same in all
+// supported kubeadm API versions.
+type kubeadmClusterConfiguration struct {
+	KubernetesVersion    string `json:"kubernetesVersion"`
+	ControlPlaneEndpoint string `json:"controlPlaneEndpoint"`
+	Networking           struct {
+		PodSubnet     string `json:"podSubnet"`
+		ServiceSubnet string `json:"serviceSubnet"`
+		DNSDomain     string `json:"dnsDomain"`
+	} `json:"networking"`
+}
+
+func detectKubeadmConfig(s *state.State) ([]Drift, error) {
+	cm := corev1.ConfigMap{}
+	key := dynclient.ObjectKey{Name: "kubeadm-config", Namespace: metav1.NamespaceSystem}
+	if err := s.DynamicClient.Get(s.Context, key, &cm); err != nil {
+		if dynclient.IgnoreNotFound(err) == nil {
+			return []Drift{{Type: TypeKubeadmConfig, Object: key.String(), Reason: "ConfigMap is missing"}}, nil
+		}
+
+		return nil, errors.Wrap(err, "failed to get kubeadm-config ConfigMap")
+	}
+
+	config := kubeadmClusterConfiguration{}
+	if err := yaml.Unmarshal([]byte(cm.Data["ClusterConfiguration"]), &config); err != nil {
+		return nil, errors.Wrap(err, "failed to parse kubeadm ClusterConfiguration")
+	}
+
+	cluster := s.Cluster
+	fields := []struct {
+		name     string
+		expected string
+		actual   string
+	}{
+		{"kubernetesVersion", "v" + strings.TrimPrefix(cluster.Versions.Kubernetes, "v"), "v" + strings.TrimPrefix(config.KubernetesVersion, "v")},
+		{"controlPlaneEndpoint", fmt.Sprintf("%s:%d", cluster.APIEndpoint.Host, cluster.APIEndpoint.Port), config.ControlPlaneEndpoint},
+		{"networking.podSubnet", cluster.ClusterNetwork.PodSubnet, config.Networking.PodSubnet},
+		{"networking.serviceSubnet", cluster.ClusterNetwork.ServiceSubnet, config.Networking.ServiceSubnet},
+		{"networking.dnsDomain", cluster.ClusterNetwork.ServiceDomainName, config.Networking.DNSDomain},
+	}
+
+	var drifts []Drift
+	for _, f := range fields {
+		if f.expected != f.actual {
+			drifts = append(drifts, Drift{
+				Type:   TypeKubeadmConfig,
+				Object: key.String(),
+				Reason: fmt.Sprintf("%s is %q, expected %q", f.name, f.actual, f.expected),
+			})
+		}
+	}
+
+	return drifts, nil
+}
+
+// decodeObjects decodes all objects from the multi-document YAML manifest
+func decodeObjects(manifest string) ([]*metav1unstructured.Unstructured, error) {
+	var objs []*metav1unstructured.Unstructured
+
+	decoder := kyaml.NewYAMLOrJSONDecoder(bytes.NewBufferString(manifest), 4096)
+	for {
+		raw := runtime.RawExtension{}
+		if err := decoder.Decode(&raw); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+
+		raw.Raw = bytes.TrimSpace(raw.Raw)
+		if len(raw.Raw) == 0 || bytes.Equal(raw.Raw, []byte("null")) {
+			continue
+		}
+
+		obj := &metav1unstructured.Unstructured{}
+		if _, _, err := metav1unstructured.UnstructuredJSONScheme.Decode(raw.Raw, nil, obj); err != nil {
+			return nil, errors.Wrap(err, "failed to parse unstructured fields")
+		}
+		objs = append(objs, obj)
+	}
+
+	return objs, nil
+}
+
+func objectName(obj *metav1unstructured.Unstructured) string {
+	name := obj.GetName()
+	if obj.GetNamespace() != "" {
+		name = obj.GetNamespace() + "/" + name
+	}
+
+	return fmt.Sprintf("%s %s", obj.GetKind(), name)
+}
+
+// ignoredFields are top-level fields that are not compared, because they are
+// managed by the cluster or are write-only
+var ignoredFields = map[string]bool{
+	"status":     true,
+	"stringData": true,
+}
+
+// firstDifference returns the path of the first field of the desired object
+// that is not set to the same value in the live object. Fields set only in
+// the live object, such as defaulted fields, are not considered a drift.
+// An empty string is returned if the desired object is a subset of the live
+// object.
+func firstDifference(desired, live map[string]interface{}) string {
+	keys := []string{}
+	for k := range desired {
+		if !ignoredFields[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if path := valueDifference("."+k, desired[k], live[k]); path != "" {
+			return path
+		}
+	}
+
+	return ""
+}
+
+func valueDifference(path string, desired, live interface{}) string {
+	switch d := desired.(type) {
+	case nil:
+		return ""
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			if live == nil && len(d) == 0 {
+				return ""
+			}
+			return path
+		}
+
+		keys := []string{}
+		for k := range d {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			if p := valueDifference(path+"."+k, d[k], l[k]); p != "" {
+				return p
+			}
+		}
+
+		return ""
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok {
+			if live == nil && len(d) == 0 {
+				return ""
+			}
+			return path
+		}
+		if len(d) != len(l) {
+			return path
+		}
+
+		for i := range d {
+			if p := valueDifference(fmt.Sprintf("%s[%d]", path, i), d[i], l[i]); p != "" {
+				return p
+			}
+		}
+
+		return ""
+	case int64:
+		if f, ok := live.(float64); ok && float64(d) == f {
+			return ""
+		}
+	case float64:
+		if i, ok := live.(int64); ok && float64(i) == d {
+			return ""
+		}
+	}
+
+	if !reflect.DeepEqual(desired, live) {
+		return path
+	}
+
+	return ""
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drift
+
+import (
+	"testing"
+)
+
+func TestFirstDifference(t *testing.T) {
+	desired := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test
+  namespace: kube-system
+  labels:
+    app: test
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: test
+        image: test:v1
+        args: []
+`
+
+	tests := []struct {
+		name string
+		live string
+		want string
+	}{
+		{
+			name: "live object with defaulted fields",
+			live: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test
+  namespace: kube-system
+  uid: 1234
+  labels:
+    app: test
+    extra: label
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: test
+        image: test:v1
+        imagePullPolicy: IfNotPresent
+      restartPolicy: Always
+status:
+  replicas: 1
+`,
+			want: "",
+		},
+		{
+			name: "modified field",
+			live: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test
+  namespace: kube-system
+  labels:
+    app: test
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: test
+        image: test:v1
+`,
+			want: ".spec.replicas",
+		},
+		{
+			name: "modified list item",
+			live: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test
+  namespace: kube-system
+  labels:
+    app: test
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: test
+        image: test:v2
+`,
+			want: ".spec.template.spec.containers[0].image",
+		},
+		{
+			name: "removed label",
+			live: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test
+  namespace: kube-system
+spec:
+  replicas: 2
+`,
+			want: ".metadata.labels",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			desiredObjs, err := decodeObjects(desired)
+			if err != nil {
+				t.Fatalf("failed to decode desired object: %v", err)
+			}
+			liveObjs, err := decodeObjects(tt.live)
+			if err != nil {
+				t.Fatalf("failed to decode live object: %v", err)
+			}
+			if len(desiredObjs) != 1 || len(liveObjs) != 1 {
+				t.Fatalf("expected one desired and one live object, got %d and %d", len(desiredObjs), len(liveObjs))
+			}
+
+			got := firstDifference(desiredObjs[0].Object, liveObjs[0].Object)
+			if got != tt.want {
+				t.Errorf("firstDifference() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeObjects(t *testing.T) {
+	manifest := `
+# comment only
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+---
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second
+`
+
+	objs, err := decodeObjects(manifest)
+	if err != nil {
+		t.Fatalf("decodeObjects() error = %v", err)
+	}
+
+	if len(objs) != 2 {
+		t.Fatalf("expected 2 objects, got %d", len(objs))
+	}
+	if objs[0].GetName() != "first" || objs[1].GetName() != "second" {
+		t.Errorf("unexpected objects decoded: %q, %q", objs[0].GetName(), objs[1].GetName())
+	}
+}
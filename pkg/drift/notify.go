@@ -0,0 +1,64 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drift
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Notification is the JSON payload sent to the webhook when drift is detected
+type Notification struct {
+	Cluster    string    `json:"cluster"`
+	DetectedAt time.Time `json:"detectedAt"`
+	Drifts     []Drift   `json:"drifts"`
+}
+
+// Notify sends the detected drifts to the webhook URL as a JSON POST request
+func Notify(ctx context.Context, webhookURL string, clusterName string, drifts []Drift) error {
+	body, err := json.Marshal(Notification{
+		Cluster:    clusterName,
+		DetectedAt: time.Now().UTC(),
+		Drifts:     drifts,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal drift notification")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to build drift notification request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to send drift notification")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("failed to send drift notification: %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/pkg/errors"
+)
+
+// ageHeader is the first line of the files encrypted by age
+const ageHeader = "age-encryption.org/v1\n"
+
+type ageKey struct {
+	recipient *age.X25519Recipient
+	// identity is nil if only the recipient is provided
+	identity *age.X25519Identity
+}
+
+func newAgeKey(value string) (*ageKey, error) {
+	if strings.HasPrefix(value, "age1") {
+		recipient, err := age.ParseX25519Recipient(value)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse the age recipient")
+		}
+
+		return &ageKey{recipient: recipient}, nil
+	}
+
+	f, err := os.Open(value)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open the age identity file")
+	}
+	defer f.Close()
+
+	identities, err := age.ParseIdentities(f)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the age identity file %q", value)
+	}
+
+	identity, ok := identities[0].(*age.X25519Identity)
+	if !ok {
+		return nil, errors.Errorf("unsupported age identity in %q", value)
+	}
+
+	return &ageKey{recipient: identity.Recipient(), identity: identity}, nil
+}
+
+func (k *ageKey) Encrypt(_ context.Context, plaintext []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	w, err := age.Encrypt(&buf, k.recipient)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt using age")
+	}
+
+	if _, err = w.Write(plaintext); err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt using age")
+	}
+
+	if err = w.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt using age")
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (k *ageKey) Decrypt(_ context.Context, ciphertext []byte) ([]byte, error) {
+	if k.identity == nil {
+		return nil, errors.New("the artifact can't be decrypted using the age recipient, the age identity file is required")
+	}
+
+	if !bytes.HasPrefix(ciphertext, []byte(ageHeader)) {
+		return nil, errors.New("the artifact is not encrypted using age")
+	}
+
+	r, err := age.Decrypt(bytes.NewReader(ciphertext), k.identity)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt using age")
+	}
+
+	plaintext, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt using age")
+	}
+
+	return plaintext, nil
+}
+
+func (k *ageKey) String() string {
+	return "age recipient " + k.recipient.String()
+}
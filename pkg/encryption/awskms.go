@@ -0,0 +1,141 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/pkg/errors"
+)
+
+// awsKMSHeader is the first line of the artifacts encrypted using AWS KMS.
+// It's followed by the length of the encrypted data key as a 32-bit big
+// endian integer, the encrypted data key, the nonce and the AES-GCM sealed
+// artifact.
+const awsKMSHeader = "kubeone-awskms/v1\n"
+
+type awsKMSKey struct {
+	client kmsiface.KMSAPI
+	keyID  string
+}
+
+func newAWSKMSKey(keyID string) (*awsKMSKey, error) {
+	if keyID == "" {
+		return nil, errors.New("AWS KMS key is required")
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create aws session")
+	}
+
+	return &awsKMSKey{client: kms.New(sess), keyID: keyID}, nil
+}
+
+func (k *awsKMSKey) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	dataKey, err := k.client.GenerateDataKeyWithContext(ctx, &kms.GenerateDataKeyInput{
+		KeyId:   aws.String(k.keyID),
+		KeySpec: aws.String(kms.DataKeySpecAes256),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to generate the data key using the AWS KMS key %s", k.keyID)
+	}
+
+	gcm, err := newGCM(dataKey.Plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Wrap(err, "failed to generate the nonce")
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(awsKMSHeader)
+	_ = binary.Write(&buf, binary.BigEndian, uint32(len(dataKey.CiphertextBlob)))
+	buf.Write(dataKey.CiphertextBlob)
+	buf.Write(nonce)
+	buf.Write(gcm.Seal(nil, nonce, plaintext, []byte(awsKMSHeader)))
+
+	return buf.Bytes(), nil
+}
+
+func (k *awsKMSKey) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	if !bytes.HasPrefix(ciphertext, []byte(awsKMSHeader)) {
+		return nil, errors.New("the artifact is not encrypted using AWS KMS")
+	}
+
+	r := bytes.NewReader(ciphertext[len(awsKMSHeader):])
+
+	var keyLen uint32
+	if err := binary.Read(r, binary.BigEndian, &keyLen); err != nil || int(keyLen) > r.Len() {
+		return nil, errors.New("the artifact encrypted using AWS KMS is corrupted")
+	}
+	encryptedKey := make([]byte, keyLen)
+	_, _ = r.Read(encryptedKey)
+
+	// The encrypted data key contains the ID of the KMS key
+	dataKey, err := k.client.DecryptWithContext(ctx, &kms.DecryptInput{
+		CiphertextBlob: encryptedKey,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt the data key using AWS KMS")
+	}
+
+	gcm, err := newGCM(dataKey.Plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed := ciphertext[len(ciphertext)-r.Len():]
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("the artifact encrypted using AWS KMS is corrupted")
+	}
+
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], []byte(awsKMSHeader))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt the artifact")
+	}
+
+	return plaintext, nil
+}
+
+func (k *awsKMSKey) String() string {
+	return "AWS KMS key " + k.keyID
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid data key")
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package encryption encrypts the sensitive artifacts written by KubeOne, such
+// as the kubeconfig and the PKI backups, before they're saved to the local disk
+// and to the state store, and decrypts them when they're read back.
+//
+// Supported keys:
+//   - age:<recipient> - the age public key (age1...). The artifacts can be
+//     encrypted, but not decrypted, which is enough for the CI runners.
+//   - age:<path> - the file with the age identity (AGE-SECRET-KEY-1...), as
+//     generated by age-keygen. The artifacts are encrypted to the recipient of
+//     the identity and can be decrypted.
+//   - awskms:<key> - the ID, ARN or alias of the symmetric AWS KMS key. Each
+//     artifact is encrypted with its own AES-256-GCM data key generated by AWS
+//     KMS. The AWS credentials are taken from the environment.
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	prefixAge    = "age:"
+	prefixAWSKMS = "awskms:"
+)
+
+// Key encrypts and decrypts the artifacts
+type Key interface {
+	// Encrypt returns the encrypted artifact
+	Encrypt(ctx context.Context, plaintext []byte) ([]byte, error)
+	// Decrypt returns the decrypted artifact, encrypted by Encrypt
+	Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error)
+	// String returns the description of the key
+	String() string
+}
+
+// New returns the key for the given value, see the package documentation for
+// the supported keys
+func New(value string) (Key, error) {
+	switch {
+	case strings.HasPrefix(value, prefixAge):
+		return newAgeKey(strings.TrimPrefix(value, prefixAge))
+	case strings.HasPrefix(value, prefixAWSKMS):
+		return newAWSKMSKey(strings.TrimPrefix(value, prefixAWSKMS))
+	}
+
+	return nil, errors.Errorf("invalid encryption key %q, expected age:<recipient>, age:<identity file> or awskms:<key>", value)
+}
+
+// IsEncrypted returns true if the artifact is encrypted by any of the keys
+func IsEncrypted(buf []byte) bool {
+	return bytes.HasPrefix(buf, []byte(ageHeader)) || bytes.HasPrefix(buf, []byte(awsKMSHeader))
+}
+
+// Decrypt returns the artifact decrypted by the key. Plaintext artifacts are
+// returned as-is, so the artifacts written before the encryption was enabled
+// can still be read.
+func Decrypt(ctx context.Context, key Key, buf []byte) ([]byte, error) {
+	if !IsEncrypted(buf) {
+		return buf, nil
+	}
+
+	if key == nil {
+		return nil, errors.New("the artifact is encrypted, but the encryption key is not provided")
+	}
+
+	return key.Decrypt(ctx, buf)
+}
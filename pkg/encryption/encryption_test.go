@@ -0,0 +1,180 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/pkg/errors"
+)
+
+// fakeKMS "encrypts" the data keys by prefixing them with the key ID
+type fakeKMS struct {
+	kmsiface.KMSAPI
+	keyID string
+}
+
+func (f *fakeKMS) GenerateDataKeyWithContext(_ aws.Context, in *kms.GenerateDataKeyInput, _ ...request.Option) (*kms.GenerateDataKeyOutput, error) {
+	dataKey := bytes.Repeat([]byte{0x42}, 32)
+
+	return &kms.GenerateDataKeyOutput{
+		KeyId:          in.KeyId,
+		Plaintext:      dataKey,
+		CiphertextBlob: append([]byte(aws.StringValue(in.KeyId)), dataKey...),
+	}, nil
+}
+
+func (f *fakeKMS) DecryptWithContext(_ aws.Context, in *kms.DecryptInput, _ ...request.Option) (*kms.DecryptOutput, error) {
+	if !bytes.HasPrefix(in.CiphertextBlob, []byte(f.keyID)) {
+		return nil, errors.New("invalid ciphertext")
+	}
+
+	return &kms.DecryptOutput{Plaintext: in.CiphertextBlob[len(f.keyID):]}, nil
+}
+
+func TestKeys(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	identityFile := filepath.Join(t.TempDir(), "key.txt")
+	if err = ioutil.WriteFile(identityFile, []byte("# created: 2021-01-01T00:00:00Z\n"+identity.String()+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	identityKey, err := New("age:" + identityFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recipientKey, err := New("age:" + identity.Recipient().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kmsKey := &awsKMSKey{client: &fakeKMS{keyID: "alias/kubeone"}, keyID: "alias/kubeone"}
+
+	tests := []struct {
+		name            string
+		encryptKey      Key
+		decryptKey      Key
+		expectedDecrypt bool
+	}{
+		{
+			name:            "age identity",
+			encryptKey:      identityKey,
+			decryptKey:      identityKey,
+			expectedDecrypt: true,
+		},
+		{
+			name:            "age recipient decrypted by the identity",
+			encryptKey:      recipientKey,
+			decryptKey:      identityKey,
+			expectedDecrypt: true,
+		},
+		{
+			name:            "age recipient can't decrypt",
+			encryptKey:      recipientKey,
+			decryptKey:      recipientKey,
+			expectedDecrypt: false,
+		},
+		{
+			name:            "aws kms",
+			encryptKey:      kmsKey,
+			decryptKey:      kmsKey,
+			expectedDecrypt: true,
+		},
+		{
+			name:            "aws kms artifact decrypted by age",
+			encryptKey:      kmsKey,
+			decryptKey:      identityKey,
+			expectedDecrypt: false,
+		},
+		{
+			name:            "aws kms with another key",
+			encryptKey:      kmsKey,
+			decryptKey:      &awsKMSKey{client: &fakeKMS{keyID: "alias/other"}, keyID: "alias/other"},
+			expectedDecrypt: false,
+		},
+	}
+
+	plaintext := []byte("apiVersion: v1\nkind: Config\n")
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			ciphertext, err := tt.encryptKey.Encrypt(ctx, plaintext)
+			if err != nil {
+				t.Fatalf("failed to encrypt: %v", err)
+			}
+
+			if !IsEncrypted(ciphertext) {
+				t.Fatal("the artifact is not recognized as encrypted")
+			}
+
+			if bytes.Contains(ciphertext, plaintext) {
+				t.Fatal("the encrypted artifact contains the plaintext")
+			}
+
+			got, err := Decrypt(ctx, tt.decryptKey, ciphertext)
+			if (err == nil) != tt.expectedDecrypt {
+				t.Fatalf("expected decrypt %t, but got error %v", tt.expectedDecrypt, err)
+			}
+
+			if tt.expectedDecrypt && !bytes.Equal(got, plaintext) {
+				t.Errorf("expected %q, but got %q", plaintext, got)
+			}
+		})
+	}
+}
+
+func TestDecryptPlaintext(t *testing.T) {
+	plaintext := []byte("apiVersion: v1\nkind: Config\n")
+
+	got, err := Decrypt(context.Background(), nil, plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("expected the plaintext artifact to be returned as-is, but got %q", got)
+	}
+
+	if _, err = Decrypt(context.Background(), nil, []byte(awsKMSHeader+"data")); err == nil {
+		t.Error("expected an error decrypting the encrypted artifact without the key")
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, value := range []string{"", "age1", "age:age1invalid", "age:/nonexistent", "awskms:", "gpg:key"} {
+		if _, err := New(value); err == nil {
+			t.Errorf("expected an error for the key %q", value)
+		}
+	}
+}
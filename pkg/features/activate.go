@@ -50,6 +50,22 @@ func Activate(s *state.State) error {
 		return errors.Wrap(err, "failed to install podNodeSelector")
 	}
 
+	if err := installNvidiaDevicePlugin(s.Cluster.Features.NvidiaGPU, s); err != nil {
+		return errors.Wrap(err, "failed to install NVIDIA device plugin")
+	}
+
+	if err := installMonitoring(s.Cluster.Features.Monitoring, s); err != nil {
+		return errors.Wrap(err, "failed to install monitoring")
+	}
+
+	if err := installClusterAutoscaler(s.Cluster.Features.ClusterAutoscaler, s); err != nil {
+		return errors.Wrap(err, "failed to install cluster-autoscaler")
+	}
+
+	if err := installIngressController(s.Cluster.Features.IngressController, s); err != nil {
+		return errors.Wrap(err, "failed to install ingress-nginx")
+	}
+
 	return nil
 }
 
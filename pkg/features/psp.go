@@ -59,7 +59,7 @@ func installKubeSystemPSP(psp *kubeoneapi.PodSecurityPolicy, s *state.State) err
 	}
 
 	for _, obj := range k8sobjects {
-		if err := clientutil.CreateOrUpdate(ctx, s.DynamicClient, obj); err != nil {
+		if err := clientutil.CreateOrUpdate(ctx, s.DynamicClient, obj, clientutil.WithManifestPatches(s.Cluster.ManifestPatches)); err != nil {
 			return errors.Wrap(err, "failed to ensure PodSecurityPolicy role binding")
 		}
 	}
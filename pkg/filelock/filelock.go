@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filelock
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// Lock is an exclusive advisory lock, shared by all KubeOne processes of the
+// same user. It's used to protect files shared by parallel 'kubeone' runs, such
+// as downloaded Helm charts and files saved by KubeOne.
+type Lock struct {
+	file *os.File
+}
+
+// lockDir returns the directory holding the lock files. The directory is per
+// user, as the lock files of other users can't be opened.
+func lockDir() string {
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "kubeone", "locks")
+	}
+
+	return filepath.Join(os.TempDir(), fmt.Sprintf("kubeone-locks-%d", os.Getuid()))
+}
+
+// Acquire blocks until the exclusive lock for the given path is acquired.
+// The path doesn't have to exist. Lock files are kept in the user cache
+// directory, so no files are created next to the protected path.
+func Acquire(path string) (*Lock, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get absolute path of %q", path)
+	}
+
+	if err = os.MkdirAll(lockDir(), 0700); err != nil {
+		return nil, errors.Wrap(err, "failed to create locks directory")
+	}
+
+	sum := sha256.Sum256([]byte(absPath))
+	lockPath := filepath.Join(lockDir(), hex.EncodeToString(sum[:])+".lock")
+
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open lock file for %q", path)
+	}
+
+	if err = lockFile(f); err != nil {
+		f.Close()
+		return nil, errors.Wrapf(err, "failed to lock %q", path)
+	}
+
+	return &Lock{file: f}, nil
+}
+
+// Release releases the lock
+func (l *Lock) Release() error {
+	if err := unlockFile(l.file); err != nil {
+		l.file.Close()
+		return errors.Wrap(err, "failed to unlock")
+	}
+
+	return l.file.Close()
+}
+
+// WithLock runs fn while holding the lock for the given path
+func WithLock(path string, fn func() error) error {
+	lock, err := Acquire(path)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	return fn()
+}
+
+// WriteFile writes data to the file, the same way as ioutil.WriteFile, but
+// atomically and while holding the lock for the file. Other processes see
+// either the old or the new content, but never a partially written file.
+func WriteFile(filename string, data []byte, perm os.FileMode) error {
+	return WriteFileWith(filename, perm, func(tmpName string) error {
+		return ioutil.WriteFile(tmpName, data, perm)
+	})
+}
+
+// WriteFileWith calls write to write the temporary file, and renames the
+// temporary file to the filename while holding the lock for the file
+func WriteFileWith(filename string, perm os.FileMode, write func(tmpName string) error) error {
+	return WithLock(filename, func() error {
+		tmp, err := ioutil.TempFile(filepath.Dir(filename), "."+filepath.Base(filename)+".tmp-")
+		if err != nil {
+			return errors.Wrapf(err, "failed to create temporary file for %q", filename)
+		}
+		tmpName := tmp.Name()
+		tmp.Close()
+		defer os.Remove(tmpName)
+
+		if err = write(tmpName); err != nil {
+			return err
+		}
+
+		if err = os.Chmod(tmpName, perm); err != nil {
+			return errors.Wrapf(err, "failed to set permissions of %q", filename)
+		}
+
+		return errors.Wrapf(os.Rename(tmpName, filename), "failed to write %q", filename)
+	})
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filelock
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAcquireIsExclusive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache")
+
+	lock, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		second, err := Acquire(path)
+		if err != nil {
+			t.Errorf("second Acquire() error = %v", err)
+			close(acquired)
+			return
+		}
+		close(acquired)
+		_ = second.Release()
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("lock acquired while it's held by another holder")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if err = lock.Release(); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+
+	select {
+	case <-acquired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("lock not acquired after it was released")
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "test-kubeconfig")
+
+	wg := sync.WaitGroup{}
+	contents := []string{"first", "second", "third", "fourth"}
+	for _, content := range contents {
+		wg.Add(1)
+		go func(content string) {
+			defer wg.Done()
+			if err := WriteFile(filename, []byte(content), 0600); err != nil {
+				t.Errorf("WriteFile() error = %v", err)
+			}
+		}(content)
+	}
+	wg.Wait()
+
+	got, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+
+	found := false
+	for _, content := range contents {
+		if string(got) == content {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("unexpected file content %q", got)
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		t.Fatalf("failed to stat file: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("unexpected file permissions %v", info.Mode().Perm())
+	}
+
+	// No temporary files are left behind
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read directory: %v", err)
+	}
+	if len(files) != 1 {
+		t.Errorf("expected only the written file in the directory, found %d files", len(files))
+	}
+}
+
+func TestLockDirIsPerUser(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("XDG_CACHE_HOME is used only on Linux")
+	}
+
+	cacheDir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheDir)
+
+	lock, err := Acquire(filepath.Join(t.TempDir(), "cache"))
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	defer lock.Release()
+
+	if dir := filepath.Dir(lock.file.Name()); dir != filepath.Join(cacheDir, "kubeone", "locks") {
+		t.Errorf("lock file created in %q, expected the user cache directory %q", dir, cacheDir)
+	}
+
+	info, err := os.Stat(lockDir())
+	if err != nil {
+		t.Fatalf("failed to stat locks directory: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0700 {
+		t.Errorf("locks directory permissions = %v, want 0700", perm)
+	}
+}
@@ -0,0 +1,274 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fleet implements managing multiple clusters at the same time, defined
+// either by the fleet file referencing the manifests of the clusters, or by a
+// manifest containing multiple KubeOneCluster documents.
+package fleet
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/apis/kubeone/config"
+	"k8c.io/kubeone/pkg/secretstore"
+
+	"sigs.k8s.io/yaml"
+)
+
+// File is the fleet file, referencing the manifests of the clusters
+type File struct {
+	// Clusters are the clusters in the fleet
+	Clusters []Cluster `json:"clusters"`
+}
+
+// Cluster references the manifest of a cluster in the fleet file. Relative
+// paths are relative to the fleet file.
+type Cluster struct {
+	// Name of the cluster, used in the logs and in the report. Defaults to
+	// the name from the manifest.
+	Name string `json:"name,omitempty"`
+	// Manifest is the path to the KubeOneCluster manifest. The manifest can
+	// contain multiple KubeOneCluster documents, if the Terraform output is
+	// not used.
+	Manifest string `json:"manifest"`
+	// TerraformState is the path to the Terraform output, the same as the
+	// --tfjson flag
+	TerraformState string `json:"tfjson,omitempty"`
+	// TerraformBackend is the path to the Terraform backend config, the same
+	// as the --tfstate-backend flag
+	TerraformBackend string `json:"tfstateBackend,omitempty"`
+	// Credentials is the path to the credentials file, the same as the
+	// --credentials flag. Defaults to the credentials file given to the
+	// command.
+	Credentials string `json:"credentials,omitempty"`
+}
+
+// Member is a single cluster of the fleet
+type Member struct {
+	// Name of the cluster
+	Name string
+	// ManifestFile is the path to the KubeOneCluster manifest
+	ManifestFile string
+	// Document is the index of the document in the multi-document manifest,
+	// nil if the manifest is a single document
+	Document *int
+	// TerraformState is the path to the Terraform output
+	TerraformState string
+	// TerraformBackend is the path to the Terraform backend config
+	TerraformBackend string
+	// CredentialsFile is the path to the credentials file
+	CredentialsFile string
+}
+
+// Result is the result of running the operation on a fleet member
+type Result struct {
+	Member   Member
+	Err      error
+	Duration time.Duration
+}
+
+// Load reads the fleet file and returns the fleet members. Manifests with
+// multiple KubeOneCluster documents result in a member per document.
+func Load(path, credentialsFile string) ([]Member, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read the fleet file")
+	}
+
+	file := File{}
+	if err = yaml.UnmarshalStrict(buf, &file); err != nil {
+		return nil, errors.Wrap(err, "unable to parse the fleet file")
+	}
+
+	baseDir := filepath.Dir(path)
+	members := []Member{}
+	for i, cluster := range file.Clusters {
+		if cluster.Manifest == "" {
+			return nil, errors.Errorf("manifest of the cluster %d in the fleet file is required", i+1)
+		}
+
+		credentials := credentialsFile
+		if cluster.Credentials != "" {
+			credentials = relativeTo(baseDir, cluster.Credentials)
+		}
+
+		clusterMembers, err := membersFromManifest(relativeTo(baseDir, cluster.Manifest), credentials)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(clusterMembers) > 1 && (cluster.TerraformState != "" || cluster.TerraformBackend != "") {
+			return nil, errors.Errorf("manifest %q contains multiple clusters, tfjson and tfstateBackend can't be used with multi-cluster manifests", cluster.Manifest)
+		}
+
+		for j := range clusterMembers {
+			if cluster.Name != "" {
+				clusterMembers[j].Name = cluster.Name
+				if len(clusterMembers) > 1 {
+					clusterMembers[j].Name = fmt.Sprintf("%s-%d", cluster.Name, j+1)
+				}
+			}
+			if cluster.TerraformState != "" {
+				clusterMembers[j].TerraformState = relativeTo(baseDir, cluster.TerraformState)
+			}
+			if cluster.TerraformBackend != "" {
+				clusterMembers[j].TerraformBackend = relativeTo(baseDir, cluster.TerraformBackend)
+			}
+		}
+
+		members = append(members, clusterMembers...)
+	}
+
+	return members, validateMembers(members)
+}
+
+// FromManifest returns the fleet members for the manifest containing one or
+// more KubeOneCluster documents
+func FromManifest(path, credentialsFile string) ([]Member, error) {
+	members, err := membersFromManifest(path, credentialsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return members, validateMembers(members)
+}
+
+// Run runs the function for each of the fleet members, with at most
+// parallelism members at the same time. Failures don't stop running the
+// function for the remaining members. The results are in the same order as
+// the members.
+func Run(members []Member, parallelism int, fn func(Member) error) []Result {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	results := make([]Result, len(members))
+	sem := make(chan struct{}, parallelism)
+	wg := sync.WaitGroup{}
+
+	for i := range members {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			start := time.Now()
+			err := fn(members[i])
+			results[i] = Result{
+				Member:   members[i],
+				Err:      err,
+				Duration: time.Since(start),
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// Failed returns the number of the failed results
+func Failed(results []Result) int {
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+
+	return failed
+}
+
+func membersFromManifest(path, credentialsFile string) ([]Member, error) {
+	manifest, err := secretstore.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read the manifest %q", path)
+	}
+
+	documents, err := config.SplitManifest(manifest)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse the manifest %q", path)
+	}
+	if len(documents) == 0 {
+		return nil, errors.Errorf("manifest %q doesn't contain any KubeOneCluster document", path)
+	}
+
+	members := []Member{}
+	for i, document := range documents {
+		member := Member{
+			Name:            documentName(document),
+			ManifestFile:    path,
+			CredentialsFile: credentialsFile,
+		}
+		if member.Name == "" {
+			member.Name = fmt.Sprintf("%s#%d", path, i+1)
+		}
+		if len(documents) > 1 {
+			i := i
+			member.Document = &i
+		}
+
+		members = append(members, member)
+	}
+
+	return members, nil
+}
+
+// documentName returns the cluster name from the manifest document, if set
+func documentName(document []byte) string {
+	meta := struct {
+		Name string `json:"name"`
+	}{}
+	if err := yaml.Unmarshal(document, &meta); err != nil {
+		return ""
+	}
+
+	return meta.Name
+}
+
+func validateMembers(members []Member) error {
+	if len(members) == 0 {
+		return errors.New("the fleet doesn't contain any cluster")
+	}
+
+	names := map[string]bool{}
+	for _, member := range members {
+		if names[member.Name] {
+			return errors.Errorf("cluster name %q is used multiple times in the fleet", member.Name)
+		}
+		names[member.Name] = true
+	}
+
+	return nil
+}
+
+func relativeTo(baseDir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(baseDir, path)
+}
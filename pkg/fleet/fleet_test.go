@@ -0,0 +1,241 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleet
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+const (
+	singleClusterManifest = `
+apiVersion: kubeone.io/v1beta1
+kind: KubeOneCluster
+name: edge-1
+`
+	multiClusterManifest = `
+apiVersion: kubeone.io/v1beta1
+kind: KubeOneCluster
+name: edge-2
+---
+# comments only
+---
+apiVersion: kubeone.io/v1beta1
+kind: KubeOneCluster
+name: edge-3
+`
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"single.yaml": singleClusterManifest,
+		"multi.yaml":  multiClusterManifest,
+	})
+
+	zero, one := 0, 1
+
+	tests := []struct {
+		name          string
+		fleetFile     string
+		expected      []Member
+		expectedError bool
+	}{
+		{
+			name: "single and multi-cluster manifests",
+			fleetFile: `
+clusters:
+- manifest: single.yaml
+  tfjson: tf.json
+  credentials: /credentials.yaml
+- manifest: multi.yaml
+`,
+			expected: []Member{
+				{
+					Name:            "edge-1",
+					ManifestFile:    filepath.Join(dir, "single.yaml"),
+					TerraformState:  filepath.Join(dir, "tf.json"),
+					CredentialsFile: "/credentials.yaml",
+				},
+				{
+					Name:            "edge-2",
+					ManifestFile:    filepath.Join(dir, "multi.yaml"),
+					Document:        &zero,
+					CredentialsFile: "global-credentials.yaml",
+				},
+				{
+					Name:            "edge-3",
+					ManifestFile:    filepath.Join(dir, "multi.yaml"),
+					Document:        &one,
+					CredentialsFile: "global-credentials.yaml",
+				},
+			},
+		},
+		{
+			name: "names from the fleet file",
+			fleetFile: `
+clusters:
+- name: first
+  manifest: single.yaml
+- name: rest
+  manifest: multi.yaml
+`,
+			expected: []Member{
+				{
+					Name:            "first",
+					ManifestFile:    filepath.Join(dir, "single.yaml"),
+					CredentialsFile: "global-credentials.yaml",
+				},
+				{
+					Name:            "rest-1",
+					ManifestFile:    filepath.Join(dir, "multi.yaml"),
+					Document:        &zero,
+					CredentialsFile: "global-credentials.yaml",
+				},
+				{
+					Name:            "rest-2",
+					ManifestFile:    filepath.Join(dir, "multi.yaml"),
+					Document:        &one,
+					CredentialsFile: "global-credentials.yaml",
+				},
+			},
+		},
+		{
+			name: "terraform output with multi-cluster manifest",
+			fleetFile: `
+clusters:
+- manifest: multi.yaml
+  tfjson: tf.json
+`,
+			expectedError: true,
+		},
+		{
+			name: "duplicate names",
+			fleetFile: `
+clusters:
+- manifest: single.yaml
+- manifest: single.yaml
+`,
+			expectedError: true,
+		},
+		{
+			name: "missing manifest",
+			fleetFile: `
+clusters:
+- name: edge-1
+`,
+			expectedError: true,
+		},
+		{
+			name: "unknown field",
+			fleetFile: `
+clusters:
+- manifests: single.yaml
+`,
+			expectedError: true,
+		},
+		{
+			name:          "no clusters",
+			fleetFile:     "clusters: []",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			fleetPath := filepath.Join(dir, "fleet.yaml")
+			writeFiles(t, dir, map[string]string{"fleet.yaml": tc.fleetFile})
+
+			got, err := Load(fleetPath, "global-credentials.yaml")
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("Load() error = %v, expectedError %v", err, tc.expectedError)
+			}
+			if tc.expectedError {
+				return
+			}
+
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("Load() = %+v, expected %+v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	members := []Member{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}, {Name: "e"}}
+	failure := errors.New("failure")
+
+	var (
+		lock        sync.Mutex
+		running     int
+		maxParallel int
+	)
+
+	results := Run(members, 2, func(member Member) error {
+		lock.Lock()
+		running++
+		if running > maxParallel {
+			maxParallel = running
+		}
+		lock.Unlock()
+
+		defer func() {
+			lock.Lock()
+			running--
+			lock.Unlock()
+		}()
+
+		if member.Name == "c" {
+			return failure
+		}
+
+		return nil
+	})
+
+	if maxParallel > 2 {
+		t.Errorf("Run() ran %d members at the same time, expected at most 2", maxParallel)
+	}
+	if len(results) != len(members) {
+		t.Fatalf("Run() returned %d results, expected %d", len(results), len(members))
+	}
+	for i, result := range results {
+		if result.Member.Name != members[i].Name {
+			t.Errorf("Run() result %d is for %q, expected %q", i, result.Member.Name, members[i].Name)
+		}
+	}
+	if !errors.Is(results[2].Err, failure) {
+		t.Errorf("Run() result of c = %v, expected %v", results[2].Err, failure)
+	}
+	if failed := Failed(results); failed != 1 {
+		t.Errorf("Failed() = %d, expected 1", failed)
+	}
+}
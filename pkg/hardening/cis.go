@@ -0,0 +1,317 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hardening applies and verifies the CIS hardening profile. The
+// profile follows the CIS Kubernetes Benchmark v1.6.0. Recommendations which
+// break kubeadm clusters, such as disabling the anonymous authentication of
+// the API server used by its liveness probes, are not applied.
+package hardening
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"k8c.io/kubeone/pkg/scripts"
+
+	kubeletconfigv1beta1 "k8s.io/kubelet/config/v1beta1"
+)
+
+const (
+	componentAPIServer         = "kube-apiserver"
+	componentControllerManager = "kube-controller-manager"
+	componentScheduler         = "kube-scheduler"
+)
+
+// TLSCipherSuites are the cipher suites allowed for the API server and the
+// kubelet (CIS 1.2.35 and 4.2.13)
+var TLSCipherSuites = []string{
+	"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+	"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+	"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+	"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+	"TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305",
+	"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305",
+}
+
+// KernelSysctls are the kernel settings kubelet expects when
+// protectKernelDefaults is enabled (CIS 4.2.6)
+var KernelSysctls = map[string]string{
+	"kernel.keys.root_maxbytes": "25000000",
+	"kernel.keys.root_maxkeys":  "1000000",
+	"kernel.panic":              "10",
+	"kernel.panic_on_oops":      "1",
+	"vm.overcommit_memory":      "1",
+	"vm.panic_on_oom":           "0",
+}
+
+// AuditWatches are the files and directories of Kubernetes and the container
+// runtimes watched by auditd. Paths which don't exist on the host are skipped.
+var AuditWatches = []string{
+	"/etc/kubernetes",
+	"/var/lib/etcd",
+	"/var/lib/kubelet/config.yaml",
+	"/etc/systemd/system/kubelet.service.d",
+	"/etc/containerd",
+	"/etc/docker",
+	"/usr/bin/containerd",
+	"/usr/bin/dockerd",
+	"/usr/bin/runc",
+	"/usr/bin/kubelet",
+	"/opt/bin/kubelet",
+}
+
+// APIServerArgs returns the API server flags (CIS 1.2.18 and 1.2.35)
+func APIServerArgs() map[string]string {
+	return map[string]string{
+		"profiling":         "false",
+		"tls-cipher-suites": strings.Join(TLSCipherSuites, ","),
+	}
+}
+
+// ControllerManagerArgs returns the controller-manager flags (CIS 1.3.1 and
+// 1.3.2). The garbage collection threshold of terminated pods is set
+// explicitly to the upstream default.
+func ControllerManagerArgs() map[string]string {
+	return map[string]string{
+		"profiling":                   "false",
+		"terminated-pod-gc-threshold": "12500",
+	}
+}
+
+// SchedulerArgs returns the scheduler flags (CIS 1.4.1)
+func SchedulerArgs() map[string]string {
+	return map[string]string{
+		"profiling": "false",
+	}
+}
+
+// ApplyKubelet applies the CIS settings to the kubelet configuration
+// (CIS 4.2.6 and 4.2.13). Anonymous authentication, webhook authorization,
+// the read-only port and certificates rotation are configured by KubeOne
+// regardless of the profile.
+func ApplyKubelet(cfg *kubeletconfigv1beta1.KubeletConfiguration) {
+	cfg.ProtectKernelDefaults = true
+	cfg.TLSCipherSuites = TLSCipherSuites
+}
+
+// File is a file, or a glob of files, which must be owned by root and have
+// at most Mode permissions. Zero Mode checks only the ownership.
+type File struct {
+	PermissionsID string
+	OwnershipID   string
+	Description   string
+	Paths         []string
+	Mode          os.FileMode
+	ControlPlane  bool
+}
+
+// Files are the files checked by the CIS profile
+var Files = []File{
+	{PermissionsID: "1.1.1", OwnershipID: "1.1.2", Description: "API server pod specification file", Paths: []string{"/etc/kubernetes/manifests/kube-apiserver.yaml"}, Mode: 0600, ControlPlane: true},
+	{PermissionsID: "1.1.3", OwnershipID: "1.1.4", Description: "controller manager pod specification file", Paths: []string{"/etc/kubernetes/manifests/kube-controller-manager.yaml"}, Mode: 0600, ControlPlane: true},
+	{PermissionsID: "1.1.5", OwnershipID: "1.1.6", Description: "scheduler pod specification file", Paths: []string{"/etc/kubernetes/manifests/kube-scheduler.yaml"}, Mode: 0600, ControlPlane: true},
+	{PermissionsID: "1.1.7", OwnershipID: "1.1.8", Description: "etcd pod specification file", Paths: []string{"/etc/kubernetes/manifests/etcd.yaml"}, Mode: 0600, ControlPlane: true},
+	{PermissionsID: "1.1.11", Description: "etcd data directory", Paths: []string{"/var/lib/etcd"}, Mode: 0700, ControlPlane: true},
+	{PermissionsID: "1.1.13", OwnershipID: "1.1.14", Description: "admin.conf file", Paths: []string{"/etc/kubernetes/admin.conf"}, Mode: 0600, ControlPlane: true},
+	{PermissionsID: "1.1.15", OwnershipID: "1.1.16", Description: "scheduler.conf file", Paths: []string{"/etc/kubernetes/scheduler.conf"}, Mode: 0600, ControlPlane: true},
+	{PermissionsID: "1.1.17", OwnershipID: "1.1.18", Description: "controller-manager.conf file", Paths: []string{"/etc/kubernetes/controller-manager.conf"}, Mode: 0600, ControlPlane: true},
+	{OwnershipID: "1.1.19", Description: "Kubernetes PKI directory", Paths: []string{"/etc/kubernetes/pki"}, ControlPlane: true},
+	{PermissionsID: "1.1.20", Description: "Kubernetes PKI certificate files", Paths: []string{"/etc/kubernetes/pki/*.crt", "/etc/kubernetes/pki/etcd/*.crt"}, Mode: 0644, ControlPlane: true},
+	{PermissionsID: "1.1.21", Description: "Kubernetes PKI key files", Paths: []string{"/etc/kubernetes/pki/*.key", "/etc/kubernetes/pki/etcd/*.key"}, Mode: 0600, ControlPlane: true},
+	{PermissionsID: "4.1.1", OwnershipID: "4.1.2", Description: "kubelet service file", Paths: []string{"/etc/systemd/system/kubelet.service.d/10-kubeadm.conf", "/usr/lib/systemd/system/kubelet.service.d/10-kubeadm.conf"}, Mode: 0644},
+	{PermissionsID: "4.1.5", OwnershipID: "4.1.6", Description: "kubelet.conf file", Paths: []string{"/etc/kubernetes/kubelet.conf"}, Mode: 0600},
+	{PermissionsID: "4.1.7", OwnershipID: "4.1.8", Description: "certificate authorities file", Paths: []string{"/etc/kubernetes/pki/ca.crt"}, Mode: 0644},
+	{PermissionsID: "4.1.9", OwnershipID: "4.1.10", Description: "kubelet config.yaml file", Paths: []string{"/var/lib/kubelet/config.yaml"}, Mode: 0600},
+}
+
+// FilePermissions returns the files of the host which permissions and
+// ownership are restricted by the CIS profile
+func FilePermissions(controlPlane bool) []scripts.FilePermission {
+	perms := []scripts.FilePermission{}
+	for _, f := range Files {
+		if f.ControlPlane && !controlPlane {
+			continue
+		}
+		perms = append(perms, scripts.FilePermission{Paths: f.Paths, Mode: f.Mode})
+	}
+
+	return perms
+}
+
+// Control is a single recommendation of the CIS Kubernetes Benchmark
+type Control struct {
+	ID          string
+	Description string
+	// ControlPlane is true if the control applies only to the control
+	// plane hosts
+	ControlPlane bool
+
+	check func(facts map[string]string) (bool, string)
+}
+
+// Controls returns all controls verified by `kubeone verify cis`, in the
+// order they're reported
+func Controls() []Control {
+	controls := []Control{}
+
+	for _, f := range Files {
+		if f.PermissionsID != "" {
+			controls = append(controls, filePermissionsControl(f))
+		}
+		if f.OwnershipID != "" {
+			controls = append(controls, fileOwnershipControl(f))
+		}
+	}
+
+	controls = append(controls,
+		flagControl("1.2.18", componentAPIServer, "profiling", "false"),
+		flagControl("1.2.22", componentAPIServer, "audit-log-path", ""),
+		flagControl("1.2.35", componentAPIServer, "tls-cipher-suites", ""),
+		flagControl("1.3.1", componentControllerManager, "terminated-pod-gc-threshold", ""),
+		flagControl("1.3.2", componentControllerManager, "profiling", "false"),
+		flagControl("1.4.1", componentScheduler, "profiling", "false"),
+		kubeletControl("4.2.1", "anonymousAuth", "false"),
+		kubeletControl("4.2.2", "authorizationMode", "Webhook"),
+		kubeletControl("4.2.4", "readOnlyPort", "0"),
+		kubeletControl("4.2.6", "protectKernelDefaults", "true"),
+		kubeletControl("4.2.7", "makeIPTablesUtilChains", "true"),
+		kubeletControl("4.2.11", "rotateCertificates", "true"),
+		kubeletControl("4.2.13", "tlsCipherSuites", ""),
+		Control{
+			ID:          "host.auditd",
+			Description: "Ensure that auditd rules watching the Kubernetes and container runtime files are loaded",
+			check: func(facts map[string]string) (bool, string) {
+				return facts["audit_rules"] == "yes", "auditd rules are not loaded"
+			},
+		},
+	)
+
+	return controls
+}
+
+// matchFiles returns the permissions of the files matching the paths, keyed
+// by the file path
+func matchFiles(facts map[string]string, paths []string) map[string]string {
+	matched := map[string]string{}
+	for key, value := range facts {
+		file := strings.TrimPrefix(key, "file.")
+		if file == key {
+			continue
+		}
+		for _, path := range paths {
+			if ok, _ := filepath.Match(path, file); ok {
+				matched[file] = value
+			}
+		}
+	}
+
+	return matched
+}
+
+func filePermissionsControl(f File) Control {
+	return Control{
+		ID:           f.PermissionsID,
+		Description:  fmt.Sprintf("Ensure that the %s permissions are set to %o or more restrictive", f.Description, f.Mode),
+		ControlPlane: f.ControlPlane,
+		check: func(facts map[string]string) (bool, string) {
+			files := matchFiles(facts, f.Paths)
+			if len(files) == 0 {
+				return false, fmt.Sprintf("%s not found", strings.Join(f.Paths, ", "))
+			}
+
+			invalid := []string{}
+			for file, stat := range files {
+				mode, err := strconv.ParseUint(strings.SplitN(stat, ":", 2)[0], 8, 32)
+				if err != nil || os.FileMode(mode)&^f.Mode != 0 {
+					invalid = append(invalid, fmt.Sprintf("%s (%s)", file, stat))
+				}
+			}
+
+			return len(invalid) == 0, fmt.Sprintf("permissions too permissive: %s", joinSorted(invalid))
+		},
+	}
+}
+
+func fileOwnershipControl(f File) Control {
+	return Control{
+		ID:           f.OwnershipID,
+		Description:  fmt.Sprintf("Ensure that the %s ownership is set to root:root", f.Description),
+		ControlPlane: f.ControlPlane,
+		check: func(facts map[string]string) (bool, string) {
+			files := matchFiles(facts, f.Paths)
+			if len(files) == 0 {
+				return false, fmt.Sprintf("%s not found", strings.Join(f.Paths, ", "))
+			}
+
+			invalid := []string{}
+			for file, stat := range files {
+				if parts := strings.SplitN(stat, ":", 2); len(parts) != 2 || parts[1] != "root:root" {
+					invalid = append(invalid, fmt.Sprintf("%s (%s)", file, stat))
+				}
+			}
+
+			return len(invalid) == 0, fmt.Sprintf("not owned by root:root: %s", joinSorted(invalid))
+		},
+	}
+}
+
+// flagControl checks the flag of the control plane component is set to want,
+// or is set to any value if want is empty
+func flagControl(id, component, flag, want string) Control {
+	desc := fmt.Sprintf("Ensure that the --%s argument of %s is set", flag, component)
+	if want != "" {
+		desc = fmt.Sprintf("Ensure that the --%s argument of %s is set to %s", flag, component, want)
+	}
+
+	return Control{
+		ID:           id,
+		Description:  desc,
+		ControlPlane: true,
+		check:        expectFact(fmt.Sprintf("flag.%s.%s", component, flag), want),
+	}
+}
+
+// kubeletControl checks the kubelet configuration field is set to want, or
+// is set to any value if want is empty
+func kubeletControl(id, field, want string) Control {
+	desc := fmt.Sprintf("Ensure that the kubelet %s setting is set", field)
+	if want != "" {
+		desc = fmt.Sprintf("Ensure that the kubelet %s setting is set to %s", field, want)
+	}
+
+	return Control{
+		ID:          id,
+		Description: desc,
+		check:       expectFact("kubelet."+field, want),
+	}
+}
+
+func expectFact(key, want string) func(map[string]string) (bool, string) {
+	return func(facts map[string]string) (bool, string) {
+		got, ok := facts[key]
+		if !ok || got == "" {
+			return false, "not set"
+		}
+		if want != "" && got != want {
+			return false, fmt.Sprintf("set to %q", got)
+		}
+
+		return true, ""
+	}
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hardening
+
+import (
+	"testing"
+)
+
+const testAPIServerManifest = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: kube-apiserver
+  namespace: kube-system
+spec:
+  containers:
+  - name: kube-apiserver
+    command:
+    - kube-apiserver
+    - --profiling=false
+    - --audit-log-path=/var/log/kubernetes/audit.log
+    - --tls-cipher-suites=TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256
+`
+
+const testKubeletConfig = `
+apiVersion: kubelet.config.k8s.io/v1beta1
+kind: KubeletConfiguration
+authentication:
+  anonymous:
+    enabled: false
+authorization:
+  mode: Webhook
+protectKernelDefaults: true
+rotateCertificates: true
+tlsCipherSuites:
+- TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256
+`
+
+// compliantFacts returns the facts of a compliant host
+func compliantFacts(t *testing.T, controlPlane bool) map[string]string {
+	t.Helper()
+
+	facts := map[string]string{"audit_rules": "yes"}
+	for _, f := range Files {
+		if f.ControlPlane && !controlPlane {
+			continue
+		}
+		facts["file."+f.Paths[0]] = "600:root:root"
+	}
+
+	kubelet, err := KubeletFacts(testKubeletConfig)
+	if err != nil {
+		t.Fatalf("KubeletFacts() error = %v", err)
+	}
+	for k, v := range kubelet {
+		facts[k] = v
+	}
+
+	if !controlPlane {
+		return facts
+	}
+
+	apiServer, err := ManifestFacts(componentAPIServer, testAPIServerManifest)
+	if err != nil {
+		t.Fatalf("ManifestFacts() error = %v", err)
+	}
+	for k, v := range apiServer {
+		facts[k] = v
+	}
+	facts["flag.kube-controller-manager.profiling"] = "false"
+	facts["flag.kube-controller-manager.terminated-pod-gc-threshold"] = "12500"
+	facts["flag.kube-scheduler.profiling"] = "false"
+
+	return facts
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name         string
+		controlPlane bool
+		facts        map[string]string
+		// remove are the facts removed from the compliant facts
+		remove     []string
+		wantFailed []string
+	}{
+		{
+			name:         "compliant control plane",
+			controlPlane: true,
+		},
+		{
+			name: "compliant static worker",
+		},
+		{
+			name:         "permissive manifest",
+			controlPlane: true,
+			facts:        map[string]string{"file./etc/kubernetes/manifests/kube-apiserver.yaml": "644:root:root"},
+			wantFailed:   []string{"1.1.1"},
+		},
+		{
+			name:       "certificate owned by user",
+			facts:      map[string]string{"file./etc/kubernetes/pki/ca.crt": "644:ubuntu:root"},
+			wantFailed: []string{"4.1.8"},
+		},
+		{
+			name:         "permissive key matched by glob",
+			controlPlane: true,
+			facts:        map[string]string{"file./etc/kubernetes/pki/etcd/peer.key": "640:root:root"},
+			wantFailed:   []string{"1.1.21"},
+		},
+		{
+			name:       "missing file",
+			remove:     []string{"file./var/lib/kubelet/config.yaml"},
+			wantFailed: []string{"4.1.9", "4.1.10"},
+		},
+		{
+			name:         "profiling enabled",
+			controlPlane: true,
+			facts:        map[string]string{"flag.kube-apiserver.profiling": "true"},
+			wantFailed:   []string{"1.2.18"},
+		},
+		{
+			name:         "no audit log",
+			controlPlane: true,
+			remove:       []string{"flag.kube-apiserver.audit-log-path"},
+			wantFailed:   []string{"1.2.22"},
+		},
+		{
+			name:       "kubelet without protectKernelDefaults",
+			facts:      map[string]string{"kubelet.protectKernelDefaults": "false"},
+			wantFailed: []string{"4.2.6"},
+		},
+		{
+			name:       "auditd rules not loaded",
+			facts:      map[string]string{"audit_rules": "no"},
+			wantFailed: []string{"host.auditd"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			facts := compliantFacts(t, tt.controlPlane)
+			for _, k := range tt.remove {
+				delete(facts, k)
+			}
+			for k, v := range tt.facts {
+				facts[k] = v
+			}
+
+			wantFailed := map[string]bool{}
+			for _, id := range tt.wantFailed {
+				wantFailed[id] = true
+			}
+
+			for _, res := range Evaluate(tt.controlPlane, facts) {
+				if failed := res.Status == StatusFail; failed != wantFailed[res.ID] {
+					t.Errorf("control %s status = %q (%s), want failed %v", res.ID, res.Status, res.Message, wantFailed[res.ID])
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluateControlPlaneOnly(t *testing.T) {
+	for _, res := range Evaluate(false, map[string]string{}) {
+		if res.ID == "1.2.18" || res.ID == "1.1.1" {
+			t.Errorf("control plane control %s evaluated on static worker", res.ID)
+		}
+	}
+}
+
+func TestKubeletFactsDefaults(t *testing.T) {
+	facts, err := KubeletFacts("apiVersion: kubelet.config.k8s.io/v1beta1\nkind: KubeletConfiguration\n")
+	if err != nil {
+		t.Fatalf("KubeletFacts() error = %v", err)
+	}
+
+	want := map[string]string{
+		"kubelet.anonymousAuth":          "true",
+		"kubelet.authorizationMode":      "Webhook",
+		"kubelet.readOnlyPort":           "0",
+		"kubelet.makeIPTablesUtilChains": "true",
+		"kubelet.protectKernelDefaults":  "false",
+	}
+	for k, v := range want {
+		if facts[k] != v {
+			t.Errorf("facts[%q] = %q, want %q", k, facts[k], v)
+		}
+	}
+}
+
+func TestFilePermissions(t *testing.T) {
+	cp := FilePermissions(true)
+	worker := FilePermissions(false)
+
+	if len(cp) != len(Files) {
+		t.Errorf("control plane files = %d, want %d", len(cp), len(Files))
+	}
+	for _, f := range worker {
+		for _, path := range f.Paths {
+			if path == "/etc/kubernetes/admin.conf" {
+				t.Errorf("admin.conf must not be restricted on static workers")
+			}
+		}
+	}
+}
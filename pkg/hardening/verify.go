@@ -0,0 +1,295 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hardening
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/hostpreflight"
+	"k8c.io/kubeone/pkg/scripts"
+	"k8c.io/kubeone/pkg/ssh"
+	"k8c.io/kubeone/pkg/state"
+
+	corev1 "k8s.io/api/core/v1"
+	kubeletconfigv1beta1 "k8s.io/kubelet/config/v1beta1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	StatusPass = "pass"
+	StatusFail = "fail"
+
+	RoleControlPlane = "control-plane"
+	RoleStaticWorker = "static-worker"
+
+	// controlSSH is reported if the facts can't be collected from the host
+	controlSSH = "ssh"
+
+	kubeletConfigPath = "/var/lib/kubelet/config.yaml"
+	manifestsDir      = "/etc/kubernetes/manifests"
+)
+
+// Report is the result of the controls of all hosts
+type Report struct {
+	Hosts []HostReport `json:"hosts"`
+	// Passed is true if no control failed on any host
+	Passed bool `json:"passed"`
+}
+
+// HostReport is the result of the controls of a single host
+type HostReport struct {
+	PublicAddress  string   `json:"publicAddress"`
+	PrivateAddress string   `json:"privateAddress"`
+	Role           string   `json:"role"`
+	Results        []Result `json:"results"`
+}
+
+// Result is the result of a single control
+type Result struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	Message     string `json:"message,omitempty"`
+}
+
+// Verify checks the controls on all hosts in parallel and returns the
+// report. It doesn't change the hosts.
+func Verify(s *state.State) *Report {
+	type hostRole struct {
+		host kubeoneapi.HostConfig
+		role string
+	}
+
+	hosts := []hostRole{}
+	for _, host := range s.Cluster.ControlPlane.Hosts {
+		hosts = append(hosts, hostRole{host: host, role: RoleControlPlane})
+	}
+	for _, host := range s.Cluster.StaticWorkers.Hosts {
+		hosts = append(hosts, hostRole{host: host, role: RoleStaticWorker})
+	}
+
+	report := &Report{
+		Hosts:  make([]HostReport, len(hosts)),
+		Passed: true,
+	}
+
+	var wg sync.WaitGroup
+	for i := range hosts {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			controlPlane := hosts[i].role == RoleControlPlane
+			results := []Result{}
+
+			facts, err := collectFacts(s, hosts[i].host, controlPlane)
+			if err != nil {
+				results = append(results, Result{ID: controlSSH, Description: "Collect the host configuration", Status: StatusFail, Message: err.Error()})
+			} else {
+				results = Evaluate(controlPlane, facts)
+			}
+
+			report.Hosts[i] = HostReport{
+				PublicAddress:  hosts[i].host.PublicAddress,
+				PrivateAddress: hosts[i].host.PrivateAddress,
+				Role:           hosts[i].role,
+				Results:        results,
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, host := range report.Hosts {
+		for _, res := range host.Results {
+			if res.Status == StatusFail {
+				report.Passed = false
+			}
+		}
+	}
+
+	return report
+}
+
+// Evaluate returns the results of the controls applying to the host, based
+// on the facts collected from the host
+func Evaluate(controlPlane bool, facts map[string]string) []Result {
+	results := []Result{}
+	for _, control := range Controls() {
+		if control.ControlPlane && !controlPlane {
+			continue
+		}
+
+		res := Result{ID: control.ID, Description: control.Description, Status: StatusPass}
+		if ok, msg := control.check(facts); !ok {
+			res.Status = StatusFail
+			res.Message = msg
+		}
+		results = append(results, res)
+	}
+
+	return results
+}
+
+// collectFacts returns the permissions of the files, whether the auditd
+// rules are loaded, the kubelet settings and, on the control plane hosts,
+// the flags of the control plane components
+func collectFacts(s *state.State, host kubeoneapi.HostConfig, controlPlane bool) (map[string]string, error) {
+	conn, err := s.Connector.Connect(host)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+	for _, f := range Files {
+		if f.ControlPlane && !controlPlane {
+			continue
+		}
+		paths = append(paths, f.Paths...)
+	}
+
+	script, err := scripts.CISVerify(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	stdout, stderr, _, err := conn.Exec(script)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to collect facts: %s", stderr)
+	}
+
+	facts, err := hostpreflight.ParseFacts(stdout)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse facts")
+	}
+
+	if kubeletConfig, ok := readFile(conn, kubeletConfigPath); ok {
+		kubeletFacts, err := KubeletFacts(kubeletConfig)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range kubeletFacts {
+			facts[k] = v
+		}
+	}
+
+	if !controlPlane {
+		return facts, nil
+	}
+
+	for _, component := range []string{componentAPIServer, componentControllerManager, componentScheduler} {
+		manifest, ok := readFile(conn, fmt.Sprintf("%s/%s.yaml", manifestsDir, component))
+		if !ok {
+			continue
+		}
+
+		flagFacts, err := ManifestFacts(component, manifest)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range flagFacts {
+			facts[k] = v
+		}
+	}
+
+	return facts, nil
+}
+
+// readFile returns the content of the file, or false if it can't be read
+func readFile(conn ssh.Connection, path string) (string, bool) {
+	stdout, _, _, err := conn.Exec(fmt.Sprintf("sudo cat %s", path))
+	if err != nil {
+		return "", false
+	}
+
+	return stdout, true
+}
+
+// KubeletFacts returns the kubelet settings checked by the controls as
+// kubelet.<field> facts, applying the kubelet defaults to the unset fields
+func KubeletFacts(config string) (map[string]string, error) {
+	cfg := kubeletconfigv1beta1.KubeletConfiguration{}
+	if err := yaml.Unmarshal([]byte(config), &cfg); err != nil {
+		return nil, errors.Wrap(err, "failed to parse kubelet configuration")
+	}
+
+	anonymousAuth := true
+	if cfg.Authentication.Anonymous.Enabled != nil {
+		anonymousAuth = *cfg.Authentication.Anonymous.Enabled
+	}
+	makeIPTablesUtilChains := true
+	if cfg.MakeIPTablesUtilChains != nil {
+		makeIPTablesUtilChains = *cfg.MakeIPTablesUtilChains
+	}
+	authorizationMode := string(cfg.Authorization.Mode)
+	if authorizationMode == "" {
+		authorizationMode = string(kubeletconfigv1beta1.KubeletAuthorizationModeWebhook)
+	}
+
+	return map[string]string{
+		"kubelet.anonymousAuth":          strconv.FormatBool(anonymousAuth),
+		"kubelet.authorizationMode":      authorizationMode,
+		"kubelet.readOnlyPort":           strconv.Itoa(int(cfg.ReadOnlyPort)),
+		"kubelet.protectKernelDefaults":  strconv.FormatBool(cfg.ProtectKernelDefaults),
+		"kubelet.makeIPTablesUtilChains": strconv.FormatBool(makeIPTablesUtilChains),
+		"kubelet.rotateCertificates":     strconv.FormatBool(cfg.RotateCertificates),
+		"kubelet.tlsCipherSuites":        strings.Join(cfg.TLSCipherSuites, ","),
+	}, nil
+}
+
+// ManifestFacts returns the flags of the control plane component static pod
+// as flag.<component>.<flag> facts
+func ManifestFacts(component, manifest string) (map[string]string, error) {
+	pod := corev1.Pod{}
+	if err := yaml.Unmarshal([]byte(manifest), &pod); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s manifest", component)
+	}
+
+	facts := map[string]string{}
+	for _, container := range pod.Spec.Containers {
+		if container.Name != component {
+			continue
+		}
+
+		for _, arg := range append(container.Command, container.Args...) {
+			if !strings.HasPrefix(arg, "--") {
+				continue
+			}
+
+			kv := strings.SplitN(strings.TrimPrefix(arg, "--"), "=", 2)
+			value := "true"
+			if len(kv) == 2 {
+				value = kv[1]
+			}
+			facts[fmt.Sprintf("flag.%s.%s", component, kv[0])] = value
+		}
+	}
+
+	return facts, nil
+}
+
+func joinSorted(items []string) string {
+	sort.Strings(items)
+
+	return strings.Join(items, ", ")
+}
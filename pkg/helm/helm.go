@@ -0,0 +1,232 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/cli/values"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/filelock"
+	"k8c.io/kubeone/pkg/secretstore"
+	"k8c.io/kubeone/pkg/state"
+)
+
+var valuesEscaper = strings.NewReplacer(`\`, `\\`, `,`, `\,`, `{`, `\{`)
+
+const (
+	// helmStorageDriver is the storage backend used for the Helm releases
+	helmStorageDriver = "secret"
+)
+
+// EnsureRelease installs the Helm release of the given chart if it doesn't
+// exist, or upgrades it otherwise
+func EnsureRelease(s *state.State, chart *kubeoneapi.HelmChart) error {
+	if s.RESTConfig == nil {
+		return errors.New("kubernetes REST config is not initialized")
+	}
+
+	settings := cli.New()
+	logger := s.Logger.WithField("release", chart.ReleaseName)
+
+	cfg := &action.Configuration{}
+	if err := cfg.Init(newRESTClientGetter(s.RESTConfig, chart.Namespace), chart.Namespace, helmStorageDriver, logger.Debugf); err != nil {
+		return errors.Wrap(err, "failed to initialize helm configuration")
+	}
+
+	vals, err := releaseValues(s, chart, settings)
+	if err != nil {
+		return err
+	}
+
+	return ensureRelease(cfg, logger, chart, settings, vals)
+}
+
+// ensureRelease installs or upgrades the release using the given Helm
+// configuration. The release left pending by the interrupted install,
+// upgrade or rollback is recovered first, because Helm refuses to upgrade it.
+// The failed releases are upgraded as usual.
+func ensureRelease(cfg *action.Configuration, logger logrus.FieldLogger, chart *kubeoneapi.HelmChart, settings *cli.EnvSettings, vals map[string]interface{}) error {
+	lastRelease, err := cfg.Releases.Last(chart.ReleaseName)
+	switch {
+	case errors.Is(err, driver.ErrReleaseNotFound):
+		return installRelease(cfg, logger, chart, settings, vals)
+	case err != nil:
+		return errors.Wrapf(err, "failed to get helm release %q", chart.ReleaseName)
+	}
+
+	if lastRelease.Info.Status.IsPending() {
+		installed, err := recoverPendingRelease(cfg, logger, lastRelease)
+		if err != nil {
+			return err
+		}
+		if !installed {
+			return installRelease(cfg, logger, chart, settings, vals)
+		}
+	}
+
+	upgrade := action.NewUpgrade(cfg)
+	upgrade.Namespace = chart.Namespace
+	upgrade.RepoURL = chart.RepoURL
+	upgrade.Version = chart.Version
+
+	helmChart, err := loadChart(chart.Chart, settings, upgrade.LocateChart)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Upgrading Helm release %q...", chart.ReleaseName)
+	_, err = upgrade.Run(chart.ReleaseName, helmChart, vals)
+
+	return errors.Wrapf(err, "failed to upgrade helm release %q", chart.ReleaseName)
+}
+
+func installRelease(cfg *action.Configuration, logger logrus.FieldLogger, chart *kubeoneapi.HelmChart, settings *cli.EnvSettings, vals map[string]interface{}) error {
+	install := action.NewInstall(cfg)
+	install.ReleaseName = chart.ReleaseName
+	install.Namespace = chart.Namespace
+	install.CreateNamespace = true
+	install.RepoURL = chart.RepoURL
+	install.Version = chart.Version
+
+	helmChart, err := loadChart(chart.Chart, settings, install.LocateChart)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Installing Helm release %q...", chart.ReleaseName)
+	_, err = install.Run(helmChart, vals)
+
+	return errors.Wrapf(err, "failed to install helm release %q", chart.ReleaseName)
+}
+
+// recoverPendingRelease uninstalls the release left pending by the
+// interrupted install, or rolls back the release left pending by the
+// interrupted upgrade or rollback to the last deployed revision. It returns
+// false if the release has been uninstalled and must be installed again.
+func recoverPendingRelease(cfg *action.Configuration, logger logrus.FieldLogger, rel *release.Release) (bool, error) {
+	if rel.Info.Status == release.StatusPendingInstall {
+		logger.Warnf("Uninstalling Helm release %q left in the %s state...", rel.Name, rel.Info.Status)
+		if _, err := action.NewUninstall(cfg).Run(rel.Name); err != nil {
+			return false, errors.Wrapf(err, "failed to uninstall helm release %q", rel.Name)
+		}
+
+		return false, nil
+	}
+
+	rollback := action.NewRollback(cfg)
+
+	// Without the deployed revision, the release is rolled back to the
+	// previous revision
+	deployed, err := cfg.Releases.Deployed(rel.Name)
+	switch {
+	case err == nil:
+		rollback.Version = deployed.Version
+	case !errors.Is(err, driver.ErrNoDeployedReleases):
+		return false, errors.Wrapf(err, "failed to get deployed helm release %q", rel.Name)
+	}
+
+	logger.Warnf("Rolling back Helm release %q left in the %s state...", rel.Name, rel.Info.Status)
+	if err := rollback.Run(rel.Name); err != nil {
+		return false, errors.Wrapf(err, "failed to roll back helm release %q", rel.Name)
+	}
+
+	return true, nil
+}
+
+// loadChart downloads the chart to the Helm repository cache, if needed, and
+// loads it. The repository cache is shared by all KubeOne and Helm processes
+// on the machine, so it's locked while the chart is downloaded and loaded.
+func loadChart(name string, settings *cli.EnvSettings, locateChart func(string, *cli.EnvSettings) (string, error)) (*chart.Chart, error) {
+	var helmChart *chart.Chart
+
+	err := filelock.WithLock(settings.RepositoryCache, func() error {
+		chartPath, err := locateChart(name, settings)
+		if err != nil {
+			return errors.Wrapf(err, "failed to locate chart %q", name)
+		}
+
+		helmChart, err = loader.Load(chartPath)
+
+		return errors.Wrapf(err, "failed to load chart %q", name)
+	})
+
+	return helmChart, err
+}
+
+// releaseValues merges values files and values of the chart. Relative paths
+// are resolved relative to the KubeOneCluster manifest.
+func releaseValues(s *state.State, chart *kubeoneapi.HelmChart, settings *cli.EnvSettings) (map[string]interface{}, error) {
+	opts := values.Options{}
+
+	for _, valuesFile := range chart.ValuesFiles {
+		if !filepath.IsAbs(valuesFile) && s.ManifestFilePath != "" {
+			manifestAbsPath, err := filepath.Abs(filepath.Dir(s.ManifestFilePath))
+			if err != nil {
+				return nil, errors.Wrap(err, "unable to get absolute path to the cluster manifest")
+			}
+			valuesFile = filepath.Join(manifestAbsPath, valuesFile)
+		}
+		opts.ValueFiles = append(opts.ValueFiles, valuesFile)
+	}
+
+	keys := make([]string, 0, len(chart.Values))
+	for k := range chart.Values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := chart.Values[k]
+		if !secretstore.IsReference(v) {
+			opts.Values = append(opts.Values, fmt.Sprintf("%s=%s", k, v))
+			continue
+		}
+
+		// Secrets are always strings, so they're set using --set-string
+		// to avoid e.g. "0123" or "true" being parsed as a number or bool,
+		// and escaped, so they're never parsed as a list or as multiple values
+		resolved, err := secretstore.Resolve(s.Context, v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to resolve helm value %q", k)
+		}
+		opts.StringValues = append(opts.StringValues, fmt.Sprintf("%s=%s", k, escapeValue(resolved)))
+	}
+
+	vals, err := opts.MergeValues(getter.All(settings))
+
+	return vals, errors.Wrap(err, "failed to merge helm values")
+}
+
+// escapeValue escapes characters of the secret value that would be otherwise
+// parsed by the Helm --set parser as separators or as the start of a list
+func escapeValue(value string) string {
+	return valuesEscaper.Replace(value)
+}
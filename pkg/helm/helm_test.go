@@ -0,0 +1,224 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/state"
+)
+
+const (
+	testChartYAML = `apiVersion: v2
+name: test
+version: 0.1.0
+`
+	testChartTemplate = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}
+data:
+  replicas: "{{ .Values.replicas }}"
+`
+)
+
+func TestEnsureRelease(t *testing.T) {
+	chartDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(chartDir, "templates"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(chartDir, "Chart.yaml"), []byte(testChartYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(chartDir, "templates", "configmap.yaml"), []byte(testChartTemplate), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	helmChart, err := loader.Load(chartDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		existing        []release.Status
+		expectedVersion int
+	}{
+		{
+			name:            "install",
+			expectedVersion: 1,
+		},
+		{
+			name:            "upgrade deployed release",
+			existing:        []release.Status{release.StatusDeployed},
+			expectedVersion: 2,
+		},
+		{
+			name:            "upgrade failed release",
+			existing:        []release.Status{release.StatusDeployed, release.StatusFailed},
+			expectedVersion: 3,
+		},
+		{
+			name:            "upgrade failed install",
+			existing:        []release.Status{release.StatusFailed},
+			expectedVersion: 2,
+		},
+		{
+			name:            "reinstall pending install",
+			existing:        []release.Status{release.StatusPendingInstall},
+			expectedVersion: 1,
+		},
+		{
+			name:            "roll back and upgrade pending upgrade",
+			existing:        []release.Status{release.StatusDeployed, release.StatusPendingUpgrade},
+			expectedVersion: 4,
+		},
+		{
+			name:            "roll back and upgrade pending rollback",
+			existing:        []release.Status{release.StatusDeployed, release.StatusSuperseded, release.StatusPendingRollback},
+			expectedVersion: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &action.Configuration{
+				Releases:     storage.Init(driver.NewMemory()),
+				KubeClient:   &kubefake.PrintingKubeClient{Out: ioutil.Discard},
+				Capabilities: chartutil.DefaultCapabilities,
+				Log:          t.Logf,
+			}
+
+			for i, status := range tt.existing {
+				rel := &release.Release{
+					Name:      "test",
+					Namespace: "kube-system",
+					Version:   i + 1,
+					Chart:     helmChart,
+					Info:      &release.Info{Status: status},
+				}
+				if err := cfg.Releases.Create(rel); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			chart := &kubeoneapi.HelmChart{
+				ReleaseName: "test",
+				Chart:       chartDir,
+				Namespace:   "kube-system",
+			}
+			settings := cli.New()
+			settings.RepositoryCache = t.TempDir()
+			vals := map[string]interface{}{"replicas": int64(3)}
+
+			if err := ensureRelease(cfg, logrus.New(), chart, settings, vals); err != nil {
+				t.Fatalf("ensureRelease() error = %v", err)
+			}
+
+			got, err := cfg.Releases.Last("test")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Version != tt.expectedVersion {
+				t.Errorf("ensureRelease() release version = %d, expected %d", got.Version, tt.expectedVersion)
+			}
+			if got.Info.Status != release.StatusDeployed {
+				t.Errorf("ensureRelease() release status = %s, expected %s", got.Info.Status, release.StatusDeployed)
+			}
+			if !strings.Contains(got.Manifest, `replicas: "3"`) {
+				t.Errorf("ensureRelease() release manifest doesn't contain the rendered value:\n%s", got.Manifest)
+			}
+		})
+	}
+}
+
+func TestReleaseValues(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/secret/data/helm-values" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": {"data": {"pin": "0123", "enabled": "true", "exp": "1e3", "list": "{a,b}", "special": "a,b[0]=c\\d"}, "metadata": {"version": 1}}}`))
+	}))
+	defer srv.Close()
+
+	t.Setenv("VAULT_ADDR", srv.URL)
+	t.Setenv("VAULT_TOKEN", "test-token")
+
+	chart := &kubeoneapi.HelmChart{
+		Values: map[string]string{
+			"replicas":         "3",
+			"auth.pin":         "vault:secret/data/helm-values#pin",
+			"auth.enabled":     "vault:secret/data/helm-values#enabled",
+			"auth.exp":         "vault:secret/data/helm-values#exp",
+			"auth.list":        "vault:secret/data/helm-values#list",
+			"auth.special":     "vault:secret/data/helm-values#special",
+			"image.pullAlways": "false",
+			"tolerations":      "{a,b}",
+			"env.a":            "1,env.b=2",
+		},
+	}
+
+	s := &state.State{Context: context.Background()}
+	got, err := releaseValues(s, chart, cli.New())
+	if err != nil {
+		t.Fatalf("releaseValues() error = %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"replicas": int64(3),
+		"image": map[string]interface{}{
+			"pullAlways": false,
+		},
+		"tolerations": []interface{}{"a", "b"},
+		"env": map[string]interface{}{
+			"a": int64(1),
+			"b": int64(2),
+		},
+		"auth": map[string]interface{}{
+			"pin":     "0123",
+			"enabled": "true",
+			"exp":     "1e3",
+			"list":    "{a,b}",
+			"special": `a,b[0]=c\d`,
+		},
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("releaseValues() = %#v, expected %#v", got, expected)
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostdiagnostics
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awscredentials "github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/credentials"
+)
+
+type awsDiagnoser struct {
+	client *ec2.EC2
+}
+
+func newAWSDiagnoser(cluster *kubeoneapi.KubeOneCluster, creds map[string]string) (*awsDiagnoser, error) {
+	config := aws.NewConfig()
+
+	if creds[credentials.AWSAccessKeyID] != "" {
+		config = config.WithCredentials(awscredentials.NewStaticCredentials(creds[credentials.AWSAccessKeyID], creds[credentials.AWSSecretAccessKey], ""))
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *config,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create aws session")
+	}
+
+	// The region is not part of the KubeOne configuration, so it's taken from
+	// the dynamic workers if not set in the environment
+	if aws.StringValue(sess.Config.Region) == "" {
+		region := workersRegion(cluster)
+		if region == "" {
+			return nil, errors.New("aws region is unknown, set the AWS_REGION environment variable")
+		}
+		sess = sess.Copy(aws.NewConfig().WithRegion(region))
+	}
+
+	return &awsDiagnoser{client: ec2.New(sess)}, nil
+}
+
+func (d *awsDiagnoser) Diagnose(ctx context.Context, host kubeoneapi.HostConfig) (*Report, error) {
+	instance, err := d.findInstance(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		InstanceID: aws.StringValue(instance.InstanceId),
+		State:      aws.StringValue(instance.State.Name),
+	}
+
+	statusOut, err := d.client.DescribeInstanceStatusWithContext(ctx, &ec2.DescribeInstanceStatusInput{
+		InstanceIds: []*string{instance.InstanceId},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to describe instance status")
+	}
+
+	for _, status := range statusOut.InstanceStatuses {
+		if status.SystemStatus != nil {
+			report.StatusChecks = append(report.StatusChecks, fmt.Sprintf("system status: %s", aws.StringValue(status.SystemStatus.Status)))
+		}
+		if status.InstanceStatus != nil {
+			report.StatusChecks = append(report.StatusChecks, fmt.Sprintf("instance status: %s", aws.StringValue(status.InstanceStatus.Status)))
+		}
+	}
+
+	consoleOut, err := d.client.GetConsoleOutputWithContext(ctx, &ec2.GetConsoleOutputInput{
+		InstanceId: instance.InstanceId,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get console output")
+	}
+
+	if output := aws.StringValue(consoleOut.Output); output != "" {
+		decoded, err := base64.StdEncoding.DecodeString(output)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode console output")
+		}
+		report.ConsoleOutput = string(decoded)
+	}
+
+	return report, nil
+}
+
+// findInstance looks up the instance by the private address, falling back
+// to the public address
+func (d *awsDiagnoser) findInstance(ctx context.Context, host kubeoneapi.HostConfig) (*ec2.Instance, error) {
+	filters := []*ec2.Filter{
+		{Name: aws.String("private-ip-address"), Values: []*string{aws.String(host.PrivateAddress)}},
+		{Name: aws.String("ip-address"), Values: []*string{aws.String(host.PublicAddress)}},
+	}
+
+	for _, filter := range filters {
+		if aws.StringValue(filter.Values[0]) == "" {
+			continue
+		}
+
+		out, err := d.client.DescribeInstancesWithContext(ctx, &ec2.DescribeInstancesInput{
+			Filters: []*ec2.Filter{filter},
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to describe instances")
+		}
+
+		for _, reservation := range out.Reservations {
+			if len(reservation.Instances) > 0 {
+				return reservation.Instances[0], nil
+			}
+		}
+	}
+
+	return nil, errors.Errorf("no instance found with address %q or %q", host.PrivateAddress, host.PublicAddress)
+}
+
+func workersRegion(cluster *kubeoneapi.KubeOneCluster) string {
+	for _, worker := range cluster.DynamicWorkers {
+		spec := struct {
+			Region string `json:"region"`
+		}{}
+
+		if err := json.Unmarshal(worker.Config.CloudProviderSpec, &spec); err == nil && spec.Region != "" {
+			return spec.Region
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hostdiagnostics inspects hosts which can't be reached over SSH
+// using the cloud provider API, to tell apart machines which are stopped or
+// failed to boot from the network issues.
+package hostdiagnostics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+)
+
+const (
+	// consoleOutputLines is the number of the last console output lines
+	// included in the report
+	consoleOutputLines = 30
+)
+
+// Diagnoser inspects the host using the cloud provider API
+type Diagnoser interface {
+	Diagnose(ctx context.Context, host kubeoneapi.HostConfig) (*Report, error)
+}
+
+// Report is the state of the host, as reported by the cloud provider
+type Report struct {
+	InstanceID    string
+	State         string
+	StatusChecks  []string
+	ConsoleOutput string
+}
+
+func (r *Report) String() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "Instance %s is %s", r.InstanceID, r.State)
+	if len(r.StatusChecks) > 0 {
+		fmt.Fprintf(&sb, " (%s)", strings.Join(r.StatusChecks, ", "))
+	}
+	sb.WriteString("\n")
+
+	if r.ConsoleOutput == "" {
+		sb.WriteString("Console output is not available\n")
+		return sb.String()
+	}
+
+	fmt.Fprintf(&sb, "Last %d lines of the console output:\n", consoleOutputLines)
+	for _, line := range lastLines(r.ConsoleOutput, consoleOutputLines) {
+		fmt.Fprintf(&sb, "\t%s\n", line)
+	}
+
+	return sb.String()
+}
+
+// New returns the Diagnoser for the cloud provider used by the cluster.
+// Only AWS is currently supported.
+func New(cluster *kubeoneapi.KubeOneCluster, credentials map[string]string) (Diagnoser, error) {
+	switch {
+	case cluster.CloudProvider.AWS != nil:
+		return newAWSDiagnoser(cluster, credentials)
+	}
+
+	return nil, errors.Errorf("diagnosing hosts is not supported for the %q cloud provider", cluster.CloudProvider.CloudProviderName())
+}
+
+func lastLines(s string, n int) []string {
+	lines := strings.Split(strings.TrimRight(strings.ReplaceAll(s, "\r\n", "\n"), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+
+	return lines
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostdiagnostics
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+)
+
+func TestReportString(t *testing.T) {
+	var longOutput []string
+	for i := 1; i <= 40; i++ {
+		longOutput = append(longOutput, fmt.Sprintf("line %d", i))
+	}
+
+	tests := []struct {
+		name     string
+		report   Report
+		contains []string
+		excludes []string
+	}{
+		{
+			name: "stopped instance without console output",
+			report: Report{
+				InstanceID: "i-0123456789",
+				State:      "stopped",
+			},
+			contains: []string{"Instance i-0123456789 is stopped\n", "Console output is not available"},
+		},
+		{
+			name: "running instance with failed status checks",
+			report: Report{
+				InstanceID:    "i-0123456789",
+				State:         "running",
+				StatusChecks:  []string{"system status: ok", "instance status: impaired"},
+				ConsoleOutput: "Booting...\r\nKernel panic - not syncing\r\n",
+			},
+			contains: []string{
+				"Instance i-0123456789 is running (system status: ok, instance status: impaired)",
+				"\tKernel panic - not syncing\n",
+			},
+		},
+		{
+			name: "only last lines of console output",
+			report: Report{
+				InstanceID:    "i-0123456789",
+				State:         "running",
+				ConsoleOutput: strings.Join(longOutput, "\n"),
+			},
+			contains: []string{"\tline 11\n", "\tline 40\n"},
+			excludes: []string{"\tline 10\n"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.report.String()
+			for _, s := range tt.contains {
+				if !strings.Contains(got, s) {
+					t.Errorf("Report.String() = %q, expected to contain %q", got, s)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(got, s) {
+					t.Errorf("Report.String() = %q, expected not to contain %q", got, s)
+				}
+			}
+		})
+	}
+}
+
+func TestWorkersRegion(t *testing.T) {
+	tests := []struct {
+		name    string
+		workers []kubeoneapi.DynamicWorkerConfig
+		want    string
+	}{
+		{
+			name: "no workers",
+			want: "",
+		},
+		{
+			name: "region from the first worker with region",
+			workers: []kubeoneapi.DynamicWorkerConfig{
+				{Config: kubeoneapi.ProviderSpec{CloudProviderSpec: []byte(`{"instanceType": "t3.medium"}`)}},
+				{Config: kubeoneapi.ProviderSpec{CloudProviderSpec: []byte(`{"region": "eu-west-3"}`)}},
+			},
+			want: "eu-west-3",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := &kubeoneapi.KubeOneCluster{DynamicWorkers: tt.workers}
+			if got := workersRegion(cluster); got != tt.want {
+				t.Errorf("workersRegion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
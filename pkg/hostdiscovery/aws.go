@@ -0,0 +1,93 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostdiscovery
+
+import (
+	"context"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awscredentials "github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+
+	kubeonev1beta1 "k8c.io/kubeone/pkg/apis/kubeone/v1beta1"
+	"k8c.io/kubeone/pkg/credentials"
+)
+
+type awsDiscoverer struct {
+	client *ec2.EC2
+}
+
+func newAWSDiscoverer(cluster *kubeonev1beta1.KubeOneCluster, creds map[string]string) (*awsDiscoverer, error) {
+	config := aws.NewConfig()
+
+	if creds[credentials.AWSAccessKeyID] != "" {
+		config = config.WithCredentials(awscredentials.NewStaticCredentials(creds[credentials.AWSAccessKeyID], creds[credentials.AWSSecretAccessKey], ""))
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *config,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create aws session")
+	}
+
+	if aws.StringValue(sess.Config.Region) == "" {
+		region := workersRegion(cluster)
+		if region == "" {
+			return nil, errors.New("aws region is unknown, set the AWS_REGION environment variable")
+		}
+		sess = sess.Copy(aws.NewConfig().WithRegion(region))
+	}
+
+	return &awsDiscoverer{client: ec2.New(sess)}, nil
+}
+
+func (d *awsDiscoverer) Discover(ctx context.Context, tags map[string]string) ([]Instance, error) {
+	filters := []*ec2.Filter{
+		{Name: aws.String("instance-state-name"), Values: []*string{aws.String(ec2.InstanceStateNameRunning)}},
+	}
+
+	keys := []string{}
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		filters = append(filters, &ec2.Filter{Name: aws.String("tag:" + key), Values: []*string{aws.String(tags[key])}})
+	}
+
+	instances := []Instance{}
+	err := d.client.DescribeInstancesPagesWithContext(ctx, &ec2.DescribeInstancesInput{Filters: filters}, func(out *ec2.DescribeInstancesOutput, _ bool) bool {
+		for _, reservation := range out.Reservations {
+			for _, instance := range reservation.Instances {
+				instances = append(instances, Instance{
+					Name:           aws.StringValue(instance.InstanceId),
+					PublicAddress:  aws.StringValue(instance.PublicIpAddress),
+					PrivateAddress: aws.StringValue(instance.PrivateIpAddress),
+				})
+			}
+		}
+
+		return true
+	})
+
+	return instances, errors.Wrap(err, "failed to describe instances")
+}
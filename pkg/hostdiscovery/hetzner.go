@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostdiscovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/credentials"
+)
+
+const hetznerEndpoint = "https://api.hetzner.cloud/v1"
+
+type hetznerDiscoverer struct {
+	endpoint string
+	token    string
+	client   *http.Client
+}
+
+type hetznerServersResponse struct {
+	Servers []struct {
+		Name      string `json:"name"`
+		Status    string `json:"status"`
+		PublicNet struct {
+			IPv4 struct {
+				IP string `json:"ip"`
+			} `json:"ipv4"`
+		} `json:"public_net"`
+		PrivateNet []struct {
+			IP string `json:"ip"`
+		} `json:"private_net"`
+	} `json:"servers"`
+	Meta struct {
+		Pagination struct {
+			NextPage int `json:"next_page"`
+		} `json:"pagination"`
+	} `json:"meta"`
+}
+
+func newHetznerDiscoverer(creds map[string]string) *hetznerDiscoverer {
+	return &hetznerDiscoverer{
+		endpoint: hetznerEndpoint,
+		token:    creds[credentials.HetznerTokenKeyMC],
+		client:   http.DefaultClient,
+	}
+}
+
+func (d *hetznerDiscoverer) Discover(ctx context.Context, tags map[string]string) ([]Instance, error) {
+	selector := []string{}
+	for key, value := range tags {
+		selector = append(selector, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(selector)
+
+	instances := []Instance{}
+	for page := 1; page != 0; {
+		query := url.Values{}
+		query.Set("label_selector", strings.Join(selector, ","))
+		query.Set("status", "running")
+		query.Set("page", fmt.Sprint(page))
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.endpoint+"/servers?"+query.Encode(), nil)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		req.Header.Set("Authorization", "Bearer "+d.token)
+
+		servers := hetznerServersResponse{}
+		if err = doJSON(d.client, req, &servers); err != nil {
+			return nil, errors.Wrap(err, "failed to list servers")
+		}
+
+		for _, server := range servers.Servers {
+			instance := Instance{
+				Name:          server.Name,
+				PublicAddress: server.PublicNet.IPv4.IP,
+			}
+			if len(server.PrivateNet) > 0 {
+				instance.PrivateAddress = server.PrivateNet[0].IP
+			}
+			instances = append(instances, instance)
+		}
+
+		page = servers.Meta.Pagination.NextPage
+	}
+
+	return instances, nil
+}
+
+// doJSON sends the request and decodes the JSON response
+func doJSON(client *http.Client, req *http.Request, out interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return errors.WithStack(json.NewDecoder(resp.Body).Decode(out))
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hostdiscovery discovers the control plane and the static worker
+// hosts by their tags using the cloud provider API, so the manifest doesn't
+// have to be updated when the instances are replaced.
+package hostdiscovery
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	kubeonev1beta1 "k8c.io/kubeone/pkg/apis/kubeone/v1beta1"
+)
+
+// Instance is the running instance found by the Discoverer
+type Instance struct {
+	Name           string
+	PublicAddress  string
+	PrivateAddress string
+}
+
+// Discoverer finds the running instances with all the given tags
+type Discoverer interface {
+	Discover(ctx context.Context, tags map[string]string) ([]Instance, error)
+}
+
+// New returns the Discoverer for the cloud provider used by the cluster
+func New(cluster *kubeonev1beta1.KubeOneCluster, credentials map[string]string) (Discoverer, error) {
+	switch {
+	case cluster.CloudProvider.AWS != nil:
+		return newAWSDiscoverer(cluster, credentials)
+	case cluster.CloudProvider.Hetzner != nil:
+		return newHetznerDiscoverer(credentials), nil
+	case cluster.CloudProvider.Openstack != nil:
+		return newOpenStackDiscoverer(credentials), nil
+	}
+
+	return nil, errors.New("host discovery is supported only on AWS, Hetzner and OpenStack")
+}
+
+// Apply adds the hosts discovered by the host discovery of the control plane
+// and the static workers to their hosts. The discovered hosts are ordered by
+// the private address, so the default leader doesn't change between runs.
+func Apply(ctx context.Context, cluster *kubeonev1beta1.KubeOneCluster, discoverer Discoverer) error {
+	if hd := cluster.ControlPlane.HostDiscovery; hd != nil {
+		hosts, err := discoverHosts(ctx, discoverer, hd)
+		if err != nil {
+			return errors.Wrap(err, "failed to discover control plane hosts")
+		}
+		if len(hosts) == 0 && len(cluster.ControlPlane.Hosts) == 0 {
+			return errors.Errorf("no running control plane instances found with tags %v", hd.Tags)
+		}
+		cluster.ControlPlane.Hosts = append(cluster.ControlPlane.Hosts, hosts...)
+	}
+
+	if hd := cluster.StaticWorkers.HostDiscovery; hd != nil {
+		hosts, err := discoverHosts(ctx, discoverer, hd)
+		if err != nil {
+			return errors.Wrap(err, "failed to discover static worker hosts")
+		}
+		cluster.StaticWorkers.Hosts = append(cluster.StaticWorkers.Hosts, hosts...)
+	}
+
+	return nil
+}
+
+func discoverHosts(ctx context.Context, discoverer Discoverer, hd *kubeonev1beta1.HostDiscoveryConfig) ([]kubeonev1beta1.HostConfig, error) {
+	// Instances without any tags would match all instances
+	if len(hd.Tags) == 0 {
+		return nil, errors.New("at least one tag is required")
+	}
+
+	instances, err := discoverer.Discover(ctx, hd.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].PrivateAddress < instances[j].PrivateAddress
+	})
+
+	hosts := []kubeonev1beta1.HostConfig{}
+	for _, instance := range instances {
+		host := *hd.Template.DeepCopy()
+		host.PublicAddress = instance.PublicAddress
+		host.PrivateAddress = instance.PrivateAddress
+		host.IsLeader = false
+		hosts = append(hosts, host)
+	}
+
+	return hosts, nil
+}
+
+// workersRegion returns the region of the dynamic workers, used if the
+// region is not set in the environment
+func workersRegion(cluster *kubeonev1beta1.KubeOneCluster) string {
+	for _, worker := range cluster.DynamicWorkers {
+		spec := struct {
+			Region string `json:"region"`
+		}{}
+
+		if err := json.Unmarshal(worker.Config.CloudProviderSpec, &spec); err == nil && spec.Region != "" {
+			return spec.Region
+		}
+	}
+
+	return ""
+}
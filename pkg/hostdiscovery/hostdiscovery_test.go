@@ -0,0 +1,216 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostdiscovery
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	kubeonev1beta1 "k8c.io/kubeone/pkg/apis/kubeone/v1beta1"
+	"k8c.io/kubeone/pkg/credentials"
+)
+
+type fakeDiscoverer map[string][]Instance
+
+func (f fakeDiscoverer) Discover(_ context.Context, tags map[string]string) ([]Instance, error) {
+	return f[tags["role"]], nil
+}
+
+func TestApply(t *testing.T) {
+	discoverer := fakeDiscoverer{
+		"control-plane": {
+			{Name: "cp-2", PublicAddress: "192.0.2.2", PrivateAddress: "10.0.0.2"},
+			{Name: "cp-1", PublicAddress: "192.0.2.1", PrivateAddress: "10.0.0.1"},
+		},
+		"worker": {
+			{Name: "worker-1", PrivateAddress: "10.0.1.1"},
+		},
+	}
+
+	tests := []struct {
+		name              string
+		cluster           kubeonev1beta1.KubeOneCluster
+		wantControlPlane  []kubeonev1beta1.HostConfig
+		wantStaticWorkers []kubeonev1beta1.HostConfig
+		wantErr           bool
+	}{
+		{
+			name: "discover control plane and static workers",
+			cluster: kubeonev1beta1.KubeOneCluster{
+				ControlPlane: kubeonev1beta1.ControlPlaneConfig{
+					HostDiscovery: &kubeonev1beta1.HostDiscoveryConfig{
+						Tags:     map[string]string{"role": "control-plane"},
+						Template: kubeonev1beta1.HostConfig{SSHUsername: "ubuntu", IsLeader: true},
+					},
+				},
+				StaticWorkers: kubeonev1beta1.StaticWorkersConfig{
+					HostDiscovery: &kubeonev1beta1.HostDiscoveryConfig{
+						Tags: map[string]string{"role": "worker"},
+					},
+				},
+			},
+			wantControlPlane: []kubeonev1beta1.HostConfig{
+				{PublicAddress: "192.0.2.1", PrivateAddress: "10.0.0.1", SSHUsername: "ubuntu"},
+				{PublicAddress: "192.0.2.2", PrivateAddress: "10.0.0.2", SSHUsername: "ubuntu"},
+			},
+			wantStaticWorkers: []kubeonev1beta1.HostConfig{
+				{PrivateAddress: "10.0.1.1"},
+			},
+		},
+		{
+			name: "discovered hosts are added to the explicit hosts",
+			cluster: kubeonev1beta1.KubeOneCluster{
+				ControlPlane: kubeonev1beta1.ControlPlaneConfig{
+					Hosts: []kubeonev1beta1.HostConfig{{PrivateAddress: "10.0.0.10"}},
+					HostDiscovery: &kubeonev1beta1.HostDiscoveryConfig{
+						Tags: map[string]string{"role": "none"},
+					},
+				},
+			},
+			wantControlPlane: []kubeonev1beta1.HostConfig{{PrivateAddress: "10.0.0.10"}},
+		},
+		{
+			name: "no control plane hosts",
+			cluster: kubeonev1beta1.KubeOneCluster{
+				ControlPlane: kubeonev1beta1.ControlPlaneConfig{
+					HostDiscovery: &kubeonev1beta1.HostDiscoveryConfig{
+						Tags: map[string]string{"role": "none"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "no tags",
+			cluster: kubeonev1beta1.KubeOneCluster{
+				ControlPlane: kubeonev1beta1.ControlPlaneConfig{
+					Hosts:         []kubeonev1beta1.HostConfig{{PrivateAddress: "10.0.0.10"}},
+					HostDiscovery: &kubeonev1beta1.HostDiscoveryConfig{},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := Apply(context.Background(), &tt.cluster, discoverer)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Apply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if !reflect.DeepEqual(tt.cluster.ControlPlane.Hosts, tt.wantControlPlane) {
+				t.Errorf("control plane hosts = %+v, want %+v", tt.cluster.ControlPlane.Hosts, tt.wantControlPlane)
+			}
+			if !reflect.DeepEqual(tt.cluster.StaticWorkers.Hosts, tt.wantStaticWorkers) {
+				t.Errorf("static worker hosts = %+v, want %+v", tt.cluster.StaticWorkers.Hosts, tt.wantStaticWorkers)
+			}
+		})
+	}
+}
+
+func TestHetznerDiscover(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if got := r.URL.Query().Get("label_selector"); got != "cluster=test,role=cp" {
+			t.Errorf("label_selector = %q", got)
+		}
+
+		switch r.URL.Query().Get("page") {
+		case "1":
+			fmt.Fprint(w, `{"servers": [{"name": "cp-1", "public_net": {"ipv4": {"ip": "192.0.2.1"}}, "private_net": [{"ip": "10.0.0.1"}]}], "meta": {"pagination": {"next_page": 2}}}`)
+		default:
+			fmt.Fprint(w, `{"servers": [{"name": "cp-2", "public_net": {"ipv4": {"ip": "192.0.2.2"}}, "private_net": []}], "meta": {"pagination": {"next_page": null}}}`)
+		}
+	}))
+	defer server.Close()
+
+	d := newHetznerDiscoverer(map[string]string{credentials.HetznerTokenKeyMC: "token"})
+	d.endpoint = server.URL
+
+	got, err := d.Discover(context.Background(), map[string]string{"role": "cp", "cluster": "test"})
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+
+	want := []Instance{
+		{Name: "cp-1", PublicAddress: "192.0.2.1", PrivateAddress: "10.0.0.1"},
+		{Name: "cp-2", PublicAddress: "192.0.2.2"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Discover() = %+v, want %+v", got, want)
+	}
+}
+
+func TestOpenStackDiscover(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/identity/v3/auth/tokens":
+			w.Header().Set("X-Subject-Token", "token")
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"token": {"catalog": [{"type": "compute", "endpoints": [
+				{"interface": "internal", "region": "de", "url": "http://internal"},
+				{"interface": "public", "region": "de", "url": "%s/compute/"}
+			]}]}}`, server.URL)
+		case "/compute/servers/detail":
+			if r.Header.Get("X-Auth-Token") != "token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{"servers": [
+				{"name": "cp-1", "metadata": {"role": "cp"}, "addresses": {"net": [
+					{"addr": "10.0.0.1", "version": 4, "OS-EXT-IPS:type": "fixed"},
+					{"addr": "fd00::1", "version": 6, "OS-EXT-IPS:type": "fixed"},
+					{"addr": "192.0.2.1", "version": 4, "OS-EXT-IPS:type": "floating"}
+				]}},
+				{"name": "worker-1", "metadata": {"role": "worker"}, "addresses": {"net": [
+					{"addr": "10.0.0.2", "version": 4, "OS-EXT-IPS:type": "fixed"}
+				]}}
+			]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	d := newOpenStackDiscoverer(map[string]string{
+		credentials.OpenStackAuthURL:    server.URL + "/identity",
+		credentials.OpenStackRegionName: "de",
+	})
+
+	got, err := d.Discover(context.Background(), map[string]string{"role": "cp"})
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+
+	want := []Instance{{Name: "cp-1", PublicAddress: "192.0.2.1", PrivateAddress: "10.0.0.1"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Discover() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostdiscovery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/credentials"
+)
+
+type openstackDiscoverer struct {
+	creds  map[string]string
+	client *http.Client
+}
+
+type openstackServersResponse struct {
+	Servers []struct {
+		Name      string            `json:"name"`
+		Metadata  map[string]string `json:"metadata"`
+		Addresses map[string][]struct {
+			Addr    string `json:"addr"`
+			Version int    `json:"version"`
+			Type    string `json:"OS-EXT-IPS:type"`
+		} `json:"addresses"`
+	} `json:"servers"`
+	Links []struct {
+		Href string `json:"href"`
+		Rel  string `json:"rel"`
+	} `json:"servers_links"`
+}
+
+func newOpenStackDiscoverer(creds map[string]string) *openstackDiscoverer {
+	return &openstackDiscoverer{
+		creds:  creds,
+		client: http.DefaultClient,
+	}
+}
+
+func (d *openstackDiscoverer) Discover(ctx context.Context, tags map[string]string) ([]Instance, error) {
+	token, computeURL, err := d.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	instances := []Instance{}
+	for next := computeURL + "/servers/detail?status=ACTIVE"; next != ""; {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, next, nil)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		req.Header.Set("X-Auth-Token", token)
+
+		servers := openstackServersResponse{}
+		if err = doJSON(d.client, req, &servers); err != nil {
+			return nil, errors.Wrap(err, "failed to list servers")
+		}
+
+		for _, server := range servers.Servers {
+			if !hasTags(server.Metadata, tags) {
+				continue
+			}
+
+			instance := Instance{Name: server.Name}
+			for _, addresses := range server.Addresses {
+				for _, addr := range addresses {
+					if addr.Version != 4 {
+						continue
+					}
+					switch {
+					case addr.Type == "floating" && instance.PublicAddress == "":
+						instance.PublicAddress = addr.Addr
+					case addr.Type != "floating" && instance.PrivateAddress == "":
+						instance.PrivateAddress = addr.Addr
+					}
+				}
+			}
+			instances = append(instances, instance)
+		}
+
+		next = ""
+		for _, link := range servers.Links {
+			if link.Rel == "next" {
+				next = link.Href
+			}
+		}
+	}
+
+	return instances, nil
+}
+
+// authenticate requests the token from Keystone and returns it, along with
+// the public compute endpoint in the region
+func (d *openstackDiscoverer) authenticate(ctx context.Context) (string, string, error) {
+	domain := map[string]string{"name": d.creds[credentials.OpenStackDomainName]}
+
+	project := map[string]interface{}{"domain": domain}
+	if id := d.creds[credentials.OpenStackTenantID]; id != "" {
+		project = map[string]interface{}{"id": id}
+	} else {
+		project["name"] = d.creds[credentials.OpenStackTenantName]
+	}
+
+	body := map[string]interface{}{
+		"auth": map[string]interface{}{
+			"identity": map[string]interface{}{
+				"methods": []string{"password"},
+				"password": map[string]interface{}{
+					"user": map[string]interface{}{
+						"name":     d.creds[credentials.OpenStackUserNameMC],
+						"domain":   domain,
+						"password": d.creds[credentials.OpenStackPassword],
+					},
+				},
+			},
+			"scope": map[string]interface{}{"project": project},
+		},
+	}
+
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return "", "", errors.WithStack(err)
+	}
+
+	authURL := strings.TrimSuffix(d.creds[credentials.OpenStackAuthURL], "/")
+	if !strings.HasSuffix(authURL, "/v3") {
+		authURL += "/v3"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, authURL+"/auth/tokens", bytes.NewReader(buf))
+	if err != nil {
+		return "", "", errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to authenticate")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", "", errors.Errorf("failed to authenticate: unexpected status %s", resp.Status)
+	}
+
+	tokenResp := struct {
+		Token struct {
+			Catalog []struct {
+				Type      string `json:"type"`
+				Endpoints []struct {
+					Interface string `json:"interface"`
+					Region    string `json:"region"`
+					URL       string `json:"url"`
+				} `json:"endpoints"`
+			} `json:"catalog"`
+		} `json:"token"`
+	}{}
+	if err = json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", "", errors.Wrap(err, "failed to decode token")
+	}
+
+	region := d.creds[credentials.OpenStackRegionName]
+	for _, service := range tokenResp.Token.Catalog {
+		if service.Type != "compute" {
+			continue
+		}
+		for _, endpoint := range service.Endpoints {
+			if endpoint.Interface == "public" && endpoint.Region == region {
+				return resp.Header.Get("X-Subject-Token"), strings.TrimSuffix(endpoint.URL, "/"), nil
+			}
+		}
+	}
+
+	return "", "", errors.Errorf("no public compute endpoint found in region %q", region)
+}
+
+func hasTags(metadata, tags map[string]string) bool {
+	for key, value := range tags {
+		if v, ok := metadata[key]; !ok || v != value {
+			return false
+		}
+	}
+
+	return true
+}
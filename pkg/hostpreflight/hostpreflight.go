@@ -0,0 +1,530 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hostpreflight checks the hosts meet the prerequisites of the
+// cluster before any changes are made to them.
+package hostpreflight
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/capacity"
+	"k8c.io/kubeone/pkg/scripts"
+	"k8c.io/kubeone/pkg/state"
+)
+
+const (
+	StatusPass = "pass"
+	StatusWarn = "warn"
+	StatusFail = "fail"
+	StatusSkip = "skip"
+
+	RoleControlPlane = "control-plane"
+	RoleStaticWorker = "static-worker"
+)
+
+const (
+	CheckSSH           = "ssh"
+	CheckSudo          = kubeoneapi.PreflightCheckSudo
+	CheckResources     = kubeoneapi.PreflightCheckResources
+	CheckSwap          = kubeoneapi.PreflightCheckSwap
+	CheckCgroup        = kubeoneapi.PreflightCheckCgroup
+	CheckTimeSync      = kubeoneapi.PreflightCheckTimeSync
+	CheckSELinux       = kubeoneapi.PreflightCheckSELinux
+	CheckAppArmor      = kubeoneapi.PreflightCheckAppArmor
+	CheckKernelModules = kubeoneapi.PreflightCheckKernelModules
+	CheckLocalPorts    = kubeoneapi.PreflightCheckLocalPorts
+	CheckPeerPorts     = kubeoneapi.PreflightCheckPeerPorts
+)
+
+// Checks are all checks in the order they're reported
+var Checks = []string{
+	CheckSSH,
+	CheckSudo,
+	CheckResources,
+	CheckSwap,
+	CheckCgroup,
+	CheckTimeSync,
+	CheckSELinux,
+	CheckAppArmor,
+	CheckKernelModules,
+	CheckLocalPorts,
+	CheckPeerPorts,
+}
+
+// Ports used by the control plane components and the kubelet
+const (
+	apiServerPort = 6443
+	kubeletPort   = 10250
+)
+
+var (
+	// kernelModules are loaded by KubeOne when installing the container runtime
+	kernelModules = []string{"overlay", "br_netfilter"}
+
+	etcdPorts = []int{2379, 2380}
+	// schedulerControllerManagerPorts are the ports of the kube-scheduler and
+	// kube-controller-manager, which are used only locally
+	schedulerControllerManagerPorts = []int{10257, 10259}
+)
+
+// Report is the result of the checks of all hosts
+type Report struct {
+	Hosts []HostReport `json:"hosts"`
+	// Passed is true if no check failed on any host
+	Passed bool `json:"passed"`
+}
+
+// HostReport is the result of the checks of a single host
+type HostReport struct {
+	PublicAddress  string   `json:"publicAddress"`
+	PrivateAddress string   `json:"privateAddress"`
+	Role           string   `json:"role"`
+	Results        []Result `json:"results"`
+}
+
+// Result is the result of a single check
+type Result struct {
+	Check   string `json:"check"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// Requirements are the prerequisites of a single host
+type Requirements struct {
+	KubernetesVersion *semver.Version
+	Docker            bool
+	CPUs              int
+	MemoryMiB         int
+	KernelModules     []string
+	// LocalPorts must not be used by other processes on the host
+	LocalPorts []int
+	// Peers are the ports on the other hosts the host must be able to
+	// connect to
+	Peers []scripts.PreflightPeer
+	// MaxClockSkew is the maximum difference between the clock of the host
+	// and the local clock
+	MaxClockSkew time.Duration
+	// Chrony is true if KubeOne installs chrony on the host, which
+	// synchronizes the clock when the host is provisioned
+	Chrony bool
+	// SELinuxMode is how KubeOne handles SELinux on the host
+	SELinuxMode kubeoneapi.SELinuxMode
+	// AppArmorMode is how KubeOne handles AppArmor on the host
+	AppArmorMode kubeoneapi.AppArmorMode
+}
+
+// Run checks all hosts in parallel and returns the report. Hosts which can't
+// be reached over SSH fail only the ssh check, other checks are skipped.
+func Run(s *state.State) (*Report, error) {
+	ver, err := semver.NewVersion(s.Cluster.Versions.Kubernetes)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid kubernetes version")
+	}
+
+	type hostRole struct {
+		host kubeoneapi.HostConfig
+		role string
+	}
+
+	hosts := []hostRole{}
+	for _, host := range s.Cluster.ControlPlane.Hosts {
+		hosts = append(hosts, hostRole{host: host, role: RoleControlPlane})
+	}
+	for _, host := range s.Cluster.StaticWorkers.Hosts {
+		hosts = append(hosts, hostRole{host: host, role: RoleStaticWorker})
+	}
+
+	report := &Report{
+		Hosts:  make([]HostReport, len(hosts)),
+		Passed: true,
+	}
+
+	var wg sync.WaitGroup
+	for i := range hosts {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			req := requirements(s.Cluster, hosts[i].host, hosts[i].role == RoleControlPlane, ver)
+			report.Hosts[i] = HostReport{
+				PublicAddress:  hosts[i].host.PublicAddress,
+				PrivateAddress: hosts[i].host.PrivateAddress,
+				Role:           hosts[i].role,
+				Results:        applyPreflightConfig(s.Cluster, checkHost(s, hosts[i].host, req)),
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, host := range report.Hosts {
+		for _, res := range host.Results {
+			if res.Status == StatusFail {
+				report.Passed = false
+			}
+		}
+	}
+
+	return report, nil
+}
+
+func checkHost(s *state.State, host kubeoneapi.HostConfig, req Requirements) []Result {
+	conn, err := s.Connector.Connect(host)
+	if err != nil {
+		return []Result{{Check: CheckSSH, Status: StatusFail, Message: err.Error()}}
+	}
+
+	script, err := scripts.HostPreflight(req.KernelModules, req.LocalPorts, req.Peers)
+	if err != nil {
+		return []Result{{Check: CheckSSH, Status: StatusFail, Message: err.Error()}}
+	}
+
+	before := time.Now()
+	stdout, stderr, _, err := conn.Exec(script)
+	after := time.Now()
+	if err != nil {
+		return []Result{{Check: CheckSSH, Status: StatusFail, Message: fmt.Sprintf("failed to run checks: %v: %s", err, stderr)}}
+	}
+
+	facts, err := ParseFacts(stdout)
+	if err != nil {
+		return []Result{{Check: CheckSSH, Status: StatusFail, Message: fmt.Sprintf("failed to parse checks output: %v", err)}}
+	}
+	if skew, skewErr := clockSkew(facts["time"], before, after); skewErr == nil {
+		facts["clock_skew_ms"] = strconv.FormatInt(skew.Milliseconds(), 10)
+	}
+
+	return append([]Result{{Check: CheckSSH, Status: StatusPass}}, Evaluate(req, facts)...)
+}
+
+// applyPreflightConfig marks the checks skipped in the manifest as skipped,
+// and failures of the warning-only checks as warnings
+func applyPreflightConfig(cluster *kubeoneapi.KubeOneCluster, results []Result) []Result {
+	for i := range results {
+		switch {
+		case cluster.PreflightSkipped(results[i].Check):
+			results[i] = Result{Check: results[i].Check, Status: StatusSkip}
+		case cluster.PreflightWarnOnly(results[i].Check) && results[i].Status == StatusFail:
+			results[i].Status = StatusWarn
+		}
+	}
+
+	return results
+}
+
+// requirements returns the prerequisites of the host. The resources of the
+// control plane hosts follow the cluster size if it's declared, or the
+// kubeadm minimum otherwise.
+func requirements(cluster *kubeoneapi.KubeOneCluster, host kubeoneapi.HostConfig, controlPlane bool, ver *semver.Version) Requirements {
+	req := Requirements{
+		KubernetesVersion: ver,
+		Docker:            cluster.ContainerRuntime.Docker != nil,
+		CPUs:              1,
+		MemoryMiB:         1024,
+		KernelModules:     kernelModules,
+		LocalPorts:        []int{kubeletPort},
+		MaxClockSkew:      cluster.MaxClockSkew(),
+		Chrony:            cluster.ChronyEnabled(),
+		SELinuxMode:       cluster.SELinuxMode(),
+		AppArmorMode:      cluster.AppArmorMode(),
+	}
+
+	for _, cp := range cluster.ControlPlane.Hosts {
+		if cp.PrivateAddress == host.PrivateAddress {
+			continue
+		}
+
+		req.Peers = append(req.Peers, scripts.PreflightPeer{Host: cp.PrivateAddress, Port: apiServerPort})
+		if controlPlane {
+			for _, port := range append([]int{kubeletPort}, etcdPorts...) {
+				req.Peers = append(req.Peers, scripts.PreflightPeer{Host: cp.PrivateAddress, Port: port})
+			}
+		}
+	}
+
+	if !controlPlane {
+		return req
+	}
+
+	// The API server connects to the kubelet on all hosts
+	for _, worker := range cluster.StaticWorkers.Hosts {
+		req.Peers = append(req.Peers, scripts.PreflightPeer{Host: worker.PrivateAddress, Port: kubeletPort})
+	}
+
+	req.LocalPorts = append([]int{apiServerPort}, etcdPorts...)
+	req.LocalPorts = append(req.LocalPorts, kubeletPort)
+	req.LocalPorts = append(req.LocalPorts, schedulerControllerManagerPorts...)
+
+	req.CPUs = 2
+	req.MemoryMiB = 1700
+	if size := cluster.ClusterSize; size != nil {
+		tier := capacity.ForClusterSize(size.Nodes, size.Pods)
+		req.CPUs = tier.CPUs
+		// The kernel reserves part of the memory, so MemTotal is always a bit
+		// lower than the memory of the host
+		req.MemoryMiB = tier.MemoryMiB * 9 / 10
+	}
+
+	return req
+}
+
+// ParseFacts parses the key=value lines printed by scripts.HostPreflight
+func ParseFacts(output string) (map[string]string, error) {
+	facts := map[string]string{}
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			return nil, errors.Errorf("invalid line %q", line)
+		}
+		facts[kv[0]] = kv[1]
+	}
+
+	return facts, scanner.Err()
+}
+
+// Evaluate returns the results of all checks, except the ssh check, based on
+// the facts collected from the host
+func Evaluate(req Requirements, facts map[string]string) []Result {
+	return []Result{
+		checkSudo(facts),
+		checkResources(req, facts),
+		checkSwap(facts),
+		checkCgroup(req, facts),
+		checkTimeSync(req, facts),
+		checkSELinux(req, facts),
+		checkAppArmor(req, facts),
+		checkKernelModules(req, facts),
+		checkLocalPorts(req, facts),
+		checkPeerPorts(req, facts),
+	}
+}
+
+func checkSudo(facts map[string]string) Result {
+	if facts["sudo"] != "yes" {
+		return Result{Check: CheckSudo, Status: StatusFail, Message: "passwordless sudo is required"}
+	}
+
+	return Result{Check: CheckSudo, Status: StatusPass}
+}
+
+func checkResources(req Requirements, facts map[string]string) Result {
+	cpus, err := strconv.Atoi(facts["cpus"])
+	if err != nil {
+		return Result{Check: CheckResources, Status: StatusFail, Message: "unable to determine number of CPUs"}
+	}
+
+	memoryKiB, err := strconv.Atoi(facts["memory_kib"])
+	if err != nil {
+		return Result{Check: CheckResources, Status: StatusFail, Message: "unable to determine memory"}
+	}
+
+	unmet := []string{}
+	if cpus < req.CPUs {
+		unmet = append(unmet, fmt.Sprintf("%d CPUs, at least %d required", cpus, req.CPUs))
+	}
+	if memoryKiB/1024 < req.MemoryMiB {
+		unmet = append(unmet, fmt.Sprintf("%d MiB memory, at least %d MiB required", memoryKiB/1024, req.MemoryMiB))
+	}
+
+	if len(unmet) > 0 {
+		return Result{Check: CheckResources, Status: StatusFail, Message: strings.Join(unmet, "; ")}
+	}
+
+	return Result{Check: CheckResources, Status: StatusPass}
+}
+
+func checkSwap(facts map[string]string) Result {
+	if swap := facts["swap_kib"]; swap != "" && swap != "0" {
+		return Result{Check: CheckSwap, Status: StatusWarn, Message: fmt.Sprintf("%s KiB swap is enabled, it will be disabled by KubeOne", swap)}
+	}
+
+	return Result{Check: CheckSwap, Status: StatusPass}
+}
+
+func checkCgroup(req Requirements, facts map[string]string) Result {
+	if facts["cgroup"] != "v2" {
+		return Result{Check: CheckCgroup, Status: StatusPass}
+	}
+
+	if req.KubernetesVersion.LessThan(semver.MustParse("1.19")) {
+		return Result{Check: CheckCgroup, Status: StatusFail, Message: "cgroup v2 requires Kubernetes 1.19 or newer"}
+	}
+	if req.Docker {
+		return Result{Check: CheckCgroup, Status: StatusWarn, Message: "cgroup v2 is supported only by Docker 20.10 or newer, containerd is recommended"}
+	}
+
+	return Result{Check: CheckCgroup, Status: StatusPass}
+}
+
+// clockSkew returns the difference between the host time, printed as
+// seconds since the epoch, and the local time window in which the host time
+// was read. The result is negative if the host clock is behind.
+func clockSkew(hostTime string, before, after time.Time) (time.Duration, error) {
+	secs, err := strconv.ParseFloat(hostTime, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid host time %q", hostTime)
+	}
+
+	t := time.Unix(0, int64(secs*float64(time.Second)))
+	switch {
+	case t.Before(before):
+		return t.Sub(before), nil
+	case t.After(after):
+		return t.Sub(after), nil
+	}
+
+	return 0, nil
+}
+
+func checkTimeSync(req Requirements, facts map[string]string) Result {
+	// chrony is installed and steps the clock when the host is provisioned
+	failStatus := StatusFail
+	if req.Chrony {
+		failStatus = StatusWarn
+	}
+
+	if skewMs, err := strconv.ParseInt(facts["clock_skew_ms"], 10, 64); err == nil {
+		skew := time.Duration(skewMs) * time.Millisecond
+		if skew < 0 {
+			skew = -skew
+		}
+		if req.MaxClockSkew > 0 && skew > req.MaxClockSkew {
+			return Result{Check: CheckTimeSync, Status: failStatus, Message: fmt.Sprintf("system clock differs by %s from the local clock, at most %s allowed", skew, req.MaxClockSkew)}
+		}
+	}
+
+	switch facts["time_synced"] {
+	case "yes":
+		return Result{Check: CheckTimeSync, Status: StatusPass}
+	case "no":
+		if req.Chrony {
+			return Result{Check: CheckTimeSync, Status: StatusWarn, Message: "system clock is not synchronized, chrony will be installed by KubeOne"}
+		}
+
+		return Result{Check: CheckTimeSync, Status: StatusFail, Message: "system clock is not synchronized, certificates and etcd require synchronized time"}
+	}
+
+	return Result{Check: CheckTimeSync, Status: StatusWarn, Message: "unable to determine if system clock is synchronized"}
+}
+
+func checkSELinux(req Requirements, facts map[string]string) Result {
+	if facts["selinux"] != "enforcing" {
+		return Result{Check: CheckSELinux, Status: StatusPass}
+	}
+
+	switch req.SELinuxMode {
+	case kubeoneapi.SELinuxModePermissive:
+		return Result{Check: CheckSELinux, Status: StatusPass, Message: "SELinux is enforcing, it will be switched to permissive by KubeOne"}
+	case kubeoneapi.SELinuxModeEnforcing:
+		return Result{Check: CheckSELinux, Status: StatusPass}
+	}
+
+	return Result{
+		Check:  CheckSELinux,
+		Status: StatusFail,
+		Message: "SELinux is enforcing, but not managed by KubeOne; set .securityModules.selinux to enforcing or permissive, " +
+			"or run 'setenforce 0' and set SELINUX=permissive in /etc/selinux/config",
+	}
+}
+
+func checkAppArmor(req Requirements, facts map[string]string) Result {
+	if facts["apparmor"] != "enabled" || facts["apparmor_parser"] == "yes" {
+		return Result{Check: CheckAppArmor, Status: StatusPass}
+	}
+
+	if req.AppArmorMode == kubeoneapi.AppArmorModeConfigure && strings.Contains(facts["os_family"], "debian") {
+		return Result{Check: CheckAppArmor, Status: StatusPass, Message: "apparmor_parser is missing, the apparmor package will be installed by KubeOne"}
+	}
+
+	return Result{
+		Check:   CheckAppArmor,
+		Status:  StatusFail,
+		Message: "AppArmor is enabled, but apparmor_parser required by the container runtime is missing; install the apparmor package",
+	}
+}
+
+func checkKernelModules(req Requirements, facts map[string]string) Result {
+	missing := []string{}
+	for _, module := range req.KernelModules {
+		if facts["module."+module] != "yes" {
+			missing = append(missing, module)
+		}
+	}
+
+	if len(missing) > 0 {
+		return Result{Check: CheckKernelModules, Status: StatusFail, Message: fmt.Sprintf("missing kernel modules: %s", strings.Join(missing, ", "))}
+	}
+
+	return Result{Check: CheckKernelModules, Status: StatusPass}
+}
+
+func checkLocalPorts(req Requirements, facts map[string]string) Result {
+	// Ports are used by the cluster components on the provisioned hosts
+	if facts["provisioned"] == "yes" {
+		return Result{Check: CheckLocalPorts, Status: StatusPass, Message: "host is already provisioned"}
+	}
+
+	used := []string{}
+	for _, port := range req.LocalPorts {
+		if facts[fmt.Sprintf("port.%d", port)] == "used" {
+			used = append(used, strconv.Itoa(port))
+		}
+	}
+
+	if len(used) > 0 {
+		return Result{Check: CheckLocalPorts, Status: StatusFail, Message: fmt.Sprintf("ports already in use: %s", strings.Join(used, ", "))}
+	}
+
+	return Result{Check: CheckLocalPorts, Status: StatusPass}
+}
+
+func checkPeerPorts(req Requirements, facts map[string]string) Result {
+	failed := []string{}
+	for _, peer := range req.Peers {
+		addr := fmt.Sprintf("%s:%d", peer.Host, peer.Port)
+		status := facts["peer."+addr]
+		if status == "" {
+			status = "unknown"
+		}
+		if status != "reachable" {
+			failed = append(failed, fmt.Sprintf("%s (%s)", addr, status))
+		}
+	}
+	sort.Strings(failed)
+
+	if len(failed) > 0 {
+		return Result{Check: CheckPeerPorts, Status: StatusFail, Message: fmt.Sprintf("unable to reach: %s", strings.Join(failed, ", "))}
+	}
+
+	return Result{Check: CheckPeerPorts, Status: StatusPass}
+}
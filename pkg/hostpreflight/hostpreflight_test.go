@@ -0,0 +1,346 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostpreflight
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/scripts"
+)
+
+func TestParseFacts(t *testing.T) {
+	facts, err := ParseFacts("sudo=yes\n\ncpus=4\npeer.10.0.0.2:6443=reachable\n")
+	if err != nil {
+		t.Fatalf("ParseFacts() error = %v", err)
+	}
+
+	want := map[string]string{"sudo": "yes", "cpus": "4", "peer.10.0.0.2:6443": "reachable"}
+	for k, v := range want {
+		if facts[k] != v {
+			t.Errorf("facts[%q] = %q, want %q", k, facts[k], v)
+		}
+	}
+
+	if _, err = ParseFacts("invalid"); err == nil {
+		t.Error("ParseFacts() expected error for invalid line")
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	req := Requirements{
+		KubernetesVersion: semver.MustParse("1.22.6"),
+		CPUs:              2,
+		MemoryMiB:         1700,
+		KernelModules:     []string{"overlay", "br_netfilter"},
+		LocalPorts:        []int{6443, 10250},
+		Peers:             []scripts.PreflightPeer{{Host: "10.0.0.2", Port: 6443}},
+		MaxClockSkew:      time.Second,
+		SELinuxMode:       kubeoneapi.SELinuxModePermissive,
+		AppArmorMode:      kubeoneapi.AppArmorModeConfigure,
+	}
+
+	healthy := map[string]string{
+		"sudo":                "yes",
+		"provisioned":         "no",
+		"cpus":                "2",
+		"memory_kib":          "2000000",
+		"swap_kib":            "0",
+		"cgroup":              "v1",
+		"time_synced":         "yes",
+		"clock_skew_ms":       "0",
+		"os_family":           "debian",
+		"selinux":             "disabled",
+		"apparmor":            "enabled",
+		"apparmor_parser":     "yes",
+		"module.overlay":      "yes",
+		"module.br_netfilter": "yes",
+		"port.6443":           "free",
+		"port.10250":          "free",
+		"peer.10.0.0.2:6443":  "reachable",
+	}
+
+	tests := []struct {
+		name       string
+		req        func(Requirements) Requirements
+		facts      map[string]string
+		wantStatus map[string]string
+	}{
+		{
+			name:  "all pass",
+			facts: map[string]string{},
+		},
+		{
+			name:       "no sudo",
+			facts:      map[string]string{"sudo": "no"},
+			wantStatus: map[string]string{CheckSudo: StatusFail},
+		},
+		{
+			name:       "not enough CPUs",
+			facts:      map[string]string{"cpus": "1"},
+			wantStatus: map[string]string{CheckResources: StatusFail},
+		},
+		{
+			name:       "swap enabled",
+			facts:      map[string]string{"swap_kib": "1024"},
+			wantStatus: map[string]string{CheckSwap: StatusWarn},
+		},
+		{
+			name: "cgroup v2 on old kubernetes",
+			req: func(r Requirements) Requirements {
+				r.KubernetesVersion = semver.MustParse("1.18.20")
+				return r
+			},
+			facts:      map[string]string{"cgroup": "v2"},
+			wantStatus: map[string]string{CheckCgroup: StatusFail},
+		},
+		{
+			name: "cgroup v2 with docker",
+			req: func(r Requirements) Requirements {
+				r.Docker = true
+				return r
+			},
+			facts:      map[string]string{"cgroup": "v2"},
+			wantStatus: map[string]string{CheckCgroup: StatusWarn},
+		},
+		{
+			name:       "time not synchronized",
+			facts:      map[string]string{"time_synced": "no"},
+			wantStatus: map[string]string{CheckTimeSync: StatusFail},
+		},
+		{
+			name: "time not synchronized with chrony",
+			req: func(r Requirements) Requirements {
+				r.Chrony = true
+				return r
+			},
+			facts:      map[string]string{"time_synced": "no"},
+			wantStatus: map[string]string{CheckTimeSync: StatusWarn},
+		},
+		{
+			name:       "clock skew",
+			facts:      map[string]string{"clock_skew_ms": "-2500"},
+			wantStatus: map[string]string{CheckTimeSync: StatusFail},
+		},
+		{
+			name:  "clock skew within limit",
+			facts: map[string]string{"clock_skew_ms": "800"},
+		},
+		{
+			name:  "selinux enforcing switched to permissive",
+			facts: map[string]string{"os_family": "rhel fedora", "selinux": "enforcing", "apparmor": "disabled"},
+		},
+		{
+			name: "selinux enforcing configured",
+			req: func(r Requirements) Requirements {
+				r.SELinuxMode = kubeoneapi.SELinuxModeEnforcing
+				return r
+			},
+			facts: map[string]string{"os_family": "rhel fedora", "selinux": "enforcing", "apparmor": "disabled"},
+		},
+		{
+			name: "selinux enforcing unmanaged",
+			req: func(r Requirements) Requirements {
+				r.SELinuxMode = kubeoneapi.SELinuxModeUnmanaged
+				return r
+			},
+			facts:      map[string]string{"os_family": "rhel fedora", "selinux": "enforcing", "apparmor": "disabled"},
+			wantStatus: map[string]string{CheckSELinux: StatusFail},
+		},
+		{
+			name:  "apparmor parser installed by kubeone",
+			facts: map[string]string{"apparmor_parser": "no"},
+		},
+		{
+			name: "apparmor parser missing unmanaged",
+			req: func(r Requirements) Requirements {
+				r.AppArmorMode = kubeoneapi.AppArmorModeUnmanaged
+				return r
+			},
+			facts:      map[string]string{"apparmor_parser": "no"},
+			wantStatus: map[string]string{CheckAppArmor: StatusFail},
+		},
+		{
+			name:       "apparmor parser missing on non-debian host",
+			facts:      map[string]string{"os_family": "suse", "apparmor_parser": "no"},
+			wantStatus: map[string]string{CheckAppArmor: StatusFail},
+		},
+		{
+			name:       "missing kernel module",
+			facts:      map[string]string{"module.br_netfilter": "no"},
+			wantStatus: map[string]string{CheckKernelModules: StatusFail},
+		},
+		{
+			name:       "port in use",
+			facts:      map[string]string{"port.6443": "used"},
+			wantStatus: map[string]string{CheckLocalPorts: StatusFail},
+		},
+		{
+			name:  "port in use on provisioned host",
+			facts: map[string]string{"port.6443": "used", "provisioned": "yes"},
+		},
+		{
+			name:       "peer port filtered",
+			facts:      map[string]string{"peer.10.0.0.2:6443": "filtered"},
+			wantStatus: map[string]string{CheckPeerPorts: StatusFail},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r := req
+			if tt.req != nil {
+				r = tt.req(r)
+			}
+
+			facts := map[string]string{}
+			for k, v := range healthy {
+				facts[k] = v
+			}
+			for k, v := range tt.facts {
+				facts[k] = v
+			}
+
+			for _, res := range Evaluate(r, facts) {
+				want, ok := tt.wantStatus[res.Check]
+				if !ok {
+					want = StatusPass
+				}
+				if res.Status != want {
+					t.Errorf("check %s status = %q (%s), want %q", res.Check, res.Status, res.Message, want)
+				}
+			}
+		})
+	}
+}
+
+func TestClockSkew(t *testing.T) {
+	before := time.Unix(1600000000, 0)
+	after := before.Add(200 * time.Millisecond)
+
+	tests := []struct {
+		name     string
+		hostTime string
+		want     time.Duration
+		wantErr  bool
+	}{
+		{
+			name:     "within window",
+			hostTime: "1600000000.100000000",
+			want:     0,
+		},
+		{
+			name:     "behind",
+			hostTime: "1599999998.000000000",
+			want:     -2 * time.Second,
+		},
+		{
+			name:     "ahead",
+			hostTime: "1600000003.200000000",
+			want:     3 * time.Second,
+		},
+		{
+			name:     "invalid",
+			hostTime: "unknown",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := clockSkew(tt.hostTime, before, after)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("clockSkew() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			// Parsing the host time as float loses sub-microsecond precision
+			if diff := got - tt.want; diff > time.Millisecond || diff < -time.Millisecond {
+				t.Errorf("clockSkew() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequirements(t *testing.T) {
+	cluster := &kubeoneapi.KubeOneCluster{
+		ControlPlane: kubeoneapi.ControlPlaneConfig{
+			Hosts: []kubeoneapi.HostConfig{
+				{PrivateAddress: "10.0.0.1"},
+				{PrivateAddress: "10.0.0.2"},
+			},
+		},
+		StaticWorkers: kubeoneapi.StaticWorkersConfig{
+			Hosts: []kubeoneapi.HostConfig{
+				{PrivateAddress: "10.0.0.3"},
+			},
+		},
+		ClusterSize: &kubeoneapi.ClusterSizeConfig{Nodes: 50},
+	}
+	ver := semver.MustParse("1.22.6")
+
+	cp := requirements(cluster, cluster.ControlPlane.Hosts[0], true, ver)
+	wantPeers := []scripts.PreflightPeer{
+		{Host: "10.0.0.2", Port: 6443},
+		{Host: "10.0.0.2", Port: 10250},
+		{Host: "10.0.0.2", Port: 2379},
+		{Host: "10.0.0.2", Port: 2380},
+		{Host: "10.0.0.3", Port: 10250},
+	}
+	if len(cp.Peers) != len(wantPeers) {
+		t.Fatalf("control plane peers = %v, want %v", cp.Peers, wantPeers)
+	}
+	for i := range wantPeers {
+		if cp.Peers[i] != wantPeers[i] {
+			t.Errorf("control plane peer %d = %v, want %v", i, cp.Peers[i], wantPeers[i])
+		}
+	}
+	if cp.CPUs != 4 {
+		t.Errorf("control plane CPUs = %d, want 4 for the declared cluster size", cp.CPUs)
+	}
+
+	worker := requirements(cluster, cluster.StaticWorkers.Hosts[0], false, ver)
+	if len(worker.Peers) != 2 || len(worker.LocalPorts) != 1 {
+		t.Errorf("worker peers = %v, local ports = %v, want API server of both control plane hosts and kubelet port", worker.Peers, worker.LocalPorts)
+	}
+}
+
+func TestApplyPreflightConfig(t *testing.T) {
+	cluster := &kubeoneapi.KubeOneCluster{
+		Preflight: &kubeoneapi.PreflightConfig{
+			Skip:     []string{CheckTimeSync},
+			WarnOnly: []string{CheckSwap, CheckPeerPorts},
+		},
+	}
+
+	results := applyPreflightConfig(cluster, []Result{
+		{Check: CheckSudo, Status: StatusFail},
+		{Check: CheckTimeSync, Status: StatusFail, Message: "not synchronized"},
+		{Check: CheckSwap, Status: StatusWarn},
+		{Check: CheckPeerPorts, Status: StatusFail},
+	})
+
+	want := []string{StatusFail, StatusSkip, StatusWarn, StatusWarn}
+	for i, res := range results {
+		if res.Status != want[i] {
+			t.Errorf("check %s status = %q, want %q", res.Check, res.Status, want[i])
+		}
+	}
+}
@@ -0,0 +1,298 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jsonschema generates the JSON Schema of the versioned API types from
+// the Go types, so editors and CI pipelines can validate and autocomplete the
+// KubeOneCluster manifests without hand-maintaining the schema.
+package jsonschema
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Draft is the JSON Schema draft used for the generated schemas
+	Draft = "http://json-schema.org/draft-07/schema#"
+
+	definitionsRef = "#/definitions/"
+)
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+	// knownTypes are the types that implement custom JSON marshaling and
+	// therefore can't be reflected
+	knownTypes = map[reflect.Type]*Schema{
+		reflect.TypeOf(json.RawMessage{}): {},
+		reflect.TypeOf(metav1.Duration{}): {Type: "string", Description: "Duration, e.g. 30s, 10m or 1h30m"},
+		reflect.TypeOf(metav1.Time{}):     {Type: "string", Format: "date-time"},
+		// Quantities can be given both as strings and as numbers
+		reflect.TypeOf(resource.Quantity{}): {Description: "Quantity, e.g. 100m, 128Mi or 2"},
+	}
+)
+
+// Schema is the subset of the JSON Schema used by the generated schemas
+type Schema struct {
+	SchemaURI            string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Definitions          map[string]*Schema `json:"definitions,omitempty"`
+}
+
+// Options of the generated schema
+type Options struct {
+	// ID is the $id of the schema
+	ID string
+	// Title of the schema
+	Title string
+	// APIVersion and Kind, if set, are required to have the given values
+	APIVersion string
+	Kind       string
+}
+
+// Generate generates the JSON Schema for the given object. Named struct types
+// are put in the definitions and referenced, the top-level object is inlined.
+// Unknown properties are not allowed, the same as the strict decoding used
+// when loading the manifests.
+func Generate(obj interface{}, opts Options) (*Schema, error) {
+	t := reflect.TypeOf(obj)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, errors.Errorf("expected struct, got %v", t)
+	}
+
+	g := &generator{definitions: map[string]*Schema{}}
+
+	root, err := g.structSchema(t)
+	if err != nil {
+		return nil, err
+	}
+
+	root.SchemaURI = Draft
+	root.ID = opts.ID
+	root.Title = opts.Title
+	if len(g.definitions) > 0 {
+		root.Definitions = g.definitions
+	}
+
+	if opts.APIVersion != "" {
+		root.Properties["apiVersion"] = &Schema{Type: "string", Enum: []string{opts.APIVersion}}
+		root.Required = appendUnique(root.Required, "apiVersion")
+	}
+	if opts.Kind != "" {
+		root.Properties["kind"] = &Schema{Type: "string", Enum: []string{opts.Kind}}
+		root.Required = appendUnique(root.Required, "kind")
+	}
+
+	return root, nil
+}
+
+type generator struct {
+	definitions map[string]*Schema
+}
+
+func (g *generator) schemaFor(t reflect.Type) (*Schema, error) {
+	if known, ok := knownTypes[t]; ok {
+		s := *known
+		return &s, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.schemaFor(t.Elem())
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}, nil
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+	case reflect.Interface:
+		return &Schema{}, nil
+	}
+
+	if t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType) ||
+		t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType) {
+		return nil, errors.Errorf("type %s implements custom marshaling and is not known", t)
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// encoding/json encodes byte slices as base64 strings
+			return &Schema{Type: "string", Format: "byte"}, nil
+		}
+		items, err := g.schemaFor(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, errors.Errorf("map key of %s must be string", t)
+		}
+		values, err := g.schemaFor(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		return g.definitionRef(t)
+	}
+
+	return nil, errors.Errorf("unsupported type %s", t)
+}
+
+// definitionRef adds the struct type to the definitions, if not already
+// added, and returns the reference to it
+func (g *generator) definitionRef(t reflect.Type) (*Schema, error) {
+	name := definitionName(t)
+	ref := &Schema{Ref: definitionsRef + name}
+
+	if _, ok := g.definitions[name]; ok {
+		return ref, nil
+	}
+
+	// Reserve the name before generating the schema to support recursive
+	// types
+	g.definitions[name] = &Schema{}
+	s, err := g.structSchema(t)
+	if err != nil {
+		return nil, err
+	}
+	g.definitions[name] = s
+
+	return ref, nil
+}
+
+func (g *generator) structSchema(t reflect.Type) (*Schema, error) {
+	s := &Schema{
+		Type:                 "object",
+		Properties:           map[string]*Schema{},
+		AdditionalProperties: false,
+	}
+
+	if err := g.addFields(s, t); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (g *generator) addFields(s *Schema, t reflect.Type) error {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			// unexported field
+			continue
+		}
+
+		name, inline, skip := parseJSONTag(f)
+		if skip {
+			continue
+		}
+
+		if inline {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if err := g.addFields(s, ft); err != nil {
+				return err
+			}
+			continue
+		}
+
+		fs, err := g.schemaFor(f.Type)
+		if err != nil {
+			return errors.Wrapf(err, "field %s.%s", t.Name(), f.Name)
+		}
+		s.Properties[name] = fs
+	}
+
+	return nil
+}
+
+// parseJSONTag returns the property name of the field, whether the field is
+// inlined in the parent object and whether the field is skipped
+func parseJSONTag(f reflect.StructField) (string, bool, bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+
+	for _, opt := range parts[1:] {
+		if opt == "inline" {
+			return "", true, false
+		}
+	}
+
+	if name == "" {
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			return "", true, false
+		}
+		name = f.Name
+	}
+
+	return name, false, false
+}
+
+// definitionName returns the name of the type qualified with the last two
+// elements of its package path, e.g. kubeone.v1beta1.HostConfig or
+// core.v1.Taint
+func definitionName(t reflect.Type) string {
+	parts := strings.Split(t.PkgPath(), "/")
+	if len(parts) > 2 {
+		parts = parts[len(parts)-2:]
+	}
+
+	return strings.Trim(strings.Join(append(parts, t.Name()), "."), ".")
+}
+
+func appendUnique(list []string, item string) []string {
+	for _, i := range list {
+		if i == item {
+			return list
+		}
+	}
+
+	return append(list, item)
+}
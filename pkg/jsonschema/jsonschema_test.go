@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	kubeonev1beta1 "k8c.io/kubeone/pkg/apis/kubeone/v1beta1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type testMeta struct {
+	Kind string `json:"kind"`
+}
+
+type testNode struct {
+	Name     string      `json:"name"`
+	Children []*testNode `json:"children,omitempty"`
+}
+
+type testObject struct {
+	testMeta `json:",inline"`
+
+	Enabled  bool              `json:"enabled"`
+	Replicas *int              `json:"replicas,omitempty"`
+	Ratio    float64           `json:"ratio"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Data     []byte            `json:"data,omitempty"`
+	Raw      json.RawMessage   `json:"raw,omitempty"`
+	Timeout  metav1.Duration   `json:"timeout"`
+	Root     testNode          `json:"root"`
+	Ignored  string            `json:"-"`
+	unexp    string
+}
+
+func TestGenerate(t *testing.T) {
+	schema, err := Generate(&testObject{}, Options{APIVersion: "test.io/v1"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	expectedProperties := map[string]*Schema{
+		"apiVersion": {Type: "string", Enum: []string{"test.io/v1"}},
+		"kind":       {Type: "string"},
+		"enabled":    {Type: "boolean"},
+		"replicas":   {Type: "integer"},
+		"ratio":      {Type: "number"},
+		"labels":     {Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+		"data":       {Type: "string", Format: "byte"},
+		"raw":        {},
+		"timeout":    {Type: "string", Description: "Duration, e.g. 30s, 10m or 1h30m"},
+		"root":       {Ref: "#/definitions/pkg.jsonschema.testNode"},
+	}
+	if !reflect.DeepEqual(schema.Properties, expectedProperties) {
+		got, _ := json.MarshalIndent(schema.Properties, "", "  ")
+		t.Errorf("Generate() properties = %s", got)
+	}
+
+	if schema.AdditionalProperties != false {
+		t.Errorf("Generate() additionalProperties = %v, expected false", schema.AdditionalProperties)
+	}
+	if !reflect.DeepEqual(schema.Required, []string{"apiVersion"}) {
+		t.Errorf("Generate() required = %v, expected [apiVersion]", schema.Required)
+	}
+
+	node, ok := schema.Definitions["pkg.jsonschema.testNode"]
+	if !ok {
+		t.Fatalf("Generate() definitions = %v, expected pkg.jsonschema.testNode", schema.Definitions)
+	}
+	expectedChildren := &Schema{Type: "array", Items: &Schema{Ref: "#/definitions/pkg.jsonschema.testNode"}}
+	if !reflect.DeepEqual(node.Properties["children"], expectedChildren) {
+		t.Errorf("Generate() recursive definition children = %+v", node.Properties["children"])
+	}
+}
+
+func TestGenerateNonStruct(t *testing.T) {
+	if _, err := Generate("string", Options{}); err == nil {
+		t.Error("Generate() expected error for non-struct object")
+	}
+}
+
+func TestGenerateKubeOneCluster(t *testing.T) {
+	schema, err := Generate(&kubeonev1beta1.KubeOneCluster{}, Options{
+		APIVersion: kubeonev1beta1.SchemeGroupVersion.String(),
+		Kind:       "KubeOneCluster",
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	for _, property := range []string{"apiVersion", "kind", "name", "controlPlane", "cloudProvider", "versions"} {
+		if _, ok := schema.Properties[property]; !ok {
+			t.Errorf("Generate() property %q is missing", property)
+		}
+	}
+
+	hostConfig, ok := schema.Definitions["kubeone.v1beta1.HostConfig"]
+	if !ok {
+		t.Fatal("Generate() definition kubeone.v1beta1.HostConfig is missing")
+	}
+	if _, ok := hostConfig.Properties["isLeader"]; !ok {
+		t.Error("Generate() property isLeader of HostConfig is missing")
+	}
+	if _, ok := hostConfig.Properties["ID"]; ok {
+		t.Error("Generate() property ID of HostConfig must be skipped")
+	}
+}
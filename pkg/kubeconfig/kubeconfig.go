@@ -17,11 +17,14 @@ limitations under the License.
 package kubeconfig
 
 import (
+	"context"
 	"io/fs"
+	"net"
 	"os"
 
 	"github.com/pkg/errors"
 
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
 	"k8c.io/kubeone/pkg/ssh"
 	"k8c.io/kubeone/pkg/ssh/sshiofs"
 	"k8c.io/kubeone/pkg/state"
@@ -33,7 +36,17 @@ import (
 
 // Download downloads Kubeconfig over SSH
 func Download(s *state.State) ([]byte, error) {
-	// connect to host
+	conn, err := connectLeader(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return CatKubernetesAdminConf(conn)
+}
+
+// connectLeader connects to the leader, or to the first reachable control
+// plane host if the leader is not reachable
+func connectLeader(s *state.State) (ssh.Connection, error) {
 	host, err := s.Cluster.Leader()
 	if err != nil {
 		return nil, err
@@ -41,10 +54,41 @@ func Download(s *state.State) ([]byte, error) {
 
 	conn, err := s.Connector.Connect(host)
 	if err != nil {
-		return nil, err
+		// The leader is not reachable, try the other control plane hosts and
+		// use the first reachable one as the leader
+		fallback, fallbackConn, fallbackErr := connectFollower(s, host)
+		if fallbackErr != nil {
+			return nil, err
+		}
+
+		s.Logger.Warnf("Leader %q is not reachable, using %q as the leader instead", host.Hostname, fallback.Hostname)
+		if err = s.Cluster.SetLeader(fallback.Hostname); err != nil {
+			return nil, err
+		}
+		conn = fallbackConn
 	}
 
-	return CatKubernetesAdminConf(conn)
+	return conn, nil
+}
+
+// connectFollower connects to the first reachable control plane host other
+// than the leader
+func connectFollower(s *state.State, leader kubeoneapi.HostConfig) (kubeoneapi.HostConfig, ssh.Connection, error) {
+	for _, host := range s.Cluster.ControlPlane.Hosts {
+		if host.ID == leader.ID {
+			continue
+		}
+
+		conn, err := s.Connector.Connect(host)
+		if err != nil {
+			s.Logger.Debugf("Control plane host %q is not reachable: %v", host.Hostname, err)
+			continue
+		}
+
+		return host, conn, nil
+	}
+
+	return kubeoneapi.HostConfig{}, nil, errors.New("no reachable control plane host found")
 }
 
 func CatKubernetesAdminConf(conn ssh.Connection) ([]byte, error) {
@@ -69,12 +113,34 @@ func BuildKubernetesClientset(s *state.State) error {
 		Deduplicate: true,
 	})
 
-	tunn, err := s.Connector.Tunnel(s.Cluster.RandomHost())
-	if err != nil {
-		return errors.Wrap(err, "failed to get SSH tunnel")
+	host := s.Cluster.RandomHost()
+	if _, err = s.Connector.Tunnel(host); err != nil {
+		// Fall back to the leader, which is known to be reachable at this point
+		leader, leaderErr := s.Cluster.Leader()
+		if leaderErr != nil || leader.ID == host.ID {
+			return errors.Wrap(err, "failed to get SSH tunnel")
+		}
+		if _, err = s.Connector.Tunnel(leader); err != nil {
+			return errors.Wrap(err, "failed to get SSH tunnel")
+		}
+		host = leader
 	}
 
-	s.RESTConfig.Dial = tunn.TunnelTo
+	// The tunnel is looked up on each dial, so the new connection is used
+	// if the host was rebooted
+	connector := s.Connector
+	s.RESTConfig.Dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+		tunn, err := connector.Tunnel(host)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get SSH tunnel")
+		}
+
+		return tunn.TunnelTo(ctx, network, address)
+	}
+
+	if s.Audit != nil {
+		s.RESTConfig.WrapTransport = s.Audit.WrapTransport
+	}
 
 	return errors.WithStack(HackIssue321InitDynamicClient(s))
 }
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+
+	clientcmdapiv1 "k8s.io/client-go/tools/clientcmd/api/v1"
+)
+
+const (
+	oidcUserName = "oidc"
+	// kubeloginCommand is the kubectl plugin (https://github.com/int128/kubelogin)
+	// used to log in to the OpenID Connect provider and obtain the ID token
+	kubeloginCommand = "kubectl"
+	execAPIVersion   = "client.authentication.k8s.io/v1beta1"
+)
+
+// OIDCOptions are the options of the OpenID Connect kubeconfig, which are not
+// part of the cluster configuration
+type OIDCOptions struct {
+	// ClientSecret is the secret of the OpenID Connect client, required only
+	// by the confidential clients
+	ClientSecret string
+	// ExtraScopes are requested in addition to the openid scope. If empty,
+	// the email and groups scopes are requested if the username and groups
+	// claims are used.
+	ExtraScopes []string
+}
+
+// OIDC converts the admin kubeconfig to the kubeconfig authenticating the
+// user against the OpenID Connect provider of the cluster, using the
+// kubelogin exec plugin. The admin credentials are not included, so the
+// kubeconfig is safe to be handed to the cluster users.
+func OIDC(adminKubeconfig []byte, clusterName string, oidc kubeoneapi.OpenIDConnectConfig, opts OIDCOptions) ([]byte, error) {
+	if oidc.IssuerURL == "" || oidc.ClientID == "" {
+		return nil, errors.New("OpenID Connect issuer URL and client ID are required")
+	}
+
+	return userKubeconfig(adminKubeconfig, clusterName, oidcUserName, clientcmdapiv1.AuthInfo{
+		Exec: &clientcmdapiv1.ExecConfig{
+			APIVersion:      execAPIVersion,
+			Command:         kubeloginCommand,
+			Args:            kubeloginArgs(oidc, opts),
+			InteractiveMode: clientcmdapiv1.IfAvailableExecInteractiveMode,
+		},
+	})
+}
+
+func kubeloginArgs(oidc kubeoneapi.OpenIDConnectConfig, opts OIDCOptions) []string {
+	args := []string{
+		"oidc-login",
+		"get-token",
+		"--oidc-issuer-url=" + oidc.IssuerURL,
+		"--oidc-client-id=" + oidc.ClientID,
+	}
+
+	if opts.ClientSecret != "" {
+		args = append(args, "--oidc-client-secret="+opts.ClientSecret)
+	}
+
+	scopes := opts.ExtraScopes
+	if len(scopes) == 0 {
+		if oidc.UsernameClaim == "email" {
+			scopes = append(scopes, "email")
+		}
+		if oidc.GroupsClaim != "" {
+			scopes = append(scopes, oidc.GroupsClaim)
+		}
+	}
+	for _, scope := range scopes {
+		args = append(args, "--oidc-extra-scope="+scope)
+	}
+
+	return args
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const testAdminKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    certificate-authority-data: Q0EgREFUQQ==
+    server: https://api.example.com:6443
+  name: kubernetes
+contexts:
+- context:
+    cluster: kubernetes
+    user: kubernetes-admin
+  name: kubernetes-admin@kubernetes
+current-context: kubernetes-admin@kubernetes
+users:
+- name: kubernetes-admin
+  user:
+    client-certificate-data: Q0VSVA==
+    client-key-data: S0VZ
+`
+
+func TestOIDC(t *testing.T) {
+	tests := []struct {
+		name         string
+		oidc         kubeoneapi.OpenIDConnectConfig
+		opts         OIDCOptions
+		expectedArgs []string
+		expectedErr  bool
+	}{
+		{
+			name: "default scopes",
+			oidc: kubeoneapi.OpenIDConnectConfig{
+				IssuerURL:     "https://dex.example.com",
+				ClientID:      "kubernetes",
+				UsernameClaim: "email",
+				GroupsClaim:   "groups",
+			},
+			expectedArgs: []string{
+				"oidc-login",
+				"get-token",
+				"--oidc-issuer-url=https://dex.example.com",
+				"--oidc-client-id=kubernetes",
+				"--oidc-extra-scope=email",
+				"--oidc-extra-scope=groups",
+			},
+		},
+		{
+			name: "client secret and extra scopes",
+			oidc: kubeoneapi.OpenIDConnectConfig{
+				IssuerURL:   "https://dex.example.com",
+				ClientID:    "kubernetes",
+				GroupsClaim: "groups",
+			},
+			opts: OIDCOptions{
+				ClientSecret: "secret",
+				ExtraScopes:  []string{"profile"},
+			},
+			expectedArgs: []string{
+				"oidc-login",
+				"get-token",
+				"--oidc-issuer-url=https://dex.example.com",
+				"--oidc-client-id=kubernetes",
+				"--oidc-client-secret=secret",
+				"--oidc-extra-scope=profile",
+			},
+		},
+		{
+			name:        "missing issuer",
+			oidc:        kubeoneapi.OpenIDConnectConfig{ClientID: "kubernetes"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := OIDC([]byte(testAdminKubeconfig), "test", tt.oidc, tt.opts)
+			if (err != nil) != tt.expectedErr {
+				t.Fatalf("OIDC() error = %v, expectedErr %v", err, tt.expectedErr)
+			}
+			if tt.expectedErr {
+				return
+			}
+
+			if strings.Contains(string(out), "client-key-data") {
+				t.Error("expected the admin credentials to be removed")
+			}
+
+			konfig, err := clientcmd.Load(out)
+			if err != nil {
+				t.Fatalf("failed to load the generated kubeconfig: %v", err)
+			}
+
+			if konfig.CurrentContext != "oidc@test" {
+				t.Errorf("expected current context %q, got %q", "oidc@test", konfig.CurrentContext)
+			}
+			cluster, ok := konfig.Clusters["test"]
+			if !ok {
+				t.Fatal("expected cluster test")
+			}
+			if cluster.Server != "https://api.example.com:6443" || string(cluster.CertificateAuthorityData) != "CA DATA" {
+				t.Errorf("expected the cluster to be copied from the admin kubeconfig, got %+v", cluster)
+			}
+
+			user, ok := konfig.AuthInfos["oidc"]
+			if !ok || user.Exec == nil {
+				t.Fatal("expected user oidc with the exec plugin")
+			}
+			if !reflect.DeepEqual(user.Exec.Args, tt.expectedArgs) {
+				t.Errorf("expected args %v, got %v", tt.expectedArgs, user.Exec.Args)
+			}
+		})
+	}
+}
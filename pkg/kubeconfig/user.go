@@ -0,0 +1,181 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/certificate"
+	"k8c.io/kubeone/pkg/ssh/sshiofs"
+	"k8c.io/kubeone/pkg/state"
+	"k8c.io/kubeone/pkg/templates/resources"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapiv1 "k8s.io/client-go/tools/clientcmd/api/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// ClientCertificateOptions are the identity and the validity of the client
+// certificate
+type ClientCertificateOptions struct {
+	// User is the CommonName of the certificate, used as the user name by the
+	// Kubernetes RBAC
+	User string
+	// Groups are the Organizations of the certificate, used as the groups by
+	// the Kubernetes RBAC
+	Groups []string
+	// TTL is the validity of the certificate
+	TTL time.Duration
+}
+
+// DownloadCA downloads the PEM-encoded Kubernetes CA certificate and key over
+// SSH
+func DownloadCA(s *state.State) (caCert, caKey []byte, err error) {
+	conn, err := connectLeader(s)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sshfs := sshiofs.New(conn)
+	if caCert, err = fs.ReadFile(sshfs, certificate.KubernetesCACertPath); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to read the CA certificate")
+	}
+	if caKey, err = fs.ReadFile(sshfs, certificate.KubernetesCAKeyPath); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to read the CA key")
+	}
+
+	return caCert, caKey, nil
+}
+
+// CAIssuer returns the Issuer of the Kubernetes CA. The CA is downloaded over
+// SSH, unless the certificates are signed by the external CA signer.
+func CAIssuer(s *state.State) (certificate.Issuer, error) {
+	if s.Cluster.ExternalCASignerEnabled() {
+		return certificate.KubernetesIssuer(s.Context, s.Cluster, nil, s.Cluster.CertificateKeyAlgorithm())
+	}
+
+	caCert, caKey, err := DownloadCA(s)
+	if err != nil {
+		return nil, err
+	}
+
+	key, cert, err := certificate.ParseCAKeyPair(caCert, caKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse the CA")
+	}
+
+	return certificate.NewKeyIssuer(key, cert, s.Cluster.CertificateKeyAlgorithm()), nil
+}
+
+// ClientCertificate converts the admin kubeconfig to the kubeconfig
+// authenticating with a new client certificate, issued by the given CA, with
+// the identity and the validity from the options. The long-lived admin
+// credentials are not included.
+func ClientCertificate(adminKubeconfig []byte, issuer certificate.Issuer, clusterName string, opts ClientCertificateOptions) ([]byte, error) {
+	if opts.User == "" {
+		return nil, errors.New("user is required")
+	}
+
+	clientCert, err := certificate.NewClientCert(opts.User, opts.Groups, opts.TTL, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	return userKubeconfig(adminKubeconfig, clusterName, opts.User, clientcmdapiv1.AuthInfo{
+		ClientCertificateData: []byte(clientCert[resources.TLSCertName]),
+		ClientKeyData:         []byte(clientCert[resources.TLSKeyName]),
+	})
+}
+
+// userKubeconfig returns the kubeconfig with the cluster taken from the admin
+// kubeconfig and the given user
+func userKubeconfig(adminKubeconfig []byte, clusterName, userName string, authInfo clientcmdapiv1.AuthInfo) ([]byte, error) {
+	admin, err := clientcmd.Load(adminKubeconfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse the admin kubeconfig")
+	}
+
+	adminContext, ok := admin.Contexts[admin.CurrentContext]
+	if !ok {
+		return nil, errors.Errorf("current context %q not found in the admin kubeconfig", admin.CurrentContext)
+	}
+	adminCluster, ok := admin.Clusters[adminContext.Cluster]
+	if !ok {
+		return nil, errors.Errorf("cluster %q not found in the admin kubeconfig", adminContext.Cluster)
+	}
+
+	return newKubeconfig(clusterName, clientcmdapiv1.Cluster{
+		Server:                   adminCluster.Server,
+		TLSServerName:            adminCluster.TLSServerName,
+		InsecureSkipTLSVerify:    adminCluster.InsecureSkipTLSVerify,
+		CertificateAuthority:     adminCluster.CertificateAuthority,
+		CertificateAuthorityData: adminCluster.CertificateAuthorityData,
+		ProxyURL:                 adminCluster.ProxyURL,
+	}, userName, authInfo)
+}
+
+// ClientCertificateKubeconfig returns the kubeconfig of the user authenticating
+// with the given PEM-encoded client certificate and key, in the same way as the
+// kubeconfig files generated by kubeadm
+func ClientCertificateKubeconfig(server string, caCert []byte, clusterName, userName string, clientCert, clientKey []byte) ([]byte, error) {
+	return newKubeconfig(clusterName, clientcmdapiv1.Cluster{
+		Server:                   server,
+		CertificateAuthorityData: caCert,
+	}, userName, clientcmdapiv1.AuthInfo{
+		ClientCertificateData: clientCert,
+		ClientKeyData:         clientKey,
+	})
+}
+
+// newKubeconfig returns the kubeconfig with the given cluster and user
+func newKubeconfig(clusterName string, cluster clientcmdapiv1.Cluster, userName string, authInfo clientcmdapiv1.AuthInfo) ([]byte, error) {
+	contextName := fmt.Sprintf("%s@%s", userName, clusterName)
+	konfig := clientcmdapiv1.Config{
+		APIVersion: "v1",
+		Kind:       "Config",
+		Clusters: []clientcmdapiv1.NamedCluster{
+			{
+				Name:    clusterName,
+				Cluster: cluster,
+			},
+		},
+		AuthInfos: []clientcmdapiv1.NamedAuthInfo{
+			{
+				Name:     userName,
+				AuthInfo: authInfo,
+			},
+		},
+		Contexts: []clientcmdapiv1.NamedContext{
+			{
+				Name: contextName,
+				Context: clientcmdapiv1.Context{
+					Cluster:  clusterName,
+					AuthInfo: userName,
+				},
+			},
+		},
+		CurrentContext: contextName,
+	}
+
+	buf, err := yaml.Marshal(konfig)
+
+	return buf, errors.Wrap(err, "failed to marshal the kubeconfig")
+}
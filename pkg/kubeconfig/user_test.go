@@ -0,0 +1,142 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"reflect"
+	"testing"
+	"time"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/certificate"
+
+	"k8s.io/client-go/tools/clientcmd"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+)
+
+func newTestCA(t *testing.T) (caCert, caKey []byte) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate CA key: %v", err)
+	}
+
+	cert, err := certutil.NewSelfSignedCACert(certutil.Config{CommonName: "kubernetes"}, key)
+	if err != nil {
+		t.Fatalf("failed to generate CA certificate: %v", err)
+	}
+
+	caKey, err = keyutil.MarshalPrivateKeyToPEM(key)
+	if err != nil {
+		t.Fatalf("failed to encode CA key: %v", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: certutil.CertificateBlockType, Bytes: cert.Raw}), caKey
+}
+
+func TestClientCertificate(t *testing.T) {
+	caCert, caKey := newTestCA(t)
+	key, cert, err := certificate.ParseCAKeyPair(caCert, caKey)
+	if err != nil {
+		t.Fatalf("failed to parse the CA: %v", err)
+	}
+	issuer := certificate.NewKeyIssuer(key, cert, kubeoneapi.KeyAlgorithmRSA)
+
+	tests := []struct {
+		name        string
+		opts        ClientCertificateOptions
+		expectedErr bool
+	}{
+		{
+			name: "user with groups",
+			opts: ClientCertificateOptions{User: "alice", Groups: []string{"admins", "developers"}, TTL: 8 * time.Hour},
+		},
+		{
+			name: "user without groups",
+			opts: ClientCertificateOptions{User: "bob", TTL: time.Hour},
+		},
+		{
+			name:        "missing user",
+			opts:        ClientCertificateOptions{TTL: time.Hour},
+			expectedErr: true,
+		},
+		{
+			name:        "outlives the CA",
+			opts:        ClientCertificateOptions{User: "alice", TTL: 20 * 365 * 24 * time.Hour},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := ClientCertificate([]byte(testAdminKubeconfig), issuer, "test", tt.opts)
+			if (err != nil) != tt.expectedErr {
+				t.Fatalf("ClientCertificate() error = %v, expectedErr %v", err, tt.expectedErr)
+			}
+			if tt.expectedErr {
+				return
+			}
+
+			konfig, err := clientcmd.Load(out)
+			if err != nil {
+				t.Fatalf("failed to load the generated kubeconfig: %v", err)
+			}
+
+			contextName := tt.opts.User + "@test"
+			if konfig.CurrentContext != contextName {
+				t.Errorf("expected current context %q, got %q", contextName, konfig.CurrentContext)
+			}
+
+			user, ok := konfig.AuthInfos[tt.opts.User]
+			if !ok {
+				t.Fatalf("expected user %q", tt.opts.User)
+			}
+
+			certs, err := certutil.ParseCertsPEM(user.ClientCertificateData)
+			if err != nil || len(certs) != 1 {
+				t.Fatalf("failed to parse the client certificate: %v", err)
+			}
+			cert := certs[0]
+
+			if cert.Subject.CommonName != tt.opts.User {
+				t.Errorf("expected CommonName %q, got %q", tt.opts.User, cert.Subject.CommonName)
+			}
+			if len(tt.opts.Groups) != 0 && !reflect.DeepEqual(cert.Subject.Organization, tt.opts.Groups) {
+				t.Errorf("expected Organizations %v, got %v", tt.opts.Groups, cert.Subject.Organization)
+			}
+			if validity := time.Until(cert.NotAfter); validity > tt.opts.TTL || validity < tt.opts.TTL-time.Minute {
+				t.Errorf("expected certificate to be valid for %s, got %s", tt.opts.TTL, validity)
+			}
+			if !reflect.DeepEqual(cert.ExtKeyUsage, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}) {
+				t.Errorf("expected client auth usage, got %v", cert.ExtKeyUsage)
+			}
+
+			roots := x509.NewCertPool()
+			roots.AppendCertsFromPEM(caCert)
+			if _, err := cert.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}); err != nil {
+				t.Errorf("expected the certificate to be signed by the CA: %v", err)
+			}
+		})
+	}
+}
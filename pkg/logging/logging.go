@@ -0,0 +1,189 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"io"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// FormatText is the human-readable log format
+	FormatText = "text"
+	// FormatJSON logs each entry as a JSON object
+	FormatJSON = "json"
+
+	// FieldSubsystem is the field holding the name of the subsystem (package)
+	// the entry is logged from. Levels can be configured per subsystem.
+	FieldSubsystem = "subsystem"
+	// FieldTask is the field holding the name of the running task
+	FieldTask = "task"
+	// FieldNode is the field holding the address of the host the task is
+	// running on
+	FieldNode = "node"
+	// FieldCluster is the field holding the name of the cluster, used when
+	// multiple clusters are managed at the same time
+	FieldCluster = "cluster"
+)
+
+// Options configures the logger
+type Options struct {
+	// Format is the log format, FormatText or FormatJSON
+	Format string
+	// Levels is a comma-separated list of the default level and levels per
+	// subsystem, e.g. "info,addons=debug,tasks=warn"
+	Levels string
+	// Verbose sets the default level to debug, unless it's set in Levels
+	Verbose bool
+}
+
+// New returns a logger configured by the given options
+func New(out io.Writer, opts Options) (*logrus.Logger, error) {
+	defaultLevel := logrus.InfoLevel
+	if opts.Verbose {
+		defaultLevel = logrus.DebugLevel
+	}
+
+	defaultLevel, subsystemLevels, err := ParseLevels(opts.Levels, defaultLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	var formatter logrus.Formatter
+	switch opts.Format {
+	case FormatText, "":
+		formatter = &logrus.TextFormatter{
+			FullTimestamp:   true,
+			TimestampFormat: "15:04:05 MST",
+		}
+	case FormatJSON:
+		formatter = &logrus.JSONFormatter{}
+	default:
+		return nil, errors.Errorf("unknown log format %q, must be one of: %s, %s", opts.Format, FormatText, FormatJSON)
+	}
+
+	// The logger must pass through entries of the most verbose level, so the
+	// formatter can filter them per subsystem
+	loggerLevel := defaultLevel
+	for _, level := range subsystemLevels {
+		if level > loggerLevel {
+			loggerLevel = level
+		}
+	}
+
+	logger := logrus.New()
+	logger.Out = out
+	logger.SetLevel(loggerLevel)
+	logger.Formatter = &levelFilterFormatter{
+		Formatter:       formatter,
+		defaultLevel:    defaultLevel,
+		subsystemLevels: subsystemLevels,
+	}
+
+	return logger, nil
+}
+
+// ParseLevels parses the comma-separated list of the default level and
+// levels per subsystem, e.g. "info,addons=debug". The given default level is
+// returned if the list doesn't set it.
+func ParseLevels(levels string, defaultLevel logrus.Level) (logrus.Level, map[string]logrus.Level, error) {
+	subsystemLevels := map[string]logrus.Level{}
+
+	for _, item := range strings.Split(levels, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		subsystem := ""
+		levelName := item
+		if i := strings.Index(item, "="); i >= 0 {
+			subsystem, levelName = strings.TrimSpace(item[:i]), strings.TrimSpace(item[i+1:])
+			if subsystem == "" {
+				return 0, nil, errors.Errorf("missing subsystem name in log level %q", item)
+			}
+		}
+
+		level, err := logrus.ParseLevel(levelName)
+		if err != nil {
+			return 0, nil, errors.Wrapf(err, "invalid log level %q", item)
+		}
+
+		if subsystem == "" {
+			defaultLevel = level
+		} else {
+			subsystemLevels[subsystem] = level
+		}
+	}
+
+	return defaultLevel, subsystemLevels, nil
+}
+
+// FuncFields returns the subsystem and the task fields for the given
+// function, based on its package and name, e.g. the subsystem "addons" and
+// the task "EnsureUserAddons" for addons.EnsureUserAddons
+func FuncFields(fn interface{}) logrus.Fields {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return logrus.Fields{}
+	}
+
+	f := runtime.FuncForPC(v.Pointer())
+	if f == nil {
+		return logrus.Fields{}
+	}
+
+	// Strip the package path, e.g. k8c.io/kubeone/pkg/addons.EnsureUserAddons
+	name := f.Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+
+	fields := logrus.Fields{FieldTask: name}
+	if i := strings.Index(name, "."); i >= 0 {
+		fields[FieldSubsystem] = name[:i]
+		fields[FieldTask] = name[i+1:]
+	}
+
+	return fields
+}
+
+// levelFilterFormatter drops entries above the level of their subsystem.
+// Nothing is written for an entry formatted to an empty output.
+type levelFilterFormatter struct {
+	logrus.Formatter
+	defaultLevel    logrus.Level
+	subsystemLevels map[string]logrus.Level
+}
+
+func (f *levelFilterFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	level := f.defaultLevel
+	if subsystem, ok := entry.Data[FieldSubsystem].(string); ok {
+		if subsystemLevel, found := f.subsystemLevels[subsystem]; found {
+			level = subsystemLevel
+		}
+	}
+
+	if entry.Level > level {
+		return nil, nil
+	}
+
+	return f.Formatter.Format(entry)
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestParseLevels(t *testing.T) {
+	tests := []struct {
+		name                string
+		levels              string
+		wantDefault         logrus.Level
+		wantSubsystemLevels map[string]logrus.Level
+		wantErr             bool
+	}{
+		{
+			name:                "empty",
+			levels:              "",
+			wantDefault:         logrus.InfoLevel,
+			wantSubsystemLevels: map[string]logrus.Level{},
+		},
+		{
+			name:        "default and subsystem levels",
+			levels:      "warn, addons=debug,tasks=error",
+			wantDefault: logrus.WarnLevel,
+			wantSubsystemLevels: map[string]logrus.Level{
+				"addons": logrus.DebugLevel,
+				"tasks":  logrus.ErrorLevel,
+			},
+		},
+		{
+			name:        "only subsystem levels",
+			levels:      "ssh=trace",
+			wantDefault: logrus.InfoLevel,
+			wantSubsystemLevels: map[string]logrus.Level{
+				"ssh": logrus.TraceLevel,
+			},
+		},
+		{
+			name:    "invalid level",
+			levels:  "info,addons=loud",
+			wantErr: true,
+		},
+		{
+			name:    "missing subsystem",
+			levels:  "=debug",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			gotDefault, gotSubsystemLevels, err := ParseLevels(tt.levels, logrus.InfoLevel)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLevels() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if gotDefault != tt.wantDefault {
+				t.Errorf("ParseLevels() default = %v, want %v", gotDefault, tt.wantDefault)
+			}
+			if !reflect.DeepEqual(gotSubsystemLevels, tt.wantSubsystemLevels) {
+				t.Errorf("ParseLevels() subsystem levels = %v, want %v", gotSubsystemLevels, tt.wantSubsystemLevels)
+			}
+		})
+	}
+}
+
+func TestNewJSONWithSubsystemLevels(t *testing.T) {
+	out := &bytes.Buffer{}
+	logger, err := New(out, Options{Format: FormatJSON, Levels: "info,addons=debug,tasks=warn"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	logger.WithField(FieldSubsystem, "addons").Debug("addons debug")
+	logger.WithField(FieldSubsystem, "tasks").Info("tasks info")
+	logger.WithField(FieldSubsystem, "tasks").Warn("tasks warn")
+	logger.Debug("default debug")
+	logger.WithFields(logrus.Fields{FieldSubsystem: "ssh", FieldNode: "10.0.0.1"}).Info("ssh info")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	got := []string{}
+	for _, line := range lines {
+		entry := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("failed to parse log entry %q: %v", line, err)
+		}
+		got = append(got, entry["msg"].(string))
+
+		if entry["msg"] == "ssh info" && entry[FieldNode] != "10.0.0.1" {
+			t.Errorf("expected the node field to be logged, got %v", entry)
+		}
+	}
+
+	want := []string{"addons debug", "tasks warn", "ssh info"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("logged entries = %v, want %v", got, want)
+	}
+}
+
+func TestNewInvalidFormat(t *testing.T) {
+	if _, err := New(&bytes.Buffer{}, Options{Format: "xml"}); err == nil {
+		t.Error("expected error for unknown log format")
+	}
+}
+
+func exampleTask() {}
+
+func TestFuncFields(t *testing.T) {
+	got := FuncFields(exampleTask)
+	want := logrus.Fields{FieldSubsystem: "logging", FieldTask: "exampleTask"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FuncFields() = %v, want %v", got, want)
+	}
+
+	var nilFunc func()
+	if got := FuncFields(nilFunc); len(got) != 0 {
+		t.Errorf("FuncFields(nil) = %v, want no fields", got)
+	}
+}
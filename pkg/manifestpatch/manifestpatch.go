@@ -0,0 +1,184 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package manifestpatch applies the user-defined manifest patches to the
+// manifests and objects KubeOne applies to the cluster
+package manifestpatch
+
+import (
+	"encoding/json"
+	"reflect"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+
+	metav1unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+)
+
+// coreGroup is used in the patch target to match the core API group, which
+// has an empty name
+const coreGroup = "core"
+
+// Apply applies the manifest patches, in the order they're defined, to all
+// manifests matching the patch target
+func Apply(manifests []runtime.RawExtension, patches []kubeoneapi.ManifestPatch) ([]runtime.RawExtension, error) {
+	if len(patches) == 0 {
+		return manifests, nil
+	}
+
+	patched := make([]runtime.RawExtension, 0, len(manifests))
+	for _, m := range manifests {
+		raw, err := applyToDocument(m.Raw, patches)
+		if err != nil {
+			return nil, err
+		}
+
+		patched = append(patched, runtime.RawExtension{Raw: raw})
+	}
+
+	return patched, nil
+}
+
+// ApplyToObject applies the manifest patches matching the object to it. If
+// the object kind isn't set, as usual for the typed objects, it's looked up
+// in the scheme.
+func ApplyToObject(obj client.Object, scheme *runtime.Scheme, patches []kubeoneapi.ManifestPatch) error {
+	if len(patches) == 0 {
+		return nil
+	}
+
+	var err error
+	objGVK := obj.GetObjectKind().GroupVersionKind()
+	gvk := objGVK
+	if gvk.Empty() {
+		if gvk, err = apiutil.GVKForObject(obj, scheme); err != nil {
+			return errors.Wrapf(err, "failed to get kind of %T", obj)
+		}
+	}
+
+	doc, err := json.Marshal(obj)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %T", obj)
+	}
+
+	// Set the kind on the document only, the typed object is left as it is
+	u := &metav1unstructured.Unstructured{}
+	if err = json.Unmarshal(doc, &u.Object); err != nil {
+		return errors.Wrapf(err, "failed to parse %T", obj)
+	}
+	u.SetGroupVersionKind(gvk)
+	if doc, err = u.MarshalJSON(); err != nil {
+		return errors.Wrapf(err, "failed to marshal %T", obj)
+	}
+
+	patched, err := applyToDocument(doc, patches)
+	if err != nil {
+		return err
+	}
+
+	// Unmarshal into the zero value, so the removed fields are removed from the object
+	target := reflect.ValueOf(obj).Elem()
+	target.Set(reflect.Zero(target.Type()))
+
+	if err = json.Unmarshal(patched, obj); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal patched %T", obj)
+	}
+	obj.GetObjectKind().SetGroupVersionKind(objGVK)
+
+	return nil
+}
+
+func applyToDocument(raw []byte, patches []kubeoneapi.ManifestPatch) ([]byte, error) {
+	for i, patch := range patches {
+		obj := &metav1unstructured.Unstructured{}
+		if _, _, err := metav1unstructured.UnstructuredJSONScheme.Decode(raw, nil, obj); err != nil {
+			return nil, errors.Wrap(err, "failed to parse unstructured fields")
+		}
+
+		if !manifestPatchMatches(patch.Target, obj) {
+			continue
+		}
+
+		var err error
+		raw, err = applyManifestPatch(raw, patch)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to apply manifest patch %d to %s %q", i, obj.GetKind(), obj.GetName())
+		}
+	}
+
+	return raw, nil
+}
+
+func manifestPatchMatches(target kubeoneapi.ManifestPatchTarget, obj *metav1unstructured.Unstructured) bool {
+	gvk := obj.GroupVersionKind()
+
+	group := target.Group
+	if group == coreGroup {
+		group = ""
+	}
+
+	switch {
+	case target.Group != "" && group != gvk.Group:
+		return false
+	case target.Kind != "" && target.Kind != gvk.Kind:
+		return false
+	case target.Name != "" && target.Name != obj.GetName():
+		return false
+	case target.Namespace != "" && target.Namespace != obj.GetNamespace():
+		return false
+	}
+
+	return true
+}
+
+func applyManifestPatch(doc []byte, patch kubeoneapi.ManifestPatch) ([]byte, error) {
+	if patch.MergePatch != "" {
+		mergePatch, err := yaml.YAMLToJSON([]byte(patch.MergePatch))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse merge patch")
+		}
+
+		return jsonpatch.MergePatch(doc, mergePatch)
+	}
+
+	jsonPatch, err := decodeJSONPatch(patch.JSONPatch)
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonPatch.Apply(doc)
+}
+
+// decodeJSONPatch decodes the JSON patch (RFC 6902) written in YAML or JSON
+func decodeJSONPatch(patch string) (jsonpatch.Patch, error) {
+	patchJSON, err := yaml.YAMLToJSON([]byte(patch))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse JSON patch")
+	}
+
+	jsonPatch, err := jsonpatch.DecodePatch(patchJSON)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode JSON patch")
+	}
+
+	return jsonPatch, nil
+}
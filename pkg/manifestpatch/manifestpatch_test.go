@@ -0,0 +1,179 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifestpatch
+
+import (
+	"reflect"
+	"testing"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+func TestApply(t *testing.T) {
+	manifests := []string{
+		`{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "app", "namespace": "kube-system"}, "spec": {"template": {"spec": {"containers": []}}}}`,
+		`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "config", "namespace": "default"}}`,
+	}
+
+	tests := []struct {
+		name     string
+		patches  []kubeoneapi.ManifestPatch
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name:     "no patches",
+			expected: manifests,
+		},
+		{
+			name: "merge patch on all deployments",
+			patches: []kubeoneapi.ManifestPatch{
+				{
+					Target: kubeoneapi.ManifestPatchTarget{Group: "apps", Kind: "Deployment"},
+					MergePatch: `
+spec:
+  template:
+    spec:
+      imagePullSecrets:
+      - name: corporate-registry
+`,
+				},
+			},
+			expected: []string{
+				`{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "app", "namespace": "kube-system"}, "spec": {"template": {"spec": {"containers": [], "imagePullSecrets": [{"name": "corporate-registry"}]}}}}`,
+				manifests[1],
+			},
+		},
+		{
+			name: "JSON patch on the core API group",
+			patches: []kubeoneapi.ManifestPatch{
+				{
+					Target:    kubeoneapi.ManifestPatchTarget{Group: "core", Namespace: "default"},
+					JSONPatch: `[{"op": "add", "path": "/metadata/labels", "value": {"owner": "platform"}}]`,
+				},
+			},
+			expected: []string{
+				manifests[0],
+				`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "config", "namespace": "default", "labels": {"owner": "platform"}}}`,
+			},
+		},
+		{
+			name: "patches applied in order to all manifests",
+			patches: []kubeoneapi.ManifestPatch{
+				{
+					MergePatch: `{"metadata": {"labels": {"owner": "platform"}}}`,
+				},
+				{
+					Target:    kubeoneapi.ManifestPatchTarget{Name: "config"},
+					JSONPatch: `[{"op": "replace", "path": "/metadata/labels/owner", "value": "team"}]`,
+				},
+			},
+			expected: []string{
+				`{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "app", "namespace": "kube-system", "labels": {"owner": "platform"}}, "spec": {"template": {"spec": {"containers": []}}}}`,
+				`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "config", "namespace": "default", "labels": {"owner": "team"}}}`,
+			},
+		},
+		{
+			name: "failing JSON patch",
+			patches: []kubeoneapi.ManifestPatch{
+				{
+					JSONPatch: `[{"op": "remove", "path": "/metadata/annotations"}]`,
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			raw := []runtime.RawExtension{}
+			for _, m := range manifests {
+				raw = append(raw, runtime.RawExtension{Raw: []byte(m)})
+			}
+
+			got, err := Apply(raw, tt.patches)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Apply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if len(got) != len(tt.expected) {
+				t.Fatalf("expected %d manifests, got %d", len(tt.expected), len(got))
+			}
+			for i := range got {
+				gotYAML, err := yaml.JSONToYAML(got[i].Raw)
+				if err != nil {
+					t.Fatalf("failed to convert the result to YAML: %v", err)
+				}
+				expectedYAML, err := yaml.JSONToYAML([]byte(tt.expected[i]))
+				if err != nil {
+					t.Fatalf("failed to convert the expected manifest to YAML: %v", err)
+				}
+				if string(gotYAML) != string(expectedYAML) {
+					t.Errorf("manifest %d:\ngot:\n%s\nexpected:\n%s", i, gotYAML, expectedYAML)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyToObject(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build the scheme: %v", err)
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "config",
+			Namespace: "default",
+		},
+		Data: map[string]string{"key": "value"},
+	}
+
+	patches := []kubeoneapi.ManifestPatch{
+		{
+			Target:     kubeoneapi.ManifestPatchTarget{Group: "core", Kind: "ConfigMap"},
+			MergePatch: `{"metadata": {"labels": {"owner": "platform"}}, "data": {"key": null, "other": "value"}}`,
+		},
+		{
+			Target:     kubeoneapi.ManifestPatchTarget{Kind: "Secret"},
+			MergePatch: `{"metadata": {"labels": {"secret": "true"}}}`,
+		},
+	}
+
+	if err := ApplyToObject(cm, scheme, patches); err != nil {
+		t.Fatalf("ApplyToObject() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(cm.Labels, map[string]string{"owner": "platform"}) {
+		t.Errorf("unexpected labels: %v", cm.Labels)
+	}
+	if !reflect.DeepEqual(cm.Data, map[string]string{"other": "value"}) {
+		t.Errorf("unexpected data: %v", cm.Data)
+	}
+	if !cm.GetObjectKind().GroupVersionKind().Empty() {
+		t.Errorf("expected the kind to be left unset, got %v", cm.GetObjectKind().GroupVersionKind())
+	}
+}
@@ -0,0 +1,265 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodehealth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/clusterstatus/etcdstatus"
+	"k8c.io/kubeone/pkg/state"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultTimeout is how long checks are retried before the node is
+	// considered unhealthy
+	DefaultTimeout = 5 * time.Minute
+
+	probeNamespace = metav1.NamespaceDefault
+)
+
+var (
+	// controlPlaneComponents are the static pods run by kubeadm on the
+	// control plane nodes
+	controlPlaneComponents = []string{
+		"etcd",
+		"kube-apiserver",
+		"kube-controller-manager",
+		"kube-scheduler",
+	}
+)
+
+type check struct {
+	name string
+	fn   func(ctx context.Context, s *state.State, node kubeoneapi.HostConfig) error
+}
+
+// Verify checks that the upgraded node is healthy before the upgrade moves
+// to the next node. The node must be Ready and run the expected kubelet
+// version. Control plane nodes must also run healthy control plane static
+// pods and a healthy etcd member. Finally, the probe pods (s.UpgradeProbes)
+// are run on the node and must succeed.
+//
+// Failed checks are retried with exponential backoff until the timeout
+// (s.UpgradeVerifyTimeout) expires.
+func Verify(s *state.State, node kubeoneapi.HostConfig, controlPlane bool) error {
+	checks := []check{{name: "node ready", fn: checkNodeReady}}
+	if controlPlane {
+		checks = append(checks,
+			check{name: "control plane static pods", fn: checkStaticPods},
+			check{name: "etcd member health", fn: checkEtcdMember},
+		)
+	}
+	for i := range s.UpgradeProbes {
+		probe := s.UpgradeProbes[i]
+		checks = append(checks, check{
+			name: fmt.Sprintf("probe %q", probe.Name),
+			fn: func(ctx context.Context, s *state.State, node kubeoneapi.HostConfig) error {
+				return runProbe(ctx, s, node, probe)
+			},
+		})
+	}
+
+	timeout := s.UpgradeVerifyTimeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(s.Context, timeout)
+	defer cancel()
+
+	for _, c := range checks {
+		s.Logger.Infof("Verifying %s...", c.name)
+		if err := retry(ctx, s, node, c); err != nil {
+			return errors.Wrapf(err, "node %q failed the %s check", node.Hostname, c.name)
+		}
+	}
+
+	return nil
+}
+
+func retry(ctx context.Context, s *state.State, node kubeoneapi.HostConfig, c check) error {
+	backoff := wait.Backoff{
+		Duration: 5 * time.Second,
+		Factor:   2,
+		Steps:    100,
+		Cap:      time.Minute,
+	}
+
+	for {
+		err := c.fn(ctx, s, node)
+		if err == nil {
+			return nil
+		}
+
+		delay := backoff.Step()
+		s.Logger.Warnf("Check %s failed, retrying in %v: %v", c.name, delay, err)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+func checkNodeReady(ctx context.Context, s *state.State, node kubeoneapi.HostConfig) error {
+	n := corev1.Node{}
+	if err := s.DynamicClient.Get(ctx, dynclient.ObjectKey{Name: node.Hostname}, &n); err != nil {
+		return errors.Wrap(err, "failed to get node")
+	}
+
+	if !NodeReady(n) {
+		return errors.New("node is not ready")
+	}
+
+	kubeletVersion := fmt.Sprintf("v%s", s.Cluster.Versions.Kubernetes)
+	if n.Status.NodeInfo.KubeletVersion != kubeletVersion {
+		return errors.Errorf("node runs kubelet %s, expected %s", n.Status.NodeInfo.KubeletVersion, kubeletVersion)
+	}
+
+	return nil
+}
+
+func checkStaticPods(ctx context.Context, s *state.State, node kubeoneapi.HostConfig) error {
+	pods := corev1.PodList{}
+	listOpts := []dynclient.ListOption{
+		dynclient.InNamespace(metav1.NamespaceSystem),
+		dynclient.MatchingLabels{"tier": "control-plane"},
+	}
+	if err := s.DynamicClient.List(ctx, &pods, listOpts...); err != nil {
+		return errors.Wrap(err, "failed to list control plane pods")
+	}
+
+	ready := map[string]bool{}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == node.Hostname {
+			ready[pod.Labels["component"]] = podReady(pod)
+		}
+	}
+
+	for _, component := range controlPlaneComponents {
+		isReady, found := ready[component]
+		if !found {
+			return errors.Errorf("%s pod not found", component)
+		}
+		if !isReady {
+			return errors.Errorf("%s pod is not ready", component)
+		}
+	}
+
+	return nil
+}
+
+func checkEtcdMember(ctx context.Context, s *state.State, node kubeoneapi.HostConfig) error {
+	// The etcd status is read using the state context
+	s = s.Clone()
+	s.Context = ctx
+
+	etcdRing, err := etcdstatus.MemberList(s)
+	if err != nil {
+		return err
+	}
+
+	status, err := etcdstatus.Get(s, node, etcdRing)
+	if err != nil {
+		return err
+	}
+
+	if !status.Member {
+		return errors.New("node is not an etcd member")
+	}
+	if !status.Health {
+		return errors.New("etcd member is not healthy")
+	}
+
+	return nil
+}
+
+// runProbe runs the probe pod on the node, and waits for it to complete.
+// The probe pod is deleted afterwards.
+func runProbe(ctx context.Context, s *state.State, node kubeoneapi.HostConfig, probe corev1.Pod) error {
+	pod := probe.DeepCopy()
+	pod.GenerateName = fmt.Sprintf("%s-", probe.Name)
+	pod.Name = ""
+	pod.ResourceVersion = ""
+	if pod.Namespace == "" {
+		pod.Namespace = probeNamespace
+	}
+	pod.Spec.NodeName = node.Hostname
+	pod.Spec.RestartPolicy = corev1.RestartPolicyNever
+
+	if err := s.DynamicClient.Create(ctx, pod); err != nil {
+		return errors.Wrap(err, "failed to create probe pod")
+	}
+	defer func() {
+		if err := s.DynamicClient.Delete(ctx, pod); err != nil {
+			s.Logger.Warnf("Failed to delete probe pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		}
+	}()
+
+	key := dynclient.ObjectKeyFromObject(pod)
+	var phase corev1.PodPhase
+	err := wait.PollImmediateUntil(5*time.Second, func() (bool, error) {
+		current := corev1.Pod{}
+		if err := s.DynamicClient.Get(ctx, key, &current); err != nil {
+			return false, nil
+		}
+		phase = current.Status.Phase
+
+		return phase == corev1.PodSucceeded || phase == corev1.PodFailed, nil
+	}, ctx.Done())
+	if err != nil {
+		return errors.Wrapf(err, "probe pod %s didn't complete (phase %q)", key, phase)
+	}
+
+	if phase == corev1.PodFailed {
+		return errors.Errorf("probe pod %s failed", key)
+	}
+
+	return nil
+}
+
+// NodeReady returns whether the node has the Ready condition
+func NodeReady(node corev1.Node) bool {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+func podReady(pod corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodehealth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/state"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newNode(name, kubeletVersion string, ready corev1.ConditionStatus) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+			NodeInfo:   corev1.NodeSystemInfo{KubeletVersion: kubeletVersion},
+		},
+	}
+}
+
+func newStaticPod(component, nodeName string, ready corev1.ConditionStatus) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      component + "-" + nodeName,
+			Namespace: metav1.NamespaceSystem,
+			Labels:    map[string]string{"component": component, "tier": "control-plane"},
+		},
+		Spec: corev1.PodSpec{NodeName: nodeName},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+		},
+	}
+}
+
+func newState(objs ...dynclient.Object) *state.State {
+	return &state.State{
+		Context:       context.Background(),
+		Cluster:       &kubeoneapi.KubeOneCluster{Versions: kubeoneapi.VersionConfig{Kubernetes: "1.22.2"}},
+		DynamicClient: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).Build(),
+	}
+}
+
+func TestCheckNodeReady(t *testing.T) {
+	tests := []struct {
+		name    string
+		node    *corev1.Node
+		wantErr bool
+	}{
+		{
+			name: "ready and upgraded",
+			node: newNode("node-0", "v1.22.2", corev1.ConditionTrue),
+		},
+		{
+			name:    "not ready",
+			node:    newNode("node-0", "v1.22.2", corev1.ConditionFalse),
+			wantErr: true,
+		},
+		{
+			name:    "old kubelet version",
+			node:    newNode("node-0", "v1.21.5", corev1.ConditionTrue),
+			wantErr: true,
+		},
+		{
+			name:    "node not found",
+			node:    newNode("node-1", "v1.22.2", corev1.ConditionTrue),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			s := newState(tt.node)
+			err := checkNodeReady(context.Background(), s, kubeoneapi.HostConfig{Hostname: "node-0"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkNodeReady() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckStaticPods(t *testing.T) {
+	tests := []struct {
+		name    string
+		pods    []dynclient.Object
+		wantErr bool
+	}{
+		{
+			name: "all components ready",
+			pods: []dynclient.Object{
+				newStaticPod("etcd", "node-0", corev1.ConditionTrue),
+				newStaticPod("kube-apiserver", "node-0", corev1.ConditionTrue),
+				newStaticPod("kube-controller-manager", "node-0", corev1.ConditionTrue),
+				newStaticPod("kube-scheduler", "node-0", corev1.ConditionTrue),
+			},
+		},
+		{
+			name: "component not ready",
+			pods: []dynclient.Object{
+				newStaticPod("etcd", "node-0", corev1.ConditionTrue),
+				newStaticPod("kube-apiserver", "node-0", corev1.ConditionFalse),
+				newStaticPod("kube-controller-manager", "node-0", corev1.ConditionTrue),
+				newStaticPod("kube-scheduler", "node-0", corev1.ConditionTrue),
+			},
+			wantErr: true,
+		},
+		{
+			name: "component running on another node",
+			pods: []dynclient.Object{
+				newStaticPod("etcd", "node-0", corev1.ConditionTrue),
+				newStaticPod("kube-apiserver", "node-1", corev1.ConditionTrue),
+				newStaticPod("kube-controller-manager", "node-0", corev1.ConditionTrue),
+				newStaticPod("kube-scheduler", "node-0", corev1.ConditionTrue),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			s := newState(tt.pods...)
+			err := checkStaticPods(context.Background(), s, kubeoneapi.HostConfig{Hostname: "node-0"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkStaticPods() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunProbeTimeout(t *testing.T) {
+	s := newState()
+	s.Logger = logrus.New()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	probe := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "probe"}}
+	start := time.Now()
+	if err := runProbe(ctx, s, kubeoneapi.HostConfig{Hostname: "node-0"}, probe); err == nil {
+		t.Fatal("expected the probe to time out")
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("probe didn't stop with the context, took %v", elapsed)
+	}
+
+	pods := corev1.PodList{}
+	if err := s.DynamicClient.List(context.Background(), &pods); err != nil {
+		t.Fatalf("failed to list pods: %v", err)
+	}
+	if len(pods.Items) != 0 {
+		t.Errorf("expected the probe pod to be deleted, found %d pods", len(pods.Items))
+	}
+}
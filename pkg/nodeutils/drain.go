@@ -21,6 +21,8 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -33,16 +35,20 @@ type Drainer interface {
 	Cordon(ctx context.Context, nodeName string, state bool) error
 }
 
-func NewDrainer(restconfig *rest.Config, logger logrus.FieldLogger) Drainer {
+// NewDrainer returns a Drainer which drains nodes according to the given
+// configuration, as returned by KubeOneCluster.NodeDrainConfig
+func NewDrainer(restconfig *rest.Config, logger logrus.FieldLogger, config kubeoneapi.NodeDrainConfig) Drainer {
 	return &drainer{
 		logger:     logger,
 		restconfig: restconfig,
+		config:     config,
 	}
 }
 
 type drainer struct {
 	logger     logrus.FieldLogger
 	restconfig *rest.Config
+	config     kubeoneapi.NodeDrainConfig
 }
 
 func (dr *drainer) Drain(ctx context.Context, nodeName string) error {
@@ -74,7 +80,7 @@ func (dr *drainer) drainHelper(ctx context.Context) (*drain.Helper, error) {
 		return nil, err
 	}
 
-	return &drain.Helper{
+	helper := &drain.Helper{
 		Ctx:                 ctx,
 		Client:              kubeClinet,
 		GracePeriodSeconds:  -1,
@@ -89,7 +95,26 @@ func (dr *drainer) drainHelper(ctx context.Context) (*drain.Helper, error) {
 			}
 			dr.logger.Infof("pod %q/%q is %s", pod.GetNamespace(), pod.GetName(), evicted)
 		},
-	}, nil
+	}
+
+	if dr.config.GracePeriodSeconds != nil {
+		helper.GracePeriodSeconds = *dr.config.GracePeriodSeconds
+	}
+	if dr.config.Timeout != nil {
+		helper.Timeout = dr.config.Timeout.Duration
+	}
+	if dr.config.DeleteEmptyDirData != nil {
+		helper.DeleteEmptyDirData = *dr.config.DeleteEmptyDirData
+	}
+	if dr.config.SkipWaitForDeleteTimeoutSeconds != nil {
+		helper.SkipWaitForDeleteTimeoutSeconds = *dr.config.SkipWaitForDeleteTimeoutSeconds
+	}
+	if dr.config.IgnorePodDisruptionBudgets != nil {
+		// Deleting pods instead of evicting them bypasses PodDisruptionBudgets
+		helper.DisableEviction = *dr.config.IgnorePodDisruptionBudgets
+	}
+
+	return helper, nil
 }
 
 type loggerIoWriter func(format string, args ...interface{})
@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutils
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// EventSourceComponent is the component name reported in Events created by KubeOne
+	EventSourceComponent = "kubeone"
+
+	// Reasons used for the Events recorded on the Node objects
+	EventReasonDrained             = "KubeOneDrained"
+	EventReasonUpgraded            = "KubeOneUpgraded"
+	EventReasonCertificatesRenewed = "KubeOneCertificatesRenewed"
+
+	// NodeConditionKubeOneOperation is the Node condition describing the last
+	// operation performed by KubeOne on the node. Its reason and message are
+	// the same as of the last recorded Event.
+	NodeConditionKubeOneOperation corev1.NodeConditionType = "KubeOneOperation"
+)
+
+// RecordEvent creates a Normal Event with the given reason and message for the
+// Node object, and sets the KubeOneOperation condition on the Node, so
+// operations performed by KubeOne are visible to the cluster users (e.g. in
+// `kubectl describe node` and `kubectl get events`).
+func RecordEvent(ctx context.Context, c dynclient.Client, nodeName, reason, message string) error {
+	node := corev1.Node{}
+	if err := c.Get(ctx, dynclient.ObjectKey{Name: nodeName}, &node); err != nil {
+		return errors.Wrapf(err, "failed to get node %q", nodeName)
+	}
+
+	now := metav1.NewTime(time.Now())
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			// The same naming scheme is used by the client-go event recorder
+			Name:      fmt.Sprintf("%s.%x", nodeName, now.UnixNano()),
+			Namespace: metav1.NamespaceDefault,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: "v1",
+			Kind:       "Node",
+			Name:       node.Name,
+			UID:        node.UID,
+		},
+		Reason:  reason,
+		Message: message,
+		Type:    corev1.EventTypeNormal,
+		Source: corev1.EventSource{
+			Component: EventSourceComponent,
+			Host:      nodeName,
+		},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+
+	if err := c.Create(ctx, event); err != nil {
+		return errors.Wrapf(err, "failed to create event for node %q", nodeName)
+	}
+
+	return setOperationCondition(ctx, c, &node, reason, message, now)
+}
+
+// setOperationCondition sets the KubeOneOperation condition on the Node. The
+// condition is patched, so the conditions reported by the kubelet are kept.
+func setOperationCondition(ctx context.Context, c dynclient.Client, node *corev1.Node, reason, message string, now metav1.Time) error {
+	oldNode := node.DeepCopy()
+
+	condition := corev1.NodeCondition{
+		Type:               NodeConditionKubeOneOperation,
+		Status:             corev1.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		LastHeartbeatTime:  now,
+		LastTransitionTime: now,
+	}
+
+	found := false
+	for i := range node.Status.Conditions {
+		if node.Status.Conditions[i].Type == NodeConditionKubeOneOperation {
+			node.Status.Conditions[i] = condition
+			found = true
+		}
+	}
+	if !found {
+		node.Status.Conditions = append(node.Status.Conditions, condition)
+	}
+
+	err := c.Status().Patch(ctx, node, dynclient.StrategicMergeFrom(oldNode))
+
+	return errors.Wrapf(err, "failed to set %s condition on node %q", NodeConditionKubeOneOperation, node.Name)
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutils
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRecordEvent(t *testing.T) {
+	readyCondition := corev1.NodeCondition{Type: corev1.NodeReady, Status: corev1.ConditionTrue}
+
+	tests := []struct {
+		name       string
+		conditions []corev1.NodeCondition
+	}{
+		{
+			name:       "condition added",
+			conditions: []corev1.NodeCondition{readyCondition},
+		},
+		{
+			name: "condition replaced",
+			conditions: []corev1.NodeCondition{
+				readyCondition,
+				{
+					Type:    NodeConditionKubeOneOperation,
+					Status:  corev1.ConditionTrue,
+					Reason:  EventReasonDrained,
+					Message: "Node drained by KubeOne",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-0", UID: "uid-0"},
+				Status:     corev1.NodeStatus{Conditions: tt.conditions},
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(node).Build()
+			ctx := context.Background()
+
+			if err := RecordEvent(ctx, c, "node-0", EventReasonUpgraded, "Node upgraded by KubeOne"); err != nil {
+				t.Fatalf("RecordEvent() error = %v", err)
+			}
+
+			events := corev1.EventList{}
+			if err := c.List(ctx, &events, dynclient.InNamespace(metav1.NamespaceDefault)); err != nil {
+				t.Fatalf("failed to list events: %v", err)
+			}
+			if len(events.Items) != 1 {
+				t.Fatalf("expected 1 event, got %d", len(events.Items))
+			}
+			event := events.Items[0]
+			if event.Reason != EventReasonUpgraded || event.InvolvedObject.Kind != "Node" || event.InvolvedObject.UID != "uid-0" {
+				t.Errorf("unexpected event: %+v", event)
+			}
+
+			got := corev1.Node{}
+			if err := c.Get(ctx, dynclient.ObjectKey{Name: "node-0"}, &got); err != nil {
+				t.Fatalf("failed to get node: %v", err)
+			}
+
+			var operation []corev1.NodeCondition
+			ready := false
+			for _, cond := range got.Status.Conditions {
+				switch cond.Type {
+				case NodeConditionKubeOneOperation:
+					operation = append(operation, cond)
+				case corev1.NodeReady:
+					ready = cond.Status == corev1.ConditionTrue
+				}
+			}
+			if !ready {
+				t.Errorf("expected the Ready condition to be kept, got %+v", got.Status.Conditions)
+			}
+			if len(operation) != 1 {
+				t.Fatalf("expected 1 %s condition, got %+v", NodeConditionKubeOneOperation, got.Status.Conditions)
+			}
+			if operation[0].Reason != EventReasonUpgraded || operation[0].Message != "Node upgraded by KubeOne" || operation[0].Status != corev1.ConditionTrue {
+				t.Errorf("unexpected condition: %+v", operation[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	operatorv1alpha1 "k8c.io/kubeone/pkg/apis/operator/v1alpha1"
+	"k8c.io/kubeone/pkg/clientutil"
+
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/pointer"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	crdName = "kubeoneclusters." + operatorv1alpha1.GroupName
+)
+
+// CustomResourceDefinition returns the CRD of the KubeOneCluster custom
+// resource
+func CustomResourceDefinition() *apiextensions.CustomResourceDefinition {
+	str := func(description string) apiextensions.JSONSchemaProps {
+		return apiextensions.JSONSchemaProps{Type: "string", Description: description}
+	}
+
+	return &apiextensions.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: crdName,
+		},
+		Spec: apiextensions.CustomResourceDefinitionSpec{
+			Group: operatorv1alpha1.GroupName,
+			Names: apiextensions.CustomResourceDefinitionNames{
+				Kind:     "KubeOneCluster",
+				ListKind: "KubeOneClusterList",
+				Plural:   "kubeoneclusters",
+				Singular: "kubeonecluster",
+			},
+			Scope: apiextensions.NamespaceScoped,
+			Versions: []apiextensions.CustomResourceDefinitionVersion{
+				{
+					Name:    operatorv1alpha1.SchemeGroupVersion.Version,
+					Served:  true,
+					Storage: true,
+					Subresources: &apiextensions.CustomResourceSubresources{
+						Status: &apiextensions.CustomResourceSubresourceStatus{},
+					},
+					AdditionalPrinterColumns: []apiextensions.CustomResourceColumnDefinition{
+						{Name: "Phase", Type: "string", JSONPath: ".status.phase"},
+						{Name: "Version", Type: "string", JSONPath: ".status.kubernetesVersion"},
+						{Name: "Last Action", Type: "string", JSONPath: ".status.lastAction"},
+						{Name: "Age", Type: "date", JSONPath: ".metadata.creationTimestamp"},
+					},
+					Schema: &apiextensions.CustomResourceValidation{
+						OpenAPIV3Schema: &apiextensions.JSONSchemaProps{
+							Type:     "object",
+							Required: []string{"spec"},
+							Properties: map[string]apiextensions.JSONSchemaProps{
+								"spec": {
+									Type:     "object",
+									Required: []string{"manifest"},
+									Properties: map[string]apiextensions.JSONSchemaProps{
+										"manifest": {
+											Type:                   "object",
+											Description:            "KubeOneCluster manifest, the same as given to 'kubeone apply'",
+											XPreserveUnknownFields: pointer.BoolPtr(true),
+										},
+										"sshPrivateKeySecretRef": {
+											Type:     "object",
+											Required: []string{"key"},
+											Properties: map[string]apiextensions.JSONSchemaProps{
+												"name": str("name of the Secret"),
+												"key":  str("key of the SSH private key in the Secret"),
+											},
+										},
+										"credentialsSecretRef": {
+											Type: "object",
+											Properties: map[string]apiextensions.JSONSchemaProps{
+												"name": str("name of the Secret holding the cloud provider credentials"),
+											},
+										},
+										"upgradeMachineDeployments": {Type: "boolean"},
+										"paused":                    {Type: "boolean"},
+									},
+								},
+								"status": {
+									Type:                   "object",
+									XPreserveUnknownFields: pointer.BoolPtr(true),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// InstallCRD creates or updates the KubeOneCluster CRD and waits until it's
+// established
+func InstallCRD(ctx context.Context, c dynclient.Client) error {
+	if err := clientutil.CreateOrUpdate(ctx, c, CustomResourceDefinition()); err != nil {
+		return errors.Wrap(err, "failed to install the KubeOneCluster CRD")
+	}
+
+	condFn := clientutil.CRDsReadyCondition(ctx, c, []string{crdName})
+
+	return errors.Wrap(wait.PollImmediate(time.Second, time.Minute, condFn), "failed to wait for the KubeOneCluster CRD to be established")
+}
@@ -0,0 +1,301 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package operator implements the controller reconciling the KubeOneCluster
+// custom resources in the management cluster, by running 'kubeone apply'
+// against the target clusters and storing the result in the status.
+package operator
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/apis/kubeone/config"
+	operatorv1alpha1 "k8c.io/kubeone/pkg/apis/operator/v1alpha1"
+	"k8c.io/kubeone/pkg/client"
+	"k8c.io/kubeone/pkg/logging"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// defaultSSHAgentSocket is the SSH agent socket defaulted for hosts
+	// without the private key file, not available in the operator
+	defaultSSHAgentSocket = "env:SSH_AUTH_SOCK"
+)
+
+// Reconciler reconciles the KubeOneCluster custom resources
+type Reconciler struct {
+	// Client is the client of the management cluster
+	Client dynclient.Client
+	// Logger logs the reconciliation of the clusters
+	Logger logrus.FieldLogger
+	// WorkDir is the directory keeping the files of each cluster, such as
+	// the PKI backups, in the <namespace>/<name> subdirectory
+	WorkDir string
+	// ResyncPeriod is how often the Ready clusters are reconciled again,
+	// correcting the drift. Zero disables resyncing.
+	ResyncPeriod time.Duration
+	// Timeout is the maximum duration of a single reconciliation
+	Timeout time.Duration
+
+	// apply reconciles the target cluster, client.Client.Apply by default
+	apply func(ctx context.Context, cluster *kubeoneapi.KubeOneCluster, opts client.Options, applyOpts client.ApplyOptions) (*client.ApplyResult, error)
+}
+
+// SetupWithManager registers the Reconciler with the manager. Only the spec
+// changes trigger the reconciliation, not the status updates.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, maxConcurrentReconciles int) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&operatorv1alpha1.KubeOneCluster{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconciles}).
+		Complete(r)
+}
+
+// Reconcile runs 'kubeone apply' for the KubeOneCluster
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	obj := &operatorv1alpha1.KubeOneCluster{}
+	if err := r.Client.Get(ctx, req.NamespacedName, obj); err != nil {
+		return reconcile.Result{}, dynclient.IgnoreNotFound(err)
+	}
+
+	logger := r.Logger.WithField(logging.FieldCluster, req.String())
+
+	if obj.Spec.Paused {
+		if obj.Status.Phase == operatorv1alpha1.PhasePaused {
+			return reconcile.Result{}, nil
+		}
+
+		logger.Infoln("Reconciliation is paused")
+		return reconcile.Result{}, r.patchStatus(ctx, obj, func(status *operatorv1alpha1.KubeOneClusterStatus) {
+			status.Phase = operatorv1alpha1.PhasePaused
+		})
+	}
+
+	if err := r.patchStatus(ctx, obj, func(status *operatorv1alpha1.KubeOneClusterStatus) {
+		status.Phase = operatorv1alpha1.PhaseReconciling
+	}); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	logger.Infoln("Reconciling the cluster...")
+	result, version, reconcileErr := r.reconcile(ctx, obj, logger)
+
+	err := r.patchStatus(ctx, obj, func(status *operatorv1alpha1.KubeOneClusterStatus) {
+		now := metav1.Now()
+		status.ObservedGeneration = obj.Generation
+		status.LastReconcileTime = &now
+
+		condition := metav1.Condition{
+			Type:               operatorv1alpha1.ConditionReady,
+			ObservedGeneration: obj.Generation,
+		}
+
+		if reconcileErr != nil {
+			status.Phase = operatorv1alpha1.PhaseFailed
+			status.Message = reconcileErr.Error()
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = "ReconcileFailed"
+			condition.Message = reconcileErr.Error()
+		} else {
+			status.Phase = operatorv1alpha1.PhaseReady
+			status.Message = ""
+			status.KubernetesVersion = version
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = "ReconcileSucceeded"
+		}
+
+		if result != nil {
+			status.LastAction = string(result.Action)
+		}
+
+		meta.SetStatusCondition(&status.Conditions, condition)
+	})
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if reconcileErr != nil {
+		logger.Errorf("Failed to reconcile the cluster: %v", reconcileErr)
+		// The failed reconciliation is retried with the backoff
+		return reconcile.Result{}, reconcileErr
+	}
+
+	logger.Infoln("Cluster reconciled")
+
+	return reconcile.Result{RequeueAfter: r.ResyncPeriod}, nil
+}
+
+// reconcile loads the cluster from the custom resource and applies it,
+// returning the Kubernetes version of the cluster
+func (r *Reconciler) reconcile(ctx context.Context, obj *operatorv1alpha1.KubeOneCluster, logger logrus.FieldLogger) (*client.ApplyResult, string, error) {
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+
+	workDir := filepath.Join(r.WorkDir, obj.Namespace, obj.Name)
+	if err := os.MkdirAll(workDir, 0700); err != nil {
+		return nil, "", errors.Wrap(err, "failed to create the work directory")
+	}
+
+	// Secrets are written to the files only for the time of the
+	// reconciliation
+	secretsDir, err := ioutil.TempDir(workDir, "secrets-")
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to create the secrets directory")
+	}
+	defer os.RemoveAll(secretsDir)
+
+	credentialsFile, credentials, err := r.writeCredentials(ctx, obj, secretsDir)
+	if err != nil {
+		return nil, "", err
+	}
+
+	cluster, err := config.BytesToKubeOneCluster(obj.Spec.Manifest.Raw, nil, credentials, logger)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to load the manifest")
+	}
+
+	if err = r.setSSHPrivateKey(ctx, obj, cluster, secretsDir); err != nil {
+		return nil, "", err
+	}
+
+	manifestFile := filepath.Join(workDir, "kubeone.yaml")
+	if err = ioutil.WriteFile(manifestFile, obj.Spec.Manifest.Raw, 0600); err != nil {
+		return nil, "", errors.Wrap(err, "failed to write the manifest")
+	}
+
+	apply := r.apply
+	if apply == nil {
+		apply = applyCluster
+	}
+
+	result, err := apply(ctx, cluster, client.Options{
+		Logger:              logger,
+		ManifestFilePath:    manifestFile,
+		CredentialsFilePath: credentialsFile,
+	}, client.ApplyOptions{
+		UpgradeMachineDeployments: obj.Spec.UpgradeMachineDeployments,
+	})
+
+	return result, cluster.Versions.Kubernetes, err
+}
+
+// writeCredentials writes the cloud provider credentials from the Secret to
+// the credentials file, returning its path and content, or an empty path if
+// the Secret is not referenced
+func (r *Reconciler) writeCredentials(ctx context.Context, obj *operatorv1alpha1.KubeOneCluster, dir string) (string, []byte, error) {
+	ref := obj.Spec.CredentialsSecretRef
+	if ref == nil {
+		return "", nil, nil
+	}
+
+	secret := corev1.Secret{}
+	if err := r.Client.Get(ctx, dynclient.ObjectKey{Namespace: obj.Namespace, Name: ref.Name}, &secret); err != nil {
+		return "", nil, errors.Wrapf(err, "failed to get the credentials Secret %q", ref.Name)
+	}
+
+	credentials := map[string]string{}
+	for key, value := range secret.Data {
+		credentials[key] = string(value)
+	}
+
+	buf, err := yaml.Marshal(credentials)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to marshal the credentials")
+	}
+
+	path := filepath.Join(dir, "credentials.yaml")
+
+	return path, buf, errors.Wrap(ioutil.WriteFile(path, buf, 0600), "failed to write the credentials")
+}
+
+// setSSHPrivateKey writes the SSH private key from the Secret to the file and
+// sets it for the hosts without their own private key file
+func (r *Reconciler) setSSHPrivateKey(ctx context.Context, obj *operatorv1alpha1.KubeOneCluster, cluster *kubeoneapi.KubeOneCluster, dir string) error {
+	ref := obj.Spec.SSHPrivateKeySecretRef
+	if ref == nil {
+		return nil
+	}
+
+	secret := corev1.Secret{}
+	if err := r.Client.Get(ctx, dynclient.ObjectKey{Namespace: obj.Namespace, Name: ref.Name}, &secret); err != nil {
+		return errors.Wrapf(err, "failed to get the SSH private key Secret %q", ref.Name)
+	}
+
+	key, ok := secret.Data[ref.Key]
+	if !ok {
+		return errors.Errorf("SSH private key Secret %q doesn't have the key %q", ref.Name, ref.Key)
+	}
+
+	path := filepath.Join(dir, "ssh-private-key")
+	if err := ioutil.WriteFile(path, key, 0600); err != nil {
+		return errors.Wrap(err, "failed to write the SSH private key")
+	}
+
+	setKey := func(hosts []kubeoneapi.HostConfig) {
+		for i := range hosts {
+			if hosts[i].SSHPrivateKeyFile != "" {
+				continue
+			}
+
+			hosts[i].SSHPrivateKeyFile = path
+			if hosts[i].SSHAgentSocket == defaultSSHAgentSocket {
+				hosts[i].SSHAgentSocket = ""
+			}
+		}
+	}
+	setKey(cluster.ControlPlane.Hosts)
+	setKey(cluster.StaticWorkers.Hosts)
+
+	return nil
+}
+
+// patchStatus updates the status of the custom resource, without
+// conflicting with the spec changes made in the meantime
+func (r *Reconciler) patchStatus(ctx context.Context, obj *operatorv1alpha1.KubeOneCluster, update func(*operatorv1alpha1.KubeOneClusterStatus)) error {
+	base := obj.DeepCopy()
+	update(&obj.Status)
+
+	return errors.Wrap(r.Client.Status().Patch(ctx, obj, dynclient.MergeFrom(base)), "failed to update the status")
+}
+
+func applyCluster(ctx context.Context, cluster *kubeoneapi.KubeOneCluster, opts client.Options, applyOpts client.ApplyOptions) (*client.ApplyResult, error) {
+	c, err := client.New(cluster, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Apply(ctx, applyOpts)
+}
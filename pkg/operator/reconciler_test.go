@@ -0,0 +1,215 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	operatorv1alpha1 "k8c.io/kubeone/pkg/apis/operator/v1alpha1"
+	"k8c.io/kubeone/pkg/client"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const testManifest = `{
+  "apiVersion": "kubeone.io/v1beta1",
+  "kind": "KubeOneCluster",
+  "name": "edge-1",
+  "versions": {"kubernetes": "1.22.2"},
+  "cloudProvider": {"none": {}},
+  "controlPlane": {"hosts": [{"publicAddress": "192.168.1.1", "privateAddress": "10.0.0.1"}]}
+}`
+
+func newTestObjects(manifest string, paused bool) []dynclient.Object {
+	return []dynclient.Object{
+		&operatorv1alpha1.KubeOneCluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "edge-1"},
+			Spec: operatorv1alpha1.KubeOneClusterSpec{
+				Manifest:               runtime.RawExtension{Raw: []byte(manifest)},
+				SSHPrivateKeySecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "ssh"}, Key: "id_rsa"},
+				CredentialsSecretRef:   &corev1.LocalObjectReference{Name: "credentials"},
+				Paused:                 paused,
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ssh"},
+			Data:       map[string][]byte{"id_rsa": []byte("private key")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "credentials"},
+			Data:       map[string][]byte{"HCLOUD_TOKEN": []byte("token")},
+		},
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	tests := []struct {
+		name       string
+		manifest   string
+		paused     bool
+		applyErr   error
+		phase      operatorv1alpha1.KubeOneClusterPhase
+		lastAction string
+		message    string
+		applied    bool
+	}{
+		{
+			name:       "ready",
+			manifest:   testManifest,
+			phase:      operatorv1alpha1.PhaseReady,
+			lastAction: string(client.ActionInstall),
+			applied:    true,
+		},
+		{
+			name:       "apply failed",
+			manifest:   testManifest,
+			applyErr:   errors.New("ssh: handshake failed"),
+			phase:      operatorv1alpha1.PhaseFailed,
+			lastAction: string(client.ActionInstall),
+			message:    "ssh: handshake failed",
+			applied:    true,
+		},
+		{
+			name:     "invalid manifest",
+			manifest: `{"apiVersion": "kubeone.io/v1beta1", "kind": "KubeOneCluster", "unknown": true}`,
+			phase:    operatorv1alpha1.PhaseFailed,
+			message:  "failed to load the manifest",
+		},
+		{
+			name:     "paused",
+			manifest: testManifest,
+			paused:   true,
+			phase:    operatorv1alpha1.PhasePaused,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := clientgoscheme.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+			if err := operatorv1alpha1.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+
+			workDir, err := ioutil.TempDir("", "kubeone-operator")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(workDir)
+
+			logger := logrus.New()
+			logger.Out = ioutil.Discard
+
+			applied := false
+			r := &Reconciler{
+				Client:       fake.NewClientBuilder().WithScheme(scheme).WithObjects(newTestObjects(tc.manifest, tc.paused)...).Build(),
+				Logger:       logger,
+				WorkDir:      workDir,
+				ResyncPeriod: time.Hour,
+				apply: func(_ context.Context, cluster *kubeoneapi.KubeOneCluster, opts client.Options, _ client.ApplyOptions) (*client.ApplyResult, error) {
+					applied = true
+
+					host := cluster.ControlPlane.Hosts[0]
+					if key, kerr := ioutil.ReadFile(host.SSHPrivateKeyFile); kerr != nil || string(key) != "private key" {
+						t.Errorf("expected the SSH private key to be written, got %q: %v", key, kerr)
+					}
+					if host.SSHAgentSocket != "" {
+						t.Errorf("expected the default SSH agent socket to be unset, got %q", host.SSHAgentSocket)
+					}
+					if creds, cerr := ioutil.ReadFile(opts.CredentialsFilePath); cerr != nil || !strings.Contains(string(creds), "HCLOUD_TOKEN: token") {
+						t.Errorf("expected the credentials to be written, got %q: %v", creds, cerr)
+					}
+
+					return &client.ApplyResult{Action: client.ActionInstall}, tc.applyErr
+				},
+			}
+
+			key := types.NamespacedName{Namespace: "default", Name: "edge-1"}
+			result, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+			if (err != nil) != (tc.phase == operatorv1alpha1.PhaseFailed) {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if applied != tc.applied {
+				t.Errorf("expected applied to be %t", tc.applied)
+			}
+			if tc.phase == operatorv1alpha1.PhaseReady && result.RequeueAfter != time.Hour {
+				t.Errorf("expected requeue after the resync period, got %s", result.RequeueAfter)
+			}
+
+			obj := &operatorv1alpha1.KubeOneCluster{}
+			if err = r.Client.Get(context.Background(), key, obj); err != nil {
+				t.Fatalf("failed to get the cluster: %v", err)
+			}
+			if obj.Status.Phase != tc.phase {
+				t.Errorf("expected phase %q, got %q", tc.phase, obj.Status.Phase)
+			}
+			if obj.Status.LastAction != tc.lastAction {
+				t.Errorf("expected last action %q, got %q", tc.lastAction, obj.Status.LastAction)
+			}
+			if !strings.Contains(obj.Status.Message, tc.message) {
+				t.Errorf("expected message %q, got %q", tc.message, obj.Status.Message)
+			}
+			if tc.phase == operatorv1alpha1.PhaseReady && obj.Status.KubernetesVersion != "1.22.2" {
+				t.Errorf("expected Kubernetes version to be set, got %q", obj.Status.KubernetesVersion)
+			}
+
+			// Secrets are removed once the reconciliation is done
+			files, _ := ioutil.ReadDir(workDir + "/default/edge-1")
+			for _, f := range files {
+				if strings.HasPrefix(f.Name(), "secrets-") {
+					t.Errorf("expected the secrets directory %q to be removed", f.Name())
+				}
+			}
+		})
+	}
+}
+
+func TestReconcileNotFound(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := operatorv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &Reconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Logger: logrus.New(),
+	}
+
+	key := types.NamespacedName{Namespace: "default", Name: "edge-1"}
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: key}); err != nil {
+		t.Errorf("expected deleted cluster to be ignored, got %v", err)
+	}
+}
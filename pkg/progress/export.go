@@ -0,0 +1,165 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progress
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// FormatJSON exports the recorded spans as a JSON list
+	FormatJSON = "json"
+	// FormatOTLP exports the recorded spans as OpenTelemetry spans, using the
+	// OTLP JSON encoding, which can be imported by the OpenTelemetry Collector
+	FormatOTLP = "otlp"
+
+	otlpSpanKindInternal = 1
+	otlpStatusCodeOK     = 1
+	otlpStatusCodeError  = 2
+)
+
+// Export writes all recorded spans in the given format
+func (r *Recorder) Export(w io.Writer, format, clusterName string) error {
+	var doc interface{}
+
+	switch format {
+	case FormatJSON:
+		doc = r.Spans()
+	case FormatOTLP:
+		var err error
+		if doc, err = r.otlp(clusterName); err != nil {
+			return err
+		}
+	default:
+		return errors.Errorf("unknown timings format %q, must be one of: %s, %s", format, FormatJSON, FormatOTLP)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return errors.Wrap(enc.Encode(doc), "failed to encode timings")
+}
+
+type otlpKeyValue struct {
+	Key   string            `json:"key"`
+	Value map[string]string `json:"value"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+func stringAttribute(key, value string) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: map[string]string{"stringValue": value}}
+}
+
+func intAttribute(key string, value int) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: map[string]string{"intValue": strconv.Itoa(value)}}
+}
+
+// otlpSpanID returns the hex-encoded OpenTelemetry span ID of the span
+func otlpSpanID(id int) string {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(id))
+
+	return hex.EncodeToString(buf)
+}
+
+func (r *Recorder) otlp(clusterName string) (interface{}, error) {
+	traceID := make([]byte, 16)
+	if _, err := rand.Read(traceID); err != nil {
+		return nil, errors.Wrap(err, "failed to generate trace ID")
+	}
+
+	now := time.Now()
+	if r != nil {
+		now = r.now()
+	}
+
+	spans := []otlpSpan{}
+	for _, sp := range r.Spans() {
+		end := sp.End
+		if sp.Running() {
+			end = now
+		}
+
+		span := otlpSpan{
+			TraceID:           hex.EncodeToString(traceID),
+			SpanID:            otlpSpanID(sp.ID),
+			Name:              sp.Name,
+			Kind:              otlpSpanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(sp.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(end.UnixNano(), 10),
+			Attributes:        []otlpKeyValue{intAttribute("kubeone.retries", sp.Retries)},
+			Status:            otlpStatus{Code: otlpStatusCodeOK},
+		}
+		if sp.ParentID != 0 {
+			span.ParentSpanID = otlpSpanID(sp.ParentID)
+		}
+		if sp.Subsystem != "" {
+			span.Attributes = append(span.Attributes, stringAttribute("kubeone.subsystem", sp.Subsystem))
+		}
+		if sp.Node != "" {
+			span.Attributes = append(span.Attributes, stringAttribute("kubeone.node", sp.Node))
+		}
+		if sp.Error != "" {
+			span.Status = otlpStatus{Code: otlpStatusCodeError, Message: sp.Error}
+		}
+
+		spans = append(spans, span)
+	}
+
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{
+					"attributes": []otlpKeyValue{
+						stringAttribute("service.name", "kubeone"),
+						stringAttribute("kubeone.cluster", clusterName),
+					},
+				},
+				"scopeSpans": []interface{}{
+					map[string]interface{}{
+						"scope": map[string]string{"name": "k8c.io/kubeone"},
+						"spans": spans,
+					},
+				},
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progress
+
+import (
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+	"github.com/prometheus/common/expfmt"
+)
+
+const (
+	// metricsJob is the Pushgateway job the metrics are pushed to
+	metricsJob = "kubeone"
+)
+
+// metrics returns the registry holding the metrics of the run, computed from
+// the recorded spans, with the given labels added to all metrics. Metrics are
+// gauges describing only the last run, so pushing them to the Pushgateway or
+// writing them to the textfile of the node-exporter textfile collector
+// replaces the metrics of the previous run.
+func (r *Recorder) metrics(runLabels prometheus.Labels) *prometheus.Registry {
+	newGaugeVec := func(name, help string, labels ...string) *prometheus.GaugeVec {
+		return prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        name,
+			Help:        help,
+			ConstLabels: runLabels,
+		}, labels)
+	}
+
+	runDuration := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:        "kubeone_run_duration_seconds",
+		Help:        "Duration of the KubeOne run.",
+		ConstLabels: runLabels,
+	})
+	runSuccess := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:        "kubeone_run_success",
+		Help:        "Whether the KubeOne run succeeded (1) or failed (0).",
+		ConstLabels: runLabels,
+	})
+	runTimestamp := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:        "kubeone_run_timestamp_seconds",
+		Help:        "Unix time of the end of the KubeOne run.",
+		ConstLabels: runLabels,
+	})
+	taskDuration := newGaugeVec("kubeone_task_duration_seconds", "Duration of the task, including retries.", "task", "subsystem")
+	taskRetries := newGaugeVec("kubeone_task_retries", "Number of retries of the task.", "task", "subsystem")
+	taskFailures := newGaugeVec("kubeone_task_failures", "Number of failed runs of the task, after all retries.", "task", "subsystem")
+	hostDuration := newGaugeVec("kubeone_host_step_duration_seconds", "Duration of the task steps running on the host.", "host")
+	hostRetries := newGaugeVec("kubeone_host_step_retries", "Number of retries of the task steps running on the host.", "host")
+	hostFailures := newGaugeVec("kubeone_host_step_failures", "Number of failed task steps running on the host.", "host")
+
+	now := time.Now()
+	if r != nil {
+		now = r.now()
+	}
+
+	var total time.Duration
+	success := true
+	for _, sp := range r.Spans() {
+		duration := sp.Duration(now).Seconds()
+		failed := sp.Running() || sp.Error != ""
+
+		if sp.Node != "" {
+			host := prometheus.Labels{"host": sp.Node}
+			hostDuration.With(host).Add(duration)
+			hostRetries.With(host).Add(float64(sp.Retries))
+			hostFailures.With(host).Add(0)
+			if failed {
+				hostFailures.With(host).Inc()
+			}
+
+			continue
+		}
+
+		task := prometheus.Labels{"task": sp.Name, "subsystem": sp.Subsystem}
+		taskDuration.With(task).Add(duration)
+		taskRetries.With(task).Add(float64(sp.Retries))
+		taskFailures.With(task).Add(0)
+		if failed {
+			taskFailures.With(task).Inc()
+		}
+
+		// Top-level tasks account for the whole run
+		if sp.ParentID == 0 {
+			total += sp.Duration(now)
+			if failed {
+				success = false
+			}
+		}
+	}
+
+	runDuration.Set(total.Seconds())
+	runTimestamp.Set(float64(now.Unix()))
+	if success {
+		runSuccess.Set(1)
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(runDuration, runSuccess, runTimestamp, taskDuration, taskRetries, taskFailures, hostDuration, hostRetries, hostFailures)
+
+	return registry
+}
+
+// ExportMetrics writes the metrics of the run in the Prometheus text format,
+// which can be read by the node-exporter textfile collector
+func (r *Recorder) ExportMetrics(w io.Writer, clusterName, command string) error {
+	runLabels := prometheus.Labels{"cluster": clusterName, "command": command}
+
+	families, err := r.metrics(runLabels).Gather()
+	if err != nil {
+		return errors.Wrap(err, "failed to gather metrics")
+	}
+
+	enc := expfmt.NewEncoder(w, expfmt.FmtText)
+	for _, family := range families {
+		if err := enc.Encode(family); err != nil {
+			return errors.Wrap(err, "failed to encode metrics")
+		}
+	}
+
+	return nil
+}
+
+// PushMetrics pushes the metrics of the run to the Prometheus Pushgateway,
+// replacing the metrics pushed by the previous run of the same command on the
+// same cluster. The cluster and command labels are added by the Pushgateway
+// from the grouping key.
+func (r *Recorder) PushMetrics(url, clusterName, command string) error {
+	err := push.New(url, metricsJob).
+		Grouping("cluster", clusterName).
+		Grouping("command", command).
+		Gatherer(r.metrics(nil)).
+		Push()
+
+	return errors.Wrap(err, "failed to push metrics to the Pushgateway")
+}
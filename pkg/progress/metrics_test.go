@@ -0,0 +1,100 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progress
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExportMetrics(t *testing.T) {
+	buf := bytes.Buffer{}
+	if err := newTestRecorder().ExportMetrics(&buf, "test", "apply"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+
+	expected := []string{
+		`kubeone_run_duration_seconds{cluster="test",command="apply"} 6`,
+		`kubeone_run_success{cluster="test",command="apply"} 0`,
+		`kubeone_task_duration_seconds{cluster="test",command="apply",subsystem="tasks",task="install prerequisites"} 5`,
+		`kubeone_task_failures{cluster="test",command="apply",subsystem="tasks",task="install prerequisites"} 1`,
+		`kubeone_task_failures{cluster="test",command="apply",subsystem="addons",task="deploy addons"} 0`,
+		`kubeone_host_step_duration_seconds{cluster="test",command="apply",host="cp-0"} 2`,
+		`kubeone_host_step_failures{cluster="test",command="apply",host="cp-0"} 0`,
+		`kubeone_host_step_failures{cluster="test",command="apply",host="cp-1"} 1`,
+		`kubeone_host_step_retries{cluster="test",command="apply",host="cp-1"} 1`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("expected metrics to contain %q, got:\n%s", line, out)
+		}
+	}
+}
+
+func TestExportMetricsNilRecorder(t *testing.T) {
+	var r *Recorder
+
+	buf := bytes.Buffer{}
+	if err := r.ExportMetrics(&buf, "test", "apply"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), `kubeone_run_success{cluster="test",command="apply"} 1`) {
+		t.Errorf("expected successful run, got:\n%s", buf.String())
+	}
+}
+
+func TestPushMetrics(t *testing.T) {
+	var method, path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		method, path = req.Method, req.URL.Path
+		b, _ := ioutil.ReadAll(req.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	if err := newTestRecorder().PushMetrics(server.URL, "test", "upgrade"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if method != http.MethodPut {
+		t.Errorf("expected %s request, got %s", http.MethodPut, method)
+	}
+	if expected := "/metrics/job/kubeone/cluster/test/command/upgrade"; path != expected {
+		t.Errorf("expected path %q, got %q", expected, path)
+	}
+	if !strings.Contains(body, "kubeone_run_duration_seconds") {
+		t.Errorf("expected pushed metrics to contain the run duration")
+	}
+}
+
+func TestPushMetricsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	if err := newTestRecorder().PushMetrics(server.URL, "test", "apply"); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
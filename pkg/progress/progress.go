@@ -0,0 +1,269 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progress
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"k8c.io/kubeone/pkg/tabwriter"
+)
+
+// Span is a task, or a step of the task running on a single node
+type Span struct {
+	ID        int       `json:"id"`
+	ParentID  int       `json:"parentID,omitempty"`
+	Name      string    `json:"name"`
+	Subsystem string    `json:"subsystem,omitempty"`
+	Node      string    `json:"node,omitempty"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end,omitempty"`
+	Retries   int       `json:"retries,omitempty"`
+	Error     string    `json:"error,omitempty"`
+
+	recorder *Recorder
+	task     bool
+}
+
+// Running returns whether the span is not ended yet
+func (sp Span) Running() bool {
+	return sp.End.IsZero()
+}
+
+// Duration returns the duration of the span, or the elapsed time if the span
+// is still running
+func (sp Span) Duration(now time.Time) time.Duration {
+	if sp.Running() {
+		return now.Sub(sp.Start)
+	}
+
+	return sp.End.Sub(sp.Start)
+}
+
+// Recorder records the start, end and retries of the tasks and their steps
+// running on each node. All methods are safe to be called on a nil Recorder,
+// in which case nothing is recorded.
+type Recorder struct {
+	lock  sync.Mutex
+	spans []*Span
+	// tasks is the stack of the running tasks, the last one is the parent of
+	// the newly started spans
+	tasks []*Span
+	now   func() time.Time
+	// notify is called with the copy of the span each time it's started,
+	// retried or finished
+	notify func(Span)
+}
+
+// NewRecorder returns a new Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{now: time.Now}
+}
+
+// Notify registers the function called with the copy of the span each time
+// the span is started, retried or finished. The function is called outside of
+// the Recorder lock, but it must not block, because the tasks wait for it.
+func (r *Recorder) Notify(fn func(Span)) {
+	if r == nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.notify = fn
+}
+
+// StartTask records the start of the task
+func (r *Recorder) StartTask(name, subsystem string) *Span {
+	if r == nil {
+		return nil
+	}
+
+	r.lock.Lock()
+	sp := r.newSpan(name, subsystem, "")
+	sp.task = true
+	r.tasks = append(r.tasks, sp)
+	r.unlockAndNotify(sp)
+
+	return sp
+}
+
+// StartNode records the start of the task step running on the node
+func (r *Recorder) StartNode(name, subsystem, node string) *Span {
+	if r == nil {
+		return nil
+	}
+
+	r.lock.Lock()
+	sp := r.newSpan(name, subsystem, node)
+	r.unlockAndNotify(sp)
+
+	return sp
+}
+
+// unlockAndNotify releases the lock and calls the notify function with the
+// copy of the span taken while the lock was held
+func (r *Recorder) unlockAndNotify(sp *Span) {
+	notify, copied := r.notify, *sp
+	r.lock.Unlock()
+
+	if notify != nil {
+		notify(copied)
+	}
+}
+
+func (r *Recorder) newSpan(name, subsystem, node string) *Span {
+	sp := &Span{
+		ID:        len(r.spans) + 1,
+		Name:      name,
+		Subsystem: subsystem,
+		Node:      node,
+		Start:     r.now(),
+		recorder:  r,
+	}
+	if len(r.tasks) > 0 {
+		sp.ParentID = r.tasks[len(r.tasks)-1].ID
+	}
+	r.spans = append(r.spans, sp)
+
+	return sp
+}
+
+// Retry records a retry of the span
+func (sp *Span) Retry() {
+	if sp == nil {
+		return
+	}
+
+	sp.recorder.lock.Lock()
+	sp.Retries++
+	sp.recorder.unlockAndNotify(sp)
+}
+
+// Finish records the end of the span, along with the error it failed with
+func (sp *Span) Finish(err error) {
+	if sp == nil {
+		return
+	}
+
+	r := sp.recorder
+	r.lock.Lock()
+	defer r.unlockAndNotify(sp)
+
+	sp.End = r.now()
+	if err != nil {
+		sp.Error = err.Error()
+	}
+
+	if sp.task {
+		for i := len(r.tasks) - 1; i >= 0; i-- {
+			if r.tasks[i] == sp {
+				r.tasks = append(r.tasks[:i], r.tasks[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// Spans returns copies of all recorded spans, in the order they were started
+func (r *Recorder) Spans() []Span {
+	if r == nil {
+		return nil
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	spans := make([]Span, 0, len(r.spans))
+	for _, sp := range r.spans {
+		spans = append(spans, *sp)
+	}
+
+	return spans
+}
+
+// WriteSummary writes the table with durations and retries of all recorded
+// spans, followed by the slowest tasks
+func (r *Recorder) WriteSummary(w io.Writer) error {
+	spans := r.Spans()
+	if len(spans) == 0 {
+		return nil
+	}
+	now := r.now()
+
+	depth := map[int]int{}
+	printer := tabwriter.GetNewTabWriter(w)
+	fmt.Fprintln(printer, "TASK\tNODE\tDURATION\tRETRIES\tSTATUS")
+	for _, sp := range spans {
+		if sp.ParentID != 0 {
+			depth[sp.ID] = depth[sp.ParentID] + 1
+		}
+
+		indent := ""
+		for i := 0; i < depth[sp.ID]; i++ {
+			indent += "  "
+		}
+
+		fmt.Fprintf(printer, "%s%s\t%s\t%s\t%d\t%s\n", indent, sp.Name, sp.Node, formatDuration(sp.Duration(now)), sp.Retries, status(sp))
+	}
+	if err := printer.Flush(); err != nil {
+		return err
+	}
+
+	// Top-level tasks account for the whole run
+	var total time.Duration
+	topLevel := []Span{}
+	for _, sp := range spans {
+		if sp.ParentID == 0 {
+			total += sp.Duration(now)
+			topLevel = append(topLevel, sp)
+		}
+	}
+	sort.SliceStable(topLevel, func(i, j int) bool {
+		return topLevel[i].Duration(now) > topLevel[j].Duration(now)
+	})
+	if len(topLevel) > 3 {
+		topLevel = topLevel[:3]
+	}
+
+	fmt.Fprintf(w, "\nTotal: %s\n", formatDuration(total))
+	fmt.Fprintln(w, "Slowest tasks:")
+	for _, sp := range topLevel {
+		fmt.Fprintf(w, "\t%s: %s\n", sp.Name, formatDuration(sp.Duration(now)))
+	}
+
+	return nil
+}
+
+func status(sp Span) string {
+	switch {
+	case sp.Running():
+		return "running"
+	case sp.Error != "":
+		return "failed"
+	default:
+		return "done"
+	}
+}
+
+func formatDuration(d time.Duration) string {
+	return d.Round(100 * time.Millisecond).String()
+}
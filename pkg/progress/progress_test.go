@@ -0,0 +1,242 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progress
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// fakeClock advances by one second on every call
+func fakeClock() func() time.Time {
+	now := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	return func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+}
+
+func newTestRecorder() *Recorder {
+	r := NewRecorder()
+	r.now = fakeClock()
+
+	task := r.StartTask("install prerequisites", "tasks")
+	node1 := r.StartNode("install prerequisites", "tasks", "cp-0")
+	node2 := r.StartNode("install prerequisites", "tasks", "cp-1")
+	node2.Retry()
+	node1.Finish(nil)
+	node2.Finish(errors.New("connection refused"))
+	task.Finish(errors.New("connection refused"))
+
+	r.StartTask("deploy addons", "addons").Finish(nil)
+
+	return r
+}
+
+func TestRecorder(t *testing.T) {
+	spans := newTestRecorder().Spans()
+
+	if len(spans) != 4 {
+		t.Fatalf("expected 4 spans, got %d", len(spans))
+	}
+
+	tests := []struct {
+		name     string
+		span     Span
+		parentID int
+		node     string
+		duration time.Duration
+		retries  int
+		err      string
+	}{
+		{
+			name:     "task",
+			span:     spans[0],
+			duration: 5 * time.Second,
+			err:      "connection refused",
+		},
+		{
+			name:     "first node",
+			span:     spans[1],
+			parentID: spans[0].ID,
+			node:     "cp-0",
+			duration: 2 * time.Second,
+		},
+		{
+			name:     "retried node",
+			span:     spans[2],
+			parentID: spans[0].ID,
+			node:     "cp-1",
+			duration: 2 * time.Second,
+			retries:  1,
+			err:      "connection refused",
+		},
+		{
+			name:     "second task",
+			span:     spans[3],
+			duration: time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.span.ParentID != tt.parentID {
+				t.Errorf("ParentID = %d, want %d", tt.span.ParentID, tt.parentID)
+			}
+			if tt.span.Node != tt.node {
+				t.Errorf("Node = %q, want %q", tt.span.Node, tt.node)
+			}
+			if tt.span.Running() {
+				t.Errorf("span is still running")
+			}
+			if got := tt.span.Duration(time.Time{}); got != tt.duration {
+				t.Errorf("Duration = %s, want %s", got, tt.duration)
+			}
+			if tt.span.Retries != tt.retries {
+				t.Errorf("Retries = %d, want %d", tt.span.Retries, tt.retries)
+			}
+			if tt.span.Error != tt.err {
+				t.Errorf("Error = %q, want %q", tt.span.Error, tt.err)
+			}
+		})
+	}
+}
+
+func TestNilRecorder(t *testing.T) {
+	var r *Recorder
+
+	span := r.StartTask("task", "tasks")
+	span.Retry()
+	span.Finish(nil)
+
+	if spans := r.Spans(); len(spans) != 0 {
+		t.Errorf("expected no spans, got %d", len(spans))
+	}
+	if line := r.StatusLine(time.Second); line != "" {
+		t.Errorf("expected empty status line, got %q", line)
+	}
+}
+
+func TestRecorderNotify(t *testing.T) {
+	r := NewRecorder()
+	r.now = fakeClock()
+
+	events := []string{}
+	r.Notify(func(sp Span) {
+		// Spans must be readable from the callback without a deadlock
+		_ = r.Spans()
+		events = append(events, fmt.Sprintf("%s/%s %s", sp.Name, sp.Node, status(sp)))
+	})
+
+	task := r.StartTask("install prerequisites", "tasks")
+	node := r.StartNode("install prerequisites", "tasks", "cp-0")
+	node.Finish(errors.New("connection refused"))
+	task.Finish(nil)
+
+	expected := []string{
+		"install prerequisites/ running",
+		"install prerequisites/cp-0 running",
+		"install prerequisites/cp-0 failed",
+		"install prerequisites/ done",
+	}
+	if strings.Join(events, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected events %q, got %q", expected, events)
+	}
+}
+
+func TestWriteSummary(t *testing.T) {
+	buf := bytes.Buffer{}
+	if err := newTestRecorder().WriteSummary(&buf); err != nil {
+		t.Fatalf("failed to write summary: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"TASK",
+		"  install prerequisites",
+		"cp-1",
+		"failed",
+		"Total: 6s",
+		"install prerequisites: 5s",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary doesn't contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestStatusLine(t *testing.T) {
+	r := NewRecorder()
+	r.now = fakeClock()
+
+	r.StartTask("upgrade control plane", "tasks")
+	node := r.StartNode("upgrade control plane", "tasks", "cp-0")
+	node.Retry()
+
+	got := r.StatusLine(time.Minute)
+	for _, want := range []string{"[1m0s]", "upgrade control plane", "retry 1", "cp-0"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("status line %q doesn't contain %q", got, want)
+		}
+	}
+}
+
+func TestExportOTLP(t *testing.T) {
+	buf := bytes.Buffer{}
+	if err := newTestRecorder().Export(&buf, FormatOTLP, "test"); err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+
+	doc := struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []otlpSpan `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}{}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("failed to decode exported spans: %v", err)
+	}
+
+	spans := doc.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 4 {
+		t.Fatalf("expected 4 spans, got %d", len(spans))
+	}
+	if spans[1].ParentSpanID != spans[0].SpanID {
+		t.Errorf("parentSpanId = %q, want %q", spans[1].ParentSpanID, spans[0].SpanID)
+	}
+	if spans[0].TraceID != spans[3].TraceID {
+		t.Errorf("spans have different trace IDs")
+	}
+	if spans[2].Status.Code != otlpStatusCodeError || spans[2].Status.Message != "connection refused" {
+		t.Errorf("unexpected status %+v", spans[2].Status)
+	}
+}
+
+func TestExportUnknownFormat(t *testing.T) {
+	if err := newTestRecorder().Export(&bytes.Buffer{}, "xml", "test"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progress
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+const (
+	// clearLine moves the cursor to the beginning of the line and clears it
+	clearLine = "\r\033[K"
+
+	defaultTerminalWidth = 120
+)
+
+// Terminal shows the running task and its steps on each node in the status
+// line at the bottom of the terminal. Everything written to the Terminal,
+// such as log entries, is printed above the status line.
+type Terminal struct {
+	out      *os.File
+	recorder *Recorder
+	start    time.Time
+
+	lock sync.Mutex
+	// drawn is whether the status line is shown
+	drawn bool
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// IsTerminal returns whether the file is a terminal, so the status line can
+// be shown
+func IsTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// NewTerminal returns the Terminal showing the spans recorded by the recorder
+func NewTerminal(out *os.File, recorder *Recorder) *Terminal {
+	return &Terminal{
+		out:      out,
+		recorder: recorder,
+		start:    time.Now(),
+	}
+}
+
+// Start refreshes the status line in the given interval, until Stop is called
+func (t *Terminal) Start(interval time.Duration) {
+	t.stop = make(chan struct{})
+	t.done = make(chan struct{})
+
+	go func() {
+		defer close(t.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-t.stop:
+				return
+			case <-ticker.C:
+				t.lock.Lock()
+				t.redraw()
+				t.lock.Unlock()
+			}
+		}
+	}()
+}
+
+// Stop stops refreshing and clears the status line
+func (t *Terminal) Stop() {
+	if t.stop == nil {
+		return
+	}
+
+	close(t.stop)
+	<-t.done
+	t.stop = nil
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.clear()
+}
+
+// Write writes p above the status line
+func (t *Terminal) Write(p []byte) (int, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.clear()
+	n, err := t.out.Write(p)
+	if t.stop != nil {
+		t.redraw()
+	}
+
+	return n, err
+}
+
+func (t *Terminal) clear() {
+	if t.drawn {
+		fmt.Fprint(t.out, clearLine)
+		t.drawn = false
+	}
+}
+
+// redraw shows the status line if any task is running, so the status line
+// doesn't interfere with the prompts
+func (t *Terminal) redraw() {
+	line := t.recorder.StatusLine(time.Since(t.start))
+	if line == "" {
+		t.clear()
+		return
+	}
+
+	width, _, err := term.GetSize(int(t.out.Fd()))
+	if err != nil || width <= 0 {
+		width = defaultTerminalWidth
+	}
+	if len(line) >= width {
+		line = line[:width-1]
+	}
+
+	fmt.Fprint(t.out, clearLine+line)
+	t.drawn = true
+}
+
+// StatusLine returns the line describing the innermost running task and its
+// steps running on each node, prefixed with the elapsed time. An empty string
+// is returned if no task is running.
+func (r *Recorder) StatusLine(elapsed time.Duration) string {
+	now := time.Now()
+	if r != nil {
+		now = r.now()
+	}
+
+	var task *Span
+	nodes := []string{}
+	spans := r.Spans()
+	for i := range spans {
+		sp := spans[i]
+		if sp.Running() && sp.Node == "" {
+			task = &spans[i]
+		}
+	}
+
+	if task == nil {
+		return ""
+	}
+
+	for _, sp := range spans {
+		if !sp.Running() || sp.Node == "" || sp.ParentID != task.ID {
+			continue
+		}
+
+		node := fmt.Sprintf("%s %s", sp.Node, sp.Duration(now).Round(time.Second))
+		if sp.Retries > 0 {
+			node += fmt.Sprintf(", retry %d", sp.Retries)
+		}
+		nodes = append(nodes, node)
+	}
+
+	status := fmt.Sprintf("[%s] %s (%s", elapsed.Round(time.Second), task.Name, task.Duration(now).Round(time.Second))
+	if task.Retries > 0 {
+		status += fmt.Sprintf(", retry %d", task.Retries)
+	}
+	status += ")"
+	if len(nodes) > 0 {
+		status += ": " + strings.Join(nodes, "; ")
+	}
+
+	return status
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scripts
+
+import "github.com/MakeNowJust/heredoc/v2"
+
+const (
+	// CARotationDir holds the new Kubernetes CA on the control plane nodes
+	// while the CA is being rotated
+	CARotationDir = "/etc/kubernetes/pki/kubeone-ca-rotation"
+	// CARotationUploadDir is the directory, relative to the work directory,
+	// the CA rotation files are uploaded to
+	CARotationUploadDir = "ca-rotation"
+	// ExternalCAUploadDir is the directory, relative to the work directory,
+	// the files issued by the external CA are uploaded to
+	ExternalCAUploadDir = "external-ca"
+)
+
+var (
+	certificatesRenewScriptTemplate = heredoc.Doc(`
+		{{ if .ALPHA -}}
+		sudo kubeadm alpha certs renew all
+		{{- else -}}
+		sudo kubeadm certs renew all
+		{{- end }}
+	`)
+
+	restartControlPlaneStaticPodsScriptTemplate = heredoc.Doc(`
+		# The stopped containers are recreated by kubelet, and the new
+		# containers pick up the renewed certificates
+		for name in kube-apiserver kube-controller-manager kube-scheduler etcd; do
+			ids=$(sudo crictl ps --name="^$name\$" -q)
+			if [ -n "$ids" ]; then
+				sudo crictl stop $ids
+			fi
+		done
+	`)
+
+	caRotationInstallScriptTemplate = heredoc.Doc(`
+		dir="{{ .WORK_DIR }}/{{ .UPLOAD_DIR }}"
+		pki=/etc/kubernetes/pki
+
+		sudo install --owner=0 --group=0 --mode=0644 "$dir/ca.crt" "$pki/ca.crt"
+		if sudo test -f "$dir/ca.key"; then
+			sudo install --owner=0 --group=0 --mode=0600 "$dir/ca.key" "$pki/ca.key"
+		fi
+
+		if sudo test -f "$dir/rotation-ca.crt"; then
+			sudo mkdir -p "{{ .ROTATION_DIR }}"
+			sudo install --owner=0 --group=0 --mode=0644 "$dir/rotation-ca.crt" "{{ .ROTATION_DIR }}/ca.crt"
+			sudo install --owner=0 --group=0 --mode=0600 "$dir/rotation-ca.key" "{{ .ROTATION_DIR }}/ca.key"
+		fi
+
+		# The trusted CAs are embedded in the kubeconfig files
+		ca_data=$(sudo base64 -w0 "$pki/ca.crt")
+		for conf in admin.conf controller-manager.conf scheduler.conf kubelet.conf; do
+			if sudo test -f "/etc/kubernetes/$conf"; then
+				sudo sed -i "s|certificate-authority-data: .*|certificate-authority-data: $ca_data|" "/etc/kubernetes/$conf"
+			fi
+		done
+
+		if sudo test -f "$dir/kubelet-client.pem"; then
+			cert="/var/lib/kubelet/pki/kubelet-client-kubeone-$(date +%s).pem"
+			sudo install --owner=0 --group=0 --mode=0600 "$dir/kubelet-client.pem" "$cert"
+			sudo ln -sf "$cert" /var/lib/kubelet/pki/kubelet-client-current.pem
+		fi
+
+		{{ if .FINALIZE -}}
+		sudo rm -rf "{{ .ROTATION_DIR }}"
+		{{ end -}}
+		sudo rm -rf "$dir"
+		sudo systemctl restart kubelet
+	`)
+
+	externalCAInstallScriptTemplate = heredoc.Doc(`
+		dir="{{ .WORK_DIR }}/{{ .UPLOAD_DIR }}"
+
+		for file in $(cd "$dir" && find . -type f); do
+			file="${file#./}"
+			mode=0600
+			case "$file" in
+				*.crt|*.pub) mode=0644 ;;
+			esac
+			sudo install -D --owner=0 --group=0 --mode=$mode "$dir/$file" "/etc/kubernetes/$file"
+		done
+
+		sudo rm -rf "$dir"
+	`)
+)
+
+// CertificatesRenew renews all certificates managed by kubeadm. The alpha
+// command is used by the kubeadm versions older than 1.20.
+func CertificatesRenew(alpha bool) (string, error) {
+	return Render(certificatesRenewScriptTemplate, Data{
+		"ALPHA": alpha,
+	})
+}
+
+// RestartControlPlaneStaticPods restarts the containers of the control plane
+// static pods
+func RestartControlPlaneStaticPods() (string, error) {
+	return Render(restartControlPlaneStaticPodsScriptTemplate, Data{})
+}
+
+// CARotationInstall installs the trusted CAs (ca.crt) and optionally the CA
+// key (ca.key), the new CA being rotated to (rotation-ca.crt and
+// rotation-ca.key) and the kubelet client certificate (kubelet-client.pem),
+// uploaded to the CARotationUploadDir in the work directory. The kubeconfig
+// files are updated to trust the installed CAs, and kubelet is restarted.
+// If finalize is set, the new CA is removed from the CARotationDir.
+func CARotationInstall(workdir string, finalize bool) (string, error) {
+	return Render(caRotationInstallScriptTemplate, Data{
+		"WORK_DIR":     workdir,
+		"UPLOAD_DIR":   CARotationUploadDir,
+		"ROTATION_DIR": CARotationDir,
+		"FINALIZE":     finalize,
+	})
+}
+
+// ExternalCAInstall installs the CA, certificates and kubeconfig files issued
+// by the external CA, uploaded to the ExternalCAUploadDir in the work
+// directory, to the same paths relative to /etc/kubernetes
+func ExternalCAInstall(workdir string) (string, error) {
+	return Render(externalCAInstallScriptTemplate, Data{
+		"WORK_DIR":   workdir,
+		"UPLOAD_DIR": ExternalCAUploadDir,
+	})
+}
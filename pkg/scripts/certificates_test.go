@@ -0,0 +1,54 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scripts
+
+import (
+	"testing"
+
+	"k8c.io/kubeone/pkg/testhelper"
+)
+
+func TestCertificatesScripts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		script func() (string, error)
+	}{
+		{name: "renew", script: func() (string, error) { return CertificatesRenew(false) }},
+		{name: "renew-alpha", script: func() (string, error) { return CertificatesRenew(true) }},
+		{name: "restart-static-pods", script: RestartControlPlaneStaticPods},
+		{name: "ca-rotation-install", script: func() (string, error) { return CARotationInstall("test-wd", false) }},
+		{name: "ca-rotation-finalize", script: func() (string, error) { return CARotationInstall("test-wd", true) }},
+		{name: "external-ca-install", script: func() (string, error) { return ExternalCAInstall("test-wd") }},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.script()
+			if err != nil {
+				t.Errorf("script error = %v", err)
+				return
+			}
+
+			testhelper.DiffOutput(t, testhelper.FSGoldenName(t), got, *updateFlag)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scripts
+
+import (
+	"github.com/MakeNowJust/heredoc/v2"
+
+	"k8c.io/kubeone/pkg/apis/kubeone"
+)
+
+var (
+	chronyDebianScriptTemplate = heredoc.Doc(`
+		sudo apt-get update
+		sudo DEBIAN_FRONTEND=noninteractive apt-get install -y chrony
+
+		{{ template "chrony-config" . }}
+
+		sudo systemctl enable chrony
+		sudo systemctl restart chrony
+	`)
+
+	chronyCentOSScriptTemplate = heredoc.Doc(`
+		sudo yum install -y chrony
+
+		{{ template "chrony-config" . }}
+
+		# chronyd conflicts with systemd-timesyncd
+		sudo systemctl disable --now systemd-timesyncd.service 2>/dev/null || true
+		sudo systemctl enable chronyd
+		sudo systemctl restart chronyd
+	`)
+
+	timesyncdFlatcarScriptTemplate = heredoc.Doc(`
+		{{- if .NTP_SERVERS }}
+		sudo mkdir -p /etc/systemd/timesyncd.conf.d
+		cat <<EOF | sudo tee /etc/systemd/timesyncd.conf.d/kubeone.conf
+		[Time]
+		NTP={{ join " " .NTP_SERVERS }}
+		EOF
+		{{- end }}
+
+		sudo systemctl enable systemd-timesyncd
+		sudo systemctl restart systemd-timesyncd
+	`)
+)
+
+// ChronyDebian returns the script installing and configuring chrony on
+// Debian and Ubuntu
+func ChronyDebian(cluster *kubeone.KubeOneCluster) (string, error) {
+	return Render(chronyDebianScriptTemplate, chronyData(cluster, "/etc/chrony/chrony.conf"))
+}
+
+// ChronyCentOS returns the script installing and configuring chrony on
+// CentOS, RHEL and Amazon Linux 2
+func ChronyCentOS(cluster *kubeone.KubeOneCluster) (string, error) {
+	return Render(chronyCentOSScriptTemplate, chronyData(cluster, "/etc/chrony.conf"))
+}
+
+// TimesyncdFlatcar returns the script configuring systemd-timesyncd on
+// Flatcar Linux, which doesn't ship chrony
+func TimesyncdFlatcar(cluster *kubeone.KubeOneCluster) (string, error) {
+	return Render(timesyncdFlatcarScriptTemplate, chronyData(cluster, ""))
+}
+
+func chronyData(cluster *kubeone.KubeOneCluster, config string) Data {
+	var servers []string
+	if cluster.TimeSync != nil {
+		servers = cluster.TimeSync.NTPServers
+	}
+
+	return Data{
+		"CHRONY_CONFIG": config,
+		"NTP_SERVERS":   servers,
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scripts
+
+import (
+	"testing"
+
+	"k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/testhelper"
+)
+
+func withNTPServers(servers ...string) genClusterOpts {
+	return func(cls *kubeone.KubeOneCluster) {
+		cls.TimeSync = &kubeone.TimeSyncConfig{
+			Chrony:     true,
+			NTPServers: servers,
+		}
+	}
+}
+
+func TestChronyScripts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		script  func(*kubeone.KubeOneCluster) (string, error)
+		cluster kubeone.KubeOneCluster
+	}{
+		{
+			name:    "debian",
+			script:  ChronyDebian,
+			cluster: genCluster(withNTPServers()),
+		},
+		{
+			name:    "debian with ntp servers",
+			script:  ChronyDebian,
+			cluster: genCluster(withNTPServers("0.pool.ntp.org", "ntp.example.com")),
+		},
+		{
+			name:    "centos with ntp servers",
+			script:  ChronyCentOS,
+			cluster: genCluster(withNTPServers("0.pool.ntp.org", "ntp.example.com")),
+		},
+		{
+			name:    "flatcar",
+			script:  TimesyncdFlatcar,
+			cluster: genCluster(withNTPServers()),
+		},
+		{
+			name:    "flatcar with ntp servers",
+			script:  TimesyncdFlatcar,
+			cluster: genCluster(withNTPServers("0.pool.ntp.org", "ntp.example.com")),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.script(&tt.cluster)
+			if err != nil {
+				t.Errorf("script error = %v", err)
+				return
+			}
+
+			testhelper.DiffOutput(t, testhelper.FSGoldenName(t), got, *updateFlag)
+		})
+	}
+}
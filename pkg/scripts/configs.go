@@ -66,6 +66,34 @@ var (
 	deleteEncryptionProvidersConfigTemplate = heredoc.Doc(`
 		sudo rm -rf /etc/kubernetes/encryption-providers/*
 	`)
+
+	staticPodsTemplate = heredoc.Doc(`
+		sudo mkdir -p {{ .STATIC_PODS_DIR }}
+		{{- range $name, $manifest := .STATIC_PODS }}
+
+		cat <<'EOF' | sudo tee {{ $.STATIC_PODS_DIR }}/{{ $.PREFIX }}{{ $name }}.yaml >/dev/null
+		{{ $manifest | trim }}
+		EOF
+		sudo chmod 600 {{ $.STATIC_PODS_DIR }}/{{ $.PREFIX }}{{ $name }}.yaml
+		{{- end }}
+
+		for manifest in $(sudo find {{ .STATIC_PODS_DIR }} -maxdepth 1 -name '{{ .PREFIX }}*.yaml'); do
+			case "$(basename "$manifest")" in
+			{{- range $name, $_ := .STATIC_PODS }}
+			{{ $.PREFIX }}{{ $name }}.yaml) ;;
+			{{- end }}
+			*) sudo rm -f "$manifest" ;;
+			esac
+		done
+	`)
+)
+
+const (
+	// StaticPodsDir is the kubelet static pod path, as configured by kubeadm
+	StaticPodsDir = "/etc/kubernetes/manifests"
+	// StaticPodManifestPrefix is the prefix of the static pod manifests
+	// managed by KubeOne, used to tell them apart from the kubeadm ones
+	StaticPodManifestPrefix = "kubeone-"
 )
 
 func SaveCloudConfig(workdir string) (string, error) {
@@ -104,3 +132,14 @@ func SaveCABundle(workdir string) (string, error) {
 		"WORK_DIR":           workdir,
 	})
 }
+
+// SaveStaticPods saves the static pod manifests, given by the name, to the
+// kubelet static pod path, and removes the previously saved manifests which
+// are not given anymore
+func SaveStaticPods(manifests map[string]string) (string, error) {
+	return Render(staticPodsTemplate, Data{
+		"STATIC_PODS":     manifests,
+		"STATIC_PODS_DIR": StaticPodsDir,
+		"PREFIX":          StaticPodManifestPrefix,
+	})
+}
@@ -73,3 +73,34 @@ func TestSaveAuditPolicyConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestSaveStaticPods(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		manifests map[string]string
+	}{
+		{name: "no-static-pods"},
+		{
+			name: "static-pods",
+			manifests: map[string]string{
+				"node-cache":      "apiVersion: v1\nkind: Pod\nmetadata:\n  name: node-cache\n",
+				"licensing-agent": "apiVersion: v1\nkind: Pod\nmetadata:\n  name: licensing-agent\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SaveStaticPods(tt.manifests)
+			if err != nil {
+				t.Errorf("SaveStaticPods() error = %v", err)
+				return
+			}
+
+			testhelper.DiffOutput(t, testhelper.FSGoldenName(t), got, *updateFlag)
+		})
+	}
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scripts
+
+import (
+	"strings"
+
+	"github.com/MakeNowJust/heredoc/v2"
+
+	"k8c.io/kubeone/pkg/apis/kubeone"
+)
+
+var (
+	firewallScriptTemplate = heredoc.Doc(`
+		backend="{{ .BACKEND }}"
+		if [ -z "$backend" ]; then
+			if sudo systemctl is-active --quiet firewalld; then
+				backend=firewalld
+			elif command -v ufw >/dev/null 2>&1; then
+				backend=ufw
+			else
+				echo "neither firewalld nor ufw found, not creating firewall rules"
+				exit 0
+			fi
+		fi
+
+		case "$backend" in
+		firewalld)
+			{{- range .PUBLIC_RULES }}
+			sudo firewall-cmd --permanent --add-port={{ .Port }}/{{ .Protocol }}
+			{{- end }}
+			{{- range $source := .SOURCES }}
+			{{- range $.RULES }}
+			sudo firewall-cmd --permanent --add-rich-rule='rule family="{{ if contains ":" $source }}ipv6{{ else }}ipv4{{ end }}" source address="{{ $source }}" port port="{{ .Port }}" protocol="{{ .Protocol }}" accept'
+			{{- end }}
+			{{- end }}
+			sudo firewall-cmd --reload
+			;;
+		ufw)
+			{{- range .PUBLIC_RULES }}
+			sudo ufw allow {{ replace "-" ":" .Port }}/{{ .Protocol }}
+			{{- end }}
+			{{- range $source := .SOURCES }}
+			{{- range $.RULES }}
+			sudo ufw allow proto {{ .Protocol }} from {{ $source }} to any port {{ replace "-" ":" .Port }}
+			{{- end }}
+			{{- end }}
+			;;
+		nftables)
+			if ! command -v nft >/dev/null 2>&1; then
+				echo "nft is required by the nftables firewall backend"
+				exit 1
+			fi
+
+			sudo mkdir -p /etc/kubeone
+			cat <<'EOF' | sudo tee /etc/kubeone/firewall.nft
+		table inet kubeone
+		delete table inet kubeone
+		table inet kubeone {
+			chain input {
+				type filter hook input priority -10; policy accept;
+				iif lo accept
+				ct state established,related accept
+				{{- if .SOURCES_V4 }}
+				ip saddr { {{ join ", " .SOURCES_V4 }} } accept
+				{{- end }}
+				{{- if .SOURCES_V6 }}
+				ip6 saddr { {{ join ", " .SOURCES_V6 }} } accept
+				{{- end }}
+				{{- if .TCP_PORTS }}
+				tcp dport { {{ join ", " .TCP_PORTS }} } drop
+				{{- end }}
+				{{- if .UDP_PORTS }}
+				udp dport { {{ join ", " .UDP_PORTS }} } drop
+				{{- end }}
+			}
+		}
+		EOF
+			cat <<EOF | sudo tee /etc/systemd/system/kubeone-firewall.service
+		[Unit]
+		Description=KubeOne firewall rules
+		Wants=network-pre.target
+		Before=network-pre.target kubelet.service
+
+		[Service]
+		Type=oneshot
+		RemainAfterExit=yes
+		ExecStart=$(command -v nft) -f /etc/kubeone/firewall.nft
+
+		[Install]
+		WantedBy=multi-user.target
+		EOF
+			sudo systemctl daemon-reload
+			sudo systemctl enable kubeone-firewall.service
+			sudo systemctl restart kubeone-firewall.service
+			;;
+		*)
+			echo "unsupported firewall backend: $backend"
+			exit 1
+			;;
+		esac
+	`)
+)
+
+// FirewallRule is the port, or the port range such as 30000-32767, and the
+// protocol allowed by the firewall
+type FirewallRule struct {
+	Protocol string
+	Port     string
+}
+
+// Firewall returns the script allowing the rules from the sources, and the
+// public rules from everywhere, using the firewall backend. If the backend is
+// empty, it's detected on the host.
+//
+// The nftables backend is different: it drops the traffic to the rules'
+// ports unless it comes from the sources, without changing other traffic.
+func Firewall(backend kubeone.FirewallBackend, sources []string, publicRules, rules []FirewallRule) (string, error) {
+	var sourcesV4, sourcesV6, tcpPorts, udpPorts []string
+
+	for _, source := range sources {
+		if strings.Contains(source, ":") {
+			sourcesV6 = append(sourcesV6, source)
+		} else {
+			sourcesV4 = append(sourcesV4, source)
+		}
+	}
+
+	for _, rule := range rules {
+		switch rule.Protocol {
+		case "tcp":
+			tcpPorts = append(tcpPorts, rule.Port)
+		case "udp":
+			udpPorts = append(udpPorts, rule.Port)
+		}
+	}
+
+	return Render(firewallScriptTemplate, Data{
+		"BACKEND":      string(backend),
+		"SOURCES":      sources,
+		"SOURCES_V4":   sourcesV4,
+		"SOURCES_V6":   sourcesV6,
+		"RULES":        rules,
+		"PUBLIC_RULES": publicRules,
+		"TCP_PORTS":    tcpPorts,
+		"UDP_PORTS":    udpPorts,
+	})
+}
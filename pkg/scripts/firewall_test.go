@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scripts
+
+import (
+	"testing"
+
+	"k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/testhelper"
+)
+
+func TestFirewall(t *testing.T) {
+	t.Parallel()
+
+	publicRules := []FirewallRule{{Protocol: "tcp", Port: "6443"}}
+	rules := []FirewallRule{
+		{Protocol: "tcp", Port: "2379-2380"},
+		{Protocol: "tcp", Port: "10250"},
+		{Protocol: "tcp", Port: "30000-32767"},
+		{Protocol: "udp", Port: "8472"},
+	}
+
+	tests := []struct {
+		name    string
+		backend kubeone.FirewallBackend
+		sources []string
+	}{
+		{
+			name:    "detect backend",
+			sources: []string{"10.0.0.0/24"},
+		},
+		{
+			name:    "firewalld",
+			backend: kubeone.FirewallBackendFirewalld,
+			sources: []string{"10.0.0.0/24", "fd00::/64"},
+		},
+		{
+			name:    "ufw",
+			backend: kubeone.FirewallBackendUFW,
+			sources: []string{"10.0.0.0/24", "fd00::/64"},
+		},
+		{
+			name:    "nftables",
+			backend: kubeone.FirewallBackendNFTables,
+			sources: []string{"10.0.0.0/24", "172.25.0.0/16", "fd00::/64"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Firewall(tt.backend, tt.sources, publicRules, rules)
+			if err != nil {
+				t.Errorf("Firewall() error = %v", err)
+				return
+			}
+
+			testhelper.DiffOutput(t, testhelper.FSGoldenName(t), got, *updateFlag)
+		})
+	}
+}
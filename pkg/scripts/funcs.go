@@ -23,6 +23,9 @@ import (
 
 	"github.com/BurntSushi/toml"
 	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/apis/kubeone"
 )
 
 var (
@@ -46,7 +49,7 @@ var (
 		{{ define "docker-daemon-config" }}
 		sudo mkdir -p /etc/docker
 		cat <<EOF | sudo tee /etc/docker/daemon.json
-		{{ dockerCfg .INSECURE_REGISTRY }}
+		{{ dockerCfg .INSECURE_REGISTRY (or .SELINUX_ENFORCING false) }}
 		EOF
 		{{ end }}
 
@@ -79,6 +82,182 @@ var (
 		EOF
 		sudo systemctl force-reload systemd-journald
 		{{ end }}
+
+		{{ define "kubelet-systemd-unit" }}
+		cat <<EOF | sudo tee /etc/systemd/system/kubelet.service
+		[Unit]
+		Description=kubelet: The Kubernetes Node Agent
+		Documentation=https://kubernetes.io/docs/home/
+		Wants=network-online.target
+		After=network-online.target
+
+		[Service]
+		ExecStart=/opt/bin/kubelet
+		Restart=always
+		StartLimitInterval=0
+		RestartSec=10
+
+		[Install]
+		WantedBy=multi-user.target
+		EOF
+
+		sudo mkdir -p /etc/systemd/system/kubelet.service.d
+		cat <<EOF | sudo tee /etc/systemd/system/kubelet.service.d/10-kubeadm.conf
+		[Service]
+		Environment="KUBELET_KUBECONFIG_ARGS=--bootstrap-kubeconfig=/etc/kubernetes/bootstrap-kubelet.conf --kubeconfig=/etc/kubernetes/kubelet.conf"
+		Environment="KUBELET_CONFIG_ARGS=--config=/var/lib/kubelet/config.yaml"
+		# This is a file that "kubeadm init" and "kubeadm join" generates at runtime, populating the KUBELET_KUBEADM_ARGS variable dynamically
+		EnvironmentFile=-/var/lib/kubelet/kubeadm-flags.env
+		# This is a file that the user can use for overrides of the kubelet args as a last resort. Preferably, the user should use
+		# the .NodeRegistration.KubeletExtraArgs object in the configuration files instead. KUBELET_EXTRA_ARGS should be sourced from this file.
+		EnvironmentFile=-/etc/default/kubelet
+		ExecStart=
+		ExecStart=/opt/bin/kubelet \$KUBELET_KUBECONFIG_ARGS \$KUBELET_CONFIG_ARGS \$KUBELET_KUBEADM_ARGS \$KUBELET_EXTRA_ARGS
+		EOF
+		{{ end }}
+
+		{{ define "download-binary" }}
+		# download_binary downloads $1 to $2 and verifies it against the SHA256
+		# checksum $3, or against the checksum published at $1.sha256 if $3 is
+		# "remote". If $4 is "signed", the cosign signature is verified as well.
+		download_binary() {
+			curl -fL --retry 3 --output "$2" "$1"
+			checksum="$3"
+			if [ "$checksum" = "remote" ]; then
+				checksum=$(curl -fsSL --retry 3 "$1.sha256" | awk '{print $1}')
+			fi
+			if [ -z "$checksum" ]; then
+				echo "missing SHA256 checksum for $1, exiting"
+				exit 1
+			fi
+			echo "$checksum  $2" | sha256sum --check
+			{{- with . }}
+			if [ "${4:-}" = "signed" ]; then
+				curl -fsSL --retry 3 --output "$2.sig" "$1.sig"
+				{{- if .PublicKey }}
+				cosign verify-blob --key /tmp/k8s-binaries/cosign.pub --signature "$2.sig" "$2"
+				{{- else }}
+				curl -fsSL --retry 3 --output "$2.cert" "$1.cert"
+				cosign verify-blob \
+					--certificate "$2.cert" \
+					--signature "$2.sig" \
+					--certificate-identity "{{ .CertificateIdentity }}" \
+					--certificate-oidc-issuer "{{ .CertificateOIDCIssuer }}" \
+					"$2"
+				{{- end }}
+			fi
+			{{- end }}
+		}
+		{{ end }}
+
+		{{ define "binaries-from-assets" }}
+		{{ template "detect-host-cpu-architecture" }}
+		{{ template "download-binary" .BINARY_ASSETS.SignatureVerification }}
+		sudo mkdir -p /opt/bin /opt/cni/bin
+		rm -rf /tmp/k8s-binaries
+		mkdir -p /tmp/k8s-binaries
+		{{- with .BINARY_ASSETS.SignatureVerification }}
+		{{- if .PublicKey }}
+
+		cat <<EOF > /tmp/k8s-binaries/cosign.pub
+		{{ .PublicKey | trim }}
+		EOF
+		{{- end }}
+		{{- end }}
+
+		{{- if .KUBEADM }}
+		download_binary "{{ .BINARY_ASSETS.CNI.URL }}" /tmp/k8s-binaries/cni.tgz "{{ .BINARY_ASSETS.CNI.SHA256 }}"
+		sudo tar -C /opt/cni/bin -xzf /tmp/k8s-binaries/cni.tgz
+		{{- if .BINARY_ASSETS.Crictl.URL }}
+
+		download_binary "{{ .BINARY_ASSETS.Crictl.URL }}" /tmp/k8s-binaries/crictl.tar.gz "{{ .BINARY_ASSETS.Crictl.SHA256 }}"
+		sudo tar -C /opt/bin -xzf /tmp/k8s-binaries/crictl.tar.gz
+		{{- end }}
+
+		download_binary "{{ .BINARY_ASSETS.Kubeadm.URL }}" /tmp/k8s-binaries/kubeadm "{{ .BINARY_ASSETS.Kubeadm.SHA256 }}"{{ if .BINARY_ASSETS.SignatureVerification }} signed{{ end }}
+		sudo install --owner=0 --group=0 --mode=0755 /tmp/k8s-binaries/kubeadm /opt/bin/kubeadm
+		{{- if .LINK_BINARIES }}
+		sudo ln -sf /opt/bin/kubeadm /usr/bin/
+		{{- end }}
+		{{- end }}
+
+		{{- if .KUBECTL }}
+		download_binary "{{ .BINARY_ASSETS.Kubectl.URL }}" /tmp/k8s-binaries/kubectl "{{ .BINARY_ASSETS.Kubectl.SHA256 }}"{{ if .BINARY_ASSETS.SignatureVerification }} signed{{ end }}
+		sudo install --owner=0 --group=0 --mode=0755 /tmp/k8s-binaries/kubectl /opt/bin/kubectl
+		{{- if .LINK_BINARIES }}
+		sudo ln -sf /opt/bin/kubectl /usr/bin/
+		{{- end }}
+		{{- end }}
+
+		{{- if .KUBELET }}
+		download_binary "{{ .BINARY_ASSETS.Kubelet.URL }}" /tmp/k8s-binaries/kubelet "{{ .BINARY_ASSETS.Kubelet.SHA256 }}"{{ if .BINARY_ASSETS.SignatureVerification }} signed{{ end }}
+		sudo install --owner=0 --group=0 --mode=0755 /tmp/k8s-binaries/kubelet /opt/bin/kubelet
+		{{- if .LINK_BINARIES }}
+		sudo ln -sf /opt/bin/kubelet /usr/bin/
+		{{- end }}
+
+		{{ template "kubelet-systemd-unit" }}
+		{{- end }}
+
+		rm -rf /tmp/k8s-binaries
+		{{ end }}
+
+		{{ define "remove-binaries-from-assets" }}
+		# Remove binaries installed from the binary assets, if any
+		sudo systemctl stop kubelet || true
+		sudo rm -f /opt/bin/kubeadm /opt/bin/kubectl /opt/bin/kubelet /opt/bin/crictl
+		sudo find /usr/bin -maxdepth 1 -lname '/opt/bin/*' -delete
+		sudo rm -f /etc/systemd/system/kubelet.service
+		sudo systemctl daemon-reload
+		{{ end }}
+
+		{{ define "cis-host-config" }}
+		sudo mkdir -p /etc/sysctl.d
+		cat <<'EOF' | sudo tee /etc/sysctl.d/90-kubeone-cis.conf
+		{{ range $key, $value := .SYSCTLS }}{{ $key }} = {{ $value }}
+		{{ end -}}
+		EOF
+		sudo sysctl --system
+
+		sudo mkdir -p /etc/audit/rules.d
+		for path in {{ join " " .AUDIT_WATCHES }}; do
+			if [ -e "$path" ]; then
+				echo "-w $path -p wa -k {{ .AUDIT_KEY }}"
+			fi
+		done | sudo tee /etc/audit/rules.d/kubeone-cis.rules
+		if command -v augenrules >/dev/null 2>&1; then
+			sudo augenrules --load
+		else
+			sudo auditctl -R /etc/audit/rules.d/kubeone-cis.rules
+		fi
+		{{ end }}
+
+		{{ define "chrony-config" }}
+		{{- if .NTP_SERVERS }}
+		cat <<EOF | sudo tee {{ .CHRONY_CONFIG }}
+		# Managed by KubeOne
+		{{- range .NTP_SERVERS }}
+		server {{ . }} iburst
+		{{- end }}
+		driftfile /var/lib/chrony/drift
+		makestep 1.0 3
+		rtcsync
+		EOF
+		{{- end }}
+		{{ end }}
+
+		{{ define "nvidia-driver-load" }}
+		# The nouveau driver conflicts with the NVIDIA driver
+		cat <<EOF | sudo tee /etc/modprobe.d/blacklist-nouveau.conf
+		blacklist nouveau
+		options nouveau modeset=0
+		EOF
+		if lsmod | grep -q '^nouveau'; then
+			echo "The nouveau driver is loaded, the host must be rebooted to load the NVIDIA driver"
+		else
+			sudo modprobe nvidia
+		fi
+		{{ end }}
 	`)
 )
 
@@ -97,9 +276,10 @@ type dockerConfig struct {
 	LogDriver          string            `json:"log-driver,omitempty"`
 	LogOpts            map[string]string `json:"log-opts,omitempty"`
 	InsecureRegistries []string          `json:"insecure-registries,omitempty"`
+	SELinuxEnabled     bool              `json:"selinux-enabled,omitempty"`
 }
 
-func dockerCfg(insecureRegistry string) (string, error) {
+func dockerCfg(insecureRegistry string, selinux bool) (string, error) {
 	cfg := dockerConfig{
 		ExecOpts:      []string{"native.cgroupdriver=systemd"},
 		StorageDriver: "overlay2",
@@ -107,6 +287,7 @@ func dockerCfg(insecureRegistry string) (string, error) {
 		LogOpts: map[string]string{
 			"max-size": "100m",
 		},
+		SELinuxEnabled: selinux,
 	}
 	if insecureRegistry != "" {
 		cfg.InsecureRegistries = []string{insecureRegistry}
@@ -131,12 +312,15 @@ type containerdMetrics struct {
 }
 
 type containerdCRIPlugin struct {
-	Containerd *containerdCRISettings `toml:"containerd"`
-	Registry   *containerdCRIRegistry `toml:"registry"`
+	SandboxImage  string                 `toml:"sandbox_image,omitempty"`
+	EnableSELinux bool                   `toml:"enable_selinux,omitempty"`
+	Containerd    *containerdCRISettings `toml:"containerd"`
+	Registry      *containerdCRIRegistry `toml:"registry"`
 }
 
 type containerdCRISettings struct {
-	Runtimes map[string]containerdCRIRuntime `toml:"runtimes"`
+	DefaultRuntimeName string                          `toml:"default_runtime_name,omitempty"`
+	Runtimes           map[string]containerdCRIRuntime `toml:"runtimes"`
 }
 
 type containerdCRIRuntime struct {
@@ -145,6 +329,7 @@ type containerdCRIRuntime struct {
 }
 
 type containerdCRIRuncOptions struct {
+	BinaryName    string `toml:"BinaryName,omitempty"`
 	SystemdCgroup bool
 }
 
@@ -156,14 +341,17 @@ type containerdMirror struct {
 	Endpoint []string `toml:"endpoint"`
 }
 
-func containerdCfg(insecureRegistry string) (string, error) {
+const defaultNvidiaContainerRuntime = "/usr/bin/nvidia-container-runtime"
+
+func containerdCfg(insecureRegistry string, settings *kubeone.ContainerRuntimeContainerd, selinux bool) (string, error) {
 	criPlugin := containerdCRIPlugin{
+		EnableSELinux: selinux,
 		Containerd: &containerdCRISettings{
 			Runtimes: map[string]containerdCRIRuntime{
 				"runc": {
 					RuntimeType: "io.containerd.runc.v2",
 					Options: containerdCRIRuncOptions{
-						SystemdCgroup: true,
+						SystemdCgroup: settings.RuncSystemdCgroup(),
 					},
 				},
 			},
@@ -177,6 +365,28 @@ func containerdCfg(insecureRegistry string) (string, error) {
 		},
 	}
 
+	if settings != nil {
+		criPlugin.SandboxImage = settings.SandboxImage
+	}
+
+	if settings.NvidiaRuntimeEnabled() {
+		binaryName := settings.NvidiaRuntime.BinaryName
+		if binaryName == "" {
+			binaryName = defaultNvidiaContainerRuntime
+		}
+
+		criPlugin.Containerd.Runtimes["nvidia"] = containerdCRIRuntime{
+			RuntimeType: "io.containerd.runc.v2",
+			Options: containerdCRIRuncOptions{
+				BinaryName:    binaryName,
+				SystemdCgroup: settings.RuncSystemdCgroup(),
+			},
+		}
+		if settings.NvidiaRuntime.DefaultRuntime {
+			criPlugin.Containerd.DefaultRuntimeName = "nvidia"
+		}
+	}
+
 	if insecureRegistry != "" {
 		criPlugin.Registry.Mirrors[insecureRegistry] = containerdMirror{
 			Endpoint: []string{fmt.Sprintf("http://%s", insecureRegistry)},
@@ -195,6 +405,36 @@ func containerdCfg(insecureRegistry string) (string, error) {
 		},
 	}
 
+	var buf strings.Builder
+	enc := toml.NewEncoder(&buf)
+	enc.Indent = ""
+	if err := enc.Encode(cfg); err != nil {
+		return "", err
+	}
+
+	if settings == nil || settings.ConfigPatch == "" {
+		return buf.String(), nil
+	}
+
+	return mergeContainerdConfigPatch(buf.String(), settings.ConfigPatch)
+}
+
+// mergeContainerdConfigPatch merges the TOML patch into the generated
+// containerd config. Tables are merged recursively, while other values from
+// the patch replace the generated values.
+func mergeContainerdConfigPatch(generated, patch string) (string, error) {
+	cfg := map[string]interface{}{}
+	if _, err := toml.Decode(generated, &cfg); err != nil {
+		return "", errors.Wrap(err, "failed to decode generated containerd config")
+	}
+
+	patchCfg := map[string]interface{}{}
+	if _, err := toml.Decode(patch, &patchCfg); err != nil {
+		return "", errors.Wrap(err, "failed to decode containerd config patch")
+	}
+
+	mergeTOMLTables(cfg, patchCfg)
+
 	var buf strings.Builder
 	enc := toml.NewEncoder(&buf)
 	enc.Indent = ""
@@ -202,3 +442,16 @@ func containerdCfg(insecureRegistry string) (string, error) {
 
 	return buf.String(), err
 }
+
+func mergeTOMLTables(dst, src map[string]interface{}) {
+	for k, v := range src {
+		srcTable, srcIsTable := v.(map[string]interface{})
+		dstTable, dstIsTable := dst[k].(map[string]interface{})
+		if srcIsTable && dstIsTable {
+			mergeTOMLTables(dstTable, srcTable)
+			continue
+		}
+
+		dst[k] = v
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scripts
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc/v2"
+)
+
+// CISAuditKey is the key of the auditd rules installed by the CIS hardening
+// profile
+const CISAuditKey = "kubeone-cis"
+
+var (
+	cisHostDebianScriptTemplate = heredoc.Doc(`
+		sudo apt-get update
+		sudo DEBIAN_FRONTEND=noninteractive apt-get install -y auditd
+		sudo systemctl enable --now auditd
+
+		{{ template "cis-host-config" . }}
+	`)
+
+	cisHostCentOSScriptTemplate = heredoc.Doc(`
+		sudo yum install -y audit
+		sudo systemctl enable --now auditd
+
+		{{ template "cis-host-config" . }}
+	`)
+
+	// Flatcar Linux ships the audit userspace tools, rules are loaded by
+	// the audit-rules service
+	cisHostFlatcarScriptTemplate = heredoc.Doc(`
+		{{ template "cis-host-config" . }}
+		sudo systemctl enable audit-rules
+	`)
+
+	cisFilePermissionsScriptTemplate = heredoc.Doc(`
+		{{- range .FILES }}
+		for f in {{ join " " .Paths }}; do
+			if sudo test -e "$f"; then
+				{{- if .Chmod }}
+				sudo chmod {{ .Chmod }} "$f"
+				{{- end }}
+				sudo chown root:root "$f"
+			fi
+		done
+		{{- end }}
+	`)
+
+	cisVerifyScriptTemplate = heredoc.Doc(`
+		{{- range .PATHS }}
+		for f in {{ . }}; do
+			if sudo test -e "$f"; then
+				echo "file.$f=$(sudo stat -c %a:%U:%G "$f")"
+			fi
+		done
+		{{- end }}
+
+		if sudo auditctl -l 2>/dev/null | grep -q -- "-k {{ .AUDIT_KEY }}"; then
+			echo "audit_rules=yes"
+		else
+			echo "audit_rules=no"
+		fi
+	`)
+)
+
+// FilePermission are the files, or globs of files, which must be owned by
+// root and have at most Mode permissions. Permissions are only restricted,
+// never extended. Zero Mode changes only the ownership.
+type FilePermission struct {
+	Paths []string
+	Mode  os.FileMode
+}
+
+// CISHostDebian returns the script installing auditd, the auditd rules
+// watching the given paths and the sysctl settings on Debian and Ubuntu
+func CISHostDebian(sysctls map[string]string, auditWatches []string) (string, error) {
+	return Render(cisHostDebianScriptTemplate, cisHostData(sysctls, auditWatches))
+}
+
+// CISHostCentOS returns the script installing auditd, the auditd rules
+// watching the given paths and the sysctl settings on CentOS, RHEL and
+// Amazon Linux 2
+func CISHostCentOS(sysctls map[string]string, auditWatches []string) (string, error) {
+	return Render(cisHostCentOSScriptTemplate, cisHostData(sysctls, auditWatches))
+}
+
+// CISHostFlatcar returns the script installing the auditd rules watching the
+// given paths and the sysctl settings on Flatcar Linux
+func CISHostFlatcar(sysctls map[string]string, auditWatches []string) (string, error) {
+	return Render(cisHostFlatcarScriptTemplate, cisHostData(sysctls, auditWatches))
+}
+
+func cisHostData(sysctls map[string]string, auditWatches []string) Data {
+	return Data{
+		"SYSCTLS":       sysctls,
+		"AUDIT_WATCHES": auditWatches,
+		"AUDIT_KEY":     CISAuditKey,
+	}
+}
+
+// CISFilePermissions returns the script restricting the permissions and the
+// ownership of the files
+func CISFilePermissions(files []FilePermission) (string, error) {
+	type file struct {
+		Paths []string
+		Chmod string
+	}
+
+	data := []file{}
+	for _, f := range files {
+		data = append(data, file{Paths: f.Paths, Chmod: chmodRestrict(f.Mode)})
+	}
+
+	return Render(cisFilePermissionsScriptTemplate, Data{
+		"FILES": data,
+	})
+}
+
+// CISVerify returns the script printing the permissions and the ownership of
+// the files, and whether the auditd rules are loaded, as key=value lines.
+// It doesn't change the host.
+func CISVerify(paths []string) (string, error) {
+	return Render(cisVerifyScriptTemplate, Data{
+		"PATHS":     paths,
+		"AUDIT_KEY": CISAuditKey,
+	})
+}
+
+// chmodRestrict returns the symbolic chmod mode removing all permissions not
+// allowed by the mode, e.g. u-x,g-rwx,o-rwx for 0600
+func chmodRestrict(mode os.FileMode) string {
+	if mode == 0 {
+		return ""
+	}
+
+	classes := []string{}
+	for i, class := range []string{"u", "g", "o"} {
+		allowed := (mode >> uint(6-3*i)) & 07
+		remove := ""
+		for j, perm := range []string{"r", "w", "x"} {
+			if allowed&(04>>uint(j)) == 0 {
+				remove += perm
+			}
+		}
+		if remove != "" {
+			classes = append(classes, fmt.Sprintf("%s-%s", class, remove))
+		}
+	}
+
+	return strings.Join(classes, ",")
+}
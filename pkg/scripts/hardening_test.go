@@ -0,0 +1,114 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scripts
+
+import (
+	"os"
+	"testing"
+
+	"k8c.io/kubeone/pkg/testhelper"
+)
+
+func TestCISHostScripts(t *testing.T) {
+	t.Parallel()
+
+	sysctls := map[string]string{
+		"vm.overcommit_memory": "1",
+		"kernel.panic":         "10",
+	}
+	auditWatches := []string{"/etc/kubernetes", "/usr/bin/containerd"}
+
+	tests := []struct {
+		name   string
+		script func(map[string]string, []string) (string, error)
+	}{
+		{
+			name:   "debian",
+			script: CISHostDebian,
+		},
+		{
+			name:   "centos",
+			script: CISHostCentOS,
+		},
+		{
+			name:   "flatcar",
+			script: CISHostFlatcar,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.script(sysctls, auditWatches)
+			if err != nil {
+				t.Errorf("script error = %v", err)
+				return
+			}
+
+			testhelper.DiffOutput(t, testhelper.FSGoldenName(t), got, *updateFlag)
+		})
+	}
+}
+
+func TestCISFilePermissions(t *testing.T) {
+	t.Parallel()
+
+	got, err := CISFilePermissions([]FilePermission{
+		{Paths: []string{"/etc/kubernetes/manifests/kube-apiserver.yaml"}, Mode: 0600},
+		{Paths: []string{"/etc/kubernetes/pki/*.crt", "/etc/kubernetes/pki/etcd/*.crt"}, Mode: 0644},
+		{Paths: []string{"/etc/kubernetes/pki"}},
+	})
+	if err != nil {
+		t.Fatalf("CISFilePermissions() error = %v", err)
+	}
+
+	testhelper.DiffOutput(t, testhelper.FSGoldenName(t), got, *updateFlag)
+}
+
+func TestCISVerify(t *testing.T) {
+	t.Parallel()
+
+	got, err := CISVerify([]string{"/etc/kubernetes/admin.conf", "/etc/kubernetes/pki/*.key"})
+	if err != nil {
+		t.Fatalf("CISVerify() error = %v", err)
+	}
+
+	testhelper.DiffOutput(t, testhelper.FSGoldenName(t), got, *updateFlag)
+}
+
+func TestChmodRestrict(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		mode uint32
+		want string
+	}{
+		{mode: 0600, want: "u-x,g-rwx,o-rwx"},
+		{mode: 0644, want: "u-x,g-wx,o-wx"},
+		{mode: 0700, want: "g-rwx,o-rwx"},
+		{mode: 0777, want: ""},
+		{mode: 0, want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := chmodRestrict(os.FileMode(tt.mode)); got != tt.want {
+			t.Errorf("chmodRestrict(%o) = %q, want %q", tt.mode, got, tt.want)
+		}
+	}
+}
@@ -19,8 +19,10 @@ package scripts
 import "github.com/MakeNowJust/heredoc/v2"
 
 var (
+	// admin.conf is not used to detect joined hosts, as it's installed before
+	// joining when the certificates are signed by the external CA signer
 	kubeadmJoinScriptTemplate = heredoc.Doc(`
-		[[ -f /etc/kubernetes/admin.conf ]] && exit 0
+		[[ -f /etc/kubernetes/kubelet.conf ]] && exit 0
 
 		sudo kubeadm {{ .VERBOSE }} join \
 			--config={{ .WORK_DIR }}/cfg/master_{{ .NODE_ID }}.yaml
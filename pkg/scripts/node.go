@@ -16,7 +16,10 @@ limitations under the License.
 
 package scripts
 
-import "github.com/MakeNowJust/heredoc/v2"
+import (
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/pkg/errors"
+)
 
 var (
 	hostnameScript = heredoc.Doc(`
@@ -25,6 +28,62 @@ var (
 		echo "$fqdn"
 	`)
 
+	shortHostnameScript = "hostname"
+
+	// instanceIDScripts print the ID of the cloud instance, read from the
+	// instance metadata service of the cloud provider
+	instanceIDScripts = map[string]string{
+		"aws": heredoc.Doc(`
+			token=$(curl -fsS -X PUT -H "X-aws-ec2-metadata-token-ttl-seconds: 60" http://169.254.169.254/latest/api/token)
+			curl -fsS -H "X-aws-ec2-metadata-token: $token" http://169.254.169.254/latest/meta-data/instance-id
+		`),
+	}
+
+	rebootRequiredScript = heredoc.Doc(`
+		# Provisioning steps requiring reboot write the reason to this file
+		if [ -f {{ .REBOOT_REQUIRED_FILE }} ]; then
+			cat {{ .REBOOT_REQUIRED_FILE }}
+		fi
+		if [ -f /var/run/reboot-required ]; then
+			echo "upgraded system packages require reboot"
+		fi
+		if command -v needs-restarting >/dev/null 2>&1 && ! sudo needs-restarting -r >/dev/null 2>&1; then
+			echo "upgraded core packages require reboot"
+		fi
+		if command -v update_engine_client >/dev/null 2>&1 && \
+			update_engine_client -status 2>/dev/null | grep -q UPDATE_STATUS_UPDATED_NEED_REBOOT; then
+			echo "installed Flatcar update requires reboot"
+		fi
+	`)
+
+	// The reboot is delayed, so the command returns before the SSH
+	// connection is closed by the reboot
+	rebootScript = heredoc.Doc(`
+		sudo nohup sh -c 'sleep 2; systemctl reboot' >/dev/null 2>&1 &
+	`)
+
+	restartKubeletScript = "sudo systemctl restart kubelet"
+
+	hostResourcesScript = heredoc.Doc(`
+		echo "cpus=$(nproc)"
+		echo "memory_kib=$(awk '/^MemTotal:/ {print $2}' /proc/meminfo)"
+
+		# etcd data directory is created by kubeadm, measure the filesystem
+		# it will be created on
+		dir=/var/lib/etcd
+		while [ ! -d "$dir" ]; do dir=$(dirname "$dir"); done
+		echo "disk_kib=$(df -Pk "$dir" | awk 'NR == 2 {print $2}')"
+
+		testfile="$dir/.kubeone-disk-test"
+		writes=200
+		start=$(date +%s%N)
+		sudo dd if=/dev/zero of="$testfile" bs=4k count=$writes oflag=dsync status=none
+		end=$(date +%s%N)
+		sudo rm -f "$testfile"
+		echo "sync_writes=$writes"
+		echo "sync_writes_ns=$((end - start))"
+	`)
+
 	restartKubeAPIServerCrictlTemplate = heredoc.Doc(`
 		# Disable exit immediately if a command in a pipeline fails.
 		# crictl logs can fail if kubelet fails to set up symlink for the API
@@ -49,12 +108,61 @@ var (
 	`)
 )
 
+const (
+	// RebootRequiredFile is written by the provisioning steps which require
+	// the host to be rebooted, with the reason of the reboot. It's removed
+	// by the reboot.
+	RebootRequiredFile = "/run/kubeone/reboot-required"
+)
+
 func Hostname() string {
 	return hostnameScript
 }
 
+// ShortHostname returns the script printing the hostname without the domain
+func ShortHostname() string {
+	return shortHostnameScript
+}
+
+// InstanceID returns the script printing the ID of the cloud instance of the
+// given cloud provider
+func InstanceID(cloudProvider string) (string, error) {
+	script, ok := instanceIDScripts[cloudProvider]
+	if !ok {
+		return "", errors.Errorf("reading the instance ID is not supported for the %q cloud provider", cloudProvider)
+	}
+
+	return script, nil
+}
+
 func RestartKubeAPIServerCrictl(ensure bool) (string, error) {
 	return Render(restartKubeAPIServerCrictlTemplate, Data{
 		"ENSURE": ensure,
 	})
 }
+
+// RebootRequired returns the script printing the reasons why the host
+// requires reboot, one per line. Nothing is printed if the reboot is not
+// required.
+func RebootRequired() (string, error) {
+	return Render(rebootRequiredScript, Data{
+		"REBOOT_REQUIRED_FILE": RebootRequiredFile,
+	})
+}
+
+// Reboot returns the script rebooting the host
+func Reboot() string {
+	return rebootScript
+}
+
+// RestartKubelet returns the script restarting kubelet
+func RestartKubelet() string {
+	return restartKubeletScript
+}
+
+// HostResources returns the script printing the number of CPUs, the memory,
+// and the size and the synchronous write speed of the filesystem storing
+// the etcd data, as key=value lines
+func HostResources() string {
+	return hostResourcesScript
+}
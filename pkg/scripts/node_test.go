@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scripts
+
+import (
+	"testing"
+
+	"k8c.io/kubeone/pkg/testhelper"
+)
+
+func TestRebootRequired(t *testing.T) {
+	t.Parallel()
+
+	got, err := RebootRequired()
+	if err != nil {
+		t.Errorf("RebootRequired() error = %v", err)
+		return
+	}
+
+	testhelper.DiffOutput(t, testhelper.FSGoldenName(t), got, *updateFlag)
+}
+
+func TestHostPreflight(t *testing.T) {
+	t.Parallel()
+
+	got, err := HostPreflight(
+		[]string{"overlay", "br_netfilter"},
+		[]int{6443, 10250},
+		[]PreflightPeer{{Host: "10.0.0.2", Port: 6443}, {Host: "10.0.0.3", Port: 10250}},
+	)
+	if err != nil {
+		t.Errorf("HostPreflight() error = %v", err)
+		return
+	}
+
+	testhelper.DiffOutput(t, testhelper.FSGoldenName(t), got, *updateFlag)
+}
+
+func TestInstanceID(t *testing.T) {
+	t.Parallel()
+
+	got, err := InstanceID("aws")
+	if err != nil {
+		t.Fatalf("InstanceID() error = %v", err)
+	}
+
+	testhelper.DiffOutput(t, testhelper.FSGoldenName(t), got, *updateFlag)
+
+	if _, err := InstanceID("azure"); err == nil {
+		t.Error("InstanceID() expected error for the azure cloud provider")
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scripts
+
+import (
+	"github.com/MakeNowJust/heredoc/v2"
+
+	"k8c.io/kubeone/pkg/apis/kubeone"
+)
+
+var (
+	nvidiaGPUDebianScriptTemplate = heredoc.Doc(`
+		source /etc/os-release
+
+		{{ if .CONFIGURE_REPOSITORIES }}
+		curl -fsSL https://nvidia.github.io/libnvidia-container/gpgkey | sudo apt-key add -
+		curl -fsSL "https://nvidia.github.io/libnvidia-container/$ID$VERSION_ID/libnvidia-container.list" |
+			sudo tee /etc/apt/sources.list.d/nvidia-container-toolkit.list
+		{{ end }}
+
+		sudo apt-get update
+		sudo DEBIAN_FRONTEND=noninteractive apt-get install -y "linux-headers-$(uname -r)"
+		if [ "$ID" == "ubuntu" ]; then
+		{{- if .DRIVER_VERSION }}
+			sudo DEBIAN_FRONTEND=noninteractive apt-get install -y nvidia-driver-{{ .DRIVER_VERSION }}-server
+		{{- else }}
+			sudo DEBIAN_FRONTEND=noninteractive apt-get install -y ubuntu-drivers-common
+			sudo ubuntu-drivers install --gpgpu
+		{{- end }}
+		else
+			# Debian provides only one driver branch in the non-free component
+			sudo DEBIAN_FRONTEND=noninteractive apt-get install -y nvidia-driver
+		fi
+		sudo DEBIAN_FRONTEND=noninteractive apt-get install -y nvidia-container-toolkit
+
+		{{ template "nvidia-driver-load" }}
+	`)
+
+	nvidiaGPUCentOSScriptTemplate = heredoc.Doc(`
+		source /etc/os-release
+		if [ "$ID" == "amzn" ]; then
+			rhel_version=7
+		else
+			rhel_version=${VERSION_ID%%.*}
+		fi
+
+		{{ if .CONFIGURE_REPOSITORIES }}
+		sudo yum install -y yum-utils
+		sudo yum-config-manager --add-repo="https://nvidia.github.io/libnvidia-container/$ID$VERSION_ID/libnvidia-container.repo"
+		sudo yum-config-manager --add-repo="https://developer.download.nvidia.com/compute/cuda/repos/rhel$rhel_version/$(uname -m)/cuda-rhel$rhel_version.repo"
+		{{ end }}
+
+		sudo yum install -y "kernel-devel-$(uname -r)" "kernel-headers-$(uname -r)"
+		if [ "$rhel_version" -ge 8 ]; then
+			sudo yum module install -y nvidia-driver:{{ or .DRIVER_VERSION "latest" }}-dkms
+		else
+		{{- if .DRIVER_VERSION }}
+			sudo yum install -y nvidia-driver-branch-{{ .DRIVER_VERSION }}
+		{{- else }}
+			sudo yum install -y nvidia-driver-latest-dkms
+		{{- end }}
+		fi
+		sudo yum install -y nvidia-container-toolkit
+
+		{{ template "nvidia-driver-load" }}
+	`)
+)
+
+// NvidiaGPUDebian returns the script installing the NVIDIA driver and the
+// NVIDIA container toolkit on Debian and Ubuntu
+func NvidiaGPUDebian(cluster *kubeone.KubeOneCluster) (string, error) {
+	return Render(nvidiaGPUDebianScriptTemplate, nvidiaGPUData(cluster))
+}
+
+// NvidiaGPUCentOS returns the script installing the NVIDIA driver and the
+// NVIDIA container toolkit on CentOS, RHEL and Amazon Linux 2
+func NvidiaGPUCentOS(cluster *kubeone.KubeOneCluster) (string, error) {
+	return Render(nvidiaGPUCentOSScriptTemplate, nvidiaGPUData(cluster))
+}
+
+func nvidiaGPUData(cluster *kubeone.KubeOneCluster) Data {
+	driverVersion := ""
+	if cluster.Features.NvidiaGPU != nil {
+		driverVersion = cluster.Features.NvidiaGPU.DriverVersion
+	}
+
+	return Data{
+		"CONFIGURE_REPOSITORIES": cluster.SystemPackages.ConfigureRepositories,
+		"DRIVER_VERSION":         driverVersion,
+	}
+}
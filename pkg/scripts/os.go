@@ -17,14 +17,117 @@ limitations under the License.
 package scripts
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/MakeNowJust/heredoc/v2"
+
+	"k8c.io/kubeone/pkg/apis/kubeone"
 )
 
 const (
 	defaultKubernetesCNIVersion = "0.8.7"
 	defaultCriToolsVersion      = "1.21.0"
+
+	defaultKubernetesReleaseURL = "https://dl.k8s.io/release"
+	defaultCNIReleaseURL        = "https://github.com/containernetworking/plugins/releases/download"
+	defaultCriToolsReleaseURL   = "https://github.com/kubernetes-sigs/cri-tools/releases/download"
+
+	// remoteChecksum instructs download_binary to verify the binary against
+	// the checksum published next to it with the .sha256 extension
+	remoteChecksum = "remote"
 )
 
+// binarySource is the URL of the binary and its expected SHA256 checksum
+type binarySource struct {
+	URL    string
+	SHA256 string
+}
+
+// binaryAssets are sources of binaries installed by the binaries-from-assets
+// template
+type binaryAssets struct {
+	Kubeadm binarySource
+	Kubelet binarySource
+	Kubectl binarySource
+	CNI     binarySource
+	// Crictl is installed only if URL is set
+	Crictl binarySource
+	// SignatureVerification enables verification of Kubeadm, Kubelet and
+	// Kubectl signatures
+	SignatureVerification *kubeone.SignatureVerification
+}
+
+// binariesFromAssets resolves sources of kubeadm, kubelet, kubectl and CNI
+// plugins from the AssetConfiguration. It returns nil if binaries should be
+// installed from packages.
+func binariesFromAssets(cluster *kubeone.KubeOneCluster) *binaryAssets {
+	if !cluster.AssetConfiguration.BinariesFromAssets() {
+		return nil
+	}
+
+	sources := binarySources(cluster)
+	if cluster.AssetConfiguration.BinariesMirror == "" {
+		// crictl is provided by packages unless the mirror is used
+		sources.Crictl = binarySource{}
+	}
+
+	return sources
+}
+
+// binarySources resolves sources of kubeadm, kubelet, kubectl, CNI plugins
+// and crictl from the AssetConfiguration, falling back to the BinariesMirror
+// if used, or to the upstream releases otherwise
+func binarySources(cluster *kubeone.KubeOneCluster) *binaryAssets {
+	assets := cluster.AssetConfiguration
+
+	kubeBaseURL := defaultKubernetesReleaseURL
+	cniBaseURL := defaultCNIReleaseURL
+	crictlBaseURL := defaultCriToolsReleaseURL
+	if mirror := strings.TrimSuffix(assets.BinariesMirror, "/"); mirror != "" {
+		kubeBaseURL = mirror
+		cniBaseURL = mirror + "/cni-plugins"
+		crictlBaseURL = mirror + "/cri-tools"
+	}
+
+	kubeBinaryURL := func(binary string) string {
+		return fmt.Sprintf("%s/v%s/bin/linux/${HOST_ARCH}/%s", kubeBaseURL, cluster.Versions.Kubernetes, binary)
+	}
+	cniURL := fmt.Sprintf("%[1]s/v%[2]s/cni-plugins-linux-${HOST_ARCH}-v%[2]s.tgz", cniBaseURL, defaultKubernetesCNIVersion)
+	crictlURL := fmt.Sprintf("%[1]s/v%[2]s/crictl-v%[2]s-linux-${HOST_ARCH}.tar.gz", crictlBaseURL, defaultCriToolsVersion)
+
+	return &binaryAssets{
+		Kubeadm:               resolveBinarySource(assets.Kubeadm, kubeBinaryURL("kubeadm")),
+		Kubelet:               resolveBinarySource(assets.Kubelet, kubeBinaryURL("kubelet")),
+		Kubectl:               resolveBinarySource(assets.Kubectl, kubeBinaryURL("kubectl")),
+		CNI:                   resolveBinarySource(assets.CNI, cniURL),
+		Crictl:                resolveBinarySource(kubeone.BinaryAsset{}, crictlURL),
+		SignatureVerification: assets.SignatureVerification,
+	}
+}
+
+func resolveBinarySource(asset kubeone.BinaryAsset, defaultURL string) binarySource {
+	src := binarySource{
+		URL:    asset.URL,
+		SHA256: binaryChecksum(asset),
+	}
+	if src.URL == "" {
+		src.URL = defaultURL
+	}
+
+	return src
+}
+
+// binaryChecksum returns the SHA256 checksum configured for the binary
+// asset, falling back to the checksum published next to the binary
+func binaryChecksum(asset kubeone.BinaryAsset) string {
+	if asset.SHA256 != "" {
+		return asset.SHA256
+	}
+
+	return remoteChecksum
+}
+
 var migrateToContainerdScriptTemplate = heredoc.Doc(`
 	sudo systemctl stop kubelet
 	sudo docker ps -q | xargs sudo docker stop || true
@@ -44,9 +147,24 @@ var migrateToContainerdScriptTemplate = heredoc.Doc(`
 	sudo systemctl restart kubelet
 `)
 
-func MigrateToContainerd(insecureRegistry string, generateContainerdConfig bool) (string, error) {
+func MigrateToContainerd(cluster *kubeone.KubeOneCluster, generateContainerdConfig bool) (string, error) {
 	return Render(migrateToContainerdScriptTemplate, Data{
-		"INSECURE_REGISTRY":          insecureRegistry,
+		"INSECURE_REGISTRY":          cluster.RegistryConfiguration.InsecureRegistryAddress(),
+		"INSTALL_CONTAINERD":         containerdSettings(cluster),
 		"GENERATE_CONTAINERD_CONFIG": generateContainerdConfig,
+		"SELINUX_ENFORCING":          cluster.SELinuxMode() == kubeone.SELinuxModeEnforcing,
 	})
 }
+
+// containerdSettings returns the containerd configuration with the sandbox
+// image resolved, or nil if containerd is not used
+func containerdSettings(cluster *kubeone.KubeOneCluster) *kubeone.ContainerRuntimeContainerd {
+	if cluster.ContainerRuntime.Containerd == nil {
+		return nil
+	}
+
+	settings := cluster.ContainerRuntime.Containerd.DeepCopy()
+	settings.SandboxImage = cluster.ContainerdSandboxImage()
+
+	return settings
+}
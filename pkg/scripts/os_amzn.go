@@ -22,8 +22,14 @@ const (
 	kubeadmAmazonLinuxTemplate = `
 sudo swapoff -a
 sudo sed -i '/.*swap.*/d' /etc/fstab
-sudo setenforce 0 || true
+{{- if .SELINUX_PERMISSIVE }}
+if ! sudo setenforce 0 && grep -qs '^SELINUX=enforcing' /etc/selinux/config; then
+	# The SELinux mode can't be changed at runtime, it's applied by the reboot
+	sudo mkdir -p {{ rebootRequiredFile | dir }}
+	echo "SELinux mode changed to permissive" | sudo tee -a {{ rebootRequiredFile }} >/dev/null
+fi
 [ -f /etc/selinux/config ] && sudo sed -i 's/SELINUX=enforcing/SELINUX=permissive/g' /etc/selinux/config
+{{- end }}
 sudo systemctl disable --now firewalld || true
 
 source /etc/kubeone/proxy-env
@@ -39,7 +45,7 @@ grep -v '#kubeone' /etc/yum.conf > /tmp/yum.conf || true
 echo -n "${yum_proxy}" >> /tmp/yum.conf
 sudo mv /tmp/yum.conf /etc/yum.conf
 
-{{ if .CONFIGURE_REPOSITORIES }}
+{{ if and .CONFIGURE_REPOSITORIES (not .BINARY_ASSETS) }}
 cat <<EOF | sudo tee /etc/yum.repos.d/kubernetes.repo
 [kubernetes]
 name=Kubernetes
@@ -72,17 +78,23 @@ sudo yum install -y \
 
 sudo mkdir -p /opt/bin /etc/kubernetes/pki /etc/kubernetes/manifests
 
+{{- if .BINARY_ASSETS }}
+{{ template "binaries-from-assets" . }}
+{{- else }}
+
+{{ template "download-binary" }}
 rm -rf /tmp/k8s-binaries
 mkdir -p /tmp/k8s-binaries
 cd /tmp/k8s-binaries
 
 {{- if .CNI_URL }}
 sudo mkdir -p /opt/cni/bin
-curl -L "{{ .CNI_URL }}" | sudo tar -C /opt/cni/bin -xz
+download_binary "{{ .CNI_URL }}" /tmp/k8s-binaries/cni.tgz "{{ .CNI_SHA256 }}"
+sudo tar -C /opt/cni/bin -xzf /tmp/k8s-binaries/cni.tgz
 {{- end }}
 
 {{- if .NODE_BINARIES_URL }}
-curl -L --output /tmp/k8s-binaries/node.tar.gz {{ .NODE_BINARIES_URL }}
+download_binary "{{ .NODE_BINARIES_URL }}" /tmp/k8s-binaries/node.tar.gz "{{ .NODE_BINARIES_SHA256 }}"
 tar xvf node.tar.gz
 {{- end }}
 
@@ -91,36 +103,7 @@ sudo install --owner=0 --group=0 --mode=0755 /tmp/k8s-binaries/kubernetes/node/b
 sudo ln -sf /opt/bin/kubelet /usr/bin/
 rm /tmp/k8s-binaries/kubernetes/node/bin/kubelet
 
-cat <<EOF | sudo tee /etc/systemd/system/kubelet.service
-[Unit]
-Description=kubelet: The Kubernetes Node Agent
-Documentation=https://kubernetes.io/docs/home/
-Wants=network-online.target
-After=network-online.target
-
-[Service]
-ExecStart=/opt/bin/kubelet
-Restart=always
-StartLimitInterval=0
-RestartSec=10
-
-[Install]
-WantedBy=multi-user.target
-EOF
-
-sudo mkdir -p /etc/systemd/system/kubelet.service.d
-cat <<EOF | sudo tee /etc/systemd/system/kubelet.service.d/10-kubeadm.conf
-[Service]
-Environment="KUBELET_KUBECONFIG_ARGS=--bootstrap-kubeconfig=/etc/kubernetes/bootstrap-kubelet.conf --kubeconfig=/etc/kubernetes/kubelet.conf"
-Environment="KUBELET_CONFIG_ARGS=--config=/var/lib/kubelet/config.yaml"
-# This is a file that "kubeadm init" and "kubeadm join" generates at runtime, populating the KUBELET_KUBEADM_ARGS variable dynamically
-EnvironmentFile=-/var/lib/kubelet/kubeadm-flags.env
-# This is a file that the user can use for overrides of the kubelet args as a last resort. Preferably, the user should use
-# the .NodeRegistration.KubeletExtraArgs object in the configuration files instead. KUBELET_EXTRA_ARGS should be sourced from this file.
-EnvironmentFile=-/etc/default/kubelet
-ExecStart=
-ExecStart=/opt/bin/kubelet \$KUBELET_KUBECONFIG_ARGS \$KUBELET_CONFIG_ARGS \$KUBELET_KUBEADM_ARGS \$KUBELET_EXTRA_ARGS
-EOF
+{{ template "kubelet-systemd-unit" }}
 {{- end }}
 
 {{- if and .KUBEADM .NODE_BINARIES_URL }}
@@ -130,11 +113,12 @@ rm /tmp/k8s-binaries/kubernetes/node/bin/kubeadm
 {{- end }}
 
 {{- if and .KUBECTL .KUBECTL_URL }}
-curl -L --output /tmp/k8s-binaries/kubectl {{ .KUBECTL_URL }}
+download_binary "{{ .KUBECTL_URL }}" /tmp/k8s-binaries/kubectl "{{ .KUBECTL_SHA256 }}"
 sudo install --owner=0 --group=0 --mode=0755 /tmp/k8s-binaries/kubectl /opt/bin/kubectl
 sudo ln -sf /opt/bin/kubectl /usr/bin/
 rm /tmp/k8s-binaries/kubectl
 {{- end }}
+{{- end }}
 
 {{ if .USE_KUBERNETES_REPO }}
 {{- if or .FORCE .UPGRADE }}
@@ -192,15 +176,19 @@ func KubeadmAmazonLinux(cluster *kubeone.KubeOneCluster, force bool) (string, er
 		proxy = cluster.Proxy.HTTP
 	}
 
-	useKubernetesRepo := cluster.AssetConfiguration.NodeBinaries.URL == ""
+	binaryAssets := binariesFromAssets(cluster)
+	useKubernetesRepo := cluster.AssetConfiguration.NodeBinaries.URL == "" && binaryAssets == nil
 
 	return Render(kubeadmAmazonLinuxTemplate, Data{
 		"KUBELET":                true,
 		"KUBEADM":                true,
 		"KUBECTL":                true,
 		"NODE_BINARIES_URL":      cluster.AssetConfiguration.NodeBinaries.URL,
+		"NODE_BINARIES_SHA256":   binaryChecksum(cluster.AssetConfiguration.NodeBinaries),
 		"CNI_URL":                cluster.AssetConfiguration.CNI.URL,
+		"CNI_SHA256":             binaryChecksum(cluster.AssetConfiguration.CNI),
 		"KUBECTL_URL":            cluster.AssetConfiguration.Kubectl.URL,
+		"KUBECTL_SHA256":         binaryChecksum(cluster.AssetConfiguration.Kubectl),
 		"KUBERNETES_VERSION":     cluster.Versions.Kubernetes,
 		"KUBERNETES_CNI_VERSION": defaultKubernetesCNIVersion,
 		"CONFIGURE_REPOSITORIES": cluster.SystemPackages.ConfigureRepositories,
@@ -208,8 +196,12 @@ func KubeadmAmazonLinux(cluster *kubeone.KubeOneCluster, force bool) (string, er
 		"PROXY":                  proxy,
 		"FORCE":                  force,
 		"INSTALL_DOCKER":         cluster.ContainerRuntime.Docker,
-		"INSTALL_CONTAINERD":     cluster.ContainerRuntime.Containerd,
+		"INSTALL_CONTAINERD":     containerdSettings(cluster),
+		"SELINUX_PERMISSIVE":     cluster.SELinuxMode() == kubeone.SELinuxModePermissive,
+		"SELINUX_ENFORCING":      cluster.SELinuxMode() == kubeone.SELinuxModeEnforcing,
 		"USE_KUBERNETES_REPO":    useKubernetesRepo,
+		"BINARY_ASSETS":          binaryAssets,
+		"LINK_BINARIES":          true,
 	})
 }
 
@@ -223,21 +215,28 @@ func UpgradeKubeadmAndCNIAmazonLinux(cluster *kubeone.KubeOneCluster) (string, e
 		proxy = cluster.Proxy.HTTP
 	}
 
-	useKubernetesRepo := cluster.AssetConfiguration.NodeBinaries.URL == ""
+	binaryAssets := binariesFromAssets(cluster)
+	useKubernetesRepo := cluster.AssetConfiguration.NodeBinaries.URL == "" && binaryAssets == nil
 
 	return Render(kubeadmAmazonLinuxTemplate, Data{
 		"UPGRADE":                true,
 		"KUBEADM":                true,
 		"NODE_BINARIES_URL":      cluster.AssetConfiguration.NodeBinaries.URL,
+		"NODE_BINARIES_SHA256":   binaryChecksum(cluster.AssetConfiguration.NodeBinaries),
 		"CNI_URL":                cluster.AssetConfiguration.CNI.URL,
+		"CNI_SHA256":             binaryChecksum(cluster.AssetConfiguration.CNI),
 		"KUBERNETES_VERSION":     cluster.Versions.Kubernetes,
 		"KUBERNETES_CNI_VERSION": defaultKubernetesCNIVersion,
 		"CONFIGURE_REPOSITORIES": cluster.SystemPackages.ConfigureRepositories,
 		"INSECURE_REGISTRY":      cluster.RegistryConfiguration.InsecureRegistryAddress(),
 		"PROXY":                  proxy,
 		"INSTALL_DOCKER":         cluster.ContainerRuntime.Docker,
-		"INSTALL_CONTAINERD":     cluster.ContainerRuntime.Containerd,
+		"INSTALL_CONTAINERD":     containerdSettings(cluster),
+		"SELINUX_PERMISSIVE":     cluster.SELinuxMode() == kubeone.SELinuxModePermissive,
+		"SELINUX_ENFORCING":      cluster.SELinuxMode() == kubeone.SELinuxModeEnforcing,
 		"USE_KUBERNETES_REPO":    useKubernetesRepo,
+		"BINARY_ASSETS":          binaryAssets,
+		"LINK_BINARIES":          true,
 	})
 }
 
@@ -247,21 +246,28 @@ func UpgradeKubeletAndKubectlAmazonLinux(cluster *kubeone.KubeOneCluster) (strin
 		proxy = cluster.Proxy.HTTP
 	}
 
-	useKubernetesRepo := cluster.AssetConfiguration.NodeBinaries.URL == ""
+	binaryAssets := binariesFromAssets(cluster)
+	useKubernetesRepo := cluster.AssetConfiguration.NodeBinaries.URL == "" && binaryAssets == nil
 
 	return Render(kubeadmAmazonLinuxTemplate, Data{
 		"UPGRADE":                true,
 		"KUBELET":                true,
 		"KUBECTL":                true,
 		"NODE_BINARIES_URL":      cluster.AssetConfiguration.NodeBinaries.URL,
+		"NODE_BINARIES_SHA256":   binaryChecksum(cluster.AssetConfiguration.NodeBinaries),
 		"KUBECTL_URL":            cluster.AssetConfiguration.Kubectl.URL,
+		"KUBECTL_SHA256":         binaryChecksum(cluster.AssetConfiguration.Kubectl),
 		"KUBERNETES_VERSION":     cluster.Versions.Kubernetes,
 		"KUBERNETES_CNI_VERSION": defaultKubernetesCNIVersion,
 		"CONFIGURE_REPOSITORIES": cluster.SystemPackages.ConfigureRepositories,
 		"INSECURE_REGISTRY":      cluster.RegistryConfiguration.InsecureRegistryAddress(),
 		"PROXY":                  proxy,
 		"INSTALL_DOCKER":         cluster.ContainerRuntime.Docker,
-		"INSTALL_CONTAINERD":     cluster.ContainerRuntime.Containerd,
+		"INSTALL_CONTAINERD":     containerdSettings(cluster),
+		"SELINUX_PERMISSIVE":     cluster.SELinuxMode() == kubeone.SELinuxModePermissive,
+		"SELINUX_ENFORCING":      cluster.SELinuxMode() == kubeone.SELinuxModeEnforcing,
 		"USE_KUBERNETES_REPO":    useKubernetesRepo,
+		"BINARY_ASSETS":          binaryAssets,
+		"LINK_BINARIES":          true,
 	})
 }
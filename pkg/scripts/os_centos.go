@@ -22,8 +22,14 @@ const (
 	kubeadmCentOSTemplate = `
 sudo swapoff -a
 sudo sed -i '/.*swap.*/d' /etc/fstab
-sudo setenforce 0 || true
+{{- if .SELINUX_PERMISSIVE }}
+if ! sudo setenforce 0 && grep -qs '^SELINUX=enforcing' /etc/selinux/config; then
+	# The SELinux mode can't be changed at runtime, it's applied by the reboot
+	sudo mkdir -p {{ rebootRequiredFile | dir }}
+	echo "SELinux mode changed to permissive" | sudo tee -a {{ rebootRequiredFile }} >/dev/null
+fi
 [ -f /etc/selinux/config ] && sudo sed -i 's/SELINUX=enforcing/SELINUX=permissive/g' /etc/selinux/config
+{{- end }}
 sudo systemctl disable --now firewalld || true
 
 source /etc/kubeone/proxy-env
@@ -39,7 +45,7 @@ grep -v '#kubeone' /etc/yum.conf > /tmp/yum.conf || true
 echo -n "${yum_proxy}" >> /tmp/yum.conf
 sudo mv /tmp/yum.conf /etc/yum.conf
 
-{{ if .CONFIGURE_REPOSITORIES }}
+{{ if and .CONFIGURE_REPOSITORIES (not .BINARY_ASSETS) }}
 cat <<EOF | sudo tee /etc/yum.repos.d/kubernetes.repo
 [kubernetes]
 name=Kubernetes
@@ -70,6 +76,10 @@ sudo yum install -y \
 {{ template "yum-containerd" . }}
 {{ end }}
 
+{{- if .BINARY_ASSETS }}
+{{ template "binaries-from-assets" . }}
+{{- else }}
+
 {{- if or .FORCE .UPGRADE }}
 sudo yum versionlock delete kubelet kubeadm kubectl kubernetes-cni || true
 {{- end }}
@@ -86,6 +96,7 @@ sudo yum install -y \
 {{- end }}
 	kubernetes-cni-{{ .KUBERNETES_CNI_VERSION }}
 sudo yum versionlock add kubelet kubeadm kubectl kubernetes-cni
+{{- end }}
 
 sudo systemctl daemon-reload
 sudo systemctl enable --now kubelet
@@ -94,12 +105,16 @@ sudo systemctl restart kubelet
 {{ end }}
 `
 	removeBinariesCentOSScriptTemplate = `
+{{- if .BINARY_ASSETS }}
+{{ template "remove-binaries-from-assets" }}
+{{- else }}
 sudo yum versionlock delete kubelet kubeadm kubectl kubernetes-cni || true
 sudo yum remove -y \
 	kubelet \
 	kubeadm \
 	kubectl
 sudo yum remove -y kubernetes-cni || true
+{{- end }}
 `
 )
 
@@ -120,12 +135,18 @@ func KubeadmCentOS(cluster *kubeone.KubeOneCluster, force bool) (string, error)
 		"PROXY":                  proxy,
 		"FORCE":                  force,
 		"INSTALL_DOCKER":         cluster.ContainerRuntime.Docker,
-		"INSTALL_CONTAINERD":     cluster.ContainerRuntime.Containerd,
+		"INSTALL_CONTAINERD":     containerdSettings(cluster),
+		"SELINUX_PERMISSIVE":     cluster.SELinuxMode() == kubeone.SELinuxModePermissive,
+		"SELINUX_ENFORCING":      cluster.SELinuxMode() == kubeone.SELinuxModeEnforcing,
+		"BINARY_ASSETS":          binariesFromAssets(cluster),
+		"LINK_BINARIES":          true,
 	})
 }
 
-func RemoveBinariesCentOS() (string, error) {
-	return Render(removeBinariesCentOSScriptTemplate, Data{})
+func RemoveBinariesCentOS(cluster *kubeone.KubeOneCluster) (string, error) {
+	return Render(removeBinariesCentOSScriptTemplate, Data{
+		"BINARY_ASSETS": binariesFromAssets(cluster),
+	})
 }
 
 func UpgradeKubeadmAndCNICentOS(cluster *kubeone.KubeOneCluster) (string, error) {
@@ -143,7 +164,11 @@ func UpgradeKubeadmAndCNICentOS(cluster *kubeone.KubeOneCluster) (string, error)
 		"INSECURE_REGISTRY":      cluster.RegistryConfiguration.InsecureRegistryAddress(),
 		"PROXY":                  proxy,
 		"INSTALL_DOCKER":         cluster.ContainerRuntime.Docker,
-		"INSTALL_CONTAINERD":     cluster.ContainerRuntime.Containerd,
+		"INSTALL_CONTAINERD":     containerdSettings(cluster),
+		"SELINUX_PERMISSIVE":     cluster.SELinuxMode() == kubeone.SELinuxModePermissive,
+		"SELINUX_ENFORCING":      cluster.SELinuxMode() == kubeone.SELinuxModeEnforcing,
+		"BINARY_ASSETS":          binariesFromAssets(cluster),
+		"LINK_BINARIES":          true,
 	})
 }
 
@@ -163,6 +188,10 @@ func UpgradeKubeletAndKubectlCentOS(cluster *kubeone.KubeOneCluster) (string, er
 		"INSECURE_REGISTRY":      cluster.RegistryConfiguration.InsecureRegistryAddress(),
 		"PROXY":                  proxy,
 		"INSTALL_DOCKER":         cluster.ContainerRuntime.Docker,
-		"INSTALL_CONTAINERD":     cluster.ContainerRuntime.Containerd,
+		"INSTALL_CONTAINERD":     containerdSettings(cluster),
+		"SELINUX_PERMISSIVE":     cluster.SELinuxMode() == kubeone.SELinuxModePermissive,
+		"SELINUX_ENFORCING":      cluster.SELinuxMode() == kubeone.SELinuxModeEnforcing,
+		"BINARY_ASSETS":          binariesFromAssets(cluster),
+		"LINK_BINARIES":          true,
 	})
 }
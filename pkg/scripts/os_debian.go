@@ -46,9 +46,15 @@ sudo DEBIAN_FRONTEND=noninteractive apt-get install --option "Dpkg::Options::=--
 	curl \
 	gnupg \
 	lsb-release \
+{{- if .BINARY_ASSETS }}
+	conntrack \
+	ebtables \
+	ethtool \
+	socat \
+{{- end }}
 	rsync
 
-{{- if .CONFIGURE_REPOSITORIES }}
+{{- if and .CONFIGURE_REPOSITORIES (not .BINARY_ASSETS) }}
 curl -fsSL https://packages.cloud.google.com/apt/doc/apt-key.gpg | sudo apt-key add -
 
 # You'd think that kubernetes-$(lsb_release -sc) belongs there instead, but the debian repo
@@ -61,7 +67,7 @@ sudo apt-get update
 kube_ver="{{ .KUBERNETES_VERSION }}*"
 cni_ver="{{ .KUBERNETES_CNI_VERSION }}*"
 
-{{- if or .FORCE .UPGRADE }}
+{{- if and (or .FORCE .UPGRADE) (not .BINARY_ASSETS) }}
 sudo apt-mark unhold kubelet kubeadm kubectl kubernetes-cni
 {{- end }}
 
@@ -74,6 +80,9 @@ sudo apt-mark unhold kubelet kubeadm kubectl kubernetes-cni
 {{ template "apt-containerd" . }}
 {{ end }}
 
+{{- if .BINARY_ASSETS }}
+{{ template "binaries-from-assets" . }}
+{{- else }}
 sudo DEBIAN_FRONTEND=noninteractive apt-get install \
 	--option "Dpkg::Options::=--force-confold" \
 	--no-install-recommends \
@@ -93,6 +102,7 @@ sudo DEBIAN_FRONTEND=noninteractive apt-get install \
 	kubernetes-cni=${cni_ver}
 
 sudo apt-mark hold kubelet kubeadm kubectl kubernetes-cni
+{{- end }}
 
 sudo systemctl daemon-reload
 sudo systemctl enable --now kubelet
@@ -103,12 +113,16 @@ sudo systemctl restart kubelet
 `
 
 	removeBinariesDebianScriptTemplate = `
+{{- if .BINARY_ASSETS }}
+{{ template "remove-binaries-from-assets" }}
+{{- else }}
 sudo apt-mark unhold kubelet kubeadm kubectl kubernetes-cni
 sudo apt-get remove --purge -y \
 	kubeadm \
 	kubectl \
 	kubelet
 sudo apt-get remove --purge -y kubernetes-cni || true
+{{- end }}
 `
 )
 
@@ -125,12 +139,16 @@ func KubeadmDebian(cluster *kubeone.KubeOneCluster, force bool) (string, error)
 		"HTTPS_PROXY":            cluster.Proxy.HTTPS,
 		"FORCE":                  force,
 		"INSTALL_DOCKER":         cluster.ContainerRuntime.Docker,
-		"INSTALL_CONTAINERD":     cluster.ContainerRuntime.Containerd,
+		"INSTALL_CONTAINERD":     containerdSettings(cluster),
+		"BINARY_ASSETS":          binariesFromAssets(cluster),
+		"LINK_BINARIES":          true,
 	})
 }
 
-func RemoveBinariesDebian() (string, error) {
-	return Render(removeBinariesDebianScriptTemplate, Data{})
+func RemoveBinariesDebian(cluster *kubeone.KubeOneCluster) (string, error) {
+	return Render(removeBinariesDebianScriptTemplate, Data{
+		"BINARY_ASSETS": binariesFromAssets(cluster),
+	})
 }
 
 func UpgradeKubeadmAndCNIDebian(cluster *kubeone.KubeOneCluster) (string, error) {
@@ -144,7 +162,9 @@ func UpgradeKubeadmAndCNIDebian(cluster *kubeone.KubeOneCluster) (string, error)
 		"HTTP_PROXY":             cluster.Proxy.HTTP,
 		"HTTPS_PROXY":            cluster.Proxy.HTTPS,
 		"INSTALL_DOCKER":         cluster.ContainerRuntime.Docker,
-		"INSTALL_CONTAINERD":     cluster.ContainerRuntime.Containerd,
+		"INSTALL_CONTAINERD":     containerdSettings(cluster),
+		"BINARY_ASSETS":          binariesFromAssets(cluster),
+		"LINK_BINARIES":          true,
 	})
 }
 
@@ -160,6 +180,8 @@ func UpgradeKubeletAndKubectlDebian(cluster *kubeone.KubeOneCluster) (string, er
 		"HTTP_PROXY":             cluster.Proxy.HTTP,
 		"HTTPS_PROXY":            cluster.Proxy.HTTPS,
 		"INSTALL_DOCKER":         cluster.ContainerRuntime.Docker,
-		"INSTALL_CONTAINERD":     cluster.ContainerRuntime.Containerd,
+		"INSTALL_CONTAINERD":     containerdSettings(cluster),
+		"BINARY_ASSETS":          binariesFromAssets(cluster),
+		"LINK_BINARIES":          true,
 	})
 }
@@ -22,19 +22,10 @@ const (
 	kubeadmFlatcarTemplate = `
 source /etc/kubeone/proxy-env
 
-{{ template "detect-host-cpu-architecture" }}
 {{ template "sysctl-k8s" }}
 {{ template "journald-config" }}
 
 sudo mkdir -p /opt/cni/bin /etc/kubernetes/pki /etc/kubernetes/manifests
-curl -L "https://github.com/containernetworking/plugins/releases/download/v{{ .KUBERNETES_CNI_VERSION }}/cni-plugins-linux-${HOST_ARCH}-v{{ .KUBERNETES_CNI_VERSION }}.tgz" |
-	sudo tar -C /opt/cni/bin -xz
-
-RELEASE="v{{ .KUBERNETES_VERSION }}"
-CRI_TOOLS_RELEASE="v{{ .CRITOOLS_VERSION }}"
-
-curl -L https://github.com/kubernetes-sigs/cri-tools/releases/download/${CRI_TOOLS_RELEASE}/crictl-${CRI_TOOLS_RELEASE}-linux-${HOST_ARCH}.tar.gz |
-	sudo tar -C /opt/bin -xz
 
 {{ if .INSTALL_DOCKER }}
 {{ template "docker-daemon-config" . }}
@@ -45,46 +36,7 @@ curl -L https://github.com/kubernetes-sigs/cri-tools/releases/download/${CRI_TOO
 {{ template "flatcar-containerd" }}
 {{ end }}
 
-sudo mkdir -p /opt/bin
-cd /opt/bin
-k8s_rel_baseurl=https://storage.googleapis.com/kubernetes-release/release
-for binary in kubeadm kubelet kubectl; do
-	curl -L --output /tmp/$binary \
-		$k8s_rel_baseurl/${RELEASE}/bin/linux/${HOST_ARCH}/$binary
-	sudo install --owner=0 --group=0 --mode=0755 /tmp/$binary /opt/bin/$binary
-	rm /tmp/$binary
-done
-
-cat <<EOF | sudo tee /etc/systemd/system/kubelet.service
-[Unit]
-Description=kubelet: The Kubernetes Node Agent
-Documentation=https://kubernetes.io/docs/home/
-Wants=network-online.target
-After=network-online.target
-
-[Service]
-ExecStart=/opt/bin/kubelet
-Restart=always
-StartLimitInterval=0
-RestartSec=10
-
-[Install]
-WantedBy=multi-user.target
-EOF
-
-sudo mkdir -p /etc/systemd/system/kubelet.service.d
-cat <<EOF | sudo tee /etc/systemd/system/kubelet.service.d/10-kubeadm.conf
-[Service]
-Environment="KUBELET_KUBECONFIG_ARGS=--bootstrap-kubeconfig=/etc/kubernetes/bootstrap-kubelet.conf --kubeconfig=/etc/kubernetes/kubelet.conf"
-Environment="KUBELET_CONFIG_ARGS=--config=/var/lib/kubelet/config.yaml"
-# This is a file that "kubeadm init" and "kubeadm join" generates at runtime, populating the KUBELET_KUBEADM_ARGS variable dynamically
-EnvironmentFile=-/var/lib/kubelet/kubeadm-flags.env
-# This is a file that the user can use for overrides of the kubelet args as a last resort. Preferably, the user should use
-# the .NodeRegistration.KubeletExtraArgs object in the configuration files instead. KUBELET_EXTRA_ARGS should be sourced from this file.
-EnvironmentFile=-/etc/default/kubelet
-ExecStart=
-ExecStart=/opt/bin/kubelet \$KUBELET_KUBECONFIG_ARGS \$KUBELET_CONFIG_ARGS \$KUBELET_KUBEADM_ARGS \$KUBELET_EXTRA_ARGS
-EOF
+{{ template "binaries-from-assets" . }}
 
 sudo systemctl daemon-reload
 sudo systemctl enable --now kubelet
@@ -102,73 +54,16 @@ sudo systemctl daemon-reload
 `
 
 	upgradeKubeadmAndCNIFlatcarScriptTemplate = `
-{{ template "detect-host-cpu-architecture" }}
-
 source /etc/kubeone/proxy-env
 
-sudo mkdir -p /opt/cni/bin
-curl -L "https://github.com/containernetworking/plugins/releases/download/v{{ .KUBERNETES_CNI_VERSION }}/cni-plugins-linux-${HOST_ARCH}-v{{ .KUBERNETES_CNI_VERSION }}.tgz" |
-	sudo tar -C /opt/cni/bin -xz
-
-RELEASE="v{{ .KUBERNETES_VERSION }}"
-
-sudo mkdir -p /var/tmp/kube-binaries
-cd /var/tmp/kube-binaries
-sudo curl -L --remote-name-all \
-	https://storage.googleapis.com/kubernetes-release/release/${RELEASE}/bin/linux/${HOST_ARCH}/kubeadm
-
-sudo mkdir -p /opt/bin
-cd /opt/bin
-sudo mv /var/tmp/kube-binaries/kubeadm .
-sudo chmod +x kubeadm
+{{ template "binaries-from-assets" . }}
 `
 
 	upgradeKubeletAndKubectlFlatcarScriptTemplate = `
 source /etc/kubeone/proxy-env
 
-{{ template "detect-host-cpu-architecture" }}
-
-RELEASE="v{{ .KUBERNETES_VERSION }}"
-sudo mkdir -p /var/tmp/kube-binaries
-cd /var/tmp/kube-binaries
-sudo curl -L --remote-name-all \
-	https://storage.googleapis.com/kubernetes-release/release/${RELEASE}/bin/linux/${HOST_ARCH}/{kubelet,kubectl}
-sudo mkdir -p /opt/bin
-cd /opt/bin
 sudo systemctl stop kubelet
-sudo mv /var/tmp/kube-binaries/{kubelet,kubectl} .
-sudo chmod +x {kubelet,kubectl}
-
-cat <<EOF | sudo tee /etc/systemd/system/kubelet.service
-[Unit]
-Description=kubelet: The Kubernetes Node Agent
-Documentation=https://kubernetes.io/docs/home/
-Wants=network-online.target
-After=network-online.target
-
-[Service]
-ExecStart=/opt/bin/kubelet
-Restart=always
-StartLimitInterval=0
-RestartSec=10
-
-[Install]
-WantedBy=multi-user.target
-EOF
-
-sudo mkdir -p /etc/systemd/system/kubelet.service.d
-cat <<EOF | sudo tee /etc/systemd/system/kubelet.service.d/10-kubeadm.conf
-[Service]
-Environment="KUBELET_KUBECONFIG_ARGS=--bootstrap-kubeconfig=/etc/kubernetes/bootstrap-kubelet.conf --kubeconfig=/etc/kubernetes/kubelet.conf"
-Environment="KUBELET_CONFIG_ARGS=--config=/var/lib/kubelet/config.yaml"
-# This is a file that "kubeadm init" and "kubeadm join" generates at runtime, populating the KUBELET_KUBEADM_ARGS variable dynamically
-EnvironmentFile=-/var/lib/kubelet/kubeadm-flags.env
-# This is a file that the user can use for overrides of the kubelet args as a last resort. Preferably, the user should use
-# the .NodeRegistration.KubeletExtraArgs object in the configuration files instead. KUBELET_EXTRA_ARGS should be sourced from this file.
-EnvironmentFile=-/etc/default/kubelet
-ExecStart=
-ExecStart=/opt/bin/kubelet \$KUBELET_KUBECONFIG_ARGS \$KUBELET_CONFIG_ARGS \$KUBELET_KUBEADM_ARGS \$KUBELET_EXTRA_ARGS
-EOF
+{{ template "binaries-from-assets" . }}
 
 sudo systemctl daemon-reload
 sudo systemctl start kubelet
@@ -177,12 +72,13 @@ sudo systemctl start kubelet
 
 func KubeadmFlatcar(cluster *kubeone.KubeOneCluster) (string, error) {
 	return Render(kubeadmFlatcarTemplate, Data{
-		"KUBERNETES_VERSION":     cluster.Versions.Kubernetes,
-		"KUBERNETES_CNI_VERSION": defaultKubernetesCNIVersion,
-		"CRITOOLS_VERSION":       defaultCriToolsVersion,
-		"INSECURE_REGISTRY":      cluster.RegistryConfiguration.InsecureRegistryAddress(),
-		"INSTALL_DOCKER":         cluster.ContainerRuntime.Docker,
-		"INSTALL_CONTAINERD":     cluster.ContainerRuntime.Containerd,
+		"KUBELET":            true,
+		"KUBEADM":            true,
+		"KUBECTL":            true,
+		"BINARY_ASSETS":      binarySources(cluster),
+		"INSECURE_REGISTRY":  cluster.RegistryConfiguration.InsecureRegistryAddress(),
+		"INSTALL_DOCKER":     cluster.ContainerRuntime.Docker,
+		"INSTALL_CONTAINERD": containerdSettings(cluster),
 	})
 }
 
@@ -190,15 +86,26 @@ func RemoveBinariesFlatcar() (string, error) {
 	return Render(removeBinariesFlatcarScriptTemplate, nil)
 }
 
-func UpgradeKubeadmAndCNIFlatcar(k8sVersion string) (string, error) {
+func UpgradeKubeadmAndCNIFlatcar(cluster *kubeone.KubeOneCluster) (string, error) {
 	return Render(upgradeKubeadmAndCNIFlatcarScriptTemplate, Data{
-		"KUBERNETES_VERSION":     k8sVersion,
-		"KUBERNETES_CNI_VERSION": defaultKubernetesCNIVersion,
+		"KUBEADM":       true,
+		"BINARY_ASSETS": flatcarUpgradeBinarySources(cluster),
 	})
 }
 
-func UpgradeKubeletAndKubectlFlatcar(k8sVersion string) (string, error) {
+func UpgradeKubeletAndKubectlFlatcar(cluster *kubeone.KubeOneCluster) (string, error) {
 	return Render(upgradeKubeletAndKubectlFlatcarScriptTemplate, Data{
-		"KUBERNETES_VERSION": k8sVersion,
+		"KUBELET":       true,
+		"KUBECTL":       true,
+		"BINARY_ASSETS": flatcarUpgradeBinarySources(cluster),
 	})
 }
+
+// flatcarUpgradeBinarySources returns binary sources without crictl, which
+// is installed only once when provisioning the node
+func flatcarUpgradeBinarySources(cluster *kubeone.KubeOneCluster) *binaryAssets {
+	sources := binarySources(cluster)
+	sources.Crictl = binarySource{}
+
+	return sources
+}
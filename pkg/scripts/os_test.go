@@ -82,6 +82,61 @@ func withDefaultAssetConfiguration(cls *kubeone.KubeOneCluster) {
 	}
 }
 
+func withBinariesMirror(cls *kubeone.KubeOneCluster) {
+	cls.AssetConfiguration = kubeone.AssetConfiguration{
+		BinariesMirror: "http://127.0.0.1:8080/kubernetes/",
+		Kubelet: kubeone.BinaryAsset{
+			SHA256: "a8a4f4c4e2d5f2e3b1c0d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8",
+		},
+	}
+}
+
+func withBinaryAssets(cls *kubeone.KubeOneCluster) {
+	cls.AssetConfiguration = kubeone.AssetConfiguration{
+		Kubeadm: kubeone.BinaryAsset{
+			URL:    "http://127.0.0.1/kubeadm",
+			SHA256: "b8a4f4c4e2d5f2e3b1c0d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8",
+		},
+		Kubelet: kubeone.BinaryAsset{
+			URL: "http://127.0.0.1/kubelet",
+		},
+		Kubectl: kubeone.BinaryAsset{
+			URL: "http://127.0.0.1/kubectl",
+		},
+		CNI: kubeone.BinaryAsset{
+			URL: "http://127.0.0.1/cni.tgz",
+		},
+	}
+}
+
+func withSignatureVerification(publicKey string) genClusterOpts {
+	return func(cls *kubeone.KubeOneCluster) {
+		cls.AssetConfiguration.SignatureVerification = &kubeone.SignatureVerification{
+			PublicKey:             publicKey,
+			CertificateIdentity:   "krel-staging@k8s-releng-prod.iam.gserviceaccount.com",
+			CertificateOIDCIssuer: "https://accounts.google.com",
+		}
+	}
+}
+
+func withContainerdSettings(cls *kubeone.KubeOneCluster) {
+	cls.ContainerRuntime.Docker = nil
+	cls.ContainerRuntime.Containerd = &kubeone.ContainerRuntimeContainerd{
+		SandboxImage: "127.0.0.1:5000/pause:3.5",
+		NvidiaRuntime: &kubeone.ContainerdNvidiaRuntime{
+			Enable:         true,
+			DefaultRuntime: true,
+		},
+		ConfigPatch: `
+[plugins."io.containerd.grpc.v1.cri"]
+max_concurrent_downloads = 10
+
+[plugins."io.containerd.grpc.v1.cri".containerd.runtimes.nvidia.options]
+BinaryName = "/usr/local/nvidia/toolkit/nvidia-container-runtime"
+`,
+	}
+}
+
 func genCluster(opts ...genClusterOpts) kubeone.KubeOneCluster {
 	cls := &kubeone.KubeOneCluster{
 		Versions: kubeone.VersionConfig{
@@ -139,6 +194,36 @@ func TestKubeadmDebian(t *testing.T) {
 				cluster: genCluster(withContainerd),
 			},
 		},
+		{
+			name: "with binaries mirror",
+			args: args{
+				cluster: genCluster(withContainerd, withBinariesMirror),
+			},
+		},
+		{
+			name: "with binary assets",
+			args: args{
+				cluster: genCluster(withContainerd, withBinaryAssets),
+			},
+		},
+		{
+			name: "with containerd settings",
+			args: args{
+				cluster: genCluster(withContainerdSettings),
+			},
+		},
+		{
+			name: "with signature verification",
+			args: args{
+				cluster: genCluster(withContainerd, withBinariesMirror, withSignatureVerification("")),
+			},
+		},
+		{
+			name: "with signature verification public key",
+			args: args{
+				cluster: genCluster(withContainerd, withBinaryAssets, withSignatureVerification("-----BEGIN PUBLIC KEY-----\nMFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAE\n-----END PUBLIC KEY-----\n")),
+			},
+		},
 		{
 			name: "with containerd with insecure registry",
 			args: args{
@@ -166,21 +251,23 @@ func TestMigrateToContainerd(t *testing.T) {
 
 	tests := []struct {
 		name                     string
-		insecureRegistry         string
+		cluster                  kubeone.KubeOneCluster
 		generateContainerdConfig bool
 		err                      error
 	}{
 		{
 			name:                     "simple",
+			cluster:                  genCluster(withContainerd),
 			generateContainerdConfig: true,
 		},
 		{
 			name:                     "flatcat",
+			cluster:                  genCluster(withContainerd),
 			generateContainerdConfig: false,
 		},
 		{
 			name:                     "insecureRegistry",
-			insecureRegistry:         "some.registry",
+			cluster:                  genCluster(withContainerd, withInsecureRegistry("some.registry")),
 			generateContainerdConfig: true,
 		},
 	}
@@ -188,7 +275,7 @@ func TestMigrateToContainerd(t *testing.T) {
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := MigrateToContainerd(tt.insecureRegistry, tt.generateContainerdConfig)
+			got, err := MigrateToContainerd(&tt.cluster, tt.generateContainerdConfig)
 			if err != tt.err {
 				t.Errorf("MigrateToContainerd() error = %v, wantErr %v", err, tt.err)
 				return
@@ -254,12 +341,42 @@ func TestKubeadmCentOS(t *testing.T) {
 				cluster: genCluster(withContainerd),
 			},
 		},
+		{
+			name: "with binaries mirror",
+			args: args{
+				cluster: genCluster(withContainerd, withBinariesMirror),
+			},
+		},
+		{
+			name: "with binary assets",
+			args: args{
+				cluster: genCluster(withContainerd, withBinaryAssets),
+			},
+		},
 		{
 			name: "with containerd with insecure registry",
 			args: args{
 				cluster: genCluster(withContainerd, withInsecureRegistry("127.0.0.1:5000")),
 			},
 		},
+		{
+			name: "with containerd with selinux enforcing",
+			args: args{
+				cluster: genCluster(withContainerd, withSELinux(kubeone.SELinuxModeEnforcing)),
+			},
+		},
+		{
+			name: "with docker with selinux enforcing",
+			args: args{
+				cluster: genCluster(withDocker, withSELinux(kubeone.SELinuxModeEnforcing)),
+			},
+		},
+		{
+			name: "with selinux unmanaged",
+			args: args{
+				cluster: genCluster(withContainerd, withSELinux(kubeone.SELinuxModeUnmanaged)),
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -353,6 +470,18 @@ func TestKubeadmAmazonLinux(t *testing.T) {
 				cluster: genCluster(withContainerd),
 			},
 		},
+		{
+			name: "with binaries mirror",
+			args: args{
+				cluster: genCluster(withContainerd, withBinariesMirror),
+			},
+		},
+		{
+			name: "with binary assets",
+			args: args{
+				cluster: genCluster(withContainerd, withBinaryAssets),
+			},
+		},
 		{
 			name: "with containerd with insecure registry",
 			args: args{
@@ -422,6 +551,30 @@ func TestKubeadmFlatcar(t *testing.T) {
 				cluster: genCluster(withContainerd),
 			},
 		},
+		{
+			name: "with binaries mirror",
+			args: args{
+				cluster: genCluster(withContainerd, withBinariesMirror),
+			},
+		},
+		{
+			name: "with binary assets",
+			args: args{
+				cluster: genCluster(withContainerd, withBinaryAssets),
+			},
+		},
+		{
+			name: "with signature verification",
+			args: args{
+				cluster: genCluster(withContainerd, withBinariesMirror, withSignatureVerification("")),
+			},
+		},
+		{
+			name: "with signature verification public key",
+			args: args{
+				cluster: genCluster(withContainerd, withBinaryAssets, withSignatureVerification("-----BEGIN PUBLIC KEY-----\nMFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAE\n-----END PUBLIC KEY-----\n")),
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -441,25 +594,63 @@ func TestKubeadmFlatcar(t *testing.T) {
 func TestRemoveBinariesDebian(t *testing.T) {
 	t.Parallel()
 
-	got, err := RemoveBinariesDebian()
-	if err != nil {
-		t.Errorf("RemoveBinariesDebian() error = %v", err)
-		return
+	tests := []struct {
+		name    string
+		cluster kubeone.KubeOneCluster
+	}{
+		{
+			name:    "simple",
+			cluster: genCluster(withContainerd),
+		},
+		{
+			name:    "with binary assets",
+			cluster: genCluster(withContainerd, withBinaryAssets),
+		},
 	}
 
-	testhelper.DiffOutput(t, testhelper.FSGoldenName(t), got, *updateFlag)
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RemoveBinariesDebian(&tt.cluster)
+			if err != nil {
+				t.Errorf("RemoveBinariesDebian() error = %v", err)
+				return
+			}
+
+			testhelper.DiffOutput(t, testhelper.FSGoldenName(t), got, *updateFlag)
+		})
+	}
 }
 
 func TestRemoveBinariesCentOS(t *testing.T) {
 	t.Parallel()
 
-	got, err := RemoveBinariesCentOS()
-	if err != nil {
-		t.Errorf("RemoveBinariesCentOS() error = %v", err)
-		return
+	tests := []struct {
+		name    string
+		cluster kubeone.KubeOneCluster
+	}{
+		{
+			name:    "simple",
+			cluster: genCluster(withContainerd),
+		},
+		{
+			name:    "with binary assets",
+			cluster: genCluster(withContainerd, withBinaryAssets),
+		},
 	}
 
-	testhelper.DiffOutput(t, testhelper.FSGoldenName(t), got, *updateFlag)
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RemoveBinariesCentOS(&tt.cluster)
+			if err != nil {
+				t.Errorf("RemoveBinariesCentOS() error = %v", err)
+				return
+			}
+
+			testhelper.DiffOutput(t, testhelper.FSGoldenName(t), got, *updateFlag)
+		})
+	}
 }
 
 func TestRemoveBinariesAmazonLinux(t *testing.T) {
@@ -528,7 +719,8 @@ func TestUpgradeKubeadmAndCNIAmazonLinux(t *testing.T) {
 func TestUpgradeKubeadmAndCNIFlatcar(t *testing.T) {
 	t.Parallel()
 
-	got, err := UpgradeKubeadmAndCNIFlatcar("v1.17.4")
+	cls := genCluster(withDocker)
+	got, err := UpgradeKubeadmAndCNIFlatcar(&cls)
 	if err != nil {
 		t.Errorf("UpgradeKubeadmAndCNIFlatcar() error = %v", err)
 		return
@@ -579,7 +771,34 @@ func TestUpgradeKubeletAndKubectlAmazonLinux(t *testing.T) {
 func TestUpgradeKubeletAndKubectlFlatcar(t *testing.T) {
 	t.Parallel()
 
-	got, err := UpgradeKubeletAndKubectlFlatcar("v1.17.4")
+	cls := genCluster(withDocker)
+	got, err := UpgradeKubeletAndKubectlFlatcar(&cls)
+	if err != nil {
+		t.Errorf("UpgradeKubeletAndKubectlFlatcar() error = %v", err)
+		return
+	}
+
+	testhelper.DiffOutput(t, testhelper.FSGoldenName(t), got, *updateFlag)
+}
+
+func TestUpgradeKubeadmAndCNIFlatcarBinariesMirror(t *testing.T) {
+	t.Parallel()
+
+	cls := genCluster(withContainerd, withBinariesMirror)
+	got, err := UpgradeKubeadmAndCNIFlatcar(&cls)
+	if err != nil {
+		t.Errorf("UpgradeKubeadmAndCNIFlatcar() error = %v", err)
+		return
+	}
+
+	testhelper.DiffOutput(t, testhelper.FSGoldenName(t), got, *updateFlag)
+}
+
+func TestUpgradeKubeletAndKubectlFlatcarBinariesMirror(t *testing.T) {
+	t.Parallel()
+
+	cls := genCluster(withContainerd, withBinariesMirror)
+	got, err := UpgradeKubeletAndKubectlFlatcar(&cls)
 	if err != nil {
 		t.Errorf("UpgradeKubeletAndKubectlFlatcar() error = %v", err)
 		return
@@ -587,3 +806,16 @@ func TestUpgradeKubeletAndKubectlFlatcar(t *testing.T) {
 
 	testhelper.DiffOutput(t, testhelper.FSGoldenName(t), got, *updateFlag)
 }
+
+func TestUpgradeKubeletAndKubectlDebianBinariesMirror(t *testing.T) {
+	t.Parallel()
+
+	cls := genCluster(withContainerd, withBinariesMirror)
+	got, err := UpgradeKubeletAndKubectlDebian(&cls)
+	if err != nil {
+		t.Errorf("UpgradeKubeletAndKubectlDebian() error = %v", err)
+		return
+	}
+
+	testhelper.DiffOutput(t, testhelper.FSGoldenName(t), got, *updateFlag)
+}
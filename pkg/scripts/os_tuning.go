@@ -0,0 +1,60 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scripts
+
+import (
+	"github.com/MakeNowJust/heredoc/v2"
+
+	"k8c.io/kubeone/pkg/apis/kubeone"
+)
+
+var (
+	osTuningScriptTemplate = heredoc.Doc(`
+		{{ if .KERNEL_MODULES -}}
+		cat <<'EOF' | sudo tee /etc/modules-load.d/kubeone.conf
+		{{ range .KERNEL_MODULES }}{{ . }}
+		{{ end -}}
+		EOF
+		{{ range .KERNEL_MODULES -}}
+		sudo modprobe {{ . }}
+		{{ end -}}
+		{{ else -}}
+		sudo rm -f /etc/modules-load.d/kubeone.conf
+		{{ end }}
+
+		{{ if .SYSCTLS -}}
+		sudo mkdir -p /etc/sysctl.d
+		cat <<'EOF' | sudo tee /etc/sysctl.d/99-kubeone.conf
+		{{ range $key, $value := .SYSCTLS }}{{ $key }} = {{ $value }}
+		{{ end -}}
+		EOF
+		{{ else -}}
+		sudo rm -f /etc/sysctl.d/99-kubeone.conf
+		{{ end -}}
+		sudo sysctl --system
+	`)
+)
+
+// OSTuning returns the script saving the kernel modules and the sysctl
+// settings to the modules-load.d and sysctl.d configuration, and applying
+// them. The configuration files are removed if there's nothing to configure.
+func OSTuning(tuning kubeone.OSTuning) (string, error) {
+	return Render(osTuningScriptTemplate, Data{
+		"KERNEL_MODULES": tuning.KernelModules,
+		"SYSCTLS":        tuning.Sysctls,
+	})
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scripts
+
+import (
+	"testing"
+
+	"k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/testhelper"
+)
+
+func TestOSTuning(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		tuning kubeone.OSTuning
+	}{
+		{
+			name:   "empty",
+			tuning: kubeone.OSTuning{},
+		},
+		{
+			name: "modules and sysctls",
+			tuning: kubeone.OSTuning{
+				KernelModules: []string{"ip_vs", "nf_conntrack"},
+				Sysctls: map[string]string{
+					"net.netfilter.nf_conntrack_max": "1000000",
+					"net.ipv4.conf.all.rp_filter":    "1",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := OSTuning(tt.tuning)
+			if err != nil {
+				t.Errorf("OSTuning() error = %v", err)
+				return
+			}
+
+			testhelper.DiffOutput(t, testhelper.FSGoldenName(t), got, *updateFlag)
+		})
+	}
+}
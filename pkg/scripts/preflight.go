@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scripts
+
+import "github.com/MakeNowJust/heredoc/v2"
+
+var (
+	hostPreflightScript = heredoc.Doc(`
+		if sudo -n true >/dev/null 2>&1; then echo "sudo=yes"; else echo "sudo=no"; fi
+
+		if [ -f /etc/kubernetes/kubelet.conf ]; then echo "provisioned=yes"; else echo "provisioned=no"; fi
+
+		echo "cpus=$(nproc)"
+		echo "memory_kib=$(awk '/^MemTotal:/ {print $2}' /proc/meminfo)"
+		echo "swap_kib=$(awk 'NR > 1 {sum += $3} END {print sum + 0}' /proc/swaps)"
+
+		if [ "$(stat -fc %T /sys/fs/cgroup)" = "cgroup2fs" ]; then echo "cgroup=v2"; else echo "cgroup=v1"; fi
+
+		echo "time=$(date +%s.%N)"
+		if command -v timedatectl >/dev/null 2>&1; then
+			echo "time_synced=$(timedatectl show -p NTPSynchronized --value 2>/dev/null || echo unknown)"
+		else
+			echo "time_synced=unknown"
+		fi
+
+		. /etc/os-release
+		echo "os_family=${ID_LIKE:-$ID}"
+		if command -v getenforce >/dev/null 2>&1; then
+			echo "selinux=$(getenforce | tr '[:upper:]' '[:lower:]')"
+		else
+			echo "selinux=disabled"
+		fi
+		if [ "$(cat /sys/module/apparmor/parameters/enabled 2>/dev/null)" = "Y" ]; then echo "apparmor=enabled"; else echo "apparmor=disabled"; fi
+		if command -v apparmor_parser >/dev/null 2>&1; then echo "apparmor_parser=yes"; else echo "apparmor_parser=no"; fi
+
+		{{ range .KERNEL_MODULES }}
+		if [ -d /sys/module/{{ . }} ] || modinfo {{ . }} >/dev/null 2>&1; then
+			echo "module.{{ . }}=yes"
+		else
+			echo "module.{{ . }}=no"
+		fi
+		{{ end }}
+
+		{{ range .LOCAL_PORTS }}
+		if sudo ss -Hltn "sport = :{{ . }}" 2>/dev/null | grep -q .; then
+			echo "port.{{ . }}=used"
+		else
+			echo "port.{{ . }}=free"
+		fi
+		{{ end }}
+
+		# Refused connection means the port is reachable, but nothing is
+		# listening on it yet. Timeout means the traffic is filtered.
+		{{ range .PEERS }}
+		rc=0
+		out=$(timeout 3 bash -c 'echo >/dev/tcp/{{ .Host }}/{{ .Port }}' 2>&1) || rc=$?
+		if [ "$rc" -eq 0 ] || echo "$out" | grep -q "refused"; then
+			echo "peer.{{ .Host }}:{{ .Port }}=reachable"
+		elif [ "$rc" -eq 124 ]; then
+			echo "peer.{{ .Host }}:{{ .Port }}=filtered"
+		else
+			echo "peer.{{ .Host }}:{{ .Port }}=unreachable"
+		fi
+		{{ end }}
+	`)
+)
+
+// PreflightPeer is the address and the port the host is expected to reach
+type PreflightPeer struct {
+	Host string
+	Port int
+}
+
+// HostPreflight returns the script checking the host prerequisites, printing
+// the results as key=value lines. It doesn't change the host.
+func HostPreflight(kernelModules []string, localPorts []int, peers []PreflightPeer) (string, error) {
+	return Render(hostPreflightScript, Data{
+		"KERNEL_MODULES": kernelModules,
+		"LOCAL_PORTS":    localPorts,
+		"PEERS":          peers,
+	})
+}
@@ -29,7 +29,7 @@ var (
 	containerRuntimeTemplates = map[string]string{
 		"containerd-config": heredoc.Doc(`
 			cat <<EOF | sudo tee /etc/containerd/config.toml
-			{{ containerdCfg .INSECURE_REGISTRY -}}
+			{{ containerdCfg .INSECURE_REGISTRY .INSTALL_CONTAINERD (or .SELINUX_ENFORCING false) -}}
 			EOF
 
 			cat <<EOF | sudo tee /etc/crictl.yaml
@@ -252,8 +252,9 @@ func Render(cmd string, variables map[string]interface{}) (string, error) {
 	tpl := template.New("base").
 		Funcs(sprig.TxtFuncMap()).
 		Funcs(template.FuncMap{
-			"dockerCfg":     dockerCfg,
-			"containerdCfg": containerdCfg,
+			"dockerCfg":          dockerCfg,
+			"containerdCfg":      containerdCfg,
+			"rebootRequiredFile": func() string { return RebootRequiredFile },
 		})
 
 	_, err := tpl.New("library").Parse(libraryTemplate)
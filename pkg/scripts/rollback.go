@@ -0,0 +1,159 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scripts
+
+import "github.com/MakeNowJust/heredoc/v2"
+
+const (
+	// RollbackSnapshotDir is where the state of the control plane node is
+	// saved before it's upgraded
+	RollbackSnapshotDir = "/var/lib/kubeone/rollback"
+)
+
+var (
+	rollbackSnapshotScriptTemplate = heredoc.Doc(`
+		dir="{{ .DIR }}"
+		# The snapshot taken before the interrupted upgrade is kept, so
+		# retrying the upgrade doesn't overwrite it with the partially
+		# upgraded state
+		if sudo test -f "$dir/upgrade-in-progress"; then
+			exit 0
+		fi
+
+		sudo rm -rf "$dir.new"
+		sudo mkdir -p "$dir.new/bin" "$dir.new/kubelet"
+
+		sudo cp -a /etc/kubernetes "$dir.new/kubernetes"
+		for file in config.yaml kubeadm-flags.env; do
+			if sudo test -f "/var/lib/kubelet/$file"; then
+				sudo cp -a "/var/lib/kubelet/$file" "$dir.new/kubelet/$file"
+			fi
+		done
+
+		for binary in kubeadm kubelet kubectl; do
+			path=$(readlink -f "$(command -v $binary)")
+			sudo cp -a "$path" "$dir.new/bin/$binary"
+			echo "$path" | sudo tee "$dir.new/bin/$binary.path"
+		done
+
+		kubelet --version | awk '{print $2}' | sudo tee "$dir.new/version"
+
+		# etcd data directory is mounted from the host into the etcd container,
+		# so the snapshot saved there by the etcdctl in the container is
+		# available on the host
+		etcd_id=$(sudo crictl ps --name='^etcd$' -q)
+		sudo crictl exec "$etcd_id" etcdctl \
+			--endpoints=https://127.0.0.1:2379 \
+			--cacert=/etc/kubernetes/pki/etcd/ca.crt \
+			--cert=/etc/kubernetes/pki/etcd/healthcheck-client.crt \
+			--key=/etc/kubernetes/pki/etcd/healthcheck-client.key \
+			snapshot save /var/lib/etcd/kubeone-rollback.db
+		sudo mv /var/lib/etcd/kubeone-rollback.db "$dir.new/etcd-snapshot.db"
+
+		sudo touch "$dir.new/upgrade-in-progress"
+		sudo rm -rf "$dir"
+		sudo mv "$dir.new" "$dir"
+	`)
+
+	rollbackMarkUpgradedScriptTemplate = heredoc.Doc(`
+		sudo rm -f "{{ .DIR }}/upgrade-in-progress"
+	`)
+
+	rollbackStatusScriptTemplate = heredoc.Doc(`
+		dir="{{ .DIR }}"
+		if sudo test -f "$dir/version"; then
+			echo "version=$(sudo cat "$dir/version")"
+			if sudo test -f "$dir/upgrade-in-progress"; then
+				echo "upgrade_in_progress=true"
+			else
+				echo "upgrade_in_progress=false"
+			fi
+		fi
+	`)
+
+	rollbackRestoreScriptTemplate = heredoc.Doc(`
+		dir="{{ .DIR }}"
+		if ! sudo test -f "$dir/version"; then
+			echo "rollback snapshot not found in $dir"
+			exit 1
+		fi
+
+		sudo systemctl stop kubelet
+		for name in kube-apiserver kube-controller-manager kube-scheduler etcd; do
+			ids=$(sudo crictl ps --name="^$name\$" -q)
+			if [ -n "$ids" ]; then
+				sudo crictl stop $ids
+			fi
+		done
+
+		# The configuration written by the failed upgrade is kept for
+		# the troubleshooting
+		sudo rm -rf /etc/kubernetes.failed-upgrade
+		sudo mv /etc/kubernetes /etc/kubernetes.failed-upgrade
+		sudo cp -a "$dir/kubernetes" /etc/kubernetes
+		for file in config.yaml kubeadm-flags.env; do
+			if sudo test -f "$dir/kubelet/$file"; then
+				sudo cp -a "$dir/kubelet/$file" "/var/lib/kubelet/$file"
+			fi
+		done
+
+		for binary in kubeadm kubelet kubectl; do
+			sudo install --owner=0 --group=0 --mode=0755 "$dir/bin/$binary" "$(sudo cat "$dir/bin/$binary.path")"
+		done
+
+		sudo rm -f "$dir/upgrade-in-progress"
+		sudo systemctl daemon-reload
+		sudo systemctl restart kubelet
+	`)
+)
+
+// RollbackSnapshot returns the script saving the state of the control plane
+// node needed to restore it, if its upgrade fails: the etcd snapshot, the
+// /etc/kubernetes directory, the kubelet configuration and the kubeadm,
+// kubelet and kubectl binaries. The node is marked as being upgraded until
+// RollbackMarkUpgraded is run, and the snapshot isn't retaken meanwhile.
+func RollbackSnapshot() (string, error) {
+	return Render(rollbackSnapshotScriptTemplate, Data{
+		"DIR": RollbackSnapshotDir,
+	})
+}
+
+// RollbackMarkUpgraded returns the script marking the node as successfully
+// upgraded, while keeping the snapshot
+func RollbackMarkUpgraded() (string, error) {
+	return Render(rollbackMarkUpgradedScriptTemplate, Data{
+		"DIR": RollbackSnapshotDir,
+	})
+}
+
+// RollbackStatus returns the script printing the Kubernetes version the
+// snapshot was taken on and whether the upgrade is still in progress, as
+// key=value lines. Nothing is printed if there's no snapshot.
+func RollbackStatus() (string, error) {
+	return Render(rollbackStatusScriptTemplate, Data{
+		"DIR": RollbackSnapshotDir,
+	})
+}
+
+// RollbackRestore returns the script restoring the /etc/kubernetes
+// directory, the kubelet configuration and the binaries from the snapshot,
+// and restarting the kubelet. The etcd data isn't restored.
+func RollbackRestore() (string, error) {
+	return Render(rollbackRestoreScriptTemplate, Data{
+		"DIR": RollbackSnapshotDir,
+	})
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scripts
+
+import (
+	"github.com/MakeNowJust/heredoc/v2"
+)
+
+var (
+	// selinuxContainerPaths are written by the containers of the control
+	// plane components and the CNI plugins, so they must be labeled with
+	// container_file_t
+	selinuxContainerPaths = []string{
+		"/etc/kubernetes",
+		"/var/lib/etcd",
+		"/etc/cni/net.d",
+		"/opt/cni/bin",
+		"/var/lib/cni",
+		"/var/lib/calico",
+	}
+
+	selinuxEnforcingCentOSScriptTemplate = heredoc.Doc(`
+		sudo yum install -y container-selinux
+		sudo yum install -y policycoreutils-python-utils || sudo yum install -y policycoreutils-python
+
+		if selinuxenabled; then
+			for path in {{ join " " .PATHS }}; do
+				sudo mkdir -p "$path"
+				sudo semanage fcontext -a -t container_file_t "$path(/.*)?" 2>/dev/null ||
+					sudo semanage fcontext -m -t container_file_t "$path(/.*)?"
+				sudo restorecon -R "$path"
+			done
+		fi
+	`)
+
+	appArmorDebianScriptTemplate = heredoc.Doc(`
+		# The container runtime requires apparmor_parser to load the AppArmor
+		# profiles if AppArmor is enabled in the kernel
+		if [ "$(cat /sys/module/apparmor/parameters/enabled 2>/dev/null)" = "Y" ] &&
+			! command -v apparmor_parser >/dev/null 2>&1; then
+			sudo apt-get update
+			sudo DEBIAN_FRONTEND=noninteractive apt-get install -y apparmor
+		fi
+	`)
+)
+
+// SELinuxEnforcingCentOS returns the script installing container-selinux and
+// labeling the directories written by the containers on CentOS, RHEL and
+// Amazon Linux 2. The directories are labeled only if SELinux is enabled.
+func SELinuxEnforcingCentOS() (string, error) {
+	return Render(selinuxEnforcingCentOSScriptTemplate, Data{
+		"PATHS": selinuxContainerPaths,
+	})
+}
+
+// AppArmorDebian returns the script installing the apparmor package on
+// Debian and Ubuntu if AppArmor is enabled, but apparmor_parser is missing
+func AppArmorDebian() (string, error) {
+	return Render(appArmorDebianScriptTemplate, Data{})
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/pkg/errors"
+)
+
+// readAWSSecretsManager reads the secret from AWS Secrets Manager. Credentials
+// and region are taken from the standard AWS SDK environment and config files.
+// If the key is not empty, the secret is expected to be a JSON object.
+func readAWSSecretsManager(ctx context.Context, secretID, key string) (string, error) {
+	sess, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create aws session")
+	}
+
+	out, err := secretsmanager.New(sess).GetSecretValueWithContext(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretID),
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get secret value")
+	}
+
+	secret := aws.StringValue(out.SecretString)
+	if key == "" {
+		return secret, nil
+	}
+
+	data := map[string]interface{}{}
+	if err = json.Unmarshal([]byte(secret), &data); err != nil {
+		return "", errors.Wrap(err, "failed to parse secret as JSON")
+	}
+
+	val, ok := data[key]
+	if !ok {
+		return "", errors.Errorf("key %q not found in the secret", key)
+	}
+
+	str, ok := val.(string)
+	if !ok {
+		return "", errors.Errorf("key %q is not a string", key)
+	}
+
+	return str, nil
+}
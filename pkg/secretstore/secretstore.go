@@ -0,0 +1,143 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package secretstore resolves references to secrets stored in the external
+// secret stores. References are resolved in memory on the machine running
+// KubeOne and resolved values are never written to the disk.
+//
+// Supported references:
+//   * vault:<path>#<key> - the key of the secret read from HashiCorp Vault,
+//     configured using the VAULT_ADDR, VAULT_TOKEN and VAULT_NAMESPACE
+//     environment variables. Each secret is read only once per run.
+//   * aws-secretsmanager:<secret-id>#<key> - the key of the JSON secret read
+//     from AWS Secrets Manager, or the whole secret string if the key is
+//     omitted. Secret ID can be name or ARN of the secret.
+//
+// Files encrypted using SOPS are decrypted using the sops binary, see ReadFile.
+package secretstore
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	vaultPrefix             = "vault:"
+	awsSecretsManagerPrefix = "aws-secretsmanager:"
+
+	keySeparator = "#"
+)
+
+// resolved holds the secrets resolved from the external secret stores, so
+// they can be redacted from the logs
+var resolved = struct {
+	sync.Mutex
+	secrets map[string]struct{}
+}{
+	secrets: map[string]struct{}{},
+}
+
+// Resolved returns the secrets resolved from the external secret stores
+// during this run
+func Resolved() []string {
+	resolved.Lock()
+	defer resolved.Unlock()
+
+	secrets := make([]string, 0, len(resolved.secrets))
+	for secret := range resolved.secrets {
+		secrets = append(secrets, secret)
+	}
+
+	return secrets
+}
+
+func addResolved(secret string) {
+	if secret == "" {
+		return
+	}
+
+	resolved.Lock()
+	defer resolved.Unlock()
+
+	resolved.secrets[secret] = struct{}{}
+}
+
+// IsReference returns true if the value is a reference to the external secret store
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, vaultPrefix) || strings.HasPrefix(value, awsSecretsManagerPrefix)
+}
+
+// Resolve returns the secret referenced by the value. Values that are not
+// references to the external secret store are returned as-is.
+func Resolve(ctx context.Context, value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, vaultPrefix):
+		path, key := splitReference(strings.TrimPrefix(value, vaultPrefix))
+		if path == "" || key == "" {
+			return "", errors.Errorf("invalid vault reference %q, expected format is vault:<path>#<key>", value)
+		}
+
+		secret, err := readVault(ctx, path, key)
+		addResolved(secret)
+
+		return secret, errors.Wrapf(err, "failed to resolve vault reference %q", value)
+	case strings.HasPrefix(value, awsSecretsManagerPrefix):
+		id, key := splitReference(strings.TrimPrefix(value, awsSecretsManagerPrefix))
+		if id == "" {
+			return "", errors.Errorf("invalid aws-secretsmanager reference %q, expected format is aws-secretsmanager:<secret-id>#<key>", value)
+		}
+
+		secret, err := readAWSSecretsManager(ctx, id, key)
+		addResolved(secret)
+
+		return secret, errors.Wrapf(err, "failed to resolve aws-secretsmanager reference %q", value)
+	}
+
+	return value, nil
+}
+
+// ResolveMap returns a copy of the map with all references to the external
+// secret stores resolved
+func ResolveMap(ctx context.Context, in map[string]string) (map[string]string, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	out := make(map[string]string, len(in))
+	for k, v := range in {
+		resolved, err := Resolve(ctx, v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to resolve %q", k)
+		}
+		out[k] = resolved
+	}
+
+	return out, nil
+}
+
+// splitReference splits reference in format <path>#<key>. The last separator
+// is used because the path (e.g. AWS ARN) can contain any other characters.
+func splitReference(ref string) (string, string) {
+	idx := strings.LastIndex(ref, keySeparator)
+	if idx < 0 {
+		return ref, ""
+	}
+
+	return ref[:idx], ref[idx+1:]
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "test-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v1/kv1/addons":
+			_, _ = w.Write([]byte(`{"data": {"apiKey": "kv1-secret"}}`))
+		case "/v1/secret/data/addons":
+			_, _ = w.Write([]byte(`{"data": {"data": {"apiKey": "kv2-secret"}, "metadata": {"version": 1}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	t.Setenv(vaultAddrEnv, srv.URL)
+	t.Setenv(vaultTokenEnv, "test-token")
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "plain value",
+			value: "plain",
+			want:  "plain",
+		},
+		{
+			name:  "vault kv version 1",
+			value: "vault:kv1/addons#apiKey",
+			want:  "kv1-secret",
+		},
+		{
+			name:  "vault kv version 2",
+			value: "vault:secret/data/addons#apiKey",
+			want:  "kv2-secret",
+		},
+		{
+			name:    "vault missing key",
+			value:   "vault:secret/data/addons#missing",
+			wantErr: true,
+		},
+		{
+			name:    "vault missing secret",
+			value:   "vault:secret/data/missing#apiKey",
+			wantErr: true,
+		},
+		{
+			name:    "vault reference without key",
+			value:   "vault:secret/data/addons",
+			wantErr: true,
+		},
+		{
+			name:    "aws-secretsmanager reference without secret id",
+			value:   "aws-secretsmanager:#apiKey",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resolve(context.Background(), tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Resolve() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadVaultCached(t *testing.T) {
+	reads := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/aws/creds/kubeone" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		// Dynamic secrets engines generate new credentials on each read
+		reads++
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"lease_id": "aws/creds/kubeone/%d", "data": {"access_key": "key-%d", "secret_key": "secret-%d"}}`, reads, reads, reads)))
+	}))
+	defer srv.Close()
+
+	t.Setenv(vaultAddrEnv, srv.URL)
+	t.Setenv(vaultTokenEnv, "test-token")
+
+	for i := 0; i < 2; i++ {
+		data, err := ReadVault(context.Background(), "aws/creds/kubeone")
+		if err != nil {
+			t.Fatalf("ReadVault() error = %v", err)
+		}
+		if data["access_key"] != "key-1" || data["secret_key"] != "secret-1" {
+			t.Errorf("ReadVault() = %v, want credentials from the first read", data)
+		}
+	}
+
+	secret, err := Resolve(context.Background(), "vault:aws/creds/kubeone#secret_key")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if secret != "secret-1" {
+		t.Errorf("Resolve() = %q, want %q", secret, "secret-1")
+	}
+
+	if reads != 1 {
+		t.Errorf("secret read %d times, want 1", reads)
+	}
+}
+
+func TestSplitReference(t *testing.T) {
+	tests := []struct {
+		name     string
+		ref      string
+		wantPath string
+		wantKey  string
+	}{
+		{
+			name:     "path and key",
+			ref:      "secret/data/addons#apiKey",
+			wantPath: "secret/data/addons",
+			wantKey:  "apiKey",
+		},
+		{
+			name:     "arn and key",
+			ref:      "arn:aws:secretsmanager:eu-west-1:123456789012:secret:addons-AbCdEf#apiKey",
+			wantPath: "arn:aws:secretsmanager:eu-west-1:123456789012:secret:addons-AbCdEf",
+			wantKey:  "apiKey",
+		},
+		{
+			name:     "no key",
+			ref:      "addons",
+			wantPath: "addons",
+			wantKey:  "",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			path, key := splitReference(tt.ref)
+			if path != tt.wantPath || key != tt.wantKey {
+				t.Errorf("splitReference() = (%q, %q), want (%q, %q)", path, key, tt.wantPath, tt.wantKey)
+			}
+		})
+	}
+}
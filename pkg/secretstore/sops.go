@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const sopsBinary = "sops"
+
+// ReadFile returns the content of the file, decrypted using the sops binary
+// if the file is encrypted using SOPS. The sops binary takes care of the
+// keys (age, PGP, AWS/GCP KMS, Azure Key Vault) in the same way as when it's
+// used directly. Plaintext files are returned as-is.
+func ReadFile(path string) ([]byte, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !IsSOPSEncrypted(buf) {
+		return buf, nil
+	}
+
+	return decryptSOPS(path)
+}
+
+// IsSOPSEncrypted returns true if the YAML or JSON document contains SOPS
+// metadata
+func IsSOPSEncrypted(buf []byte) bool {
+	doc := map[string]interface{}{}
+	if err := yaml.Unmarshal(buf, &doc); err != nil {
+		return false
+	}
+
+	metadata, ok := doc["sops"].(map[interface{}]interface{})
+	if !ok {
+		return false
+	}
+
+	_, ok = metadata["mac"]
+
+	return ok
+}
+
+func decryptSOPS(path string) ([]byte, error) {
+	// sops detects the format based on the file extension, which doesn't have
+	// to be set for KubeOne manifests and credentials files
+	inputType := "yaml"
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		inputType = "json"
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(sopsBinary, "--decrypt", "--input-type", inputType, "--output-type", "yaml", path)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrapf(err, "failed to decrypt %q using sops: %s", path, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const sopsEncryptedCredentials = `AWS_ACCESS_KEY_ID: ENC[AES256_GCM,data:Zm9v,iv:YmFy,tag:YmF6,type:str]
+sops:
+    age:
+        - recipient: age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p
+    lastmodified: "2021-12-01T10:00:00Z"
+    mac: ENC[AES256_GCM,data:bWFj,iv:aXY=,tag:dGFn,type:str]
+    version: 3.7.1
+`
+
+func TestIsSOPSEncrypted(t *testing.T) {
+	tests := []struct {
+		name string
+		buf  string
+		want bool
+	}{
+		{
+			name: "plaintext yaml",
+			buf:  "AWS_ACCESS_KEY_ID: foo\n",
+			want: false,
+		},
+		{
+			name: "sops encrypted yaml",
+			buf:  sopsEncryptedCredentials,
+			want: true,
+		},
+		{
+			name: "sops encrypted json",
+			buf:  `{"AWS_ACCESS_KEY_ID": "ENC[AES256_GCM,data:Zm9v,type:str]", "sops": {"mac": "ENC[AES256_GCM,data:bWFj,type:str]"}}`,
+			want: true,
+		},
+		{
+			name: "sops key without metadata",
+			buf:  "sops: enabled\n",
+			want: false,
+		},
+		{
+			name: "not yaml",
+			buf:  "\t: :",
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsSOPSEncrypted([]byte(tt.buf)); got != tt.want {
+				t.Errorf("IsSOPSEncrypted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	dir := t.TempDir()
+
+	// fake sops binary printing the decrypted document
+	fakeSOPS := "#!/bin/sh\necho 'AWS_ACCESS_KEY_ID: decrypted'\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, sopsBinary), []byte(fakeSOPS), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "plaintext",
+			content: "AWS_ACCESS_KEY_ID: plain\n",
+			want:    "AWS_ACCESS_KEY_ID: plain\n",
+		},
+		{
+			name:    "sops encrypted",
+			content: sopsEncryptedCredentials,
+			want:    "AWS_ACCESS_KEY_ID: decrypted\n",
+		},
+	}
+
+	for i, tt := range tests {
+		tt := tt
+		path := filepath.Join(dir, fmt.Sprintf("credentials-%d.yaml", i))
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ioutil.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ReadFile(path)
+			if err != nil {
+				t.Fatalf("ReadFile() error = %v", err)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("ReadFile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	vaultAddrEnv      = "VAULT_ADDR"
+	vaultTokenEnv     = "VAULT_TOKEN"
+	vaultNamespaceEnv = "VAULT_NAMESPACE"
+
+	vaultRequestTimeout = 30 * time.Second
+)
+
+type vaultResponse struct {
+	Data map[string]interface{} `json:"data"`
+}
+
+// vaultCache holds the secrets read from Vault, keyed by the Vault address,
+// namespace and path of the secret
+var vaultCache = struct {
+	sync.Mutex
+	secrets map[string]map[string]interface{}
+}{
+	secrets: map[string]map[string]interface{}{},
+}
+
+// readVault reads the key of the secret from the Vault KV secrets engine.
+// Both KV version 1 and version 2 are supported.
+func readVault(ctx context.Context, path, key string) (string, error) {
+	data, err := ReadVault(ctx, path)
+	if err != nil {
+		return "", err
+	}
+
+	val, ok := data[key]
+	if !ok {
+		return "", errors.Errorf("key %q not found in the secret", key)
+	}
+
+	str, ok := val.(string)
+	if !ok {
+		return "", errors.Errorf("key %q is not a string", key)
+	}
+
+	return str, nil
+}
+
+// ReadVault returns the data of the secret read from Vault. Both KV secrets
+// engines and dynamic secrets engines (e.g. aws/creds/<role>) are supported.
+// Each secret is read only once and then cached in memory, so credentials
+// generated by the dynamic secrets engines are the same for the whole run.
+func ReadVault(ctx context.Context, path string) (map[string]interface{}, error) {
+	addr := os.Getenv(vaultAddrEnv)
+	if addr == "" {
+		return nil, errors.Errorf("%s environment variable is not set", vaultAddrEnv)
+	}
+
+	token := os.Getenv(vaultTokenEnv)
+	if token == "" {
+		return nil, errors.Errorf("%s environment variable is not set", vaultTokenEnv)
+	}
+
+	ns := os.Getenv(vaultNamespaceEnv)
+	path = strings.TrimPrefix(path, "/")
+
+	vaultCache.Lock()
+	defer vaultCache.Unlock()
+
+	cacheKey := strings.Join([]string{addr, ns, path}, "#")
+	if data, ok := vaultCache.secrets[cacheKey]; ok {
+		return data, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, vaultRequestTimeout)
+	defer cancel()
+
+	url := fmt.Sprintf("%s/v1/%s", strings.TrimSuffix(addr, "/"), path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create vault request")
+	}
+
+	req.Header.Set("X-Vault-Token", token)
+	if ns != "" {
+		req.Header.Set("X-Vault-Namespace", ns)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read secret from vault")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to read secret from vault, unexpected status code %d", resp.StatusCode)
+	}
+
+	var vr vaultResponse
+	if err = json.NewDecoder(resp.Body).Decode(&vr); err != nil {
+		return nil, errors.Wrap(err, "failed to decode vault response")
+	}
+
+	data := vr.Data
+	// KV version 2 wraps the secret data along with the metadata
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+
+	vaultCache.secrets[cacheKey] = data
+
+	return data, nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8c.io/kubeone/pkg/client"
+)
+
+// OperationState is the state of the operation
+type OperationState string
+
+const (
+	// OperationRunning means the operation is still running
+	OperationRunning OperationState = "running"
+	// OperationSucceeded means the operation finished successfully
+	OperationSucceeded OperationState = "succeeded"
+	// OperationFailed means the operation failed
+	OperationFailed OperationState = "failed"
+)
+
+// Operation is an apply or upgrade running on the cluster in the background
+type Operation struct {
+	ID      string              `json:"id"`
+	Cluster string              `json:"cluster"`
+	Type    string              `json:"type"`
+	State   OperationState      `json:"state"`
+	Error   string              `json:"error,omitempty"`
+	Result  *client.ApplyResult `json:"result,omitempty"`
+	Start   time.Time           `json:"start"`
+	End     *time.Time          `json:"end,omitempty"`
+
+	logs   *logBuffer
+	cancel context.CancelFunc
+}
+
+// logBuffer keeps the logs of the operation, so they can be streamed to any
+// number of readers, while the operation is still writing them
+type logBuffer struct {
+	lock sync.Mutex
+	buf  []byte
+	done bool
+	// changed is closed and replaced each time the logs are written or
+	// closed, waking up the readers
+	changed chan struct{}
+}
+
+func newLogBuffer() *logBuffer {
+	return &logBuffer{changed: make(chan struct{})}
+}
+
+// Write appends p to the logs
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.buf = append(b.buf, p...)
+	b.notify()
+
+	return len(p), nil
+}
+
+// close marks the logs as complete
+func (b *logBuffer) close() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.done = true
+	b.notify()
+}
+
+func (b *logBuffer) notify() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+// read returns the logs written after the offset, whether the logs are
+// complete, and the channel closed once more logs are written
+func (b *logBuffer) read(offset int) ([]byte, bool, <-chan struct{}) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if offset > len(b.buf) {
+		offset = len(b.buf)
+	}
+
+	return append([]byte{}, b.buf[offset:]...), b.done, b.changed
+}
@@ -0,0 +1,454 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package server implements the API for running KubeOne operations remotely.
+// The server holds the manifests and the SSH keys of the clusters, so the
+// clients, such as CI runners, need only the API token.
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"k8c.io/kubeone/pkg/client"
+	"k8c.io/kubeone/pkg/clusterstatus"
+	"k8c.io/kubeone/pkg/logging"
+)
+
+const (
+	operationApply   = "apply"
+	operationUpgrade = "upgrade"
+
+	// maxFinishedOperations is the number of finished operations kept, so
+	// their results and logs can still be fetched
+	maxFinishedOperations = 100
+)
+
+// Cluster runs the operations on a single cluster, implemented by the
+// client.Client
+type Cluster interface {
+	Apply(ctx context.Context, opts client.ApplyOptions) (*client.ApplyResult, error)
+	Upgrade(ctx context.Context, opts client.UpgradeOptions) error
+	Status(ctx context.Context) (*clusterstatus.Report, error)
+}
+
+// Loader returns the cluster with the given name, logging to the logger. The
+// cluster is loaded for each operation, so the changes to the manifest are
+// picked up without restarting the server.
+type Loader func(ctx context.Context, name string, logger logrus.FieldLogger) (Cluster, error)
+
+// ApplyRequest is the body of the apply request
+type ApplyRequest struct {
+	NoInit                    bool `json:"noInit,omitempty"`
+	ForceInstall              bool `json:"forceInstall,omitempty"`
+	ForceUpgrade              bool `json:"forceUpgrade,omitempty"`
+	UpgradeMachineDeployments bool `json:"upgradeMachineDeployments,omitempty"`
+}
+
+// UpgradeRequest is the body of the upgrade request
+type UpgradeRequest struct {
+	Force                     bool `json:"force,omitempty"`
+	UpgradeMachineDeployments bool `json:"upgradeMachineDeployments,omitempty"`
+}
+
+// Options configures the Server
+type Options struct {
+	// Clusters are the names of the served clusters
+	Clusters []string
+	// Load returns the cluster for running an operation
+	Load Loader
+	// Token authenticates the requests, given as the bearer token
+	Token string
+	// Logger logs the requests and the operations. The logs of each
+	// operation are kept separately, and streamed by the logs endpoint.
+	Logger logrus.FieldLogger
+	// Logging configures the format and the levels of the operation logs
+	Logging logging.Options
+}
+
+// Server is the http.Handler serving the API:
+//
+//	GET  /v1/clusters                       names of the served clusters
+//	GET  /v1/clusters/<name>/status         status report of the cluster
+//	POST /v1/clusters/<name>/apply          start 'kubeone apply', returns the operation
+//	POST /v1/clusters/<name>/upgrade        start 'kubeone upgrade', returns the operation
+//	GET  /v1/operations                     all operations
+//	GET  /v1/operations/<id>                the operation
+//	GET  /v1/operations/<id>/logs           logs of the operation, streamed until it finishes
+//	DELETE /v1/operations/<id>              cancel the operation
+//
+// Only a single operation can run on a cluster at the time.
+type Server struct {
+	opts     Options
+	clusters map[string]bool
+	ctx      context.Context
+	cancel   context.CancelFunc
+
+	lock       sync.Mutex
+	operations []*Operation
+	running    map[string]*Operation
+	wg         sync.WaitGroup
+}
+
+// New returns the Server
+func New(opts Options) (*Server, error) {
+	if opts.Token == "" {
+		return nil, errors.New("token is required")
+	}
+	if opts.Load == nil {
+		return nil, errors.New("cluster loader is required")
+	}
+	if opts.Logger == nil {
+		opts.Logger = logrus.StandardLogger()
+	}
+	if _, err := logging.New(ioutil.Discard, opts.Logging); err != nil {
+		return nil, errors.Wrap(err, "invalid logging options")
+	}
+
+	clusters := map[string]bool{}
+	for _, name := range opts.Clusters {
+		clusters[name] = true
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Server{
+		opts:     opts,
+		clusters: clusters,
+		ctx:      ctx,
+		cancel:   cancel,
+		running:  map[string]*Operation{},
+	}, nil
+}
+
+// Close cancels the running operations and waits for them to finish
+func (s *Server) Close() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// ServeHTTP authenticates and serves the request
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authenticated(r) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(path) < 2 || path[0] != "v1" {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+
+	switch {
+	case path[1] == "clusters" && len(path) == 2 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, s.opts.Clusters)
+	case path[1] == "clusters" && len(path) == 4:
+		s.serveCluster(w, r, path[2], path[3])
+	case path[1] == "operations" && len(path) == 2 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, s.listOperations())
+	case path[1] == "operations" && len(path) == 3:
+		s.serveOperation(w, r, path[2])
+	case path[1] == "operations" && len(path) == 4 && path[3] == "logs" && r.Method == http.MethodGet:
+		s.streamLogs(w, r, path[2])
+	default:
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+	}
+}
+
+func (s *Server) authenticated(r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.Token)) == 1
+}
+
+func (s *Server) serveCluster(w http.ResponseWriter, r *http.Request, name, action string) {
+	if !s.clusters[name] {
+		writeError(w, http.StatusNotFound, errors.Errorf("cluster %q not found", name))
+		return
+	}
+
+	switch {
+	case action == "status" && r.Method == http.MethodGet:
+		s.status(w, r, name)
+	case action == operationApply && r.Method == http.MethodPost:
+		req := ApplyRequest{}
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+
+		s.startOperation(w, name, operationApply, func(ctx context.Context, cluster Cluster) (*client.ApplyResult, error) {
+			return cluster.Apply(ctx, client.ApplyOptions{
+				NoInit:                    req.NoInit,
+				ForceInstall:              req.ForceInstall,
+				ForceUpgrade:              req.ForceUpgrade,
+				UpgradeMachineDeployments: req.UpgradeMachineDeployments,
+			})
+		})
+	case action == operationUpgrade && r.Method == http.MethodPost:
+		req := UpgradeRequest{}
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+
+		s.startOperation(w, name, operationUpgrade, func(ctx context.Context, cluster Cluster) (*client.ApplyResult, error) {
+			return nil, cluster.Upgrade(ctx, client.UpgradeOptions{
+				Force:                     req.Force,
+				UpgradeMachineDeployments: req.UpgradeMachineDeployments,
+			})
+		})
+	default:
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+	}
+}
+
+func (s *Server) status(w http.ResponseWriter, r *http.Request, name string) {
+	logger := s.opts.Logger.WithField(logging.FieldCluster, name)
+
+	cluster, err := s.opts.Load(r.Context(), name, logger)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, errors.Wrap(err, "failed to load the cluster"))
+		return
+	}
+
+	report, err := cluster.Status(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+// startOperation runs the operation in the background and responds with it
+func (s *Server) startOperation(w http.ResponseWriter, name, typ string, run func(context.Context, Cluster) (*client.ApplyResult, error)) {
+	// Options are validated by New
+	logs := newLogBuffer()
+	logger, _ := logging.New(logs, s.opts.Logging)
+
+	s.lock.Lock()
+	if op, ok := s.running[name]; ok {
+		s.lock.Unlock()
+		writeError(w, http.StatusConflict, errors.Errorf("operation %s is already running on cluster %q", op.ID, name))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	op := &Operation{
+		ID:      newOperationID(),
+		Cluster: name,
+		Type:    typ,
+		State:   OperationRunning,
+		Start:   time.Now(),
+		logs:    logs,
+		cancel:  cancel,
+	}
+	s.running[name] = op
+	s.addOperation(op)
+	resp := *op
+	s.lock.Unlock()
+
+	serverLogger := s.opts.Logger.WithField(logging.FieldCluster, name).WithField("operation", op.ID)
+	serverLogger.Infof("Starting %s", typ)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer cancel()
+
+		var result *client.ApplyResult
+		cluster, err := s.opts.Load(ctx, name, logger)
+		if err == nil {
+			result, err = run(ctx, cluster)
+		} else {
+			err = errors.Wrap(err, "failed to load the cluster")
+		}
+
+		if err != nil {
+			logger.Errorln(err)
+			serverLogger.Errorf("Failed to %s: %v", typ, err)
+		} else {
+			serverLogger.Infof("Finished %s", typ)
+		}
+		s.finishOperation(op, result, err)
+	}()
+
+	writeJSON(w, http.StatusAccepted, resp)
+}
+
+// addOperation records the operation, dropping the oldest finished
+// operations over the limit. The lock must be held.
+func (s *Server) addOperation(op *Operation) {
+	s.operations = append(s.operations, op)
+
+	finished := len(s.operations) - len(s.running)
+	kept := s.operations[:0]
+	for _, o := range s.operations {
+		if finished > maxFinishedOperations && o.State != OperationRunning {
+			finished--
+			continue
+		}
+		kept = append(kept, o)
+	}
+	s.operations = kept
+}
+
+func (s *Server) finishOperation(op *Operation, result *client.ApplyResult, err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	end := time.Now()
+	op.End = &end
+	op.Result = result
+	op.State = OperationSucceeded
+	if err != nil {
+		op.State = OperationFailed
+		op.Error = err.Error()
+	}
+	delete(s.running, op.Cluster)
+	op.logs.close()
+}
+
+func (s *Server) getOperation(id string) (Operation, *logBuffer, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, op := range s.operations {
+		if op.ID == id {
+			return *op, op.logs, true
+		}
+	}
+
+	return Operation{}, nil, false
+}
+
+func (s *Server) listOperations() []Operation {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	ops := []Operation{}
+	for _, op := range s.operations {
+		ops = append(ops, *op)
+	}
+	sort.SliceStable(ops, func(i, j int) bool {
+		return ops[i].Start.Before(ops[j].Start)
+	})
+
+	return ops
+}
+
+func (s *Server) serveOperation(w http.ResponseWriter, r *http.Request, id string) {
+	op, _, ok := s.getOperation(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.Errorf("operation %q not found", id))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, op)
+	case http.MethodDelete:
+		if op.cancel != nil {
+			op.cancel()
+		}
+		writeJSON(w, http.StatusAccepted, op)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+	}
+}
+
+// streamLogs writes the logs of the operation as they're written, until the
+// operation finishes or the client disconnects
+func (s *Server) streamLogs(w http.ResponseWriter, r *http.Request, id string) {
+	_, logs, ok := s.getOperation(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.Errorf("operation %q not found", id))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+
+	offset := 0
+	for {
+		buf, done, changed := logs.read(offset)
+		if len(buf) > 0 {
+			if _, err := w.Write(buf); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+			offset += len(buf)
+		}
+
+		if done {
+			return
+		}
+
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// decodeRequest decodes the optional JSON body of the request, responding
+// with an error if it's invalid
+func decodeRequest(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, errors.Wrap(err, "invalid request"))
+		return false
+	}
+
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, code int, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(obj)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
+
+func newOperationID() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,285 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"k8c.io/kubeone/pkg/client"
+	"k8c.io/kubeone/pkg/clusterstatus"
+)
+
+const testToken = "secret"
+
+type fakeCluster struct {
+	logger  logrus.FieldLogger
+	release chan struct{}
+	err     error
+}
+
+func (c *fakeCluster) Apply(ctx context.Context, opts client.ApplyOptions) (*client.ApplyResult, error) {
+	c.logger.Infof("applying, force upgrade: %t", opts.ForceUpgrade)
+
+	select {
+	case <-c.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	return &client.ApplyResult{Action: client.ActionReconcile}, nil
+}
+
+func (c *fakeCluster) Upgrade(ctx context.Context, opts client.UpgradeOptions) error {
+	_, err := c.Apply(ctx, client.ApplyOptions{})
+	return err
+}
+
+func (c *fakeCluster) Status(ctx context.Context) (*clusterstatus.Report, error) {
+	return &clusterstatus.Report{}, nil
+}
+
+func newTestServer(t *testing.T, release chan struct{}, err error) *Server {
+	t.Helper()
+
+	logger := logrus.New()
+	logger.Out = ioutil.Discard
+
+	srv, serr := New(Options{
+		Clusters: []string{"edge-1"},
+		Token:    testToken,
+		Logger:   logger,
+		Load: func(_ context.Context, name string, logger logrus.FieldLogger) (Cluster, error) {
+			return &fakeCluster{logger: logger, release: release, err: err}, nil
+		},
+	})
+	if serr != nil {
+		t.Fatalf("failed to create server: %v", serr)
+	}
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func doRequest(srv *Server, method, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(Options{Load: func(context.Context, string, logrus.FieldLogger) (Cluster, error) { return nil, nil }}); err == nil {
+		t.Error("expected error without the token")
+	}
+	if _, err := New(Options{Token: testToken}); err == nil {
+		t.Error("expected error without the loader")
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	srv := newTestServer(t, nil, nil)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		body   string
+		code   int
+	}{
+		{
+			name:   "missing token",
+			method: http.MethodGet,
+			path:   "/v1/clusters",
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:   "wrong token",
+			method: http.MethodGet,
+			path:   "/v1/clusters",
+			token:  "wrong",
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:   "list clusters",
+			method: http.MethodGet,
+			path:   "/v1/clusters",
+			token:  testToken,
+			code:   http.StatusOK,
+		},
+		{
+			name:   "status",
+			method: http.MethodGet,
+			path:   "/v1/clusters/edge-1/status",
+			token:  testToken,
+			code:   http.StatusOK,
+		},
+		{
+			name:   "unknown cluster",
+			method: http.MethodPost,
+			path:   "/v1/clusters/edge-2/apply",
+			token:  testToken,
+			code:   http.StatusNotFound,
+		},
+		{
+			name:   "unknown field",
+			method: http.MethodPost,
+			path:   "/v1/clusters/edge-1/apply",
+			token:  testToken,
+			body:   `{"autoApprove": true}`,
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "unknown operation",
+			method: http.MethodGet,
+			path:   "/v1/operations/123",
+			token:  testToken,
+			code:   http.StatusNotFound,
+		},
+		{
+			name:   "unknown path",
+			method: http.MethodGet,
+			path:   "/v2/clusters",
+			token:  testToken,
+			code:   http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			rec := doRequest(srv, tc.method, tc.path, tc.token, tc.body)
+			if rec.Code != tc.code {
+				t.Errorf("expected code %d, got %d: %s", tc.code, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestOperation(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		state OperationState
+	}{
+		{
+			name:  "succeeded",
+			state: OperationSucceeded,
+		},
+		{
+			name:  "failed",
+			err:   errors.New("ssh: handshake failed"),
+			state: OperationFailed,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			release := make(chan struct{})
+			srv := newTestServer(t, release, tc.err)
+
+			rec := doRequest(srv, http.MethodPost, "/v1/clusters/edge-1/apply", testToken, `{"forceUpgrade": true}`)
+			if rec.Code != http.StatusAccepted {
+				t.Fatalf("expected code %d, got %d: %s", http.StatusAccepted, rec.Code, rec.Body.String())
+			}
+
+			op := Operation{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &op); err != nil {
+				t.Fatalf("failed to decode operation: %v", err)
+			}
+			if op.State != OperationRunning {
+				t.Errorf("expected operation to be running, got %q", op.State)
+			}
+
+			if rec = doRequest(srv, http.MethodPost, "/v1/clusters/edge-1/upgrade", testToken, ""); rec.Code != http.StatusConflict {
+				t.Errorf("expected concurrent operation to conflict, got %d", rec.Code)
+			}
+
+			// Logs are streamed until the operation finishes
+			logs := make(chan string)
+			go func() {
+				rec := doRequest(srv, http.MethodGet, "/v1/operations/"+op.ID+"/logs", testToken, "")
+				logs <- rec.Body.String()
+			}()
+			close(release)
+
+			select {
+			case out := <-logs:
+				if !strings.Contains(out, "applying, force upgrade: true") {
+					t.Errorf("expected logs of the operation, got %q", out)
+				}
+			case <-time.After(10 * time.Second):
+				t.Fatal("timed out waiting for the logs")
+			}
+
+			rec = doRequest(srv, http.MethodGet, "/v1/operations/"+op.ID, testToken, "")
+			if err := json.Unmarshal(rec.Body.Bytes(), &op); err != nil {
+				t.Fatalf("failed to decode operation: %v", err)
+			}
+			if op.State != tc.state {
+				t.Errorf("expected operation state %q, got %q", tc.state, op.State)
+			}
+			if op.End == nil {
+				t.Error("expected operation end time to be set")
+			}
+		})
+	}
+}
+
+func TestCancelOperation(t *testing.T) {
+	srv := newTestServer(t, make(chan struct{}), nil)
+
+	rec := doRequest(srv, http.MethodPost, "/v1/clusters/edge-1/upgrade", testToken, "")
+	op := Operation{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &op); err != nil {
+		t.Fatalf("failed to decode operation: %v", err)
+	}
+
+	if rec = doRequest(srv, http.MethodDelete, "/v1/operations/"+op.ID, testToken, ""); rec.Code != http.StatusAccepted {
+		t.Fatalf("expected code %d, got %d", http.StatusAccepted, rec.Code)
+	}
+
+	// Logs are complete once the canceled operation finishes
+	doRequest(srv, http.MethodGet, "/v1/operations/"+op.ID+"/logs", testToken, "")
+
+	rec = doRequest(srv, http.MethodGet, "/v1/operations/"+op.ID, testToken, "")
+	if err := json.Unmarshal(rec.Body.Bytes(), &op); err != nil {
+		t.Fatalf("failed to decode operation: %v", err)
+	}
+	if op.State != OperationFailed || !strings.Contains(op.Error, "canceled") {
+		t.Errorf("expected operation to fail with canceled context, got %q: %s", op.State, op.Error)
+	}
+}
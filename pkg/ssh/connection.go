@@ -30,9 +30,14 @@ import (
 	"github.com/pkg/errors"
 	"golang.org/x/crypto/ssh"
 	"golang.org/x/crypto/ssh/agent"
+
+	"k8c.io/kubeone/pkg/secretstore"
 )
 
-const socketEnvPrefix = "env:"
+const (
+	socketEnvPrefix = "env:"
+	certFileSuffix  = "-cert.pub"
+)
 
 var (
 	_ Tunneler = &connection{}
@@ -56,18 +61,37 @@ type Tunneler interface {
 // Opts represents all the possible options for connecting to
 // a remote server via SSH.
 type Opts struct {
-	Context     context.Context
-	Username    string
-	Password    string
-	Hostname    string
-	Port        int
-	PrivateKey  string
-	KeyFile     string
+	Context    context.Context
+	Username   string
+	Password   string
+	Hostname   string
+	Port       int
+	PrivateKey string
+	KeyFile    string
+	// Passphrase of the encrypted private key, either the passphrase itself,
+	// reference to the environment variable (env:<name>) or to the secret in
+	// the external secret store. If empty, the passphrase is prompted using
+	// the Connector's prompt function.
+	Passphrase string
+	// CertFile is path to the OpenSSH certificate for the private key
+	CertFile    string
+	Certificate string
 	AgentSocket string
 	Timeout     time.Duration
 	Bastion     string
 	BastionPort int
 	BastionUser string
+	// JumpHosts is the chain of the bastion (or jump) hosts traversed in
+	// order to reach the host. Authentication options not set for a jump
+	// host are taken from the host. Takes precedence over the Bastion.
+	JumpHosts []Opts
+	// ReadOnly refuses to run commands which are not known to only read
+	// the host, see IsReadOnlyCommand
+	ReadOnly bool
+
+	// keySource is the key file (or reference) the private key is read from,
+	// used when prompting for the passphrase
+	keySource string
 }
 
 func validateOptions(o Opts) (Opts, error) {
@@ -83,26 +107,107 @@ func validateOptions(o Opts) (Opts, error) {
 		return o, errors.New("must specify at least one of password, private key, keyfile or agent socket")
 	}
 
+	if len(o.JumpHosts) == 0 && o.Bastion != "" {
+		o.JumpHosts = []Opts{{
+			Hostname: o.Bastion,
+			Port:     o.BastionPort,
+			Username: o.BastionUser,
+		}}
+	}
+
+	jumpHosts := make([]Opts, 0, len(o.JumpHosts))
+	for _, jh := range o.JumpHosts {
+		jh.Context = o.Context
+		if jh.Username == "" {
+			jh.Username = o.Username
+		}
+		if jh.Timeout == 0 {
+			jh.Timeout = o.Timeout
+		}
+		if len(jh.Password) == 0 && len(jh.PrivateKey) == 0 && len(jh.KeyFile) == 0 && len(jh.AgentSocket) == 0 {
+			jh.Password = o.Password
+			jh.PrivateKey = o.PrivateKey
+			jh.KeyFile = o.KeyFile
+			jh.Passphrase = o.Passphrase
+			jh.CertFile = o.CertFile
+			jh.Certificate = o.Certificate
+			jh.AgentSocket = o.AgentSocket
+		}
+
+		jh, err := validateOptions(jh)
+		if err != nil {
+			return o, errors.Wrapf(err, "invalid jump host %q", jh.Hostname)
+		}
+
+		jumpHosts = append(jumpHosts, jh)
+	}
+	o.JumpHosts = jumpHosts
+
+	ctx := o.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if len(o.KeyFile) > 0 {
+		o.keySource = o.KeyFile
+	}
+
+	if secretstore.IsReference(o.KeyFile) {
+		key, err := secretstore.Resolve(ctx, o.KeyFile)
+		if err != nil {
+			return o, errors.Wrap(err, "failed to read private key")
+		}
+
+		o.PrivateKey = key
+		o.KeyFile = ""
+	}
+
 	if len(o.KeyFile) > 0 {
 		content, err := ioutil.ReadFile(o.KeyFile)
 		if err != nil {
 			return o, errors.Wrapf(err, "failed to read keyfile %q", o.KeyFile)
 		}
 
+		// Same as OpenSSH, the certificate is picked up from the file next
+		// to the private key
+		if o.CertFile == "" {
+			if _, err = os.Stat(o.KeyFile + certFileSuffix); err == nil {
+				o.CertFile = o.KeyFile + certFileSuffix
+			}
+		}
+
 		o.PrivateKey = string(content)
 		o.KeyFile = ""
 	}
 
-	if o.Port <= 0 {
-		o.Port = 22
+	if len(o.CertFile) > 0 {
+		content, err := ioutil.ReadFile(o.CertFile)
+		if err != nil {
+			return o, errors.Wrapf(err, "failed to read certificate file %q", o.CertFile)
+		}
+
+		o.Certificate = string(content)
+		o.CertFile = ""
 	}
 
-	if o.BastionPort <= 0 {
-		o.BastionPort = 22
+	switch {
+	case strings.HasPrefix(o.Passphrase, socketEnvPrefix):
+		envName := strings.TrimPrefix(o.Passphrase, socketEnvPrefix)
+		o.Passphrase = os.Getenv(envName)
+		if o.Passphrase == "" {
+			return o, errors.Errorf("private key passphrase environment variable %q is not set", envName)
+		}
+	case secretstore.IsReference(o.Passphrase):
+		passphrase, err := secretstore.Resolve(ctx, o.Passphrase)
+		if err != nil {
+			return o, errors.Wrap(err, "failed to read private key passphrase")
+		}
+
+		o.Passphrase = passphrase
 	}
 
-	if o.BastionUser == "" {
-		o.BastionUser = o.Username
+	if o.Port <= 0 {
+		o.Port = 22
 	}
 
 	if o.Timeout == 0 {
@@ -118,6 +223,7 @@ type connection struct {
 	connector *Connector
 	ctx       context.Context
 	cancel    context.CancelFunc
+	readOnly  bool
 }
 
 // NewConnection attempts to create a new SSH connection to the host
@@ -128,6 +234,55 @@ func NewConnection(connector *Connector, o Opts) (Connection, error) {
 		return nil, errors.Wrap(err, "failed to validate ssh connection options")
 	}
 
+	var client *ssh.Client
+
+	// Connect to the first jump host (or directly to the host) and then
+	// dial each next hop from the previous one
+	for _, hop := range append(o.JumpHosts, o) {
+		sshConfig, configErr := clientConfig(connector, hop)
+		if configErr != nil {
+			return nil, configErr
+		}
+
+		// do not use fmt.Sprintf() to allow proper IPv6 handling if hostname is an IP address
+		endpoint := net.JoinHostPort(hop.Hostname, strconv.Itoa(hop.Port))
+
+		if client == nil {
+			client, err = ssh.Dial("tcp", endpoint, sshConfig)
+			if err != nil {
+				return nil, errors.Wrapf(err, "could not establish connection to %s", endpoint)
+			}
+
+			continue
+		}
+
+		conn, dialErr := client.Dial("tcp", endpoint)
+		if dialErr != nil {
+			return nil, errors.Wrapf(dialErr, "could not establish connection to %s", endpoint)
+		}
+
+		ncc, chans, reqs, connErr := ssh.NewClientConn(conn, endpoint, sshConfig)
+		if connErr != nil {
+			return nil, errors.Wrapf(connErr, "could not establish connection to %s", endpoint)
+		}
+
+		client = ssh.NewClient(ncc, chans, reqs)
+	}
+
+	ctx, cancelFn := context.WithCancel(connector.ctx)
+
+	return &connection{
+		sshclient: client,
+		connector: connector,
+		ctx:       ctx,
+		cancel:    cancelFn,
+		readOnly:  o.ReadOnly,
+	}, nil
+}
+
+// clientConfig returns the SSH client configuration with the authentication
+// methods configured in the options
+func clientConfig(connector *Connector, o Opts) (*ssh.ClientConfig, error) {
 	authMethods := make([]ssh.AuthMethod, 0)
 
 	if len(o.Password) > 0 {
@@ -135,12 +290,12 @@ func NewConnection(connector *Connector, o Opts) (Connection, error) {
 	}
 
 	if len(o.PrivateKey) > 0 {
-		signer, parseErr := ssh.ParsePrivateKey([]byte(o.PrivateKey))
-		if parseErr != nil {
-			return nil, errors.Wrap(parseErr, "the given SSH key could not be parsed (note that password-protected keys are not supported)")
+		signers, signersErr := privateKeySigners(connector, o)
+		if signersErr != nil {
+			return nil, signersErr
 		}
 
-		authMethods = append(authMethods, ssh.PublicKeys(signer))
+		authMethods = append(authMethods, ssh.PublicKeys(signers...))
 	}
 
 	if len(o.AgentSocket) > 0 {
@@ -170,60 +325,56 @@ func NewConnection(connector *Connector, o Opts) (Connection, error) {
 		authMethods = append(authMethods, ssh.PublicKeys(signers...))
 	}
 
-	sshConfig := &ssh.ClientConfig{
+	return &ssh.ClientConfig{
 		User:            o.Username,
 		Timeout:         o.Timeout,
 		Auth:            authMethods,
 		HostKeyCallback: ssh.InsecureIgnoreHostKey(), //nolint:gosec
-	}
+	}, nil
+}
 
-	targetHost := o.Hostname
-	targetPort := strconv.Itoa(o.Port)
+// privateKeySigners returns the signer for the private key, decrypting the
+// key if needed. If the certificate is given, the certificate signer is
+// returned first, followed by the plain private key signer.
+func privateKeySigners(connector *Connector, o Opts) ([]ssh.Signer, error) {
+	signer, err := ssh.ParsePrivateKey([]byte(o.PrivateKey))
+
+	var missingErr *ssh.PassphraseMissingError
+	if errors.As(err, &missingErr) {
+		passphrase := o.Passphrase
+		if passphrase == "" {
+			passphrase, err = connector.passphrase(o.keySource)
+			if err != nil {
+				return nil, err
+			}
+		}
 
-	if o.Bastion != "" {
-		targetHost = o.Bastion
-		targetPort = strconv.Itoa(o.BastionPort)
-		sshConfig.User = o.BastionUser
+		signer, err = ssh.ParsePrivateKeyWithPassphrase([]byte(o.PrivateKey), []byte(passphrase))
 	}
-
-	// do not use fmt.Sprintf() to allow proper IPv6 handling if hostname is an IP address
-	endpoint := net.JoinHostPort(targetHost, targetPort)
-
-	client, err := ssh.Dial("tcp", endpoint, sshConfig)
 	if err != nil {
-		return nil, errors.Wrapf(err, "could not establish connection to %s", endpoint)
+		return nil, errors.Wrap(err, "the given SSH key could not be parsed")
 	}
 
-	ctx, cancelFn := context.WithCancel(connector.ctx)
-	sshConn := &connection{
-		connector: connector,
-		ctx:       ctx,
-		cancel:    cancelFn,
+	if len(o.Certificate) == 0 {
+		return []ssh.Signer{signer}, nil
 	}
 
-	if o.Bastion == "" {
-		sshConn.sshclient = client
-		// connection established
-		return sshConn, nil
+	pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(o.Certificate))
+	if err != nil {
+		return nil, errors.Wrap(err, "the given SSH certificate could not be parsed")
 	}
 
-	// continue to setup if we are running over bastion
-	endpointBehindBastion := net.JoinHostPort(o.Hostname, strconv.Itoa(o.Port))
-
-	// Dial a connection to the service host, from the bastion
-	conn, err := client.Dial("tcp", endpointBehindBastion)
-	if err != nil {
-		return nil, errors.Wrapf(err, "could not establish connection to %s", endpointBehindBastion)
+	cert, ok := pub.(*ssh.Certificate)
+	if !ok {
+		return nil, errors.New("the given SSH certificate file contains a public key, not a certificate")
 	}
 
-	sshConfig.User = o.Username
-	ncc, chans, reqs, err := ssh.NewClientConn(conn, endpointBehindBastion, sshConfig)
+	certSigner, err := ssh.NewCertSigner(cert, signer)
 	if err != nil {
-		return nil, errors.Wrapf(err, "could not establish connection to %s", endpointBehindBastion)
+		return nil, errors.Wrap(err, "the given SSH certificate doesn't match the private key")
 	}
 
-	sshConn.sshclient = ssh.NewClient(ncc, chans, reqs)
-	return sshConn, nil
+	return []ssh.Signer{certSigner, signer}, nil
 }
 
 func (c *connection) TunnelTo(_ context.Context, network, addr string) (net.Conn, error) {
@@ -256,6 +407,10 @@ func (c *connection) Close() error {
 }
 
 func (c *connection) POpen(cmd string, stdin io.Reader, stdout io.Writer, stderr io.Writer) (int, error) {
+	if c.readOnly && (stdin != nil || !IsReadOnlyCommand(cmd)) {
+		return -1, errors.Wrapf(ErrReadOnly, "refused to run %q", cmd)
+	}
+
 	sess, err := c.session()
 	if err != nil {
 		return 0, errors.Wrap(err, "failed to get SSH session")
@@ -0,0 +1,203 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssh
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestPrivateKeySigners(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plainKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	//nolint:staticcheck
+	encryptedBlock, err := x509.EncryptPEMBlock(rand.Reader, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key), []byte("secret"), x509.PEMCipherAES256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encryptedKey := pem.EncodeToMemory(encryptedBlock)
+
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caSigner, err := ssh.NewSignerFromKey(caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert := &ssh.Certificate{
+		Key:             signer.PublicKey(),
+		CertType:        ssh.UserCert,
+		ValidPrincipals: []string{"root"},
+		ValidBefore:     ssh.CertTimeInfinity,
+	}
+	if err = cert.SignCert(rand.Reader, caSigner); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		opts        Opts
+		prompt      func(string) (string, error)
+		wantSigners int
+		wantCert    bool
+		wantErr     bool
+	}{
+		{
+			name:        "plain key",
+			opts:        Opts{PrivateKey: string(plainKey)},
+			wantSigners: 1,
+		},
+		{
+			name:        "encrypted key with passphrase",
+			opts:        Opts{PrivateKey: string(encryptedKey), Passphrase: "secret"},
+			wantSigners: 1,
+		},
+		{
+			name: "encrypted key with prompted passphrase",
+			opts: Opts{PrivateKey: string(encryptedKey), keySource: "id_rsa"},
+			prompt: func(string) (string, error) {
+				return "secret", nil
+			},
+			wantSigners: 1,
+		},
+		{
+			name:    "encrypted key without passphrase",
+			opts:    Opts{PrivateKey: string(encryptedKey), keySource: "id_rsa"},
+			wantErr: true,
+		},
+		{
+			name:    "encrypted key with wrong passphrase",
+			opts:    Opts{PrivateKey: string(encryptedKey), Passphrase: "wrong"},
+			wantErr: true,
+		},
+		{
+			name:        "key with certificate",
+			opts:        Opts{PrivateKey: string(plainKey), Certificate: string(ssh.MarshalAuthorizedKey(cert))},
+			wantSigners: 2,
+			wantCert:    true,
+		},
+		{
+			name:    "public key instead of certificate",
+			opts:    Opts{PrivateKey: string(plainKey), Certificate: string(ssh.MarshalAuthorizedKey(signer.PublicKey()))},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			connector := NewConnector(context.Background())
+			if tt.prompt != nil {
+				connector.SetPassphrasePrompt(tt.prompt)
+			}
+
+			signers, err := privateKeySigners(connector, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("privateKeySigners() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(signers) != tt.wantSigners {
+				t.Fatalf("privateKeySigners() returned %d signers, want %d", len(signers), tt.wantSigners)
+			}
+			if tt.wantSigners == 0 {
+				return
+			}
+
+			_, isCert := signers[0].PublicKey().(*ssh.Certificate)
+			if isCert != tt.wantCert {
+				t.Errorf("first signer is certificate = %v, want %v", isCert, tt.wantCert)
+			}
+		})
+	}
+}
+
+func TestValidateOptionsJumpHosts(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Opts
+		want []Opts
+	}{
+		{
+			name: "no jump hosts",
+			opts: Opts{Username: "root", Hostname: "10.0.0.2", PrivateKey: "key"},
+			want: []Opts{},
+		},
+		{
+			name: "bastion",
+			opts: Opts{Username: "root", Hostname: "10.0.0.2", PrivateKey: "key", Bastion: "1.2.3.4", BastionUser: "jump"},
+			want: []Opts{
+				{Username: "jump", Hostname: "1.2.3.4", Port: 22, PrivateKey: "key"},
+			},
+		},
+		{
+			name: "jump hosts chain",
+			opts: Opts{
+				Username:   "root",
+				Hostname:   "10.0.0.2",
+				PrivateKey: "key",
+				Bastion:    "ignored",
+				JumpHosts: []Opts{
+					{Hostname: "1.2.3.4"},
+					{Hostname: "10.0.0.1", Port: 2222, Username: "jump", PrivateKey: "jump-key"},
+				},
+			},
+			want: []Opts{
+				{Username: "root", Hostname: "1.2.3.4", Port: 22, PrivateKey: "key"},
+				{Username: "jump", Hostname: "10.0.0.1", Port: 2222, PrivateKey: "jump-key"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := validateOptions(tt.opts)
+			if err != nil {
+				t.Fatalf("validateOptions() error = %v", err)
+			}
+
+			if len(got.JumpHosts) != len(tt.want) {
+				t.Fatalf("validateOptions() returned %d jump hosts, want %d", len(got.JumpHosts), len(tt.want))
+			}
+
+			for i, jh := range got.JumpHosts {
+				w := tt.want[i]
+				if jh.Username != w.Username || jh.Hostname != w.Hostname || jh.Port != w.Port || jh.PrivateKey != w.PrivateKey {
+					t.Errorf("jump host %d = %s@%s:%d (key %q), want %s@%s:%d (key %q)",
+						i, jh.Username, jh.Hostname, jh.Port, jh.PrivateKey, w.Username, w.Hostname, w.Port, w.PrivateKey)
+				}
+			}
+		})
+	}
+}
@@ -31,12 +31,19 @@ type Connector struct {
 	lock        sync.Mutex
 	connections map[int]Connection
 	ctx         context.Context
+	wrap        func(kubeoneapi.HostConfig, Connection) Connection
+	readOnly    bool
+
+	promptLock  sync.Mutex
+	prompt      func(keySource string) (string, error)
+	passphrases map[string]string
 }
 
 // NewConnector constructor
 func NewConnector(ctx context.Context) *Connector {
 	return &Connector{
 		connections: make(map[int]Connection),
+		passphrases: make(map[string]string),
 		ctx:         ctx,
 	}
 }
@@ -56,6 +63,59 @@ func (c *Connector) Tunnel(host kubeoneapi.HostConfig) (Tunneler, error) {
 	return tunn, err
 }
 
+// WrapConnections sets the function wrapping all connections returned by
+// Connect and Tunnel, e.g. to record the commands run over them. The wrapped
+// connection must implement Tunneler.
+func (c *Connector) WrapConnections(wrap func(kubeoneapi.HostConfig, Connection) Connection) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.wrap = wrap
+}
+
+// SetReadOnly sets whether the new connections refuse to run commands which
+// can change the hosts. It must be set before connecting to the hosts.
+func (c *Connector) SetReadOnly(readOnly bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.readOnly = readOnly
+}
+
+// SetPassphrasePrompt sets the function used to ask for the passphrase of the
+// encrypted private keys, if the passphrase is not configured. Each private
+// key is asked for only once.
+func (c *Connector) SetPassphrasePrompt(prompt func(keySource string) (string, error)) {
+	c.promptLock.Lock()
+	defer c.promptLock.Unlock()
+
+	c.prompt = prompt
+}
+
+// passphrase returns the passphrase of the encrypted private key read from
+// the key source
+func (c *Connector) passphrase(keySource string) (string, error) {
+	c.promptLock.Lock()
+	defer c.promptLock.Unlock()
+
+	if passphrase, ok := c.passphrases[keySource]; ok {
+		return passphrase, nil
+	}
+
+	if c.prompt == nil {
+		return "", errors.Errorf("the SSH key %q is encrypted, but the passphrase is not given", keySource)
+	}
+
+	passphrase, err := c.prompt(keySource)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read the passphrase for the SSH key %q", keySource)
+	}
+
+	c.passphrases[keySource] = passphrase
+
+	return passphrase, nil
+}
+
 // Connect to the node
 func (c *Connector) Connect(host kubeoneapi.HostConfig) (Connection, error) {
 	var err error
@@ -67,6 +127,7 @@ func (c *Connector) Connect(host kubeoneapi.HostConfig) (Connection, error) {
 	if !found {
 		opts := sshOpts(host)
 		opts.Context = c.ctx
+		opts.ReadOnly = c.readOnly
 		conn, err = NewConnection(c, opts)
 		if err != nil {
 			return nil, err
@@ -75,6 +136,10 @@ func (c *Connector) Connect(host kubeoneapi.HostConfig) (Connection, error) {
 		c.connections[host.ID] = conn
 	}
 
+	if c.wrap != nil {
+		return c.wrap(host, conn), nil
+	}
+
 	return conn, nil
 }
 
@@ -90,15 +155,30 @@ func (c *Connector) forgetConnection(conn *connection) {
 }
 
 func sshOpts(host kubeoneapi.HostConfig) Opts {
+	jumpHosts := []Opts{}
+	for _, bh := range host.BastionHosts {
+		jumpHosts = append(jumpHosts, Opts{
+			Username:    bh.User,
+			Hostname:    bh.Host,
+			Port:        bh.Port,
+			KeyFile:     bh.SSHPrivateKeyFile,
+			AgentSocket: bh.SSHAgentSocket,
+			Timeout:     10 * time.Second,
+		})
+	}
+
 	return Opts{
 		Username:    host.SSHUsername,
 		Port:        host.SSHPort,
 		Hostname:    host.PublicAddress,
 		KeyFile:     host.SSHPrivateKeyFile,
+		Passphrase:  host.SSHPrivateKeyPassphrase,
+		CertFile:    host.SSHCertFile,
 		AgentSocket: host.SSHAgentSocket,
 		Timeout:     10 * time.Second,
 		Bastion:     host.Bastion,
 		BastionPort: host.BastionPort,
 		BastionUser: host.BastionUser,
+		JumpHosts:   jumpHosts,
 	}
 }
@@ -0,0 +1,98 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssh
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrReadOnly is returned when the command which can change the host is run
+// over the read-only connection
+var ErrReadOnly = errors.New("command is not allowed in read-only mode")
+
+const (
+	// quotedArg is a double-quoted argument without expansions, as
+	// produced by the %q verb
+	quotedArg = `"[^"$` + "`" + `\\]*"`
+	// pathArg is an unquoted path or a systemd unit name
+	pathArg = `[\w@./:-]+`
+)
+
+// readOnlyLines match the lines of the commands used by the probes and for
+// reading files, which are known not to change the host. Whole lines must
+// match, so the commands can't be chained with the other commands.
+var readOnlyLines = []*regexp.Regexp{
+	// Prologue of all scripts, see scripts.Render
+	regexp.MustCompile(`^set -xeu pipefail$`),
+	regexp.MustCompile(`^export "PATH=\$PATH:/sbin:/usr/local/bin:/opt/bin"$`),
+
+	// Probes
+	regexp.MustCompile(`^systemctl show ` + pathArg + ` -p [\w,]+$`),
+	regexp.MustCompile(`^test -f ` + pathArg + `$`),
+	regexp.MustCompile(`^` + pathArg + ` --version( +\| +awk( -F [-,])? '\{print \$\d\}')+$`),
+	regexp.MustCompile(`^cat /proc/sys/kernel/random/boot_id$`),
+
+	// Hostname, see scripts.Hostname
+	regexp.MustCompile(`^hostname$`),
+	regexp.MustCompile(`^fqdn=\$\(hostname -f\)$`),
+	regexp.MustCompile(`^\[ "\$fqdn" = localhost \] && fqdn=\$\(hostname\)$`),
+	regexp.MustCompile(`^echo "\$fqdn"$`),
+
+	// Instance ID, see scripts.InstanceID
+	regexp.MustCompile(`^token=\$\(curl -fsS -X PUT -H "X-aws-ec2-metadata-token-ttl-seconds: 60" http://169\.254\.169\.254/latest/api/token\)$`),
+	regexp.MustCompile(`^curl -fsS -H "X-aws-ec2-metadata-token: \$token" http://169\.254\.169\.254/latest/meta-data/instance-id$`),
+
+	// Reading files, see sshiofs
+	regexp.MustCompile(`^sudo cat ` + quotedArg + `$`),
+	regexp.MustCompile(`^sudo stat --printf='%s %f %Y' ` + quotedArg + `$`),
+	regexp.MustCompile(`^sudo dd status=none iflag=count_bytes,skip_bytes skip=\d+ count=\d+ if=` + quotedArg + `$`),
+}
+
+// IsReadOnlyCommand returns whether the command is known not to change the
+// host. Each line of the command must be empty, a comment, or a known
+// read-only command.
+func IsReadOnlyCommand(cmd string) bool {
+	if strings.TrimSpace(cmd) == "" {
+		return false
+	}
+
+	for _, line := range strings.Split(cmd, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if !isReadOnlyLine(line) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isReadOnlyLine(line string) bool {
+	for _, re := range readOnlyLines {
+		if re.MatchString(line) {
+			return true
+		}
+	}
+
+	return false
+}
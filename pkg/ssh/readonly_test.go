@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssh
+
+import (
+	"testing"
+)
+
+func TestIsReadOnlyCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cmd  string
+		want bool
+	}{
+		{
+			name: "systemd unit status",
+			cmd:  "systemctl show kubelet -p LoadState,ActiveState,SubState",
+			want: true,
+		},
+		{
+			name: "component version",
+			cmd:  "/usr/bin/containerd --version | awk '{print $3}' | awk -F - '{print $1}'  | awk -F , '{print $1}'",
+			want: true,
+		},
+		{
+			name: "hostname script",
+			cmd:  "set -xeu pipefail\nexport \"PATH=$PATH:/sbin:/usr/local/bin:/opt/bin\"\n\nfqdn=$(hostname -f)\n[ \"$fqdn\" = localhost ] && fqdn=$(hostname)\necho \"$fqdn\"\n",
+			want: true,
+		},
+		{
+			name: "aws instance ID script",
+			cmd:  "token=$(curl -fsS -X PUT -H \"X-aws-ec2-metadata-token-ttl-seconds: 60\" http://169.254.169.254/latest/api/token)\ncurl -fsS -H \"X-aws-ec2-metadata-token: $token\" http://169.254.169.254/latest/meta-data/instance-id\n",
+			want: true,
+		},
+		{
+			name: "metadata request piped to shell",
+			cmd:  "curl -fsS http://169.254.169.254/latest/meta-data/instance-id | sh",
+			want: false,
+		},
+		{
+			name: "read file",
+			cmd:  `sudo dd status=none iflag=count_bytes,skip_bytes skip=0 count=512 if="/etc/kubernetes/admin.conf"`,
+			want: true,
+		},
+		{
+			name: "stat file",
+			cmd:  `sudo stat --printf='%s %f %Y' "/etc/os-release"`,
+			want: true,
+		},
+		{
+			name: "empty",
+			cmd:  "",
+			want: false,
+		},
+		{
+			name: "write file",
+			cmd:  `sudo dd status=none oflag=seek_bytes conv=notrunc seek=0 of="/etc/hosts"`,
+			want: false,
+		},
+		{
+			name: "restart service",
+			cmd:  "sudo systemctl restart kubelet",
+			want: false,
+		},
+		{
+			name: "chained command",
+			cmd:  "test -f /etc/kubernetes/kubelet.conf; sudo rm -rf /etc/kubernetes",
+			want: false,
+		},
+		{
+			name: "command on the next line",
+			cmd:  "hostname\nsudo reboot",
+			want: false,
+		},
+		{
+			name: "command substitution in the path",
+			cmd:  `sudo cat "$(sudo rm -rf /etc/kubernetes)"`,
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := IsReadOnlyCommand(tt.cmd); got != tt.want {
+				t.Errorf("IsReadOnlyCommand() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -66,7 +66,12 @@ type Host struct {
 	EarliestCertExpiry time.Time
 
 	IsInCluster bool
-	Kubeconfig  []byte
+	// Replaced is set if the Node object of the host exists, but the host
+	// is not initialized, i.e. the machine was recreated with an empty disk.
+	// Such host is not considered to be in the cluster, and it's rejoined
+	// after its stale Node object and etcd member are removed.
+	Replaced   bool
+	Kubeconfig []byte
 }
 
 type ComponentStatus struct {
@@ -94,20 +99,16 @@ const (
 	Cluster level checks
 */
 
-const (
-	x90Days = time.Hour * 24 * 90
-)
-
-// CertsToExpireInLessThen90Days will return true if any of the control plane certificates are to be expired soon (90
-// days).
-func (c *Cluster) CertsToExpireInLessThen90Days() bool {
+// CertsToExpireInLessThan will return true if any of the control plane certificates are to be expired in less than
+// the given duration.
+func (c *Cluster) CertsToExpireInLessThan(renewBefore time.Duration) bool {
 	var (
 		now       = time.Now()
 		needRenew bool
 	)
 
 	for _, host := range c.ControlPlane {
-		if !host.EarliestCertExpiry.IsZero() && host.EarliestCertExpiry.Sub(now) <= x90Days {
+		if !host.EarliestCertExpiry.IsZero() && host.EarliestCertExpiry.Sub(now) <= renewBefore {
 			needRenew = true
 		}
 	}
@@ -160,6 +161,19 @@ func (c *Cluster) BrokenHosts() []string {
 	return brokenNodes
 }
 
+// ReplacedHosts returns the control plane and static worker hosts whose
+// machines were recreated
+func (c *Cluster) ReplacedHosts() []Host {
+	replaced := []Host{}
+	for _, host := range append(append([]Host{}, c.ControlPlane...), c.StaticWorkers...) {
+		if host.Replaced {
+			replaced = append(replaced, host)
+		}
+	}
+
+	return replaced
+}
+
 func (c *Cluster) SafeToDeleteHosts() []string {
 	safeToDelete := []string{}
 	deleteCandidate := []string{}
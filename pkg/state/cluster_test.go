@@ -21,26 +21,42 @@ import (
 	"time"
 )
 
-func TestCluster_CertsToExpireInLessThen90Days(t *testing.T) {
+func TestCluster_CertsToExpireInLessThan(t *testing.T) {
 	tests := []struct {
-		name  string
-		hosts []Host
-		want  bool
+		name        string
+		hosts       []Host
+		renewBefore time.Duration
+		want        bool
 	}{
 		{
-			name:  "expired now",
-			hosts: []Host{{EarliestCertExpiry: time.Now()}},
-			want:  true,
+			name:        "expired now",
+			hosts:       []Host{{EarliestCertExpiry: time.Now()}},
+			renewBefore: time.Hour * 24 * 90,
+			want:        true,
 		},
 		{
-			name:  "expire soon",
-			hosts: []Host{{EarliestCertExpiry: time.Now().Add(time.Hour * 24)}},
-			want:  true,
+			name:        "expire soon",
+			hosts:       []Host{{EarliestCertExpiry: time.Now().Add(time.Hour * 24)}},
+			renewBefore: time.Hour * 24 * 90,
+			want:        true,
 		},
 		{
-			name:  "expire after 91 days",
-			hosts: []Host{{EarliestCertExpiry: time.Now().Add(time.Hour * 24 * 91)}},
-			want:  false,
+			name:        "expire after 91 days",
+			hosts:       []Host{{EarliestCertExpiry: time.Now().Add(time.Hour * 24 * 91)}},
+			renewBefore: time.Hour * 24 * 90,
+			want:        false,
+		},
+		{
+			name:        "expire after 91 days with longer threshold",
+			hosts:       []Host{{EarliestCertExpiry: time.Now().Add(time.Hour * 24 * 91)}},
+			renewBefore: time.Hour * 24 * 120,
+			want:        true,
+		},
+		{
+			name:        "expire soon with shorter threshold",
+			hosts:       []Host{{EarliestCertExpiry: time.Now().Add(time.Hour * 24 * 10)}},
+			renewBefore: time.Hour * 24 * 7,
+			want:        false,
 		},
 	}
 
@@ -51,8 +67,8 @@ func TestCluster_CertsToExpireInLessThen90Days(t *testing.T) {
 				ControlPlane: tt.hosts,
 			}
 
-			if got := c.CertsToExpireInLessThen90Days(); got != tt.want {
-				t.Errorf("Cluster.CertsToExpireInLessThen90Days() = %v, want %v", got, tt.want)
+			if got := c.CertsToExpireInLessThan(tt.renewBefore); got != tt.want {
+				t.Errorf("Cluster.CertsToExpireInLessThan() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -20,15 +20,22 @@ import (
 	"context"
 	"path"
 	"strings"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
 	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/audit"
 	"k8c.io/kubeone/pkg/configupload"
+	"k8c.io/kubeone/pkg/encryption"
+	"k8c.io/kubeone/pkg/progress"
 	"k8c.io/kubeone/pkg/runner"
 	"k8c.io/kubeone/pkg/ssh"
+	"k8c.io/kubeone/pkg/statestore"
 	"k8c.io/kubeone/pkg/templates/images"
 
+	corev1 "k8s.io/api/core/v1"
 	apiserverconfigv1 "k8s.io/apiserver/pkg/apis/config/v1"
 	"k8s.io/client-go/rest"
 	bootstraputil "k8s.io/cluster-bootstrap/token/util"
@@ -62,6 +69,13 @@ func New(ctx context.Context) (*State, error) {
 
 			return s.Cluster.RegistryConfiguration.OverwriteRegistry
 		}),
+		images.WithOverwriteImageGetter(func(res images.Resource) (string, string) {
+			if s.Cluster == nil || s.Cluster.MachineController == nil || res != images.MachineController {
+				return "", ""
+			}
+
+			return s.Cluster.MachineController.ImageRepository, s.Cluster.MachineController.ImageTag
+		}),
 		images.WithKubernetesVersionGetter(func() string {
 			if s.Cluster == nil {
 				return "0.0.0"
@@ -93,7 +107,11 @@ type State struct {
 	Verbose                   bool
 	BackupFile                string
 	DestroyWorkers            bool
+	KeepMachineDeployments    bool
+	MachineDeploymentsFile    string
+	DrainNodes                bool
 	RemoveBinaries            bool
+	PreservePVCVolumes        bool
 	ForceUpgrade              bool
 	ForceInstall              bool
 	UpgradeMachineDeployments bool
@@ -102,6 +120,62 @@ type State struct {
 	CredentialsFilePath       string
 	ManifestFilePath          string
 	PauseImage                string
+	// CanaryUpgrade upgrades the leader and a single static worker node
+	// first, and continues only if CanaryCheck succeeds
+	CanaryUpgrade bool
+	CanaryCheck   func(s *State, nodes []kubeoneapi.HostConfig) error
+	// JoinOnlyHosts are the hostnames or addresses of the new nodes to
+	// provision and join, without reconciling the rest of the cluster
+	JoinOnlyHosts []string
+	// UpgradeVerifyTimeout is how long the upgraded node is allowed to
+	// become healthy, before the upgrade is stopped
+	UpgradeVerifyTimeout time.Duration
+	// UpgradeProbes are pods run on each upgraded node, which must succeed
+	// before the upgrade continues
+	UpgradeProbes []corev1.Pod
+	// DiagnoseHost returns the diagnosis of the host which can't be connected
+	// to over SSH. It's nil unless diagnosing hosts is enabled.
+	DiagnoseHost func(ctx context.Context, host kubeoneapi.HostConfig) string
+	// RetryPending is set while the running task is retried if it fails. The
+	// unreachable hosts are diagnosed only when it's not set.
+	RetryPending bool
+	// Progress records the progress and durations of the tasks. It's nil
+	// unless the progress reporting is enabled.
+	Progress *progress.Recorder
+	// Audit records all commands run over SSH and all changes made using
+	// the Kubernetes API. It's nil unless the audit log is enabled.
+	Audit *audit.Log
+	// StateStore persists the generated artifacts, such as the kubeconfig
+	// and the PKI backups. It's nil unless the state store is configured, in
+	// which case the artifacts are only saved locally.
+	StateStore statestore.Store
+	// EncryptionKey encrypts the sensitive artifacts, such as the kubeconfig
+	// and the PKI backups, before they're saved locally and to the state
+	// store. It's nil unless the encryption key is configured.
+	EncryptionKey encryption.Key
+}
+
+// EncryptArtifact returns the artifact encrypted by the encryption key, or
+// the artifact as-is if the encryption key is not configured
+func (s *State) EncryptArtifact(data []byte) ([]byte, error) {
+	if s.EncryptionKey == nil {
+		return data, nil
+	}
+
+	buf, err := s.EncryptionKey.Encrypt(s.Context, data)
+
+	return buf, errors.Wrapf(err, "failed to encrypt the artifact using the %s", s.EncryptionKey)
+}
+
+// PersistArtifact saves the artifact to the state store, if it's configured
+func (s *State) PersistArtifact(name string, data []byte) error {
+	if s.StateStore == nil {
+		return nil
+	}
+
+	s.Logger.Infof("Saving %q to the state store %s...", name, s.StateStore)
+
+	return errors.Wrapf(s.StateStore.Put(s.Context, name, data), "failed to save %q to the state store", name)
 }
 
 func (s *State) KubeadmVerboseFlag() string {
@@ -23,6 +23,7 @@ import (
 	"github.com/pkg/errors"
 
 	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/logging"
 	"k8c.io/kubeone/pkg/runner"
 	"k8c.io/kubeone/pkg/ssh"
 )
@@ -40,7 +41,14 @@ func (s *State) runTask(node *kubeoneapi.HostConfig, task NodeTask) error {
 	// because we want to re-use it for future tasks)
 	conn, err = s.Connector.Connect(*node)
 	if err != nil {
-		return errors.Wrapf(err, "failed to connect to %s", node.PublicAddress)
+		err = errors.Wrapf(err, "failed to connect to %s", node.PublicAddress)
+		// Diagnose the host only once the task won't be retried anymore
+		if s.DiagnoseHost != nil && !s.RetryPending {
+			s.Logger.Infof("Diagnosing host %s...", node.PublicAddress)
+			return errors.WithMessage(err, s.DiagnoseHost(s.Context, *node))
+		}
+
+		return err
 	}
 
 	s.Runner = &runner.Runner{
@@ -60,22 +68,30 @@ func (s *State) RunTaskOnNodes(nodes []kubeoneapi.HostConfig, task NodeTask, par
 	wg := sync.WaitGroup{}
 	hasErrors := false
 
+	taskFields := logging.FuncFields(task)
+	taskName, _ := taskFields[logging.FieldTask].(string)
+	subsystem, _ := taskFields[logging.FieldSubsystem].(string)
+
 	for i := range nodes {
 		ctx := s.Clone()
-		ctx.Logger = ctx.Logger.WithField("node", nodes[i].PublicAddress)
+		ctx.Logger = ctx.Logger.WithFields(taskFields).WithField(logging.FieldNode, nodes[i].PublicAddress)
 
 		if parallel == RunParallel {
 			wg.Add(1)
 			go func(ctx *State, node *kubeoneapi.HostConfig) {
-				err = ctx.runTask(node, task)
-				if err != nil {
-					ctx.Logger.Error(err)
+				span := ctx.Progress.StartNode(taskName, subsystem, node.PublicAddress)
+				nodeErr := ctx.runTask(node, task)
+				span.Finish(nodeErr)
+				if nodeErr != nil {
+					ctx.Logger.Error(nodeErr)
 					hasErrors = true
 				}
 				wg.Done()
 			}(ctx, &nodes[i])
 		} else {
+			span := ctx.Progress.StartNode(taskName, subsystem, nodes[i].PublicAddress)
 			err = ctx.runTask(&nodes[i], task)
+			span.Finish(err)
 			if err != nil {
 				break
 			}
@@ -0,0 +1,84 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package state
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/ssh"
+)
+
+func TestRunTaskDiagnoseHost(t *testing.T) {
+	tests := []struct {
+		name         string
+		retryPending bool
+		wantDiagnose bool
+	}{
+		{
+			name:         "task retried",
+			retryPending: true,
+			wantDiagnose: false,
+		},
+		{
+			name:         "final attempt",
+			retryPending: false,
+			wantDiagnose: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			diagnosed := 0
+			s := &State{
+				Context:      context.Background(),
+				Connector:    ssh.NewConnector(context.Background()),
+				Logger:       logrus.New(),
+				RetryPending: tt.retryPending,
+				DiagnoseHost: func(context.Context, kubeoneapi.HostConfig) string {
+					diagnosed++
+					return "instance is stopped"
+				},
+			}
+
+			// The host without the address can't be connected to
+			host := &kubeoneapi.HostConfig{ID: 1, SSHUsername: "root"}
+			err := s.runTask(host, func(*State, *kubeoneapi.HostConfig, ssh.Connection) error {
+				t.Fatal("task run on the unreachable host")
+				return nil
+			})
+			if err == nil {
+				t.Fatal("expected the connection error")
+			}
+
+			if tt.wantDiagnose != (diagnosed == 1) {
+				t.Errorf("expected diagnosis %v, host diagnosed %d times", tt.wantDiagnose, diagnosed)
+			}
+			if tt.wantDiagnose != strings.Contains(err.Error(), "instance is stopped") {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !strings.Contains(errors.Cause(err).Error(), "no hostname specified") {
+				t.Errorf("expected the connection error as the cause, got %v", errors.Cause(err))
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statestore
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/filelock"
+)
+
+// localStore keeps the artifacts in the local directory
+type localStore struct {
+	dir string
+}
+
+func newLocalStore(dir string) (*localStore, error) {
+	if dir == "" {
+		return nil, errors.New("directory of the local state store is required")
+	}
+
+	return &localStore{dir: dir}, nil
+}
+
+func (l *localStore) Put(_ context.Context, name string, data []byte) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(l.dir, 0700); err != nil {
+		return errors.Wrap(err, "failed to create the state store directory")
+	}
+
+	return filelock.WriteFile(filepath.Join(l.dir, name), data, 0600)
+}
+
+func (l *localStore) Get(_ context.Context, name string) ([]byte, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+
+	buf, err := ioutil.ReadFile(filepath.Join(l.dir, name))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+
+	return buf, errors.Wrapf(err, "failed to read %q", name)
+}
+
+func (l *localStore) Delete(_ context.Context, name string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+
+	err := os.Remove(filepath.Join(l.dir, name))
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return errors.Wrapf(err, "failed to delete %q", name)
+}
+
+func (l *localStore) List(_ context.Context) ([]string, error) {
+	files, err := ioutil.ReadDir(l.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the state store directory")
+	}
+
+	names := []string{}
+	for _, f := range files {
+		if f.Mode().IsRegular() {
+			names = append(names, f.Name())
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func (l *localStore) String() string {
+	return l.dir
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statestore
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/pkg/errors"
+)
+
+// s3Store keeps the artifacts in the S3 bucket, under the prefix
+type s3Store struct {
+	bucket string
+	prefix string
+	client *s3.S3
+}
+
+func newS3Store(u *url.URL) (*s3Store, error) {
+	if u.Host == "" {
+		return nil, errors.New("bucket of the s3 state store is required, expected format is s3://<bucket>/<prefix>")
+	}
+
+	query := u.Query()
+	awsConfig := aws.Config{}
+	if region := query.Get("region"); region != "" {
+		awsConfig.Region = aws.String(region)
+	}
+	if endpoint := query.Get("endpoint"); endpoint != "" {
+		awsConfig.Endpoint = aws.String(endpoint)
+		awsConfig.S3ForcePathStyle = aws.Bool(query.Get("force_path_style") == "true")
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            awsConfig,
+		Profile:           query.Get("profile"),
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create aws session")
+	}
+
+	return &s3Store{
+		bucket: u.Host,
+		prefix: strings.Trim(u.Path, "/"),
+		client: s3.New(sess),
+	}, nil
+}
+
+func (s *s3Store) key(name string) string {
+	return path.Join(s.prefix, name)
+}
+
+func (s *s3Store) Put(ctx context.Context, name string, data []byte) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+
+	_, err := s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:               aws.String(s.bucket),
+		Key:                  aws.String(s.key(name)),
+		Body:                 bytes.NewReader(data),
+		ServerSideEncryption: aws.String(s3.ServerSideEncryptionAes256),
+	})
+
+	return errors.Wrapf(err, "failed to put s3://%s/%s", s.bucket, s.key(name))
+}
+
+func (s *s3Store) Get(ctx context.Context, name string) ([]byte, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+
+	out, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(name)),
+	})
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get s3://%s/%s", s.bucket, s.key(name))
+	}
+	defer out.Body.Close()
+
+	return ioutil.ReadAll(out.Body)
+}
+
+func (s *s3Store) Delete(ctx context.Context, name string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+
+	_, err := s.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(name)),
+	})
+
+	return errors.Wrapf(err, "failed to delete s3://%s/%s", s.bucket, s.key(name))
+}
+
+func (s *s3Store) List(ctx context.Context) ([]string, error) {
+	prefix := ""
+	if s.prefix != "" {
+		prefix = s.prefix + "/"
+	}
+
+	names := []string{}
+	err := s.client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket:    aws.String(s.bucket),
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String("/"),
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, obj := range page.Contents {
+			names = append(names, strings.TrimPrefix(aws.StringValue(obj.Key), prefix))
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list s3://%s/%s", s.bucket, prefix)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func (s *s3Store) String() string {
+	return "s3://" + path.Join(s.bucket, s.prefix)
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statestore
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// secretStore keeps the artifacts in the Secret, each under its own key
+type secretStore struct {
+	client dynclient.Client
+	key    dynclient.ObjectKey
+}
+
+func newSecretStoreFromURL(u *url.URL) (*secretStore, error) {
+	namespace, name := u.Host, strings.Trim(u.Path, "/")
+	if namespace == "" || name == "" || strings.Contains(name, "/") {
+		return nil, errors.New("invalid secret state store, expected format is secret://<namespace>/<name>")
+	}
+
+	cfg, err := ctrl.GetConfig()
+	if kubeconfig := u.Query().Get("kubeconfig"); kubeconfig != "" {
+		cfg, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load the kubeconfig of the secret state store")
+	}
+
+	c, err := dynclient.New(cfg, dynclient.Options{Scheme: scheme.Scheme})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the client of the secret state store")
+	}
+
+	return newSecretStore(c, namespace, name), nil
+}
+
+func newSecretStore(c dynclient.Client, namespace, name string) *secretStore {
+	return &secretStore{
+		client: c,
+		key:    dynclient.ObjectKey{Namespace: namespace, Name: name},
+	}
+}
+
+func (s *secretStore) Put(ctx context.Context, name string, data []byte) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret := corev1.Secret{}
+		err := s.client.Get(ctx, s.key, &secret)
+		if k8serrors.IsNotFound(err) {
+			secret = corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: s.key.Namespace,
+					Name:      s.key.Name,
+				},
+				Type: corev1.SecretTypeOpaque,
+				Data: map[string][]byte{name: data},
+			}
+
+			return s.client.Create(ctx, &secret)
+		}
+		if err != nil {
+			return err
+		}
+
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[name] = data
+
+		return s.client.Update(ctx, &secret)
+	})
+
+	return errors.Wrapf(err, "failed to store %q in the Secret %s", name, s.key)
+}
+
+func (s *secretStore) Get(ctx context.Context, name string) ([]byte, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+
+	secret := corev1.Secret{}
+	err := s.client.Get(ctx, s.key, &secret)
+	if k8serrors.IsNotFound(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the Secret %s", s.key)
+	}
+
+	data, ok := secret.Data[name]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return data, nil
+}
+
+func (s *secretStore) Delete(ctx context.Context, name string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret := corev1.Secret{}
+		if err := s.client.Get(ctx, s.key, &secret); err != nil {
+			return dynclient.IgnoreNotFound(err)
+		}
+
+		if _, ok := secret.Data[name]; !ok {
+			return nil
+		}
+		delete(secret.Data, name)
+
+		return s.client.Update(ctx, &secret)
+	})
+
+	return errors.Wrapf(err, "failed to delete %q from the Secret %s", name, s.key)
+}
+
+func (s *secretStore) List(ctx context.Context) ([]string, error) {
+	secret := corev1.Secret{}
+	err := s.client.Get(ctx, s.key, &secret)
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the Secret %s", s.key)
+	}
+
+	names := []string{}
+	for name := range secret.Data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func (s *secretStore) String() string {
+	return "secret://" + s.key.String()
+}
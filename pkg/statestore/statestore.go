@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package statestore persists the artifacts generated by KubeOne, such as the
+// kubeconfig, the PKI backups and the saved MachineDeployments, outside of
+// the machine running KubeOne. This allows running KubeOne from ephemeral
+// machines, such as CI runners, without losing the artifacts.
+//
+// Supported locations:
+//   - <dir> or file://<dir> - the local directory
+//   - s3://<bucket>/<prefix> - the AWS S3 bucket, configured using the
+//     standard AWS SDK environment and config files. The region, endpoint,
+//     force_path_style and profile query parameters are supported, e.g.
+//     s3://kubeone/clusters?region=eu-west-1.
+//   - secret://<namespace>/<name> - the Secret in the Kubernetes cluster,
+//     each artifact is stored under its own key. The cluster is configured
+//     using the KUBECONFIG environment variable or the in-cluster config,
+//     or using the kubeconfig query parameter.
+package statestore
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	schemeFile   = "file"
+	schemeS3     = "s3"
+	schemeSecret = "secret"
+)
+
+// ErrNotFound is returned if the artifact is not found in the store
+var ErrNotFound = errors.New("artifact not found")
+
+// validName matches the names which can be used as the keys of the Secret,
+// the S3 objects and the files
+var validName = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+
+// Store persists the artifacts, identified by their names
+type Store interface {
+	// Put stores the artifact, replacing the existing one
+	Put(ctx context.Context, name string, data []byte) error
+	// Get returns the artifact, or ErrNotFound if it doesn't exist
+	Get(ctx context.Context, name string) ([]byte, error)
+	// Delete removes the artifact, it's not an error if it doesn't exist
+	Delete(ctx context.Context, name string) error
+	// List returns the names of all stored artifacts
+	List(ctx context.Context) ([]string, error)
+	// String returns the location of the store
+	String() string
+}
+
+// New returns the store for the given location, see the package
+// documentation for the supported locations
+func New(location string) (Store, error) {
+	if !strings.Contains(location, "://") {
+		return newLocalStore(location)
+	}
+
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid state store location %q", location)
+	}
+
+	switch u.Scheme {
+	case schemeFile:
+		return newLocalStore(u.Path)
+	case schemeS3:
+		return newS3Store(u)
+	case schemeSecret:
+		return newSecretStoreFromURL(u)
+	}
+
+	return nil, errors.Errorf("unsupported state store %q, supported stores are %s://, %s:// and %s://", u.Scheme, schemeFile, schemeS3, schemeSecret)
+}
+
+// validateName checks the artifact name can be used by all stores
+func validateName(name string) error {
+	if !validName.MatchString(name) {
+		return errors.Errorf("invalid artifact name %q, only alphanumeric characters, '-', '_' and '.' are allowed", name)
+	}
+
+	return nil
+}
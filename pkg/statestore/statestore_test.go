@@ -0,0 +1,181 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statestore
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		location string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "directory",
+			location: "./state",
+			want:     "./state",
+		},
+		{
+			name:     "file scheme",
+			location: "file:///var/lib/kubeone",
+			want:     "/var/lib/kubeone",
+		},
+		{
+			name:     "empty file path",
+			location: "file://",
+			wantErr:  true,
+		},
+		{
+			name:     "s3 without bucket",
+			location: "s3:///prefix",
+			wantErr:  true,
+		},
+		{
+			name:     "secret without name",
+			location: "secret://kube-system",
+			wantErr:  true,
+		},
+		{
+			name:     "secret with nested name",
+			location: "secret://kube-system/a/b",
+			wantErr:  true,
+		},
+		{
+			name:     "unsupported scheme",
+			location: "gcs://bucket",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.location)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("New() = %q, want %q", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{name: "example-kubeconfig"},
+		{name: "example_machinedeployments.yaml"},
+		{name: "", wantErr: true},
+		{name: "../kubeconfig", wantErr: true},
+		{name: "dir/kubeconfig", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateName(tt.name); (err != nil) != tt.wantErr {
+				t.Errorf("validateName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStores(t *testing.T) {
+	local, err := newLocalStore(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stores := map[string]Store{
+		"local":  local,
+		"secret": newSecretStore(fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(), "kube-system", "kubeone-state"),
+	}
+
+	for name, store := range stores {
+		store := store
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			names, err := store.List(ctx)
+			if err != nil {
+				t.Fatalf("List() on empty store failed: %v", err)
+			}
+			if len(names) != 0 {
+				t.Errorf("List() on empty store = %v, want none", names)
+			}
+
+			if _, err = store.Get(ctx, "kubeconfig"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Get() on empty store error = %v, want ErrNotFound", err)
+			}
+
+			for _, artifact := range []string{"kubeconfig", "backup.tar.gz"} {
+				if err = store.Put(ctx, artifact, []byte("old")); err != nil {
+					t.Fatalf("Put(%q) failed: %v", artifact, err)
+				}
+			}
+			if err = store.Put(ctx, "kubeconfig", []byte("new")); err != nil {
+				t.Fatalf("Put() replacing the artifact failed: %v", err)
+			}
+			if err = store.Put(ctx, "../kubeconfig", []byte("new")); err == nil {
+				t.Error("Put() with invalid name succeeded")
+			}
+
+			data, err := store.Get(ctx, "kubeconfig")
+			if err != nil {
+				t.Fatalf("Get() failed: %v", err)
+			}
+			if string(data) != "new" {
+				t.Errorf("Get() = %q, want %q", data, "new")
+			}
+
+			if _, err = store.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Get() of missing artifact error = %v, want ErrNotFound", err)
+			}
+
+			names, err = store.List(ctx)
+			if err != nil {
+				t.Fatalf("List() failed: %v", err)
+			}
+			if want := []string{"backup.tar.gz", "kubeconfig"}; !reflect.DeepEqual(names, want) {
+				t.Errorf("List() = %v, want %v", names, want)
+			}
+
+			for i := 0; i < 2; i++ {
+				if err = store.Delete(ctx, "backup.tar.gz"); err != nil {
+					t.Fatalf("Delete() failed: %v", err)
+				}
+			}
+			if _, err = store.Get(ctx, "backup.tar.gz"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Get() of deleted artifact error = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
@@ -57,7 +57,7 @@ func ccmMigrationValidateConfig(s *state.State) error {
 		return errors.New("the ccm/csi migration is currently in progress, run command with --complete to finish it")
 	}
 	if s.Cluster.CloudProvider.Vsphere != nil && s.Cluster.CloudProvider.CSIConfig == "" {
-		return errors.New("the ccm/csi migration for vsphere requires providing csi configuration using .cloudProvider.csiConfig field")
+		return errors.New("the ccm/csi migration for vsphere requires providing csi configuration using .cloudProvider.csiConfig or .cloudProvider.vsphere.csi field")
 	}
 
 	return nil
@@ -139,7 +139,7 @@ func ccmMigrationUpdateControlPlaneKubeletConfigInternal(s *state.State, node *k
 	logger := s.Logger.WithField("node", node.PublicAddress)
 	logger.Info("Updating config and restarting Kubelet...")
 
-	drainer := nodeutils.NewDrainer(s.RESTConfig, logger)
+	drainer := nodeutils.NewDrainer(s.RESTConfig, logger, s.Cluster.NodeDrainConfig(*node))
 
 	logger.Infoln("Cordoning node...")
 	if err := drainer.Cordon(s.Context, node.Hostname, true); err != nil {
@@ -183,7 +183,7 @@ func ccmMigrationUpdateStaticWorkersKubeletConfigInternal(s *state.State, node *
 	logger := s.Logger.WithField("node", node.PublicAddress)
 	logger.Info("Updating config and restarting Kubelet...")
 
-	drainer := nodeutils.NewDrainer(s.RESTConfig, logger)
+	drainer := nodeutils.NewDrainer(s.RESTConfig, logger, s.Cluster.NodeDrainConfig(*node))
 
 	logger.Infoln("Cordoning node...")
 	if err := drainer.Cordon(s.Context, node.Hostname, true); err != nil {
@@ -22,13 +22,13 @@ import (
 	"io/fs"
 	"time"
 
-	"github.com/Masterminds/semver/v3"
 	"github.com/pkg/errors"
 
 	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
 	"k8c.io/kubeone/pkg/certificate/cabundle"
 	"k8c.io/kubeone/pkg/clientutil"
 	"k8c.io/kubeone/pkg/kubeconfig"
+	"k8c.io/kubeone/pkg/nodeutils"
 	"k8c.io/kubeone/pkg/scripts"
 	"k8c.io/kubeone/pkg/ssh"
 	"k8c.io/kubeone/pkg/ssh/sshiofs"
@@ -37,35 +37,49 @@ import (
 
 func renewControlPlaneCerts(s *state.State) error {
 	if !s.ForceUpgrade {
-		s.Logger.Warn("Your control-plane certificates are about to expire in less then 90 days")
+		s.Logger.Warnf("Your control-plane certificates are about to expire in less then %s", s.Cluster.CertificateRenewBefore())
 		s.Logger.Warn("To renew them without changing kubernetes version run `kubeone apply --force-upgrade`")
 		return nil
 	}
-	s.Logger.Warn("Your control-plane certificates are about to expire in less then 90 days")
+	s.Logger.Warnf("Your control-plane certificates are about to expire in less then %s", s.Cluster.CertificateRenewBefore())
 	s.Logger.Warn("Force renewing Kubernetes certificates")
 
 	// /etc/kubernetes/admin.conf will be changed after certificates renew, so we have to initialize client again
 	s.Logger.Infoln("Resetting Kubernetes clientset...")
 	s.DynamicClient = nil
 
-	renewCmd := "sudo kubeadm alpha certs renew all"
-	greaterThen120, _ := semver.NewConstraint(">=1.20")
-	if greaterThen120.Check(s.LiveCluster.ExpectedVersion) {
-		renewCmd = "sudo kubeadm certs renew all"
+	err := s.RunTaskOnControlPlane(renewNodeCerts, state.RunParallel)
+	if err != nil {
+		return err
 	}
 
-	err := s.RunTaskOnControlPlane(
-		func(s *state.State, node *kubeoneapi.HostConfig, conn ssh.Connection) error {
-			_, _, err := s.Runner.RunRaw(renewCmd)
-			return err
-		},
-		state.RunParallel,
-	)
+	if err = kubeconfig.BuildKubernetesClientset(s); err != nil {
+		return err
+	}
+
+	for i := range s.Cluster.ControlPlane.Hosts {
+		recordNodeEvent(s, &s.Cluster.ControlPlane.Hosts[i], nodeutils.EventReasonCertificatesRenewed, "Control plane certificates renewed by KubeOne")
+	}
+
+	return nil
+}
+
+// renewNodeCerts renews the certificates on the control plane node. If the
+// certificates are signed by the external CA signer, they're issued by KubeOne,
+// as kubeadm can't renew them without the CA key.
+func renewNodeCerts(s *state.State, node *kubeoneapi.HostConfig, conn ssh.Connection) error {
+	if s.Cluster.ExternalCASignerEnabled() {
+		return installExternalCAFiles(s, node, conn, true)
+	}
+
+	cmd, err := scripts.CertificatesRenew(kubeadmCertsRenewAlpha(s))
 	if err != nil {
 		return err
 	}
 
-	return kubeconfig.BuildKubernetesClientset(s)
+	_, _, err = s.Runner.RunRaw(cmd)
+
+	return err
 }
 
 func fetchCert(sshfs fs.FS, filename string) (*x509.Certificate, error) {
@@ -136,7 +150,7 @@ func ensureCABundleConfigMap(s *state.State) error {
 	s.Logger.Infoln("Creating ca-bundle configMap...")
 
 	cm := cabundle.ConfigMap(s.Cluster.CABundle)
-	return clientutil.CreateOrUpdate(s.Context, s.DynamicClient, cm)
+	return clientutil.CreateOrUpdate(s.Context, s.DynamicClient, cm, clientutil.WithManifestPatches(s.Cluster.ManifestPatches))
 }
 
 func saveCABundle(s *state.State) error {
@@ -0,0 +1,398 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"io/fs"
+	"path"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/certificate"
+	"k8c.io/kubeone/pkg/clusterstatus/apiserverstatus"
+	"k8c.io/kubeone/pkg/configupload"
+	"k8c.io/kubeone/pkg/kubeconfig"
+	"k8c.io/kubeone/pkg/nodeutils"
+	"k8c.io/kubeone/pkg/scripts"
+	"k8c.io/kubeone/pkg/ssh"
+	"k8c.io/kubeone/pkg/state"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientcmdapiv1 "k8s.io/client-go/tools/clientcmd/api/v1"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/retry"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// Phases of the Kubernetes CA rotation. The phases must be run in order, and
+// each phase must be completed on all nodes before running the next one.
+const (
+	// CARotationPhaseTrust generates the new CA and adds it to the CAs
+	// trusted by all nodes and components. Certificates are still signed by
+	// the old CA.
+	CARotationPhaseTrust = "trust"
+	// CARotationPhaseSign signs all certificates, including the kubelet
+	// client certificates, by the new CA. The old CA is still trusted.
+	CARotationPhaseSign = "sign"
+	// CARotationPhaseFinalize removes the old CA from the trusted CAs
+	CARotationPhaseFinalize = "finalize"
+
+	// apiserverHealthyTimeout is how long to wait for the API server to
+	// become healthy after the control plane static pods are restarted
+	apiserverHealthyTimeout = 5 * time.Minute
+
+	clusterInfoNamespace = "kube-public"
+	clusterInfoName      = "cluster-info"
+	clusterInfoKey       = "kubeconfig"
+)
+
+// CARotationPhases are the phases of the Kubernetes CA rotation, in order
+var CARotationPhases = []string{CARotationPhaseTrust, CARotationPhaseSign, CARotationPhaseFinalize}
+
+// certificatesRotation renews the control plane certificates, and
+// optionally runs the phase of the Kubernetes CA rotation
+type certificatesRotation struct {
+	caPhase string
+
+	// trustedCAs is installed as ca.crt on all nodes, the CA signing the
+	// certificates is the first one
+	trustedCAs []byte
+	// signingKey is installed as ca.key on the control plane nodes, if set
+	signingKey []byte
+	// rotationCA and rotationKey are the new CA, saved to the control plane
+	// nodes in the trust phase
+	rotationCA  []byte
+	rotationKey []byte
+	// kubeletCerts are the kubelet client certificates signed by the new CA,
+	// by the node name
+	kubeletCerts map[string][]byte
+	// keyAlgorithm is the algorithm of the keys of the new CA and the
+	// kubelet client certificates
+	keyAlgorithm kubeoneapi.KeyAlgorithm
+	// certificateValidity is the validity period of the kubelet client
+	// certificates
+	certificateValidity time.Duration
+}
+
+// WithCertificatesRotation renews the certificates of the control plane
+// nodes one node at a time, and restarts the control plane static pods. If
+// the CA rotation phase is given, the phase is run on all nodes as well.
+func WithCertificatesRotation(t Tasks, caPhase string) Tasks {
+	r := &certificatesRotation{caPhase: caPhase}
+
+	return t.append(Tasks{
+		{Fn: kubeconfig.BuildKubernetesClientset, ErrMsg: "failed to build kubernetes clientset"},
+		{
+			Fn:          r.prepareCARotation,
+			ErrMsg:      "failed to prepare the CA rotation",
+			Description: "prepare the CA rotation",
+			Retries:     1,
+			Predicate:   func(*state.State) bool { return caPhase != "" },
+		},
+		{
+			Fn: func(s *state.State) error {
+				return s.RunTaskOnControlPlane(r.rotateControlPlaneNode, state.RunSequentially)
+			},
+			ErrMsg:      "failed to rotate control plane certificates",
+			Description: "rotate control plane certificates",
+			Retries:     1,
+		},
+		{
+			Fn: func(s *state.State) error {
+				return s.RunTaskOnStaticWorkers(r.installCARotationOnWorker, state.RunSequentially)
+			},
+			ErrMsg:      "failed to rotate static workers CA",
+			Description: "rotate static workers CA",
+			Retries:     1,
+			Predicate:   func(*state.State) bool { return caPhase != "" },
+		},
+		// admin.conf is changed by the rotation
+		{Fn: kubeconfig.BuildKubernetesClientset, ErrMsg: "failed to build kubernetes clientset"},
+		{
+			Fn:          r.updateClusterInfo,
+			ErrMsg:      "failed to update the cluster-info ConfigMap",
+			Description: "update the CA in the cluster-info ConfigMap",
+			Predicate:   func(*state.State) bool { return caPhase != "" },
+		},
+		{Fn: saveKubeconfig, ErrMsg: "failed to save kubeconfig to the local machine"},
+	}...)
+}
+
+// kubeadmCertsRenewAlpha returns whether the certificates are renewed by the
+// alpha kubeadm command, used by kubeadm older than 1.20
+func kubeadmCertsRenewAlpha(s *state.State) bool {
+	greaterThen120, _ := semver.NewConstraint(">=1.20")
+
+	return !greaterThen120.Check(s.LiveCluster.ExpectedVersion)
+}
+
+// prepareCARotation reads the CAs from the leader, validates that the phase
+// can be run and generates the files to be installed on the nodes
+func (r *certificatesRotation) prepareCARotation(s *state.State) error {
+	if s.Cluster.ExternalCA() != nil {
+		return errors.New("the CA provided by the user can't be rotated by KubeOne, update the external CA configuration instead")
+	}
+
+	r.keyAlgorithm = s.Cluster.KubeadmKeyAlgorithm()
+	r.certificateValidity = s.Cluster.CertificateValidity()
+
+	var currentCAs, rotationCA, rotationKey []byte
+
+	err := s.RunTaskOnLeader(func(s *state.State, _ *kubeoneapi.HostConfig, _ ssh.Connection) error {
+		sshfs := s.Runner.NewFS()
+
+		var err error
+		if currentCAs, err = fs.ReadFile(sshfs, certificate.KubernetesCACertPath); err != nil {
+			return err
+		}
+
+		rotationCA, err = fs.ReadFile(sshfs, path.Join(scripts.CARotationDir, "ca.crt"))
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		rotationKey, err = fs.ReadFile(sshfs, path.Join(scripts.CARotationDir, "ca.key"))
+
+		return err
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to read the CA from the leader")
+	}
+
+	if rotationCA == nil {
+		if r.caPhase != CARotationPhaseTrust {
+			return errors.Errorf("the CA rotation is not started, run the %q phase first", CARotationPhaseTrust)
+		}
+
+		s.Logger.Info("Generating the new CA...")
+		if rotationCA, rotationKey, err = certificate.NewCA("kubernetes", r.keyAlgorithm); err != nil {
+			return err
+		}
+	}
+
+	hosts := append([]kubeoneapi.HostConfig{}, s.Cluster.ControlPlane.Hosts...)
+	hosts = append(hosts, s.Cluster.StaticWorkers.Hosts...)
+
+	return r.plan(hosts, currentCAs, rotationCA, rotationKey)
+}
+
+// plan validates that the CA rotation phase can be run, given the CAs
+// currently trusted by the cluster and the new CA, and generates the files to
+// be installed on the given hosts
+func (r *certificatesRotation) plan(hosts []kubeoneapi.HostConfig, currentCAs, rotationCA, rotationKey []byte) error {
+	currentCerts, err := certutil.ParseCertsPEM(currentCAs)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse the CA")
+	}
+
+	newKey, newCert, err := certificate.ParseCAKeyPair(rotationCA, rotationKey)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse the new CA")
+	}
+
+	oldCerts := []*x509.Certificate{}
+	for _, cert := range currentCerts {
+		if !cert.Equal(newCert) {
+			oldCerts = append(oldCerts, cert)
+		}
+	}
+	signedByNewCA := currentCerts[0].Equal(newCert)
+
+	switch r.caPhase {
+	case CARotationPhaseTrust:
+		if signedByNewCA {
+			return errors.Errorf("certificates are already signed by the new CA, run the %q phase", CARotationPhaseFinalize)
+		}
+		// The old CA keeps signing the certificates
+		r.trustedCAs = encodeCerts(append(oldCerts, newCert)...)
+		r.rotationCA, r.rotationKey = rotationCA, rotationKey
+	case CARotationPhaseSign:
+		r.trustedCAs = encodeCerts(append([]*x509.Certificate{newCert}, oldCerts...)...)
+		r.signingKey = rotationKey
+		r.kubeletCerts = map[string][]byte{}
+		newIssuer := certificate.NewKeyIssuer(newKey, newCert, r.keyAlgorithm)
+		for _, host := range hosts {
+			cert, certErr := certificate.NewKubeletClientCert(host.Hostname, r.certificateValidity, newIssuer)
+			if certErr != nil {
+				return errors.Wrapf(certErr, "failed to generate the kubelet client certificate for %q", host.Hostname)
+			}
+			r.kubeletCerts[host.Hostname] = cert
+		}
+	case CARotationPhaseFinalize:
+		if !signedByNewCA {
+			return errors.Errorf("certificates are not signed by the new CA yet, run the %q phase first", CARotationPhaseSign)
+		}
+		r.trustedCAs = encodeCerts(newCert)
+	default:
+		return errors.Errorf("unknown CA rotation phase %q", r.caPhase)
+	}
+
+	return nil
+}
+
+func encodeCerts(certs ...*x509.Certificate) []byte {
+	buf := bytes.Buffer{}
+	for _, cert := range certs {
+		buf.Write(pem.EncodeToMemory(&pem.Block{Type: certutil.CertificateBlockType, Bytes: cert.Raw}))
+	}
+
+	return buf.Bytes()
+}
+
+// rotateControlPlaneNode runs the CA rotation phase, renews the certificates
+// and restarts the control plane static pods on the node, and waits for the
+// API server on the node to become healthy
+func (r *certificatesRotation) rotateControlPlaneNode(s *state.State, node *kubeoneapi.HostConfig, conn ssh.Connection) error {
+	if r.caPhase != "" {
+		s.Logger.Infof("Running the %q phase of the CA rotation...", r.caPhase)
+		if err := r.installCARotation(s, conn, r.controlPlaneFiles(node)); err != nil {
+			return err
+		}
+	}
+
+	renew := r.caPhase == "" || r.caPhase == CARotationPhaseSign
+	if renew {
+		s.Logger.Info("Renewing certificates...")
+		if err := renewNodeCerts(s, node, conn); err != nil {
+			return err
+		}
+	}
+
+	s.Logger.Info("Restarting control plane static pods...")
+	cmd, err := scripts.RestartControlPlaneStaticPods()
+	if err != nil {
+		return err
+	}
+	if _, _, err = s.Runner.RunRaw(cmd); err != nil {
+		return err
+	}
+
+	s.Logger.Info("Waiting for the API server to become healthy...")
+	if err = waitForAPIServerHealthy(s, *node, apiserverHealthyTimeout); err != nil {
+		return errors.Wrap(err, "API server didn't become healthy")
+	}
+
+	if renew {
+		recordNodeEvent(s, node, nodeutils.EventReasonCertificatesRenewed, "Control plane certificates renewed by KubeOne")
+	}
+
+	return nil
+}
+
+func (r *certificatesRotation) installCARotationOnWorker(s *state.State, node *kubeoneapi.HostConfig, conn ssh.Connection) error {
+	s.Logger.Infof("Running the %q phase of the CA rotation...", r.caPhase)
+
+	return r.installCARotation(s, conn, r.nodeFiles(node))
+}
+
+// nodeFiles returns the CA rotation files installed on all nodes
+func (r *certificatesRotation) nodeFiles(node *kubeoneapi.HostConfig) map[string][]byte {
+	files := map[string][]byte{"ca.crt": r.trustedCAs}
+	if cert, ok := r.kubeletCerts[node.Hostname]; ok {
+		files["kubelet-client.pem"] = cert
+	}
+
+	return files
+}
+
+// controlPlaneFiles returns the CA rotation files installed on the control
+// plane nodes, including the CA keys
+func (r *certificatesRotation) controlPlaneFiles(node *kubeoneapi.HostConfig) map[string][]byte {
+	files := r.nodeFiles(node)
+	if r.signingKey != nil {
+		files["ca.key"] = r.signingKey
+	}
+	if r.rotationCA != nil {
+		files["rotation-ca.crt"] = r.rotationCA
+		files["rotation-ca.key"] = r.rotationKey
+	}
+
+	return files
+}
+
+func (r *certificatesRotation) installCARotation(s *state.State, conn ssh.Connection, files map[string][]byte) error {
+	upload := configupload.NewConfiguration()
+	for name, content := range files {
+		upload.AddFile(path.Join(scripts.CARotationUploadDir, name), string(content))
+	}
+	if err := upload.UploadTo(conn, s.WorkDir); err != nil {
+		return errors.Wrap(err, "failed to upload the CA rotation files")
+	}
+
+	cmd, err := scripts.CARotationInstall(s.WorkDir, r.caPhase == CARotationPhaseFinalize)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = s.Runner.RunRaw(cmd)
+
+	return err
+}
+
+// updateClusterInfo updates the CAs in the cluster-info ConfigMap, used by
+// the new nodes to discover the cluster. The ConfigMap signatures are
+// updated by the bootstrap signer.
+func (r *certificatesRotation) updateClusterInfo(s *state.State) error {
+	if s.DynamicClient == nil {
+		return errors.New("kubernetes client not initialized")
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm := corev1.ConfigMap{}
+		key := dynclient.ObjectKey{Namespace: clusterInfoNamespace, Name: clusterInfoName}
+		if err := s.DynamicClient.Get(s.Context, key, &cm); err != nil {
+			return errors.Wrapf(err, "failed to get ConfigMap %s", key)
+		}
+
+		konfig := clientcmdapiv1.Config{}
+		if err := yaml.Unmarshal([]byte(cm.Data[clusterInfoKey]), &konfig); err != nil {
+			return errors.Wrap(err, "failed to parse the cluster-info kubeconfig")
+		}
+		for i := range konfig.Clusters {
+			konfig.Clusters[i].Cluster.CertificateAuthorityData = r.trustedCAs
+		}
+
+		buf, err := yaml.Marshal(konfig)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal the cluster-info kubeconfig")
+		}
+		cm.Data[clusterInfoKey] = string(buf)
+
+		return s.DynamicClient.Update(s.Context, &cm)
+	})
+}
+
+func waitForAPIServerHealthy(s *state.State, node kubeoneapi.HostConfig, timeout time.Duration) error {
+	return wait.PollImmediate(5*time.Second, timeout, func() (bool, error) {
+		report, err := apiserverstatus.Get(s, node)
+		if err != nil {
+			s.Logger.Debugf("API server is not healthy yet: %v", err)
+			return false, nil
+		}
+
+		return report.Health, nil
+	})
+}
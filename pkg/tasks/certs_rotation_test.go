@@ -0,0 +1,132 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"bytes"
+	"testing"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/certificate"
+
+	certutil "k8s.io/client-go/util/cert"
+)
+
+func TestCertificatesRotationPlan(t *testing.T) {
+	oldCA, _, err := certificate.NewCA("kubernetes", kubeoneapi.KeyAlgorithmRSA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newCA, newKey, err := certificate.NewCA("kubernetes", kubeoneapi.KeyAlgorithmRSA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hosts := []kubeoneapi.HostConfig{{Hostname: "cp-0"}, {Hostname: "worker-0"}}
+
+	tests := []struct {
+		name               string
+		phase              string
+		currentCAs         []byte
+		expectedTrustedCAs []byte
+		expectedSigningKey bool
+		expectedRotationCA bool
+		expectedKubelet    bool
+		expectedError      bool
+	}{
+		{
+			name:               "trust",
+			phase:              CARotationPhaseTrust,
+			currentCAs:         oldCA,
+			expectedTrustedCAs: append(append([]byte{}, oldCA...), newCA...),
+			expectedRotationCA: true,
+		},
+		{
+			name:               "trust again",
+			phase:              CARotationPhaseTrust,
+			currentCAs:         append(append([]byte{}, oldCA...), newCA...),
+			expectedTrustedCAs: append(append([]byte{}, oldCA...), newCA...),
+			expectedRotationCA: true,
+		},
+		{
+			name:          "trust after sign",
+			phase:         CARotationPhaseTrust,
+			currentCAs:    append(append([]byte{}, newCA...), oldCA...),
+			expectedError: true,
+		},
+		{
+			name:               "sign",
+			phase:              CARotationPhaseSign,
+			currentCAs:         append(append([]byte{}, oldCA...), newCA...),
+			expectedTrustedCAs: append(append([]byte{}, newCA...), oldCA...),
+			expectedSigningKey: true,
+			expectedKubelet:    true,
+		},
+		{
+			name:               "finalize",
+			phase:              CARotationPhaseFinalize,
+			currentCAs:         append(append([]byte{}, newCA...), oldCA...),
+			expectedTrustedCAs: newCA,
+		},
+		{
+			name:          "finalize before sign",
+			phase:         CARotationPhaseFinalize,
+			currentCAs:    append(append([]byte{}, oldCA...), newCA...),
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			r := &certificatesRotation{
+				caPhase:             tc.phase,
+				certificateValidity: kubeoneapi.DefaultCertificateValidity,
+			}
+
+			err := r.plan(hosts, tc.currentCAs, newCA, newKey)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("expected error = %v, got %v", tc.expectedError, err)
+			}
+			if tc.expectedError {
+				return
+			}
+
+			if !bytes.Equal(r.trustedCAs, tc.expectedTrustedCAs) {
+				t.Errorf("unexpected trusted CAs:\n%s", r.trustedCAs)
+			}
+			if (r.signingKey != nil) != tc.expectedSigningKey {
+				t.Errorf("expected signing key = %v, got %v", tc.expectedSigningKey, r.signingKey != nil)
+			}
+			if (r.rotationCA != nil) != tc.expectedRotationCA {
+				t.Errorf("expected rotation CA = %v, got %v", tc.expectedRotationCA, r.rotationCA != nil)
+			}
+			if (len(r.kubeletCerts) == len(hosts)) != tc.expectedKubelet {
+				t.Errorf("expected kubelet certificates = %v, got %d", tc.expectedKubelet, len(r.kubeletCerts))
+			}
+
+			for name, pemBytes := range r.kubeletCerts {
+				certs, err := certutil.ParseCertsPEM(pemBytes)
+				if err != nil {
+					t.Fatalf("failed to parse the kubelet certificate of %q: %v", name, err)
+				}
+				if certs[0].Subject.CommonName != "system:node:"+name {
+					t.Errorf("unexpected kubelet certificate CommonName %q", certs[0].Subject.CommonName)
+				}
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/pkg/errors"
 
 	"k8c.io/kubeone/pkg/addons"
+	"k8c.io/kubeone/pkg/helm"
 	"k8c.io/kubeone/pkg/kubeconfig"
 	"k8c.io/kubeone/pkg/state"
 	"k8c.io/kubeone/pkg/templates/resources"
@@ -47,6 +48,11 @@ func ensureCNI(s *state.State) error {
 		}
 	case s.Cluster.ClusterNetwork.CNI.External != nil:
 		s.Logger.Infoln("External CNI plugin will be used")
+		if helmChart := s.Cluster.ClusterNetwork.CNI.External.HelmChart; helmChart != nil {
+			if err := helm.EnsureRelease(s, helmChart); err != nil {
+				return err
+			}
+		}
 	default:
 		return errors.Errorf("unknown CNI provider")
 	}
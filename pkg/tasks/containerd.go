@@ -17,20 +17,34 @@ limitations under the License.
 package tasks
 
 import (
-	"errors"
+	"io"
 	"time"
 
+	"github.com/pkg/errors"
+
 	"k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/clientutil"
+	"k8c.io/kubeone/pkg/nodeutils"
 	"k8c.io/kubeone/pkg/scripts"
 	"k8c.io/kubeone/pkg/ssh"
 	"k8c.io/kubeone/pkg/state"
 
 	corev1 "k8s.io/api/core/v1"
+	nodev1 "k8s.io/api/node/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 const (
 	kubeadmCRISocket = "kubeadm.alpha.kubernetes.io/cri-socket"
+
+	// nvidiaRuntimeHandler is the name of the nvidia runtime handler in the
+	// containerd config and of the RuntimeClass using it
+	nvidiaRuntimeHandler = "nvidia"
+
+	// containerdMigrationTimeout is how long to wait for a node and its pods
+	// to become ready after migrating it to containerd
+	containerdMigrationTimeout = 10 * time.Minute
 )
 
 var (
@@ -48,6 +62,19 @@ func validateContainerdInConfig(s *state.State) error {
 	return nil
 }
 
+// ensureNvidiaRuntimeClass creates the nvidia RuntimeClass, so GPU workloads
+// can be run using the nvidia runtime handler configured in containerd
+func ensureNvidiaRuntimeClass(s *state.State) error {
+	runtimeClass := &nodev1.RuntimeClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: nvidiaRuntimeHandler,
+		},
+		Handler: nvidiaRuntimeHandler,
+	}
+
+	return clientutil.CreateOrUpdate(s.Context, s.DynamicClient, runtimeClass, clientutil.WithManifestPatches(s.Cluster.ManifestPatches))
+}
+
 func patchCRISocketAnnotation(s *state.State) error {
 	var nodes corev1.NodeList
 
@@ -81,9 +108,24 @@ func migrateToContainerd(s *state.State) error {
 }
 
 func migrateToContainerdTask(s *state.State, node *kubeone.HostConfig, conn ssh.Connection) error {
-	s.Logger.Info("Migrating container runtime to containerd")
+	logger := s.Logger.WithField("node", node.PublicAddress)
 
-	err := updateRemoteFile(s, kubeadmEnvFlagsFile, func(content []byte) ([]byte, error) {
+	migrated, err := kubeletUsesContainerd(s)
+	if err != nil {
+		return errors.Wrap(err, "failed to read kubelet flags")
+	}
+	if migrated {
+		logger.Info("Node is already using containerd, skipping")
+		return nil
+	}
+
+	if err = drainNode(s, node, conn); err != nil {
+		return err
+	}
+
+	logger.Info("Migrating container runtime to containerd")
+
+	err = updateRemoteFile(s, kubeadmEnvFlagsFile, func(content []byte) ([]byte, error) {
 		kubeletFlags, err := unmarshalKubeletFlags(content)
 		if err != nil {
 			return nil, err
@@ -101,7 +143,7 @@ func migrateToContainerdTask(s *state.State, node *kubeone.HostConfig, conn ssh.
 	}
 
 	generateContainerdConfig := node.OperatingSystem != kubeone.OperatingSystemNameFlatcar
-	migrateScript, err := scripts.MigrateToContainerd(s.Cluster.RegistryConfiguration.InsecureRegistryAddress(), generateContainerdConfig)
+	migrateScript, err := scripts.MigrateToContainerd(s.Cluster, generateContainerdConfig)
 	if err != nil {
 		return err
 	}
@@ -111,12 +153,26 @@ func migrateToContainerdTask(s *state.State, node *kubeone.HostConfig, conn ssh.
 		return err
 	}
 
-	s.Logger.Infof("Waiting all pods on %q to became Ready...", node.Hostname)
-	err = wait.Poll(10*time.Second, 10*time.Minute, func() (bool, error) {
+	logger.Infof("Waiting up to %s for node to become ready...", containerdMigrationTimeout)
+	if err = waitForKubeletReady(conn, containerdMigrationTimeout); err != nil {
+		return errors.Wrapf(err, "kubelet failed to start in %s", containerdMigrationTimeout)
+	}
+	if err = waitForNodeReady(s, node.Hostname, containerdMigrationTimeout); err != nil {
+		return errors.Wrapf(err, "node didn't become ready in %s", containerdMigrationTimeout)
+	}
+
+	logger.Infoln("Uncordoning node...")
+	drainer := nodeutils.NewDrainer(s.RESTConfig, logger, s.Cluster.NodeDrainConfig(*node))
+	if err = drainer.Cordon(s.Context, node.Hostname, false); err != nil {
+		return errors.Wrap(err, "failed to uncordon node")
+	}
+
+	logger.Infof("Waiting all pods on %q to became Ready...", node.Hostname)
+	err = wait.Poll(10*time.Second, containerdMigrationTimeout, func() (bool, error) {
 		var podsList corev1.PodList
 
 		if perr := s.DynamicClient.List(s.Context, &podsList); perr != nil {
-			return false, err
+			return false, perr
 		}
 
 		for _, pod := range podsList.Items {
@@ -150,3 +206,31 @@ func migrateToContainerdTask(s *state.State, node *kubeone.HostConfig, conn ssh.
 
 	return err
 }
+
+// kubeletUsesContainerd checks the kubelet flags written by kubeadm to
+// find out is the node already migrated to containerd
+func kubeletUsesContainerd(s *state.State) (bool, error) {
+	f, err := s.Runner.NewFS().Open(kubeadmEnvFlagsFile)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	buf, err := io.ReadAll(f)
+	if err != nil {
+		return false, err
+	}
+
+	kubeletFlags, err := unmarshalKubeletFlags(buf)
+	if err != nil {
+		return false, err
+	}
+
+	for k, v := range containerdKubeletFlags {
+		if kubeletFlags[k] != v {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/clientutil"
+	"k8c.io/kubeone/pkg/credentials"
+	"k8c.io/kubeone/pkg/state"
+	"k8c.io/kubeone/pkg/templates/csi"
+	"k8c.io/kubeone/pkg/templates/externalccm"
+	"k8c.io/kubeone/pkg/templates/machinecontroller"
+	"k8c.io/kubeone/pkg/templates/resources"
+	"k8c.io/kubeone/pkg/templates/velero"
+
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	// credentialsSecrets are the Secrets (namespace/name) with the cloud
+	// credentials, created by KubeOne or by the embedded addons
+	credentialsSecrets = sets.NewString(
+		metav1.NamespaceSystem+"/"+credentials.SecretName,
+		metav1.NamespaceSystem+"/"+credentials.VsphereSecretName,
+		metav1.NamespaceSystem+"/"+credentials.CloudConfigSecretName,
+		metav1.NamespaceSystem+"/aws-ebs-csi-credentials",
+		metav1.NamespaceSystem+"/openstack-ccm-cloud-config",
+		metav1.NamespaceSystem+"/packet-cloud-config",
+		"velero/cloud-credentials",
+	)
+)
+
+// reapplyCredentialsAddons applies the addons which embed the cloud
+// credentials or reference them by the credentials keys
+func reapplyCredentialsAddons(s *state.State) error {
+	if s.Cluster.MachineController.Deploy {
+		if err := machinecontroller.Ensure(s); err != nil {
+			return err
+		}
+	}
+
+	if err := externalccm.Ensure(s); err != nil {
+		return err
+	}
+
+	if err := csi.Ensure(s); err != nil {
+		return err
+	}
+
+	return velero.Ensure(s)
+}
+
+// restartCredentialsConsumers rolls out the Deployments and DaemonSets
+// using the credentials Secrets, so they pick up the new credentials, and
+// waits for the rollouts to complete
+func restartCredentialsConsumers(s *state.State) error {
+	deployments := appsv1.DeploymentList{}
+	if err := s.DynamicClient.List(s.Context, &deployments); err != nil {
+		return errors.Wrap(err, "failed to list Deployments")
+	}
+
+	daemonSets := appsv1.DaemonSetList{}
+	if err := s.DynamicClient.List(s.Context, &daemonSets); err != nil {
+		return errors.Wrap(err, "failed to list DaemonSets")
+	}
+
+	var restarted []func() (bool, error)
+
+	for _, deploy := range deployments.Items {
+		if !usesSecrets(deploy.Namespace, deploy.Spec.Template.Spec, credentialsSecrets) {
+			continue
+		}
+
+		key := dynclient.ObjectKey{Name: deploy.Name, Namespace: deploy.Namespace}
+		s.Logger.Infof("Restarting Deployment %s...", key)
+		if err := clientutil.RestartDeployment(s.Context, s.DynamicClient, key); err != nil {
+			return err
+		}
+		restarted = append(restarted, clientutil.DeploymentRolledOutCondition(s.Context, s.DynamicClient, key))
+	}
+
+	for _, ds := range daemonSets.Items {
+		if !usesSecrets(ds.Namespace, ds.Spec.Template.Spec, credentialsSecrets) {
+			continue
+		}
+
+		key := dynclient.ObjectKey{Name: ds.Name, Namespace: ds.Namespace}
+		s.Logger.Infof("Restarting DaemonSet %s...", key)
+		if err := clientutil.RestartDaemonSet(s.Context, s.DynamicClient, key); err != nil {
+			return err
+		}
+		restarted = append(restarted, clientutil.DaemonSetRolledOutCondition(s.Context, s.DynamicClient, key))
+	}
+
+	s.Logger.Infoln("Waiting for the restarted workloads to roll out...")
+	for _, condFn := range restarted {
+		if err := wait.PollImmediate(5*time.Second, 10*time.Minute, condFn); err != nil {
+			return errors.Wrap(err, "failed to wait for the restarted workloads to roll out")
+		}
+	}
+
+	return nil
+}
+
+// usesSecrets returns true if the pod uses any of the given Secrets
+// (namespace/name) in the environment variables or the volumes
+func usesSecrets(namespace string, spec corev1.PodSpec, secrets sets.String) bool {
+	uses := func(name string) bool {
+		return secrets.Has(namespace + "/" + name)
+	}
+
+	for _, vol := range spec.Volumes {
+		if vol.Secret != nil && uses(vol.Secret.SecretName) {
+			return true
+		}
+		if vol.Projected != nil {
+			for _, src := range vol.Projected.Sources {
+				if src.Secret != nil && uses(src.Secret.Name) {
+					return true
+				}
+			}
+		}
+	}
+
+	containers := append([]corev1.Container{}, spec.InitContainers...)
+	containers = append(containers, spec.Containers...)
+	for _, container := range containers {
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.SecretRef != nil && uses(envFrom.SecretRef.Name) {
+				return true
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil && uses(env.ValueFrom.SecretKeyRef.Name) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// verifyMachineDeployments checks that machine-controller can reconcile the
+// machines with the new credentials. The MachineDeployments are updated in
+// the dry-run mode, so the machine-controller webhook validates them against
+// the cloud provider API using the new credentials.
+func verifyMachineDeployments(s *state.State) error {
+	if err := machinecontroller.WaitReady(s); err != nil {
+		return err
+	}
+
+	s.Logger.Infoln("Verifying MachineDeployments with the new credentials...")
+
+	mdList := clusterv1alpha1.MachineDeploymentList{}
+	if err := s.DynamicClient.List(s.Context, &mdList, dynclient.InNamespace(resources.MachineControllerNameSpace)); err != nil {
+		return errors.Wrap(err, "failed to list MachineDeployments")
+	}
+
+	for i := range mdList.Items {
+		md := &mdList.Items[i]
+		if err := s.DynamicClient.Update(s.Context, md, dynclient.DryRunAll); err != nil {
+			return errors.Wrapf(err, "MachineDeployment %q can't be reconciled with the new credentials", md.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_usesSecrets(t *testing.T) {
+	secretKeyRef := func(name string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: name},
+				Key:                  "key",
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		namespace string
+		spec      corev1.PodSpec
+		want      bool
+	}{
+		{
+			name:      "env secretKeyRef",
+			namespace: "kube-system",
+			spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Env: []corev1.EnvVar{{Name: "TOKEN", ValueFrom: secretKeyRef("cloud-provider-credentials")}}},
+				},
+			},
+			want: true,
+		},
+		{
+			name:      "envFrom in init container",
+			namespace: "kube-system",
+			spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{
+					{EnvFrom: []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "aws-ebs-csi-credentials"},
+					}}}},
+				},
+			},
+			want: true,
+		},
+		{
+			name:      "secret volume",
+			namespace: "velero",
+			spec: corev1.PodSpec{
+				Volumes: []corev1.Volume{
+					{Name: "creds", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "cloud-credentials"}}},
+				},
+			},
+			want: true,
+		},
+		{
+			name:      "projected volume",
+			namespace: "kube-system",
+			spec: corev1.PodSpec{
+				Volumes: []corev1.Volume{
+					{Name: "config", VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{
+						Sources: []corev1.VolumeProjection{{Secret: &corev1.SecretProjection{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "cloud-config"},
+						}}},
+					}}},
+				},
+			},
+			want: true,
+		},
+		{
+			name:      "same name in another namespace",
+			namespace: "default",
+			spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Env: []corev1.EnvVar{{Name: "TOKEN", ValueFrom: secretKeyRef("cloud-provider-credentials")}}},
+				},
+			},
+			want: false,
+		},
+		{
+			name:      "unrelated secret",
+			namespace: "kube-system",
+			spec: corev1.PodSpec{
+				Volumes: []corev1.Volume{
+					{Name: "certs", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "webhook-certs"}}},
+				},
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := usesSecrets(tt.namespace, tt.spec, credentialsSecrets); got != tt.want {
+				t.Errorf("usesSecrets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
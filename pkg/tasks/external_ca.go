@@ -0,0 +1,362 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"io/fs"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/certificate"
+	"k8c.io/kubeone/pkg/configupload"
+	"k8c.io/kubeone/pkg/kubeconfig"
+	"k8c.io/kubeone/pkg/scripts"
+	"k8c.io/kubeone/pkg/ssh"
+	"k8c.io/kubeone/pkg/state"
+	"k8c.io/kubeone/pkg/templates/kubeadm/kubeadmconfig"
+	"k8c.io/kubeone/pkg/templates/resources"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	certutil "k8s.io/client-go/util/cert"
+	utilnet "k8s.io/utils/net"
+)
+
+const kubeletCSRSignInterval = 5 * time.Second
+
+// externalCACert is the certificate issued by the external CA, which kubeadm
+// can't generate in the external CA mode
+type externalCACert struct {
+	// name is the path of the certificate and key, without the extension,
+	// relative to /etc/kubernetes/pki
+	name string
+	cfg  certutil.Config
+}
+
+// externalCAKubeconfig is the kubeconfig file authenticating with the client
+// certificate issued by the external CA
+type externalCAKubeconfig struct {
+	// name is the path of the kubeconfig relative to /etc/kubernetes
+	name   string
+	user   string
+	groups []string
+}
+
+// installExternalCA installs the CA provided by the user on the control plane
+// hosts before kubeadm generates the certificates. If the certificates are
+// signed by the external CA signer, the certificates and kubeconfig files
+// kubeadm can't generate without the CA key are issued and installed as well.
+// Files that already exist on the hosts are not replaced.
+func installExternalCA(s *state.State) error {
+	return s.RunTaskOnControlPlane(installExternalCAOnNode, state.RunSequentially)
+}
+
+func installExternalCAOnNode(s *state.State, node *kubeoneapi.HostConfig, conn ssh.Connection) error {
+	return installExternalCAFiles(s, node, conn, false)
+}
+
+// installExternalCAFiles installs the files provided by the external CA on the
+// control plane host. If renew is set, the certificates and the kubeconfig
+// files are issued again, as done by kubeadm certs renew.
+func installExternalCAFiles(s *state.State, node *kubeoneapi.HostConfig, conn ssh.Connection, renew bool) error {
+	files, err := externalCAFiles(s, *node, renew)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	s.Logger.Info("Installing the external CA files...")
+
+	upload := configupload.NewConfiguration()
+	for name, content := range files {
+		upload.AddFile(path.Join(scripts.ExternalCAUploadDir, name), string(content))
+	}
+	if err = upload.UploadTo(conn, s.WorkDir); err != nil {
+		return errors.Wrap(err, "failed to upload the external CA files")
+	}
+
+	cmd, err := scripts.ExternalCAInstall(s.WorkDir)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = s.Runner.RunRaw(cmd)
+
+	return err
+}
+
+// externalCAFiles returns the files to be installed on the control plane host,
+// keyed by the path relative to /etc/kubernetes
+func externalCAFiles(s *state.State, node kubeoneapi.HostConfig, renew bool) (map[string][]byte, error) {
+	ca := s.Cluster.ExternalCA()
+	sshfs := s.Runner.NewFS()
+	files := map[string][]byte{}
+
+	missing := func(name string) bool {
+		_, err := fs.Stat(sshfs, path.Join("/etc/kubernetes", name))
+
+		return errors.Is(err, fs.ErrNotExist)
+	}
+
+	if !s.Cluster.ExternalCASignerEnabled() {
+		// The followers get the CA with the rest of the PKI from the leader
+		if node.IsLeader && missing("pki/ca.crt") {
+			files["pki/ca.crt"] = []byte(ca.IntermediateCertificate)
+			files["pki/ca.key"] = []byte(ca.IntermediateKey)
+		}
+
+		return files, nil
+	}
+
+	issuer, err := certificate.KubernetesIssuer(s.Context, s.Cluster, nil, s.Cluster.KubeadmKeyAlgorithm())
+	if err != nil {
+		return nil, err
+	}
+
+	if missing("pki/ca.crt") {
+		files["pki/ca.crt"] = encodeCerts(issuer.CACert())
+	}
+
+	// kubeadm doesn't generate the service account keys in the external CA mode
+	if node.IsLeader && missing("pki/sa.key") {
+		saKey, saPub, saErr := certificate.NewServiceAccountKeyPair(s.Cluster.KubeadmKeyAlgorithm())
+		if saErr != nil {
+			return nil, saErr
+		}
+		files["pki/sa.key"] = saKey
+		files["pki/sa.pub"] = saPub
+	}
+
+	apiserverCfg, err := apiserverCertConfig(s.Cluster, node)
+	if err != nil {
+		return nil, err
+	}
+	certs := []externalCACert{
+		{name: "apiserver", cfg: apiserverCfg},
+		{
+			name: "apiserver-kubelet-client",
+			cfg: certutil.Config{
+				CommonName:   "kube-apiserver-kubelet-client",
+				Organization: []string{"system:masters"},
+				Usages:       []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+			},
+		},
+	}
+	for _, cert := range certs {
+		if !renew && !missing("pki/"+cert.name+".crt") {
+			continue
+		}
+
+		s.Logger.Infof("Issuing the %s certificate...", cert.name)
+		issued, certErr := certificate.NewCert(cert.cfg, s.Cluster.CertificateValidity(), issuer)
+		if certErr != nil {
+			return nil, errors.Wrapf(certErr, "failed to issue the %s certificate", cert.name)
+		}
+		files["pki/"+cert.name+".crt"] = []byte(issued[resources.TLSCertName])
+		files["pki/"+cert.name+".key"] = []byte(issued[resources.TLSKeyName])
+	}
+
+	kubeconfigs := []externalCAKubeconfig{
+		{name: "admin.conf", user: "kubernetes-admin", groups: []string{"system:masters"}},
+		{name: "controller-manager.conf", user: "system:kube-controller-manager"},
+		{name: "scheduler.conf", user: "system:kube-scheduler"},
+	}
+	// The followers get the kubelet client certificate using the TLS bootstrap,
+	// and the certificate is rotated by kubelet, like with kubeadm certs renew
+	if node.IsLeader && !renew {
+		kubeconfigs = append(kubeconfigs, externalCAKubeconfig{
+			name:   "kubelet.conf",
+			user:   "system:node:" + node.Hostname,
+			groups: []string{"system:nodes"},
+		})
+	}
+	server := "https://" + net.JoinHostPort(s.Cluster.APIEndpoint.Host, strconv.Itoa(s.Cluster.APIEndpoint.Port))
+	for _, kc := range kubeconfigs {
+		if !renew && !missing(kc.name) {
+			continue
+		}
+
+		s.Logger.Infof("Issuing the %s kubeconfig...", kc.name)
+		clientCert, certErr := certificate.NewClientCert(kc.user, kc.groups, s.Cluster.CertificateValidity(), issuer)
+		if certErr != nil {
+			return nil, errors.Wrapf(certErr, "failed to issue the %s client certificate", kc.name)
+		}
+
+		konfig, certErr := kubeconfig.ClientCertificateKubeconfig(
+			server,
+			[]byte(clientCert[resources.KubernetesCACertName]),
+			s.Cluster.Name,
+			kc.user,
+			[]byte(clientCert[resources.TLSCertName]),
+			[]byte(clientCert[resources.TLSKeyName]),
+		)
+		if certErr != nil {
+			return nil, certErr
+		}
+		files[kc.name] = konfig
+	}
+
+	return files, nil
+}
+
+// apiserverCertConfig returns the configuration of the API server serving
+// certificate, with the same SANs as the certificate generated by kubeadm
+func apiserverCertConfig(cluster *kubeoneapi.KubeOneCluster, node kubeoneapi.HostConfig) (certutil.Config, error) {
+	altNames := certutil.AltNames{
+		DNSNames: []string{
+			node.Hostname,
+			"kubernetes",
+			"kubernetes.default",
+			"kubernetes.default.svc",
+			"kubernetes.default.svc." + cluster.ClusterNetwork.ServiceDomainName,
+		},
+		IPs: []net.IP{net.ParseIP(kubeadmconfig.NodeIP(node))},
+	}
+
+	for _, subnet := range strings.Split(cluster.ClusterNetwork.ServiceSubnet, ",") {
+		_, serviceNet, err := net.ParseCIDR(strings.TrimSpace(subnet))
+		if err != nil {
+			return certutil.Config{}, errors.Wrapf(err, "failed to parse the service subnet %q", subnet)
+		}
+
+		serviceIP, err := utilnet.GetIndexedIP(serviceNet, 1)
+		if err != nil {
+			return certutil.Config{}, errors.Wrap(err, "failed to get the kubernetes service IP")
+		}
+		altNames.IPs = append(altNames.IPs, serviceIP)
+	}
+
+	for _, san := range certificate.GetCertificateSANs(cluster.APIEndpoint.Host, cluster.APIEndpoint.AlternativeNames) {
+		if ip := net.ParseIP(san); ip != nil {
+			altNames.IPs = append(altNames.IPs, ip)
+		} else {
+			altNames.DNSNames = append(altNames.DNSNames, san)
+		}
+	}
+
+	return certutil.Config{
+		CommonName: "kube-apiserver",
+		AltNames:   altNames,
+		Usages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, nil
+}
+
+// withKubeletCSRSigner runs the task while issuing the kubelet client
+// certificates requested by the joining hosts. Without the CA key,
+// kube-controller-manager approves the certificate signing requests, but
+// it doesn't sign them.
+func withKubeletCSRSigner(fn func(*state.State) error) func(*state.State) error {
+	return func(s *state.State) error {
+		if !s.Cluster.ExternalCASignerEnabled() {
+			return fn(s)
+		}
+
+		issuer, err := certificate.KubernetesIssuer(s.Context, s.Cluster, s.Configuration, s.Cluster.KubeadmKeyAlgorithm())
+		if err != nil {
+			return err
+		}
+
+		if s.RESTConfig == nil {
+			return errors.New("kubernetes client not initialized")
+		}
+		clientset, err := kubernetes.NewForConfig(s.RESTConfig)
+		if err != nil {
+			return errors.Wrap(err, "failed to create kubernetes clientset")
+		}
+
+		// Certificate signing requests created by kubelet rotating its
+		// certificate are signed as well
+		if err = signKubeletCSRs(s.Context, s, clientset, issuer); err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithCancel(s.Context)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			wait.UntilWithContext(ctx, func(ctx context.Context) {
+				if signErr := signKubeletCSRs(ctx, s, clientset, issuer); signErr != nil {
+					s.Logger.Warnf("Failed to sign the kubelet certificate signing requests: %v", signErr)
+				}
+			}, kubeletCSRSignInterval)
+		}()
+
+		err = fn(s)
+		cancel()
+		<-done
+
+		return err
+	}
+}
+
+// signKubeletCSRs issues the certificates of the approved kubelet client
+// certificate signing requests
+func signKubeletCSRs(ctx context.Context, s *state.State, clientset kubernetes.Interface, issuer certificate.Issuer) error {
+	csrClient := clientset.CertificatesV1().CertificateSigningRequests()
+
+	csrs, err := csrClient.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list certificate signing requests")
+	}
+
+	for i := range csrs.Items {
+		csr := &csrs.Items[i]
+		if csr.Spec.SignerName != certificatesv1.KubeAPIServerClientKubeletSignerName || len(csr.Status.Certificate) > 0 || !csrApproved(csr) {
+			continue
+		}
+
+		s.Logger.Infof("Issuing the kubelet client certificate for %q...", csr.Spec.Username)
+		cert, err := issuer.IssueCSR(csr.Spec.Request, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, s.Cluster.CertificateValidity())
+		if err != nil {
+			return errors.Wrapf(err, "failed to issue the certificate for %q", csr.Name)
+		}
+
+		csr.Status.Certificate = pem.EncodeToMemory(&pem.Block{Type: certutil.CertificateBlockType, Bytes: cert.Raw})
+		if _, err = csrClient.UpdateStatus(ctx, csr, metav1.UpdateOptions{}); err != nil {
+			return errors.Wrapf(err, "failed to update the certificate signing request %q", csr.Name)
+		}
+	}
+
+	return nil
+}
+
+func csrApproved(csr *certificatesv1.CertificateSigningRequest) bool {
+	approved := false
+	for _, cond := range csr.Status.Conditions {
+		switch cond.Type {
+		case certificatesv1.CertificateDenied, certificatesv1.CertificateFailed:
+			return false
+		case certificatesv1.CertificateApproved:
+			approved = true
+		}
+	}
+
+	return approved
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+)
+
+func TestAPIServerCertConfig(t *testing.T) {
+	cluster := &kubeoneapi.KubeOneCluster{
+		APIEndpoint: kubeoneapi.APIEndpoint{
+			Host:             "API.example.com",
+			AlternativeNames: []string{"192.0.2.100", "api.internal"},
+		},
+		ClusterNetwork: kubeoneapi.ClusterNetworkConfig{
+			ServiceSubnet:     "10.96.0.0/12,fd00::/108",
+			ServiceDomainName: "cluster.local",
+		},
+	}
+	node := kubeoneapi.HostConfig{Hostname: "cp-0", PublicAddress: "192.0.2.10", PrivateAddress: "10.0.0.10"}
+
+	cfg, err := apiserverCertConfig(cluster, node)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedDNSNames := []string{
+		"cp-0",
+		"kubernetes",
+		"kubernetes.default",
+		"kubernetes.default.svc",
+		"kubernetes.default.svc.cluster.local",
+		"api.example.com",
+		"api.internal",
+	}
+	if !reflect.DeepEqual(cfg.AltNames.DNSNames, expectedDNSNames) {
+		t.Errorf("expected DNS names %v, got %v", expectedDNSNames, cfg.AltNames.DNSNames)
+	}
+
+	expectedIPs := []string{"10.0.0.10", "10.96.0.1", "fd00::1", "192.0.2.100"}
+	if len(cfg.AltNames.IPs) != len(expectedIPs) {
+		t.Fatalf("expected IPs %v, got %v", expectedIPs, cfg.AltNames.IPs)
+	}
+	for i, ip := range expectedIPs {
+		if !cfg.AltNames.IPs[i].Equal(net.ParseIP(ip)) {
+			t.Errorf("expected IPs %v, got %v", expectedIPs, cfg.AltNames.IPs)
+		}
+	}
+}
+
+func TestCSRApproved(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []certificatesv1.CertificateSigningRequestCondition
+		expected   bool
+	}{
+		{
+			name:     "pending",
+			expected: false,
+		},
+		{
+			name:       "approved",
+			conditions: []certificatesv1.CertificateSigningRequestCondition{{Type: certificatesv1.CertificateApproved}},
+			expected:   true,
+		},
+		{
+			name: "approved and failed",
+			conditions: []certificatesv1.CertificateSigningRequestCondition{
+				{Type: certificatesv1.CertificateApproved},
+				{Type: certificatesv1.CertificateFailed},
+			},
+			expected: false,
+		},
+		{
+			name:       "denied",
+			conditions: []certificatesv1.CertificateSigningRequestCondition{{Type: certificatesv1.CertificateDenied}},
+			expected:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			csr := &certificatesv1.CertificateSigningRequest{
+				Status: certificatesv1.CertificateSigningRequestStatus{Conditions: tt.conditions},
+			}
+			if got := csrApproved(csr); got != tt.expected {
+				t.Errorf("csrApproved() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
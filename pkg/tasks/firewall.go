@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/scripts"
+	"k8c.io/kubeone/pkg/ssh"
+	"k8c.io/kubeone/pkg/state"
+)
+
+// ensureFirewall creates the firewall rules on all hosts. The rules are
+// recreated on every apply, so the changes to the node CIDRs are applied.
+func ensureFirewall(s *state.State) error {
+	s.Logger.Infoln("Ensuring firewall rules...")
+
+	return s.RunTaskOnAllNodes(applyFirewall, state.RunParallel)
+}
+
+func applyFirewall(s *state.State, node *kubeoneapi.HostConfig, _ ssh.Connection) error {
+	publicRules, rules := firewallRules(s.Cluster, isControlPlaneHost(s.Cluster, *node))
+
+	sources := append([]string{}, s.Cluster.Firewall.NodeCIDRs...)
+	if s.Cluster.ClusterNetwork.PodSubnet != "" {
+		sources = append(sources, s.Cluster.ClusterNetwork.PodSubnet)
+	}
+
+	cmd, err := scripts.Firewall(s.Cluster.Firewall.Backend, sources, publicRules, rules)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = s.Runner.RunRaw(cmd)
+
+	return errors.WithStack(err)
+}
+
+// firewallRules returns the rules allowed from everywhere, and the rules
+// allowed only from the nodes and the pods, of the host
+func firewallRules(cluster *kubeoneapi.KubeOneCluster, controlPlane bool) ([]scripts.FirewallRule, []scripts.FirewallRule) {
+	publicRules := []scripts.FirewallRule{}
+	rules := []scripts.FirewallRule{
+		{Protocol: "tcp", Port: "10250"},
+	}
+
+	if controlPlane {
+		publicRules = append(publicRules, scripts.FirewallRule{Protocol: "tcp", Port: "6443"})
+		rules = append(rules, scripts.FirewallRule{Protocol: "tcp", Port: "2379-2380"})
+	}
+
+	if nodePorts := cluster.ClusterNetwork.NodePortRange; nodePorts != "" {
+		rules = append(rules,
+			scripts.FirewallRule{Protocol: "tcp", Port: nodePorts},
+			scripts.FirewallRule{Protocol: "udp", Port: nodePorts},
+		)
+	}
+
+	// metrics-server in the host network is reached by the API server on
+	// any node
+	if ms := cluster.Features.MetricsServer; ms != nil && ms.Enable && ms.HostNetwork {
+		rules = append(rules, scripts.FirewallRule{Protocol: "tcp", Port: "4443"})
+	}
+
+	// node-exporter listens in the host network of every node
+	if m := cluster.Features.Monitoring; m != nil && m.Enable {
+		rules = append(rules, scripts.FirewallRule{Protocol: "tcp", Port: "9100"})
+	}
+
+	cni := cluster.ClusterNetwork.CNI
+	switch {
+	case cni == nil:
+	case cni.Canal != nil:
+		// flannel VXLAN
+		rules = append(rules, scripts.FirewallRule{Protocol: "udp", Port: "8472"})
+	case cni.Cilium != nil:
+		// VXLAN, health checks and Hubble
+		rules = append(rules,
+			scripts.FirewallRule{Protocol: "udp", Port: "8472"},
+			scripts.FirewallRule{Protocol: "tcp", Port: "4240"},
+			scripts.FirewallRule{Protocol: "tcp", Port: "4244"},
+		)
+	case cni.WeaveNet != nil:
+		// control and data plane
+		rules = append(rules,
+			scripts.FirewallRule{Protocol: "tcp", Port: "6783"},
+			scripts.FirewallRule{Protocol: "udp", Port: "6783-6784"},
+		)
+	}
+
+	return publicRules, rules
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+	"testing"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/scripts"
+)
+
+func Test_firewallRules(t *testing.T) {
+	tests := []struct {
+		name            string
+		cni             *kubeoneapi.CNI
+		metricsServer   *kubeoneapi.MetricsServer
+		monitoring      *kubeoneapi.Monitoring
+		controlPlane    bool
+		wantPublicRules []scripts.FirewallRule
+		wantRules       []scripts.FirewallRule
+	}{
+		{
+			name:            "control plane with canal",
+			cni:             &kubeoneapi.CNI{Canal: &kubeoneapi.CanalSpec{}},
+			controlPlane:    true,
+			wantPublicRules: []scripts.FirewallRule{{Protocol: "tcp", Port: "6443"}},
+			wantRules: []scripts.FirewallRule{
+				{Protocol: "tcp", Port: "10250"},
+				{Protocol: "tcp", Port: "2379-2380"},
+				{Protocol: "tcp", Port: "30000-32767"},
+				{Protocol: "udp", Port: "30000-32767"},
+				{Protocol: "udp", Port: "8472"},
+			},
+		},
+		{
+			name:            "static worker with weave-net",
+			cni:             &kubeoneapi.CNI{WeaveNet: &kubeoneapi.WeaveNetSpec{}},
+			wantPublicRules: []scripts.FirewallRule{},
+			wantRules: []scripts.FirewallRule{
+				{Protocol: "tcp", Port: "10250"},
+				{Protocol: "tcp", Port: "30000-32767"},
+				{Protocol: "udp", Port: "30000-32767"},
+				{Protocol: "tcp", Port: "6783"},
+				{Protocol: "udp", Port: "6783-6784"},
+			},
+		},
+		{
+			name:            "static worker with metrics-server in host network",
+			cni:             &kubeoneapi.CNI{External: &kubeoneapi.ExternalCNISpec{}},
+			metricsServer:   &kubeoneapi.MetricsServer{Enable: true, HostNetwork: true},
+			wantPublicRules: []scripts.FirewallRule{},
+			wantRules: []scripts.FirewallRule{
+				{Protocol: "tcp", Port: "10250"},
+				{Protocol: "tcp", Port: "30000-32767"},
+				{Protocol: "udp", Port: "30000-32767"},
+				{Protocol: "tcp", Port: "4443"},
+			},
+		},
+		{
+			name:            "control plane with monitoring",
+			cni:             &kubeoneapi.CNI{External: &kubeoneapi.ExternalCNISpec{}},
+			monitoring:      &kubeoneapi.Monitoring{Enable: true},
+			controlPlane:    true,
+			wantPublicRules: []scripts.FirewallRule{{Protocol: "tcp", Port: "6443"}},
+			wantRules: []scripts.FirewallRule{
+				{Protocol: "tcp", Port: "10250"},
+				{Protocol: "tcp", Port: "2379-2380"},
+				{Protocol: "tcp", Port: "30000-32767"},
+				{Protocol: "udp", Port: "30000-32767"},
+				{Protocol: "tcp", Port: "9100"},
+			},
+		},
+		{
+			name:            "static worker with external cni",
+			cni:             &kubeoneapi.CNI{External: &kubeoneapi.ExternalCNISpec{}},
+			wantPublicRules: []scripts.FirewallRule{},
+			wantRules: []scripts.FirewallRule{
+				{Protocol: "tcp", Port: "10250"},
+				{Protocol: "tcp", Port: "30000-32767"},
+				{Protocol: "udp", Port: "30000-32767"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := &kubeoneapi.KubeOneCluster{
+				ClusterNetwork: kubeoneapi.ClusterNetworkConfig{
+					NodePortRange: "30000-32767",
+					CNI:           tt.cni,
+				},
+				Features: kubeoneapi.Features{
+					MetricsServer: tt.metricsServer,
+					Monitoring:    tt.monitoring,
+				},
+			}
+
+			publicRules, rules := firewallRules(cluster, tt.controlPlane)
+			if !reflect.DeepEqual(publicRules, tt.wantPublicRules) {
+				t.Errorf("firewallRules() publicRules = %v, want %v", publicRules, tt.wantPublicRules)
+			}
+			if !reflect.DeepEqual(rules, tt.wantRules) {
+				t.Errorf("firewallRules() rules = %v, want %v", rules, tt.wantRules)
+			}
+		})
+	}
+}
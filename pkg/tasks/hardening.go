@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/hardening"
+	"k8c.io/kubeone/pkg/scripts"
+	"k8c.io/kubeone/pkg/ssh"
+	"k8c.io/kubeone/pkg/state"
+)
+
+// ensureCISHardening applies the host settings and restricts the permissions
+// of the Kubernetes files of the CIS hardening profile on all hosts. The
+// files are rewritten by kubeadm on upgrades, so this runs on every apply.
+func ensureCISHardening(s *state.State) error {
+	s.Logger.Infoln("Ensuring CIS hardening...")
+
+	return s.RunTaskOnAllNodes(func(s *state.State, node *kubeoneapi.HostConfig, _ ssh.Connection) error {
+		if err := applyCISHost(s, *node); err != nil {
+			return err
+		}
+
+		cmd, err := scripts.CISFilePermissions(hardening.FilePermissions(isControlPlaneHost(s.Cluster, *node)))
+		if err != nil {
+			return err
+		}
+
+		_, _, err = s.Runner.RunRaw(cmd)
+
+		return errors.WithStack(err)
+	}, state.RunParallel)
+}
+
+// applyCISHost installs auditd and its rules, and the sysctl settings
+// required by kubelet with protectKernelDefaults
+func applyCISHost(s *state.State, node kubeoneapi.HostConfig) error {
+	runScript := func(script func(map[string]string, []string) (string, error)) runOnOSFn {
+		return func(s *state.State) error {
+			cmd, err := script(hardening.KernelSysctls, hardening.AuditWatches)
+			if err != nil {
+				return err
+			}
+
+			_, _, err = s.Runner.RunRaw(cmd)
+
+			return errors.WithStack(err)
+		}
+	}
+
+	return runOnOS(s, node.OperatingSystem, map[kubeoneapi.OperatingSystemName]runOnOSFn{
+		kubeoneapi.OperatingSystemNameAmazon:  runScript(scripts.CISHostCentOS),
+		kubeoneapi.OperatingSystemNameCentOS:  runScript(scripts.CISHostCentOS),
+		kubeoneapi.OperatingSystemNameDebian:  runScript(scripts.CISHostDebian),
+		kubeoneapi.OperatingSystemNameFlatcar: runScript(scripts.CISHostFlatcar),
+		kubeoneapi.OperatingSystemNameRHEL:    runScript(scripts.CISHostCentOS),
+		kubeoneapi.OperatingSystemNameUbuntu:  runScript(scripts.CISHostDebian),
+	})
+}
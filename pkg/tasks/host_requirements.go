@@ -0,0 +1,102 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/capacity"
+	"k8c.io/kubeone/pkg/scripts"
+	"k8c.io/kubeone/pkg/ssh"
+	"k8c.io/kubeone/pkg/state"
+)
+
+// hostRequirementsTask returns the task checking the control plane hosts,
+// if the cluster size is declared in the manifest
+func hostRequirementsTask() Task {
+	return Task{
+		Fn:          checkHostRequirements,
+		ErrMsg:      "control plane hosts requirements check failed",
+		Description: "check control plane hosts meet the requirements of the cluster size",
+		Predicate: func(s *state.State) bool {
+			return s.Cluster.ClusterSize != nil && !s.Cluster.PreflightSkipped(kubeoneapi.PreflightCheckResources)
+		},
+	}
+}
+
+// checkHostRequirements verifies the control plane hosts have enough
+// resources for the cluster of the size declared in the manifest. Unmet
+// requirements fail the task only if they're enforced.
+func checkHostRequirements(s *state.State) error {
+	size := s.Cluster.ClusterSize
+	req := capacity.ForClusterSize(size.Nodes, size.Pods)
+
+	s.Logger.Infof("Checking control plane hosts for the cluster of %d nodes and %d pods...", size.Nodes, size.Pods)
+	s.Logger.Debugf("Required: %d CPUs, %d MiB memory, %d GiB etcd disk, %d etcd disk synchronous writes per second",
+		req.CPUs, req.MemoryMiB, req.EtcdDiskGiB, req.EtcdDiskSyncIOPS)
+
+	var lock sync.Mutex
+	unmet := map[string][]string{}
+
+	err := s.RunTaskOnControlPlane(func(s *state.State, node *kubeoneapi.HostConfig, _ ssh.Connection) error {
+		stdout, _, err := s.Runner.Run(scripts.HostResources(), nil)
+		if err != nil {
+			return err
+		}
+
+		res, err := capacity.ParseResources(stdout)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse host resources")
+		}
+
+		if hostUnmet := capacity.Check(req, res); len(hostUnmet) > 0 {
+			lock.Lock()
+			unmet[node.PublicAddress] = hostUnmet
+			lock.Unlock()
+		}
+
+		return nil
+	}, state.RunParallel)
+	if err != nil {
+		return err
+	}
+
+	if len(unmet) == 0 {
+		return nil
+	}
+
+	hosts := []string{}
+	for host := range unmet {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	for _, host := range hosts {
+		s.Logger.Warnf("Control plane host %s doesn't meet the requirements: %s", host, strings.Join(unmet[host], "; "))
+	}
+
+	if size.Enforce && !s.Cluster.PreflightWarnOnly(kubeoneapi.PreflightCheckResources) {
+		return nonRetryable(errors.Errorf("%d control plane host(s) are undersized for the cluster of %d nodes and %d pods", len(hosts), size.Nodes, size.Pods))
+	}
+
+	return nil
+}
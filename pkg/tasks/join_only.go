@@ -0,0 +1,218 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/certificate"
+	"k8c.io/kubeone/pkg/kubeconfig"
+	"k8c.io/kubeone/pkg/state"
+)
+
+// WithJoinOnly provisions and joins only the nodes selected by
+// JoinOnlyHosts, without touching the nodes already in the cluster, except
+// for creating the bootstrap token on the leader
+func WithJoinOnly(t Tasks) Tasks {
+	return WithHostnameOSAndProbes(t).
+		append(
+			Task{Fn: installPrerequisitesOnJoinOnlyHosts, ErrMsg: "failed to install prerequisites"},
+			Task{Fn: rebootJoinOnlyHostsIfRequired, ErrMsg: "failed to reboot hosts"},
+		).
+		append(kubernetesConfigFilesForJoinOnly()...).
+		append(Tasks{
+			{
+				Fn: func(s *state.State) error {
+					s.Logger.Info("Downloading PKI...")
+					return s.RunTaskOnLeader(certificate.DownloadKubePKI)
+				},
+				ErrMsg:    "failed to download Kubernetes PKI from the leader",
+				Predicate: joinOnlyHasControlPlane,
+			},
+			{
+				Fn: func(s *state.State) error {
+					s.Logger.Info("Uploading PKI...")
+					return runOnJoinOnlyControlPlane(s, certificate.UploadKubePKI, state.RunParallel)
+				},
+				ErrMsg:    "failed to upload Kubernetes PKI",
+				Predicate: joinOnlyHasControlPlane,
+			},
+			{
+				Fn: func(s *state.State) error {
+					return runOnJoinOnlyControlPlane(s, installExternalCAOnNode, state.RunSequentially)
+				},
+				ErrMsg: "failed to install the external CA",
+				Predicate: func(s *state.State) bool {
+					return joinOnlyHasControlPlane(s) && s.Cluster.ExternalCA() != nil
+				},
+			},
+			{
+				Fn: func(s *state.State) error {
+					s.Logger.Infoln("Configuring certs and etcd on new control plane nodes...")
+					return runOnJoinOnlyControlPlane(s, kubeadmCertsExecutor, state.RunParallel)
+				},
+				ErrMsg:    "failed to provision certs and etcd on new control plane nodes",
+				Predicate: joinOnlyHasControlPlane,
+			},
+			{Fn: initKubernetesLeader, ErrMsg: "failed to create the bootstrap token on leader"},
+			{Fn: kubeconfig.BuildKubernetesClientset, ErrMsg: "failed to build kubernetes clientset"},
+			{Fn: repairClusterIfNeeded, ErrMsg: "failed to repair cluster", Predicate: joinOnlyHasControlPlane},
+			{
+				Fn: withKubeletCSRSigner(func(s *state.State) error {
+					s.Logger.Infoln("Joining new control plane nodes...")
+					return runOnJoinOnlyControlPlane(s, joinControlPlaneNodeInternal, state.RunSequentially)
+				}),
+				ErrMsg:    "failed to join control plane nodes to the cluster",
+				Predicate: joinOnlyHasControlPlane,
+			},
+			{
+				Fn: withKubeletCSRSigner(func(s *state.State) error {
+					_, workers, err := JoinOnlyHosts(s)
+					if err != nil {
+						return err
+					}
+
+					return s.RunTaskOnNodes(workers, joinStaticWorkerInternal, state.RunParallel)
+				}),
+				ErrMsg: "failed to join worker nodes to the cluster",
+			},
+			{Fn: labelNodeOSes, ErrMsg: "failed to label nodes with their OS"},
+		}...)
+}
+
+// JoinOnlyHosts returns the control plane and static worker hosts selected
+// by JoinOnlyHosts. Each of them must be defined in the manifest and must not
+// be a part of the cluster yet.
+func JoinOnlyHosts(s *state.State) ([]kubeoneapi.HostConfig, []kubeoneapi.HostConfig, error) {
+	if len(s.JoinOnlyHosts) == 0 {
+		return nil, nil, errors.New("no hosts to join")
+	}
+
+	inCluster := map[int]bool{}
+	for _, h := range append(append([]state.Host{}, s.LiveCluster.ControlPlane...), s.LiveCluster.StaticWorkers...) {
+		inCluster[h.Config.ID] = h.IsInCluster
+	}
+
+	var controlPlane, workers []kubeoneapi.HostConfig
+	for _, name := range s.JoinOnlyHosts {
+		host, isControlPlane, found := FindHost(s.Cluster, name)
+		if !found {
+			return nil, nil, errors.Errorf("host %q is not defined in the manifest", name)
+		}
+		if inCluster[host.ID] {
+			return nil, nil, errors.Errorf("host %q is already a part of the cluster", name)
+		}
+		if host.IsLeader {
+			return nil, nil, errors.Errorf("host %q is the leader, the cluster must be provisioned using 'kubeone apply'", name)
+		}
+
+		if isControlPlane {
+			controlPlane = append(controlPlane, host)
+		} else {
+			workers = append(workers, host)
+		}
+	}
+
+	return controlPlane, workers, nil
+}
+
+// FindHost finds the control plane or static worker host by its hostname,
+// public or private address. It returns whether the host is a control plane
+// host and whether the host is found.
+func FindHost(cluster *kubeoneapi.KubeOneCluster, name string) (kubeoneapi.HostConfig, bool, bool) {
+	matches := func(h kubeoneapi.HostConfig) bool {
+		return name != "" && (h.Hostname == name || h.PublicAddress == name || h.PrivateAddress == name)
+	}
+
+	for _, h := range cluster.ControlPlane.Hosts {
+		if matches(h) {
+			return h, true, true
+		}
+	}
+	for _, h := range cluster.StaticWorkers.Hosts {
+		if matches(h) {
+			return h, false, true
+		}
+	}
+
+	return kubeoneapi.HostConfig{}, false, false
+}
+
+func joinOnlyHostConfigs(s *state.State) ([]kubeoneapi.HostConfig, error) {
+	controlPlane, workers, err := JoinOnlyHosts(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(controlPlane, workers...), nil
+}
+
+func joinOnlyHasControlPlane(s *state.State) bool {
+	controlPlane, _, err := JoinOnlyHosts(s)
+
+	return err == nil && len(controlPlane) > 0
+}
+
+func runOnJoinOnlyControlPlane(s *state.State, task state.NodeTask, mode state.RunModeEnum) error {
+	controlPlane, _, err := JoinOnlyHosts(s)
+	if err != nil {
+		return err
+	}
+
+	return s.RunTaskOnNodes(controlPlane, task, mode)
+}
+
+func kubernetesConfigFilesForJoinOnly() Tasks {
+	return Tasks{
+		{Fn: generateKubeadm, ErrMsg: "failed to generate kubeadm config files"},
+		{Fn: generateConfigurationFiles, ErrMsg: "failed to generate config files"},
+		{
+			Fn: func(s *state.State) error {
+				hosts, err := joinOnlyHostConfigs(s)
+				if err != nil {
+					return err
+				}
+
+				return s.RunTaskOnNodes(hosts, uploadConfigurationFilesToNode, state.RunParallel)
+			},
+			ErrMsg: "failed to upload config files",
+		},
+	}
+}
+
+func installPrerequisitesOnJoinOnlyHosts(s *state.State) error {
+	s.Logger.Infoln("Installing prerequisites...")
+
+	hosts, err := joinOnlyHostConfigs(s)
+	if err != nil {
+		return err
+	}
+
+	return s.RunTaskOnNodes(hosts, installPrerequisitesOnNode, state.RunParallel)
+}
+
+func rebootJoinOnlyHostsIfRequired(s *state.State) error {
+	s.Logger.Infoln("Checking if hosts require reboot...")
+
+	hosts, err := joinOnlyHostConfigs(s)
+	if err != nil {
+		return err
+	}
+
+	return s.RunTaskOnNodes(hosts, rebootHostIfRequired, state.RunSequentially)
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+	"testing"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/state"
+)
+
+func TestJoinOnlyHosts(t *testing.T) {
+	cluster := &kubeoneapi.KubeOneCluster{
+		ControlPlane: kubeoneapi.ControlPlaneConfig{
+			Hosts: []kubeoneapi.HostConfig{
+				{ID: 0, Hostname: "cp-0", IsLeader: true},
+				{ID: 1, Hostname: "cp-1", PrivateAddress: "10.0.0.1"},
+			},
+		},
+		StaticWorkers: kubeoneapi.StaticWorkersConfig{
+			Hosts: []kubeoneapi.HostConfig{
+				{ID: 2, Hostname: "worker-0"},
+				{ID: 3, Hostname: "worker-1", PublicAddress: "1.1.1.1"},
+			},
+		},
+	}
+
+	liveCluster := &state.Cluster{
+		ControlPlane: []state.Host{
+			{Config: &cluster.ControlPlane.Hosts[0], IsInCluster: true},
+			{Config: &cluster.ControlPlane.Hosts[1]},
+		},
+		StaticWorkers: []state.Host{
+			{Config: &cluster.StaticWorkers.Hosts[0], IsInCluster: true},
+			{Config: &cluster.StaticWorkers.Hosts[1]},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		hosts            []string
+		wantControlPlane []string
+		wantWorkers      []string
+		wantErr          bool
+	}{
+		{
+			name:             "new nodes by address",
+			hosts:            []string{"10.0.0.1", "1.1.1.1"},
+			wantControlPlane: []string{"cp-1"},
+			wantWorkers:      []string{"worker-1"},
+		},
+		{
+			name:        "new worker by hostname",
+			hosts:       []string{"worker-1"},
+			wantWorkers: []string{"worker-1"},
+		},
+		{
+			name:    "node already in cluster",
+			hosts:   []string{"worker-0"},
+			wantErr: true,
+		},
+		{
+			name:    "leader",
+			hosts:   []string{"cp-0"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown node",
+			hosts:   []string{"worker-2"},
+			wantErr: true,
+		},
+		{
+			name:    "no nodes",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			s := &state.State{
+				Cluster:       cluster,
+				LiveCluster:   liveCluster,
+				JoinOnlyHosts: tt.hosts,
+			}
+
+			controlPlane, workers, err := JoinOnlyHosts(s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("JoinOnlyHosts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got := hostnames(controlPlane); !reflect.DeepEqual(got, nonNil(tt.wantControlPlane)) {
+				t.Errorf("JoinOnlyHosts() control plane = %v, want %v", got, tt.wantControlPlane)
+			}
+			if got := hostnames(workers); !reflect.DeepEqual(got, nonNil(tt.wantWorkers)) {
+				t.Errorf("JoinOnlyHosts() workers = %v, want %v", got, tt.wantWorkers)
+			}
+		})
+	}
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+
+	return s
+}
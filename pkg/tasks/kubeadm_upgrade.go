@@ -17,20 +17,24 @@ limitations under the License.
 package tasks
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/pkg/errors"
 
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
 	"k8c.io/kubeone/pkg/scripts"
 	"k8c.io/kubeone/pkg/state"
 	"k8c.io/kubeone/pkg/templates/kubeadm"
 )
 
-func upgradeLeaderControlPlane(s *state.State) error {
+func upgradeLeaderControlPlane(s *state.State, node kubeoneapi.HostConfig) error {
 	kadm, err := kubeadm.New(s.Cluster.Versions.Kubernetes)
 	if err != nil {
 		return errors.Wrap(err, "failed to init kubeadm")
 	}
 
-	cmd, err := scripts.KubeadmUpgradeLeader(kadm.UpgradeLeaderCommand(), s.WorkDir)
+	cmd, err := scripts.KubeadmUpgradeLeader(withIgnorePreflightErrors(s, node, kadm.UpgradeLeaderCommand()), s.WorkDir)
 	if err != nil {
 		return err
 	}
@@ -40,22 +44,33 @@ func upgradeLeaderControlPlane(s *state.State) error {
 	return err
 }
 
-func upgradeFollowerControlPlane(s *state.State) error {
+func upgradeFollowerControlPlane(s *state.State, node kubeoneapi.HostConfig) error {
 	kadm, err := kubeadm.New(s.Cluster.Versions.Kubernetes)
 	if err != nil {
 		return errors.Wrap(err, "failed to init kubadm")
 	}
 
-	_, _, err = s.Runner.Run(`sudo `+kadm.UpgradeFollowerCommand(), nil)
+	_, _, err = s.Runner.Run(`sudo `+withIgnorePreflightErrors(s, node, kadm.UpgradeFollowerCommand()), nil)
 	return err
 }
 
-func upgradeStaticWorker(s *state.State) error {
+func upgradeStaticWorker(s *state.State, node kubeoneapi.HostConfig) error {
 	kadm, err := kubeadm.New(s.Cluster.Versions.Kubernetes)
 	if err != nil {
 		return errors.Wrap(err, "failed to init kubadm")
 	}
 
-	_, _, err = s.Runner.Run(`sudo `+kadm.UpgradeStaticWorkerCommand(), nil)
+	_, _, err = s.Runner.Run(`sudo `+withIgnorePreflightErrors(s, node, kadm.UpgradeStaticWorkerCommand()), nil)
 	return err
 }
+
+// withIgnorePreflightErrors adds the preflight errors ignored on the given
+// node to the 'kubeadm upgrade' command
+func withIgnorePreflightErrors(s *state.State, node kubeoneapi.HostConfig, kubeadmCmd string) string {
+	ignored := s.Cluster.IgnorePreflightErrorsConfig(node).Upgrade
+	if len(ignored) == 0 {
+		return kubeadmCmd
+	}
+
+	return fmt.Sprintf("%s --ignore-preflight-errors=%s", kubeadmCmd, strings.Join(ignored, ","))
+}
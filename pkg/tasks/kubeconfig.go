@@ -18,10 +18,10 @@ package tasks
 
 import (
 	"fmt"
-	"io/ioutil"
 
 	"github.com/pkg/errors"
 
+	"k8c.io/kubeone/pkg/filelock"
 	"k8c.io/kubeone/pkg/kubeconfig"
 	"k8c.io/kubeone/pkg/state"
 )
@@ -34,7 +34,21 @@ func saveKubeconfig(s *state.State) error {
 		return err
 	}
 
+	if kc, err = s.EncryptArtifact(kc); err != nil {
+		return err
+	}
+
 	fileName := fmt.Sprintf("%s-kubeconfig", s.Cluster.Name)
-	err = ioutil.WriteFile(fileName, kc, 0600)
-	return errors.Wrap(err, "error saving kubeconfig file to the local machine")
+	if err = filelock.WriteFile(fileName, kc, 0600); err != nil {
+		return errors.Wrap(err, "error saving kubeconfig file to the local machine")
+	}
+
+	return s.PersistArtifact(fileName, kc)
+}
+
+// persistKubernetesVersion records the Kubernetes version the cluster is
+// reconciled to, which is useful if .versions.kubernetes is pinned to the
+// latest patch release, e.g. 1.21.x
+func persistKubernetesVersion(s *state.State) error {
+	return s.PersistArtifact(fmt.Sprintf("%s-kubernetes-version", s.Cluster.Name), []byte(s.Cluster.Versions.Kubernetes+"\n"))
 }
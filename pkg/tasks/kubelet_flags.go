@@ -0,0 +1,109 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/scripts"
+	"k8c.io/kubeone/pkg/ssh"
+	"k8c.io/kubeone/pkg/state"
+	"k8c.io/kubeone/pkg/templates/kubeadm/kubeadmconfig"
+)
+
+const kubeletRestartTimeout = 2 * time.Minute
+
+// ensureKubeletFlags reconciles the kubelet flags set from the host kubelet
+// configuration on the hosts already joined to the cluster. kubeadm writes
+// the flags only when the host is joined, so the changed configuration is
+// written to the kubeadm flags file and kubelet is restarted.
+func ensureKubeletFlags(s *state.State) error {
+	hosts := []kubeoneapi.HostConfig{}
+	for _, h := range append(append([]state.Host{}, s.LiveCluster.ControlPlane...), s.LiveCluster.StaticWorkers...) {
+		if h.Initialized() {
+			hosts = append(hosts, *h.Config)
+		}
+	}
+
+	if len(hosts) == 0 {
+		return nil
+	}
+
+	s.Logger.Infoln("Ensuring kubelet flags...")
+
+	// Kubelet is restarted on one host at a time
+	return s.RunTaskOnNodes(hosts, reconcileKubeletFlags, state.RunSequentially)
+}
+
+func reconcileKubeletFlags(s *state.State, node *kubeoneapi.HostConfig, conn ssh.Connection) error {
+	changed := false
+	err := updateRemoteFile(s, kubeadmEnvFlagsFile, func(content []byte) ([]byte, error) {
+		kubeletFlags, err := unmarshalKubeletFlags(bytes.TrimSpace(content))
+		if err != nil {
+			return nil, err
+		}
+
+		if changed = updateHostKubeletFlags(kubeletFlags, node.Kubelet); !changed {
+			return content, nil
+		}
+
+		return marshalKubeletFlags(kubeletFlags), nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to update kubelet flags")
+	}
+
+	if !changed {
+		return nil
+	}
+
+	s.Logger.Info("Restarting kubelet to apply the changed flags...")
+	if _, _, err = s.Runner.RunRaw(scripts.RestartKubelet()); err != nil {
+		return err
+	}
+
+	return errors.Wrapf(waitForKubeletReady(conn, kubeletRestartTimeout), "kubelet failed to start in %s", kubeletRestartTimeout)
+}
+
+// updateHostKubeletFlags sets the flags to the values configured by the host
+// kubelet configuration, and returns whether any flag was changed. The flags
+// removed from the configuration are removed.
+func updateHostKubeletFlags(kubeletFlags map[string]string, kubelet *kubeoneapi.KubeletConfig) bool {
+	names, desired := kubeadmconfig.HostKubeletFlags(kubelet)
+
+	changed := false
+	for _, name := range names {
+		flag := "--" + name
+		current, found := kubeletFlags[flag]
+		value, configured := desired[name]
+
+		switch {
+		case configured && (!found || current != value):
+			kubeletFlags[flag] = value
+			changed = true
+		case !configured && found:
+			delete(kubeletFlags, flag)
+			changed = true
+		}
+	}
+
+	return changed
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+	"testing"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+)
+
+func TestUpdateHostKubeletFlags(t *testing.T) {
+	maxPods := int32(110)
+
+	tests := []struct {
+		name        string
+		flags       map[string]string
+		kubelet     *kubeoneapi.KubeletConfig
+		wantFlags   map[string]string
+		wantChanged bool
+	}{
+		{
+			name:        "no configuration",
+			flags:       map[string]string{"--node-ip": "10.0.0.20"},
+			wantFlags:   map[string]string{"--node-ip": "10.0.0.20"},
+			wantChanged: false,
+		},
+		{
+			name:  "flags added",
+			flags: map[string]string{"--node-ip": "10.0.0.20"},
+			kubelet: &kubeoneapi.KubeletConfig{
+				MaxPods:      &maxPods,
+				EvictionHard: map[string]string{"memory.available": "100Mi"},
+			},
+			wantFlags: map[string]string{
+				"--node-ip":       "10.0.0.20",
+				"--max-pods":      "110",
+				"--eviction-hard": "memory.available<100Mi",
+			},
+			wantChanged: true,
+		},
+		{
+			name: "flags unchanged",
+			flags: map[string]string{
+				"--node-ip":         "10.0.0.20",
+				"--system-reserved": "cpu=200m,memory=500Mi",
+			},
+			kubelet: &kubeoneapi.KubeletConfig{
+				SystemReserved: map[string]string{"memory": "500Mi", "cpu": "200m"},
+			},
+			wantFlags: map[string]string{
+				"--node-ip":         "10.0.0.20",
+				"--system-reserved": "cpu=200m,memory=500Mi",
+			},
+			wantChanged: false,
+		},
+		{
+			name: "flags changed and removed",
+			flags: map[string]string{
+				"--node-ip":       "10.0.0.20",
+				"--max-pods":      "200",
+				"--kube-reserved": "memory=500Mi",
+			},
+			kubelet: &kubeoneapi.KubeletConfig{
+				MaxPods: &maxPods,
+			},
+			wantFlags: map[string]string{
+				"--node-ip":  "10.0.0.20",
+				"--max-pods": "110",
+			},
+			wantChanged: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			changed := updateHostKubeletFlags(tt.flags, tt.kubelet)
+			if changed != tt.wantChanged {
+				t.Errorf("updateHostKubeletFlags() = %v, want %v", changed, tt.wantChanged)
+			}
+			if !reflect.DeepEqual(tt.flags, tt.wantFlags) {
+				t.Errorf("flags = %v, want %v", tt.flags, tt.wantFlags)
+			}
+		})
+	}
+}
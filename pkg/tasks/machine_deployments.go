@@ -17,6 +17,10 @@ limitations under the License.
 package tasks
 
 import (
+	"context"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
 	"github.com/pkg/errors"
 
 	"k8c.io/kubeone/pkg/state"
@@ -24,7 +28,9 @@ import (
 
 	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
 	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -34,10 +40,23 @@ func createMachineDeployments(s *state.State) error {
 		return nil
 	}
 
+	if s.Cluster.CloudProvider.Hetzner != nil {
+		if err := machinecontroller.EnsureHetznerPlacementGroups(s); err != nil {
+			return errors.Wrap(err, "failed to ensure placement groups")
+		}
+	}
+
 	s.Logger.Infoln("Creating worker machines...")
 	return errors.Wrap(machinecontroller.CreateMachineDeployments(s), "failed to deploy Machines")
 }
 
+const (
+	// machineDeploymentsRolloutTimeout is how long to wait for
+	// machine-controller to replace the Machines of the upgraded
+	// MachineDeployments
+	machineDeploymentsRolloutTimeout = 30 * time.Minute
+)
+
 func upgradeMachineDeployments(s *state.State) error {
 	if !s.UpgradeMachineDeployments {
 		s.Logger.Info("Upgrade MachineDeployments skip per lack of flag...")
@@ -76,3 +95,65 @@ func upgradeMachineDeployments(s *state.State) error {
 
 	return nil
 }
+
+// WaitMachineDeploymentsUpgraded waits until machine-controller has rolled out
+// all MachineDeployments and all the Nodes of the Machines run kubelet of the
+// requested Kubernetes version
+func WaitMachineDeploymentsUpgraded(s *state.State) error {
+	version, err := semver.NewVersion(s.Cluster.Versions.Kubernetes)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse the kubernetes version")
+	}
+
+	s.Logger.Infof("Waiting for the MachineDeployments to be upgraded to %s...", s.Cluster.Versions.Kubernetes)
+	err = wait.PollImmediate(5*time.Second, machineDeploymentsRolloutTimeout, machineDeploymentsUpgradedCondition(s.Context, s.DynamicClient, version))
+
+	return errors.Wrap(err, "failed to wait for the MachineDeployments to be upgraded")
+}
+
+// machineDeploymentsUpgradedCondition returns true once every
+// MachineDeployment has all replicas updated and every Node of the Machines
+// reports the given kubelet version
+func machineDeploymentsUpgradedCondition(ctx context.Context, c dynclient.Client, version *semver.Version) wait.ConditionFunc {
+	return func() (bool, error) {
+		machineDeployments := clusterv1alpha1.MachineDeploymentList{}
+		if err := c.List(ctx, &machineDeployments, dynclient.InNamespace(metav1.NamespaceSystem)); err != nil {
+			return false, errors.Wrap(err, "failed to list MachineDeployments")
+		}
+
+		for _, md := range machineDeployments.Items {
+			replicas := int32(1)
+			if md.Spec.Replicas != nil {
+				replicas = *md.Spec.Replicas
+			}
+			if md.Status.ObservedGeneration < md.Generation ||
+				md.Status.UpdatedReplicas != replicas ||
+				md.Status.Replicas != replicas {
+				return false, nil
+			}
+		}
+
+		machines := clusterv1alpha1.MachineList{}
+		if err := c.List(ctx, &machines, dynclient.InNamespace(metav1.NamespaceSystem)); err != nil {
+			return false, errors.Wrap(err, "failed to list Machines")
+		}
+
+		for _, machine := range machines.Items {
+			if machine.Status.NodeRef == nil {
+				return false, nil
+			}
+
+			node := corev1.Node{}
+			if err := c.Get(ctx, dynclient.ObjectKey{Name: machine.Status.NodeRef.Name}, &node); err != nil {
+				return false, errors.Wrapf(err, "failed to get Node %q", machine.Status.NodeRef.Name)
+			}
+
+			kubeletVersion, err := semver.NewVersion(node.Status.NodeInfo.KubeletVersion)
+			if err != nil || !kubeletVersion.Equal(version) {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMachineDeploymentsUpgradedCondition(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := clusterv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	replicas := int32(1)
+	machineDeployment := func(updatedReplicas, currentReplicas int32) *clusterv1alpha1.MachineDeployment {
+		return &clusterv1alpha1.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "workers", Namespace: metav1.NamespaceSystem},
+			Spec:       clusterv1alpha1.MachineDeploymentSpec{Replicas: &replicas},
+			Status: clusterv1alpha1.MachineDeploymentStatus{
+				Replicas:        currentReplicas,
+				UpdatedReplicas: updatedReplicas,
+			},
+		}
+	}
+	machine := &clusterv1alpha1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Name: "workers-1", Namespace: metav1.NamespaceSystem},
+		Status: clusterv1alpha1.MachineStatus{
+			NodeRef: &corev1.ObjectReference{Name: "worker-1"},
+		},
+	}
+	node := func(kubeletVersion string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker-1"},
+			Status: corev1.NodeStatus{
+				NodeInfo: corev1.NodeSystemInfo{KubeletVersion: kubeletVersion},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		objects []dynclient.Object
+		want    bool
+	}{
+		{
+			name:    "rolled out",
+			objects: []dynclient.Object{machineDeployment(1, 1), machine, node("v1.22.5")},
+			want:    true,
+		},
+		{
+			name:    "replicas not updated",
+			objects: []dynclient.Object{machineDeployment(0, 1), machine, node("v1.22.5")},
+			want:    false,
+		},
+		{
+			name:    "old replicas not removed",
+			objects: []dynclient.Object{machineDeployment(1, 2), machine, node("v1.22.5")},
+			want:    false,
+		},
+		{
+			name:    "node runs old kubelet",
+			objects: []dynclient.Object{machineDeployment(1, 1), machine, node("v1.21.8")},
+			want:    false,
+		},
+		{
+			name:    "machine without node",
+			objects: []dynclient.Object{machineDeployment(1, 1), &clusterv1alpha1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "workers-2", Namespace: metav1.NamespaceSystem}}},
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build()
+
+			got, err := machineDeploymentsUpgradedCondition(context.Background(), c, semver.MustParse("1.22.5"))()
+			if err != nil {
+				t.Fatalf("machineDeploymentsUpgradedCondition() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("machineDeploymentsUpgradedCondition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
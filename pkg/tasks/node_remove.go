@@ -0,0 +1,170 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"net/url"
+
+	"github.com/pkg/errors"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/clientutil"
+	"k8c.io/kubeone/pkg/etcdutil"
+	"k8c.io/kubeone/pkg/kubeconfig"
+	"k8c.io/kubeone/pkg/state"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WithRemoveNode drains the node, removes its etcd member if it's a control
+// plane node, resets it and deletes its Node object
+func WithRemoveNode(t Tasks, host kubeoneapi.HostConfig, controlPlane bool) Tasks {
+	hosts := []kubeoneapi.HostConfig{host}
+
+	return t.append(Tasks{
+		{Fn: kubeconfig.BuildKubernetesClientset, ErrMsg: "failed to build kubernetes clientset"},
+		{
+			Fn: func(s *state.State) error {
+				return s.RunTaskOnNodes(hosts, drainNode, state.RunSequentially)
+			},
+			ErrMsg:      "failed to drain node",
+			Description: "cordon and drain the node",
+		},
+		{
+			Fn: func(s *state.State) error {
+				return removeEtcdMember(s, host)
+			},
+			ErrMsg:      "failed to remove etcd member",
+			Description: "remove the etcd member",
+			Predicate:   func(s *state.State) bool { return controlPlane },
+		},
+		{
+			Fn: func(s *state.State) error {
+				return s.RunTaskOnNodes(hosts, resetNode, state.RunSequentially)
+			},
+			ErrMsg:      "failed to reset node",
+			Description: "reset the node using kubeadm",
+		},
+		{
+			Fn: func(s *state.State) error {
+				return deleteNodeObject(s, host.Hostname)
+			},
+			ErrMsg:      "failed to delete Node object",
+			Description: "delete the Node object",
+		},
+	}...)
+}
+
+// cleanupReplacedHosts removes the stale etcd members and Node objects of the
+// hosts whose machines were recreated, so they can be joined again
+func cleanupReplacedHosts(s *state.State) error {
+	for _, host := range s.LiveCluster.ReplacedHosts() {
+		s.Logger.Infof("Cleaning up the recreated host %q...", host.Config.Hostname)
+
+		if isControlPlaneHost(s.Cluster, *host.Config) {
+			if err := removeEtcdMember(s, *host.Config); err != nil {
+				return errors.Wrapf(err, "failed to remove etcd member of host %q", host.Config.Hostname)
+			}
+		}
+
+		if err := deleteNodeObject(s, host.Config.Hostname); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isControlPlaneHost(cluster *kubeoneapi.KubeOneCluster, host kubeoneapi.HostConfig) bool {
+	for _, h := range cluster.ControlPlane.Hosts {
+		if h.ID == host.ID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func deleteNodeObject(s *state.State, name string) error {
+	s.Logger.Infof("Deleting Node object %q...", name)
+
+	return clientutil.DeleteIfExists(s.Context, s.DynamicClient, &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+	})
+}
+
+// removeEtcdMember removes the etcd member of the control plane host, using
+// the leader to connect to etcd
+func removeEtcdMember(s *state.State, host kubeoneapi.HostConfig) error {
+	leader, err := s.Cluster.Leader()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	etcdcfg, err := etcdutil.NewClientConfig(s, leader)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	etcdcli, err := clientv3.New(*etcdcfg)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer etcdcli.Close()
+
+	members, err := etcdcli.MemberList(s.Context)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	for _, member := range members.Members {
+		if !isEtcdMemberOf(member.Name, member.PeerURLs, host) {
+			continue
+		}
+
+		s.Logger.Infof("Removing etcd member %q...", member.Name)
+		if _, err = etcdcli.MemberRemove(s.Context, member.ID); err != nil {
+			return errors.WithStack(err)
+		}
+
+		return nil
+	}
+
+	s.Logger.Warnf("No etcd member found for the host %q", host.Hostname)
+
+	return nil
+}
+
+func isEtcdMemberOf(name string, peerURLs []string, host kubeoneapi.HostConfig) bool {
+	if name != "" && name == host.Hostname {
+		return true
+	}
+
+	for _, peerURL := range peerURLs {
+		u, err := url.Parse(peerURL)
+		if err != nil {
+			continue
+		}
+		if h := u.Hostname(); h == host.PrivateAddress || h == host.PublicAddress {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+)
+
+func Test_isEtcdMemberOf(t *testing.T) {
+	host := kubeoneapi.HostConfig{
+		Hostname:       "cp-1",
+		PublicAddress:  "1.1.1.1",
+		PrivateAddress: "10.0.0.1",
+	}
+
+	tests := []struct {
+		name     string
+		member   string
+		peerURLs []string
+		want     bool
+	}{
+		{
+			name:   "matching name",
+			member: "cp-1",
+			want:   true,
+		},
+		{
+			name:     "matching private address",
+			member:   "other",
+			peerURLs: []string{"https://10.0.0.1:2380"},
+			want:     true,
+		},
+		{
+			name:     "different member",
+			member:   "cp-2",
+			peerURLs: []string{"https://10.0.0.2:2380"},
+			want:     false,
+		},
+		{
+			name:     "unnamed member without matching peer",
+			peerURLs: []string{"https://10.0.0.2:2380"},
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isEtcdMemberOf(tt.member, tt.peerURLs, host); got != tt.want {
+				t.Errorf("isEtcdMemberOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
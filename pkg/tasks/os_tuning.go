@@ -0,0 +1,43 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/scripts"
+	"k8c.io/kubeone/pkg/ssh"
+	"k8c.io/kubeone/pkg/state"
+)
+
+// ensureOSTuning saves and applies the configured kernel modules and sysctl
+// settings on all hosts
+func ensureOSTuning(s *state.State) error {
+	s.Logger.Infoln("Ensuring kernel modules and sysctl settings...")
+
+	return s.RunTaskOnAllNodes(applyOSTuning, state.RunParallel)
+}
+
+func applyOSTuning(s *state.State, node *kubeoneapi.HostConfig, _ ssh.Connection) error {
+	cmd, err := scripts.OSTuning(s.Cluster.HostOSTuning(isControlPlaneHost(s.Cluster, *node)))
+	if err != nil {
+		return err
+	}
+
+	_, _, err = s.Runner.RunRaw(cmd)
+
+	return err
+}
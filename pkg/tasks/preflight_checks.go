@@ -25,6 +25,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
 	"k8c.io/kubeone/pkg/clusterstatus/preflightstatus"
 	"k8c.io/kubeone/pkg/state"
 
@@ -56,13 +57,25 @@ func runPreflightChecks(s *state.State) error {
 		return errors.Wrap(err, "unable to verify prerequisites")
 	}
 
-	s.Logger.Infoln("Verifying is it possible to upgrade to the desired version...")
-	if err := verifyVersion(s.Logger, s.Cluster.Versions.Kubernetes, &nodes, s.Verbose, s.ForceUpgrade); err != nil {
-		return errors.Wrap(err, "unable to verify components version")
+	if s.Cluster.PreflightSkipped(kubeoneapi.PreflightCheckVersion) {
+		s.Logger.Warnf("Skipping the %s preflight check.", kubeoneapi.PreflightCheckVersion)
+	} else {
+		s.Logger.Infoln("Verifying is it possible to upgrade to the desired version...")
+		if err := verifyVersion(s.Logger, s.Cluster.Versions.Kubernetes, &nodes, s.Verbose, s.ForceUpgrade); err != nil {
+			if !s.Cluster.PreflightWarnOnly(kubeoneapi.PreflightCheckVersion) {
+				return errors.Wrap(err, "unable to verify components version")
+			}
+			s.Logger.Warningf("version check failed: %v", err)
+		}
+	}
+
+	if s.Cluster.PreflightSkipped(kubeoneapi.PreflightCheckVersionSkew) {
+		s.Logger.Warnf("Skipping the %s preflight check.", kubeoneapi.PreflightCheckVersionSkew)
+		return nil
 	}
 
 	if canPass, err := verifyVersionSkew(s, &nodes, s.Verbose); err != nil {
-		if s.ForceUpgrade && canPass {
+		if (s.ForceUpgrade && canPass) || s.Cluster.PreflightWarnOnly(kubeoneapi.PreflightCheckVersionSkew) {
 			s.Logger.Warningf("version skew check failed: %v", err)
 		} else {
 			return errors.Wrap(err, "version skew check failed")
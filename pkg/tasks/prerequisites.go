@@ -94,8 +94,50 @@ func installPrerequisitesOnNode(s *state.State, node *kubeoneapi.HostConfig, con
 		return errors.Wrap(err, "failed to configure proxy for docker daemon")
 	}
 
+	logger.Infoln("Applying kernel modules and sysctl settings...")
+	if err := applyOSTuning(s, node, conn); err != nil {
+		return errors.Wrap(err, "failed to apply kernel modules and sysctl settings")
+	}
+
+	if s.Cluster.ChronyEnabled() {
+		logger.Infoln("Installing and configuring time synchronization...")
+		if err := installTimeSync(s, *node); err != nil {
+			return errors.Wrap(err, "failed to install time synchronization")
+		}
+	}
+
+	logger.Infoln("Configuring SELinux and AppArmor...")
+	if err := configureSecurityModules(s, *node); err != nil {
+		return errors.Wrap(err, "failed to configure SELinux and AppArmor")
+	}
+
+	if s.Cluster.FirewallEnabled() {
+		logger.Infoln("Creating firewall rules...")
+		if err := applyFirewall(s, node, conn); err != nil {
+			return errors.Wrap(err, "failed to create firewall rules")
+		}
+	}
+
 	logger.Infoln("Installing kubeadm...")
-	return errors.Wrap(installKubeadm(s, *node), "failed to install kubeadm")
+	if err := installKubeadm(s, *node); err != nil {
+		return errors.Wrap(err, "failed to install kubeadm")
+	}
+
+	// kubelet with protectKernelDefaults fails to start without the sysctl
+	// settings, so they're applied before the node is joined
+	if s.Cluster.CISHardeningEnabled() {
+		logger.Infoln("Applying CIS hardening host settings...")
+		if err := applyCISHost(s, *node); err != nil {
+			return errors.Wrap(err, "failed to apply CIS hardening host settings")
+		}
+	}
+
+	if !node.NvidiaGPU {
+		return nil
+	}
+
+	logger.Infoln("Installing NVIDIA driver and container toolkit...")
+	return errors.Wrap(installNvidiaGPU(s, *node), "failed to install NVIDIA driver and container toolkit")
 }
 
 func createEnvironmentFile(s *state.State) error {
@@ -120,6 +162,126 @@ func installKubeadm(s *state.State, node kubeoneapi.HostConfig) error {
 	})
 }
 
+func installTimeSync(s *state.State, node kubeoneapi.HostConfig) error {
+	return runOnOS(s, node.OperatingSystem, map[kubeoneapi.OperatingSystemName]runOnOSFn{
+		kubeoneapi.OperatingSystemNameAmazon:  installChronyCentOS,
+		kubeoneapi.OperatingSystemNameCentOS:  installChronyCentOS,
+		kubeoneapi.OperatingSystemNameDebian:  installChronyDebian,
+		kubeoneapi.OperatingSystemNameFlatcar: configureTimesyncdFlatcar,
+		kubeoneapi.OperatingSystemNameRHEL:    installChronyCentOS,
+		kubeoneapi.OperatingSystemNameUbuntu:  installChronyDebian,
+	})
+}
+
+func installChronyDebian(s *state.State) error {
+	cmd, err := scripts.ChronyDebian(s.Cluster)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = s.Runner.RunRaw(cmd)
+
+	return errors.WithStack(err)
+}
+
+func installChronyCentOS(s *state.State) error {
+	cmd, err := scripts.ChronyCentOS(s.Cluster)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = s.Runner.RunRaw(cmd)
+
+	return errors.WithStack(err)
+}
+
+func configureTimesyncdFlatcar(s *state.State) error {
+	cmd, err := scripts.TimesyncdFlatcar(s.Cluster)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = s.Runner.RunRaw(cmd)
+
+	return errors.WithStack(err)
+}
+
+func configureSecurityModules(s *state.State, node kubeoneapi.HostConfig) error {
+	return runOnOS(s, node.OperatingSystem, map[kubeoneapi.OperatingSystemName]runOnOSFn{
+		kubeoneapi.OperatingSystemNameAmazon:  configureSELinuxCentOS,
+		kubeoneapi.OperatingSystemNameCentOS:  configureSELinuxCentOS,
+		kubeoneapi.OperatingSystemNameDebian:  configureAppArmorDebian,
+		kubeoneapi.OperatingSystemNameFlatcar: func(*state.State) error { return nil },
+		kubeoneapi.OperatingSystemNameRHEL:    configureSELinuxCentOS,
+		kubeoneapi.OperatingSystemNameUbuntu:  configureAppArmorDebian,
+	})
+}
+
+// configureSELinuxCentOS prepares the host for running containers with
+// SELinux in enforcing mode. In permissive mode SELinux is switched to
+// permissive by the kubeadm installation script.
+func configureSELinuxCentOS(s *state.State) error {
+	if s.Cluster.SELinuxMode() != kubeoneapi.SELinuxModeEnforcing {
+		return nil
+	}
+
+	cmd, err := scripts.SELinuxEnforcingCentOS()
+	if err != nil {
+		return err
+	}
+
+	_, _, err = s.Runner.RunRaw(cmd)
+
+	return errors.WithStack(err)
+}
+
+func configureAppArmorDebian(s *state.State) error {
+	if s.Cluster.AppArmorMode() != kubeoneapi.AppArmorModeConfigure {
+		return nil
+	}
+
+	cmd, err := scripts.AppArmorDebian()
+	if err != nil {
+		return err
+	}
+
+	_, _, err = s.Runner.RunRaw(cmd)
+
+	return errors.WithStack(err)
+}
+
+func installNvidiaGPU(s *state.State, node kubeoneapi.HostConfig) error {
+	return runOnOS(s, node.OperatingSystem, map[kubeoneapi.OperatingSystemName]runOnOSFn{
+		kubeoneapi.OperatingSystemNameAmazon: installNvidiaGPUCentOS,
+		kubeoneapi.OperatingSystemNameCentOS: installNvidiaGPUCentOS,
+		kubeoneapi.OperatingSystemNameDebian: installNvidiaGPUDebian,
+		kubeoneapi.OperatingSystemNameRHEL:   installNvidiaGPUCentOS,
+		kubeoneapi.OperatingSystemNameUbuntu: installNvidiaGPUDebian,
+	})
+}
+
+func installNvidiaGPUDebian(s *state.State) error {
+	cmd, err := scripts.NvidiaGPUDebian(s.Cluster)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = s.Runner.RunRaw(cmd)
+
+	return errors.WithStack(err)
+}
+
+func installNvidiaGPUCentOS(s *state.State) error {
+	cmd, err := scripts.NvidiaGPUCentOS(s.Cluster)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = s.Runner.RunRaw(cmd)
+
+	return errors.WithStack(err)
+}
+
 func installKubeadmDebian(s *state.State) error {
 	cmd, err := scripts.KubeadmDebian(s.Cluster, s.ForceInstall)
 	if err != nil {
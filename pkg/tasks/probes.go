@@ -23,6 +23,7 @@ import (
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v2"
 
 	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
@@ -277,23 +278,36 @@ func investigateCluster(s *state.State) error {
 
 	s.Logger.Info("Electing cluster leader...")
 	s.LiveCluster.Lock.Lock()
+
+	// The leader defined in the manifest (or using the --leader flag) is
+	// preferred, as long as its API server is healthy
+	preferredLeader := -1
 	for i := range s.LiveCluster.ControlPlane {
+		if s.LiveCluster.ControlPlane[i].Config.IsLeader {
+			preferredLeader = i
+		}
 		s.LiveCluster.ControlPlane[i].Config.IsLeader = false
 	}
 
-	leaderElected := false
+	leader := -1
 	for i := range s.LiveCluster.ControlPlane {
 		apiserverStatus, _ := apiserverstatus.Get(s, *s.LiveCluster.ControlPlane[i].Config)
 		if apiserverStatus != nil && apiserverStatus.Health {
 			s.LiveCluster.ControlPlane[i].APIServer.Status |= state.PodRunning
-			if !leaderElected {
-				s.LiveCluster.ControlPlane[i].Config.IsLeader = true
-				leaderElected = true
-				s.Logger.Infof("Elected leader %q...", s.LiveCluster.ControlPlane[i].Config.Hostname)
+			if leader < 0 || i == preferredLeader {
+				leader = i
 			}
 		}
 	}
-	if !leaderElected {
+	if leader >= 0 {
+		s.LiveCluster.ControlPlane[leader].Config.IsLeader = true
+		if preferredLeader >= 0 && preferredLeader != leader {
+			s.Logger.Warnf("Leader %q is not healthy, falling back to the next healthy control plane host",
+				s.LiveCluster.ControlPlane[preferredLeader].Config.Hostname)
+		}
+		s.Logger.Infof("Elected leader %q...", s.LiveCluster.ControlPlane[leader].Config.Hostname)
+	}
+	if leader < 0 {
 		s.Logger.Errorln("Failed to elect leader.")
 		s.Logger.Errorln("Quorum is mostly like lost, manual cluster repair might be needed.")
 		s.Logger.Errorln("Consider the KubeOne documentation for further steps.")
@@ -362,7 +376,10 @@ func investigateCluster(s *state.State) error {
 			}
 		}
 	}
+	detectReplacedHosts(s.LiveCluster.ControlPlane, s.Logger)
+	detectReplacedHosts(s.LiveCluster.StaticWorkers, s.Logger)
 	s.LiveCluster.Lock.Unlock()
+
 	encryptionEnabled, err := detectEncryptionProvidersEnabled(s)
 	if err != nil {
 		return errors.Wrap(err, "failed to check for EncryptionProviders")
@@ -390,6 +407,19 @@ func investigateCluster(s *state.State) error {
 	return nil
 }
 
+// detectReplacedHosts marks the hosts which have the Node object, but are not
+// initialized, as replaced. Machines of such hosts were recreated (e.g. by
+// the immutable infrastructure tooling), so they have to be joined again.
+func detectReplacedHosts(hosts []state.Host, logger logrus.FieldLogger) {
+	for i := range hosts {
+		if hosts[i].IsInCluster && !hosts[i].Initialized() {
+			logger.Warnf("Host %q has the Node object, but is not initialized, assuming the machine was recreated", hosts[i].Config.Hostname)
+			hosts[i].Replaced = true
+			hosts[i].IsInCluster = false
+		}
+	}
+}
+
 type systemdUnitInfoOpt func(component *state.ComponentStatus, conn ssh.Connection) error
 
 func systemdUnitInfo(name string, conn ssh.Connection, opts ...systemdUnitInfoOpt) (state.ComponentStatus, error) {
@@ -600,6 +630,9 @@ func detectCCMMigrationStatus(s *state.State) (*state.CCMStatus, error) {
 	ccmLabel := ""
 	ccmLabelValue := ""
 	switch {
+	case s.Cluster.CloudProvider.AWS != nil:
+		ccmLabel = "k8s-app"
+		ccmLabelValue = "aws-cloud-controller-manager"
 	case s.Cluster.CloudProvider.Openstack != nil:
 		ccmLabel = "k8s-app"
 		ccmLabelValue = "openstack-cloud-controller-manager"
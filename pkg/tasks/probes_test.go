@@ -0,0 +1,59 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/state"
+)
+
+func Test_detectReplacedHosts(t *testing.T) {
+	provisioned := state.ComponentStatus{Status: state.ComponentInstalled}
+	initialized := state.ComponentStatus{Status: state.ComponentInstalled | state.KubeletInitialized}
+
+	hosts := []state.Host{
+		{
+			Config:                     &kubeoneapi.HostConfig{Hostname: "initialized"},
+			ContainerRuntimeContainerd: provisioned,
+			Kubelet:                    initialized,
+			IsInCluster:                true,
+		},
+		{
+			Config:      &kubeoneapi.HostConfig{Hostname: "recreated"},
+			IsInCluster: true,
+		},
+		{
+			Config: &kubeoneapi.HostConfig{Hostname: "new"},
+		},
+	}
+
+	detectReplacedHosts(hosts, logrus.New())
+
+	want := map[string]bool{"initialized": false, "recreated": true, "new": false}
+	for _, host := range hosts {
+		if host.Replaced != want[host.Config.Hostname] {
+			t.Errorf("host %q: Replaced = %v, want %v", host.Config.Hostname, host.Replaced, want[host.Config.Hostname])
+		}
+		if host.Replaced && host.IsInCluster {
+			t.Errorf("host %q: replaced host must not be considered in the cluster", host.Config.Hostname)
+		}
+	}
+}
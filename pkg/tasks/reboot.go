@@ -0,0 +1,207 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/kubeconfig"
+	"k8c.io/kubeone/pkg/nodehealth"
+	"k8c.io/kubeone/pkg/nodeutils"
+	"k8c.io/kubeone/pkg/scripts"
+	"k8c.io/kubeone/pkg/ssh"
+	"k8c.io/kubeone/pkg/state"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultHostRebootTimeout = 10 * time.Minute
+
+	bootIDCommand = "cat /proc/sys/kernel/random/boot_id"
+)
+
+// rebootHostsIfRequired reboots the hosts which require reboot after the
+// prerequisites are installed. Hosts are rebooted one at a time, to keep
+// the etcd quorum and the workloads running.
+func rebootHostsIfRequired(s *state.State) error {
+	s.Logger.Infoln("Checking if hosts require reboot...")
+
+	return s.RunTaskOnAllNodes(rebootHostIfRequired, state.RunSequentially)
+}
+
+func rebootHostIfRequired(s *state.State, node *kubeoneapi.HostConfig, conn ssh.Connection) error {
+	cmd, err := scripts.RebootRequired()
+	if err != nil {
+		return err
+	}
+
+	stdout, _, err := s.Runner.RunRaw(cmd)
+	if err != nil {
+		return err
+	}
+
+	reasons := []string{}
+	for _, line := range strings.Split(stdout, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			reasons = append(reasons, line)
+		}
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+	reason := strings.Join(reasons, "; ")
+
+	if s.Cluster.HostReboot == nil || !s.Cluster.HostReboot.Enable {
+		s.Logger.Warnf("Host requires reboot (%s), reboot it manually or enable hostReboot in the KubeOneCluster manifest", reason)
+		return nil
+	}
+
+	s.Logger.Infof("Host requires reboot: %s", reason)
+
+	return rebootHost(s, node, conn)
+}
+
+// rebootHost reboots the host and waits for it to come back. If the host
+// is part of the cluster, it's drained before the reboot, and uncordoned
+// once it's ready again.
+func rebootHost(s *state.State, node *kubeoneapi.HostConfig, conn ssh.Connection) error {
+	timeout := defaultHostRebootTimeout
+	if s.Cluster.HostReboot.Timeout != nil {
+		timeout = s.Cluster.HostReboot.Timeout.Duration
+	}
+
+	joined := hostInCluster(s, node)
+	if joined && s.RESTConfig == nil {
+		if err := kubeconfig.BuildKubernetesClientset(s); err != nil {
+			return errors.Wrap(err, "failed to build kubernetes clientset")
+		}
+	}
+
+	var drainer nodeutils.Drainer
+	if joined {
+		drainer = nodeutils.NewDrainer(s.RESTConfig, s.Logger, s.Cluster.NodeDrainConfig(*node))
+
+		s.Logger.Infoln("Cordoning node...")
+		if err := drainer.Cordon(s.Context, node.Hostname, true); err != nil {
+			return errors.Wrap(err, "failed to cordon node")
+		}
+
+		s.Logger.Infoln("Draining node...")
+		if err := drainer.Drain(s.Context, node.Hostname); err != nil {
+			return errors.Wrap(err, "failed to drain node")
+		}
+	}
+
+	bootID, _, _, err := conn.Exec(bootIDCommand)
+	if err != nil {
+		return errors.Wrap(err, "failed to get boot ID")
+	}
+
+	s.Logger.Infoln("Rebooting host...")
+	if _, _, err = s.Runner.RunRaw(scripts.Reboot()); err != nil {
+		return errors.Wrap(err, "failed to reboot host")
+	}
+
+	// Closing the connection removes it from the connector, so the host is
+	// connected to again after the reboot
+	conn.Close()
+
+	s.Logger.Infof("Waiting up to %s for host to come back...", timeout)
+	conn, err = waitForReboot(s, *node, bootID, timeout)
+	if err != nil {
+		return errors.Wrapf(err, "host didn't come back in %s", timeout)
+	}
+	s.Runner.Conn = conn
+
+	if !joined {
+		return nil
+	}
+
+	s.Logger.Infof("Waiting up to %s for node to become ready...", timeout)
+	if err = waitForKubeletReady(conn, timeout); err != nil {
+		return errors.Wrapf(err, "kubelet failed to start in %s", timeout)
+	}
+	if err = waitForNodeReady(s, node.Hostname, timeout); err != nil {
+		return errors.Wrapf(err, "node didn't become ready in %s", timeout)
+	}
+
+	s.Logger.Infoln("Uncordoning node...")
+
+	return errors.Wrap(drainer.Cordon(s.Context, node.Hostname, false), "failed to uncordon node")
+}
+
+// hostInCluster returns whether the host is joined to the cluster
+func hostInCluster(s *state.State, node *kubeoneapi.HostConfig) bool {
+	if s.LiveCluster == nil {
+		return false
+	}
+
+	for _, hosts := range [][]state.Host{s.LiveCluster.ControlPlane, s.LiveCluster.StaticWorkers} {
+		for _, host := range hosts {
+			if host.Config.PublicAddress == node.PublicAddress {
+				return host.IsInCluster
+			}
+		}
+	}
+
+	return false
+}
+
+// waitForReboot connects to the host until it's running with a new boot ID
+func waitForReboot(s *state.State, node kubeoneapi.HostConfig, bootID string, timeout time.Duration) (ssh.Connection, error) {
+	var conn ssh.Connection
+
+	err := wait.PollImmediate(5*time.Second, timeout, func() (bool, error) {
+		c, err := s.Connector.Connect(node)
+		if err != nil {
+			s.Logger.Debugf("Host is not reachable yet: %v", err)
+			return false, nil
+		}
+
+		newBootID, _, _, err := c.Exec(bootIDCommand)
+		if err != nil || newBootID == bootID {
+			// The host is shutting down
+			c.Close()
+			return false, nil
+		}
+
+		conn = c
+
+		return true, nil
+	})
+
+	return conn, err
+}
+
+func waitForNodeReady(s *state.State, nodeName string, timeout time.Duration) error {
+	return wait.PollImmediate(5*time.Second, timeout, func() (bool, error) {
+		node := corev1.Node{}
+		if err := s.DynamicClient.Get(s.Context, dynclient.ObjectKey{Name: nodeName}, &node); err != nil {
+			s.Logger.Debugf("Failed to get node: %v", err)
+			return false, nil
+		}
+
+		return nodehealth.NodeReady(node), nil
+	})
+}
@@ -17,11 +17,15 @@ limitations under the License.
 package tasks
 
 import (
+	"path/filepath"
+
 	"github.com/pkg/errors"
 
 	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
 	"k8c.io/kubeone/pkg/clientutil"
+	"k8c.io/kubeone/pkg/filelock"
 	"k8c.io/kubeone/pkg/kubeconfig"
+	"k8c.io/kubeone/pkg/nodeutils"
 	"k8c.io/kubeone/pkg/scripts"
 	"k8c.io/kubeone/pkg/ssh"
 	"k8c.io/kubeone/pkg/state"
@@ -63,8 +67,18 @@ func destroyWorkers(s *state.State) error {
 		return nil
 	}
 
+	if s.KeepMachineDeployments {
+		if lastErr = backupMachineDeployments(s); lastErr != nil {
+			return lastErr
+		}
+	}
+
 	_ = wait.ExponentialBackoff(defaultRetryBackoff(3), func() (bool, error) {
-		lastErr = machinecontroller.DestroyWorkers(s)
+		if s.KeepMachineDeployments {
+			lastErr = machinecontroller.ScaleDownWorkers(s)
+		} else {
+			lastErr = machinecontroller.DestroyWorkers(s)
+		}
 		if lastErr != nil {
 			s.Logger.Warn("Unable to destroy worker nodes. Retrying...")
 			return false, nil
@@ -90,6 +104,76 @@ func destroyWorkers(s *state.State) error {
 	return nil
 }
 
+// backupMachineDeployments saves MachineDeployments to a file before they're
+// scaled down and removed together with the cluster
+func backupMachineDeployments(s *state.State) error {
+	if !s.Cluster.MachineController.Deploy {
+		return nil
+	}
+
+	s.Logger.Infof("Saving MachineDeployments to %q...", s.MachineDeploymentsFile)
+
+	manifest, err := machinecontroller.ExportMachineDeployments(s)
+	if err != nil {
+		return errors.Wrap(err, "unable to export MachineDeployments")
+	}
+
+	if err = filelock.WriteFile(s.MachineDeploymentsFile, []byte(manifest), 0600); err != nil {
+		return errors.Wrap(err, "unable to save MachineDeployments")
+	}
+
+	return s.PersistArtifact(filepath.Base(s.MachineDeploymentsFile), []byte(manifest))
+}
+
+func drainAllNodes(s *state.State) error {
+	if s.RESTConfig == nil {
+		if err := kubeconfig.BuildKubernetesClientset(s); err != nil {
+			s.Logger.Warn("Unable to connect to the control plane API, skipping draining nodes")
+			return nil
+		}
+	}
+
+	s.Logger.Infoln("Draining all the nodes...")
+
+	// Static workers are drained first, so workloads are not moved to the
+	// other static workers, which are about to be drained as well
+	if err := s.RunTaskOnStaticWorkers(drainNode, state.RunSequentially); err != nil {
+		return err
+	}
+
+	return s.RunTaskOnControlPlane(drainNode, state.RunSequentially)
+}
+
+func drainControlPlane(s *state.State) error {
+	if s.RESTConfig == nil {
+		if err := kubeconfig.BuildKubernetesClientset(s); err != nil {
+			s.Logger.Warn("Unable to connect to the control plane API, skipping draining nodes")
+			return nil
+		}
+	}
+
+	s.Logger.Infoln("Draining the control plane nodes...")
+
+	return s.RunTaskOnControlPlane(drainNode, state.RunSequentially)
+}
+
+func drainNode(s *state.State, node *kubeoneapi.HostConfig, _ ssh.Connection) error {
+	logger := s.Logger.WithField("node", node.PublicAddress)
+	drainer := nodeutils.NewDrainer(s.RESTConfig, logger, s.Cluster.NodeDrainConfig(*node))
+
+	logger.Infoln("Cordoning node...")
+	if err := drainer.Cordon(s.Context, node.Hostname, true); err != nil {
+		return errors.Wrap(err, "failed to cordon node")
+	}
+
+	logger.Infoln("Draining node...")
+	if err := drainer.Drain(s.Context, node.Hostname); err != nil {
+		return errors.Wrap(err, "failed to drain node")
+	}
+
+	return nil
+}
+
 func resetAllNodes(s *state.State) error {
 	s.Logger.Infoln("Resettings all the nodes...")
 
@@ -137,7 +221,7 @@ func removeBinaries(s *state.State, node *kubeoneapi.HostConfig, conn ssh.Connec
 }
 
 func removeBinariesDebian(s *state.State) error {
-	cmd, err := scripts.RemoveBinariesDebian()
+	cmd, err := scripts.RemoveBinariesDebian(s.Cluster)
 	if err != nil {
 		return err
 	}
@@ -147,7 +231,7 @@ func removeBinariesDebian(s *state.State) error {
 }
 
 func removeBinariesCentOS(s *state.State) error {
-	cmd, err := scripts.RemoveBinariesCentOS()
+	cmd, err := scripts.RemoveBinariesCentOS(s.Cluster)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,163 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/clientutil"
+	"k8c.io/kubeone/pkg/kubeconfig"
+	"k8c.io/kubeone/pkg/state"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DeletableVolumes returns the dynamically provisioned PersistentVolumes,
+// whose cloud volumes are deleted by the provisioner once their claims are
+// deleted
+func DeletableVolumes(ctx context.Context, client dynclient.Client) ([]corev1.PersistentVolume, error) {
+	pvList := corev1.PersistentVolumeList{}
+	if err := client.List(ctx, &pvList); err != nil {
+		return nil, errors.Wrap(err, "unable to list PersistentVolumes")
+	}
+
+	return deletableVolumes(pvList.Items), nil
+}
+
+func deletableVolumes(pvs []corev1.PersistentVolume) []corev1.PersistentVolume {
+	deletable := []corev1.PersistentVolume{}
+	for _, pv := range pvs {
+		if _, ok := pv.Annotations[provisionedByAnnotation]; !ok {
+			continue
+		}
+		if pv.Spec.PersistentVolumeReclaimPolicy != corev1.PersistentVolumeReclaimDelete || pv.Spec.ClaimRef == nil {
+			continue
+		}
+
+		deletable = append(deletable, pv)
+	}
+
+	return deletable
+}
+
+// deleteVolumes deletes the claims of the dynamically provisioned volumes,
+// along with the workloads using them, and waits for the provisioners to
+// delete the cloud volumes. Otherwise, the cloud volumes are left behind
+// after the cluster is reset.
+func deleteVolumes(s *state.State) error {
+	if s.DynamicClient == nil {
+		if err := kubeconfig.BuildKubernetesClientset(s); err != nil {
+			s.Logger.Warn("Unable to connect to the control plane API and delete volumes")
+			s.Logger.Warn("You can skip deleting volumes and delete them manually using `--preserve-pvc-volumes`")
+			return errors.Wrap(err, "unable to build kubernetes clientset")
+		}
+	}
+
+	pvs, err := DeletableVolumes(s.Context, s.DynamicClient)
+	if err != nil {
+		return err
+	}
+	if len(pvs) == 0 {
+		return nil
+	}
+
+	s.Logger.Infoln("Deleting dynamically provisioned volumes...")
+
+	claims := map[string]sets.String{}
+	for _, pv := range pvs {
+		ns := pv.Spec.ClaimRef.Namespace
+		if claims[ns] == nil {
+			claims[ns] = sets.NewString()
+		}
+		claims[ns].Insert(pv.Spec.ClaimRef.Name)
+	}
+
+	for ns, names := range claims {
+		for _, name := range names.List() {
+			s.Logger.Infof("Deleting PersistentVolumeClaim %s/%s...", ns, name)
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+			}
+			if err = clientutil.DeleteIfExists(s.Context, s.DynamicClient, pvc); err != nil {
+				return err
+			}
+		}
+
+		// Claims are deleted only once they are not used by any pod
+		if err = deleteClaimUsers(s, ns, names); err != nil {
+			return err
+		}
+	}
+
+	err = wait.Poll(5*time.Second, 5*time.Minute, func() (bool, error) {
+		remaining, lErr := DeletableVolumes(s.Context, s.DynamicClient)
+		if lErr != nil {
+			return false, lErr
+		}
+
+		return len(remaining) == 0, nil
+	})
+
+	return errors.Wrap(err, "failed waiting for volumes to be deleted, use `--preserve-pvc-volumes` to skip deleting volumes")
+}
+
+// deleteClaimUsers deletes the pods using the claims. StatefulSets are
+// deleted instead of their pods, as they would recreate the claims.
+func deleteClaimUsers(s *state.State, namespace string, claims sets.String) error {
+	podList := corev1.PodList{}
+	if err := s.DynamicClient.List(s.Context, &podList, dynclient.InNamespace(namespace)); err != nil {
+		return errors.Wrapf(err, "unable to list pods in namespace %q", namespace)
+	}
+
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if !usesClaims(pod, claims) {
+			continue
+		}
+
+		var obj dynclient.Object = pod
+		if owner := metav1.GetControllerOf(pod); owner != nil && owner.Kind == "StatefulSet" {
+			obj = &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: owner.Name, Namespace: namespace},
+			}
+		}
+
+		if err := clientutil.DeleteIfExists(s.Context, s.DynamicClient, obj); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func usesClaims(pod *corev1.Pod, claims sets.String) bool {
+	for _, vol := range pod.Spec.Volumes {
+		if vol.PersistentVolumeClaim != nil && claims.Has(vol.PersistentVolumeClaim.ClaimName) {
+			return true
+		}
+	}
+
+	return false
+}
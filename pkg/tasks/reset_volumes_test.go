@@ -0,0 +1,82 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func Test_deletableVolumes(t *testing.T) {
+	newPV := func(name string, provisioned bool, policy corev1.PersistentVolumeReclaimPolicy, bound bool) corev1.PersistentVolume {
+		pv := corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeReclaimPolicy: policy,
+			},
+		}
+		if provisioned {
+			pv.Annotations = map[string]string{provisionedByAnnotation: "ebs.csi.aws.com"}
+		}
+		if bound {
+			pv.Spec.ClaimRef = &corev1.ObjectReference{Namespace: "default", Name: name}
+		}
+
+		return pv
+	}
+
+	pvs := []corev1.PersistentVolume{
+		newPV("dynamic", true, corev1.PersistentVolumeReclaimDelete, true),
+		newPV("retained", true, corev1.PersistentVolumeReclaimRetain, true),
+		newPV("static", false, corev1.PersistentVolumeReclaimDelete, true),
+		newPV("unbound", true, corev1.PersistentVolumeReclaimDelete, false),
+	}
+
+	got := deletableVolumes(pvs)
+	if len(got) != 1 || got[0].Name != "dynamic" {
+		t.Errorf("deletableVolumes() = %v, expected only the dynamic volume", got)
+	}
+}
+
+func Test_usesClaims(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Volumes: []corev1.Volume{
+				{
+					Name:         "config",
+					VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{}},
+				},
+				{
+					Name: "data",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data-0"},
+					},
+				},
+			},
+		},
+	}
+
+	if !usesClaims(pod, sets.NewString("data-0")) {
+		t.Errorf("expected the pod to use the data-0 claim")
+	}
+	if usesClaims(pod, sets.NewString("data-1")) {
+		t.Errorf("expected the pod not to use the data-1 claim")
+	}
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/kubeconfig"
+	"k8c.io/kubeone/pkg/nodehealth"
+	"k8c.io/kubeone/pkg/nodeutils"
+	"k8c.io/kubeone/pkg/scripts"
+	"k8c.io/kubeone/pkg/ssh"
+	"k8c.io/kubeone/pkg/state"
+)
+
+// RollbackNode is the control plane node with the rollback snapshot
+type RollbackNode struct {
+	Host kubeoneapi.HostConfig
+	// Version is the Kubernetes version the snapshot was taken on
+	Version string
+	// UpgradeInProgress is true if the upgrade of the node was started, but
+	// the upgraded node wasn't verified to be healthy
+	UpgradeInProgress bool
+}
+
+// snapshotControlPlaneNode saves the state of the control plane node, which
+// is restored by 'kubeone rollback' if the upgrade fails
+func snapshotControlPlaneNode(s *state.State) error {
+	cmd, err := scripts.RollbackSnapshot()
+	if err != nil {
+		return err
+	}
+
+	_, _, err = s.Runner.RunRaw(cmd)
+
+	return errors.Wrap(err, "failed to snapshot the control plane node")
+}
+
+// markControlPlaneNodeUpgraded marks the control plane node as upgraded, so
+// it's no longer rolled back by default
+func markControlPlaneNodeUpgraded(s *state.State) error {
+	cmd, err := scripts.RollbackMarkUpgraded()
+	if err != nil {
+		return err
+	}
+
+	_, _, err = s.Runner.RunRaw(cmd)
+
+	return errors.WithStack(err)
+}
+
+// RollbackNodes returns the control plane nodes with the rollback snapshot,
+// in the order of the hosts in the manifest
+func RollbackNodes(s *state.State) ([]RollbackNode, error) {
+	cmd, err := scripts.RollbackStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	var lock sync.Mutex
+	nodes := []RollbackNode{}
+
+	err = s.RunTaskOnControlPlane(func(s *state.State, node *kubeoneapi.HostConfig, _ ssh.Connection) error {
+		stdout, _, err := s.Runner.RunRaw(cmd)
+		if err != nil {
+			return err
+		}
+
+		status := parseRollbackStatus(stdout)
+		if status["version"] == "" {
+			return nil
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+		nodes = append(nodes, RollbackNode{
+			Host:              *node,
+			Version:           status["version"],
+			UpgradeInProgress: status["upgrade_in_progress"] == "true",
+		})
+
+		return nil
+	}, state.RunParallel)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the rollback snapshots")
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Host.ID < nodes[j].Host.ID
+	})
+
+	return nodes, nil
+}
+
+// SelectRollbackNodes returns the nodes to be rolled back: the given hosts,
+// which must have the rollback snapshot, or, if no hosts are given, the
+// nodes whose upgrade wasn't completed
+func SelectRollbackNodes(cluster *kubeoneapi.KubeOneCluster, nodes []RollbackNode, hosts []string) ([]RollbackNode, error) {
+	selected := []RollbackNode{}
+
+	if len(hosts) == 0 {
+		for _, node := range nodes {
+			if node.UpgradeInProgress {
+				selected = append(selected, node)
+			}
+		}
+
+		return selected, nil
+	}
+
+	for _, name := range hosts {
+		host, isControlPlane, found := FindHost(cluster, name)
+		if !found || !isControlPlane {
+			return nil, errors.Errorf("control plane host %q is not defined in the manifest", name)
+		}
+
+		var node *RollbackNode
+		for i := range nodes {
+			if nodes[i].Host.ID == host.ID {
+				node = &nodes[i]
+				break
+			}
+		}
+		if node == nil {
+			return nil, errors.Errorf("control plane host %q has no rollback snapshot", name)
+		}
+
+		selected = append(selected, *node)
+	}
+
+	return selected, nil
+}
+
+func parseRollbackStatus(stdout string) map[string]string {
+	status := map[string]string{}
+	for _, line := range strings.Split(stdout, "\n") {
+		kv := strings.SplitN(strings.TrimSpace(line), "=", 2)
+		if len(kv) == 2 {
+			status[kv[0]] = kv[1]
+		}
+	}
+
+	return status
+}
+
+// WithRollback restores the control plane nodes from the rollback snapshots,
+// one by one, and uncordons them once they're healthy
+func WithRollback(t Tasks, nodes []kubeoneapi.HostConfig) Tasks {
+	return t.append(Tasks{
+		{
+			Fn: func(s *state.State) error {
+				return s.RunTaskOnNodes(nodes, rollbackExecutor, state.RunSequentially)
+			},
+			ErrMsg:      "failed to roll back the control plane nodes",
+			Description: "restore /etc/kubernetes, kubelet configuration and Kubernetes binaries from the snapshot",
+			Retries:     1,
+		},
+		{Fn: kubeconfig.BuildKubernetesClientset, ErrMsg: "failed to build kubernetes clientset"},
+		{
+			Fn: func(s *state.State) error {
+				return s.RunTaskOnNodes(nodes, uncordonRolledBackExecutor, state.RunSequentially)
+			},
+			ErrMsg:      "failed to uncordon the rolled back control plane nodes",
+			Description: "verify the rolled back nodes are healthy and uncordon them",
+		},
+	}...)
+}
+
+func rollbackExecutor(s *state.State, _ *kubeoneapi.HostConfig, _ ssh.Connection) error {
+	s.Logger.Infoln("Restoring the control plane node from the rollback snapshot...")
+
+	cmd, err := scripts.RollbackRestore()
+	if err != nil {
+		return err
+	}
+
+	_, _, err = s.Runner.RunRaw(cmd)
+
+	return errors.WithStack(err)
+}
+
+func uncordonRolledBackExecutor(s *state.State, node *kubeoneapi.HostConfig, _ ssh.Connection) error {
+	s.Logger.Infoln("Verifying that the rolled back node is healthy...")
+	if err := nodehealth.Verify(s, *node, true); err != nil {
+		return nonRetryable(errors.Wrap(err, "rolled back node is not healthy"))
+	}
+
+	drainer := nodeutils.NewDrainer(s.RESTConfig, s.Logger, s.Cluster.NodeDrainConfig(*node))
+
+	s.Logger.Infoln("Uncordoning the rolled back node...")
+	if err := drainer.Cordon(s.Context, node.Hostname, false); err != nil {
+		return errors.Wrap(err, "failed to uncordon the rolled back node")
+	}
+
+	return errors.Wrap(unlabelNode(s.DynamicClient, node), "failed to unlabel the rolled back node")
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+	"testing"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+)
+
+func TestParseRollbackStatus(t *testing.T) {
+	got := parseRollbackStatus("version=v1.21.5\nupgrade_in_progress=true\n")
+	want := map[string]string{
+		"version":             "v1.21.5",
+		"upgrade_in_progress": "true",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseRollbackStatus() = %v, want %v", got, want)
+	}
+
+	if got = parseRollbackStatus(""); len(got) != 0 {
+		t.Errorf("parseRollbackStatus() of empty output = %v, want empty", got)
+	}
+}
+
+func TestSelectRollbackNodes(t *testing.T) {
+	cluster := &kubeoneapi.KubeOneCluster{
+		ControlPlane: kubeoneapi.ControlPlaneConfig{
+			Hosts: []kubeoneapi.HostConfig{
+				{ID: 0, Hostname: "cp-0", PublicAddress: "10.0.0.1"},
+				{ID: 1, Hostname: "cp-1", PublicAddress: "10.0.0.2"},
+				{ID: 2, Hostname: "cp-2", PublicAddress: "10.0.0.3"},
+			},
+		},
+		StaticWorkers: kubeoneapi.StaticWorkersConfig{
+			Hosts: []kubeoneapi.HostConfig{
+				{ID: 3, Hostname: "worker-0"},
+			},
+		},
+	}
+
+	nodes := []RollbackNode{
+		{Host: cluster.ControlPlane.Hosts[0], Version: "v1.21.5"},
+		{Host: cluster.ControlPlane.Hosts[1], Version: "v1.21.5", UpgradeInProgress: true},
+	}
+
+	tests := []struct {
+		name    string
+		hosts   []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "interrupted upgrades",
+			want: []string{"cp-1"},
+		},
+		{
+			name:  "given hosts",
+			hosts: []string{"10.0.0.1", "cp-1"},
+			want:  []string{"cp-0", "cp-1"},
+		},
+		{
+			name:    "host without snapshot",
+			hosts:   []string{"cp-2"},
+			wantErr: true,
+		},
+		{
+			name:    "static worker",
+			hosts:   []string{"worker-0"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown host",
+			hosts:   []string{"cp-5"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SelectRollbackNodes(cluster, nodes, tt.hosts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SelectRollbackNodes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			gotNames := []string{}
+			for _, node := range got {
+				gotNames = append(gotNames, node.Host.Hostname)
+			}
+			if !reflect.DeepEqual(gotNames, tt.want) {
+				t.Errorf("SelectRollbackNodes() = %v, want %v", gotNames, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/scripts"
+	"k8c.io/kubeone/pkg/ssh"
+	"k8c.io/kubeone/pkg/state"
+)
+
+// ensureStaticPods saves the configured static pod manifests on all hosts
+// and removes manifests of static pods which are no longer configured
+func ensureStaticPods(s *state.State) error {
+	s.Logger.Infoln("Ensuring static pods...")
+
+	if err := s.RunTaskOnControlPlane(saveStaticPodsExecutor(s.Cluster.ControlPlane.StaticPods), state.RunParallel); err != nil {
+		return err
+	}
+
+	return s.RunTaskOnStaticWorkers(saveStaticPodsExecutor(s.Cluster.StaticWorkers.StaticPods), state.RunParallel)
+}
+
+func saveStaticPodsExecutor(pods []kubeoneapi.StaticPod) state.NodeTask {
+	manifests := map[string]string{}
+	for _, pod := range pods {
+		manifests[pod.Name] = pod.Manifest
+	}
+
+	return func(s *state.State, _ *kubeoneapi.HostConfig, _ ssh.Connection) error {
+		cmd, err := scripts.SaveStaticPods(manifests)
+		if err != nil {
+			return err
+		}
+
+		_, _, err = s.Runner.RunRaw(cmd)
+		return err
+	}
+}
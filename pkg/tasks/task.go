@@ -19,6 +19,9 @@ package tasks
 import (
 	"time"
 
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/logging"
 	"k8c.io/kubeone/pkg/state"
 
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -33,6 +36,24 @@ func defaultRetryBackoff(retries int) wait.Backoff {
 	}
 }
 
+// nonRetryableError stops the task from being retried, because retrying it
+// would repeat actions which already failed
+type nonRetryableError struct {
+	error
+}
+
+func (e nonRetryableError) Unwrap() error {
+	return e.error
+}
+
+func nonRetryable(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return nonRetryableError{err}
+}
+
 // Task is a runnable task
 type Task struct {
 	Fn          func(*state.State) error
@@ -50,13 +71,39 @@ func (t *Task) Run(s *state.State) error {
 
 	backoff := defaultRetryBackoff(t.Retries)
 
-	var lastError error
+	// Entries logged by the task are annotated with the task name and subsystem
+	fields := logging.FuncFields(t.Fn)
+	logger := s.Logger
+	s.Logger = logger.WithFields(fields)
+	defer func() { s.Logger = logger }()
+
+	name, _ := fields[logging.FieldTask].(string)
+	if t.Description != "" {
+		name = t.Description
+	}
+	subsystem, _ := fields[logging.FieldSubsystem].(string)
+	span := s.Progress.StartTask(name, subsystem)
+
+	retryPending := s.RetryPending
+	defer func() { s.RetryPending = retryPending }()
+
+	var (
+		lastError error
+		attempt   int
+	)
 	err := wait.ExponentialBackoff(backoff, func() (bool, error) {
 		if lastError != nil {
 			s.Logger.Warn("Retrying task...")
+			span.Retry()
 		}
 
+		attempt++
+		s.RetryPending = attempt < backoff.Steps
 		lastError = t.Fn(s)
+		var nonRetryableErr nonRetryableError
+		if errors.As(lastError, &nonRetryableErr) {
+			return false, lastError
+		}
 		if lastError != nil {
 			s.Logger.Warnf("Task failed, error was: %s", lastError)
 			return false, nil
@@ -66,5 +113,7 @@ func (t *Task) Run(s *state.State) error {
 	if err == wait.ErrWaitTimeout {
 		err = lastError
 	}
+	span.Finish(err)
+
 	return err
 }
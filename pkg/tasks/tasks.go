@@ -17,19 +17,30 @@ limitations under the License.
 package tasks
 
 import (
+	"reflect"
+
 	"github.com/pkg/errors"
 
 	"k8c.io/kubeone/pkg/addons"
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
 	"k8c.io/kubeone/pkg/certificate"
 	"k8c.io/kubeone/pkg/clusterstatus"
 	"k8c.io/kubeone/pkg/credentials"
 	"k8c.io/kubeone/pkg/features"
 	"k8c.io/kubeone/pkg/kubeconfig"
 	"k8c.io/kubeone/pkg/state"
+	"k8c.io/kubeone/pkg/templates/certmanager"
 	"k8c.io/kubeone/pkg/templates/csi"
 	"k8c.io/kubeone/pkg/templates/externalccm"
+	"k8c.io/kubeone/pkg/templates/flux"
 	"k8c.io/kubeone/pkg/templates/machinecontroller"
+	"k8c.io/kubeone/pkg/templates/rbac"
 	"k8c.io/kubeone/pkg/templates/resources"
+	"k8c.io/kubeone/pkg/templates/velero"
+	"k8c.io/kubeone/pkg/templates/weave"
+
+	apiserverconfigv1 "k8s.io/apiserver/pkg/apis/config/v1"
+	kyaml "sigs.k8s.io/yaml"
 )
 
 type Tasks []Task
@@ -72,11 +83,13 @@ func (t Tasks) prepend(newtasks ...Task) Tasks {
 
 // WithBinariesOnly will prepend passed tasks with tasks WithHostnameOS() and
 // append install prerequisite binaries (docker, kubeadm, kubelet, etc...) on
-// all hosts
+// all hosts, rebooting the hosts which require it afterwards
 func WithBinariesOnly(t Tasks) Tasks {
 	return WithHostnameOSAndProbes(t).
 		append(
+			hostRequirementsTask(),
 			Task{Fn: installPrerequisites, ErrMsg: "failed to install prerequisites"},
+			Task{Fn: rebootHostsIfRequired, ErrMsg: "failed to reboot hosts"},
 		)
 }
 
@@ -114,6 +127,11 @@ func WithFullInstall(t Tasks) Tasks {
 	return WithBinariesOnly(t).
 		append(kubernetesConfigFiles()...).
 		append(Tasks{
+			{
+				Fn:        installExternalCA,
+				ErrMsg:    "failed to install the external CA",
+				Predicate: func(s *state.State) bool { return s.Cluster.ExternalCA() != nil },
+			},
 			{
 				Fn: func(s *state.State) error {
 					s.Logger.Infoln("Configuring certs and etcd on control plane node...")
@@ -144,8 +162,15 @@ func WithFullInstall(t Tasks) Tasks {
 			},
 			{Fn: initKubernetesLeader, ErrMsg: "failed to init kubernetes on leader"},
 			{Fn: kubeconfig.BuildKubernetesClientset, ErrMsg: "failed to build kubernetes clientset"},
+			{
+				Fn:     cleanupReplacedHosts,
+				ErrMsg: "failed to clean up recreated hosts",
+				Predicate: func(s *state.State) bool {
+					return s.LiveCluster != nil && len(s.LiveCluster.ReplacedHosts()) > 0
+				},
+			},
 			{Fn: repairClusterIfNeeded, ErrMsg: "failed to repair cluster"},
-			{Fn: joinControlplaneNode, ErrMsg: "failed to join other masters a cluster"},
+			{Fn: withKubeletCSRSigner(joinControlplaneNode), ErrMsg: "failed to join other masters a cluster"},
 			{Fn: restartKubeAPIServer, ErrMsg: "failed to restart unhealthy kube-apiserver"},
 		}...).
 		append(WithResources(nil)...).
@@ -167,18 +192,49 @@ func WithResources(t Tasks) Tasks {
 				Fn:     patchStaticPods,
 				ErrMsg: "failed to patch static pods",
 			},
+			{
+				Fn:     ensureStaticPods,
+				ErrMsg: "failed to ensure static pods",
+			},
+			{
+				Fn:     ensureOSTuning,
+				ErrMsg: "failed to ensure kernel modules and sysctl settings",
+			},
+			{
+				Fn:     ensureKubeletFlags,
+				ErrMsg: "failed to ensure kubelet flags",
+			},
+			{
+				Fn:     ensureCISHardening,
+				ErrMsg: "failed to ensure CIS hardening",
+				Predicate: func(s *state.State) bool {
+					return s.Cluster.CISHardeningEnabled()
+				},
+			},
+			{
+				Fn:     ensureFirewall,
+				ErrMsg: "failed to ensure firewall rules",
+				Predicate: func(s *state.State) bool {
+					return s.Cluster.FirewallEnabled()
+				},
+			},
 			{
 				Fn:          renewControlPlaneCerts,
 				ErrMsg:      "failed to renew certificates",
 				Description: "renew all certificates",
 				Predicate: func(s *state.State) bool {
-					return s.LiveCluster.CertsToExpireInLessThen90Days()
+					return s.LiveCluster.CertsToExpireInLessThan(s.Cluster.CertificateRenewBefore())
 				},
 			},
 			{
 				Fn:     saveKubeconfig,
 				ErrMsg: "failed to save kubeconfig to the local machine",
 			},
+			{
+				Fn:        persistKubernetesVersion,
+				ErrMsg:    "failed to save Kubernetes version to the state store",
+				Predicate: func(s *state.State) bool { return s.StateStore != nil },
+			},
 			{
 				Fn: func(s *state.State) error {
 					s.Logger.Info("Downloading PKI...")
@@ -206,7 +262,16 @@ func WithResources(t Tasks) Tasks {
 				Fn:          ensureCNI,
 				ErrMsg:      "failed to install cni plugin",
 				Description: "ensure CNI",
-				Predicate:   func(s *state.State) bool { return s.Cluster.ClusterNetwork.CNI.External == nil },
+				Predicate: func(s *state.State) bool {
+					external := s.Cluster.ClusterNetwork.CNI.External
+					return external == nil || external.HelmChart != nil
+				},
+			},
+			{
+				Fn:          ensureNvidiaRuntimeClass,
+				ErrMsg:      "failed to ensure nvidia RuntimeClass",
+				Description: "ensure nvidia RuntimeClass",
+				Predicate:   func(s *state.State) bool { return s.Cluster.ContainerRuntime.Containerd.NvidiaRuntimeEnabled() },
 			},
 			{
 				Fn:          ensureCABundleConfigMap,
@@ -214,6 +279,18 @@ func WithResources(t Tasks) Tasks {
 				Description: "ensure caBundle configMap",
 				Predicate:   func(s *state.State) bool { return s.Cluster.CABundle != "" },
 			},
+			{
+				Fn:          rbac.Ensure,
+				ErrMsg:      "failed to ensure RBAC resources",
+				Description: "ensure RBAC resources",
+				Predicate:   func(s *state.State) bool { return s.Cluster.RBAC != nil },
+			},
+			{
+				Fn:          certmanager.Ensure,
+				ErrMsg:      "failed to ensure cert-manager",
+				Description: "ensure cert-manager",
+				Predicate:   func(s *state.State) bool { return s.Cluster.Features.CertManager.Enabled() },
+			},
 			{
 				Fn:          addons.EnsureUserAddons,
 				ErrMsg:      "failed to apply addons",
@@ -238,7 +315,7 @@ func WithResources(t Tasks) Tasks {
 				Predicate:   func(s *state.State) bool { return s.Cluster.CloudProvider.External },
 			},
 			{
-				Fn:     joinStaticWorkerNodes,
+				Fn:     withKubeletCSRSigner(joinStaticWorkerNodes),
 				ErrMsg: "failed to join worker nodes to the cluster",
 			},
 			{
@@ -261,6 +338,18 @@ func WithResources(t Tasks) Tasks {
 				Description: "upgrade MachineDeployments",
 				Predicate:   func(s *state.State) bool { return s.UpgradeMachineDeployments },
 			},
+			{
+				Fn:          velero.Ensure,
+				ErrMsg:      "failed to ensure Velero",
+				Description: "ensure Velero",
+				Predicate:   func(s *state.State) bool { return s.Cluster.Features.Velero.Enabled() },
+			},
+			{
+				Fn:          flux.Ensure,
+				ErrMsg:      "failed to ensure Flux",
+				Description: "ensure Flux",
+				Predicate:   func(s *state.State) bool { return s.Cluster.Features.GitOps.Enabled() },
+			},
 		}...,
 	)
 }
@@ -271,7 +360,20 @@ func WithUpgrade(t Tasks) Tasks {
 		append(Tasks{
 			{Fn: kubeconfig.BuildKubernetesClientset, ErrMsg: "failed to build kubernetes clientset"},
 			{Fn: runPreflightChecks, ErrMsg: "preflight checks failed", Retries: 1},
+			hostRequirementsTask(),
 			{Fn: upgradeLeader, ErrMsg: "failed to upgrade leader control plane"},
+			{
+				Fn:          upgradeCanaryStaticWorker,
+				ErrMsg:      "failed to upgrade canary static worker",
+				Description: "upgrade the canary static worker node",
+				Predicate:   func(s *state.State) bool { return s.CanaryUpgrade && len(s.Cluster.StaticWorkers.Hosts) > 0 },
+			},
+			{
+				Fn:          checkCanary,
+				ErrMsg:      "canary check failed, the upgrade is stopped",
+				Description: "check the canary nodes before upgrading the rest of the cluster",
+				Predicate:   func(s *state.State) bool { return s.CanaryUpgrade },
+			},
 			{Fn: upgradeFollower, ErrMsg: "failed to upgrade follower control plane"},
 			{
 				Fn: func(s *state.State) error {
@@ -296,12 +398,107 @@ func WithUpgrade(t Tasks) Tasks {
 
 func WithReset(t Tasks) Tasks {
 	return t.append(Tasks{
+		{
+			Fn:        deleteVolumes,
+			ErrMsg:    "failed to delete volumes",
+			Predicate: func(s *state.State) bool { return !s.PreservePVCVolumes },
+		},
 		{Fn: destroyWorkers, ErrMsg: "failed to destroy workers"},
+		{
+			Fn:        drainAllNodes,
+			ErrMsg:    "failed to drain nodes",
+			Predicate: func(s *state.State) bool { return s.DrainNodes },
+		},
 		{Fn: resetAllNodes, ErrMsg: "failed to reset nodes"},
 		{Fn: removeBinariesAllNodes, ErrMsg: "failed to remove binaries from nodes"},
 	}...)
 }
 
+// WithResetWorkers destroys the machine-controller managed worker nodes,
+// leaving the control plane and the static worker nodes intact
+func WithResetWorkers(t Tasks) Tasks {
+	return t.append(Tasks{
+		{
+			Fn:          destroyWorkers,
+			ErrMsg:      "failed to destroy workers",
+			Description: "delete MachineDeployments and wait for the machines to be deleted",
+			Predicate:   func(s *state.State) bool { return !s.KeepMachineDeployments },
+		},
+		{
+			Fn:          destroyWorkers,
+			ErrMsg:      "failed to destroy workers",
+			Description: "save MachineDeployments and scale them down to zero replicas",
+			Predicate:   func(s *state.State) bool { return s.KeepMachineDeployments },
+		},
+	}...)
+}
+
+// WithResetControlPlane resets the control plane nodes, leaving the worker
+// nodes intact
+func WithResetControlPlane(t Tasks) Tasks {
+	return t.append(Tasks{
+		{
+			Fn:          drainControlPlane,
+			ErrMsg:      "failed to drain nodes",
+			Description: "cordon and drain the control plane nodes",
+			Predicate:   func(s *state.State) bool { return s.DrainNodes },
+		},
+		{
+			Fn: func(s *state.State) error {
+				s.Logger.Infoln("Resetting the control plane nodes...")
+				return s.RunTaskOnControlPlane(resetNode, state.RunSequentially)
+			},
+			ErrMsg:      "failed to reset nodes",
+			Description: "reset the control plane nodes using kubeadm",
+		},
+		{
+			Fn: func(s *state.State) error {
+				s.Logger.Infoln("Removing binaries from the control plane nodes...")
+				return s.RunTaskOnControlPlane(removeBinaries, state.RunParallel)
+			},
+			ErrMsg:      "failed to remove binaries from nodes",
+			Description: "remove Kubernetes binaries from the control plane nodes",
+			Predicate:   func(s *state.State) bool { return s.RemoveBinaries },
+		},
+	}...)
+}
+
+// WithResetNode wipes a single control plane or static worker node, so it
+// can be provisioned again by the apply command
+func WithResetNode(t Tasks, host kubeoneapi.HostConfig, controlPlane bool) Tasks {
+	hosts := []kubeoneapi.HostConfig{host}
+
+	return WithRemoveNode(t, host, controlPlane).append(Tasks{
+		{
+			Fn: func(s *state.State) error {
+				return s.RunTaskOnNodes(hosts, removeBinaries, state.RunSequentially)
+			},
+			ErrMsg:      "failed to remove binaries from node",
+			Description: "remove Kubernetes binaries from the node",
+			Predicate:   func(s *state.State) bool { return s.RemoveBinaries },
+		},
+	}...)
+}
+
+// WithResetAddons deletes the addons applied from the addons directory and
+// the addons listed in the KubeOneCluster manifest
+func WithResetAddons(t Tasks) Tasks {
+	return WithProbes(WithHostnameOS(t)).append(Tasks{
+		{
+			Fn: func(s *state.State) error {
+				s.Logger.Info("Downloading PKI...")
+				return s.RunTaskOnLeader(certificate.DownloadKubePKI)
+			},
+			ErrMsg: "failed to download Kubernetes PKI from the leader",
+		},
+		{
+			Fn:          addons.DeleteUserAddons,
+			ErrMsg:      "failed to delete addons",
+			Description: "delete the addons configured in the manifest",
+		},
+	}...)
+}
+
 func WithContainerDMigration(t Tasks) Tasks {
 	return WithHostnameOS(t).
 		append(Tasks{
@@ -421,6 +618,44 @@ func WithCustomEncryptionConfigUpdated(t Tasks) Tasks {
 	}...)
 }
 
+// WithUpgradeAndEncryption returns the tasks upgrading the cluster, along with
+// the tasks disabling, enabling or updating the Encryption Providers, if their
+// configuration in the manifest differs from the live cluster. Returned
+// operations describe the Encryption Providers changes.
+func WithUpgradeAndEncryption(t Tasks, s *state.State) (Tasks, []string, error) {
+	operations := []string{}
+
+	// disable case, we do this as early as possible.
+	if s.ShouldDisableEncryption() {
+		t = WithDisableEncryptionProviders(t, s.LiveCluster.EncryptionConfiguration.Custom)
+	}
+
+	t = WithUpgrade(t)
+
+	if s.ShouldEnableEncryption() {
+		operations = append(operations, "enable Encryption Provider support")
+		t = WithRewriteSecrets(t)
+	}
+
+	// custom encryption configuration was modified
+	if s.LiveCluster.CustomEncryptionEnabled() &&
+		s.Cluster.Features.EncryptionProviders != nil &&
+		s.Cluster.Features.EncryptionProviders.CustomEncryptionConfiguration != "" {
+		config := &apiserverconfigv1.EncryptionConfiguration{}
+		err := kyaml.UnmarshalStrict([]byte(s.Cluster.Features.EncryptionProviders.CustomEncryptionConfiguration), config)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if !reflect.DeepEqual(config, s.LiveCluster.EncryptionConfiguration.Config) {
+			operations = append(operations, []string{"update Encryption Provider configuration", "restart KubeAPI"}...)
+			t = WithCustomEncryptionConfigUpdated(t)
+		}
+	}
+
+	return t, operations, nil
+}
+
 func WithRotateKey(t Tasks) Tasks {
 	return WithHostnameOSAndProbes(t).
 		append(Tasks{
@@ -457,6 +692,49 @@ func WithRotateKey(t Tasks) Tasks {
 		}...)
 }
 
+func WithRotateWeavePassword(t Tasks) Tasks {
+	return t.append(Tasks{
+		{Fn: kubeconfig.BuildKubernetesClientset, ErrMsg: "failed to build kubernetes clientset"},
+		{
+			Fn:          weave.RotateSecret,
+			ErrMsg:      "failed to rotate weave-net password",
+			Description: "rotate weave-net encryption password",
+		},
+		{
+			Fn:          weave.RestartDaemonSet,
+			ErrMsg:      "failed to restart weave-net",
+			Description: "rolling restart weave-net DaemonSet",
+		},
+	}...)
+}
+
+func WithRotateCredentials(t Tasks) Tasks {
+	return t.append(Tasks{
+		{Fn: kubeconfig.BuildKubernetesClientset, ErrMsg: "failed to build kubernetes clientset"},
+		{
+			Fn:          credentials.Ensure,
+			ErrMsg:      "failed to update the credentials secrets",
+			Description: "update the cloud credentials secrets",
+		},
+		{
+			Fn:          reapplyCredentialsAddons,
+			ErrMsg:      "failed to re-apply the addons using the credentials",
+			Description: "re-apply the addons using the credentials (machine-controller, CCM, CSI, Velero)",
+		},
+		{
+			Fn:          restartCredentialsConsumers,
+			ErrMsg:      "failed to restart the workloads using the credentials",
+			Description: "rolling restart Deployments and DaemonSets using the credentials",
+		},
+		{
+			Fn:          verifyMachineDeployments,
+			ErrMsg:      "failed to verify MachineDeployments",
+			Description: "verify MachineDeployments can be reconciled with the new credentials",
+			Predicate:   func(s *state.State) bool { return s.Cluster.MachineController.Deploy },
+		},
+	}...)
+}
+
 func WithCCMCSIMigration(t Tasks) Tasks {
 	return t.append(Tasks{
 		{Fn: ccmMigrationValidateConfig, ErrMsg: "failed to validate config", Retries: 1},
@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/state"
+)
+
+// canaryNodes returns the nodes upgraded before the rest of the cluster: the
+// leader control plane node and the first static worker node, if any
+func canaryNodes(cluster *kubeoneapi.KubeOneCluster) ([]kubeoneapi.HostConfig, error) {
+	leader, err := cluster.Leader()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []kubeoneapi.HostConfig{leader}
+	if len(cluster.StaticWorkers.Hosts) > 0 {
+		nodes = append(nodes, cluster.StaticWorkers.Hosts[0])
+	}
+
+	return nodes, nil
+}
+
+// nonCanaryStaticWorkers returns the static worker nodes which are not
+// upgraded as part of the canary
+func nonCanaryStaticWorkers(s *state.State) []kubeoneapi.HostConfig {
+	hosts := s.Cluster.StaticWorkers.Hosts
+	if s.CanaryUpgrade && len(hosts) > 0 {
+		return hosts[1:]
+	}
+
+	return hosts
+}
+
+func upgradeCanaryStaticWorker(s *state.State) error {
+	hosts := s.Cluster.StaticWorkers.Hosts
+	if len(hosts) == 0 {
+		return nil
+	}
+
+	// The canary worker runs the new kubelet while the follower control
+	// plane nodes are still on the old version, until the canary check passes
+	s.Logger.Infof("Upgrading canary static worker node %q...", hosts[0].Hostname)
+
+	return s.RunTaskOnNodes(hosts[:1], upgradeStaticWorkersExecutor, state.RunSequentially)
+}
+
+// checkCanary runs the canary check to decide whether the upgrade should
+// continue. The canary nodes are already verified to be healthy once they're
+// upgraded.
+func checkCanary(s *state.State) error {
+	nodes, err := canaryNodes(s.Cluster)
+	if err != nil {
+		return err
+	}
+
+	if s.CanaryCheck == nil {
+		return nil
+	}
+
+	s.Logger.Infoln("Running canary check...")
+
+	return nonRetryable(s.CanaryCheck(s, nodes))
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+	"testing"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/state"
+)
+
+func Test_canaryNodes(t *testing.T) {
+	controlPlane := kubeoneapi.ControlPlaneConfig{
+		Hosts: []kubeoneapi.HostConfig{
+			{Hostname: "cp-0"},
+			{Hostname: "cp-1", IsLeader: true},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		staticWorkers []kubeoneapi.HostConfig
+		canary        bool
+		wantCanary    []string
+		wantRemaining []string
+	}{
+		{
+			name:          "no static workers",
+			canary:        true,
+			wantCanary:    []string{"cp-1"},
+			wantRemaining: []string{},
+		},
+		{
+			name:          "static workers",
+			staticWorkers: []kubeoneapi.HostConfig{{Hostname: "worker-0"}, {Hostname: "worker-1"}},
+			canary:        true,
+			wantCanary:    []string{"cp-1", "worker-0"},
+			wantRemaining: []string{"worker-1"},
+		},
+		{
+			name:          "canary upgrade disabled",
+			staticWorkers: []kubeoneapi.HostConfig{{Hostname: "worker-0"}, {Hostname: "worker-1"}},
+			wantCanary:    []string{"cp-1", "worker-0"},
+			wantRemaining: []string{"worker-0", "worker-1"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			s := &state.State{
+				Cluster: &kubeoneapi.KubeOneCluster{
+					ControlPlane:  controlPlane,
+					StaticWorkers: kubeoneapi.StaticWorkersConfig{Hosts: tt.staticWorkers},
+				},
+				CanaryUpgrade: tt.canary,
+			}
+
+			nodes, err := canaryNodes(s.Cluster)
+			if err != nil {
+				t.Fatalf("canaryNodes() error = %v", err)
+			}
+			if got := hostnames(nodes); !reflect.DeepEqual(got, tt.wantCanary) {
+				t.Errorf("canaryNodes() = %v, want %v", got, tt.wantCanary)
+			}
+
+			if got := hostnames(nonCanaryStaticWorkers(s)); !reflect.DeepEqual(got, tt.wantRemaining) {
+				t.Errorf("nonCanaryStaticWorkers() = %v, want %v", got, tt.wantRemaining)
+			}
+		})
+	}
+}
+
+func hostnames(hosts []kubeoneapi.HostConfig) []string {
+	names := []string{}
+	for _, host := range hosts {
+		names = append(names, host.Hostname)
+	}
+
+	return names
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package tasks
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/pkg/errors"
@@ -39,7 +40,7 @@ func upgradeFollowerExecutor(s *state.State, node *kubeoneapi.HostConfig, conn s
 		return errors.Wrap(err, "failed to label follower control plane node")
 	}
 
-	drainer := nodeutils.NewDrainer(s.RESTConfig, logger)
+	drainer := nodeutils.NewDrainer(s.RESTConfig, logger, s.Cluster.NodeDrainConfig(*node))
 
 	logger.Infoln("Cordon the follower control plane node...")
 	if err := drainer.Cordon(s.Context, node.Hostname, true); err != nil {
@@ -50,6 +51,12 @@ func upgradeFollowerExecutor(s *state.State, node *kubeoneapi.HostConfig, conn s
 	if err := drainer.Drain(s.Context, node.Hostname); err != nil {
 		return errors.Wrap(err, "failed to drain follower control plane node")
 	}
+	recordNodeEvent(s, node, nodeutils.EventReasonDrained, "Node drained by KubeOne")
+
+	logger.Infoln("Snapshotting the follower control plane for rollback...")
+	if err := snapshotControlPlaneNode(s); err != nil {
+		return err
+	}
 
 	logger.Infoln("Upgrading Kubernetes binaries on follower control plane...")
 	if err := upgradeKubeadmAndCNIBinaries(s, *node); err != nil {
@@ -57,7 +64,7 @@ func upgradeFollowerExecutor(s *state.State, node *kubeoneapi.HostConfig, conn s
 	}
 
 	logger.Infoln("Running 'kubeadm upgrade' on the follower control plane node...")
-	if err := upgradeFollowerControlPlane(s); err != nil {
+	if err := upgradeFollowerControlPlane(s, *node); err != nil {
 		return errors.Wrap(err, "failed to upgrade follower control plane")
 	}
 
@@ -65,6 +72,15 @@ func upgradeFollowerExecutor(s *state.State, node *kubeoneapi.HostConfig, conn s
 	if err := upgradeKubeletAndKubectlBinaries(s, *node); err != nil {
 		return errors.Wrap(err, "failed to upgrade kubernetes system binaries on follower control plane")
 	}
+	recordNodeEvent(s, node, nodeutils.EventReasonUpgraded, fmt.Sprintf("Node upgraded to Kubernetes %s by KubeOne", s.Cluster.Versions.Kubernetes))
+
+	if err := verifyUpgradedNode(s, node, true); err != nil {
+		return err
+	}
+
+	if err := markControlPlaneNodeUpgraded(s); err != nil {
+		return errors.Wrap(err, "failed to mark the follower control plane as upgraded")
+	}
 
 	logger.Infoln("Uncordoning follower control plane...")
 	if err := drainer.Cordon(s.Context, node.Hostname, false); err != nil {
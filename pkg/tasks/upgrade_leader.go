@@ -17,6 +17,7 @@ limitations under the License.
 package tasks
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/pkg/errors"
@@ -39,7 +40,7 @@ func upgradeLeaderExecutor(s *state.State, node *kubeoneapi.HostConfig, conn ssh
 		return errors.Wrap(err, "failed to label leader control plane node")
 	}
 
-	drainer := nodeutils.NewDrainer(s.RESTConfig, logger)
+	drainer := nodeutils.NewDrainer(s.RESTConfig, logger, s.Cluster.NodeDrainConfig(*node))
 
 	logger.Infoln("Cordoning leader control plane...")
 	if err := drainer.Cordon(s.Context, node.Hostname, true); err != nil {
@@ -50,6 +51,12 @@ func upgradeLeaderExecutor(s *state.State, node *kubeoneapi.HostConfig, conn ssh
 	if err := drainer.Drain(s.Context, node.Hostname); err != nil {
 		return errors.Wrap(err, "failed to drain follower control plane node")
 	}
+	recordNodeEvent(s, node, nodeutils.EventReasonDrained, "Node drained by KubeOne")
+
+	logger.Infoln("Snapshotting the leader control plane for rollback...")
+	if err := snapshotControlPlaneNode(s); err != nil {
+		return err
+	}
 
 	logger.Infoln("Upgrading kubeadm binary on the leader control plane...")
 	if err := upgradeKubeadmAndCNIBinaries(s, *node); err != nil {
@@ -57,7 +64,7 @@ func upgradeLeaderExecutor(s *state.State, node *kubeoneapi.HostConfig, conn ssh
 	}
 
 	logger.Infoln("Running 'kubeadm upgrade' on leader control plane node...")
-	if err := upgradeLeaderControlPlane(s); err != nil {
+	if err := upgradeLeaderControlPlane(s, *node); err != nil {
 		return errors.Wrap(err, "failed to run 'kubeadm upgrade' on leader control plane")
 	}
 
@@ -65,6 +72,15 @@ func upgradeLeaderExecutor(s *state.State, node *kubeoneapi.HostConfig, conn ssh
 	if err := upgradeKubeletAndKubectlBinaries(s, *node); err != nil {
 		return errors.Wrap(err, "failed to upgrade kubernetes system binaries on leader control plane")
 	}
+	recordNodeEvent(s, node, nodeutils.EventReasonUpgraded, fmt.Sprintf("Node upgraded to Kubernetes %s by KubeOne", s.Cluster.Versions.Kubernetes))
+
+	if err := verifyUpgradedNode(s, node, true); err != nil {
+		return err
+	}
+
+	if err := markControlPlaneNodeUpgraded(s); err != nil {
+		return errors.Wrap(err, "failed to mark the leader control plane as upgraded")
+	}
 
 	logger.Infoln("Uncordoning leader control plane...")
 	if err := drainer.Cordon(s.Context, node.Hostname, false); err != nil {
@@ -17,6 +17,7 @@ limitations under the License.
 package tasks
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/pkg/errors"
@@ -29,7 +30,7 @@ import (
 
 func upgradeStaticWorkers(s *state.State) error {
 	// we upgrade seqentially to minimize cluster disruption
-	return s.RunTaskOnStaticWorkers(upgradeStaticWorkersExecutor, state.RunSequentially)
+	return s.RunTaskOnNodes(nonCanaryStaticWorkers(s), upgradeStaticWorkersExecutor, state.RunSequentially)
 }
 
 func upgradeStaticWorkersExecutor(s *state.State, node *kubeoneapi.HostConfig, conn ssh.Connection) error {
@@ -41,7 +42,7 @@ func upgradeStaticWorkersExecutor(s *state.State, node *kubeoneapi.HostConfig, c
 		return errors.Wrap(err, "failed to label static worker node")
 	}
 
-	drainer := nodeutils.NewDrainer(s.RESTConfig, logger)
+	drainer := nodeutils.NewDrainer(s.RESTConfig, logger, s.Cluster.NodeDrainConfig(*node))
 
 	logger.Infoln("Cordoning static worker node...")
 	if err := drainer.Cordon(s.Context, node.Hostname, true); err != nil {
@@ -52,6 +53,7 @@ func upgradeStaticWorkersExecutor(s *state.State, node *kubeoneapi.HostConfig, c
 	if err := drainer.Drain(s.Context, node.Hostname); err != nil {
 		return errors.Wrap(err, "failed to drain follower control plane node")
 	}
+	recordNodeEvent(s, node, nodeutils.EventReasonDrained, "Node drained by KubeOne")
 
 	logger.Infoln("Upgrading Kubernetes binaries on static worker node...")
 	if err := upgradeKubeadmAndCNIBinaries(s, *node); err != nil {
@@ -59,7 +61,7 @@ func upgradeStaticWorkersExecutor(s *state.State, node *kubeoneapi.HostConfig, c
 	}
 
 	logger.Infoln("Running 'kubeadm upgrade' on the static worker node...")
-	if err := upgradeStaticWorker(s); err != nil {
+	if err := upgradeStaticWorker(s, *node); err != nil {
 		return errors.Wrap(err, "failed to upgrade static worker node")
 	}
 
@@ -67,6 +69,11 @@ func upgradeStaticWorkersExecutor(s *state.State, node *kubeoneapi.HostConfig, c
 	if err := upgradeKubeletAndKubectlBinaries(s, *node); err != nil {
 		return errors.Wrap(err, "failed to upgrade kubernetes system binaries on the static worker node")
 	}
+	recordNodeEvent(s, node, nodeutils.EventReasonUpgraded, fmt.Sprintf("Node upgraded to Kubernetes %s by KubeOne", s.Cluster.Versions.Kubernetes))
+
+	if err := verifyUpgradedNode(s, node, false); err != nil {
+		return err
+	}
 
 	logger.Infoln("Uncordoning static worker node...")
 	if err := drainer.Cordon(s.Context, node.Hostname, false); err != nil {
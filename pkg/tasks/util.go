@@ -25,10 +25,13 @@ import (
 	"github.com/pkg/errors"
 
 	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/nodehealth"
+	"k8c.io/kubeone/pkg/nodeutils"
 	"k8c.io/kubeone/pkg/scripts"
 	"k8c.io/kubeone/pkg/ssh"
 	"k8c.io/kubeone/pkg/ssh/sshiofs"
 	"k8c.io/kubeone/pkg/state"
+	"k8c.io/kubeone/pkg/templates/kubeadm/kubeadmconfig"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -52,12 +55,28 @@ func determineHostname(s *state.State) error {
 			return nil
 		}
 
-		hostnameCmd := scripts.Hostname()
-
-		// on azure the name of the Node should == name of the VM
-		if s.Cluster.CloudProvider.Azure != nil {
-			hostnameCmd = `hostname`
+		var (
+			hostnameCmd string
+			err         error
+		)
+
+		// on azure the name of the Node should == name of the VM, so the
+		// strategy defaults to the short hostname there
+		switch s.Cluster.NodeNamingStrategy() {
+		case kubeoneapi.NodeNamingHostname:
+			hostnameCmd = scripts.ShortHostname()
+		case kubeoneapi.NodeNamingPrivateIP:
+			node.SetHostname(kubeadmconfig.NodeIP(*node))
+			return nil
+		case kubeoneapi.NodeNamingInstanceID:
+			hostnameCmd, err = scripts.InstanceID(s.Cluster.CloudProvider.CloudProviderName())
+			if err != nil {
+				return err
+			}
+		default:
+			hostnameCmd = scripts.Hostname()
 		}
+
 		stdout, _, err := s.Runner.Run(hostnameCmd, nil)
 		if err != nil {
 			return err
@@ -120,6 +139,15 @@ func unlabelNode(client dynclient.Client, host *kubeoneapi.HostConfig) error {
 	return errors.Wrapf(retErr, "failed to remove label %s from node %s", labelUpgradeLock, host.Hostname)
 }
 
+// recordNodeEvent records an Event and sets the KubeOneOperation condition for
+// the given node. Failing to record them doesn't fail the operation, so the
+// error is only logged.
+func recordNodeEvent(s *state.State, host *kubeoneapi.HostConfig, reason, message string) {
+	if err := nodeutils.RecordEvent(s.Context, s.DynamicClient, host.Hostname, reason, message); err != nil {
+		s.Logger.WithField("node", host.PublicAddress).Warnf("Failed to record %s event: %v", reason, err)
+	}
+}
+
 type runOnOSFn func(*state.State) error
 
 func runOnOS(s *state.State, osname kubeoneapi.OperatingSystemName, fnMap map[kubeoneapi.OperatingSystemName]runOnOSFn) error {
@@ -130,3 +158,19 @@ func runOnOS(s *state.State, osname kubeoneapi.OperatingSystemName, fnMap map[ku
 
 	return errors.WithStack(fn(s))
 }
+
+// verifyUpgradedNode checks that the upgraded node is healthy. If it isn't,
+// the node is left cordoned and labeled, and the upgrade is stopped without
+// retrying it.
+func verifyUpgradedNode(s *state.State, node *kubeoneapi.HostConfig, controlPlane bool) error {
+	s.Logger.Infoln("Verifying that the upgraded node is healthy...")
+	if err := nodehealth.Verify(s, *node, controlPlane); err != nil {
+		s.Logger.Errorf("The node is left cordoned and labeled with %q. Once the node is fixed, uncordon it, remove the label and run the upgrade again.", labelUpgradeLock)
+		if controlPlane {
+			s.Logger.Errorln("To restore the previous Kubernetes version on the node instead, run 'kubeone rollback'.")
+		}
+		return nonRetryable(errors.Wrap(err, "upgraded node is not healthy"))
+	}
+
+	return nil
+}
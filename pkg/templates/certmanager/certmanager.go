@@ -0,0 +1,132 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certmanager
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/addons"
+	"k8c.io/kubeone/pkg/clientutil"
+	"k8c.io/kubeone/pkg/state"
+	"k8c.io/kubeone/pkg/templates/resources"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const webhookName = "cert-manager-webhook"
+
+// Ensure deploys cert-manager and waits for it to become ready, so the user
+// addons can rely on the cert-manager resources. If the ClusterIssuer is
+// enabled, it's bootstrapped once cert-manager is ready.
+func Ensure(s *state.State) error {
+	if !s.Cluster.Features.CertManager.Enabled() {
+		return nil
+	}
+
+	s.Logger.Infoln("Ensure cert-manager...")
+
+	if err := addons.EnsureAddonByName(s, resources.AddonCertManager); err != nil {
+		return errors.Wrap(err, "failed to deploy cert-manager")
+	}
+
+	if err := waitReady(s); err != nil {
+		return err
+	}
+
+	if !s.Cluster.Features.CertManager.ClusterIssuerEnabled() {
+		return nil
+	}
+
+	s.Logger.Infoln("Ensure cert-manager ClusterIssuer...")
+
+	err := addons.EnsureAddonByName(s, resources.AddonCertManagerIssuer)
+	return errors.Wrap(err, "failed to deploy cert-manager ClusterIssuer")
+}
+
+// waitReady waits for the cert-manager CRDs to become established, for the
+// cert-manager pods to become ready and for the CA bundle to be injected in
+// the cert-manager webhooks
+func waitReady(s *state.State) error {
+	s.Logger.Infoln("Waiting for cert-manager to come up...")
+
+	condFn := clientutil.CRDsReadyCondition(s.Context, s.DynamicClient, CRDNames())
+	if err := wait.Poll(5*time.Second, 3*time.Minute, condFn); err != nil {
+		return errors.Wrap(err, "cert-manager CRDs did not come up")
+	}
+
+	condFn = clientutil.PodsReadyCondition(s.Context, s.DynamicClient, dynclient.ListOptions{
+		Namespace: resources.CertManagerNamespace,
+		LabelSelector: labels.SelectorFromSet(map[string]string{
+			"app.kubernetes.io/instance": "cert-manager",
+		}),
+	})
+	if err := wait.Poll(5*time.Second, 5*time.Minute, condFn); err != nil {
+		return errors.Wrap(err, "cert-manager did not come up")
+	}
+
+	err := wait.Poll(5*time.Second, 3*time.Minute, webhookCAInjectedCondition(s.Context, s.DynamicClient))
+	return errors.Wrap(err, "CA bundle was not injected in the cert-manager webhooks")
+}
+
+// webhookCAInjectedCondition checks if the cainjector has injected the CA
+// bundle in the cert-manager webhooks, otherwise the API server can't call
+// the webhooks and the cert-manager resources can't be created
+func webhookCAInjectedCondition(ctx context.Context, client dynclient.Client) func() (bool, error) {
+	return func() (bool, error) {
+		key := dynclient.ObjectKey{Name: webhookName}
+
+		mutating := admissionregistrationv1.MutatingWebhookConfiguration{}
+		if err := client.Get(ctx, key, &mutating); err != nil {
+			return false, err
+		}
+		for _, webhook := range mutating.Webhooks {
+			if len(webhook.ClientConfig.CABundle) == 0 {
+				return false, nil
+			}
+		}
+
+		validating := admissionregistrationv1.ValidatingWebhookConfiguration{}
+		if err := client.Get(ctx, key, &validating); err != nil {
+			return false, err
+		}
+		for _, webhook := range validating.Webhooks {
+			if len(webhook.ClientConfig.CABundle) == 0 {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	}
+}
+
+// CRDNames returns the names of the cert-manager CRDs
+func CRDNames() []string {
+	return []string{
+		"certificaterequests.cert-manager.io",
+		"certificates.cert-manager.io",
+		"challenges.acme.cert-manager.io",
+		"clusterissuers.cert-manager.io",
+		"issuers.cert-manager.io",
+		"orders.acme.cert-manager.io",
+	}
+}
@@ -34,29 +34,49 @@ func Ensure(s *state.State) error {
 	var err error
 
 	switch {
+	case s.Cluster.CloudProvider.AWS != nil:
+		if !s.Cluster.CloudProvider.AWS.EBSCSIEnabled() {
+			s.Logger.Info("EBS CSI driver is not enabled (.cloudProvider.aws.ebsCSI.enable), skipping")
+			return nil
+		}
+		err = addons.EnsureAddonByName(s, resources.AddonCSIAwsEBS)
 	case s.Cluster.CloudProvider.Azure != nil:
 		if s.Cluster.CloudProvider.CloudConfig == "" {
 			return errors.New("cloudConfig not defined")
 		}
 
 		// Deploy AzureDisk CSI driver
-		if err = addons.EnsureAddonByName(s, resources.AddonCSIAzureDisk); err != nil {
-			return errors.Wrap(err, "failed to deploy azuredisk CSI driver")
+		if s.Cluster.CloudProvider.Azure.DiskCSI.Enabled() {
+			if err = addons.EnsureAddonByName(s, resources.AddonCSIAzureDisk); err != nil {
+				return errors.Wrap(err, "failed to deploy azuredisk CSI driver")
+			}
+		} else {
+			s.Logger.Info("AzureDisk CSI driver is not managed by KubeOne, skipping")
 		}
 
 		// Deploy AzureFile CSI driver
+		if !s.Cluster.CloudProvider.Azure.FileCSI.Enabled() {
+			s.Logger.Info("AzureFile CSI driver is not managed by KubeOne, skipping")
+			return nil
+		}
 		err = addons.EnsureAddonByName(s, resources.AddonCSIAzureFile)
 		return errors.Wrap(err, "failed to deploy azurefile CSI driver")
+	case s.Cluster.CloudProvider.DigitalOcean != nil:
+		err = addons.EnsureAddonByName(s, resources.AddonCSIDigitalOcean)
 	case s.Cluster.CloudProvider.Hetzner != nil:
 		err = addons.EnsureAddonByName(s, resources.AddonCSIHetnzer)
 	case s.Cluster.CloudProvider.Openstack != nil:
+		if !s.Cluster.CloudProvider.Openstack.CinderCSI.Enabled() {
+			s.Logger.Info("Cinder CSI driver is not managed by KubeOne, skipping")
+			return nil
+		}
 		if s.Cluster.CloudProvider.CloudConfig == "" {
 			return errors.New("cloudConfig not defined")
 		}
 		err = addons.EnsureAddonByName(s, resources.AddonCSIOpenStackCinder)
 	case s.Cluster.CloudProvider.Vsphere != nil:
 		if s.Cluster.CloudProvider.CSIConfig == "" {
-			s.Logger.Warnln("vSphere CSI driver requires CSI config to be provided via .cloudProvider.csiConfig. Skipping...")
+			s.Logger.Warnln("vSphere CSI driver requires CSI config to be provided via .cloudProvider.csiConfig or .cloudProvider.vsphere.csi. Skipping...")
 			return nil
 		}
 		err = addons.EnsureAddonByName(s, resources.AddonCSIVsphere)
@@ -47,6 +47,10 @@ func Ensure(s *state.State) error {
 	case s.Cluster.CloudProvider.AWS != nil:
 		err = addons.EnsureAddonByName(s, resources.AddonCCMAws)
 	case s.Cluster.CloudProvider.Azure != nil:
+		if !s.Cluster.CloudProvider.Azure.CCM.Enabled() {
+			s.Logger.Info("Azure CCM is not managed by KubeOne, skipping")
+			return nil
+		}
 		if s.Cluster.CloudProvider.CloudConfig == "" {
 			return errors.New("cloudConfig not defined")
 		}
@@ -58,6 +62,10 @@ func Ensure(s *state.State) error {
 	case s.Cluster.CloudProvider.Packet != nil:
 		err = addons.EnsureAddonByName(s, resources.AddonCCMPacket)
 	case s.Cluster.CloudProvider.Openstack != nil:
+		if !s.Cluster.CloudProvider.Openstack.CCM.Enabled() {
+			s.Logger.Info("OpenStack CCM is not managed by KubeOne, skipping")
+			return nil
+		}
 		if s.Cluster.CloudProvider.CloudConfig == "" {
 			return errors.New("cloudConfig not defined")
 		}
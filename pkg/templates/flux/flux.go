@@ -0,0 +1,67 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flux
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/addons"
+	"k8c.io/kubeone/pkg/clientutil"
+	"k8c.io/kubeone/pkg/state"
+	"k8c.io/kubeone/pkg/templates/resources"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// Ensure deploys Flux and configures it to reconcile the git repository
+// from the GitOps feature
+func Ensure(s *state.State) error {
+	if !s.Cluster.Features.GitOps.Enabled() {
+		return nil
+	}
+
+	s.Logger.Infoln("Ensure Flux...")
+
+	if err := addons.EnsureAddonByName(s, resources.AddonFlux); err != nil {
+		return errors.Wrap(err, "failed to deploy Flux")
+	}
+
+	// The GitRepository and Kustomization objects can be created only once
+	// the Flux CRDs are established
+	condFn := clientutil.CRDsReadyCondition(s.Context, s.DynamicClient, CRDNames())
+	if err := wait.Poll(5*time.Second, 3*time.Minute, condFn); err != nil {
+		return errors.Wrap(err, "failed waiting for Flux CRDs to become ready and established")
+	}
+
+	s.Logger.Infof("Configuring Flux to reconcile %s...", s.Cluster.Features.GitOps.Repository.URL)
+
+	err := addons.EnsureAddonByName(s, resources.AddonFluxSync)
+	return errors.Wrap(err, "failed to configure Flux")
+}
+
+// CRDNames returns the names of the Flux CRDs
+func CRDNames() []string {
+	return []string{
+		"buckets.source.toolkit.fluxcd.io",
+		"gitrepositories.source.toolkit.fluxcd.io",
+		"helmcharts.source.toolkit.fluxcd.io",
+		"helmrepositories.source.toolkit.fluxcd.io",
+		"kustomizations.kustomize.toolkit.fluxcd.io",
+	}
+}
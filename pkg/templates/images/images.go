@@ -69,17 +69,34 @@ const (
 
 	// Addons
 	ClusterAutoscaler
+	NvidiaDevicePlugin
+	KubeStateMetrics
+	NodeExporter
+	CertManagerController
+	CertManagerCAInjector
+	CertManagerWebhook
+	IngressNginxController
+	FluxSourceController
+	FluxKustomizeController
+	Velero
+	VeleroPluginForAWS
+	VeleroPluginForGCP
+	VeleroPluginForAzure
 
 	// General CSI images (to be removed)
 	CSIAttacher
 	CSINodeDriverRegistar
 	CSIProvisioner
 	CSISnapshotter
+	CSISnapshotController
 	CSIResizer
 	CSILivenessProbe
 
 	AwsCCM
 
+	// AWS EBS CSI
+	AwsEBSCSI
+
 	// Azure CCM
 	AzureCCM
 	AzureCNM
@@ -106,6 +123,7 @@ const (
 
 	// CCMs and CSI plugins
 	DigitaloceanCCM
+	DigitaloceanCSI
 	HetznerCCM
 	HetznerCSI
 	OpenstackCCM
@@ -150,6 +168,7 @@ func optionalResources() map[Resource]map[string]string {
 			">= 1.19.0, < 1.20.0": "k8s.gcr.io/sig-storage/csi-snapshotter:v3.0.3",
 			">= 1.20.0":           "k8s.gcr.io/sig-storage/csi-snapshotter:v4.2.0",
 		},
+		CSISnapshotController: {">= 1.20.0": "k8s.gcr.io/sig-storage/snapshot-controller:v4.2.0"},
 
 		AwsCCM: {
 			"1.19.x":    "us.gcr.io/k8s-artifacts-prod/provider-aws/cloud-controller-manager:v1.19.0-alpha.1",
@@ -158,6 +177,9 @@ func optionalResources() map[Resource]map[string]string {
 			">= 1.22.0": "us.gcr.io/k8s-artifacts-prod/provider-aws/cloud-controller-manager:v1.22.0-alpha.0",
 		},
 
+		// AWS EBS CSI
+		AwsEBSCSI: {"*": "k8s.gcr.io/provider-aws/aws-ebs-csi-driver:v1.4.0"},
+
 		// Azure CCM
 		AzureCCM: {
 			"1.19.x":    "mcr.microsoft.com/oss/kubernetes/azure-cloud-controller-manager:v0.6.0",
@@ -195,6 +217,9 @@ func optionalResources() map[Resource]map[string]string {
 		// DigitalOcean CCM
 		DigitaloceanCCM: {"*": "docker.io/digitalocean/digitalocean-cloud-controller-manager:v0.1.33"},
 
+		// DigitalOcean CSI
+		DigitaloceanCSI: {"*": "docker.io/digitalocean/do-csi-plugin:v3.0.0"},
+
 		// Hetzner CCM
 		HetznerCCM: {"*": "docker.io/hetznercloud/hcloud-cloud-controller-manager:v1.12.0"},
 
@@ -227,9 +252,18 @@ func optionalResources() map[Resource]map[string]string {
 			">= 1.21.0": "gcr.io/cloud-provider-vsphere/cpi/release/manager:v1.21.0",
 		},
 
-		// vSphere CSI
-		VsphereCSIDriver: {"*": "gcr.io/cloud-provider-vsphere/csi/release/driver:v2.3.0"},
-		VsphereCSISyncer: {"*": "gcr.io/cloud-provider-vsphere/csi/release/syncer:v2.3.0"},
+		// vSphere CSI, the newest driver supporting the Kubernetes version.
+		// Snapshots are supported since v2.5.0.
+		VsphereCSIDriver: {
+			"1.19.x":    "gcr.io/cloud-provider-vsphere/csi/release/driver:v2.3.0",
+			"1.20.x":    "gcr.io/cloud-provider-vsphere/csi/release/driver:v2.4.0",
+			">= 1.21.0": "gcr.io/cloud-provider-vsphere/csi/release/driver:v2.5.0",
+		},
+		VsphereCSISyncer: {
+			"1.19.x":    "gcr.io/cloud-provider-vsphere/csi/release/syncer:v2.3.0",
+			"1.20.x":    "gcr.io/cloud-provider-vsphere/csi/release/syncer:v2.4.0",
+			">= 1.21.0": "gcr.io/cloud-provider-vsphere/csi/release/syncer:v2.5.0",
+		},
 
 		// WeaveNet CNI plugin
 		WeaveNetCNIKube: {"*": "docker.io/weaveworks/weave-kube:2.8.1"},
@@ -252,6 +286,31 @@ func optionalResources() map[Resource]map[string]string {
 			"1.21.x":    "k8s.gcr.io/autoscaling/cluster-autoscaler:v1.21.0",
 			">= 1.22.0": "k8s.gcr.io/autoscaling/cluster-autoscaler:v1.22.0",
 		},
+
+		// NVIDIA device plugin addon
+		NvidiaDevicePlugin: {"*": "nvcr.io/nvidia/k8s-device-plugin:v0.10.0"},
+
+		// Monitoring addon
+		KubeStateMetrics: {"*": "k8s.gcr.io/kube-state-metrics/kube-state-metrics:v2.2.0"},
+		NodeExporter:     {"*": "quay.io/prometheus/node-exporter:v1.2.2"},
+
+		// cert-manager addon
+		CertManagerController: {"*": "quay.io/jetstack/cert-manager-controller:v1.6.1"},
+		CertManagerCAInjector: {"*": "quay.io/jetstack/cert-manager-cainjector:v1.6.1"},
+		CertManagerWebhook:    {"*": "quay.io/jetstack/cert-manager-webhook:v1.6.1"},
+
+		// ingress-nginx addon
+		IngressNginxController: {"*": "k8s.gcr.io/ingress-nginx/controller:v1.1.0"},
+
+		// Flux addon
+		FluxSourceController:    {"*": "ghcr.io/fluxcd/source-controller:v0.19.2"},
+		FluxKustomizeController: {"*": "ghcr.io/fluxcd/kustomize-controller:v0.18.2"},
+
+		// Velero addon
+		Velero:               {"*": "docker.io/velero/velero:v1.7.1"},
+		VeleroPluginForAWS:   {"*": "docker.io/velero/velero-plugin-for-aws:v1.3.0"},
+		VeleroPluginForGCP:   {"*": "docker.io/velero/velero-plugin-for-gcp:v1.3.0"},
+		VeleroPluginForAzure: {"*": "docker.io/velero/velero-plugin-for-microsoft-azure:v1.3.1"},
 	}
 }
 
@@ -274,6 +333,15 @@ func WithOverwriteRegistryGetter(getter func() string) Opt {
 	}
 }
 
+// WithOverwriteImageGetter sets the function returning the repository and the
+// tag used instead of the default ones of the resource. Empty values keep the
+// default repository and tag.
+func WithOverwriteImageGetter(getter func(Resource) (repository, tag string)) Opt {
+	return func(r *Resolver) {
+		r.overwriteImageGetter = getter
+	}
+}
+
 func WithKubernetesVersionGetter(getter func() string) Opt {
 	return func(r *Resolver) {
 		r.kubernetesVersionGetter = getter
@@ -299,6 +367,7 @@ func NewResolver(opts ...Opt) *Resolver {
 
 type Resolver struct {
 	overwriteRegistryGetter func() string
+	overwriteImageGetter    func(Resource) (string, string)
 	kubernetesVersionGetter func() string
 }
 
@@ -333,6 +402,12 @@ func (r *Resolver) List(lf ListFilter) []string {
 }
 
 func (r *Resolver) Tag(res Resource) string {
+	if r.overwriteImageGetter != nil {
+		if _, tag := r.overwriteImageGetter(res); tag != "" {
+			return tag
+		}
+	}
+
 	named := res.namedReference(r.kubernetesVersionGetter)
 	if tagged, ok := named.(reference.Tagged); ok {
 		return tagged.Tag()
@@ -369,10 +444,9 @@ func (r *Resolver) Get(res Resource, opts ...GetOpt) string {
 	domain := reference.Domain(named)
 	reminder := reference.Path(named)
 
+	tag := "latest"
 	if tagged, ok := named.(reference.Tagged); ok {
-		reminder += ":" + tagged.Tag()
-	} else {
-		reminder += ":latest"
+		tag = tagged.Tag()
 	}
 
 	if r.overwriteRegistryGetter != nil {
@@ -382,6 +456,17 @@ func (r *Resolver) Get(res Resource, opts ...GetOpt) string {
 	}
 
 	ret := domain + "/" + reminder
+	if r.overwriteImageGetter != nil {
+		repository, overwriteTag := r.overwriteImageGetter(res)
+		if repository != "" {
+			ret = repository
+		}
+		if overwriteTag != "" {
+			tag = overwriteTag
+		}
+	}
+
+	ret += ":" + tag
 	for _, opt := range opts {
 		ret = opt(ret)
 	}
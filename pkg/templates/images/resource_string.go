@@ -24,45 +24,61 @@ func _() {
 	_ = x[MachineController-14]
 	_ = x[MetricsServer-15]
 	_ = x[ClusterAutoscaler-16]
-	_ = x[CSIAttacher-17]
-	_ = x[CSINodeDriverRegistar-18]
-	_ = x[CSIProvisioner-19]
-	_ = x[CSISnapshotter-20]
-	_ = x[CSIResizer-21]
-	_ = x[CSILivenessProbe-22]
-	_ = x[AwsCCM-23]
-	_ = x[AzureCCM-24]
-	_ = x[AzureCNM-25]
-	_ = x[AzureFileCSI-26]
-	_ = x[AzureFileCSIAttacher-27]
-	_ = x[AzureFileCSILivenessProbe-28]
-	_ = x[AzureFileCSINodeDriverRegistar-29]
-	_ = x[AzureFileCSIProvisioner-30]
-	_ = x[AzureFileCSIResizer-31]
-	_ = x[AzureFileCSISnapshotter-32]
-	_ = x[AzureFileCSISnapshotterController-33]
-	_ = x[AzureDiskCSI-34]
-	_ = x[AzureDiskCSIAttacher-35]
-	_ = x[AzureDiskCSILivenessProbe-36]
-	_ = x[AzureDiskCSINodeDriverRegistar-37]
-	_ = x[AzureDiskCSIProvisioner-38]
-	_ = x[AzureDiskCSIResizer-39]
-	_ = x[AzureDiskCSISnapshotter-40]
-	_ = x[AzureDiskCSISnapshotterController-41]
-	_ = x[DigitaloceanCCM-42]
-	_ = x[HetznerCCM-43]
-	_ = x[HetznerCSI-44]
-	_ = x[OpenstackCCM-45]
-	_ = x[OpenstackCSI-46]
-	_ = x[PacketCCM-47]
-	_ = x[VsphereCCM-48]
-	_ = x[VsphereCSIDriver-49]
-	_ = x[VsphereCSISyncer-50]
+	_ = x[NvidiaDevicePlugin-17]
+	_ = x[KubeStateMetrics-18]
+	_ = x[NodeExporter-19]
+	_ = x[CertManagerController-20]
+	_ = x[CertManagerCAInjector-21]
+	_ = x[CertManagerWebhook-22]
+	_ = x[IngressNginxController-23]
+	_ = x[FluxSourceController-24]
+	_ = x[FluxKustomizeController-25]
+	_ = x[Velero-26]
+	_ = x[VeleroPluginForAWS-27]
+	_ = x[VeleroPluginForGCP-28]
+	_ = x[VeleroPluginForAzure-29]
+	_ = x[CSIAttacher-30]
+	_ = x[CSINodeDriverRegistar-31]
+	_ = x[CSIProvisioner-32]
+	_ = x[CSISnapshotter-33]
+	_ = x[CSISnapshotController-34]
+	_ = x[CSIResizer-35]
+	_ = x[CSILivenessProbe-36]
+	_ = x[AwsCCM-37]
+	_ = x[AwsEBSCSI-38]
+	_ = x[AzureCCM-39]
+	_ = x[AzureCNM-40]
+	_ = x[AzureFileCSI-41]
+	_ = x[AzureFileCSIAttacher-42]
+	_ = x[AzureFileCSILivenessProbe-43]
+	_ = x[AzureFileCSINodeDriverRegistar-44]
+	_ = x[AzureFileCSIProvisioner-45]
+	_ = x[AzureFileCSIResizer-46]
+	_ = x[AzureFileCSISnapshotter-47]
+	_ = x[AzureFileCSISnapshotterController-48]
+	_ = x[AzureDiskCSI-49]
+	_ = x[AzureDiskCSIAttacher-50]
+	_ = x[AzureDiskCSILivenessProbe-51]
+	_ = x[AzureDiskCSINodeDriverRegistar-52]
+	_ = x[AzureDiskCSIProvisioner-53]
+	_ = x[AzureDiskCSIResizer-54]
+	_ = x[AzureDiskCSISnapshotter-55]
+	_ = x[AzureDiskCSISnapshotterController-56]
+	_ = x[DigitaloceanCCM-57]
+	_ = x[DigitaloceanCSI-58]
+	_ = x[HetznerCCM-59]
+	_ = x[HetznerCSI-60]
+	_ = x[OpenstackCCM-61]
+	_ = x[OpenstackCSI-62]
+	_ = x[PacketCCM-63]
+	_ = x[VsphereCCM-64]
+	_ = x[VsphereCSIDriver-65]
+	_ = x[VsphereCSISyncer-66]
 }
 
-const _Resource_name = "CalicoCNICalicoControllerCalicoNodeFlannelCiliumCiliumOperatorHubbleRelayHubbleUIHubbleUIBackendHubbleProxyWeaveNetCNIKubeWeaveNetCNINPCDNSNodeCacheMachineControllerMetricsServerClusterAutoscalerCSIAttacherCSINodeDriverRegistarCSIProvisionerCSISnapshotterCSIResizerCSILivenessProbeAwsCCMAzureCCMAzureCNMAzureFileCSIAzureFileCSIAttacherAzureFileCSILivenessProbeAzureFileCSINodeDriverRegistarAzureFileCSIProvisionerAzureFileCSIResizerAzureFileCSISnapshotterAzureFileCSISnapshotterControllerAzureDiskCSIAzureDiskCSIAttacherAzureDiskCSILivenessProbeAzureDiskCSINodeDriverRegistarAzureDiskCSIProvisionerAzureDiskCSIResizerAzureDiskCSISnapshotterAzureDiskCSISnapshotterControllerDigitaloceanCCMHetznerCCMHetznerCSIOpenstackCCMOpenstackCSIPacketCCMVsphereCCMVsphereCSIDriverVsphereCSISyncer"
+const _Resource_name = "CalicoCNICalicoControllerCalicoNodeFlannelCiliumCiliumOperatorHubbleRelayHubbleUIHubbleUIBackendHubbleProxyWeaveNetCNIKubeWeaveNetCNINPCDNSNodeCacheMachineControllerMetricsServerClusterAutoscalerNvidiaDevicePluginKubeStateMetricsNodeExporterCertManagerControllerCertManagerCAInjectorCertManagerWebhookIngressNginxControllerFluxSourceControllerFluxKustomizeControllerVeleroVeleroPluginForAWSVeleroPluginForGCPVeleroPluginForAzureCSIAttacherCSINodeDriverRegistarCSIProvisionerCSISnapshotterCSISnapshotControllerCSIResizerCSILivenessProbeAwsCCMAwsEBSCSIAzureCCMAzureCNMAzureFileCSIAzureFileCSIAttacherAzureFileCSILivenessProbeAzureFileCSINodeDriverRegistarAzureFileCSIProvisionerAzureFileCSIResizerAzureFileCSISnapshotterAzureFileCSISnapshotterControllerAzureDiskCSIAzureDiskCSIAttacherAzureDiskCSILivenessProbeAzureDiskCSINodeDriverRegistarAzureDiskCSIProvisionerAzureDiskCSIResizerAzureDiskCSISnapshotterAzureDiskCSISnapshotterControllerDigitaloceanCCMDigitaloceanCSIHetznerCCMHetznerCSIOpenstackCCMOpenstackCSIPacketCCMVsphereCCMVsphereCSIDriverVsphereCSISyncer"
 
-var _Resource_index = [...]uint16{0, 9, 25, 35, 42, 48, 62, 73, 81, 96, 107, 122, 136, 148, 165, 178, 195, 206, 227, 241, 255, 265, 281, 287, 295, 303, 315, 335, 360, 390, 413, 432, 455, 488, 500, 520, 545, 575, 598, 617, 640, 673, 688, 698, 708, 720, 732, 741, 751, 767, 783}
+var _Resource_index = [...]uint16{0, 9, 25, 35, 42, 48, 62, 73, 81, 96, 107, 122, 136, 148, 165, 178, 195, 213, 229, 241, 262, 283, 301, 323, 343, 366, 372, 390, 408, 428, 439, 460, 474, 488, 509, 519, 535, 541, 550, 558, 566, 578, 598, 623, 653, 676, 695, 718, 751, 763, 783, 808, 838, 861, 880, 903, 936, 951, 966, 976, 986, 998, 1010, 1019, 1029, 1045, 1061}
 
 func (i Resource) String() string {
 	i -= 1
@@ -17,20 +17,52 @@ limitations under the License.
 package kubeadm
 
 import (
+	"fmt"
+
 	"github.com/Masterminds/semver/v3"
 	"github.com/pkg/errors"
 
 	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
 	"k8c.io/kubeone/pkg/state"
+	"k8c.io/kubeone/pkg/templates"
+	"k8c.io/kubeone/pkg/templates/kubeadm/kubeadmconfig"
+	"k8c.io/kubeone/pkg/templates/kubeadm/v1beta2"
+	"k8c.io/kubeone/pkg/templates/kubeadm/v1beta3"
+
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 const (
 	kubeadmUpgradeNodeCommand = "kubeadm upgrade node --certificate-renewal=true"
 )
 
-var (
-	lessThanv22x = mustParseConstraint(">= 1.15.0, < 1.22.0")
-)
+// renderer converts the kubeadm configuration to the kubeadm API version
+type renderer struct {
+	// versions are the Kubernetes versions configured using this API version
+	versions     *semver.Constraints
+	apiVersion   string
+	controlPlane func(*kubeadmconfig.Config) ([]runtime.Object, error)
+	worker       func(*kubeadmconfig.Config) ([]runtime.Object, error)
+}
+
+// renderers for all supported kubeadm API versions. The configuration is
+// built only once by the kubeadmconfig package, so supporting the new kubeadm
+// API version (e.g. kubeadm.k8s.io/v1beta4) requires only the converter and
+// the entry with the Kubernetes versions using it.
+var renderers = []renderer{
+	{
+		versions:     mustParseConstraint(">= 1.15.0, < 1.22.0"),
+		apiVersion:   "kubeadm.k8s.io/v1beta2",
+		controlPlane: v1beta2.NewConfig,
+		worker:       v1beta2.NewConfigWorker,
+	},
+	{
+		versions:     mustParseConstraint(">= 1.22.0"),
+		apiVersion:   "kubeadm.k8s.io/v1beta3",
+		controlPlane: v1beta3.NewConfig,
+		worker:       v1beta3.NewConfigWorker,
+	},
+}
 
 // Kubedm interface abstract differences between different kubeadm versions
 type Kubedm interface {
@@ -48,12 +80,83 @@ func New(ver string) (Kubedm, error) {
 		return nil, errors.Wrap(err, "failed to parse version")
 	}
 
-	switch {
-	case lessThanv22x.Check(sver):
-		return &kubeadmv1beta2{version: ver}, nil
-	default:
-		return &kubeadmv1beta3{version: ver}, nil
+	r, err := rendererFor(sver)
+	if err != nil {
+		return nil, err
 	}
+
+	return &versionedKubeadm{version: ver, renderer: r}, nil
+}
+
+// APIVersion returns the kubeadm API version used to configure the
+// Kubernetes version
+func APIVersion(ver string) (string, error) {
+	sver, err := semver.NewVersion(ver)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse version")
+	}
+
+	r, err := rendererFor(sver)
+	if err != nil {
+		return "", err
+	}
+
+	return r.apiVersion, nil
+}
+
+func rendererFor(ver *semver.Version) (renderer, error) {
+	for _, r := range renderers {
+		if r.versions.Check(ver) {
+			return r, nil
+		}
+	}
+
+	return renderer{}, errors.Errorf("kubeadm configuration for kubernetes version %s is not supported", ver)
+}
+
+type versionedKubeadm struct {
+	version  string
+	renderer renderer
+}
+
+func (k *versionedKubeadm) Config(s *state.State, instance kubeoneapi.HostConfig) (string, error) {
+	cfg, err := kubeadmconfig.NewControlPlane(s, instance)
+	if err != nil {
+		return "", err
+	}
+
+	config, err := k.renderer.controlPlane(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	return templates.KubernetesToYAML(config)
+}
+
+func (k *versionedKubeadm) ConfigWorker(s *state.State, instance kubeoneapi.HostConfig) (string, error) {
+	cfg, err := kubeadmconfig.NewWorker(s, instance)
+	if err != nil {
+		return "", err
+	}
+
+	config, err := k.renderer.worker(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	return templates.KubernetesToYAML(config)
+}
+
+func (k *versionedKubeadm) UpgradeLeaderCommand() string {
+	return fmt.Sprintf("kubeadm upgrade apply -y --certificate-renewal=true %s", k.version)
+}
+
+func (*versionedKubeadm) UpgradeFollowerCommand() string {
+	return kubeadmUpgradeNodeCommand
+}
+
+func (*versionedKubeadm) UpgradeStaticWorkerCommand() string {
+	return kubeadmUpgradeNodeCommand
 }
 
 func mustParseConstraint(constraint string) *semver.Constraints {
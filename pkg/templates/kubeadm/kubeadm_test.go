@@ -0,0 +1,274 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/sirupsen/logrus"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/apis/kubeone/config"
+	"k8c.io/kubeone/pkg/state"
+	"k8c.io/kubeone/pkg/testhelper"
+)
+
+// testKubernetesVersions are the latest patch releases of all supported
+// Kubernetes minor versions
+var testKubernetesVersions = []string{
+	"1.19.16",
+	"1.20.15",
+	"1.21.9",
+	"1.22.6",
+}
+
+const testClusterTemplate = `
+apiVersion: kubeone.io/v1beta1
+kind: KubeOneCluster
+name: test
+versions:
+  kubernetes: %s
+controlPlane:
+  hosts:
+  - publicAddress: 192.0.2.10
+    privateAddress: 10.0.0.10
+    hostname: control-plane-0
+    sshUsername: root
+    sshPrivateKeyFile: /tmp/id_rsa
+staticWorkers:
+  hosts:
+  - publicAddress: 192.0.2.20
+    privateAddress: 10.0.0.20
+    hostname: worker-0
+    sshUsername: root
+    sshPrivateKeyFile: /tmp/id_rsa
+apiEndpoint:
+  host: api.example.com
+  port: 6443
+`
+
+func TestConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		cluster string
+	}{
+		{
+			name: "defaults",
+			cluster: `
+cloudProvider:
+  none: {}
+`,
+		},
+		{
+			name: "aws-in-tree",
+			cluster: `
+cloudProvider:
+  aws: {}
+  cloudConfig: test-cloud-config
+`,
+		},
+		{
+			name: "openstack-external",
+			cluster: `
+cloudProvider:
+  openstack: {}
+  external: true
+  cloudConfig: test-cloud-config
+`,
+		},
+		{
+			name: "cis-hardening",
+			cluster: `
+cloudProvider:
+  none: {}
+hardening:
+  profile: cis
+`,
+		},
+		{
+			name: "features",
+			cluster: `
+cloudProvider:
+  none: {}
+clusterNetwork:
+  kubeProxy:
+    ipvs:
+      scheduler: lc
+assetConfiguration:
+  pause:
+    imageRepository: registry.example.com
+    imageTag: "3.5"
+features:
+  podNodeSelector:
+    enable: true
+    config:
+      configFilePath: /tmp/podnodeselector.yaml
+  podSecurityPolicy:
+    enable: true
+  staticAuditLog:
+    enable: true
+    config:
+      policyFilePath: /tmp/audit.yaml
+  encryptionProviders:
+    enable: true
+  openidConnect:
+    enable: true
+    config:
+      issuerUrl: https://dex.example.com
+      clientId: kubernetes
+`,
+		},
+		{
+			name: "ecdsa-keys",
+			cluster: `
+cloudProvider:
+  none: {}
+certificateAuthority:
+  keyAlgorithm: ECDSA
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		for _, version := range testKubernetesVersions {
+			tt := tt
+			version := version
+			t.Run(fmt.Sprintf("%s-v%s", tt.name, version), func(t *testing.T) {
+				s := testState(t, fmt.Sprintf(testClusterTemplate, version)+tt.cluster)
+
+				kubeadm, err := New(version)
+				if err != nil {
+					t.Fatalf("New() error = %v", err)
+				}
+
+				var out strings.Builder
+
+				controlPlaneConfig, err := kubeadm.Config(s, s.Cluster.ControlPlane.Hosts[0])
+				if err != nil {
+					t.Fatalf("Config() error = %v", err)
+				}
+				out.WriteString("# control plane\n")
+				out.WriteString(controlPlaneConfig)
+
+				workerConfig, err := kubeadm.ConfigWorker(s, s.Cluster.StaticWorkers.Hosts[0])
+				if err != nil {
+					t.Fatalf("ConfigWorker() error = %v", err)
+				}
+				out.WriteString("# static worker\n")
+				out.WriteString(workerConfig)
+
+				testhelper.DiffOutput(t, testhelper.FSGoldenName(t), out.String(), *updateFlag)
+			})
+		}
+	}
+}
+
+func TestConfigWorkerHostKubelet(t *testing.T) {
+	maxPods := int32(110)
+	kubelet := &kubeoneapi.KubeletConfig{
+		MaxPods:        &maxPods,
+		SystemReserved: map[string]string{"memory": "500Mi", "cpu": "200m"},
+		KubeReserved:   map[string]string{"memory": "500Mi"},
+		EvictionHard:   map[string]string{"memory.available": "100Mi"},
+	}
+
+	for _, version := range testKubernetesVersions {
+		version := version
+		t.Run(version, func(t *testing.T) {
+			s := testState(t, fmt.Sprintf(testClusterTemplate, version)+"cloudProvider:\n  none: {}\n")
+			host := s.Cluster.StaticWorkers.Hosts[0]
+			host.Kubelet = kubelet
+
+			kubeadm, err := New(version)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			workerConfig, err := kubeadm.ConfigWorker(s, host)
+			if err != nil {
+				t.Fatalf("ConfigWorker() error = %v", err)
+			}
+
+			for _, arg := range []string{
+				`max-pods: "110"`,
+				"system-reserved: cpu=200m,memory=500Mi",
+				"kube-reserved: memory=500Mi",
+				"eviction-hard: memory.available<100Mi",
+			} {
+				if !strings.Contains(workerConfig, "\n    "+arg+"\n") {
+					t.Errorf("kubeletExtraArgs of the join configuration don't include %q:\n%s", arg, workerConfig)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderers(t *testing.T) {
+	tests := []struct {
+		version        string
+		wantAPIVersion string
+	}{
+		{version: "1.19.16", wantAPIVersion: "kubeadm.k8s.io/v1beta2"},
+		{version: "1.20.15", wantAPIVersion: "kubeadm.k8s.io/v1beta2"},
+		{version: "1.21.9", wantAPIVersion: "kubeadm.k8s.io/v1beta2"},
+		{version: "1.22.0", wantAPIVersion: "kubeadm.k8s.io/v1beta3"},
+		{version: "1.22.6", wantAPIVersion: "kubeadm.k8s.io/v1beta3"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.version, func(t *testing.T) {
+			sver := semver.MustParse(tt.version)
+
+			matched := []string{}
+			for _, r := range renderers {
+				if r.versions.Check(sver) {
+					matched = append(matched, r.apiVersion)
+				}
+			}
+
+			if len(matched) != 1 || matched[0] != tt.wantAPIVersion {
+				t.Errorf("renderers for %s = %v, want [%s]", tt.version, matched, tt.wantAPIVersion)
+			}
+		})
+	}
+}
+
+func testState(t *testing.T, manifest string) *state.State {
+	t.Helper()
+
+	cluster, err := config.BytesToKubeOneCluster([]byte(manifest), nil, nil, logrus.New())
+	if err != nil {
+		t.Fatalf("failed to load the cluster manifest: %v", err)
+	}
+
+	s, err := state.New(context.Background())
+	if err != nil {
+		t.Fatalf("failed to initialize State: %v", err)
+	}
+	s.Cluster = cluster
+	s.JoinToken = "abcdef.0123456789abcdef"
+	s.LiveCluster = &state.Cluster{
+		EncryptionConfiguration: &state.EncryptionConfiguration{},
+	}
+
+	return s
+}
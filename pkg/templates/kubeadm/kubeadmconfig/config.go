@@ -0,0 +1,521 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kubeadmconfig builds the kubeadm configuration independently of
+// the kubeadm API version. The versioned renderers (see the parent package)
+// only convert the Config to the kubeadm API version supported by the
+// Kubernetes version, so all settings, including the ones depending on the
+// Kubernetes minor version, are defined only once.
+package kubeadmconfig
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/certificate"
+	"k8c.io/kubeone/pkg/features"
+	"k8c.io/kubeone/pkg/hardening"
+	"k8c.io/kubeone/pkg/kubeflags"
+	"k8c.io/kubeone/pkg/state"
+	"k8c.io/kubeone/pkg/templates/kubeadm/kubeadmargs"
+	"k8c.io/kubeone/pkg/templates/resources"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	componentbasev1alpha1 "k8s.io/component-base/config/v1alpha1"
+	kubeproxyv1alpha1 "k8s.io/kube-proxy/config/v1alpha1"
+	kubeletconfigv1beta1 "k8s.io/kubelet/config/v1beta1"
+)
+
+const (
+	// BootstrapTokenTTL is TTL of the bootstrap token used to join the nodes
+	BootstrapTokenTTL = 60 * time.Minute
+
+	renderedCloudConfig = "/etc/kubernetes/cloud-config"
+)
+
+// Config is the kubeadm configuration of the host
+type Config struct {
+	// ControlPlane is true for the control plane hosts. InitConfiguration
+	// and ClusterConfiguration are rendered only for the control plane hosts.
+	ControlPlane bool
+
+	KubernetesVersion    string
+	ClusterName          string
+	ControlPlaneEndpoint string
+	AdvertiseAddress     string
+	JoinToken            string
+	ImageRepository      string
+	CertSANs             []string
+	Networking           Networking
+	Etcd                 ImageMeta
+	DNS                  ImageMeta
+	APIServer            ControlPlaneComponent
+	ControllerManager    ControlPlaneComponent
+	Scheduler            ControlPlaneComponent
+	FeatureGates         map[string]bool
+	NodeRegistration     NodeRegistration
+
+	Kubelet   *kubeletconfigv1beta1.KubeletConfiguration
+	KubeProxy *kubeproxyv1alpha1.KubeProxyConfiguration
+}
+
+// Networking is the cluster networking configuration
+type Networking struct {
+	PodSubnet     string
+	ServiceSubnet string
+	DNSDomain     string
+}
+
+// ImageMeta is the image repository and tag of the control plane component
+type ImageMeta struct {
+	ImageRepository string
+	ImageTag        string
+}
+
+// ControlPlaneComponent is the configuration of the control plane component
+type ControlPlaneComponent struct {
+	ExtraArgs    map[string]string
+	ExtraVolumes []HostPathMount
+}
+
+// HostPathMount is the volume mounted from the host to the control plane
+// component
+type HostPathMount struct {
+	Name      string
+	HostPath  string
+	MountPath string
+	ReadOnly  bool
+	PathType  corev1.HostPathType
+}
+
+// NodeRegistration is the configuration of the Node object of the host
+type NodeRegistration struct {
+	Name                  string
+	CRISocket             string
+	Taints                []corev1.Taint
+	KubeletExtraArgs      map[string]string
+	IgnorePreflightErrors []string
+}
+
+// NewControlPlane returns the kubeadm configuration of the control plane host
+func NewControlPlane(s *state.State, host kubeoneapi.HostConfig) (*Config, error) {
+	cluster := s.Cluster
+	kubeSemVer, err := semver.NewVersion(cluster.Versions.Kubernetes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse generate config, wrong kubernetes version %s", cluster.Versions.Kubernetes)
+	}
+
+	cfg := newConfig(s, host)
+	cfg.ControlPlane = true
+	cfg.NodeRegistration.IgnorePreflightErrors = kubeoneapi.MergePreflightErrors(
+		[]string{
+			"DirAvailable--var-lib-etcd",
+			"DirAvailable--etc-kubernetes-manifests",
+			"ImagePull",
+		},
+		cluster.IgnorePreflightErrorsConfig(host).Install,
+	)
+
+	cfg.KubernetesVersion = cluster.Versions.Kubernetes
+	cfg.ClusterName = cluster.Name
+	cfg.ImageRepository = cluster.AssetConfiguration.Kubernetes.ImageRepository
+	cfg.CertSANs = certificate.GetCertificateSANs(cluster.APIEndpoint.Host, cluster.APIEndpoint.AlternativeNames)
+	cfg.Networking = Networking{
+		PodSubnet:     cluster.ClusterNetwork.PodSubnet,
+		ServiceSubnet: cluster.ClusterNetwork.ServiceSubnet,
+		DNSDomain:     cluster.ClusterNetwork.ServiceDomainName,
+	}
+	cfg.Etcd = ImageMeta{
+		ImageRepository: cluster.AssetConfiguration.Etcd.ImageRepository,
+		ImageTag:        cluster.AssetConfiguration.Etcd.ImageTag,
+	}
+	cfg.DNS = ImageMeta{
+		ImageRepository: cluster.AssetConfiguration.CoreDNS.ImageRepository,
+		ImageTag:        cluster.AssetConfiguration.CoreDNS.ImageTag,
+	}
+	cfg.APIServer = ControlPlaneComponent{
+		ExtraArgs: map[string]string{
+			"endpoint-reconciler-type": "lease",
+			"service-node-port-range":  cluster.ClusterNetwork.NodePortRange,
+			"enable-admission-plugins": kubeflags.DefaultAdmissionControllers(kubeSemVer),
+		},
+	}
+	cfg.ControllerManager = ControlPlaneComponent{
+		ExtraArgs: map[string]string{
+			"flex-volume-plugin-dir": "/var/lib/kubelet/volumeplugins",
+		},
+	}
+
+	if s.ShouldEnableInTreeCloudProvider() {
+		cloudConfigVol := HostPathMount{
+			Name:      "cloud-config",
+			HostPath:  renderedCloudConfig,
+			MountPath: renderedCloudConfig,
+			ReadOnly:  true,
+			PathType:  corev1.HostPathFile,
+		}
+		provider := cluster.CloudProvider.CloudProviderName()
+
+		cfg.APIServer.ExtraArgs["cloud-provider"] = provider
+		cfg.APIServer.ExtraArgs["cloud-config"] = renderedCloudConfig
+		cfg.APIServer.ExtraVolumes = append(cfg.APIServer.ExtraVolumes, cloudConfigVol)
+
+		cfg.ControllerManager.ExtraArgs["cloud-provider"] = provider
+		cfg.ControllerManager.ExtraArgs["cloud-config"] = renderedCloudConfig
+		cfg.ControllerManager.ExtraArgs["cluster-name"] = s.Cluster.Name
+		cfg.ControllerManager.ExtraVolumes = append(cfg.ControllerManager.ExtraVolumes, cloudConfigVol)
+
+		switch {
+		case cluster.CloudProvider.Azure != nil:
+			cfg.ControllerManager.ExtraArgs["configure-cloud-routes"] = "false"
+		case cluster.CloudProvider.AWS != nil:
+			cfg.ControllerManager.ExtraArgs["configure-cloud-routes"] = "false"
+		}
+	}
+
+	if cluster.CloudProvider.External {
+		if !s.ShouldEnableInTreeCloudProvider() {
+			delete(cfg.APIServer.ExtraArgs, "cloud-provider")
+			delete(cfg.ControllerManager.ExtraArgs, "cloud-provider")
+		} else {
+			// .cloudProvider.external enabled, but in-tree cloud provider should be enabled
+			// means that we're in the CCM migration process.
+			// In that case, we should leave cloud-provider flags in place, but explicitly
+			// disable CCM-related controllers.
+			cfg.ControllerManager.ExtraArgs["controllers"] = "*,bootstrapsigner,tokencleaner,-cloud-node-lifecycle,-route,-service"
+		}
+
+		if s.ShouldEnableCSIMigration() {
+			_, featureGatesFlag, err := s.Cluster.CSIMigrationFeatureGates(s.ShouldUnregisterInTreeCloudProvider())
+			if err != nil {
+				return nil, err
+			}
+
+			appendFeatureGatesFlag(cfg.APIServer.ExtraArgs, featureGatesFlag)
+			appendFeatureGatesFlag(cfg.ControllerManager.ExtraArgs, featureGatesFlag)
+		}
+	}
+
+	if cluster.Features.StaticAuditLog != nil && cluster.Features.StaticAuditLog.Enable {
+		auditPolicyVol := HostPathMount{
+			Name:      "audit-conf",
+			HostPath:  "/etc/kubernetes/audit",
+			MountPath: "/etc/kubernetes/audit",
+			ReadOnly:  true,
+			PathType:  corev1.HostPathDirectoryOrCreate,
+		}
+		logVol := HostPathMount{
+			Name:      "log",
+			HostPath:  filepath.Dir(cluster.Features.StaticAuditLog.Config.LogPath),
+			MountPath: filepath.Dir(cluster.Features.StaticAuditLog.Config.LogPath),
+			ReadOnly:  false,
+			PathType:  corev1.HostPathDirectoryOrCreate,
+		}
+		cfg.APIServer.ExtraVolumes = append(cfg.APIServer.ExtraVolumes, auditPolicyVol, logVol)
+	}
+
+	if cluster.Features.PodNodeSelector != nil && cluster.Features.PodNodeSelector.Enable {
+		admissionVol := HostPathMount{
+			Name:      "admission-conf",
+			HostPath:  "/etc/kubernetes/admission",
+			MountPath: "/etc/kubernetes/admission",
+			ReadOnly:  true,
+			PathType:  corev1.HostPathDirectoryOrCreate,
+		}
+		cfg.APIServer.ExtraVolumes = append(cfg.APIServer.ExtraVolumes, admissionVol)
+	}
+
+	// this is not exactly as s.EncryptionEnabled(). We need this to be true during the enable/disable or disable/enable transition.
+	if (cluster.Features.EncryptionProviders != nil && cluster.Features.EncryptionProviders.Enable) ||
+		s.LiveCluster.EncryptionConfiguration.Enable {
+		encryptionProvidersVol := HostPathMount{
+			Name:      "encryption-providers-conf",
+			HostPath:  "/etc/kubernetes/encryption-providers",
+			MountPath: "/etc/kubernetes/encryption-providers",
+			ReadOnly:  true,
+			PathType:  corev1.HostPathDirectoryOrCreate,
+		}
+		cfg.APIServer.ExtraVolumes = append(cfg.APIServer.ExtraVolumes, encryptionProvidersVol)
+
+		// Handle external KMS case.
+		if s.LiveCluster.CustomEncryptionEnabled() ||
+			s.Cluster.Features.EncryptionProviders != nil && s.Cluster.Features.EncryptionProviders.CustomEncryptionConfiguration != "" {
+			ksmSocket, err := s.GetKMSSocketPath()
+			if err != nil {
+				return nil, err
+			}
+			if ksmSocket != "" {
+				cfg.APIServer.ExtraVolumes = append(cfg.APIServer.ExtraVolumes, HostPathMount{
+					Name:      "kms-endpoint",
+					HostPath:  ksmSocket,
+					MountPath: ksmSocket,
+					PathType:  corev1.HostPathSocket,
+				})
+			}
+		}
+	}
+
+	if cluster.CISHardeningEnabled() {
+		for k, v := range hardening.APIServerArgs() {
+			cfg.APIServer.ExtraArgs[k] = v
+		}
+		for k, v := range hardening.ControllerManagerArgs() {
+			cfg.ControllerManager.ExtraArgs[k] = v
+		}
+		cfg.Scheduler.ExtraArgs = hardening.SchedulerArgs()
+	}
+
+	args := kubeadmargs.NewFrom(cfg.APIServer.ExtraArgs)
+	features.UpdateKubeadmClusterConfiguration(cluster.Features, args)
+
+	cfg.APIServer.ExtraArgs = args.APIServer.ExtraArgs
+	cfg.FeatureGates = args.FeatureGates
+
+	// kubeadm generates RSA keys unless the PublicKeysECDSA feature gate is enabled
+	if cluster.KubeadmKeyAlgorithm() == kubeoneapi.KeyAlgorithmECDSA {
+		cfg.FeatureGates["PublicKeysECDSA"] = true
+	}
+
+	if err := setKubeletFeatureGates(s, cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// NewWorker returns the kubeadm configuration of the static worker host
+func NewWorker(s *state.State, host kubeoneapi.HostConfig) (*Config, error) {
+	cfg := newConfig(s, host)
+	cfg.NodeRegistration.IgnorePreflightErrors = kubeoneapi.MergePreflightErrors(
+		[]string{
+			"DirAvailable--etc-kubernetes-manifests",
+		},
+		s.Cluster.IgnorePreflightErrorsConfig(host).Install,
+	)
+
+	if err := setKubeletFeatureGates(s, cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// newConfig returns the configuration shared by all hosts
+func newConfig(s *state.State, host kubeoneapi.HostConfig) *Config {
+	cluster := s.Cluster
+
+	cfg := &Config{
+		ControlPlaneEndpoint: fmt.Sprintf("%s:%d", cluster.APIEndpoint.Host, cluster.APIEndpoint.Port),
+		AdvertiseAddress:     NodeIP(host),
+		JoinToken:            s.JoinToken,
+		NodeRegistration: NodeRegistration{
+			Name:      host.Hostname,
+			Taints:    host.Taints,
+			CRISocket: cluster.ContainerRuntime.CRISocket(),
+			KubeletExtraArgs: map[string]string{
+				"node-ip":           NodeIP(host),
+				"volume-plugin-dir": "/var/lib/kubelet/volumeplugins",
+			},
+		},
+		Kubelet:   kubeletConfiguration(cluster),
+		KubeProxy: kubeProxyConfiguration(s),
+	}
+
+	if cluster.CISHardeningEnabled() {
+		hardening.ApplyKubelet(cfg.Kubelet)
+	}
+
+	kubeletArgs := cfg.NodeRegistration.KubeletExtraArgs
+	if cluster.AssetConfiguration.Pause.ImageRepository != "" {
+		kubeletArgs["pod-infra-container-image"] = cluster.AssetConfiguration.Pause.ImageRepository + "/pause:" + cluster.AssetConfiguration.Pause.ImageTag
+	}
+
+	nodeLabels := host.Labels
+	if host.NvidiaGPU {
+		nodeLabels = labels.Merge(host.Labels, map[string]string{resources.NvidiaGPULabel: "true"})
+	}
+
+	if len(nodeLabels) > 0 {
+		labelFlags := []string{}
+		for k, v := range nodeLabels {
+			labelFlags = append(labelFlags, k+"="+v)
+		}
+		sort.Strings(labelFlags)
+		kubeletArgs["node-labels"] = strings.Join(labelFlags, ",")
+	}
+
+	setHostKubeletArgs(kubeletArgs, host.Kubelet)
+
+	if s.ShouldEnableInTreeCloudProvider() {
+		kubeletArgs["cloud-provider"] = cluster.CloudProvider.CloudProviderName()
+		kubeletArgs["cloud-config"] = renderedCloudConfig
+	}
+
+	if cluster.CloudProvider.External && !s.ShouldEnableInTreeCloudProvider() {
+		kubeletArgs["cloud-provider"] = "external"
+	}
+
+	return cfg
+}
+
+// hostKubeletFlags are the kubelet flags set from the host kubelet configuration
+var hostKubeletFlags = []string{"max-pods", "system-reserved", "kube-reserved", "eviction-hard"}
+
+// HostKubeletFlags returns the names of the kubelet flags set from the host
+// kubelet configuration, and the values of the flags configured for the host.
+// The flags which are not configured are not in the returned map.
+func HostKubeletFlags(kubelet *kubeoneapi.KubeletConfig) ([]string, map[string]string) {
+	args := map[string]string{}
+	setHostKubeletArgs(args, kubelet)
+
+	return append([]string{}, hostKubeletFlags...), args
+}
+
+// setHostKubeletArgs sets the kubelet flags configured for the host
+func setHostKubeletArgs(args map[string]string, kubelet *kubeoneapi.KubeletConfig) {
+	if kubelet == nil {
+		return
+	}
+
+	if kubelet.MaxPods != nil {
+		args["max-pods"] = fmt.Sprint(*kubelet.MaxPods)
+	}
+	if len(kubelet.SystemReserved) > 0 {
+		args["system-reserved"] = joinKeyValues(kubelet.SystemReserved, "=")
+	}
+	if len(kubelet.KubeReserved) > 0 {
+		args["kube-reserved"] = joinKeyValues(kubelet.KubeReserved, "=")
+	}
+	if len(kubelet.EvictionHard) > 0 {
+		args["eviction-hard"] = joinKeyValues(kubelet.EvictionHard, "<")
+	}
+}
+
+// joinKeyValues returns the map as the sorted list of key-value pairs, as
+// expected by the kubelet flags
+func joinKeyValues(m map[string]string, sep string) string {
+	pairs := []string{}
+	for k, v := range m {
+		pairs = append(pairs, k+sep+v)
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+// setKubeletFeatureGates enables the CSI migration feature gates in kubelet
+func setKubeletFeatureGates(s *state.State, cfg *Config) error {
+	if !s.Cluster.CloudProvider.External || !s.ShouldEnableCSIMigration() {
+		return nil
+	}
+
+	featureGates, _, err := s.Cluster.CSIMigrationFeatureGates(s.ShouldUnregisterInTreeCloudProvider())
+	if err != nil {
+		return err
+	}
+
+	for k, v := range featureGates {
+		cfg.Kubelet.FeatureGates[k] = v
+	}
+
+	return nil
+}
+
+// appendFeatureGatesFlag appends the feature gates to the --feature-gates flag
+func appendFeatureGatesFlag(args map[string]string, featureGatesFlag string) {
+	if fg, ok := args["feature-gates"]; ok && len(fg) > 0 {
+		args["feature-gates"] = fmt.Sprintf("%s,%s", fg, featureGatesFlag)
+	} else {
+		args["feature-gates"] = featureGatesFlag
+	}
+}
+
+// NodeIP returns the IP address the node is advertised on
+func NodeIP(host kubeoneapi.HostConfig) string {
+	nodeIP := host.PrivateAddress
+	if nodeIP == "" {
+		nodeIP = host.PublicAddress
+	}
+
+	return nodeIP
+}
+
+func kubeletConfiguration(cluster *kubeoneapi.KubeOneCluster) *kubeletconfigv1beta1.KubeletConfiguration {
+	bfalse := false
+
+	cgroupDriver := "systemd"
+	if cluster.ContainerRuntime.Containerd != nil {
+		cgroupDriver = cluster.ContainerRuntime.Containerd.CgroupDriverName()
+	}
+
+	return &kubeletconfigv1beta1.KubeletConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "kubelet.config.k8s.io/v1beta1",
+			Kind:       "KubeletConfiguration",
+		},
+		CgroupDriver:       cgroupDriver,
+		ReadOnlyPort:       0,
+		RotateCertificates: true,
+		ClusterDNS:         []string{resources.NodeLocalDNSVirtualIP},
+		Authentication: kubeletconfigv1beta1.KubeletAuthentication{
+			Anonymous: kubeletconfigv1beta1.KubeletAnonymousAuthentication{
+				Enabled: &bfalse,
+			},
+		},
+		FeatureGates: map[string]bool{},
+	}
+}
+
+func kubeProxyConfiguration(s *state.State) *kubeproxyv1alpha1.KubeProxyConfiguration {
+	kubeProxyConfig := &kubeproxyv1alpha1.KubeProxyConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "KubeProxyConfiguration",
+			APIVersion: "kubeproxy.config.k8s.io/v1alpha1",
+		},
+		ClusterCIDR: s.Cluster.ClusterNetwork.PodSubnet,
+		ClientConnection: componentbasev1alpha1.ClientConnectionConfiguration{
+			Kubeconfig: "/var/lib/kube-proxy/kubeconfig.conf",
+		},
+	}
+
+	if kbPrx := s.Cluster.ClusterNetwork.KubeProxy; kbPrx != nil {
+		switch {
+		case kbPrx.IPVS != nil:
+			kubeProxyConfig.Mode = kubeproxyv1alpha1.ProxyMode("ipvs")
+			kubeProxyConfig.IPVS = kubeproxyv1alpha1.KubeProxyIPVSConfiguration{
+				StrictARP:     kbPrx.IPVS.StrictARP,
+				Scheduler:     kbPrx.IPVS.Scheduler,
+				ExcludeCIDRs:  kbPrx.IPVS.ExcludeCIDRs,
+				TCPTimeout:    kbPrx.IPVS.TCPTimeout,
+				TCPFinTimeout: kbPrx.IPVS.TCPFinTimeout,
+				UDPTimeout:    kbPrx.IPVS.UDPTimeout,
+			}
+		case kbPrx.IPTables != nil:
+			kubeProxyConfig.Mode = kubeproxyv1alpha1.ProxyMode("iptables")
+		}
+	}
+
+	return kubeProxyConfig
+}
@@ -17,59 +17,29 @@ limitations under the License.
 package v1beta2
 
 import (
-	"fmt"
-	"path/filepath"
-	"time"
-
-	"github.com/Masterminds/semver/v3"
-	"github.com/pkg/errors"
-
 	kubeadmv1beta2 "k8c.io/kubeone/pkg/apis/kubeadm/v1beta2"
-	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
-	"k8c.io/kubeone/pkg/certificate"
-	"k8c.io/kubeone/pkg/features"
-	"k8c.io/kubeone/pkg/kubeflags"
-	"k8c.io/kubeone/pkg/state"
-	"k8c.io/kubeone/pkg/templates/kubeadm/kubeadmargs"
-	"k8c.io/kubeone/pkg/templates/resources"
+	"k8c.io/kubeone/pkg/templates/kubeadm/kubeadmconfig"
 
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	componentbasev1alpha1 "k8s.io/component-base/config/v1alpha1"
-	kubeproxyv1alpha1 "k8s.io/kube-proxy/config/v1alpha1"
-	kubeletconfigv1beta1 "k8s.io/kubelet/config/v1beta1"
 )
 
 const (
-	bootstrapTokenTTL = 60 * time.Minute
+	apiVersion = "kubeadm.k8s.io/v1beta2"
 )
 
 // NewConfig returns all required configs to init a cluster via a set of v1beta2 configs
-func NewConfig(s *state.State, host kubeoneapi.HostConfig) ([]runtime.Object, error) {
-	cluster := s.Cluster
-	kubeSemVer, err := semver.NewVersion(cluster.Versions.Kubernetes)
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to parse generate config, wrong kubernetes version %s", cluster.Versions.Kubernetes)
-	}
-
-	nodeRegistration := newNodeRegistration(s, host)
-	nodeRegistration.IgnorePreflightErrors = []string{
-		"DirAvailable--var-lib-etcd",
-		"DirAvailable--etc-kubernetes-manifests",
-		"ImagePull",
-	}
-
-	bootstrapToken, err := kubeadmv1beta2.NewBootstrapTokenString(s.JoinToken)
+func NewConfig(cfg *kubeadmconfig.Config) ([]runtime.Object, error) {
+	bootstrapToken, err := kubeadmv1beta2.NewBootstrapTokenString(cfg.JoinToken)
 	if err != nil {
 		return nil, err
 	}
 
-	controlPlaneEndpoint := fmt.Sprintf("%s:%d", cluster.APIEndpoint.Host, cluster.APIEndpoint.Port)
+	nodeRegistration := newNodeRegistration(cfg.NodeRegistration)
 
 	initConfig := &kubeadmv1beta2.InitConfiguration{
 		TypeMeta: metav1.TypeMeta{
-			APIVersion: "kubeadm.k8s.io/v1beta2",
+			APIVersion: apiVersion,
 			Kind:       "InitConfiguration",
 		},
 		BootstrapTokens: []kubeadmv1beta2.BootstrapToken{
@@ -79,7 +49,7 @@ func NewConfig(s *state.State, host kubeoneapi.HostConfig) ([]runtime.Object, er
 					"system:bootstrappers:kubeadm:default-node-token",
 				},
 				TTL: &metav1.Duration{
-					Duration: bootstrapTokenTTL,
+					Duration: kubeadmconfig.BootstrapTokenTTL,
 				},
 				Usages: []string{
 					"signing",
@@ -88,369 +58,108 @@ func NewConfig(s *state.State, host kubeoneapi.HostConfig) ([]runtime.Object, er
 			},
 		},
 		LocalAPIEndpoint: kubeadmv1beta2.APIEndpoint{
-			AdvertiseAddress: newNodeIP(host),
+			AdvertiseAddress: cfg.AdvertiseAddress,
 		},
+		NodeRegistration: nodeRegistration,
 	}
 
-	joinConfig := &kubeadmv1beta2.JoinConfiguration{
-		TypeMeta: metav1.TypeMeta{
-			APIVersion: "kubeadm.k8s.io/v1beta2",
-			Kind:       "JoinConfiguration",
-		},
-		ControlPlane: &kubeadmv1beta2.JoinControlPlane{
-			LocalAPIEndpoint: kubeadmv1beta2.APIEndpoint{
-				AdvertiseAddress: newNodeIP(host),
-			},
-		},
-		Discovery: kubeadmv1beta2.Discovery{
-			BootstrapToken: &kubeadmv1beta2.BootstrapTokenDiscovery{
-				Token:                    s.JoinToken,
-				APIServerEndpoint:        controlPlaneEndpoint,
-				UnsafeSkipCAVerification: true,
-			},
+	joinConfig := newJoinConfiguration(cfg, nodeRegistration)
+	joinConfig.ControlPlane = &kubeadmv1beta2.JoinControlPlane{
+		LocalAPIEndpoint: kubeadmv1beta2.APIEndpoint{
+			AdvertiseAddress: cfg.AdvertiseAddress,
 		},
 	}
 
-	certSANS := certificate.GetCertificateSANs(cluster.APIEndpoint.Host, cluster.APIEndpoint.AlternativeNames)
-
 	clusterConfig := &kubeadmv1beta2.ClusterConfiguration{
 		TypeMeta: metav1.TypeMeta{
-			APIVersion: "kubeadm.k8s.io/v1beta2",
+			APIVersion: apiVersion,
 			Kind:       "ClusterConfiguration",
 		},
 		Networking: kubeadmv1beta2.Networking{
-			PodSubnet:     cluster.ClusterNetwork.PodSubnet,
-			ServiceSubnet: cluster.ClusterNetwork.ServiceSubnet,
-			DNSDomain:     cluster.ClusterNetwork.ServiceDomainName,
+			PodSubnet:     cfg.Networking.PodSubnet,
+			ServiceSubnet: cfg.Networking.ServiceSubnet,
+			DNSDomain:     cfg.Networking.DNSDomain,
 		},
-		KubernetesVersion:    cluster.Versions.Kubernetes,
-		ControlPlaneEndpoint: controlPlaneEndpoint,
+		KubernetesVersion:    cfg.KubernetesVersion,
+		ControlPlaneEndpoint: cfg.ControlPlaneEndpoint,
 		APIServer: kubeadmv1beta2.APIServer{
-			ControlPlaneComponent: kubeadmv1beta2.ControlPlaneComponent{
-				ExtraArgs: map[string]string{
-					"endpoint-reconciler-type": "lease",
-					"service-node-port-range":  cluster.ClusterNetwork.NodePortRange,
-					"enable-admission-plugins": kubeflags.DefaultAdmissionControllers(kubeSemVer),
-				},
-				ExtraVolumes: []kubeadmv1beta2.HostPathMount{},
-			},
-			CertSANs: certSANS,
-		},
-		ControllerManager: kubeadmv1beta2.ControlPlaneComponent{
-			ExtraArgs: map[string]string{
-				"flex-volume-plugin-dir": "/var/lib/kubelet/volumeplugins",
-			},
-			ExtraVolumes: []kubeadmv1beta2.HostPathMount{},
+			ControlPlaneComponent: newControlPlaneComponent(cfg.APIServer),
+			CertSANs:              cfg.CertSANs,
 		},
-		ClusterName:     cluster.Name,
-		ImageRepository: cluster.AssetConfiguration.Kubernetes.ImageRepository,
+		ControllerManager: newControlPlaneComponent(cfg.ControllerManager),
+		Scheduler:         newControlPlaneComponent(cfg.Scheduler),
+		ClusterName:       cfg.ClusterName,
+		ImageRepository:   cfg.ImageRepository,
 		Etcd: kubeadmv1beta2.Etcd{
 			Local: &kubeadmv1beta2.LocalEtcd{
-				ImageMeta: kubeadmv1beta2.ImageMeta{
-					ImageRepository: cluster.AssetConfiguration.Etcd.ImageRepository,
-					ImageTag:        cluster.AssetConfiguration.Etcd.ImageTag,
-				},
+				ImageMeta: newImageMeta(cfg.Etcd),
 			},
 		},
 		DNS: kubeadmv1beta2.DNS{
-			ImageMeta: kubeadmv1beta2.ImageMeta{
-				ImageRepository: cluster.AssetConfiguration.CoreDNS.ImageRepository,
-				ImageTag:        cluster.AssetConfiguration.CoreDNS.ImageTag,
-			},
+			ImageMeta: newImageMeta(cfg.DNS),
 		},
+		FeatureGates: cfg.FeatureGates,
 	}
 
-	bfalse := false
-	kubeletConfig := &kubeletconfigv1beta1.KubeletConfiguration{
-		TypeMeta: metav1.TypeMeta{
-			APIVersion: "kubelet.config.k8s.io/v1beta1",
-			Kind:       "KubeletConfiguration",
-		},
-		CgroupDriver:       "systemd",
-		ReadOnlyPort:       0,
-		RotateCertificates: true,
-		ClusterDNS:         []string{resources.NodeLocalDNSVirtualIP},
-		Authentication: kubeletconfigv1beta1.KubeletAuthentication{
-			Anonymous: kubeletconfigv1beta1.KubeletAnonymousAuthentication{
-				Enabled: &bfalse,
-			},
-		},
-		FeatureGates: map[string]bool{},
-	}
-
-	if cluster.AssetConfiguration.Pause.ImageRepository != "" {
-		nodeRegistration.KubeletExtraArgs["pod-infra-container-image"] = cluster.AssetConfiguration.Pause.ImageRepository + "/pause:" + cluster.AssetConfiguration.Pause.ImageTag
-	}
-
-	if s.ShouldEnableInTreeCloudProvider() {
-		renderedCloudConfig := "/etc/kubernetes/cloud-config"
-		cloudConfigVol := kubeadmv1beta2.HostPathMount{
-			Name:      "cloud-config",
-			HostPath:  renderedCloudConfig,
-			MountPath: renderedCloudConfig,
-			ReadOnly:  true,
-			PathType:  corev1.HostPathFile,
-		}
-		provider := cluster.CloudProvider.CloudProviderName()
-
-		clusterConfig.APIServer.ExtraArgs["cloud-provider"] = provider
-		clusterConfig.APIServer.ExtraArgs["cloud-config"] = renderedCloudConfig
-		clusterConfig.APIServer.ExtraVolumes = append(clusterConfig.APIServer.ExtraVolumes, cloudConfigVol)
-
-		clusterConfig.ControllerManager.ExtraArgs["cloud-provider"] = provider
-		clusterConfig.ControllerManager.ExtraArgs["cloud-config"] = renderedCloudConfig
-		clusterConfig.ControllerManager.ExtraArgs["cluster-name"] = s.Cluster.Name
-		clusterConfig.ControllerManager.ExtraVolumes = append(clusterConfig.ControllerManager.ExtraVolumes, cloudConfigVol)
-
-		nodeRegistration.KubeletExtraArgs["cloud-provider"] = provider
-		nodeRegistration.KubeletExtraArgs["cloud-config"] = renderedCloudConfig
-
-		switch {
-		case cluster.CloudProvider.Azure != nil:
-			clusterConfig.ControllerManager.ExtraArgs["configure-cloud-routes"] = "false"
-		case cluster.CloudProvider.AWS != nil:
-			clusterConfig.ControllerManager.ExtraArgs["configure-cloud-routes"] = "false"
-		}
-	}
-
-	if cluster.CloudProvider.External {
-		if !s.ShouldEnableInTreeCloudProvider() {
-			delete(clusterConfig.APIServer.ExtraArgs, "cloud-provider")
-			delete(clusterConfig.ControllerManager.ExtraArgs, "cloud-provider")
-			nodeRegistration.KubeletExtraArgs["cloud-provider"] = "external"
-		} else {
-			// .cloudProvider.external enabled, but in-tree cloud provider should be enabled
-			// means that we're in the CCM migration process.
-			// In that case, we should leave cloud-provider flags in place, but explicitly
-			// disable CCM-related controllers.
-			clusterConfig.ControllerManager.ExtraArgs["controllers"] = "*,bootstrapsigner,tokencleaner,-cloud-node-lifecycle,-route,-service"
-		}
-
-		if s.ShouldEnableCSIMigration() {
-			featureGates, featureGatesFlag, err := s.Cluster.CSIMigrationFeatureGates(s.ShouldUnregisterInTreeCloudProvider())
-			if err != nil {
-				return nil, err
-			}
-
-			// Kubernetes API server
-			if fg, ok := clusterConfig.APIServer.ExtraArgs["feature-gates"]; ok && len(fg) > 0 {
-				clusterConfig.APIServer.ExtraArgs["feature-gates"] = fmt.Sprintf("%s,%s", clusterConfig.APIServer.ExtraArgs["feature-gates"], featureGatesFlag)
-			} else {
-				clusterConfig.APIServer.ExtraArgs["feature-gates"] = featureGatesFlag
-			}
-
-			// Kubernetes Controller Manager
-			if fg, ok := clusterConfig.ControllerManager.ExtraArgs["feature-gates"]; ok && len(fg) > 0 {
-				clusterConfig.ControllerManager.ExtraArgs["feature-gates"] = fmt.Sprintf("%s,%s", clusterConfig.ControllerManager.ExtraArgs["feature-gates"], featureGatesFlag)
-			} else {
-				clusterConfig.ControllerManager.ExtraArgs["feature-gates"] = featureGatesFlag
-			}
-
-			// Kubelet
-			for k, v := range featureGates {
-				kubeletConfig.FeatureGates[k] = v
-			}
-		}
-	}
-
-	if cluster.Features.StaticAuditLog != nil && cluster.Features.StaticAuditLog.Enable {
-		auditPolicyVol := kubeadmv1beta2.HostPathMount{
-			Name:      "audit-conf",
-			HostPath:  "/etc/kubernetes/audit",
-			MountPath: "/etc/kubernetes/audit",
-			ReadOnly:  true,
-			PathType:  corev1.HostPathDirectoryOrCreate,
-		}
-		logVol := kubeadmv1beta2.HostPathMount{
-			Name:      "log",
-			HostPath:  filepath.Dir(cluster.Features.StaticAuditLog.Config.LogPath),
-			MountPath: filepath.Dir(cluster.Features.StaticAuditLog.Config.LogPath),
-			ReadOnly:  false,
-			PathType:  corev1.HostPathDirectoryOrCreate,
-		}
-		clusterConfig.APIServer.ExtraVolumes = append(clusterConfig.APIServer.ExtraVolumes, auditPolicyVol)
-		clusterConfig.APIServer.ExtraVolumes = append(clusterConfig.APIServer.ExtraVolumes, logVol)
-	}
-
-	if cluster.Features.PodNodeSelector != nil && cluster.Features.PodNodeSelector.Enable {
-		admissionVol := kubeadmv1beta2.HostPathMount{
-			Name:      "admission-conf",
-			HostPath:  "/etc/kubernetes/admission",
-			MountPath: "/etc/kubernetes/admission",
-			ReadOnly:  true,
-			PathType:  corev1.HostPathDirectoryOrCreate,
-		}
-		clusterConfig.APIServer.ExtraVolumes = append(clusterConfig.APIServer.ExtraVolumes, admissionVol)
-	}
-	// this is not exactly as s.EncryptionEnabled(). We need this to be true during the enable/disable or disable/enable transition.
-	if (cluster.Features.EncryptionProviders != nil && cluster.Features.EncryptionProviders.Enable) ||
-		s.LiveCluster.EncryptionConfiguration.Enable {
-		encryptionProvidersVol := kubeadmv1beta2.HostPathMount{
-			Name:      "encryption-providers-conf",
-			HostPath:  "/etc/kubernetes/encryption-providers",
-			MountPath: "/etc/kubernetes/encryption-providers",
-			ReadOnly:  true,
-			PathType:  corev1.HostPathDirectoryOrCreate,
-		}
-		clusterConfig.APIServer.ExtraVolumes = append(clusterConfig.APIServer.ExtraVolumes, encryptionProvidersVol)
-
-		// Handle external KMS case.
-		if s.LiveCluster.CustomEncryptionEnabled() ||
-			s.Cluster.Features.EncryptionProviders != nil && s.Cluster.Features.EncryptionProviders.CustomEncryptionConfiguration != "" {
-			ksmSocket, err := s.GetKMSSocketPath()
-			if err != nil {
-				return nil, err
-			}
-			if ksmSocket != "" {
-				clusterConfig.APIServer.ExtraVolumes = append(clusterConfig.APIServer.ExtraVolumes, kubeadmv1beta2.HostPathMount{
-					Name:      "kms-endpoint",
-					HostPath:  ksmSocket,
-					MountPath: ksmSocket,
-					PathType:  corev1.HostPathSocket,
-				})
-			}
-		}
-	}
-
-	args := kubeadmargs.NewFrom(clusterConfig.APIServer.ExtraArgs)
-	features.UpdateKubeadmClusterConfiguration(cluster.Features, args)
-
-	clusterConfig.APIServer.ExtraArgs = args.APIServer.ExtraArgs
-	clusterConfig.FeatureGates = args.FeatureGates
-
-	initConfig.NodeRegistration = nodeRegistration
-	joinConfig.NodeRegistration = nodeRegistration
-
-	kubeproxyConfig := kubeProxyConfiguration(s)
-
-	return []runtime.Object{initConfig, joinConfig, clusterConfig, kubeletConfig, kubeproxyConfig}, nil
+	return []runtime.Object{initConfig, joinConfig, clusterConfig, cfg.Kubelet, cfg.KubeProxy}, nil
 }
 
-// NewConfigWorker returns all required configs to init a cluster via a set of v1beta2 configs
-func NewConfigWorker(s *state.State, host kubeoneapi.HostConfig) ([]runtime.Object, error) {
-	cluster := s.Cluster
-
-	nodeRegistration := newNodeRegistration(s, host)
-	nodeRegistration.IgnorePreflightErrors = []string{
-		"DirAvailable--etc-kubernetes-manifests",
-	}
+// NewConfigWorker returns all required configs to join a static worker via a set of v1beta2 configs
+func NewConfigWorker(cfg *kubeadmconfig.Config) ([]runtime.Object, error) {
+	joinConfig := newJoinConfiguration(cfg, newNodeRegistration(cfg.NodeRegistration))
 
-	controlPlaneEndpoint := fmt.Sprintf("%s:%d", cluster.APIEndpoint.Host, cluster.APIEndpoint.Port)
+	return []runtime.Object{joinConfig, cfg.Kubelet, cfg.KubeProxy}, nil
+}
 
-	joinConfig := &kubeadmv1beta2.JoinConfiguration{
+func newJoinConfiguration(cfg *kubeadmconfig.Config, nodeRegistration kubeadmv1beta2.NodeRegistrationOptions) *kubeadmv1beta2.JoinConfiguration {
+	return &kubeadmv1beta2.JoinConfiguration{
 		TypeMeta: metav1.TypeMeta{
-			APIVersion: "kubeadm.k8s.io/v1beta2",
+			APIVersion: apiVersion,
 			Kind:       "JoinConfiguration",
 		},
 		Discovery: kubeadmv1beta2.Discovery{
 			BootstrapToken: &kubeadmv1beta2.BootstrapTokenDiscovery{
-				Token:                    s.JoinToken,
-				APIServerEndpoint:        controlPlaneEndpoint,
+				Token:                    cfg.JoinToken,
+				APIServerEndpoint:        cfg.ControlPlaneEndpoint,
 				UnsafeSkipCAVerification: true,
 			},
 		},
+		NodeRegistration: nodeRegistration,
 	}
-
-	bfalse := false
-	kubeletConfig := &kubeletconfigv1beta1.KubeletConfiguration{
-		TypeMeta: metav1.TypeMeta{
-			APIVersion: "kubelet.config.k8s.io/v1beta1",
-			Kind:       "KubeletConfiguration",
-		},
-		CgroupDriver:       "systemd",
-		ReadOnlyPort:       0,
-		RotateCertificates: true,
-		ClusterDNS:         []string{resources.NodeLocalDNSVirtualIP},
-		Authentication: kubeletconfigv1beta1.KubeletAuthentication{
-			Anonymous: kubeletconfigv1beta1.KubeletAnonymousAuthentication{
-				Enabled: &bfalse,
-			},
-		},
-		FeatureGates: map[string]bool{},
-	}
-
-	if cluster.AssetConfiguration.Pause.ImageRepository != "" {
-		nodeRegistration.KubeletExtraArgs["pod-infra-container-image"] = cluster.AssetConfiguration.Pause.ImageRepository + "/pause:" + cluster.AssetConfiguration.Pause.ImageTag
-	}
-
-	if s.ShouldEnableInTreeCloudProvider() {
-		renderedCloudConfig := "/etc/kubernetes/cloud-config"
-
-		nodeRegistration.KubeletExtraArgs["cloud-provider"] = cluster.CloudProvider.CloudProviderName()
-		nodeRegistration.KubeletExtraArgs["cloud-config"] = renderedCloudConfig
-	}
-
-	if cluster.CloudProvider.External {
-		if !s.ShouldEnableInTreeCloudProvider() {
-			nodeRegistration.KubeletExtraArgs["cloud-provider"] = "external"
-		}
-		if s.ShouldEnableCSIMigration() {
-			featureGates, _, err := s.Cluster.CSIMigrationFeatureGates(s.ShouldUnregisterInTreeCloudProvider())
-			if err != nil {
-				return nil, err
-			}
-			for k, v := range featureGates {
-				kubeletConfig.FeatureGates[k] = v
-			}
-		}
-	}
-
-	joinConfig.NodeRegistration = nodeRegistration
-
-	kubeproxyConfig := kubeProxyConfiguration(s)
-
-	return []runtime.Object{joinConfig, kubeletConfig, kubeproxyConfig}, nil
 }
 
-func newNodeIP(host kubeoneapi.HostConfig) string {
-	nodeIP := host.PrivateAddress
-	if nodeIP == "" {
-		nodeIP = host.PublicAddress
-	}
-
-	return nodeIP
-}
-
-func newNodeRegistration(s *state.State, host kubeoneapi.HostConfig) kubeadmv1beta2.NodeRegistrationOptions {
+func newNodeRegistration(nr kubeadmconfig.NodeRegistration) kubeadmv1beta2.NodeRegistrationOptions {
 	return kubeadmv1beta2.NodeRegistrationOptions{
-		Name:      host.Hostname,
-		Taints:    host.Taints,
-		CRISocket: s.Cluster.ContainerRuntime.CRISocket(),
-		KubeletExtraArgs: map[string]string{
-			"node-ip":           newNodeIP(host),
-			"volume-plugin-dir": "/var/lib/kubelet/volumeplugins",
-		},
+		Name:                  nr.Name,
+		Taints:                nr.Taints,
+		CRISocket:             nr.CRISocket,
+		KubeletExtraArgs:      nr.KubeletExtraArgs,
+		IgnorePreflightErrors: nr.IgnorePreflightErrors,
 	}
 }
 
-func kubeProxyConfiguration(s *state.State) *kubeproxyv1alpha1.KubeProxyConfiguration {
-	kubeProxyConfig := &kubeproxyv1alpha1.KubeProxyConfiguration{
-		TypeMeta: metav1.TypeMeta{
-			Kind:       "KubeProxyConfiguration",
-			APIVersion: "kubeproxy.config.k8s.io/v1alpha1",
-		},
-		ClusterCIDR: s.Cluster.ClusterNetwork.PodSubnet,
-		ClientConnection: componentbasev1alpha1.ClientConnectionConfiguration{
-			Kubeconfig: "/var/lib/kube-proxy/kubeconfig.conf",
-		},
+func newControlPlaneComponent(c kubeadmconfig.ControlPlaneComponent) kubeadmv1beta2.ControlPlaneComponent {
+	component := kubeadmv1beta2.ControlPlaneComponent{
+		ExtraArgs:    c.ExtraArgs,
+		ExtraVolumes: []kubeadmv1beta2.HostPathMount{},
 	}
 
-	if kbPrx := s.Cluster.ClusterNetwork.KubeProxy; kbPrx != nil {
-		switch {
-		case kbPrx.IPVS != nil:
-			kubeProxyConfig.Mode = kubeproxyv1alpha1.ProxyMode("ipvs")
-			kubeProxyConfig.IPVS = kubeproxyv1alpha1.KubeProxyIPVSConfiguration{
-				StrictARP:     kbPrx.IPVS.StrictARP,
-				Scheduler:     kbPrx.IPVS.Scheduler,
-				ExcludeCIDRs:  kbPrx.IPVS.ExcludeCIDRs,
-				TCPTimeout:    kbPrx.IPVS.TCPTimeout,
-				TCPFinTimeout: kbPrx.IPVS.TCPFinTimeout,
-				UDPTimeout:    kbPrx.IPVS.UDPTimeout,
-			}
-		case kbPrx.IPTables != nil:
-			kubeProxyConfig.Mode = kubeproxyv1alpha1.ProxyMode("iptables")
-		}
+	for _, vol := range c.ExtraVolumes {
+		component.ExtraVolumes = append(component.ExtraVolumes, kubeadmv1beta2.HostPathMount{
+			Name:      vol.Name,
+			HostPath:  vol.HostPath,
+			MountPath: vol.MountPath,
+			ReadOnly:  vol.ReadOnly,
+			PathType:  vol.PathType,
+		})
 	}
 
-	return kubeProxyConfig
+	return component
+}
+
+func newImageMeta(im kubeadmconfig.ImageMeta) kubeadmv1beta2.ImageMeta {
+	return kubeadmv1beta2.ImageMeta{
+		ImageRepository: im.ImageRepository,
+		ImageTag:        im.ImageTag,
+	}
 }
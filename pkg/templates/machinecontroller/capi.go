@@ -0,0 +1,386 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinecontroller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/state"
+	"k8c.io/kubeone/pkg/templates"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	capiAPIVersion          = "cluster.x-k8s.io/v1beta1"
+	capiBootstrapAPIVersion = "bootstrap.cluster.x-k8s.io/v1beta1"
+
+	// CAPIUnmappedFieldsAnnotation lists the cloudProviderSpec fields that
+	// couldn't be translated to the infrastructure machine template
+	CAPIUnmappedFieldsAnnotation = "kubeone.io/unmapped-cloud-provider-spec-fields"
+
+	// capiFailureDomain is the field mapped to the failureDomain of the Machine
+	capiFailureDomain = "@failureDomain"
+)
+
+// capiProvider describes the infrastructure machine template of the Cluster
+// API provider and how the machine-controller cloudProviderSpec fields map to
+// the template spec
+type capiProvider struct {
+	apiVersion string
+	kind       string
+	// fields maps the cloudProviderSpec field to the dot separated path in
+	// the spec.template.spec of the machine template
+	fields map[string]string
+}
+
+var capiProviders = map[string]capiProvider{
+	"aws": {
+		apiVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+		kind:       "AWSMachineTemplate",
+		fields: map[string]string{
+			"instanceType":       "instanceType",
+			"ami":                "ami.id",
+			"instanceProfile":    "iamInstanceProfile",
+			"subnetId":           "subnet.id",
+			"diskSize":           "rootVolume.size",
+			"diskType":           "rootVolume.type",
+			"diskIops":           "rootVolume.iops",
+			"ebsVolumeEncrypted": "rootVolume.encrypted",
+			"ebsVolumeKmsKeyId":  "rootVolume.encryptionKey",
+			"assignPublicIP":     "publicIP",
+			"tags":               "additionalTags",
+			"availabilityZone":   capiFailureDomain,
+
+			"instanceMetadataOptions": "instanceMetadataOptions",
+		},
+	},
+	"azure": {
+		apiVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+		kind:       "AzureMachineTemplate",
+		fields: map[string]string{
+			"vmSize":         "vmSize",
+			"imageID":        "image.id",
+			"osDiskSize":     "osDisk.diskSizeGB",
+			"assignPublicIP": "allocatePublicIP",
+			"tags":           "additionalTags",
+		},
+	},
+	"digitalocean": {
+		apiVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+		kind:       "DOMachineTemplate",
+		fields: map[string]string{
+			"size": "size",
+			"tags": "additionalTags",
+		},
+	},
+	"gce": {
+		apiVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+		kind:       "GCPMachineTemplate",
+		fields: map[string]string{
+			"machineType":           "instanceType",
+			"customImage":           "image",
+			"diskSize":              "rootDeviceSize",
+			"diskType":              "rootDeviceType",
+			"assignPublicIPAddress": "publicIP",
+			"labels":                "additionalLabels",
+			"tags":                  "additionalNetworkTags",
+			"zone":                  capiFailureDomain,
+			"serviceAccount":        "serviceAccounts.email",
+			"scopes":                "serviceAccounts.scopes",
+		},
+	},
+	"hetzner": {
+		apiVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+		kind:       "HCloudMachineTemplate",
+		fields: map[string]string{
+			"serverType": "type",
+			"image":      "imageName",
+			"location":   capiFailureDomain,
+		},
+	},
+	"openstack": {
+		apiVersion: "infrastructure.cluster.x-k8s.io/v1alpha4",
+		kind:       "OpenStackMachineTemplate",
+		fields: map[string]string{
+			"flavor":           "flavor",
+			"image":            "image",
+			"rootDiskSizeGB":   "rootVolume.diskSize",
+			"availabilityZone": capiFailureDomain,
+			"serverGroup":      "serverGroupID",
+			"configDrive":      "configDrive",
+		},
+	},
+	"vsphere": {
+		apiVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+		kind:       "VSphereMachineTemplate",
+		fields: map[string]string{
+			"templateVMName": "template",
+			"datacenter":     "datacenter",
+			"datastore":      "datastore",
+			"folder":         "folder",
+			"resourcePool":   "resourcePool",
+			"cpus":           "numCPUs",
+			"memoryMB":       "memoryMiB",
+			"diskSizeGB":     "diskGiB",
+		},
+	},
+}
+
+// GenerateCAPIManifest generates YAML manifests containing the Cluster API
+// MachineDeployment, KubeadmConfigTemplate and infrastructure machine template
+// for each MachineDeployment present in the state. The cloudProviderSpec
+// fields without a Cluster API equivalent are listed in the
+// CAPIUnmappedFieldsAnnotation annotation of the machine template.
+func GenerateCAPIManifest(s *state.State, namespace string) (string, error) {
+	objs, err := capiObjects(s.Cluster, namespace)
+	if err != nil {
+		return "", err
+	}
+	if len(objs) == 0 {
+		return "", nil
+	}
+
+	return templates.KubernetesToYAML(objs)
+}
+
+func capiObjects(cluster *kubeoneapi.KubeOneCluster, namespace string) ([]runtime.Object, error) {
+	providerName := cluster.CloudProvider.CloudProviderName()
+	provider, ok := capiProviders[providerName]
+	if !ok && len(cluster.DynamicWorkers) > 0 {
+		return nil, errors.Errorf("cloud provider %q has no Cluster API equivalent", providerName)
+	}
+
+	workersets, err := Workersets(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	objs := []runtime.Object{}
+	for _, workerset := range workersets {
+		spec, err := machineSpec(cluster, workerset, cluster.CloudProvider)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to generate machineSpec for %q", workerset.Name)
+		}
+
+		machineTemplate, failureDomain, err := capiMachineTemplate(provider, workerset.Name, namespace, spec)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to generate %s for %q", provider.kind, workerset.Name)
+		}
+
+		objs = append(objs,
+			capiMachineDeployment(cluster, workerset, namespace, provider, failureDomain),
+			capiKubeadmConfigTemplate(workerset, namespace),
+			machineTemplate,
+		)
+	}
+
+	return objs, nil
+}
+
+func capiMachineDeployment(cluster *kubeoneapi.KubeOneCluster, workerset kubeoneapi.DynamicWorkerConfig, namespace string, provider capiProvider, failureDomain string) *unstructured.Unstructured {
+	selector := map[string]string{
+		"cluster.x-k8s.io/cluster-name": cluster.Name,
+		"workerset":                     workerset.Name,
+	}
+
+	templateSpec := map[string]interface{}{
+		"clusterName": cluster.Name,
+		"version":     "v" + strings.TrimPrefix(cluster.Versions.Kubernetes, "v"),
+		"bootstrap": map[string]interface{}{
+			"configRef": map[string]interface{}{
+				"apiVersion": capiBootstrapAPIVersion,
+				"kind":       "KubeadmConfigTemplate",
+				"name":       workerset.Name,
+			},
+		},
+		"infrastructureRef": map[string]interface{}{
+			"apiVersion": provider.apiVersion,
+			"kind":       provider.kind,
+			"name":       workerset.Name,
+		},
+	}
+	if failureDomain != "" {
+		templateSpec["failureDomain"] = failureDomain
+	}
+
+	md := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": capiAPIVersion,
+		"kind":       "MachineDeployment",
+		"metadata": map[string]interface{}{
+			"name":      workerset.Name,
+			"namespace": namespace,
+		},
+		"spec": map[string]interface{}{
+			"clusterName": cluster.Name,
+			"replicas":    int64(*workerset.Replicas),
+			"selector": map[string]interface{}{
+				"matchLabels": stringMap(selector),
+			},
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": stringMap(labels.Merge(workerset.Config.Labels, selector)),
+				},
+				"spec": templateSpec,
+			},
+		},
+	}}
+	if len(workerset.Config.Annotations) > 0 {
+		md.SetAnnotations(workerset.Config.Annotations)
+	}
+	if len(workerset.Config.MachineAnnotations) > 0 {
+		_ = unstructured.SetNestedStringMap(md.Object, workerset.Config.MachineAnnotations, "spec", "template", "metadata", "annotations")
+	}
+
+	return md
+}
+
+func capiKubeadmConfigTemplate(workerset kubeoneapi.DynamicWorkerConfig, namespace string) *unstructured.Unstructured {
+	kubeletArgs := map[string]interface{}{}
+	if len(workerset.Config.Labels) > 0 {
+		kubeletArgs["node-labels"] = labels.FormatLabels(workerset.Config.Labels)
+	}
+	if len(workerset.Config.Taints) > 0 {
+		taints := []string{}
+		for _, taint := range workerset.Config.Taints {
+			taints = append(taints, taint.ToString())
+		}
+		kubeletArgs["register-with-taints"] = strings.Join(taints, ",")
+	}
+
+	spec := map[string]interface{}{
+		"joinConfiguration": map[string]interface{}{
+			"nodeRegistration": map[string]interface{}{
+				"kubeletExtraArgs": kubeletArgs,
+			},
+		},
+	}
+
+	if len(workerset.Config.SSHPublicKeys) > 0 {
+		keys := []interface{}{}
+		for _, key := range workerset.Config.SSHPublicKeys {
+			keys = append(keys, key)
+		}
+		spec["users"] = []interface{}{
+			map[string]interface{}{
+				"name":              "capi",
+				"sudo":              "ALL=(ALL) NOPASSWD:ALL",
+				"sshAuthorizedKeys": keys,
+			},
+		}
+	}
+
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": capiBootstrapAPIVersion,
+		"kind":       "KubeadmConfigTemplate",
+		"metadata": map[string]interface{}{
+			"name":      workerset.Name,
+			"namespace": namespace,
+		},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": spec,
+			},
+		},
+	}}
+}
+
+// capiMachineTemplate translates the cloudProviderSpec to the machine
+// template of the infrastructure provider. It returns the failure domain of
+// the machines, if the provider has one in the cloudProviderSpec.
+func capiMachineTemplate(provider capiProvider, name, namespace string, spec map[string]interface{}) (*unstructured.Unstructured, string, error) {
+	templateSpec := map[string]interface{}{}
+	failureDomain := ""
+	unmapped := []string{}
+
+	for field, value := range spec {
+		if isZeroValue(value) {
+			continue
+		}
+
+		target, ok := provider.fields[field]
+		if !ok {
+			unmapped = append(unmapped, field)
+			continue
+		}
+
+		if target == capiFailureDomain {
+			failureDomain = fmt.Sprint(value)
+			continue
+		}
+
+		if err := unstructured.SetNestedField(templateSpec, runtime.DeepCopyJSONValue(value), strings.Split(target, ".")...); err != nil {
+			return nil, "", errors.Wrapf(err, "failed to set %q", target)
+		}
+	}
+
+	tpl := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": provider.apiVersion,
+		"kind":       provider.kind,
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+		},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": templateSpec,
+			},
+		},
+	}}
+
+	if len(unmapped) > 0 {
+		sort.Strings(unmapped)
+		tpl.SetAnnotations(map[string]string{
+			CAPIUnmappedFieldsAnnotation: strings.Join(unmapped, ","),
+		})
+	}
+
+	return tpl, failureDomain, nil
+}
+
+func isZeroValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	}
+
+	return false
+}
+
+func stringMap(m map[string]string) map[string]interface{} {
+	out := map[string]interface{}{}
+	for k, v := range m {
+		out[k] = v
+	}
+
+	return out
+}
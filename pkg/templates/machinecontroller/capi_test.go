@@ -0,0 +1,95 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinecontroller
+
+import (
+	"encoding/json"
+	"testing"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/templates"
+	"k8c.io/kubeone/pkg/testhelper"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestCAPIObjects(t *testing.T) {
+	replicas := 3
+	cluster := &kubeoneapi.KubeOneCluster{
+		Name: "test",
+		CloudProvider: kubeoneapi.CloudProviderSpec{
+			AWS: &kubeoneapi.AWSSpec{},
+		},
+		Versions: kubeoneapi.VersionConfig{
+			Kubernetes: "1.22.2",
+		},
+		DynamicWorkers: []kubeoneapi.DynamicWorkerConfig{
+			{
+				Name:     "test-pool1",
+				Replicas: &replicas,
+				Config: kubeoneapi.ProviderSpec{
+					CloudProviderSpec: json.RawMessage(`{
+						"ami": "ami-123",
+						"availabilityZone": "eu-west-3a",
+						"diskSize": 50,
+						"instanceType": "t3.medium",
+						"region": "eu-west-3",
+						"subnetId": "subnet-123",
+						"vpcId": "vpc-123"
+					}`),
+					Labels:        map[string]string{"pool": "pool1"},
+					Taints:        []corev1.Taint{{Key: "dedicated", Value: "pool1", Effect: corev1.TaintEffectNoSchedule}},
+					SSHPublicKeys: []string{"ssh-rsa AAAA"},
+				},
+			},
+		},
+	}
+
+	objs, err := capiObjects(cluster, "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := templates.KubernetesToYAML(objs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testhelper.DiffOutput(t, testhelper.FSGoldenName(t), got, *updateFlag)
+}
+
+func TestCAPIObjectsUnsupportedProvider(t *testing.T) {
+	replicas := 1
+	cluster := &kubeoneapi.KubeOneCluster{
+		CloudProvider: kubeoneapi.CloudProviderSpec{
+			Packet: &kubeoneapi.PacketSpec{},
+		},
+		DynamicWorkers: []kubeoneapi.DynamicWorkerConfig{
+			{
+				Name:     "pool",
+				Replicas: &replicas,
+				Config: kubeoneapi.ProviderSpec{
+					CloudProviderSpec: json.RawMessage(`{}`),
+				},
+			},
+		},
+	}
+
+	if _, err := capiObjects(cluster, "default"); err == nil {
+		t.Fatal("expected error for provider without Cluster API equivalent")
+	}
+}
@@ -16,6 +16,14 @@ limitations under the License.
 
 package machinecontroller
 
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
 // AWSSpec holds cloudprovider spec for AWS
 type AWSSpec struct {
 	AMI                string            `json:"ami"`
@@ -33,6 +41,85 @@ type AWSSpec struct {
 	SubnetID           string            `json:"subnetId"`
 	Tags               map[string]string `json:"tags"`
 	VPCID              string            `json:"vpcId"`
+
+	// InstanceTypes is a list of instance types ordered by priority. The
+	// first instance type is used as instanceType, unless it's set.
+	InstanceTypes           []string                    `json:"instanceTypes,omitempty"`
+	EBSVolumeKMSKeyID       string                      `json:"ebsVolumeKmsKeyId,omitempty"`
+	SpotInstanceConfig      *AWSSpotInstanceConfig      `json:"spotInstanceConfig,omitempty"`
+	InstanceMetadataOptions *AWSInstanceMetadataOptions `json:"instanceMetadataOptions,omitempty"`
+}
+
+// AWSSpotInstanceConfig holds the spot market options of the instances
+type AWSSpotInstanceConfig struct {
+	// MaxPrice is the maximum hourly price, defaults to the on-demand price
+	MaxPrice             *string `json:"maxPrice,omitempty"`
+	PersistentRequest    *bool   `json:"persistentRequest,omitempty"`
+	InterruptionBehavior string  `json:"interruptionBehavior,omitempty"`
+}
+
+// AWSInstanceMetadataOptions holds the instance metadata service (IMDS)
+// options of the instances
+type AWSInstanceMetadataOptions struct {
+	// HTTPTokens set to required enforces IMDSv2
+	HTTPTokens              string `json:"httpTokens,omitempty"`
+	HTTPPutResponseHopLimit *int   `json:"httpPutResponseHopLimit,omitempty"`
+}
+
+const (
+	// AWSHTTPTokensRequired enforces IMDSv2 by requiring session tokens
+	AWSHTTPTokensRequired = "required"
+	// AWSHTTPTokensOptional allows both IMDSv1 and IMDSv2
+	AWSHTTPTokensOptional = "optional"
+)
+
+// Validate checks the AWS spec for combinations of fields that can't be
+// fulfilled by AWS
+func (s AWSSpec) Validate() error {
+	if s.SpotInstanceConfig != nil && (s.IsSpotInstance == nil || !*s.IsSpotInstance) {
+		return errors.New("spotInstanceConfig requires isSpotInstance")
+	}
+
+	if s.SpotInstanceConfig != nil {
+		switch s.SpotInstanceConfig.InterruptionBehavior {
+		case "", "terminate":
+		case "stop", "hibernate":
+			if s.SpotInstanceConfig.PersistentRequest == nil || !*s.SpotInstanceConfig.PersistentRequest {
+				return errors.Errorf("spotInstanceConfig.interruptionBehavior %q requires persistentRequest", s.SpotInstanceConfig.InterruptionBehavior)
+			}
+		default:
+			return errors.Errorf("unknown spotInstanceConfig.interruptionBehavior %q, must be one of: terminate, stop, hibernate", s.SpotInstanceConfig.InterruptionBehavior)
+		}
+	}
+
+	seen := map[string]bool{}
+	for i, instanceType := range s.InstanceTypes {
+		if instanceType == "" {
+			return errors.Errorf("instanceTypes[%d]: instance type can't be empty", i)
+		}
+		if seen[instanceType] {
+			return errors.Errorf("instanceTypes[%d]: duplicate instance type %q", i, instanceType)
+		}
+		seen[instanceType] = true
+	}
+
+	if s.EBSVolumeKMSKeyID != "" && !s.EBSVolumeEncrypted {
+		return errors.New("ebsVolumeKmsKeyId requires ebsVolumeEncrypted")
+	}
+
+	if opts := s.InstanceMetadataOptions; opts != nil {
+		switch opts.HTTPTokens {
+		case "", AWSHTTPTokensRequired, AWSHTTPTokensOptional:
+		default:
+			return errors.Errorf("unknown instanceMetadataOptions.httpTokens %q, must be one of: %s, %s", opts.HTTPTokens, AWSHTTPTokensRequired, AWSHTTPTokensOptional)
+		}
+
+		if opts.HTTPPutResponseHopLimit != nil && (*opts.HTTPPutResponseHopLimit < 1 || *opts.HTTPPutResponseHopLimit > 64) {
+			return errors.New("instanceMetadataOptions.httpPutResponseHopLimit must be between 1 and 64")
+		}
+	}
+
+	return nil
 }
 
 // DigitalOceanSpec holds cloudprovider spec for DigitalOcean
@@ -44,6 +131,32 @@ type DigitalOceanSpec struct {
 	PrivateNetworking bool     `json:"private_networking"`
 	Monitoring        bool     `json:"monitoring"`
 	Tags              []string `json:"tags"`
+	VPCUUID           string   `json:"vpc_uuid,omitempty"`
+}
+
+// digitalOceanTagRegexp matches valid DigitalOcean tags
+var digitalOceanTagRegexp = regexp.MustCompile(`^[a-zA-Z0-9_:.-]{1,255}$`)
+
+// Validate checks the DigitalOcean spec for values rejected by DigitalOcean
+func (s DigitalOceanSpec) Validate() error {
+	if s.VPCUUID != "" && !uuidRegexp.MatchString(s.VPCUUID) {
+		return errors.Errorf("vpc_uuid %q is not a valid UUID", s.VPCUUID)
+	}
+
+	for i, tag := range s.Tags {
+		if !digitalOceanTagRegexp.MatchString(tag) {
+			return errors.Errorf("tags[%d]: invalid tag %q, must be letters, numbers, colons, dots, dashes and underscores", i, tag)
+		}
+	}
+
+	return nil
+}
+
+// DigitalOceanClusterTag returns the tag KubeOne sets on all DigitalOcean
+// worker droplets of the cluster. Firewalls and load balancers created by
+// the Terraform configs target the droplets by this tag.
+func DigitalOceanClusterTag(clusterName string) string {
+	return fmt.Sprintf("kubernetes-cluster:%s", clusterName)
 }
 
 // OpenStackSpec holds cloudprovider spec for OpenStack
@@ -59,6 +172,54 @@ type OpenStackSpec struct {
 	NodeVolumeAttachLimit *uint             `json:"nodeVolumeAttachLimit,omitempty"`
 	TrustDevicePath       bool              `json:"trustDevicePath"`
 	Tags                  map[string]string `json:"tags"`
+
+	// Networks attaches the server to multiple networks, one NIC per
+	// network. The first network is the primary network. Mutually
+	// exclusive with Network.
+	Networks []string `json:"networks,omitempty"`
+	// ServerGroup is the ID of an existing server group, usually with the
+	// anti-affinity policy, the servers are created in
+	ServerGroup string `json:"serverGroup,omitempty"`
+	ConfigDrive *bool  `json:"configDrive,omitempty"`
+}
+
+// Validate checks the OpenStack spec for combinations of fields that can't
+// be fulfilled by OpenStack. The cloudConfig is the OpenStack cloud-config
+// of the cluster.
+func (s OpenStackSpec) Validate(cloudConfig string) error {
+	if s.Network != "" && len(s.Networks) > 0 {
+		return errors.New("network and networks are mutually exclusive")
+	}
+
+	seen := map[string]bool{}
+	for i, network := range s.Networks {
+		if network == "" {
+			return errors.Errorf("networks[%d]: network can't be empty", i)
+		}
+		if seen[network] {
+			return errors.Errorf("networks[%d]: duplicate network %q", i, network)
+		}
+		seen[network] = true
+	}
+
+	// with multiple networks, the cloud provider needs to know which network
+	// holds the node addresses. Networks can be referenced by ID as well, in
+	// which case they can't be compared with the internal network name.
+	internalNetwork := iniValue(cloudConfig, "Networking", "internal-network-name")
+	if len(s.Networks) > 1 {
+		if internalNetwork == "" {
+			return errors.New("multiple networks require internal-network-name in the [Networking] section of the cloud-config")
+		}
+		if !seen[internalNetwork] && !containsUUID(s.Networks) {
+			return errors.Errorf("networks must include the internal network %q from the cloud-config", internalNetwork)
+		}
+	}
+
+	if s.TrustDevicePath && iniValue(cloudConfig, "BlockStorage", "trust-device-path") == "false" {
+		return errors.New("trustDevicePath conflicts with trust-device-path=false in the [BlockStorage] section of the cloud-config")
+	}
+
+	return nil
 }
 
 // GCESpec holds cloudprovider spec for GCE
@@ -76,6 +237,67 @@ type GCESpec struct {
 	MultiZone             *bool             `json:"multizone"`
 	Regional              *bool             `json:"regional"`
 	CustomImage           string            `json:"customImage,omitempty"`
+
+	ShieldedVM     *GCEShieldedVMConfig `json:"shieldedVM,omitempty"`
+	ServiceAccount string               `json:"serviceAccount,omitempty"`
+	Scopes         []string             `json:"scopes,omitempty"`
+}
+
+// GCEShieldedVMConfig holds the Shielded VM options of the instances
+type GCEShieldedVMConfig struct {
+	EnableSecureBoot          *bool `json:"enableSecureBoot,omitempty"`
+	EnableVTPM                *bool `json:"enableVtpm,omitempty"`
+	EnableIntegrityMonitoring *bool `json:"enableIntegrityMonitoring,omitempty"`
+}
+
+// gceNetworkTagRegexp matches valid GCE network tags
+var gceNetworkTagRegexp = regexp.MustCompile(`^[a-z]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// Validate checks the GCE spec for combinations of fields that can't be
+// fulfilled by GCE
+func (s GCESpec) Validate() error {
+	if vm := s.ShieldedVM; vm != nil {
+		if vm.EnableIntegrityMonitoring != nil && *vm.EnableIntegrityMonitoring && vm.EnableVTPM != nil && !*vm.EnableVTPM {
+			return errors.New("shieldedVM.enableIntegrityMonitoring requires shieldedVM.enableVtpm")
+		}
+	}
+
+	if s.ServiceAccount != "" && !strings.Contains(s.ServiceAccount, "@") {
+		return errors.Errorf("serviceAccount %q must be the email address of the service account", s.ServiceAccount)
+	}
+
+	for i, scope := range s.Scopes {
+		if scope == "" {
+			return errors.Errorf("scopes[%d]: scope can't be empty", i)
+		}
+	}
+
+	for i, tag := range s.Tags {
+		if !gceNetworkTagRegexp.MatchString(tag) {
+			return errors.Errorf("tags[%d]: invalid network tag %q, must be lowercase letters, numbers and dashes, start with a letter and be at most 63 characters long", i, tag)
+		}
+	}
+
+	return nil
+}
+
+// GCENetworkTag returns the network tag KubeOne sets on all GCE worker
+// instances of the cluster. The tag is derived from the cluster name, with
+// all characters not allowed in network tags replaced with dashes.
+func GCENetworkTag(clusterName string) string {
+	tag := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(clusterName))
+
+	tag = "kubeone-" + tag
+	if len(tag) > 63 {
+		tag = tag[:63]
+	}
+
+	return strings.TrimRight(tag, "-")
 }
 
 // HetznerSpec holds cloudprovider spec for Hetzner
@@ -86,6 +308,10 @@ type HetznerSpec struct {
 	Image      string            `json:"image"`
 	Networks   []string          `json:"networks"`
 	Labels     map[string]string `json:"labels,omitempty"`
+
+	// PlacementGroup is the name of the spread placement group the servers
+	// are created in. The placement group is created if it doesn't exist.
+	PlacementGroup string `json:"placementGroup,omitempty"`
 }
 
 // PacketSpec holds cloudprovider spec for Packet
@@ -111,6 +337,45 @@ type VSphereSpec struct {
 	MemoryMB         int    `json:"memoryMB"`
 	TemplateVMName   string `json:"templateVMName"`
 	VMNetName        string `json:"vmNetName,omitempty"`
+	// VMAntiAffinity creates the DRS anti-affinity rule, so the machines of
+	// the same MachineSet are running on different ESXi hosts. Requires Cluster.
+	VMAntiAffinity bool `json:"vmAntiAffinity,omitempty"`
+	// Tags are attached to the machines
+	Tags []VSphereTag `json:"tags,omitempty"`
+}
+
+// VSphereTag is the vSphere tag from the tag category
+type VSphereTag struct {
+	Name        string `json:"name"`
+	CategoryID  string `json:"categoryID"`
+	Description string `json:"description,omitempty"`
+}
+
+// Validate checks the vSphere spec can be used by machine-controller
+func (s VSphereSpec) Validate() error {
+	switch {
+	case s.Datastore == "" && s.DatastoreCluster == "":
+		return errors.New("either datastore or datastoreCluster is required")
+	case s.Datastore != "" && s.DatastoreCluster != "":
+		return errors.New("datastore and datastoreCluster are mutually exclusive")
+	case s.VMAntiAffinity && s.Cluster == "":
+		return errors.New("vmAntiAffinity requires cluster")
+	}
+
+	seen := map[VSphereTag]bool{}
+	for i, tag := range s.Tags {
+		if tag.Name == "" || tag.CategoryID == "" {
+			return errors.Errorf("tags[%d]: name and categoryID are required", i)
+		}
+
+		key := VSphereTag{Name: tag.Name, CategoryID: tag.CategoryID}
+		if seen[key] {
+			return errors.Errorf("tags[%d]: duplicate tag %q in category %q", i, tag.Name, tag.CategoryID)
+		}
+		seen[key] = true
+	}
+
+	return nil
 }
 
 // AzureSpec holds cloudprovider spec for Azure
@@ -130,6 +395,71 @@ type AzureSpec struct {
 	ImageID           string            `json:"imageID"`
 	OSDiskSize        int               `json:"osDiskSize"`
 	DataDiskSize      int               `json:"dataDiskSize"`
+
+	EnableAcceleratedNetworking *bool    `json:"enableAcceleratedNetworking,omitempty"`
+	Priority                    string   `json:"priority,omitempty"`
+	EvictionPolicy              string   `json:"evictionPolicy,omitempty"`
+	MaxPrice                    *float64 `json:"maxPrice,omitempty"`
+}
+
+const (
+	// AzurePriorityRegular creates regular (on-demand) VMs
+	AzurePriorityRegular = "Regular"
+	// AzurePrioritySpot creates Spot VMs, which can be evicted at any time
+	AzurePrioritySpot = "Spot"
+
+	// AzureEvictionPolicyDeallocate stops evicted Spot VMs, keeping their disks
+	AzureEvictionPolicyDeallocate = "Deallocate"
+	// AzureEvictionPolicyDelete deletes evicted Spot VMs along with their disks
+	AzureEvictionPolicyDelete = "Delete"
+)
+
+// Validate checks the Azure spec for combinations of fields that can't be
+// fulfilled by Azure
+func (s AzureSpec) Validate() error {
+	switch s.Priority {
+	case "", AzurePriorityRegular:
+		if s.EvictionPolicy != "" || s.MaxPrice != nil {
+			return errors.New("evictionPolicy and maxPrice require priority Spot")
+		}
+	case AzurePrioritySpot:
+		if s.AvailabilitySet != "" {
+			return errors.New("priority Spot can't be used with availabilitySet")
+		}
+	default:
+		return errors.Errorf("unknown priority %q, must be one of: %s, %s", s.Priority, AzurePriorityRegular, AzurePrioritySpot)
+	}
+
+	switch s.EvictionPolicy {
+	case "", AzureEvictionPolicyDeallocate, AzureEvictionPolicyDelete:
+	default:
+		return errors.Errorf("unknown evictionPolicy %q, must be one of: %s, %s", s.EvictionPolicy, AzureEvictionPolicyDeallocate, AzureEvictionPolicyDelete)
+	}
+
+	// -1 caps the price at the on-demand price of the VM size
+	if s.MaxPrice != nil && *s.MaxPrice != -1 && *s.MaxPrice <= 0 {
+		return errors.New("maxPrice must be either -1 or greater than 0")
+	}
+
+	if len(s.Zones) > 0 && s.AvailabilitySet != "" {
+		return errors.New("zones and availabilitySet are mutually exclusive")
+	}
+
+	seen := map[string]bool{}
+	for i, zone := range s.Zones {
+		switch zone {
+		case "1", "2", "3":
+		default:
+			return errors.Errorf("zones[%d]: unknown zone %q, must be one of: 1, 2, 3", i, zone)
+		}
+
+		if seen[zone] {
+			return errors.Errorf("zones[%d]: duplicate zone %q", i, zone)
+		}
+		seen[zone] = true
+	}
+
+	return nil
 }
 
 type AzureImagePlan struct {
@@ -137,3 +467,46 @@ type AzureImagePlan struct {
 	Publisher string `json:"publisher,omitempty"`
 	Product   string `json:"product,omitempty"`
 }
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{12}$`)
+
+func containsUUID(list []string) bool {
+	for _, s := range list {
+		if uuidRegexp.MatchString(s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// iniValue returns the value of the key in the section of the INI-formatted
+// config, such as the OpenStack cloud-config. Keys are case-insensitive and
+// quotes around the value are removed.
+func iniValue(config, section, key string) string {
+	current := ""
+	for _, line := range strings.Split(config, "\n") {
+		line = strings.TrimSpace(line)
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			current = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		if !strings.EqualFold(current, section) {
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 || !strings.EqualFold(strings.TrimSpace(parts[0]), key) {
+			continue
+		}
+
+		return strings.Trim(strings.TrimSpace(parts[1]), `"`)
+	}
+
+	return ""
+}
@@ -90,32 +90,8 @@ func DestroyWorkers(s *state.State) error {
 
 	ctx := context.Background()
 
-	// Annotate nodes with kubermatic.io/skip-eviction=true to skip eviction
-	s.Logger.Info("Annotating nodes to skip eviction...")
-	nodes := &corev1.NodeList{}
-	if err := s.DynamicClient.List(ctx, nodes); err != nil {
-		return errors.Wrap(err, "unable to list nodes")
-	}
-
-	for _, node := range nodes.Items {
-		nodeKey := dynclient.ObjectKey{Name: node.Name}
-
-		retErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-			n := corev1.Node{}
-			if err := s.DynamicClient.Get(ctx, nodeKey, &n); err != nil {
-				return err
-			}
-
-			if n.Annotations == nil {
-				n.Annotations = map[string]string{}
-			}
-			n.Annotations["kubermatic.io/skip-eviction"] = "true"
-			return s.DynamicClient.Update(ctx, &n)
-		})
-
-		if retErr != nil {
-			return errors.Wrapf(retErr, "unable to annotate node %s", node.Name)
-		}
+	if err := annotateNodesSkipEviction(ctx, s); err != nil {
+		return err
 	}
 
 	// Delete all MachineDeployment objects
@@ -170,6 +146,40 @@ func DestroyWorkers(s *state.State) error {
 	return nil
 }
 
+// annotateNodesSkipEviction annotates all nodes with
+// kubermatic.io/skip-eviction=true, so machine-controller deletes machines
+// without evicting pods first
+func annotateNodesSkipEviction(ctx context.Context, s *state.State) error {
+	s.Logger.Info("Annotating nodes to skip eviction...")
+	nodes := &corev1.NodeList{}
+	if err := s.DynamicClient.List(ctx, nodes); err != nil {
+		return errors.Wrap(err, "unable to list nodes")
+	}
+
+	for _, node := range nodes.Items {
+		nodeKey := dynclient.ObjectKey{Name: node.Name}
+
+		retErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			n := corev1.Node{}
+			if err := s.DynamicClient.Get(ctx, nodeKey, &n); err != nil {
+				return err
+			}
+
+			if n.Annotations == nil {
+				n.Annotations = map[string]string{}
+			}
+			n.Annotations["kubermatic.io/skip-eviction"] = "true"
+			return s.DynamicClient.Update(ctx, &n)
+		})
+
+		if retErr != nil {
+			return errors.Wrapf(retErr, "unable to annotate node %s", node.Name)
+		}
+	}
+
+	return nil
+}
+
 // WaitDestroy waits for all Machines to be deleted
 func WaitDestroy(s *state.State) error {
 	s.Logger.Info("Waiting for all machines to get deleted...")
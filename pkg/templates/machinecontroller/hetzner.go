@@ -0,0 +1,178 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinecontroller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/credentials"
+	"k8c.io/kubeone/pkg/state"
+)
+
+const (
+	// HetznerPlacementGroupMaxServers is the maximum number of servers in a
+	// spread placement group. KubeOne doesn't default the placement group
+	// for workersets with more replicas.
+	HetznerPlacementGroupMaxServers = 10
+
+	hetznerEndpoint = "https://api.hetzner.cloud/v1"
+
+	// hetznerClusterLabel is set on the placement groups created by KubeOne
+	hetznerClusterLabel = "kubeone_cluster_name"
+)
+
+type hetznerClient struct {
+	endpoint string
+	token    string
+	client   *http.Client
+}
+
+type hetznerPlacementGroupsResponse struct {
+	PlacementGroups []struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"placement_groups"`
+}
+
+// EnsureHetznerPlacementGroups creates the spread placement groups used by
+// the MachineDeployments, if they don't exist
+func EnsureHetznerPlacementGroups(s *state.State) error {
+	creds, err := credentials.ProviderCredentials(s.Cluster.CloudProvider, s.CredentialsFilePath)
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch credentials")
+	}
+
+	client := &hetznerClient{
+		endpoint: hetznerEndpoint,
+		token:    creds[credentials.HetznerTokenKeyMC],
+		client:   http.DefaultClient,
+	}
+
+	return ensureHetznerPlacementGroups(s.Context, s.Cluster, client)
+}
+
+func ensureHetznerPlacementGroups(ctx context.Context, cluster *kubeoneapi.KubeOneCluster, client *hetznerClient) error {
+	names, err := hetznerPlacementGroups(cluster)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		exists, err := client.placementGroupExists(ctx, name)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get placement group %q", name)
+		}
+		if exists {
+			continue
+		}
+
+		if err := client.createPlacementGroup(ctx, name, map[string]string{hetznerClusterLabel: cluster.Name}); err != nil {
+			return errors.Wrapf(err, "failed to create placement group %q", name)
+		}
+	}
+
+	return nil
+}
+
+// hetznerPlacementGroups returns the sorted names of the placement groups
+// used by the workersets
+func hetznerPlacementGroups(cluster *kubeoneapi.KubeOneCluster) ([]string, error) {
+	workersets, err := Workersets(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	names := []string{}
+	for _, workerset := range workersets {
+		spec, err := machineSpec(cluster, workerset, cluster.CloudProvider)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to generate machineSpec for %q", workerset.Name)
+		}
+
+		name, _ := spec["placementGroup"].(string)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func (c *hetznerClient) placementGroupExists(ctx context.Context, name string) (bool, error) {
+	query := url.Values{}
+	query.Set("name", name)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+"/placement_groups?"+query.Encode(), nil)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	groups := hetznerPlacementGroupsResponse{}
+	if err = c.do(req, &groups); err != nil {
+		return false, err
+	}
+
+	return len(groups.PlacementGroups) > 0, nil
+}
+
+func (c *hetznerClient) createPlacementGroup(ctx context.Context, name string, labels map[string]string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"name":   name,
+		"type":   "spread",
+		"labels": labels,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+"/placement_groups", bytes.NewReader(body))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return c.do(req, &struct{}{})
+}
+
+// do sends the request and decodes the JSON response
+func (c *hetznerClient) do(req *http.Request, out interface{}) error {
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return errors.WithStack(json.NewDecoder(resp.Body).Decode(out))
+}
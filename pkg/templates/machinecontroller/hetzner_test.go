@@ -0,0 +1,137 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinecontroller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+)
+
+func TestMachineSpecHetzner(t *testing.T) {
+	replicas := 3
+	tooMany := HetznerPlacementGroupMaxServers + 1
+
+	tests := []struct {
+		name     string
+		spec     string
+		replicas *int
+		want     string
+	}{
+		{
+			name:     "defaults",
+			spec:     `{"serverType": "cx21"}`,
+			replicas: &replicas,
+			want:     `{"networks":["1234"],"placementGroup":"test-pool1","serverType":"cx21"}`,
+		},
+		{
+			name:     "explicit network and placement group",
+			spec:     `{"serverType": "cx21", "networks": ["workers"], "placementGroup": "workers"}`,
+			replicas: &replicas,
+			want:     `{"networks":["workers"],"placementGroup":"workers","serverType":"cx21"}`,
+		},
+		{
+			name:     "too many replicas for a placement group",
+			spec:     `{"serverType": "cx21"}`,
+			replicas: &tooMany,
+			want:     `{"networks":["1234"],"serverType":"cx21"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			provider := kubeoneapi.CloudProviderSpec{Hetzner: &kubeoneapi.HetznerSpec{NetworkID: "1234"}}
+			cluster := &kubeoneapi.KubeOneCluster{Name: "test", CloudProvider: provider}
+			workerset := kubeoneapi.DynamicWorkerConfig{
+				Name:     "pool1",
+				Replicas: tt.replicas,
+				Config:   kubeoneapi.ProviderSpec{CloudProviderSpec: json.RawMessage(tt.spec)},
+			}
+
+			spec, err := machineSpec(cluster, workerset, provider)
+			if err != nil {
+				t.Fatalf("machineSpec() error = %v", err)
+			}
+
+			got, _ := json.Marshal(spec)
+			if string(got) != tt.want {
+				t.Errorf("machineSpec() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnsureHetznerPlacementGroups(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		created []map[string]interface{}
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			groups := []map[string]interface{}{}
+			if r.URL.Query().Get("name") == "test-pool1" {
+				groups = append(groups, map[string]interface{}{"id": 1, "name": "test-pool1"})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"placement_groups": groups})
+		case http.MethodPost:
+			body := map[string]interface{}{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			mu.Lock()
+			created = append(created, body)
+			mu.Unlock()
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer srv.Close()
+
+	replicas := 2
+	cluster := &kubeoneapi.KubeOneCluster{
+		Name:          "test",
+		CloudProvider: kubeoneapi.CloudProviderSpec{Hetzner: &kubeoneapi.HetznerSpec{}},
+		DynamicWorkers: []kubeoneapi.DynamicWorkerConfig{
+			{Name: "pool1", Replicas: &replicas, Config: kubeoneapi.ProviderSpec{CloudProviderSpec: json.RawMessage(`{}`)}},
+			{Name: "pool2", Replicas: &replicas, Config: kubeoneapi.ProviderSpec{CloudProviderSpec: json.RawMessage(`{}`)}},
+		},
+	}
+
+	client := &hetznerClient{endpoint: srv.URL, token: "secret", client: srv.Client()}
+	if err := ensureHetznerPlacementGroups(context.Background(), cluster, client); err != nil {
+		t.Fatalf("ensureHetznerPlacementGroups() error = %v", err)
+	}
+
+	want := []map[string]interface{}{
+		{"name": "test-pool2", "type": "spread", "labels": map[string]interface{}{hetznerClusterLabel: "test"}},
+	}
+	if !reflect.DeepEqual(created, want) {
+		t.Errorf("created placement groups = %v, want %v", created, want)
+	}
+}
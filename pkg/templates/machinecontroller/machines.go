@@ -20,21 +20,26 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/pkg/errors"
 
 	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
 	"k8c.io/kubeone/pkg/clientutil"
+	"k8c.io/kubeone/pkg/manifestpatch"
 	"k8c.io/kubeone/pkg/state"
 	"k8c.io/kubeone/pkg/templates"
+	"k8c.io/kubeone/pkg/templates/resources"
 
 	clustercommon "github.com/kubermatic/machine-controller/pkg/apis/cluster/common"
 	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
 
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // CreateMachineDeployments creates MachineDeployments that create appropriate
@@ -46,14 +51,27 @@ func CreateMachineDeployments(s *state.State) error {
 
 	ctx := context.Background()
 
+	workersets, err := Workersets(s.Cluster)
+	if err != nil {
+		return err
+	}
+
 	// Apply MachineDeployments
-	for _, workerset := range s.Cluster.DynamicWorkers {
+	for _, workerset := range workersets {
 		machinedeployment, err := createMachineDeployment(s.Cluster, workerset)
 		if err != nil {
 			return errors.Wrap(err, "failed to generate MachineDeployment")
 		}
 
-		err = clientutil.CreateOrUpdate(ctx, s.DynamicClient, machinedeployment)
+		if workerset.Autoscaling != nil {
+			// The replicas of the existing MachineDeployment are managed by
+			// the cluster-autoscaler and must not be reset
+			if err = keepExistingReplicas(ctx, s.DynamicClient, machinedeployment); err != nil {
+				return err
+			}
+		}
+
+		err = clientutil.CreateOrUpdate(ctx, s.DynamicClient, machinedeployment, clientutil.WithManifestPatches(s.Cluster.ManifestPatches))
 		if err != nil {
 			return errors.Wrap(err, "failed to ensure MachineDeployment")
 		}
@@ -62,6 +80,25 @@ func CreateMachineDeployments(s *state.State) error {
 	return nil
 }
 
+// keepExistingReplicas sets the replicas of the MachineDeployment to the
+// replicas of the existing one, if it exists
+func keepExistingReplicas(ctx context.Context, c dynclient.Client, md *clusterv1alpha1.MachineDeployment) error {
+	existing := &clusterv1alpha1.MachineDeployment{}
+	err := c.Get(ctx, dynclient.ObjectKey{Name: md.Name, Namespace: md.Namespace}, existing)
+	switch {
+	case k8serrors.IsNotFound(err):
+		return nil
+	case err != nil:
+		return errors.Wrapf(err, "failed to get MachineDeployment %q", md.Name)
+	}
+
+	if existing.Spec.Replicas != nil {
+		md.Spec.Replicas = existing.Spec.Replicas
+	}
+
+	return nil
+}
+
 // GenerateMachineDeploymentsManifest generates YAML manifests containing
 // all MachineDeployments present in the state.
 func GenerateMachineDeploymentsManifest(s *state.State) (string, error) {
@@ -69,8 +106,13 @@ func GenerateMachineDeploymentsManifest(s *state.State) (string, error) {
 		return "", nil
 	}
 
+	workersets, err := Workersets(s.Cluster)
+	if err != nil {
+		return "", err
+	}
+
 	objs := []runtime.Object{}
-	for _, workerset := range s.Cluster.DynamicWorkers {
+	for _, workerset := range workersets {
 		machinedeployment, err := createMachineDeployment(s.Cluster, workerset)
 		if err != nil {
 			return "", errors.Wrap(err, "failed to generate MachineDeployment")
@@ -79,6 +121,9 @@ func GenerateMachineDeploymentsManifest(s *state.State) (string, error) {
 			APIVersion: clusterv1alpha1.SchemeGroupVersion.String(),
 			Kind:       "MachineDeployment",
 		}
+		if err = manifestpatch.ApplyToObject(machinedeployment, nil, s.Cluster.ManifestPatches); err != nil {
+			return "", errors.Wrapf(err, "failed to patch MachineDeployment %q", machinedeployment.Name)
+		}
 
 		objs = append(objs, machinedeployment)
 	}
@@ -86,6 +131,75 @@ func GenerateMachineDeploymentsManifest(s *state.State) (string, error) {
 	return templates.KubernetesToYAML(objs)
 }
 
+// Workersets returns the workersets a MachineDeployment is created for.
+// Azure VMs can be placed only in a single availability zone, so Azure
+// workersets with multiple zones are spread across the zones by creating
+// a workerset named <name>-<zone> for each zone. Replicas are split evenly
+// between the zones, with the remainder going to the first zones.
+func Workersets(cluster *kubeoneapi.KubeOneCluster) ([]kubeoneapi.DynamicWorkerConfig, error) {
+	if cluster.CloudProvider.Azure == nil {
+		return cluster.DynamicWorkers, nil
+	}
+
+	workersets := []kubeoneapi.DynamicWorkerConfig{}
+	for _, workerset := range cluster.DynamicWorkers {
+		zoned, err := spreadAzureZones(workerset)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to spread workerset %q across zones", workerset.Name)
+		}
+		workersets = append(workersets, zoned...)
+	}
+
+	return workersets, nil
+}
+
+func spreadAzureZones(workerset kubeoneapi.DynamicWorkerConfig) ([]kubeoneapi.DynamicWorkerConfig, error) {
+	if workerset.Config.CloudProviderSpec == nil {
+		return []kubeoneapi.DynamicWorkerConfig{workerset}, nil
+	}
+
+	var azureSpec AzureSpec
+	if err := json.Unmarshal(workerset.Config.CloudProviderSpec, &azureSpec); err != nil {
+		return nil, errors.Wrap(err, "could not parse Azure Spec for worker machines")
+	}
+
+	if len(azureSpec.Zones) < 2 {
+		return []kubeoneapi.DynamicWorkerConfig{workerset}, nil
+	}
+
+	replicas := 0
+	if workerset.Replicas != nil {
+		replicas = *workerset.Replicas
+	}
+
+	zoned := []kubeoneapi.DynamicWorkerConfig{}
+	for i, zone := range azureSpec.Zones {
+		spec := map[string]interface{}{}
+		if err := json.Unmarshal(workerset.Config.CloudProviderSpec, &spec); err != nil {
+			return nil, errors.Wrap(err, "unable to parse the workerset spec")
+		}
+		spec["zones"] = []string{zone}
+
+		specRaw, err := json.Marshal(spec)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not marshal AzureSpec")
+		}
+
+		zoneReplicas := replicas / len(azureSpec.Zones)
+		if i < replicas%len(azureSpec.Zones) {
+			zoneReplicas++
+		}
+
+		ws := *workerset.DeepCopy()
+		ws.Name = fmt.Sprintf("%s-%s", workerset.Name, zone)
+		ws.Replicas = &zoneReplicas
+		ws.Config.CloudProviderSpec = specRaw
+		zoned = append(zoned, ws)
+	}
+
+	return zoned, nil
+}
+
 func createMachineDeployment(cluster *kubeoneapi.KubeOneCluster, workerset kubeoneapi.DynamicWorkerConfig) (*clusterv1alpha1.MachineDeployment, error) {
 	cloudProviderSpec, err := machineSpec(cluster, workerset, cluster.CloudProvider)
 	if err != nil {
@@ -99,6 +213,17 @@ func createMachineDeployment(cluster *kubeoneapi.KubeOneCluster, workerset kubeo
 
 	workerset.Config.CloudProviderSpec = cloudProviderSpecJSON
 
+	if workerset.NvidiaGPU {
+		workerset.Config.Labels = labels.Merge(workerset.Config.Labels, map[string]string{resources.NvidiaGPULabel: "true"})
+	}
+
+	if as := workerset.Autoscaling; as != nil {
+		workerset.Config.Annotations = labels.Merge(workerset.Config.Annotations, map[string]string{
+			resources.AutoscalerMinSizeAnnotation: strconv.Itoa(as.MinReplicas),
+			resources.AutoscalerMaxSizeAnnotation: strconv.Itoa(as.MaxReplicas),
+		})
+	}
+
 	encoded, err := json.Marshal(struct {
 		kubeoneapi.ProviderSpec
 		CloudProvider string `json:"cloudProvider"`
@@ -183,6 +308,14 @@ func machineSpec(cluster *kubeoneapi.KubeOneCluster, workerset kubeoneapi.Dynami
 			return nil, errors.Wrap(err, "could not parse AWS Spec for worker machines")
 		}
 
+		if err = awsSpec.Validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid AWS Spec of workerset %q", workerset.Name)
+		}
+
+		if (awsSpec.InstanceType == nil || *awsSpec.InstanceType == "") && len(awsSpec.InstanceTypes) > 0 {
+			awsSpec.InstanceType = &awsSpec.InstanceTypes[0]
+		}
+
 		tagName := fmt.Sprintf("kubernetes.io/cluster/%s", cluster.Name)
 		tagValue := "shared"
 		if awsSpec.Tags == nil {
@@ -197,11 +330,131 @@ func machineSpec(cluster *kubeoneapi.KubeOneCluster, workerset kubeoneapi.Dynami
 		}
 	}
 
+	if provider.Vsphere != nil {
+		var vsphereSpec VSphereSpec
+
+		if err = json.Unmarshal(specRaw, &vsphereSpec); err != nil {
+			return nil, errors.Wrap(err, "could not parse vSphere Spec for worker machines")
+		}
+
+		if err = vsphereSpec.Validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid vSphere Spec of workerset %q", workerset.Name)
+		}
+	}
+
+	if provider.DigitalOcean != nil {
+		var doSpec DigitalOceanSpec
+
+		if err = json.Unmarshal(specRaw, &doSpec); err != nil {
+			return nil, errors.Wrap(err, "could not parse DigitalOcean Spec for worker machines")
+		}
+
+		if err = doSpec.Validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid DigitalOcean Spec of workerset %q", workerset.Name)
+		}
+	}
+
+	if provider.Openstack != nil {
+		var openstackSpec OpenStackSpec
+
+		if err = json.Unmarshal(specRaw, &openstackSpec); err != nil {
+			return nil, errors.Wrap(err, "could not parse OpenStack Spec for worker machines")
+		}
+
+		if err = openstackSpec.Validate(provider.CloudConfig); err != nil {
+			return nil, errors.Wrapf(err, "invalid OpenStack Spec of workerset %q", workerset.Name)
+		}
+	}
+
+	if provider.GCE != nil {
+		var gceSpec GCESpec
+
+		if err = json.Unmarshal(specRaw, &gceSpec); err != nil {
+			return nil, errors.Wrap(err, "could not parse GCE Spec for worker machines")
+		}
+
+		if err = gceSpec.Validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid GCE Spec of workerset %q", workerset.Name)
+		}
+	}
+
+	if provider.Azure != nil {
+		var azureSpec AzureSpec
+
+		if err = json.Unmarshal(specRaw, &azureSpec); err != nil {
+			return nil, errors.Wrap(err, "could not parse Azure Spec for worker machines")
+		}
+
+		if err = azureSpec.Validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid Azure Spec of workerset %q", workerset.Name)
+		}
+	}
+
 	spec := make(map[string]interface{})
 	err = json.Unmarshal(specRaw, &spec)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to parse the workerset spec")
 	}
 
+	if provider.Azure != nil {
+		// Azure doesn't allow slashes in tag names, so the AWS-like
+		// kubernetes.io/cluster/<name> tag is set with underscores instead.
+		// The tag is set directly on the spec, so fields unknown to
+		// AzureSpec are preserved.
+		tags, _ := spec["tags"].(map[string]interface{})
+		if tags == nil {
+			tags = map[string]interface{}{}
+		}
+		tags[fmt.Sprintf("kubernetes.io_cluster_%s", cluster.Name)] = "shared"
+		spec["tags"] = tags
+	}
+
+	if provider.Hetzner != nil {
+		// attach the machines to the cluster's private network, so the
+		// workers can reach the control plane over the private addresses
+		networks, _ := spec["networks"].([]interface{})
+		if len(networks) == 0 && provider.Hetzner.NetworkID != "" {
+			spec["networks"] = []interface{}{provider.Hetzner.NetworkID}
+		}
+
+		placementGroup, _ := spec["placementGroup"].(string)
+		if placementGroup == "" && workerset.Replicas != nil && *workerset.Replicas <= HetznerPlacementGroupMaxServers {
+			spec["placementGroup"] = fmt.Sprintf("%s-%s", cluster.Name, workerset.Name)
+		}
+	}
+
+	if provider.DigitalOcean != nil {
+		tag := DigitalOceanClusterTag(cluster.Name)
+		tags, _ := spec["tags"].([]interface{})
+		if !containsTag(tags, tag) {
+			spec["tags"] = append(tags, tag)
+		}
+
+		vpc, _ := spec["vpc_uuid"].(string)
+		if vpc == "" && provider.DigitalOcean.VPCUUID != "" {
+			spec["vpc_uuid"] = provider.DigitalOcean.VPCUUID
+		}
+	}
+
+	if provider.GCE != nil {
+		// the network tag allows firewall rules to target all worker
+		// instances of the cluster
+		tag := GCENetworkTag(cluster.Name)
+		tags, _ := spec["tags"].([]interface{})
+		if !containsTag(tags, tag) {
+			spec["tags"] = append(tags, tag)
+		}
+	}
+
 	return spec, nil
 }
+
+func containsTag(tags []interface{}, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
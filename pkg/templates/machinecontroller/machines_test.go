@@ -0,0 +1,576 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinecontroller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMachineSpecVSphere(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr bool
+	}{
+		{
+			name: "datastore cluster, resource pool, anti-affinity and tags",
+			spec: `{"cluster": "cl-1", "datastoreCluster": "dsc-1", "resourcePool": "pool-1", "vmAntiAffinity": true,
+				"tags": [{"name": "k8s-worker", "categoryID": "urn:vmomi:InventoryServiceCategory:1:GLOBAL"}]}`,
+		},
+		{
+			name: "empty datastore cluster from terraform",
+			spec: `{"datastore": "ds-1", "datastoreCluster": ""}`,
+		},
+		{
+			name:    "no datastore",
+			spec:    `{"cluster": "cl-1"}`,
+			wantErr: true,
+		},
+		{
+			name:    "datastore and datastore cluster",
+			spec:    `{"datastore": "ds-1", "datastoreCluster": "dsc-1"}`,
+			wantErr: true,
+		},
+		{
+			name:    "anti-affinity without cluster",
+			spec:    `{"datastore": "ds-1", "vmAntiAffinity": true}`,
+			wantErr: true,
+		},
+		{
+			name:    "tag without category",
+			spec:    `{"datastore": "ds-1", "tags": [{"name": "k8s-worker"}]}`,
+			wantErr: true,
+		},
+		{
+			name: "duplicate tag",
+			spec: `{"datastore": "ds-1", "tags": [
+				{"name": "k8s-worker", "categoryID": "cat-1"},
+				{"name": "k8s-worker", "categoryID": "cat-1", "description": "worker"}
+			]}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			provider := kubeoneapi.CloudProviderSpec{Vsphere: &kubeoneapi.VsphereSpec{}}
+			cluster := &kubeoneapi.KubeOneCluster{Name: "test", CloudProvider: provider}
+			workerset := kubeoneapi.DynamicWorkerConfig{
+				Name:   "pool1",
+				Config: kubeoneapi.ProviderSpec{CloudProviderSpec: json.RawMessage(tt.spec)},
+			}
+
+			spec, err := machineSpec(cluster, workerset, provider)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("machineSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			// the spec is passed to machine-controller as it is
+			want := map[string]interface{}{}
+			if err := json.Unmarshal([]byte(tt.spec), &want); err != nil {
+				t.Fatal(err)
+			}
+			gotJSON, _ := json.Marshal(spec)
+			wantJSON, _ := json.Marshal(want)
+			if string(gotJSON) != string(wantJSON) {
+				t.Errorf("machineSpec() = %s, want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
+
+func TestMachineSpecAzure(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		wantTags map[string]interface{}
+		wantErr  bool
+	}{
+		{
+			name: "spot with accelerated networking",
+			spec: `{"vmSize": "Standard_D4s_v3", "zones": ["1", "2"], "priority": "Spot", "evictionPolicy": "Delete",
+				"maxPrice": -1, "enableAcceleratedNetworking": true, "tags": {"team": "infra"}}`,
+			wantTags: map[string]interface{}{"team": "infra", "kubernetes.io_cluster_test": "shared"},
+		},
+		{
+			name:     "regular without tags",
+			spec:     `{"vmSize": "Standard_D4s_v3", "priority": "Regular"}`,
+			wantTags: map[string]interface{}{"kubernetes.io_cluster_test": "shared"},
+		},
+		{
+			name:    "eviction policy without spot",
+			spec:    `{"evictionPolicy": "Deallocate"}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown priority",
+			spec:    `{"priority": "Low"}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid max price",
+			spec:    `{"priority": "Spot", "maxPrice": 0}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown zone",
+			spec:    `{"zones": ["4"]}`,
+			wantErr: true,
+		},
+		{
+			name:    "zones with availability set",
+			spec:    `{"zones": ["1"], "availabilitySet": "workers"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			provider := kubeoneapi.CloudProviderSpec{Azure: &kubeoneapi.AzureSpec{}}
+			cluster := &kubeoneapi.KubeOneCluster{Name: "test", CloudProvider: provider}
+			workerset := kubeoneapi.DynamicWorkerConfig{
+				Name:   "pool1",
+				Config: kubeoneapi.ProviderSpec{CloudProviderSpec: json.RawMessage(tt.spec)},
+			}
+
+			spec, err := machineSpec(cluster, workerset, provider)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("machineSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			gotJSON, _ := json.Marshal(spec["tags"])
+			wantJSON, _ := json.Marshal(tt.wantTags)
+			if string(gotJSON) != string(wantJSON) {
+				t.Errorf("machineSpec() tags = %s, want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
+
+func TestWorkersetsAzureZones(t *testing.T) {
+	replicas := 5
+	cluster := &kubeoneapi.KubeOneCluster{
+		Name:          "test",
+		CloudProvider: kubeoneapi.CloudProviderSpec{Azure: &kubeoneapi.AzureSpec{}},
+		DynamicWorkers: []kubeoneapi.DynamicWorkerConfig{
+			{
+				Name:     "pool1",
+				Replicas: &replicas,
+				Config:   kubeoneapi.ProviderSpec{CloudProviderSpec: json.RawMessage(`{"vmSize": "Standard_D4s_v3", "zones": ["1", "2", "3"]}`)},
+			},
+			{
+				Name:     "pool2",
+				Replicas: &replicas,
+				Config:   kubeoneapi.ProviderSpec{CloudProviderSpec: json.RawMessage(`{"vmSize": "Standard_D4s_v3", "zones": ["2"]}`)},
+			},
+		},
+	}
+
+	workersets, err := Workersets(cluster)
+	if err != nil {
+		t.Fatalf("Workersets() error = %v", err)
+	}
+
+	want := []struct {
+		name     string
+		replicas int
+		spec     string
+	}{
+		{name: "pool1-1", replicas: 2, spec: `{"vmSize":"Standard_D4s_v3","zones":["1"]}`},
+		{name: "pool1-2", replicas: 2, spec: `{"vmSize":"Standard_D4s_v3","zones":["2"]}`},
+		{name: "pool1-3", replicas: 1, spec: `{"vmSize":"Standard_D4s_v3","zones":["3"]}`},
+		{name: "pool2", replicas: 5, spec: `{"vmSize": "Standard_D4s_v3", "zones": ["2"]}`},
+	}
+
+	if len(workersets) != len(want) {
+		t.Fatalf("Workersets() returned %d workersets, want %d", len(workersets), len(want))
+	}
+
+	for i, w := range want {
+		got := workersets[i]
+		if got.Name != w.name || *got.Replicas != w.replicas || string(got.Config.CloudProviderSpec) != w.spec {
+			t.Errorf("workerset %d = %s (%d replicas, %s), want %s (%d replicas, %s)",
+				i, got.Name, *got.Replicas, got.Config.CloudProviderSpec, w.name, w.replicas, w.spec)
+		}
+	}
+
+	if *cluster.DynamicWorkers[0].Replicas != 5 {
+		t.Errorf("Workersets() modified the original workerset")
+	}
+}
+
+func TestMachineSpecAWS(t *testing.T) {
+	tests := []struct {
+		name             string
+		spec             string
+		wantInstanceType string
+		wantErr          bool
+	}{
+		{
+			name: "spot with instance types, KMS key and IMDSv2",
+			spec: `{"instanceTypes": ["m5.large", "m5a.large"], "isSpotInstance": true,
+				"spotInstanceConfig": {"maxPrice": "0.05", "persistentRequest": true, "interruptionBehavior": "stop"},
+				"ebsVolumeEncrypted": true, "ebsVolumeKmsKeyId": "arn:aws:kms:eu-west-1:123456789012:key/abcd",
+				"instanceMetadataOptions": {"httpTokens": "required", "httpPutResponseHopLimit": 2}}`,
+			wantInstanceType: "m5.large",
+		},
+		{
+			name:             "instance type takes precedence over instance types",
+			spec:             `{"instanceType": "t3.medium", "instanceTypes": ["m5.large"]}`,
+			wantInstanceType: "t3.medium",
+		},
+		{
+			name:    "spot config without spot instance",
+			spec:    `{"instanceType": "t3.medium", "spotInstanceConfig": {"maxPrice": "0.05"}}`,
+			wantErr: true,
+		},
+		{
+			name:    "stop interruption behavior without persistent request",
+			spec:    `{"instanceType": "t3.medium", "isSpotInstance": true, "spotInstanceConfig": {"interruptionBehavior": "stop"}}`,
+			wantErr: true,
+		},
+		{
+			name:    "duplicate instance types",
+			spec:    `{"instanceTypes": ["m5.large", "m5.large"]}`,
+			wantErr: true,
+		},
+		{
+			name:    "KMS key without encryption",
+			spec:    `{"instanceType": "t3.medium", "ebsVolumeKmsKeyId": "alias/workers"}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown http tokens",
+			spec:    `{"instanceType": "t3.medium", "instanceMetadataOptions": {"httpTokens": "always"}}`,
+			wantErr: true,
+		},
+		{
+			name:    "hop limit out of range",
+			spec:    `{"instanceType": "t3.medium", "instanceMetadataOptions": {"httpPutResponseHopLimit": 65}}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			provider := kubeoneapi.CloudProviderSpec{AWS: &kubeoneapi.AWSSpec{}}
+			cluster := &kubeoneapi.KubeOneCluster{Name: "test", CloudProvider: provider}
+			workerset := kubeoneapi.DynamicWorkerConfig{
+				Name:   "pool1",
+				Config: kubeoneapi.ProviderSpec{CloudProviderSpec: json.RawMessage(tt.spec)},
+			}
+
+			spec, err := machineSpec(cluster, workerset, provider)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("machineSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if spec["instanceType"] != tt.wantInstanceType {
+				t.Errorf("machineSpec() instanceType = %v, want %s", spec["instanceType"], tt.wantInstanceType)
+			}
+
+			tags, _ := spec["tags"].(map[string]interface{})
+			if tags["kubernetes.io/cluster/test"] != "shared" {
+				t.Errorf("machineSpec() tags = %v, want the cluster tag", tags)
+			}
+		})
+	}
+}
+
+func TestMachineSpecGCE(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		wantTags []interface{}
+		wantErr  bool
+	}{
+		{
+			name: "shielded VM with service account",
+			spec: `{"machineType": "n1-standard-2", "tags": ["firewall", "targets"],
+				"shieldedVM": {"enableSecureBoot": true, "enableVtpm": true, "enableIntegrityMonitoring": true},
+				"serviceAccount": "workers@project.iam.gserviceaccount.com", "scopes": ["https://www.googleapis.com/auth/cloud-platform"]}`,
+			wantTags: []interface{}{"firewall", "targets", "kubeone-test"},
+		},
+		{
+			name:     "cluster tag already set",
+			spec:     `{"machineType": "n1-standard-2", "tags": ["kubeone-test"]}`,
+			wantTags: []interface{}{"kubeone-test"},
+		},
+		{
+			name:    "integrity monitoring without vTPM",
+			spec:    `{"shieldedVM": {"enableVtpm": false, "enableIntegrityMonitoring": true}}`,
+			wantErr: true,
+		},
+		{
+			name:    "service account without email",
+			spec:    `{"serviceAccount": "workers"}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid network tag",
+			spec:    `{"tags": ["Workers_Pool"]}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			provider := kubeoneapi.CloudProviderSpec{GCE: &kubeoneapi.GCESpec{}}
+			cluster := &kubeoneapi.KubeOneCluster{Name: "test", CloudProvider: provider}
+			workerset := kubeoneapi.DynamicWorkerConfig{
+				Name:   "pool1",
+				Config: kubeoneapi.ProviderSpec{CloudProviderSpec: json.RawMessage(tt.spec)},
+			}
+
+			spec, err := machineSpec(cluster, workerset, provider)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("machineSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			gotJSON, _ := json.Marshal(spec["tags"])
+			wantJSON, _ := json.Marshal(tt.wantTags)
+			if string(gotJSON) != string(wantJSON) {
+				t.Errorf("machineSpec() tags = %s, want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
+
+func TestGCENetworkTag(t *testing.T) {
+	tests := []struct {
+		clusterName string
+		want        string
+	}{
+		{clusterName: "prod", want: "kubeone-prod"},
+		{clusterName: "Prod_EU.1", want: "kubeone-prod-eu-1"},
+		{clusterName: "a-very-long-cluster-name-that-does-not-fit-in-a-network-tag-", want: "kubeone-a-very-long-cluster-name-that-does-not-fit-in-a-network"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.clusterName, func(t *testing.T) {
+			if got := GCENetworkTag(tt.clusterName); got != tt.want {
+				t.Errorf("GCENetworkTag() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMachineSpecOpenStack(t *testing.T) {
+	const multiNetworkCloudConfig = `
+[Global]
+auth-url = "https://keystone.example.com:5000/v3"
+
+[Networking]
+internal-network-name = "k8s-internal"
+
+[BlockStorage]
+trust-device-path = false
+`
+
+	tests := []struct {
+		name        string
+		spec        string
+		cloudConfig string
+		wantErr     bool
+	}{
+		{
+			name:        "two networks with server group and config drive",
+			spec:        `{"flavor": "m1.medium", "networks": ["k8s-internal", "storage"], "serverGroup": "a1b2", "configDrive": true}`,
+			cloudConfig: multiNetworkCloudConfig,
+		},
+		{
+			name:        "networks referenced by ID",
+			spec:        `{"networks": ["9e5b4d2a-3c1f-4e8a-b6d7-0f1e2d3c4b5a", "storage"]}`,
+			cloudConfig: multiNetworkCloudConfig,
+		},
+		{
+			name: "single network",
+			spec: `{"network": "k8s-internal"}`,
+		},
+		{
+			name:    "network and networks",
+			spec:    `{"network": "k8s-internal", "networks": ["storage"]}`,
+			wantErr: true,
+		},
+		{
+			name:    "multiple networks without internal network",
+			spec:    `{"networks": ["k8s-internal", "storage"]}`,
+			wantErr: true,
+		},
+		{
+			name:        "multiple networks without the internal network",
+			spec:        `{"networks": ["public", "storage"]}`,
+			cloudConfig: multiNetworkCloudConfig,
+			wantErr:     true,
+		},
+		{
+			name:        "trust device path disabled in cloud-config",
+			spec:        `{"network": "k8s-internal", "trustDevicePath": true}`,
+			cloudConfig: multiNetworkCloudConfig,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			provider := kubeoneapi.CloudProviderSpec{Openstack: &kubeoneapi.OpenstackSpec{}, CloudConfig: tt.cloudConfig}
+			cluster := &kubeoneapi.KubeOneCluster{Name: "test", CloudProvider: provider}
+			workerset := kubeoneapi.DynamicWorkerConfig{
+				Name:   "pool1",
+				Config: kubeoneapi.ProviderSpec{CloudProviderSpec: json.RawMessage(tt.spec)},
+			}
+
+			_, err := machineSpec(cluster, workerset, provider)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("machineSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMachineSpecDigitalOcean(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			spec: `{"size": "s-2vcpu-4gb", "tags": ["workers"]}`,
+			want: `{"size":"s-2vcpu-4gb","tags":["workers","kubernetes-cluster:test"],"vpc_uuid":"5a4981aa-9653-4bd1-bef5-d6bff52042e4"}`,
+		},
+		{
+			name: "explicit VPC and cluster tag",
+			spec: `{"size": "s-2vcpu-4gb", "tags": ["kubernetes-cluster:test"], "vpc_uuid": "0d3176ad-41e0-4021-b831-0c5c45c60959"}`,
+			want: `{"size":"s-2vcpu-4gb","tags":["kubernetes-cluster:test"],"vpc_uuid":"0d3176ad-41e0-4021-b831-0c5c45c60959"}`,
+		},
+		{
+			name:    "invalid VPC UUID",
+			spec:    `{"vpc_uuid": "default"}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid tag",
+			spec:    `{"tags": ["workers pool"]}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			provider := kubeoneapi.CloudProviderSpec{DigitalOcean: &kubeoneapi.DigitalOceanSpec{VPCUUID: "5a4981aa-9653-4bd1-bef5-d6bff52042e4"}}
+			cluster := &kubeoneapi.KubeOneCluster{Name: "test", CloudProvider: provider}
+			workerset := kubeoneapi.DynamicWorkerConfig{
+				Name:   "pool1",
+				Config: kubeoneapi.ProviderSpec{CloudProviderSpec: json.RawMessage(tt.spec)},
+			}
+
+			spec, err := machineSpec(cluster, workerset, provider)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("machineSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got, _ := json.Marshal(spec)
+			if string(got) != tt.want {
+				t.Errorf("machineSpec() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeepExistingReplicas(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clusterv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	existingReplicas := int32(7)
+	existing := &clusterv1alpha1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "autoscaled", Namespace: metav1.NamespaceSystem},
+		Spec:       clusterv1alpha1.MachineDeploymentSpec{Replicas: &existingReplicas},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+
+	tests := []struct {
+		name     string
+		mdName   string
+		expected int32
+	}{
+		{
+			name:     "existing MachineDeployment",
+			mdName:   "autoscaled",
+			expected: 7,
+		},
+		{
+			name:     "new MachineDeployment",
+			mdName:   "new",
+			expected: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			replicas := int32(2)
+			md := &clusterv1alpha1.MachineDeployment{
+				ObjectMeta: metav1.ObjectMeta{Name: tt.mdName, Namespace: metav1.NamespaceSystem},
+				Spec:       clusterv1alpha1.MachineDeploymentSpec{Replicas: &replicas},
+			}
+
+			if err := keepExistingReplicas(context.Background(), c, md); err != nil {
+				t.Fatalf("keepExistingReplicas() error = %v", err)
+			}
+
+			if *md.Spec.Replicas != tt.expected {
+				t.Errorf("expected %d replicas, got %d", tt.expected, *md.Spec.Replicas)
+			}
+		})
+	}
+}
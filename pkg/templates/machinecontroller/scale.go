@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinecontroller
+
+import (
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/state"
+	"k8c.io/kubeone/pkg/templates"
+	"k8c.io/kubeone/pkg/templates/resources"
+
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/retry"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GetMachineDeployment returns the MachineDeployment with the given name
+func GetMachineDeployment(s *state.State, name string) (*clusterv1alpha1.MachineDeployment, error) {
+	if s.DynamicClient == nil {
+		return nil, errors.New("kubernetes dynamic client in not initialized")
+	}
+
+	md := &clusterv1alpha1.MachineDeployment{}
+	key := dynclient.ObjectKey{Name: name, Namespace: resources.MachineControllerNameSpace}
+	if err := s.DynamicClient.Get(s.Context, key, md); err != nil {
+		return nil, errors.Wrapf(err, "failed to get MachineDeployment %q", name)
+	}
+
+	return md, nil
+}
+
+// ScaleMachineDeployment sets number of replicas of the MachineDeployment
+func ScaleMachineDeployment(s *state.State, name string, replicas int32) error {
+	if s.DynamicClient == nil {
+		return errors.New("kubernetes dynamic client in not initialized")
+	}
+
+	key := dynclient.ObjectKey{Name: name, Namespace: resources.MachineControllerNameSpace}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		md := clusterv1alpha1.MachineDeployment{}
+		if err := s.DynamicClient.Get(s.Context, key, &md); err != nil {
+			return err
+		}
+
+		md.Spec.Replicas = &replicas
+		return s.DynamicClient.Update(s.Context, &md)
+	})
+
+	return errors.Wrapf(err, "failed to scale MachineDeployment %q", name)
+}
+
+// ScaleDownWorkers scales all MachineDeployments to zero replicas, so all
+// Machines are deleted, but MachineDeployments are kept
+func ScaleDownWorkers(s *state.State) error {
+	if !s.Cluster.MachineController.Deploy {
+		s.Logger.Info("Skipping scaling down workers because machine-controller is disabled in configuration.")
+		return nil
+	}
+	if s.DynamicClient == nil {
+		return errors.New("kubernetes client not initialized")
+	}
+
+	if err := annotateNodesSkipEviction(s.Context, s); err != nil {
+		return err
+	}
+
+	s.Logger.Info("Scaling down MachineDeployment objects...")
+	mdList := &clusterv1alpha1.MachineDeploymentList{}
+	if err := s.DynamicClient.List(s.Context, mdList, dynclient.InNamespace(resources.MachineControllerNameSpace)); err != nil {
+		return errors.Wrap(err, "unable to list machinedeployment objects")
+	}
+
+	for _, md := range mdList.Items {
+		if err := ScaleMachineDeployment(s, md.Name, 0); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ExportMachineDeployments returns YAML manifest of all MachineDeployments
+// in the cluster, stripped of the server-populated fields, so they can be
+// recreated later on
+func ExportMachineDeployments(s *state.State) (string, error) {
+	if s.DynamicClient == nil {
+		return "", errors.New("kubernetes client not initialized")
+	}
+
+	mdList := &clusterv1alpha1.MachineDeploymentList{}
+	if err := s.DynamicClient.List(s.Context, mdList, dynclient.InNamespace(resources.MachineControllerNameSpace)); err != nil {
+		return "", errors.Wrap(err, "unable to list machinedeployment objects")
+	}
+
+	objs := []runtime.Object{}
+	for i := range mdList.Items {
+		md := mdList.Items[i]
+		md.TypeMeta = metav1.TypeMeta{
+			APIVersion: clusterv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "MachineDeployment",
+		}
+		md.ObjectMeta = metav1.ObjectMeta{
+			Name:        md.Name,
+			Namespace:   md.Namespace,
+			Labels:      md.Labels,
+			Annotations: md.Annotations,
+		}
+		md.Status = clusterv1alpha1.MachineDeploymentStatus{}
+
+		objs = append(objs, &md)
+	}
+
+	return templates.KubernetesToYAML(objs)
+}
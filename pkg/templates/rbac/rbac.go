@@ -0,0 +1,269 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/clientutil"
+	"k8c.io/kubeone/pkg/state"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// componentName is used to label the RBAC resources managed by KubeOne,
+	// so they can be found and removed when removed from the configuration
+	componentName = "rbac-bootstrap"
+)
+
+// Ensure creates and updates the RBAC resources defined in the
+// KubeOneCluster manifest, and removes resources that are no longer defined
+func Ensure(s *state.State) error {
+	if s.DynamicClient == nil {
+		return errors.New("kubernetes client not initialized")
+	}
+
+	ctx := s.Context
+	var (
+		clusterRoles        []rbacv1.ClusterRole
+		clusterRoleBindings []rbacv1.ClusterRoleBinding
+		roleBindings        []rbacv1.RoleBinding
+	)
+
+	if s.Cluster.RBAC != nil {
+		clusterRoles = desiredClusterRoles(s.Cluster.RBAC)
+		clusterRoleBindings = desiredClusterRoleBindings(s.Cluster.RBAC)
+		roleBindings = desiredRoleBindings(s.Cluster.RBAC)
+	}
+
+	for i := range clusterRoles {
+		if _, err := getManaged(ctx, s.DynamicClient, dynclient.ObjectKeyFromObject(&clusterRoles[i]), &rbacv1.ClusterRole{}); err != nil {
+			return errors.Wrapf(err, "failed to ensure ClusterRole %q", clusterRoles[i].Name)
+		}
+		if err := clientutil.CreateOrUpdate(ctx, s.DynamicClient, &clusterRoles[i], clientutil.WithComponentLabel(componentName), clientutil.WithManifestPatches(s.Cluster.ManifestPatches)); err != nil {
+			return errors.Wrapf(err, "failed to ensure ClusterRole %q", clusterRoles[i].Name)
+		}
+	}
+
+	for i := range clusterRoleBindings {
+		if err := ensureBinding(ctx, s.DynamicClient, &clusterRoleBindings[i], clusterRoleBindings[i].RoleRef, &rbacv1.ClusterRoleBinding{}, s.Cluster.ManifestPatches); err != nil {
+			return errors.Wrapf(err, "failed to ensure ClusterRoleBinding %q", clusterRoleBindings[i].Name)
+		}
+	}
+
+	for i := range roleBindings {
+		if err := ensureBinding(ctx, s.DynamicClient, &roleBindings[i], roleBindings[i].RoleRef, &rbacv1.RoleBinding{}, s.Cluster.ManifestPatches); err != nil {
+			return errors.Wrapf(err, "failed to ensure RoleBinding %s/%s", roleBindings[i].Namespace, roleBindings[i].Name)
+		}
+	}
+
+	return cleanup(s, clusterRoles, clusterRoleBindings, roleBindings)
+}
+
+// ensureBinding creates or updates the binding. RoleRef is immutable, so the
+// binding is recreated if the referenced role is changed.
+func ensureBinding(ctx context.Context, c dynclient.Client, obj dynclient.Object, roleRef rbacv1.RoleRef, existing dynclient.Object, patches []kubeoneapi.ManifestPatch) error {
+	found, err := getManaged(ctx, c, dynclient.ObjectKeyFromObject(obj), existing)
+	if err != nil {
+		return err
+	}
+
+	if found {
+		var existingRoleRef rbacv1.RoleRef
+		switch e := existing.(type) {
+		case *rbacv1.ClusterRoleBinding:
+			existingRoleRef = e.RoleRef
+		case *rbacv1.RoleBinding:
+			existingRoleRef = e.RoleRef
+		}
+
+		if existingRoleRef != roleRef {
+			if err = clientutil.DeleteIfExists(ctx, c, existing); err != nil {
+				return err
+			}
+		}
+	}
+
+	return clientutil.CreateOrUpdate(ctx, c, obj, clientutil.WithComponentLabel(componentName), clientutil.WithManifestPatches(patches))
+}
+
+// getManaged gets the object with the given key into existing, and returns
+// whether it exists. The existing object not labeled as managed by KubeOne is
+// refused, so RBAC resources created by the cluster users aren't taken over.
+func getManaged(ctx context.Context, c dynclient.Client, key dynclient.ObjectKey, existing dynclient.Object) (bool, error) {
+	err := c.Get(ctx, key, existing)
+	switch {
+	case k8serrors.IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	if existing.GetLabels()[clientutil.KubeoneComponentLabel] != componentName {
+		return true, errors.Errorf("already exists and is not managed by KubeOne (missing the %s=%s label)", clientutil.KubeoneComponentLabel, componentName)
+	}
+
+	return true, nil
+}
+
+func cleanup(s *state.State, clusterRoles []rbacv1.ClusterRole, clusterRoleBindings []rbacv1.ClusterRoleBinding, roleBindings []rbacv1.RoleBinding) error {
+	ctx := s.Context
+	listOpts := dynclient.MatchingLabels{clientutil.KubeoneComponentLabel: componentName}
+
+	desired := map[dynclient.ObjectKey]bool{}
+	for i := range clusterRoles {
+		desired[dynclient.ObjectKeyFromObject(&clusterRoles[i])] = true
+	}
+	existingClusterRoles := rbacv1.ClusterRoleList{}
+	if err := s.DynamicClient.List(ctx, &existingClusterRoles, listOpts); err != nil {
+		return errors.Wrap(err, "failed to list ClusterRoles")
+	}
+	for i := range existingClusterRoles.Items {
+		cr := &existingClusterRoles.Items[i]
+		if desired[dynclient.ObjectKeyFromObject(cr)] {
+			continue
+		}
+		s.Logger.Infof("Removing ClusterRole %q...", cr.Name)
+		if err := clientutil.DeleteIfExists(ctx, s.DynamicClient, cr); err != nil {
+			return errors.Wrapf(err, "failed to remove ClusterRole %q", cr.Name)
+		}
+	}
+
+	desired = map[dynclient.ObjectKey]bool{}
+	for i := range clusterRoleBindings {
+		desired[dynclient.ObjectKeyFromObject(&clusterRoleBindings[i])] = true
+	}
+	existingClusterRoleBindings := rbacv1.ClusterRoleBindingList{}
+	if err := s.DynamicClient.List(ctx, &existingClusterRoleBindings, listOpts); err != nil {
+		return errors.Wrap(err, "failed to list ClusterRoleBindings")
+	}
+	for i := range existingClusterRoleBindings.Items {
+		crb := &existingClusterRoleBindings.Items[i]
+		if desired[dynclient.ObjectKeyFromObject(crb)] {
+			continue
+		}
+		s.Logger.Infof("Removing ClusterRoleBinding %q...", crb.Name)
+		if err := clientutil.DeleteIfExists(ctx, s.DynamicClient, crb); err != nil {
+			return errors.Wrapf(err, "failed to remove ClusterRoleBinding %q", crb.Name)
+		}
+	}
+
+	desired = map[dynclient.ObjectKey]bool{}
+	for i := range roleBindings {
+		desired[dynclient.ObjectKeyFromObject(&roleBindings[i])] = true
+	}
+	existingRoleBindings := rbacv1.RoleBindingList{}
+	if err := s.DynamicClient.List(ctx, &existingRoleBindings, listOpts); err != nil {
+		return errors.Wrap(err, "failed to list RoleBindings")
+	}
+	for i := range existingRoleBindings.Items {
+		rb := &existingRoleBindings.Items[i]
+		if desired[dynclient.ObjectKeyFromObject(rb)] {
+			continue
+		}
+		s.Logger.Infof("Removing RoleBinding %s/%s...", rb.Namespace, rb.Name)
+		if err := clientutil.DeleteIfExists(ctx, s.DynamicClient, rb); err != nil {
+			return errors.Wrapf(err, "failed to remove RoleBinding %s/%s", rb.Namespace, rb.Name)
+		}
+	}
+
+	return nil
+}
+
+func desiredClusterRoles(cfg *kubeoneapi.RBACConfig) []rbacv1.ClusterRole {
+	clusterRoles := []rbacv1.ClusterRole{}
+
+	for _, cr := range cfg.ClusterRoles {
+		clusterRoles = append(clusterRoles, rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: cr.Name,
+			},
+			Rules: cr.Rules,
+		})
+	}
+
+	return clusterRoles
+}
+
+func desiredClusterRoleBindings(cfg *kubeoneapi.RBACConfig) []rbacv1.ClusterRoleBinding {
+	clusterRoleBindings := []rbacv1.ClusterRoleBinding{}
+
+	for _, crb := range cfg.ClusterRoleBindings {
+		clusterRoleBindings = append(clusterRoleBindings, rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: crb.Name,
+			},
+			RoleRef:  clusterRoleRef(crb.ClusterRole),
+			Subjects: subjects(crb),
+		})
+	}
+
+	return clusterRoleBindings
+}
+
+func desiredRoleBindings(cfg *kubeoneapi.RBACConfig) []rbacv1.RoleBinding {
+	roleBindings := []rbacv1.RoleBinding{}
+
+	for _, rb := range cfg.RoleBindings {
+		roleBindings = append(roleBindings, rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      rb.Name,
+				Namespace: rb.Namespace,
+			},
+			RoleRef:  clusterRoleRef(rb.ClusterRole),
+			Subjects: subjects(rb),
+		})
+	}
+
+	return roleBindings
+}
+
+func clusterRoleRef(name string) rbacv1.RoleRef {
+	return rbacv1.RoleRef{
+		APIGroup: rbacv1.GroupName,
+		Kind:     "ClusterRole",
+		Name:     name,
+	}
+}
+
+func subjects(binding kubeoneapi.RBACRoleBinding) []rbacv1.Subject {
+	subjects := []rbacv1.Subject{}
+
+	for _, group := range binding.Groups {
+		subjects = append(subjects, rbacv1.Subject{
+			APIGroup: rbacv1.GroupName,
+			Kind:     rbacv1.GroupKind,
+			Name:     group,
+		})
+	}
+	for _, user := range binding.Users {
+		subjects = append(subjects, rbacv1.Subject{
+			APIGroup: rbacv1.GroupName,
+			Kind:     rbacv1.UserKind,
+			Name:     user,
+		})
+	}
+
+	return subjects
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/clientutil"
+	"k8c.io/kubeone/pkg/state"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var testRBACConfig = &kubeoneapi.RBACConfig{
+	ClusterRoleBindings: []kubeoneapi.RBACRoleBinding{
+		{
+			Name:        "oidc-admins",
+			ClusterRole: "cluster-admin",
+			Groups:      []string{"oidc:admins"},
+			Users:       []string{"admin@example.com"},
+		},
+	},
+	RoleBindings: []kubeoneapi.RBACRoleBinding{
+		{
+			Name:        "oidc-developers",
+			Namespace:   "dev",
+			ClusterRole: "edit",
+			Groups:      []string{"oidc:developers"},
+		},
+	},
+}
+
+func newState(rbac *kubeoneapi.RBACConfig, objs ...dynclient.Object) *state.State {
+	return &state.State{
+		Context:       context.Background(),
+		Logger:        logrus.New(),
+		Cluster:       &kubeoneapi.KubeOneCluster{RBAC: rbac},
+		DynamicClient: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).Build(),
+	}
+}
+
+func managedLabels() map[string]string {
+	return map[string]string{clientutil.KubeoneComponentLabel: componentName}
+}
+
+func TestDesiredBindings(t *testing.T) {
+	clusterRoleBindings := desiredClusterRoleBindings(testRBACConfig)
+	expectedClusterRoleBindings := []rbacv1.ClusterRoleBinding{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "oidc-admins"},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "cluster-admin"},
+			Subjects: []rbacv1.Subject{
+				{APIGroup: rbacv1.GroupName, Kind: rbacv1.GroupKind, Name: "oidc:admins"},
+				{APIGroup: rbacv1.GroupName, Kind: rbacv1.UserKind, Name: "admin@example.com"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(clusterRoleBindings, expectedClusterRoleBindings) {
+		t.Errorf("desiredClusterRoleBindings() = %+v, want %+v", clusterRoleBindings, expectedClusterRoleBindings)
+	}
+
+	roleBindings := desiredRoleBindings(testRBACConfig)
+	expectedRoleBindings := []rbacv1.RoleBinding{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "oidc-developers", Namespace: "dev"},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "edit"},
+			Subjects: []rbacv1.Subject{
+				{APIGroup: rbacv1.GroupName, Kind: rbacv1.GroupKind, Name: "oidc:developers"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(roleBindings, expectedRoleBindings) {
+		t.Errorf("desiredRoleBindings() = %+v, want %+v", roleBindings, expectedRoleBindings)
+	}
+}
+
+func TestEnsure(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []dynclient.Object
+		wantErr  bool
+	}{
+		{
+			name: "bindings created",
+		},
+		{
+			name: "managed binding with changed role recreated",
+			existing: []dynclient.Object{
+				&rbacv1.ClusterRoleBinding{
+					ObjectMeta: metav1.ObjectMeta{Name: "oidc-admins", Labels: managedLabels()},
+					RoleRef:    clusterRoleRef("view"),
+				},
+			},
+		},
+		{
+			name: "removed managed binding deleted",
+			existing: []dynclient.Object{
+				&rbacv1.RoleBinding{
+					ObjectMeta: metav1.ObjectMeta{Name: "removed", Namespace: "dev", Labels: managedLabels()},
+					RoleRef:    clusterRoleRef("view"),
+				},
+			},
+		},
+		{
+			name: "unmanaged ClusterRoleBinding refused",
+			existing: []dynclient.Object{
+				&rbacv1.ClusterRoleBinding{
+					ObjectMeta: metav1.ObjectMeta{Name: "oidc-admins"},
+					RoleRef:    clusterRoleRef("view"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unmanaged RoleBinding refused",
+			existing: []dynclient.Object{
+				&rbacv1.RoleBinding{
+					ObjectMeta: metav1.ObjectMeta{Name: "oidc-developers", Namespace: "dev"},
+					RoleRef:    clusterRoleRef("edit"),
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			s := newState(testRBACConfig, tt.existing...)
+			ctx := context.Background()
+
+			err := Ensure(s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Ensure() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				// The unmanaged binding is left as it is
+				for _, obj := range tt.existing {
+					got := obj.DeepCopyObject().(dynclient.Object)
+					if err = s.DynamicClient.Get(ctx, dynclient.ObjectKeyFromObject(obj), got); err != nil {
+						t.Fatalf("failed to get %s: %v", obj.GetName(), err)
+					}
+					if _, ok := got.GetLabels()[clientutil.KubeoneComponentLabel]; ok {
+						t.Errorf("unmanaged %s was labeled", obj.GetName())
+					}
+				}
+
+				return
+			}
+
+			crb := rbacv1.ClusterRoleBinding{}
+			if err = s.DynamicClient.Get(ctx, dynclient.ObjectKey{Name: "oidc-admins"}, &crb); err != nil {
+				t.Fatalf("failed to get ClusterRoleBinding: %v", err)
+			}
+			if crb.RoleRef != clusterRoleRef("cluster-admin") || !reflect.DeepEqual(crb.Labels, managedLabels()) {
+				t.Errorf("unexpected ClusterRoleBinding: %+v", crb)
+			}
+
+			rb := rbacv1.RoleBinding{}
+			if err = s.DynamicClient.Get(ctx, dynclient.ObjectKey{Name: "oidc-developers", Namespace: "dev"}, &rb); err != nil {
+				t.Fatalf("failed to get RoleBinding: %v", err)
+			}
+			if rb.RoleRef != clusterRoleRef("edit") || !reflect.DeepEqual(rb.Labels, managedLabels()) {
+				t.Errorf("unexpected RoleBinding: %+v", rb)
+			}
+
+			err = s.DynamicClient.Get(ctx, dynclient.ObjectKey{Name: "removed", Namespace: "dev"}, &rbacv1.RoleBinding{})
+			if !k8serrors.IsNotFound(err) {
+				t.Errorf("expected the removed RoleBinding to be deleted, got %v", err)
+			}
+		})
+	}
+}
@@ -31,21 +31,42 @@ const (
 	AddonCCMOpenStack       = "ccm-openstack"
 	AddonCCMPacket          = "ccm-packet"
 	AddonCCMVsphere         = "ccm-vsphere"
+	AddonCertManager        = "cert-manager"
+	AddonCertManagerIssuer  = "cert-manager-issuer"
+	AddonClusterAutoscaler  = "cluster-autoscaler"
+	AddonCSIAwsEBS          = "csi-aws-ebs"
 	AddonCSIAzureDisk       = "csi-azuredisk"
 	AddonCSIAzureFile       = "csi-azurefile"
+	AddonCSIDigitalOcean    = "csi-digitalocean"
 	AddonCSIHetnzer         = "csi-hetzner"
 	AddonCSIOpenStackCinder = "csi-openstack-cinder"
 	AddonCSIVsphere         = "csi-vsphere"
 	AddonCNICanal           = "cni-canal"
 	AddonCNICilium          = "cni-cilium"
 	AddonCNIWeavenet        = "cni-weavenet"
+	AddonFlux               = "flux"
+	AddonFluxSync           = "flux-sync"
+	AddonIngressNginx       = "ingress-nginx"
 	AddonMachineController  = "machinecontroller"
 	AddonMetricsServer      = "metrics-server"
+	AddonMonitoring         = "monitoring"
 	AddonNodeLocalDNS       = "nodelocaldns"
+	AddonNvidiaDevicePlugin = "nvidia-device-plugin"
+	AddonVelero             = "velero"
+	AddonVeleroBackups      = "velero-backups"
 )
 
 const (
 	NodeLocalDNSVirtualIP = "169.254.20.10"
+
+	// NvidiaGPULabel is set on nodes with NVIDIA GPUs, so the NVIDIA device
+	// plugin is scheduled only on them
+	NvidiaGPULabel = "nvidia.com/gpu.present"
+
+	// AutoscalerMinSizeAnnotation and AutoscalerMaxSizeAnnotation are set on
+	// the MachineDeployments scaled by the cluster-autoscaler
+	AutoscalerMinSizeAnnotation = "cluster.k8s.io/cluster-api-autoscaler-node-group-min-size"
+	AutoscalerMaxSizeAnnotation = "cluster.k8s.io/cluster-api-autoscaler-node-group-max-size"
 )
 
 const (
@@ -59,6 +80,13 @@ const (
 
 	VsphereCSIWebhookName      = "vsphere-webhook-svc"
 	VsphereCSIWebhookNamespace = metav1.NamespaceSystem
+
+	// Hubble certificates CommonNames, Cilium expects them in format
+	// *.<cluster-name>.hubble-grpc.cilium.io and *.hubble-relay.cilium.io
+	HubbleServerCommonName      = "*.default.hubble-grpc.cilium.io"
+	HubbleRelayClientCommonName = "*.hubble-relay.cilium.io"
+
+	CertManagerNamespace = "cert-manager"
 )
 
 const (
@@ -78,6 +106,7 @@ func All() map[string]string {
 		"MachineControllerWebhookName": MachineControllerWebhookName,
 		"KubeletImageRepository":       KubeletImageRepository,
 		"NodeLocalDNSVirtualIP":        NodeLocalDNSVirtualIP,
+		"NvidiaGPULabel":               NvidiaGPULabel,
 		"CABundleSSLCertFilePath":      cabundle.SSLCertFilePath,
 	}
 }
@@ -0,0 +1,105 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package velero
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8c.io/kubeone/pkg/addons"
+	kubeoneapi "k8c.io/kubeone/pkg/apis/kubeone"
+	"k8c.io/kubeone/pkg/clientutil"
+	"k8c.io/kubeone/pkg/credentials"
+	"k8c.io/kubeone/pkg/state"
+	"k8c.io/kubeone/pkg/templates/resources"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// Ensure deploys Velero along with the backup storage location and the
+// default scheduled backup from the Velero feature
+func Ensure(s *state.State) error {
+	if !s.Cluster.Features.Velero.Enabled() {
+		return nil
+	}
+
+	s.Logger.Infoln("Ensure Velero...")
+
+	creds, err := credentials.Any(s.Cluster.CloudProvider, s.CredentialsFilePath)
+	if err != nil {
+		return errors.Wrap(err, "unable to fetch credentials")
+	}
+
+	provider := s.Cluster.VeleroProvider()
+	for _, key := range CredentialKeys(provider) {
+		if creds[key] == "" {
+			return errors.Errorf("credential %s is required for storing the Velero backups in %s", key, provider)
+		}
+	}
+
+	if err = addons.EnsureAddonByName(s, resources.AddonVelero); err != nil {
+		return errors.Wrap(err, "failed to deploy Velero")
+	}
+
+	// The BackupStorageLocation and Schedule objects can be created only once
+	// the Velero CRDs are established
+	condFn := clientutil.CRDsReadyCondition(s.Context, s.DynamicClient, CRDNames())
+	if err = wait.Poll(5*time.Second, 3*time.Minute, condFn); err != nil {
+		return errors.Wrap(err, "failed waiting for Velero CRDs to become ready and established")
+	}
+
+	err = addons.EnsureAddonByName(s, resources.AddonVeleroBackups)
+	return errors.Wrap(err, "failed to configure Velero backups")
+}
+
+// CredentialKeys returns the names of the credentials required for storing
+// the backups in the object storage of the given provider
+func CredentialKeys(provider kubeoneapi.VeleroProvider) []string {
+	switch provider {
+	case kubeoneapi.VeleroProviderAWS:
+		return []string{credentials.AWSAccessKeyID, credentials.AWSSecretAccessKey}
+	case kubeoneapi.VeleroProviderGCP:
+		return []string{credentials.GoogleServiceAccountKey}
+	case kubeoneapi.VeleroProviderAzure:
+		return []string{
+			credentials.AzureClientID,
+			credentials.AzureClientSecret,
+			credentials.AzureTenantID,
+			credentials.AzureSubscribtionID,
+		}
+	}
+
+	return nil
+}
+
+// CRDNames returns the names of the Velero CRDs
+func CRDNames() []string {
+	return []string{
+		"backups.velero.io",
+		"backupstoragelocations.velero.io",
+		"deletebackuprequests.velero.io",
+		"downloadrequests.velero.io",
+		"podvolumebackups.velero.io",
+		"podvolumerestores.velero.io",
+		"resticrepositories.velero.io",
+		"restores.velero.io",
+		"schedules.velero.io",
+		"serverstatusrequests.velero.io",
+		"volumesnapshotlocations.velero.io",
+	}
+}
@@ -19,17 +19,27 @@ package weave
 import (
 	"crypto/rand"
 	"encoding/base64"
+	"time"
 
 	"github.com/pkg/errors"
 
+	"k8c.io/kubeone/pkg/clientutil"
 	"k8c.io/kubeone/pkg/state"
 
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const (
+	weaveNetName         = "weave-net"
+	weavePasswordName    = "weave-passwd"
+	rolloutTimeout       = 10 * time.Minute
+	rolloutCheckInterval = 5 * time.Second
+)
+
 // EnsureSecret ensure weave-net Secret with password exists
 func EnsureSecret(s *state.State) error {
 	pass, err := genPassword()
@@ -58,6 +68,56 @@ func EnsureSecret(s *state.State) error {
 	return nil
 }
 
+// RotateSecret replaces the password in the weave-net Secret with a newly
+// generated one. The weave-net pods keep using the old password until they
+// are restarted.
+func RotateSecret(s *state.State) error {
+	pass, err := genPassword()
+	if err != nil {
+		return errors.Wrap(err, "failed to generate random password")
+	}
+
+	sec := weaveSecret(pass)
+	key := client.ObjectKey{
+		Name:      sec.GetName(),
+		Namespace: sec.GetNamespace(),
+	}
+
+	existing := &corev1.Secret{}
+	err = s.DynamicClient.Get(s.Context, key, existing)
+	switch {
+	case k8serrors.IsNotFound(err):
+		return errors.Wrap(s.DynamicClient.Create(s.Context, sec), "failed to create weave-net Secret")
+	case err != nil:
+		return errors.Wrap(err, "failed to get weave-net Secret")
+	}
+
+	existing.Data = nil
+	existing.StringData = sec.StringData
+
+	return errors.Wrap(s.DynamicClient.Update(s.Context, existing), "failed to update weave-net Secret")
+}
+
+// RestartDaemonSet rolls out the weave-net DaemonSet so the pods pick up
+// the current password, and waits for the rollout to complete.
+func RestartDaemonSet(s *state.State) error {
+	key := client.ObjectKey{
+		Name:      weaveNetName,
+		Namespace: metav1.NamespaceSystem,
+	}
+
+	if err := clientutil.RestartDaemonSet(s.Context, s.DynamicClient, key); err != nil {
+		return errors.Wrap(err, "failed to restart weave-net DaemonSet")
+	}
+
+	s.Logger.Infoln("Waiting for weave-net DaemonSet to roll out...")
+
+	return errors.Wrap(
+		wait.PollImmediate(rolloutCheckInterval, rolloutTimeout, clientutil.DaemonSetRolledOutCondition(s.Context, s.DynamicClient, key)),
+		"failed to wait for weave-net DaemonSet to roll out",
+	)
+}
+
 func genPassword() (string, error) {
 	pi := make([]byte, 32)
 	_, err := rand.Reader.Read(pi)
@@ -70,14 +130,14 @@ func genPassword() (string, error) {
 func weaveSecret(pass string) *corev1.Secret {
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "weave-passwd",
+			Name:      weavePasswordName,
 			Namespace: metav1.NamespaceSystem,
 			Labels: map[string]string{
-				"name": "weave-net",
+				"name": weaveNetName,
 			},
 		},
 		StringData: map[string]string{
-			"weave-passwd": pass,
+			weavePasswordName: pass,
 		},
 	}
 }
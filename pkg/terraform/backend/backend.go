@@ -0,0 +1,141 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backend reads the terraform outputs directly from the state stored
+// in a remote terraform (or OpenTofu) backend, so running
+// `terraform output -json` is not needed.
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// BackendS3 reads the state from the AWS S3 bucket
+	BackendS3 = "s3"
+	// BackendGCS reads the state from the Google Cloud Storage bucket
+	BackendGCS = "gcs"
+	// BackendRemote reads the state from the Terraform Cloud or Terraform
+	// Enterprise workspace
+	BackendRemote = "remote"
+
+	defaultWorkspace = "default"
+	requestTimeout   = 60 * time.Second
+)
+
+// Config describes the backend storing the terraform state. The Config
+// values use the same names as the arguments of the backend block in the
+// terraform configuration.
+type Config struct {
+	// Backend is the type of the backend, one of s3, gcs or remote (cloud
+	// is accepted as an alias for remote)
+	Backend string `json:"backend"`
+	// Workspace is the terraform workspace. Defaults to "default".
+	Workspace string `json:"workspace,omitempty"`
+	// Config are the arguments of the backend
+	Config map[string]string `json:"config"`
+}
+
+// state is the part of the terraform state we are interested in. Outputs
+// have the same format as the `terraform output -json` output.
+type state struct {
+	Version int                        `json:"version"`
+	Outputs map[string]json.RawMessage `json:"outputs"`
+}
+
+// Load reads the backend config from the given YAML or JSON file
+func Load(path string) (*Config, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read the terraform backend config")
+	}
+
+	cfg := &Config{}
+	if err = yaml.UnmarshalStrict(buf, cfg); err != nil {
+		return nil, errors.Wrap(err, "unable to parse the terraform backend config")
+	}
+
+	return cfg, nil
+}
+
+// ReadOutputs reads the state from the backend and returns the outputs in
+// the `terraform output -json` format
+func (c *Config) ReadOutputs(ctx context.Context) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	var (
+		buf []byte
+		err error
+	)
+
+	switch c.Backend {
+	case BackendS3:
+		buf, err = readS3(ctx, c)
+	case BackendGCS:
+		buf, err = readGCS(ctx, c)
+	case BackendRemote, "cloud":
+		buf, err = readRemote(ctx, c)
+	default:
+		return nil, errors.Errorf("unsupported terraform backend %q, supported backends are %q, %q and %q", c.Backend, BackendS3, BackendGCS, BackendRemote)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read terraform state from the %s backend", c.Backend)
+	}
+
+	return outputsFromState(buf)
+}
+
+func (c *Config) workspace() string {
+	if c.Workspace == "" {
+		return defaultWorkspace
+	}
+
+	return c.Workspace
+}
+
+// required returns the value of the backend argument, or an error if it's
+// not set
+func (c *Config) required(name string) (string, error) {
+	val := c.Config[name]
+	if val == "" {
+		return "", errors.Errorf("%q is required for the %s backend", name, c.Backend)
+	}
+
+	return val, nil
+}
+
+func outputsFromState(buf []byte) ([]byte, error) {
+	st := state{}
+	if err := json.Unmarshal(buf, &st); err != nil {
+		return nil, errors.Wrap(err, "failed to parse terraform state")
+	}
+	if st.Version < 4 {
+		return nil, errors.Errorf("unsupported terraform state version %d, at least version 4 is required", st.Version)
+	}
+	if st.Outputs == nil {
+		st.Outputs = map[string]json.RawMessage{}
+	}
+
+	return json.Marshal(st.Outputs)
+}
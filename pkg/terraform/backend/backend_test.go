@@ -0,0 +1,186 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backend
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testState = `{
+	"version": 4,
+	"terraform_version": "1.0.0",
+	"outputs": {
+		"kubeone_api": {"value": {"endpoint": "lb"}, "type": ["object", {"endpoint": "string"}]}
+	},
+	"resources": []
+}`
+
+func TestOutputsFromState(t *testing.T) {
+	tests := []struct {
+		name    string
+		state   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "outputs",
+			state: testState,
+			want:  `{"kubeone_api":{"value":{"endpoint":"lb"},"type":["object",{"endpoint":"string"}]}}`,
+		},
+		{
+			name:  "no outputs",
+			state: `{"version": 4}`,
+			want:  `{}`,
+		},
+		{
+			name:    "old state version",
+			state:   `{"version": 3, "modules": []}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid state",
+			state:   `not json`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := outputsFromState([]byte(tt.state))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("outputsFromState() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("outputsFromState() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadOutputsGCS(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.EscapedPath() != "/storage/v1/b/bucket/o/cluster%2Fprod.tfstate" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, testState)
+	}))
+	defer srv.Close()
+
+	cfg := &Config{
+		Backend:   BackendGCS,
+		Workspace: "prod",
+		Config: map[string]string{
+			"bucket":                  "bucket",
+			"prefix":                  "cluster",
+			"access_token":            "secret",
+			"storage_custom_endpoint": srv.URL,
+		},
+	}
+
+	got, err := cfg.ReadOutputs(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(got), `"endpoint":"lb"`) {
+		t.Errorf("unexpected outputs: %s", got)
+	}
+}
+
+func TestReadOutputsRemote(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/api/v2/organizations/org/workspaces/cluster":
+			fmt.Fprint(w, `{"data": {"id": "ws-123"}}`)
+		case "/api/v2/workspaces/ws-123/current-state-version":
+			fmt.Fprintf(w, `{"data": {"attributes": {"hosted-state-download-url": "%s/state"}}}`, srv.URL)
+		case "/state":
+			fmt.Fprint(w, testState)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := &Config{
+		Backend: BackendRemote,
+		Config: map[string]string{
+			"hostname":     srv.URL,
+			"organization": "org",
+			"workspace":    "cluster",
+			"token":        "secret",
+		},
+	}
+
+	got, err := cfg.ReadOutputs(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(got), `"endpoint":"lb"`) {
+		t.Errorf("unexpected outputs: %s", got)
+	}
+}
+
+func TestReadOutputsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{
+			name:    "unsupported backend",
+			cfg:     Config{Backend: "consul"},
+			wantErr: `unsupported terraform backend "consul"`,
+		},
+		{
+			name:    "missing bucket",
+			cfg:     Config{Backend: BackendS3, Config: map[string]string{"key": "state"}},
+			wantErr: `"bucket" is required for the s3 backend`,
+		},
+		{
+			name:    "missing organization",
+			cfg:     Config{Backend: BackendRemote, Config: map[string]string{"token": "secret"}},
+			wantErr: `"organization" is required for the remote backend`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.cfg.ReadOutputs(context.Background())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
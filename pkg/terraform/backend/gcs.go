@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backend
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	gcsEndpoint       = "https://storage.googleapis.com"
+	gcsAccessTokenEnv = "GOOGLE_OAUTH_ACCESS_TOKEN"
+)
+
+// readGCS downloads the state from the Google Cloud Storage bucket. The
+// access token is taken from the access_token argument, the
+// GOOGLE_OAUTH_ACCESS_TOKEN environment variable or from gcloud.
+func readGCS(ctx context.Context, c *Config) ([]byte, error) {
+	bucket, err := c.required("bucket")
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := gcsAccessToken(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := c.Config["storage_custom_endpoint"]
+	if endpoint == "" {
+		endpoint = gcsEndpoint
+	}
+
+	// the gcs backend stores the state of each workspace as
+	// <prefix>/<workspace>.tfstate
+	object := path.Join(c.Config["prefix"], c.workspace()+".tfstate")
+	u := fmt.Sprintf("%s/storage/v1/b/%s/o/%s?alt=media", strings.TrimSuffix(endpoint, "/"), url.PathEscape(bucket), url.PathEscape(object))
+
+	return httpGet(ctx, u, "Bearer "+token)
+}
+
+func gcsAccessToken(ctx context.Context, c *Config) (string, error) {
+	if token := c.Config["access_token"]; token != "" {
+		return token, nil
+	}
+	if token := os.Getenv(gcsAccessTokenEnv); token != "" {
+		return token, nil
+	}
+
+	out, err := exec.CommandContext(ctx, "gcloud", "auth", "print-access-token").Output()
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to get the google access token, set access_token or the %s environment variable", gcsAccessTokenEnv)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const defaultRemoteHostname = "app.terraform.io"
+
+type remoteStateVersion struct {
+	Data struct {
+		Attributes struct {
+			HostedStateDownloadURL string `json:"hosted-state-download-url"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+type remoteWorkspace struct {
+	Data struct {
+		ID string `json:"id"`
+	} `json:"data"`
+}
+
+type remoteCredentials struct {
+	Credentials map[string]struct {
+		Token string `json:"token"`
+	} `json:"credentials"`
+}
+
+// readRemote downloads the current state of the Terraform Cloud or
+// Terraform Enterprise workspace. The workspace argument is the name of the
+// workspace, defaulting to the workspace of the backend config. The API
+// token is taken from the token argument, the TF_TOKEN_<hostname>
+// environment variable or the terraform CLI credentials file.
+func readRemote(ctx context.Context, c *Config) ([]byte, error) {
+	org, err := c.required("organization")
+	if err != nil {
+		return nil, err
+	}
+
+	workspace := c.Config["workspace"]
+	if workspace == "" {
+		workspace = c.workspace()
+	}
+
+	hostname := c.Config["hostname"]
+	if hostname == "" {
+		hostname = defaultRemoteHostname
+	}
+
+	token, err := remoteToken(c, hostname)
+	if err != nil {
+		return nil, err
+	}
+
+	base := "https://" + hostname
+	if strings.Contains(hostname, "://") {
+		base = strings.TrimSuffix(hostname, "/")
+	}
+	auth := "Bearer " + token
+
+	buf, err := httpGet(ctx, fmt.Sprintf("%s/api/v2/organizations/%s/workspaces/%s", base, url.PathEscape(org), url.PathEscape(workspace)), auth)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get workspace %s/%s", org, workspace)
+	}
+
+	ws := remoteWorkspace{}
+	if err = json.Unmarshal(buf, &ws); err != nil {
+		return nil, errors.Wrap(err, "failed to parse workspace")
+	}
+
+	buf, err = httpGet(ctx, fmt.Sprintf("%s/api/v2/workspaces/%s/current-state-version", base, url.PathEscape(ws.Data.ID)), auth)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get current state version of workspace %s/%s", org, workspace)
+	}
+
+	sv := remoteStateVersion{}
+	if err = json.Unmarshal(buf, &sv); err != nil {
+		return nil, errors.Wrap(err, "failed to parse state version")
+	}
+	if sv.Data.Attributes.HostedStateDownloadURL == "" {
+		return nil, errors.Errorf("workspace %s/%s has no state", org, workspace)
+	}
+
+	return httpGet(ctx, sv.Data.Attributes.HostedStateDownloadURL, auth)
+}
+
+func remoteToken(c *Config, hostname string) (string, error) {
+	if token := c.Config["token"]; token != "" {
+		return token, nil
+	}
+
+	host := hostname
+	if u, err := url.Parse(hostname); err == nil && u.Host != "" {
+		host = u.Host
+	}
+
+	env := "TF_TOKEN_" + strings.NewReplacer(".", "_", "-", "__", ":", "_").Replace(host)
+	if token := os.Getenv(env); token != "" {
+		return token, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err == nil {
+		buf, rerr := ioutil.ReadFile(filepath.Join(home, ".terraform.d", "credentials.tfrc.json"))
+		if rerr == nil {
+			creds := remoteCredentials{}
+			if jerr := json.Unmarshal(buf, &creds); jerr == nil && creds.Credentials[host].Token != "" {
+				return creds.Credentials[host].Token, nil
+			}
+		}
+	}
+
+	return "", errors.Errorf("no API token found for %s, set token or the %s environment variable, or run 'terraform login'", host, env)
+}
+
+func httpGet(ctx context.Context, u, auth string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Authorization", auth)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
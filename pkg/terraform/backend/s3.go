@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backend
+
+import (
+	"context"
+	"io/ioutil"
+	"path"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/pkg/errors"
+)
+
+// readS3 downloads the state from the S3 bucket. Credentials are taken from
+// the standard AWS SDK environment and config files, or from the profile
+// argument.
+func readS3(ctx context.Context, c *Config) ([]byte, error) {
+	bucket, err := c.required("bucket")
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := c.required("key")
+	if err != nil {
+		return nil, err
+	}
+
+	if ws := c.workspace(); ws != defaultWorkspace {
+		prefix := c.Config["workspace_key_prefix"]
+		if prefix == "" {
+			prefix = "env:"
+		}
+		key = path.Join(prefix, ws, key)
+	}
+
+	awsConfig := aws.Config{}
+	if region := c.Config["region"]; region != "" {
+		awsConfig.Region = aws.String(region)
+	}
+	if endpoint := c.Config["endpoint"]; endpoint != "" {
+		awsConfig.Endpoint = aws.String(endpoint)
+		awsConfig.S3ForcePathStyle = aws.Bool(c.Config["force_path_style"] == "true")
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            awsConfig,
+		Profile:           c.Config["profile"],
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create aws session")
+	}
+
+	out, err := s3.New(sess).GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get s3://%s/%s", bucket, key)
+	}
+	defer out.Body.Close()
+
+	return ioutil.ReadAll(out.Body)
+}
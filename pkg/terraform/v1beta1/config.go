@@ -19,6 +19,7 @@ package v1beta1
 import (
 	"encoding/json"
 	"sort"
+	"strings"
 
 	"github.com/imdario/mergo"
 	"github.com/pkg/errors"
@@ -29,12 +30,29 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
+const (
+	// SchemaV1 is the original terraform output schema, used if the
+	// kubeone_output_version output is not set
+	SchemaV1 = "v1"
+	// SchemaV2 adds multiple API endpoints, per-host settings (bastion,
+	// labels and taints) and overrides of the dynamic workers
+	SchemaV2 = "v2"
+)
+
 // Config represents configuration in the terraform output format
 type Config struct {
+	KubeOneOutputVersion struct {
+		Value string `json:"value"`
+	} `json:"kubeone_output_version"`
+
 	KubeOneAPI struct {
 		Value struct {
 			Endpoint                  string   `json:"endpoint"`
 			APIServerAlternativeNames []string `json:"apiserver_alternative_names"`
+			// Endpoints (v2) are the load balancers in front of the API
+			// server. The first one is used as the API endpoint, the other
+			// ones are added to the alternative names.
+			Endpoints []string `json:"endpoints"`
 		} `json:"value"`
 	} `json:"kubeone_api"`
 
@@ -52,17 +70,32 @@ type Config struct {
 		Value map[string]hostsSpec `json:"value"`
 	} `json:"kubeone_static_workers"`
 
+	// KubeOneWorkersOverrides (v2) override the settings of the dynamic
+	// workers defined in the manifest or in the kubeone_workers output
+	KubeOneWorkersOverrides struct {
+		Value map[string]workersetOverride `json:"value"`
+	} `json:"kubeone_workers_overrides"`
+
 	Proxy struct {
 		Value kubeonev1beta1.ProxyConfig `json:"value"`
 	} `json:"proxy"`
 }
 
+type workersetOverride struct {
+	Replicas           *int              `json:"replicas"`
+	Labels             map[string]string `json:"labels"`
+	Annotations        map[string]string `json:"annotations"`
+	MachineAnnotations map[string]string `json:"machine_annotations"`
+	Taints             []corev1.Taint    `json:"taints"`
+}
+
 type controlPlane struct {
 	ClusterName   string  `json:"cluster_name"`
 	CloudProvider *string `json:"cloud_provider"`
 	LeaderIP      string  `json:"leader_ip"`
 	Untaint       bool    `json:"untaint"`
 	NetworkID     string  `json:"network_id"`
+	VPCUUID       string  `json:"vpc_uuid"`
 	hostsSpec
 }
 
@@ -77,6 +110,34 @@ type hostsSpec struct {
 	Bastion           string   `json:"bastion"`
 	BastionPort       int      `json:"bastion_port"`
 	BastionUser       string   `json:"bastion_user"`
+
+	// v2 fields, applied to all hosts in the group unless overridden in Hosts
+	BastionHosts []bastionHost     `json:"bastion_hosts"`
+	Labels       map[string]string `json:"labels"`
+	Taints       []corev1.Taint    `json:"taints"`
+	// Hosts (v2) describe each host separately, instead of the address and
+	// hostname lists
+	Hosts []hostSpec `json:"hosts"`
+}
+
+type hostSpec struct {
+	PublicAddress  string            `json:"public_address"`
+	PrivateAddress string            `json:"private_address"`
+	Hostname       string            `json:"hostname"`
+	Bastion        string            `json:"bastion"`
+	BastionPort    int               `json:"bastion_port"`
+	BastionUser    string            `json:"bastion_user"`
+	BastionHosts   []bastionHost     `json:"bastion_hosts"`
+	Labels         map[string]string `json:"labels"`
+	Taints         []corev1.Taint    `json:"taints"`
+}
+
+type bastionHost struct {
+	Host              string `json:"host"`
+	Port              int    `json:"port"`
+	User              string `json:"user"`
+	SSHPrivateKeyFile string `json:"ssh_private_key_file"`
+	SSHAgentSocket    string `json:"ssh_agent_socket"`
 }
 
 type hostConfigsOpts func([]kubeonev1beta1.HostConfig)
@@ -117,6 +178,18 @@ func idIncrementerHostConfigsOpts(currentHostID int) hostConfigsOpts {
 func (hs *hostsSpec) toHostConfigs(opts ...hostConfigsOpts) []kubeonev1beta1.HostConfig {
 	hosts := []kubeonev1beta1.HostConfig{}
 
+	if len(hs.Hosts) > 0 {
+		for i := range hs.Hosts {
+			hosts = append(hosts, hs.Hosts[i].toHostConfig(hs))
+		}
+
+		for _, mutatorFn := range opts {
+			mutatorFn(hosts)
+		}
+
+		return hosts
+	}
+
 	for i, publicIP := range hs.PublicAddress {
 		privateIP := publicIP
 		if i < len(hs.PrivateAddress) {
@@ -155,10 +228,154 @@ type cloudProviderFlags struct {
 	value interface{}
 }
 
+// toHostConfig returns the host config, using the settings of the group
+// for the settings not set for the host
+func (h *hostSpec) toHostConfig(hs *hostsSpec) kubeonev1beta1.HostConfig {
+	privateIP := h.PrivateAddress
+	if privateIP == "" {
+		privateIP = h.PublicAddress
+	}
+
+	host := newHostConfig(h.PublicAddress, privateIP, h.Hostname, hs)
+
+	if h.Bastion != "" {
+		host.Bastion = h.Bastion
+		host.BastionPort = h.BastionPort
+		host.BastionUser = h.BastionUser
+		host.BastionHosts = nil
+	}
+	if h.BastionHosts != nil {
+		host.Bastion = ""
+		host.BastionPort = 0
+		host.BastionUser = ""
+		host.BastionHosts = toBastionHosts(h.BastionHosts)
+	}
+	if h.Labels != nil {
+		host.Labels = h.Labels
+	}
+	if h.Taints != nil {
+		host.Taints = h.Taints
+	}
+
+	return host
+}
+
+func toBastionHosts(bastions []bastionHost) []kubeonev1beta1.BastionHost {
+	if len(bastions) == 0 {
+		return nil
+	}
+
+	hosts := []kubeonev1beta1.BastionHost{}
+	for _, b := range bastions {
+		hosts = append(hosts, kubeonev1beta1.BastionHost{
+			Host:              b.Host,
+			Port:              b.Port,
+			User:              b.User,
+			SSHPrivateKeyFile: b.SSHPrivateKeyFile,
+			SSHAgentSocket:    b.SSHAgentSocket,
+		})
+	}
+
+	return hosts
+}
+
+// v2Fields returns the names of the v2 fields set in the hosts group
+func (hs *hostsSpec) v2Fields(prefix string) []string {
+	fields := []string{}
+	if hs.BastionHosts != nil {
+		fields = append(fields, prefix+".bastion_hosts")
+	}
+	if hs.Labels != nil {
+		fields = append(fields, prefix+".labels")
+	}
+	if hs.Taints != nil {
+		fields = append(fields, prefix+".taints")
+	}
+	if hs.Hosts != nil {
+		fields = append(fields, prefix+".hosts")
+	}
+
+	return fields
+}
+
 // NewConfigFromJSON creates a new config object from json
 func NewConfigFromJSON(j []byte) (c *Config, err error) {
 	c = &Config{}
-	return c, json.Unmarshal(j, c)
+	if err = json.Unmarshal(j, c); err != nil {
+		return c, err
+	}
+
+	return c, c.validateSchema()
+}
+
+// validateSchema verifies the output uses only the fields of the declared
+// schema version
+func (c *Config) validateSchema() error {
+	version := c.KubeOneOutputVersion.Value
+	switch version {
+	case "", SchemaV1:
+	case SchemaV2:
+		return c.validateV2()
+	default:
+		return errors.Errorf("unsupported terraform output schema version %q (kubeone_output_version), supported versions are %q and %q", version, SchemaV1, SchemaV2)
+	}
+
+	fields := []string{}
+	if c.KubeOneAPI.Value.Endpoints != nil {
+		fields = append(fields, "kubeone_api.endpoints")
+	}
+	fields = append(fields, c.KubeOneHosts.Value.ControlPlane.v2Fields("kubeone_hosts.control_plane")...)
+
+	groups := []string{}
+	for name := range c.KubeOneStaticWorkers.Value {
+		groups = append(groups, name)
+	}
+	sort.Strings(groups)
+	for _, name := range groups {
+		group := c.KubeOneStaticWorkers.Value[name]
+		fields = append(fields, group.v2Fields("kubeone_static_workers."+name)...)
+	}
+
+	if c.KubeOneWorkersOverrides.Value != nil {
+		fields = append(fields, "kubeone_workers_overrides")
+	}
+
+	if len(fields) > 0 {
+		return errors.Errorf("terraform output uses %s, which require the %q schema; set the kubeone_output_version output to %q", strings.Join(fields, ", "), SchemaV2, SchemaV2)
+	}
+
+	return nil
+}
+
+// validateV2 verifies the v2 hosts groups don't mix the hosts with the
+// address lists
+func (c *Config) validateV2() error {
+	groups := map[string]hostsSpec{
+		"kubeone_hosts.control_plane": c.KubeOneHosts.Value.ControlPlane.hostsSpec,
+	}
+	for name, group := range c.KubeOneStaticWorkers.Value {
+		groups["kubeone_static_workers."+name] = group
+	}
+
+	names := []string{}
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		group := groups[name]
+		if len(group.Hosts) > 0 && (len(group.PublicAddress) > 0 || len(group.PrivateAddress) > 0 || len(group.Hostnames) > 0) {
+			return errors.Errorf("%s: hosts can't be used along with public_address, private_address and hostnames", name)
+		}
+		for i, h := range group.Hosts {
+			if h.PublicAddress == "" && h.PrivateAddress == "" {
+				return errors.Errorf("%s.hosts[%d]: public_address or private_address is required", name, i)
+			}
+		}
+	}
+
+	return nil
 }
 
 // Apply adds the terraform configuration options to the given
@@ -172,6 +389,15 @@ func (c *Config) Apply(cluster *kubeonev1beta1.KubeOneCluster) error {
 		cluster.APIEndpoint.AlternativeNames = c.KubeOneAPI.Value.APIServerAlternativeNames
 	}
 
+	if endpoints := c.KubeOneAPI.Value.Endpoints; len(endpoints) > 0 {
+		cluster.APIEndpoint.Host = endpoints[0]
+		for _, endpoint := range endpoints[1:] {
+			if !containsString(cluster.APIEndpoint.AlternativeNames, endpoint) {
+				cluster.APIEndpoint.AlternativeNames = append(cluster.APIEndpoint.AlternativeNames, endpoint)
+			}
+		}
+	}
+
 	cp := c.KubeOneHosts.Value.ControlPlane
 
 	if cp.CloudProvider != nil {
@@ -219,6 +445,10 @@ func (c *Config) Apply(cluster *kubeonev1beta1.KubeOneCluster) error {
 		cluster.CloudProvider.Hetzner.NetworkID = cp.NetworkID
 	}
 
+	if len(cp.VPCUUID) > 0 && cluster.CloudProvider.DigitalOcean != nil {
+		cluster.CloudProvider.DigitalOcean.VPCUUID = cp.VPCUUID
+	}
+
 	// Walk through all configued workersets from terraform and apply their config
 	// by either merging it into an existing workerSet or creating a new one
 	for workersetName, workersetValue := range c.KubeOneWorkers.Value {
@@ -272,9 +502,71 @@ func (c *Config) Apply(cluster *kubeonev1beta1.KubeOneCluster) error {
 		}
 	}
 
+	return c.applyWorkersOverrides(cluster)
+}
+
+// applyWorkersOverrides applies the overrides to the dynamic workers. All
+// overridden workersets must exist.
+func (c *Config) applyWorkersOverrides(cluster *kubeonev1beta1.KubeOneCluster) error {
+	names := []string{}
+	for name := range c.KubeOneWorkersOverrides.Value {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		override := c.KubeOneWorkersOverrides.Value[name]
+
+		var workerset *kubeonev1beta1.DynamicWorkerConfig
+		for idx := range cluster.DynamicWorkers {
+			if cluster.DynamicWorkers[idx].Name == name {
+				workerset = &cluster.DynamicWorkers[idx]
+				break
+			}
+		}
+		if workerset == nil {
+			return errors.Errorf("kubeone_workers_overrides: workerset %q is not defined in the manifest nor in the kubeone_workers output", name)
+		}
+
+		if override.Replicas != nil {
+			replicas := *override.Replicas
+			workerset.Replicas = &replicas
+		}
+		if override.Taints != nil {
+			workerset.Config.Taints = override.Taints
+		}
+		workerset.Config.Labels = mergeMaps(workerset.Config.Labels, override.Labels)
+		workerset.Config.Annotations = mergeMaps(workerset.Config.Annotations, override.Annotations)
+		workerset.Config.MachineAnnotations = mergeMaps(workerset.Config.MachineAnnotations, override.MachineAnnotations)
+	}
+
 	return nil
 }
 
+func mergeMaps(dst, src map[string]string) map[string]string {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = map[string]string{}
+	}
+	for k, v := range src {
+		dst[k] = v
+	}
+
+	return dst
+}
+
+func containsString(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+
+	return false
+}
+
 func newHostConfig(publicIP, privateIP, hostname string, hs *hostsSpec) kubeonev1beta1.HostConfig {
 	return kubeonev1beta1.HostConfig{
 		Bastion:           hs.Bastion,
@@ -287,6 +579,9 @@ func newHostConfig(publicIP, privateIP, hostname string, hs *hostsSpec) kubeonev
 		SSHPrivateKeyFile: hs.SSHPrivateKeyFile,
 		SSHUsername:       hs.SSHUser,
 		SSHPort:           hs.SSHPort,
+		BastionHosts:      toBastionHosts(hs.BastionHosts),
+		Labels:            hs.Labels,
+		Taints:            hs.Taints,
 	}
 }
 
@@ -313,6 +608,10 @@ func (c *Config) updateAWSWorkerset(existingWorkerSet *kubeonev1beta1.DynamicWor
 		{key: "subnetId", value: awsCloudConfig.SubnetID},
 		{key: "tags", value: awsCloudConfig.Tags},
 		{key: "vpcId", value: awsCloudConfig.VPCID},
+		{key: "instanceTypes", value: awsCloudConfig.InstanceTypes},
+		{key: "ebsVolumeKmsKeyId", value: awsCloudConfig.EBSVolumeKMSKeyID},
+		{key: "spotInstanceConfig", value: awsCloudConfig.SpotInstanceConfig},
+		{key: "instanceMetadataOptions", value: awsCloudConfig.InstanceMetadataOptions},
 	}
 
 	for _, flag := range flags {
@@ -347,6 +646,10 @@ func (c *Config) updateAzureWorkerset(existingWorkerSet *kubeonev1beta1.DynamicW
 		{key: "imageID", value: azureCloudConfig.ImageID},
 		{key: "osDiskSize", value: azureCloudConfig.OSDiskSize},
 		{key: "dataDiskSize", value: azureCloudConfig.DataDiskSize},
+		{key: "enableAcceleratedNetworking", value: azureCloudConfig.EnableAcceleratedNetworking},
+		{key: "priority", value: azureCloudConfig.Priority},
+		{key: "evictionPolicy", value: azureCloudConfig.EvictionPolicy},
+		{key: "maxPrice", value: azureCloudConfig.MaxPrice},
 	}
 
 	for _, flag := range flags {
@@ -379,6 +682,9 @@ func (c *Config) updateGCEWorkerset(existingWorkerSet *kubeonev1beta1.DynamicWor
 		{key: "multizone", value: gceCloudConfig.MultiZone},
 		{key: "regional", value: gceCloudConfig.Regional},
 		{key: "customImage", value: gceCloudConfig.CustomImage},
+		{key: "shieldedVM", value: gceCloudConfig.ShieldedVM},
+		{key: "serviceAccount", value: gceCloudConfig.ServiceAccount},
+		{key: "scopes", value: gceCloudConfig.Scopes},
 	}
 
 	for _, flag := range flags {
@@ -405,6 +711,7 @@ func (c *Config) updateDigitalOceanWorkerset(existingWorkerSet *kubeonev1beta1.D
 		{key: "private_networking", value: doCloudConfig.PrivateNetworking},
 		{key: "monitoring", value: doCloudConfig.Monitoring},
 		{key: "tags", value: doCloudConfig.Tags},
+		{key: "vpc_uuid", value: doCloudConfig.VPCUUID},
 	}
 
 	for _, flag := range flags {
@@ -430,6 +737,7 @@ func (c *Config) updateHetznerWorkerset(existingWorkerSet *kubeonev1beta1.Dynami
 		{key: "image", value: hetznerConfig.Image},
 		{key: "networks", value: hetznerConfig.Networks},
 		{key: "labels", value: hetznerConfig.Labels},
+		{key: "placementGroup", value: hetznerConfig.PlacementGroup},
 	}
 
 	for _, flag := range flags {
@@ -460,6 +768,9 @@ func (c *Config) updateOpenStackWorkerset(existingWorkerSet *kubeonev1beta1.Dyna
 		{key: "nodeVolumeAttachLimit", value: openstackConfig.NodeVolumeAttachLimit},
 		{key: "tags", value: openstackConfig.Tags},
 		{key: "trustDevicePath", value: openstackConfig.TrustDevicePath},
+		{key: "networks", value: openstackConfig.Networks},
+		{key: "serverGroup", value: openstackConfig.ServerGroup},
+		{key: "configDrive", value: openstackConfig.ConfigDrive},
 	}
 
 	for _, flag := range flags {
@@ -515,6 +826,8 @@ func (c *Config) updateVSphereWorkerset(existingWorkerSet *kubeonev1beta1.Dynami
 		{key: "memoryMB", value: vsphereConfig.MemoryMB},
 		{key: "templateVMName", value: vsphereConfig.TemplateVMName},
 		{key: "vmNetName", value: vsphereConfig.VMNetName},
+		{key: "vmAntiAffinity", value: vsphereConfig.VMAntiAffinity},
+		{key: "tags", value: vsphereConfig.Tags},
 	}
 
 	for _, flag := range flags {
@@ -562,11 +875,31 @@ func setWorkersetFlag(w *kubeonev1beta1.DynamicWorkerConfig, name string, value
 		if s == nil {
 			return nil
 		}
+	case *float64:
+		if s == nil {
+			return nil
+		}
+	case *machinecontroller.AWSSpotInstanceConfig:
+		if s == nil {
+			return nil
+		}
+	case *machinecontroller.AWSInstanceMetadataOptions:
+		if s == nil {
+			return nil
+		}
+	case *machinecontroller.GCEShieldedVMConfig:
+		if s == nil {
+			return nil
+		}
 	case machinecontroller.AzureImagePlan:
 	case *machinecontroller.AzureImagePlan:
 		if s == nil {
 			return nil
 		}
+	case []machinecontroller.VSphereTag:
+		if len(s) == 0 {
+			return nil
+		}
 	default:
 		return errors.New("unsupported type")
 	}
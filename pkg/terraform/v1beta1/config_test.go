@@ -0,0 +1,158 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"strings"
+	"testing"
+
+	kubeonev1beta1 "k8c.io/kubeone/pkg/apis/kubeone/v1beta1"
+)
+
+func TestNewConfigFromJSONSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		wantErr string
+	}{
+		{
+			name:   "v1 without version",
+			output: `{"kubeone_api": {"value": {"endpoint": "lb"}}}`,
+		},
+		{
+			name:   "v2 fields with v2 version",
+			output: `{"kubeone_output_version": {"value": "v2"}, "kubeone_api": {"value": {"endpoints": ["lb1", "lb2"]}}}`,
+		},
+		{
+			name:    "v2 fields without version",
+			output:  `{"kubeone_api": {"value": {"endpoints": ["lb1"]}}, "kubeone_hosts": {"value": {"control_plane": {"labels": {"a": "b"}}}}}`,
+			wantErr: `kubeone_api.endpoints, kubeone_hosts.control_plane.labels, which require the "v2" schema`,
+		},
+		{
+			name:    "unsupported version",
+			output:  `{"kubeone_output_version": {"value": "v3"}}`,
+			wantErr: `unsupported terraform output schema version "v3"`,
+		},
+		{
+			name:    "hosts mixed with address lists",
+			output:  `{"kubeone_output_version": {"value": "v2"}, "kubeone_static_workers": {"value": {"workers": {"public_address": ["1.1.1.1"], "hosts": [{"public_address": "2.2.2.2"}]}}}}`,
+			wantErr: "kubeone_static_workers.workers: hosts can't be used along with",
+		},
+		{
+			name:    "host without address",
+			output:  `{"kubeone_output_version": {"value": "v2"}, "kubeone_hosts": {"value": {"control_plane": {"hosts": [{"hostname": "cp"}]}}}}`,
+			wantErr: "kubeone_hosts.control_plane.hosts[0]: public_address or private_address is required",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewConfigFromJSON([]byte(tt.output))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestApplyV2(t *testing.T) {
+	output := `{
+		"kubeone_output_version": {"value": "v2"},
+		"kubeone_api": {"value": {"endpoints": ["lb1", "lb2"], "apiserver_alternative_names": ["lb2", "name"]}},
+		"kubeone_hosts": {"value": {"control_plane": {
+			"cluster_name": "test",
+			"ssh_user": "ubuntu",
+			"bastion": "bastion",
+			"labels": {"group": "cp"},
+			"untaint": true,
+			"hosts": [
+				{"public_address": "1.1.1.1", "private_address": "10.0.0.1", "hostname": "cp-1"},
+				{"private_address": "10.0.0.2", "labels": {"own": "label"}, "bastion_hosts": [{"host": "jump1"}, {"host": "jump2", "port": 2222}]}
+			]
+		}}},
+		"kubeone_workers": {"value": {"pool1": {"replicas": 1}}},
+		"kubeone_workers_overrides": {"value": {"pool1": {"replicas": 3, "labels": {"a": "b"}}}}
+	}`
+
+	c, err := NewConfigFromJSON([]byte(output))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cluster := &kubeonev1beta1.KubeOneCluster{}
+	if err = c.Apply(cluster); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cluster.APIEndpoint.Host != "lb1" {
+		t.Errorf("expected API endpoint host lb1, got %q", cluster.APIEndpoint.Host)
+	}
+	if got := strings.Join(cluster.APIEndpoint.AlternativeNames, ","); got != "lb2,name" {
+		t.Errorf("expected alternative names lb2,name, got %q", got)
+	}
+
+	hosts := cluster.ControlPlane.Hosts
+	if len(hosts) != 2 {
+		t.Fatalf("expected 2 control plane hosts, got %d", len(hosts))
+	}
+	if hosts[0].Labels["group"] != "cp" || hosts[0].Bastion != "bastion" || hosts[0].Hostname != "cp-1" {
+		t.Errorf("expected first host to use group settings, got %+v", hosts[0])
+	}
+	if hosts[1].PrivateAddress != "10.0.0.2" || hosts[1].ID != 1 {
+		t.Errorf("expected second host to have private address 10.0.0.2 and ID 1, got %+v", hosts[1])
+	}
+	if hosts[1].Labels["own"] != "label" || hosts[1].Labels["group"] != "" {
+		t.Errorf("expected second host to override labels, got %v", hosts[1].Labels)
+	}
+	if hosts[1].Bastion != "" || len(hosts[1].BastionHosts) != 2 || hosts[1].BastionHosts[1].Port != 2222 {
+		t.Errorf("expected second host to use bastion chain, got %+v", hosts[1])
+	}
+	for _, h := range hosts {
+		if h.Taints == nil || len(h.Taints) != 0 {
+			t.Errorf("expected control plane host %q to be untainted, got %v", h.PrivateAddress, h.Taints)
+		}
+	}
+
+	if len(cluster.DynamicWorkers) != 1 {
+		t.Fatalf("expected 1 workerset, got %d", len(cluster.DynamicWorkers))
+	}
+	ws := cluster.DynamicWorkers[0]
+	if ws.Replicas == nil || *ws.Replicas != 3 || ws.Config.Labels["a"] != "b" {
+		t.Errorf("expected workerset overrides to be applied, got %+v", ws)
+	}
+}
+
+func TestApplyWorkersOverridesUnknownWorkerset(t *testing.T) {
+	output := `{"kubeone_output_version": {"value": "v2"}, "kubeone_workers_overrides": {"value": {"missing": {"replicas": 1}}}}`
+
+	c, err := NewConfigFromJSON([]byte(output))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = c.Apply(&kubeonev1beta1.KubeOneCluster{})
+	if err == nil || !strings.Contains(err.Error(), `workerset "missing" is not defined`) {
+		t.Fatalf("expected unknown workerset error, got %v", err)
+	}
+}
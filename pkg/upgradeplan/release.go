@@ -0,0 +1,110 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgradeplan
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultReleaseURL is the location of the Kubernetes release markers,
+	// such as stable-1.21.txt
+	DefaultReleaseURL = "https://dl.k8s.io/release"
+)
+
+// LatestPatch returns the latest patch release of the minor version v, as
+// published in the stable-X.Y.txt release marker under the releaseURL. The
+// releaseURL can be the local directory, or the file:// URL, mirroring the
+// release markers.
+func LatestPatch(ctx context.Context, releaseURL string, v *semver.Version) (*semver.Version, error) {
+	markerURL := fmt.Sprintf("%s/stable-%d.%d.txt", strings.TrimSuffix(releaseURL, "/"), v.Major(), v.Minor())
+
+	buf, err := readReleaseMarker(ctx, markerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := semver.NewVersion(strings.TrimSpace(string(buf)))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse release marker %q", markerURL)
+	}
+
+	if latest.Major() != v.Major() || latest.Minor() != v.Minor() {
+		return nil, errors.Errorf("release marker %q points to unexpected version %s", markerURL, versionString(latest, false))
+	}
+
+	return latest, nil
+}
+
+func readReleaseMarker(ctx context.Context, markerURL string) ([]byte, error) {
+	if !strings.HasPrefix(markerURL, "http://") && !strings.HasPrefix(markerURL, "https://") {
+		buf, err := ioutil.ReadFile(strings.TrimPrefix(markerURL, "file://"))
+
+		return buf, errors.Wrapf(err, "failed to read release marker %q", markerURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, markerURL, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build release marker request")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch release marker %q", markerURL)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to fetch release marker %q: %s", markerURL, resp.Status)
+	}
+
+	buf, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+	return buf, errors.Wrapf(err, "failed to read release marker %q", markerURL)
+}
+
+// ResolveIntermediate replaces targets of the intermediate hops with the
+// latest patch release of the given minor version. Resolved hops are no
+// longer marked as intermediate, as they point to the concrete version.
+func ResolveIntermediate(ctx context.Context, releaseURL string, hops []Hop) error {
+	for i := range hops {
+		if !hops[i].Intermediate {
+			continue
+		}
+
+		latest, err := LatestPatch(ctx, releaseURL, hops[i].To)
+		if err != nil {
+			return err
+		}
+
+		hops[i].To = latest
+		hops[i].Intermediate = false
+		if i+1 < len(hops) {
+			hops[i+1].From = latest
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgradeplan
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+func TestResolveIntermediate(t *testing.T) {
+	tests := []struct {
+		name    string
+		markers map[string]string
+		current string
+		target  string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "minor upgrade",
+			current: "1.21.5",
+			target:  "1.22.4",
+			want:    []string{"v1.21.5 -> v1.22.4"},
+		},
+		{
+			name: "multi-minor upgrade",
+			markers: map[string]string{
+				"/stable-1.21.txt": "v1.21.14\n",
+				"/stable-1.22.txt": "v1.22.17\n",
+			},
+			current: "1.20.11",
+			target:  "1.23.1",
+			want: []string{
+				"v1.20.11 -> v1.21.14",
+				"v1.21.14 -> v1.22.17",
+				"v1.22.17 -> v1.23.1",
+			},
+		},
+		{
+			name:    "missing release marker",
+			current: "1.20.11",
+			target:  "1.22.4",
+			wantErr: true,
+		},
+		{
+			name: "release marker of another minor",
+			markers: map[string]string{
+				"/stable-1.21.txt": "v1.22.0",
+			},
+			current: "1.20.11",
+			target:  "1.22.4",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				marker, ok := tt.markers[r.URL.Path]
+				if !ok {
+					http.NotFound(w, r)
+					return
+				}
+				_, _ = w.Write([]byte(marker))
+			}))
+			defer srv.Close()
+
+			hops, err := Path(semver.MustParse(tt.current), semver.MustParse(tt.target))
+			if err != nil {
+				t.Fatalf("Path() error = %v", err)
+			}
+
+			err = ResolveIntermediate(context.Background(), srv.URL, hops)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveIntermediate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var got []string
+			for _, hop := range hops {
+				got = append(got, hop.String())
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ResolveIntermediate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLatestPatchLocalMirror(t *testing.T) {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "stable-1.21.txt"), []byte("v1.21.14\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, releaseURL := range []string{dir, "file://" + dir} {
+		got, err := LatestPatch(context.Background(), releaseURL, semver.MustParse("1.21.0"))
+		if err != nil {
+			t.Fatalf("LatestPatch(%q) error = %v", releaseURL, err)
+		}
+		if got.String() != "1.21.14" {
+			t.Errorf("LatestPatch(%q) = %s, want 1.21.14", releaseURL, got)
+		}
+	}
+
+	if _, err := LatestPatch(context.Background(), dir, semver.MustParse("1.22.0")); err == nil {
+		t.Error("LatestPatch() of the missing release marker succeeded")
+	}
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package upgradeplan calculates the sequence of upgrades needed to reach the
+// desired Kubernetes version, following the kubeadm and Kubernetes version
+// skew policies.
+package upgradeplan
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+const (
+	// maxKubeletSkew is the maximum number of minor versions kubelet can be
+	// older than kube-apiserver
+	// https://kubernetes.io/docs/setup/release/version-skew-policy/#kubelet
+	maxKubeletSkew = 2
+)
+
+// Hop is a single upgrade step, that can be done by running `kubeone apply` once.
+// kubeadm supports upgrading only one minor version at the time.
+type Hop struct {
+	From *semver.Version
+	To   *semver.Version
+
+	// Intermediate is true if the hop targets the minor release between the
+	// current and the requested version. Intermediate hops should be done to
+	// the latest patch release of the given minor.
+	Intermediate bool
+}
+
+func (h Hop) String() string {
+	return fmt.Sprintf("%s -> %s", versionString(h.From, false), versionString(h.To, h.Intermediate))
+}
+
+// ToString returns the target version of the hop, formatted for the humans
+func (h Hop) ToString() string {
+	return versionString(h.To, h.Intermediate)
+}
+
+// Node is the node which is being upgraded
+type Node struct {
+	Name         string
+	ControlPlane bool
+	Version      *semver.Version
+}
+
+// Path returns the list of hops needed to upgrade from the current to the
+// target version
+func Path(current, target *semver.Version) ([]Hop, error) {
+	if current == nil || target == nil {
+		return nil, errors.New("current and target versions must be known")
+	}
+	if current.Major() != target.Major() {
+		return nil, errors.New("upgrading between major versions is not supported")
+	}
+	if target.LessThan(current) {
+		return nil, errors.Errorf("downgrading from %s to %s is not supported", versionString(current, false), versionString(target, false))
+	}
+	if target.Equal(current) {
+		return nil, nil
+	}
+
+	hops := []Hop{}
+	from := current
+	for minor := current.Minor() + 1; minor < target.Minor(); minor++ {
+		to := semver.MustParse(fmt.Sprintf("%d.%d.0", current.Major(), minor))
+		hops = append(hops, Hop{
+			From:         from,
+			To:           to,
+			Intermediate: true,
+		})
+		from = to
+	}
+
+	return append(hops, Hop{From: from, To: target}), nil
+}
+
+// ValidateSkew validates versions of the nodes against the target version
+// and the version skew policy, and returns the lowest version of the control
+// plane nodes
+func ValidateSkew(nodes []Node, target *semver.Version) (*semver.Version, error) {
+	var (
+		errs            []error
+		lowestCP        *semver.Version
+		highestCP       *semver.Version
+		hasControlPlane bool
+	)
+
+	for _, node := range nodes {
+		if node.Version == nil {
+			errs = append(errs, errors.Errorf("node %q: kubelet version is unknown, node is not provisioned", node.Name))
+			continue
+		}
+		if node.Version.GreaterThan(target) {
+			errs = append(errs, errors.Errorf("node %q: kubelet version %s is newer than the requested version %s", node.Name, versionString(node.Version, false), versionString(target, false)))
+		}
+		if !node.ControlPlane {
+			continue
+		}
+
+		hasControlPlane = true
+		if lowestCP == nil || node.Version.LessThan(lowestCP) {
+			lowestCP = node.Version
+		}
+		if highestCP == nil || node.Version.GreaterThan(highestCP) {
+			highestCP = node.Version
+		}
+	}
+
+	if !hasControlPlane && len(errs) == 0 {
+		errs = append(errs, errors.New("no provisioned control plane nodes found"))
+	}
+
+	if lowestCP != nil && highestCP != nil && lowestCP.Minor() != highestCP.Minor() {
+		errs = append(errs, errors.Errorf("control plane nodes are running different minor versions (%s and %s), run 'kubeone apply' to reconcile the control plane first", versionString(lowestCP, false), versionString(highestCP, false)))
+	}
+
+	if lowestCP != nil {
+		for _, node := range nodes {
+			if node.ControlPlane || node.Version == nil {
+				continue
+			}
+			if node.Version.Minor() > lowestCP.Minor() {
+				errs = append(errs, errors.Errorf("node %q: kubelet version %s is newer than the control plane version %s", node.Name, versionString(node.Version, false), versionString(lowestCP, false)))
+			} else if lowestCP.Minor()-node.Version.Minor() > maxKubeletSkew {
+				errs = append(errs, errors.Errorf("node %q: kubelet version %s is more than %d minor versions older than the control plane version %s", node.Name, versionString(node.Version, false), maxKubeletSkew, versionString(lowestCP, false)))
+			}
+		}
+	}
+
+	return lowestCP, utilerrors.NewAggregate(errs)
+}
+
+func versionString(v *semver.Version, minorOnly bool) string {
+	if v == nil {
+		return "unknown"
+	}
+	if minorOnly {
+		return fmt.Sprintf("v%d.%d.x", v.Major(), v.Minor())
+	}
+
+	return "v" + v.String()
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2021 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgradeplan
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+func TestPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		target  string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "same version",
+			current: "1.22.2",
+			target:  "1.22.2",
+			want:    nil,
+		},
+		{
+			name:    "patch upgrade",
+			current: "1.22.2",
+			target:  "1.22.4",
+			want:    []string{"v1.22.2 -> v1.22.4"},
+		},
+		{
+			name:    "minor upgrade",
+			current: "1.21.5",
+			target:  "1.22.4",
+			want:    []string{"v1.21.5 -> v1.22.4"},
+		},
+		{
+			name:    "multi-minor upgrade",
+			current: "1.20.11",
+			target:  "1.23.1",
+			want: []string{
+				"v1.20.11 -> v1.21.x",
+				"v1.21.0 -> v1.22.x",
+				"v1.22.0 -> v1.23.1",
+			},
+		},
+		{
+			name:    "downgrade",
+			current: "1.22.2",
+			target:  "1.21.5",
+			wantErr: true,
+		},
+		{
+			name:    "major upgrade",
+			current: "1.22.2",
+			target:  "2.0.0",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			hops, err := Path(semver.MustParse(tt.current), semver.MustParse(tt.target))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Path() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var got []string
+			for _, hop := range hops {
+				got = append(got, hop.String())
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Path() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSkew(t *testing.T) {
+	tests := []struct {
+		name       string
+		nodes      []Node
+		target     string
+		wantLowest string
+		wantErr    bool
+	}{
+		{
+			name: "valid cluster",
+			nodes: []Node{
+				{Name: "cp-1", ControlPlane: true, Version: semver.MustParse("1.21.5")},
+				{Name: "cp-2", ControlPlane: true, Version: semver.MustParse("1.21.4")},
+				{Name: "worker-1", Version: semver.MustParse("1.20.11")},
+			},
+			target:     "1.23.1",
+			wantLowest: "1.21.4",
+		},
+		{
+			name: "control plane nodes with different minor versions",
+			nodes: []Node{
+				{Name: "cp-1", ControlPlane: true, Version: semver.MustParse("1.22.2")},
+				{Name: "cp-2", ControlPlane: true, Version: semver.MustParse("1.21.5")},
+			},
+			target:     "1.23.1",
+			wantLowest: "1.21.5",
+			wantErr:    true,
+		},
+		{
+			name: "node newer than target",
+			nodes: []Node{
+				{Name: "cp-1", ControlPlane: true, Version: semver.MustParse("1.22.2")},
+			},
+			target:     "1.21.5",
+			wantLowest: "1.22.2",
+			wantErr:    true,
+		},
+		{
+			name: "worker newer than control plane",
+			nodes: []Node{
+				{Name: "cp-1", ControlPlane: true, Version: semver.MustParse("1.21.5")},
+				{Name: "worker-1", Version: semver.MustParse("1.22.2")},
+			},
+			target:     "1.22.2",
+			wantLowest: "1.21.5",
+			wantErr:    true,
+		},
+		{
+			name: "worker too old",
+			nodes: []Node{
+				{Name: "cp-1", ControlPlane: true, Version: semver.MustParse("1.22.2")},
+				{Name: "worker-1", Version: semver.MustParse("1.19.16")},
+			},
+			target:     "1.23.1",
+			wantLowest: "1.22.2",
+			wantErr:    true,
+		},
+		{
+			name: "unprovisioned node",
+			nodes: []Node{
+				{Name: "cp-1", ControlPlane: true, Version: semver.MustParse("1.22.2")},
+				{Name: "cp-2", ControlPlane: true},
+			},
+			target:     "1.23.1",
+			wantLowest: "1.22.2",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			lowest, err := ValidateSkew(tt.nodes, semver.MustParse(tt.target))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateSkew() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if lowest.String() != tt.wantLowest {
+				t.Errorf("ValidateSkew() lowest = %s, want %s", lowest, tt.wantLowest)
+			}
+		})
+	}
+}